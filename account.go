@@ -0,0 +1,402 @@
+package dim
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ErrEmailChangeNotFound dikembalikan EmailChangeStore jika permintaan ganti email tidak ditemukan.
+var ErrEmailChangeNotFound = errors.New("email change request not found")
+
+// EmailChange adalah permintaan ganti email yang menunggu konfirmasi dari alamat lama dan
+// alamat baru. Hanya hash token yang disimpan.
+type EmailChange struct {
+	ID             int64      `json:"id"`
+	UserID         string     `json:"user_id"`
+	NewEmail       string     `json:"new_email"`
+	NewTokenHash   string     `json:"-"`
+	OldTokenHash   string     `json:"-"`
+	NewConfirmedAt *time.Time `json:"new_confirmed_at,omitempty"`
+	OldConfirmedAt *time.Time `json:"old_confirmed_at,omitempty"`
+	ExpiresAt      time.Time  `json:"expires_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// Confirmed melaporkan apakah kedua alamat sudah mengonfirmasi perubahan.
+func (c *EmailChange) Confirmed() bool {
+	return c.NewConfirmedAt != nil && c.OldConfirmedAt != nil
+}
+
+// EmailChangeStore mendefinisikan penyimpanan permintaan ganti email.
+// DatabaseTokenStore dan MockTokenStore mengimplementasikan interface ini.
+type EmailChangeStore interface {
+	// SaveEmailChange menyimpan permintaan baru dan menghapus permintaan user yang masih tertunda
+	SaveEmailChange(ctx context.Context, change *EmailChange) error
+	// FindEmailChange mencari permintaan berdasarkan hash token alamat baru atau alamat lama
+	FindEmailChange(ctx context.Context, tokenHash string) (*EmailChange, error) // ErrEmailChangeNotFound jika tidak ada
+	// UpdateEmailChange menyimpan NewConfirmedAt dan OldConfirmedAt
+	UpdateEmailChange(ctx context.Context, change *EmailChange) error
+	DeleteEmailChange(ctx context.Context, id int64) error
+}
+
+// EmailUpdater mengganti email user. DatabaseAuthUserStore mengimplementasikan interface ini.
+type EmailUpdater interface {
+	UpdateEmail(ctx context.Context, userID, email string) error
+}
+
+// EmailChangeEmail adalah data untuk template email konfirmasi ganti email. Template dipanggil
+// dua kali: untuk alamat baru (ToOldAddress false) dan alamat lama (ToOldAddress true).
+type EmailChangeEmail struct {
+	To           string
+	OldEmail     string
+	NewEmail     string
+	URL          string
+	ExpiresAt    time.Time
+	ToOldAddress bool
+}
+
+// EmailChangeOptions mengatur ChangeEmail. Store wajib diisi.
+type EmailChangeOptions struct {
+	// Store menyimpan permintaan ganti email, biasanya DatabaseTokenStore.
+	Store EmailChangeStore
+	// Mailer mengirim email konfirmasi ke kedua alamat. Jika nil, ChangeEmail hanya
+	// mengembalikan token untuk dikirim sendiri oleh pemanggil.
+	Mailer Mailer
+	// URL adalah format URL halaman konfirmasi dengan %s untuk token,
+	// misal "https://app.example.com/confirm-email?token=%s".
+	URL string
+	// Subject email ke alamat baru (default: "Konfirmasi email baru Anda").
+	Subject string
+	// OldSubject email ke alamat lama (default: "Konfirmasi perubahan email akun Anda").
+	OldSubject string
+	// Template mengganti isi email default.
+	Template func(data EmailChangeEmail) *MailMessage
+	// Expiry adalah masa berlaku permintaan (default: 24 jam).
+	Expiry time.Duration
+}
+
+// WithEmailChange mengaktifkan ChangeEmail dan mengembalikan instance service.
+//
+// Parameters:
+//   - users: pengubah email user, biasanya DatabaseAuthUserStore
+//   - options: penyimpanan permintaan, mailer, URL, dan masa berlaku
+//
+// Example:
+//
+//	authService.WithEmailChange(userStore, dim.EmailChangeOptions{
+//	    Store:  tokenStore,
+//	    Mailer: mailer,
+//	    URL:    "https://app.example.com/confirm-email?token=%s",
+//	})
+func (s *AuthService) WithEmailChange(users EmailUpdater, options EmailChangeOptions) *AuthService {
+	if options.Subject == "" {
+		options.Subject = "Konfirmasi email baru Anda"
+	}
+	if options.OldSubject == "" {
+		options.OldSubject = "Konfirmasi perubahan email akun Anda"
+	}
+	if options.Expiry <= 0 {
+		options.Expiry = 24 * time.Hour
+	}
+	s.emailUpdater = users
+	s.emailChange = options
+	return s
+}
+
+// ChangePassword mengganti password user setelah memverifikasi password saat ini. Password baru
+// harus lolos PasswordValidator dan berbeda dari password saat ini. Setelah berhasil, semua
+// session lain user dibatalkan dan session ID-nya dimasukkan ke blocklist; session milik access
+// token di ctx (claim sid dari RequireAuth) tetap login.
+//
+// Parameters:
+//   - ctx: context request, biasanya r.Context() di belakang RequireAuth
+//   - userID: ID user
+//   - currentPassword: password saat ini untuk otentikasi ulang
+//   - newPassword: password baru
+//
+// Returns:
+//   - error: *AppError (400 validasi atau password saat ini salah, 404 user tidak ditemukan, 500)
+//
+// Example:
+//
+//	user, _ := dim.GetUser(r)
+//	err := authService.ChangePassword(r.Context(), user.GetID(), req.CurrentPassword, req.NewPassword)
+func (s *AuthService) ChangePassword(ctx context.Context, userID, currentPassword, newPassword string) error {
+	locale := LocaleFromContext(ctx)
+	v := NewValidator().WithLocale(locale).
+		Required("current_password", currentPassword).
+		Required("password", newPassword)
+
+	if !v.IsValid() {
+		err := NewAppError("Validasi gagal", 400)
+		err.Errors = v.ErrorMap()
+		return err
+	}
+
+	user, err := s.userStore.FindByID(ctx, userID)
+	if err != nil {
+		return NewAppError("Pengguna tidak ditemukan", 404)
+	}
+	if err := s.verifyCurrentPassword(ctx, user, currentPassword); err != nil {
+		return err
+	}
+
+	if newPassword == currentPassword {
+		return NewAppError("Validasi kata sandi gagal", 400).
+			WithFieldError("password", Translate(locale, "Password baru harus berbeda dari password saat ini"))
+	}
+	if err := s.checkPassword(ctx, locale, newPassword); err != nil {
+		return err
+	}
+
+	passwordHash, err := s.hasher.Hash(newPassword)
+	if err != nil {
+		return NewAppError("Gagal memproses password hash", 500)
+	}
+	user.SetPassword(passwordHash)
+	if err := s.userStore.Update(ctx, user); err != nil {
+		return NewAppError("Gagal memperbarui password", 500)
+	}
+
+	return s.revokeOtherSessions(ctx, userID, contextSessionID(ctx))
+}
+
+// verifyCurrentPassword memverifikasi ulang password user sebelum perubahan kredensial.
+func (s *AuthService) verifyCurrentPassword(ctx context.Context, user Authenticatable, password string) error {
+	if user.GetPassword() == "" || s.hasher.Verify(user.GetPassword(), password) != nil {
+		return NewAppError("Validasi gagal", 400).
+			WithFieldError("current_password", Translate(LocaleFromContext(ctx), "Password saat ini salah"))
+	}
+	return nil
+}
+
+// revokeOtherSessions membatalkan semua session aktif user kecuali keepSessionID, termasuk
+// memasukkan session ID ke blocklist agar access token yang masih berlaku ikut ditolak.
+func (s *AuthService) revokeOtherSessions(ctx context.Context, userID, keepSessionID string) error {
+	sessions, err := s.tokenStore.ListActiveSessions(ctx, userID)
+	if err != nil {
+		return NewAppError("Gagal mengakhiri sesi lain", 500)
+	}
+
+	for _, session := range sessions {
+		if keepSessionID != "" && session.SessionID == keepSessionID {
+			continue
+		}
+		if err := s.tokenStore.RevokeSession(ctx, userID, session.ID); err != nil && !errors.Is(err, ErrSessionNotFound) {
+			return NewAppError("Gagal mengakhiri sesi lain", 500)
+		}
+		if session.SessionID != "" && s.blocklist != nil {
+			if err := s.blocklist.Invalidate(ctx, session.SessionID, s.accessTokenExpiry()); err != nil && s.logger != nil {
+				s.logger.Warn("Failed to blacklist session", "session_id", session.SessionID, "error", err.Error())
+			}
+		}
+	}
+	return nil
+}
+
+// contextSessionID mengembalikan claim sid dari user yang di-set RequireAuth di context.
+func contextSessionID(ctx context.Context) string {
+	user, ok := ctx.Value(userKey).(interface{ GetClaims() map[string]interface{} })
+	if !ok {
+		return ""
+	}
+	sid, _ := user.GetClaims()["sid"].(string)
+	return sid
+}
+
+// ChangeEmail memulai penggantian email setelah memverifikasi password saat ini. Token
+// konfirmasi dikirim ke alamat baru dan alamat lama; email baru diterapkan setelah kedua token
+// dikonfirmasi lewat ConfirmEmailChange. Permintaan sebelumnya yang belum selesai dibatalkan.
+//
+// Parameters:
+//   - ctx: context request
+//   - userID: ID user
+//   - password: password saat ini untuk otentikasi ulang
+//   - newEmail: alamat email baru
+//
+// Returns:
+//   - string: token konfirmasi untuk alamat baru
+//   - string: token konfirmasi untuk alamat lama
+//   - error: *AppError (400 validasi atau password salah, 409 email sudah terdaftar, 500)
+//
+// Example:
+//
+//	user, _ := dim.GetUser(r)
+//	if _, _, err := authService.ChangeEmail(r.Context(), user.GetID(), req.Password, req.Email); err != nil {
+//	    appErr, _ := dim.AsAppError(err)
+//	    dim.JsonAppError(w, appErr)
+//	    return
+//	}
+//	dim.Json(w, http.StatusAccepted, map[string]string{"message": "Periksa email lama dan baru Anda"})
+func (s *AuthService) ChangeEmail(ctx context.Context, userID, password, newEmail string) (string, string, error) {
+	store := s.emailChange.Store
+	if store == nil || s.emailUpdater == nil {
+		return "", "", NewAppError("Ganti email tidak dikonfigurasi", http.StatusInternalServerError)
+	}
+
+	locale := LocaleFromContext(ctx)
+	newEmail = strings.ToLower(strings.TrimSpace(newEmail))
+	v := NewValidator().WithLocale(locale).
+		Required("current_password", password).
+		Required("email", newEmail).
+		Email("email", newEmail).
+		MaxLength("email", newEmail, 255)
+
+	if !v.IsValid() {
+		err := NewAppError("Validasi gagal", 400)
+		err.Errors = v.ErrorMap()
+		return "", "", err
+	}
+
+	user, err := s.userStore.FindByID(ctx, userID)
+	if err != nil {
+		return "", "", NewAppError("Pengguna tidak ditemukan", 404)
+	}
+	if err := s.verifyCurrentPassword(ctx, user, password); err != nil {
+		return "", "", err
+	}
+	if strings.EqualFold(user.GetEmail(), newEmail) {
+		return "", "", NewAppError("Validasi gagal", 400).
+			WithFieldError("email", Translate(locale, "Email baru sama dengan email saat ini"))
+	}
+	if err := s.ensureEmailAvailable(ctx, newEmail); err != nil {
+		return "", "", err
+	}
+
+	newToken, err := GenerateSecureToken(32)
+	if err != nil {
+		return "", "", NewAppError("Gagal membuat token konfirmasi", 500)
+	}
+	oldToken, err := GenerateSecureToken(32)
+	if err != nil {
+		return "", "", NewAppError("Gagal membuat token konfirmasi", 500)
+	}
+
+	change := &EmailChange{
+		UserID:       userID,
+		NewEmail:     newEmail,
+		NewTokenHash: GenerateTokenHash(newToken),
+		OldTokenHash: GenerateTokenHash(oldToken),
+		ExpiresAt:    time.Now().Add(s.emailChange.Expiry),
+	}
+	if err := store.SaveEmailChange(ctx, change); err != nil {
+		if s.logger != nil {
+			s.logger.Error("Failed to save email change", "error", err.Error())
+		}
+		return "", "", NewAppError("Gagal menyimpan permintaan ganti email", 500)
+	}
+
+	if mailer := s.emailChange.Mailer; mailer != nil {
+		data := EmailChangeEmail{OldEmail: user.GetEmail(), NewEmail: newEmail, ExpiresAt: change.ExpiresAt}
+		for _, recipient := range []struct {
+			to, token string
+			old       bool
+		}{{newEmail, newToken, false}, {user.GetEmail(), oldToken, true}} {
+			data.To, data.URL, data.ToOldAddress = recipient.to, fmt.Sprintf(s.emailChange.URL, recipient.token), recipient.old
+			if err := mailer.Send(ctx, s.emailChangeMessage(data)); err != nil {
+				if s.logger != nil {
+					s.logger.Error("Failed to send email change confirmation", "error", err.Error())
+				}
+				return "", "", NewAppError("Gagal mengirim email konfirmasi", 500)
+			}
+		}
+	}
+
+	return newToken, oldToken, nil
+}
+
+// ensureEmailAvailable mengembalikan 409 jika email sudah dipakai user lain.
+func (s *AuthService) ensureEmailAvailable(ctx context.Context, email string) error {
+	if s.registration != nil {
+		exists, err := s.registration.Exists(ctx, email)
+		if err != nil {
+			return NewAppError("Gagal memeriksa email", 500)
+		}
+		if !exists {
+			return nil
+		}
+	} else if _, err := s.userStore.FindByEmail(ctx, email); err != nil {
+		return nil
+	}
+	return NewAppError("Email sudah terdaftar", http.StatusConflict).
+		WithFieldError("email", Translate(LocaleFromContext(ctx), "Email sudah terdaftar"))
+}
+
+func (s *AuthService) emailChangeMessage(data EmailChangeEmail) *MailMessage {
+	if s.emailChange.Template != nil {
+		return s.emailChange.Template(data)
+	}
+
+	subject, intro := s.emailChange.Subject, "Klik link berikut untuk mengonfirmasi "+data.NewEmail+" sebagai email baru akun Anda."
+	if data.ToOldAddress {
+		subject, intro = s.emailChange.OldSubject, "Ada permintaan mengganti email akun Anda menjadi "+data.NewEmail+". Klik link berikut untuk mengonfirmasi."
+	}
+
+	msg := NewMailMessage([]string{data.To}, subject)
+	msg.HTML = fmt.Sprintf(
+		`<p>%s</p><p><a href="%s">Konfirmasi</a></p><p>Email diganti setelah alamat lama dan alamat baru sama-sama dikonfirmasi. Link berlaku hingga %s. Abaikan email ini jika Anda tidak meminta perubahan.</p>`,
+		html.EscapeString(intro), html.EscapeString(data.URL), data.ExpiresAt.Format("2 Jan 2006 15:04 MST"),
+	)
+	msg.PlainText = fmt.Sprintf(
+		"%s\n\n%s\n\nEmail diganti setelah alamat lama dan alamat baru sama-sama dikonfirmasi. Link berlaku hingga %s. Abaikan email ini jika Anda tidak meminta perubahan.\n",
+		intro, data.URL, data.ExpiresAt.Format("2 Jan 2006 15:04 MST"),
+	)
+	return msg
+}
+
+// ConfirmEmailChange mengonfirmasi salah satu token dari ChangeEmail. Setelah token alamat baru
+// dan alamat lama sama-sama dikonfirmasi, email user diganti dan permintaan dihapus.
+//
+// Parameters:
+//   - ctx: context request
+//   - token: token dari link email
+//
+// Returns:
+//   - bool: true jika email sudah diganti, false jika masih menunggu konfirmasi alamat lainnya
+//   - error: *AppError (400 jika token tidak valid atau kadaluarsa, 409 jika email baru sudah dipakai)
+func (s *AuthService) ConfirmEmailChange(ctx context.Context, token string) (bool, error) {
+	store := s.emailChange.Store
+	if store == nil || s.emailUpdater == nil {
+		return false, NewAppError("Ganti email tidak dikonfigurasi", http.StatusInternalServerError)
+	}
+
+	tokenHash := GenerateTokenHash(token)
+	change, err := store.FindEmailChange(ctx, tokenHash)
+	if err != nil || time.Now().After(change.ExpiresAt) {
+		return false, NewAppError("Token konfirmasi tidak valid atau kadaluarsa", 400)
+	}
+
+	now := time.Now()
+	if tokenHash == change.NewTokenHash && change.NewConfirmedAt == nil {
+		change.NewConfirmedAt = &now
+	} else if tokenHash == change.OldTokenHash && change.OldConfirmedAt == nil {
+		change.OldConfirmedAt = &now
+	}
+
+	if !change.Confirmed() {
+		if err := store.UpdateEmailChange(ctx, change); err != nil {
+			return false, NewAppError("Gagal menyimpan konfirmasi", 500)
+		}
+		return false, nil
+	}
+
+	// Email could have been taken since the request was made
+	if err := s.ensureEmailAvailable(ctx, change.NewEmail); err != nil {
+		return false, err
+	}
+	if err := s.emailUpdater.UpdateEmail(ctx, change.UserID, change.NewEmail); err != nil {
+		if s.logger != nil {
+			s.logger.Error("Failed to update email", "error", err.Error())
+		}
+		return false, NewAppError("Gagal memperbarui email", 500)
+	}
+	if err := store.DeleteEmailChange(ctx, change.ID); err != nil && s.logger != nil {
+		s.logger.Warn("Failed to delete email change", "id", change.ID, "error", err.Error())
+	}
+	return true, nil
+}
@@ -0,0 +1,84 @@
+package dim
+
+import (
+	"context"
+)
+
+// GetEmailChangeMigrations mengembalikan migrasi tabel email_changes yang dipakai
+// DatabaseTokenStore sebagai EmailChangeStore. Modul ini opsional sehingga tidak termasuk dalam
+// GetFrameworkMigrations; gabungkan secara manual setelah migrasi users.
+// Menggunakan versi 191 agar tidak bentrok dengan migrasi framework maupun aplikasi.
+//
+// Example:
+//
+//	migrations := append(dim.GetFrameworkMigrations(), dim.GetEmailChangeMigrations()...)
+//	dim.RunMigrations(db, migrations)
+func GetEmailChangeMigrations() []Migration {
+	return []Migration{
+		{
+			Version: 191,
+			Name:    "create_email_changes_table",
+			Up:      CreateEmailChangesTable,
+			Down:    DropEmailChangesTable,
+		},
+	}
+}
+
+// CreateEmailChangesTable membuat tabel email_changes. Hanya hash token konfirmasi yang disimpan.
+func CreateEmailChangesTable(db Database) error {
+	var query string
+	switch db.DriverName() {
+	case "sqlite":
+		query = `
+			CREATE TABLE IF NOT EXISTS email_changes (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+				new_email TEXT NOT NULL,
+				new_token_hash TEXT UNIQUE NOT NULL,
+				old_token_hash TEXT UNIQUE NOT NULL,
+				new_confirmed_at TIMESTAMP NULL,
+				old_confirmed_at TIMESTAMP NULL,
+				expires_at TIMESTAMP NOT NULL,
+				created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+			);
+			CREATE INDEX IF NOT EXISTS idx_email_changes_user_id ON email_changes(user_id);
+		`
+	case "mysql":
+		query = `
+			CREATE TABLE IF NOT EXISTS email_changes (
+				id BIGINT AUTO_INCREMENT PRIMARY KEY,
+				user_id CHAR(36) NOT NULL,
+				new_email VARCHAR(255) NOT NULL,
+				new_token_hash VARCHAR(64) UNIQUE NOT NULL,
+				old_token_hash VARCHAR(64) UNIQUE NOT NULL,
+				new_confirmed_at DATETIME NULL,
+				old_confirmed_at DATETIME NULL,
+				expires_at DATETIME NOT NULL,
+				created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				INDEX idx_email_changes_user_id (user_id),
+				CONSTRAINT fk_email_changes_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+		`
+	default:
+		query = `
+			CREATE TABLE IF NOT EXISTS email_changes (
+				id BIGSERIAL PRIMARY KEY,
+				user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+				new_email VARCHAR(255) NOT NULL,
+				new_token_hash VARCHAR(64) UNIQUE NOT NULL,
+				old_token_hash VARCHAR(64) UNIQUE NOT NULL,
+				new_confirmed_at TIMESTAMP NULL,
+				old_confirmed_at TIMESTAMP NULL,
+				expires_at TIMESTAMP NOT NULL,
+				created_at TIMESTAMP NOT NULL DEFAULT NOW()
+			);
+			CREATE INDEX IF NOT EXISTS idx_email_changes_user_id ON email_changes(user_id);
+		`
+	}
+	return db.Exec(context.Background(), query)
+}
+
+// DropEmailChangesTable menghapus tabel email_changes.
+func DropEmailChangesTable(db Database) error {
+	return db.Exec(context.Background(), "DROP TABLE IF EXISTS email_changes")
+}
@@ -0,0 +1,131 @@
+package dim
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// UpdateEmail changes the email of a user and bumps the row version.
+func (s *DatabaseAuthUserStore) UpdateEmail(ctx context.Context, userID, email string) error {
+	query := s.db.Rebind(`UPDATE users SET email = $1, updated_at = $2, version = version + 1 WHERE id = $3`)
+	if err := s.db.Exec(ctx, query, email, time.Now().UTC().Truncate(time.Second), userID); err != nil {
+		return fmt.Errorf("failed to update email: %w", err)
+	}
+	return nil
+}
+
+// SaveEmailChange saves an email change request, replacing any pending request of the user.
+func (s *DatabaseTokenStore) SaveEmailChange(ctx context.Context, change *EmailChange) error {
+	return InTx(ctx, s.db, func(ctx context.Context) error {
+		if err := s.db.Exec(ctx, s.db.Rebind(`DELETE FROM email_changes WHERE user_id = $1`), change.UserID); err != nil {
+			return fmt.Errorf("failed to delete pending email change: %w", err)
+		}
+
+		now := time.Now().UTC().Truncate(time.Second)
+		query := `INSERT INTO email_changes (user_id, new_email, new_token_hash, old_token_hash, expires_at, created_at)
+			 VALUES ($1, $2, $3, $4, $5, $6)
+			 RETURNING id, created_at`
+
+		err := s.db.QueryRow(ctx, s.db.Rebind(query),
+			change.UserID,
+			change.NewEmail,
+			change.NewTokenHash,
+			change.OldTokenHash,
+			change.ExpiresAt.UTC().Truncate(time.Second),
+			now,
+		).Scan(&change.ID, &change.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to save email change: %w", err)
+		}
+		return nil
+	})
+}
+
+// FindEmailChange finds an email change request by the hash of either confirmation token.
+func (s *DatabaseTokenStore) FindEmailChange(ctx context.Context, tokenHash string) (*EmailChange, error) {
+	change := &EmailChange{}
+	query := `SELECT id, user_id, new_email, new_token_hash, old_token_hash, new_confirmed_at, old_confirmed_at, expires_at, created_at
+		 FROM email_changes WHERE new_token_hash = $1 OR old_token_hash = $2`
+
+	err := s.db.QueryRow(ctx, s.db.Rebind(query), tokenHash, tokenHash).Scan(
+		&change.ID, &change.UserID, &change.NewEmail, &change.NewTokenHash, &change.OldTokenHash,
+		&change.NewConfirmedAt, &change.OldConfirmedAt, &change.ExpiresAt, &change.CreatedAt,
+	)
+	if err != nil {
+		if isNoRows(err) {
+			return nil, ErrEmailChangeNotFound
+		}
+		return nil, fmt.Errorf("failed to find email change: %w", err)
+	}
+
+	return change, nil
+}
+
+// UpdateEmailChange stores the confirmation timestamps of an email change request.
+func (s *DatabaseTokenStore) UpdateEmailChange(ctx context.Context, change *EmailChange) error {
+	query := `UPDATE email_changes SET new_confirmed_at = $1, old_confirmed_at = $2 WHERE id = $3`
+
+	if err := s.db.Exec(ctx, s.db.Rebind(query), utcTimePtr(change.NewConfirmedAt), utcTimePtr(change.OldConfirmedAt), change.ID); err != nil {
+		return fmt.Errorf("failed to update email change: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteEmailChange deletes an email change request.
+func (s *DatabaseTokenStore) DeleteEmailChange(ctx context.Context, id int64) error {
+	if err := s.db.Exec(ctx, s.db.Rebind(`DELETE FROM email_changes WHERE id = $1`), id); err != nil {
+		return fmt.Errorf("failed to delete email change: %w", err)
+	}
+	return nil
+}
+
+func utcTimePtr(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	utc := t.UTC().Truncate(time.Second)
+	return &utc
+}
+
+// SaveEmailChange saves an email change request in mock store, replacing any pending request of the user.
+func (s *MockTokenStore) SaveEmailChange(ctx context.Context, change *EmailChange) error {
+	var lastID int64
+	for id, existing := range s.emailChanges {
+		if existing.UserID == change.UserID {
+			delete(s.emailChanges, id)
+		}
+		lastID = max(lastID, id)
+	}
+	change.ID = lastID + 1
+	change.CreatedAt = time.Now()
+	copied := *change
+	s.emailChanges[change.ID] = &copied
+	return nil
+}
+
+// FindEmailChange finds an email change request by token hash in mock store.
+func (s *MockTokenStore) FindEmailChange(ctx context.Context, tokenHash string) (*EmailChange, error) {
+	for _, change := range s.emailChanges {
+		if change.NewTokenHash == tokenHash || change.OldTokenHash == tokenHash {
+			copied := *change
+			return &copied, nil
+		}
+	}
+	return nil, ErrEmailChangeNotFound
+}
+
+// UpdateEmailChange stores the confirmation timestamps of an email change request in mock store.
+func (s *MockTokenStore) UpdateEmailChange(ctx context.Context, change *EmailChange) error {
+	if existing, ok := s.emailChanges[change.ID]; ok {
+		existing.NewConfirmedAt, existing.OldConfirmedAt = change.NewConfirmedAt, change.OldConfirmedAt
+	}
+	return nil
+}
+
+// DeleteEmailChange deletes an email change request in mock store.
+func (s *MockTokenStore) DeleteEmailChange(ctx context.Context, id int64) error {
+	delete(s.emailChanges, id)
+	return nil
+}
@@ -0,0 +1,169 @@
+package dim
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func accountAppStatus(err error) int {
+	if appErr, ok := AsAppError(err); ok {
+		return appErr.StatusCode
+	}
+	return 0
+}
+
+func TestAuthService_ChangePassword(t *testing.T) {
+	ctx := context.Background()
+	hasher := NewBcryptHasher(4)
+	hash, _ := hasher.Hash("OldPass123!")
+	userStore := NewMockUserStore()
+	userStore.AddUser(&MockUser{ID: "1", Email: "ana@example.com", Password: hash})
+	tokenStore := NewMockTokenStore()
+	blocklist := &ttlBlocklist{InMemoryBlocklist: NewInMemoryBlocklist(), ttls: map[string]time.Duration{}}
+	service, err := NewAuthService(userStore, tokenStore, blocklist, &JWTConfig{
+		HMACSecret:         "test-secret",
+		SigningMethod:      "HS256",
+		AccessTokenExpiry:  15 * time.Minute,
+		RefreshTokenExpiry: 7 * 24 * time.Hour,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	service.WithPasswordHasher(hasher)
+
+	// Dua device login; perubahan dilakukan dari device pertama
+	current, _, err := service.Login(ctx, "ana@example.com", "OldPass123!")
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, _, err := service.Login(ctx, "ana@example.com", "OldPass123!")
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, _ := service.tokenManager.VerifyToken(current)
+	otherClaims, _ := service.tokenManager.VerifyToken(other)
+	authCtx := context.WithValue(ctx, userKey, Authenticatable(&TokenUser{ID: "1", Claims: claims}))
+
+	if err := service.ChangePassword(authCtx, "1", "WrongPass123!", "NewPass123!"); accountAppStatus(err) != http.StatusBadRequest {
+		t.Errorf("wrong current password error = %v, want 400", err)
+	}
+	if err := service.ChangePassword(authCtx, "1", "OldPass123!", "OldPass123!"); accountAppStatus(err) != http.StatusBadRequest {
+		t.Errorf("unchanged password error = %v, want 400", err)
+	}
+	if err := service.ChangePassword(authCtx, "1", "OldPass123!", "weak"); accountAppStatus(err) != http.StatusBadRequest {
+		t.Errorf("weak password error = %v, want 400", err)
+	}
+
+	if err := service.ChangePassword(authCtx, "1", "OldPass123!", "NewPass123!"); err != nil {
+		t.Fatalf("ChangePassword error: %v", err)
+	}
+	if _, _, err := service.Login(ctx, "ana@example.com", "OldPass123!"); err == nil {
+		t.Error("old password should no longer work")
+	}
+
+	sessions, _ := service.Sessions(ctx, "1")
+	if len(sessions) != 1 || sessions[0].SessionID != claims["sid"] {
+		t.Errorf("remaining sessions = %+v, want only the current session", sessions)
+	}
+	if revoked, _ := blocklist.IsRevoked(ctx, otherClaims["sid"].(string)); !revoked {
+		t.Error("other session should be blocklisted")
+	}
+	if ttl := blocklist.ttls[otherClaims["sid"].(string)]; ttl != 15*time.Minute {
+		t.Errorf("other session blocklisted for %v, want the access token expiry", ttl)
+	}
+	if revoked, _ := blocklist.IsRevoked(ctx, claims["sid"].(string)); revoked {
+		t.Error("current session should stay valid")
+	}
+}
+
+func TestAuthService_ChangeEmail(t *testing.T) {
+	ctx := context.Background()
+	db := newTestSQLiteAuthDB(t)
+	if err := RunMigrations(db, GetEmailChangeMigrations()); err != nil {
+		t.Fatal(err)
+	}
+	userStore := NewDatabaseAuthUserStore(db)
+	tokenStore := NewDatabaseTokenStore(db)
+	hasher := NewBcryptHasher(4)
+	hash, _ := hasher.Hash("ValidPass123!")
+	ana := &User{Email: "ana@example.com", Password: hash}
+	if err := userStore.Create(ctx, ana); err != nil {
+		t.Fatal(err)
+	}
+	if err := userStore.Create(ctx, &User{Email: "budi@example.com", Password: hash}); err != nil {
+		t.Fatal(err)
+	}
+
+	mailer := &captureOrgMailer{}
+	service, err := NewAuthService(userStore, tokenStore, nil, &JWTConfig{
+		HMACSecret:         "test-secret",
+		SigningMethod:      "HS256",
+		AccessTokenExpiry:  15 * time.Minute,
+		RefreshTokenExpiry: 7 * 24 * time.Hour,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	service.WithPasswordHasher(hasher).WithEmailChange(userStore, EmailChangeOptions{
+		Store:  tokenStore,
+		Mailer: mailer,
+		URL:    "https://app.test/confirm-email?token=%s",
+	})
+
+	if _, _, err := service.ChangeEmail(ctx, ana.ID, "WrongPass123!", "ana@new.example.com"); accountAppStatus(err) != http.StatusBadRequest {
+		t.Errorf("wrong password error = %v, want 400", err)
+	}
+	if _, _, err := service.ChangeEmail(ctx, ana.ID, "ValidPass123!", "budi@example.com"); accountAppStatus(err) != http.StatusConflict {
+		t.Errorf("taken email error = %v, want 409", err)
+	}
+
+	newToken, oldToken, err := service.ChangeEmail(ctx, ana.ID, "ValidPass123!", "Ana@New.example.com")
+	if err != nil {
+		t.Fatalf("ChangeEmail error: %v", err)
+	}
+	if len(mailer.sent) != 2 {
+		t.Fatalf("expected 2 emails, got %d", len(mailer.sent))
+	}
+	if msg := mailer.sent[0]; msg.To[0] != "ana@new.example.com" || !strings.Contains(msg.PlainText, "token="+newToken) {
+		t.Errorf("unexpected email to new address: %+v", msg)
+	}
+	if msg := mailer.sent[1]; msg.To[0] != "ana@example.com" || msg.Subject != "Konfirmasi perubahan email akun Anda" ||
+		!strings.Contains(msg.PlainText, "token="+oldToken) {
+		t.Errorf("unexpected email to old address: %+v", msg)
+	}
+
+	// Email baru diterapkan setelah kedua alamat mengonfirmasi
+	if done, err := service.ConfirmEmailChange(ctx, newToken); err != nil || done {
+		t.Fatalf("ConfirmEmailChange(new) = %v, %v; want pending", done, err)
+	}
+	if done, err := service.ConfirmEmailChange(ctx, newToken); err != nil || done {
+		t.Fatalf("ConfirmEmailChange(new) again = %v, %v; want pending", done, err)
+	}
+	if user, _ := userStore.FindByID(ctx, ana.ID); user.GetEmail() != "ana@example.com" {
+		t.Errorf("email changed before both confirmations: %s", user.GetEmail())
+	}
+	if done, err := service.ConfirmEmailChange(ctx, oldToken); err != nil || !done {
+		t.Fatalf("ConfirmEmailChange(old) = %v, %v; want done", done, err)
+	}
+	if user, _ := userStore.FindByID(ctx, ana.ID); user.GetEmail() != "ana@new.example.com" {
+		t.Errorf("email after confirmation = %s", user.GetEmail())
+	}
+	if _, err := service.ConfirmEmailChange(ctx, oldToken); accountAppStatus(err) != http.StatusBadRequest {
+		t.Errorf("confirming a completed change error = %v, want 400", err)
+	}
+
+	// Permintaan baru menggantikan permintaan sebelumnya
+	first, _, err := service.ChangeEmail(ctx, ana.ID, "ValidPass123!", "ana@first.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := service.ChangeEmail(ctx, ana.ID, "ValidPass123!", "ana@second.example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.ConfirmEmailChange(ctx, first); accountAppStatus(err) != http.StatusBadRequest {
+		t.Errorf("superseded token error = %v, want 400", err)
+	}
+}
@@ -2,6 +2,7 @@ package dim
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 )
@@ -22,6 +23,8 @@ type TokenResponse struct {
 
 // AuthUserStore mendefinisikan interface yang dibutuhkan oleh AuthService
 // untuk berinteraksi dengan penyimpanan data pengguna.
+// FindByEmail dan FindByID mengembalikan ErrUserNotFound (boleh di-wrap) atau error no rows
+// dari driver database jika user tidak ditemukan; error lain dianggap kegagalan infrastruktur.
 type AuthUserStore interface {
 	FindByEmail(ctx context.Context, email string) (Authenticatable, error)
 	FindByID(ctx context.Context, id string) (Authenticatable, error)
@@ -32,6 +35,48 @@ type AuthUserStore interface {
 // Gunakan ini untuk menyisipkan data tambahan ke dalam JWT (seperti workspace_id, role, dll).
 type ClaimsProvider func(ctx context.Context, user Authenticatable) (map[string]interface{}, error)
 
+// MergeClaimsProviders menggabungkan beberapa ClaimsProvider menjadi satu, misal claims
+// organisasi dan RBAC. Provider dijalankan berurutan; key yang sama ditimpa provider terakhir.
+//
+// Example:
+//
+//	authService.WithClaimsProvider(dim.MergeClaimsProviders(orgs.ClaimsProvider(), rbac.ClaimsProvider()))
+func MergeClaimsProviders(providers ...ClaimsProvider) ClaimsProvider {
+	return func(ctx context.Context, user Authenticatable) (map[string]interface{}, error) {
+		var merged map[string]interface{}
+		for _, provider := range providers {
+			claims, err := provider(ctx, user)
+			if err != nil {
+				return nil, err
+			}
+			if len(claims) > 0 && merged == nil {
+				merged = make(map[string]interface{}, len(claims))
+			}
+			for k, v := range claims {
+				merged[k] = v
+			}
+		}
+		return merged, nil
+	}
+}
+
+// defaultAccessTokenExpiry adalah lama session yang dicabut diblokir jika TTL access token tidak
+// diketahui dari TokenManager.
+const defaultAccessTokenExpiry = time.Hour
+
+// SecurityEventRefreshTokenReuse menandakan refresh token yang sudah dibatalkan dipakai kembali.
+// Seluruh session (semua refresh token dengan sid yang sama) langsung dibatalkan.
+const SecurityEventRefreshTokenReuse = "refresh_token_reuse"
+
+// SecurityEvent adalah kejadian keamanan yang terdeteksi AuthService dan dikirim ke callback
+// OnSecurityEvent.
+type SecurityEvent struct {
+	Type      string
+	UserID    string
+	SessionID string
+	Time      time.Time
+}
+
 // AuthService menangani operasi otentikasi seperti login, register, dan manajemen token.
 type AuthService struct {
 	userStore      AuthUserStore
@@ -39,8 +84,24 @@ type AuthService struct {
 	blocklist      TokenBlocklist
 	tokenManager   TokenManager
 	pwValidator    *PasswordValidator
+	hasher         PasswordHasher
+	breachChecker  BreachChecker
 	claimsProvider ClaimsProvider
+	verifier       CredentialVerifier
+	mfaStore       MFAStore
+	mfaIssuer      string
+	magicLinks     MagicLinkStore
+	magicLink      MagicLinkOptions
+	registration   UserRegistrationStore
+	registerOpts   RegistrationOptions
+	emailUpdater   EmailUpdater
+	emailChange    EmailChangeOptions
+	impersonation  ImpersonationOptions
+	sessionOpts    SessionOptions
+	securityEvents func(ctx context.Context, event SecurityEvent)
+	events         *EventBus
 	logger         *Logger
+	now            func() time.Time
 }
 
 // NewAuthService membuat instance AuthService baru menggunakan JWTConfig.
@@ -60,6 +121,8 @@ func NewAuthService(
 
 // NewAuthServiceWithManager membuat instance AuthService baru menggunakan TokenManager yang sudah diinisialisasi.
 // Gunakan ini untuk memilih token provider secara eksplisit (JWT, Branca, dll).
+// Masa berlaku session diambil dari konfigurasi *JWTManager atau *BrancaManager; untuk
+// TokenManager lain gunakan WithSessionOptions.
 //
 // Example:
 //
@@ -77,6 +140,9 @@ func NewAuthServiceWithManager(
 		blocklist:    blocklist,
 		tokenManager: manager,
 		pwValidator:  NewPasswordValidator(),
+		hasher:       NewBcryptHasher(BcryptCost),
+		sessionOpts:  sessionOptionsFromManager(manager),
+		now:          time.Now,
 	}, nil
 }
 
@@ -87,6 +153,40 @@ func (s *AuthService) WithClaimsProvider(provider ClaimsProvider) *AuthService {
 	return s
 }
 
+// WithCredentialVerifier mengatur CredentialVerifier yang digunakan Login untuk memverifikasi password
+// dan mengembalikan instance service. Secara default Login menggunakan LocalCredentialVerifier
+// (hash password di AuthUserStore). Gunakan ini untuk mendelegasikan verifikasi ke LDAP/Active
+// Directory atau identity API eksternal, sambil tetap menerbitkan token dan session dim.
+//
+// Example:
+//
+//	authService.WithCredentialVerifier(dim.ChainCredentialVerifiers(
+//	    dim.NewLocalCredentialVerifier(userStore),
+//	    ldapVerifier,
+//	))
+func (s *AuthService) WithCredentialVerifier(verifier CredentialVerifier) *AuthService {
+	s.verifier = verifier
+	return s
+}
+
+// WithPasswordHasher mengganti PasswordHasher yang dipakai Register, ResetPassword, dan verifikasi
+// password lokal saat Login (default bcrypt dengan BcryptCost), lalu mengembalikan instance service.
+func (s *AuthService) WithPasswordHasher(hasher PasswordHasher) *AuthService {
+	s.hasher = hasher
+	return s
+}
+
+// WithPasswordValidator mengganti aturan kekuatan password yang dipakai Register dan
+// ResetPassword, lalu mengembalikan instance service.
+//
+// Example:
+//
+//	authService.WithPasswordValidator(dim.NewPasswordValidator().SetMinLength(12).RequireSpecial(false))
+func (s *AuthService) WithPasswordValidator(validator *PasswordValidator) *AuthService {
+	s.pwValidator = validator
+	return s
+}
+
 // WithLogger mengatur logger untuk AuthService dan mengembalikan instance service.
 // Logger digunakan untuk mencatat internal errors yang tidak dikirim ke client.
 // Method ini menggunakan pola chaining untuk memudahkan konfigurasi.
@@ -100,8 +200,24 @@ func (s *AuthService) WithLogger(logger *Logger) *AuthService {
 	return s
 }
 
+// OnSecurityEvent mendaftarkan callback yang dipanggil saat AuthService mendeteksi kejadian
+// keamanan, misal refresh token yang sudah dibatalkan dipakai kembali. Callback dipanggil secara
+// sinkron setelah session terkait dibatalkan; gunakan untuk audit log atau notifikasi ke user.
+//
+// Example:
+//
+//	authService.OnSecurityEvent(func(ctx context.Context, event dim.SecurityEvent) {
+//	    logger.Warn("security event", "type", event.Type, "user_id", event.UserID)
+//	})
+func (s *AuthService) OnSecurityEvent(fn func(ctx context.Context, event SecurityEvent)) *AuthService {
+	s.securityEvents = fn
+	return s
+}
+
 // Login mengotentikasi pengguna menggunakan email dan password.
 // Mengembalikan access token dan refresh token jika kredensial valid.
+// Jika WithMFA aktif dan user sudah mengaktifkan MFA, Login mengembalikan *MFARequiredError
+// (errors.Is(err, ErrMFARequired)) berisi token mfa_pending untuk VerifyMFA.
 //
 // Parameters:
 //   - ctx: context request
@@ -114,7 +230,7 @@ func (s *AuthService) WithLogger(logger *Logger) *AuthService {
 //   - error: error jika kredensial tidak valid atau terjadi kesalahan server
 func (s *AuthService) Login(ctx context.Context, email, password string) (string, string, error) {
 	// Validate input
-	v := NewValidator().
+	v := NewValidator().WithLocale(LocaleFromContext(ctx)).
 		Required("email", email).
 		Email("email", email).
 		Required("password", password)
@@ -124,25 +240,53 @@ func (s *AuthService) Login(ctx context.Context, email, password string) (string
 		return "", "", err
 	}
 
-	// Find user by email
-	user, err := s.userStore.FindByEmail(ctx, email)
+	// Verify credentials (local hash by default, or delegated verifier)
+	verifier := s.verifier
+	if verifier == nil {
+		verifier = NewLocalCredentialVerifier(s.userStore).WithHasher(s.hasher)
+	}
+
+	user, err := verifier.Verify(ctx, email, password)
 	if err != nil {
+		// Infrastructure errors (e.g. database or LDAP unreachable) are not a wrong password:
+		// log them and return a server error without revealing the cause to the client
+		if !errors.Is(err, ErrInvalidCredentials) {
+			if s.logger != nil {
+				s.logger.Error("Credential verification failed", "error", err.Error())
+			}
+			return "", "", NewAppError("Gagal memverifikasi kredensial", 500)
+		}
+		s.loginFailed(ctx, email, "", LoginFailedInvalidCredentials)
 		return "", "", NewAppError("Kredensial tidak valid", 401)
 	}
 
-	// Verify password
-	if err := VerifyPassword(user.GetPassword(), password); err != nil {
-		return "", "", NewAppError("Kredensial tidak valid", 401)
+	// Registered users must confirm their email first (WithRegistration)
+	if err := s.requireVerifiedEmail(ctx, user); err != nil {
+		s.loginFailed(ctx, email, user.GetID(), LoginFailedEmailNotVerified)
+		return "", "", err
+	}
+
+	// Second factor required: issue mfa_pending token instead of a session
+	challenge, err := s.mfaChallenge(ctx, user)
+	if err != nil {
+		return "", "", err
+	}
+	if challenge != nil {
+		return "", "", challenge
 	}
 
+	return s.login(ctx, user, LoginMethodPassword, false)
+}
+
+// issueTokens membuat session baru untuk user yang sudah terotentikasi: access token, refresh token,
+// dan hash refresh token di TokenStore. Alur login memanggilnya lewat login agar LoginSucceeded
+// dipublikasikan.
+// mfa menambahkan claim MFAClaim ke access token.
+func (s *AuthService) issueTokens(ctx context.Context, user Authenticatable, mfa bool) (string, string, error) {
 	// Get custom claims
-	var extraClaims map[string]interface{}
-	if s.claimsProvider != nil {
-		var err error
-		extraClaims, err = s.claimsProvider(ctx, user)
-		if err != nil {
-			return "", "", NewAppError("Gagal membuat claims", 500)
-		}
+	extraClaims, err := s.extraClaims(ctx, user, mfa)
+	if err != nil {
+		return "", "", err
 	}
 
 	// Generate Session ID (UUID)
@@ -154,17 +298,25 @@ func (s *AuthService) Login(ctx context.Context, email, password string) (string
 		return "", "", NewAppError("Gagal membuat access token", 500)
 	}
 
-	refreshToken, err := s.tokenManager.GenerateRefreshToken(user.GetID(), sessionID)
+	rememberMe := RememberMeFromContext(ctx)
+	expiresAt, sessionExpiresAt := s.newSessionExpiry(rememberMe)
+	refreshToken, err := s.generateRefreshToken(user.GetID(), sessionID, expiresAt)
 	if err != nil {
 		return "", "", NewAppError("Gagal membuat refresh token", 500)
 	}
 
 	// Store refresh token hash
 	refreshTokenHash := GenerateTokenHash(refreshToken)
+	client, _ := ClientInfoFromContext(ctx)
 	refreshTokenEntity := &RefreshToken{
-		UserID:    user.GetID(),
-		TokenHash: refreshTokenHash,
-		ExpiresAt: time.Now().Add(7 * 24 * time.Hour).UTC().Truncate(time.Second),
+		UserID:           user.GetID(),
+		TokenHash:        refreshTokenHash,
+		SessionID:        sessionID,
+		UserAgent:        client.UserAgent,
+		IPAddress:        client.IPAddress,
+		ExpiresAt:        expiresAt,
+		RememberMe:       rememberMe,
+		SessionExpiresAt: sessionExpiresAt,
 	}
 
 	if err := s.tokenStore.SaveRefreshToken(ctx, refreshTokenEntity); err != nil {
@@ -174,8 +326,32 @@ func (s *AuthService) Login(ctx context.Context, email, password string) (string
 	return accessToken, refreshToken, nil
 }
 
+// extraClaims mengembalikan claims dari ClaimsProvider, ditambah MFAClaim jika mfa bernilai true.
+func (s *AuthService) extraClaims(ctx context.Context, user Authenticatable, mfa bool) (map[string]interface{}, error) {
+	var claims map[string]interface{}
+	if s.claimsProvider != nil {
+		var err error
+		claims, err = s.claimsProvider(ctx, user)
+		if err != nil {
+			return nil, NewAppError("Gagal membuat claims", 500)
+		}
+	}
+	if mfa {
+		// Copy so a map shared by the ClaimsProvider is never mutated
+		withMFA := make(map[string]interface{}, len(claims)+1)
+		for k, v := range claims {
+			withMFA[k] = v
+		}
+		withMFA[MFAClaim] = true
+		claims = withMFA
+	}
+	return claims, nil
+}
+
 // RefreshToken memperbarui access token menggunakan refresh token yang valid.
 // Method ini akan membatalkan refresh token lama dan mengeluarkan pasangan token baru (Token Rotation).
+// Kadaluarsa session digeser ke sekarang + TTL session (remember-me atau biasa), dibatasi
+// SessionOptions.MaxLifetime sejak login.
 //
 // Parameters:
 //   - ctx: context request
@@ -203,8 +379,10 @@ func (s *AuthService) RefreshToken(ctx context.Context, refreshTokenStr string)
 		return "", "", NewAppError("Refresh token tidak valid", 401)
 	}
 
-	// Check if token is revoked
+	// A revoked token being presented again means it was rotated and is now replayed,
+	// most likely by whoever stole it: revoke the whole session family
 	if storedToken.RevokedAt != nil {
+		s.revokeReusedSession(ctx, storedToken, sessionID)
 		return "", "", NewAppError("Token telah dibatalkan (revoked)", 401)
 	}
 
@@ -219,13 +397,14 @@ func (s *AuthService) RefreshToken(ctx context.Context, refreshTokenStr string)
 		return "", "", NewAppError("Pengguna tidak ditemukan", 404)
 	}
 
-	// Get custom claims
-	var extraClaims map[string]interface{}
-	if s.claimsProvider != nil {
-		extraClaims, err = s.claimsProvider(ctx, user)
-		if err != nil {
-			return "", "", NewAppError("Gagal membuat claims", 500)
-		}
+	// Get custom claims. Sessions of MFA users were verified by VerifyMFA at login.
+	mfa, err := s.MFAEnabled(ctx, user.GetID())
+	if err != nil {
+		return "", "", NewAppError("Gagal memeriksa status MFA", 500)
+	}
+	extraClaims, err := s.extraClaims(ctx, user, mfa)
+	if err != nil {
+		return "", "", err
 	}
 
 	// Generate new access token
@@ -234,36 +413,105 @@ func (s *AuthService) RefreshToken(ctx context.Context, refreshTokenStr string)
 		return "", "", NewAppError("Gagal membuat access token", 500)
 	}
 
-	// Generate new refresh token
-	newRefreshToken, err := s.tokenManager.GenerateRefreshToken(user.GetID(), sessionID)
+	// Generate new refresh token, sliding the session up to its absolute cap
+	expiresAt := s.slideSessionExpiry(storedToken)
+	newRefreshToken, err := s.generateRefreshToken(user.GetID(), sessionID, expiresAt)
 	if err != nil {
 		return "", "", NewAppError("Gagal membuat refresh token", 500)
 	}
 
-	// Revoke old refresh token
-	_ = s.tokenStore.RevokeRefreshToken(ctx, refreshTokenHash)
+	// Revoke old refresh token. The revoke is conditional, so of two concurrent refreshes with
+	// the same token only one wins; the other presented an already-rotated token
+	if err := s.tokenStore.RevokeRefreshToken(ctx, refreshTokenHash); err != nil {
+		if errors.Is(err, ErrRefreshTokenRevoked) {
+			s.revokeReusedSession(ctx, storedToken, sessionID)
+			return "", "", NewAppError("Token telah dibatalkan (revoked)", 401)
+		}
+		return "", "", NewAppError("Gagal membatalkan refresh token", 500)
+	}
 
 	// Store new refresh token hash
+	// Keep the device of the session unless the request says otherwise
 	newRefreshTokenHash := GenerateTokenHash(newRefreshToken)
+	client, ok := ClientInfoFromContext(ctx)
+	if !ok {
+		client = ClientInfo{UserAgent: storedToken.UserAgent, IPAddress: storedToken.IPAddress}
+	}
 	newRefreshTokenEntity := &RefreshToken{
-		UserID:    user.GetID(),
-		TokenHash: newRefreshTokenHash,
-		ExpiresAt: time.Now().Add(7 * 24 * time.Hour).UTC().Truncate(time.Second),
+		UserID:           user.GetID(),
+		TokenHash:        newRefreshTokenHash,
+		SessionID:        sessionID,
+		UserAgent:        client.UserAgent,
+		IPAddress:        client.IPAddress,
+		ExpiresAt:        expiresAt,
+		RememberMe:       storedToken.RememberMe,
+		SessionExpiresAt: storedToken.SessionExpiresAt,
 	}
 
 	if err := s.tokenStore.SaveRefreshToken(ctx, newRefreshTokenEntity); err != nil {
 		return "", "", NewAppError("Gagal menyimpan refresh token", 500)
 	}
 
+	s.emit(ctx, TokenRefreshed{UserID: user.GetID(), SessionID: sessionID, Time: time.Now()})
 	return newAccessToken, newRefreshToken, nil
 }
 
+// accessTokenExpiry mengembalikan TTL access token dari konfigurasi TokenManager bawaan, atau
+// defaultAccessTokenExpiry untuk TokenManager lain. Session yang dicabut diblokir selama TTL ini
+// agar semua access token yang sudah diterbitkan untuk session tersebut ikut ditolak.
+func (s *AuthService) accessTokenExpiry() time.Duration {
+	var ttl time.Duration
+	switch m := s.tokenManager.(type) {
+	case *JWTManager:
+		ttl = m.config.AccessTokenExpiry
+	case *BrancaManager:
+		ttl = m.config.AccessTokenExpiry
+	}
+	if ttl <= 0 {
+		return defaultAccessTokenExpiry
+	}
+	return ttl
+}
+
+// revokeReusedSession membatalkan semua refresh token dalam session yang sama (sid) dengan token
+// yang dipakai ulang, memblokir access token session tersebut, lalu mengirim SecurityEvent.
+func (s *AuthService) revokeReusedSession(ctx context.Context, token *RefreshToken, sessionID string) {
+	if token.SessionID != "" {
+		sessionID = token.SessionID
+	}
+
+	if s.logger != nil {
+		s.logger.Warn("Refresh token reuse detected, revoking session", "user_id", token.UserID, "session_id", sessionID)
+	}
+
+	// Tokens stored before session IDs were recorded cannot be traced to their family
+	if sessionID != "" {
+		if err := s.tokenStore.RevokeBySession(ctx, sessionID); err != nil && s.logger != nil {
+			s.logger.Error("Failed to revoke session tokens", "session_id", sessionID, "error", err.Error())
+		}
+		if s.blocklist != nil {
+			if err := s.blocklist.Invalidate(ctx, sessionID, s.accessTokenExpiry()); err != nil && s.logger != nil {
+				s.logger.Warn("Failed to blacklist session", "session_id", sessionID, "error", err.Error())
+			}
+		}
+	}
+
+	if s.securityEvents != nil {
+		s.securityEvents(ctx, SecurityEvent{
+			Type:      SecurityEventRefreshTokenReuse,
+			UserID:    token.UserID,
+			SessionID: sessionID,
+			Time:      time.Now(),
+		})
+	}
+}
+
 // RequestPasswordReset memproses permintaan reset password.
 // Akan membuat token reset dan menyimpannya (pengiriman email dilakukan oleh pemanggil).
 // Mengembalikan token reset yang belum di-hash agar bisa dikirim ke user.
 func (s *AuthService) RequestPasswordReset(ctx context.Context, email string) (string, error) {
 	// Validate email
-	v := NewValidator().
+	v := NewValidator().WithLocale(LocaleFromContext(ctx)).
 		Required("email", email).
 		Email("email", email)
 
@@ -305,7 +553,7 @@ func (s *AuthService) RequestPasswordReset(ctx context.Context, email string) (s
 // Setelah berhasil, semua refresh token pengguna akan dihapus untuk alasan keamanan.
 func (s *AuthService) ResetPassword(ctx context.Context, resetTokenStr, newPassword string) error {
 	// Validate input
-	v := NewValidator().
+	v := NewValidator().WithLocale(LocaleFromContext(ctx)).
 		Required("password", newPassword)
 
 	if !v.IsValid() {
@@ -315,7 +563,7 @@ func (s *AuthService) ResetPassword(ctx context.Context, resetTokenStr, newPassw
 	}
 
 	// Validate password strength
-	if err := s.pwValidator.Validate(newPassword); err != nil {
+	if err := s.checkPassword(ctx, LocaleFromContext(ctx), newPassword); err != nil {
 		return err
 	}
 
@@ -343,7 +591,7 @@ func (s *AuthService) ResetPassword(ctx context.Context, resetTokenStr, newPassw
 	}
 
 	// Hash new password
-	passwordHash, err := HashPassword(newPassword)
+	passwordHash, err := s.hasher.Hash(newPassword)
 	if err != nil {
 		return NewAppError("Gagal memproses password hash", 500)
 	}
@@ -362,6 +610,7 @@ func (s *AuthService) ResetPassword(ctx context.Context, resetTokenStr, newPassw
 	// Revoke all user's refresh tokens for security
 	_ = s.tokenStore.RevokeAllUserTokens(ctx, user.GetID())
 
+	s.emit(ctx, PasswordReset{User: user, Time: time.Now()})
 	return nil
 }
 
@@ -374,8 +623,7 @@ func (s *AuthService) Logout(ctx context.Context, refreshTokenStr string) error
 	}
 
 	// 1. Dapatkan Session ID dari Refresh Token
-	// Kita abaikan userID karena tidak digunakan disini
-	_, sid, err := s.tokenManager.VerifyRefreshToken(refreshTokenStr)
+	userID, sid, err := s.tokenManager.VerifyRefreshToken(refreshTokenStr)
 	if err != nil {
 		// Log internal error jika logger tersedia
 		if s.logger != nil {
@@ -398,11 +646,70 @@ func (s *AuthService) Logout(ctx context.Context, refreshTokenStr string) error
 		}
 	}
 
-	// 3. Revoke refresh token (Standard Procedure)
+	// 3. Cabut access token request saat ini (route Logout dilindungi RequireAuth) sampai exp-nya
+	if user, ok := ctx.Value(userKey).(interface{ GetClaims() map[string]interface{} }); ok && s.blocklist != nil {
+		if err := s.revokeClaims(ctx, user.GetClaims()); err != nil && s.logger != nil {
+			s.logger.Warn("Failed to blacklist access token", "session_id", sid, "error", err.Error())
+		}
+	}
+
+	// 4. Revoke refresh token (Standard Procedure). Logout dengan token yang sudah dicabut tetap berhasil
 	refreshTokenHash := GenerateTokenHash(refreshTokenStr)
-	if err := s.tokenStore.RevokeRefreshToken(ctx, refreshTokenHash); err != nil {
+	if err := s.tokenStore.RevokeRefreshToken(ctx, refreshTokenHash); err != nil && !errors.Is(err, ErrRefreshTokenRevoked) {
 		return NewAppError("Gagal logout", 500)
 	}
 
+	s.emit(ctx, Logout{UserID: userID, SessionID: sid, Time: time.Now()})
 	return nil
 }
+
+// RevokeAccessToken mencabut satu access token sebelum kadaluarsa dengan memasukkan claim jti-nya
+// ke TokenBlocklist sampai exp token tersebut. Berbeda dengan Logout, session dan access token
+// lain milik user tetap berlaku. RequireAuth menolak token yang jti-nya dicabut.
+//
+// Parameters:
+//   - ctx: context request
+//   - accessToken: access token yang akan dicabut
+//
+// Returns:
+//   - error: *AppError 400 jika token tidak valid, 500 jika blocklist gagal atau tidak dipasang
+//
+// Example:
+//
+//	token, _ := dim.GetAuthToken(r)
+//	if err := authService.RevokeAccessToken(r.Context(), token); err != nil {
+//	    appErr, _ := dim.AsAppError(err)
+//	    dim.JsonAppError(w, appErr)
+//	}
+func (s *AuthService) RevokeAccessToken(ctx context.Context, accessToken string) error {
+	if s.blocklist == nil {
+		return NewAppError("Token blocklist tidak dikonfigurasi", 500)
+	}
+	claims, err := s.tokenManager.VerifyToken(accessToken)
+	if err != nil {
+		if errors.Is(err, ErrTokenExpired) {
+			return nil
+		}
+		return NewAppError("Token tidak valid", 400)
+	}
+	if err := s.revokeClaims(ctx, claims); err != nil {
+		if s.logger != nil {
+			s.logger.Error("Failed to blacklist access token", "error", err.Error())
+		}
+		return NewAppError("Gagal mencabut token", 500)
+	}
+	return nil
+}
+
+// revokeClaims memasukkan jti token ke blocklist selama sisa umur token (exp - sekarang).
+func (s *AuthService) revokeClaims(ctx context.Context, raw TokenClaims) error {
+	claims, err := ParseClaims(raw)
+	if err != nil {
+		return err
+	}
+	ttl := time.Until(claims.ExpiresAt.Time)
+	if claims.ID == "" || ttl <= 0 {
+		return nil
+	}
+	return s.blocklist.Invalidate(ctx, claims.ID, ttl)
+}
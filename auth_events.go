@@ -0,0 +1,161 @@
+package dim
+
+import (
+	"context"
+	"time"
+)
+
+// Nama event AuthService untuk EventBus.Listen.
+const (
+	EventUserRegistered = "auth.user_registered"
+	EventLoginSucceeded = "auth.login_succeeded"
+	EventLoginFailed    = "auth.login_failed"
+	EventPasswordReset  = "auth.password_reset"
+	EventTokenRefreshed = "auth.token_refreshed"
+	EventLogout         = "auth.logout"
+)
+
+// Metode login pada LoginSucceeded.Method.
+const (
+	LoginMethodPassword  = "password"
+	LoginMethodMFA       = "mfa"
+	LoginMethodMagicLink = "magic_link"
+	LoginMethodPasskey   = "passkey"
+	LoginMethodOAuth     = "oauth"
+)
+
+// Alasan pada LoginFailed.Reason.
+const (
+	LoginFailedInvalidCredentials = "invalid_credentials"
+	LoginFailedEmailNotVerified   = "email_not_verified"
+	LoginFailedInvalidMFACode     = "invalid_mfa_code"
+)
+
+// UserRegistered dipublikasikan setelah Register berhasil menyimpan user baru.
+type UserRegistered struct {
+	User                 Authenticatable
+	VerificationRequired bool
+	Time                 time.Time
+}
+
+// EventName mengimplementasikan Event.
+func (UserRegistered) EventName() string { return EventUserRegistered }
+
+// LoginSucceeded dipublikasikan setiap kali session baru diterbitkan untuk login.
+// MFA bernilai true jika faktor kedua sudah diverifikasi.
+type LoginSucceeded struct {
+	User      Authenticatable
+	Method    string
+	MFA       bool
+	IPAddress string
+	UserAgent string
+	Time      time.Time
+}
+
+// EventName mengimplementasikan Event.
+func (LoginSucceeded) EventName() string { return EventLoginSucceeded }
+
+// LoginFailed dipublikasikan saat Login atau VerifyMFA ditolak. Email kosong untuk kegagalan
+// VerifyMFA; gunakan UserID.
+type LoginFailed struct {
+	Email     string
+	UserID    string
+	Reason    string
+	IPAddress string
+	UserAgent string
+	Time      time.Time
+}
+
+// EventName mengimplementasikan Event.
+func (LoginFailed) EventName() string { return EventLoginFailed }
+
+// PasswordReset dipublikasikan setelah ResetPassword berhasil mengganti password.
+type PasswordReset struct {
+	User Authenticatable
+	Time time.Time
+}
+
+// EventName mengimplementasikan Event.
+func (PasswordReset) EventName() string { return EventPasswordReset }
+
+// TokenRefreshed dipublikasikan setelah RefreshToken menerbitkan pasangan token baru.
+type TokenRefreshed struct {
+	UserID    string
+	SessionID string
+	Time      time.Time
+}
+
+// EventName mengimplementasikan Event.
+func (TokenRefreshed) EventName() string { return EventTokenRefreshed }
+
+// Logout dipublikasikan setelah Logout membatalkan session.
+type Logout struct {
+	UserID    string
+	SessionID string
+	Time      time.Time
+}
+
+// EventName mengimplementasikan Event.
+func (Logout) EventName() string { return EventLogout }
+
+// WithEventBus memasang EventBus yang menerima event AuthService (UserRegistered, LoginSucceeded,
+// LoginFailed, PasswordReset, TokenRefreshed, Logout) lalu mengembalikan instance service.
+// Event dipublikasikan setelah aksi selesai; error listener sinkron dicatat ke logger dan tidak
+// menggagalkan aksi tersebut.
+//
+// Example:
+//
+//	bus := dim.NewEventBus()
+//	dim.SubscribeAsync(bus, func(ctx context.Context, e dim.UserRegistered) error {
+//	    return sendWelcomeEmail(ctx, e.User)
+//	})
+//	dim.Subscribe(bus, func(ctx context.Context, e dim.LoginFailed) error {
+//	    auditLog.Record(ctx, "login_failed", e.Email, e.Reason, e.IPAddress)
+//	    return nil
+//	})
+//	authService.WithEventBus(bus)
+func (s *AuthService) WithEventBus(bus *EventBus) *AuthService {
+	s.events = bus
+	return s
+}
+
+// emit mempublikasikan event ke EventBus service jika dipasang.
+func (s *AuthService) emit(ctx context.Context, event Event) {
+	if s.events == nil {
+		return
+	}
+	if err := s.events.Publish(ctx, event); err != nil && s.logger != nil {
+		s.logger.Warn("Auth event listener failed", "event", event.EventName(), "error", err.Error())
+	}
+}
+
+// login menerbitkan session untuk user yang sudah terotentikasi lalu mempublikasikan LoginSucceeded.
+func (s *AuthService) login(ctx context.Context, user Authenticatable, method string, mfa bool) (string, string, error) {
+	accessToken, refreshToken, err := s.issueTokens(ctx, user, mfa)
+	if err != nil {
+		return "", "", err
+	}
+	client, _ := ClientInfoFromContext(ctx)
+	s.emit(ctx, LoginSucceeded{
+		User:      user,
+		Method:    method,
+		MFA:       mfa,
+		IPAddress: client.IPAddress,
+		UserAgent: client.UserAgent,
+		Time:      time.Now(),
+	})
+	return accessToken, refreshToken, nil
+}
+
+// loginFailed mempublikasikan LoginFailed dengan info client dari ctx.
+func (s *AuthService) loginFailed(ctx context.Context, email, userID, reason string) {
+	client, _ := ClientInfoFromContext(ctx)
+	s.emit(ctx, LoginFailed{
+		Email:     email,
+		UserID:    userID,
+		Reason:    reason,
+		IPAddress: client.IPAddress,
+		UserAgent: client.UserAgent,
+		Time:      time.Now(),
+	})
+}
@@ -0,0 +1,82 @@
+package dim
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAuthService_Events(t *testing.T) {
+	ctx := WithClientInfo(context.Background(), ClientInfo{IPAddress: "203.0.113.7", UserAgent: "test"})
+	service, _, _ := newTestRegisterService(t, RegistrationOptions{})
+
+	var mu sync.Mutex
+	var events []Event
+	bus := NewEventBus().Listen("*", func(ctx context.Context, event Event) error {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, event)
+		return nil
+	})
+	registered := make(chan UserRegistered, 1)
+	SubscribeAsync(bus, func(ctx context.Context, e UserRegistered) error {
+		registered <- e
+		return nil
+	})
+	service.WithEventBus(bus)
+
+	if _, err := service.Register(ctx, RegisterRequest{Email: "ana@example.com", Password: "ValidPass123!"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := service.Login(ctx, "ana@example.com", "WrongPass123!"); err == nil {
+		t.Fatal("expected login with wrong password to fail")
+	}
+	_, refresh, err := service.Login(ctx, "ana@example.com", "ValidPass123!")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, refresh, err = service.RefreshToken(ctx, refresh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := service.Logout(ctx, refresh); err != nil {
+		t.Fatal(err)
+	}
+	reset, err := service.RequestPasswordReset(ctx, "ana@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := service.ResetPassword(ctx, reset, "NewPass123!"); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case e := <-registered:
+		if e.User.GetEmail() != "ana@example.com" || e.VerificationRequired {
+			t.Errorf("async UserRegistered = %+v", e)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("async UserRegistered listener was not called")
+	}
+
+	want := []string{EventUserRegistered, EventLoginFailed, EventLoginSucceeded, EventTokenRefreshed, EventLogout, EventPasswordReset}
+	if len(events) != len(want) {
+		t.Fatalf("events = %v, want %v", events, want)
+	}
+	for i := range want {
+		if events[i].EventName() != want[i] {
+			t.Fatalf("event %d = %s, want %s", i, events[i].EventName(), want[i])
+		}
+	}
+	if e := events[1].(LoginFailed); e.Email != "ana@example.com" || e.Reason != LoginFailedInvalidCredentials || e.IPAddress != "203.0.113.7" {
+		t.Errorf("LoginFailed = %+v", e)
+	}
+	if e := events[2].(LoginSucceeded); e.Method != LoginMethodPassword || e.User.GetEmail() != "ana@example.com" || e.UserAgent != "test" {
+		t.Errorf("LoginSucceeded = %+v", e)
+	}
+	refreshed, logout := events[3].(TokenRefreshed), events[4].(Logout)
+	if refreshed.SessionID == "" || logout.SessionID != refreshed.SessionID || logout.UserID != refreshed.UserID {
+		t.Errorf("TokenRefreshed = %+v, Logout = %+v", refreshed, logout)
+	}
+}
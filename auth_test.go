@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
@@ -39,14 +40,14 @@ func (s *MockUserStore) FindByEmail(ctx context.Context, email string) (Authenti
 			return u, nil
 		}
 	}
-	return nil, errors.New("user not found")
+	return nil, ErrUserNotFound
 }
 
 func (s *MockUserStore) FindByID(ctx context.Context, id string) (Authenticatable, error) {
 	if u, ok := s.users[id]; ok {
 		return u, nil
 	}
-	return nil, errors.New("user not found")
+	return nil, ErrUserNotFound
 }
 
 func (s *MockUserStore) Update(ctx context.Context, user Authenticatable) error {
@@ -170,6 +171,122 @@ func TestRefreshTokenSuccess(t *testing.T) {
 	}
 }
 
+func TestRefreshToken_ReuseRevokesSession(t *testing.T) {
+	userStore := NewMockUserStore()
+	tokenStore := NewMockTokenStore()
+	blocklist := NewInMemoryBlocklist()
+	config := &JWTConfig{
+		HMACSecret:         "test-secret",
+		SigningMethod:      "HS256",
+		AccessTokenExpiry:  15 * time.Minute,
+		RefreshTokenExpiry: 7 * 24 * time.Hour,
+	}
+
+	hashedPassword, _ := HashPassword("ValidPass123!")
+	userStore.AddUser(&MockUser{ID: "1", Email: "test@example.com", Password: hashedPassword})
+
+	service, err := NewAuthService(userStore, tokenStore, blocklist, config)
+	if err != nil {
+		t.Fatalf("NewAuthService error: %v", err)
+	}
+	var events []SecurityEvent
+	service.OnSecurityEvent(func(ctx context.Context, event SecurityEvent) {
+		events = append(events, event)
+	})
+	ctx := context.Background()
+
+	_, stolen, _ := service.Login(ctx, "test@example.com", "ValidPass123!")
+	_, otherSession, _ := service.Login(ctx, "test@example.com", "ValidPass123!")
+	_, rotated, err := service.RefreshToken(ctx, stolen)
+	if err != nil {
+		t.Fatalf("RefreshToken() error = %v", err)
+	}
+
+	// Token lama yang sudah di-rotate dipakai lagi
+	if _, _, err := service.RefreshToken(ctx, stolen); err == nil {
+		t.Fatal("expected reused refresh token to be rejected")
+	}
+	if _, _, err := service.RefreshToken(ctx, rotated); err == nil {
+		t.Error("expected rotated token of the same session to be revoked")
+	}
+	if _, _, err := service.RefreshToken(ctx, otherSession); err != nil {
+		t.Errorf("other session should stay valid: %v", err)
+	}
+
+	_, sid, _ := service.tokenManager.VerifyRefreshToken(stolen)
+	if revoked, _ := blocklist.IsRevoked(ctx, sid); !revoked {
+		t.Error("expected session to be blocklisted")
+	}
+	if len(events) == 0 || events[0].Type != SecurityEventRefreshTokenReuse || events[0].UserID != "1" || events[0].SessionID != sid {
+		t.Errorf("unexpected security events: %+v", events)
+	}
+}
+
+// racingTokenStore mensimulasikan refresh bersamaan: token dicabut oleh request lain tepat
+// setelah FindRefreshToken.
+type racingTokenStore struct {
+	*MockTokenStore
+}
+
+func (s *racingTokenStore) FindRefreshToken(ctx context.Context, tokenHash string) (*RefreshToken, error) {
+	token, err := s.MockTokenStore.FindRefreshToken(ctx, tokenHash)
+	if err != nil {
+		return nil, err
+	}
+	found := *token
+	_ = s.MockTokenStore.RevokeRefreshToken(ctx, tokenHash)
+	return &found, nil
+}
+
+// ttlBlocklist mencatat durasi blocklist per identifier.
+type ttlBlocklist struct {
+	*InMemoryBlocklist
+	ttls map[string]time.Duration
+}
+
+func (b *ttlBlocklist) Invalidate(ctx context.Context, identifier string, expiresIn time.Duration) error {
+	b.ttls[identifier] = expiresIn
+	return b.InMemoryBlocklist.Invalidate(ctx, identifier, expiresIn)
+}
+
+func TestRefreshToken_ConcurrentRotationIsReuse(t *testing.T) {
+	userStore := NewMockUserStore()
+	tokenStore := &racingTokenStore{NewMockTokenStore()}
+	blocklist := &ttlBlocklist{InMemoryBlocklist: NewInMemoryBlocklist(), ttls: map[string]time.Duration{}}
+	config := &JWTConfig{
+		HMACSecret:         "test-secret",
+		SigningMethod:      "HS256",
+		AccessTokenExpiry:  15 * time.Minute,
+		RefreshTokenExpiry: 7 * 24 * time.Hour,
+	}
+
+	hashedPassword, _ := HashPassword("ValidPass123!")
+	userStore.AddUser(&MockUser{ID: "1", Email: "test@example.com", Password: hashedPassword})
+
+	service, err := NewAuthService(userStore, tokenStore, blocklist, config)
+	if err != nil {
+		t.Fatalf("NewAuthService error: %v", err)
+	}
+	var events []SecurityEvent
+	service.OnSecurityEvent(func(ctx context.Context, event SecurityEvent) {
+		events = append(events, event)
+	})
+	ctx := context.Background()
+
+	_, refresh, _ := service.Login(ctx, "test@example.com", "ValidPass123!")
+	if _, _, err := service.RefreshToken(ctx, refresh); err == nil {
+		t.Fatal("expected refresh that loses the revoke race to be rejected")
+	}
+
+	_, sid, _ := service.tokenManager.VerifyRefreshToken(refresh)
+	if len(events) != 1 || events[0].Type != SecurityEventRefreshTokenReuse || events[0].SessionID != sid {
+		t.Errorf("unexpected security events: %+v", events)
+	}
+	if ttl := blocklist.ttls[sid]; ttl != config.AccessTokenExpiry {
+		t.Errorf("session blocklisted for %v, want access token expiry %v", ttl, config.AccessTokenExpiry)
+	}
+}
+
 func TestLogoutSuccess(t *testing.T) {
 	userStore := NewMockUserStore()
 	tokenStore := NewMockTokenStore()
@@ -435,3 +552,63 @@ func TestWithClaimsProvider(t *testing.T) {
 		t.Errorf("Expected workspace_id 'workspace-456' after refresh, got %v", newClaims["workspace_id"])
 	}
 }
+
+func newTestRevocationService(t *testing.T) (*AuthService, *InMemoryBlocklist) {
+	t.Helper()
+	userStore := NewMockUserStore()
+	hashedPassword, _ := HashPassword("ValidPass123!")
+	userStore.AddUser(&MockUser{ID: "1", Email: "test@example.com", Password: hashedPassword})
+
+	blocklist := NewInMemoryBlocklist()
+	service, err := NewAuthService(userStore, NewMockTokenStore(), blocklist, &JWTConfig{
+		HMACSecret:         "test-secret",
+		SigningMethod:      "HS256",
+		AccessTokenExpiry:  15 * time.Minute,
+		RefreshTokenExpiry: time.Hour,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return service, blocklist
+}
+
+func TestRevokeAccessToken(t *testing.T) {
+	service, blocklist := newTestRevocationService(t)
+	ctx := context.Background()
+	accessToken, _, _ := service.Login(ctx, "test@example.com", "ValidPass123!")
+	otherToken, _, _ := service.Login(ctx, "test@example.com", "ValidPass123!")
+
+	if err := service.RevokeAccessToken(ctx, accessToken); err != nil {
+		t.Fatalf("RevokeAccessToken() error = %v", err)
+	}
+	claims, _ := service.tokenManager.VerifyToken(accessToken)
+	if revoked, _ := blocklist.IsRevoked(ctx, claims["jti"].(string)); !revoked {
+		t.Error("jti should be blocklisted")
+	}
+	if revoked, _ := blocklist.IsRevoked(ctx, claims["sid"].(string)); revoked {
+		t.Error("session should stay valid")
+	}
+	other, _ := service.tokenManager.VerifyToken(otherToken)
+	if revoked, _ := blocklist.IsRevoked(ctx, other["jti"].(string)); revoked {
+		t.Error("other access tokens should stay valid")
+	}
+
+	var appErr *AppError
+	if err := service.RevokeAccessToken(ctx, "garbage"); !errors.As(err, &appErr) || appErr.StatusCode != 400 {
+		t.Errorf("invalid token: err = %v, want AppError 400", err)
+	}
+}
+
+func TestLogoutRevokesCurrentAccessToken(t *testing.T) {
+	service, blocklist := newTestRevocationService(t)
+	accessToken, refreshToken, _ := service.Login(context.Background(), "test@example.com", "ValidPass123!")
+	claims, _ := service.tokenManager.VerifyToken(accessToken)
+
+	req := SetUser(httptest.NewRequest("POST", "/logout", nil), &TokenUser{ID: "1", Claims: claims})
+	if err := service.Logout(req.Context(), refreshToken); err != nil {
+		t.Fatalf("Logout() error = %v", err)
+	}
+	if revoked, _ := blocklist.IsRevoked(context.Background(), claims["jti"].(string)); !revoked {
+		t.Error("access token of the logout request should be blocklisted by jti")
+	}
+}
@@ -2,10 +2,21 @@ package dim
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
 )
 
+// isNoRows reports whether err means the query matched no rows, for both pgx and database/sql drivers.
+func isNoRows(err error) bool {
+	return errors.Is(err, pgx.ErrNoRows) || errors.Is(err, sql.ErrNoRows)
+}
+
 // DatabaseAuthUserStore is a generic implementation of AuthUserStore for SQL databases.
-// It assumes a standard 'users' table structure.
+// It assumes the 'users' table from GetUserMigrations and works with PostgreSQL, MySQL and SQLite.
+// Soft-deleted users are hidden from the finders unless the context comes from WithTrashed.
 type DatabaseAuthUserStore struct {
 	db Database
 }
@@ -22,8 +33,8 @@ func NewAuthUserStore(db Database) *DatabaseAuthUserStore {
 
 func (s *DatabaseAuthUserStore) FindByEmail(ctx context.Context, email string) (Authenticatable, error) {
 	user := &TokenUser{}
-	query := s.db.Rebind(`SELECT id, email, password FROM users WHERE email = $1`)
-	err := s.db.QueryRow(ctx, query, email).Scan(&user.ID, &user.Email, &user.Password)
+	query := s.db.Rebind(softDeleteAnd(ctx, `SELECT id, email, password, deleted_at FROM users WHERE email = $1`))
+	err := s.db.QueryRow(ctx, query, email).Scan(&user.ID, &user.Email, &user.Password, &user.DeletedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -32,8 +43,8 @@ func (s *DatabaseAuthUserStore) FindByEmail(ctx context.Context, email string) (
 
 func (s *DatabaseAuthUserStore) FindByID(ctx context.Context, id string) (Authenticatable, error) {
 	user := &TokenUser{}
-	query := s.db.Rebind(`SELECT id, email, password FROM users WHERE id = $1`)
-	err := s.db.QueryRow(ctx, query, id).Scan(&user.ID, &user.Email, &user.Password)
+	query := s.db.Rebind(softDeleteAnd(ctx, `SELECT id, email, password, deleted_at FROM users WHERE id = $1`))
+	err := s.db.QueryRow(ctx, query, id).Scan(&user.ID, &user.Email, &user.Password, &user.DeletedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -41,6 +52,21 @@ func (s *DatabaseAuthUserStore) FindByID(ctx context.Context, id string) (Authen
 }
 
 func (s *DatabaseAuthUserStore) Update(ctx context.Context, user Authenticatable) error {
-	query := s.db.Rebind(`UPDATE users SET email = $1, password = $2 WHERE id = $3`)
+	query := s.db.Rebind(`UPDATE users SET email = $1, password = $2, version = version + 1 WHERE id = $3`)
 	return s.db.Exec(ctx, query, user.GetEmail(), user.GetPassword(), user.GetID())
 }
+
+// SoftDelete marks the user as deleted by setting deleted_at. The row is kept, so the email
+// stays taken and refresh tokens remain until revoked. Deleting an already deleted user keeps
+// the original deleted_at.
+func (s *DatabaseAuthUserStore) SoftDelete(ctx context.Context, id string) error {
+	now := time.Now().UTC().Truncate(time.Second)
+	query := s.db.Rebind(`UPDATE users SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL`)
+	return s.db.Exec(ctx, query, now, id)
+}
+
+// Restore clears deleted_at so the user is visible to the finders again.
+func (s *DatabaseAuthUserStore) Restore(ctx context.Context, id string) error {
+	query := s.db.Rebind(`UPDATE users SET deleted_at = NULL WHERE id = $1`)
+	return s.db.Exec(ctx, query, id)
+}
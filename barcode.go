@@ -0,0 +1,233 @@
+package dim
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+)
+
+// code128Patterns adalah lebar bar/space (dalam modul) untuk simbol 0-105 dan stop (106).
+var code128Patterns = [107]string{
+	"212222", "222122", "222221", "121223", "121322", "131222", "122213", "122312", "132212", "221213",
+	"221312", "231212", "112232", "122132", "122231", "113222", "123122", "123221", "223211", "221132",
+	"221231", "213212", "223112", "312131", "311222", "321122", "321221", "312212", "322112", "322211",
+	"212123", "212321", "232121", "111323", "131123", "131321", "112313", "132113", "132311", "211313",
+	"231113", "231311", "112133", "112331", "132131", "113123", "113321", "133121", "313121", "211331",
+	"231131", "213113", "213311", "213131", "311123", "311321", "331121", "312113", "312311", "332111",
+	"314111", "221411", "431111", "111224", "111422", "121124", "121421", "141122", "141221", "112214",
+	"112412", "122114", "122411", "142112", "142211", "241211", "221114", "413111", "241112", "134111",
+	"111242", "121142", "121241", "114212", "124112", "124211", "411212", "421112", "421211", "212141",
+	"214121", "412121", "111143", "111341", "131141", "114113", "114311", "411113", "411311", "113141",
+	"114131", "311141", "411131", "211412", "211214", "211232", "2331112",
+}
+
+const (
+	code128CodeC  = 99
+	code128CodeB  = 100
+	code128StartB = 104
+	code128StartC = 105
+	code128Stop   = 106
+)
+
+// ErrBarcodeInvalidContent dikembalikan jika konten kosong atau berisi karakter di luar ASCII 32-126.
+var ErrBarcodeInvalidContent = errors.New("barcode: content must be printable ASCII")
+
+// Code128 adalah barcode Code 128 hasil encode.
+// Teks di-encode dengan code set B; deretan angka (minimal 4 digit) otomatis memakai
+// code set C agar barcode lebih pendek, misal untuk nomor resi atau SKU numerik.
+type Code128 struct {
+	Content string
+
+	bars []bool // satu elemen per modul, true = bar
+}
+
+// NewCode128 meng-encode content menjadi barcode Code 128.
+//
+// Parameters:
+//   - content: teks ASCII printable (32-126)
+//
+// Returns:
+//   - *Code128: barcode hasil encode
+//   - error: ErrBarcodeInvalidContent jika content kosong atau tidak valid
+//
+// Example:
+//
+//	barcode, err := dim.NewCode128("INV-2024-000123")
+func NewCode128(content string) (*Code128, error) {
+	if content == "" {
+		return nil, ErrBarcodeInvalidContent
+	}
+	for i := 0; i < len(content); i++ {
+		if content[i] < 32 || content[i] > 126 {
+			return nil, ErrBarcodeInvalidContent
+		}
+	}
+
+	var symbols []int
+	set := 0
+	for i := 0; i < len(content); {
+		digits := 0
+		for i+digits < len(content) && content[i+digits] >= '0' && content[i+digits] <= '9' {
+			digits++
+		}
+
+		if digits >= 4 {
+			switch {
+			case len(symbols) == 0:
+				symbols = append(symbols, code128StartC)
+			case set != code128StartC:
+				symbols = append(symbols, code128CodeC)
+			}
+			set = code128StartC
+			for end := i + digits - digits%2; i < end; i += 2 {
+				symbols = append(symbols, int(content[i]-'0')*10+int(content[i+1]-'0'))
+			}
+			continue
+		}
+
+		switch {
+		case len(symbols) == 0:
+			symbols = append(symbols, code128StartB)
+		case set != code128StartB:
+			symbols = append(symbols, code128CodeB)
+		}
+		set = code128StartB
+		symbols = append(symbols, int(content[i])-32)
+		i++
+	}
+
+	checksum := symbols[0]
+	for i := 1; i < len(symbols); i++ {
+		checksum += i * symbols[i]
+	}
+	symbols = append(symbols, checksum%103, code128Stop)
+
+	var bars []bool
+	for _, symbol := range symbols {
+		for i, width := range code128Patterns[symbol] {
+			for n := 0; n < int(width-'0'); n++ {
+				bars = append(bars, i%2 == 0)
+			}
+		}
+	}
+
+	return &Code128{Content: content, bars: bars}, nil
+}
+
+// Modules mengembalikan lebar barcode dalam modul, tanpa quiet zone.
+func (b *Code128) Modules() int {
+	return len(b.bars)
+}
+
+// Bar melaporkan apakah modul ke-i adalah bar (gelap).
+func (b *Code128) Bar(i int) bool {
+	return i >= 0 && i < len(b.bars) && b.bars[i]
+}
+
+// BarcodeOptions mengatur ukuran gambar barcode.
+type BarcodeOptions struct {
+	Width  int // lebar minimal dalam pixel; default 2 pixel per modul
+	Height int // tinggi dalam pixel; default 80
+	Margin int // quiet zone kiri/kanan dalam modul; default 10
+}
+
+func (o BarcodeOptions) layout(modules int) (cols, scale, height int) {
+	margin := o.Margin
+	if margin <= 0 {
+		margin = 10
+	}
+	cols = modules + 2*margin
+	scale = max(2, (o.Width+cols-1)/cols)
+	height = o.Height
+	if height <= 0 {
+		height = 80
+	}
+	return cols, scale, height
+}
+
+// Code128PNG meng-encode content menjadi barcode Code 128 dalam format PNG.
+//
+// Example:
+//
+//	img, err := dim.Code128PNG("INV-2024-000123", dim.BarcodeOptions{Height: 60})
+func Code128PNG(content string, opts BarcodeOptions) ([]byte, error) {
+	barcode, err := NewCode128(content)
+	if err != nil {
+		return nil, err
+	}
+	cols, scale, height := opts.layout(barcode.Modules())
+	margin := (cols - barcode.Modules()) / 2
+	return encodeModulesPNG(cols, 1, scale, height, func(x, _ int) bool {
+		return barcode.Bar(x - margin)
+	})
+}
+
+// Code128SVG meng-encode content menjadi barcode Code 128 dalam format SVG.
+//
+// Example:
+//
+//	svg, err := dim.Code128SVG("8991234567890", dim.BarcodeOptions{})
+func Code128SVG(content string, opts BarcodeOptions) ([]byte, error) {
+	barcode, err := NewCode128(content)
+	if err != nil {
+		return nil, err
+	}
+	cols, scale, height := opts.layout(barcode.Modules())
+	margin := (cols - barcode.Modules()) / 2
+	return encodeModulesSVG(cols, 1, cols*scale, height, func(x, _ int) bool {
+		return barcode.Bar(x - margin)
+	}), nil
+}
+
+// encodeModulesPNG merender grid modul cols x rows menjadi PNG grayscale.
+// Setiap modul berukuran scaleX x scaleY pixel.
+func encodeModulesPNG(cols, rows, scaleX, scaleY int, dark func(x, y int) bool) ([]byte, error) {
+	img := image.NewGray(image.Rect(0, 0, cols*scaleX, rows*scaleY))
+	for y := 0; y < rows; y++ {
+		for x := 0; x < cols; x++ {
+			c := color.Gray{Y: 0xFF}
+			if dark(x, y) {
+				c = color.Gray{Y: 0x00}
+			}
+			for py := y * scaleY; py < (y+1)*scaleY; py++ {
+				for px := x * scaleX; px < (x+1)*scaleX; px++ {
+					img.SetGray(px, py, c)
+				}
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode png: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// encodeModulesSVG merender grid modul menjadi SVG dengan satu path.
+// Modul gelap yang berdampingan dalam satu baris digabung agar ukuran file kecil.
+func encodeModulesSVG(cols, rows, width, height int, dark func(x, y int) bool) []byte {
+	var path strings.Builder
+	for y := 0; y < rows; y++ {
+		for x := 0; x < cols; {
+			if !dark(x, y) {
+				x++
+				continue
+			}
+			start := x
+			for x < cols && dark(x, y) {
+				x++
+			}
+			fmt.Fprintf(&path, "M%d %dh%dv1h-%dz", start, y, x-start, x-start)
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" preserveAspectRatio="none" shape-rendering="crispEdges">`, width, height, cols, rows)
+	fmt.Fprintf(&buf, `<rect width="%d" height="%d" fill="#fff"/><path fill="#000" d="%s"/></svg>`, cols, rows, path.String())
+	return buf.Bytes()
+}
@@ -0,0 +1,163 @@
+package dim
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// QRHandler membuat handler yang merender QR code dari payload bertanda tangan.
+// Hanya URL hasil signer.SignQuery yang dilayani; payload tanpa signature, signature salah,
+// atau yang sudah kadaluarsa ditolak dengan 403 sehingga endpoint tidak bisa dipakai
+// sebagai generator QR code terbuka.
+//
+// Parameter query opsional:
+//   - format: png (default) atau svg
+//   - size: lebar gambar dalam pixel (64-1024, default 256)
+//   - level: L, M (default), Q, atau H
+//
+// Example:
+//
+//	signer := dim.NewPayloadSigner(cfg.QRSecret)
+//	router.Get("/qr", dim.QRHandler(signer))
+//
+//	// Di handler lain: URL gambar untuk link pembayaran
+//	src := "/qr?" + signer.SignQuery(paymentURL, 15*time.Minute).Encode() + "&size=300"
+func QRHandler(signer *PayloadSigner) HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		payload, ok := verifySignedCodeRequest(w, r, signer)
+		if !ok {
+			return
+		}
+
+		q := r.URL.Query()
+		errs := FieldErrors{}
+		format := codeImageFormat(q.Get("format"), errs)
+		size := codeImageDimension(q.Get("size"), 256, 64, 1024, "size", errs)
+		level := QRLevel(strings.ToUpper(q.Get("level")))
+		if _, ok := level.index(); !ok {
+			errs["level"] = "harus L, M, Q, atau H"
+		}
+		if len(errs) > 0 {
+			BadRequest(w, "Parameter tidak valid", errs)
+			return
+		}
+
+		opts := QROptions{Level: level, Size: size}
+		var img []byte
+		var err error
+		if format == "svg" {
+			img, err = QRCodeSVG(payload, opts)
+		} else {
+			img, err = QRCodePNG(payload, opts)
+		}
+		if err != nil {
+			writeCodeImageError(w, err)
+			return
+		}
+		writeCodeImage(w, format, img)
+	}
+}
+
+// BarcodeHandler membuat handler yang merender barcode Code 128 dari payload bertanda tangan,
+// dengan aturan signature yang sama seperti QRHandler.
+//
+// Parameter query opsional:
+//   - format: png (default) atau svg
+//   - width: lebar minimal dalam pixel (maksimal 2048)
+//   - height: tinggi dalam pixel (20-600, default 80)
+//
+// Example:
+//
+//	router.Get("/barcode", dim.BarcodeHandler(signer))
+//	src := "/barcode?" + signer.SignQuery(shipment.TrackingNumber, 0).Encode()
+func BarcodeHandler(signer *PayloadSigner) HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		payload, ok := verifySignedCodeRequest(w, r, signer)
+		if !ok {
+			return
+		}
+
+		q := r.URL.Query()
+		errs := FieldErrors{}
+		format := codeImageFormat(q.Get("format"), errs)
+		width := codeImageDimension(q.Get("width"), 0, 0, 2048, "width", errs)
+		height := codeImageDimension(q.Get("height"), 80, 20, 600, "height", errs)
+		if len(errs) > 0 {
+			BadRequest(w, "Parameter tidak valid", errs)
+			return
+		}
+
+		opts := BarcodeOptions{Width: width, Height: height}
+		var img []byte
+		var err error
+		if format == "svg" {
+			img, err = Code128SVG(payload, opts)
+		} else {
+			img, err = Code128PNG(payload, opts)
+		}
+		if err != nil {
+			writeCodeImageError(w, err)
+			return
+		}
+		writeCodeImage(w, format, img)
+	}
+}
+
+func verifySignedCodeRequest(w http.ResponseWriter, r *http.Request, signer *PayloadSigner) (string, bool) {
+	payload, err := signer.VerifyQuery(r.URL.Query())
+	if err != nil {
+		Forbidden(w, "Tautan tidak valid atau telah kadaluarsa")
+		return "", false
+	}
+	return payload, true
+}
+
+func codeImageFormat(value string, errs FieldErrors) string {
+	switch value {
+	case "", "png":
+		return "png"
+	case "svg":
+		return "svg"
+	}
+	errs["format"] = "harus png atau svg"
+	return ""
+}
+
+// codeImageDimension membaca parameter ukuran dan membatasinya ke rentang [lower, upper].
+func codeImageDimension(value string, fallback, lower, upper int, field string, errs FieldErrors) int {
+	if value == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		errs[field] = "harus berupa angka"
+		return 0
+	}
+	return min(max(n, lower), upper)
+}
+
+func writeCodeImageError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrQRTooLong):
+		BadRequest(w, "Konten terlalu panjang untuk QR code", nil)
+	case errors.Is(err, ErrBarcodeInvalidContent):
+		BadRequest(w, "Konten barcode tidak valid", nil)
+	default:
+		InternalServerError(w, "Gagal membuat gambar")
+	}
+}
+
+func writeCodeImage(w http.ResponseWriter, format string, img []byte) {
+	if format == "svg" {
+		w.Header().Set("Content-Type", "image/svg+xml")
+	} else {
+		w.Header().Set("Content-Type", "image/png")
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(len(img)))
+	w.Header().Set("Cache-Control", "private, max-age=300")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	w.Write(img) //nolint:errcheck
+}
@@ -0,0 +1,103 @@
+package dim
+
+import (
+	"bytes"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func serveCodeImage(handler HandlerFunc, query string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest(http.MethodGet, "/code?"+query, nil))
+	return w
+}
+
+func TestQRHandler(t *testing.T) {
+	signer := NewPayloadSigner("qr-secret")
+	handler := QRHandler(signer)
+	signed := signer.SignQuery("https://example.com/pay/INV-001", time.Minute).Encode()
+
+	t.Run("png", func(t *testing.T) {
+		w := serveCodeImage(handler, signed+"&size=128&level=h")
+		if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "image/png" {
+			t.Fatalf("status = %d, content-type = %q, body = %s", w.Code, w.Header().Get("Content-Type"), w.Body.String())
+		}
+		img, err := png.Decode(bytes.NewReader(w.Body.Bytes()))
+		if err != nil {
+			t.Fatalf("invalid png: %v", err)
+		}
+		if img.Bounds().Dx() < 128 {
+			t.Errorf("width = %d, want at least 128", img.Bounds().Dx())
+		}
+		if w.Header().Get("X-Content-Type-Options") != "nosniff" {
+			t.Error("expected nosniff header")
+		}
+	})
+
+	t.Run("svg", func(t *testing.T) {
+		w := serveCodeImage(handler, signed+"&format=svg")
+		if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "image/svg+xml" || !strings.HasPrefix(w.Body.String(), "<svg") {
+			t.Errorf("status = %d, content-type = %q", w.Code, w.Header().Get("Content-Type"))
+		}
+	})
+
+	forbidden := map[string]string{
+		"unsigned": "data=https://evil.example.com",
+		"tampered": strings.Replace(signed, "INV-001", "INV-002", 1),
+	}
+	expired := NewPayloadSigner("qr-secret")
+	expired.now = func() time.Time { return time.Now().Add(-time.Hour) }
+	forbidden["expired"] = expired.SignQuery("x", time.Minute).Encode()
+
+	for name, query := range forbidden {
+		t.Run(name, func(t *testing.T) {
+			if w := serveCodeImage(handler, query); w.Code != http.StatusForbidden {
+				t.Errorf("status = %d, want 403", w.Code)
+			}
+		})
+	}
+
+	t.Run("invalid parameters", func(t *testing.T) {
+		w := serveCodeImage(handler, signed+"&format=gif&size=big&level=Z")
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("status = %d, want 400", w.Code)
+		}
+		for _, field := range []string{"format", "size", "level"} {
+			if !strings.Contains(w.Body.String(), `"`+field+`"`) {
+				t.Errorf("expected field error for %s in %s", field, w.Body.String())
+			}
+		}
+	})
+
+	t.Run("content too long", func(t *testing.T) {
+		long := signer.SignQuery(strings.Repeat("x", 1300), time.Minute).Encode()
+		if w := serveCodeImage(handler, long+"&level=H"); w.Code != http.StatusBadRequest {
+			t.Errorf("status = %d, want 400", w.Code)
+		}
+	})
+}
+
+func TestBarcodeHandler(t *testing.T) {
+	signer := NewPayloadSigner("barcode-secret")
+	handler := BarcodeHandler(signer)
+
+	w := serveCodeImage(handler, signer.SignQuery("JNE-0012345678", 0).Encode()+"&height=40")
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "image/png" {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	img, err := png.Decode(bytes.NewReader(w.Body.Bytes()))
+	if err != nil || img.Bounds().Dy() != 40 {
+		t.Errorf("unexpected image: %v", err)
+	}
+
+	if w := serveCodeImage(handler, signer.SignQuery("café", 0).Encode()); w.Code != http.StatusBadRequest {
+		t.Errorf("invalid content: status = %d, want 400", w.Code)
+	}
+	if w := serveCodeImage(handler, "data=123"); w.Code != http.StatusForbidden {
+		t.Errorf("unsigned: status = %d, want 403", w.Code)
+	}
+}
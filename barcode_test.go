@@ -0,0 +1,119 @@
+package dim
+
+import (
+	"bytes"
+	"errors"
+	"image/png"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// code128BarsForTest expands symbol values (including checksum and stop) into modules.
+func code128BarsForTest(symbols ...int) []bool {
+	var bars []bool
+	for _, symbol := range symbols {
+		for i, width := range code128Patterns[symbol] {
+			for n := 0; n < int(width-'0'); n++ {
+				bars = append(bars, i%2 == 0)
+			}
+		}
+	}
+	return bars
+}
+
+// TestCode128Patterns tests that every symbol is 11 modules wide and the stop symbol 13
+func TestCode128Patterns(t *testing.T) {
+	for symbol, pattern := range code128Patterns {
+		sum := 0
+		for _, width := range pattern {
+			sum += int(width - '0')
+		}
+		want := 11
+		if symbol == code128Stop {
+			want = 13
+		}
+		if sum != want {
+			t.Errorf("symbol %d: width %d, want %d", symbol, sum, want)
+		}
+	}
+}
+
+// TestNewCode128 tests code set selection and checksum
+func TestNewCode128(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		symbols []int
+	}{
+		// Check symbol 55 from the standard "PJJ123C" example
+		{"code set B", "PJJ123C", []int{code128StartB, 48, 42, 42, 17, 18, 19, 35, 55, code128Stop}},
+		{"code set C", "12345678", []int{code128StartC, 12, 34, 56, 78, 47, code128Stop}},
+		{"odd digit run", "AB1234567", []int{code128StartB, 33, 34, code128CodeC, 12, 34, 56, code128CodeB, 23, 86, code128Stop}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			barcode, err := NewCode128(tt.content)
+			if err != nil {
+				t.Fatalf("NewCode128 error: %v", err)
+			}
+			if !reflect.DeepEqual(barcode.bars, code128BarsForTest(tt.symbols...)) {
+				t.Errorf("bars do not match symbols %v", tt.symbols)
+			}
+			if barcode.Modules() != 11*(len(tt.symbols)-1)+13 {
+				t.Errorf("Modules() = %d", barcode.Modules())
+			}
+			if !barcode.Bar(0) || barcode.Bar(-1) || barcode.Bar(barcode.Modules()) {
+				t.Error("expected leading bar and light out-of-range modules")
+			}
+		})
+	}
+}
+
+// TestNewCode128_InvalidContent tests rejected input
+func TestNewCode128_InvalidContent(t *testing.T) {
+	for _, content := range []string{"", "line\nbreak", "café"} {
+		if _, err := NewCode128(content); !errors.Is(err, ErrBarcodeInvalidContent) {
+			t.Errorf("%q: expected ErrBarcodeInvalidContent, got %v", content, err)
+		}
+	}
+}
+
+// TestCode128PNG tests image dimensions and quiet zone
+func TestCode128PNG(t *testing.T) {
+	data, err := Code128PNG("12345678", BarcodeOptions{Height: 50})
+	if err != nil {
+		t.Fatalf("Code128PNG error: %v", err)
+	}
+
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("invalid png: %v", err)
+	}
+
+	// 79 modules + 2*10 quiet zone, 2px per module
+	if b := img.Bounds(); b.Dx() != 198 || b.Dy() != 50 {
+		t.Fatalf("size = %v, want 198x50", b)
+	}
+	if r, _, _, _ := img.At(19, 25).RGBA(); r == 0 {
+		t.Error("expected light quiet zone")
+	}
+	if r, _, _, _ := img.At(20, 25).RGBA(); r != 0 {
+		t.Error("expected start symbol bar after quiet zone")
+	}
+}
+
+// TestCode128SVG tests SVG output
+func TestCode128SVG(t *testing.T) {
+	svg, err := Code128SVG("12345678", BarcodeOptions{Width: 400, Height: 60})
+	if err != nil {
+		t.Fatalf("Code128SVG error: %v", err)
+	}
+
+	s := string(svg)
+	// 99 columns scaled to 5px each
+	if !strings.Contains(s, `width="495" height="60" viewBox="0 0 99 1"`) || !strings.Contains(s, "M10 0h2v1h-2z") {
+		t.Errorf("unexpected svg %q", s[:min(len(s), 200)])
+	}
+}
@@ -0,0 +1,320 @@
+package dim
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Status subscription yang dikenali. Nilai mengikuti status subscription Stripe.
+const (
+	SubscriptionActive     = "active"
+	SubscriptionTrialing   = "trialing"
+	SubscriptionPastDue    = "past_due"
+	SubscriptionCanceled   = "canceled"
+	SubscriptionIncomplete = "incomplete"
+	SubscriptionUnpaid     = "unpaid"
+)
+
+// Unlimited menandai limit entitlement tanpa batas.
+const Unlimited int64 = -1
+
+var (
+	// ErrBillingCustomerNotFound dikembalikan store jika customer tidak ditemukan.
+	ErrBillingCustomerNotFound = errors.New("billing customer not found")
+	// ErrSubscriptionNotFound dikembalikan store jika owner tidak memiliki subscription.
+	ErrSubscriptionNotFound = errors.New("subscription not found")
+)
+
+// BillingCustomer merepresentasikan customer di billing provider yang terhubung ke owner aplikasi
+// (user atau organisasi).
+type BillingCustomer struct {
+	ID        string    `json:"id"`
+	OwnerID   string    `json:"owner_id"`
+	Email     string    `json:"email"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Subscription merepresentasikan subscription customer pada sebuah price/plan.
+type Subscription struct {
+	ID                string    `json:"id"`
+	CustomerID        string    `json:"customer_id"`
+	PriceID           string    `json:"price_id"`
+	Status            string    `json:"status"`
+	CurrentPeriodEnd  time.Time `json:"current_period_end"`
+	CancelAtPeriodEnd bool      `json:"cancel_at_period_end"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// IsActive mengembalikan true jika subscription memberi akses ke plan (active atau trialing).
+func (s *Subscription) IsActive() bool {
+	return s.Status == SubscriptionActive || s.Status == SubscriptionTrialing
+}
+
+// CustomerSyncer menyimpan perubahan customer dari billing provider.
+type CustomerSyncer interface {
+	UpsertCustomer(ctx context.Context, customer *BillingCustomer) error
+	DeleteCustomer(ctx context.Context, customerID string) error
+}
+
+// SubscriptionSyncer menyimpan perubahan subscription dari billing provider.
+type SubscriptionSyncer interface {
+	UpsertSubscription(ctx context.Context, sub *Subscription) error
+	DeleteSubscription(ctx context.Context, subscriptionID string) error
+}
+
+// BillingEvent merepresentasikan event webhook yang diterima dari billing provider.
+type BillingEvent struct {
+	ID          string     `json:"id"`
+	Type        string     `json:"type"`
+	Payload     []byte     `json:"-"`
+	ReceivedAt  time.Time  `json:"received_at"`
+	ProcessedAt *time.Time `json:"processed_at,omitempty"`
+	Error       string     `json:"error,omitempty"`
+}
+
+// BillingEventStore mencatat event webhook agar setiap event hanya diproses sekali.
+type BillingEventStore interface {
+	// BeginEvent menyimpan event jika belum ada dan mengembalikan true jika event
+	// sudah pernah diproses dengan sukses sebelumnya.
+	BeginEvent(ctx context.Context, event *BillingEvent) (bool, error)
+	// CompleteEvent menandai event selesai diproses; processErr non-nil dicatat dan
+	// event akan diproses ulang saat provider mengirim ulang.
+	CompleteEvent(ctx context.Context, eventID string, processErr error) error
+}
+
+// BillingStore menggabungkan sinkronisasi customer/subscription, pencatatan event,
+// dan lookup subscription untuk entitlement.
+type BillingStore interface {
+	CustomerSyncer
+	SubscriptionSyncer
+	BillingEventStore
+
+	FindCustomer(ctx context.Context, customerID string) (*BillingCustomer, error)
+	// FindSubscriptionByOwner mengembalikan subscription terbaru milik owner.
+	FindSubscriptionByOwner(ctx context.Context, ownerID string) (*Subscription, error)
+}
+
+// Plan mendefinisikan fitur dan limit yang didapat dari satu atau beberapa price billing provider.
+type Plan struct {
+	ID       string
+	Name     string
+	PriceIDs []string
+	Features []string
+	Limits   map[string]int64
+}
+
+// HasFeature memeriksa apakah plan memiliki fitur tertentu.
+func (p *Plan) HasFeature(feature string) bool {
+	return slices.Contains(p.Features, feature)
+}
+
+// Limit mengembalikan limit untuk key tertentu, 0 jika tidak didefinisikan, atau Unlimited.
+func (p *Plan) Limit(key string) int64 {
+	return p.Limits[key]
+}
+
+// PlanCatalog memetakan price ID ke Plan dan menyimpan plan default untuk owner tanpa subscription aktif.
+type PlanCatalog struct {
+	mu          sync.RWMutex
+	plans       map[string]*Plan
+	byPrice     map[string]*Plan
+	defaultPlan *Plan
+}
+
+// NewPlanCatalog membuat PlanCatalog dari daftar plan.
+//
+// Parameters:
+//   - plans: daftar plan; price ID harus unik di seluruh plan
+//
+// Returns:
+//   - *PlanCatalog: katalog plan
+//
+// Example:
+//
+//	catalog := dim.NewPlanCatalog(
+//	    &dim.Plan{ID: "free", Limits: map[string]int64{"projects": 3}},
+//	    &dim.Plan{ID: "pro", PriceIDs: []string{"price_pro_monthly", "price_pro_yearly"},
+//	        Features: []string{"export", "sso"}, Limits: map[string]int64{"projects": dim.Unlimited}},
+//	).WithDefault("free")
+func NewPlanCatalog(plans ...*Plan) *PlanCatalog {
+	c := &PlanCatalog{
+		plans:   make(map[string]*Plan),
+		byPrice: make(map[string]*Plan),
+	}
+	for _, p := range plans {
+		c.Add(p)
+	}
+	return c
+}
+
+// Add menambahkan atau mengganti plan di katalog.
+func (c *PlanCatalog) Add(plan *Plan) *PlanCatalog {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.plans[plan.ID] = plan
+	for _, priceID := range plan.PriceIDs {
+		c.byPrice[priceID] = plan
+	}
+	return c
+}
+
+// WithDefault mengatur plan yang dipakai owner tanpa subscription aktif (misal "free").
+func (c *PlanCatalog) WithDefault(planID string) *PlanCatalog {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.defaultPlan = c.plans[planID]
+	return c
+}
+
+// Plan mengambil plan berdasarkan ID.
+func (c *PlanCatalog) Plan(planID string) (*Plan, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	p, ok := c.plans[planID]
+	return p, ok
+}
+
+// PlanForPrice mengambil plan berdasarkan price ID billing provider.
+func (c *PlanCatalog) PlanForPrice(priceID string) (*Plan, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	p, ok := c.byPrice[priceID]
+	return p, ok
+}
+
+// Default mengembalikan plan default, atau nil jika tidak di-set.
+func (c *PlanCatalog) Default() *Plan {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.defaultPlan
+}
+
+// Entitlements menjawab pertanyaan "apa yang boleh dilakukan owner ini" berdasarkan
+// subscription aktif dan PlanCatalog. Dapat dipakai oleh feature flag maupun quota middleware.
+type Entitlements struct {
+	store   BillingStore
+	catalog *PlanCatalog
+}
+
+// NewEntitlements membuat Entitlements.
+//
+// Parameters:
+//   - store: BillingStore untuk lookup subscription owner
+//   - catalog: PlanCatalog untuk memetakan price ke plan
+//
+// Returns:
+//   - *Entitlements: entitlement lookup yang siap digunakan
+func NewEntitlements(store BillingStore, catalog *PlanCatalog) *Entitlements {
+	return &Entitlements{store: store, catalog: catalog}
+}
+
+// PlanFor mengembalikan plan owner: plan dari subscription aktif, atau plan default.
+// Mengembalikan nil tanpa error jika owner tidak memiliki subscription aktif dan tidak ada plan default.
+func (e *Entitlements) PlanFor(ctx context.Context, ownerID string) (*Plan, error) {
+	sub, err := e.store.FindSubscriptionByOwner(ctx, ownerID)
+	if err != nil && !errors.Is(err, ErrSubscriptionNotFound) {
+		return nil, fmt.Errorf("failed to find subscription: %w", err)
+	}
+
+	if sub != nil && sub.IsActive() {
+		if plan, ok := e.catalog.PlanForPrice(sub.PriceID); ok {
+			return plan, nil
+		}
+	}
+
+	return e.catalog.Default(), nil
+}
+
+// HasFeature memeriksa apakah plan owner memiliki fitur tertentu.
+func (e *Entitlements) HasFeature(ctx context.Context, ownerID, feature string) (bool, error) {
+	plan, err := e.PlanFor(ctx, ownerID)
+	if err != nil || plan == nil {
+		return false, err
+	}
+	return plan.HasFeature(feature), nil
+}
+
+// Limit mengembalikan limit owner untuk key tertentu (0 jika tidak ada, Unlimited jika tanpa batas).
+func (e *Entitlements) Limit(ctx context.Context, ownerID, key string) (int64, error) {
+	plan, err := e.PlanFor(ctx, ownerID)
+	if err != nil || plan == nil {
+		return 0, err
+	}
+	return plan.Limit(key), nil
+}
+
+// WithinLimit memeriksa apakah pemakaian saat ini masih di bawah limit owner.
+// Berguna untuk quota check sebelum membuat resource baru.
+//
+// Example:
+//
+//	count, _ := projects.CountByOwner(ctx, ownerID)
+//	ok, err := entitlements.WithinLimit(ctx, ownerID, "projects", count)
+func (e *Entitlements) WithinLimit(ctx context.Context, ownerID, key string, used int64) (bool, error) {
+	limit, err := e.Limit(ctx, ownerID, key)
+	if err != nil {
+		return false, err
+	}
+	return limit == Unlimited || used < limit, nil
+}
+
+// BillingOwnerFunc menentukan owner billing dari request.
+type BillingOwnerFunc func(r *http.Request) string
+
+// DefaultBillingOwner memakai organisasi aktif (claim org_id) jika ada, selain itu ID user.
+func DefaultBillingOwner(r *http.Request) string {
+	if orgID := GetOrgID(r); orgID != "" {
+		return orgID
+	}
+	if user, ok := GetUser(r); ok {
+		return user.GetID()
+	}
+	return ""
+}
+
+// RequireEntitlement membuat middleware yang mewajibkan plan owner memiliki fitur tertentu.
+// Mengembalikan 402 Payment Required jika fitur tidak tersedia. Pasang setelah RequireAuth.
+//
+// Parameters:
+//   - e: Entitlements untuk lookup plan
+//   - feature: nama fitur pada Plan.Features
+//   - owner: BillingOwnerFunc, nil untuk DefaultBillingOwner
+//
+// Returns:
+//   - MiddlewareFunc: middleware pemeriksa entitlement
+//
+// Example:
+//
+//	api.Get("/reports/export", exportHandler, dim.RequireEntitlement(entitlements, "export", nil))
+func RequireEntitlement(e *Entitlements, feature string, owner BillingOwnerFunc) MiddlewareFunc {
+	if owner == nil {
+		owner = DefaultBillingOwner
+	}
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			ownerID := owner(r)
+			if ownerID == "" {
+				JsonError(w, http.StatusUnauthorized, "User tidak terotentikasi", nil)
+				return
+			}
+
+			ok, err := e.HasFeature(r.Context(), ownerID, feature)
+			if err != nil {
+				JsonError(w, http.StatusInternalServerError, "Gagal memeriksa paket langganan", nil)
+				return
+			}
+			if !ok {
+				JsonError(w, http.StatusPaymentRequired, "Fitur tidak tersedia pada paket Anda", nil)
+				return
+			}
+
+			next(w, r)
+		}
+	}
+}
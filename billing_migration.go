@@ -0,0 +1,187 @@
+package dim
+
+import (
+	"context"
+)
+
+// GetBillingMigrations mengembalikan daftar migrasi modul billing.
+// Seperti modul organisasi, migrasi ini opsional dan tidak termasuk dalam GetFrameworkMigrations.
+// Menggunakan versi 111-113.
+//
+// Example:
+//
+//	migrations := append(dim.GetFrameworkMigrations(), dim.GetBillingMigrations()...)
+//	dim.RunMigrations(db, migrations)
+func GetBillingMigrations() []Migration {
+	return []Migration{
+		{
+			Version: 111,
+			Name:    "create_billing_customers_table",
+			Up:      CreateBillingCustomersTable,
+			Down:    DropBillingCustomersTable,
+		},
+		{
+			Version: 112,
+			Name:    "create_billing_subscriptions_table",
+			Up:      CreateBillingSubscriptionsTable,
+			Down:    DropBillingSubscriptionsTable,
+		},
+		{
+			Version: 113,
+			Name:    "create_billing_events_table",
+			Up:      CreateBillingEventsTable,
+			Down:    DropBillingEventsTable,
+		},
+	}
+}
+
+// CreateBillingCustomersTable membuat tabel billing_customers.
+func CreateBillingCustomersTable(db Database) error {
+	var query string
+	if db.DriverName() == "sqlite" {
+		query = `
+			CREATE TABLE IF NOT EXISTS billing_customers (
+				id TEXT PRIMARY KEY,
+				owner_id TEXT NOT NULL DEFAULT '',
+				email TEXT NOT NULL DEFAULT '',
+				updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+			);
+			CREATE INDEX IF NOT EXISTS idx_billing_customers_owner_id ON billing_customers(owner_id);
+		`
+	} else if db.DriverName() == "mysql" {
+		query = `
+			CREATE TABLE IF NOT EXISTS billing_customers (
+				id VARCHAR(255) PRIMARY KEY,
+				owner_id VARCHAR(255) NOT NULL DEFAULT '',
+				email VARCHAR(255) NOT NULL DEFAULT '',
+				updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				INDEX idx_billing_customers_owner_id (owner_id)
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+		`
+	} else {
+		query = `
+			CREATE TABLE IF NOT EXISTS billing_customers (
+				id VARCHAR(255) PRIMARY KEY,
+				owner_id VARCHAR(255) NOT NULL DEFAULT '',
+				email VARCHAR(255) NOT NULL DEFAULT '',
+				updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+			);
+			CREATE INDEX IF NOT EXISTS idx_billing_customers_owner_id ON billing_customers(owner_id);
+		`
+	}
+	return db.Exec(context.Background(), query)
+}
+
+// DropBillingCustomersTable menghapus tabel billing_customers.
+func DropBillingCustomersTable(db Database) error {
+	query := "DROP TABLE IF EXISTS billing_customers CASCADE"
+	if db.DriverName() == "sqlite" {
+		query = "DROP TABLE IF EXISTS billing_customers"
+	}
+	return db.Exec(context.Background(), query)
+}
+
+// CreateBillingSubscriptionsTable membuat tabel billing_subscriptions.
+// customer_id sengaja tanpa foreign key karena event subscription bisa tiba sebelum event customer.
+func CreateBillingSubscriptionsTable(db Database) error {
+	var query string
+	if db.DriverName() == "sqlite" {
+		query = `
+			CREATE TABLE IF NOT EXISTS billing_subscriptions (
+				id TEXT PRIMARY KEY,
+				customer_id TEXT NOT NULL,
+				price_id TEXT NOT NULL DEFAULT '',
+				status TEXT NOT NULL,
+				current_period_end TIMESTAMP NOT NULL,
+				cancel_at_period_end BOOLEAN NOT NULL DEFAULT 0,
+				updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+			);
+			CREATE INDEX IF NOT EXISTS idx_billing_subscriptions_customer_id ON billing_subscriptions(customer_id);
+		`
+	} else if db.DriverName() == "mysql" {
+		query = `
+			CREATE TABLE IF NOT EXISTS billing_subscriptions (
+				id VARCHAR(255) PRIMARY KEY,
+				customer_id VARCHAR(255) NOT NULL,
+				price_id VARCHAR(255) NOT NULL DEFAULT '',
+				status VARCHAR(50) NOT NULL,
+				current_period_end DATETIME NOT NULL,
+				cancel_at_period_end BOOLEAN NOT NULL DEFAULT FALSE,
+				updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				INDEX idx_billing_subscriptions_customer_id (customer_id)
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+		`
+	} else {
+		query = `
+			CREATE TABLE IF NOT EXISTS billing_subscriptions (
+				id VARCHAR(255) PRIMARY KEY,
+				customer_id VARCHAR(255) NOT NULL,
+				price_id VARCHAR(255) NOT NULL DEFAULT '',
+				status VARCHAR(50) NOT NULL,
+				current_period_end TIMESTAMP NOT NULL,
+				cancel_at_period_end BOOLEAN NOT NULL DEFAULT FALSE,
+				updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+			);
+			CREATE INDEX IF NOT EXISTS idx_billing_subscriptions_customer_id ON billing_subscriptions(customer_id);
+		`
+	}
+	return db.Exec(context.Background(), query)
+}
+
+// DropBillingSubscriptionsTable menghapus tabel billing_subscriptions.
+func DropBillingSubscriptionsTable(db Database) error {
+	query := "DROP TABLE IF EXISTS billing_subscriptions CASCADE"
+	if db.DriverName() == "sqlite" {
+		query = "DROP TABLE IF EXISTS billing_subscriptions"
+	}
+	return db.Exec(context.Background(), query)
+}
+
+// CreateBillingEventsTable membuat tabel billing_events untuk idempotensi webhook.
+func CreateBillingEventsTable(db Database) error {
+	var query string
+	if db.DriverName() == "sqlite" {
+		query = `
+			CREATE TABLE IF NOT EXISTS billing_events (
+				id TEXT PRIMARY KEY,
+				type TEXT NOT NULL,
+				payload TEXT NOT NULL,
+				received_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				processed_at TIMESTAMP NULL,
+				error TEXT NULL
+			);
+		`
+	} else if db.DriverName() == "mysql" {
+		query = `
+			CREATE TABLE IF NOT EXISTS billing_events (
+				id VARCHAR(255) PRIMARY KEY,
+				type VARCHAR(255) NOT NULL,
+				payload TEXT NOT NULL,
+				received_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				processed_at DATETIME NULL,
+				error TEXT NULL
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+		`
+	} else {
+		query = `
+			CREATE TABLE IF NOT EXISTS billing_events (
+				id VARCHAR(255) PRIMARY KEY,
+				type VARCHAR(255) NOT NULL,
+				payload TEXT NOT NULL,
+				received_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				processed_at TIMESTAMP NULL,
+				error TEXT NULL
+			);
+		`
+	}
+	return db.Exec(context.Background(), query)
+}
+
+// DropBillingEventsTable menghapus tabel billing_events.
+func DropBillingEventsTable(db Database) error {
+	query := "DROP TABLE IF EXISTS billing_events CASCADE"
+	if db.DriverName() == "sqlite" {
+		query = "DROP TABLE IF EXISTS billing_events"
+	}
+	return db.Exec(context.Background(), query)
+}
@@ -0,0 +1,315 @@
+package dim
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DatabaseBillingStore is the SQL implementation of BillingStore (PostgreSQL, MySQL & SQLite)
+type DatabaseBillingStore struct {
+	db Database
+}
+
+// NewDatabaseBillingStore creates a new SQL billing store.
+// Requires the tables created by GetBillingMigrations.
+func NewDatabaseBillingStore(db Database) *DatabaseBillingStore {
+	return &DatabaseBillingStore{db: db}
+}
+
+// UpsertCustomer inserts or updates a billing customer.
+func (s *DatabaseBillingStore) UpsertCustomer(ctx context.Context, customer *BillingCustomer) error {
+	customer.UpdatedAt = time.Now().UTC().Truncate(time.Second)
+	upsert := `ON CONFLICT (id) DO UPDATE SET owner_id = excluded.owner_id, email = excluded.email, updated_at = excluded.updated_at`
+	if s.db.DriverName() == "mysql" {
+		upsert = `ON DUPLICATE KEY UPDATE owner_id = $2, email = $3, updated_at = $4`
+	}
+	query := `INSERT INTO billing_customers (id, owner_id, email, updated_at)
+		 VALUES ($1, $2, $3, $4) ` + upsert
+
+	err := s.db.Exec(ctx, s.db.Rebind(query), customer.ID, customer.OwnerID, customer.Email, customer.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to upsert billing customer: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteCustomer deletes a billing customer.
+func (s *DatabaseBillingStore) DeleteCustomer(ctx context.Context, customerID string) error {
+	query := `DELETE FROM billing_customers WHERE id = $1`
+
+	if err := s.db.Exec(ctx, s.db.Rebind(query), customerID); err != nil {
+		return fmt.Errorf("failed to delete billing customer: %w", err)
+	}
+
+	return nil
+}
+
+// FindCustomer finds a billing customer by provider ID.
+func (s *DatabaseBillingStore) FindCustomer(ctx context.Context, customerID string) (*BillingCustomer, error) {
+	c := &BillingCustomer{}
+	query := `SELECT id, owner_id, email, updated_at FROM billing_customers WHERE id = $1`
+
+	err := s.db.QueryRow(ctx, s.db.Rebind(query), customerID).Scan(&c.ID, &c.OwnerID, &c.Email, &c.UpdatedAt)
+	if err != nil {
+		if isNoRows(err) {
+			return nil, ErrBillingCustomerNotFound
+		}
+		return nil, fmt.Errorf("failed to find billing customer: %w", err)
+	}
+
+	return c, nil
+}
+
+// UpsertSubscription inserts or updates a subscription.
+func (s *DatabaseBillingStore) UpsertSubscription(ctx context.Context, sub *Subscription) error {
+	sub.UpdatedAt = time.Now().UTC().Truncate(time.Second)
+	upsert := `ON CONFLICT (id) DO UPDATE SET
+		   customer_id = excluded.customer_id,
+		   price_id = excluded.price_id,
+		   status = excluded.status,
+		   current_period_end = excluded.current_period_end,
+		   cancel_at_period_end = excluded.cancel_at_period_end,
+		   updated_at = excluded.updated_at`
+	if s.db.DriverName() == "mysql" {
+		upsert = `ON DUPLICATE KEY UPDATE
+		   customer_id = $2, price_id = $3, status = $4,
+		   current_period_end = $5, cancel_at_period_end = $6, updated_at = $7`
+	}
+	query := `INSERT INTO billing_subscriptions (id, customer_id, price_id, status, current_period_end, cancel_at_period_end, updated_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7) ` + upsert
+
+	err := s.db.Exec(ctx, s.db.Rebind(query),
+		sub.ID,
+		sub.CustomerID,
+		sub.PriceID,
+		sub.Status,
+		sub.CurrentPeriodEnd.UTC().Truncate(time.Second),
+		sub.CancelAtPeriodEnd,
+		sub.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to upsert subscription: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteSubscription marks a subscription as canceled.
+// The row is kept so entitlement lookups fall back to the default plan.
+func (s *DatabaseBillingStore) DeleteSubscription(ctx context.Context, subscriptionID string) error {
+	query := `UPDATE billing_subscriptions SET status = $1, updated_at = $2 WHERE id = $3`
+
+	err := s.db.Exec(ctx, s.db.Rebind(query), SubscriptionCanceled, time.Now().UTC().Truncate(time.Second), subscriptionID)
+	if err != nil {
+		return fmt.Errorf("failed to delete subscription: %w", err)
+	}
+
+	return nil
+}
+
+// FindSubscriptionByOwner finds the owner's subscription, preferring active ones, then the most recently updated.
+func (s *DatabaseBillingStore) FindSubscriptionByOwner(ctx context.Context, ownerID string) (*Subscription, error) {
+	sub := &Subscription{}
+	query := `SELECT s.id, s.customer_id, s.price_id, s.status, s.current_period_end, s.cancel_at_period_end, s.updated_at
+		 FROM billing_subscriptions s
+		 JOIN billing_customers c ON c.id = s.customer_id
+		 WHERE c.owner_id = $1
+		 ORDER BY CASE WHEN s.status IN ('active', 'trialing') THEN 0 ELSE 1 END, s.updated_at DESC
+		 LIMIT 1`
+
+	err := s.db.QueryRow(ctx, s.db.Rebind(query), ownerID).Scan(
+		&sub.ID, &sub.CustomerID, &sub.PriceID, &sub.Status,
+		&sub.CurrentPeriodEnd, &sub.CancelAtPeriodEnd, &sub.UpdatedAt,
+	)
+	if err != nil {
+		if isNoRows(err) {
+			return nil, ErrSubscriptionNotFound
+		}
+		return nil, fmt.Errorf("failed to find subscription: %w", err)
+	}
+
+	return sub, nil
+}
+
+// BeginEvent records a webhook event and reports whether it was already processed successfully.
+func (s *DatabaseBillingStore) BeginEvent(ctx context.Context, event *BillingEvent) (bool, error) {
+	event.ReceivedAt = time.Now().UTC().Truncate(time.Second)
+	insert := `INSERT INTO billing_events (id, type, payload, received_at)
+		 VALUES ($1, $2, $3, $4)
+		 ON CONFLICT (id) DO NOTHING`
+	if s.db.DriverName() == "mysql" {
+		insert = `INSERT INTO billing_events (id, type, payload, received_at)
+		 VALUES ($1, $2, $3, $4)
+		 ON DUPLICATE KEY UPDATE id = id`
+	}
+
+	if err := s.db.Exec(ctx, s.db.Rebind(insert), event.ID, event.Type, string(event.Payload), event.ReceivedAt); err != nil {
+		return false, fmt.Errorf("failed to record billing event: %w", err)
+	}
+
+	var processedAt *time.Time
+	query := `SELECT processed_at FROM billing_events WHERE id = $1`
+	if err := s.db.QueryRow(ctx, s.db.Rebind(query), event.ID).Scan(&processedAt); err != nil {
+		return false, fmt.Errorf("failed to find billing event: %w", err)
+	}
+
+	return processedAt != nil, nil
+}
+
+// CompleteEvent marks a webhook event as processed, or records the processing error.
+func (s *DatabaseBillingStore) CompleteEvent(ctx context.Context, eventID string, processErr error) error {
+	var err error
+	if processErr != nil {
+		query := `UPDATE billing_events SET error = $1 WHERE id = $2`
+		err = s.db.Exec(ctx, s.db.Rebind(query), processErr.Error(), eventID)
+	} else {
+		query := `UPDATE billing_events SET processed_at = $1, error = NULL WHERE id = $2`
+		err = s.db.Exec(ctx, s.db.Rebind(query), time.Now().UTC().Truncate(time.Second), eventID)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to complete billing event: %w", err)
+	}
+
+	return nil
+}
+
+// MockBillingStore is a mock implementation for testing
+type MockBillingStore struct {
+	mu            sync.RWMutex
+	customers     map[string]*BillingCustomer
+	subscriptions map[string]*Subscription
+	events        map[string]*BillingEvent
+}
+
+// NewMockBillingStore creates a new mock billing store.
+func NewMockBillingStore() *MockBillingStore {
+	return &MockBillingStore{
+		customers:     make(map[string]*BillingCustomer),
+		subscriptions: make(map[string]*Subscription),
+		events:        make(map[string]*BillingEvent),
+	}
+}
+
+// UpsertCustomer saves a customer in mock store.
+func (s *MockBillingStore) UpsertCustomer(ctx context.Context, customer *BillingCustomer) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	customer.UpdatedAt = time.Now()
+	copied := *customer
+	s.customers[customer.ID] = &copied
+	return nil
+}
+
+// DeleteCustomer deletes a customer in mock store.
+func (s *MockBillingStore) DeleteCustomer(ctx context.Context, customerID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.customers, customerID)
+	return nil
+}
+
+// FindCustomer finds a customer in mock store.
+func (s *MockBillingStore) FindCustomer(ctx context.Context, customerID string) (*BillingCustomer, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	c, exists := s.customers[customerID]
+	if !exists {
+		return nil, ErrBillingCustomerNotFound
+	}
+	copied := *c
+	return &copied, nil
+}
+
+// UpsertSubscription saves a subscription in mock store.
+func (s *MockBillingStore) UpsertSubscription(ctx context.Context, sub *Subscription) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sub.UpdatedAt = time.Now()
+	copied := *sub
+	s.subscriptions[sub.ID] = &copied
+	return nil
+}
+
+// DeleteSubscription marks a subscription as canceled in mock store.
+func (s *MockBillingStore) DeleteSubscription(ctx context.Context, subscriptionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if sub, exists := s.subscriptions[subscriptionID]; exists {
+		sub.Status = SubscriptionCanceled
+		sub.UpdatedAt = time.Now()
+	}
+	return nil
+}
+
+// FindSubscriptionByOwner finds the owner's subscription in mock store.
+func (s *MockBillingStore) FindSubscriptionByOwner(ctx context.Context, ownerID string) (*Subscription, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var candidates []*Subscription
+	for _, sub := range s.subscriptions {
+		if c, exists := s.customers[sub.CustomerID]; exists && c.OwnerID == ownerID {
+			candidates = append(candidates, sub)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, ErrSubscriptionNotFound
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].IsActive() != candidates[j].IsActive() {
+			return candidates[i].IsActive()
+		}
+		return candidates[i].UpdatedAt.After(candidates[j].UpdatedAt)
+	})
+	copied := *candidates[0]
+	return &copied, nil
+}
+
+// BeginEvent records an event in mock store.
+func (s *MockBillingStore) BeginEvent(ctx context.Context, event *BillingEvent) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if existing, exists := s.events[event.ID]; exists {
+		return existing.ProcessedAt != nil, nil
+	}
+	event.ReceivedAt = time.Now()
+	copied := *event
+	s.events[event.ID] = &copied
+	return false, nil
+}
+
+// CompleteEvent marks an event as processed in mock store.
+func (s *MockBillingStore) CompleteEvent(ctx context.Context, eventID string, processErr error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	event, exists := s.events[eventID]
+	if !exists {
+		return nil
+	}
+	if processErr != nil {
+		event.Error = processErr.Error()
+		return nil
+	}
+	now := time.Now()
+	event.ProcessedAt = &now
+	event.Error = ""
+	return nil
+}
+
+// Event returns a recorded event from mock store.
+func (s *MockBillingStore) Event(eventID string) (*BillingEvent, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	event, exists := s.events[eventID]
+	if !exists {
+		return nil, false
+	}
+	copied := *event
+	return &copied, true
+}
@@ -0,0 +1,350 @@
+package dim
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StripeSignatureHeader adalah header yang berisi signature webhook Stripe.
+const StripeSignatureHeader = "Stripe-Signature"
+
+var (
+	// ErrStripeSignatureMissing dikembalikan jika header Stripe-Signature kosong atau tidak lengkap.
+	ErrStripeSignatureMissing = errors.New("stripe: missing signature")
+	// ErrStripeSignatureInvalid dikembalikan jika tidak ada signature v1 yang cocok.
+	ErrStripeSignatureInvalid = errors.New("stripe: invalid signature")
+	// ErrStripeSignatureExpired dikembalikan jika timestamp signature di luar toleransi.
+	ErrStripeSignatureExpired = errors.New("stripe: signature timestamp outside tolerance")
+)
+
+// StripeEvent adalah envelope event webhook Stripe.
+// Object berisi data.object mentah untuk di-decode oleh handler.
+type StripeEvent struct {
+	ID      string `json:"id"`
+	Type    string `json:"type"`
+	Created int64  `json:"created"`
+	Data    struct {
+		Object json.RawMessage `json:"object"`
+	} `json:"data"`
+}
+
+// StripeEventHandler memproses satu event Stripe. Error menyebabkan webhook merespons 500
+// sehingga Stripe mengirim ulang event tersebut.
+type StripeEventHandler func(ctx context.Context, event *StripeEvent) error
+
+// StripeWebhookConfig mengatur StripeWebhook.
+type StripeWebhookConfig struct {
+	// Secret adalah signing secret endpoint (whsec_...). Wajib diisi.
+	Secret string
+	// Tolerance adalah selisih maksimum timestamp signature dengan waktu server (default 5 menit).
+	Tolerance time.Duration
+	// MaxBodyBytes adalah batas ukuran payload (default 1 MiB).
+	MaxBodyBytes int64
+	// Store mencatat event untuk idempotensi dan menerima sinkronisasi customer/subscription.
+	Store BillingStore
+	// OwnerMetadataKey adalah key metadata customer yang berisi owner ID aplikasi (default "owner_id").
+	OwnerMetadataKey string
+}
+
+// StripeWebhook memverifikasi dan memproses webhook Stripe.
+// Event customer.* dan customer.subscription.* disinkronkan ke Store secara otomatis;
+// event lain dapat ditangani dengan On.
+type StripeWebhook struct {
+	config   StripeWebhookConfig
+	handlers map[string][]StripeEventHandler
+	now      func() time.Time
+}
+
+// NewStripeWebhook membuat StripeWebhook baru.
+//
+// Parameters:
+//   - config: StripeWebhookConfig dengan Secret dan Store wajib diisi
+//
+// Returns:
+//   - *StripeWebhook: webhook yang siap didaftarkan ke router
+//
+// Example:
+//
+//	webhook := dim.NewStripeWebhook(dim.StripeWebhookConfig{
+//	    Secret: os.Getenv("STRIPE_WEBHOOK_SECRET"),
+//	    Store:  dim.NewDatabaseBillingStore(db),
+//	})
+//	webhook.On("invoice.payment_failed", notifyPaymentFailed)
+//	router.Post("/webhooks/stripe", webhook.Handler())
+func NewStripeWebhook(config StripeWebhookConfig) *StripeWebhook {
+	if config.Tolerance == 0 {
+		config.Tolerance = 5 * time.Minute
+	}
+	if config.MaxBodyBytes == 0 {
+		config.MaxBodyBytes = 1 << 20
+	}
+	if config.OwnerMetadataKey == "" {
+		config.OwnerMetadataKey = "owner_id"
+	}
+
+	w := &StripeWebhook{
+		config:   config,
+		handlers: make(map[string][]StripeEventHandler),
+		now:      time.Now,
+	}
+
+	for _, t := range []string{"customer.created", "customer.updated"} {
+		w.On(t, w.syncCustomer)
+	}
+	w.On("customer.deleted", w.deleteCustomer)
+	for _, t := range []string{"customer.subscription.created", "customer.subscription.updated", "customer.subscription.paused", "customer.subscription.resumed"} {
+		w.On(t, w.syncSubscription)
+	}
+	w.On("customer.subscription.deleted", w.deleteSubscription)
+
+	return w
+}
+
+// On menambahkan handler untuk tipe event tertentu. Beberapa handler dipanggil sesuai urutan pendaftaran.
+// Gunakan "*" untuk menerima semua event.
+func (w *StripeWebhook) On(eventType string, handler StripeEventHandler) *StripeWebhook {
+	w.handlers[eventType] = append(w.handlers[eventType], handler)
+	return w
+}
+
+// Handler mengembalikan HandlerFunc untuk endpoint webhook.
+// Respons: 400 jika signature atau payload tidak valid, 200 jika event diproses atau
+// sudah pernah diproses, 500 jika handler gagal (Stripe akan mengirim ulang).
+func (w *StripeWebhook) Handler() HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		payload, err := io.ReadAll(http.MaxBytesReader(rw, r.Body, w.config.MaxBodyBytes))
+		if err != nil {
+			JsonError(rw, http.StatusRequestEntityTooLarge, "Ukuran request terlalu besar", nil)
+			return
+		}
+
+		event, err := w.ConstructEvent(payload, r.Header.Get(StripeSignatureHeader))
+		if err != nil {
+			JsonError(rw, http.StatusBadRequest, "Signature webhook tidak valid", nil)
+			return
+		}
+
+		if err := w.Process(r.Context(), event, payload); err != nil {
+			JsonError(rw, http.StatusInternalServerError, "Gagal memproses event", nil)
+			return
+		}
+
+		Json(rw, http.StatusOK, map[string]bool{"received": true})
+	}
+}
+
+// ConstructEvent memverifikasi signature dan men-decode payload menjadi StripeEvent.
+//
+// Parameters:
+//   - payload: body request mentah (harus byte yang sama persis dengan yang dikirim Stripe)
+//   - signatureHeader: nilai header Stripe-Signature
+//
+// Returns:
+//   - *StripeEvent: event yang sudah diverifikasi
+//   - error: ErrStripeSignatureMissing, ErrStripeSignatureInvalid, ErrStripeSignatureExpired, atau error decode
+func (w *StripeWebhook) ConstructEvent(payload []byte, signatureHeader string) (*StripeEvent, error) {
+	if err := VerifyStripeSignature(payload, signatureHeader, w.config.Secret, w.config.Tolerance, w.now()); err != nil {
+		return nil, err
+	}
+
+	var event StripeEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("failed to decode stripe event: %w", err)
+	}
+	if event.ID == "" || event.Type == "" {
+		return nil, errors.New("stripe: event without id or type")
+	}
+	return &event, nil
+}
+
+// Process menjalankan handler untuk event secara idempoten: event yang sudah sukses diproses dilewati.
+func (w *StripeWebhook) Process(ctx context.Context, event *StripeEvent, payload []byte) error {
+	processed, err := w.config.Store.BeginEvent(ctx, &BillingEvent{
+		ID:      event.ID,
+		Type:    event.Type,
+		Payload: payload,
+	})
+	if err != nil {
+		return err
+	}
+	if processed {
+		return nil
+	}
+
+	handleErr := w.dispatch(ctx, event)
+	if err := w.config.Store.CompleteEvent(ctx, event.ID, handleErr); err != nil {
+		return err
+	}
+	return handleErr
+}
+
+func (w *StripeWebhook) dispatch(ctx context.Context, event *StripeEvent) error {
+	handlers := slices.Concat(w.handlers[event.Type], w.handlers["*"])
+	for _, h := range handlers {
+		if err := h(ctx, event); err != nil {
+			return fmt.Errorf("stripe event %s (%s): %w", event.ID, event.Type, err)
+		}
+	}
+	return nil
+}
+
+type stripeCustomerObject struct {
+	ID       string            `json:"id"`
+	Email    string            `json:"email"`
+	Metadata map[string]string `json:"metadata"`
+}
+
+type stripeSubscriptionObject struct {
+	ID                string `json:"id"`
+	Customer          string `json:"customer"`
+	Status            string `json:"status"`
+	CurrentPeriodEnd  int64  `json:"current_period_end"`
+	CancelAtPeriodEnd bool   `json:"cancel_at_period_end"`
+	Items             struct {
+		Data []struct {
+			CurrentPeriodEnd int64 `json:"current_period_end"`
+			Price            struct {
+				ID string `json:"id"`
+			} `json:"price"`
+		} `json:"data"`
+	} `json:"items"`
+}
+
+func (w *StripeWebhook) syncCustomer(ctx context.Context, event *StripeEvent) error {
+	var obj stripeCustomerObject
+	if err := json.Unmarshal(event.Data.Object, &obj); err != nil {
+		return err
+	}
+	return w.config.Store.UpsertCustomer(ctx, &BillingCustomer{
+		ID:      obj.ID,
+		OwnerID: obj.Metadata[w.config.OwnerMetadataKey],
+		Email:   obj.Email,
+	})
+}
+
+func (w *StripeWebhook) deleteCustomer(ctx context.Context, event *StripeEvent) error {
+	var obj stripeCustomerObject
+	if err := json.Unmarshal(event.Data.Object, &obj); err != nil {
+		return err
+	}
+	return w.config.Store.DeleteCustomer(ctx, obj.ID)
+}
+
+func (w *StripeWebhook) syncSubscription(ctx context.Context, event *StripeEvent) error {
+	var obj stripeSubscriptionObject
+	if err := json.Unmarshal(event.Data.Object, &obj); err != nil {
+		return err
+	}
+
+	sub := &Subscription{
+		ID:                obj.ID,
+		CustomerID:        obj.Customer,
+		Status:            obj.Status,
+		CancelAtPeriodEnd: obj.CancelAtPeriodEnd,
+	}
+
+	periodEnd := obj.CurrentPeriodEnd
+	if len(obj.Items.Data) > 0 {
+		sub.PriceID = obj.Items.Data[0].Price.ID
+		// API versi baru memindahkan current_period_end ke item subscription
+		if periodEnd == 0 {
+			periodEnd = obj.Items.Data[0].CurrentPeriodEnd
+		}
+	}
+	if periodEnd > 0 {
+		sub.CurrentPeriodEnd = time.Unix(periodEnd, 0).UTC()
+	}
+
+	return w.config.Store.UpsertSubscription(ctx, sub)
+}
+
+func (w *StripeWebhook) deleteSubscription(ctx context.Context, event *StripeEvent) error {
+	var obj stripeSubscriptionObject
+	if err := json.Unmarshal(event.Data.Object, &obj); err != nil {
+		return err
+	}
+	return w.config.Store.DeleteSubscription(ctx, obj.ID)
+}
+
+// VerifyStripeSignature memverifikasi header Stripe-Signature ("t=...,v1=...") terhadap payload.
+// Signature dihitung dengan HMAC-SHA256 atas "<timestamp>.<payload>" dan dibandingkan secara constant-time.
+//
+// Parameters:
+//   - payload: body request mentah
+//   - header: nilai header Stripe-Signature
+//   - secret: signing secret endpoint
+//   - tolerance: selisih maksimum timestamp, 0 untuk tidak memeriksa
+//   - now: waktu acuan
+//
+// Returns:
+//   - error: nil jika salah satu signature v1 cocok
+func VerifyStripeSignature(payload []byte, header, secret string, tolerance time.Duration, now time.Time) error {
+	var timestamp int64
+	var signatures [][]byte
+
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			timestamp, _ = strconv.ParseInt(value, 10, 64)
+		case "v1":
+			if sig, err := hex.DecodeString(value); err == nil {
+				signatures = append(signatures, sig)
+			}
+		}
+	}
+
+	if timestamp == 0 || len(signatures) == 0 {
+		return ErrStripeSignatureMissing
+	}
+
+	expected := computeStripeSignature(payload, secret, timestamp)
+	valid := false
+	for _, sig := range signatures {
+		if hmac.Equal(sig, expected) {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return ErrStripeSignatureInvalid
+	}
+
+	if tolerance > 0 {
+		age := now.Sub(time.Unix(timestamp, 0))
+		if age > tolerance || age < -tolerance {
+			return ErrStripeSignatureExpired
+		}
+	}
+
+	return nil
+}
+
+// SignStripePayload membuat nilai header Stripe-Signature untuk payload.
+// Berguna untuk testing webhook handler dengan fixture event.
+func SignStripePayload(payload []byte, secret string, timestamp time.Time) string {
+	t := timestamp.Unix()
+	return fmt.Sprintf("t=%d,v1=%s", t, hex.EncodeToString(computeStripeSignature(payload, secret, t)))
+}
+
+func computeStripeSignature(payload []byte, secret string, timestamp int64) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,226 @@
+package dim
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const testStripeSecret = "whsec_test_secret"
+
+func loadStripeFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "stripe", name+".json"))
+	if err != nil {
+		t.Fatalf("failed to read fixture %s: %v", name, err)
+	}
+	return data
+}
+
+func postStripeEvent(t *testing.T, handler HandlerFunc, payload []byte, signature string) *httptest.ResponseRecorder {
+	t.Helper()
+	r := httptest.NewRequest(http.MethodPost, "/webhooks/stripe", bytes.NewReader(payload))
+	r.Header.Set(StripeSignatureHeader, signature)
+	w := httptest.NewRecorder()
+	handler(w, r)
+	return w
+}
+
+func testPlanCatalog() *PlanCatalog {
+	return NewPlanCatalog(
+		&Plan{ID: "free", Limits: map[string]int64{"projects": 3}},
+		&Plan{
+			ID:       "pro",
+			PriceIDs: []string{"price_pro_monthly", "price_pro_yearly"},
+			Features: []string{"export"},
+			Limits:   map[string]int64{"projects": Unlimited},
+		},
+	).WithDefault("free")
+}
+
+func TestVerifyStripeSignature(t *testing.T) {
+	payload := []byte(`{"id":"evt_1"}`)
+	now := time.Unix(1718000000, 0)
+	valid := SignStripePayload(payload, testStripeSecret, now)
+
+	tests := []struct {
+		name    string
+		header  string
+		now     time.Time
+		wantErr error
+	}{
+		{"valid", valid, now, nil},
+		{"valid with extra v0 and v1", valid + ",v0=deadbeef,v1=00", now, nil},
+		{"missing", "", now, ErrStripeSignatureMissing},
+		{"wrong secret", SignStripePayload(payload, "other", now), now, ErrStripeSignatureInvalid},
+		{"tampered timestamp", "t=1718000001," + valid[len("t=1718000000,"):], now, ErrStripeSignatureInvalid},
+		{"expired", valid, now.Add(10 * time.Minute), ErrStripeSignatureExpired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyStripeSignature(payload, tt.header, testStripeSecret, 5*time.Minute, tt.now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestStripeWebhook_SyncsRecordedEvents(t *testing.T) {
+	store := NewMockBillingStore()
+	webhook := NewStripeWebhook(StripeWebhookConfig{Secret: testStripeSecret, Store: store})
+	handler := webhook.Handler()
+	entitlements := NewEntitlements(store, testPlanCatalog())
+	ctx := context.Background()
+
+	for _, name := range []string{"subscription_created", "customer_created"} {
+		payload := loadStripeFixture(t, name)
+		w := postStripeEvent(t, handler, payload, SignStripePayload(payload, testStripeSecret, time.Now()))
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d, body = %s", name, w.Code, w.Body.String())
+		}
+	}
+
+	customer, err := store.FindCustomer(ctx, "cus_QAcme001")
+	if err != nil || customer.OwnerID != "org-acme" || customer.Email != "billing@acme.test" {
+		t.Fatalf("unexpected customer %+v, %v", customer, err)
+	}
+
+	sub, err := store.FindSubscriptionByOwner(ctx, "org-acme")
+	if err != nil {
+		t.Fatalf("FindSubscriptionByOwner error: %v", err)
+	}
+	if sub.PriceID != "price_pro_monthly" || !sub.IsActive() || sub.CurrentPeriodEnd.Unix() != 1720592060 {
+		t.Errorf("unexpected subscription %+v", sub)
+	}
+
+	plan, _ := entitlements.PlanFor(ctx, "org-acme")
+	if plan == nil || plan.ID != "pro" {
+		t.Errorf("expected pro plan, got %+v", plan)
+	}
+
+	payload := loadStripeFixture(t, "subscription_deleted")
+	postStripeEvent(t, handler, payload, SignStripePayload(payload, testStripeSecret, time.Now()))
+
+	plan, _ = entitlements.PlanFor(ctx, "org-acme")
+	if plan == nil || plan.ID != "free" {
+		t.Errorf("expected fallback to free plan after cancel, got %+v", plan)
+	}
+}
+
+func TestStripeWebhook_Idempotent(t *testing.T) {
+	store := NewMockBillingStore()
+	calls := 0
+	fail := true
+	webhook := NewStripeWebhook(StripeWebhookConfig{Secret: testStripeSecret, Store: store}).
+		On("invoice.payment_failed", func(ctx context.Context, event *StripeEvent) error {
+			calls++
+			if fail {
+				return errors.New("mailer down")
+			}
+			return nil
+		})
+	handler := webhook.Handler()
+
+	payload := loadStripeFixture(t, "invoice_payment_failed")
+	sign := func() string { return SignStripePayload(payload, testStripeSecret, time.Now()) }
+
+	// Handler gagal: 500 dan error dicatat sehingga Stripe mengirim ulang
+	if w := postStripeEvent(t, handler, payload, sign()); w.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", w.Code)
+	}
+	if event, _ := store.Event("evt_1PinvoicePaymentFailed"); event == nil || event.Error == "" || event.ProcessedAt != nil {
+		t.Errorf("expected failed event to be recorded, got %+v", event)
+	}
+
+	fail = false
+	for i := 0; i < 2; i++ {
+		if w := postStripeEvent(t, handler, payload, sign()); w.Code != http.StatusOK {
+			t.Fatalf("retry %d: status = %d, want 200", i, w.Code)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("expected handler to run twice (failure + first retry), got %d", calls)
+	}
+}
+
+func TestStripeWebhook_RejectsInvalidSignature(t *testing.T) {
+	store := NewMockBillingStore()
+	handler := NewStripeWebhook(StripeWebhookConfig{Secret: testStripeSecret, Store: store}).Handler()
+
+	payload := loadStripeFixture(t, "customer_created")
+	w := postStripeEvent(t, handler, payload, SignStripePayload(payload, "whsec_wrong", time.Now()))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", w.Code)
+	}
+	if _, ok := store.Event("evt_1PcustomerCreated"); ok {
+		t.Error("expected event with invalid signature not to be recorded")
+	}
+}
+
+func TestEntitlements_Limits(t *testing.T) {
+	store := NewMockBillingStore()
+	entitlements := NewEntitlements(store, testPlanCatalog())
+	ctx := context.Background()
+
+	ok, err := entitlements.WithinLimit(ctx, "user-1", "projects", 2)
+	if err != nil || !ok {
+		t.Errorf("expected free plan to allow 2 projects, got %v, %v", ok, err)
+	}
+	if ok, _ := entitlements.WithinLimit(ctx, "user-1", "projects", 3); ok {
+		t.Error("expected free plan to reject the 4th project")
+	}
+
+	store.UpsertCustomer(ctx, &BillingCustomer{ID: "cus_1", OwnerID: "user-1"})
+	store.UpsertSubscription(ctx, &Subscription{ID: "sub_1", CustomerID: "cus_1", PriceID: "price_pro_yearly", Status: SubscriptionTrialing})
+
+	if ok, _ := entitlements.WithinLimit(ctx, "user-1", "projects", 1000); !ok {
+		t.Error("expected unlimited projects on pro plan")
+	}
+	if has, _ := entitlements.HasFeature(ctx, "user-1", "export"); !has {
+		t.Error("expected export feature on pro plan")
+	}
+}
+
+func TestRequireEntitlement(t *testing.T) {
+	store := NewMockBillingStore()
+	ctx := context.Background()
+	store.UpsertCustomer(ctx, &BillingCustomer{ID: "cus_1", OwnerID: "org-pro"})
+	store.UpsertSubscription(ctx, &Subscription{ID: "sub_1", CustomerID: "cus_1", PriceID: "price_pro_monthly", Status: SubscriptionActive})
+
+	mw := RequireEntitlement(NewEntitlements(store, testPlanCatalog()), "export", nil)
+	handler := mw(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name string
+		user *TokenUser
+		want int
+	}{
+		{"org on pro plan", &TokenUser{ID: "user-1", Claims: map[string]interface{}{OrgIDClaim: "org-pro"}}, http.StatusOK},
+		{"user on free plan", &TokenUser{ID: "user-2"}, http.StatusPaymentRequired},
+		{"anonymous", nil, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/export", nil)
+			if tt.user != nil {
+				r = SetUser(r, tt.user)
+			}
+			w := httptest.NewRecorder()
+			handler(w, r)
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
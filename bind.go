@@ -0,0 +1,502 @@
+package dim
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultBindMaxBytes adalah batas ukuran body default untuk Bind (10 MiB).
+const DefaultBindMaxBytes int64 = 10 << 20
+
+// Validatable diimplementasikan oleh struct request yang ingin divalidasi otomatis oleh Bind.
+// Bind menjalankan aturan tag `validate` (lihat ValidateStruct) lalu memanggil Validate
+// setelah decode berhasil; jika validator berisi error, Bind mengembalikan
+// AppError 400 "Validasi gagal" dengan field errors dari ErrorMap.
+//
+// Example:
+//
+//	func (r *CreateUserRequest) Validate(v *dim.Validator) {
+//	    v.Required("email", r.Email).Email("email", r.Email)
+//	    v.MinLength("password", r.Password, 8)
+//	}
+type Validatable interface {
+	Validate(v *Validator)
+}
+
+// BindOption adalah functional option untuk mengkonfigurasi Bind.
+type BindOption func(*bindConfig)
+
+type bindConfig struct {
+	maxBytes              int64
+	disallowUnknownFields bool
+	skipValidation        bool
+	locale                string
+}
+
+// WithMaxBytes membatasi ukuran body request. Body yang melebihi batas menghasilkan AppError 413.
+//
+// Parameters:
+//   - n: ukuran maksimum body dalam byte
+//
+// Returns:
+//   - BindOption: option untuk Bind
+func WithMaxBytes(n int64) BindOption {
+	return func(c *bindConfig) {
+		c.maxBytes = n
+	}
+}
+
+// WithDisallowUnknownFields menolak field JSON yang tidak ada di struct tujuan.
+//
+// Returns:
+//   - BindOption: option untuk Bind
+func WithDisallowUnknownFields() BindOption {
+	return func(c *bindConfig) {
+		c.disallowUnknownFields = true
+	}
+}
+
+// WithoutValidation melewati validasi tag `validate` dan Validatable.Validate setelah decode.
+//
+// Returns:
+//   - BindOption: option untuk Bind
+func WithoutValidation() BindOption {
+	return func(c *bindConfig) {
+		c.skipValidation = true
+	}
+}
+
+func newBindConfig(r *http.Request, opts []BindOption) *bindConfig {
+	cfg := &bindConfig{maxBytes: DefaultBindMaxBytes, locale: GetLocale(r)}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// Bind men-decode request ke dst berdasarkan Content-Type dan memvalidasi hasilnya.
+// Query string selalu di-bind ke field bertag `query`. Body di-decode sebagai:
+//   - serializer terdaftar untuk Content-Type tersebut (lihat RegisterSerializer)
+//   - JSON untuk application/json, application/*+json, atau Content-Type kosong
+//   - form untuk application/x-www-form-urlencoded dan multipart/form-data (tag `form`)
+//
+// Content-Type lain menghasilkan AppError 415. Error decode dan validasi dikembalikan sebagai
+// *AppError dengan field errors, sehingga handler cukup meneruskannya ke JsonAppError.
+//
+// Parameters:
+//   - r: HTTP request
+//   - dst: pointer ke struct tujuan
+//   - opts: BindOption (WithMaxBytes, WithDisallowUnknownFields, WithoutValidation)
+//
+// Returns:
+//   - error: *AppError jika decode atau validasi gagal, nil jika berhasil
+//
+// Example:
+//
+//	type CreatePostRequest struct {
+//	    Title  string `json:"title" form:"title"`
+//	    Draft  bool   `query:"draft"`
+//	}
+//
+//	var req CreatePostRequest
+//	if err := dim.Bind(r, &req); err != nil {
+//	    appErr, _ := dim.AsAppError(err)
+//	    dim.JsonAppError(w, appErr)
+//	    return
+//	}
+func Bind(r *http.Request, dst interface{}, opts ...BindOption) error {
+	cfg := newBindConfig(r, opts)
+
+	if err := bindValues(r.URL.Query(), "query", dst, cfg.locale); err != nil {
+		return err
+	}
+
+	if r.Body != nil && r.Body != http.NoBody && r.ContentLength != 0 {
+		mediaType := ""
+		if ct := r.Header.Get("Content-Type"); ct != "" {
+			parsed, _, err := mime.ParseMediaType(ct)
+			if err != nil {
+				return NewAppError("Content-Type tidak valid", http.StatusUnsupportedMediaType)
+			}
+			mediaType = parsed
+		}
+
+		var err error
+		serializer, registered := LookupSerializer(mediaType)
+		switch {
+		case mediaType != "" && registered:
+			err = decodeSerializedBody(r, dst, serializer, cfg)
+		case mediaType == "" || isJSONMediaType(mediaType):
+			err = decodeJSONBody(r, dst, cfg)
+		case mediaType == "application/x-www-form-urlencoded" || mediaType == "multipart/form-data":
+			err = decodeFormBody(r, dst, cfg)
+		default:
+			return NewAppError("Content-Type tidak didukung", http.StatusUnsupportedMediaType)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return validateBound(dst, cfg)
+}
+
+// BindJSON men-decode body JSON ke dst tanpa memeriksa Content-Type, lalu memvalidasinya.
+//
+// Parameters:
+//   - r: HTTP request
+//   - dst: pointer ke struct tujuan
+//   - opts: BindOption
+//
+// Returns:
+//   - error: *AppError jika decode atau validasi gagal
+func BindJSON(r *http.Request, dst interface{}, opts ...BindOption) error {
+	cfg := newBindConfig(r, opts)
+	if err := decodeJSONBody(r, dst, cfg); err != nil {
+		return err
+	}
+	return validateBound(dst, cfg)
+}
+
+// BindQuery men-decode query string ke field bertag `query`, lalu memvalidasinya.
+//
+// Parameters:
+//   - r: HTTP request
+//   - dst: pointer ke struct tujuan
+//   - opts: BindOption
+//
+// Returns:
+//   - error: *AppError jika konversi atau validasi gagal
+//
+// Example:
+//
+//	type ListParams struct {
+//	    Status []string  `query:"status"`
+//	    Since  time.Time `query:"since"`
+//	}
+func BindQuery(r *http.Request, dst interface{}, opts ...BindOption) error {
+	cfg := newBindConfig(r, opts)
+	if err := bindValues(r.URL.Query(), "query", dst, cfg.locale); err != nil {
+		return err
+	}
+	return validateBound(dst, cfg)
+}
+
+// BindForm men-decode body form (urlencoded atau multipart) ke field bertag `form`, lalu memvalidasinya.
+// Field bertipe *multipart.FileHeader atau []*multipart.FileHeader menerima file upload.
+//
+// Parameters:
+//   - r: HTTP request
+//   - dst: pointer ke struct tujuan
+//   - opts: BindOption
+//
+// Returns:
+//   - error: *AppError jika parsing, konversi, atau validasi gagal
+func BindForm(r *http.Request, dst interface{}, opts ...BindOption) error {
+	cfg := newBindConfig(r, opts)
+	if err := decodeFormBody(r, dst, cfg); err != nil {
+		return err
+	}
+	return validateBound(dst, cfg)
+}
+
+func decodeJSONBody(r *http.Request, dst interface{}, cfg *bindConfig) error {
+	body := io.Reader(r.Body)
+	if cfg.maxBytes > 0 {
+		body = http.MaxBytesReader(nil, r.Body, cfg.maxBytes)
+	}
+
+	decoder := json.NewDecoder(body)
+	if cfg.disallowUnknownFields {
+		decoder.DisallowUnknownFields()
+	}
+
+	if err := decoder.Decode(dst); err != nil {
+		return jsonBindError(err, cfg.locale)
+	}
+	return nil
+}
+
+// decodeSerializedBody men-decode body dengan serializer yang didaftarkan via RegisterSerializer.
+func decodeSerializedBody(r *http.Request, dst interface{}, s Serializer, cfg *bindConfig) error {
+	body := io.Reader(r.Body)
+	if cfg.maxBytes > 0 {
+		body = http.MaxBytesReader(nil, r.Body, cfg.maxBytes)
+	}
+
+	if err := s.Decode(body, dst); err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			return NewAppError("Ukuran request terlalu besar", http.StatusRequestEntityTooLarge)
+		}
+		return NewAppError("Format body tidak valid", http.StatusBadRequest)
+	}
+	return nil
+}
+
+// jsonBindError mengubah error encoding/json menjadi AppError dengan field errors.
+func jsonBindError(err error, locale string) error {
+	var maxErr *http.MaxBytesError
+	var typeErr *json.UnmarshalTypeError
+	var syntaxErr *json.SyntaxError
+
+	switch {
+	case errors.As(err, &maxErr):
+		return NewAppError("Ukuran request terlalu besar", http.StatusRequestEntityTooLarge)
+	case errors.As(err, &typeErr):
+		field := typeErr.Field
+		if field == "" {
+			return NewAppError("Format JSON tidak valid", http.StatusBadRequest)
+		}
+		return NewAppError("Format JSON tidak valid", http.StatusBadRequest).
+			WithFieldError(field, Translate(locale, "%s harus bertipe %s", field, typeErr.Type.String()))
+	case errors.As(err, &syntaxErr), errors.Is(err, io.ErrUnexpectedEOF), errors.Is(err, io.EOF):
+		return NewAppError("Format JSON tidak valid", http.StatusBadRequest)
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		field := strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`)
+		return NewAppError("Format JSON tidak valid", http.StatusBadRequest).
+			WithFieldError(field, Translate(locale, "%s tidak dikenal", field))
+	}
+	return NewAppError("Format JSON tidak valid", http.StatusBadRequest)
+}
+
+func decodeFormBody(r *http.Request, dst interface{}, cfg *bindConfig) error {
+	if cfg.maxBytes > 0 {
+		r.Body = http.MaxBytesReader(nil, r.Body, cfg.maxBytes)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
+	var err error
+	if mediaType == "multipart/form-data" {
+		maxMemory := cfg.maxBytes
+		if maxMemory <= 0 {
+			maxMemory = DefaultBindMaxBytes
+		}
+		err = r.ParseMultipartForm(maxMemory)
+	} else {
+		err = r.ParseForm()
+	}
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			return NewAppError("Ukuran request terlalu besar", http.StatusRequestEntityTooLarge)
+		}
+		return NewAppError("Format form tidak valid", http.StatusBadRequest)
+	}
+
+	if err := bindValues(r.PostForm, "form", dst, cfg.locale); err != nil {
+		return err
+	}
+	if r.MultipartForm != nil {
+		return bindFiles(r.MultipartForm.File, dst)
+	}
+	return nil
+}
+
+func validateBound(dst interface{}, cfg *bindConfig) error {
+	if cfg.skipValidation {
+		return nil
+	}
+
+	v := NewValidator().WithLocale(cfg.locale).Struct(dst)
+	if target, ok := dst.(Validatable); ok {
+		target.Validate(v)
+	}
+	if !v.IsValid() {
+		return NewAppError("Validasi gagal", http.StatusBadRequest).WithFieldErrors(v.ErrorMap())
+	}
+	return nil
+}
+
+var (
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	fileHeaderType      = reflect.TypeOf((*multipart.FileHeader)(nil))
+)
+
+// bindValues mengisi field struct bertag tag dari url.Values.
+// Field tanpa tag tersebut dilewati; struct embedded diproses secara rekursif.
+func bindValues(values url.Values, tag string, dst interface{}, locale string) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("dim: bind target must be a non-nil pointer, got %T", dst)
+	}
+	rv = rv.Elem()
+	if rv.Kind() != reflect.Struct {
+		// Map atau tipe lain hanya didukung untuk body JSON
+		return nil
+	}
+
+	appErr := NewAppError("Parameter tidak valid", http.StatusBadRequest)
+	bindStructValues(values, tag, rv, appErr, locale)
+	if len(appErr.Errors) > 0 {
+		return appErr
+	}
+	return nil
+}
+
+func bindStructValues(values url.Values, tag string, rv reflect.Value, appErr *AppError, locale string) {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		fv := rv.Field(i)
+
+		if field.Anonymous && field.Type.Kind() == reflect.Struct && field.Tag.Get(tag) == "" {
+			bindStructValues(values, tag, fv, appErr, locale)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		name := field.Tag.Get(tag)
+		if name == "" || name == "-" {
+			continue
+		}
+		name = strings.Split(name, ",")[0]
+
+		raw, ok := values[name]
+		if !ok || len(raw) == 0 {
+			continue
+		}
+
+		if err := setFieldFromStrings(fv, raw); err != nil {
+			appErr.WithFieldError(name, Translate(locale, "%s %s", name, translateError(locale, err)))
+		}
+	}
+}
+
+// setFieldFromStrings mengisi field dari satu atau beberapa nilai string.
+// Slice menerima parameter berulang (?tag=a&tag=b) maupun daftar dipisah koma (?tag=a,b).
+func setFieldFromStrings(fv reflect.Value, raw []string) error {
+	if fv.Kind() == reflect.Slice && fv.Type().Elem().Kind() != reflect.Uint8 {
+		var parts []string
+		for _, r := range raw {
+			parts = append(parts, strings.Split(r, ",")...)
+		}
+		slice := reflect.MakeSlice(fv.Type(), 0, len(parts))
+		for _, p := range parts {
+			elem := reflect.New(fv.Type().Elem()).Elem()
+			if err := setFieldFromString(elem, strings.TrimSpace(p)); err != nil {
+				return err
+			}
+			slice = reflect.Append(slice, elem)
+		}
+		fv.Set(slice)
+		return nil
+	}
+	return setFieldFromString(fv, raw[0])
+}
+
+func setFieldFromString(fv reflect.Value, s string) error {
+	if fv.Kind() == reflect.Ptr {
+		elem := reflect.New(fv.Type().Elem())
+		if err := setFieldFromString(elem.Elem(), s); err != nil {
+			return err
+		}
+		fv.Set(elem)
+		return nil
+	}
+
+	if fv.Type() == timeType {
+		for _, layout := range []string{time.RFC3339Nano, "2006-01-02"} {
+			if t, err := time.Parse(layout, s); err == nil {
+				fv.Set(reflect.ValueOf(t))
+				return nil
+			}
+		}
+		return fmt.Errorf("harus berupa tanggal (RFC3339 atau YYYY-MM-DD)")
+	}
+
+	if fv.CanAddr() && fv.Addr().Type().Implements(textUnmarshalerType) {
+		if err := fv.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s)); err != nil {
+			return fmt.Errorf("tidak valid")
+		}
+		return nil
+	}
+
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return fmt.Errorf("harus berupa boolean")
+		}
+		fv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if fv.Type() == reflect.TypeOf(time.Duration(0)) {
+			d, err := time.ParseDuration(s)
+			if err != nil {
+				return fmt.Errorf("harus berupa durasi")
+			}
+			fv.SetInt(int64(d))
+			return nil
+		}
+		n, err := strconv.ParseInt(s, 10, fv.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("harus berupa bilangan bulat")
+		}
+		fv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, fv.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("harus berupa bilangan bulat positif")
+		}
+		fv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, fv.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("harus berupa angka")
+		}
+		fv.SetFloat(n)
+	default:
+		return fmt.Errorf("bertipe %s yang tidak didukung", fv.Type())
+	}
+	return nil
+}
+
+// bindFiles mengisi field *multipart.FileHeader dan []*multipart.FileHeader bertag `form`.
+func bindFiles(files map[string][]*multipart.FileHeader, dst interface{}) error {
+	rv := reflect.ValueOf(dst).Elem()
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		name := strings.Split(field.Tag.Get("form"), ",")[0]
+		if name == "" || name == "-" || !field.IsExported() {
+			continue
+		}
+
+		headers := files[name]
+		if len(headers) == 0 {
+			continue
+		}
+
+		switch {
+		case field.Type == fileHeaderType:
+			rv.Field(i).Set(reflect.ValueOf(headers[0]))
+		case field.Type.Kind() == reflect.Slice && field.Type.Elem() == fileHeaderType:
+			rv.Field(i).Set(reflect.ValueOf(headers))
+		}
+	}
+	return nil
+}
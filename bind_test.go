@@ -0,0 +1,208 @@
+package dim
+
+import (
+	"bytes"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type bindTestRequest struct {
+	Name   string   `json:"name" form:"name"`
+	Age    int      `json:"age" form:"age"`
+	Tags   []string `json:"tags" form:"tags"`
+	DryRun bool     `query:"dry_run"`
+}
+
+func (r *bindTestRequest) Validate(v *Validator) {
+	v.Required("name", r.Name)
+	if r.Age < 0 {
+		v.AddError("age", "age tidak boleh negatif")
+	}
+}
+
+type bindTestLevel int
+
+func (l *bindTestLevel) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "low":
+		*l = 1
+	case "high":
+		*l = 3
+	default:
+		return errors.New("unknown level")
+	}
+	return nil
+}
+
+func bindAppError(t *testing.T, err error) *AppError {
+	t.Helper()
+	appErr, ok := AsAppError(err)
+	if !ok {
+		t.Fatalf("expected *AppError, got %T: %v", err, err)
+	}
+	return appErr
+}
+
+func TestBind_JSON(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/?dry_run=true", strings.NewReader(`{"name":"Alice","age":30,"tags":["a","b"]}`))
+	r.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+	var req bindTestRequest
+	if err := Bind(r, &req); err != nil {
+		t.Fatalf("Bind error: %v", err)
+	}
+	if req.Name != "Alice" || req.Age != 30 || len(req.Tags) != 2 || !req.DryRun {
+		t.Errorf("unexpected result %+v", req)
+	}
+}
+
+func TestBind_Form(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("name=Bob&age=41&tags=x&tags=y"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var req bindTestRequest
+	if err := Bind(r, &req); err != nil {
+		t.Fatalf("Bind error: %v", err)
+	}
+	if req.Name != "Bob" || req.Age != 41 || len(req.Tags) != 2 || req.Tags[1] != "y" {
+		t.Errorf("unexpected result %+v", req)
+	}
+}
+
+func TestBind_Multipart(t *testing.T) {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	mw.WriteField("title", "Laporan")
+	fw, _ := mw.CreateFormFile("attachment", "report.txt")
+	fw.Write([]byte("hello"))
+	mw.Close()
+
+	r := httptest.NewRequest(http.MethodPost, "/", &body)
+	r.Header.Set("Content-Type", mw.FormDataContentType())
+
+	var req struct {
+		Title      string                `form:"title"`
+		Attachment *multipart.FileHeader `form:"attachment"`
+	}
+	if err := Bind(r, &req); err != nil {
+		t.Fatalf("Bind error: %v", err)
+	}
+	if req.Title != "Laporan" || req.Attachment == nil || req.Attachment.Filename != "report.txt" {
+		t.Errorf("unexpected result %+v", req)
+	}
+}
+
+func TestBind_ValidationErrors(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"age":-1}`))
+	r.Header.Set("Content-Type", "application/json")
+
+	var req bindTestRequest
+	appErr := bindAppError(t, Bind(r, &req))
+	if appErr.StatusCode != http.StatusBadRequest || appErr.Message != "Validasi gagal" {
+		t.Errorf("unexpected error %+v", appErr)
+	}
+	if _, ok := appErr.Errors["name"]; !ok {
+		t.Errorf("expected name field error, got %v", appErr.Errors)
+	}
+	if _, ok := appErr.Errors["age"]; !ok {
+		t.Errorf("expected age field error, got %v", appErr.Errors)
+	}
+
+	r = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{}`))
+	if err := Bind(r, &req, WithoutValidation()); err != nil {
+		t.Errorf("expected validation to be skipped, got %v", err)
+	}
+}
+
+func TestBind_DecodeErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		opts        []BindOption
+		wantStatus  int
+		wantField   string
+	}{
+		{"syntax", "application/json", `{invalid}`, nil, 400, ""},
+		{"type mismatch", "application/json", `{"name":"a","age":"old"}`, nil, 400, "age"},
+		{"unknown field", "application/json", `{"name":"a","nickname":"x"}`, []BindOption{WithDisallowUnknownFields()}, 400, "nickname"},
+		{"too large", "application/json", `{"name":"` + strings.Repeat("a", 64) + `"}`, []BindOption{WithMaxBytes(16)}, 413, ""},
+		{"unsupported media type", "text/xml", `<a/>`, nil, 415, ""},
+		{"form conversion", "application/x-www-form-urlencoded", `name=a&age=abc`, nil, 400, "age"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			r.Header.Set("Content-Type", tt.contentType)
+
+			var req bindTestRequest
+			appErr := bindAppError(t, Bind(r, &req, tt.opts...))
+			if appErr.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", appErr.StatusCode, tt.wantStatus)
+			}
+			if tt.wantField != "" {
+				if _, ok := appErr.Errors[tt.wantField]; !ok {
+					t.Errorf("expected field error for %s, got %v", tt.wantField, appErr.Errors)
+				}
+			}
+		})
+	}
+}
+
+func TestBindQuery(t *testing.T) {
+	type listParams struct {
+		Status  []string      `query:"status"`
+		Since   time.Time     `query:"since"`
+		Limit   *int          `query:"limit"`
+		Timeout time.Duration `query:"timeout"`
+		Level   bindTestLevel `query:"level"`
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/?status=active,pending&since=2024-05-01&limit=20&timeout=5s&level=high", nil)
+
+	var params listParams
+	if err := BindQuery(r, &params); err != nil {
+		t.Fatalf("BindQuery error: %v", err)
+	}
+	if len(params.Status) != 2 || params.Status[1] != "pending" {
+		t.Errorf("unexpected status %v", params.Status)
+	}
+	if !params.Since.Equal(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected since %v", params.Since)
+	}
+	if params.Limit == nil || *params.Limit != 20 {
+		t.Errorf("unexpected limit %v", params.Limit)
+	}
+	if params.Timeout != 5*time.Second {
+		t.Errorf("unexpected timeout %v", params.Timeout)
+	}
+	if params.Level != 3 {
+		t.Errorf("unexpected level %v", params.Level)
+	}
+
+	r = httptest.NewRequest(http.MethodGet, "/?since=yesterday", nil)
+	appErr := bindAppError(t, BindQuery(r, &params))
+	if _, ok := appErr.Errors["since"]; !ok {
+		t.Errorf("expected since field error, got %v", appErr.Errors)
+	}
+}
+
+func TestBind_GetWithoutBody(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/?dry_run=1", nil)
+
+	var req struct {
+		DryRun bool `query:"dry_run"`
+	}
+	if err := Bind(r, &req); err != nil {
+		t.Fatalf("Bind error: %v", err)
+	}
+	if !req.DryRun {
+		t.Error("expected dry_run to be bound from query")
+	}
+}
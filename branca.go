@@ -33,9 +33,9 @@ var brancaReservedClaims = map[string]struct{}{
 type BrancaConfig struct {
 	// Key is a 32-byte symmetric key encoded as hex (64 chars) or base64.
 	// Generate with: openssl rand -hex 32
-	Key                string
-	AccessTokenExpiry  time.Duration
-	RefreshTokenExpiry time.Duration
+	Key                string        `env:"BRANCA_KEY" desc:"32-byte Branca key (hex or base64); replaces JWT when set"`
+	AccessTokenExpiry  time.Duration `env:"BRANCA_ACCESS_TOKEN_EXPIRY" desc:"Branca access token lifetime"`
+	RefreshTokenExpiry time.Duration `env:"BRANCA_REFRESH_TOKEN_EXPIRY" desc:"Branca refresh token lifetime"`
 }
 
 // BrancaManager implements TokenManager using Branca tokens (XChaCha20-Poly1305 encryption).
@@ -82,13 +82,19 @@ func (m *BrancaManager) GenerateAccessToken(userID, email, sessionID string, ext
 
 // GenerateRefreshToken creates an encrypted Branca refresh token.
 func (m *BrancaManager) GenerateRefreshToken(userID, sessionID string) (string, error) {
+	return m.GenerateRefreshTokenWithExpiry(userID, sessionID, time.Now().Add(m.config.RefreshTokenExpiry))
+}
+
+// GenerateRefreshTokenWithExpiry creates an encrypted Branca refresh token that expires at
+// expiresAt. Implements RefreshTokenExpiryGenerator.
+func (m *BrancaManager) GenerateRefreshTokenWithExpiry(userID, sessionID string, expiresAt time.Time) (string, error) {
 	now := time.Now()
 	claims := map[string]interface{}{
 		"sub": userID,
 		"sid": sessionID,
 		"jti": NewUuid().String(),
 		"iat": now.Unix(),
-		"exp": now.Add(m.config.RefreshTokenExpiry).Unix(),
+		"exp": expiresAt.Unix(),
 		"nbf": now.Unix(),
 		"typ": brancaRefreshType,
 	}
@@ -298,7 +298,7 @@ func TestBrancaManager_ExtraClaimsCannotOverwriteReserved(t *testing.T) {
 }
 
 func TestLoadBrancaConfig_Defaults(t *testing.T) {
-	cfg, err := loadBrancaConfig()
+	cfg, err := loadBrancaConfig(envConfigSource)
 	if err != nil {
 		t.Fatalf("loadBrancaConfig failed: %v", err)
 	}
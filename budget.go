@@ -0,0 +1,141 @@
+package dim
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// minStatementTimeout adalah batas bawah statement_timeout yang dikirim ke database.
+// PostgreSQL memperlakukan statement_timeout = 0 sebagai "tanpa batas", sehingga
+// sisa budget yang sangat kecil tetap harus dibulatkan ke nilai positif.
+const minStatementTimeout = time.Millisecond
+
+// WithBudget membuat context dengan total time budget untuk satu request.
+// Jika parent context sudah memiliki deadline yang lebih awal, deadline parent yang dipakai
+// sehingga budget tidak pernah memperpanjang deadline yang sudah ada.
+//
+// Parameters:
+//   - ctx: parent context
+//   - total: total durasi budget
+//
+// Returns:
+//   - context.Context: context dengan deadline budget
+//   - context.CancelFunc: function untuk melepas resource context
+//
+// Example:
+//
+//	ctx, cancel := dim.WithBudget(r.Context(), 2*time.Second)
+//	defer cancel()
+func WithBudget(ctx context.Context, total time.Duration) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, total)
+}
+
+// AllocateBudget menurunkan context dengan deadline yang lebih pendek untuk downstream call
+// (database, cache, HTTP client, dsb.). Deadline child adalah yang paling awal antara
+// now+share dan deadline parent, sehingga budget selalu menyusut dan tidak pernah bertambah.
+//
+// Parameters:
+//   - ctx: parent context (biasanya hasil WithBudget atau request context)
+//   - share: porsi budget yang dialokasikan untuk downstream call
+//
+// Returns:
+//   - context.Context: context dengan deadline yang sudah dialokasikan
+//   - context.CancelFunc: function untuk melepas resource context
+//
+// Example:
+//
+//	// Total 2s: 1.5s untuk DB, 0.4s untuk cache
+//	ctx, cancel := dim.WithBudget(r.Context(), 2*time.Second)
+//	defer cancel()
+//
+//	dbCtx, dbCancel := dim.AllocateBudget(ctx, 1500*time.Millisecond)
+//	defer dbCancel()
+//	rows, err := db.Query(dbCtx, "SELECT ...")
+//
+//	cacheCtx, cacheCancel := dim.AllocateBudget(ctx, 400*time.Millisecond)
+//	defer cacheCancel()
+func AllocateBudget(ctx context.Context, share time.Duration) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, share)
+}
+
+// ReserveBudget menurunkan context yang berakhir lebih awal sebesar reserve dari deadline parent.
+// Berguna untuk menyisakan waktu bagi pekerjaan setelah downstream call selesai
+// (misalnya serialisasi response). Jika parent tidak memiliki deadline, context dikembalikan
+// tanpa deadline tambahan.
+//
+// Parameters:
+//   - ctx: parent context
+//   - reserve: durasi yang disisakan sebelum deadline parent
+//
+// Returns:
+//   - context.Context: context dengan deadline yang sudah dikurangi reserve
+//   - context.CancelFunc: function untuk melepas resource context
+//
+// Example:
+//
+//	// Sisakan 100ms untuk menulis response
+//	dbCtx, cancel := dim.ReserveBudget(r.Context(), 100*time.Millisecond)
+//	defer cancel()
+func ReserveBudget(ctx context.Context, reserve time.Duration) (context.Context, context.CancelFunc) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return context.WithCancel(ctx)
+	}
+	return context.WithDeadline(ctx, deadline.Add(-reserve))
+}
+
+// RemainingBudget mengembalikan sisa waktu sebelum deadline context.
+// Nilai yang dikembalikan bisa negatif jika deadline sudah lewat.
+//
+// Returns:
+//   - time.Duration: sisa waktu
+//   - bool: false jika context tidak memiliki deadline
+func RemainingBudget(ctx context.Context) (time.Duration, bool) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return 0, false
+	}
+	return time.Until(deadline), true
+}
+
+// DeadlineBudget membuat middleware yang memasang total time budget pada request context.
+// Handler dan downstream call (termasuk query database) akan mewarisi deadline ini,
+// dan dapat membaginya lebih lanjut dengan AllocateBudget.
+//
+// Parameters:
+//   - total: total durasi budget untuk setiap request
+//
+// Returns:
+//   - MiddlewareFunc: middleware yang memasang deadline pada request context
+//
+// Example:
+//
+//	router.Use(dim.DeadlineBudget(2 * time.Second))
+func DeadlineBudget(total time.Duration) MiddlewareFunc {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := WithBudget(r.Context(), total)
+			defer cancel()
+
+			next(w, r.WithContext(ctx))
+		}
+	}
+}
+
+// statementTimeout menghitung statement_timeout dari deadline context.
+// Mengembalikan ok=false jika context tidak memiliki deadline, dan error jika
+// deadline sudah terlewati sehingga query tidak perlu dikirim sama sekali.
+func statementTimeout(ctx context.Context) (time.Duration, bool, error) {
+	remaining, ok := RemainingBudget(ctx)
+	if !ok {
+		return 0, false, nil
+	}
+	if remaining <= 0 {
+		return 0, true, context.DeadlineExceeded
+	}
+	if remaining < minStatementTimeout {
+		remaining = minStatementTimeout
+	}
+	return remaining.Truncate(time.Millisecond), true, nil
+}
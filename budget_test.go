@@ -0,0 +1,177 @@
+package dim
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestWithBudget_SetsDeadline(t *testing.T) {
+	ctx, cancel := WithBudget(context.Background(), 2*time.Second)
+	defer cancel()
+
+	remaining, ok := RemainingBudget(ctx)
+	if !ok {
+		t.Fatal("expected context to have a deadline")
+	}
+	if remaining <= 0 || remaining > 2*time.Second {
+		t.Errorf("expected remaining within (0, 2s], got %v", remaining)
+	}
+}
+
+func TestAllocateBudget_NeverExtendsParent(t *testing.T) {
+	parent, cancel := WithBudget(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	child, childCancel := AllocateBudget(parent, 5*time.Second)
+	defer childCancel()
+
+	parentDeadline, _ := parent.Deadline()
+	childDeadline, _ := child.Deadline()
+	if childDeadline.After(parentDeadline) {
+		t.Errorf("child deadline %v should not be after parent deadline %v", childDeadline, parentDeadline)
+	}
+}
+
+func TestAllocateBudget_Shrinks(t *testing.T) {
+	parent, cancel := WithBudget(context.Background(), 2*time.Second)
+	defer cancel()
+
+	dbCtx, dbCancel := AllocateBudget(parent, 1500*time.Millisecond)
+	defer dbCancel()
+	cacheCtx, cacheCancel := AllocateBudget(parent, 400*time.Millisecond)
+	defer cacheCancel()
+
+	dbRemaining, _ := RemainingBudget(dbCtx)
+	cacheRemaining, _ := RemainingBudget(cacheCtx)
+
+	if dbRemaining > 1500*time.Millisecond {
+		t.Errorf("expected db budget <= 1.5s, got %v", dbRemaining)
+	}
+	if cacheRemaining > 400*time.Millisecond {
+		t.Errorf("expected cache budget <= 400ms, got %v", cacheRemaining)
+	}
+}
+
+func TestReserveBudget(t *testing.T) {
+	t.Run("subtracts reserve from parent deadline", func(t *testing.T) {
+		parent, cancel := WithBudget(context.Background(), time.Second)
+		defer cancel()
+
+		ctx, reserveCancel := ReserveBudget(parent, 300*time.Millisecond)
+		defer reserveCancel()
+
+		parentDeadline, _ := parent.Deadline()
+		deadline, ok := ctx.Deadline()
+		if !ok {
+			t.Fatal("expected deadline")
+		}
+		if got := parentDeadline.Sub(deadline); got != 300*time.Millisecond {
+			t.Errorf("expected 300ms reserve, got %v", got)
+		}
+	})
+
+	t.Run("no deadline on parent", func(t *testing.T) {
+		ctx, cancel := ReserveBudget(context.Background(), 300*time.Millisecond)
+		defer cancel()
+
+		if _, ok := ctx.Deadline(); ok {
+			t.Error("expected no deadline when parent has none")
+		}
+	})
+}
+
+func TestRemainingBudget_NoDeadline(t *testing.T) {
+	if _, ok := RemainingBudget(context.Background()); ok {
+		t.Error("expected ok=false for context without deadline")
+	}
+}
+
+func TestDeadlineBudget_Middleware(t *testing.T) {
+	var hasDeadline bool
+	handler := DeadlineBudget(time.Second)(func(w http.ResponseWriter, r *http.Request) {
+		_, hasDeadline = r.Context().Deadline()
+		w.WriteHeader(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+
+	if !hasDeadline {
+		t.Error("expected request context to have a deadline")
+	}
+}
+
+func TestStatementTimeout(t *testing.T) {
+	t.Run("no deadline", func(t *testing.T) {
+		_, ok, err := statementTimeout(context.Background())
+		if ok || err != nil {
+			t.Errorf("expected ok=false and nil error, got ok=%v err=%v", ok, err)
+		}
+	})
+
+	t.Run("derived from deadline", func(t *testing.T) {
+		ctx, cancel := WithBudget(context.Background(), 1500*time.Millisecond)
+		defer cancel()
+
+		timeout, ok, err := statementTimeout(ctx)
+		if !ok || err != nil {
+			t.Fatalf("expected ok=true and nil error, got ok=%v err=%v", ok, err)
+		}
+		if timeout <= 0 || timeout > 1500*time.Millisecond {
+			t.Errorf("expected timeout within (0, 1.5s], got %v", timeout)
+		}
+		if timeout%time.Millisecond != 0 {
+			t.Errorf("expected timeout truncated to milliseconds, got %v", timeout)
+		}
+	})
+
+	t.Run("expired deadline", func(t *testing.T) {
+		ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+		defer cancel()
+
+		_, _, err := statementTimeout(ctx)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected context.DeadlineExceeded, got %v", err)
+		}
+	})
+}
+
+func TestPostgresDatabase_DeadlineStatementTimeoutDisabled(t *testing.T) {
+	db := &PostgresDatabase{}
+
+	ctx, cancel := WithBudget(context.Background(), time.Second)
+	defer cancel()
+
+	if _, ok, err := db.deadlineStatementTimeout(ctx); ok || err != nil {
+		t.Errorf("expected disabled statement timeout, got ok=%v err=%v", ok, err)
+	}
+}
+
+func TestDeadlineRow_ScanAfterTransactionFinished(t *testing.T) {
+	row := &deadlineRow{
+		fields: []pgconn.FieldDescription{
+			{Name: "id", DataTypeOID: pgtype.Int8OID, Format: pgtype.TextFormatCode},
+			{Name: "email", DataTypeOID: pgtype.TextOID, Format: pgtype.TextFormatCode},
+			{Name: "deleted_at", DataTypeOID: pgtype.TimestamptzOID, Format: pgtype.TextFormatCode},
+		},
+		values: [][]byte{[]byte("42"), []byte("a@example.com"), nil},
+	}
+
+	var id int64
+	var email string
+	var deletedAt *time.Time
+	if err := row.Scan(&id, &email, &deletedAt); err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if id != 42 || email != "a@example.com" || deletedAt != nil {
+		t.Errorf("Scan() = %d, %q, %v", id, email, deletedAt)
+	}
+}
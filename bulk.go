@@ -0,0 +1,361 @@
+package dim
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// BulkMode menentukan perilaku BulkHandler ketika ada item yang gagal.
+type BulkMode int
+
+const (
+	// BulkAllOrNothing membatalkan seluruh batch jika satu item gagal validasi atau eksekusi.
+	BulkAllOrNothing BulkMode = iota
+	// BulkBestEffort menyimpan item yang berhasil; setiap item dijalankan di SAVEPOINT sendiri.
+	BulkBestEffort
+)
+
+// Operasi bawaan BulkHandler.
+const (
+	BulkCreate = "create"
+	BulkUpdate = "update"
+	BulkDelete = "delete"
+)
+
+// DefaultBulkMaxItems adalah jumlah item maksimum per request BulkHandler.
+const DefaultBulkMaxItems = 100
+
+// BulkItem adalah satu item request bulk yang sudah di-decode dan divalidasi.
+type BulkItem[T any] struct {
+	// Index adalah posisi item di array request.
+	Index int
+	// ID adalah ID resource; wajib untuk semua operasi kecuali "create".
+	ID string
+	// Op adalah nama operasi, misal "update".
+	Op string
+	// Data adalah payload item. Bernilai zero value jika item tidak mengirim data.
+	Data T
+	// HasData bernilai true jika item mengirim field data.
+	HasData bool
+}
+
+// BulkFunc menjalankan satu item bulk. ctx berada di dalam transaksi batch, sehingga
+// store yang dipanggil dengan ctx ini otomatis ikut transaksi (lihat InTx).
+// Nilai kembalian disertakan sebagai data pada hasil item.
+type BulkFunc[T any] func(ctx context.Context, item BulkItem[T]) (interface{}, error)
+
+// BulkResult adalah hasil satu item pada response multi-status.
+type BulkResult struct {
+	Index  int            `json:"index"`
+	ID     string         `json:"id,omitempty"`
+	Op     string         `json:"op"`
+	Status int            `json:"status"`
+	Data   interface{}    `json:"data,omitempty"`
+	Error  *ErrorResponse `json:"error,omitempty"`
+}
+
+// BulkMeta merangkum hasil batch.
+type BulkMeta struct {
+	Total     int  `json:"total"`
+	Succeeded int  `json:"succeeded"`
+	Failed    int  `json:"failed"`
+	Committed bool `json:"committed"`
+	DryRun    bool `json:"dry_run,omitempty"`
+}
+
+// BulkResponse adalah body response 207 Multi-Status dari BulkHandler.
+type BulkResponse struct {
+	Results []BulkResult `json:"results"`
+	Meta    BulkMeta     `json:"meta"`
+}
+
+// bulkRawItem adalah bentuk item sebelum data di-decode ke T.
+type bulkRawItem struct {
+	ID   json.RawMessage `json:"id"`
+	Op   string          `json:"op"`
+	Data json.RawMessage `json:"data"`
+}
+
+// errBulkAbort membatalkan transaksi BulkAllOrNothing setelah item gagal.
+var errBulkAbort = errors.New("dim: bulk aborted")
+
+// BulkHandler mengimplementasikan endpoint mutasi massal yang menerima
+// [{"id": "...", "op": "update", "data": {...}}, ...]. Data setiap item di-decode dan divalidasi
+// seperti Bind (tag `validate` dan Validatable), lalu semua item dijalankan dalam satu transaksi.
+// Response selalu 207 Multi-Status berisi status per item, kecuali request-nya sendiri tidak valid.
+type BulkHandler[T any] struct {
+	db       Database
+	fn       BulkFunc[T]
+	mode     BulkMode
+	maxItems int
+	ops      map[string]bool
+	bindOpts []BindOption
+	logger   *Logger
+}
+
+// NewBulkHandler membuat BulkHandler dengan mode BulkAllOrNothing, operasi create/update/delete,
+// dan maksimal DefaultBulkMaxItems item. Jika request berada dalam mode dry-run
+// (DryRunMiddleware), transaksi selalu di-rollback.
+//
+// Parameters:
+//   - db: database tempat transaksi batch dijalankan
+//   - fn: fungsi yang mengeksekusi satu item
+//
+// Returns:
+//   - *BulkHandler[T]: handler yang dipasang dengan method Handle
+//
+// Example:
+//
+//	bulk := dim.NewBulkHandler(db, func(ctx context.Context, item dim.BulkItem[UpdatePostRequest]) (interface{}, error) {
+//	    switch item.Op {
+//	    case dim.BulkUpdate:
+//	        return postStore.Update(ctx, item.ID, item.Data)
+//	    case dim.BulkDelete:
+//	        return nil, postStore.Delete(ctx, item.ID)
+//	    }
+//	    return nil, nil
+//	}).WithOps(dim.BulkUpdate, dim.BulkDelete).WithMode(dim.BulkBestEffort)
+//
+//	api.Patch("/posts", bulk.Handle)
+func NewBulkHandler[T any](db Database, fn BulkFunc[T]) *BulkHandler[T] {
+	if fn == nil {
+		panic("dim: NewBulkHandler requires a non-nil function")
+	}
+	return &BulkHandler[T]{
+		db:       db,
+		fn:       fn,
+		mode:     BulkAllOrNothing,
+		maxItems: DefaultBulkMaxItems,
+		ops:      map[string]bool{BulkCreate: true, BulkUpdate: true, BulkDelete: true},
+	}
+}
+
+// WithMode mengatur BulkAllOrNothing atau BulkBestEffort.
+func (b *BulkHandler[T]) WithMode(mode BulkMode) *BulkHandler[T] {
+	b.mode = mode
+	return b
+}
+
+// WithMaxItems mengatur jumlah item maksimum per request. Nilai <= 0 berarti tanpa batas.
+func (b *BulkHandler[T]) WithMaxItems(n int) *BulkHandler[T] {
+	b.maxItems = n
+	return b
+}
+
+// WithOps mengganti daftar operasi yang diterima, termasuk operasi custom seperti "archive".
+func (b *BulkHandler[T]) WithOps(ops ...string) *BulkHandler[T] {
+	b.ops = make(map[string]bool, len(ops))
+	for _, op := range ops {
+		b.ops[op] = true
+	}
+	return b
+}
+
+// WithBindOptions meneruskan BindOption (WithMaxBytes, WithDisallowUnknownFields,
+// WithoutValidation) ke decode body dan data setiap item.
+func (b *BulkHandler[T]) WithBindOptions(opts ...BindOption) *BulkHandler[T] {
+	b.bindOpts = opts
+	return b
+}
+
+// WithLogger mencatat error internal item yang disembunyikan dari response sebagai 500.
+func (b *BulkHandler[T]) WithLogger(logger *Logger) *BulkHandler[T] {
+	b.logger = logger
+	return b
+}
+
+// Handle adalah HandlerFunc untuk dipasang di router.
+func (b *BulkHandler[T]) Handle(w http.ResponseWriter, r *http.Request) {
+	cfg := newBindConfig(r, b.bindOpts)
+	locale := cfg.locale
+
+	var raws []bulkRawItem
+	if err := decodeJSONBody(r, &raws, cfg); err != nil {
+		appErr, _ := AsAppError(err)
+		JsonAppError(w, appErr)
+		return
+	}
+	if len(raws) == 0 {
+		BadRequest(w, "Daftar item tidak boleh kosong", nil)
+		return
+	}
+	if b.maxItems > 0 && len(raws) > b.maxItems {
+		BadRequest(w, Translate(locale, "Maksimal %d item per request", b.maxItems), nil)
+		return
+	}
+
+	items := make([]BulkItem[T], len(raws))
+	results := make([]BulkResult, len(raws))
+	valid := make([]bool, len(raws))
+	invalid := 0
+	for i, raw := range raws {
+		item, appErr := b.decodeItem(i, raw, cfg)
+		items[i] = item
+		results[i] = BulkResult{Index: i, ID: item.ID, Op: item.Op}
+		if appErr != nil {
+			results[i].Status = appErr.StatusCode
+			results[i].Error = bulkErrorResponse(locale, appErr)
+			invalid++
+			continue
+		}
+		valid[i] = true
+	}
+
+	ctx := r.Context()
+	committed, aborted := false, false
+	if invalid == 0 || b.mode == BulkBestEffort {
+		err := DryRunTx(ctx, b.db, func(ctx context.Context, tx Tx) error {
+			return b.execute(ctx, items, valid, results, locale)
+		})
+		switch {
+		case err == nil:
+			committed = !IsDryRun(ctx)
+		case errors.Is(err, errBulkAbort):
+			aborted = true
+		default:
+			if b.logger != nil {
+				b.logger.Error("Bulk transaction failed", "error", err.Error(), "path", r.URL.Path)
+			}
+			JsonError(w, http.StatusInternalServerError, "Gagal menyimpan perubahan", nil)
+			return
+		}
+	}
+
+	meta := BulkMeta{Total: len(results), Committed: committed, DryRun: IsDryRun(ctx)}
+	for i := range results {
+		if results[i].Status == 0 || (aborted && results[i].Error == nil) {
+			// Item yang tidak dijalankan atau ikut di-rollback karena item lain gagal
+			results[i].Status = http.StatusFailedDependency
+			results[i].Data = nil
+			results[i].Error = &ErrorResponse{Message: Translate(locale, "Dibatalkan karena item lain gagal")}
+		}
+		if results[i].Error == nil {
+			meta.Succeeded++
+		} else {
+			meta.Failed++
+		}
+	}
+
+	Json(w, http.StatusMultiStatus, BulkResponse{Results: results, Meta: meta})
+}
+
+// execute menjalankan item valid di dalam transaksi batch. Pada BulkAllOrNothing, item gagal
+// pertama menghentikan batch dan mengembalikan errBulkAbort agar transaksi di-rollback.
+func (b *BulkHandler[T]) execute(ctx context.Context, items []BulkItem[T], valid []bool, results []BulkResult, locale string) error {
+	for i, item := range items {
+		if !valid[i] {
+			continue
+		}
+
+		var data interface{}
+		var err error
+		if b.mode == BulkBestEffort {
+			err = InTx(ctx, b.db, func(ctx context.Context) error {
+				data, err = b.fn(ctx, item)
+				return err
+			})
+		} else {
+			data, err = b.fn(ctx, item)
+		}
+
+		if err != nil {
+			results[i].Status, results[i].Error = b.itemError(locale, err)
+			if b.mode == BulkAllOrNothing {
+				return errBulkAbort
+			}
+			continue
+		}
+
+		results[i].Status = http.StatusOK
+		if item.Op == BulkCreate {
+			results[i].Status = http.StatusCreated
+		}
+		results[i].Data = data
+	}
+	return nil
+}
+
+// decodeItem memvalidasi op dan id lalu men-decode data item ke T.
+func (b *BulkHandler[T]) decodeItem(index int, raw bulkRawItem, cfg *bindConfig) (BulkItem[T], *AppError) {
+	item := BulkItem[T]{Index: index, Op: raw.Op}
+	appErr := NewAppError("Validasi gagal", http.StatusBadRequest)
+
+	id, ok := bulkItemID(raw.ID)
+	item.ID = id
+	if !ok {
+		appErr.WithFieldError("id", Translate(cfg.locale, "%s harus bertipe %s", "id", "string"))
+	}
+	if !b.ops[raw.Op] {
+		appErr.WithFieldError("op", Translate(cfg.locale, "%s memiliki nilai yang tidak valid", "op"))
+	}
+	if ok && raw.Op != BulkCreate && id == "" {
+		appErr.WithFieldError("id", Translate(cfg.locale, "%s wajib diisi", "id"))
+	}
+	if len(appErr.Errors) > 0 {
+		return item, appErr
+	}
+
+	data := bytes.TrimSpace(raw.Data)
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return item, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if cfg.disallowUnknownFields {
+		decoder.DisallowUnknownFields()
+	}
+	if err := decoder.Decode(&item.Data); err != nil {
+		appErr, _ := AsAppError(jsonBindError(err, cfg.locale))
+		return item, appErr
+	}
+	item.HasData = true
+
+	if err := validateBound(&item.Data, cfg); err != nil {
+		appErr, _ := AsAppError(err)
+		return item, appErr
+	}
+	return item, nil
+}
+
+// itemError mengubah error dari BulkFunc menjadi status dan ErrorResponse item.
+// Error selain AppError dan not-found disembunyikan sebagai 500.
+func (b *BulkHandler[T]) itemError(locale string, err error) (int, *ErrorResponse) {
+	if appErr, ok := AsAppError(err); ok {
+		return appErr.StatusCode, bulkErrorResponse(locale, appErr)
+	}
+	if errors.Is(err, ErrOwnerNotFound) || isNoRows(err) {
+		return http.StatusNotFound, &ErrorResponse{Message: Translate(locale, "Tidak ditemukan")}
+	}
+	if b.logger != nil {
+		b.logger.Error("Bulk item failed", "error", err.Error())
+	}
+	return http.StatusInternalServerError, &ErrorResponse{Message: Translate(locale, "Kesalahan server internal")}
+}
+
+func bulkErrorResponse(locale string, appErr *AppError) *ErrorResponse {
+	return &ErrorResponse{
+		Message: Translate(locale, appErr.Message),
+		Errors:  translateFieldErrors(locale, appErr.Errors),
+	}
+}
+
+// bulkItemID menerima id berupa string atau angka JSON.
+func bulkItemID(raw json.RawMessage) (string, bool) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return "", true
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s, true
+	}
+	var n json.Number
+	if err := json.Unmarshal(raw, &n); err == nil {
+		return strings.TrimSpace(n.String()), true
+	}
+	return "", false
+}
@@ -0,0 +1,248 @@
+package dim
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type bulkPostRequest struct {
+	Title string `json:"title" validate:"required,max=20"`
+}
+
+// newBulkTestHandler menyiapkan tabel posts berisi p-1 dan p-2 serta BulkHandler yang menulis ke tabel tersebut.
+func newBulkTestHandler(t *testing.T) (*SQLiteDatabase, *BulkHandler[bulkPostRequest]) {
+	t.Helper()
+	db := newTestSQLiteAuthDB(t)
+	ctx := context.Background()
+	if err := db.Exec(ctx, "CREATE TABLE posts (id TEXT PRIMARY KEY, title TEXT NOT NULL)"); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Exec(ctx, "INSERT INTO posts (id, title) VALUES ('p-1', 'one'), ('p-2', 'two')"); err != nil {
+		t.Fatal(err)
+	}
+
+	bulk := NewBulkHandler(db, func(ctx context.Context, item BulkItem[bulkPostRequest]) (interface{}, error) {
+		switch item.Op {
+		case BulkCreate:
+			return map[string]string{"title": item.Data.Title}, db.Exec(ctx, db.Rebind("INSERT INTO posts (id, title) VALUES ($1, $2)"), "p-new", item.Data.Title)
+		case BulkUpdate:
+			var id string
+			err := db.QueryRow(ctx, db.Rebind("UPDATE posts SET title = $1 WHERE id = $2 RETURNING id"), item.Data.Title, item.ID).Scan(&id)
+			return nil, err
+		case BulkDelete:
+			if item.ID == "locked" {
+				return nil, NewAppError("Post terkunci", http.StatusConflict)
+			}
+			if item.ID == "boom" {
+				return nil, errors.New("disk full")
+			}
+			return nil, db.Exec(ctx, db.Rebind("DELETE FROM posts WHERE id = $1"), item.ID)
+		}
+		return nil, nil
+	})
+	return db, bulk
+}
+
+func doBulk(t *testing.T, handler HandlerFunc, target, body string) (*httptest.ResponseRecorder, BulkResponse) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPatch, target, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	handler(rec, req)
+
+	var resp BulkResponse
+	if rec.Code == http.StatusMultiStatus {
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("invalid response %s: %v", rec.Body.String(), err)
+		}
+	}
+	return rec, resp
+}
+
+func postTitles(t *testing.T, db Database) map[string]string {
+	t.Helper()
+	rows, err := db.Query(context.Background(), "SELECT id, title FROM posts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	titles := map[string]string{}
+	for rows.Next() {
+		var id, title string
+		if err := rows.Scan(&id, &title); err != nil {
+			t.Fatal(err)
+		}
+		titles[id] = title
+	}
+	return titles
+}
+
+func bulkStatuses(resp BulkResponse) []int {
+	statuses := make([]int, len(resp.Results))
+	for i, r := range resp.Results {
+		statuses[i] = r.Status
+	}
+	return statuses
+}
+
+func TestBulkHandler_AllOrNothingSuccess(t *testing.T) {
+	db, bulk := newBulkTestHandler(t)
+	rec, resp := doBulk(t, bulk.Handle, "/posts", `[
+		{"id": "p-1", "op": "update", "data": {"title": "satu"}},
+		{"op": "create", "data": {"title": "baru"}},
+		{"id": "p-2", "op": "delete"}
+	]`)
+
+	if rec.Code != http.StatusMultiStatus {
+		t.Fatalf("status = %d, body %s", rec.Code, rec.Body.String())
+	}
+	if got := bulkStatuses(resp); got[0] != 200 || got[1] != 201 || got[2] != 200 {
+		t.Errorf("statuses = %v", got)
+	}
+	if !resp.Meta.Committed || resp.Meta.Succeeded != 3 || resp.Meta.Failed != 0 {
+		t.Errorf("meta = %+v", resp.Meta)
+	}
+	titles := postTitles(t, db)
+	if titles["p-1"] != "satu" || titles["p-new"] != "baru" || len(titles) != 2 {
+		t.Errorf("posts = %v", titles)
+	}
+}
+
+func TestBulkHandler_AllOrNothingRollsBack(t *testing.T) {
+	db, bulk := newBulkTestHandler(t)
+	_, resp := doBulk(t, bulk.Handle, "/posts", `[
+		{"id": "p-1", "op": "update", "data": {"title": "satu"}},
+		{"id": "p-9", "op": "update", "data": {"title": "hilang"}},
+		{"id": "p-2", "op": "delete"}
+	]`)
+
+	if got := bulkStatuses(resp); got[0] != 424 || got[1] != 404 || got[2] != 424 {
+		t.Errorf("statuses = %v", got)
+	}
+	if resp.Meta.Committed || resp.Meta.Failed != 3 {
+		t.Errorf("meta = %+v", resp.Meta)
+	}
+	if titles := postTitles(t, db); titles["p-1"] != "one" || len(titles) != 2 {
+		t.Errorf("posts changed after rollback: %v", titles)
+	}
+}
+
+func TestBulkHandler_ValidationErrors(t *testing.T) {
+	db, bulk := newBulkTestHandler(t)
+	_, resp := doBulk(t, bulk.Handle, "/posts", `[
+		{"id": "p-1", "op": "update", "data": {"title": "satu"}},
+		{"id": "p-2", "op": "update", "data": {"title": ""}},
+		{"op": "update", "data": {"title": "x"}},
+		{"id": "p-2", "op": "archive"},
+		{"id": "p-2", "op": "update", "data": {"title": 5}}
+	]`)
+
+	if got := bulkStatuses(resp); got[0] != 424 || got[1] != 400 || got[2] != 400 || got[3] != 400 || got[4] != 400 {
+		t.Errorf("statuses = %v", got)
+	}
+	if resp.Results[1].Error == nil || resp.Results[1].Error.Errors["title"] == nil {
+		t.Errorf("missing title field error: %+v", resp.Results[1].Error)
+	}
+	if resp.Results[2].Error.Errors["id"] == nil || resp.Results[3].Error.Errors["op"] == nil {
+		t.Errorf("missing id/op field errors: %+v %+v", resp.Results[2].Error, resp.Results[3].Error)
+	}
+	if titles := postTitles(t, db); titles["p-1"] != "one" {
+		t.Errorf("valid item executed despite invalid batch: %v", titles)
+	}
+}
+
+func TestBulkHandler_BestEffort(t *testing.T) {
+	db, bulk := newBulkTestHandler(t)
+	bulk.WithMode(BulkBestEffort)
+	_, resp := doBulk(t, bulk.Handle, "/posts", `[
+		{"id": "p-1", "op": "update", "data": {"title": "satu"}},
+		{"id": "p-2", "op": "update", "data": {"title": ""}},
+		{"id": "locked", "op": "delete"},
+		{"id": "boom", "op": "delete"},
+		{"op": "create", "data": {"title": "baru"}},
+		{"op": "create", "data": {"title": "duplikat"}}
+	]`)
+
+	want := []int{200, 400, 409, 500, 201, 500}
+	got := bulkStatuses(resp)
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("statuses = %v, want %v", got, want)
+			break
+		}
+	}
+	if resp.Results[2].Error.Message != "Post terkunci" {
+		t.Errorf("app error message = %q", resp.Results[2].Error.Message)
+	}
+	if resp.Results[3].Error.Message != "Kesalahan server internal" {
+		t.Errorf("internal error leaked: %q", resp.Results[3].Error.Message)
+	}
+	if !resp.Meta.Committed || resp.Meta.Succeeded != 2 || resp.Meta.Failed != 4 {
+		t.Errorf("meta = %+v", resp.Meta)
+	}
+	titles := postTitles(t, db)
+	if titles["p-1"] != "satu" || titles["p-new"] != "baru" || titles["p-2"] != "two" {
+		t.Errorf("posts = %v", titles)
+	}
+}
+
+func TestBulkHandler_DryRun(t *testing.T) {
+	db, bulk := newBulkTestHandler(t)
+	handler := DryRunMiddleware()(bulk.Handle)
+	_, resp := doBulk(t, handler, "/posts?dry_run=true", `[{"id": "p-1", "op": "delete"}]`)
+
+	if resp.Meta.Committed || !resp.Meta.DryRun || resp.Results[0].Status != 200 {
+		t.Errorf("response = %+v", resp)
+	}
+	if titles := postTitles(t, db); len(titles) != 2 {
+		t.Errorf("dry-run deleted rows: %v", titles)
+	}
+}
+
+func TestBulkHandler_RequestErrors(t *testing.T) {
+	_, bulk := newBulkTestHandler(t)
+	bulk.WithMaxItems(2)
+
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"invalid json", `{"id": 1}`, http.StatusBadRequest},
+		{"empty", `[]`, http.StatusBadRequest},
+		{"too many", `[{"op":"create"},{"op":"create"},{"op":"create"}]`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, _ := doBulk(t, bulk.Handle, "/posts", tt.body)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d (body %s)", rec.Code, tt.want, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestBulkItemID(t *testing.T) {
+	tests := []struct {
+		raw    string
+		want   string
+		wantOK bool
+	}{
+		{`"abc"`, "abc", true},
+		{`42`, "42", true},
+		{`null`, "", true},
+		{``, "", true},
+		{`{"x":1}`, "", false},
+	}
+	for _, tt := range tests {
+		got, ok := bulkItemID(json.RawMessage(tt.raw))
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("bulkItemID(%s) = %q, %v; want %q, %v", tt.raw, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
@@ -0,0 +1,427 @@
+package dim
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen dikembalikan oleh CircuitBreaker.Execute ketika circuit sedang open
+// atau kuota probe half-open sudah habis.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitState merepresentasikan state dari sebuah circuit.
+type CircuitState int
+
+const (
+	// CircuitClosed: request diteruskan normal, kegagalan dihitung.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen: request langsung ditolak sampai OpenTimeout berlalu.
+	CircuitOpen
+	// CircuitHalfOpen: sejumlah terbatas request probe diizinkan untuk menguji pemulihan.
+	CircuitHalfOpen
+)
+
+// String mengembalikan nama state untuk logging.
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitBreakerConfig berisi konfigurasi CircuitBreaker.
+type CircuitBreakerConfig struct {
+	// FailureThreshold adalah jumlah kegagalan berturut-turut sebelum circuit open (default: 5).
+	FailureThreshold int
+	// OpenTimeout adalah durasi circuit tetap open sebelum beralih ke half-open (default: 30s).
+	OpenTimeout time.Duration
+	// HalfOpenMaxRequests adalah jumlah request probe yang diizinkan saat half-open (default: 1).
+	HalfOpenMaxRequests int
+	// SuccessThreshold adalah jumlah probe sukses yang dibutuhkan untuk menutup circuit (default: 1).
+	SuccessThreshold int
+	// KeyFunc menentukan key circuit per request, misalnya per upstream.
+	// Jika nil, semua request berbagi satu circuit.
+	KeyFunc func(r *http.Request) string
+	// IsFailure menentukan apakah status response dihitung sebagai kegagalan.
+	// Jika nil, status >= 500 dianggap gagal.
+	IsFailure func(statusCode int) bool
+	// OnStateChange dipanggil setiap kali state sebuah circuit berubah (opsional).
+	// Dipanggil di luar lock sehingga boleh memanggil method CircuitBreaker (misal State).
+	OnStateChange func(key string, from, to CircuitState)
+	// IdleTimeout adalah lama circuit closed tanpa request sebelum dihapus dari memori
+	// (default: 10 menit). Mencegah map circuit tumbuh tanpa batas jika key berasal dari
+	// nilai dinamis seperti host atau route.
+	IdleTimeout time.Duration
+}
+
+// CircuitTicket adalah izin dari Allow untuk satu request. Hasil request dilaporkan dengan
+// RecordSuccess atau RecordFailure menggunakan ticket yang sama, sehingga hasil request yang
+// diizinkan sebelum circuit berpindah state diabaikan.
+type CircuitTicket struct {
+	key        string
+	generation uint64
+}
+
+// circuit menyimpan state untuk satu key.
+type circuit struct {
+	state      CircuitState
+	generation uint64 // berubah setiap transisi; ticket dari generation lain diabaikan
+	failures   int
+	successes  int
+	inFlight   int
+	openedAt   time.Time
+	lastUsed   time.Time
+}
+
+// circuitChange adalah transisi state yang dilaporkan ke OnStateChange setelah lock dilepas.
+type circuitChange struct {
+	key      string
+	from, to CircuitState
+}
+
+// CircuitBreaker melindungi route atau outbound call dari dependency yang sedang bermasalah.
+// State disimpan per key sehingga satu upstream yang gagal tidak memblokir upstream lain.
+// Thread-safe.
+type CircuitBreaker struct {
+	config     CircuitBreakerConfig
+	mu         sync.Mutex
+	circuits   map[string]*circuit
+	generation uint64
+	lastSweep  time.Time
+	now        func() time.Time
+}
+
+// NewCircuitBreaker membuat CircuitBreaker baru dengan default untuk field yang kosong.
+//
+// Parameters:
+//   - config: CircuitBreakerConfig berisi threshold dan timeout
+//
+// Returns:
+//   - *CircuitBreaker: instance circuit breaker yang siap digunakan
+//
+// Example:
+//
+//	cb := dim.NewCircuitBreaker(dim.CircuitBreakerConfig{
+//	    FailureThreshold: 5,
+//	    OpenTimeout:      30 * time.Second,
+//	    KeyFunc: func(r *http.Request) string {
+//	        return dim.GetParam(r, "provider")
+//	    },
+//	})
+func NewCircuitBreaker(config CircuitBreakerConfig) *CircuitBreaker {
+	if config.FailureThreshold <= 0 {
+		config.FailureThreshold = 5
+	}
+	if config.OpenTimeout <= 0 {
+		config.OpenTimeout = 30 * time.Second
+	}
+	if config.HalfOpenMaxRequests <= 0 {
+		config.HalfOpenMaxRequests = 1
+	}
+	if config.SuccessThreshold <= 0 {
+		config.SuccessThreshold = 1
+	}
+	if config.IdleTimeout <= 0 {
+		config.IdleTimeout = 10 * time.Minute
+	}
+	if config.IsFailure == nil {
+		config.IsFailure = func(statusCode int) bool {
+			return statusCode >= http.StatusInternalServerError
+		}
+	}
+
+	return &CircuitBreaker{
+		config:   config,
+		circuits: make(map[string]*circuit),
+		now:      time.Now,
+	}
+}
+
+// Allow mengecek apakah request untuk key boleh diteruskan.
+// Jika diizinkan, pemanggil wajib melaporkan hasilnya via RecordSuccess atau RecordFailure
+// dengan ticket yang dikembalikan.
+//
+// Parameters:
+//   - key: identifier circuit (misal nama upstream)
+//
+// Returns:
+//   - CircuitTicket: ticket untuk melaporkan hasil request
+//   - bool: true jika request boleh diteruskan
+//   - time.Duration: sisa waktu sebelum circuit mencoba half-open (hanya jika ditolak)
+//
+// Example:
+//
+//	ticket, allowed, _ := cb.Allow("payment-gateway")
+//	if !allowed {
+//	    return dim.ErrCircuitOpen
+//	}
+//	if err := client.Charge(ctx, req); err != nil {
+//	    cb.RecordFailure(ticket)
+//	    return err
+//	}
+//	cb.RecordSuccess(ticket)
+func (cb *CircuitBreaker) Allow(key string) (CircuitTicket, bool, time.Duration) {
+	cb.mu.Lock()
+	now := cb.now()
+	cb.sweep(now)
+
+	c := cb.getCircuit(key)
+	c.lastUsed = now
+
+	var change *circuitChange
+	if c.state == CircuitOpen {
+		elapsed := now.Sub(c.openedAt)
+		if elapsed < cb.config.OpenTimeout {
+			cb.mu.Unlock()
+			return CircuitTicket{}, false, cb.config.OpenTimeout - elapsed
+		}
+		change = cb.transition(key, c, CircuitHalfOpen)
+	}
+
+	allowed := true
+	if c.state == CircuitHalfOpen {
+		if c.inFlight >= cb.config.HalfOpenMaxRequests {
+			allowed = false
+		} else {
+			c.inFlight++
+		}
+	}
+	ticket := CircuitTicket{key: key, generation: c.generation}
+	cb.mu.Unlock()
+
+	cb.notify(change)
+	if !allowed {
+		return CircuitTicket{}, false, time.Second
+	}
+	return ticket, true, 0
+}
+
+// RecordSuccess melaporkan request yang berhasil untuk ticket dari Allow.
+// Ticket dari state sebelumnya (misal request yang diizinkan saat closed dan selesai setelah
+// circuit half-open) diabaikan.
+func (cb *CircuitBreaker) RecordSuccess(ticket CircuitTicket) {
+	cb.mu.Lock()
+	var change *circuitChange
+	if c := cb.ticketCircuit(ticket); c != nil {
+		switch c.state {
+		case CircuitClosed:
+			c.failures = 0
+		case CircuitHalfOpen:
+			c.inFlight--
+			c.successes++
+			if c.successes >= cb.config.SuccessThreshold {
+				change = cb.transition(ticket.key, c, CircuitClosed)
+			}
+		}
+	}
+	cb.mu.Unlock()
+	cb.notify(change)
+}
+
+// RecordFailure melaporkan request yang gagal untuk ticket dari Allow.
+// Circuit open ketika kegagalan berturut-turut mencapai FailureThreshold,
+// atau langsung open kembali jika kegagalan terjadi saat half-open.
+// Seperti RecordSuccess, ticket dari state sebelumnya diabaikan.
+func (cb *CircuitBreaker) RecordFailure(ticket CircuitTicket) {
+	cb.mu.Lock()
+	var change *circuitChange
+	if c := cb.ticketCircuit(ticket); c != nil {
+		switch c.state {
+		case CircuitClosed:
+			c.failures++
+			if c.failures >= cb.config.FailureThreshold {
+				change = cb.transition(ticket.key, c, CircuitOpen)
+			}
+		case CircuitHalfOpen:
+			c.inFlight--
+			change = cb.transition(ticket.key, c, CircuitOpen)
+		}
+	}
+	cb.mu.Unlock()
+	cb.notify(change)
+}
+
+// State mengembalikan state circuit saat ini untuk key.
+func (cb *CircuitBreaker) State(key string) CircuitState {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	c, ok := cb.circuits[key]
+	if !ok {
+		return CircuitClosed
+	}
+	return c.state
+}
+
+// Reset mengembalikan circuit untuk key ke state closed.
+func (cb *CircuitBreaker) Reset(key string) {
+	cb.mu.Lock()
+	var change *circuitChange
+	if c, ok := cb.circuits[key]; ok {
+		change = cb.transition(key, c, CircuitClosed)
+	}
+	cb.mu.Unlock()
+	cb.notify(change)
+}
+
+// Execute menjalankan fn melalui circuit breaker untuk key.
+// Berguna untuk melindungi outbound call di luar konteks middleware (HTTP client, SDK, dsb.).
+// Panic di fn dicatat sebagai kegagalan lalu diteruskan ke caller.
+//
+// Parameters:
+//   - key: identifier circuit
+//   - fn: function yang memanggil dependency
+//
+// Returns:
+//   - error: ErrCircuitOpen jika circuit open, atau error dari fn
+//
+// Example:
+//
+//	err := cb.Execute("payment-gateway", func() error {
+//	    return client.Charge(ctx, req)
+//	})
+//	if errors.Is(err, dim.ErrCircuitOpen) {
+//	    // fallback
+//	}
+func (cb *CircuitBreaker) Execute(key string, fn func() error) error {
+	ticket, allowed, _ := cb.Allow(key)
+	if !allowed {
+		return ErrCircuitOpen
+	}
+
+	// Panic dicatat sebagai kegagalan agar slot half-open tidak tertahan selamanya
+	defer func() {
+		if err := recover(); err != nil {
+			cb.RecordFailure(ticket)
+			panic(err)
+		}
+	}()
+
+	if err := fn(); err != nil {
+		cb.RecordFailure(ticket)
+		return err
+	}
+
+	cb.RecordSuccess(ticket)
+	return nil
+}
+
+// getCircuit mengambil atau membuat circuit untuk key. Caller harus memegang lock.
+func (cb *CircuitBreaker) getCircuit(key string) *circuit {
+	c, ok := cb.circuits[key]
+	if !ok {
+		cb.generation++
+		c = &circuit{state: CircuitClosed, generation: cb.generation}
+		cb.circuits[key] = c
+	}
+	return c
+}
+
+// ticketCircuit mengembalikan circuit milik ticket, atau nil jika circuit sudah berpindah
+// state (atau dihapus) sejak ticket diterbitkan. Caller harus memegang lock.
+func (cb *CircuitBreaker) ticketCircuit(ticket CircuitTicket) *circuit {
+	c, ok := cb.circuits[ticket.key]
+	if !ok || ticket.generation == 0 || c.generation != ticket.generation {
+		return nil
+	}
+	return c
+}
+
+// sweep menghapus circuit closed yang tidak dipakai selama IdleTimeout. Dijalankan paling
+// sering sekali per IdleTimeout. Caller harus memegang lock.
+func (cb *CircuitBreaker) sweep(now time.Time) {
+	if now.Sub(cb.lastSweep) < cb.config.IdleTimeout {
+		return
+	}
+	cb.lastSweep = now
+	for key, c := range cb.circuits {
+		if c.state == CircuitClosed && now.Sub(c.lastUsed) >= cb.config.IdleTimeout {
+			delete(cb.circuits, key)
+		}
+	}
+}
+
+// transition memindahkan circuit ke state baru, mereset counter, dan mengembalikan perubahan
+// untuk OnStateChange (nil jika state tidak berubah). Caller harus memegang lock.
+func (cb *CircuitBreaker) transition(key string, c *circuit, to CircuitState) *circuitChange {
+	from := c.state
+	cb.generation++
+	c.generation = cb.generation
+	c.state = to
+	c.failures = 0
+	c.successes = 0
+	c.inFlight = 0
+	if to == CircuitOpen {
+		c.openedAt = cb.now()
+	}
+
+	if from == to || cb.config.OnStateChange == nil {
+		return nil
+	}
+	return &circuitChange{key: key, from: from, to: to}
+}
+
+// notify memanggil OnStateChange untuk change. Dipanggil setelah lock dilepas.
+func (cb *CircuitBreaker) notify(change *circuitChange) {
+	if change != nil {
+		cb.config.OnStateChange(change.key, change.from, change.to)
+	}
+}
+
+// CircuitBreakerMiddleware membuat middleware yang menolak request dengan 503 Service Unavailable
+// ketika circuit untuk request tersebut sedang open. Status response handler dicatat sebagai
+// sukses atau gagal sesuai IsFailure pada konfigurasi.
+// Berguna untuk route yang mem-proxy ke service eksternal yang tidak stabil.
+//
+// Parameters:
+//   - cb: *CircuitBreaker yang menyimpan state circuit
+//
+// Returns:
+//   - MiddlewareFunc: middleware function untuk router
+//
+// Example:
+//
+//	cb := dim.NewCircuitBreaker(dim.CircuitBreakerConfig{FailureThreshold: 3})
+//	router.Get("/weather", weatherHandler, dim.CircuitBreakerMiddleware(cb))
+func CircuitBreakerMiddleware(cb *CircuitBreaker) MiddlewareFunc {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			key := ""
+			if cb.config.KeyFunc != nil {
+				key = cb.config.KeyFunc(r)
+			}
+
+			ticket, allowed, retryAfter := cb.Allow(key)
+			if !allowed {
+				ServiceUnavailable(w, int(math.Ceil(retryAfter.Seconds())))
+				return
+			}
+
+			rw := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+
+			defer func() {
+				if err := recover(); err != nil {
+					cb.RecordFailure(ticket)
+					panic(err)
+				}
+			}()
+
+			next(rw, r)
+
+			if cb.config.IsFailure(rw.statusCode) {
+				cb.RecordFailure(ticket)
+				return
+			}
+			cb.RecordSuccess(ticket)
+		}
+	}
+}
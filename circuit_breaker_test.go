@@ -0,0 +1,300 @@
+package dim
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newTestCircuitBreaker(config CircuitBreakerConfig) (*CircuitBreaker, *time.Time) {
+	cb := NewCircuitBreaker(config)
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	cb.now = func() time.Time { return now }
+	return cb, &now
+}
+
+// recordCircuit mengambil ticket dari Allow lalu melaporkan hasilnya.
+func recordCircuit(t *testing.T, cb *CircuitBreaker, key string, success bool) {
+	t.Helper()
+	ticket, allowed, _ := cb.Allow(key)
+	if !allowed {
+		t.Fatalf("request for %s should be allowed", key)
+	}
+	if success {
+		cb.RecordSuccess(ticket)
+	} else {
+		cb.RecordFailure(ticket)
+	}
+}
+
+func TestCircuitBreaker_OpensAfterThreshold(t *testing.T) {
+	cb, _ := newTestCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 3})
+
+	for i := 0; i < 3; i++ {
+		recordCircuit(t, cb, "api", false)
+	}
+
+	if state := cb.State("api"); state != CircuitOpen {
+		t.Fatalf("expected state open, got %s", state)
+	}
+
+	_, allowed, retryAfter := cb.Allow("api")
+	if allowed {
+		t.Error("expected request to be rejected while open")
+	}
+	if retryAfter != 30*time.Second {
+		t.Errorf("expected retryAfter 30s, got %v", retryAfter)
+	}
+}
+
+func TestCircuitBreaker_SuccessResetsFailures(t *testing.T) {
+	cb, _ := newTestCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 2})
+
+	recordCircuit(t, cb, "api", false)
+	recordCircuit(t, cb, "api", true)
+	recordCircuit(t, cb, "api", false)
+
+	if state := cb.State("api"); state != CircuitClosed {
+		t.Errorf("expected state closed, got %s", state)
+	}
+}
+
+func TestCircuitBreaker_HalfOpenProbe(t *testing.T) {
+	cb, now := newTestCircuitBreaker(CircuitBreakerConfig{
+		FailureThreshold: 1,
+		OpenTimeout:      10 * time.Second,
+	})
+
+	recordCircuit(t, cb, "api", false)
+	*now = now.Add(10 * time.Second)
+
+	probe, allowed, _ := cb.Allow("api")
+	if !allowed {
+		t.Fatal("expected probe to be allowed after open timeout")
+	}
+	if state := cb.State("api"); state != CircuitHalfOpen {
+		t.Fatalf("expected state half-open, got %s", state)
+	}
+
+	// Only one probe allowed by default
+	if _, allowed, _ := cb.Allow("api"); allowed {
+		t.Error("expected second probe to be rejected")
+	}
+
+	cb.RecordSuccess(probe)
+	if state := cb.State("api"); state != CircuitClosed {
+		t.Errorf("expected state closed after successful probe, got %s", state)
+	}
+}
+
+func TestCircuitBreaker_HalfOpenFailureReopens(t *testing.T) {
+	cb, now := newTestCircuitBreaker(CircuitBreakerConfig{
+		FailureThreshold: 1,
+		OpenTimeout:      10 * time.Second,
+	})
+
+	recordCircuit(t, cb, "api", false)
+	*now = now.Add(10 * time.Second)
+	recordCircuit(t, cb, "api", false)
+
+	if state := cb.State("api"); state != CircuitOpen {
+		t.Errorf("expected state open after failed probe, got %s", state)
+	}
+}
+
+func TestCircuitBreaker_PerKeyState(t *testing.T) {
+	cb, _ := newTestCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 1})
+
+	recordCircuit(t, cb, "upstream-a", false)
+
+	if state := cb.State("upstream-a"); state != CircuitOpen {
+		t.Errorf("expected upstream-a open, got %s", state)
+	}
+	if _, allowed, _ := cb.Allow("upstream-b"); !allowed {
+		t.Error("expected upstream-b to be unaffected")
+	}
+}
+
+func TestCircuitBreaker_OnStateChange(t *testing.T) {
+	var transitions []string
+	var cb *CircuitBreaker
+	cb, _ = newTestCircuitBreaker(CircuitBreakerConfig{
+		FailureThreshold: 1,
+		OnStateChange: func(key string, from, to CircuitState) {
+			// Callback dipanggil di luar lock sehingga boleh membaca state
+			if state := cb.State(key); state != to {
+				t.Errorf("State() in callback = %s, want %s", state, to)
+			}
+			transitions = append(transitions, key+":"+from.String()+"->"+to.String())
+		},
+	})
+
+	recordCircuit(t, cb, "api", false)
+	cb.Reset("api")
+
+	expected := []string{"api:closed->open", "api:open->closed"}
+	if len(transitions) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, transitions)
+	}
+	for i := range expected {
+		if transitions[i] != expected[i] {
+			t.Errorf("transition %d: expected %s, got %s", i, expected[i], transitions[i])
+		}
+	}
+}
+
+func TestCircuitBreaker_IgnoresStaleTickets(t *testing.T) {
+	cb, now := newTestCircuitBreaker(CircuitBreakerConfig{
+		FailureThreshold: 1,
+		OpenTimeout:      10 * time.Second,
+	})
+
+	// Request diizinkan saat closed, tetapi selesai setelah circuit open lalu half-open
+	slow, _, _ := cb.Allow("api")
+	recordCircuit(t, cb, "api", false)
+	*now = now.Add(10 * time.Second)
+	probe, allowed, _ := cb.Allow("api")
+	if !allowed {
+		t.Fatal("expected probe to be allowed")
+	}
+
+	cb.RecordSuccess(slow)
+	if state := cb.State("api"); state != CircuitHalfOpen {
+		t.Fatalf("stale success changed state to %s", state)
+	}
+	cb.RecordFailure(slow)
+	if state := cb.State("api"); state != CircuitHalfOpen {
+		t.Fatalf("stale failure changed state to %s", state)
+	}
+
+	cb.RecordSuccess(probe)
+	if state := cb.State("api"); state != CircuitClosed {
+		t.Errorf("expected state closed after probe, got %s", state)
+	}
+	// Ticket yang sudah dilaporkan tidak dapat dipakai ulang
+	cb.RecordFailure(probe)
+	if state := cb.State("api"); state != CircuitClosed {
+		t.Errorf("reused ticket changed state to %s", state)
+	}
+}
+
+func TestCircuitBreaker_EvictsIdleCircuits(t *testing.T) {
+	cb, now := newTestCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 1, IdleTimeout: time.Minute})
+
+	recordCircuit(t, cb, "idle", true)
+	recordCircuit(t, cb, "broken", false)
+	*now = now.Add(time.Minute)
+	recordCircuit(t, cb, "active", true)
+
+	cb.mu.Lock()
+	_, idle := cb.circuits["idle"]
+	_, broken := cb.circuits["broken"]
+	cb.mu.Unlock()
+	if idle {
+		t.Error("expected idle closed circuit to be evicted")
+	}
+	if !broken {
+		t.Error("open circuit must not be evicted")
+	}
+}
+
+func TestCircuitBreaker_Execute(t *testing.T) {
+	cb, _ := newTestCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 1})
+	upstreamErr := errors.New("upstream down")
+
+	if err := cb.Execute("api", func() error { return upstreamErr }); !errors.Is(err, upstreamErr) {
+		t.Errorf("expected upstream error, got %v", err)
+	}
+
+	called := false
+	err := cb.Execute("api", func() error {
+		called = true
+		return nil
+	})
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("expected ErrCircuitOpen, got %v", err)
+	}
+	if called {
+		t.Error("fn should not be called while circuit is open")
+	}
+}
+
+func TestCircuitBreaker_ExecutePanicReleasesHalfOpenSlot(t *testing.T) {
+	cb, now := newTestCircuitBreaker(CircuitBreakerConfig{
+		FailureThreshold: 1,
+		OpenTimeout:      10 * time.Second,
+	})
+	recordCircuit(t, cb, "api", false)
+	*now = now.Add(10 * time.Second)
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected panic to be re-raised")
+			}
+		}()
+		cb.Execute("api", func() error { panic("boom") })
+	}()
+
+	if state := cb.State("api"); state != CircuitOpen {
+		t.Fatalf("expected state open after panicking probe, got %s", state)
+	}
+	*now = now.Add(10 * time.Second)
+	if err := cb.Execute("api", func() error { return nil }); err != nil {
+		t.Errorf("expected next probe to be allowed, got %v", err)
+	}
+	if state := cb.State("api"); state != CircuitClosed {
+		t.Errorf("expected state closed, got %s", state)
+	}
+}
+
+func TestCircuitBreakerMiddleware(t *testing.T) {
+	cb, _ := newTestCircuitBreaker(CircuitBreakerConfig{
+		FailureThreshold: 2,
+		OpenTimeout:      5 * time.Second,
+		KeyFunc: func(r *http.Request) string {
+			return r.URL.Query().Get("upstream")
+		},
+	})
+
+	handler := CircuitBreakerMiddleware(cb)(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	})
+
+	for i := 0; i < 2; i++ {
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(http.MethodGet, "/proxy?upstream=a", nil))
+		if rec.Code != http.StatusBadGateway {
+			t.Fatalf("expected 502 from handler, got %d", rec.Code)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, "/proxy?upstream=a", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 when open, got %d", rec.Code)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "5" {
+		t.Errorf("expected Retry-After 5, got %q", got)
+	}
+
+	rec = httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, "/proxy?upstream=b", nil))
+	if rec.Code != http.StatusBadGateway {
+		t.Errorf("expected other upstream to pass through, got %d", rec.Code)
+	}
+}
+
+func TestServiceUnavailable(t *testing.T) {
+	rec := httptest.NewRecorder()
+	ServiceUnavailable(rec, 30)
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503, got %d", rec.Code)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "30" {
+		t.Errorf("expected Retry-After 30, got %q", got)
+	}
+}
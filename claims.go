@@ -0,0 +1,208 @@
+package dim
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// Claims adalah claim standar access token dalam bentuk bertipe, sebagai pengganti asersi
+// claims["x"].(string) pada TokenClaims. Claim lain (dari ClaimsProvider atau extraClaims)
+// tersedia di Custom.
+//
+// Claims dapat di-embed ke struct claim milik aplikasi untuk dipakai dengan ClaimsAs:
+//
+//	type AppClaims struct {
+//	    dim.Claims
+//	    OrgID string   `json:"org_id"`
+//	    Roles []string `json:"roles"`
+//	}
+type Claims struct {
+	Subject   string       `json:"sub"`
+	Email     string       `json:"email,omitempty"`
+	SessionID string       `json:"sid,omitempty"`
+	ID        string       `json:"jti,omitempty"`
+	Issuer    string       `json:"iss,omitempty"`
+	Audience  ClaimStrings `json:"aud,omitempty"`
+	IssuedAt  NumericDate  `json:"iat,omitzero"`
+	ExpiresAt NumericDate  `json:"exp,omitzero"`
+	NotBefore NumericDate  `json:"nbf,omitzero"`
+	// Custom berisi claim selain claim standar di atas. Hanya diisi oleh ParseClaims.
+	Custom map[string]interface{} `json:"-"`
+}
+
+// standardClaims adalah claim yang dipetakan ke field Claims (typ dipakai BrancaManager).
+var standardClaims = map[string]bool{
+	"sub": true, "email": true, "sid": true, "jti": true, "iss": true,
+	"aud": true, "iat": true, "exp": true, "nbf": true, "typ": true,
+}
+
+// NumericDate adalah waktu claim JWT (detik Unix, RFC 7519) yang di-encode sebagai angka JSON.
+type NumericDate struct {
+	time.Time
+}
+
+// MarshalJSON meng-encode waktu sebagai detik Unix.
+func (d NumericDate) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.FormatInt(d.Unix(), 10)), nil
+}
+
+// UnmarshalJSON men-decode detik Unix (boleh pecahan).
+func (d *NumericDate) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	seconds, err := strconv.ParseFloat(string(data), 64)
+	if err != nil {
+		return fmt.Errorf("invalid numeric date %s", data)
+	}
+	whole, frac := math.Modf(seconds)
+	d.Time = time.Unix(int64(whole), int64(frac*1e9))
+	return nil
+}
+
+// ClaimStrings adalah claim yang boleh berupa string tunggal atau array string, seperti aud.
+type ClaimStrings []string
+
+// UnmarshalJSON menerima string maupun array string.
+func (s *ClaimStrings) UnmarshalJSON(data []byte) error {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte(`"`)) {
+		var single string
+		if err := json.Unmarshal(data, &single); err != nil {
+			return err
+		}
+		*s = ClaimStrings{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*s = list
+	return nil
+}
+
+// Contains mengembalikan true jika value ada di dalam claim.
+func (s ClaimStrings) Contains(value string) bool {
+	return slices.Contains(s, value)
+}
+
+// ParseClaims mengubah TokenClaims (hasil VerifyToken) menjadi Claims bertipe.
+//
+// Parameters:
+//   - claims: map claims dari TokenManager.VerifyToken atau GetClaims
+//
+// Returns:
+//   - *Claims: claim standar dengan claim lain di Custom
+//   - error: error jika tipe claim standar tidak sesuai (misal exp bukan angka)
+//
+// Example:
+//
+//	raw, err := tokenManager.VerifyToken(token)
+//	claims, err := dim.ParseClaims(raw)
+//	log.Println(claims.Subject, claims.ExpiresAt)
+func ParseClaims(claims TokenClaims) (*Claims, error) {
+	parsed, err := ClaimsAs[Claims](claims)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range claims {
+		if standardClaims[k] {
+			continue
+		}
+		if parsed.Custom == nil {
+			parsed.Custom = make(map[string]interface{})
+		}
+		parsed.Custom[k] = v
+	}
+	return &parsed, nil
+}
+
+// ClaimsAs men-decode TokenClaims ke struct claim milik aplikasi lewat tag json. Embed Claims
+// untuk ikut mendapatkan claim standar.
+//
+// Parameters:
+//   - claims: map claims dari VerifyToken atau GetClaims
+//
+// Returns:
+//   - T: struct claim yang sudah terisi
+//   - error: error jika tipe claim tidak sesuai dengan field T
+//
+// Example:
+//
+//	claims, err := dim.ClaimsAs[AppClaims](raw)
+//	if err == nil && slices.Contains(claims.Roles, "admin") { ... }
+func ClaimsAs[T any](claims TokenClaims) (T, error) {
+	var out T
+	data, err := json.Marshal(claims)
+	if err != nil {
+		return out, fmt.Errorf("invalid token claims: %w", err)
+	}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return out, fmt.Errorf("invalid token claims: %w", err)
+	}
+	return out, nil
+}
+
+// VerifyClaims memverifikasi access token lewat TokenManager lalu men-decode claims-nya ke T.
+//
+// Example:
+//
+//	claims, err := dim.VerifyClaims[AppClaims](jwtManager, token)
+func VerifyClaims[T any](tm TokenManager, token string) (T, error) {
+	raw, err := tm.VerifyToken(token)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return ClaimsAs[T](raw)
+}
+
+// ErrNoTokenClaims dikembalikan GetClaimsAs jika request tidak membawa claims token
+// (route tanpa RequireAuth atau user tidak terotentikasi).
+var ErrNoTokenClaims = errors.New("request has no token claims")
+
+// GetTypedClaims mengambil Claims bertipe dari request yang sudah melewati RequireAuth.
+//
+// Returns:
+//   - *Claims: claims token
+//   - bool: false jika request tidak terotentikasi atau claims tidak valid
+//
+// Example:
+//
+//	claims, ok := dim.GetTypedClaims(r)
+//	if ok {
+//	    log.Println("session", claims.SessionID)
+//	}
+func GetTypedClaims(r *http.Request) (*Claims, bool) {
+	raw := GetClaims(r)
+	if raw == nil {
+		return nil, false
+	}
+	claims, err := ParseClaims(raw)
+	return claims, err == nil
+}
+
+// GetClaimsAs men-decode claims request yang sudah melewati RequireAuth ke struct T.
+//
+// Example:
+//
+//	claims, err := dim.GetClaimsAs[AppClaims](r)
+//	if err != nil {
+//	    dim.Unauthorized(w, "Token tidak valid")
+//	    return
+//	}
+func GetClaimsAs[T any](r *http.Request) (T, error) {
+	raw := GetClaims(r)
+	if raw == nil {
+		var zero T
+		return zero, ErrNoTokenClaims
+	}
+	return ClaimsAs[T](raw)
+}
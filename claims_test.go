@@ -0,0 +1,106 @@
+package dim
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type testAppClaims struct {
+	Claims
+	OrgID string   `json:"org_id"`
+	Roles []string `json:"roles"`
+}
+
+func TestParseClaims_JWT(t *testing.T) {
+	manager, err := NewJWTManager(&JWTConfig{
+		HMACSecret:        "test-secret",
+		SigningMethod:     "HS256",
+		AccessTokenExpiry: 15 * time.Minute,
+		Issuer:            "https://auth.example.com",
+		Audience:          []string{"api"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, _ := manager.GenerateAccessToken("u-1", "ana@example.com", "sid-1", map[string]interface{}{"org_id": "org-9"})
+	raw, err := manager.VerifyToken(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, err := ParseClaims(raw)
+	if err != nil {
+		t.Fatalf("ParseClaims() error = %v", err)
+	}
+	if claims.Subject != "u-1" || claims.Email != "ana@example.com" || claims.SessionID != "sid-1" || claims.ID == "" {
+		t.Errorf("claims = %+v", claims)
+	}
+	if claims.Issuer != "https://auth.example.com" || !claims.Audience.Contains("api") {
+		t.Errorf("iss/aud = %q/%v", claims.Issuer, claims.Audience)
+	}
+	if d := time.Until(claims.ExpiresAt.Time); d <= 14*time.Minute || d > 15*time.Minute {
+		t.Errorf("ExpiresAt = %v", claims.ExpiresAt)
+	}
+	if len(claims.Custom) != 1 || claims.Custom["org_id"] != "org-9" {
+		t.Errorf("Custom = %v", claims.Custom)
+	}
+}
+
+func TestVerifyClaims_AppStruct(t *testing.T) {
+	manager, err := NewBrancaManager(&BrancaConfig{
+		Key:               testBrancaKey(),
+		AccessTokenExpiry: time.Minute,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, _ := manager.GenerateAccessToken("u-1", "ana@example.com", "sid-1", map[string]interface{}{
+		"org_id": "org-9",
+		"roles":  []string{"admin", "billing"},
+	})
+
+	claims, err := VerifyClaims[testAppClaims](manager, token)
+	if err != nil {
+		t.Fatalf("VerifyClaims() error = %v", err)
+	}
+	if claims.Subject != "u-1" || claims.OrgID != "org-9" || len(claims.Roles) != 2 || claims.ExpiresAt.IsZero() {
+		t.Errorf("claims = %+v", claims)
+	}
+
+	if _, err := VerifyClaims[testAppClaims](manager, token+"x"); err == nil {
+		t.Error("invalid token should fail")
+	}
+}
+
+func TestClaimsAs_TypeMismatch(t *testing.T) {
+	if _, err := ClaimsAs[testAppClaims](TokenClaims{"sub": "u-1", "roles": "admin"}); err == nil {
+		t.Error("roles string should not decode into []string")
+	}
+	if _, err := ParseClaims(TokenClaims{"sub": "u-1", "exp": "tomorrow"}); err == nil {
+		t.Error("non-numeric exp should fail")
+	}
+}
+
+func TestGetClaimsAs(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+	if _, err := GetClaimsAs[testAppClaims](req); !errors.Is(err, ErrNoTokenClaims) {
+		t.Errorf("err = %v, want ErrNoTokenClaims", err)
+	}
+	if _, ok := GetTypedClaims(req); ok {
+		t.Error("GetTypedClaims should fail without user")
+	}
+
+	req = SetUser(req, &TokenUser{ID: "u-1", Claims: map[string]interface{}{
+		"sub": "u-1", "sid": "sid-1", "aud": []interface{}{"api", "admin"}, "exp": float64(1893456000), "org_id": "org-9",
+	}})
+	claims, err := GetClaimsAs[testAppClaims](req)
+	if err != nil || claims.OrgID != "org-9" || claims.SessionID != "sid-1" {
+		t.Fatalf("GetClaimsAs = %+v, %v", claims, err)
+	}
+	typed, ok := GetTypedClaims(req)
+	if !ok || !typed.Audience.Contains("admin") || typed.ExpiresAt.Unix() != 1893456000 {
+		t.Errorf("GetTypedClaims = %+v, %v", typed, ok)
+	}
+}
@@ -1,9 +1,11 @@
 package dim
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"os"
 	"strings"
 	"time"
@@ -19,125 +21,241 @@ type Config struct {
 	RateLimit RateLimitConfig
 	CORS      CORSConfig
 	CSRF      CSRFConfig
+	Password  PasswordConfig
+	Redis     RedisConfig
+
+	// source adalah sumber nilai saat Config dimuat, dipakai oleh RegisterSection.
+	source configSource
+	// sections adalah section milik aplikasi yang didaftarkan lewat RegisterSection.
+	sections []*configSection
 }
 
 // ServerConfig holds server configuration
 type ServerConfig struct {
-	Port            string
-	ReadTimeout     time.Duration
-	WriteTimeout    time.Duration
-	IdleTimeout     time.Duration
-	ShutdownTimeout time.Duration
+	// Env adalah environment aplikasi dari APP_ENV (default: "development").
+	// Validasi yang lebih ketat diterapkan jika bernilai "production".
+	Env             string        `env:"APP_ENV" desc:"Application environment; production enables stricter validation"`
+	Port            string        `env:"SERVER_PORT" desc:"HTTP listen port"`
+	ReadTimeout     time.Duration `env:"SERVER_READ_TIMEOUT" desc:"Maximum duration for reading the entire request"`
+	WriteTimeout    time.Duration `env:"SERVER_WRITE_TIMEOUT" desc:"Maximum duration before timing out writes of the response"`
+	IdleTimeout     time.Duration `env:"SERVER_IDLE_TIMEOUT" desc:"Maximum time to wait for the next request on keep-alive connections"`
+	ShutdownTimeout time.Duration `env:"SERVER_SHUTDOWN_TIMEOUT" desc:"Maximum time to wait for in-flight requests during graceful shutdown"`
+
+	// TLSCertFile dan TLSKeyFile mengaktifkan HTTPS dengan sertifikat dari file (PEM).
+	TLSCertFile string `env:"SERVER_TLS_CERT_FILE" desc:"Path to the PEM certificate file; enables HTTPS"`
+	TLSKeyFile  string `env:"SERVER_TLS_KEY_FILE" desc:"Path to the PEM private key file"`
+	// AutoTLSDomains mengaktifkan sertifikat otomatis Let's Encrypt (ACME) untuk domain-domain ini.
+	AutoTLSDomains []string `env:"SERVER_AUTOTLS_DOMAINS" desc:"Comma-separated domains for automatic Let's Encrypt certificates"`
+	// AutoTLSCacheDir adalah direktori penyimpanan sertifikat ACME (default: "autocert-cache").
+	AutoTLSCacheDir string `env:"SERVER_AUTOTLS_CACHE_DIR" desc:"Directory for cached ACME certificates"`
+	// AutoTLSEmail adalah email kontak akun ACME (opsional).
+	AutoTLSEmail string `env:"SERVER_AUTOTLS_EMAIL" desc:"Contact email for the ACME account"`
+	// HTTPRedirectAddr membuka listener HTTP tambahan (misal ":80") yang me-redirect ke HTTPS
+	// dan melayani challenge ACME HTTP-01.
+	HTTPRedirectAddr string `env:"SERVER_HTTP_REDIRECT_ADDR" desc:"Address of an extra HTTP listener that redirects to HTTPS, e.g. :80"`
+	// H2C mengaktifkan HTTP/2 cleartext di listener tanpa TLS (SERVER_H2C).
+	H2C bool `env:"SERVER_H2C" desc:"Enable HTTP/2 cleartext on non-TLS listeners"`
+	// UnixSocket membuka listener unix socket tambahan di path ini (SERVER_UNIX_SOCKET).
+	UnixSocket string `env:"SERVER_UNIX_SOCKET" desc:"Path of an additional unix socket listener"`
+	// InternalPort membuka listener terpisah untuk route Router.Internal (INTERNAL_PORT), misal
+	// "9090" atau "127.0.0.1:9090". Kosong berarti route internal tidak dilayani.
+	InternalPort string `env:"INTERNAL_PORT" desc:"Port or address for routes registered with Router.Internal; empty disables them"`
 }
 
 // JWTConfig holds JWT configuration
 type JWTConfig struct {
-	AccessTokenExpiry  time.Duration
-	RefreshTokenExpiry time.Duration
+	AccessTokenExpiry  time.Duration `env:"JWT_ACCESS_TOKEN_EXPIRY" desc:"Access token lifetime"`
+	RefreshTokenExpiry time.Duration `env:"JWT_REFRESH_TOKEN_EXPIRY" desc:"Refresh token lifetime"`
+
+	// Session lifetime: remember-me logins get a longer refresh token, and every refresh slides
+	// the session forward but never past SessionMaxLifetime counted from login (0 = no cap)
+	RememberMeExpiry   time.Duration `env:"JWT_REMEMBER_ME_EXPIRY" desc:"Refresh token lifetime for remember-me logins"`
+	SessionMaxLifetime time.Duration `env:"JWT_SESSION_MAX_LIFETIME" desc:"Absolute session cap from login that sliding refresh cannot extend; 0 disables"`
 
 	// Algorithm configuration
-	SigningMethod string // "HS256" (default), "RS256", "ES256"
+	SigningMethod string `env:"JWT_SIGNING_METHOD" desc:"JWT signing algorithm, e.g. HS256, RS256, ES256"` // "HS256" (default), "RS256", "ES256"
 
 	// Symmetric Config (HMAC: HS256, HS384, HS512)
-	HMACSecret string
+	HMACSecret string `env:"JWT_SECRET" desc:"HMAC secret; required for HS* methods unless BRANCA_KEY is set"`
 
 	// Asymmetric Config (RSA/ECDSA: RS256, ES256)
-	PrivateKey string            // PEM content for Signing
-	PublicKeys map[string]string // Key ID (kid) -> PEM content Public Key (for rotation)
+	PrivateKey string            `env:"JWT_PRIVATE_KEY" desc:"PEM private key, base64-encoded PEM or file path; required for RS*/ES* methods"` // PEM content for Signing
+	PublicKeys map[string]string `env:"JWT_PUBLIC_KEYS" desc:"JSON object of key ID to PEM public key, for key rotation"`                      // Key ID (kid) -> PEM content Public Key (for rotation)
 
 	// Remote Verification (JWKS)
-	JWKSURL string
+	JWKSURL string `env:"JWT_JWKS_URL" desc:"JWKS endpoint for remote token verification"`
+	// JWKSCacheTTL overrides how long fetched JWKS keys are cached; 0 follows the response's
+	// Cache-Control max-age (default 1h when absent)
+	JWKSCacheTTL time.Duration `env:"JWT_JWKS_CACHE_TTL" desc:"How long remote JWKS keys are cached; empty follows Cache-Control max-age"`
+
+	// Claim validation: tokens with a different iss/aud (e.g. from another environment) are rejected
+	Issuer          string        `env:"JWT_ISSUER" desc:"Value of the iss claim; tokens with another issuer are rejected"`
+	Audience        []string      `env:"JWT_AUDIENCE" desc:"Comma-separated aud claim values; tokens must contain at least one"`
+	ClockSkewLeeway time.Duration `env:"JWT_CLOCK_SKEW_LEEWAY" desc:"Tolerance for exp/nbf checks between servers with clock drift"`
 }
 
 // DatabaseConfig holds database configuration
 type DatabaseConfig struct {
-	Driver        string // "postgres" or "sqlite"
-	WriteHost     string
-	ReadHosts     []string
-	Port          int
-	Database      string
-	Username      string
-	Password      string
-	MaxConns      int
-	SSLMode       string            // SSL mode: "disable", "require", "prefer", "allow", "verify-ca", "verify-full" (default: "disable")
-	RuntimeParams map[string]string // Custom runtime parameters (search_path, standard_conforming_strings, etc)
-	QueryExecMode string            // Query execution mode: "simple" or "" (default)
+	Driver        string            `env:"DB_DRIVER" validate:"oneof=postgres|mysql|sqlite" desc:"Database driver"` // "postgres", "mysql", or "sqlite"
+	WriteHost     string            `env:"DB_WRITE_HOST" desc:"Primary host; required for postgres and mysql"`
+	ReadHosts     []string          `env:"DB_READ_HOSTS" desc:"Comma-separated read replica hosts (postgres only)"`
+	Port          int               `env:"DB_PORT" desc:"Database port (3306 when DB_DRIVER is mysql)"`
+	Database      string            `env:"DB_NAME" validate:"required" desc:"Database name, or file path for sqlite"`
+	Username      string            `env:"DB_USER" desc:"Database user; required for postgres and mysql"`
+	Password      string            `env:"DB_PASSWORD" desc:"Database password"`
+	MaxConns      int               `env:"DB_MAX_CONNS" desc:"Maximum open connections per pool"`
+	SSLMode       string            `env:"DB_SSL_MODE" validate:"oneof=disable|allow|prefer|require|verify-ca|verify-full" desc:"TLS mode for the database connection"` // SSL mode: "disable", "require", "prefer", "allow", "verify-ca", "verify-full" (default: "disable")
+	RuntimeParams map[string]string `env:"-"`                                                                                                                           // Custom runtime parameters (search_path, standard_conforming_strings, etc)
+	QueryExecMode string            `env:"-"`                                                                                                                           // Query execution mode: "simple" or "" (default)
+
+	// StatementTimeoutFromDeadline mengaktifkan SET LOCAL statement_timeout yang diturunkan
+	// dari deadline context (lihat WithBudget/DeadlineBudget), sehingga query dibatalkan di sisi
+	// server ketika budget request habis. DB_STATEMENT_TIMEOUT_FROM_DEADLINE (default: false).
+	StatementTimeoutFromDeadline bool `env:"DB_STATEMENT_TIMEOUT_FROM_DEADLINE" desc:"Derive statement_timeout from the request deadline (postgres)"`
 
 	// Migration-specific connection overrides.
 	// If empty, the corresponding Write connection value is used as fallback.
-	MigrationHost     string // DB_MIGRATION_HOST (fallback: WriteHost)
-	MigrationPort     int    // DB_MIGRATION_PORT (fallback: Port)
-	MigrationUsername string // DB_MIGRATION_USER (fallback: Username)
-	MigrationPassword string // DB_MIGRATION_PASSWORD (fallback: Password)
+	MigrationHost     string `env:"DB_MIGRATION_HOST" desc:"Host for migrations (fallback: DB_WRITE_HOST)"`       // DB_MIGRATION_HOST (fallback: WriteHost)
+	MigrationPort     int    `env:"DB_MIGRATION_PORT" desc:"Port for migrations; 0 uses DB_PORT"`                 // DB_MIGRATION_PORT (fallback: Port)
+	MigrationUsername string `env:"DB_MIGRATION_USER" desc:"User for migrations (fallback: DB_USER)"`             // DB_MIGRATION_USER (fallback: Username)
+	MigrationPassword string `env:"DB_MIGRATION_PASSWORD" desc:"Password for migrations (fallback: DB_PASSWORD)"` // DB_MIGRATION_PASSWORD (fallback: Password)
 }
 
 // EmailConfig holds email configuration and branding settings.
 type EmailConfig struct {
 	// From is the default sender email address.
-	From string
+	From string `env:"MAIL_FROM" desc:"Default sender email address"`
+
+	// Transport is the mail delivery method: "smtp", "ses", "mailgun", "sendgrid", "postmark",
+	// "resend", "log", or "null" (default: "null"). "log" captures emails in memory for the
+	// development preview (see CaptureMailer) and is rejected in production.
+	Transport string `env:"MAIL_TRANSPORT" validate:"oneof=smtp|ses|mailgun|sendgrid|postmark|resend|log|null" desc:"Mail delivery method"`
 
-	// Transport is the mail delivery method: "smtp", "ses", or "null" (default: "null").
-	Transport string
+	// CaptureLimit is the number of recent emails kept by the "log" transport (default: 50).
+	CaptureLimit int `env:"MAIL_CAPTURE_LIMIT" desc:"Number of recent emails kept by the log transport"`
 
 	// SMTP Configuration (required if Transport is "smtp")
-	SMTPHost     string
-	SMTPPort     int
-	SMTPUsername string
-	SMTPPassword string
+	SMTPHost     string `env:"MAIL_SMTP_HOST" desc:"SMTP host; required when MAIL_TRANSPORT is smtp"`
+	SMTPPort     int    `env:"MAIL_SMTP_PORT" desc:"SMTP port"`
+	SMTPUsername string `env:"MAIL_SMTP_USERNAME" desc:"SMTP username"`
+	SMTPPassword string `env:"MAIL_SMTP_PASSWORD" desc:"SMTP password"`
 
 	// SES Configuration (required if Transport is "ses")
-	SESRegion           string
-	SESAccessKeyID      string
-	SESSecretAccessKey  string
-	SESConfigurationSet string
+	SESRegion           string `env:"AWS_REGION" desc:"SES region (fallback: SES_REGION); required when MAIL_TRANSPORT is ses"`
+	SESAccessKeyID      string `env:"AWS_ACCESS_KEY_ID" desc:"SES access key ID (fallback: SES_ACCESS_KEY_ID)"`
+	SESSecretAccessKey  string `env:"AWS_SECRET_ACCESS_KEY" desc:"SES secret access key (fallback: SES_SECRET_ACCESS_KEY)"`
+	SESConfigurationSet string `env:"SES_CONFIGURATION_SET" desc:"SES configuration set name"`
+
+	// Mailgun Configuration (required if Transport is "mailgun")
+	MailgunDomain            string `env:"MAILGUN_DOMAIN" desc:"Mailgun sending domain; required when MAIL_TRANSPORT is mailgun"`
+	MailgunAPIKey            string `env:"MAILGUN_API_KEY" desc:"Mailgun API key; required when MAIL_TRANSPORT is mailgun"`
+	MailgunAPIBase           string `env:"MAILGUN_API_BASE" desc:"Mailgun API base URL (https://api.eu.mailgun.net for the EU region)"`
+	MailgunWebhookSigningKey string `env:"MAILGUN_WEBHOOK_SIGNING_KEY" desc:"Mailgun webhook signing key for bounce/complaint webhooks"`
+
+	// SendGrid Configuration (required if Transport is "sendgrid")
+	SendGridAPIKey           string `env:"SENDGRID_API_KEY" desc:"SendGrid API key; required when MAIL_TRANSPORT is sendgrid"`
+	SendGridWebhookPublicKey string `env:"SENDGRID_WEBHOOK_PUBLIC_KEY" desc:"SendGrid Event Webhook verification key (base64 ECDSA public key)"`
+
+	// Postmark Configuration (required if Transport is "postmark")
+	PostmarkServerToken     string `env:"POSTMARK_SERVER_TOKEN" desc:"Postmark server API token; required when MAIL_TRANSPORT is postmark"`
+	PostmarkMessageStream   string `env:"POSTMARK_MESSAGE_STREAM" desc:"Postmark message stream"`
+	PostmarkWebhookUsername string `env:"POSTMARK_WEBHOOK_USERNAME" desc:"Basic auth username configured on Postmark webhooks"`
+	PostmarkWebhookPassword string `env:"POSTMARK_WEBHOOK_PASSWORD" desc:"Basic auth password configured on Postmark webhooks"`
+
+	// Resend Configuration (required if Transport is "resend")
+	ResendAPIKey        string `env:"RESEND_API_KEY" desc:"Resend API key; required when MAIL_TRANSPORT is resend"`
+	ResendWebhookSecret string `env:"RESEND_WEBHOOK_SECRET" desc:"Resend webhook signing secret (whsec_...)"`
 
 	// Branding settings for email templates
-	AppName      string // Application name shown in emails (default: "App")
-	LogoURL      string // URL to application logo (optional)
-	PrimaryColor string // Primary brand color in hex (default: "#007bff")
-	SupportEmail string // Support contact email (optional)
-	SupportURL   string // Support website URL (optional)
-	CompanyName  string // Company name for footer (optional)
-	SocialLinks  string // JSON array of SocialLink objects (optional)
+	AppName      string `env:"MAIL_APP_NAME" desc:"Application name shown in emails"`              // Application name shown in emails (default: "App")
+	LogoURL      string `env:"MAIL_LOGO_URL" desc:"URL to the application logo"`                   // URL to application logo (optional)
+	PrimaryColor string `env:"MAIL_PRIMARY_COLOR" desc:"Primary brand color in hex"`               // Primary brand color in hex (default: "#007bff")
+	SupportEmail string `env:"MAIL_SUPPORT_EMAIL" desc:"Support contact email"`                    // Support contact email (optional)
+	SupportURL   string `env:"MAIL_SUPPORT_URL" desc:"Support website URL"`                        // Support website URL (optional)
+	CompanyName  string `env:"MAIL_COMPANY_NAME" desc:"Company name for the email footer"`         // Company name for footer (optional)
+	SocialLinks  string `env:"MAIL_SOCIAL_LINKS" desc:"JSON array of social links for the footer"` // JSON array of SocialLink objects (optional)
+
+	// TemplateDir overrides the built-in email templates per file (see NewDefaultEmailRenderer).
+	TemplateDir string `env:"MAIL_TEMPLATE_DIR" desc:"Directory whose templates override the built-in email templates"`
 
 	// BaseURL is the application root URL, required for generating action links (e.g. password reset).
-	BaseURL string
+	BaseURL string `env:"APP_BASE_URL" desc:"Application root URL used in email action links"`
 }
 
 // RateLimitConfig holds rate limiting configuration
 type RateLimitConfig struct {
-	Enabled     bool
-	PerIP       int
-	PerUser     int
-	ResetPeriod time.Duration
+	Enabled     bool          `env:"RATE_LIMIT_ENABLED" desc:"Enable rate limiting"`
+	PerIP       int           `env:"RATE_LIMIT_PER_IP" desc:"Requests allowed per IP per period"`
+	PerUser     int           `env:"RATE_LIMIT_PER_USER" desc:"Requests allowed per user per period"`
+	ResetPeriod time.Duration `env:"RATE_LIMIT_RESET_PERIOD" desc:"Rate limit window"`
 }
 
 // CORSConfig holds CORS configuration
 type CORSConfig struct {
-	AllowedOrigins   []string
-	AllowedMethods   []string
-	AllowedHeaders   []string
-	ExposedHeaders   []string
-	AllowCredentials bool
-	MaxAge           int
+	AllowedOrigins   []string `env:"CORS_ALLOWED_ORIGINS" desc:"Comma-separated allowed origins"`
+	AllowedMethods   []string `env:"CORS_ALLOWED_METHODS" desc:"Comma-separated allowed methods"`
+	AllowedHeaders   []string `env:"CORS_ALLOWED_HEADERS" desc:"Comma-separated allowed request headers"`
+	ExposedHeaders   []string `env:"CORS_EXPOSED_HEADERS" desc:"Comma-separated response headers exposed to the browser"`
+	AllowCredentials bool     `env:"CORS_ALLOW_CREDENTIALS" desc:"Allow cookies and credentials in cross-origin requests"`
+	MaxAge           int      `env:"CORS_MAX_AGE" desc:"Preflight cache duration in seconds"`
+	// DevMode mengaktifkan preset permisif untuk development (CORS_DEV_MODE=true):
+	// semua origin, method, dan header diizinkan. Ditolak oleh Validate di production.
+	DevMode bool `env:"CORS_DEV_MODE" desc:"Allow every origin, method and header (rejected in production)"`
 }
 
 // CSRFConfig holds CSRF configuration
 type CSRFConfig struct {
-	Enabled      bool
-	ExemptPaths  []string
-	TokenLength  int
-	CookieName   string
-	HeaderName   string
-	CookieMaxAge int
+	Enabled      bool     `env:"CSRF_ENABLED" desc:"Enable CSRF protection"`
+	ExemptPaths  []string `env:"CSRF_EXEMPT_PATHS" desc:"Comma-separated path prefixes exempt from CSRF checks"`
+	TokenLength  int      `env:"CSRF_TOKEN_LENGTH" desc:"CSRF token length in bytes"`
+	CookieName   string   `env:"CSRF_COOKIE_NAME" desc:"CSRF cookie name"`
+	HeaderName   string   `env:"CSRF_HEADER_NAME" desc:"Request header carrying the CSRF token"`
+	CookieMaxAge int      `env:"CSRF_COOKIE_MAX_AGE" desc:"CSRF cookie lifetime in seconds"`
+}
+
+// PasswordConfig holds password hashing and password policy configuration
+type PasswordConfig struct {
+	Algorithm         string `env:"PASSWORD_HASH_ALGORITHM" validate:"oneof=bcrypt|argon2id" desc:"Password hash algorithm (bcrypt or argon2id)"`
+	BcryptCost        int    `env:"PASSWORD_BCRYPT_COST" desc:"bcrypt cost factor (4-31)"`
+	Argon2Memory      uint32 `env:"PASSWORD_ARGON2_MEMORY" desc:"argon2id memory in KiB"`
+	Argon2Iterations  uint32 `env:"PASSWORD_ARGON2_ITERATIONS" desc:"argon2id iterations"`
+	Argon2Parallelism uint8  `env:"PASSWORD_ARGON2_PARALLELISM" desc:"argon2id parallelism"`
+
+	MinLength        int      `env:"PASSWORD_MIN_LENGTH" desc:"Minimum password length"`
+	MaxLength        int      `env:"PASSWORD_MAX_LENGTH" desc:"Maximum password length in bytes (0 disables the limit)"`
+	RequireUppercase bool     `env:"PASSWORD_REQUIRE_UPPERCASE" desc:"Require at least one uppercase letter"`
+	RequireLowercase bool     `env:"PASSWORD_REQUIRE_LOWERCASE" desc:"Require at least one lowercase letter"`
+	RequireDigit     bool     `env:"PASSWORD_REQUIRE_DIGIT" desc:"Require at least one digit"`
+	RequireSpecial   bool     `env:"PASSWORD_REQUIRE_SPECIAL" desc:"Require at least one special character"`
+	DenyList         []string `env:"PASSWORD_DENY_LIST" desc:"Comma-separated passwords that are always rejected"`
+	BreachCheck      bool     `env:"PASSWORD_BREACH_CHECK" desc:"Reject passwords found in the Pwned Passwords breach corpus"`
+}
+
+// RedisConfig holds Redis connection configuration (used by NewRedisClient)
+type RedisConfig struct {
+	// Addr adalah alamat host:port Redis. Kosong berarti Redis tidak dipakai.
+	Addr     string `env:"REDIS_ADDR" desc:"Redis address as host:port; empty disables Redis"`
+	Username string `env:"REDIS_USERNAME" desc:"Redis ACL username (Redis 6+)"`
+	Password string `env:"REDIS_PASSWORD" desc:"Redis password"`
+	DB       int    `env:"REDIS_DB" desc:"Redis logical database number"`
+	TLS      bool   `env:"REDIS_TLS" desc:"Connect to Redis over TLS"`
+
+	// Connection pool
+	PoolSize     int           `env:"REDIS_POOL_SIZE" desc:"Maximum open connections in the pool"`
+	DialTimeout  time.Duration `env:"REDIS_DIAL_TIMEOUT" desc:"Timeout for establishing a new connection"`
+	ReadTimeout  time.Duration `env:"REDIS_READ_TIMEOUT" desc:"Timeout for reading a command reply"`
+	WriteTimeout time.Duration `env:"REDIS_WRITE_TIMEOUT" desc:"Timeout for writing a command"`
+	IdleTimeout  time.Duration `env:"REDIS_IDLE_TIMEOUT" desc:"Close pooled connections idle longer than this; 0 keeps them open"`
 }
 
 // LoadConfig memuat konfigurasi aplikasi dari environment variables.
-// Menggabungkan konfigurasi dari semua bagian (Server, JWT, Database, Email, RateLimit, CORS, CSRF).
+// Menggabungkan konfigurasi dari semua bagian (Server, JWT, Database, Email, RateLimit, CORS, CSRF, Password, Redis).
+// File .env dan .env.local (jika ada) dimuat terlebih dahulu lewat LoadDotenv, tanpa menimpa
+// environment variable yang sudah di-set.
 //
 // Returns:
 //   - *Config: struktur konfigurasi lengkap aplikasi
-//   - error: error jika validasi konfigurasi gagal
+//   - error: error jika file .env tidak valid atau validasi konfigurasi gagal
 //
 // Example:
 //
@@ -146,42 +264,104 @@ type CSRFConfig struct {
 //	  log.Fatal(err)
 //	}
 func LoadConfig() (*Config, error) {
-	serverCfg, err := loadServerConfig()
+	if err := LoadDotenv(); err != nil {
+		return nil, err
+	}
+	return loadConfig(envConfigSource)
+}
+
+// configSource mengembalikan nilai konfigurasi berdasarkan nama environment variable
+// (misal "DB_WRITE_HOST"), string kosong jika tidak ada.
+type configSource func(key string) string
+
+// envConfigSource membaca konfigurasi langsung dari environment variables.
+var envConfigSource configSource = os.Getenv
+
+func (s configSource) get(key string) string {
+	return s(key)
+}
+
+func (s configSource) getOrDefault(key, defaultValue string) string {
+	if value := s(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// loadConfig memuat semua bagian konfigurasi dari src lalu menjalankan Validate.
+// Nilai berbentuk referensi secret (misal "vault://secret/jwt#private_key") diresolusi
+// melalui SecretResolver yang terdaftar.
+func loadConfig(src configSource) (*Config, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), secretResolveTimeout)
+	defer cancel()
+
+	secrets := newSecretConfigSource(ctx, src)
+	cfg, err := loadConfigSections(secrets.get)
+	// Error resolusi secret lebih informatif daripada error parsing nilai kosong
+	if secrets.err != nil {
+		return nil, secrets.err
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.source = src
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// loadConfigSections memuat setiap bagian konfigurasi dari src.
+func loadConfigSections(src configSource) (*Config, error) {
+	serverCfg, err := loadServerConfig(src)
 	if err != nil {
 		return nil, err
 	}
 
-	jwtCfg, err := loadJWTConfig()
+	jwtCfg, err := loadJWTConfig(src)
 	if err != nil {
 		return nil, err
 	}
 
-	dbCfg, err := loadDatabaseConfig()
+	dbCfg, err := loadDatabaseConfig(src)
 	if err != nil {
 		return nil, err
 	}
 
-	rateLimitCfg, err := loadRateLimitConfig()
+	rateLimitCfg, err := loadRateLimitConfig(src)
 	if err != nil {
 		return nil, err
 	}
 
-	corsCfg, err := loadCORSConfig()
+	corsCfg, err := loadCORSConfig(src)
 	if err != nil {
 		return nil, err
 	}
 
-	csrfCfg, err := loadCSRFConfig()
+	csrfCfg, err := loadCSRFConfig(src)
 	if err != nil {
 		return nil, err
 	}
 
-	emailCfg, err := loadEmailConfig()
+	emailCfg, err := loadEmailConfig(src)
 	if err != nil {
 		return nil, err
 	}
 
-	brancaCfg, err := loadBrancaConfig()
+	brancaCfg, err := loadBrancaConfig(src)
+	if err != nil {
+		return nil, err
+	}
+
+	passwordCfg, err := loadPasswordConfig(src)
+	if err != nil {
+		return nil, err
+	}
+
+	redisCfg, err := loadRedisConfig(src)
 	if err != nil {
 		return nil, err
 	}
@@ -195,66 +375,120 @@ func LoadConfig() (*Config, error) {
 		RateLimit: rateLimitCfg,
 		CORS:      corsCfg,
 		CSRF:      csrfCfg,
-	}
-
-	if err := cfg.Validate(); err != nil {
-		return nil, err
+		Password:  passwordCfg,
+		Redis:     redisCfg,
 	}
 
 	return cfg, nil
 }
 
 // loadServerConfig loads server configuration
-func loadServerConfig() (ServerConfig, error) {
-	readTimeout, err := ParseEnvDuration(GetEnvOrDefault("SERVER_READ_TIMEOUT", "30s"))
+func loadServerConfig(src configSource) (ServerConfig, error) {
+	readTimeout, err := ParseEnvDuration(src.getOrDefault("SERVER_READ_TIMEOUT", "30s"))
 	if err != nil {
 		return ServerConfig{}, fmt.Errorf("invalid SERVER_READ_TIMEOUT: %w", err)
 	}
 
-	writeTimeout, err := ParseEnvDuration(GetEnvOrDefault("SERVER_WRITE_TIMEOUT", "30s"))
+	writeTimeout, err := ParseEnvDuration(src.getOrDefault("SERVER_WRITE_TIMEOUT", "30s"))
 	if err != nil {
 		return ServerConfig{}, fmt.Errorf("invalid SERVER_WRITE_TIMEOUT: %w", err)
 	}
 
-	idleTimeout, err := ParseEnvDuration(GetEnvOrDefault("SERVER_IDLE_TIMEOUT", "120s"))
+	idleTimeout, err := ParseEnvDuration(src.getOrDefault("SERVER_IDLE_TIMEOUT", "120s"))
 	if err != nil {
 		return ServerConfig{}, fmt.Errorf("invalid SERVER_IDLE_TIMEOUT: %w", err)
 	}
 
-	shutdownTimeout, err := ParseEnvDuration(GetEnvOrDefault("SERVER_SHUTDOWN_TIMEOUT", "10s"))
+	shutdownTimeout, err := ParseEnvDuration(src.getOrDefault("SERVER_SHUTDOWN_TIMEOUT", "10s"))
 	if err != nil {
 		return ServerConfig{}, fmt.Errorf("invalid SERVER_SHUTDOWN_TIMEOUT: %w", err)
 	}
 
+	var autoTLSDomains []string
+	for _, domain := range strings.Split(src.get("SERVER_AUTOTLS_DOMAINS"), ",") {
+		if domain = strings.TrimSpace(domain); domain != "" {
+			autoTLSDomains = append(autoTLSDomains, domain)
+		}
+	}
+
 	return ServerConfig{
-		Port:            GetEnvOrDefault("SERVER_PORT", "8080"),
-		ReadTimeout:     readTimeout,
-		WriteTimeout:    writeTimeout,
-		IdleTimeout:     idleTimeout,
-		ShutdownTimeout: shutdownTimeout,
+		Env:              strings.ToLower(strings.TrimSpace(src.getOrDefault(configProfileKey, "development"))),
+		Port:             src.getOrDefault("SERVER_PORT", "8080"),
+		ReadTimeout:      readTimeout,
+		WriteTimeout:     writeTimeout,
+		IdleTimeout:      idleTimeout,
+		ShutdownTimeout:  shutdownTimeout,
+		TLSCertFile:      src.get("SERVER_TLS_CERT_FILE"),
+		TLSKeyFile:       src.get("SERVER_TLS_KEY_FILE"),
+		AutoTLSDomains:   autoTLSDomains,
+		AutoTLSCacheDir:  src.getOrDefault("SERVER_AUTOTLS_CACHE_DIR", defaultAutoTLSCacheDir),
+		AutoTLSEmail:     src.get("SERVER_AUTOTLS_EMAIL"),
+		HTTPRedirectAddr: src.get("SERVER_HTTP_REDIRECT_ADDR"),
+		H2C:              ParseEnvBool(src.get("SERVER_H2C")),
+		UnixSocket:       src.get("SERVER_UNIX_SOCKET"),
+		InternalPort:     src.get("INTERNAL_PORT"),
 	}, nil
 }
 
 // loadJWTConfig loads JWT configuration
-func loadJWTConfig() (JWTConfig, error) {
-	accessTokenExpiry, err := ParseEnvDuration(GetEnvOrDefault("JWT_ACCESS_TOKEN_EXPIRY", "15m"))
+func loadJWTConfig(src configSource) (JWTConfig, error) {
+	accessTokenExpiry, err := ParseEnvDuration(src.getOrDefault("JWT_ACCESS_TOKEN_EXPIRY", "15m"))
 	if err != nil {
 		return JWTConfig{}, fmt.Errorf("invalid JWT_ACCESS_TOKEN_EXPIRY: %w", err)
 	}
 
-	refreshTokenExpiry, err := ParseEnvDuration(GetEnvOrDefault("JWT_REFRESH_TOKEN_EXPIRY", "168h"))
+	refreshTokenExpiry, err := ParseEnvDuration(src.getOrDefault("JWT_REFRESH_TOKEN_EXPIRY", "168h"))
 	if err != nil {
 		return JWTConfig{}, fmt.Errorf("invalid JWT_REFRESH_TOKEN_EXPIRY: %w", err)
 	}
 
-	signingMethod := GetEnvOrDefault("JWT_SIGNING_METHOD", "HS256")
-	hmacSecret := GetEnv("JWT_SECRET")
-	privateKey := resolveKeyContent(GetEnv("JWT_PRIVATE_KEY"))
-	jwksURL := GetEnv("JWT_JWKS_URL")
+	rememberMeExpiry, err := ParseEnvDuration(src.getOrDefault("JWT_REMEMBER_ME_EXPIRY", "720h"))
+	if err != nil {
+		return JWTConfig{}, fmt.Errorf("invalid JWT_REMEMBER_ME_EXPIRY: %w", err)
+	}
+	if rememberMeExpiry < 0 {
+		return JWTConfig{}, fmt.Errorf("invalid JWT_REMEMBER_ME_EXPIRY: must not be negative")
+	}
+
+	sessionMaxLifetime, err := ParseEnvDuration(src.get("JWT_SESSION_MAX_LIFETIME"))
+	if err != nil {
+		return JWTConfig{}, fmt.Errorf("invalid JWT_SESSION_MAX_LIFETIME: %w", err)
+	}
+	if sessionMaxLifetime < 0 {
+		return JWTConfig{}, fmt.Errorf("invalid JWT_SESSION_MAX_LIFETIME: must not be negative")
+	}
+
+	signingMethod := src.getOrDefault("JWT_SIGNING_METHOD", "HS256")
+	hmacSecret := src.get("JWT_SECRET")
+	privateKey := resolveKeyContent(src.get("JWT_PRIVATE_KEY"))
+	jwksURL := src.get("JWT_JWKS_URL")
+
+	jwksCacheTTL, err := ParseEnvDuration(src.get("JWT_JWKS_CACHE_TTL"))
+	if err != nil {
+		return JWTConfig{}, fmt.Errorf("invalid JWT_JWKS_CACHE_TTL: %w", err)
+	}
+	if jwksCacheTTL < 0 {
+		return JWTConfig{}, fmt.Errorf("invalid JWT_JWKS_CACHE_TTL: must not be negative")
+	}
+
+	leeway, err := ParseEnvDuration(src.get("JWT_CLOCK_SKEW_LEEWAY"))
+	if err != nil {
+		return JWTConfig{}, fmt.Errorf("invalid JWT_CLOCK_SKEW_LEEWAY: %w", err)
+	}
+	if leeway < 0 {
+		return JWTConfig{}, fmt.Errorf("invalid JWT_CLOCK_SKEW_LEEWAY: must not be negative")
+	}
+
+	var audience []string
+	for _, aud := range strings.Split(src.get("JWT_AUDIENCE"), ",") {
+		if aud = strings.TrimSpace(aud); aud != "" {
+			audience = append(audience, aud)
+		}
+	}
 
 	// Parse Public Keys (JSON format: {"kid1": "pem1", "kid2": "pem2"})
 	publicKeys := make(map[string]string)
-	publicKeysStr := GetEnv("JWT_PUBLIC_KEYS")
+	publicKeysStr := src.get("JWT_PUBLIC_KEYS")
 	if publicKeysStr != "" {
 		if err := json.Unmarshal([]byte(publicKeysStr), &publicKeys); err != nil {
 			return JWTConfig{}, fmt.Errorf("invalid JWT_PUBLIC_KEYS format (expected JSON): %w", err)
@@ -268,11 +502,17 @@ func loadJWTConfig() (JWTConfig, error) {
 	return JWTConfig{
 		AccessTokenExpiry:  accessTokenExpiry,
 		RefreshTokenExpiry: refreshTokenExpiry,
+		RememberMeExpiry:   rememberMeExpiry,
+		SessionMaxLifetime: sessionMaxLifetime,
 		SigningMethod:      signingMethod,
 		HMACSecret:         hmacSecret,
 		PrivateKey:         privateKey,
 		PublicKeys:         publicKeys,
 		JWKSURL:            jwksURL,
+		JWKSCacheTTL:       jwksCacheTTL,
+		Issuer:             src.get("JWT_ISSUER"),
+		Audience:           audience,
+		ClockSkewLeeway:    leeway,
 	}, nil
 }
 
@@ -304,10 +544,10 @@ func resolveKeyContent(val string) string {
 }
 
 // loadDatabaseConfig loads database configuration
-func loadDatabaseConfig() (DatabaseConfig, error) {
-	driver := GetEnvOrDefault("DB_DRIVER", "postgres")
+func loadDatabaseConfig(src configSource) (DatabaseConfig, error) {
+	driver := src.getOrDefault("DB_DRIVER", "postgres")
 
-	readHostsStr := GetEnv("DB_READ_HOSTS")
+	readHostsStr := src.get("DB_READ_HOSTS")
 	readHosts := []string{}
 	if readHostsStr != "" {
 		readHosts = strings.Split(readHostsStr, ",")
@@ -316,104 +556,128 @@ func loadDatabaseConfig() (DatabaseConfig, error) {
 		}
 	}
 
-	port, err := ParseEnvInt(GetEnvOrDefault("DB_PORT", "5432"))
+	defaultPort := "5432"
+	if driver == "mysql" {
+		defaultPort = "3306"
+	}
+	port, err := ParseEnvInt(src.getOrDefault("DB_PORT", defaultPort))
 	if err != nil {
 		return DatabaseConfig{}, fmt.Errorf("invalid DB_PORT: %w", err)
 	}
 
-	maxConns, err := ParseEnvInt(GetEnvOrDefault("DB_MAX_CONNS", "25"))
+	maxConns, err := ParseEnvInt(src.getOrDefault("DB_MAX_CONNS", "25"))
 	if err != nil {
 		return DatabaseConfig{}, fmt.Errorf("invalid DB_MAX_CONNS: %w", err)
 	}
 
-	migrationPort, err := ParseEnvInt(GetEnvOrDefault("DB_MIGRATION_PORT", "0"))
+	migrationPort, err := ParseEnvInt(src.getOrDefault("DB_MIGRATION_PORT", "0"))
 	if err != nil {
 		return DatabaseConfig{}, fmt.Errorf("invalid DB_MIGRATION_PORT: %w", err)
 	}
 
 	return DatabaseConfig{
-		Driver:        driver,
-		WriteHost:     GetEnv("DB_WRITE_HOST"),
-		ReadHosts:     readHosts,
-		Port:          port,
-		Database:      GetEnv("DB_NAME"),
-		Username:      GetEnv("DB_USER"),
-		Password:      GetEnv("DB_PASSWORD"),
-		MaxConns:      maxConns,
-		SSLMode:       GetEnvOrDefault("DB_SSL_MODE", "disable"),
-		RuntimeParams: make(map[string]string),
-		QueryExecMode: "",
-		MigrationHost:     GetEnv("DB_MIGRATION_HOST"),
-		MigrationPort:     migrationPort,
-		MigrationUsername: GetEnv("DB_MIGRATION_USER"),
-		MigrationPassword: GetEnv("DB_MIGRATION_PASSWORD"),
+		Driver:                       driver,
+		WriteHost:                    src.get("DB_WRITE_HOST"),
+		ReadHosts:                    readHosts,
+		Port:                         port,
+		Database:                     src.get("DB_NAME"),
+		Username:                     src.get("DB_USER"),
+		Password:                     src.get("DB_PASSWORD"),
+		MaxConns:                     maxConns,
+		SSLMode:                      src.getOrDefault("DB_SSL_MODE", "disable"),
+		RuntimeParams:                make(map[string]string),
+		QueryExecMode:                "",
+		StatementTimeoutFromDeadline: ParseEnvBool(src.getOrDefault("DB_STATEMENT_TIMEOUT_FROM_DEADLINE", "false")),
+		MigrationHost:                src.get("DB_MIGRATION_HOST"),
+		MigrationPort:                migrationPort,
+		MigrationUsername:            src.get("DB_MIGRATION_USER"),
+		MigrationPassword:            src.get("DB_MIGRATION_PASSWORD"),
 	}, nil
 }
 
 // loadEmailConfig loads email configuration
-func loadEmailConfig() (EmailConfig, error) {
-	smtpPort, err := ParseEnvInt(GetEnvOrDefault("MAIL_SMTP_PORT", "587"))
+func loadEmailConfig(src configSource) (EmailConfig, error) {
+	smtpPort, err := ParseEnvInt(src.getOrDefault("MAIL_SMTP_PORT", "587"))
 	if err != nil {
 		return EmailConfig{}, fmt.Errorf("invalid MAIL_SMTP_PORT: %w", err)
 	}
 
+	captureLimit, err := ParseEnvInt(src.getOrDefault("MAIL_CAPTURE_LIMIT", "50"))
+	if err != nil {
+		return EmailConfig{}, fmt.Errorf("invalid MAIL_CAPTURE_LIMIT: %w", err)
+	}
+
 	// SES Config Loading with Fallbacks
-	sesRegion := GetEnv("AWS_REGION")
+	sesRegion := src.get("AWS_REGION")
 	if sesRegion == "" {
-		sesRegion = GetEnv("SES_REGION")
+		sesRegion = src.get("SES_REGION")
 	}
 
-	sesAccessKey := GetEnv("AWS_ACCESS_KEY_ID")
+	sesAccessKey := src.get("AWS_ACCESS_KEY_ID")
 	if sesAccessKey == "" {
-		sesAccessKey = GetEnv("SES_ACCESS_KEY_ID")
+		sesAccessKey = src.get("SES_ACCESS_KEY_ID")
 	}
 
-	sesSecretKey := GetEnv("AWS_SECRET_ACCESS_KEY")
+	sesSecretKey := src.get("AWS_SECRET_ACCESS_KEY")
 	if sesSecretKey == "" {
-		sesSecretKey = GetEnv("SES_SECRET_ACCESS_KEY")
+		sesSecretKey = src.get("SES_SECRET_ACCESS_KEY")
 	}
 
 	return EmailConfig{
-		From:                GetEnv("MAIL_FROM"),
-		Transport:           GetEnvOrDefault("MAIL_TRANSPORT", "null"),
-		SMTPHost:            GetEnv("MAIL_SMTP_HOST"),
-		SMTPPort:            smtpPort,
-		SMTPUsername:        GetEnv("MAIL_SMTP_USERNAME"),
-		SMTPPassword:        GetEnv("MAIL_SMTP_PASSWORD"),
-		SESRegion:           sesRegion,
-		SESAccessKeyID:      sesAccessKey,
-		SESSecretAccessKey:  sesSecretKey,
-		SESConfigurationSet: GetEnv("SES_CONFIGURATION_SET"),
-		AppName:             GetEnvOrDefault("MAIL_APP_NAME", "App"),
-		LogoURL:             GetEnv("MAIL_LOGO_URL"),
-		PrimaryColor:        GetEnvOrDefault("MAIL_PRIMARY_COLOR", "#007bff"),
-		SupportEmail:        GetEnv("MAIL_SUPPORT_EMAIL"),
-		SupportURL:          GetEnv("MAIL_SUPPORT_URL"),
-		CompanyName:         GetEnv("MAIL_COMPANY_NAME"),
-		SocialLinks:         GetEnv("MAIL_SOCIAL_LINKS"),
-		BaseURL:             GetEnv("APP_BASE_URL"),
+		From:                     src.get("MAIL_FROM"),
+		Transport:                src.getOrDefault("MAIL_TRANSPORT", "null"),
+		CaptureLimit:             captureLimit,
+		SMTPHost:                 src.get("MAIL_SMTP_HOST"),
+		SMTPPort:                 smtpPort,
+		SMTPUsername:             src.get("MAIL_SMTP_USERNAME"),
+		SMTPPassword:             src.get("MAIL_SMTP_PASSWORD"),
+		SESRegion:                sesRegion,
+		SESAccessKeyID:           sesAccessKey,
+		SESSecretAccessKey:       sesSecretKey,
+		SESConfigurationSet:      src.get("SES_CONFIGURATION_SET"),
+		MailgunDomain:            src.get("MAILGUN_DOMAIN"),
+		MailgunAPIKey:            src.get("MAILGUN_API_KEY"),
+		MailgunAPIBase:           src.getOrDefault("MAILGUN_API_BASE", "https://api.mailgun.net"),
+		MailgunWebhookSigningKey: src.get("MAILGUN_WEBHOOK_SIGNING_KEY"),
+		SendGridAPIKey:           src.get("SENDGRID_API_KEY"),
+		SendGridWebhookPublicKey: src.get("SENDGRID_WEBHOOK_PUBLIC_KEY"),
+		PostmarkServerToken:      src.get("POSTMARK_SERVER_TOKEN"),
+		PostmarkMessageStream:    src.getOrDefault("POSTMARK_MESSAGE_STREAM", "outbound"),
+		PostmarkWebhookUsername:  src.get("POSTMARK_WEBHOOK_USERNAME"),
+		PostmarkWebhookPassword:  src.get("POSTMARK_WEBHOOK_PASSWORD"),
+		ResendAPIKey:             src.get("RESEND_API_KEY"),
+		ResendWebhookSecret:      src.get("RESEND_WEBHOOK_SECRET"),
+		AppName:                  src.getOrDefault("MAIL_APP_NAME", "App"),
+		LogoURL:                  src.get("MAIL_LOGO_URL"),
+		PrimaryColor:             src.getOrDefault("MAIL_PRIMARY_COLOR", "#007bff"),
+		SupportEmail:             src.get("MAIL_SUPPORT_EMAIL"),
+		SupportURL:               src.get("MAIL_SUPPORT_URL"),
+		CompanyName:              src.get("MAIL_COMPANY_NAME"),
+		SocialLinks:              src.get("MAIL_SOCIAL_LINKS"),
+		TemplateDir:              src.get("MAIL_TEMPLATE_DIR"),
+		BaseURL:                  src.get("APP_BASE_URL"),
 	}, nil
 }
 
 // loadRateLimitConfig loads rate limiting configuration
-func loadRateLimitConfig() (RateLimitConfig, error) {
-	perIP, err := ParseEnvInt(GetEnvOrDefault("RATE_LIMIT_PER_IP", "100"))
+func loadRateLimitConfig(src configSource) (RateLimitConfig, error) {
+	perIP, err := ParseEnvInt(src.getOrDefault("RATE_LIMIT_PER_IP", "100"))
 	if err != nil {
 		return RateLimitConfig{}, fmt.Errorf("invalid RATE_LIMIT_PER_IP: %w", err)
 	}
 
-	perUser, err := ParseEnvInt(GetEnvOrDefault("RATE_LIMIT_PER_USER", "200"))
+	perUser, err := ParseEnvInt(src.getOrDefault("RATE_LIMIT_PER_USER", "200"))
 	if err != nil {
 		return RateLimitConfig{}, fmt.Errorf("invalid RATE_LIMIT_PER_USER: %w", err)
 	}
 
-	resetPeriod, err := ParseEnvDuration(GetEnvOrDefault("RATE_LIMIT_RESET_PERIOD", "1h"))
+	resetPeriod, err := ParseEnvDuration(src.getOrDefault("RATE_LIMIT_RESET_PERIOD", "1h"))
 	if err != nil {
 		return RateLimitConfig{}, fmt.Errorf("invalid RATE_LIMIT_RESET_PERIOD: %w", err)
 	}
 
 	return RateLimitConfig{
-		Enabled:     ParseEnvBool(GetEnvOrDefault("RATE_LIMIT_ENABLED", "true")),
+		Enabled:     ParseEnvBool(src.getOrDefault("RATE_LIMIT_ENABLED", "true")),
 		PerIP:       perIP,
 		PerUser:     perUser,
 		ResetPeriod: resetPeriod,
@@ -421,26 +685,43 @@ func loadRateLimitConfig() (RateLimitConfig, error) {
 }
 
 // loadCORSConfig loads CORS configuration
-func loadCORSConfig() (CORSConfig, error) {
-	originsStr := GetEnvOrDefault("CORS_ALLOWED_ORIGINS", "http://localhost:3000")
-	origins := strings.Split(originsStr, ",")
-	for i := range origins {
-		origins[i] = strings.TrimSpace(origins[i])
+func loadCORSConfig(src configSource) (CORSConfig, error) {
+	maxAge, err := ParseEnvInt(src.getOrDefault("CORS_MAX_AGE", "3600"))
+	if err != nil {
+		return CORSConfig{}, fmt.Errorf("invalid CORS_MAX_AGE: %w", err)
+	}
+
+	if ParseEnvBool(src.get("CORS_DEV_MODE")) {
+		return corsDevPreset(maxAge), nil
 	}
 
-	methodsStr := GetEnvOrDefault("CORS_ALLOWED_METHODS", "GET,POST,PUT,DELETE,PATCH,OPTIONS")
+	// Default localhost hanya untuk development; production tanpa CORS_ALLOWED_ORIGINS
+	// tidak mengizinkan origin lain sama sekali.
+	defaultOrigins := "http://localhost:3000"
+	if isProductionEnv(src.get(configProfileKey)) {
+		defaultOrigins = ""
+	}
+	originsStr := src.getOrDefault("CORS_ALLOWED_ORIGINS", defaultOrigins)
+	origins := []string{}
+	for _, origin := range strings.Split(originsStr, ",") {
+		if trimmed := strings.TrimSpace(origin); trimmed != "" {
+			origins = append(origins, trimmed)
+		}
+	}
+
+	methodsStr := src.getOrDefault("CORS_ALLOWED_METHODS", "GET,POST,PUT,DELETE,PATCH,OPTIONS")
 	methods := strings.Split(methodsStr, ",")
 	for i := range methods {
 		methods[i] = strings.TrimSpace(methods[i])
 	}
 
-	headersStr := GetEnvOrDefault("CORS_ALLOWED_HEADERS", "Content-Type,Authorization,X-CSRF-Token")
+	headersStr := src.getOrDefault("CORS_ALLOWED_HEADERS", "Content-Type,Authorization,X-CSRF-Token")
 	headers := strings.Split(headersStr, ",")
 	for i := range headers {
 		headers[i] = strings.TrimSpace(headers[i])
 	}
 
-	exposedHeadersStr := GetEnvOrDefault("CORS_EXPOSED_HEADERS", "")
+	exposedHeadersStr := src.getOrDefault("CORS_EXPOSED_HEADERS", "")
 	exposedHeaders := []string{}
 	if exposedHeadersStr != "" {
 		parts := strings.Split(exposedHeadersStr, ",")
@@ -452,24 +733,19 @@ func loadCORSConfig() (CORSConfig, error) {
 		}
 	}
 
-	maxAge, err := ParseEnvInt(GetEnvOrDefault("CORS_MAX_AGE", "3600"))
-	if err != nil {
-		return CORSConfig{}, fmt.Errorf("invalid CORS_MAX_AGE: %w", err)
-	}
-
 	return CORSConfig{
 		AllowedOrigins:   origins,
 		AllowedMethods:   methods,
 		AllowedHeaders:   headers,
 		ExposedHeaders:   exposedHeaders,
-		AllowCredentials: ParseEnvBool(GetEnvOrDefault("CORS_ALLOW_CREDENTIALS", "true")),
+		AllowCredentials: ParseEnvBool(src.getOrDefault("CORS_ALLOW_CREDENTIALS", "true")),
 		MaxAge:           maxAge,
 	}, nil
 }
 
 // loadCSRFConfig loads CSRF configuration
-func loadCSRFConfig() (CSRFConfig, error) {
-	exemptPathsStr := GetEnv("CSRF_EXEMPT_PATHS")
+func loadCSRFConfig(src configSource) (CSRFConfig, error) {
+	exemptPathsStr := src.get("CSRF_EXEMPT_PATHS")
 	exemptPaths := []string{}
 	if exemptPathsStr != "" {
 		exemptPaths = strings.Split(exemptPathsStr, ",")
@@ -478,45 +754,155 @@ func loadCSRFConfig() (CSRFConfig, error) {
 		}
 	}
 
-	tokenLength, err := ParseEnvInt(GetEnvOrDefault("CSRF_TOKEN_LENGTH", "32"))
+	tokenLength, err := ParseEnvInt(src.getOrDefault("CSRF_TOKEN_LENGTH", "32"))
 	if err != nil {
 		return CSRFConfig{}, fmt.Errorf("invalid CSRF_TOKEN_LENGTH: %w", err)
 	}
 
-	cookieMaxAge, err := ParseEnvInt(GetEnvOrDefault("CSRF_COOKIE_MAX_AGE", "43200")) // Default 12 jam
+	cookieMaxAge, err := ParseEnvInt(src.getOrDefault("CSRF_COOKIE_MAX_AGE", "43200")) // Default 12 jam
 	if err != nil {
 		return CSRFConfig{}, fmt.Errorf("invalid CSRF_COOKIE_MAX_AGE: %w", err)
 	}
 
 	return CSRFConfig{
-		Enabled:      ParseEnvBool(GetEnvOrDefault("CSRF_ENABLED", "true")),
+		Enabled:      ParseEnvBool(src.getOrDefault("CSRF_ENABLED", "true")),
 		ExemptPaths:  exemptPaths,
 		TokenLength:  tokenLength,
-		CookieName:   GetEnvOrDefault("CSRF_COOKIE_NAME", "csrf_token"),
-		HeaderName:   GetEnvOrDefault("CSRF_HEADER_NAME", "X-CSRF-Token"),
+		CookieName:   src.getOrDefault("CSRF_COOKIE_NAME", "csrf_token"),
+		HeaderName:   src.getOrDefault("CSRF_HEADER_NAME", "X-CSRF-Token"),
 		CookieMaxAge: cookieMaxAge,
 	}, nil
 }
 
 // loadBrancaConfig loads Branca token configuration from environment variables.
-func loadBrancaConfig() (BrancaConfig, error) {
-	accessExpiry, err := ParseEnvDuration(GetEnvOrDefault("BRANCA_ACCESS_TOKEN_EXPIRY", "15m"))
+func loadBrancaConfig(src configSource) (BrancaConfig, error) {
+	accessExpiry, err := ParseEnvDuration(src.getOrDefault("BRANCA_ACCESS_TOKEN_EXPIRY", "15m"))
 	if err != nil {
 		return BrancaConfig{}, fmt.Errorf("invalid BRANCA_ACCESS_TOKEN_EXPIRY: %w", err)
 	}
 
-	refreshExpiry, err := ParseEnvDuration(GetEnvOrDefault("BRANCA_REFRESH_TOKEN_EXPIRY", "168h"))
+	refreshExpiry, err := ParseEnvDuration(src.getOrDefault("BRANCA_REFRESH_TOKEN_EXPIRY", "168h"))
 	if err != nil {
 		return BrancaConfig{}, fmt.Errorf("invalid BRANCA_REFRESH_TOKEN_EXPIRY: %w", err)
 	}
 
 	return BrancaConfig{
-		Key:                GetEnv("BRANCA_KEY"),
+		Key:                src.get("BRANCA_KEY"),
 		AccessTokenExpiry:  accessExpiry,
 		RefreshTokenExpiry: refreshExpiry,
 	}, nil
 }
 
+// loadPasswordConfig loads password hashing and password policy configuration
+func loadPasswordConfig(src configSource) (PasswordConfig, error) {
+	algorithm := strings.ToLower(src.getOrDefault("PASSWORD_HASH_ALGORITHM", PasswordAlgorithmBcrypt))
+	if algorithm != PasswordAlgorithmBcrypt && algorithm != PasswordAlgorithmArgon2id {
+		return PasswordConfig{}, fmt.Errorf("invalid PASSWORD_HASH_ALGORITHM: %q (must be bcrypt or argon2id)", algorithm)
+	}
+
+	bcryptCost, err := ParseEnvInt(src.getOrDefault("PASSWORD_BCRYPT_COST", fmt.Sprint(BcryptCost)))
+	if err != nil {
+		return PasswordConfig{}, fmt.Errorf("invalid PASSWORD_BCRYPT_COST: %w", err)
+	}
+
+	defaults := DefaultArgon2idParams()
+	memory, err := ParseEnvInt(src.getOrDefault("PASSWORD_ARGON2_MEMORY", fmt.Sprint(defaults.Memory)))
+	if err != nil || memory <= 0 {
+		return PasswordConfig{}, fmt.Errorf("invalid PASSWORD_ARGON2_MEMORY: %q", src.get("PASSWORD_ARGON2_MEMORY"))
+	}
+
+	iterations, err := ParseEnvInt(src.getOrDefault("PASSWORD_ARGON2_ITERATIONS", fmt.Sprint(defaults.Iterations)))
+	if err != nil || iterations <= 0 {
+		return PasswordConfig{}, fmt.Errorf("invalid PASSWORD_ARGON2_ITERATIONS: %q", src.get("PASSWORD_ARGON2_ITERATIONS"))
+	}
+
+	parallelism, err := ParseEnvInt(src.getOrDefault("PASSWORD_ARGON2_PARALLELISM", fmt.Sprint(defaults.Parallelism)))
+	if err != nil || parallelism <= 0 || parallelism > 255 {
+		return PasswordConfig{}, fmt.Errorf("invalid PASSWORD_ARGON2_PARALLELISM: %q", src.get("PASSWORD_ARGON2_PARALLELISM"))
+	}
+
+	minLength, err := ParseEnvInt(src.getOrDefault("PASSWORD_MIN_LENGTH", fmt.Sprint(MinPasswordLength)))
+	if err != nil || minLength < 1 {
+		return PasswordConfig{}, fmt.Errorf("invalid PASSWORD_MIN_LENGTH: %q", src.get("PASSWORD_MIN_LENGTH"))
+	}
+
+	maxLength, err := ParseEnvInt(src.getOrDefault("PASSWORD_MAX_LENGTH", fmt.Sprint(MaxPasswordLength)))
+	if err != nil || maxLength < 0 || (maxLength > 0 && maxLength < minLength) {
+		return PasswordConfig{}, fmt.Errorf("invalid PASSWORD_MAX_LENGTH: %q (must be 0 or at least PASSWORD_MIN_LENGTH)", src.get("PASSWORD_MAX_LENGTH"))
+	}
+
+	denyList := []string{}
+	if denyListStr := src.get("PASSWORD_DENY_LIST"); denyListStr != "" {
+		for _, password := range strings.Split(denyListStr, ",") {
+			if password = strings.TrimSpace(password); password != "" {
+				denyList = append(denyList, password)
+			}
+		}
+	}
+
+	return PasswordConfig{
+		Algorithm:         algorithm,
+		BcryptCost:        bcryptCost,
+		Argon2Memory:      uint32(memory),
+		Argon2Iterations:  uint32(iterations),
+		Argon2Parallelism: uint8(parallelism),
+		MinLength:         minLength,
+		MaxLength:         maxLength,
+		RequireUppercase:  ParseEnvBool(src.getOrDefault("PASSWORD_REQUIRE_UPPERCASE", "true")),
+		RequireLowercase:  ParseEnvBool(src.getOrDefault("PASSWORD_REQUIRE_LOWERCASE", "true")),
+		RequireDigit:      ParseEnvBool(src.getOrDefault("PASSWORD_REQUIRE_DIGIT", "true")),
+		RequireSpecial:    ParseEnvBool(src.getOrDefault("PASSWORD_REQUIRE_SPECIAL", "true")),
+		DenyList:          denyList,
+		BreachCheck:       ParseEnvBool(src.getOrDefault("PASSWORD_BREACH_CHECK", "false")),
+	}, nil
+}
+
+// loadRedisConfig loads Redis connection configuration
+func loadRedisConfig(src configSource) (RedisConfig, error) {
+	db, err := ParseEnvInt(src.getOrDefault("REDIS_DB", "0"))
+	if err != nil || db < 0 {
+		return RedisConfig{}, fmt.Errorf("invalid REDIS_DB: %q", src.get("REDIS_DB"))
+	}
+
+	poolSize, err := ParseEnvInt(src.getOrDefault("REDIS_POOL_SIZE", "10"))
+	if err != nil || poolSize < 1 {
+		return RedisConfig{}, fmt.Errorf("invalid REDIS_POOL_SIZE: %q (must be at least 1)", src.get("REDIS_POOL_SIZE"))
+	}
+
+	dialTimeout, err := ParseEnvDuration(src.getOrDefault("REDIS_DIAL_TIMEOUT", "5s"))
+	if err != nil {
+		return RedisConfig{}, fmt.Errorf("invalid REDIS_DIAL_TIMEOUT: %w", err)
+	}
+
+	readTimeout, err := ParseEnvDuration(src.getOrDefault("REDIS_READ_TIMEOUT", "3s"))
+	if err != nil {
+		return RedisConfig{}, fmt.Errorf("invalid REDIS_READ_TIMEOUT: %w", err)
+	}
+
+	writeTimeout, err := ParseEnvDuration(src.getOrDefault("REDIS_WRITE_TIMEOUT", "3s"))
+	if err != nil {
+		return RedisConfig{}, fmt.Errorf("invalid REDIS_WRITE_TIMEOUT: %w", err)
+	}
+
+	idleTimeout, err := ParseEnvDuration(src.getOrDefault("REDIS_IDLE_TIMEOUT", "5m"))
+	if err != nil {
+		return RedisConfig{}, fmt.Errorf("invalid REDIS_IDLE_TIMEOUT: %w", err)
+	}
+
+	return RedisConfig{
+		Addr:         src.get("REDIS_ADDR"),
+		Username:     src.get("REDIS_USERNAME"),
+		Password:     src.get("REDIS_PASSWORD"),
+		DB:           db,
+		TLS:          ParseEnvBool(src.get("REDIS_TLS")),
+		PoolSize:     poolSize,
+		DialTimeout:  dialTimeout,
+		ReadTimeout:  readTimeout,
+		WriteTimeout: writeTimeout,
+		IdleTimeout:  idleTimeout,
+	}, nil
+}
+
 // Validate memvalidasi konfigurasi aplikasi untuk memastikan nilai required sudah ada.
 // Jika BRANCA_KEY di-set, validasi Branca dijalankan dan JWT_SECRET tidak wajib.
 // Jika BRANCA_KEY kosong, validasi JWT dijalankan (JWT_SECRET atau JWT_PRIVATE_KEY wajib).
@@ -538,22 +924,29 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	if c.Server.InternalPort != "" && serverAddr(c.Server.InternalPort) == serverAddr(c.Server.Port) {
+		return fmt.Errorf("INTERNAL_PORT must differ from SERVER_PORT")
+	}
+
 	if c.Database.Database == "" {
 		return fmt.Errorf("DB_NAME is required")
 	}
 
-	// Validation specific to Postgres
-	if c.Database.Driver == "postgres" {
+	// Validation specific to Postgres and MySQL
+	if c.Database.Driver == "postgres" || c.Database.Driver == "mysql" {
 		if c.Database.WriteHost == "" {
-			return fmt.Errorf("DB_WRITE_HOST is required for postgres")
+			return fmt.Errorf("DB_WRITE_HOST is required for %s", c.Database.Driver)
 		}
 		if c.Database.Username == "" {
-			return fmt.Errorf("DB_USER is required for postgres")
+			return fmt.Errorf("DB_USER is required for %s", c.Database.Driver)
 		}
 	}
 
 	// Email Validation
-	if c.Email.Transport != "null" && c.Email.Transport != "" {
+	if c.Email.Transport == "log" && c.IsProduction() {
+		return fmt.Errorf("MAIL_TRANSPORT=log must not be used when APP_ENV=%s; emails would only be captured in memory", c.Server.Env)
+	}
+	if c.Email.Transport != "null" && c.Email.Transport != "log" && c.Email.Transport != "" {
 		if c.Email.From == "" {
 			return fmt.Errorf("MAIL_FROM is required when mail transport is enabled")
 		}
@@ -567,6 +960,29 @@ func (c *Config) Validate() error {
 				return fmt.Errorf("AWS_REGION (or SES_REGION) is required for SES transport")
 			}
 		}
+		switch {
+		case c.Email.Transport == "mailgun" && (c.Email.MailgunDomain == "" || c.Email.MailgunAPIKey == ""):
+			return fmt.Errorf("MAILGUN_DOMAIN and MAILGUN_API_KEY are required for Mailgun transport")
+		case c.Email.Transport == "sendgrid" && c.Email.SendGridAPIKey == "":
+			return fmt.Errorf("SENDGRID_API_KEY is required for SendGrid transport")
+		case c.Email.Transport == "postmark" && c.Email.PostmarkServerToken == "":
+			return fmt.Errorf("POSTMARK_SERVER_TOKEN is required for Postmark transport")
+		case c.Email.Transport == "resend" && c.Email.ResendAPIKey == "":
+			return fmt.Errorf("RESEND_API_KEY is required for Resend transport")
+		}
+	}
+
+	if err := c.Server.validateTLS(); err != nil {
+		return err
+	}
+	if err := c.validateCORS(); err != nil {
+		return err
+	}
+	if err := c.validateSections(); err != nil {
+		return err
+	}
+	for _, warning := range c.Warnings() {
+		slog.Warn("config warning", "warning", warning)
 	}
 
 	return nil
@@ -0,0 +1,65 @@
+package dim
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+// ConfigSchemaCommand menulis JSON Schema semua environment variable konfigurasi,
+// termasuk section yang didaftarkan aplikasi lewat Config.RegisterSection.
+type ConfigSchemaCommand struct {
+	env    string
+	output string
+}
+
+func (c *ConfigSchemaCommand) Name() string {
+	return "config:schema"
+}
+
+func (c *ConfigSchemaCommand) Description() string {
+	return "Export a JSON Schema of all configuration environment variables"
+}
+
+func (c *ConfigSchemaCommand) DefineFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.env, "env", "", "Environment used to compute defaults (default: APP_ENV of the loaded config)")
+	fs.StringVar(&c.output, "o", "", "Write the schema to this file instead of stdout")
+}
+
+func (c *ConfigSchemaCommand) Execute(ctx *CommandContext) error {
+	env := c.env
+	if env == "" && ctx.Config != nil {
+		env = ctx.Config.Server.Env
+	}
+
+	schema, err := GenerateConfigSchema(ctx.Config, env)
+	if err != nil {
+		return err
+	}
+
+	var out io.Writer = os.Stdout
+	if ctx.Out != nil {
+		out = ctx.Out
+	}
+	if c.output != "" {
+		f, err := os.Create(c.output)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", c.output, err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(schema); err != nil {
+		return fmt.Errorf("failed to write config schema: %w", err)
+	}
+
+	if c.output != "" && ctx.Err != nil {
+		fmt.Fprintf(ctx.Err, "✓ Config schema written to %s (%d variables)\n", c.output, len(schema.Properties))
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package dim
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfigSchemaCommand_Stdout(t *testing.T) {
+	var out bytes.Buffer
+	console := NewConsole(nil, nil, &Config{Server: ServerConfig{Env: "production"}})
+	console.SetOutput(&out, &bytes.Buffer{})
+	console.Register(&ConfigSchemaCommand{})
+
+	if err := console.Run([]string{"config:schema"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var schema ConfigSchema
+	if err := json.Unmarshal(out.Bytes(), &schema); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, out.String())
+	}
+	// Default mengikuti APP_ENV config yang dimuat
+	if schema.Title != "Configuration (production)" || schema.Properties["DB_PORT"] == nil {
+		t.Errorf("schema = %s", out.String())
+	}
+	if !strings.Contains(out.String(), `"$schema": "https://json-schema.org/draft/2020-12/schema"`) {
+		t.Errorf("missing $schema: %s", out.String()[:200])
+	}
+}
+
+func TestConfigSchemaCommand_OutputFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.schema.json")
+	var out, errOut bytes.Buffer
+	console := NewConsole(nil, nil, nil)
+	console.SetOutput(&out, &errOut)
+	console.Register(&ConfigSchemaCommand{})
+
+	if err := console.Run([]string{"config:schema", "-env", "staging", "-o", path}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var schema ConfigSchema
+	if err := json.Unmarshal(data, &schema); err != nil || schema.Title != "Configuration (staging)" {
+		t.Errorf("schema title = %q, err = %v", schema.Title, err)
+	}
+	if out.Len() != 0 || !strings.Contains(errOut.String(), path) {
+		t.Errorf("stdout = %q, stderr = %q", out.String(), errOut.String())
+	}
+}
@@ -0,0 +1,115 @@
+package dim
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// corsMaxAllowedHeaders adalah jumlah allowed header di atas batas wajar yang memicu warning.
+const corsMaxAllowedHeaders = 15
+
+// IsProduction mengembalikan true jika APP_ENV bernilai "production" atau "prod".
+func (c *Config) IsProduction() bool {
+	return isProductionEnv(c.Server.Env)
+}
+
+func isProductionEnv(env string) bool {
+	env = strings.ToLower(strings.TrimSpace(env))
+	return env == "production" || env == "prod"
+}
+
+// corsDevPreset mengembalikan konfigurasi CORS permisif untuk development (CORS_DEV_MODE=true).
+// Origin request selalu dipantulkan dan header yang diminta preflight selalu diizinkan,
+// sehingga frontend di port mana pun dapat memanggil API dengan cookie/credentials.
+func corsDevPreset(maxAge int) CORSConfig {
+	return CORSConfig{
+		AllowedOrigins:   []string{"*"},
+		AllowedMethods:   []string{"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowedHeaders:   []string{"*"},
+		ExposedHeaders:   []string{},
+		AllowCredentials: true,
+		MaxAge:           maxAge,
+		DevMode:          true,
+	}
+}
+
+// validateCORS menerapkan aturan CORS yang ketat di production. Di environment lain,
+// konfigurasi apa pun diterima.
+func (c *Config) validateCORS() error {
+	if !c.IsProduction() {
+		return nil
+	}
+
+	cors := c.CORS
+	if cors.DevMode {
+		return fmt.Errorf("CORS_DEV_MODE must not be enabled when APP_ENV=%s; remove it and set CORS_ALLOWED_ORIGINS to your frontend origins", c.Server.Env)
+	}
+
+	for _, origin := range cors.AllowedOrigins {
+		if origin == "*" {
+			if cors.AllowCredentials {
+				return fmt.Errorf("CORS_ALLOWED_ORIGINS=* is not allowed with CORS_ALLOW_CREDENTIALS=true in production; list exact origins (e.g. CORS_ALLOWED_ORIGINS=https://app.example.com) or set CORS_ALLOW_CREDENTIALS=false")
+			}
+			continue
+		}
+
+		u, err := url.Parse(origin)
+		if err != nil || u.Host == "" || (u.Path != "" && u.Path != "/") || u.RawQuery != "" || u.Fragment != "" {
+			return fmt.Errorf("CORS_ALLOWED_ORIGINS contains invalid origin %q; use scheme://host[:port] without path, e.g. https://app.example.com", origin)
+		}
+		if u.Scheme != "https" {
+			return fmt.Errorf("CORS_ALLOWED_ORIGINS must use https in production, got %q; use %q", origin, "https://"+u.Host)
+		}
+		if u.Path == "/" {
+			return fmt.Errorf("CORS_ALLOWED_ORIGINS origin %q must not end with \"/\" (browsers send %q); use %q", origin, "https://"+u.Host, "https://"+u.Host)
+		}
+	}
+
+	return nil
+}
+
+// Warnings mengembalikan peringatan konfigurasi yang tidak menggagalkan validasi tetapi
+// sebaiknya diperbaiki, misal allowlist header CORS yang terlalu luas di production.
+// Validate mencatat setiap peringatan dengan level warning.
+//
+// Returns:
+//   - []string: daftar peringatan, kosong jika tidak ada
+func (c *Config) Warnings() []string {
+	if !c.IsProduction() {
+		return nil
+	}
+
+	var warnings []string
+	cors := c.CORS
+
+	for _, origin := range cors.AllowedOrigins {
+		if origin == "*" {
+			warnings = append(warnings, "CORS_ALLOWED_ORIGINS=* allows any website to call the API; list exact origins unless the API is intentionally public")
+			break
+		}
+	}
+	if containsHeader(cors.AllowedHeaders, "*") {
+		warnings = append(warnings, "CORS_ALLOWED_HEADERS=* allows any request header; list only the headers the frontend sends (e.g. Content-Type,Authorization,X-CSRF-Token)")
+	} else if len(cors.AllowedHeaders) > corsMaxAllowedHeaders {
+		warnings = append(warnings, fmt.Sprintf("CORS_ALLOWED_HEADERS lists %d headers; consider trimming it to the headers the frontend actually sends", len(cors.AllowedHeaders)))
+	}
+	if containsHeader(cors.ExposedHeaders, "*") {
+		warnings = append(warnings, "CORS_EXPOSED_HEADERS=* exposes every response header to scripts; list only the headers the frontend reads")
+	}
+	if containsHeader(cors.ExposedHeaders, "Set-Cookie") || containsHeader(cors.ExposedHeaders, "Authorization") {
+		warnings = append(warnings, "CORS_EXPOSED_HEADERS exposes a credential header (Set-Cookie/Authorization); remove it unless scripts must read it")
+	}
+
+	return warnings
+}
+
+// containsHeader mengecek apakah headers berisi name (case-insensitive).
+func containsHeader(headers []string, name string) bool {
+	for _, header := range headers {
+		if strings.EqualFold(strings.TrimSpace(header), name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,168 @@
+package dim
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func productionCORSConfig(cors CORSConfig) *Config {
+	return &Config{
+		Server:   ServerConfig{Env: "production"},
+		JWT:      JWTConfig{HMACSecret: "secret", SigningMethod: "HS256"},
+		Database: DatabaseConfig{Driver: "sqlite", Database: "app.db"},
+		CORS:     cors,
+	}
+}
+
+func TestValidate_CORSProduction(t *testing.T) {
+	tests := []struct {
+		name    string
+		cors    CORSConfig
+		wantErr string
+	}{
+		{"valid https origins", CORSConfig{AllowedOrigins: []string{"https://app.example.com", "https://admin.example.com:8443"}, AllowCredentials: true}, ""},
+		{"no origins", CORSConfig{AllowedOrigins: []string{}}, ""},
+		{"wildcard without credentials", CORSConfig{AllowedOrigins: []string{"*"}}, ""},
+		{"wildcard with credentials", CORSConfig{AllowedOrigins: []string{"*"}, AllowCredentials: true}, "CORS_ALLOW_CREDENTIALS=false"},
+		{"http origin", CORSConfig{AllowedOrigins: []string{"http://app.example.com"}}, `use "https://app.example.com"`},
+		{"localhost", CORSConfig{AllowedOrigins: []string{"http://localhost:3000"}}, "must use https"},
+		{"trailing slash", CORSConfig{AllowedOrigins: []string{"https://app.example.com/"}}, `must not end with "/"`},
+		{"path", CORSConfig{AllowedOrigins: []string{"https://app.example.com/app"}}, "without path"},
+		{"no scheme", CORSConfig{AllowedOrigins: []string{"app.example.com"}}, "invalid origin"},
+		{"dev mode", corsDevPreset(3600), "CORS_DEV_MODE must not be enabled"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := productionCORSConfig(tt.cors).Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_CORSDevelopmentIsPermissive(t *testing.T) {
+	cfg := productionCORSConfig(CORSConfig{AllowedOrigins: []string{"*", "http://localhost:3000"}, AllowCredentials: true, DevMode: true})
+	cfg.Server.Env = "development"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+	if warnings := cfg.Warnings(); len(warnings) != 0 {
+		t.Errorf("Warnings() = %v, want none outside production", warnings)
+	}
+}
+
+func TestConfig_Warnings(t *testing.T) {
+	manyHeaders := make([]string, corsMaxAllowedHeaders+1)
+	for i := range manyHeaders {
+		manyHeaders[i] = "X-Header-" + string(rune('A'+i))
+	}
+
+	tests := []struct {
+		name string
+		cors CORSConfig
+		want string
+	}{
+		{"wildcard origin", CORSConfig{AllowedOrigins: []string{"*"}}, "CORS_ALLOWED_ORIGINS=*"},
+		{"wildcard header", CORSConfig{AllowedHeaders: []string{"Content-Type", "*"}}, "CORS_ALLOWED_HEADERS=*"},
+		{"many headers", CORSConfig{AllowedHeaders: manyHeaders}, "lists 16 headers"},
+		{"wildcard exposed", CORSConfig{ExposedHeaders: []string{"*"}}, "CORS_EXPOSED_HEADERS=*"},
+		{"credential exposed", CORSConfig{ExposedHeaders: []string{"set-cookie"}}, "credential header"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warnings := productionCORSConfig(tt.cors).Warnings()
+			if len(warnings) != 1 || !strings.Contains(warnings[0], tt.want) {
+				t.Errorf("Warnings() = %v, want one containing %q", warnings, tt.want)
+			}
+		})
+	}
+
+	if warnings := productionCORSConfig(CORSConfig{AllowedHeaders: []string{"Content-Type", "Authorization"}}).Warnings(); len(warnings) != 0 {
+		t.Errorf("Warnings() = %v, want none", warnings)
+	}
+}
+
+func TestLoadCORSConfig_DevMode(t *testing.T) {
+	values := map[string]string{
+		"CORS_DEV_MODE":        "true",
+		"CORS_ALLOWED_ORIGINS": "https://ignored.example.com",
+	}
+	cfg, err := loadCORSConfig(func(key string) string { return values[key] })
+	if err != nil {
+		t.Fatalf("loadCORSConfig() error = %v", err)
+	}
+	if !cfg.DevMode || len(cfg.AllowedOrigins) != 1 || cfg.AllowedOrigins[0] != "*" || !cfg.AllowCredentials || cfg.MaxAge != 3600 {
+		t.Errorf("dev preset = %+v", cfg)
+	}
+}
+
+func TestLoadCORSConfig_ProductionDefaultOrigins(t *testing.T) {
+	tests := []struct {
+		env  string
+		want int
+	}{
+		{"", 1},
+		{"development", 1},
+		{"production", 0},
+		{"PROD", 0},
+	}
+	for _, tt := range tests {
+		cfg, err := loadCORSConfig(func(key string) string {
+			if key == "APP_ENV" {
+				return tt.env
+			}
+			return ""
+		})
+		if err != nil {
+			t.Fatalf("loadCORSConfig() error = %v", err)
+		}
+		if len(cfg.AllowedOrigins) != tt.want {
+			t.Errorf("APP_ENV=%q: origins = %v, want %d", tt.env, cfg.AllowedOrigins, tt.want)
+		}
+	}
+}
+
+func TestLoadConfig_CORSProductionError(t *testing.T) {
+	values := map[string]string{
+		"APP_ENV":              "production",
+		"JWT_SECRET":           "secret",
+		"DB_DRIVER":            "sqlite",
+		"DB_NAME":              "app.db",
+		"CORS_ALLOWED_ORIGINS": "*",
+	}
+	_, err := loadConfig(func(key string) string { return values[key] })
+	if err == nil || !strings.Contains(err.Error(), "CORS_ALLOW_CREDENTIALS=true") {
+		t.Errorf("loadConfig() error = %v", err)
+	}
+}
+
+func TestCORSMiddleware_DevModeReflectsHeaders(t *testing.T) {
+	handler := CORS(corsDevPreset(600))(func(w http.ResponseWriter, r *http.Request) {})
+
+	req := httptest.NewRequest(http.MethodOptions, "/api", nil)
+	req.Header.Set("Origin", "http://localhost:5173")
+	req.Header.Set("Access-Control-Request-Headers", "content-type,x-tenant")
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "http://localhost:5173" {
+		t.Errorf("Allow-Origin = %q", got)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Headers"); got != "content-type,x-tenant" {
+		t.Errorf("Allow-Headers = %q", got)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Credentials"); got != "true" {
+		t.Errorf("Allow-Credentials = %q", got)
+	}
+}
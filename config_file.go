@@ -0,0 +1,645 @@
+package dim
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// configProfileKey adalah variable yang memilih profile file, misal APP_ENV=production
+// memuat config.production.yaml di atas config.yaml.
+const configProfileKey = "APP_ENV"
+
+// configJSONKeys adalah variable yang nilainya berupa JSON. Object atau array di file
+// konfigurasi untuk key ini di-encode sebagai JSON alih-alih di-flatten.
+var configJSONKeys = map[string]bool{
+	"JWT_PUBLIC_KEYS":   true,
+	"MAIL_SOCIAL_LINKS": true,
+}
+
+// LoadConfigFrom memuat konfigurasi dari file YAML, TOML, atau JSON, lalu menimpanya dengan
+// environment variables dan overrides eksplisit, dan menjalankan Validate di akhir.
+//
+// Key di file memakai nama yang sama dengan environment variable: key bersarang digabung
+// dengan "_" dan dijadikan huruf besar, sehingga `db: {write_host: localhost}` sama dengan
+// DB_WRITE_HOST=localhost. Array digabung dengan koma (misal CORS_ALLOWED_ORIGINS), kecuali
+// untuk JWT_PUBLIC_KEYS dan MAIL_SOCIAL_LINKS yang di-encode sebagai JSON.
+//
+// Jika APP_ENV di-set (dari overrides, environment, atau file utama), profile file di
+// direktori yang sama ikut dimuat, misal config.production.yaml untuk APP_ENV=production.
+// Profile file bersifat opsional.
+//
+// Urutan prioritas (yang terakhir menang):
+//  1. file utama (config.yaml)
+//  2. profile file (config.production.yaml)
+//  3. environment variables yang tidak kosong (termasuk yang dimuat dari .env dan .env.local)
+//  4. overrides, berurutan sesuai argumen
+//
+// Format ditentukan dari ekstensi: .yaml/.yml, .toml, atau .json. Parser YAML dan TOML
+// mendukung subset yang umum untuk konfigurasi: mapping/table bersarang, string (dengan atau
+// tanpa kutip), angka, boolean, array, dan komentar. Anchor YAML, multi-line string, dan
+// array of tables TOML tidak didukung.
+//
+// Parameters:
+//   - path: path file konfigurasi utama (wajib ada)
+//   - overrides: nilai eksplisit berdasarkan nama environment variable, misal dari flag CLI
+//
+// Returns:
+//   - *Config: struktur konfigurasi lengkap aplikasi
+//   - error: jika file (termasuk .env) tidak dapat dibaca atau di-parse, nilai tidak valid, atau validasi gagal
+//
+// Example:
+//
+//	// config.yaml:
+//	//   server:
+//	//     port: 8080
+//	//   db:
+//	//     write_host: localhost
+//	//     name: app
+//	cfg, err := dim.LoadConfigFrom("config.yaml", map[string]string{
+//	    "SERVER_PORT": *portFlag,
+//	})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+func LoadConfigFrom(path string, overrides ...map[string]string) (*Config, error) {
+	if err := LoadDotenv(); err != nil {
+		return nil, err
+	}
+
+	values, err := readConfigFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	lookupExplicit := func(key string) (string, bool) {
+		for i := len(overrides) - 1; i >= 0; i-- {
+			if value, ok := overrides[i][key]; ok {
+				return value, true
+			}
+		}
+		if value := os.Getenv(key); value != "" {
+			return value, true
+		}
+		return "", false
+	}
+
+	profile, ok := lookupExplicit(configProfileKey)
+	if !ok {
+		profile = values[configProfileKey]
+	}
+	if profile != "" {
+		profilePath := configProfilePath(path, profile)
+		profileValues, err := readConfigFile(profilePath)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		for key, value := range profileValues {
+			values[key] = value
+		}
+	}
+
+	return loadConfig(func(key string) string {
+		if value, ok := lookupExplicit(key); ok {
+			return value
+		}
+		return values[key]
+	})
+}
+
+// configProfilePath menyisipkan profile sebelum ekstensi, misal "config.yaml" → "config.production.yaml".
+func configProfilePath(path, profile string) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "." + profile + ext
+}
+
+// readConfigFile membaca file konfigurasi dan mengembalikan nilainya berdasarkan nama environment variable.
+func readConfigFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var tree map[string]any
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		tree, err = parseYAMLConfig(data)
+	case ".toml":
+		tree, err = parseTOMLConfig(data)
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		err = decoder.Decode(&tree)
+	default:
+		return nil, fmt.Errorf("unsupported config file format %q (expected .yaml, .yml, .toml or .json)", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+
+	values := make(map[string]string)
+	if err := flattenConfigValues("", tree, values); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	return values, nil
+}
+
+// flattenConfigValues menulis nilai tree ke out dengan key gaya environment variable.
+func flattenConfigValues(prefix string, value any, out map[string]string) error {
+	if configJSONKeys[prefix] {
+		switch value.(type) {
+		case map[string]any, []any:
+			encoded, err := json.Marshal(value)
+			if err != nil {
+				return fmt.Errorf("%s: %w", prefix, err)
+			}
+			return setConfigValue(out, prefix, string(encoded))
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		for key, child := range v {
+			name := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(key), "-", "_"))
+			if prefix != "" {
+				name = prefix + "_" + name
+			}
+			if err := flattenConfigValues(name, child, out); err != nil {
+				return err
+			}
+		}
+		return nil
+	case []any:
+		items := make([]string, len(v))
+		for i, item := range v {
+			scalar, err := configScalar(prefix, item)
+			if err != nil {
+				return err
+			}
+			items[i] = scalar
+		}
+		return setConfigValue(out, prefix, strings.Join(items, ","))
+	}
+
+	scalar, err := configScalar(prefix, value)
+	if err != nil {
+		return err
+	}
+	if prefix == "" {
+		return fmt.Errorf("top-level value must be an object")
+	}
+	return setConfigValue(out, prefix, scalar)
+}
+
+// setConfigValue menolak dua key yang menghasilkan nama variable sama, misal
+// `db: {write_host: a}` dan `db: {write: {host: b}}`, agar hasilnya tidak bergantung urutan map.
+func setConfigValue(out map[string]string, key, value string) error {
+	if _, exists := out[key]; exists {
+		return fmt.Errorf("%s is defined more than once", key)
+	}
+	out[key] = value
+	return nil
+}
+
+// configScalar memformat nilai scalar sebagai string environment variable.
+func configScalar(key string, value any) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case json.Number:
+		return v.String(), nil
+	}
+	return "", fmt.Errorf("%s: nested value is not supported here", key)
+}
+
+// yamlConfigLine adalah satu baris YAML yang sudah dibersihkan dari komentar.
+type yamlConfigLine struct {
+	num    int
+	indent int
+	text   string
+}
+
+// parseYAMLConfig mem-parse subset YAML untuk file konfigurasi: mapping bersarang berbasis
+// indentasi, sequence "- item" dan "[a, b]", scalar dengan atau tanpa kutip, serta komentar.
+func parseYAMLConfig(data []byte) (map[string]any, error) {
+	var lines []yamlConfigLine
+	for i, raw := range strings.Split(string(data), "\n") {
+		raw = strings.TrimRight(raw, "\r")
+		text := strings.TrimRight(stripConfigComment(raw), " \t")
+		trimmed := strings.TrimLeft(text, " ")
+		if trimmed == "" || trimmed == "---" {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "\t") {
+			return nil, fmt.Errorf("line %d: tabs are not allowed for indentation", i+1)
+		}
+		lines = append(lines, yamlConfigLine{num: i + 1, indent: len(text) - len(trimmed), text: trimmed})
+	}
+	if len(lines) == 0 {
+		return map[string]any{}, nil
+	}
+
+	p := &yamlConfigParser{lines: lines}
+	value, err := p.parseBlock(lines[0].indent)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(lines) {
+		return nil, fmt.Errorf("line %d: unexpected indentation", lines[p.pos].num)
+	}
+	tree, ok := value.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("top-level value must be a mapping")
+	}
+	return tree, nil
+}
+
+type yamlConfigParser struct {
+	lines []yamlConfigLine
+	pos   int
+}
+
+// parseBlock mem-parse mapping atau sequence yang dimulai di baris saat ini dengan indentasi indent.
+func (p *yamlConfigParser) parseBlock(indent int) (any, error) {
+	if isYAMLSequenceItem(p.lines[p.pos].text) {
+		return p.parseSequence(indent)
+	}
+	return p.parseMapping(indent)
+}
+
+func (p *yamlConfigParser) parseMapping(indent int) (map[string]any, error) {
+	result := make(map[string]any)
+	for p.pos < len(p.lines) {
+		line := p.lines[p.pos]
+		if line.indent < indent {
+			break
+		}
+		if line.indent > indent {
+			return nil, fmt.Errorf("line %d: unexpected indentation", line.num)
+		}
+		if isYAMLSequenceItem(line.text) {
+			return nil, fmt.Errorf("line %d: sequence item inside a mapping", line.num)
+		}
+
+		key, rest, ok := splitYAMLKey(line.text)
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected \"key: value\"", line.num)
+		}
+		if _, exists := result[key]; exists {
+			return nil, fmt.Errorf("line %d: duplicate key %q", line.num, key)
+		}
+		p.pos++
+
+		if rest != "" {
+			value, err := parseConfigScalar(rest)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line.num, err)
+			}
+			result[key] = value
+			continue
+		}
+
+		// Nilai kosong: block di baris berikutnya (lebih menjorok, atau sequence di indentasi yang sama)
+		if p.pos < len(p.lines) {
+			next := p.lines[p.pos]
+			if next.indent > indent || (next.indent == indent && isYAMLSequenceItem(next.text)) {
+				value, err := p.parseBlock(next.indent)
+				if err != nil {
+					return nil, err
+				}
+				result[key] = value
+				continue
+			}
+		}
+		result[key] = nil
+	}
+	return result, nil
+}
+
+func (p *yamlConfigParser) parseSequence(indent int) ([]any, error) {
+	var result []any
+	for p.pos < len(p.lines) {
+		line := p.lines[p.pos]
+		if line.indent != indent || !isYAMLSequenceItem(line.text) {
+			if line.indent > indent {
+				return nil, fmt.Errorf("line %d: unexpected indentation", line.num)
+			}
+			break
+		}
+		p.pos++
+
+		item := strings.TrimSpace(line.text[1:])
+		if item == "" {
+			if p.pos < len(p.lines) && p.lines[p.pos].indent > indent {
+				value, err := p.parseBlock(p.lines[p.pos].indent)
+				if err != nil {
+					return nil, err
+				}
+				result = append(result, value)
+				continue
+			}
+			result = append(result, nil)
+			continue
+		}
+		value, err := parseConfigScalar(item)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line.num, err)
+		}
+		result = append(result, value)
+	}
+	return result, nil
+}
+
+func isYAMLSequenceItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+// splitYAMLKey memisahkan "key: value" atau "key:"; key boleh diberi kutip.
+func splitYAMLKey(text string) (string, string, bool) {
+	end := -1
+	if text[0] == '"' || text[0] == '\'' {
+		if closing := strings.IndexByte(text[1:], text[0]); closing >= 0 {
+			end = closing + 2
+		}
+	} else {
+		end = strings.Index(text+" ", ": ")
+	}
+	if end <= 0 || end >= len(text) || text[end] != ':' || (end+1 < len(text) && text[end+1] != ' ') {
+		return "", "", false
+	}
+
+	key := text[:end]
+	if key[0] == '"' || key[0] == '\'' {
+		unquoted, err := unquoteConfigString(key)
+		if err != nil {
+			return "", "", false
+		}
+		key = unquoted
+	}
+	return key, strings.TrimSpace(text[end+1:]), true
+}
+
+// parseTOMLConfig mem-parse subset TOML untuk file konfigurasi: [table] dan [a.b], key
+// bertitik, string basic dan literal, angka, boolean, array (boleh multi-baris), serta komentar.
+func parseTOMLConfig(data []byte) (map[string]any, error) {
+	root := make(map[string]any)
+	table := root
+	lines := strings.Split(string(data), "\n")
+
+	for i := 0; i < len(lines); i++ {
+		num := i + 1
+		line := strings.TrimSpace(stripConfigComment(strings.TrimRight(lines[i], "\r")))
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			if strings.HasPrefix(line, "[[") {
+				return nil, fmt.Errorf("line %d: arrays of tables are not supported", num)
+			}
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("line %d: invalid table header", num)
+			}
+			path, err := splitTOMLKey(line[1 : len(line)-1])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", num, err)
+			}
+			if table, err = tomlTable(root, path); err != nil {
+				return nil, fmt.Errorf("line %d: %w", num, err)
+			}
+			continue
+		}
+
+		rawKey, rawValue, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected \"key = value\"", num)
+		}
+		path, err := splitTOMLKey(rawKey)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", num, err)
+		}
+		rawValue = strings.TrimSpace(rawValue)
+
+		// Array multi-baris: gabungkan baris sampai kurung siku tertutup
+		for strings.HasPrefix(rawValue, "[") && !configBracketsClosed(rawValue) && i+1 < len(lines) {
+			i++
+			rawValue += " " + strings.TrimSpace(stripConfigComment(strings.TrimRight(lines[i], "\r")))
+		}
+
+		value, err := parseConfigScalar(rawValue)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", num, err)
+		}
+		parent, err := tomlTable(table, path[:len(path)-1])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", num, err)
+		}
+		key := path[len(path)-1]
+		if _, exists := parent[key]; exists {
+			return nil, fmt.Errorf("line %d: duplicate key %q", num, key)
+		}
+		parent[key] = value
+	}
+	return root, nil
+}
+
+// splitTOMLKey memisahkan key bertitik, misal `db.write_host` atau `"a.b".c`.
+func splitTOMLKey(raw string) ([]string, error) {
+	var parts []string
+	raw = strings.TrimSpace(raw)
+	for raw != "" {
+		var part string
+		if raw[0] == '"' || raw[0] == '\'' {
+			closing := strings.IndexByte(raw[1:], raw[0])
+			if closing < 0 {
+				return nil, fmt.Errorf("unterminated quoted key")
+			}
+			unquoted, err := unquoteConfigString(raw[:closing+2])
+			if err != nil {
+				return nil, err
+			}
+			part, raw = unquoted, strings.TrimSpace(raw[closing+2:])
+		} else {
+			end := strings.IndexByte(raw, '.')
+			if end < 0 {
+				end = len(raw)
+			}
+			part, raw = strings.TrimSpace(raw[:end]), strings.TrimSpace(raw[end:])
+		}
+		if part == "" {
+			return nil, fmt.Errorf("empty key")
+		}
+		parts = append(parts, part)
+
+		if raw != "" {
+			if raw[0] != '.' {
+				return nil, fmt.Errorf("invalid key")
+			}
+			raw = strings.TrimSpace(raw[1:])
+			if raw == "" {
+				return nil, fmt.Errorf("empty key")
+			}
+		}
+	}
+	if len(parts) == 0 {
+		return nil, fmt.Errorf("empty key")
+	}
+	return parts, nil
+}
+
+// tomlTable mengembalikan (dan membuat jika perlu) table bersarang pada path.
+func tomlTable(root map[string]any, path []string) (map[string]any, error) {
+	table := root
+	for _, key := range path {
+		switch child := table[key].(type) {
+		case nil:
+			next := make(map[string]any)
+			table[key] = next
+			table = next
+		case map[string]any:
+			table = child
+		default:
+			return nil, fmt.Errorf("key %q is already a value", key)
+		}
+	}
+	return table, nil
+}
+
+// parseConfigScalar mem-parse nilai scalar atau array inline ("[a, b]") YAML/TOML.
+// Angka dan boolean dikembalikan sebagai teks aslinya karena semua nilai menjadi string environment.
+func parseConfigScalar(raw string) (any, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, nil
+	}
+
+	switch raw[0] {
+	case '"', '\'':
+		return unquoteConfigString(raw)
+	case '[':
+		if !strings.HasSuffix(raw, "]") {
+			return nil, fmt.Errorf("unterminated array")
+		}
+		var items []any
+		for _, item := range splitConfigArray(raw[1 : len(raw)-1]) {
+			value, err := parseConfigScalar(item)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, value)
+		}
+		if items == nil {
+			items = []any{}
+		}
+		return items, nil
+	case '{':
+		return nil, fmt.Errorf("inline tables are not supported")
+	}
+
+	if raw == "~" || raw == "null" {
+		return nil, nil
+	}
+	return raw, nil
+}
+
+// unquoteConfigString menghapus kutip: "..." dengan escape gaya Go/JSON, '...' apa adanya
+// (dua kutip tunggal berturut-turut menjadi satu, seperti YAML).
+func unquoteConfigString(raw string) (string, error) {
+	if len(raw) < 2 || raw[len(raw)-1] != raw[0] {
+		return "", fmt.Errorf("unterminated string %s", raw)
+	}
+	if raw[0] == '\'' {
+		return strings.ReplaceAll(raw[1:len(raw)-1], "''", "'"), nil
+	}
+	value, err := strconv.Unquote(raw)
+	if err != nil {
+		return "", fmt.Errorf("invalid string %s", raw)
+	}
+	return value, nil
+}
+
+// splitConfigArray memisahkan isi array inline berdasarkan koma di luar kutip. Item kosong
+// (misal trailing comma) diabaikan.
+func splitConfigArray(raw string) []string {
+	var items []string
+	var quote byte
+	start := 0
+	for i := 0; i <= len(raw); i++ {
+		if i < len(raw) {
+			c := raw[i]
+			switch {
+			case quote != 0:
+				if c == '\\' && quote == '"' {
+					i++
+				} else if c == quote {
+					quote = 0
+				}
+				continue
+			case c == '"' || c == '\'':
+				quote = c
+				continue
+			case c != ',':
+				continue
+			}
+		}
+		if item := strings.TrimSpace(raw[start:i]); item != "" {
+			items = append(items, item)
+		}
+		start = i + 1
+	}
+	return items
+}
+
+// configBracketsClosed melaporkan apakah semua "[" di luar kutip sudah ditutup.
+func configBracketsClosed(raw string) bool {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(raw); i++ {
+		c := raw[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+		}
+	}
+	return depth <= 0
+}
+
+// stripConfigComment menghapus komentar "#" yang berada di luar kutip dan diawali spasi
+// (atau di awal baris), sehingga nilai seperti "#007bff" dalam kutip tetap utuh.
+func stripConfigComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
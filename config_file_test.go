@@ -0,0 +1,337 @@
+package dim
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeConfigFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+	return path
+}
+
+func TestLoadConfigFrom_YAML(t *testing.T) {
+	path := writeConfigFile(t, t.TempDir(), "config.yaml", `
+# Konfigurasi aplikasi
+server:
+  port: 9000
+  read_timeout: 5s   # komentar setelah nilai
+jwt:
+  secret: "s3cret # bukan komentar"
+db:
+  driver: postgres
+  write_host: localhost
+  read_hosts:
+    - replica-1
+    - replica-2
+  name: app
+  user: 'o''neil'
+cors:
+  allowed_origins: [https://app.example.com, "https://admin.example.com"]
+mail:
+  primary_color: "#ff0000"
+rate-limit:
+  enabled: false
+`)
+
+	cfg, err := LoadConfigFrom(path)
+	if err != nil {
+		t.Fatalf("LoadConfigFrom() failed: %v", err)
+	}
+
+	if cfg.Server.Port != "9000" {
+		t.Errorf("Server.Port = %s, want 9000", cfg.Server.Port)
+	}
+	if cfg.Server.ReadTimeout != 5*time.Second {
+		t.Errorf("Server.ReadTimeout = %v, want 5s", cfg.Server.ReadTimeout)
+	}
+	if cfg.Server.WriteTimeout != 30*time.Second {
+		t.Errorf("Server.WriteTimeout = %v, want default 30s", cfg.Server.WriteTimeout)
+	}
+	if cfg.JWT.HMACSecret != "s3cret # bukan komentar" {
+		t.Errorf("JWT.HMACSecret = %q", cfg.JWT.HMACSecret)
+	}
+	if !reflect.DeepEqual(cfg.Database.ReadHosts, []string{"replica-1", "replica-2"}) {
+		t.Errorf("Database.ReadHosts = %v", cfg.Database.ReadHosts)
+	}
+	if cfg.Database.Username != "o'neil" {
+		t.Errorf("Database.Username = %q, want o'neil", cfg.Database.Username)
+	}
+	if !reflect.DeepEqual(cfg.CORS.AllowedOrigins, []string{"https://app.example.com", "https://admin.example.com"}) {
+		t.Errorf("CORS.AllowedOrigins = %v", cfg.CORS.AllowedOrigins)
+	}
+	if cfg.Email.PrimaryColor != "#ff0000" {
+		t.Errorf("Email.PrimaryColor = %q, want #ff0000", cfg.Email.PrimaryColor)
+	}
+	if cfg.RateLimit.Enabled {
+		t.Error("RateLimit.Enabled should be false (key rate-limit maps to RATE_LIMIT)")
+	}
+}
+
+func TestLoadConfigFrom_TOML(t *testing.T) {
+	path := writeConfigFile(t, t.TempDir(), "config.toml", `
+# Konfigurasi aplikasi
+jwt.secret = "toml-secret"
+
+[server]
+port = 7000
+
+[db]
+driver = 'sqlite'
+name = "app.db" # komentar
+read_hosts = [
+  "replica-1",
+  "replica-2", # trailing comma
+]
+
+[csrf]
+enabled = true
+exempt_paths = ["/webhooks", "/health"]
+`)
+
+	cfg, err := LoadConfigFrom(path)
+	if err != nil {
+		t.Fatalf("LoadConfigFrom() failed: %v", err)
+	}
+
+	if cfg.JWT.HMACSecret != "toml-secret" {
+		t.Errorf("JWT.HMACSecret = %q", cfg.JWT.HMACSecret)
+	}
+	if cfg.Server.Port != "7000" {
+		t.Errorf("Server.Port = %s, want 7000", cfg.Server.Port)
+	}
+	if cfg.Database.Driver != "sqlite" || cfg.Database.Database != "app.db" {
+		t.Errorf("Database = %s/%s, want sqlite/app.db", cfg.Database.Driver, cfg.Database.Database)
+	}
+	if !reflect.DeepEqual(cfg.Database.ReadHosts, []string{"replica-1", "replica-2"}) {
+		t.Errorf("Database.ReadHosts = %v", cfg.Database.ReadHosts)
+	}
+	if !cfg.CSRF.Enabled || !reflect.DeepEqual(cfg.CSRF.ExemptPaths, []string{"/webhooks", "/health"}) {
+		t.Errorf("CSRF = %+v", cfg.CSRF)
+	}
+}
+
+func TestLoadConfigFrom_JSON(t *testing.T) {
+	path := writeConfigFile(t, t.TempDir(), "config.json", `{
+  "jwt": {
+    "secret": "json-secret",
+    "public_keys": {"kid-1": "-----BEGIN PUBLIC KEY-----\nabc\n-----END PUBLIC KEY-----"}
+  },
+  "db": {"driver": "sqlite", "name": "app.db", "max_conns": 10},
+  "rate_limit": {"enabled": true, "per_ip": 50},
+  "mail": {"social_links": [{"name": "GitHub", "url": "https://github.com/dim"}]}
+}`)
+
+	cfg, err := LoadConfigFrom(path)
+	if err != nil {
+		t.Fatalf("LoadConfigFrom() failed: %v", err)
+	}
+
+	if cfg.Database.MaxConns != 10 {
+		t.Errorf("Database.MaxConns = %d, want 10", cfg.Database.MaxConns)
+	}
+	if !cfg.RateLimit.Enabled || cfg.RateLimit.PerIP != 50 {
+		t.Errorf("RateLimit = %+v", cfg.RateLimit)
+	}
+	if !strings.Contains(cfg.JWT.PublicKeys["kid-1"], "BEGIN PUBLIC KEY") {
+		t.Errorf("JWT.PublicKeys = %v", cfg.JWT.PublicKeys)
+	}
+	if cfg.Email.SocialLinks != `[{"name":"GitHub","url":"https://github.com/dim"}]` {
+		t.Errorf("Email.SocialLinks = %s", cfg.Email.SocialLinks)
+	}
+}
+
+func TestLoadConfigFrom_Precedence(t *testing.T) {
+	dir := t.TempDir()
+	path := writeConfigFile(t, dir, "config.yaml", `
+app:
+  env: production
+server:
+  port: 8000
+  read_timeout: 10s
+  write_timeout: 10s
+  idle_timeout: 10s
+jwt:
+  secret: base-secret
+db:
+  driver: sqlite
+  name: base.db
+`)
+	writeConfigFile(t, dir, "config.production.yaml", `
+server:
+  port: 8100
+  read_timeout: 20s
+  write_timeout: 20s
+db:
+  name: production.db
+`)
+
+	t.Setenv("SERVER_READ_TIMEOUT", "40s")
+	t.Setenv("SERVER_WRITE_TIMEOUT", "40s")
+
+	cfg, err := LoadConfigFrom(path, map[string]string{"SERVER_WRITE_TIMEOUT": "50s"})
+	if err != nil {
+		t.Fatalf("LoadConfigFrom() failed: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		got  interface{}
+		want interface{}
+	}{
+		{"base file only", cfg.Server.IdleTimeout, 10 * time.Second},
+		{"profile over base", cfg.Server.Port, "8100"},
+		{"profile over base (db)", cfg.Database.Database, "production.db"},
+		{"env over profile", cfg.Server.ReadTimeout, 40 * time.Second},
+		{"override over env", cfg.Server.WriteTimeout, 50 * time.Second},
+		{"default when unset", cfg.Server.ShutdownTimeout, 10 * time.Second},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestLoadConfigFrom_ProfileSelection(t *testing.T) {
+	dir := t.TempDir()
+	path := writeConfigFile(t, dir, "config.yaml", "jwt:\n  secret: s\ndb:\n  driver: sqlite\n  name: base.db\n")
+	writeConfigFile(t, dir, "config.staging.yaml", "db:\n  name: staging.db\n")
+	writeConfigFile(t, dir, "config.testing.yaml", "db:\n  name: testing.db\n")
+
+	t.Run("from env", func(t *testing.T) {
+		t.Setenv("APP_ENV", "staging")
+		cfg, err := LoadConfigFrom(path)
+		if err != nil {
+			t.Fatalf("LoadConfigFrom() failed: %v", err)
+		}
+		if cfg.Database.Database != "staging.db" {
+			t.Errorf("Database.Database = %s, want staging.db", cfg.Database.Database)
+		}
+	})
+
+	t.Run("override wins over env", func(t *testing.T) {
+		t.Setenv("APP_ENV", "staging")
+		cfg, err := LoadConfigFrom(path, map[string]string{"APP_ENV": "testing"})
+		if err != nil {
+			t.Fatalf("LoadConfigFrom() failed: %v", err)
+		}
+		if cfg.Database.Database != "testing.db" {
+			t.Errorf("Database.Database = %s, want testing.db", cfg.Database.Database)
+		}
+	})
+
+	t.Run("missing profile file is optional", func(t *testing.T) {
+		t.Setenv("APP_ENV", "development")
+		cfg, err := LoadConfigFrom(path)
+		if err != nil {
+			t.Fatalf("LoadConfigFrom() failed: %v", err)
+		}
+		if cfg.Database.Database != "base.db" {
+			t.Errorf("Database.Database = %s, want base.db", cfg.Database.Database)
+		}
+	})
+}
+
+func TestLoadConfigFrom_OverridesApplyInOrder(t *testing.T) {
+	path := writeConfigFile(t, t.TempDir(), "config.json", `{"jwt": {"secret": "s"}, "db": {"driver": "sqlite", "name": "app.db"}}`)
+
+	cfg, err := LoadConfigFrom(path,
+		map[string]string{"SERVER_PORT": "1000", "DB_NAME": "first.db"},
+		map[string]string{"SERVER_PORT": "2000"},
+	)
+	if err != nil {
+		t.Fatalf("LoadConfigFrom() failed: %v", err)
+	}
+	if cfg.Server.Port != "2000" {
+		t.Errorf("Server.Port = %s, want 2000 (last override wins)", cfg.Server.Port)
+	}
+	if cfg.Database.Database != "first.db" {
+		t.Errorf("Database.Database = %s, want first.db", cfg.Database.Database)
+	}
+}
+
+func TestLoadConfigFrom_RunsValidate(t *testing.T) {
+	path := writeConfigFile(t, t.TempDir(), "config.yaml", "jwt:\n  secret: s\ndb:\n  driver: postgres\n  name: app\n")
+
+	_, err := LoadConfigFrom(path)
+	if err == nil || !strings.Contains(err.Error(), "DB_WRITE_HOST") {
+		t.Errorf("LoadConfigFrom() error = %v, want DB_WRITE_HOST validation error", err)
+	}
+
+	// Override yang melengkapi konfigurasi membuat validasi lolos
+	if _, err := LoadConfigFrom(path, map[string]string{"DB_WRITE_HOST": "localhost", "DB_USER": "app"}); err != nil {
+		t.Errorf("LoadConfigFrom() with overrides failed: %v", err)
+	}
+}
+
+func TestLoadConfigFrom_InvalidValue(t *testing.T) {
+	path := writeConfigFile(t, t.TempDir(), "config.yaml", "server:\n  read_timeout: soon\n")
+
+	_, err := LoadConfigFrom(path)
+	if err == nil || !strings.Contains(err.Error(), "SERVER_READ_TIMEOUT") {
+		t.Errorf("LoadConfigFrom() error = %v, want SERVER_READ_TIMEOUT error", err)
+	}
+}
+
+func TestLoadConfigFrom_FileErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		wantErr string
+	}{
+		{"unsupported extension", "config.ini", "a=b", "unsupported config file format"},
+		{"yaml bad indentation", "config.yaml", "server:\n  port: 1\n    host: x\n", "line 3"},
+		{"yaml missing colon", "config.yaml", "server\n", "line 1"},
+		{"yaml duplicate key", "config.yaml", "server:\n  port: 1\n  port: 2\n", "duplicate key"},
+		{"yaml tab indentation", "config.yaml", "server:\n\tport: 1\n", "tabs"},
+		{"toml array of tables", "config.toml", "[[servers]]\n", "arrays of tables"},
+		{"toml missing equals", "config.toml", "[server]\nport\n", "line 2"},
+		{"toml unterminated string", "config.toml", "name = \"app\n", "unterminated"},
+		{"json syntax", "config.json", "{", "invalid config file"},
+		{"flattened key collision", "config.yaml", "db:\n  write_host: a\n  write:\n    host: b\n", "DB_WRITE_HOST is defined more than once"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeConfigFile(t, dir, tt.file, tt.content)
+			_, err := LoadConfigFrom(path)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadConfigFrom() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := LoadConfigFrom(filepath.Join(dir, "missing.yaml"))
+		if !os.IsNotExist(err) {
+			t.Errorf("LoadConfigFrom() error = %v, want not-exist error", err)
+		}
+	})
+}
+
+func TestParseYAMLConfig_SequenceAtSameIndent(t *testing.T) {
+	tree, err := parseYAMLConfig([]byte("cors:\n  allowed_methods:\n  - GET\n  - POST\nempty:\n"))
+	if err != nil {
+		t.Fatalf("parseYAMLConfig() failed: %v", err)
+	}
+	values := make(map[string]string)
+	if err := flattenConfigValues("", tree, values); err != nil {
+		t.Fatalf("flattenConfigValues() failed: %v", err)
+	}
+	want := map[string]string{"CORS_ALLOWED_METHODS": "GET,POST", "EMPTY": ""}
+	if !reflect.DeepEqual(values, want) {
+		t.Errorf("values = %v, want %v", values, want)
+	}
+}
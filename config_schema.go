@@ -0,0 +1,281 @@
+package dim
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// jsonSchemaDialect adalah versi JSON Schema yang dihasilkan GenerateConfigSchema.
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// ConfigSchema adalah JSON Schema yang mendeskripsikan semua environment variable konfigurasi.
+// Nama property adalah nama environment variable (misal "DB_PORT").
+type ConfigSchema struct {
+	Schema     string                           `json:"$schema"`
+	Title      string                           `json:"title"`
+	Type       string                           `json:"type"`
+	Properties map[string]*ConfigSchemaProperty `json:"properties"`
+	Required   []string                         `json:"required,omitempty"`
+}
+
+// ConfigSchemaProperty mendeskripsikan satu environment variable.
+// Slice ditulis sebagai nilai dipisah koma di environment dan sebagai array di file konfigurasi.
+type ConfigSchemaProperty struct {
+	Type                 string                `json:"type"`
+	Format               string                `json:"format,omitempty"`
+	Description          string                `json:"description,omitempty"`
+	Default              any                   `json:"default,omitempty"`
+	Enum                 []string              `json:"enum,omitempty"`
+	Items                *ConfigSchemaProperty `json:"items,omitempty"`
+	AdditionalProperties *ConfigSchemaProperty `json:"additionalProperties,omitempty"`
+	// Section adalah nama bagian konfigurasi asal variable (misal "database" atau nama RegisterSection).
+	Section string `json:"x-section,omitempty"`
+}
+
+// GenerateConfigSchema membuat JSON Schema dari struct konfigurasi bawaan dan section yang
+// didaftarkan lewat Config.RegisterSection. Metadata dibaca dari tag struct:
+//   - env:"NAME": nama environment variable; field bawaan tanpa tag env tidak dimasukkan
+//   - desc:"...": deskripsi variable
+//   - validate:"...": "required" menandai variable wajib, "oneof=a|b" menjadi enum
+//   - default:"value": nilai default untuk field section
+//
+// Default konfigurasi bawaan diambil dengan memuat konfigurasi kosong untuk env, sehingga
+// default yang bergantung environment (misal CORS_ALLOWED_ORIGINS di production) sesuai.
+//
+// Parameters:
+//   - cfg: konfigurasi dengan section aplikasi (boleh nil untuk konfigurasi bawaan saja)
+//   - env: nilai APP_ENV yang dipakai untuk menghitung default (default: "development")
+//
+// Returns:
+//   - *ConfigSchema: schema yang siap di-encode sebagai JSON
+//   - error: jika default tidak dapat dimuat atau nama variable bentrok antar section
+//
+// Example:
+//
+//	schema, err := dim.GenerateConfigSchema(cfg, "production")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	json.NewEncoder(os.Stdout).Encode(schema)
+func GenerateConfigSchema(cfg *Config, env string) (*ConfigSchema, error) {
+	if env == "" {
+		env = "development"
+	}
+
+	defaults, err := loadConfigSections(func(key string) string {
+		if key == configProfileKey {
+			return env
+		}
+		return ""
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load default configuration: %w", err)
+	}
+
+	schema := &ConfigSchema{
+		Schema:     jsonSchemaDialect,
+		Title:      fmt.Sprintf("Configuration (%s)", env),
+		Type:       "object",
+		Properties: make(map[string]*ConfigSchemaProperty),
+	}
+
+	rv := reflect.ValueOf(defaults).Elem()
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if !field.IsExported() || field.Type.Kind() != reflect.Struct {
+			continue
+		}
+		section := strings.ToLower(configEnvName(field.Name))
+		if err := schema.addBuiltin(section, rv.Field(i)); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg != nil {
+		for _, section := range cfg.sections {
+			if err := schema.addSection(section.name, section.prefix, reflect.TypeOf(section.target).Elem()); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return schema, nil
+}
+
+// addBuiltin menambahkan field struct konfigurasi bawaan yang memiliki tag env.
+// Nilai field dari konfigurasi kosong menjadi default.
+func (s *ConfigSchema) addBuiltin(section string, rv reflect.Value) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		key := field.Tag.Get("env")
+		if !field.IsExported() || key == "" || key == "-" {
+			continue
+		}
+		prop := configSchemaType(field.Type)
+		prop.Default = configSchemaDefault(rv.Field(i))
+		if err := s.add(section, key, field, prop); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addSection menambahkan field section aplikasi dengan aturan penamaan yang sama seperti
+// loadConfigStruct. Default diambil dari tag default.
+func (s *ConfigSchema) addSection(section, prefix string, rt reflect.Type) error {
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		tag := field.Tag.Get("env")
+		if tag == "-" {
+			continue
+		}
+
+		if field.Anonymous && tag == "" && field.Type.Kind() == reflect.Struct {
+			if err := s.addSection(section, prefix, field.Type); err != nil {
+				return err
+			}
+			continue
+		}
+
+		key := tag
+		if key == "" {
+			key = configEnvName(field.Name)
+		}
+		key = prefix + key
+
+		if field.Type.Kind() == reflect.Struct && !isLeafStruct(field.Type) {
+			if err := s.addSection(section, key+"_", field.Type); err != nil {
+				return err
+			}
+			continue
+		}
+
+		prop := configSchemaType(field.Type)
+		if raw := field.Tag.Get("default"); raw != "" {
+			value := reflect.New(field.Type).Elem()
+			if err := setConfigField(value, raw); err != nil {
+				return fmt.Errorf("config section %s: invalid default for %s: %w", section, key, err)
+			}
+			prop.Default = configSchemaDefault(value)
+		}
+		if err := s.add(section, key, field, prop); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// add melengkapi property dengan desc dan aturan validate lalu mendaftarkannya.
+func (s *ConfigSchema) add(section, key string, field reflect.StructField, prop *ConfigSchemaProperty) error {
+	if existing, ok := s.Properties[key]; ok {
+		return fmt.Errorf("config key %s is defined by both %s and %s", key, existing.Section, section)
+	}
+
+	prop.Section = section
+	prop.Description = field.Tag.Get("desc")
+	for _, rule := range strings.Split(field.Tag.Get("validate"), ",") {
+		name, param, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		switch name {
+		case "required":
+			s.Required = append(s.Required, key)
+		case "oneof":
+			prop.Enum = strings.Split(param, "|")
+		}
+	}
+
+	s.Properties[key] = prop
+	return nil
+}
+
+// configSchemaType memetakan tipe Go ke tipe JSON Schema.
+func configSchemaType(t reflect.Type) *ConfigSchemaProperty {
+	if t == reflect.TypeOf(time.Duration(0)) {
+		return &ConfigSchemaProperty{Type: "string", Format: "duration"}
+	}
+	if t.Kind() == reflect.Ptr {
+		return configSchemaType(t.Elem())
+	}
+	if reflect.PointerTo(t).Implements(reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()) {
+		return &ConfigSchemaProperty{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &ConfigSchemaProperty{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &ConfigSchemaProperty{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &ConfigSchemaProperty{Type: "number"}
+	case reflect.Slice:
+		return &ConfigSchemaProperty{Type: "array", Items: configSchemaType(t.Elem())}
+	case reflect.Map:
+		return &ConfigSchemaProperty{Type: "object", AdditionalProperties: configSchemaType(t.Elem())}
+	default:
+		return &ConfigSchemaProperty{Type: "string"}
+	}
+}
+
+// configSchemaDefault mengubah nilai field menjadi default JSON. Nilai kosong menghasilkan nil
+// (tanpa default), kecuali boolean yang selalu memiliki default.
+func configSchemaDefault(v reflect.Value) any {
+	if v.Type() == reflect.TypeOf(time.Duration(0)) {
+		if v.Int() == 0 {
+			return nil
+		}
+		return formatConfigDuration(time.Duration(v.Int()))
+	}
+	if marshaler, ok := v.Interface().(encoding.TextMarshaler); ok && !v.IsZero() {
+		text, err := marshaler.MarshalText()
+		if err != nil {
+			return nil
+		}
+		return string(text)
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		return v.Bool()
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+		return configSchemaDefault(v.Elem())
+	case reflect.Slice:
+		if v.Len() == 0 {
+			return nil
+		}
+		values := make([]any, v.Len())
+		for i := range values {
+			values[i] = configSchemaDefault(v.Index(i))
+		}
+		return values
+	case reflect.Map:
+		return nil
+	}
+
+	if v.IsZero() {
+		return nil
+	}
+	return v.Interface()
+}
+
+// formatConfigDuration menulis durasi tanpa komponen nol di akhir, misal 168h0m0s → "168h".
+func formatConfigDuration(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
@@ -0,0 +1,185 @@
+package dim
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestGenerateConfigSchema_Builtin(t *testing.T) {
+	schema, err := GenerateConfigSchema(nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if schema.Schema != jsonSchemaDialect || schema.Type != "object" || schema.Title != "Configuration (development)" {
+		t.Errorf("schema header = %s %s %s", schema.Schema, schema.Type, schema.Title)
+	}
+
+	tests := []struct {
+		key     string
+		typ     string
+		def     any
+		section string
+	}{
+		{"DB_PORT", "integer", 5432, "database"},
+		{"SERVER_READ_TIMEOUT", "string", "30s", "server"},
+		{"JWT_REFRESH_TOKEN_EXPIRY", "string", "168h", "jwt"},
+		{"RATE_LIMIT_ENABLED", "boolean", true, "rate_limit"},
+		{"SERVER_H2C", "boolean", false, "server"},
+		{"BRANCA_KEY", "string", nil, "branca"},
+		{"CORS_ALLOWED_ORIGINS", "array", []any{"http://localhost:3000"}, "cors"},
+		{"JWT_PUBLIC_KEYS", "object", nil, "jwt"},
+	}
+	for _, tt := range tests {
+		prop := schema.Properties[tt.key]
+		if prop == nil {
+			t.Errorf("%s missing from schema", tt.key)
+			continue
+		}
+		if prop.Type != tt.typ || !reflect.DeepEqual(prop.Default, tt.def) || prop.Section != tt.section || prop.Description == "" {
+			t.Errorf("%s = %+v, want type %s default %v section %s", tt.key, prop, tt.typ, tt.def, tt.section)
+		}
+	}
+
+	if prop := schema.Properties["SERVER_READ_TIMEOUT"]; prop.Format != "duration" {
+		t.Errorf("duration format = %q", prop.Format)
+	}
+	if enum := schema.Properties["DB_DRIVER"].Enum; !reflect.DeepEqual(enum, []string{"postgres", "mysql", "sqlite"}) {
+		t.Errorf("DB_DRIVER enum = %v", enum)
+	}
+	if !reflect.DeepEqual(schema.Required, []string{"DB_NAME"}) {
+		t.Errorf("required = %v", schema.Required)
+	}
+	if _, ok := schema.Properties["DB_RUNTIME_PARAMS"]; ok {
+		t.Error("fields tagged env:\"-\" should be skipped")
+	}
+}
+
+func TestGenerateConfigSchema_PerEnvironment(t *testing.T) {
+	schema, err := GenerateConfigSchema(nil, "production")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if schema.Properties["APP_ENV"].Default != "production" {
+		t.Errorf("APP_ENV default = %v", schema.Properties["APP_ENV"].Default)
+	}
+	// Production tidak memiliki default origin localhost
+	if def := schema.Properties["CORS_ALLOWED_ORIGINS"].Default; def != nil {
+		t.Errorf("CORS_ALLOWED_ORIGINS default in production = %v", def)
+	}
+}
+
+// TestGenerateConfigSchema_CoversLoadedKeys memastikan setiap variable yang dibaca loader
+// konfigurasi bawaan memiliki tag env, sehingga schema tidak tertinggal dari kode.
+func TestGenerateConfigSchema_CoversLoadedKeys(t *testing.T) {
+	requested := make(map[string]bool)
+	if _, err := loadConfigSections(func(key string) string {
+		requested[key] = true
+		return ""
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	schema, _ := GenerateConfigSchema(nil, "")
+	fallbacks := map[string]bool{"SES_REGION": true, "SES_ACCESS_KEY_ID": true, "SES_SECRET_ACCESS_KEY": true}
+	var missing []string
+	for key := range requested {
+		if schema.Properties[key] == nil && !fallbacks[key] {
+			missing = append(missing, key)
+		}
+	}
+	sort.Strings(missing)
+	if len(missing) > 0 {
+		t.Errorf("config keys without env tag: %v", missing)
+	}
+}
+
+func TestGenerateConfigSchema_Sections(t *testing.T) {
+	type webhookConfig struct {
+		Secret string `validate:"required" desc:"Webhook signing secret"`
+	}
+	type paymentsConfig struct {
+		StripeKey  string        `validate:"required" desc:"Stripe API key"`
+		Currency   string        `default:"IDR" validate:"oneof=IDR|USD"`
+		Timeout    time.Duration `default:"90s"`
+		Retries    int           `default:"3"`
+		WebhookIPs []string      `env:"WEBHOOK_ALLOWED_IPS" default:"10.0.0.1,10.0.0.2"`
+		Webhook    webhookConfig
+		Internal   string `env:"-"`
+	}
+
+	t.Setenv("PAYMENTS_STRIPE_KEY", "sk_test")
+	t.Setenv("PAYMENTS_WEBHOOK_SECRET", "whsec")
+	cfg := &Config{}
+	var payments paymentsConfig
+	if err := cfg.RegisterSection("payments", &payments); err != nil {
+		t.Fatal(err)
+	}
+
+	schema, err := GenerateConfigSchema(cfg, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		key string
+		typ string
+		def any
+	}{
+		{"PAYMENTS_STRIPE_KEY", "string", nil},
+		{"PAYMENTS_CURRENCY", "string", "IDR"},
+		{"PAYMENTS_TIMEOUT", "string", "1m30s"},
+		{"PAYMENTS_RETRIES", "integer", 3},
+		{"PAYMENTS_WEBHOOK_ALLOWED_IPS", "array", []any{"10.0.0.1", "10.0.0.2"}},
+		{"PAYMENTS_WEBHOOK_SECRET", "string", nil},
+	}
+	for _, tt := range tests {
+		prop := schema.Properties[tt.key]
+		if prop == nil || prop.Type != tt.typ || !reflect.DeepEqual(prop.Default, tt.def) || prop.Section != "payments" {
+			t.Errorf("%s = %+v, want type %s default %#v", tt.key, prop, tt.typ, tt.def)
+		}
+	}
+	if schema.Properties["PAYMENTS_STRIPE_KEY"].Description != "Stripe API key" {
+		t.Errorf("description = %q", schema.Properties["PAYMENTS_STRIPE_KEY"].Description)
+	}
+	if !reflect.DeepEqual(schema.Properties["PAYMENTS_CURRENCY"].Enum, []string{"IDR", "USD"}) {
+		t.Errorf("enum = %v", schema.Properties["PAYMENTS_CURRENCY"].Enum)
+	}
+	if _, ok := schema.Properties["PAYMENTS_INTERNAL"]; ok {
+		t.Error("env:\"-\" field should be skipped")
+	}
+	if !reflect.DeepEqual(schema.Required, []string{"DB_NAME", "PAYMENTS_STRIPE_KEY", "PAYMENTS_WEBHOOK_SECRET"}) {
+		t.Errorf("required = %v", schema.Required)
+	}
+}
+
+func TestGenerateConfigSchema_KeyConflict(t *testing.T) {
+	type dbOverrides struct {
+		Port int `default:"6432"`
+	}
+	cfg := &Config{}
+	var overrides dbOverrides
+	if err := cfg.RegisterSection("db", &overrides); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := GenerateConfigSchema(cfg, ""); err == nil {
+		t.Error("section key colliding with DB_PORT should fail")
+	}
+}
+
+func TestFormatConfigDuration(t *testing.T) {
+	tests := map[time.Duration]string{
+		168 * time.Hour:                "168h",
+		90 * time.Minute:               "1h30m",
+		2 * time.Minute:                "2m",
+		30 * time.Second:               "30s",
+		1500 * time.Millisecond:        "1.5s",
+		time.Hour + 30*time.Second:     "1h0m30s",
+		10*time.Minute + 5*time.Second: "10m5s",
+	}
+	for d, want := range tests {
+		if got := formatConfigDuration(d); got != want {
+			t.Errorf("formatConfigDuration(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
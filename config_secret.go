@@ -0,0 +1,472 @@
+package dim
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+)
+
+// ErrSecretNotFound dikembalikan resolver ketika secret atau key di dalamnya tidak ada.
+var ErrSecretNotFound = errors.New("secret not found")
+
+// secretResolveTimeout membatasi total waktu resolusi secret saat konfigurasi dimuat.
+const secretResolveTimeout = 30 * time.Second
+
+// SecretRef adalah referensi secret yang sudah di-parse, misal "vault://secret/jwt#private_key"
+// menjadi Scheme "vault", Path "secret/jwt", dan Key "private_key".
+type SecretRef struct {
+	Scheme string
+	Path   string
+	// Key adalah field di dalam secret berformat JSON/object (opsional, setelah "#").
+	Key string
+}
+
+// String mengembalikan referensi dalam bentuk aslinya.
+func (r SecretRef) String() string {
+	if r.Key != "" {
+		return r.Scheme + "://" + r.Path + "#" + r.Key
+	}
+	return r.Scheme + "://" + r.Path
+}
+
+// SecretResolver mengambil nilai secret dari backend eksternal (Vault, AWS Secrets Manager, file).
+type SecretResolver interface {
+	ResolveSecret(ctx context.Context, ref SecretRef) (string, error)
+}
+
+// SecretResolverFunc adalah adapter fungsi untuk SecretResolver.
+type SecretResolverFunc func(ctx context.Context, ref SecretRef) (string, error)
+
+// ResolveSecret memanggil f(ctx, ref).
+func (f SecretResolverFunc) ResolveSecret(ctx context.Context, ref SecretRef) (string, error) {
+	return f(ctx, ref)
+}
+
+var (
+	secretResolversMu sync.RWMutex
+	secretResolvers   = map[string]SecretResolver{
+		"file":   FileSecretResolver{},
+		"vault":  NewVaultSecretResolver(VaultConfig{}),
+		"aws-sm": NewAWSSecretsManagerResolver(AWSSecretsManagerConfig{}),
+	}
+)
+
+// RegisterSecretResolver mendaftarkan (atau mengganti) resolver untuk sebuah scheme.
+// Resolver bawaan: "file", "vault" (VAULT_ADDR/VAULT_TOKEN), dan "aws-sm" (credential chain AWS default).
+// Sebaiknya dipanggil saat startup sebelum LoadConfig.
+//
+// Parameters:
+//   - scheme: scheme referensi tanpa "://", misal "vault" atau "gcp-sm"
+//   - resolver: implementasi SecretResolver
+//
+// Example:
+//
+//	// Vault dengan konfigurasi eksplisit
+//	dim.RegisterSecretResolver("vault", dim.NewVaultSecretResolver(dim.VaultConfig{
+//	    Address: "https://vault.internal:8200",
+//	    Token:   os.Getenv("VAULT_TOKEN"),
+//	}))
+//
+//	// Backend sendiri
+//	dim.RegisterSecretResolver("gcp-sm", dim.SecretResolverFunc(func(ctx context.Context, ref dim.SecretRef) (string, error) {
+//	    return gcpClient.Access(ctx, ref.Path)
+//	}))
+func RegisterSecretResolver(scheme string, resolver SecretResolver) {
+	secretResolversMu.Lock()
+	defer secretResolversMu.Unlock()
+	secretResolvers[strings.ToLower(scheme)] = resolver
+}
+
+// ParseSecretRef mem-parse nilai berbentuk "scheme://path#key". Mengembalikan false jika nilai
+// bukan referensi atau scheme-nya tidak memiliki resolver terdaftar, sehingga nilai biasa
+// seperti URL http(s) tidak pernah dianggap secret.
+func ParseSecretRef(value string) (SecretRef, bool) {
+	scheme, rest, ok := strings.Cut(strings.TrimSpace(value), "://")
+	if !ok || scheme == "" || rest == "" {
+		return SecretRef{}, false
+	}
+	scheme = strings.ToLower(scheme)
+
+	secretResolversMu.RLock()
+	_, registered := secretResolvers[scheme]
+	secretResolversMu.RUnlock()
+	if !registered {
+		return SecretRef{}, false
+	}
+
+	ref := SecretRef{Scheme: scheme, Path: rest}
+	if idx := strings.LastIndex(rest, "#"); idx >= 0 {
+		ref.Path, ref.Key = rest[:idx], rest[idx+1:]
+	}
+	if ref.Path == "" {
+		return SecretRef{}, false
+	}
+	return ref, true
+}
+
+// ResolveSecret mengembalikan nilai secret jika value adalah referensi dengan resolver terdaftar,
+// atau value apa adanya jika bukan.
+//
+// Parameters:
+//   - ctx: context untuk timeout/cancellation request ke backend
+//   - value: nilai konfigurasi, misal "aws-sm://prod/db#password"
+//
+// Returns:
+//   - string: nilai secret atau value asli
+//   - error: jika resolver gagal; pesan error tidak pernah berisi nilai secret
+func ResolveSecret(ctx context.Context, value string) (string, error) {
+	ref, ok := ParseSecretRef(value)
+	if !ok {
+		return value, nil
+	}
+
+	secretResolversMu.RLock()
+	resolver := secretResolvers[ref.Scheme]
+	secretResolversMu.RUnlock()
+
+	secret, err := resolver.ResolveSecret(ctx, ref)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve secret %s: %w", ref, err)
+	}
+	return secret, nil
+}
+
+// secretConfigSource membungkus configSource dan meresolusi referensi secret.
+// Hasil di-cache per nilai agar referensi yang sama hanya diambil sekali per load;
+// error pertama disimpan dan diperiksa setelah semua bagian konfigurasi dimuat.
+type secretConfigSource struct {
+	src   configSource
+	ctx   context.Context
+	cache map[string]string
+	err   error
+}
+
+func newSecretConfigSource(ctx context.Context, src configSource) *secretConfigSource {
+	return &secretConfigSource{src: src, ctx: ctx, cache: make(map[string]string)}
+}
+
+func (s *secretConfigSource) get(key string) string {
+	value := s.src(key)
+	if _, ok := ParseSecretRef(value); !ok {
+		return value
+	}
+	if secret, ok := s.cache[value]; ok {
+		return secret
+	}
+
+	secret, err := ResolveSecret(s.ctx, value)
+	if err != nil {
+		if s.err == nil {
+			s.err = fmt.Errorf("%s: %w", key, err)
+		}
+		return ""
+	}
+	s.cache[value] = secret
+	return secret
+}
+
+// secretField mengembalikan seluruh secret jika key kosong, atau field key dari secret berformat
+// JSON object. Field non-string di-encode sebagai JSON.
+func secretField(secret string, key string) (string, error) {
+	if key == "" {
+		return secret, nil
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(secret), &fields); err != nil {
+		return "", fmt.Errorf("secret is not a JSON object, cannot read key %q", key)
+	}
+	return secretMapField(fields, key)
+}
+
+func secretMapField(fields map[string]json.RawMessage, key string) (string, error) {
+	raw, ok := fields[key]
+	if !ok {
+		return "", fmt.Errorf("%w: key %q", ErrSecretNotFound, key)
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s, nil
+	}
+	return string(raw), nil
+}
+
+// FileSecretResolver membaca secret dari file, misal Docker/Kubernetes secret yang di-mount:
+// "file:///run/secrets/db_password" (absolut) atau "file://secrets/db_password" (relatif).
+// Newline di akhir file dibuang. Dengan "#key", file di-parse sebagai JSON object.
+type FileSecretResolver struct{}
+
+// ResolveSecret membaca file ref.Path.
+func (FileSecretResolver) ResolveSecret(ctx context.Context, ref SecretRef) (string, error) {
+	data, err := os.ReadFile(ref.Path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("%w: %s", ErrSecretNotFound, ref.Path)
+		}
+		return "", err
+	}
+	return secretField(strings.TrimRight(string(data), "\r\n"), ref.Key)
+}
+
+// VaultConfig mengatur VaultSecretResolver. Field kosong dibaca dari environment saat resolusi.
+type VaultConfig struct {
+	// Address adalah URL server Vault (default: VAULT_ADDR).
+	Address string
+	// Token adalah token Vault (default: VAULT_TOKEN).
+	Token string
+	// Namespace adalah namespace Vault Enterprise (default: VAULT_NAMESPACE, opsional).
+	Namespace string
+	// KVVersion adalah versi secrets engine KV: 1 atau 2 (default: 2).
+	KVVersion int
+	// HTTPClient dipakai untuk request ke Vault (default: client dengan timeout 10 detik).
+	HTTPClient *http.Client
+}
+
+// VaultSecretResolver membaca secret dari secrets engine KV HashiCorp Vault melalui HTTP API.
+// Referensi "vault://secret/app/jwt#private_key" membaca field private_key dari path app/jwt
+// di mount "secret". Tanpa "#key", secret harus berisi tepat satu field.
+type VaultSecretResolver struct {
+	config VaultConfig
+}
+
+// NewVaultSecretResolver membuat resolver Vault.
+func NewVaultSecretResolver(config VaultConfig) *VaultSecretResolver {
+	if config.KVVersion == 0 {
+		config.KVVersion = 2
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &VaultSecretResolver{config: config}
+}
+
+// ResolveSecret membaca secret dari Vault.
+func (v *VaultSecretResolver) ResolveSecret(ctx context.Context, ref SecretRef) (string, error) {
+	address := v.config.Address
+	if address == "" {
+		address = os.Getenv("VAULT_ADDR")
+	}
+	token := v.config.Token
+	if token == "" {
+		token = os.Getenv("VAULT_TOKEN")
+	}
+	namespace := v.config.Namespace
+	if namespace == "" {
+		namespace = os.Getenv("VAULT_NAMESPACE")
+	}
+	if address == "" || token == "" {
+		return "", errors.New("vault address and token are required (VAULT_ADDR, VAULT_TOKEN)")
+	}
+
+	mount, path, ok := strings.Cut(strings.Trim(ref.Path, "/"), "/")
+	if !ok || path == "" {
+		return "", fmt.Errorf("vault reference must be mount/path, got %q", ref.Path)
+	}
+	endpoint := strings.TrimRight(address, "/") + "/v1/" + mount + "/" + path
+	if v.config.KVVersion == 2 {
+		endpoint = strings.TrimRight(address, "/") + "/v1/" + mount + "/data/" + path
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if namespace != "" {
+		req.Header.Set("X-Vault-Namespace", namespace)
+	}
+
+	resp, err := v.config.HTTPClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("vault request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", fmt.Errorf("%w: %s", ErrSecretNotFound, ref.Path)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("vault returned status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("invalid vault response: %w", err)
+	}
+	data := body.Data
+	if v.config.KVVersion == 2 {
+		var nested struct {
+			Data json.RawMessage `json:"data"`
+		}
+		if err := json.Unmarshal(body.Data, &nested); err != nil {
+			return "", fmt.Errorf("invalid vault response: %w", err)
+		}
+		data = nested.Data
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil || fields == nil {
+		return "", fmt.Errorf("%w: %s", ErrSecretNotFound, ref.Path)
+	}
+	if ref.Key == "" {
+		if len(fields) != 1 {
+			return "", fmt.Errorf("vault secret %s has %d fields, specify one with #key", ref.Path, len(fields))
+		}
+		for key := range fields {
+			return secretMapField(fields, key)
+		}
+	}
+	return secretMapField(fields, ref.Key)
+}
+
+// AWSSecretsManagerConfig mengatur AWSSecretsManagerResolver. Field kosong diambil dari
+// konfigurasi AWS default (environment, shared config, IAM role) saat resolusi pertama.
+type AWSSecretsManagerConfig struct {
+	// Region AWS (default: dari ARN secret, lalu AWS_REGION/konfigurasi default).
+	Region string
+	// Credentials (default: credential chain AWS default).
+	Credentials aws.CredentialsProvider
+	// Endpoint meng-override URL service, misal untuk LocalStack (opsional).
+	Endpoint string
+	// HTTPClient dipakai untuk request ke AWS (default: client dengan timeout 10 detik).
+	HTTPClient *http.Client
+}
+
+// AWSSecretsManagerResolver membaca secret dari AWS Secrets Manager (GetSecretValue).
+// Referensi "aws-sm://prod/db#password" membaca field password dari secret JSON "prod/db";
+// tanpa "#key" seluruh SecretString dikembalikan. ARN lengkap juga didukung.
+type AWSSecretsManagerResolver struct {
+	config AWSSecretsManagerConfig
+	signer *v4.Signer
+
+	once    sync.Once
+	loadErr error
+}
+
+// NewAWSSecretsManagerResolver membuat resolver AWS Secrets Manager.
+func NewAWSSecretsManagerResolver(config AWSSecretsManagerConfig) *AWSSecretsManagerResolver {
+	if config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &AWSSecretsManagerResolver{config: config, signer: v4.NewSigner()}
+}
+
+// loadDefaults melengkapi region dan credentials dari konfigurasi AWS default (sekali).
+func (a *AWSSecretsManagerResolver) loadDefaults(ctx context.Context) error {
+	a.once.Do(func() {
+		if a.config.Credentials != nil && a.config.Region != "" {
+			return
+		}
+		cfg, err := awsconfig.LoadDefaultConfig(ctx)
+		if err != nil {
+			a.loadErr = fmt.Errorf("failed to load AWS config: %w", err)
+			return
+		}
+		if a.config.Credentials == nil {
+			a.config.Credentials = cfg.Credentials
+		}
+		if a.config.Region == "" {
+			a.config.Region = cfg.Region
+		}
+	})
+	return a.loadErr
+}
+
+// ResolveSecret memanggil GetSecretValue untuk ref.Path.
+func (a *AWSSecretsManagerResolver) ResolveSecret(ctx context.Context, ref SecretRef) (string, error) {
+	if err := a.loadDefaults(ctx); err != nil {
+		return "", err
+	}
+
+	region := a.config.Region
+	// arn:aws:secretsmanager:<region>:<account>:secret:<name>
+	if parts := strings.Split(ref.Path, ":"); len(parts) >= 7 && parts[0] == "arn" {
+		region = parts[3]
+	}
+	if region == "" {
+		return "", errors.New("AWS region is required for Secrets Manager (AWS_REGION)")
+	}
+	if a.config.Credentials == nil {
+		return "", errors.New("AWS credentials are required for Secrets Manager")
+	}
+
+	endpoint := a.config.Endpoint
+	if endpoint == "" {
+		endpoint = "https://secretsmanager." + region + ".amazonaws.com"
+	}
+
+	payload, _ := json.Marshal(map[string]string{"SecretId": ref.Path})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+
+	creds, err := a.config.Credentials.Retrieve(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to retrieve AWS credentials: %w", err)
+	}
+	hash := sha256.Sum256(payload)
+	if err := a.signer.SignHTTP(ctx, creds, req, hex.EncodeToString(hash[:]), "secretsmanager", region, time.Now()); err != nil {
+		return "", fmt.Errorf("failed to sign AWS request: %w", err)
+	}
+
+	resp, err := a.config.HTTPClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("secrets manager request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var awsErr struct {
+			Type string `json:"__type"`
+		}
+		json.Unmarshal(body, &awsErr)
+		if strings.HasSuffix(awsErr.Type, "ResourceNotFoundException") {
+			return "", fmt.Errorf("%w: %s", ErrSecretNotFound, ref.Path)
+		}
+		return "", fmt.Errorf("secrets manager returned status %d (%s)", resp.StatusCode, awsErr.Type)
+	}
+
+	var out struct {
+		SecretString *string `json:"SecretString"`
+		SecretBinary *string `json:"SecretBinary"`
+	}
+	if err := json.Unmarshal(body, &out); err != nil {
+		return "", fmt.Errorf("invalid secrets manager response: %w", err)
+	}
+
+	var secret string
+	switch {
+	case out.SecretString != nil:
+		secret = *out.SecretString
+	case out.SecretBinary != nil:
+		decoded, err := base64.StdEncoding.DecodeString(*out.SecretBinary)
+		if err != nil {
+			return "", fmt.Errorf("invalid SecretBinary: %w", err)
+		}
+		secret = string(decoded)
+	}
+	return secretField(secret, ref.Key)
+}
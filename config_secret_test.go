@@ -0,0 +1,291 @@
+package dim
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/credentials"
+)
+
+// registerTestSecretResolver mendaftarkan resolver sementara dan menghapusnya setelah test.
+func registerTestSecretResolver(t *testing.T, scheme string, resolver SecretResolver) {
+	t.Helper()
+	secretResolversMu.RLock()
+	previous, existed := secretResolvers[scheme]
+	secretResolversMu.RUnlock()
+
+	RegisterSecretResolver(scheme, resolver)
+	t.Cleanup(func() {
+		secretResolversMu.Lock()
+		defer secretResolversMu.Unlock()
+		if existed {
+			secretResolvers[scheme] = previous
+		} else {
+			delete(secretResolvers, scheme)
+		}
+	})
+}
+
+func TestParseSecretRef(t *testing.T) {
+	tests := []struct {
+		value  string
+		want   SecretRef
+		wantOK bool
+	}{
+		{"vault://secret/jwt#private_key", SecretRef{Scheme: "vault", Path: "secret/jwt", Key: "private_key"}, true},
+		{"aws-sm://prod/db-password", SecretRef{Scheme: "aws-sm", Path: "prod/db-password"}, true},
+		{"file:///run/secrets/db", SecretRef{Scheme: "file", Path: "/run/secrets/db"}, true},
+		{"FILE://secrets/db#password", SecretRef{Scheme: "file", Path: "secrets/db", Key: "password"}, true},
+		{"https://example.com/jwks.json", SecretRef{}, false},
+		{"postgres://user@localhost/db", SecretRef{}, false},
+		{"plain-value", SecretRef{}, false},
+		{"vault://", SecretRef{}, false},
+		{"vault://#key", SecretRef{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, ok := ParseSecretRef(tt.value)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("ParseSecretRef(%q) = %+v, %v; want %+v, %v", tt.value, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+
+	ref := SecretRef{Scheme: "vault", Path: "secret/jwt", Key: "private_key"}
+	if ref.String() != "vault://secret/jwt#private_key" {
+		t.Errorf("String() = %q", ref.String())
+	}
+}
+
+func TestFileSecretResolver(t *testing.T) {
+	dir := t.TempDir()
+	plain := filepath.Join(dir, "db_password")
+	jsonFile := filepath.Join(dir, "db.json")
+	os.WriteFile(plain, []byte("s3cr3t\n"), 0600)
+	os.WriteFile(jsonFile, []byte(`{"password":"from-json","port":5432}`), 0600)
+
+	tests := []struct {
+		value   string
+		want    string
+		wantErr error
+	}{
+		{"file://" + plain, "s3cr3t", nil},
+		{"file://" + jsonFile + "#password", "from-json", nil},
+		{"file://" + jsonFile + "#port", "5432", nil},
+		{"file://" + jsonFile + "#missing", "", ErrSecretNotFound},
+		{"file://" + filepath.Join(dir, "nope"), "", ErrSecretNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ResolveSecret(context.Background(), tt.value)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("ResolveSecret() = %q, %v; want %q", got, err, tt.want)
+			}
+		})
+	}
+
+	if got, _ := ResolveSecret(context.Background(), "not-a-ref"); got != "not-a-ref" {
+		t.Errorf("plain value should be returned as is, got %q", got)
+	}
+}
+
+func TestLoadConfig_ResolvesSecrets(t *testing.T) {
+	calls := map[string]int{}
+	registerTestSecretResolver(t, "test", SecretResolverFunc(func(ctx context.Context, ref SecretRef) (string, error) {
+		calls[ref.String()]++
+		switch ref.String() {
+		case "test://app/jwt#secret":
+			return "resolved-jwt-secret-that-is-long-enough", nil
+		case "test://app/db#password":
+			return "resolved-db-password", nil
+		}
+		return "", ErrSecretNotFound
+	}))
+
+	values := map[string]string{
+		"JWT_SECRET":     "test://app/jwt#secret",
+		"DB_DRIVER":      "sqlite",
+		"DB_NAME":        "app.db",
+		"DB_PASSWORD":    "test://app/db#password",
+		"CSRF_ENABLED":   "false",
+		"SERVER_PORT":    "8080",
+		"JWT_JWKS_URL":   "https://example.com/jwks.json",
+		"SESSION_SECRET": "test://app/jwt#secret",
+	}
+	cfg, err := loadConfig(func(key string) string { return values[key] })
+	if err != nil {
+		t.Fatalf("loadConfig() error = %v", err)
+	}
+	if cfg.JWT.HMACSecret != "resolved-jwt-secret-that-is-long-enough" {
+		t.Errorf("JWT.HMACSecret = %q", cfg.JWT.HMACSecret)
+	}
+	if cfg.Database.Password != "resolved-db-password" {
+		t.Errorf("Database.Password = %q", cfg.Database.Password)
+	}
+	if cfg.JWT.JWKSURL != "https://example.com/jwks.json" {
+		t.Errorf("non-secret URL should be untouched, got %q", cfg.JWT.JWKSURL)
+	}
+	if calls["test://app/jwt#secret"] != 1 {
+		t.Errorf("secret should be resolved once per load, got %d calls", calls["test://app/jwt#secret"])
+	}
+}
+
+func TestLoadConfig_SecretError(t *testing.T) {
+	registerTestSecretResolver(t, "test", SecretResolverFunc(func(ctx context.Context, ref SecretRef) (string, error) {
+		return "", errors.New("backend unavailable")
+	}))
+
+	values := map[string]string{
+		"JWT_SECRET": "test://app/jwt#secret",
+		"DB_DRIVER":  "sqlite",
+		"DB_NAME":    "app.db",
+	}
+	_, err := loadConfig(func(key string) string { return values[key] })
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !strings.HasPrefix(err.Error(), "JWT_SECRET: failed to resolve secret test://app/jwt#secret") || !strings.Contains(err.Error(), "backend unavailable") {
+		t.Errorf("error = %v", err)
+	}
+}
+
+func TestVaultSecretResolver(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "root" || r.Header.Get("X-Vault-Namespace") != "team" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/app/jwt":
+			w.Write([]byte(`{"data":{"data":{"private_key":"PEM","kid":"k1"},"metadata":{"version":3}}}`))
+		case "/v1/secret/data/app/single":
+			w.Write([]byte(`{"data":{"data":{"password":"only"}}}`))
+		case "/v1/kv/app/db":
+			w.Write([]byte(`{"data":{"password":"v1-password"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	v2 := NewVaultSecretResolver(VaultConfig{Address: server.URL, Token: "root", Namespace: "team"})
+	v1 := NewVaultSecretResolver(VaultConfig{Address: server.URL, Token: "root", Namespace: "team", KVVersion: 1})
+
+	tests := []struct {
+		name     string
+		resolver SecretResolver
+		ref      SecretRef
+		want     string
+		wantErr  string
+	}{
+		{"kv2 key", v2, SecretRef{Scheme: "vault", Path: "secret/app/jwt", Key: "private_key"}, "PEM", ""},
+		{"kv2 single field", v2, SecretRef{Scheme: "vault", Path: "secret/app/single"}, "only", ""},
+		{"kv2 ambiguous", v2, SecretRef{Scheme: "vault", Path: "secret/app/jwt"}, "", "specify one with #key"},
+		{"kv2 missing key", v2, SecretRef{Scheme: "vault", Path: "secret/app/jwt", Key: "nope"}, "", "secret not found"},
+		{"kv2 missing path", v2, SecretRef{Scheme: "vault", Path: "secret/app/nope", Key: "x"}, "", "secret not found"},
+		{"kv1", v1, SecretRef{Scheme: "vault", Path: "kv/app/db", Key: "password"}, "v1-password", ""},
+		{"no mount", v2, SecretRef{Scheme: "vault", Path: "jwt", Key: "x"}, "", "mount/path"},
+		{"forbidden", NewVaultSecretResolver(VaultConfig{Address: server.URL, Token: "bad"}), SecretRef{Scheme: "vault", Path: "secret/app/jwt", Key: "kid"}, "", "status 403"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.resolver.ResolveSecret(context.Background(), tt.ref)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("ResolveSecret() = %q, %v; want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestVaultSecretResolver_EnvDefaults(t *testing.T) {
+	t.Setenv("VAULT_ADDR", "")
+	t.Setenv("VAULT_TOKEN", "")
+
+	_, err := NewVaultSecretResolver(VaultConfig{}).ResolveSecret(context.Background(), SecretRef{Scheme: "vault", Path: "secret/x"})
+	if err == nil || !strings.Contains(err.Error(), "VAULT_ADDR") {
+		t.Errorf("error = %v, want missing VAULT_ADDR", err)
+	}
+}
+
+func TestAWSSecretsManagerResolver(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Amz-Target") != "secretsmanager.GetSecretValue" ||
+			!strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/") ||
+			!strings.Contains(r.Header.Get("Authorization"), "/ap-southeast-1/secretsmanager/aws4_request") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		var req struct{ SecretId string }
+		json.Unmarshal(body, &req)
+
+		switch req.SecretId {
+		case "prod/db":
+			w.Write([]byte(`{"Name":"prod/db","SecretString":"{\"password\":\"aws-password\",\"port\":5432}"}`))
+		case "prod/api-key":
+			w.Write([]byte(`{"Name":"prod/api-key","SecretString":"plain-key"}`))
+		case "prod/binary":
+			w.Write([]byte(`{"Name":"prod/binary","SecretBinary":"YmluYXJ5LXNlY3JldA=="}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"__type":"ResourceNotFoundException","message":"Secrets Manager can't find the specified secret."}`))
+		}
+	}))
+	defer server.Close()
+
+	resolver := NewAWSSecretsManagerResolver(AWSSecretsManagerConfig{
+		Region:      "ap-southeast-1",
+		Endpoint:    server.URL,
+		Credentials: credentials.NewStaticCredentialsProvider("AKID", "SECRET", ""),
+	})
+
+	tests := []struct {
+		ref     SecretRef
+		want    string
+		wantErr error
+	}{
+		{SecretRef{Scheme: "aws-sm", Path: "prod/db", Key: "password"}, "aws-password", nil},
+		{SecretRef{Scheme: "aws-sm", Path: "prod/db", Key: "port"}, "5432", nil},
+		{SecretRef{Scheme: "aws-sm", Path: "prod/api-key"}, "plain-key", nil},
+		{SecretRef{Scheme: "aws-sm", Path: "prod/binary"}, "binary-secret", nil},
+		{SecretRef{Scheme: "aws-sm", Path: "prod/missing"}, "", ErrSecretNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref.String(), func(t *testing.T) {
+			got, err := resolver.ResolveSecret(context.Background(), tt.ref)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("ResolveSecret() = %q, %v; want %q", got, err, tt.want)
+			}
+		})
+	}
+}
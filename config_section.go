@@ -0,0 +1,262 @@
+package dim
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// configSection adalah section konfigurasi aplikasi yang didaftarkan lewat Config.RegisterSection.
+type configSection struct {
+	name   string
+	prefix string
+	target any
+}
+
+// RegisterSection memuat struct konfigurasi milik aplikasi dari sumber yang sama dengan Config
+// (environment, .env, file LoadConfigFrom, dan overrides), memvalidasinya, lalu menyimpannya
+// sehingga ikut divalidasi setiap kali Config.Validate dipanggil dan dimuat ulang oleh ConfigWatcher.
+//
+// Nama environment variable dibentuk dari prefix nama section dan nama field dalam UPPER_SNAKE_CASE:
+// section "payments" dengan field StripeKey dibaca dari PAYMENTS_STRIPE_KEY (atau
+// `payments: {stripe_key: ...}` di file konfigurasi). Struct bersarang menambah prefix nama field-nya.
+//
+// Tag yang didukung:
+//   - env:"NAME": nama setelah prefix (default: nama field dalam UPPER_SNAKE_CASE); "-" melewati field
+//   - default:"value": nilai jika variable kosong
+//   - validate:"...": aturan ValidateStruct, misal "required,min=1"
+//
+// Tipe field: string, bool, int/uint/float, time.Duration, slice (dipisah koma), pointer,
+// dan tipe yang mengimplementasikan encoding.TextUnmarshaler. Referensi secret (vault://, aws-sm://,
+// file://) diresolusi seperti konfigurasi bawaan. Jika target memiliki method Validate() error,
+// method tersebut dipanggil setelah validasi tag.
+//
+// Parameters:
+//   - name: nama section, menjadi prefix environment variable
+//   - target: pointer ke struct yang akan diisi
+//
+// Returns:
+//   - error: jika nama tidak valid atau sudah terdaftar, target bukan pointer ke struct,
+//     nilai tidak dapat di-parse, atau validasi gagal
+//
+// Example:
+//
+//	type PaymentsConfig struct {
+//	    StripeKey  string        `validate:"required"`
+//	    Currency   string        `default:"IDR" validate:"oneof=IDR|USD"`
+//	    Timeout    time.Duration `default:"10s"`
+//	    WebhookIPs []string      `env:"WEBHOOK_ALLOWED_IPS"`
+//	}
+//
+//	cfg, _ := dim.LoadConfig()
+//	var payments PaymentsConfig
+//	if err := cfg.RegisterSection("payments", &payments); err != nil {
+//	    log.Fatal(err) // misal: config section payments: StripeKey is required
+//	}
+func (c *Config) RegisterSection(name string, target any) error {
+	if name == "" {
+		return fmt.Errorf("config section name is required")
+	}
+	rv := reflect.ValueOf(target)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("config section %s: target must be a non-nil pointer to struct, got %T", name, target)
+	}
+	if _, ok := c.Section(name); ok {
+		return fmt.Errorf("config section already registered: %s", name)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), secretResolveTimeout)
+	defer cancel()
+
+	section := &configSection{name: name, prefix: configSectionPrefix(name), target: target}
+	secrets := newSecretConfigSource(ctx, c.rawSource())
+	err := section.load(secrets.get)
+	if secrets.err != nil {
+		return fmt.Errorf("config section %s: %w", name, secrets.err)
+	}
+	if err != nil {
+		return fmt.Errorf("config section %s: %w", name, err)
+	}
+	if err := section.validate(); err != nil {
+		return err
+	}
+
+	c.sections = append(c.sections, section)
+	return nil
+}
+
+// Section mengembalikan target section yang terdaftar berdasarkan nama.
+//
+// Returns:
+//   - any: pointer yang diberikan ke RegisterSection
+//   - bool: false jika section tidak terdaftar
+func (c *Config) Section(name string) (any, bool) {
+	for _, section := range c.sections {
+		if section.name == name {
+			return section.target, true
+		}
+	}
+	return nil, false
+}
+
+// ConfigSection mengembalikan section bertipe T dari cfg. Berguna bersama ConfigWatcher,
+// karena setiap reload membuat instance section baru.
+//
+// Example:
+//
+//	watcher.OnChange(func(old, new *dim.Config) {
+//	    payments, _ := dim.ConfigSection[PaymentsConfig](new, "payments")
+//	    client.SetTimeout(payments.Timeout)
+//	})
+func ConfigSection[T any](cfg *Config, name string) (*T, bool) {
+	target, ok := cfg.Section(name)
+	if !ok {
+		return nil, false
+	}
+	typed, ok := target.(*T)
+	return typed, ok
+}
+
+// reloadSections mendaftarkan ulang section dari old ke c dengan instance baru bertipe sama.
+func (c *Config) reloadSections(old *Config) error {
+	for _, section := range old.sections {
+		target := reflect.New(reflect.TypeOf(section.target).Elem()).Interface()
+		if err := c.RegisterSection(section.name, target); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateSections menjalankan validasi semua section yang terdaftar.
+func (c *Config) validateSections() error {
+	for _, section := range c.sections {
+		if err := section.validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rawSource mengembalikan sumber yang dipakai saat Config dimuat, atau environment
+// jika Config dibuat manual.
+func (c *Config) rawSource() configSource {
+	if c.source == nil {
+		return envConfigSource
+	}
+	return c.source
+}
+
+func (s *configSection) load(src configSource) error {
+	return loadConfigStruct(src, s.prefix, reflect.ValueOf(s.target).Elem())
+}
+
+func (s *configSection) validate() error {
+	v := NewValidator().WithLocale("en").Struct(s.target)
+	if !v.IsValid() {
+		messages := v.Errors()
+		sort.Strings(messages)
+		return fmt.Errorf("config section %s: %s", s.name, strings.Join(messages, "; "))
+	}
+	if validatable, ok := s.target.(interface{ Validate() error }); ok {
+		if err := validatable.Validate(); err != nil {
+			return fmt.Errorf("config section %s: %w", s.name, err)
+		}
+	}
+	return nil
+}
+
+// loadConfigStruct mengisi field struct rv dari src dengan prefix environment variable.
+func loadConfigStruct(src configSource, prefix string, rv reflect.Value) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		tag := field.Tag.Get("env")
+		if tag == "-" {
+			continue
+		}
+		fv := rv.Field(i)
+
+		if field.Anonymous && tag == "" && field.Type.Kind() == reflect.Struct {
+			if err := loadConfigStruct(src, prefix, fv); err != nil {
+				return err
+			}
+			continue
+		}
+
+		key := tag
+		if key == "" {
+			key = configEnvName(field.Name)
+		}
+		key = prefix + key
+
+		if field.Type.Kind() == reflect.Struct && !isLeafStruct(field.Type) {
+			if err := loadConfigStruct(src, key+"_", fv); err != nil {
+				return err
+			}
+			continue
+		}
+
+		raw := src.get(key)
+		if raw == "" {
+			raw = field.Tag.Get("default")
+		}
+		if raw == "" {
+			continue
+		}
+		if err := setConfigField(fv, raw); err != nil {
+			return fmt.Errorf("invalid %s: cannot parse %q as %s", key, raw, field.Type)
+		}
+	}
+	return nil
+}
+
+// setConfigField mengisi field dari nilai string environment. Boolean mengikuti ParseEnvBool
+// dan slice dipisah koma.
+func setConfigField(fv reflect.Value, raw string) error {
+	switch {
+	case fv.Type() == reflect.TypeOf(time.Duration(0)):
+		d, err := ParseEnvDuration(raw)
+		if err != nil {
+			return err
+		}
+		fv.SetInt(int64(d))
+		return nil
+	case fv.Kind() == reflect.Bool:
+		fv.SetBool(ParseEnvBool(raw))
+		return nil
+	}
+	return setFieldFromStrings(fv, []string{raw})
+}
+
+// configSectionPrefix mengubah nama section menjadi prefix environment variable,
+// misal "payments" → "PAYMENTS_" dan "search-index" → "SEARCH_INDEX_".
+func configSectionPrefix(name string) string {
+	prefix := strings.ToUpper(strings.NewReplacer("-", "_", ".", "_", " ", "_").Replace(name))
+	return strings.TrimSuffix(prefix, "_") + "_"
+}
+
+// configEnvName mengubah nama field Go menjadi UPPER_SNAKE_CASE,
+// misal "StripeKey" → "STRIPE_KEY" dan "APIBaseURL" → "API_BASE_URL".
+func configEnvName(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
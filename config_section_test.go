@@ -0,0 +1,243 @@
+package dim
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type testPaymentsConfig struct {
+	StripeKey  string        `validate:"required"`
+	Currency   string        `default:"IDR" validate:"oneof=IDR|USD"`
+	Timeout    time.Duration `default:"10s"`
+	Sandbox    bool
+	MaxRetries int      `default:"3"`
+	WebhookIPs []string `env:"WEBHOOK_ALLOWED_IPS"`
+	Internal   string   `env:"-"`
+	APIBaseURL string
+	Webhook    struct {
+		Secret string
+		Path   string `default:"/webhooks/stripe"`
+	}
+}
+
+type testSearchConfig struct {
+	Replicas int
+}
+
+func (c *testSearchConfig) Validate() error {
+	if c.Replicas > 5 {
+		return errors.New("REPLICAS must be at most 5")
+	}
+	return nil
+}
+
+func sectionTestConfig(values map[string]string) *Config {
+	return &Config{source: func(key string) string { return values[key] }}
+}
+
+func TestConfig_RegisterSection(t *testing.T) {
+	cfg := sectionTestConfig(map[string]string{
+		"PAYMENTS_STRIPE_KEY":           "sk_test",
+		"PAYMENTS_TIMEOUT":              "30s",
+		"PAYMENTS_SANDBOX":              "yes",
+		"PAYMENTS_WEBHOOK_ALLOWED_IPS":  "10.0.0.1,10.0.0.2",
+		"PAYMENTS_INTERNAL":             "ignored",
+		"PAYMENTS_API_BASE_URL":         "https://api.stripe.com",
+		"PAYMENTS_WEBHOOK_SECRET":       "whsec",
+		"PAYMENTS_UNRELATED_OTHER_PART": "x",
+	})
+
+	var payments testPaymentsConfig
+	if err := cfg.RegisterSection("payments", &payments); err != nil {
+		t.Fatalf("RegisterSection() error = %v", err)
+	}
+
+	if payments.StripeKey != "sk_test" || payments.Currency != "IDR" || payments.Timeout != 30*time.Second ||
+		!payments.Sandbox || payments.MaxRetries != 3 || payments.APIBaseURL != "https://api.stripe.com" {
+		t.Errorf("payments = %+v", payments)
+	}
+	if len(payments.WebhookIPs) != 2 || payments.WebhookIPs[1] != "10.0.0.2" {
+		t.Errorf("WebhookIPs = %v", payments.WebhookIPs)
+	}
+	if payments.Internal != "" {
+		t.Errorf("Internal = %q, want skipped", payments.Internal)
+	}
+	if payments.Webhook.Secret != "whsec" || payments.Webhook.Path != "/webhooks/stripe" {
+		t.Errorf("Webhook = %+v", payments.Webhook)
+	}
+
+	got, ok := ConfigSection[testPaymentsConfig](cfg, "payments")
+	if !ok || got != &payments {
+		t.Errorf("ConfigSection() = %p, %v; want %p", got, ok, &payments)
+	}
+	if _, ok := ConfigSection[testSearchConfig](cfg, "payments"); ok {
+		t.Error("ConfigSection() with wrong type should return false")
+	}
+	if _, ok := cfg.Section("missing"); ok {
+		t.Error("Section(missing) should return false")
+	}
+}
+
+func TestConfig_RegisterSectionErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  map[string]string
+		section string
+		target  any
+		wantErr string
+	}{
+		{"empty name", nil, "", &testSearchConfig{}, "name is required"},
+		{"not pointer", nil, "search", testSearchConfig{}, "pointer to struct"},
+		{"nil pointer", nil, "search", (*testSearchConfig)(nil), "pointer to struct"},
+		{"required", nil, "payments", &testPaymentsConfig{}, "config section payments: StripeKey"},
+		{"oneof", map[string]string{"PAYMENTS_STRIPE_KEY": "sk", "PAYMENTS_CURRENCY": "EUR"}, "payments", &testPaymentsConfig{}, "Currency"},
+		{"parse", map[string]string{"PAYMENTS_STRIPE_KEY": "sk", "PAYMENTS_MAX_RETRIES": "many"}, "payments", &testPaymentsConfig{}, `invalid PAYMENTS_MAX_RETRIES: cannot parse "many" as int`},
+		{"duration", map[string]string{"PAYMENTS_STRIPE_KEY": "sk", "PAYMENTS_TIMEOUT": "soon"}, "payments", &testPaymentsConfig{}, "invalid PAYMENTS_TIMEOUT"},
+		{"custom validate", map[string]string{"SEARCH_INDEX_REPLICAS": "9"}, "search-index", &testSearchConfig{}, "config section search-index: REPLICAS must be at most 5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := sectionTestConfig(tt.values)
+			err := cfg.RegisterSection(tt.section, tt.target)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("RegisterSection() error = %v, want containing %q", err, tt.wantErr)
+			}
+			if len(cfg.sections) != 0 {
+				t.Error("failed section should not be registered")
+			}
+		})
+	}
+}
+
+func TestConfig_RegisterSectionDuplicate(t *testing.T) {
+	cfg := sectionTestConfig(nil)
+	if err := cfg.RegisterSection("search", &testSearchConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.RegisterSection("search", &testSearchConfig{}); err == nil || !strings.Contains(err.Error(), "already registered") {
+		t.Errorf("RegisterSection() error = %v", err)
+	}
+}
+
+func TestConfig_RegisterSectionSecretRef(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stripe")
+	os.WriteFile(path, []byte("sk_from_file\n"), 0600)
+
+	cfg := sectionTestConfig(map[string]string{"PAYMENTS_STRIPE_KEY": "file://" + path})
+	var payments testPaymentsConfig
+	if err := cfg.RegisterSection("payments", &payments); err != nil {
+		t.Fatalf("RegisterSection() error = %v", err)
+	}
+	if payments.StripeKey != "sk_from_file" {
+		t.Errorf("StripeKey = %q", payments.StripeKey)
+	}
+
+	cfg = sectionTestConfig(map[string]string{"PAYMENTS_STRIPE_KEY": "file://" + path + ".missing"})
+	if err := cfg.RegisterSection("payments", &testPaymentsConfig{}); err == nil || !strings.Contains(err.Error(), "config section payments") {
+		t.Errorf("RegisterSection() error = %v", err)
+	}
+}
+
+func TestConfig_ValidateSections(t *testing.T) {
+	cfg := productionCORSConfig(CORSConfig{})
+	cfg.Server.Env = "development"
+	search := &testSearchConfig{Replicas: 2}
+	if err := cfg.RegisterSection("search", search); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	search.Replicas = 10
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "config section search") {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
+
+func TestLoadConfigFrom_RegisterSection(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	path := filepath.Join(dir, "config.yaml")
+	os.WriteFile(path, []byte("jwt:\n  secret: a-very-long-secret-for-testing-purposes-123\ndb:\n  driver: sqlite\n  name: app.db\npayments:\n  stripe_key: sk_yaml\n  currency: USD\n  webhook:\n    secret: whsec_yaml\n"), 0644)
+
+	cfg, err := LoadConfigFrom(path, map[string]string{"PAYMENTS_CURRENCY": "IDR"})
+	if err != nil {
+		t.Fatalf("LoadConfigFrom() error = %v", err)
+	}
+	var payments testPaymentsConfig
+	if err := cfg.RegisterSection("payments", &payments); err != nil {
+		t.Fatalf("RegisterSection() error = %v", err)
+	}
+	if payments.StripeKey != "sk_yaml" || payments.Currency != "IDR" || payments.Webhook.Secret != "whsec_yaml" {
+		t.Errorf("payments = %+v", payments)
+	}
+}
+
+func TestConfigWatcher_ReloadSections(t *testing.T) {
+	var replicas atomic.Int64
+	replicas.Store(1)
+	load := func() (*Config, error) {
+		return sectionTestConfig(map[string]string{"SEARCH_REPLICAS": strconv.FormatInt(replicas.Load(), 10)}), nil
+	}
+
+	w, err := NewConfigWatcher(load)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Current().RegisterSection("search", &testSearchConfig{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if changed, err := w.Reload(); changed || err != nil {
+		t.Fatalf("Reload() = %v, %v; want false, nil", changed, err)
+	}
+
+	replicas.Store(3)
+	var seen int
+	w.OnChange(func(old, new *Config) {
+		search, _ := ConfigSection[testSearchConfig](new, "search")
+		seen = search.Replicas
+	})
+	if changed, err := w.Reload(); !changed || err != nil {
+		t.Fatalf("Reload() = %v, %v; want true, nil", changed, err)
+	}
+	if seen != 3 {
+		t.Errorf("new section Replicas = %d, want 3", seen)
+	}
+
+	// Section yang tidak valid menggagalkan reload dan konfigurasi lama tetap dipakai
+	replicas.Store(9)
+	if _, err := w.Reload(); err == nil || !strings.Contains(err.Error(), "config section search") {
+		t.Errorf("Reload() error = %v", err)
+	}
+	if search, _ := ConfigSection[testSearchConfig](w.Current(), "search"); search.Replicas != 3 {
+		t.Errorf("current Replicas = %d, want 3", search.Replicas)
+	}
+}
+
+func TestConfigEnvName(t *testing.T) {
+	tests := map[string]string{
+		"StripeKey":    "STRIPE_KEY",
+		"APIBaseURL":   "API_BASE_URL",
+		"MaxRetries":   "MAX_RETRIES",
+		"HTTP2Enabled": "HTTP2_ENABLED",
+		"Timeout":      "TIMEOUT",
+	}
+	for in, want := range tests {
+		if got := configEnvName(in); got != want {
+			t.Errorf("configEnvName(%q) = %q, want %q", in, got, want)
+		}
+	}
+
+	if got := configSectionPrefix("search-index"); got != "SEARCH_INDEX_" {
+		t.Errorf("configSectionPrefix() = %q", got)
+	}
+}
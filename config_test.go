@@ -10,7 +10,7 @@ import (
 )
 
 func TestLoadServerConfig_Defaults(t *testing.T) {
-	cfg, err := loadServerConfig()
+	cfg, err := loadServerConfig(envConfigSource)
 	if err != nil {
 		t.Fatalf("loadServerConfig() with defaults failed: %v", err)
 	}
@@ -23,7 +23,7 @@ func TestLoadServerConfig_WithEnv(t *testing.T) {
 	os.Setenv("SERVER_PORT", "9000")
 	defer os.Unsetenv("SERVER_PORT")
 
-	cfg, err := loadServerConfig()
+	cfg, err := loadServerConfig(envConfigSource)
 	if err != nil {
 		t.Fatalf("loadServerConfig() with env failed: %v", err)
 	}
@@ -36,7 +36,7 @@ func TestLoadServerConfig_InvalidDurations(t *testing.T) {
 	t.Run("invalid write timeout", func(t *testing.T) {
 		os.Setenv("SERVER_WRITE_TIMEOUT", "abc")
 		defer os.Unsetenv("SERVER_WRITE_TIMEOUT")
-		_, err := loadServerConfig()
+		_, err := loadServerConfig(envConfigSource)
 		if err == nil {
 			t.Error("loadServerConfig() should have returned an error for invalid write timeout")
 		}
@@ -45,7 +45,7 @@ func TestLoadServerConfig_InvalidDurations(t *testing.T) {
 	t.Run("invalid read timeout", func(t *testing.T) {
 		os.Setenv("SERVER_READ_TIMEOUT", "abc")
 		defer os.Unsetenv("SERVER_READ_TIMEOUT")
-		_, err := loadServerConfig()
+		_, err := loadServerConfig(envConfigSource)
 		if err == nil {
 			t.Error("loadServerConfig() should have returned an error for invalid read timeout")
 		}
@@ -56,7 +56,7 @@ func TestLoadJWTConfig(t *testing.T) {
 	os.Setenv("JWT_SECRET", "test-secret")
 	defer os.Unsetenv("JWT_SECRET")
 
-	cfg, err := loadJWTConfig()
+	cfg, err := loadJWTConfig(envConfigSource)
 	if err != nil {
 		t.Fatalf("loadJWTConfig() failed: %v", err)
 	}
@@ -65,6 +65,47 @@ func TestLoadJWTConfig(t *testing.T) {
 	}
 }
 
+func TestLoadJWTConfig_IssuerAudienceLeeway(t *testing.T) {
+	t.Setenv("JWT_ISSUER", "https://auth.example.com")
+	t.Setenv("JWT_AUDIENCE", "api, admin")
+	t.Setenv("JWT_CLOCK_SKEW_LEEWAY", "30s")
+
+	cfg, err := loadJWTConfig(envConfigSource)
+	if err != nil {
+		t.Fatalf("loadJWTConfig() failed: %v", err)
+	}
+	if cfg.Issuer != "https://auth.example.com" || len(cfg.Audience) != 2 || cfg.Audience[1] != "admin" || cfg.ClockSkewLeeway != 30*time.Second {
+		t.Errorf("cfg = %+v", cfg)
+	}
+
+	t.Setenv("JWT_CLOCK_SKEW_LEEWAY", "-1s")
+	if _, err := loadJWTConfig(envConfigSource); err == nil {
+		t.Error("negative leeway should be rejected")
+	}
+}
+
+func TestLoadJWTConfig_SessionExpiry(t *testing.T) {
+	cfg, err := loadJWTConfig(envConfigSource)
+	if err != nil {
+		t.Fatalf("loadJWTConfig() failed: %v", err)
+	}
+	if cfg.RememberMeExpiry != 720*time.Hour || cfg.SessionMaxLifetime != 0 {
+		t.Errorf("defaults = %v, %v", cfg.RememberMeExpiry, cfg.SessionMaxLifetime)
+	}
+
+	t.Setenv("JWT_REMEMBER_ME_EXPIRY", "2160h")
+	t.Setenv("JWT_SESSION_MAX_LIFETIME", "4320h")
+	cfg, err = loadJWTConfig(envConfigSource)
+	if err != nil || cfg.RememberMeExpiry != 2160*time.Hour || cfg.SessionMaxLifetime != 4320*time.Hour {
+		t.Errorf("cfg = %v, %v, %v", cfg.RememberMeExpiry, cfg.SessionMaxLifetime, err)
+	}
+
+	t.Setenv("JWT_SESSION_MAX_LIFETIME", "-1h")
+	if _, err := loadJWTConfig(envConfigSource); err == nil {
+		t.Error("negative session max lifetime should be rejected")
+	}
+}
+
 func TestLoadDatabaseConfig(t *testing.T) {
 	os.Setenv("DB_WRITE_HOST", "localhost")
 	os.Setenv("DB_NAME", "testdb")
@@ -75,7 +116,7 @@ func TestLoadDatabaseConfig(t *testing.T) {
 		os.Unsetenv("DB_USER")
 	}()
 
-	cfg, err := loadDatabaseConfig()
+	cfg, err := loadDatabaseConfig(envConfigSource)
 	if err != nil {
 		t.Fatalf("loadDatabaseConfig() failed: %v", err)
 	}
@@ -84,11 +125,37 @@ func TestLoadDatabaseConfig(t *testing.T) {
 	}
 }
 
+func TestLoadDatabaseConfig_MySQLDefaultPort(t *testing.T) {
+	t.Setenv("DB_DRIVER", "mysql")
+	cfg, err := loadDatabaseConfig(envConfigSource)
+	if err != nil {
+		t.Fatalf("loadDatabaseConfig() failed: %v", err)
+	}
+	if cfg.Driver != "mysql" || cfg.Port != 3306 {
+		t.Errorf("driver = %s, port = %d, want mysql 3306", cfg.Driver, cfg.Port)
+	}
+
+	t.Setenv("DB_PORT", "3307")
+	if cfg, _ := loadDatabaseConfig(envConfigSource); cfg.Port != 3307 {
+		t.Errorf("port = %d, want explicit 3307", cfg.Port)
+	}
+}
+
+func TestValidate_MySQLRequiresUser(t *testing.T) {
+	cfg := &Config{
+		JWT:      JWTConfig{HMACSecret: "secret", SigningMethod: "HS256"},
+		Database: DatabaseConfig{Driver: "mysql", WriteHost: "localhost", Database: "testdb"},
+	}
+	if err := cfg.Validate(); err == nil || err.Error() != "DB_USER is required for mysql" {
+		t.Errorf("Validate() = %v, want DB_USER is required for mysql", err)
+	}
+}
+
 func TestLoadDatabaseConfig_InvalidValues(t *testing.T) {
 	t.Run("invalid port", func(t *testing.T) {
 		os.Setenv("DB_PORT", "not-a-port")
 		defer os.Unsetenv("DB_PORT")
-		_, err := loadDatabaseConfig()
+		_, err := loadDatabaseConfig(envConfigSource)
 		if err == nil {
 			t.Error("expected an error for invalid port")
 		}
@@ -97,7 +164,7 @@ func TestLoadDatabaseConfig_InvalidValues(t *testing.T) {
 	t.Run("invalid max conns", func(t *testing.T) {
 		os.Setenv("DB_MAX_CONNS", "not-an-int")
 		defer os.Unsetenv("DB_MAX_CONNS")
-		_, err := loadDatabaseConfig()
+		_, err := loadDatabaseConfig(envConfigSource)
 		if err == nil {
 			t.Error("expected an error for invalid max conns")
 		}
@@ -107,7 +174,7 @@ func TestLoadDatabaseConfig_InvalidValues(t *testing.T) {
 func TestLoadRateLimitConfig_Invalid(t *testing.T) {
 	os.Setenv("RATE_LIMIT_RESET_PERIOD", "invalid")
 	defer os.Unsetenv("RATE_LIMIT_RESET_PERIOD")
-	_, err := loadRateLimitConfig()
+	_, err := loadRateLimitConfig(envConfigSource)
 	if err == nil {
 		t.Error("expected an error for invalid reset period")
 	}
@@ -342,10 +409,37 @@ func TestValidate_SQLite_Success(t *testing.T) {
 	}
 }
 
+func TestValidate_InternalPortConflict(t *testing.T) {
+	cfg := &Config{
+		Server:   ServerConfig{Port: "8080", InternalPort: ":8080"},
+		JWT:      JWTConfig{HMACSecret: "secret", SigningMethod: "HS256"},
+		Database: DatabaseConfig{Driver: "sqlite", Database: "test.db"},
+	}
+	if err := cfg.Validate(); err == nil || err.Error() != "INTERNAL_PORT must differ from SERVER_PORT" {
+		t.Errorf("Validate() = %v, want INTERNAL_PORT conflict", err)
+	}
+
+	cfg.Server.InternalPort = "9090"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() = %v", err)
+	}
+}
+
+func TestLoadServerConfig_InternalPort(t *testing.T) {
+	t.Setenv("INTERNAL_PORT", "127.0.0.1:9090")
+	cfg, err := loadServerConfig(envConfigSource)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.InternalPort != "127.0.0.1:9090" {
+		t.Errorf("InternalPort = %q", cfg.InternalPort)
+	}
+}
+
 func TestLoadJWTConfig_InvalidRefreshTokenExpiry(t *testing.T) {
 	os.Setenv("JWT_REFRESH_TOKEN_EXPIRY", "invalid")
 	defer os.Unsetenv("JWT_REFRESH_TOKEN_EXPIRY")
-	_, err := loadJWTConfig()
+	_, err := loadJWTConfig(envConfigSource)
 	if err == nil {
 		t.Error("loadJWTConfig() should fail with invalid refresh token expiry")
 	}
@@ -354,7 +448,7 @@ func TestLoadJWTConfig_InvalidRefreshTokenExpiry(t *testing.T) {
 func TestLoadJWTConfig_InvalidAccessTokenExpiry(t *testing.T) {
 	os.Setenv("JWT_ACCESS_TOKEN_EXPIRY", "invalid")
 	defer os.Unsetenv("JWT_ACCESS_TOKEN_EXPIRY")
-	_, err := loadJWTConfig()
+	_, err := loadJWTConfig(envConfigSource)
 	if err == nil {
 		t.Error("loadJWTConfig() should fail with invalid access token expiry")
 	}
@@ -364,7 +458,7 @@ func TestLoadDatabaseConfig_ReadHostsParsing(t *testing.T) {
 	os.Setenv("DB_READ_HOSTS", "replica1.example.com, replica2.example.com , replica3.example.com")
 	defer os.Unsetenv("DB_READ_HOSTS")
 
-	cfg, err := loadDatabaseConfig()
+	cfg, err := loadDatabaseConfig(envConfigSource)
 	if err != nil {
 		t.Fatalf("loadDatabaseConfig() failed: %v", err)
 	}
@@ -383,7 +477,7 @@ func TestLoadDatabaseConfig_ReadHostsParsing(t *testing.T) {
 
 func TestLoadDatabaseConfig_EmptyReadHosts(t *testing.T) {
 	os.Unsetenv("DB_READ_HOSTS")
-	cfg, err := loadDatabaseConfig()
+	cfg, err := loadDatabaseConfig(envConfigSource)
 	if err != nil {
 		t.Fatalf("loadDatabaseConfig() failed: %v", err)
 	}
@@ -414,7 +508,7 @@ func TestLoadDatabaseConfig_SSLMode(t *testing.T) {
 				os.Unsetenv("DB_SSL_MODE")
 			}
 
-			cfg, err := loadDatabaseConfig()
+			cfg, err := loadDatabaseConfig(envConfigSource)
 			if err != nil {
 				t.Fatalf("loadDatabaseConfig() failed: %v", err)
 			}
@@ -429,7 +523,7 @@ func TestLoadDatabaseConfig_SSLMode(t *testing.T) {
 func TestLoadRateLimitConfig_InvalidPerIP(t *testing.T) {
 	os.Setenv("RATE_LIMIT_PER_IP", "not-a-number")
 	defer os.Unsetenv("RATE_LIMIT_PER_IP")
-	_, err := loadRateLimitConfig()
+	_, err := loadRateLimitConfig(envConfigSource)
 	if err == nil {
 		t.Error("loadRateLimitConfig() should fail with invalid per IP value")
 	}
@@ -438,7 +532,7 @@ func TestLoadRateLimitConfig_InvalidPerIP(t *testing.T) {
 func TestLoadRateLimitConfig_InvalidPerUser(t *testing.T) {
 	os.Setenv("RATE_LIMIT_PER_USER", "not-a-number")
 	defer os.Unsetenv("RATE_LIMIT_PER_USER")
-	_, err := loadRateLimitConfig()
+	_, err := loadRateLimitConfig(envConfigSource)
 	if err == nil {
 		t.Error("loadRateLimitConfig() should fail with invalid per user value")
 	}
@@ -456,7 +550,7 @@ func TestLoadRateLimitConfig_FullConfig(t *testing.T) {
 		os.Unsetenv("RATE_LIMIT_RESET_PERIOD")
 	}()
 
-	cfg, err := loadRateLimitConfig()
+	cfg, err := loadRateLimitConfig(envConfigSource)
 	if err != nil {
 		t.Fatalf("loadRateLimitConfig() failed: %v", err)
 	}
@@ -479,7 +573,7 @@ func TestLoadCSRFConfig_ExemptPathsParsing(t *testing.T) {
 	os.Setenv("CSRF_EXEMPT_PATHS", "/api/webhook, /api/public , /health")
 	defer os.Unsetenv("CSRF_EXEMPT_PATHS")
 
-	cfg, err := loadCSRFConfig()
+	cfg, err := loadCSRFConfig(envConfigSource)
 	if err != nil {
 		t.Fatalf("loadCSRFConfig() failed: %v", err)
 	}
@@ -498,7 +592,7 @@ func TestLoadCSRFConfig_ExemptPathsParsing(t *testing.T) {
 
 func TestLoadCSRFConfig_EmptyExemptPaths(t *testing.T) {
 	os.Unsetenv("CSRF_EXEMPT_PATHS")
-	cfg, err := loadCSRFConfig()
+	cfg, err := loadCSRFConfig(envConfigSource)
 	if err != nil {
 		t.Fatalf("loadCSRFConfig() failed: %v", err)
 	}
@@ -520,7 +614,7 @@ func TestLoadCSRFConfig_FullConfig(t *testing.T) {
 		os.Unsetenv("CSRF_HEADER_NAME")
 	}()
 
-	cfg, err := loadCSRFConfig()
+	cfg, err := loadCSRFConfig(envConfigSource)
 	if err != nil {
 		t.Fatalf("loadCSRFConfig() failed: %v", err)
 	}
@@ -555,7 +649,7 @@ func TestLoadJWTConfig_WithKeyFile(t *testing.T) {
 	defer os.Unsetenv("JWT_PRIVATE_KEY")
 
 	// 3. Load config
-	cfg, err := loadJWTConfig()
+	cfg, err := loadJWTConfig(envConfigSource)
 	if err != nil {
 		t.Fatalf("loadJWTConfig() failed: %v", err)
 	}
@@ -573,7 +667,7 @@ func TestLoadJWTConfig_WithRawKeyContent(t *testing.T) {
 	os.Setenv("JWT_PRIVATE_KEY", rawContent)
 	defer os.Unsetenv("JWT_PRIVATE_KEY")
 
-	cfg, err := loadJWTConfig()
+	cfg, err := loadJWTConfig(envConfigSource)
 	if err != nil {
 		t.Fatalf("loadJWTConfig() failed: %v", err)
 	}
@@ -600,7 +694,7 @@ func TestLoadJWTConfig_WithPublicKeyFile(t *testing.T) {
 	defer os.Unsetenv("JWT_PUBLIC_KEYS")
 
 	// 3. Load config
-	cfg, err := loadJWTConfig()
+	cfg, err := loadJWTConfig(envConfigSource)
 	if err != nil {
 		t.Fatalf("loadJWTConfig() failed: %v", err)
 	}
@@ -631,7 +725,7 @@ func TestResolveKeyContent_Base64PublicKeyViaEnv(t *testing.T) {
 	os.Setenv("JWT_PUBLIC_KEYS", envVal)
 	defer os.Unsetenv("JWT_PUBLIC_KEYS")
 
-	cfg, err := loadJWTConfig()
+	cfg, err := loadJWTConfig(envConfigSource)
 	if err != nil {
 		t.Fatalf("loadJWTConfig() failed: %v", err)
 	}
@@ -658,3 +752,99 @@ func TestResolveKeyContent_EmptyString(t *testing.T) {
 		t.Errorf("Expected empty string, got: %s", result)
 	}
 }
+
+func TestLoadPasswordConfig(t *testing.T) {
+	cfg, err := loadPasswordConfig(envConfigSource)
+	if err != nil {
+		t.Fatalf("loadPasswordConfig() failed: %v", err)
+	}
+	if cfg.Algorithm != PasswordAlgorithmBcrypt || cfg.BcryptCost != BcryptCost || cfg.Argon2Memory != 19*1024 {
+		t.Errorf("unexpected defaults: %+v", cfg)
+	}
+	if cfg.MinLength != MinPasswordLength || cfg.MaxLength != MaxPasswordLength || !cfg.RequireSpecial || cfg.BreachCheck || len(cfg.DenyList) != 0 {
+		t.Errorf("unexpected policy defaults: %+v", cfg)
+	}
+
+	os.Setenv("PASSWORD_HASH_ALGORITHM", "Argon2id")
+	os.Setenv("PASSWORD_ARGON2_MEMORY", "65536")
+	os.Setenv("PASSWORD_ARGON2_PARALLELISM", "4")
+	defer os.Unsetenv("PASSWORD_HASH_ALGORITHM")
+	defer os.Unsetenv("PASSWORD_ARGON2_MEMORY")
+	defer os.Unsetenv("PASSWORD_ARGON2_PARALLELISM")
+	cfg, err = loadPasswordConfig(envConfigSource)
+	if err != nil {
+		t.Fatalf("loadPasswordConfig() failed: %v", err)
+	}
+	if cfg.Algorithm != PasswordAlgorithmArgon2id || cfg.Argon2Memory != 65536 || cfg.Argon2Parallelism != 4 || cfg.Argon2Iterations != 2 {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+
+	os.Setenv("PASSWORD_HASH_ALGORITHM", "md5")
+	if _, err := loadPasswordConfig(envConfigSource); err == nil {
+		t.Error("expected an error for unsupported algorithm")
+	}
+	os.Setenv("PASSWORD_HASH_ALGORITHM", "argon2id")
+	os.Setenv("PASSWORD_ARGON2_PARALLELISM", "300")
+	if _, err := loadPasswordConfig(envConfigSource); err == nil {
+		t.Error("expected an error for out of range parallelism")
+	}
+}
+
+func TestLoadPasswordConfig_Policy(t *testing.T) {
+	os.Setenv("PASSWORD_MIN_LENGTH", "12")
+	os.Setenv("PASSWORD_REQUIRE_SPECIAL", "false")
+	os.Setenv("PASSWORD_DENY_LIST", "Password123!, letmein ,")
+	os.Setenv("PASSWORD_BREACH_CHECK", "true")
+	defer os.Unsetenv("PASSWORD_MIN_LENGTH")
+	defer os.Unsetenv("PASSWORD_REQUIRE_SPECIAL")
+	defer os.Unsetenv("PASSWORD_DENY_LIST")
+	defer os.Unsetenv("PASSWORD_BREACH_CHECK")
+
+	cfg, err := loadPasswordConfig(envConfigSource)
+	if err != nil {
+		t.Fatalf("loadPasswordConfig() failed: %v", err)
+	}
+	if cfg.MinLength != 12 || cfg.RequireSpecial || !cfg.RequireUppercase || !cfg.BreachCheck {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+	if len(cfg.DenyList) != 2 || cfg.DenyList[1] != "letmein" {
+		t.Errorf("DenyList = %q", cfg.DenyList)
+	}
+
+	os.Setenv("PASSWORD_MAX_LENGTH", "10")
+	defer os.Unsetenv("PASSWORD_MAX_LENGTH")
+	if _, err := loadPasswordConfig(envConfigSource); err == nil {
+		t.Error("expected an error when PASSWORD_MAX_LENGTH is below PASSWORD_MIN_LENGTH")
+	}
+}
+
+func TestLoadRedisConfig(t *testing.T) {
+	cfg, err := loadRedisConfig(envConfigSource)
+	if err != nil {
+		t.Fatalf("loadRedisConfig() failed: %v", err)
+	}
+	if cfg.Addr != "" || cfg.PoolSize != 10 || cfg.DialTimeout != 5*time.Second || cfg.IdleTimeout != 5*time.Minute {
+		t.Errorf("unexpected defaults: %+v", cfg)
+	}
+
+	os.Setenv("REDIS_ADDR", "redis:6379")
+	os.Setenv("REDIS_DB", "3")
+	os.Setenv("REDIS_POOL_SIZE", "25")
+	os.Setenv("REDIS_TLS", "true")
+	defer os.Unsetenv("REDIS_ADDR")
+	defer os.Unsetenv("REDIS_DB")
+	defer os.Unsetenv("REDIS_POOL_SIZE")
+	defer os.Unsetenv("REDIS_TLS")
+	cfg, err = loadRedisConfig(envConfigSource)
+	if err != nil {
+		t.Fatalf("loadRedisConfig() failed: %v", err)
+	}
+	if cfg.Addr != "redis:6379" || cfg.DB != 3 || cfg.PoolSize != 25 || !cfg.TLS {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+
+	os.Setenv("REDIS_POOL_SIZE", "0")
+	if _, err := loadRedisConfig(envConfigSource); err == nil {
+		t.Error("expected an error for REDIS_POOL_SIZE=0")
+	}
+}
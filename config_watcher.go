@@ -0,0 +1,372 @@
+package dim
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// defaultConfigWatchInterval adalah interval default pengecekan perubahan file konfigurasi.
+const defaultConfigWatchInterval = 2 * time.Second
+
+// ConfigChangeFunc dipanggil setelah konfigurasi baru berhasil dimuat dan divalidasi.
+// old dan new tidak boleh diubah; keduanya dapat dibaca concurrent oleh request lain.
+type ConfigChangeFunc func(old, new *Config)
+
+// ConfigWatcher memuat ulang konfigurasi saat menerima SIGHUP atau saat file yang dipantau
+// berubah, memvalidasi hasilnya, lalu memanggil callback yang terdaftar tanpa restart server.
+// Konfigurasi yang gagal dimuat atau divalidasi ditolak dan konfigurasi lama tetap dipakai.
+//
+// Perubahan yang hanya dibaca saat startup (misal SERVER_PORT atau koneksi database) tidak
+// berlaku sampai restart; gunakan OnChange untuk bagian yang dapat diterapkan saat runtime.
+type ConfigWatcher struct {
+	load     func() (*Config, error)
+	current  atomic.Pointer[Config]
+	interval time.Duration
+	signals  []os.Signal
+	onError  func(error)
+
+	mu        sync.Mutex
+	callbacks []ConfigChangeFunc
+	files     []string
+	fileStats map[string]configFileStat
+}
+
+type configFileStat struct {
+	modTime time.Time
+	size    int64
+}
+
+// NewConfigWatcher membuat ConfigWatcher dengan loader sendiri dan langsung memuat konfigurasi awal.
+// Loader harus memvalidasi hasilnya (LoadConfig dan LoadConfigFrom sudah menjalankan Validate).
+//
+// Parameters:
+//   - load: fungsi yang memuat konfigurasi, misal dim.LoadConfig
+//
+// Returns:
+//   - *ConfigWatcher: watcher dengan konfigurasi awal
+//   - error: jika konfigurasi awal gagal dimuat
+//
+// Example:
+//
+//	watcher, err := dim.NewConfigWatcher(dim.LoadConfig)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	cfg := watcher.Current()
+func NewConfigWatcher(load func() (*Config, error)) (*ConfigWatcher, error) {
+	cfg, err := load()
+	if err != nil {
+		return nil, err
+	}
+
+	w := &ConfigWatcher{
+		load:      load,
+		interval:  defaultConfigWatchInterval,
+		signals:   []os.Signal{syscall.SIGHUP},
+		fileStats: make(map[string]configFileStat),
+	}
+	w.current.Store(cfg)
+	return w, nil
+}
+
+// WatchConfig membuat ConfigWatcher untuk LoadConfigFrom(path, overrides...) yang memantau
+// file konfigurasi, profile file-nya (berdasarkan APP_ENV saat ini), dan file .env default.
+// Jika path kosong, LoadConfig yang dipakai dan hanya file .env yang dipantau.
+//
+// Parameters:
+//   - path: file konfigurasi YAML/TOML/JSON, atau "" untuk environment saja
+//   - overrides: nilai eksplisit seperti pada LoadConfigFrom
+//
+// Returns:
+//   - *ConfigWatcher: watcher dengan konfigurasi awal
+//   - error: jika konfigurasi awal gagal dimuat
+//
+// Example:
+//
+//	watcher, err := dim.WatchConfig("config.yaml")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	router.Use(watcher.CORS())
+//	go watcher.Run(ctx)
+func WatchConfig(path string, overrides ...map[string]string) (*ConfigWatcher, error) {
+	load := LoadConfig
+	files := append([]string(nil), DefaultDotenvFiles...)
+	if path != "" {
+		load = func() (*Config, error) {
+			return LoadConfigFrom(path, overrides...)
+		}
+		files = append(files, path)
+	}
+
+	w, err := NewConfigWatcher(load)
+	if err != nil {
+		return nil, err
+	}
+	if path != "" {
+		if profile := os.Getenv(configProfileKey); profile != "" {
+			files = append(files, configProfilePath(path, profile))
+		}
+	}
+	return w.WatchFiles(files...), nil
+}
+
+// WatchFiles menambahkan file yang dipantau. Perubahan waktu modifikasi atau ukuran file
+// (termasuk file dibuat atau dihapus) memicu reload saat Run berjalan.
+func (w *ConfigWatcher) WatchFiles(paths ...string) *ConfigWatcher {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, path := range paths {
+		if _, ok := w.fileStats[path]; ok {
+			continue
+		}
+		w.files = append(w.files, path)
+		w.fileStats[path] = statConfigFile(path)
+	}
+	return w
+}
+
+// WithInterval mengatur interval pengecekan file (default: 2 detik). 0 menonaktifkan
+// pemantauan file sehingga reload hanya terjadi lewat signal atau Reload.
+func (w *ConfigWatcher) WithInterval(interval time.Duration) *ConfigWatcher {
+	w.interval = interval
+	return w
+}
+
+// WithSignals mengganti signal yang memicu reload (default: SIGHUP).
+// Tanpa argumen, reload lewat signal dinonaktifkan.
+func (w *ConfigWatcher) WithSignals(signals ...os.Signal) *ConfigWatcher {
+	w.signals = signals
+	return w
+}
+
+// OnError mengatur callback untuk reload yang gagal. Default: error di-log.
+func (w *ConfigWatcher) OnError(fn func(error)) *ConfigWatcher {
+	w.onError = fn
+	return w
+}
+
+// OnChange mendaftarkan callback yang dipanggil berurutan setiap kali konfigurasi berubah.
+// Callback tidak dipanggil jika hasil reload sama dengan konfigurasi saat ini.
+//
+// Parameters:
+//   - fn: callback yang menerima konfigurasi lama dan baru
+//
+// Example:
+//
+//	watcher.OnChange(func(old, new *dim.Config) {
+//	    if old.Email.From != new.Email.From {
+//	        mailer.SetFrom(new.Email.From)
+//	    }
+//	})
+func (w *ConfigWatcher) OnChange(fn ConfigChangeFunc) *ConfigWatcher {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.callbacks = append(w.callbacks, fn)
+	return w
+}
+
+// Current mengembalikan konfigurasi yang sedang berlaku. Aman dipanggil concurrent;
+// nilai yang dikembalikan tidak boleh diubah.
+func (w *ConfigWatcher) Current() *Config {
+	return w.current.Load()
+}
+
+// Reload memuat ulang konfigurasi sekarang. Jika gagal, konfigurasi lama tetap dipakai
+// dan callback tidak dipanggil.
+//
+// Returns:
+//   - bool: true jika konfigurasi berubah dan callback dipanggil
+//   - error: jika loader atau validasi gagal
+func (w *ConfigWatcher) Reload() (bool, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	cfg, err := w.load()
+	if err != nil {
+		return false, fmt.Errorf("config reload: %w", err)
+	}
+
+	// Section aplikasi dimuat ulang ke instance baru agar ikut berubah dan tervalidasi
+	old := w.current.Load()
+	if err := cfg.reloadSections(old); err != nil {
+		return false, fmt.Errorf("config reload: %w", err)
+	}
+	if configEqual(old, cfg) {
+		return false, nil
+	}
+	w.current.Store(cfg)
+
+	for _, fn := range w.callbacks {
+		w.runCallback(fn, old, cfg)
+	}
+	slog.Info("config reloaded")
+	return true, nil
+}
+
+// configEqual membandingkan dua Config tanpa sumber nilainya (berupa func yang tidak dapat dibandingkan).
+func configEqual(a, b *Config) bool {
+	ac, bc := *a, *b
+	ac.source, bc.source = nil, nil
+	return reflect.DeepEqual(ac, bc)
+}
+
+// runCallback memanggil satu callback dengan recovery dari panic agar callback lain tetap berjalan.
+func (w *ConfigWatcher) runCallback(fn ConfigChangeFunc, old, cfg *Config) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			slog.Error("config change callback panicked", "panic", rec)
+		}
+	}()
+	fn(old, cfg)
+}
+
+// Run memantau signal dan file sampai ctx dibatalkan. Biasanya dijalankan di goroutine
+// terpisah bersama StartServer.
+//
+// Parameters:
+//   - ctx: context yang menghentikan watcher saat dibatalkan
+//
+// Returns:
+//   - error: ctx.Err() saat watcher berhenti
+//
+// Example:
+//
+//	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+//	defer stop()
+//	go watcher.Run(ctx)
+//	dim.StartServer(ctx, watcher.Current().Server, router)
+func (w *ConfigWatcher) Run(ctx context.Context) error {
+	var sigCh chan os.Signal
+	if len(w.signals) > 0 {
+		sigCh = make(chan os.Signal, 1)
+		signal.Notify(sigCh, w.signals...)
+		defer signal.Stop(sigCh)
+	}
+
+	var tick <-chan time.Time
+	if w.interval > 0 {
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case sig := <-sigCh:
+			slog.Info("config reload signal received", "signal", sig.String())
+			w.reloadAndReport()
+		case <-tick:
+			if w.filesChanged() {
+				w.reloadAndReport()
+			}
+		}
+	}
+}
+
+func (w *ConfigWatcher) reloadAndReport() {
+	if _, err := w.Reload(); err != nil {
+		if w.onError != nil {
+			w.onError(err)
+			return
+		}
+		slog.Error("config reload failed, keeping previous config", "error", err)
+	}
+}
+
+// filesChanged mengecek apakah ada file yang berubah sejak pengecekan terakhir.
+func (w *ConfigWatcher) filesChanged() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	changed := false
+	for _, path := range w.files {
+		stat := statConfigFile(path)
+		if stat != w.fileStats[path] {
+			w.fileStats[path] = stat
+			changed = true
+		}
+	}
+	return changed
+}
+
+// statConfigFile mengembalikan waktu modifikasi dan ukuran file, zero value jika file tidak ada.
+func statConfigFile(path string) configFileStat {
+	info, err := os.Stat(path)
+	if err != nil {
+		return configFileStat{}
+	}
+	return configFileStat{modTime: info.ModTime(), size: info.Size()}
+}
+
+// Middleware membuat middleware yang dibangun ulang dari konfigurasi setiap kali konfigurasi berubah.
+// Request yang sedang berjalan tetap memakai middleware lama.
+//
+// Parameters:
+//   - build: fungsi yang membuat middleware dari konfigurasi
+//
+// Returns:
+//   - MiddlewareFunc: middleware yang selalu mengikuti konfigurasi terbaru
+//
+// Example:
+//
+//	router.Use(watcher.Middleware(func(cfg *dim.Config) dim.MiddlewareFunc {
+//	    return dim.CSRFMiddleware(cfg.CSRF)
+//	}))
+func (w *ConfigWatcher) Middleware(build func(cfg *Config) MiddlewareFunc) MiddlewareFunc {
+	return w.middleware(build, nil)
+}
+
+// CORS membuat middleware CORS yang mengikuti konfigurasi CORS terbaru.
+func (w *ConfigWatcher) CORS() MiddlewareFunc {
+	return w.middleware(func(cfg *Config) MiddlewareFunc {
+		return CORS(cfg.CORS)
+	}, func(old, new *Config) bool {
+		return !reflect.DeepEqual(old.CORS, new.CORS)
+	})
+}
+
+// RateLimit membuat middleware rate limit yang mengikuti konfigurasi RateLimit terbaru.
+// Middleware hanya dibangun ulang jika bagian RateLimit berubah. Tanpa store, counter
+// in-memory di-reset saat dibangun ulang; berikan store yang sama agar counter dipertahankan.
+func (w *ConfigWatcher) RateLimit(store ...RateLimitStore) MiddlewareFunc {
+	return w.middleware(func(cfg *Config) MiddlewareFunc {
+		return RateLimit(cfg.RateLimit, store...)
+	}, func(old, new *Config) bool {
+		return !reflect.DeepEqual(old.RateLimit, new.RateLimit)
+	})
+}
+
+// middleware membangun middleware dari konfigurasi saat ini dan menggantinya saat konfigurasi
+// berubah. Jika changed tidak nil, middleware hanya dibangun ulang ketika changed bernilai true.
+func (w *ConfigWatcher) middleware(build func(cfg *Config) MiddlewareFunc, changed func(old, new *Config) bool) MiddlewareFunc {
+	var current atomic.Pointer[MiddlewareFunc]
+	mw := build(w.Current())
+	current.Store(&mw)
+
+	w.OnChange(func(old, new *Config) {
+		if changed != nil && !changed(old, new) {
+			return
+		}
+		mw := build(new)
+		current.Store(&mw)
+	})
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(rw http.ResponseWriter, r *http.Request) {
+			(*current.Load())(next)(rw, r)
+		}
+	}
+}
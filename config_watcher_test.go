@@ -0,0 +1,239 @@
+package dim
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// staticConfigLoader mengembalikan loader yang membaca Config dari pointer yang dapat diganti test.
+func staticConfigLoader(cfg *atomic.Pointer[Config], fail *atomic.Bool) func() (*Config, error) {
+	return func() (*Config, error) {
+		if fail != nil && fail.Load() {
+			return nil, errors.New("JWT_SECRET is required")
+		}
+		c := *cfg.Load()
+		return &c, nil
+	}
+}
+
+func watcherTestConfig(origins ...string) *Config {
+	return &Config{
+		CORS:      CORSConfig{AllowedOrigins: origins, AllowedMethods: []string{"GET"}},
+		RateLimit: RateLimitConfig{Enabled: true, PerIP: 100, ResetPeriod: time.Minute},
+	}
+}
+
+func TestConfigWatcher_Reload(t *testing.T) {
+	var source atomic.Pointer[Config]
+	var fail atomic.Bool
+	source.Store(watcherTestConfig("https://a.example.com"))
+
+	w, err := NewConfigWatcher(staticConfigLoader(&source, &fail))
+	if err != nil {
+		t.Fatalf("NewConfigWatcher() error = %v", err)
+	}
+
+	var calls []string
+	w.OnChange(func(old, new *Config) {
+		calls = append(calls, old.CORS.AllowedOrigins[0]+" -> "+new.CORS.AllowedOrigins[0])
+	})
+	w.OnChange(func(old, new *Config) {
+		panic("callback panics")
+	})
+	w.OnChange(func(old, new *Config) {
+		calls = append(calls, "after panic")
+	})
+
+	// Unchanged config does not fire callbacks
+	if changed, err := w.Reload(); changed || err != nil {
+		t.Fatalf("Reload() = %v, %v; want false, nil", changed, err)
+	}
+
+	source.Store(watcherTestConfig("https://b.example.com"))
+	if changed, err := w.Reload(); !changed || err != nil {
+		t.Fatalf("Reload() = %v, %v; want true, nil", changed, err)
+	}
+	if len(calls) != 2 || calls[0] != "https://a.example.com -> https://b.example.com" || calls[1] != "after panic" {
+		t.Errorf("callbacks = %v", calls)
+	}
+	if got := w.Current().CORS.AllowedOrigins[0]; got != "https://b.example.com" {
+		t.Errorf("Current() origin = %s", got)
+	}
+
+	// Invalid config keeps the previous one
+	fail.Store(true)
+	source.Store(watcherTestConfig("https://c.example.com"))
+	if changed, err := w.Reload(); changed || err == nil {
+		t.Fatalf("Reload() = %v, %v; want false, error", changed, err)
+	}
+	if got := w.Current().CORS.AllowedOrigins[0]; got != "https://b.example.com" || len(calls) != 2 {
+		t.Errorf("failed reload should keep config, origin = %s, calls = %d", got, len(calls))
+	}
+}
+
+func TestNewConfigWatcher_InitialError(t *testing.T) {
+	_, err := NewConfigWatcher(func() (*Config, error) { return nil, errors.New("boom") })
+	if err == nil {
+		t.Error("expected initial load error")
+	}
+}
+
+func TestConfigWatcher_CORSAndRateLimit(t *testing.T) {
+	var source atomic.Pointer[Config]
+	source.Store(watcherTestConfig("https://a.example.com"))
+	w, _ := NewConfigWatcher(staticConfigLoader(&source, nil))
+
+	router := NewRouter()
+	router.Use(w.CORS())
+	router.Use(w.RateLimit())
+	router.Get("/ping", func(rw http.ResponseWriter, r *http.Request) {
+		OK(rw, "pong")
+	})
+
+	request := func(origin string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+		req.Header.Set("Origin", origin)
+		req.RemoteAddr = "10.0.0.1:1234"
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if got := request("https://b.example.com").Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Fatalf("origin b should not be allowed yet, got %q", got)
+	}
+
+	next := watcherTestConfig("https://b.example.com")
+	next.RateLimit.PerIP = 1
+	source.Store(next)
+	if _, err := w.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	rec := request("https://b.example.com")
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "https://b.example.com" {
+		t.Errorf("origin b should be allowed after reload, got %q", got)
+	}
+	if rec.Code != http.StatusOK {
+		t.Fatalf("first request status = %d", rec.Code)
+	}
+	if code := request("https://b.example.com").Code; code != http.StatusTooManyRequests {
+		t.Errorf("new PerIP limit should apply, status = %d", code)
+	}
+}
+
+func TestConfigWatcher_RunFileChange(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.env")
+	os.WriteFile(path, []byte("a"), 0644)
+
+	var source atomic.Pointer[Config]
+	source.Store(watcherTestConfig("https://a.example.com"))
+	w, _ := NewConfigWatcher(staticConfigLoader(&source, nil))
+	w.WatchFiles(path).WithInterval(10 * time.Millisecond).WithSignals()
+
+	changed := make(chan string, 1)
+	w.OnChange(func(old, new *Config) {
+		changed <- new.CORS.AllowedOrigins[0]
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- w.Run(ctx) }()
+
+	source.Store(watcherTestConfig("https://b.example.com"))
+	os.WriteFile(path, []byte("ab"), 0644)
+
+	select {
+	case origin := <-changed:
+		if origin != "https://b.example.com" {
+			t.Errorf("origin = %s", origin)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("file change did not trigger reload")
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Run() error = %v", err)
+	}
+}
+
+func TestConfigWatcher_RunSignal(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("SIGHUP cannot be sent on windows")
+	}
+	// Pastikan SIGHUP tidak menghentikan proses test sebelum Run memasang signal.Notify
+	guard := make(chan os.Signal, 1)
+	signal.Notify(guard, syscall.SIGHUP)
+	defer signal.Stop(guard)
+
+	var source atomic.Pointer[Config]
+	source.Store(watcherTestConfig("https://a.example.com"))
+	w, _ := NewConfigWatcher(staticConfigLoader(&source, nil))
+	w.WithInterval(0).WithSignals(syscall.SIGHUP)
+
+	changed := make(chan struct{}, 1)
+	w.OnChange(func(old, new *Config) { changed <- struct{}{} })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go w.Run(ctx)
+
+	source.Store(watcherTestConfig("https://b.example.com"))
+	self, _ := os.FindProcess(os.Getpid())
+	// Kirim ulang sampai Run sudah memasang signal.Notify
+	deadline := time.After(2 * time.Second)
+	for {
+		self.Signal(syscall.SIGHUP)
+		select {
+		case <-changed:
+			return
+		case <-deadline:
+			t.Fatal("signal did not trigger reload")
+		case <-time.After(20 * time.Millisecond):
+		}
+	}
+}
+
+func TestWatchConfig_File(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	path := filepath.Join(dir, "config.yaml")
+	os.WriteFile(path, []byte("jwt:\n  secret: a-very-long-secret-for-testing-purposes-123\ndb:\n  driver: sqlite\n  name: app.db\ncors:\n  allowed_origins:\n    - https://a.example.com\n"), 0644)
+
+	w, err := WatchConfig(path)
+	if err != nil {
+		t.Fatalf("WatchConfig() error = %v", err)
+	}
+	if got := w.Current().CORS.AllowedOrigins; len(got) != 1 || got[0] != "https://a.example.com" {
+		t.Fatalf("origins = %v", got)
+	}
+
+	os.WriteFile(path, []byte("jwt:\n  secret: a-very-long-secret-for-testing-purposes-123\ndb:\n  driver: sqlite\n  name: app.db\ncors:\n  allowed_origins:\n    - https://b.example.com\n"), 0644)
+	if changed, err := w.Reload(); !changed || err != nil {
+		t.Fatalf("Reload() = %v, %v", changed, err)
+	}
+	if got := w.Current().CORS.AllowedOrigins[0]; got != "https://b.example.com" {
+		t.Errorf("origin after reload = %s", got)
+	}
+
+	// Validation failure keeps the previous config
+	os.WriteFile(path, []byte("db:\n  driver: sqlite\n  name: app.db\n"), 0644)
+	if _, err := w.Reload(); err == nil {
+		t.Error("expected validation error")
+	}
+	if got := w.Current().CORS.AllowedOrigins[0]; got != "https://b.example.com" {
+		t.Errorf("origin after failed reload = %s", got)
+	}
+}
@@ -55,6 +55,10 @@ type CommandContext struct {
 	// Err adalah output writer untuk stderr (default: os.Stderr)
 	// Digunakan untuk error messages dan warnings
 	Err io.Writer
+
+	// Warmup adalah registry loader cache yang dijalankan sebelum server start (boleh nil).
+	// Set via Console.WithWarmup() sebelum Run().
+	Warmup *Warmup
 }
 
 // Console adalah registry dan executor untuk CLI commands.
@@ -65,6 +69,7 @@ type Console struct {
 	migrationDB Database // optional, fallback ke db jika nil
 	router      *Router
 	config      *Config
+	warmup      *Warmup
 	out         io.Writer // Output writer (default: os.Stdout)
 	err         io.Writer // Error writer (default: os.Stderr)
 }
@@ -124,6 +129,20 @@ func (c *Console) WithMigrationDB(db Database) *Console {
 	return c
 }
 
+// WithWarmup mengatur registry Warmup yang dijalankan oleh perintah serve
+// sebelum server mulai menerima traffic.
+//
+// Example:
+//
+//	warmup := dim.NewWarmup()
+//	warmup.Register(dim.WarmupTask{Name: "settings", Load: settingsStore.Warm})
+//	console := dim.NewConsole(db, router, cfg)
+//	console.WithWarmup(warmup)
+func (c *Console) WithWarmup(w *Warmup) *Console {
+	c.warmup = w
+	return c
+}
+
 // Register mendaftarkan custom command ke console.
 // Command name harus unik, jika sudah ada akan mengembalikan error.
 //
@@ -157,8 +176,12 @@ func (c *Console) RegisterBuiltInCommands() {
 	c.Register(&ServeCommand{})
 	c.Register(&MigrateCommand{})
 	c.Register(&MigrateRollbackCommand{})
+	c.Register(&MigrateStatusCommand{})
 	c.Register(&MigrateListCommand{})
+	c.Register(&MigrateFreshCommand{})
+	c.Register(&SeedCommand{})
 	c.Register(&RouteListCommand{})
+	c.Register(&ConfigSchemaCommand{})
 	c.Register(&MakeMigrationCommand{})
 	c.Register(&HelpCommand{console: c})
 }
@@ -202,6 +225,7 @@ func (c *Console) Run(args []string) error {
 		Config:      c.config,
 		Out:         c.out,
 		Err:         c.err,
+		Warmup:      c.warmup,
 	}
 
 	// Check if command implements FlaggedCommand
@@ -105,8 +105,12 @@ func TestConsoleRegisterBuiltInCommands(t *testing.T) {
 		"serve",
 		"migrate",
 		"migrate:rollback",
+		"migrate:status",
 		"migrate:list",
+		"migrate:fresh",
+		"db:seed",
 		"route:list",
+		"config:schema",
 		"help",
 		"make:migration",
 	}
@@ -0,0 +1,170 @@
+package dim
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidCredentials menandakan bahwa verifier menolak kredensial (user tidak dikenal,
+// password salah, atau user tidak memiliki password lokal). ChainCredentialVerifiers
+// melanjutkan ke verifier berikutnya ketika menerima error ini.
+var ErrInvalidCredentials = errors.New("invalid credentials")
+
+// ErrUserNotFound dikembalikan AuthUserStore jika user tidak ditemukan.
+var ErrUserNotFound = errors.New("user not found")
+
+// CredentialVerifier memverifikasi email dan password dan mengembalikan user yang terotentikasi.
+// Implementasi dapat memeriksa hash lokal, bind ke LDAP/Active Directory, atau memanggil
+// identity API eksternal. AuthService tetap menerbitkan token dan session dim untuk user
+// yang dikembalikan, sehingga verifier eksternal wajib mengembalikan user yang memiliki ID
+// di AuthUserStore (misalnya dengan provisioning otomatis saat login pertama).
+//
+// Kembalikan error yang membungkus ErrInvalidCredentials jika kredensial ditolak.
+// Error lain dianggap kegagalan infrastruktur (misal LDAP tidak dapat dihubungi).
+type CredentialVerifier interface {
+	Verify(ctx context.Context, email, password string) (Authenticatable, error)
+}
+
+// CredentialVerifierFunc adalah adapter agar function biasa dapat digunakan sebagai CredentialVerifier.
+type CredentialVerifierFunc func(ctx context.Context, email, password string) (Authenticatable, error)
+
+// Verify memanggil f(ctx, email, password).
+func (f CredentialVerifierFunc) Verify(ctx context.Context, email, password string) (Authenticatable, error) {
+	return f(ctx, email, password)
+}
+
+// LocalCredentialVerifier memverifikasi password terhadap hash yang tersimpan di AuthUserStore.
+// Ini adalah perilaku default AuthService.Login.
+type LocalCredentialVerifier struct {
+	userStore AuthUserStore
+	hasher    PasswordHasher
+}
+
+// NewLocalCredentialVerifier membuat verifier berbasis hash password lokal.
+//
+// Parameters:
+//   - userStore: AuthUserStore untuk mencari user berdasarkan email
+//
+// Returns:
+//   - *LocalCredentialVerifier: verifier yang siap digunakan
+func NewLocalCredentialVerifier(userStore AuthUserStore) *LocalCredentialVerifier {
+	return &LocalCredentialVerifier{userStore: userStore, hasher: NewBcryptHasher(BcryptCost)}
+}
+
+// WithHasher mengganti PasswordHasher yang dipakai untuk mencocokkan password (default bcrypt)
+// dan mengembalikan verifier yang sama.
+func (v *LocalCredentialVerifier) WithHasher(hasher PasswordHasher) *LocalCredentialVerifier {
+	v.hasher = hasher
+	return v
+}
+
+// Verify mencari user berdasarkan email dan mencocokkan password dengan hash lokal.
+// User yang tidak ditemukan atau tanpa hash password (misal hasil provisioning LDAP) ditolak
+// dengan ErrInvalidCredentials; error lain dari AuthUserStore (misal database tidak dapat
+// dihubungi) dikembalikan apa adanya (di-wrap) agar tidak terlihat seperti password salah.
+// Jika hash dibuat dengan algoritma atau parameter lama (PasswordHasher.NeedsRehash), password
+// di-hash ulang dan disimpan; kegagalan rehash tidak menggagalkan login.
+func (v *LocalCredentialVerifier) Verify(ctx context.Context, email, password string) (Authenticatable, error) {
+	user, err := v.userStore.FindByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) || isNoRows(err) {
+			return nil, fmt.Errorf("%w: user not found", ErrInvalidCredentials)
+		}
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	if user.GetPassword() == "" {
+		return nil, fmt.Errorf("%w: user has no local password", ErrInvalidCredentials)
+	}
+
+	if err := v.hasher.Verify(user.GetPassword(), password); err != nil {
+		return nil, fmt.Errorf("%w: password mismatch", ErrInvalidCredentials)
+	}
+
+	if v.hasher.NeedsRehash(user.GetPassword()) {
+		if hash, err := v.hasher.Hash(password); err == nil {
+			user.SetPassword(hash)
+			_ = v.userStore.Update(ctx, user)
+		}
+	}
+
+	return user, nil
+}
+
+// ChainCredentialVerifiers mencoba beberapa verifier secara berurutan dan mengembalikan
+// user dari verifier pertama yang berhasil. Kegagalan infrastruktur pada satu verifier
+// tidak menghentikan verifier berikutnya; jika tidak ada yang berhasil, error infrastruktur
+// pertama dikembalikan, atau ErrInvalidCredentials jika semua menolak kredensial.
+//
+// Parameters:
+//   - verifiers: verifier dalam urutan fallback
+//
+// Returns:
+//   - CredentialVerifier: verifier gabungan
+//
+// Example:
+//
+//	// Hash lokal terlebih dahulu, lalu LDAP
+//	verifier := dim.ChainCredentialVerifiers(
+//	    dim.NewLocalCredentialVerifier(userStore),
+//	    ldapVerifier,
+//	)
+//	authService.WithCredentialVerifier(verifier)
+func ChainCredentialVerifiers(verifiers ...CredentialVerifier) CredentialVerifier {
+	return CredentialVerifierFunc(func(ctx context.Context, email, password string) (Authenticatable, error) {
+		var firstErr error
+		for _, v := range verifiers {
+			user, err := v.Verify(ctx, email, password)
+			if err == nil {
+				return user, nil
+			}
+			if !errors.Is(err, ErrInvalidCredentials) && firstErr == nil {
+				firstErr = err
+			}
+		}
+
+		if firstErr != nil {
+			return nil, firstErr
+		}
+		return nil, ErrInvalidCredentials
+	})
+}
+
+// RouteCredentialsByDomain memilih verifier berdasarkan domain email (case-insensitive).
+// Domain yang tidak terdaftar diarahkan ke fallback; jika fallback nil, kredensial ditolak.
+//
+// Parameters:
+//   - routes: map domain email (misal "corp.example.com") ke verifier
+//   - fallback: verifier untuk domain lainnya (boleh nil)
+//
+// Returns:
+//   - CredentialVerifier: verifier yang melakukan routing per domain
+//
+// Example:
+//
+//	verifier := dim.RouteCredentialsByDomain(map[string]dim.CredentialVerifier{
+//	    "corp.example.com": ldapVerifier,
+//	}, dim.NewLocalCredentialVerifier(userStore))
+func RouteCredentialsByDomain(routes map[string]CredentialVerifier, fallback CredentialVerifier) CredentialVerifier {
+	normalized := make(map[string]CredentialVerifier, len(routes))
+	for domain, v := range routes {
+		normalized[strings.ToLower(domain)] = v
+	}
+
+	return CredentialVerifierFunc(func(ctx context.Context, email, password string) (Authenticatable, error) {
+		domain := ""
+		if at := strings.LastIndex(email, "@"); at >= 0 {
+			domain = strings.ToLower(email[at+1:])
+		}
+
+		if v, ok := normalized[domain]; ok {
+			return v.Verify(ctx, email, password)
+		}
+		if fallback != nil {
+			return fallback.Verify(ctx, email, password)
+		}
+		return nil, fmt.Errorf("%w: no verifier for domain %q", ErrInvalidCredentials, domain)
+	})
+}
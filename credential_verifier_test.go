@@ -0,0 +1,180 @@
+package dim
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func newVerifierTestStore(t *testing.T) *MockUserStore {
+	t.Helper()
+	store := NewMockUserStore()
+	hashed, err := HashPassword("ValidPass123!")
+	if err != nil {
+		t.Fatalf("HashPassword error: %v", err)
+	}
+	store.AddUser(&MockUser{ID: "1", Email: "local@example.com", Password: hashed})
+	store.AddUser(&MockUser{ID: "2", Email: "staff@corp.example.com"})
+	return store
+}
+
+func staticVerifier(user Authenticatable, err error) CredentialVerifier {
+	return CredentialVerifierFunc(func(ctx context.Context, email, password string) (Authenticatable, error) {
+		return user, err
+	})
+}
+
+func TestLocalCredentialVerifier(t *testing.T) {
+	store := newVerifierTestStore(t)
+	v := NewLocalCredentialVerifier(store)
+	ctx := context.Background()
+
+	if user, err := v.Verify(ctx, "local@example.com", "ValidPass123!"); err != nil || user.GetID() != "1" {
+		t.Errorf("expected user 1, got %v, %v", user, err)
+	}
+
+	tests := []struct {
+		name     string
+		email    string
+		password string
+	}{
+		{"wrong password", "local@example.com", "wrong"},
+		{"unknown user", "nobody@example.com", "ValidPass123!"},
+		{"no local password", "staff@corp.example.com", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := v.Verify(ctx, tt.email, tt.password)
+			if !errors.Is(err, ErrInvalidCredentials) {
+				t.Errorf("expected ErrInvalidCredentials, got %v", err)
+			}
+		})
+	}
+}
+
+// unavailableUserStore mensimulasikan database yang tidak dapat dihubungi.
+type unavailableUserStore struct {
+	*MockUserStore
+}
+
+func (s unavailableUserStore) FindByEmail(ctx context.Context, email string) (Authenticatable, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestLocalCredentialVerifier_StoreError(t *testing.T) {
+	store := unavailableUserStore{NewMockUserStore()}
+	_, err := NewLocalCredentialVerifier(store).Verify(context.Background(), "local@example.com", "ValidPass123!")
+	if err == nil || errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("expected infrastructure error, got %v", err)
+	}
+
+	service, err := NewAuthService(store, NewMockTokenStore(), nil, &JWTConfig{
+		HMACSecret:         "test-secret",
+		SigningMethod:      "HS256",
+		AccessTokenExpiry:  15 * time.Minute,
+		RefreshTokenExpiry: 7 * 24 * time.Hour,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = service.Login(context.Background(), "local@example.com", "ValidPass123!")
+	if appErr, ok := AsAppError(err); !ok || appErr.StatusCode != 500 {
+		t.Errorf("expected 500 AppError during outage, got %v", err)
+	}
+}
+
+func TestChainCredentialVerifiers(t *testing.T) {
+	ctx := context.Background()
+	external := &MockUser{ID: "ext", Email: "staff@corp.example.com"}
+	infraErr := errors.New("ldap unreachable")
+
+	t.Run("falls back to next verifier", func(t *testing.T) {
+		chain := ChainCredentialVerifiers(staticVerifier(nil, ErrInvalidCredentials), staticVerifier(external, nil))
+		user, err := chain.Verify(ctx, "staff@corp.example.com", "secret")
+		if err != nil || user.GetID() != "ext" {
+			t.Errorf("expected external user, got %v, %v", user, err)
+		}
+	})
+
+	t.Run("infrastructure error does not stop chain", func(t *testing.T) {
+		chain := ChainCredentialVerifiers(staticVerifier(nil, infraErr), staticVerifier(external, nil))
+		if _, err := chain.Verify(ctx, "a@b.com", "secret"); err != nil {
+			t.Errorf("expected success, got %v", err)
+		}
+	})
+
+	t.Run("returns infrastructure error when nothing succeeds", func(t *testing.T) {
+		chain := ChainCredentialVerifiers(staticVerifier(nil, ErrInvalidCredentials), staticVerifier(nil, infraErr))
+		if _, err := chain.Verify(ctx, "a@b.com", "secret"); !errors.Is(err, infraErr) {
+			t.Errorf("expected infrastructure error, got %v", err)
+		}
+	})
+
+	t.Run("all rejected", func(t *testing.T) {
+		chain := ChainCredentialVerifiers(staticVerifier(nil, ErrInvalidCredentials))
+		if _, err := chain.Verify(ctx, "a@b.com", "secret"); !errors.Is(err, ErrInvalidCredentials) {
+			t.Errorf("expected ErrInvalidCredentials, got %v", err)
+		}
+	})
+}
+
+func TestRouteCredentialsByDomain(t *testing.T) {
+	ctx := context.Background()
+	corp := &MockUser{ID: "corp"}
+	other := &MockUser{ID: "other"}
+
+	v := RouteCredentialsByDomain(map[string]CredentialVerifier{
+		"Corp.Example.com": staticVerifier(corp, nil),
+	}, staticVerifier(other, nil))
+
+	if user, _ := v.Verify(ctx, "staff@CORP.example.com", "x"); user.GetID() != "corp" {
+		t.Errorf("expected corp verifier, got %s", user.GetID())
+	}
+	if user, _ := v.Verify(ctx, "someone@gmail.com", "x"); user.GetID() != "other" {
+		t.Errorf("expected fallback verifier, got %s", user.GetID())
+	}
+
+	noFallback := RouteCredentialsByDomain(map[string]CredentialVerifier{}, nil)
+	if _, err := noFallback.Verify(ctx, "someone@gmail.com", "x"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("expected ErrInvalidCredentials without fallback, got %v", err)
+	}
+}
+
+func TestLogin_WithCredentialVerifier(t *testing.T) {
+	store := newVerifierTestStore(t)
+	config := &JWTConfig{
+		HMACSecret:         "test-secret",
+		SigningMethod:      "HS256",
+		AccessTokenExpiry:  15 * time.Minute,
+		RefreshTokenExpiry: 7 * 24 * time.Hour,
+	}
+
+	service, err := NewAuthService(store, NewMockTokenStore(), nil, config)
+	if err != nil {
+		t.Fatalf("NewAuthService error: %v", err)
+	}
+
+	ldap := CredentialVerifierFunc(func(ctx context.Context, email, password string) (Authenticatable, error) {
+		if password != "ldap-secret" {
+			return nil, ErrInvalidCredentials
+		}
+		return store.FindByEmail(ctx, email)
+	})
+	service.WithCredentialVerifier(ChainCredentialVerifiers(NewLocalCredentialVerifier(store), ldap))
+
+	ctx := context.Background()
+	if _, _, err := service.Login(ctx, "staff@corp.example.com", "ldap-secret"); err != nil {
+		t.Errorf("expected LDAP login to succeed, got %v", err)
+	}
+	if _, _, err := service.Login(ctx, "local@example.com", "ValidPass123!"); err != nil {
+		t.Errorf("expected local login to succeed, got %v", err)
+	}
+
+	_, _, err = service.Login(ctx, "staff@corp.example.com", "wrong")
+	appErr, ok := AsAppError(err)
+	if !ok || appErr.StatusCode != 401 {
+		t.Errorf("expected 401 AppError, got %v", err)
+	}
+}
@@ -1,7 +1,6 @@
 package dim
 
 import (
-	"encoding/json"
 	"net/http"
 )
 
@@ -55,8 +54,12 @@ func (c *Ctx) ClientIP() string {
 	return GetClientIP(c.r)
 }
 
-func (c *Ctx) Bind(v interface{}) error {
-	return json.NewDecoder(c.r.Body).Decode(v)
+func (c *Ctx) Bind(v interface{}, opts ...BindOption) error {
+	return Bind(c.r, v, opts...)
+}
+
+func (c *Ctx) BindQuery(v interface{}, opts ...BindOption) error {
+	return BindQuery(c.r, v, opts...)
 }
 
 func (c *Ctx) Validate() *Validator {
@@ -64,8 +67,8 @@ func (c *Ctx) Validate() *Validator {
 }
 
 // Response
-func (c *Ctx) JSON(status int, data interface{}) error {
-	return Json(c.w, status, data)
+func (c *Ctx) JSON(status int, data interface{}, opts ...ResponseOption) error {
+	return Json(c.w, status, data, opts...)
 }
 
 func (c *Ctx) OK(data interface{}) error {
@@ -108,6 +111,10 @@ func (c *Ctx) TooManyRequests(retryAfterSeconds int) error {
 	return TooManyRequests(c.w, retryAfterSeconds)
 }
 
+func (c *Ctx) ServiceUnavailable(retryAfterSeconds int) error {
+	return ServiceUnavailable(c.w, retryAfterSeconds)
+}
+
 func (c *Ctx) AppError(appErr *AppError) error {
 	return JsonAppError(c.w, appErr)
 }
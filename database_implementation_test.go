@@ -2,7 +2,10 @@ package dim
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"os"
+	"strings"
 	"testing"
 	"time"
 )
@@ -171,6 +174,12 @@ func TestDatabaseTokenStore_SQLite(t *testing.T) {
 	store := NewDatabaseTokenStore(db)
 	ctx := context.Background()
 
+	// Foreign key users(id) ditegakkan di SQLite
+	if err := db.Exec(ctx, db.Rebind("INSERT INTO users (id, email, password) VALUES ($1, $2, $3)"),
+		"550e8400-e29b-41d4-a716-446655440000", "token@example.com", "hash"); err != nil {
+		t.Fatalf("insert user failed: %v", err)
+	}
+
 	token := &RefreshToken{
 		UserID:    "550e8400-e29b-41d4-a716-446655440000",
 		TokenHash: "hash123",
@@ -206,6 +215,192 @@ func TestDatabaseTokenStore_SQLite(t *testing.T) {
 	if found.RevokedAt == nil {
 		t.Error("Expected RevokedAt to be set")
 	}
+	if err := store.RevokeRefreshToken(ctx, "hash123"); !errors.Is(err, ErrRefreshTokenRevoked) {
+		t.Errorf("second RevokeRefreshToken error = %v, want ErrRefreshTokenRevoked", err)
+	}
+}
+
+func TestSQLiteDSN(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{":memory:", ":memory:?_pragma=foreign_keys%281%29&_time_format=sqlite&_timezone=UTC"},
+		{"./app.db?_pragma=busy_timeout(5000)", "./app.db?_pragma=busy_timeout%285000%29&_pragma=foreign_keys%281%29&_time_format=sqlite&_timezone=UTC"},
+		{"file:app.db?_pragma=foreign_keys(0)&_timezone=Asia/Jakarta", "file:app.db?_pragma=foreign_keys%280%29&_time_format=sqlite&_timezone=Asia%2FJakarta"},
+	}
+	for _, tt := range tests {
+		if got := sqliteDSN(tt.path); got != tt.want {
+			t.Errorf("sqliteDSN(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+// newTestSQLiteAuthDB membuat database SQLite in-memory dengan migrasi users dan token.
+func newTestSQLiteAuthDB(t *testing.T) *SQLiteDatabase {
+	t.Helper()
+	db, err := NewSQLiteDatabase(DatabaseConfig{Database: ":memory:"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := RunMigrations(db, append(GetUserMigrations(), GetTokenMigrations()...)); err != nil {
+		t.Fatalf("Migration failed: %v", err)
+	}
+	return db
+}
+
+func TestDatabaseAuthUserStore_SQLite(t *testing.T) {
+	db := newTestSQLiteAuthDB(t)
+	ctx := context.Background()
+	if err := db.Exec(ctx, db.Rebind("INSERT INTO users (id, email, password) VALUES ($1, $2, $3)"), "u-1", "ana@example.com", "hash"); err != nil {
+		t.Fatal(err)
+	}
+
+	store := NewDatabaseAuthUserStore(db)
+	user, err := store.FindByEmail(ctx, "ana@example.com")
+	if err != nil || user.GetID() != "u-1" || user.GetPassword() != "hash" {
+		t.Fatalf("FindByEmail = %+v, %v", user, err)
+	}
+
+	user.SetPassword("new-hash")
+	if err := store.Update(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+	found, err := store.FindByID(ctx, "u-1")
+	if err != nil || found.GetPassword() != "new-hash" {
+		t.Errorf("FindByID = %+v, %v", found, err)
+	}
+
+	if _, err := store.FindByEmail(ctx, "missing@example.com"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("FindByEmail(missing) error = %v, want sql.ErrNoRows", err)
+	}
+}
+
+func TestDatabaseTokenStore_SQLite_Lifecycle(t *testing.T) {
+	db := newTestSQLiteAuthDB(t)
+	ctx := context.Background()
+	if err := db.Exec(ctx, "INSERT INTO users (id, email, password) VALUES ('u-1', 'ana@example.com', 'hash')"); err != nil {
+		t.Fatal(err)
+	}
+	store := NewDatabaseTokenStore(db)
+
+	for _, hash := range []string{"r1", "r2"} {
+		if err := store.SaveRefreshToken(ctx, &RefreshToken{UserID: "u-1", TokenHash: hash, SessionID: "sid-" + hash, ExpiresAt: time.Now().Add(time.Hour)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.RevokeBySession(ctx, "sid-r1"); err != nil {
+		t.Fatal(err)
+	}
+	if r1, _ := store.FindRefreshToken(ctx, "r1"); r1 == nil || r1.SessionID != "sid-r1" || r1.RevokedAt == nil {
+		t.Errorf("FindRefreshToken(r1) after RevokeBySession = %+v", r1)
+	}
+	if r2, _ := store.FindRefreshToken(ctx, "r2"); r2 == nil || r2.RevokedAt != nil {
+		t.Errorf("FindRefreshToken(r2) after RevokeBySession = %+v", r2)
+	}
+	if err := store.RevokeAllUserTokens(ctx, "u-1"); err != nil {
+		t.Fatal(err)
+	}
+	for _, hash := range []string{"r1", "r2"} {
+		if token, err := store.FindRefreshToken(ctx, hash); err != nil || token.RevokedAt == nil {
+			t.Errorf("FindRefreshToken(%s) = %+v, %v", hash, token, err)
+		}
+	}
+
+	reset := &PasswordResetToken{UserID: "u-1", TokenHash: "p1", ExpiresAt: time.Now().Add(time.Hour)}
+	if err := store.SavePasswordResetToken(ctx, reset); err != nil || reset.ID == 0 || reset.CreatedAt.IsZero() {
+		t.Fatalf("SavePasswordResetToken = %+v, %v", reset, err)
+	}
+	if err := store.MarkPasswordResetUsed(ctx, "p1"); err != nil {
+		t.Fatal(err)
+	}
+	if found, err := store.FindPasswordResetToken(ctx, "p1"); err != nil || found.UsedAt == nil || found.ExpiresAt.Location() != time.UTC {
+		t.Errorf("FindPasswordResetToken = %+v, %v", found, err)
+	}
+
+	// Waktu dari Go ditulis dengan format yang sama seperti CURRENT_TIMESTAMP
+	var stored string
+	var future bool
+	if err := db.QueryRow(ctx, "SELECT CAST(expires_at AS TEXT), expires_at > CURRENT_TIMESTAMP FROM password_reset_tokens").Scan(&stored, &future); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(stored, "+00:00") || !future {
+		t.Errorf("stored expires_at = %q, after CURRENT_TIMESTAMP = %v", stored, future)
+	}
+
+	// ON DELETE CASCADE berlaku karena foreign key diaktifkan
+	if err := db.Exec(ctx, "DELETE FROM users WHERE id = 'u-1'"); err != nil {
+		t.Fatal(err)
+	}
+	var remaining int
+	db.QueryRow(ctx, "SELECT (SELECT COUNT(*) FROM refresh_tokens) + (SELECT COUNT(*) FROM password_reset_tokens)").Scan(&remaining)
+	if remaining != 0 {
+		t.Errorf("tokens after user delete = %d, want 0", remaining)
+	}
+}
+
+func TestDatabaseTokenStore_SQLite_DeleteExpired(t *testing.T) {
+	db := newTestSQLiteAuthDB(t)
+	ctx := context.Background()
+	if err := db.Exec(ctx, "INSERT INTO users (id, email, password) VALUES ('u-1', 'ana@example.com', 'hash')"); err != nil {
+		t.Fatal(err)
+	}
+	store := NewDatabaseTokenStore(db)
+	now := time.Now()
+
+	for hash, expiresAt := range map[string]time.Time{"expired": now.Add(-time.Hour), "active": now.Add(time.Hour)} {
+		if err := store.SaveRefreshToken(ctx, &RefreshToken{UserID: "u-1", TokenHash: hash, SessionID: "sid-" + hash, ExpiresAt: expiresAt}); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.SavePasswordResetToken(ctx, &PasswordResetToken{UserID: "u-1", TokenHash: "reset-" + hash, ExpiresAt: expiresAt}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.RevokeBySession(ctx, "sid-expired"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.DeleteExpired(ctx, now); err != nil {
+		t.Fatalf("DeleteExpired() error = %v", err)
+	}
+
+	if _, err := store.FindRefreshToken(ctx, "expired"); err == nil {
+		t.Error("expired refresh token should be deleted")
+	}
+	if _, err := store.FindPasswordResetToken(ctx, "reset-expired"); err == nil {
+		t.Error("expired password reset token should be deleted")
+	}
+	if _, err := store.FindRefreshToken(ctx, "active"); err != nil {
+		t.Errorf("active refresh token deleted: %v", err)
+	}
+	if _, err := store.FindPasswordResetToken(ctx, "reset-active"); err != nil {
+		t.Errorf("active password reset token deleted: %v", err)
+	}
+}
+
+func TestDatabaseBlocklist_SQLite(t *testing.T) {
+	db := newTestSQLiteAuthDB(t)
+	ctx := context.Background()
+	blocklist := NewDatabaseBlocklist(db)
+
+	blocklist.Invalidate(ctx, "active", time.Hour)
+	blocklist.Invalidate(ctx, "expired", -time.Hour)
+	if revoked, err := blocklist.IsRevoked(ctx, "active"); err != nil || !revoked {
+		t.Errorf("IsRevoked(active) = %v, %v", revoked, err)
+	}
+	if revoked, _ := blocklist.IsRevoked(ctx, "expired"); revoked {
+		t.Error("expired entry should not be revoked")
+	}
+
+	if err := blocklist.Cleanup(ctx); err != nil {
+		t.Fatal(err)
+	}
+	var count int
+	db.QueryRow(ctx, "SELECT COUNT(*) FROM token_blocklist").Scan(&count)
+	if count != 1 {
+		t.Errorf("entries after Cleanup = %d, want 1", count)
+	}
 }
 
 func TestDatabaseRateLimitStore_SQLite(t *testing.T) {
@@ -0,0 +1,362 @@
+package dim
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Nama metric database.
+const (
+	// MetricDBQueries (counter, label: operation, status) — query yang dieksekusi.
+	// operation adalah kata kunci SQL pertama: select, insert, update, delete, begin, commit,
+	// rollback, atau other.
+	MetricDBQueries = "dim_db_queries_total"
+	// MetricDBQueryDuration (histogram, label: operation, status) — durasi query.
+	MetricDBQueryDuration = "dim_db_query_duration_seconds"
+	// MetricDBSlowQueries (counter, label: operation) — query di atas SlowQueryThreshold.
+	MetricDBSlowQueries = "dim_db_slow_queries_total"
+	// MetricDBPoolConns (gauge, label: pool, state) — koneksi pgx pool per state:
+	// acquired, idle, constructing, total, max. pool bernilai "write" atau "read_<n>".
+	MetricDBPoolConns = "dim_db_pool_conns"
+	// MetricDBPoolEmptyAcquires (counter, label: pool) — acquire yang harus menunggu karena pool kosong.
+	MetricDBPoolEmptyAcquires = "dim_db_pool_empty_acquires_total"
+)
+
+// DBInstrumentationConfig mengatur NewInstrumentedDatabase.
+type DBInstrumentationConfig struct {
+	// SlowQueryThreshold adalah durasi minimum query yang dicatat sebagai slow query
+	// (default: 200ms). Negatif menonaktifkan log slow query.
+	SlowQueryThreshold time.Duration
+
+	// Logger menerima log slow query (default: slog.Default()).
+	Logger *slog.Logger
+
+	// LogArgs menyertakan argumen query di log slow query. Argumen untuk query yang
+	// menyentuh kolom sensitif (password, token, email, ...) selalu disamarkan.
+	LogArgs bool
+
+	// Metrics mencatat counter dan histogram query ke registry (opsional).
+	Metrics *MetricsRegistry
+
+	// PoolStatsInterval adalah interval pencatatan statistik pgx pool ke Metrics
+	// (default: 15 detik). Hanya berlaku untuk PostgresDatabase dan jika Metrics diisi.
+	PoolStatsInterval time.Duration
+
+	// OnSlowQuery dipanggil untuk setiap slow query, misal untuk mengirim ke APM (opsional).
+	OnSlowQuery func(ctx context.Context, query string, duration time.Duration, err error)
+}
+
+// InstrumentedDatabase membungkus Database dan mencatat durasi setiap Exec, Query, dan QueryRow:
+// query yang melewati SlowQueryThreshold di-log via slog, dan counter/histogram dicatat ke
+// MetricsRegistry yang sama dengan metric HTTP sehingga dapat diekspos di endpoint /metrics.
+// Transaksi dari Begin dan WithTx ikut diinstrumentasi. Untuk PostgresDatabase, statistik pgx
+// pool dicatat berkala sebagai gauge.
+//
+// Durasi Query diukur sampai rows dikembalikan, bukan sampai rows selesai dibaca.
+type InstrumentedDatabase struct {
+	db     Database
+	config DBInstrumentationConfig
+
+	queries   *Counter
+	durations *Histogram
+	slow      *Counter
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// NewInstrumentedDatabase membungkus db dengan instrumentasi query. Jika db adalah
+// PostgresDatabase dan config.Metrics diisi, goroutine pencatat statistik pool dijalankan
+// hingga Close dipanggil.
+//
+// Parameters:
+//   - db: database yang dibungkus
+//   - config: DBInstrumentationConfig berisi threshold, logger, dan registry
+//
+// Returns:
+//   - *InstrumentedDatabase: Database yang dapat dipakai di tempat db
+//
+// Example:
+//
+//	metrics := dim.NewMetricsRegistry()
+//	db := dim.NewInstrumentedDatabase(pg, dim.DBInstrumentationConfig{
+//	    SlowQueryThreshold: 500 * time.Millisecond,
+//	    Metrics:            metrics,
+//	})
+//	defer db.Close()
+//	userStore := dim.NewDatabaseAuthUserStore(db)
+func NewInstrumentedDatabase(db Database, config DBInstrumentationConfig) *InstrumentedDatabase {
+	if config.SlowQueryThreshold == 0 {
+		config.SlowQueryThreshold = 200 * time.Millisecond
+	}
+	if config.Logger == nil {
+		config.Logger = slog.Default()
+	}
+	if config.PoolStatsInterval <= 0 {
+		config.PoolStatsInterval = 15 * time.Second
+	}
+
+	d := &InstrumentedDatabase{db: db, config: config}
+	if config.Metrics != nil {
+		d.queries = config.Metrics.Counter(MetricDBQueries, "Total number of database queries.", "operation", "status")
+		d.durations = config.Metrics.Histogram(MetricDBQueryDuration, "Database query duration in seconds.", nil, "operation", "status")
+		d.slow = config.Metrics.Counter(MetricDBSlowQueries, "Total number of queries above the slow query threshold.", "operation")
+
+		if pg, ok := db.(*PostgresDatabase); ok {
+			d.stop = make(chan struct{})
+			d.done = make(chan struct{})
+			go d.recordPoolStats(pg)
+		}
+	}
+	return d
+}
+
+// Unwrap mengembalikan Database yang dibungkus.
+func (d *InstrumentedDatabase) Unwrap() Database {
+	return d.db
+}
+
+func (d *InstrumentedDatabase) Exec(ctx context.Context, query string, args ...interface{}) error {
+	start := time.Now()
+	err := d.db.Exec(ctx, query, args...)
+	d.observe(ctx, query, args, time.Since(start), err)
+	return err
+}
+
+func (d *InstrumentedDatabase) Query(ctx context.Context, query string, args ...interface{}) (Rows, error) {
+	start := time.Now()
+	rows, err := d.db.Query(ctx, query, args...)
+	d.observe(ctx, query, args, time.Since(start), err)
+	return rows, err
+}
+
+func (d *InstrumentedDatabase) QueryRow(ctx context.Context, query string, args ...interface{}) Row {
+	start := time.Now()
+	return &instrumentedRow{
+		row:   d.db.QueryRow(ctx, query, args...),
+		start: start,
+		observe: func(duration time.Duration, err error) {
+			d.observe(ctx, query, args, duration, err)
+		},
+	}
+}
+
+func (d *InstrumentedDatabase) Begin(ctx context.Context) (Tx, error) {
+	start := time.Now()
+	tx, err := d.db.Begin(ctx)
+	d.observe(ctx, "BEGIN", nil, time.Since(start), err)
+	if err != nil {
+		return nil, err
+	}
+	return &instrumentedTx{tx: tx, d: d}, nil
+}
+
+// WithTx meneruskan ke WithTx milik database yang dibungkus, sehingga transaksi di context dan
+// SAVEPOINT untuk InTx bertingkat tetap bekerja seperti tanpa instrumentasi.
+func (d *InstrumentedDatabase) WithTx(ctx context.Context, fn TransactionFunc) error {
+	return d.db.WithTx(ctx, func(ctx context.Context, tx Tx) error {
+		return fn(ctx, &instrumentedTx{tx: tx, d: d})
+	})
+}
+
+// Close menghentikan pencatatan statistik pool lalu menutup database yang dibungkus.
+func (d *InstrumentedDatabase) Close() error {
+	d.stopOnce.Do(func() {
+		if d.stop != nil {
+			close(d.stop)
+			<-d.done
+		}
+	})
+	return d.db.Close()
+}
+
+func (d *InstrumentedDatabase) DriverName() string {
+	return d.db.DriverName()
+}
+
+func (d *InstrumentedDatabase) Rebind(query string) string {
+	return d.db.Rebind(query)
+}
+
+// observe mencatat metric dan log slow query untuk satu query.
+func (d *InstrumentedDatabase) observe(ctx context.Context, query string, args []interface{}, duration time.Duration, err error) {
+	operation := queryOperation(query)
+	if d.queries != nil {
+		status := metricStatus(err)
+		d.queries.Inc(operation, status)
+		d.durations.Observe(duration.Seconds(), operation, status)
+	}
+
+	if d.config.SlowQueryThreshold < 0 || duration < d.config.SlowQueryThreshold {
+		return
+	}
+	if d.slow != nil {
+		d.slow.Inc(operation)
+	}
+	if d.config.OnSlowQuery != nil {
+		d.config.OnSlowQuery(ctx, query, duration, err)
+	}
+
+	attrs := []any{
+		"query", query,
+		"duration_ms", duration.Milliseconds(),
+		"threshold_ms", d.config.SlowQueryThreshold.Milliseconds(),
+		"driver", d.db.DriverName(),
+	}
+	if d.config.LogArgs {
+		attrs = append(attrs, "args", sanitizeArgs(query, args))
+	}
+	if err != nil {
+		attrs = append(attrs, "error", err)
+	}
+	d.config.Logger.WarnContext(ctx, "slow query", attrs...)
+}
+
+// recordPoolStats mencatat statistik pool PostgreSQL setiap PoolStatsInterval.
+func (d *InstrumentedDatabase) recordPoolStats(pg *PostgresDatabase) {
+	defer close(d.done)
+
+	conns := d.config.Metrics.Gauge(MetricDBPoolConns, "Database pool connections by state.", "pool", "state")
+	emptyAcquires := d.config.Metrics.Counter(MetricDBPoolEmptyAcquires, "Total number of pool acquires that waited for a connection.", "pool")
+
+	pools := map[string]*pgxpool.Pool{"write": pg.WritePool()}
+	for i, pool := range pg.ReadPools() {
+		if pool != pg.WritePool() {
+			pools["read_"+strconv.Itoa(i)] = pool
+		}
+	}
+	lastEmpty := make(map[string]int64, len(pools))
+
+	record := func() {
+		for name, pool := range pools {
+			stat := pool.Stat()
+			conns.Set(float64(stat.AcquiredConns()), name, "acquired")
+			conns.Set(float64(stat.IdleConns()), name, "idle")
+			conns.Set(float64(stat.ConstructingConns()), name, "constructing")
+			conns.Set(float64(stat.TotalConns()), name, "total")
+			conns.Set(float64(stat.MaxConns()), name, "max")
+
+			empty := stat.EmptyAcquireCount()
+			if delta := empty - lastEmpty[name]; delta > 0 {
+				emptyAcquires.Add(float64(delta), name)
+			}
+			lastEmpty[name] = empty
+		}
+	}
+
+	record()
+	ticker := time.NewTicker(d.config.PoolStatsInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			record()
+		case <-d.stop:
+			return
+		}
+	}
+}
+
+// instrumentedTx mencatat query yang dijalankan langsung pada Tx.
+type instrumentedTx struct {
+	tx Tx
+	d  *InstrumentedDatabase
+}
+
+func (t *instrumentedTx) Exec(ctx context.Context, query string, args ...interface{}) error {
+	start := time.Now()
+	err := t.tx.Exec(ctx, query, args...)
+	t.d.observe(ctx, query, args, time.Since(start), err)
+	return err
+}
+
+func (t *instrumentedTx) Query(ctx context.Context, query string, args ...interface{}) (Rows, error) {
+	start := time.Now()
+	rows, err := t.tx.Query(ctx, query, args...)
+	t.d.observe(ctx, query, args, time.Since(start), err)
+	return rows, err
+}
+
+func (t *instrumentedTx) QueryRow(ctx context.Context, query string, args ...interface{}) Row {
+	start := time.Now()
+	return &instrumentedRow{
+		row:   t.tx.QueryRow(ctx, query, args...),
+		start: start,
+		observe: func(duration time.Duration, err error) {
+			t.d.observe(ctx, query, args, duration, err)
+		},
+	}
+}
+
+func (t *instrumentedTx) Commit(ctx context.Context) error {
+	start := time.Now()
+	err := t.tx.Commit(ctx)
+	t.d.observe(ctx, "COMMIT", nil, time.Since(start), err)
+	return err
+}
+
+func (t *instrumentedTx) Rollback(ctx context.Context) error {
+	start := time.Now()
+	err := t.tx.Rollback(ctx)
+	t.d.observe(ctx, "ROLLBACK", nil, time.Since(start), err)
+	return err
+}
+
+// Unwrap mengembalikan Tx yang dibungkus, misal *PostgresTx untuk akses PgxTx.
+func (t *instrumentedTx) Unwrap() Tx {
+	return t.tx
+}
+
+// instrumentedRow mengukur QueryRow sampai Scan, karena driver mengeksekusi query secara lazy.
+type instrumentedRow struct {
+	row     Row
+	start   time.Time
+	observe func(duration time.Duration, err error)
+}
+
+func (r *instrumentedRow) Scan(dest ...interface{}) error {
+	err := r.row.Scan(dest...)
+	status := err
+	if isNoRows(err) {
+		// Tidak ada row bukan kegagalan query
+		status = nil
+	}
+	r.observe(time.Since(r.start), status)
+	return err
+}
+
+// queryOperation mengembalikan kata kunci SQL pertama (huruf kecil) untuk label metric.
+// Kata kunci di luar daftar dikelompokkan sebagai "other" agar cardinality tetap kecil.
+func queryOperation(query string) string {
+	query = strings.TrimLeft(query, " \t\r\n(")
+	end := strings.IndexAny(query, " \t\r\n(;")
+	if end < 0 {
+		end = len(query)
+	}
+	switch op := strings.ToLower(query[:end]); op {
+	case "select", "insert", "update", "delete", "with", "begin", "commit", "rollback",
+		"savepoint", "release", "copy", "create", "alter", "drop":
+		return op
+	default:
+		return "other"
+	}
+}
+
+// unwrapDatabase melepas pembungkus seperti InstrumentedDatabase untuk mendapatkan driver asli,
+// misal untuk jalur cepat khusus PostgreSQL.
+func unwrapDatabase(db Database) Database {
+	for {
+		u, ok := db.(interface{ Unwrap() Database })
+		if !ok {
+			return db
+		}
+		db = u.Unwrap()
+	}
+}
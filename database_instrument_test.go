@@ -0,0 +1,128 @@
+package dim
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInstrumentedDatabase_MetricsAndSlowLog(t *testing.T) {
+	var logs bytes.Buffer
+	metrics := NewMetricsRegistry()
+	var slowQueries []string
+	db := NewInstrumentedDatabase(newTestSQLiteAuthDB(t), DBInstrumentationConfig{
+		SlowQueryThreshold: time.Nanosecond,
+		Logger:             slog.New(slog.NewTextHandler(&logs, nil)),
+		LogArgs:            true,
+		Metrics:            metrics,
+		OnSlowQuery: func(ctx context.Context, query string, duration time.Duration, err error) {
+			slowQueries = append(slowQueries, query)
+		},
+	})
+	ctx := context.Background()
+	store := NewDatabaseAuthUserStore(db)
+
+	if err := db.Exec(ctx, db.Rebind("INSERT INTO users (id, email, password) VALUES ($1, $2, $3)"), "u-1", "ana@example.com", "hash"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.FindByID(ctx, "u-1"); err != nil {
+		t.Fatal(err)
+	}
+	// Tidak ada row bukan kegagalan
+	if _, err := store.FindByID(ctx, "missing"); err == nil {
+		t.Fatal("expected no rows")
+	}
+	if err := db.Exec(ctx, "SELECT * FROM missing_table"); err == nil {
+		t.Fatal("expected error")
+	}
+
+	var out bytes.Buffer
+	metrics.WriteTo(&out)
+	for _, want := range []string{
+		`dim_db_queries_total{operation="insert",status="success"} 1`,
+		`dim_db_queries_total{operation="select",status="success"} 2`,
+		`dim_db_queries_total{operation="select",status="failure"} 1`,
+		`dim_db_query_duration_seconds_count{operation="insert",status="success"} 1`,
+		`dim_db_slow_queries_total{operation="select"} 3`,
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("metrics missing %s\n%s", want, out.String())
+		}
+	}
+
+	if len(slowQueries) != 4 {
+		t.Errorf("OnSlowQuery calls = %d, want 4", len(slowQueries))
+	}
+	if !strings.Contains(logs.String(), "slow query") || strings.Contains(logs.String(), "ana@example.com") {
+		t.Errorf("slow query log not written or args not masked:\n%s", logs.String())
+	}
+}
+
+func TestInstrumentedDatabase_Tx(t *testing.T) {
+	metrics := NewMetricsRegistry()
+	db := NewInstrumentedDatabase(newTestSQLiteAuthDB(t), DBInstrumentationConfig{
+		SlowQueryThreshold: -1,
+		Metrics:            metrics,
+	})
+	store := NewDatabaseAuthUserStore(db)
+
+	// InTx bertingkat tetap memakai transaksi dan SAVEPOINT driver asli
+	err := InTx(context.Background(), db, func(ctx context.Context) error {
+		if err := store.CreateBatch(ctx, []*User{{Email: "ana@example.com", Password: "hash"}}); err != nil {
+			return err
+		}
+		return errors.New("abort")
+	})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if _, total, _ := store.List(context.Background(), ListUsersQuery{}); total != 0 {
+		t.Errorf("users stored = %d, want 0", total)
+	}
+
+	tx, err := db.Begin(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Exec(context.Background(), "DELETE FROM users"); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Commit(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	metrics.WriteTo(&out)
+	for _, want := range []string{
+		`dim_db_queries_total{operation="begin",status="success"} 1`,
+		`dim_db_queries_total{operation="delete",status="success"} 1`,
+		`dim_db_queries_total{operation="commit",status="success"} 1`,
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("metrics missing %s\n%s", want, out.String())
+		}
+	}
+	if err := db.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+}
+
+func TestQueryOperation(t *testing.T) {
+	tests := map[string]string{
+		"SELECT 1":                      "select",
+		"  insert into users values ()": "insert",
+		"(SELECT 1) UNION (SELECT 2)":   "select",
+		"WITH x AS (SELECT 1) SELECT *": "with",
+		"VACUUM":                        "other",
+		"":                              "other",
+	}
+	for query, want := range tests {
+		if got := queryOperation(query); got != want {
+			t.Errorf("queryOperation(%q) = %q, want %q", query, got, want)
+		}
+	}
+}
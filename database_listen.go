@@ -0,0 +1,205 @@
+package dim
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/rand/v2"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Batas backoff reconnect Listen. Berupa variabel agar dapat diperkecil di test.
+var (
+	listenMinBackoff = 100 * time.Millisecond
+	listenMaxBackoff = 30 * time.Second
+)
+
+// Notification adalah pesan NOTIFY yang diterima oleh PostgresDatabase.Listen.
+type Notification struct {
+	// Channel adalah nama channel LISTEN.
+	Channel string
+	// Payload adalah payload NOTIFY (boleh kosong).
+	Payload string
+	// PID adalah process ID backend PostgreSQL yang mengirim NOTIFY.
+	PID uint32
+	// Reconnected bernilai true untuk notifikasi sintetis yang dikirim setelah koneksi LISTEN
+	// pulih. NOTIFY yang dikirim selama koneksi terputus hilang, sehingga consumer sebaiknya
+	// menganggap state-nya basi (misal mengosongkan seluruh cache). Payload dan PID kosong.
+	Reconnected bool
+}
+
+// Listen berlangganan channel NOTIFY PostgreSQL dan mengirim setiap notifikasi ke channel Go
+// yang dikembalikan. Listen memakai satu koneksi khusus yang diambil dari write pool (tidak
+// dikembalikan ke pool), sehingga tidak mengurangi koneksi untuk query biasa setelahnya.
+// Jika koneksi terputus, Listen menyambung ulang dengan exponential backoff (100ms hingga 30s)
+// lalu mengirim Notification dengan Reconnected true.
+//
+// Channel ditutup ketika ctx dibatalkan atau db ditutup. Consumer harus terus membaca channel;
+// notifikasi berikutnya tertahan selama channel penuh.
+//
+// Parameters:
+//   - ctx: lifetime langganan
+//   - channel: nama channel LISTEN (di-quote sebagai identifier, case-sensitive)
+//
+// Returns:
+//   - <-chan Notification: notifikasi yang diterima
+//   - error: jika koneksi pertama atau perintah LISTEN gagal
+//
+// Example:
+//
+//	notifications, err := db.Listen(ctx, "cache_invalidation")
+//	if err != nil {
+//	    return err
+//	}
+//	go func() {
+//	    for n := range notifications {
+//	        if n.Reconnected {
+//	            cache.Clear()
+//	            continue
+//	        }
+//	        cache.Delete(n.Payload)
+//	    }
+//	}()
+func (db *PostgresDatabase) Listen(ctx context.Context, channel string) (<-chan Notification, error) {
+	if channel == "" {
+		return nil, errors.New("listen channel is required")
+	}
+
+	conn, err := db.listenConn(ctx, channel)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan Notification, 64)
+	go db.listenLoop(ctx, channel, conn, out)
+	return out, nil
+}
+
+// Notify mengirim NOTIFY ke channel dengan payload via pg_notify. Di dalam InTx, notifikasi
+// baru terkirim saat transaksi di-commit dan dibuang jika di-rollback.
+//
+// Example:
+//
+//	err := db.Notify(ctx, "cache_invalidation", "user:"+user.ID)
+func (db *PostgresDatabase) Notify(ctx context.Context, channel, payload string) error {
+	return db.Exec(ctx, "SELECT pg_notify($1, $2)", channel, payload)
+}
+
+// listenConn mengambil koneksi dari write pool, melepasnya dari pool, lalu menjalankan LISTEN.
+func (db *PostgresDatabase) listenConn(ctx context.Context, channel string) (*pgx.Conn, error) {
+	pooled, err := db.writePool.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	conn := pooled.Hijack()
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+		conn.Close(context.Background()) //nolint:errcheck
+		return nil, err
+	}
+	return conn, nil
+}
+
+// listenLoop meneruskan notifikasi ke out dan menyambung ulang koneksi yang terputus.
+func (db *PostgresDatabase) listenLoop(ctx context.Context, channel string, conn *pgx.Conn, out chan<- Notification) {
+	defer close(out)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-db.listenDone():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err == nil {
+			select {
+			case out <- Notification{Channel: n.Channel, Payload: n.Payload, PID: n.PID}:
+				continue
+			case <-ctx.Done():
+				conn.Close(context.Background()) //nolint:errcheck
+				return
+			}
+		}
+
+		conn.Close(context.Background()) //nolint:errcheck
+		if ctx.Err() != nil {
+			return
+		}
+		slog.Warn("listen connection lost, reconnecting", "channel", channel, "error", err)
+
+		conn = db.reconnectListen(ctx, channel)
+		if conn == nil {
+			return
+		}
+		select {
+		case out <- Notification{Channel: channel, Reconnected: true}:
+		case <-ctx.Done():
+			conn.Close(context.Background()) //nolint:errcheck
+			return
+		}
+	}
+}
+
+// reconnectListen mencoba listenConn dengan exponential backoff hingga berhasil.
+// Mengembalikan nil jika ctx dibatalkan.
+func (db *PostgresDatabase) reconnectListen(ctx context.Context, channel string) *pgx.Conn {
+	for attempt := 0; ; attempt++ {
+		timer := time.NewTimer(listenBackoff(attempt))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		}
+
+		conn, err := db.listenConn(ctx, channel)
+		if err == nil {
+			slog.Info("listen connection restored", "channel", channel, "attempts", attempt+1)
+			return conn
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+		slog.Warn("listen reconnect failed", "channel", channel, "attempt", attempt+1, "error", err)
+	}
+}
+
+// listenBackoff mengembalikan jeda sebelum percobaan reconnect ke-attempt (mulai dari 0):
+// listenMinBackoff dikali dua setiap percobaan, dibatasi listenMaxBackoff, dengan jitter
+// hingga 50% agar listener dari banyak instance tidak menyambung ulang bersamaan.
+func listenBackoff(attempt int) time.Duration {
+	d := listenMaxBackoff
+	if attempt < 30 {
+		d = min(listenMinBackoff<<attempt, listenMaxBackoff)
+	}
+	return d/2 + rand.N(d/2+1)
+}
+
+// listenDone mengembalikan channel yang ditutup oleh Close untuk menghentikan semua Listen.
+func (db *PostgresDatabase) listenDone() <-chan struct{} {
+	db.listenMu.Lock()
+	defer db.listenMu.Unlock()
+	if db.listenClosed == nil {
+		db.listenClosed = make(chan struct{})
+	}
+	return db.listenClosed
+}
+
+// stopListeners menghentikan semua Listen yang berjalan. Aman dipanggil lebih dari sekali.
+func (db *PostgresDatabase) stopListeners() {
+	done := db.listenDone()
+	db.listenMu.Lock()
+	defer db.listenMu.Unlock()
+	select {
+	case <-done:
+	default:
+		close(db.listenClosed)
+	}
+}
@@ -0,0 +1,78 @@
+package dim
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestListenBackoff(t *testing.T) {
+	for attempt, base := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond} {
+		for i := 0; i < 20; i++ {
+			if d := listenBackoff(attempt); d < base/2 || d > base {
+				t.Fatalf("listenBackoff(%d) = %v, want within [%v, %v]", attempt, d, base/2, base)
+			}
+		}
+	}
+	if d := listenBackoff(100); d < listenMaxBackoff/2 || d > listenMaxBackoff {
+		t.Errorf("listenBackoff(100) = %v, want capped at %v", d, listenMaxBackoff)
+	}
+}
+
+func TestPostgresDatabase_Listen(t *testing.T) {
+	db := newTestPostgresDB(t)
+	listenMinBackoff = 10 * time.Millisecond
+	t.Cleanup(func() { listenMinBackoff = 100 * time.Millisecond })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	notifications, err := db.Listen(ctx, "dim_Test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	receive := func() Notification {
+		t.Helper()
+		select {
+		case n, ok := <-notifications:
+			if !ok {
+				t.Fatal("notification channel closed")
+			}
+			return n
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting for notification")
+		}
+		return Notification{}
+	}
+
+	if err := db.Notify(ctx, "dim_Test", "user:1"); err != nil {
+		t.Fatal(err)
+	}
+	if n := receive(); n.Channel != "dim_Test" || n.Payload != "user:1" || n.Reconnected {
+		t.Errorf("notification = %+v", n)
+	}
+
+	// Putuskan koneksi listener dari sisi server
+	if err := db.Exec(ctx, "SELECT pg_terminate_backend(pid) FROM pg_stat_activity WHERE query LIKE 'LISTEN%' AND pid <> pg_backend_pid()"); err != nil {
+		t.Fatal(err)
+	}
+	if n := receive(); !n.Reconnected {
+		t.Errorf("expected reconnect notification, got %+v", n)
+	}
+	if err := db.Notify(ctx, "dim_Test", "user:2"); err != nil {
+		t.Fatal(err)
+	}
+	if n := receive(); n.Payload != "user:2" {
+		t.Errorf("notification after reconnect = %+v", n)
+	}
+
+	cancel()
+	select {
+	case _, ok := <-notifications:
+		if ok {
+			t.Error("expected channel to be closed")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("channel not closed after cancel")
+	}
+}
@@ -0,0 +1,349 @@
+package dim
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// mysqlReturningPattern memisahkan klausa RETURNING di akhir query.
+var mysqlReturningPattern = regexp.MustCompile(`(?is)^(.*?)\s+RETURNING\s+(.+?)\s*;?\s*$`)
+
+// mysqlInsertPattern mengambil nama tabel dari statement INSERT.
+var mysqlInsertPattern = regexp.MustCompile("(?is)^\\s*INSERT\\s+(?:IGNORE\\s+)?INTO\\s+([^\\s(]+)")
+
+// MySQLDatabase adalah implementasi Database untuk MySQL dan MariaDB.
+//
+// Query ditulis dengan placeholder PostgreSQL ($1, $2, ...) seperti driver lain; placeholder
+// ditulis ulang menjadi ? saat eksekusi, termasuk placeholder yang dipakai ulang atau tidak
+// berurutan. INSERT ... RETURNING diemulasikan dengan LAST_INSERT_ID() sehingga tabel harus
+// memiliki kolom id AUTO_INCREMENT; INSERT yang tidak menulis baris mengembalikan sql.ErrNoRows.
+type MySQLDatabase struct {
+	db *sql.DB
+}
+
+// NewMySQLDatabase membuat koneksi database MySQL/MariaDB.
+// Koneksi menggunakan parseTime dan zona waktu sesi UTC agar NOW() dan kolom TIMESTAMP
+// konsisten dengan waktu yang ditulis framework.
+//
+// Parameters:
+//   - config: DatabaseConfig (WriteHost, Port default 3306, Database, Username, Password, MaxConns,
+//     SSLMode, RuntimeParams sebagai system variable sesi). WriteHost berawalan "/" dianggap unix socket.
+//
+// Returns:
+//   - *MySQLDatabase: instance database yang siap digunakan
+//   - error: error jika koneksi gagal
+//
+// Example:
+//
+//	db, err := dim.NewMySQLDatabase(dim.DatabaseConfig{
+//	    Driver:    "mysql",
+//	    WriteHost: "127.0.0.1",
+//	    Database:  "app",
+//	    Username:  "app",
+//	    Password:  os.Getenv("DB_PASSWORD"),
+//	})
+func NewMySQLDatabase(config DatabaseConfig) (*MySQLDatabase, error) {
+	cfg, err := mysqlConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	connector, err := mysql.NewConnector(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create mysql connector: %w", err)
+	}
+
+	db := sql.OpenDB(connector)
+	if config.MaxConns > 0 {
+		db.SetMaxOpenConns(config.MaxConns)
+		db.SetMaxIdleConns(config.MaxConns)
+	}
+	// Hindari koneksi yang sudah ditutup server karena wait_timeout
+	db.SetConnMaxLifetime(5 * time.Minute)
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to ping mysql database: %w", err)
+	}
+
+	return &MySQLDatabase{db: db}, nil
+}
+
+// mysqlConfig menerjemahkan DatabaseConfig menjadi konfigurasi go-sql-driver/mysql.
+func mysqlConfig(config DatabaseConfig) (*mysql.Config, error) {
+	cfg := mysql.NewConfig()
+	cfg.User = config.Username
+	cfg.Passwd = config.Password
+	cfg.DBName = config.Database
+	cfg.ParseTime = true
+	cfg.Loc = time.UTC
+	// Migration framework mengirim beberapa statement DDL dalam satu Exec
+	cfg.MultiStatements = true
+
+	if strings.HasPrefix(config.WriteHost, "/") {
+		cfg.Net = "unix"
+		cfg.Addr = config.WriteHost
+	} else {
+		port := config.Port
+		if port == 0 {
+			port = 3306
+		}
+		host := config.WriteHost
+		if host == "" {
+			host = "127.0.0.1"
+		}
+		cfg.Net = "tcp"
+		cfg.Addr = net.JoinHostPort(host, strconv.Itoa(port))
+	}
+
+	switch config.SSLMode {
+	case "", "disable":
+	case "allow", "prefer":
+		cfg.TLSConfig = "preferred"
+	case "require":
+		cfg.TLSConfig = "skip-verify"
+	case "verify-ca", "verify-full":
+		cfg.TLSConfig = "true"
+	default:
+		return nil, fmt.Errorf("unsupported ssl mode for mysql: %s", config.SSLMode)
+	}
+
+	cfg.Params = map[string]string{"time_zone": "'+00:00'"}
+	for key, value := range config.RuntimeParams {
+		cfg.Params[key] = value
+	}
+
+	return cfg, nil
+}
+
+// Exec mengeksekusi write query (INSERT, UPDATE, DELETE, DDL).
+func (db *MySQLDatabase) Exec(ctx context.Context, query string, args ...interface{}) error {
+	if tx, ok := contextTx(ctx, db); ok {
+		return tx.Exec(ctx, query, args...)
+	}
+	return mysqlExec(ctx, db.db, query, args)
+}
+
+// Query mengeksekusi read query dan mengembalikan banyak baris.
+func (db *MySQLDatabase) Query(ctx context.Context, query string, args ...interface{}) (Rows, error) {
+	if tx, ok := contextTx(ctx, db); ok {
+		return tx.Query(ctx, query, args...)
+	}
+	return mysqlQuery(ctx, db.db, query, args)
+}
+
+// QueryRow mengeksekusi query yang mengembalikan satu baris.
+// INSERT ... RETURNING diemulasikan dengan membaca baris berdasarkan LAST_INSERT_ID().
+func (db *MySQLDatabase) QueryRow(ctx context.Context, query string, args ...interface{}) Row {
+	if tx, ok := contextTx(ctx, db); ok {
+		return tx.QueryRow(ctx, query, args...)
+	}
+	return mysqlQueryRow(ctx, db.db, query, args)
+}
+
+// Begin memulai transaksi baru.
+func (db *MySQLDatabase) Begin(ctx context.Context) (Tx, error) {
+	tx, err := db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	return &MySQLTx{tx: tx}, nil
+}
+
+// WithTx menjalankan fn di dalam transaksi dengan commit/rollback otomatis.
+// Transaksi disimpan di ctx yang diterima fn; pemanggilan bertingkat memakai SAVEPOINT.
+func (db *MySQLDatabase) WithTx(ctx context.Context, fn TransactionFunc) error {
+	return runTx(ctx, db, fn)
+}
+
+// Close menutup koneksi database.
+func (db *MySQLDatabase) Close() error {
+	return db.db.Close()
+}
+
+// DriverName mengembalikan nama driver.
+func (db *MySQLDatabase) DriverName() string {
+	return "mysql"
+}
+
+// Rebind mengembalikan query apa adanya. Placeholder $n ditulis ulang saat eksekusi agar
+// argumen dapat diurutkan ulang untuk placeholder yang dipakai lebih dari sekali.
+func (db *MySQLDatabase) Rebind(query string) string {
+	return query
+}
+
+// MySQLTx mengimplementasikan Tx untuk MySQL.
+type MySQLTx struct {
+	tx *sql.Tx
+}
+
+func (t *MySQLTx) Exec(ctx context.Context, query string, args ...interface{}) error {
+	return mysqlExec(ctx, t.tx, query, args)
+}
+
+func (t *MySQLTx) Query(ctx context.Context, query string, args ...interface{}) (Rows, error) {
+	return mysqlQuery(ctx, t.tx, query, args)
+}
+
+func (t *MySQLTx) QueryRow(ctx context.Context, query string, args ...interface{}) Row {
+	return mysqlQueryRow(ctx, t.tx, query, args)
+}
+
+func (t *MySQLTx) Commit(ctx context.Context) error {
+	return t.tx.Commit()
+}
+
+func (t *MySQLTx) Rollback(ctx context.Context) error {
+	return t.tx.Rollback()
+}
+
+// mysqlExecutor adalah method yang dimiliki *sql.DB dan *sql.Tx.
+type mysqlExecutor interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+func mysqlExec(ctx context.Context, ex mysqlExecutor, query string, args []interface{}) error {
+	query, args, err := bindMySQL(query, args)
+	if err != nil {
+		return err
+	}
+	_, err = ex.ExecContext(ctx, query, args...)
+	return err
+}
+
+func mysqlQuery(ctx context.Context, ex mysqlExecutor, query string, args []interface{}) (Rows, error) {
+	query, args, err := bindMySQL(query, args)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := ex.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return &sqliteRows{rows: rows}, nil
+}
+
+func mysqlQueryRow(ctx context.Context, ex mysqlExecutor, query string, args []interface{}) Row {
+	insert, table, columns, returning, err := splitMySQLReturning(query)
+	if err != nil {
+		return &mysqlRow{err: err}
+	}
+
+	query, args, err = bindMySQL(insert, args)
+	if err != nil {
+		return &mysqlRow{err: err}
+	}
+	if !returning {
+		return &mysqlRow{row: ex.QueryRowContext(ctx, query, args...)}
+	}
+
+	result, err := ex.ExecContext(ctx, query, args...)
+	if err != nil {
+		return &mysqlRow{err: err}
+	}
+	// INSERT yang tidak menulis baris (ON DUPLICATE KEY UPDATE tanpa perubahan atau INSERT IGNORE)
+	// berperilaku seperti ON CONFLICT DO NOTHING RETURNING: tidak ada baris.
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return &mysqlRow{err: sql.ErrNoRows}
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return &mysqlRow{err: err}
+	}
+	if id == 0 {
+		return &mysqlRow{err: fmt.Errorf("mysql: RETURNING emulation requires an AUTO_INCREMENT id column on %s", table)}
+	}
+	return &mysqlRow{row: ex.QueryRowContext(ctx, "SELECT "+columns+" FROM "+table+" WHERE id = ?", id)}
+}
+
+// splitMySQLReturning memisahkan "INSERT ... RETURNING cols" menjadi statement INSERT,
+// nama tabel, dan kolom. returning false jika query tidak memiliki klausa RETURNING.
+func splitMySQLReturning(query string) (insert, table, columns string, returning bool, err error) {
+	m := mysqlReturningPattern.FindStringSubmatch(query)
+	if m == nil {
+		return query, "", "", false, nil
+	}
+	t := mysqlInsertPattern.FindStringSubmatch(m[1])
+	if t == nil {
+		return "", "", "", false, fmt.Errorf("mysql: RETURNING is only supported for INSERT statements")
+	}
+	return m[1], t[1], m[2], true, nil
+}
+
+// bindMySQL menulis ulang placeholder $n menjadi ? dan menyusun argumen sesuai urutan
+// kemunculan placeholder. Placeholder di dalam string literal atau identifier ber-quote
+// diabaikan. Query tanpa placeholder $n dikembalikan apa adanya.
+func bindMySQL(query string, args []interface{}) (string, []interface{}, error) {
+	if !strings.Contains(query, "$") {
+		return query, args, nil
+	}
+
+	var b strings.Builder
+	b.Grow(len(query))
+	bound := make([]interface{}, 0, len(args))
+	found := false
+	var quote byte
+
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		if quote != 0 {
+			b.WriteByte(c)
+			if c == '\\' && quote != '`' && i+1 < len(query) {
+				i++
+				b.WriteByte(query[i])
+			} else if c == quote {
+				quote = 0
+			}
+			continue
+		}
+
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+			b.WriteByte(c)
+		case c == '$' && i+1 < len(query) && query[i+1] >= '0' && query[i+1] <= '9':
+			j := i + 1
+			for j < len(query) && query[j] >= '0' && query[j] <= '9' {
+				j++
+			}
+			n, _ := strconv.Atoi(query[i+1 : j])
+			if n < 1 || n > len(args) {
+				return "", nil, fmt.Errorf("mysql: placeholder $%d has no matching argument (got %d)", n, len(args))
+			}
+			b.WriteByte('?')
+			bound = append(bound, args[n-1])
+			found = true
+			i = j - 1
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	if !found {
+		return query, args, nil
+	}
+	return b.String(), bound, nil
+}
+
+// mysqlRow mengimplementasikan Row dengan error yang tertunda sampai Scan.
+type mysqlRow struct {
+	row *sql.Row
+	err error
+}
+
+func (r *mysqlRow) Scan(dest ...interface{}) error {
+	if r.err != nil {
+		return r.err
+	}
+	return r.row.Scan(dest...)
+}
@@ -0,0 +1,372 @@
+package dim
+
+import (
+	"context"
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// newTestMySQLDB creates a MySQLDatabase from env vars, or skips the test.
+func newTestMySQLDB(t *testing.T) *MySQLDatabase {
+	t.Helper()
+	host := os.Getenv("TEST_MYSQL_HOST")
+	if host == "" {
+		t.Skip("TEST_MYSQL_HOST not set — skipping MySQL integration test")
+	}
+	db, err := NewMySQLDatabase(DatabaseConfig{
+		Driver:    "mysql",
+		WriteHost: host,
+		Database:  os.Getenv("TEST_MYSQL_DB"),
+		Username:  os.Getenv("TEST_MYSQL_USER"),
+		Password:  os.Getenv("TEST_MYSQL_PASS"),
+		MaxConns:  2,
+	})
+	if err != nil {
+		t.Fatalf("NewMySQLDatabase: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestBindMySQL(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		args      []interface{}
+		wantQuery string
+		wantArgs  []interface{}
+	}{
+		{"sequential", "SELECT * FROM users WHERE id = $1 AND email = $2", []interface{}{1, "a"},
+			"SELECT * FROM users WHERE id = ? AND email = ?", []interface{}{1, "a"}},
+		{"reused", "UPDATE t SET a = IF(b < $2, $1, a), c = $2", []interface{}{"x", 5},
+			"UPDATE t SET a = IF(b < ?, ?, a), c = ?", []interface{}{5, "x", 5}},
+		{"two digits", "VALUES ($10, $1)", []interface{}{1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
+			"VALUES (?, ?)", []interface{}{10, 1}},
+		{"string literal", "SELECT '$1', `$2`, \"it\\\"s $1\" FROM t WHERE a = $1", []interface{}{"v"},
+			"SELECT '$1', `$2`, \"it\\\"s $1\" FROM t WHERE a = ?", []interface{}{"v"}},
+		{"native placeholders", "SELECT * FROM t WHERE a = ?", []interface{}{1},
+			"SELECT * FROM t WHERE a = ?", []interface{}{1}},
+		{"escaped backslash", `SELECT a FROM t WHERE a LIKE $1 ESCAPE '\\' AND b = $2`, []interface{}{"x", "y"},
+			`SELECT a FROM t WHERE a LIKE ? ESCAPE '\\' AND b = ?`, []interface{}{"x", "y"}},
+		{"dollar amount", "SELECT '$' AS c, price FROM t", nil,
+			"SELECT '$' AS c, price FROM t", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args, err := bindMySQL(tt.query, tt.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if query != tt.wantQuery || !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("bindMySQL() = %q %v, want %q %v", query, args, tt.wantQuery, tt.wantArgs)
+			}
+		})
+	}
+
+	if _, _, err := bindMySQL("SELECT $2", []interface{}{1}); err == nil {
+		t.Error("placeholder without argument should fail")
+	}
+}
+
+func TestSplitMySQLReturning(t *testing.T) {
+	insert, table, columns, ok, err := splitMySQLReturning(`INSERT INTO refresh_tokens (user_id, token_hash)
+		 VALUES ($1, $2)
+		 RETURNING id, created_at`)
+	if err != nil || !ok || table != "refresh_tokens" || columns != "id, created_at" {
+		t.Fatalf("split = %q %q %v %v", table, columns, ok, err)
+	}
+	if insert != "INSERT INTO refresh_tokens (user_id, token_hash)\n\t\t VALUES ($1, $2)" {
+		t.Errorf("insert = %q", insert)
+	}
+
+	if _, _, _, ok, err := splitMySQLReturning("SELECT id FROM users WHERE email = $1"); ok || err != nil {
+		t.Errorf("plain query: ok = %v, err = %v", ok, err)
+	}
+	if _, _, _, _, err := splitMySQLReturning("UPDATE users SET name = $1 RETURNING id"); err == nil {
+		t.Error("UPDATE ... RETURNING should fail")
+	}
+}
+
+func TestMySQLConfig(t *testing.T) {
+	cfg, err := mysqlConfig(DatabaseConfig{
+		WriteHost:     "db.internal",
+		Database:      "app",
+		Username:      "user",
+		Password:      "secret",
+		SSLMode:       "require",
+		RuntimeParams: map[string]string{"sql_mode": "'STRICT_ALL_TABLES'"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Addr != "db.internal:3306" || cfg.Net != "tcp" || cfg.DBName != "app" || !cfg.ParseTime ||
+		cfg.Loc != time.UTC || cfg.TLSConfig != "skip-verify" {
+		t.Errorf("config = %+v", cfg)
+	}
+	if cfg.Params["time_zone"] != "'+00:00'" || cfg.Params["sql_mode"] != "'STRICT_ALL_TABLES'" {
+		t.Errorf("params = %v", cfg.Params)
+	}
+
+	cfg, _ = mysqlConfig(DatabaseConfig{WriteHost: "/var/run/mysqld/mysqld.sock", Port: 3307})
+	if cfg.Net != "unix" || cfg.Addr != "/var/run/mysqld/mysqld.sock" {
+		t.Errorf("unix socket config = %s %s", cfg.Net, cfg.Addr)
+	}
+
+	if _, err := mysqlConfig(DatabaseConfig{SSLMode: "bogus"}); err == nil {
+		t.Error("unknown ssl mode should fail")
+	}
+}
+
+func TestMySQLDatabase_AuthStack(t *testing.T) {
+	db := newTestMySQLDB(t)
+	ctx := context.Background()
+
+	migrations := append(GetFrameworkMigrations(), GetMFAMigrations()...)
+	migrations = append(migrations, GetMagicLinkMigrations()...)
+	for i := len(migrations) - 1; i >= 0; i-- {
+		migrations[i].Down(db)
+	}
+	db.Exec(ctx, "DROP TABLE IF EXISTS migrations")
+	if err := RunMigrations(db, migrations); err != nil {
+		t.Fatalf("RunMigrations: %v", err)
+	}
+
+	userID := "7d1e5a8e-2f7c-4c3e-9a61-8b4f1f3c2d10"
+	if err := db.Exec(ctx, "INSERT INTO users (id, email, password) VALUES ($1, $2, $3)", userID, "ana@example.com", "hash"); err != nil {
+		t.Fatal(err)
+	}
+	user, err := NewDatabaseAuthUserStore(db).FindByEmail(ctx, "ana@example.com")
+	if err != nil || user.GetID() != userID {
+		t.Fatalf("FindByEmail = %v, %v", user, err)
+	}
+
+	// Filter like memakai ESCAPE '\\' yang valid di MySQL
+	users, total, err := NewDatabaseAuthUserStore(db).List(ctx, ListUsersQuery{
+		Filters: []FilterCondition{{Field: "email", Op: FilterOpLike, Values: []string{"ana@"}}},
+	})
+	if err != nil || total != 1 || len(users) != 1 {
+		t.Errorf("List(like) = %d users, total %d, %v", len(users), total, err)
+	}
+
+	db.Exec(ctx, "DROP TABLE IF EXISTS slug_test_posts")
+	if err := db.Exec(ctx, "CREATE TABLE slug_test_posts (id INT AUTO_INCREMENT PRIMARY KEY, slug VARCHAR(100) UNIQUE)"); err != nil {
+		t.Fatal(err)
+	}
+	defer db.Exec(ctx, "DROP TABLE IF EXISTS slug_test_posts")
+	for _, want := range []string{"post", "post-2"} {
+		err := db.WithTx(ctx, func(ctx context.Context, tx Tx) error {
+			slug, err := EnsureUniqueSlug(ctx, tx, "slug_test_posts", "slug", "post")
+			if err != nil {
+				return err
+			}
+			if slug != want {
+				t.Errorf("EnsureUniqueSlug = %q, want %q", slug, want)
+			}
+			return tx.Exec(ctx, "INSERT INTO slug_test_posts (slug) VALUES ($1)", slug)
+		})
+		if err != nil {
+			t.Fatalf("EnsureUniqueSlug: %v", err)
+		}
+	}
+
+	tokens := NewDatabaseTokenStore(db)
+	token := &RefreshToken{UserID: userID, TokenHash: "h1", UserAgent: "test", IPAddress: "127.0.0.1", ExpiresAt: time.Now().Add(time.Hour)}
+	if err := tokens.SaveRefreshToken(ctx, token); err != nil {
+		t.Fatalf("SaveRefreshToken: %v", err)
+	}
+	if token.ID == 0 || token.CreatedAt.IsZero() {
+		t.Errorf("RETURNING emulation did not populate token: %+v", token)
+	}
+	if err := tokens.RevokeRefreshToken(ctx, "h1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := tokens.RevokeRefreshToken(ctx, "h1"); !errors.Is(err, ErrRefreshTokenRevoked) {
+		t.Errorf("second RevokeRefreshToken = %v, want ErrRefreshTokenRevoked", err)
+	}
+	found, err := tokens.FindRefreshToken(ctx, "h1")
+	if err != nil || found.RevokedAt == nil {
+		t.Errorf("FindRefreshToken = %+v, %v", found, err)
+	}
+
+	blocklist := NewDatabaseBlocklist(db)
+	blocklist.Invalidate(ctx, "jti-1", time.Minute)
+	if blocked, err := blocklist.IsRevoked(ctx, "jti-1"); err != nil || !blocked {
+		t.Errorf("IsRevoked = %v, %v", blocked, err)
+	}
+
+	limiter := NewDatabaseRateLimitStore(db)
+	for i := 1; i <= 3; i++ {
+		allowed, err := limiter.Allow(ctx, "login:ana", 2, time.Minute)
+		if err != nil || allowed != (i <= 2) {
+			t.Errorf("Allow #%d = %v, %v", i, allowed, err)
+		}
+	}
+
+	// Challenge MFA dinaikkan dan dipakai dengan SELECT ... FOR UPDATE
+	if err := tokens.SaveMFAChallenge(ctx, &MFAChallenge{TokenHash: "mfa1", UserID: userID, ExpiresAt: time.Now().Add(time.Minute)}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i <= 3; i++ {
+		n, err := tokens.IncrementMFAChallengeAttempts(ctx, "mfa1", 2)
+		if (i <= 2 && (err != nil || n != i)) || (i > 2 && !errors.Is(err, ErrMFAChallengeNotFound)) {
+			t.Errorf("IncrementMFAChallengeAttempts #%d = %d, %v", i, n, err)
+		}
+	}
+	if err := tokens.ConsumeMFAChallenge(ctx, "mfa1"); err != nil {
+		t.Errorf("ConsumeMFAChallenge = %v", err)
+	}
+	if err := tokens.ConsumeMFAChallenge(ctx, "mfa1"); !errors.Is(err, ErrMFAChallengeNotFound) {
+		t.Errorf("second ConsumeMFAChallenge = %v, want ErrMFAChallengeNotFound", err)
+	}
+
+	// Magic link dipakai dengan SELECT ... FOR UPDATE
+	if err := tokens.SaveMagicLinkToken(ctx, &MagicLinkToken{UserID: userID, TokenHash: "ml1", ExpiresAt: time.Now().Add(time.Minute)}); err != nil {
+		t.Fatal(err)
+	}
+	if id, err := tokens.ConsumeMagicLinkToken(ctx, "ml1", time.Now()); err != nil || id != userID {
+		t.Errorf("ConsumeMagicLinkToken = %q, %v", id, err)
+	}
+	if _, err := tokens.ConsumeMagicLinkToken(ctx, "ml1", time.Now()); !errors.Is(err, ErrMagicLinkNotFound) {
+		t.Errorf("second ConsumeMagicLinkToken = %v, want ErrMagicLinkNotFound", err)
+	}
+
+	// Transaksi yang gagal tidak meninggalkan data
+	errRollback := errors.New("rollback")
+	err = db.WithTx(ctx, func(ctx context.Context, tx Tx) error {
+		tx.Exec(ctx, "DELETE FROM refresh_tokens WHERE token_hash = $1", "h1")
+		return errRollback
+	})
+	if _, findErr := tokens.FindRefreshToken(ctx, "h1"); !errors.Is(err, errRollback) || findErr != nil {
+		t.Errorf("WithTx err = %v, find err = %v", err, findErr)
+	}
+}
+
+func TestMySQLDatabase_ModuleStores(t *testing.T) {
+	db := newTestMySQLDB(t)
+	ctx := context.Background()
+
+	migrations := append(GetUserMigrations(), GetOrganizationMigrations()...)
+	migrations = append(migrations, GetBillingMigrations()...)
+	migrations = append(migrations, GetFilterPresetMigrations()...)
+	for i := len(migrations) - 1; i >= 0; i-- {
+		migrations[i].Down(db)
+	}
+	db.Exec(ctx, "DROP TABLE IF EXISTS migrations")
+	if err := RunMigrations(db, migrations); err != nil {
+		t.Fatalf("RunMigrations: %v", err)
+	}
+
+	userID := "0b6f3c52-7a1d-4e0a-b7d2-5c9e8f1a2b34"
+	if err := db.Exec(ctx, "INSERT INTO users (id, email, password) VALUES ($1, $2, $3)", userID, "budi@example.com", "hash"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Upsert membership memakai ON DUPLICATE KEY UPDATE lalu membaca created_at
+	orgs := NewDatabaseOrganizationStore(db)
+	org := &Organization{ID: "5f0c7a9e-3b2d-4c1e-8a6f-9d7b2e4c1a05", Name: "Acme"}
+	if err := orgs.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+	for _, role := range []string{OrgRoleMember, OrgRoleAdmin} {
+		m := &OrgMembership{OrgID: org.ID, UserID: userID, Role: role}
+		if err := orgs.SaveMembership(ctx, m); err != nil || m.CreatedAt.IsZero() {
+			t.Fatalf("SaveMembership(%s) = %+v, %v", role, m, err)
+		}
+	}
+	if m, err := orgs.FindMembership(ctx, org.ID, userID); err != nil || m.Role != OrgRoleAdmin {
+		t.Errorf("FindMembership = %+v, %v", m, err)
+	}
+	inv := &OrgInvitation{OrgID: org.ID, Email: "c@example.com", Role: OrgRoleMember, InvitedBy: userID, TokenHash: "inv1", ExpiresAt: time.Now().Add(time.Hour)}
+	if err := orgs.SaveInvitation(ctx, inv); err != nil {
+		t.Fatal(err)
+	}
+	if err := orgs.ConsumeInvitation(ctx, "inv1", time.Now()); err != nil {
+		t.Errorf("ConsumeInvitation = %v", err)
+	}
+	if err := orgs.ConsumeInvitation(ctx, "inv1", time.Now()); !errors.Is(err, ErrInvitationNotFound) {
+		t.Errorf("second ConsumeInvitation = %v, want ErrInvitationNotFound", err)
+	}
+
+	billing := NewDatabaseBillingStore(db)
+	for _, email := range []string{"a@example.com", "b@example.com"} {
+		if err := billing.UpsertCustomer(ctx, &BillingCustomer{ID: "cus_1", OwnerID: userID, Email: email}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if c, err := billing.FindCustomer(ctx, "cus_1"); err != nil || c.Email != "b@example.com" {
+		t.Errorf("FindCustomer = %+v, %v", c, err)
+	}
+	for i := 0; i < 2; i++ {
+		processed, err := billing.BeginEvent(ctx, &BillingEvent{ID: "evt_1", Type: "customer.created", Payload: []byte("{}")})
+		if err != nil || processed {
+			t.Errorf("BeginEvent #%d = %v, %v", i+1, processed, err)
+		}
+	}
+
+	presets := NewDatabaseFilterPresetStore(db)
+	preset := &FilterPreset{UserID: userID, Resource: "products", Name: "cheap", Filters: map[string][]string{"price": {"lte:100"}}}
+	if err := presets.CreatePreset(ctx, preset); err != nil || preset.ID == 0 {
+		t.Fatalf("CreatePreset = %+v, %v", preset, err)
+	}
+	if err := presets.CreatePreset(ctx, &FilterPreset{UserID: userID, Resource: "products", Name: "cheap"}); !errors.Is(err, ErrFilterPresetExists) {
+		t.Errorf("duplicate CreatePreset = %v, want ErrFilterPresetExists", err)
+	}
+	if err := presets.UpdatePreset(ctx, preset); err != nil {
+		t.Errorf("UpdatePreset = %v", err)
+	}
+	if err := presets.DeletePreset(ctx, userID, "products", "cheap"); err != nil {
+		t.Errorf("DeletePreset = %v", err)
+	}
+	if err := presets.DeletePreset(ctx, userID, "products", "cheap"); !errors.Is(err, ErrFilterPresetNotFound) {
+		t.Errorf("second DeletePreset = %v, want ErrFilterPresetNotFound", err)
+	}
+}
+
+func TestMySQLDatabase_PermissionStore(t *testing.T) {
+	db := newTestMySQLDB(t)
+	ctx := context.Background()
+
+	migrations := append(GetUserMigrations(), GetRBACMigrations()...)
+	for i := len(migrations) - 1; i >= 0; i-- {
+		migrations[i].Down(db)
+	}
+	db.Exec(ctx, "DROP TABLE IF EXISTS migrations")
+	if err := RunMigrations(db, migrations); err != nil {
+		t.Fatalf("RunMigrations: %v", err)
+	}
+
+	userID := "3c9a1f7e-6d2b-4a8e-9f05-1b7c4e2d8a63"
+	if err := db.Exec(ctx, "INSERT INTO users (id, email, password) VALUES ($1, $2, $3)", userID, "dewi@example.com", "hash"); err != nil {
+		t.Fatal(err)
+	}
+
+	store := NewDatabasePermissionStore(db)
+	for _, description := range []string{"Editor", "Content editor"} {
+		role := &Role{Name: "editor", Description: description}
+		if err := store.SaveRole(ctx, role); err != nil || role.CreatedAt.IsZero() {
+			t.Fatalf("SaveRole(%s) = %+v, %v", description, role, err)
+		}
+	}
+	if role, err := store.FindRole(ctx, "editor"); err != nil || role.Description != "Content editor" {
+		t.Errorf("FindRole = %+v, %v", role, err)
+	}
+
+	// Grant dan assign yang sama dua kali adalah no-op
+	for i := 0; i < 2; i++ {
+		if err := store.GrantPermission(ctx, "editor", "posts.write"); err != nil {
+			t.Fatalf("GrantPermission #%d: %v", i+1, err)
+		}
+		if err := store.AssignRole(ctx, userID, "editor"); err != nil {
+			t.Fatalf("AssignRole #%d: %v", i+1, err)
+		}
+	}
+	if perms, err := store.UserPermissions(ctx, userID); err != nil || !reflect.DeepEqual(perms, []string{"posts.write"}) {
+		t.Errorf("UserPermissions = %v, %v", perms, err)
+	}
+	if err := store.AssignRole(ctx, userID, "missing"); err == nil {
+		t.Error("assigning an unknown role should fail the foreign key")
+	}
+}
@@ -4,9 +4,14 @@ import (
 	"context"
 	"fmt"
 	"maps"
+	"slices"
+	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -44,10 +49,15 @@ func (p *PostgresTx) PgxTx() pgx.Tx {
 // PostgresDatabase is the PostgreSQL implementation of Database interface
 // It supports read/write connection splitting with load balancing on read connections
 type PostgresDatabase struct {
-	writePool   *pgxpool.Pool
-	readPools   []*pgxpool.Pool
-	readIndex   atomic.Uint32
-	hookManager *hookManager
+	writePool       *pgxpool.Pool
+	readPools       []*pgxpool.Pool
+	readIndex       atomic.Uint32
+	hookManager     *hookManager
+	deadlineTimeout bool
+
+	// listenMu menjaga listenClosed, yang ditutup oleh Close untuk menghentikan Listen
+	listenMu     sync.Mutex
+	listenClosed chan struct{}
 }
 
 // NewPostgresDatabase membuat koneksi database PostgreSQL baru dengan mendukung read/write splitting.
@@ -102,10 +112,11 @@ func NewPostgresDatabase(config DatabaseConfig) (*PostgresDatabase, error) {
 	}
 
 	return &PostgresDatabase{
-		writePool:   writePool,
-		readPools:   readPools,
-		readIndex:   atomic.Uint32{},
-		hookManager: hm,
+		writePool:       writePool,
+		readPools:       readPools,
+		readIndex:       atomic.Uint32{},
+		hookManager:     hm,
+		deadlineTimeout: config.StatementTimeoutFromDeadline,
 	}, nil
 }
 
@@ -159,15 +170,17 @@ func NewMigrationDatabase(config DatabaseConfig) (*PostgresDatabase, error) {
 	}
 
 	return &PostgresDatabase{
-		writePool:   pool,
-		readPools:   []*pgxpool.Pool{pool},
-		readIndex:   atomic.Uint32{},
-		hookManager: hm,
+		writePool:       pool,
+		readPools:       []*pgxpool.Pool{pool},
+		readIndex:       atomic.Uint32{},
+		hookManager:     hm,
+		deadlineTimeout: config.StatementTimeoutFromDeadline,
 	}, nil
 }
 
 // Exec mengeksekusi write query (INSERT, UPDATE, DELETE) ke write connection pool.
 // Semua operasi write selalu dikirim ke write pool untuk consistency.
+// Di dalam WithTx/InTx, query dijalankan pada transaction yang tersimpan di ctx.
 // Gunakan sticky mode jika perlu subsequent reads ke write connection yang sama.
 //
 // Parameters:
@@ -182,12 +195,33 @@ func NewMigrationDatabase(config DatabaseConfig) (*PostgresDatabase, error) {
 //
 //	err := db.Exec(ctx, "INSERT INTO users (email, name) VALUES ($1, $2)", email, name)
 func (db *PostgresDatabase) Exec(ctx context.Context, query string, args ...interface{}) error {
-	_, err := db.writePool.Exec(ctx, query, args...)
+	if tx, ok := contextTx(ctx, db); ok {
+		return tx.Exec(ctx, query, args...)
+	}
+
+	timeout, ok, err := db.deadlineStatementTimeout(ctx)
+	if err != nil {
+		return err
+	}
+	if ok {
+		tx, err := beginWithStatementTimeout(ctx, db.writePool, timeout)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, query, args...); err != nil {
+			tx.Rollback(ctx) //nolint:errcheck
+			return err
+		}
+		return tx.Commit(ctx)
+	}
+
+	_, err = db.writePool.Exec(ctx, query, args...)
 	return err
 }
 
 // Query mengeksekusi read query (SELECT) dengan routing based on sticky mode.
 // Menggunakan decision tree untuk menentukan pool mana yang digunakan:
+// 0. Jika ctx berasal dari WithTx/InTx, query memakai transaction tersebut
 // 1. Jika query adalah write operation, route ke write pool
 // 2. Jika sticky mode enabled dan ada write dalam request, route ke write pool
 // 3. Otherwise: route ke read pool dengan round-robin load balancing
@@ -205,8 +239,30 @@ func (db *PostgresDatabase) Exec(ctx context.Context, query string, args ...inte
 //
 //	rows, err := db.Query(ctx, "SELECT id, email FROM users WHERE id = $1", userID)
 func (db *PostgresDatabase) Query(ctx context.Context, query string, args ...interface{}) (Rows, error) {
+	if tx, ok := contextTx(ctx, db); ok {
+		return tx.Query(ctx, query, args...)
+	}
+
 	// Decision tree for routing
 	pool := db.routeReadQuery(query)
+
+	timeout, ok, err := db.deadlineStatementTimeout(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		tx, err := beginWithStatementTimeout(ctx, pool, timeout)
+		if err != nil {
+			return nil, err
+		}
+		rows, err := tx.Query(ctx, query, args...)
+		if err != nil {
+			tx.Rollback(ctx) //nolint:errcheck
+			return nil, err
+		}
+		return &deadlineRows{Rows: rows, ctx: ctx, tx: tx}, nil
+	}
+
 	rows, err := pool.Query(ctx, query, args...)
 	return rows, err
 }
@@ -226,7 +282,20 @@ func (db *PostgresDatabase) Query(ctx context.Context, query string, args ...int
 //
 //	err := db.QueryRow(ctx, "SELECT email FROM users WHERE id = $1", userID).Scan(&email)
 func (db *PostgresDatabase) QueryRow(ctx context.Context, query string, args ...interface{}) Row {
+	if tx, ok := contextTx(ctx, db); ok {
+		return tx.QueryRow(ctx, query, args...)
+	}
+
 	pool := db.routeReadQuery(query)
+
+	timeout, ok, err := db.deadlineStatementTimeout(ctx)
+	if err != nil {
+		return errRow{err: err}
+	}
+	if ok {
+		return queryRowWithStatementTimeout(ctx, pool, timeout, query, args)
+	}
+
 	return pool.QueryRow(ctx, query, args...)
 }
 
@@ -260,6 +329,18 @@ func (db *PostgresDatabase) routeReadQuery(query string) *pgxpool.Pool {
 //	}
 //	defer tx.Rollback(ctx)
 func (db *PostgresDatabase) Begin(ctx context.Context) (Tx, error) {
+	timeout, ok, err := db.deadlineStatementTimeout(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		tx, err := beginWithStatementTimeout(ctx, db.writePool, timeout)
+		if err != nil {
+			return nil, err
+		}
+		return &PostgresTx{tx: tx}, nil
+	}
+
 	tx, err := db.writePool.Begin(ctx)
 	if err != nil {
 		return nil, err
@@ -267,7 +348,124 @@ func (db *PostgresDatabase) Begin(ctx context.Context) (Tx, error) {
 	return &PostgresTx{tx: tx}, nil
 }
 
-// Close menutup semua connection pools (write dan read).
+// deadlineStatementTimeout mengembalikan statement_timeout yang diturunkan dari deadline ctx.
+// Selalu ok=false jika StatementTimeoutFromDeadline tidak diaktifkan.
+func (db *PostgresDatabase) deadlineStatementTimeout(ctx context.Context) (time.Duration, bool, error) {
+	if !db.deadlineTimeout {
+		return 0, false, nil
+	}
+	return statementTimeout(ctx)
+}
+
+// beginWithStatementTimeout memulai transaction dan memasang SET LOCAL statement_timeout
+// sehingga query dibatalkan di sisi server ketika budget request habis.
+// SET LOCAL hanya berlaku sampai transaction selesai, aman untuk pgbouncer transaction mode.
+func beginWithStatementTimeout(ctx context.Context, pool *pgxpool.Pool, timeout time.Duration) (pgx.Tx, error) {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.Exec(ctx, fmt.Sprintf("SET LOCAL statement_timeout = %d", timeout.Milliseconds())); err != nil {
+		tx.Rollback(ctx) //nolint:errcheck
+		return nil, fmt.Errorf("failed to set statement_timeout: %w", err)
+	}
+
+	return tx, nil
+}
+
+// deadlineRows membungkus pgx.Rows yang dijalankan di dalam transaction statement_timeout.
+// Transaction diselesaikan ketika rows ditutup.
+type deadlineRows struct {
+	pgx.Rows
+	ctx context.Context
+	tx  pgx.Tx
+}
+
+func (r *deadlineRows) Close() {
+	r.Rows.Close()
+	if r.Rows.Err() != nil {
+		r.tx.Rollback(r.ctx) //nolint:errcheck
+		return
+	}
+	r.tx.Commit(r.ctx) //nolint:errcheck
+}
+
+// queryRowWithStatementTimeout menjalankan query di dalam transaction statement_timeout dan
+// membaca baris pertama sebelum transaction diselesaikan, sehingga transaction dan koneksi
+// dikembalikan ke pool walaupun Row tidak pernah di-scan.
+func queryRowWithStatementTimeout(ctx context.Context, pool *pgxpool.Pool, timeout time.Duration, query string, args []interface{}) Row {
+	tx, err := beginWithStatementTimeout(ctx, pool, timeout)
+	if err != nil {
+		return errRow{err: err}
+	}
+
+	row, err := readDeadlineRow(ctx, tx, query, args)
+	if err != nil {
+		tx.Rollback(ctx) //nolint:errcheck
+		return errRow{err: err}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return errRow{err: err}
+	}
+	return row
+}
+
+// readDeadlineRow menyalin baris pertama hasil query. Mengembalikan errRow berisi pgx.ErrNoRows
+// jika query tidak menghasilkan baris.
+func readDeadlineRow(ctx context.Context, tx pgx.Tx, query string, args []interface{}) (Row, error) {
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		return errRow{err: pgx.ErrNoRows}, nil
+	}
+
+	// RawValues hanya valid sampai Next/Close berikutnya
+	raw := rows.RawValues()
+	values := make([][]byte, len(raw))
+	for i, v := range raw {
+		if v != nil {
+			values[i] = append([]byte{}, v...)
+		}
+	}
+	row := &deadlineRow{fields: slices.Clone(rows.FieldDescriptions()), values: values}
+
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return row, nil
+}
+
+// deadlineRow adalah baris yang sudah dibaca dari transaction statement_timeout yang sudah
+// selesai. Nilai di-decode saat Scan dengan type map bawaan pgx, karena type map milik koneksi
+// sudah dipakai ulang oleh pool.
+type deadlineRow struct {
+	fields []pgconn.FieldDescription
+	values [][]byte
+}
+
+func (r *deadlineRow) Scan(dest ...interface{}) error {
+	return pgx.ScanRow(pgtype.NewMap(), r.fields, r.values, dest...)
+}
+
+// errRow adalah Row yang selalu mengembalikan error saat Scan.
+type errRow struct {
+	err error
+}
+
+func (r errRow) Scan(dest ...interface{}) error {
+	return r.err
+}
+
+// Close menghentikan semua Listen lalu menutup semua connection pools (write dan read).
 // Harus dipanggil sebelum aplikasi shutdown untuk cleanup yang proper.
 //
 // Returns:
@@ -277,6 +475,7 @@ func (db *PostgresDatabase) Begin(ctx context.Context) (Tx, error) {
 //
 //	defer db.Close()
 func (db *PostgresDatabase) Close() error {
+	db.stopListeners()
 	db.writePool.Close()
 	for _, pool := range db.readPools {
 		// Only close if it's different from writePool
@@ -384,6 +583,8 @@ func formatConnectionString(host string, port int, database, username, password,
 
 // WithTx mengeksekusi function dalam transaction dengan auto rollback/commit.
 // Jika fn return error, transaction di-rollback. Jika sukses, transaction di-commit.
+// Transaction disimpan di ctx yang diterima fn, sehingga Exec/Query/QueryRow pada db dengan ctx
+// tersebut otomatis berjalan di transaction yang sama. WithTx bertingkat memakai SAVEPOINT.
 //
 // Parameters:
 //   - ctx: context untuk membatalkan operasi
@@ -398,22 +599,5 @@ func formatConnectionString(host string, port int, database, username, password,
 //	  return tx.Exec(ctx, "INSERT INTO users VALUES ($1)", email)
 //	})
 func (db *PostgresDatabase) WithTx(ctx context.Context, fn TransactionFunc) error {
-	tx, err := db.Begin(ctx)
-	if err != nil {
-		return err
-	}
-
-	defer func() {
-		if err := recover(); err != nil {
-			tx.Rollback(ctx) //nolint:errcheck
-			panic(err)
-		}
-	}()
-
-	if err := fn(ctx, tx); err != nil {
-		tx.Rollback(ctx) //nolint:errcheck
-		return err
-	}
-
-	return tx.Commit(ctx)
+	return runTx(ctx, db, fn)
 }
@@ -4,7 +4,9 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"net/url"
 	"regexp"
+	"strings"
 	"sync"
 
 	_ "modernc.org/sqlite"
@@ -16,7 +18,13 @@ type SQLiteDatabase struct {
 	mu sync.RWMutex
 }
 
-// NewSQLiteDatabase creates a new SQLite database connection
+// NewSQLiteDatabase creates a new SQLite database connection.
+//
+// Every connection enables foreign keys (so ON DELETE CASCADE in the framework migrations
+// behaves like PostgreSQL) and writes time.Time values in UTC using SQLite's own
+// "YYYY-MM-DD HH:MM:SS" layout, so they compare correctly with CURRENT_TIMESTAMP defaults
+// and work with SQLite date functions. Both can be overridden with the DSN query parameters
+// _pragma=foreign_keys(0), _time_format and _timezone in the Database field.
 //
 // Parameters:
 //   - config: DatabaseConfig containing connection configuration (Database field is used as file path)
@@ -25,7 +33,7 @@ type SQLiteDatabase struct {
 //   - *SQLiteDatabase: database instance ready for use
 //   - error: error if connection fails
 func NewSQLiteDatabase(config DatabaseConfig) (*SQLiteDatabase, error) {
-	db, err := sql.Open("sqlite", config.Database)
+	db, err := sql.Open("sqlite", sqliteDSN(config.Database))
 	if err != nil {
 		return nil, fmt.Errorf("failed to open sqlite database: %w", err)
 	}
@@ -64,14 +72,50 @@ func NewSQLiteDatabase(config DatabaseConfig) (*SQLiteDatabase, error) {
 	return sqliteDB, nil
 }
 
-// Exec executes a write query (INSERT, UPDATE, DELETE)
+// sqliteDSN adds the framework's per-connection defaults to a SQLite path or file: URI.
+// Parameters already present in the DSN are left untouched.
+func sqliteDSN(path string) string {
+	name, rawQuery, _ := strings.Cut(path, "?")
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		// Let the driver report the malformed DSN
+		return path
+	}
+
+	foreignKeys := false
+	for _, pragma := range query["_pragma"] {
+		if strings.HasPrefix(strings.ToLower(strings.TrimSpace(pragma)), "foreign_keys") {
+			foreignKeys = true
+		}
+	}
+	if !foreignKeys {
+		query.Add("_pragma", "foreign_keys(1)")
+	}
+	if !query.Has("_time_format") {
+		query.Set("_time_format", "sqlite")
+	}
+	if !query.Has("_timezone") {
+		query.Set("_timezone", "UTC")
+	}
+
+	return name + "?" + query.Encode()
+}
+
+// Exec executes a write query (INSERT, UPDATE, DELETE).
+// Inside WithTx/InTx the query runs on the transaction stored in ctx.
 func (db *SQLiteDatabase) Exec(ctx context.Context, query string, args ...interface{}) error {
+	if tx, ok := contextTx(ctx, db); ok {
+		return tx.Exec(ctx, query, args...)
+	}
 	_, err := db.db.ExecContext(ctx, query, args...)
 	return err
 }
 
 // Query executes a read query (SELECT) and returns multiple rows
 func (db *SQLiteDatabase) Query(ctx context.Context, query string, args ...interface{}) (Rows, error) {
+	if tx, ok := contextTx(ctx, db); ok {
+		return tx.Query(ctx, query, args...)
+	}
 	rows, err := db.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
@@ -81,6 +125,9 @@ func (db *SQLiteDatabase) Query(ctx context.Context, query string, args ...inter
 
 // QueryRow executes a read query that returns a single row
 func (db *SQLiteDatabase) QueryRow(ctx context.Context, query string, args ...interface{}) Row {
+	if tx, ok := contextTx(ctx, db); ok {
+		return tx.QueryRow(ctx, query, args...)
+	}
 	row := db.db.QueryRowContext(ctx, query, args...)
 	return &sqliteRow{row: row}
 }
@@ -110,26 +157,10 @@ func (db *SQLiteDatabase) Rebind(query string) string {
 	return re.ReplaceAllString(query, "?")
 }
 
-// WithTx executes a function within a transaction with auto rollback/commit
+// WithTx executes a function within a transaction with auto rollback/commit.
+// The transaction is stored in the ctx passed to fn; nested calls use savepoints.
 func (db *SQLiteDatabase) WithTx(ctx context.Context, fn TransactionFunc) error {
-	tx, err := db.Begin(ctx)
-	if err != nil {
-		return err
-	}
-
-	defer func() {
-		if p := recover(); p != nil {
-			tx.Rollback(ctx)
-			panic(p)
-		}
-	}()
-
-	if err := fn(ctx, tx); err != nil {
-		tx.Rollback(ctx)
-		return err
-	}
-
-	return tx.Commit(ctx)
+	return runTx(ctx, db, fn)
 }
 
 // SQLiteTx implements Tx interface for SQLite
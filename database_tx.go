@@ -0,0 +1,121 @@
+package dim
+
+import (
+	"context"
+	"fmt"
+)
+
+const txKey contextKey = "db_tx"
+
+// contextTxValue menghubungkan transaksi aktif dengan database pemiliknya, sehingga transaksi
+// database lain di context yang sama tidak ikut terpakai.
+type contextTxValue struct {
+	db    Database
+	tx    Tx
+	depth int
+}
+
+// contextTx mengembalikan transaksi aktif milik db dari context, jika ada.
+func contextTx(ctx context.Context, db Database) (Tx, bool) {
+	v, ok := ctx.Value(txKey).(*contextTxValue)
+	if !ok || v.db != db {
+		return nil, false
+	}
+	return v.tx, true
+}
+
+// TxFromContext mengembalikan transaksi yang sedang berjalan di context (dari WithTx atau InTx).
+// Biasanya tidak perlu dipanggil langsung: Exec, Query, dan QueryRow pada Database sudah
+// otomatis memakai transaksi ini.
+//
+// Returns:
+//   - Tx: transaksi aktif
+//   - bool: false jika context tidak berada di dalam transaksi
+func TxFromContext(ctx context.Context) (Tx, bool) {
+	v, ok := ctx.Value(txKey).(*contextTxValue)
+	if !ok {
+		return nil, false
+	}
+	return v.tx, true
+}
+
+// InTx menjalankan fn di dalam transaksi db. Selama fn berjalan, semua Exec, Query, dan QueryRow
+// ke db dengan context yang diterima fn (termasuk dari UserStore, TokenStore, dan store lain)
+// otomatis memakai transaksi yang sama. Transaksi di-commit jika fn berhasil dan di-rollback jika
+// fn mengembalikan error atau panic. Pemanggilan bertingkat memakai SAVEPOINT, sehingga error di
+// blok dalam hanya membatalkan blok tersebut.
+//
+// Parameters:
+//   - ctx: context induk
+//   - db: database
+//   - fn: operasi yang dijalankan di dalam transaksi
+//
+// Returns:
+//   - error: error dari fn, begin, atau commit
+//
+// Example:
+//
+//	err := dim.InTx(r.Context(), db, func(ctx context.Context) error {
+//	    if err := userStore.Create(ctx, user); err != nil {
+//	        return err
+//	    }
+//	    return verificationStore.Save(ctx, user.ID, token)
+//	})
+func InTx(ctx context.Context, db Database, fn func(ctx context.Context) error) error {
+	return db.WithTx(ctx, func(ctx context.Context, _ Tx) error {
+		return fn(ctx)
+	})
+}
+
+// runTx adalah implementasi WithTx bersama untuk driver bawaan. Transaksi disimpan di context
+// yang diteruskan ke fn; jika context sudah berisi transaksi db, fn dijalankan di SAVEPOINT.
+func runTx(ctx context.Context, db Database, fn TransactionFunc) error {
+	if outer, ok := ctx.Value(txKey).(*contextTxValue); ok && outer.db == db {
+		return runSavepoint(ctx, outer, fn)
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback(ctx) //nolint:errcheck
+			panic(p)
+		}
+	}()
+
+	txCtx := context.WithValue(ctx, txKey, &contextTxValue{db: db, tx: tx})
+	if err := fn(txCtx, tx); err != nil {
+		tx.Rollback(ctx) //nolint:errcheck
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// runSavepoint menjalankan fn di dalam SAVEPOINT pada transaksi outer.
+// SAVEPOINT didukung PostgreSQL, MySQL (InnoDB), dan SQLite.
+func runSavepoint(ctx context.Context, outer *contextTxValue, fn TransactionFunc) error {
+	depth := outer.depth + 1
+	name := fmt.Sprintf("dim_sp_%d", depth)
+	if err := outer.tx.Exec(ctx, "SAVEPOINT "+name); err != nil {
+		return fmt.Errorf("failed to create savepoint: %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			outer.tx.Exec(ctx, "ROLLBACK TO SAVEPOINT "+name) //nolint:errcheck
+			panic(p)
+		}
+	}()
+
+	txCtx := context.WithValue(ctx, txKey, &contextTxValue{db: outer.db, tx: outer.tx, depth: depth})
+	if err := fn(txCtx, outer.tx); err != nil {
+		outer.tx.Exec(ctx, "ROLLBACK TO SAVEPOINT "+name) //nolint:errcheck
+		return err
+	}
+
+	return outer.tx.Exec(ctx, "RELEASE SAVEPOINT "+name)
+}
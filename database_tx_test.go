@@ -0,0 +1,174 @@
+package dim
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func countUsers(t *testing.T, ctx context.Context, db Database) int {
+	t.Helper()
+	var n int
+	if err := db.QueryRow(ctx, "SELECT COUNT(*) FROM users").Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestInTx_StoresJoinTransaction(t *testing.T) {
+	// Database SQLite test memakai satu koneksi: tanpa propagasi context, store yang dipanggil
+	// di dalam transaksi akan menunggu koneksi selamanya.
+	db := newTestSQLiteAuthDB(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	tokens := NewDatabaseTokenStore(db)
+	users := NewDatabaseAuthUserStore(db)
+
+	errSend := errors.New("send verification failed")
+	err := InTx(ctx, db, func(ctx context.Context) error {
+		if err := db.Exec(ctx, db.Rebind("INSERT INTO users (id, email, password) VALUES ($1, $2, $3)"), "u-1", "ana@example.com", "hash"); err != nil {
+			return err
+		}
+		if _, err := users.FindByEmail(ctx, "ana@example.com"); err != nil {
+			t.Errorf("FindByEmail inside tx: %v", err)
+		}
+		if err := tokens.SavePasswordResetToken(ctx, &PasswordResetToken{UserID: "u-1", TokenHash: "h1", ExpiresAt: time.Now().Add(time.Hour)}); err != nil {
+			return err
+		}
+		return errSend
+	})
+	if !errors.Is(err, errSend) {
+		t.Fatalf("InTx error = %v, want %v", err, errSend)
+	}
+	if n := countUsers(t, ctx, db); n != 0 {
+		t.Errorf("users after rollback = %d, want 0", n)
+	}
+
+	err = InTx(ctx, db, func(ctx context.Context) error {
+		if err := db.Exec(ctx, db.Rebind("INSERT INTO users (id, email, password) VALUES ($1, $2, $3)"), "u-1", "ana@example.com", "hash"); err != nil {
+			return err
+		}
+		return tokens.SavePasswordResetToken(ctx, &PasswordResetToken{UserID: "u-1", TokenHash: "h1", ExpiresAt: time.Now().Add(time.Hour)})
+	})
+	if err != nil {
+		t.Fatalf("InTx error = %v", err)
+	}
+	if _, err := tokens.FindPasswordResetToken(ctx, "h1"); err != nil {
+		t.Errorf("FindPasswordResetToken after commit: %v", err)
+	}
+}
+
+func TestWithTx_NestedSavepoint(t *testing.T) {
+	db := newTestSQLiteAuthDB(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	insert := db.Rebind("INSERT INTO users (id, email, password) VALUES ($1, $2, $3)")
+
+	errInner := errors.New("inner failed")
+	err := db.WithTx(ctx, func(ctx context.Context, tx Tx) error {
+		if err := db.Exec(ctx, insert, "u-1", "ana@example.com", "hash"); err != nil {
+			return err
+		}
+		innerErr := db.WithTx(ctx, func(ctx context.Context, inner Tx) error {
+			if inner != tx {
+				t.Error("nested WithTx should reuse the outer transaction")
+			}
+			if err := db.Exec(ctx, insert, "u-2", "budi@example.com", "hash"); err != nil {
+				return err
+			}
+			return errInner
+		})
+		if !errors.Is(innerErr, errInner) {
+			t.Errorf("inner error = %v, want %v", innerErr, errInner)
+		}
+		return InTx(ctx, db, func(ctx context.Context) error {
+			return db.Exec(ctx, insert, "u-3", "citra@example.com", "hash")
+		})
+	})
+	if err != nil {
+		t.Fatalf("WithTx error = %v", err)
+	}
+
+	rows, err := db.Query(ctx, "SELECT id FROM users ORDER BY id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) != 2 || ids[0] != "u-1" || ids[1] != "u-3" {
+		t.Errorf("committed users = %v, want [u-1 u-3]", ids)
+	}
+}
+
+func TestWithTx_PanicRollsBack(t *testing.T) {
+	db := newTestSQLiteAuthDB(t)
+	ctx := context.Background()
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected panic to propagate")
+			}
+		}()
+		db.WithTx(ctx, func(ctx context.Context, tx Tx) error {
+			db.Exec(ctx, db.Rebind("INSERT INTO users (id, email, password) VALUES ($1, $2, $3)"), "u-1", "ana@example.com", "hash")
+			panic("boom")
+		})
+	}()
+
+	if n := countUsers(t, ctx, db); n != 0 {
+		t.Errorf("users after panic = %d, want 0", n)
+	}
+}
+
+func TestTxFromContext(t *testing.T) {
+	db := newTestSQLiteAuthDB(t)
+	other := newTestSQLiteAuthDB(t)
+	ctx := context.Background()
+
+	if _, ok := TxFromContext(ctx); ok {
+		t.Error("TxFromContext outside transaction should be false")
+	}
+	db.WithTx(ctx, func(ctx context.Context, tx Tx) error {
+		if got, ok := TxFromContext(ctx); !ok || got != tx {
+			t.Errorf("TxFromContext = %v, %v; want active tx", got, ok)
+		}
+		// Transaksi db tidak dipakai oleh database lain.
+		if _, ok := contextTx(ctx, other); ok {
+			t.Error("contextTx should not return a transaction owned by another database")
+		}
+		if n := countUsers(t, ctx, other); n != 0 {
+			t.Errorf("other db users = %d, want 0", n)
+		}
+		return nil
+	})
+}
+
+func TestDryRunTx_InsideOuterTransaction(t *testing.T) {
+	db := newTestSQLiteAuthDB(t)
+	ctx := context.Background()
+	insert := db.Rebind("INSERT INTO users (id, email, password) VALUES ($1, $2, $3)")
+
+	err := InTx(ctx, db, func(ctx context.Context) error {
+		if err := db.Exec(ctx, insert, "u-1", "ana@example.com", "hash"); err != nil {
+			return err
+		}
+		return DryRunTx(WithDryRun(ctx), db, func(ctx context.Context, tx Tx) error {
+			return db.Exec(ctx, insert, "u-2", "budi@example.com", "hash")
+		})
+	})
+	if err != nil {
+		t.Fatalf("InTx error = %v", err)
+	}
+	if n := countUsers(t, ctx, db); n != 1 {
+		t.Errorf("users = %d, want 1 (dry-run savepoint rolled back)", n)
+	}
+}
@@ -94,3 +94,19 @@ func IsSafeRead(query string) bool {
 	// 4. Default Fallback: Assume Write (Not Safe Read)
 	return false
 }
+
+// txDriverName mengembalikan nama driver (sesuai Database.DriverName) dari Tx bawaan framework,
+// termasuk Tx yang dibungkus InstrumentedDatabase. Tx lain menghasilkan string kosong.
+func txDriverName(tx Tx) string {
+	switch t := tx.(type) {
+	case *instrumentedTx:
+		return txDriverName(t.tx)
+	case *PostgresTx:
+		return "postgres"
+	case *MySQLTx:
+		return "mysql"
+	case *SQLiteTx:
+		return "sqlite"
+	}
+	return ""
+}
@@ -0,0 +1,363 @@
+package dim
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrDependencyUnavailable dikembalikan oleh DependencyRegistry.Do ketika dependency sedang down
+// dan tidak ada fallback.
+var ErrDependencyUnavailable = errors.New("dependency unavailable")
+
+// Dependency mendeskripsikan dependency opsional (cache, email, search) beserta kebijakan
+// degradasinya. Dependency wajib seperti database sebaiknya tetap didaftarkan di HealthChecker.
+type Dependency struct {
+	// Name adalah nama unik dependency (contoh: "cache", "search").
+	Name string
+	// Check memeriksa dependency secara aktif lewat Refresh atau Start. Jika nil, status hanya
+	// berubah lewat ReportFailure, ReportSuccess, dan Do.
+	Check HealthCheckFunc
+	// Timeout adalah batas waktu Check (default: 2s).
+	Timeout time.Duration
+	// FailureThreshold adalah jumlah kegagalan berturut-turut sebelum dependency dianggap down (default: 1).
+	FailureThreshold int
+	// RecoveryThreshold adalah jumlah keberhasilan berturut-turut sebelum dependency dianggap pulih (default: 1).
+	RecoveryThreshold int
+	// OnDown dipanggil sekali saat dependency berubah menjadi down, misal untuk mengalihkan
+	// email ke antrian disk (opsional).
+	OnDown func(name string, err error)
+	// OnRecover dipanggil sekali saat dependency pulih, misal untuk mengirim ulang email
+	// yang tertunda (opsional).
+	OnRecover func(name string)
+}
+
+// DependencyStatus adalah status terkini satu dependency.
+type DependencyStatus struct {
+	Name      string    `json:"name"`
+	Available bool      `json:"available"`
+	Since     time.Time `json:"since"`
+	LastError string    `json:"last_error,omitempty"`
+}
+
+// dependencyState menyimpan state runtime satu dependency.
+type dependencyState struct {
+	dep       Dependency
+	available bool
+	since     time.Time
+	failures  int
+	successes int
+	lastErr   error
+}
+
+// DependencyRegistry melacak status dependency opsional dan menerapkan kebijakan degradasi
+// (lewati caching, antrikan email ke disk, matikan filter search) selama dependency down,
+// sehingga fitur inti seperti auth dan CRUD tetap berjalan saat terjadi gangguan parsial.
+// Status berubah dari health check berkala (Start) maupun dari kegagalan nyata yang dilaporkan
+// fitur (ReportFailure, Do), dan pemulihan dideteksi otomatis. Thread-safe.
+type DependencyRegistry struct {
+	mu         sync.RWMutex
+	deps       map[string]*dependencyState
+	order      []string
+	retryAfter time.Duration
+	now        func() time.Time
+}
+
+// NewDependencyRegistry membuat DependencyRegistry kosong.
+//
+// Returns:
+//   - *DependencyRegistry: registry yang siap diisi dengan Add atau Register
+//
+// Example:
+//
+//	deps := dim.NewDependencyRegistry()
+//	deps.Register(dim.Dependency{
+//	    Name:  "cache",
+//	    Check: func(ctx context.Context) error { return rdb.Ping(ctx).Err() },
+//	})
+//	deps.Start(ctx, 10*time.Second)
+func NewDependencyRegistry() *DependencyRegistry {
+	return &DependencyRegistry{
+		deps:       make(map[string]*dependencyState),
+		retryAfter: 30 * time.Second,
+		now:        time.Now,
+	}
+}
+
+// Register mendaftarkan dependency. Dependency dianggap tersedia sampai ada kegagalan.
+//
+// Parameters:
+//   - dep: Dependency yang akan didaftarkan
+//
+// Returns:
+//   - error: error jika nama kosong atau sudah terdaftar
+func (d *DependencyRegistry) Register(dep Dependency) error {
+	if dep.Name == "" {
+		return fmt.Errorf("dependency name is required")
+	}
+	if dep.Timeout == 0 {
+		dep.Timeout = 2 * time.Second
+	}
+	if dep.FailureThreshold <= 0 {
+		dep.FailureThreshold = 1
+	}
+	if dep.RecoveryThreshold <= 0 {
+		dep.RecoveryThreshold = 1
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.deps[dep.Name]; ok {
+		return fmt.Errorf("dependency already registered: %s", dep.Name)
+	}
+	d.deps[dep.Name] = &dependencyState{dep: dep, available: true, since: d.now()}
+	d.order = append(d.order, dep.Name)
+	return nil
+}
+
+// Add mendaftarkan dependency dengan check dan pengaturan default. Panic jika nama kosong atau
+// duplikat, karena merupakan kesalahan wiring saat startup.
+//
+// Example:
+//
+//	deps.Add("search", func(ctx context.Context) error { return es.Ping(ctx) }).
+//	    Add("mail", mailer.Ping)
+func (d *DependencyRegistry) Add(name string, check HealthCheckFunc) *DependencyRegistry {
+	if err := d.Register(Dependency{Name: name, Check: check}); err != nil {
+		panic("dim: " + err.Error())
+	}
+	return d
+}
+
+// Available melaporkan apakah dependency sedang tersedia. Nama yang tidak terdaftar dianggap
+// tersedia agar fitur tidak terdegradasi karena salah ketik nama.
+//
+// Example:
+//
+//	if deps.Available("search") {
+//	    query = applySearchFilters(query, r)
+//	}
+func (d *DependencyRegistry) Available(name string) bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if s, ok := d.deps[name]; ok {
+		return s.available
+	}
+	return true
+}
+
+// ReportFailure mencatat kegagalan nyata dependency (misal error koneksi cache) di luar health
+// check. Setelah FailureThreshold kegagalan berturut-turut, dependency dianggap down dan OnDown dipanggil.
+func (d *DependencyRegistry) ReportFailure(name string, err error) {
+	if err == nil {
+		err = ErrDependencyUnavailable
+	}
+	d.record(name, err)
+}
+
+// ReportSuccess mencatat keberhasilan dependency. Dependency yang down dianggap pulih setelah
+// RecoveryThreshold keberhasilan berturut-turut dan OnRecover dipanggil.
+func (d *DependencyRegistry) ReportSuccess(name string) {
+	d.record(name, nil)
+}
+
+// record memperbarui state dependency dengan satu hasil dan menjalankan hook transisi di luar lock.
+func (d *DependencyRegistry) record(name string, err error) {
+	d.mu.Lock()
+	s, ok := d.deps[name]
+	if !ok {
+		d.mu.Unlock()
+		return
+	}
+
+	var transition func()
+	if err != nil {
+		s.successes = 0
+		s.failures++
+		s.lastErr = err
+		if s.available && s.failures >= s.dep.FailureThreshold {
+			s.available = false
+			s.since = d.now()
+			onDown := s.dep.OnDown
+			transition = func() {
+				slog.Warn("dependency down, degrading", "dependency", name, "error", err)
+				if onDown != nil {
+					onDown(name, err)
+				}
+			}
+		}
+	} else {
+		s.failures = 0
+		if s.available {
+			s.lastErr = nil
+		} else if s.successes++; s.successes >= s.dep.RecoveryThreshold {
+			s.available = true
+			s.since = d.now()
+			s.successes = 0
+			s.lastErr = nil
+			onRecover := s.dep.OnRecover
+			transition = func() {
+				slog.Info("dependency recovered", "dependency", name)
+				if onRecover != nil {
+					onRecover(name)
+				}
+			}
+		}
+	}
+	d.mu.Unlock()
+
+	if transition != nil {
+		transition()
+	}
+}
+
+// Refresh menjalankan Check semua dependency secara concurrent dan memperbarui statusnya.
+// Dependency tanpa Check dilewati.
+func (d *DependencyRegistry) Refresh(ctx context.Context) {
+	d.mu.RLock()
+	deps := make([]Dependency, 0, len(d.order))
+	for _, name := range d.order {
+		if dep := d.deps[name].dep; dep.Check != nil {
+			deps = append(deps, dep)
+		}
+	}
+	d.mu.RUnlock()
+
+	var wg sync.WaitGroup
+	for _, dep := range deps {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			d.record(dep.Name, callHealthCheck(ctx, dep.Check, dep.Timeout))
+		}()
+	}
+	wg.Wait()
+}
+
+// Start menjalankan Refresh segera lalu setiap interval di background sampai ctx dibatalkan,
+// sehingga dependency yang down terdeteksi dan pemulihannya diterapkan otomatis. Interval juga
+// dipakai sebagai Retry-After oleh RequireDependency.
+//
+// Parameters:
+//   - ctx: context yang menghentikan pemeriksaan saat dibatalkan
+//   - interval: jeda antar pemeriksaan
+func (d *DependencyRegistry) Start(ctx context.Context, interval time.Duration) {
+	d.mu.Lock()
+	d.retryAfter = interval
+	d.mu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			d.Refresh(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Statuses mengembalikan status semua dependency sesuai urutan pendaftaran.
+func (d *DependencyRegistry) Statuses() []DependencyStatus {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	statuses := make([]DependencyStatus, 0, len(d.order))
+	for _, name := range d.order {
+		s := d.deps[name]
+		status := DependencyStatus{Name: name, Available: s.available, Since: s.since.UTC()}
+		if s.lastErr != nil {
+			status.LastError = s.lastErr.Error()
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// Do menjalankan primary jika dependency tersedia dan fallback jika dependency down atau
+// primary gagal. Hasil primary dilaporkan ke registry; error karena ctx dibatalkan tidak
+// dihitung sebagai kegagalan dependency.
+//
+// Parameters:
+//   - ctx: context request
+//   - name: nama dependency
+//   - primary: operasi yang memakai dependency
+//   - fallback: kebijakan degradasi; nil berarti mengembalikan error (ErrDependencyUnavailable saat down)
+//
+// Returns:
+//   - error: error dari fallback, atau dari primary jika fallback nil
+//
+// Example:
+//
+//	// Lewati caching saat Redis down
+//	err := deps.Do(ctx, "cache",
+//	    func(ctx context.Context) error { return rdb.Set(ctx, key, value, time.Minute).Err() },
+//	    func(ctx context.Context) error { return nil })
+func (d *DependencyRegistry) Do(ctx context.Context, name string, primary, fallback func(ctx context.Context) error) error {
+	if !d.Available(name) {
+		if fallback == nil {
+			return ErrDependencyUnavailable
+		}
+		return fallback(ctx)
+	}
+
+	err := primary(ctx)
+	if err == nil {
+		d.ReportSuccess(name)
+		return nil
+	}
+	if ctx.Err() == nil {
+		d.ReportFailure(name, err)
+	}
+	if fallback == nil {
+		return err
+	}
+	return fallback(ctx)
+}
+
+// StatusHandler melayani Statuses sebagai JSON dengan status 200, karena dependency opsional
+// yang down tidak membuat aplikasi tidak siap. Daftarkan di router Internal.
+func (d *DependencyRegistry) StatusHandler() HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "no-store")
+		Json(w, http.StatusOK, d.Statuses())
+	}
+}
+
+// RequireDependency menolak request dengan 503 dan header Retry-After ketika salah satu
+// dependency sedang down. Pasang hanya pada route yang tidak dapat berjalan tanpa dependency
+// tersebut (misal endpoint search), agar route lain tetap dilayani.
+//
+// Parameters:
+//   - deps: registry dependency
+//   - names: dependency yang dibutuhkan route
+//
+// Returns:
+//   - MiddlewareFunc: middleware pemeriksa dependency
+//
+// Example:
+//
+//	router.Get("/search", searchHandler, dim.RequireDependency(deps, "search"))
+func RequireDependency(deps *DependencyRegistry, names ...string) MiddlewareFunc {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			for _, name := range names {
+				if !deps.Available(name) {
+					deps.mu.RLock()
+					retryAfter := deps.retryAfter
+					deps.mu.RUnlock()
+					ServiceUnavailable(w, int(math.Ceil(retryAfter.Seconds())))
+					return
+				}
+			}
+			next(w, r)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package dim
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDependencyRegistry_Thresholds(t *testing.T) {
+	var downs, recovers []string
+	deps := NewDependencyRegistry()
+	err := deps.Register(Dependency{
+		Name:              "cache",
+		FailureThreshold:  2,
+		RecoveryThreshold: 2,
+		OnDown:            func(name string, err error) { downs = append(downs, name+": "+err.Error()) },
+		OnRecover:         func(name string) { recovers = append(recovers, name) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deps.ReportFailure("cache", errors.New("connection refused"))
+	if !deps.Available("cache") {
+		t.Fatal("cache down after 1 failure, threshold 2")
+	}
+	deps.ReportFailure("cache", errors.New("connection refused"))
+	deps.ReportFailure("cache", errors.New("connection refused"))
+	if deps.Available("cache") || len(downs) != 1 || downs[0] != "cache: connection refused" {
+		t.Fatalf("available = %v, downs = %v", deps.Available("cache"), downs)
+	}
+
+	deps.ReportSuccess("cache")
+	if deps.Available("cache") {
+		t.Fatal("cache recovered after 1 success, threshold 2")
+	}
+	deps.ReportSuccess("cache")
+	if !deps.Available("cache") || len(recovers) != 1 {
+		t.Fatalf("available = %v, recovers = %v", deps.Available("cache"), recovers)
+	}
+
+	statuses := deps.Statuses()
+	if len(statuses) != 1 || !statuses[0].Available || statuses[0].LastError != "" {
+		t.Errorf("statuses = %+v", statuses)
+	}
+	if !deps.Available("unknown") {
+		t.Error("unregistered dependency must be available")
+	}
+}
+
+func TestDependencyRegistry_RegisterErrors(t *testing.T) {
+	deps := NewDependencyRegistry().Add("search", nil)
+	if err := deps.Register(Dependency{}); err == nil {
+		t.Error("expected error for empty name")
+	}
+	if err := deps.Register(Dependency{Name: "search"}); err == nil || !strings.Contains(err.Error(), "already registered") {
+		t.Errorf("duplicate error = %v", err)
+	}
+}
+
+func TestDependencyRegistry_RefreshRecovery(t *testing.T) {
+	var healthy atomic.Bool
+	deps := NewDependencyRegistry()
+	deps.Add("search", func(ctx context.Context) error {
+		if !healthy.Load() {
+			return errors.New("search cluster unreachable")
+		}
+		return nil
+	})
+	deps.Register(Dependency{Name: "slow", Timeout: 10 * time.Millisecond, Check: func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}})
+
+	deps.Refresh(context.Background())
+	statuses := deps.Statuses()
+	if statuses[0].Available || statuses[0].LastError != "search cluster unreachable" || statuses[1].Available {
+		t.Fatalf("statuses = %+v", statuses)
+	}
+
+	healthy.Store(true)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	deps.Start(ctx, 5*time.Millisecond)
+
+	deadline := time.Now().Add(time.Second)
+	for !deps.Available("search") {
+		if time.Now().After(deadline) {
+			t.Fatal("search not recovered by background checks")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestDependencyRegistry_Do(t *testing.T) {
+	deps := NewDependencyRegistry()
+	deps.Add("cache", nil)
+	ctx := context.Background()
+	var fallbacks int
+	fallback := func(ctx context.Context) error { fallbacks++; return nil }
+
+	// Primary gagal: fallback dipakai dan dependency menjadi down
+	err := deps.Do(ctx, "cache", func(ctx context.Context) error { return errors.New("timeout") }, fallback)
+	if err != nil || fallbacks != 1 || deps.Available("cache") {
+		t.Fatalf("err = %v, fallbacks = %d, available = %v", err, fallbacks, deps.Available("cache"))
+	}
+
+	// Selama down, primary tidak dipanggil
+	err = deps.Do(ctx, "cache", func(ctx context.Context) error { t.Error("primary called while down"); return nil }, fallback)
+	if err != nil || fallbacks != 2 {
+		t.Fatalf("err = %v, fallbacks = %d", err, fallbacks)
+	}
+	if err := deps.Do(ctx, "cache", nil, nil); !errors.Is(err, ErrDependencyUnavailable) {
+		t.Errorf("no fallback error = %v", err)
+	}
+
+	// Request yang dibatalkan tidak dihitung sebagai kegagalan dependency
+	deps.ReportSuccess("cache")
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	err = deps.Do(canceled, "cache", func(ctx context.Context) error { return ctx.Err() }, nil)
+	if !errors.Is(err, context.Canceled) || !deps.Available("cache") {
+		t.Errorf("err = %v, available = %v", err, deps.Available("cache"))
+	}
+}
+
+func TestRequireDependency(t *testing.T) {
+	deps := NewDependencyRegistry()
+	deps.Add("search", nil)
+	handler := RequireDependency(deps, "search")(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, "/search", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d", rec.Code)
+	}
+
+	deps.ReportFailure("search", nil)
+	rec = httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, "/search", nil))
+	if rec.Code != http.StatusServiceUnavailable || rec.Header().Get("Retry-After") != "30" {
+		t.Errorf("status = %d, Retry-After = %q", rec.Code, rec.Header().Get("Retry-After"))
+	}
+
+	rec = httptest.NewRecorder()
+	deps.StatusHandler()(rec, httptest.NewRequest(http.MethodGet, "/dependencies", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"available":false`) || !strings.Contains(rec.Body.String(), ErrDependencyUnavailable.Error()) {
+		t.Errorf("status handler = %d %s", rec.Code, rec.Body.String())
+	}
+}
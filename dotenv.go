@@ -0,0 +1,276 @@
+package dim
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// DefaultDotenvFiles adalah file yang dimuat LoadDotenv jika tidak ada path yang diberikan,
+// dan yang dimuat otomatis oleh LoadConfig dan LoadConfigFrom.
+var DefaultDotenvFiles = []string{".env", ".env.local"}
+
+// dotenvSet mencatat nilai yang di-set oleh LoadDotenv, sehingga pemanggilan berikutnya
+// (misal saat ConfigWatcher me-reload) dapat memperbarui nilai tersebut tanpa menimpa
+// environment variable yang di-set dari luar.
+var (
+	dotenvMu  sync.Mutex
+	dotenvSet = make(map[string]string)
+)
+
+// LoadDotenv memuat variabel dari file .env ke environment proses, sehingga development lokal
+// tidak membutuhkan tool seperti direnv. File dimuat berurutan dan file yang tidak ada dilewati.
+//
+// Urutan prioritas:
+//   - environment variable yang sudah di-set sebelum LoadDotenv dipanggil tidak pernah ditimpa,
+//     kecuali nilai yang di-set oleh LoadDotenv sebelumnya (sehingga perubahan file ikut dimuat ulang)
+//   - file berikutnya menimpa file sebelumnya (.env.local menimpa .env)
+//
+// Format yang didukung:
+//   - KEY=value, dengan atau tanpa prefix "export "
+//   - komentar "# ..." di baris sendiri atau setelah nilai tanpa kutip (diawali spasi)
+//   - "double quote": escape \n, \r, \t, \", \\, \$ dan ekspansi variabel; boleh multi-baris
+//   - 'single quote': literal tanpa escape dan ekspansi; boleh multi-baris
+//   - ekspansi $VAR, ${VAR}, ${VAR:-default} (jika kosong/tidak ada), ${VAR-default} (jika tidak ada),
+//     dari environment atau baris sebelumnya; variabel yang tidak ada menjadi string kosong
+//
+// Parameters:
+//   - paths: file yang dimuat berurutan (default: DefaultDotenvFiles)
+//
+// Returns:
+//   - error: jika file tidak dapat dibaca atau ada baris yang tidak valid (dengan nama file dan nomor baris)
+//
+// Example:
+//
+//	// .env
+//	//   export DB_HOST=localhost
+//	//   DB_URL="postgres://${DB_USER:-app}@${DB_HOST}/app"
+//	if err := dim.LoadDotenv(); err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	// File per environment
+//	dim.LoadDotenv(".env", ".env."+os.Getenv("APP_ENV"), ".env.local")
+func LoadDotenv(paths ...string) error {
+	if len(paths) == 0 {
+		paths = DefaultDotenvFiles
+	}
+
+	dotenvMu.Lock()
+	defer dotenvMu.Unlock()
+
+	// Variabel yang sudah ada sebelum pemanggilan selalu menang atas file,
+	// kecuali nilainya masih sama dengan yang di-set oleh LoadDotenv sebelumnya
+	preset := make(map[string]bool)
+	for _, kv := range os.Environ() {
+		if key, value, ok := strings.Cut(kv, "="); ok {
+			if loaded, ok := dotenvSet[key]; ok && loaded == value {
+				continue
+			}
+			preset[key] = true
+		}
+	}
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return err
+		}
+
+		err = parseDotenv(string(data), os.LookupEnv, func(key, value string) error {
+			if preset[key] {
+				return nil
+			}
+			dotenvSet[key] = value
+			return os.Setenv(key, value)
+		})
+		if err != nil {
+			return fmt.Errorf("%s:%w", path, err)
+		}
+	}
+	return nil
+}
+
+// parseDotenv mem-parse isi file .env dan memanggil set untuk setiap variabel sesuai urutan,
+// sehingga ekspansi pada baris berikutnya dapat memakai nilai baris sebelumnya lewat lookup.
+// Error diawali nomor baris, misal "3: missing '='".
+func parseDotenv(content string, lookup func(string) (string, bool), set func(key, value string) error) error {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	lines := strings.Split(content, "\n")
+
+	for i := 0; i < len(lines); i++ {
+		num := i + 1
+		line := strings.TrimSpace(lines[i])
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if rest, ok := strings.CutPrefix(line, "export"); ok && rest != "" && (rest[0] == ' ' || rest[0] == '\t') {
+			line = strings.TrimSpace(rest)
+		}
+
+		key, raw, ok := strings.Cut(line, "=")
+		if !ok {
+			return fmt.Errorf("%d: missing '=' in %q", num, line)
+		}
+		key = strings.TrimSpace(key)
+		if !isDotenvName(key) {
+			return fmt.Errorf("%d: invalid variable name %q", num, key)
+		}
+		raw = strings.TrimLeft(raw, " \t")
+
+		var value string
+		switch {
+		case raw != "" && (raw[0] == '"' || raw[0] == '\''):
+			// Nilai berkutip boleh melanjutkan ke baris berikutnya sampai kutip penutup
+			quote := raw[0]
+			body := raw[1:]
+			end := closingDotenvQuote(body, quote)
+			for end < 0 && i+1 < len(lines) {
+				i++
+				body += "\n" + lines[i]
+				end = closingDotenvQuote(body, quote)
+			}
+			if end < 0 {
+				return fmt.Errorf("%d: unterminated quoted value for %s", num, key)
+			}
+			if rest := strings.TrimSpace(body[end+1:]); rest != "" && !strings.HasPrefix(rest, "#") {
+				return fmt.Errorf("%d: unexpected characters after quoted value for %s", num, key)
+			}
+			if quote == '\'' {
+				value = body[:end]
+			} else {
+				value = expandDotenv(body[:end], lookup, true)
+			}
+
+		default:
+			if idx := strings.Index(raw, " #"); idx >= 0 {
+				raw = raw[:idx]
+			} else if idx := strings.Index(raw, "\t#"); idx >= 0 {
+				raw = raw[:idx]
+			}
+			value = expandDotenv(strings.TrimSpace(raw), lookup, false)
+		}
+
+		if err := set(key, value); err != nil {
+			return fmt.Errorf("%d: %w", num, err)
+		}
+	}
+	return nil
+}
+
+// closingDotenvQuote mengembalikan posisi kutip penutup di s, atau -1. Di dalam double quote,
+// kutip yang di-escape (\") dilewati.
+func closingDotenvQuote(s string, quote byte) int {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if quote == '"' {
+				i++
+			}
+		case quote:
+			return i
+		}
+	}
+	return -1
+}
+
+// expandDotenv mengekspansi $VAR, ${VAR}, ${VAR:-default}, dan ${VAR-default}. Jika escapes true
+// (double quote), \n, \r, \t, \", \\ dan \$ diterjemahkan; selain itu hanya \$ yang di-escape.
+func expandDotenv(s string, lookup func(string) (string, bool), escapes bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+
+		if c == '\\' && i+1 < len(s) {
+			next := s[i+1]
+			if next == '$' {
+				b.WriteByte('$')
+				i++
+				continue
+			}
+			if escapes {
+				if r, ok := dotenvEscapes[next]; ok {
+					b.WriteString(r)
+					i++
+					continue
+				}
+			}
+			b.WriteByte(c)
+			continue
+		}
+
+		if c != '$' || i+1 >= len(s) {
+			b.WriteByte(c)
+			continue
+		}
+
+		if s[i+1] == '{' {
+			end := strings.IndexByte(s[i+2:], '}')
+			if end < 0 {
+				b.WriteString(s[i:])
+				break
+			}
+			b.WriteString(expandDotenvExpr(s[i+2:i+2+end], lookup, escapes))
+			i += end + 2
+			continue
+		}
+
+		j := i + 1
+		for j < len(s) && isDotenvNameChar(s[j], j == i+1) {
+			j++
+		}
+		if j == i+1 {
+			b.WriteByte(c)
+			continue
+		}
+		value, _ := lookup(s[i+1 : j])
+		b.WriteString(value)
+		i = j - 1
+	}
+	return b.String()
+}
+
+// expandDotenvExpr mengevaluasi isi ${...}: NAME, NAME:-default, atau NAME-default.
+func expandDotenvExpr(expr string, lookup func(string) (string, bool), escapes bool) string {
+	name, fallback, emptyCounts := expr, "", false
+	hasDefault := false
+	if idx := strings.Index(expr, ":-"); idx >= 0 {
+		name, fallback, hasDefault, emptyCounts = expr[:idx], expr[idx+2:], true, true
+	} else if idx := strings.IndexByte(expr, '-'); idx >= 0 {
+		name, fallback, hasDefault = expr[:idx], expr[idx+1:], true
+	}
+
+	value, ok := lookup(name)
+	if hasDefault && (!ok || (emptyCounts && value == "")) {
+		return expandDotenv(fallback, lookup, escapes)
+	}
+	return value
+}
+
+var dotenvEscapes = map[byte]string{
+	'n':  "\n",
+	'r':  "\r",
+	't':  "\t",
+	'"':  "\"",
+	'\\': "\\",
+}
+
+func isDotenvName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if !isDotenvNameChar(s[i], i == 0) {
+			return false
+		}
+	}
+	return true
+}
+
+func isDotenvNameChar(c byte, first bool) bool {
+	return c == '_' || (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (!first && c >= '0' && c <= '9')
+}
@@ -0,0 +1,201 @@
+package dim
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeDotenv menulis file .env di direktori sementara dan memastikan key yang dimuat
+// dihapus kembali dari environment setelah test selesai.
+func writeDotenv(t *testing.T, dir, name, content string, keys ...string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+	for _, key := range keys {
+		t.Cleanup(func() { os.Unsetenv(key) })
+	}
+	return path
+}
+
+func TestLoadDotenv(t *testing.T) {
+	dir := t.TempDir()
+	path := writeDotenv(t, dir, ".env", `
+# Comment line
+export DOTENV_PLAIN=value1
+DOTENV_SPACED = spaced value # trailing comment
+DOTENV_HASH=abc#def
+DOTENV_DOUBLE="line1\nline2 \"quoted\" # not a comment"
+DOTENV_SINGLE='literal $DOTENV_PLAIN \n'
+DOTENV_EMPTY=
+DOTENV_MULTI="first
+second"
+`, "DOTENV_PLAIN", "DOTENV_SPACED", "DOTENV_HASH", "DOTENV_DOUBLE", "DOTENV_SINGLE", "DOTENV_EMPTY", "DOTENV_MULTI")
+
+	if err := LoadDotenv(path); err != nil {
+		t.Fatalf("LoadDotenv() error = %v", err)
+	}
+
+	tests := map[string]string{
+		"DOTENV_PLAIN":  "value1",
+		"DOTENV_SPACED": "spaced value",
+		"DOTENV_HASH":   "abc#def",
+		"DOTENV_DOUBLE": "line1\nline2 \"quoted\" # not a comment",
+		"DOTENV_SINGLE": `literal $DOTENV_PLAIN \n`,
+		"DOTENV_EMPTY":  "",
+		"DOTENV_MULTI":  "first\nsecond",
+	}
+	for key, want := range tests {
+		got, ok := os.LookupEnv(key)
+		if !ok {
+			t.Errorf("%s not set", key)
+			continue
+		}
+		if got != want {
+			t.Errorf("%s = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestLoadDotenv_Expansion(t *testing.T) {
+	t.Setenv("DOTENV_FROM_ENV", "env")
+	t.Setenv("DOTENV_BLANK", "")
+
+	dir := t.TempDir()
+	path := writeDotenv(t, dir, ".env", `
+DOTENV_HOST=localhost
+DOTENV_URL="postgres://${DOTENV_USER:-app}@${DOTENV_HOST}/$DOTENV_FROM_ENV"
+DOTENV_UNQUOTED=$DOTENV_HOST:5432
+DOTENV_BLANK_DEFAULT=${DOTENV_BLANK:-fallback}
+DOTENV_UNSET_DEFAULT=${DOTENV_BLANK-fallback}
+DOTENV_ESCAPED="price \$5"
+DOTENV_MISSING=x${DOTENV_DOES_NOT_EXIST}y
+`, "DOTENV_HOST", "DOTENV_URL", "DOTENV_UNQUOTED", "DOTENV_BLANK_DEFAULT", "DOTENV_UNSET_DEFAULT", "DOTENV_ESCAPED", "DOTENV_MISSING")
+
+	if err := LoadDotenv(path); err != nil {
+		t.Fatalf("LoadDotenv() error = %v", err)
+	}
+
+	tests := map[string]string{
+		"DOTENV_URL":           "postgres://app@localhost/env",
+		"DOTENV_UNQUOTED":      "localhost:5432",
+		"DOTENV_BLANK_DEFAULT": "fallback",
+		"DOTENV_UNSET_DEFAULT": "",
+		"DOTENV_ESCAPED":       "price $5",
+		"DOTENV_MISSING":       "xy",
+	}
+	for key, want := range tests {
+		if got := os.Getenv(key); got != want {
+			t.Errorf("%s = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestLoadDotenv_Precedence(t *testing.T) {
+	t.Setenv("DOTENV_EXISTING", "from-env")
+
+	dir := t.TempDir()
+	base := writeDotenv(t, dir, ".env", "DOTENV_EXISTING=from-file\nDOTENV_LAYERED=base\nDOTENV_BASE_ONLY=base\n",
+		"DOTENV_LAYERED", "DOTENV_BASE_ONLY")
+	local := writeDotenv(t, dir, ".env.local", "DOTENV_EXISTING=from-local\nDOTENV_LAYERED=local\n")
+
+	if err := LoadDotenv(base, filepath.Join(dir, ".env.missing"), local); err != nil {
+		t.Fatalf("LoadDotenv() error = %v", err)
+	}
+
+	if got := os.Getenv("DOTENV_EXISTING"); got != "from-env" {
+		t.Errorf("existing env var should win, got %q", got)
+	}
+	if got := os.Getenv("DOTENV_LAYERED"); got != "local" {
+		t.Errorf("later file should override earlier file, got %q", got)
+	}
+	if got := os.Getenv("DOTENV_BASE_ONLY"); got != "base" {
+		t.Errorf("DOTENV_BASE_ONLY = %q, want %q", got, "base")
+	}
+}
+
+func TestLoadDotenv_Reload(t *testing.T) {
+	dir := t.TempDir()
+	path := writeDotenv(t, dir, ".env", "DOTENV_RELOAD=first\nDOTENV_RELOAD_EXTERNAL=file\n", "DOTENV_RELOAD", "DOTENV_RELOAD_EXTERNAL")
+	if err := LoadDotenv(path); err != nil {
+		t.Fatalf("LoadDotenv() error = %v", err)
+	}
+
+	// Nilai yang diubah dari luar setelah load pertama tetap menang
+	os.Setenv("DOTENV_RELOAD_EXTERNAL", "external")
+	writeDotenv(t, dir, ".env", "DOTENV_RELOAD=second\nDOTENV_RELOAD_EXTERNAL=file2\n")
+	if err := LoadDotenv(path); err != nil {
+		t.Fatalf("LoadDotenv() error = %v", err)
+	}
+
+	if got := os.Getenv("DOTENV_RELOAD"); got != "second" {
+		t.Errorf("value loaded from file should be refreshed, got %q", got)
+	}
+	if got := os.Getenv("DOTENV_RELOAD_EXTERNAL"); got != "external" {
+		t.Errorf("externally set value should win, got %q", got)
+	}
+}
+
+func TestLoadDotenv_DefaultFiles(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	writeDotenv(t, dir, ".env", "DOTENV_DEFAULT=base\n", "DOTENV_DEFAULT")
+	writeDotenv(t, dir, ".env.local", "DOTENV_DEFAULT=local\n")
+
+	if err := LoadDotenv(); err != nil {
+		t.Fatalf("LoadDotenv() error = %v", err)
+	}
+	if got := os.Getenv("DOTENV_DEFAULT"); got != "local" {
+		t.Errorf("DOTENV_DEFAULT = %q, want %q", got, "local")
+	}
+}
+
+func TestLoadDotenv_NotFound(t *testing.T) {
+	if err := LoadDotenv("/nonexistent/path/.env"); err != nil {
+		t.Errorf("LoadDotenv() should not error on missing file, got %v", err)
+	}
+}
+
+func TestLoadDotenv_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"missing equals", "DOTENV_OK=1\nMALFORMED_LINE\n", ":2: missing '='"},
+		{"invalid name", "1DOTENV=value\n", ":1: invalid variable name"},
+		{"unterminated quote", "DOTENV_QUOTE=\"open\nstill open\n", ":1: unterminated quoted value"},
+		{"trailing characters", "DOTENV_QUOTE='value' extra\n", ":1: unexpected characters"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeDotenv(t, t.TempDir(), ".env", tt.content, "DOTENV_OK")
+			err := LoadDotenv(path)
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if !strings.HasPrefix(err.Error(), path) || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %q, want prefix %q and %q", err.Error(), path, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_LoadsDotenv(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	writeDotenv(t, dir, ".env", "SERVER_PORT=9191\nJWT_SECRET=dotenv-secret-key-that-is-long-enough\nDB_DRIVER=sqlite\nDB_NAME=app.db\n",
+		"SERVER_PORT", "JWT_SECRET", "DB_DRIVER", "DB_NAME")
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if cfg.Server.Port != "9191" {
+		t.Errorf("Server.Port = %q, want %q", cfg.Server.Port, "9191")
+	}
+}
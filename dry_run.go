@@ -0,0 +1,220 @@
+package dim
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+const dryRunKey contextKey = "dry_run"
+
+// DryRunParam adalah query parameter yang mengaktifkan dry-run, misal DELETE /users?dry_run=true.
+const DryRunParam = "dry_run"
+
+// DryRunHeader adalah response header yang bernilai "true" untuk request dry-run.
+const DryRunHeader = "Dry-Run"
+
+// errDryRunRollback membatalkan transaksi DryRunTx setelah fn berhasil.
+var errDryRunRollback = errors.New("dim: dry-run rollback")
+
+// DryRunChange mendeskripsikan satu perubahan yang akan terjadi jika request tidak dry-run.
+type DryRunChange struct {
+	// Action adalah jenis perubahan, misal "delete", "update", atau "create".
+	Action string `json:"action"`
+	// Resource adalah nama resource atau tabel, misal "users".
+	Resource string `json:"resource"`
+	// ID adalah ID record yang berubah (opsional untuk perubahan massal).
+	ID string `json:"id,omitempty"`
+	// Count adalah jumlah record yang terpengaruh (opsional).
+	Count int64 `json:"count,omitempty"`
+	// Before dan After adalah nilai sebelum dan sesudah perubahan (opsional).
+	Before interface{} `json:"before,omitempty"`
+	After  interface{} `json:"after,omitempty"`
+}
+
+// DryRunReport adalah body response JsonDryRun.
+type DryRunReport struct {
+	DryRun  bool           `json:"dry_run"`
+	Changes []DryRunChange `json:"changes"`
+	Data    interface{}    `json:"data,omitempty"`
+}
+
+// dryRunState mengumpulkan perubahan yang dicatat selama request dry-run.
+type dryRunState struct {
+	mu      sync.Mutex
+	changes []DryRunChange
+}
+
+// DryRunMiddleware mengaktifkan mode dry-run untuk request dengan ?dry_run=true (juga "1", "yes",
+// "on"). Handler memeriksa IsDryRun atau memakai DryRunTx agar perubahan tidak disimpan, dan
+// response mendapat header "Dry-Run: true". Nilai yang tidak dikenal ditolak dengan 400 agar
+// salah ketik tidak menjalankan operasi destruktif secara nyata.
+//
+// Returns:
+//   - MiddlewareFunc: middleware dry-run
+//
+// Example:
+//
+//	admin := router.Group("/admin", dim.RequireAuth(jwtManager), dim.DryRunMiddleware())
+//	admin.Delete("/users", bulkDeleteUsersHandler)
+func DryRunMiddleware() MiddlewareFunc {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			values, ok := r.URL.Query()[DryRunParam]
+			if !ok {
+				next(w, r)
+				return
+			}
+
+			enabled, valid := parseDryRun(values[len(values)-1])
+			if !valid {
+				BadRequest(w, "Parameter tidak valid", FieldErrors{
+					DryRunParam: "harus bernilai true atau false",
+				})
+				return
+			}
+			if enabled {
+				w.Header().Set(DryRunHeader, "true")
+				r = r.WithContext(WithDryRun(r.Context()))
+			}
+			next(w, r)
+		}
+	}
+}
+
+// parseDryRun mengurai nilai dry_run. Parameter tanpa nilai (?dry_run) dianggap true.
+func parseDryRun(value string) (enabled, valid bool) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "true", "1", "yes", "on":
+		return true, true
+	case "false", "0", "no", "off":
+		return false, true
+	}
+	return false, false
+}
+
+// WithDryRun menandai context sebagai dry-run, misal untuk menjalankan operasi admin dari CLI
+// atau test tanpa DryRunMiddleware.
+func WithDryRun(ctx context.Context) context.Context {
+	if IsDryRun(ctx) {
+		return ctx
+	}
+	return context.WithValue(ctx, dryRunKey, &dryRunState{})
+}
+
+// IsDryRun mengembalikan true jika context berasal dari request dry-run.
+func IsDryRun(ctx context.Context) bool {
+	return dryRunStateFrom(ctx) != nil
+}
+
+func dryRunStateFrom(ctx context.Context) *dryRunState {
+	if ctx == nil {
+		return nil
+	}
+	state, _ := ctx.Value(dryRunKey).(*dryRunState)
+	return state
+}
+
+// RecordDryRunChange mencatat perubahan yang akan terjadi untuk response JsonDryRun.
+// Tidak melakukan apa pun jika context bukan dry-run, sehingga aman dipanggil di store.
+//
+// Parameters:
+//   - ctx: context request
+//   - change: deskripsi perubahan
+//
+// Example:
+//
+//	dim.RecordDryRunChange(ctx, dim.DryRunChange{Action: "delete", Resource: "users", Count: affected})
+func RecordDryRunChange(ctx context.Context, change DryRunChange) {
+	state := dryRunStateFrom(ctx)
+	if state == nil {
+		return
+	}
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	state.changes = append(state.changes, change)
+}
+
+// DryRunChanges mengembalikan copy perubahan yang dicatat dengan RecordDryRunChange.
+func DryRunChanges(ctx context.Context) []DryRunChange {
+	state := dryRunStateFrom(ctx)
+	if state == nil {
+		return nil
+	}
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	return append([]DryRunChange(nil), state.changes...)
+}
+
+// DryRunTx menjalankan fn di dalam transaksi. Jika context adalah dry-run, transaksi selalu
+// di-rollback setelah fn berhasil, sehingga query (termasuk trigger dan constraint) dijalankan
+// secara nyata tetapi tidak ada perubahan yang tersimpan. Di luar dry-run, DryRunTx sama dengan
+// db.WithTx.
+//
+// Parameters:
+//   - ctx: context request
+//   - db: database
+//   - fn: operasi yang dijalankan di dalam transaksi
+//
+// Returns:
+//   - error: error dari fn atau transaksi; nil jika dry-run berhasil di-rollback
+//
+// Example:
+//
+//	err := dim.DryRunTx(r.Context(), db, func(ctx context.Context, tx dim.Tx) error {
+//	    var count int64
+//	    if err := tx.QueryRow(ctx, "WITH d AS (DELETE FROM users WHERE last_login < $1 RETURNING 1) SELECT COUNT(*) FROM d", cutoff).Scan(&count); err != nil {
+//	        return err
+//	    }
+//	    dim.RecordDryRunChange(ctx, dim.DryRunChange{Action: "delete", Resource: "users", Count: count})
+//	    return nil
+//	})
+func DryRunTx(ctx context.Context, db Database, fn TransactionFunc) error {
+	err := db.WithTx(ctx, func(ctx context.Context, tx Tx) error {
+		if err := fn(ctx, tx); err != nil {
+			return err
+		}
+		if IsDryRun(ctx) {
+			return errDryRunRollback
+		}
+		return nil
+	})
+	if errors.Is(err, errDryRunRollback) {
+		return nil
+	}
+	return err
+}
+
+// JsonDryRun menulis DryRunReport berisi perubahan yang dicatat selama request dry-run.
+// Response format: {"dry_run": true, "changes": [...], "data": ...}
+// Di luar dry-run, dry_run bernilai false dan changes kosong.
+//
+// Parameters:
+//   - w: http.ResponseWriter untuk menulis response
+//   - r: request yang context-nya berisi perubahan
+//   - data: data tambahan (opsional), misal preview record yang akan dihapus
+//   - opts: (opsional) override ResponseConfig global untuk response ini
+//
+// Returns:
+//   - error: error jika encoding JSON gagal
+//
+// Example:
+//
+//	if dim.IsDryRun(r.Context()) {
+//	    dim.JsonDryRun(w, r, nil)
+//	    return
+//	}
+//	dim.NoContent(w)
+func JsonDryRun(w http.ResponseWriter, r *http.Request, data interface{}, opts ...ResponseOption) error {
+	changes := DryRunChanges(r.Context())
+	if changes == nil {
+		changes = []DryRunChange{}
+	}
+	return Json(w, http.StatusOK, DryRunReport{
+		DryRun:  IsDryRun(r.Context()),
+		Changes: changes,
+		Data:    data,
+	}, opts...)
+}
@@ -0,0 +1,148 @@
+package dim
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDryRunMiddleware(t *testing.T) {
+	tests := []struct {
+		query      string
+		wantDryRun bool
+		wantStatus int
+	}{
+		{"", false, http.StatusOK},
+		{"?dry_run=true", true, http.StatusOK},
+		{"?dry_run", true, http.StatusOK},
+		{"?dry_run=1", true, http.StatusOK},
+		{"?dry_run=false", false, http.StatusOK},
+		{"?dry_run=ture", false, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			var got bool
+			handler := DryRunMiddleware()(func(w http.ResponseWriter, r *http.Request) {
+				got = IsDryRun(r.Context())
+			})
+
+			rec := httptest.NewRecorder()
+			handler(rec, httptest.NewRequest(http.MethodDelete, "/users"+tt.query, nil))
+			if rec.Code != tt.wantStatus || got != tt.wantDryRun {
+				t.Errorf("status = %d, dry-run = %v, want %d %v", rec.Code, got, tt.wantStatus, tt.wantDryRun)
+			}
+			if header := rec.Header().Get(DryRunHeader); (header == "true") != tt.wantDryRun {
+				t.Errorf("%s header = %q", DryRunHeader, header)
+			}
+		})
+	}
+}
+
+func TestRecordDryRunChange(t *testing.T) {
+	ctx := context.Background()
+	RecordDryRunChange(ctx, DryRunChange{Action: "delete", Resource: "users"})
+	if changes := DryRunChanges(ctx); changes != nil {
+		t.Errorf("changes outside dry-run = %v", changes)
+	}
+
+	ctx = WithDryRun(ctx)
+	if WithDryRun(ctx) != ctx {
+		t.Error("WithDryRun should keep the existing dry-run state")
+	}
+	RecordDryRunChange(ctx, DryRunChange{Action: "delete", Resource: "users", Count: 3})
+	RecordDryRunChange(ctx, DryRunChange{Action: "update", Resource: "teams", ID: "t-1"})
+
+	changes := DryRunChanges(ctx)
+	if len(changes) != 2 || changes[0].Count != 3 || changes[1].ID != "t-1" {
+		t.Errorf("changes = %+v", changes)
+	}
+	changes[0].Count = 99
+	if DryRunChanges(ctx)[0].Count != 3 {
+		t.Error("DryRunChanges should return a copy")
+	}
+}
+
+func TestDryRunTx(t *testing.T) {
+	db, err := NewSQLiteDatabase(DatabaseConfig{Database: ":memory:"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	ctx := context.Background()
+	db.Exec(ctx, "CREATE TABLE items (id INTEGER PRIMARY KEY, name TEXT)")
+	db.Exec(ctx, "INSERT INTO items (name) VALUES ('a'), ('b'), ('c')")
+
+	count := func() int {
+		var n int
+		db.QueryRow(ctx, "SELECT COUNT(*) FROM items").Scan(&n)
+		return n
+	}
+	deleteAll := func(ctx context.Context, tx Tx) error {
+		var n int64
+		if err := tx.QueryRow(ctx, "SELECT COUNT(*) FROM items").Scan(&n); err != nil {
+			return err
+		}
+		if err := tx.Exec(ctx, "DELETE FROM items"); err != nil {
+			return err
+		}
+		RecordDryRunChange(ctx, DryRunChange{Action: "delete", Resource: "items", Count: n})
+		return nil
+	}
+
+	// Dry-run: query dijalankan tetapi di-rollback
+	dryCtx := WithDryRun(ctx)
+	if err := DryRunTx(dryCtx, db, deleteAll); err != nil {
+		t.Fatalf("DryRunTx(dry-run) error = %v", err)
+	}
+	if count() != 3 {
+		t.Errorf("rows after dry-run = %d, want 3", count())
+	}
+	if changes := DryRunChanges(dryCtx); len(changes) != 1 || changes[0].Count != 3 {
+		t.Errorf("changes = %+v", changes)
+	}
+
+	// Error dari fn tetap dikembalikan saat dry-run
+	errBoom := errors.New("boom")
+	if err := DryRunTx(dryCtx, db, func(ctx context.Context, tx Tx) error { return errBoom }); !errors.Is(err, errBoom) {
+		t.Errorf("DryRunTx error = %v, want boom", err)
+	}
+
+	// Tanpa dry-run perubahan di-commit
+	if err := DryRunTx(ctx, db, deleteAll); err != nil {
+		t.Fatal(err)
+	}
+	if count() != 0 {
+		t.Errorf("rows after real run = %d, want 0", count())
+	}
+}
+
+func TestJsonDryRun(t *testing.T) {
+	handler := DryRunMiddleware()(func(w http.ResponseWriter, r *http.Request) {
+		RecordDryRunChange(r.Context(), DryRunChange{Action: "delete", Resource: "users", ID: "u-1"})
+		JsonDryRun(w, r, map[string]int{"affected": 1})
+	})
+
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodDelete, "/users/u-1?dry_run=true", nil))
+
+	var report struct {
+		DryRun  bool           `json:"dry_run"`
+		Changes []DryRunChange `json:"changes"`
+		Data    map[string]int `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+	if !report.DryRun || len(report.Changes) != 1 || report.Changes[0].ID != "u-1" || report.Data["affected"] != 1 {
+		t.Errorf("report = %s", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodDelete, "/users/u-1", nil))
+	if rec.Body.String() != "{\"dry_run\":false,\"changes\":[],\"data\":{\"affected\":1}}\n" {
+		t.Errorf("non dry-run body = %s", rec.Body.String())
+	}
+}
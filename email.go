@@ -82,10 +82,37 @@ func NewMailMessage(to []string, subject string) *MailMessage {
 }
 
 // NewMailerFromConfig membuat instance Mailer berdasarkan EmailConfig yang diberikan.
-// Menggunakan library goreus/pkg/mail di belakang layar.
+// Transport "mailgun", "sendgrid", "postmark", dan "resend" memakai HTTP API provider,
+// transport "log" mengembalikan *CaptureMailer yang menulis ringkasan email ke output;
+// transport lain menggunakan library goreus/pkg/mail di belakang layar.
 //
-// Note: Parameter 'output' saat ini diabaikan karena goreus NullMailer selalu menulis ke stdout.
+// Note: Parameter 'output' hanya dipakai transport "log" karena goreus NullMailer selalu menulis ke stdout.
 func NewMailerFromConfig(cfg *EmailConfig, output io.Writer) (Mailer, error) {
+	switch cfg.Transport {
+	case "log":
+		mailer := NewCaptureMailer(cfg.CaptureLimit)
+		if output != nil {
+			mailer.WithOutput(output)
+		}
+		return mailer, nil
+	case "mailgun":
+		mailer := NewMailgunMailer(cfg.MailgunDomain, cfg.MailgunAPIKey, cfg.From)
+		if cfg.MailgunAPIBase != "" {
+			mailer.WithBaseURL(cfg.MailgunAPIBase)
+		}
+		return mailer, nil
+	case "sendgrid":
+		return NewSendGridMailer(cfg.SendGridAPIKey, cfg.From), nil
+	case "postmark":
+		mailer := NewPostmarkMailer(cfg.PostmarkServerToken, cfg.From)
+		if cfg.PostmarkMessageStream != "" {
+			mailer.WithMessageStream(cfg.PostmarkMessageStream)
+		}
+		return mailer, nil
+	case "resend":
+		return NewResendMailer(cfg.ResendAPIKey, cfg.From), nil
+	}
+
 	goreusCfg := &mail.Config{
 		Transport:   cfg.Transport,
 		FromAddress: cfg.From,
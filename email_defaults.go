@@ -0,0 +1,224 @@
+package dim
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+//go:embed templates/email/*.html templates/email/*.txt
+var defaultEmailTemplates embed.FS
+
+// Nama template email bawaan, untuk EmailRenderer.Render dan Message.
+const (
+	EmailTemplateWelcome       = "welcome"        // data: WelcomeEmail
+	EmailTemplateVerifyEmail   = "verify_email"   // data: EmailVerificationEmail
+	EmailTemplatePasswordReset = "password_reset" // data: PasswordResetEmail
+	EmailTemplateMagicLink     = "magic_link"     // data: MagicLinkEmail
+	EmailTemplateMFACode       = "mfa_code"       // data: MFACodeEmail
+)
+
+// WelcomeEmail adalah data untuk template email "welcome". URL opsional; jika diisi, email
+// menampilkan tombol menuju aplikasi.
+type WelcomeEmail struct {
+	Email string
+	Name  string
+	URL   string
+}
+
+// PasswordResetEmail adalah data untuk template email "password_reset".
+type PasswordResetEmail struct {
+	Email     string
+	Name      string
+	URL       string
+	ExpiresAt time.Time
+}
+
+// MFACodeEmail adalah data untuk template email "mfa_code" yang berisi kode OTP sekali pakai.
+type MFACodeEmail struct {
+	Email     string
+	Name      string
+	Code      string
+	ExpiresAt time.Time
+}
+
+// DefaultEmailTemplates mengembalikan template email bawaan (welcome, verify_email,
+// password_reset, magic_link, mfa_code) beserta partial _header.html, _footer.html, dan
+// _footer.txt. Semua template memakai field branding EmailConfig.
+func DefaultEmailTemplates() fs.FS {
+	sub, _ := fs.Sub(defaultEmailTemplates, "templates/email")
+	return sub
+}
+
+// NewDefaultEmailRenderer membuat EmailRenderer dari template bawaan. Jika MAIL_TEMPLATE_DIR
+// diisi, file di direktori tersebut menggantikan template bawaan dengan nama yang sama,
+// sehingga aplikasi cukup menyalin template yang ingin diubah (misal welcome.html atau
+// _footer.html) dan dapat menambah template baru.
+//
+// Parameters:
+//   - cfg: EmailConfig untuk branding dan TemplateDir
+//
+// Returns:
+//   - *EmailRenderer: renderer siap digunakan
+//   - error: jika TemplateDir tidak ada atau ada template yang gagal di-parse
+//
+// Example:
+//
+//	renderer, err := dim.NewDefaultEmailRenderer(&cfg.Email)
+//	msg, err := renderer.Message([]string{user.Email}, "Reset password", dim.EmailTemplatePasswordReset, dim.PasswordResetEmail{
+//	    Email:     user.Email,
+//	    URL:       "https://app.example.com/reset?token=" + token,
+//	    ExpiresAt: time.Now().Add(time.Hour),
+//	})
+func NewDefaultEmailRenderer(cfg *EmailConfig) (*EmailRenderer, error) {
+	fsys := DefaultEmailTemplates()
+	if cfg.TemplateDir != "" {
+		info, err := os.Stat(cfg.TemplateDir)
+		if err != nil {
+			return nil, fmt.Errorf("invalid MAIL_TEMPLATE_DIR: %w", err)
+		}
+		if !info.IsDir() {
+			return nil, fmt.Errorf("invalid MAIL_TEMPLATE_DIR: %s is not a directory", cfg.TemplateDir)
+		}
+		fsys = OverlayFS(os.DirFS(cfg.TemplateDir), fsys)
+	}
+	return NewEmailRenderer(cfg, fsys)
+}
+
+// OverlayFS menggabungkan beberapa fs.FS: file dicari berurutan dan layer pertama yang
+// memilikinya menang, sedangkan Glob dan ReadDir mengembalikan gabungan semua layer.
+//
+// Example:
+//
+//	//go:embed emails
+//	var appEmails embed.FS
+//
+//	sub, _ := fs.Sub(appEmails, "emails")
+//	renderer, err := dim.NewEmailRenderer(&cfg.Email, dim.OverlayFS(sub, dim.DefaultEmailTemplates()))
+func OverlayFS(layers ...fs.FS) fs.FS {
+	return overlayFS(layers)
+}
+
+type overlayFS []fs.FS
+
+func (o overlayFS) Open(name string) (fs.File, error) {
+	for _, layer := range o {
+		f, err := layer.Open(name)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		// Direktori berisi gabungan entry semua layer
+		if info, err := f.Stat(); err == nil && info.IsDir() {
+			entries, err := o.ReadDir(name)
+			if err != nil {
+				f.Close()
+				return nil, err
+			}
+			return &overlayDir{File: f, entries: entries}, nil
+		}
+		return f, nil
+	}
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+func (o overlayFS) Glob(pattern string) ([]string, error) {
+	var matches []string
+	for _, layer := range o {
+		names, err := fs.Glob(layer, pattern)
+		if err != nil {
+			return nil, err
+		}
+		matches = append(matches, names...)
+	}
+	slices.Sort(matches)
+	return slices.Compact(matches), nil
+}
+
+func (o overlayFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	var entries []fs.DirEntry
+	seen := make(map[string]bool)
+	found := false
+	for _, layer := range o {
+		list, err := fs.ReadDir(layer, name)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		found = true
+		for _, e := range list {
+			if !seen[e.Name()] {
+				seen[e.Name()] = true
+				entries = append(entries, e)
+			}
+		}
+	}
+	if !found {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+	slices.SortFunc(entries, func(a, b fs.DirEntry) int { return strings.Compare(a.Name(), b.Name()) })
+	return entries, nil
+}
+
+// overlayDir adalah direktori hasil OverlayFS.Open yang ReadDir-nya mengembalikan gabungan layer.
+type overlayDir struct {
+	fs.File
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *overlayDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	rest := d.entries[d.offset:]
+	if n <= 0 {
+		d.offset = len(d.entries)
+		return rest, nil
+	}
+	if len(rest) == 0 {
+		return nil, io.EOF
+	}
+	n = min(n, len(rest))
+	d.offset += n
+	return rest[:n], nil
+}
+
+// VerificationTemplate mengembalikan fungsi untuk RegistrationOptions.Template yang merender
+// template "verify_email". Jika render gagal, Register memakai isi email default.
+//
+// Example:
+//
+//	authService.WithRegistration(userStore, dim.RegistrationOptions{
+//	    Mailer:   mailer,
+//	    URL:      "https://app.example.com/verify-email?token=%s",
+//	    Template: renderer.VerificationTemplate("Verifikasi email Anda"),
+//	})
+func (r *EmailRenderer) VerificationTemplate(subject string) func(data EmailVerificationEmail) *MailMessage {
+	return func(data EmailVerificationEmail) *MailMessage {
+		msg, err := r.Message([]string{data.Email}, subject, EmailTemplateVerifyEmail, data)
+		if err != nil {
+			return nil
+		}
+		return msg
+	}
+}
+
+// MagicLinkTemplate mengembalikan fungsi untuk MagicLinkOptions.Template yang merender
+// template "magic_link". Jika render gagal, RequestMagicLink memakai isi email default.
+func (r *EmailRenderer) MagicLinkTemplate(subject string) func(data MagicLinkEmail) *MailMessage {
+	return func(data MagicLinkEmail) *MailMessage {
+		msg, err := r.Message([]string{data.Email}, subject, EmailTemplateMagicLink, data)
+		if err != nil {
+			return nil
+		}
+		return msg
+	}
+}
@@ -0,0 +1,171 @@
+package dim
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func testBrandedEmailConfig() *EmailConfig {
+	return &EmailConfig{
+		AppName:      "Dim",
+		LogoURL:      "https://cdn.example.com/logo.png",
+		PrimaryColor: "#e11d48",
+		SupportEmail: "help@example.com",
+		CompanyName:  "Dim Labs",
+		SocialLinks:  `[{"name":"GitHub","url":"https://github.com/dimframework"}]`,
+	}
+}
+
+func TestDefaultEmailTemplates_RenderAll(t *testing.T) {
+	renderer, err := NewDefaultEmailRenderer(testBrandedEmailConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expiresAt := time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		data any
+		want []string
+	}{
+		{EmailTemplateWelcome, WelcomeEmail{Email: "ana@example.com", Name: "Ana", URL: "https://app.example.com"}, []string{"Selamat datang di Dim, Ana!", `href="https://app.example.com"`}},
+		{EmailTemplateVerifyEmail, EmailVerificationEmail{Email: "ana@example.com", URL: "https://app.example.com/verify?token=abc", ExpiresAt: expiresAt}, []string{"Verifikasi email", "16 Oct 2026 09:30 UTC"}},
+		{EmailTemplatePasswordReset, PasswordResetEmail{Email: "ana@example.com", URL: "https://app.example.com/reset?token=abc", ExpiresAt: expiresAt}, []string{"Buat password baru", "token=abc"}},
+		{EmailTemplateMagicLink, MagicLinkEmail{Email: "ana@example.com", URL: "https://app.example.com/magic?token=abc", ExpiresAt: expiresAt}, []string{"Masuk ke Dim"}},
+		{EmailTemplateMFACode, MFACodeEmail{Email: "ana@example.com", Code: "482913", ExpiresAt: expiresAt}, []string{"482913"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			html, text, err := renderer.Render(tt.name, tt.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Branding dari EmailConfig
+			for _, want := range append(tt.want,
+				`src="https://cdn.example.com/logo.png"`,
+				`border-bottom:3px solid #e11d48`,
+				`<a href="https://github.com/dimframework" style="color:#7b8794;">GitHub</a>`,
+				"mailto:help@example.com",
+				"Dim Labs",
+			) {
+				if !strings.Contains(html, want) {
+					t.Errorf("html missing %q", want)
+				}
+			}
+			if text == "" || strings.Contains(text, "<") || !strings.Contains(text, "Dim Labs") {
+				t.Errorf("text = %q", text)
+			}
+		})
+	}
+}
+
+func TestDefaultEmailTemplates_EscapesData(t *testing.T) {
+	renderer, err := NewDefaultEmailRenderer(&EmailConfig{AppName: "Dim", PrimaryColor: "#007bff"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	html, _, err := renderer.Render(EmailTemplateWelcome, WelcomeEmail{Name: "<script>", URL: "javascript:alert(1)"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(html, "<script>") || strings.Contains(html, `href="javascript:`) {
+		t.Errorf("unescaped data in html: %s", html)
+	}
+	if strings.Contains(html, "<img") {
+		t.Error("logo rendered without LogoURL")
+	}
+}
+
+func TestNewDefaultEmailRenderer_TemplateDir(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"welcome.html": `<h1>Custom {{.AppName}}</h1>{{template "_footer.html" .}}`,
+		"invoice.txt":  `Invoice {{.Data}}`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cfg := testBrandedEmailConfig()
+	cfg.TemplateDir = dir
+
+	renderer, err := NewDefaultEmailRenderer(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	html, text, err := renderer.Render(EmailTemplateWelcome, WelcomeEmail{Name: "Ana"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(html, "<h1>Custom Dim</h1>") || !strings.Contains(html, "Dim Labs") {
+		t.Errorf("overridden html = %s", html)
+	}
+	if !strings.Contains(text, "Selamat datang di Dim, Ana!") {
+		t.Errorf("welcome.txt should fall back to the built-in template, got %q", text)
+	}
+	if _, text, _ := renderer.Render("invoice", "INV-1"); text != "Invoice INV-1" {
+		t.Errorf("new template text = %q", text)
+	}
+
+	cfg.TemplateDir = filepath.Join(dir, "missing")
+	if _, err := NewDefaultEmailRenderer(cfg); err == nil {
+		t.Error("missing template dir should fail")
+	}
+}
+
+func TestOverlayFS(t *testing.T) {
+	fsys := OverlayFS(
+		fstest.MapFS{"a.txt": {Data: []byte("top")}},
+		fstest.MapFS{"a.txt": {Data: []byte("bottom")}, "b.txt": {Data: []byte("b")}},
+	)
+	if err := fstest.TestFS(fsys, "a.txt", "b.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := fs.ReadFile(fsys, "a.txt"); string(data) != "top" {
+		t.Errorf("a.txt = %q", data)
+	}
+	if _, err := fsys.Open("c.txt"); err == nil {
+		t.Error("missing file should fail")
+	}
+}
+
+func TestEmailRenderer_VerificationTemplate(t *testing.T) {
+	renderer, err := NewDefaultEmailRenderer(testBrandedEmailConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	mailer := &captureOrgMailer{}
+	service, _, _ := newTestRegisterService(t, RegistrationOptions{
+		RequireEmailVerification: true,
+		Mailer:                   mailer,
+		URL:                      "https://app.example.com/verify?token=%s",
+		Template:                 renderer.VerificationTemplate("Konfirmasi akun Dim"),
+	})
+
+	result, err := service.Register(context.Background(), RegisterRequest{Email: "ana@example.com", Password: "ValidPass123!"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mailer.sent) != 1 {
+		t.Fatalf("expected 1 email, got %d", len(mailer.sent))
+	}
+	msg := mailer.sent[0]
+	if msg.Subject != "Konfirmasi akun Dim" || !strings.Contains(msg.HTML, "background-color:#e11d48") ||
+		!strings.Contains(msg.PlainText, "token="+result.VerificationToken) {
+		t.Fatalf("message = %+v", msg)
+	}
+
+	// Template yang gagal dirender kembali ke isi email default
+	broken, _ := NewEmailRenderer(&EmailConfig{}, fstest.MapFS{"verify_email.txt": {Data: []byte(`{{.Data.Missing}}`)}})
+	service.registerOpts.Template = broken.VerificationTemplate("x")
+	if msg := service.emailVerificationMessage(EmailVerificationEmail{Email: "ana@example.com"}); msg == nil || msg.Subject != "Verifikasi email Anda" {
+		t.Errorf("fallback message = %+v", msg)
+	}
+}
@@ -0,0 +1,154 @@
+package dim
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	texttemplate "text/template"
+)
+
+// ErrEmailTemplateNotFound dikembalikan EmailRenderer jika template HTML maupun teks dengan
+// nama tersebut tidak ada.
+var ErrEmailTemplateNotFound = errors.New("email template not found")
+
+// EmailTemplateData adalah data yang diterima template email: field branding dari BaseEmailData
+// ({{.AppName}}, {{.PrimaryColor}}, {{.LogoURL.Value}}, dll.) dan data milik email di {{.Data}}.
+type EmailTemplateData struct {
+	BaseEmailData
+	Data any
+}
+
+// EmailRenderer merender template email menjadi body HTML dan plain text. Template "welcome"
+// diambil dari file welcome.html (html/template, di-escape) dan welcome.txt (text/template);
+// salah satunya boleh tidak ada. File dengan awalan "_" (misal _layout.html) dipakai sebagai
+// partial yang dapat dipanggil dengan {{template "_layout.html" .}}.
+type EmailRenderer struct {
+	html  *htmltemplate.Template
+	text  *texttemplate.Template
+	brand BaseEmailData
+}
+
+// NewEmailRenderer membaca semua template *.html dan *.txt di root fsys.
+//
+// Parameters:
+//   - cfg: EmailConfig untuk field branding (AppName, LogoURL, PrimaryColor, SocialLinks, ...)
+//   - fsys: direktori template, misal embed.FS atau os.DirFS("templates/email")
+//
+// Returns:
+//   - *EmailRenderer: renderer siap digunakan
+//   - error: error jika ada template yang gagal di-parse
+//
+// Example:
+//
+//	//go:embed templates/email
+//	var emailFS embed.FS
+//
+//	sub, _ := fs.Sub(emailFS, "templates/email")
+//	renderer, err := dim.NewEmailRenderer(&cfg.Email, sub)
+func NewEmailRenderer(cfg *EmailConfig, fsys fs.FS) (*EmailRenderer, error) {
+	r := &EmailRenderer{
+		html:  htmltemplate.New(""),
+		text:  texttemplate.New(""),
+		brand: NewBaseEmailData(cfg),
+	}
+
+	htmlFiles, err := fs.Glob(fsys, "*.html")
+	if err != nil {
+		return nil, err
+	}
+	if len(htmlFiles) > 0 {
+		if r.html, err = r.html.ParseFS(fsys, "*.html"); err != nil {
+			return nil, fmt.Errorf("failed to parse email html templates: %w", err)
+		}
+	}
+
+	textFiles, err := fs.Glob(fsys, "*.txt")
+	if err != nil {
+		return nil, err
+	}
+	if len(textFiles) > 0 {
+		if r.text, err = r.text.ParseFS(fsys, "*.txt"); err != nil {
+			return nil, fmt.Errorf("failed to parse email text templates: %w", err)
+		}
+	}
+
+	return r, nil
+}
+
+// Render mengeksekusi template name (tanpa ekstensi) dengan data.
+//
+// Returns:
+//   - string: body HTML (kosong jika name.html tidak ada)
+//   - string: body plain text (kosong jika name.txt tidak ada)
+//   - error: ErrEmailTemplateNotFound jika keduanya tidak ada, atau error eksekusi template
+func (r *EmailRenderer) Render(name string, data any) (string, string, error) {
+	tmplData := EmailTemplateData{BaseEmailData: r.brand, Data: data}
+	htmlTmpl := r.html.Lookup(name + ".html")
+	textTmpl := r.text.Lookup(name + ".txt")
+	if htmlTmpl == nil && textTmpl == nil {
+		return "", "", fmt.Errorf("%w: %s", ErrEmailTemplateNotFound, name)
+	}
+
+	var html, text bytes.Buffer
+	if htmlTmpl != nil {
+		if err := htmlTmpl.Execute(&html, tmplData); err != nil {
+			return "", "", fmt.Errorf("failed to execute email template %s.html: %w", name, err)
+		}
+	}
+	if textTmpl != nil {
+		if err := textTmpl.Execute(&text, tmplData); err != nil {
+			return "", "", fmt.Errorf("failed to execute email template %s.txt: %w", name, err)
+		}
+	}
+	return html.String(), text.String(), nil
+}
+
+// Message merender template name lalu membuat MailMessage untuk penerima to.
+//
+// Example:
+//
+//	msg, err := renderer.Message([]string{user.Email}, "Selamat datang", "welcome", map[string]any{
+//	    "Name": user.Name,
+//	})
+//	if err == nil {
+//	    err = mailer.Send(ctx, msg)
+//	}
+func (r *EmailRenderer) Message(to []string, subject, name string, data any) (*MailMessage, error) {
+	html, text, err := r.Render(name, data)
+	if err != nil {
+		return nil, err
+	}
+	msg := NewMailMessage(to, subject)
+	msg.HTML = html
+	msg.PlainText = text
+	return msg, nil
+}
+
+// NewAttachment membuat lampiran email. Content type ditebak dari ekstensi filename, atau dari
+// isi data jika ekstensinya tidak dikenal.
+//
+// Example:
+//
+//	msg.Attachments = append(msg.Attachments, dim.NewAttachment("invoice.pdf", pdf))
+func NewAttachment(filename string, data []byte) Attachment {
+	contentType := mime.TypeByExtension(filepath.Ext(filename))
+	if contentType == "" {
+		contentType = http.DetectContentType(data)
+	}
+	return Attachment{Filename: filename, ContentType: contentType, Data: data}
+}
+
+// AttachmentFromFile membaca file di path menjadi lampiran email dengan nama file yang sama.
+func AttachmentFromFile(path string) (Attachment, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Attachment{}, fmt.Errorf("failed to read attachment: %w", err)
+	}
+	return NewAttachment(filepath.Base(path), data), nil
+}
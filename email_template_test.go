@@ -0,0 +1,74 @@
+package dim
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestEmailRenderer(t *testing.T) {
+	fsys := fstest.MapFS{
+		"_footer.html": {Data: []byte(`<footer>{{.AppName}} {{.Year}}{{range .SocialLinks}} <a href="{{.URL}}">{{.Name}}</a>{{end}}</footer>`)},
+		"welcome.html": {Data: []byte(`<h1 style="color: {{.PrimaryColor}}">Halo {{.Data.Name}}</h1>{{template "_footer.html" .}}`)},
+		"welcome.txt":  {Data: []byte(`Halo {{.Data.Name}}, selamat datang di {{.AppName}}`)},
+		"notice.html":  {Data: []byte(`<p>{{.Data}}</p>`)},
+	}
+	renderer, err := NewEmailRenderer(&EmailConfig{
+		AppName:      "Dim",
+		PrimaryColor: "#ff0000",
+		SocialLinks:  `[{"name":"GitHub","url":"https://github.com/dimframework"}]`,
+	}, fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg, err := renderer.Message([]string{"ana@example.com"}, "Selamat datang", "welcome", map[string]string{"Name": "<Ana>"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(msg.HTML, `color: #ff0000`) || !strings.Contains(msg.HTML, "Halo &lt;Ana&gt;") || !strings.Contains(msg.HTML, `<a href="https://github.com/dimframework">GitHub</a>`) {
+		t.Errorf("HTML = %s", msg.HTML)
+	}
+	if msg.PlainText != "Halo <Ana>, selamat datang di Dim" || msg.Subject != "Selamat datang" || msg.To[0] != "ana@example.com" {
+		t.Errorf("message = %+v", msg)
+	}
+
+	html, text, err := renderer.Render("notice", "Maintenance")
+	if err != nil || html != "<p>Maintenance</p>" || text != "" {
+		t.Errorf("Render(notice) = %q, %q, %v", html, text, err)
+	}
+	if _, _, err := renderer.Render("missing", nil); !errors.Is(err, ErrEmailTemplateNotFound) {
+		t.Errorf("err = %v, want ErrEmailTemplateNotFound", err)
+	}
+}
+
+func TestEmailRenderer_ParseError(t *testing.T) {
+	_, err := NewEmailRenderer(&EmailConfig{}, fstest.MapFS{"broken.html": {Data: []byte(`{{.Data`)}})
+	if err == nil {
+		t.Error("invalid template should fail to parse")
+	}
+}
+
+func TestAttachmentFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.csv")
+	if err := os.WriteFile(path, []byte("id,total\n1,100\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	att, err := AttachmentFromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if att.Filename != "report.csv" || !strings.HasPrefix(att.ContentType, "text/csv") || len(att.Data) != 15 {
+		t.Errorf("attachment = %+v", att)
+	}
+
+	if att := NewAttachment("blob", []byte("%PDF-1.4")); att.ContentType != "application/pdf" {
+		t.Errorf("sniffed content type = %q", att.ContentType)
+	}
+	if _, err := AttachmentFromFile(filepath.Join(t.TempDir(), "missing.pdf")); err == nil {
+		t.Error("missing file should fail")
+	}
+}
@@ -0,0 +1,166 @@
+package dim
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Event adalah kejadian yang dipublikasikan lewat EventBus. EventName dipakai untuk memilih
+// listener, misal "auth.user_registered".
+type Event interface {
+	EventName() string
+}
+
+// EventHandler memproses satu event. Error dari listener sinkron dikembalikan oleh Publish;
+// error dari listener async dicatat ke logger EventBus.
+type EventHandler func(ctx context.Context, event Event) error
+
+type eventListener struct {
+	handler EventHandler
+	async   bool
+}
+
+// EventBus meneruskan event ke listener yang terdaftar berdasarkan nama event.
+// Listener sinkron dipanggil berurutan di goroutine pemanggil Publish; listener async dipanggil
+// di goroutine terpisah dengan context yang tidak ikut dibatalkan saat request selesai.
+// Panic pada listener dipulihkan dan diperlakukan sebagai error.
+type EventBus struct {
+	mu        sync.RWMutex
+	listeners map[string][]eventListener
+	wg        sync.WaitGroup
+	logger    *Logger
+}
+
+// NewEventBus membuat EventBus kosong.
+//
+// Example:
+//
+//	bus := dim.NewEventBus().WithLogger(logger)
+//	dim.SubscribeAsync(bus, func(ctx context.Context, e dim.UserRegistered) error {
+//	    return mailer.Send(welcomeEmail(e.User))
+//	})
+//	authService.WithEventBus(bus)
+//	server.OnShutdown("events", bus.Wait)
+func NewEventBus() *EventBus {
+	return &EventBus{listeners: make(map[string][]eventListener)}
+}
+
+// WithLogger mengatur logger untuk error listener async dan mengembalikan instance bus.
+func (b *EventBus) WithLogger(logger *Logger) *EventBus {
+	b.logger = logger
+	return b
+}
+
+// Listen mendaftarkan listener sinkron untuk event dengan nama tertentu. Gunakan "*" untuk
+// menerima semua event. Listener dipanggil sesuai urutan pendaftaran.
+func (b *EventBus) Listen(name string, handler EventHandler) *EventBus {
+	return b.listen(name, handler, false)
+}
+
+// ListenAsync mendaftarkan listener yang dipanggil di goroutine terpisah, cocok untuk pekerjaan
+// lambat seperti mengirim email atau memanggil API eksternal. Gunakan "*" untuk semua event.
+func (b *EventBus) ListenAsync(name string, handler EventHandler) *EventBus {
+	return b.listen(name, handler, true)
+}
+
+func (b *EventBus) listen(name string, handler EventHandler, async bool) *EventBus {
+	if handler == nil {
+		panic("dim: EventBus listener must not be nil")
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.listeners[name] = append(b.listeners[name], eventListener{handler: handler, async: async})
+	return b
+}
+
+// Subscribe mendaftarkan listener sinkron bertipe untuk event E. Nama event diambil dari
+// E.EventName, sehingga E harus berupa tipe value (bukan pointer).
+//
+// Example:
+//
+//	dim.Subscribe(bus, func(ctx context.Context, e dim.LoginFailed) error {
+//	    loginFailures.WithLabelValues(e.Reason).Inc()
+//	    return nil
+//	})
+func Subscribe[E Event](bus *EventBus, handler func(ctx context.Context, event E) error) *EventBus {
+	var zero E
+	return bus.Listen(zero.EventName(), typedEventHandler(handler))
+}
+
+// SubscribeAsync seperti Subscribe, tetapi listener dipanggil di goroutine terpisah.
+func SubscribeAsync[E Event](bus *EventBus, handler func(ctx context.Context, event E) error) *EventBus {
+	var zero E
+	return bus.ListenAsync(zero.EventName(), typedEventHandler(handler))
+}
+
+func typedEventHandler[E Event](handler func(ctx context.Context, event E) error) EventHandler {
+	return func(ctx context.Context, event Event) error {
+		typed, ok := event.(E)
+		if !ok {
+			return nil
+		}
+		return handler(ctx, typed)
+	}
+}
+
+// Publish mengirim event ke listener dengan nama yang sama dan listener "*".
+// Listener async dijalankan lebih dulu di background, lalu listener sinkron dipanggil berurutan.
+//
+// Parameters:
+//   - ctx: context request; listener async menerima context.WithoutCancel(ctx)
+//   - event: event yang dipublikasikan
+//
+// Returns:
+//   - error: gabungan error listener sinkron (errors.Join), atau nil
+func (b *EventBus) Publish(ctx context.Context, event Event) error {
+	b.mu.RLock()
+	listeners := make([]eventListener, 0, len(b.listeners[event.EventName()])+len(b.listeners["*"]))
+	listeners = append(listeners, b.listeners[event.EventName()]...)
+	listeners = append(listeners, b.listeners["*"]...)
+	b.mu.RUnlock()
+
+	var errs []error
+	for _, listener := range listeners {
+		if listener.async {
+			b.wg.Add(1)
+			go func(handler EventHandler) {
+				defer b.wg.Done()
+				if err := callEventHandler(context.WithoutCancel(ctx), handler, event); err != nil && b.logger != nil {
+					b.logger.Error("Event listener failed", "event", event.EventName(), "error", err.Error())
+				}
+			}(listener.handler)
+			continue
+		}
+		if err := callEventHandler(ctx, listener.handler, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Wait menunggu semua listener async yang sedang berjalan selesai atau ctx berakhir.
+// Signature-nya sesuai ShutdownFunc sehingga dapat didaftarkan ke Server.OnShutdown.
+func (b *EventBus) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		b.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func callEventHandler(ctx context.Context, handler EventHandler, event Event) (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = fmt.Errorf("event listener panic: %v", rec)
+		}
+	}()
+	return handler(ctx, event)
+}
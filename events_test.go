@@ -0,0 +1,93 @@
+package dim
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testEvent struct{ ID string }
+
+func (testEvent) EventName() string { return "test.event" }
+
+func TestEventBus_Publish(t *testing.T) {
+	bus := NewEventBus()
+	var mu sync.Mutex
+	var calls []string
+	record := func(name string) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls = append(calls, name)
+	}
+
+	Subscribe(bus, func(ctx context.Context, e testEvent) error {
+		record("typed:" + e.ID)
+		return nil
+	})
+	bus.Listen("test.event", func(ctx context.Context, event Event) error {
+		record("failing")
+		return errors.New("listener failed")
+	})
+	bus.Listen("test.event", func(ctx context.Context, event Event) error {
+		panic("boom")
+	})
+	bus.Listen("*", func(ctx context.Context, event Event) error {
+		record("wildcard:" + event.EventName())
+		return nil
+	})
+	bus.Listen("other.event", func(ctx context.Context, event Event) error {
+		record("other")
+		return nil
+	})
+
+	err := bus.Publish(context.Background(), testEvent{ID: "1"})
+	if err == nil {
+		t.Fatal("expected listener errors to be returned")
+	}
+	want := []string{"typed:1", "failing", "wildcard:test.event"}
+	if len(calls) != len(want) {
+		t.Fatalf("calls = %v, want %v", calls, want)
+	}
+	for i := range want {
+		if calls[i] != want[i] {
+			t.Errorf("calls = %v, want %v", calls, want)
+			break
+		}
+	}
+}
+
+func TestEventBus_Async(t *testing.T) {
+	bus := NewEventBus()
+	release := make(chan struct{})
+	done := make(chan string, 1)
+	SubscribeAsync(bus, func(ctx context.Context, e testEvent) error {
+		<-release
+		if ctx.Err() != nil {
+			t.Error("async listener context should not be canceled with the request")
+		}
+		done <- e.ID
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := bus.Publish(ctx, testEvent{ID: "async"}); err != nil {
+		t.Fatalf("Publish error: %v", err)
+	}
+	cancel()
+
+	waitCtx, waitCancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer waitCancel()
+	if err := bus.Wait(waitCtx); err == nil {
+		t.Error("Wait should time out while a listener is still running")
+	}
+
+	close(release)
+	if err := bus.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait error: %v", err)
+	}
+	if id := <-done; id != "async" {
+		t.Errorf("async listener received %q", id)
+	}
+}
@@ -0,0 +1,173 @@
+package dim
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+const gateKey contextKey = "gate"
+
+// PolicyFunc memutuskan apakah user boleh melakukan ability terhadap resource. Resource boleh
+// nil untuk ability yang tidak terikat ke record tertentu (misal "posts.create").
+type PolicyFunc func(ctx context.Context, user Authenticatable, resource interface{}) bool
+
+// BeforeFunc dijalankan sebelum policy. Mengembalikan decided true untuk langsung memakai
+// allowed tanpa memanggil policy, misal agar super admin selalu diizinkan.
+type BeforeFunc func(ctx context.Context, user Authenticatable, ability string) (allowed bool, decided bool)
+
+// Gate menyimpan policy otorisasi per ability sehingga pemeriksaan tingkat resource
+// (kepemilikan, status, dll.) didefinisikan sekali dan tidak tersebar di handler.
+// Ability yang tidak didefinisikan selalu ditolak.
+type Gate struct {
+	mu       sync.RWMutex
+	policies map[string]PolicyFunc
+	before   []BeforeFunc
+}
+
+// DefaultGate adalah Gate yang dipakai Authorize dan Can jika context tidak membawa Gate
+// lain (lihat WithGate).
+var DefaultGate = NewGate()
+
+// NewGate membuat Gate kosong.
+//
+// Example:
+//
+//	gate := dim.NewGate()
+//	gate.Define("posts.update", dim.Policy(func(ctx context.Context, user dim.Authenticatable, post *Post) bool {
+//	    return post.AuthorID == user.GetID()
+//	}))
+func NewGate() *Gate {
+	return &Gate{policies: make(map[string]PolicyFunc)}
+}
+
+// Define mendaftarkan policy untuk ability, menggantikan policy sebelumnya dengan nama yang sama.
+// Gunakan Policy untuk menulis policy dengan tipe resource yang spesifik.
+//
+// Returns:
+//   - *Gate: gate yang sama untuk chaining
+func (g *Gate) Define(ability string, policy PolicyFunc) *Gate {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.policies[ability] = policy
+	return g
+}
+
+// Before mendaftarkan pemeriksaan yang dijalankan sebelum policy setiap ability.
+//
+// Example:
+//
+//	gate.Before(func(ctx context.Context, user dim.Authenticatable, ability string) (bool, bool) {
+//	    if ok, _ := rbac.HasRole(ctx, user.GetID(), "admin"); ok {
+//	        return true, true
+//	    }
+//	    return false, false
+//	})
+func (g *Gate) Before(fn BeforeFunc) *Gate {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.before = append(g.before, fn)
+	return g
+}
+
+// Has melaporkan apakah ability sudah didefinisikan.
+func (g *Gate) Has(ability string) bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	_, ok := g.policies[ability]
+	return ok
+}
+
+// Allows melaporkan apakah user boleh melakukan ability terhadap resource. User nil dan
+// ability yang tidak didefinisikan selalu ditolak.
+func (g *Gate) Allows(ctx context.Context, user Authenticatable, ability string, resource interface{}) bool {
+	if user == nil {
+		return false
+	}
+
+	g.mu.RLock()
+	before := g.before
+	policy, ok := g.policies[ability]
+	g.mu.RUnlock()
+
+	for _, fn := range before {
+		if allowed, decided := fn(ctx, user, ability); decided {
+			return allowed
+		}
+	}
+	return ok && policy(ctx, user, resource)
+}
+
+// Authorize memeriksa ability seperti Allows dan mengembalikan *AppError yang siap dikirim
+// ke client: 401 jika user nil, 403 jika ditolak.
+//
+// Returns:
+//   - error: nil jika diizinkan
+func (g *Gate) Authorize(ctx context.Context, user Authenticatable, ability string, resource interface{}) error {
+	if user == nil {
+		return NewAppError("User tidak terotentikasi", http.StatusUnauthorized)
+	}
+	if !g.Allows(ctx, user, ability, resource) {
+		return NewAppError("Anda tidak memiliki izin untuk melakukan aksi ini", http.StatusForbidden)
+	}
+	return nil
+}
+
+// Policy mengubah fungsi dengan tipe resource spesifik menjadi PolicyFunc. Resource dengan
+// tipe lain (termasuk nil) ditolak.
+//
+// Example:
+//
+//	dim.DefaultGate.Define("posts.delete", dim.Policy(func(ctx context.Context, user dim.Authenticatable, post *Post) bool {
+//	    return post.AuthorID == user.GetID() && post.Status == "draft"
+//	}))
+func Policy[T any](fn func(ctx context.Context, user Authenticatable, resource T) bool) PolicyFunc {
+	return func(ctx context.Context, user Authenticatable, resource interface{}) bool {
+		typed, ok := resource.(T)
+		return ok && fn(ctx, user, typed)
+	}
+}
+
+// WithGate menyimpan Gate di context sehingga Authorize dan Can memakainya alih-alih DefaultGate.
+func WithGate(ctx context.Context, gate *Gate) context.Context {
+	return context.WithValue(ctx, gateKey, gate)
+}
+
+// GateFromContext mengembalikan Gate dari WithGate, atau DefaultGate.
+func GateFromContext(ctx context.Context) *Gate {
+	if gate, ok := ctx.Value(gateKey).(*Gate); ok && gate != nil {
+		return gate
+	}
+	return DefaultGate
+}
+
+// Authorize memeriksa apakah user terotentikasi di context (di-set RequireAuth) boleh melakukan
+// ability terhadap resource, memakai Gate dari context atau DefaultGate.
+//
+// Parameters:
+//   - ctx: context request, biasanya r.Context()
+//   - ability: nama ability, misal "posts.update"
+//   - resource: resource yang diperiksa, boleh nil
+//
+// Returns:
+//   - error: *AppError 401 jika tidak ada user, 403 jika ditolak, nil jika diizinkan
+//
+// Example:
+//
+//	post, err := posts.Find(r.Context(), dim.GetParam(r, "id"))
+//	if err := dim.Authorize(r.Context(), "posts.update", post); err != nil {
+//	    appErr, _ := dim.AsAppError(err)
+//	    dim.JsonAppError(w, appErr)
+//	    return
+//	}
+func Authorize(ctx context.Context, ability string, resource interface{}) error {
+	user, _ := ctx.Value(userKey).(Authenticatable)
+	return GateFromContext(ctx).Authorize(ctx, user, ability, resource)
+}
+
+// Can melaporkan apakah user terotentikasi di context boleh melakukan ability terhadap resource,
+// misal untuk menampilkan tombol edit hanya jika diizinkan.
+func Can(ctx context.Context, ability string, resource interface{}) bool {
+	user, _ := ctx.Value(userKey).(Authenticatable)
+	return GateFromContext(ctx).Allows(ctx, user, ability, resource)
+}
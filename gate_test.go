@@ -0,0 +1,117 @@
+package dim
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type gateTestPost struct {
+	AuthorID string
+	Locked   bool
+}
+
+func newTestGate() *Gate {
+	return NewGate().
+		Define("posts.update", Policy(func(ctx context.Context, user Authenticatable, post *gateTestPost) bool {
+			return post.AuthorID == user.GetID() && !post.Locked
+		})).
+		Define("posts.create", func(ctx context.Context, user Authenticatable, resource interface{}) bool {
+			return true
+		})
+}
+
+func TestGate_Allows(t *testing.T) {
+	ctx := context.Background()
+	gate := newTestGate()
+	author := &MockUser{ID: "1"}
+	other := &MockUser{ID: "2"}
+	post := &gateTestPost{AuthorID: "1"}
+
+	cases := []struct {
+		name     string
+		user     Authenticatable
+		ability  string
+		resource interface{}
+		want     bool
+	}{
+		{"author", author, "posts.update", post, true},
+		{"other user", other, "posts.update", post, false},
+		{"locked post", author, "posts.update", &gateTestPost{AuthorID: "1", Locked: true}, false},
+		{"wrong resource type", author, "posts.update", "post", false},
+		{"nil resource", author, "posts.update", nil, false},
+		{"no resource needed", other, "posts.create", nil, true},
+		{"undefined ability", author, "posts.delete", post, false},
+		{"no user", nil, "posts.create", nil, false},
+	}
+	for _, c := range cases {
+		if got := gate.Allows(ctx, c.user, c.ability, c.resource); got != c.want {
+			t.Errorf("%s: Allows = %v, want %v", c.name, got, c.want)
+		}
+	}
+
+	// Before hook bisa mengizinkan atau menolak sebelum policy
+	gate.Before(func(ctx context.Context, user Authenticatable, ability string) (bool, bool) {
+		if user.GetID() == "admin" {
+			return true, true
+		}
+		if ability == "posts.create" && user.GetID() == "2" {
+			return false, true
+		}
+		return false, false
+	})
+	if !gate.Allows(ctx, &MockUser{ID: "admin"}, "posts.delete", nil) {
+		t.Error("before hook should allow admin")
+	}
+	if gate.Allows(ctx, other, "posts.create", nil) {
+		t.Error("before hook should deny user 2")
+	}
+	if !gate.Allows(ctx, author, "posts.update", post) {
+		t.Error("undecided before hook should fall through to the policy")
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	gate := newTestGate()
+	post := &gateTestPost{AuthorID: "1"}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if err := Authorize(r.Context(), "posts.update", post); err != nil {
+			appErr, _ := AsAppError(err)
+			JsonAppError(w, appErr)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+	request := func(user Authenticatable) int {
+		r := httptest.NewRequest(http.MethodPut, "/posts/1", nil)
+		r = r.WithContext(WithGate(r.Context(), gate))
+		if user != nil {
+			r = SetUser(r, user)
+		}
+		w := httptest.NewRecorder()
+		handler(w, r)
+		return w.Code
+	}
+
+	if got := request(&MockUser{ID: "1"}); got != http.StatusOK {
+		t.Errorf("author status = %d, want 200", got)
+	}
+	if got := request(&MockUser{ID: "2"}); got != http.StatusForbidden {
+		t.Errorf("other user status = %d, want 403", got)
+	}
+	if got := request(nil); got != http.StatusUnauthorized {
+		t.Errorf("anonymous status = %d, want 401", got)
+	}
+
+	// Tanpa WithGate, DefaultGate dipakai
+	ctx := context.WithValue(context.Background(), userKey, Authenticatable(&MockUser{ID: "1"}))
+	if Can(ctx, "gate-test.undefined", nil) {
+		t.Error("undefined ability on DefaultGate should be denied")
+	}
+	DefaultGate.Define("gate-test.allowed", func(ctx context.Context, user Authenticatable, resource interface{}) bool { return true })
+	if !Can(ctx, "gate-test.allowed", nil) || Authorize(ctx, "gate-test.allowed", nil) != nil {
+		t.Error("DefaultGate policy should allow")
+	}
+}
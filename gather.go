@@ -0,0 +1,240 @@
+package dim
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// errGatherSkipped adalah cause pembatalan ketika call lain gagal secara fatal.
+// Membungkus context.Canceled agar errors.Is(err, context.Canceled) tetap berlaku.
+var errGatherSkipped = fmt.Errorf("gather: canceled after another call failed: %w", context.Canceled)
+
+// GatherError membungkus error satu call agar asal error tetap diketahui setelah digabung.
+type GatherError struct {
+	// Name adalah nama call yang gagal.
+	Name string
+	// Err adalah error asli dari call.
+	Err error
+}
+
+// Error mengimplementasikan interface error.
+func (e *GatherError) Error() string {
+	return fmt.Sprintf("gather %s: %v", e.Name, e.Err)
+}
+
+// Unwrap mengembalikan error asli agar errors.Is dan errors.As tetap bekerja.
+func (e *GatherError) Unwrap() error {
+	return e.Err
+}
+
+// Gatherable adalah call yang dapat dijalankan oleh Gather. Dibuat dengan Call.
+type Gatherable interface {
+	gatherName() string
+	gatherOptional() bool
+	gatherRun(ctx context.Context) error
+	gatherSkip(err error)
+	gatherErr() error
+}
+
+// GatherCall adalah satu pemanggilan store dengan hasil bertipe T. Hasil tersedia
+// setelah Gather selesai.
+type GatherCall[T any] struct {
+	name     string
+	fn       func(ctx context.Context) (T, error)
+	optional bool
+	value    T
+	err      error
+}
+
+// Call membuat GatherCall untuk fn. Name dipakai di GatherError.
+//
+// Parameters:
+//   - name: nama call untuk atribusi error (contoh: "users")
+//   - fn: fungsi yang dijalankan, biasanya method store
+//
+// Returns:
+//   - *GatherCall[T]: call yang siap diberikan ke Gather
+//
+// Example:
+//
+//	users := dim.Call("users", userStore.ListRecent)
+//	stats := dim.Call("stats", statsStore.Summary).Optional()
+func Call[T any](name string, fn func(ctx context.Context) (T, error)) *GatherCall[T] {
+	return &GatherCall[T]{name: name, fn: fn}
+}
+
+// Optional menandai call sebagai non-fatal: kegagalannya tidak membatalkan call lain dan tidak
+// ikut dikembalikan oleh Gather. Periksa Err untuk mengetahui hasilnya.
+func (c *GatherCall[T]) Optional() *GatherCall[T] {
+	c.optional = true
+	return c
+}
+
+// Name mengembalikan nama call.
+func (c *GatherCall[T]) Name() string {
+	return c.name
+}
+
+// Value mengembalikan hasil call, atau zero value jika call gagal atau dibatalkan.
+func (c *GatherCall[T]) Value() T {
+	return c.value
+}
+
+// Err mengembalikan error call (tanpa pembungkus GatherError), atau nil jika berhasil.
+func (c *GatherCall[T]) Err() error {
+	return c.err
+}
+
+// Result mengembalikan hasil dan error call sekaligus.
+func (c *GatherCall[T]) Result() (T, error) {
+	return c.value, c.err
+}
+
+func (c *GatherCall[T]) gatherName() string   { return c.name }
+func (c *GatherCall[T]) gatherOptional() bool { return c.optional }
+func (c *GatherCall[T]) gatherSkip(err error) { c.err = err }
+func (c *GatherCall[T]) gatherErr() error     { return c.err }
+
+func (c *GatherCall[T]) gatherRun(ctx context.Context) (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = fmt.Errorf("panic: %v", rec)
+			c.err = err
+		}
+	}()
+	value, err := c.fn(ctx)
+	if err != nil {
+		c.err = err
+		return err
+	}
+	c.value = value
+	return nil
+}
+
+// Gatherer menjalankan beberapa call secara concurrent dengan batas paralelisme dan
+// kebijakan pembatalan yang dapat diatur. Gunakan Gather untuk konfigurasi default.
+type Gatherer struct {
+	concurrency int
+	failFast    bool
+}
+
+// NewGatherer membuat Gatherer tanpa batas concurrency yang membatalkan call lain pada
+// kegagalan fatal pertama.
+//
+// Example:
+//
+//	err := dim.NewGatherer().WithConcurrency(4).Run(ctx, users, orders, invoices)
+func NewGatherer() *Gatherer {
+	return &Gatherer{failFast: true}
+}
+
+// WithConcurrency membatasi jumlah call yang berjalan bersamaan. 0 berarti tanpa batas.
+// Berguna agar satu request tidak menghabiskan connection pool database.
+func (g *Gatherer) WithConcurrency(n int) *Gatherer {
+	g.concurrency = n
+	return g
+}
+
+// WithFailFast mengatur apakah kegagalan fatal pertama membatalkan call lain (default true).
+// Jika false, semua call dijalankan sampai selesai dan semua error dikembalikan.
+func (g *Gatherer) WithFailFast(enabled bool) *Gatherer {
+	g.failFast = enabled
+	return g
+}
+
+// Run menjalankan semua call secara concurrent dan menunggu semuanya selesai.
+// Panic di dalam call ditangkap dan diperlakukan sebagai kegagalan call.
+//
+// Call yang dibatalkan karena call lain gagal mendapat error yang membungkus context.Canceled
+// dan tidak ikut dikembalikan, sehingga error hanya menunjuk call yang benar-benar gagal.
+// Pembatalan ctx induk dikembalikan sebagai kegagalan setiap call yang belum selesai.
+//
+// Parameters:
+//   - ctx: context induk; pembatalan ctx menghentikan semua call
+//   - calls: call yang dibuat dengan Call
+//
+// Returns:
+//   - error: gabungan *GatherError dari call non-optional yang gagal, terurut sesuai urutan
+//     argumen; nil jika semua berhasil
+func (g *Gatherer) Run(ctx context.Context, calls ...Gatherable) error {
+	runCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	var sem chan struct{}
+	if g.concurrency > 0 {
+		sem = make(chan struct{}, g.concurrency)
+	}
+
+	failed := make([]bool, len(calls))
+	var wg sync.WaitGroup
+	for i, call := range calls {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var err error
+			if sem != nil {
+				select {
+				case sem <- struct{}{}:
+					defer func() { <-sem }()
+				case <-runCtx.Done():
+				}
+			}
+			if runCtx.Err() != nil {
+				err = context.Cause(runCtx)
+				call.gatherSkip(err)
+			} else {
+				err = call.gatherRun(runCtx)
+			}
+
+			if err == nil || call.gatherOptional() {
+				return
+			}
+			// Error akibat pembatalan oleh call lain bukan kegagalan call ini
+			if errors.Is(context.Cause(runCtx), errGatherSkipped) && errors.Is(err, context.Canceled) {
+				return
+			}
+			failed[i] = true
+			if g.failFast {
+				cancel(errGatherSkipped)
+			}
+		}()
+	}
+	wg.Wait()
+
+	var errs []error
+	for i, call := range calls {
+		if failed[i] {
+			errs = append(errs, &GatherError{Name: call.gatherName(), Err: call.gatherErr()})
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Gather menjalankan call secara concurrent tanpa batas paralelisme dan membatalkan call
+// lain pada kegagalan fatal pertama. Hasil bertipe dibaca dari masing-masing call.
+//
+// Parameters:
+//   - ctx: context induk, biasanya r.Context()
+//   - calls: call yang dibuat dengan Call
+//
+// Returns:
+//   - error: gabungan *GatherError dari call non-optional yang gagal, nil jika semua berhasil
+//
+// Example:
+//
+//	users := dim.Call("users", userStore.ListRecent)
+//	orders := dim.Call("orders", func(ctx context.Context) ([]Order, error) {
+//	    return orderStore.ListByUser(ctx, userID)
+//	})
+//	stats := dim.Call("stats", statsStore.Summary).Optional()
+//
+//	if err := dim.Gather(r.Context(), users, orders, stats); err != nil {
+//	    dim.InternalServerError(w, "Gagal memuat dashboard")
+//	    return
+//	}
+//	dim.OK(w, Dashboard{Users: users.Value(), Orders: orders.Value(), Stats: stats.Value()})
+func Gather(ctx context.Context, calls ...Gatherable) error {
+	return NewGatherer().Run(ctx, calls...)
+}
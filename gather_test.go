@@ -0,0 +1,136 @@
+package dim
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGather_TypedResults(t *testing.T) {
+	users := Call("users", func(ctx context.Context) ([]string, error) { return []string{"ana", "budi"}, nil })
+	count := Call("count", func(ctx context.Context) (int, error) { return 42, nil })
+
+	if err := Gather(context.Background(), users, count); err != nil {
+		t.Fatal(err)
+	}
+	if len(users.Value()) != 2 || count.Value() != 42 || users.Err() != nil || count.Name() != "count" {
+		t.Errorf("users = %v, count = %v", users.Value(), count.Value())
+	}
+}
+
+func TestGather_ErrorAttribution(t *testing.T) {
+	errNotFound := errors.New("not found")
+	users := Call("users", func(ctx context.Context) (int, error) { return 0, errNotFound })
+	orders := Call("orders", func(ctx context.Context) (int, error) { panic("boom") })
+	stats := Call("stats", func(ctx context.Context) (int, error) { return 1, nil })
+
+	err := NewGatherer().WithFailFast(false).Run(context.Background(), users, orders, stats)
+	if !errors.Is(err, errNotFound) {
+		t.Errorf("errors.Is(err, errNotFound) = false, err = %v", err)
+	}
+	var gatherErr *GatherError
+	if !errors.As(err, &gatherErr) || gatherErr.Name != "users" {
+		t.Errorf("first GatherError = %+v", gatherErr)
+	}
+	if !strings.Contains(err.Error(), "gather orders: panic: boom") || strings.Contains(err.Error(), "stats") {
+		t.Errorf("err = %v", err)
+	}
+	if stats.Value() != 1 {
+		t.Errorf("stats = %v, want completed without fail-fast", stats.Value())
+	}
+}
+
+func TestGather_FailFast(t *testing.T) {
+	errDown := errors.New("database down")
+	failing := Call("failing", func(ctx context.Context) (int, error) { return 7, errDown })
+	slow := Call("slow", func(ctx context.Context) (int, error) {
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-time.After(5 * time.Second):
+			return 1, nil
+		}
+	})
+
+	start := time.Now()
+	err := Gather(context.Background(), failing, slow)
+	if time.Since(start) > time.Second {
+		t.Fatal("slow call was not canceled")
+	}
+	// Hanya call yang benar-benar gagal yang dikembalikan
+	if err == nil || err.Error() != "gather failing: database down" {
+		t.Errorf("err = %v", err)
+	}
+	if failing.Value() != 0 || !errors.Is(slow.Err(), context.Canceled) {
+		t.Errorf("failing = %v, slow err = %v", failing.Value(), slow.Err())
+	}
+}
+
+func TestGather_Optional(t *testing.T) {
+	stats := Call("stats", func(ctx context.Context) (int, error) { return 0, errors.New("timeout") }).Optional()
+	users := Call("users", func(ctx context.Context) (bool, error) {
+		time.Sleep(20 * time.Millisecond)
+		return ctx.Err() == nil, nil
+	})
+
+	if err := Gather(context.Background(), stats, users); err != nil {
+		t.Errorf("optional failure should not be returned, err = %v", err)
+	}
+	if !users.Value() {
+		t.Error("optional failure should not cancel other calls")
+	}
+	if _, err := stats.Result(); err == nil || err.Error() != "timeout" {
+		t.Errorf("stats err = %v", err)
+	}
+}
+
+func TestGather_Concurrency(t *testing.T) {
+	var running, peak atomic.Int32
+	calls := make([]Gatherable, 8)
+	for i := range calls {
+		calls[i] = Call("call", func(ctx context.Context) (int, error) {
+			n := running.Add(1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			running.Add(-1)
+			return i, nil
+		})
+	}
+
+	if err := NewGatherer().WithConcurrency(2).Run(context.Background(), calls...); err != nil {
+		t.Fatal(err)
+	}
+	if peak.Load() > 2 {
+		t.Errorf("peak concurrency = %d, want <= 2", peak.Load())
+	}
+	if calls[5].(*GatherCall[int]).Value() != 5 {
+		t.Errorf("calls[5] = %v", calls[5].(*GatherCall[int]).Value())
+	}
+}
+
+func TestGather_ParentCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var ran atomic.Bool
+	call := Call("users", func(ctx context.Context) (int, error) {
+		ran.Store(true)
+		return 1, nil
+	})
+	err := NewGatherer().WithConcurrency(1).Run(ctx, call)
+	if ran.Load() || !errors.Is(call.Err(), context.Canceled) {
+		t.Errorf("ran = %v, call err = %v", ran.Load(), call.Err())
+	}
+	// Hasil kosong tidak boleh terlihat seperti sukses
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+}
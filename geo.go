@@ -0,0 +1,366 @@
+package dim
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// EarthRadiusKm adalah radius rata-rata bumi (IUGG) yang dipakai GeoPoint.DistanceKm.
+const EarthRadiusKm = 6371.0088
+
+// ErrInvalidGeoPoint dikembalikan jika koordinat tidak dapat di-parse atau di luar rentang
+// latitude -90..90 dan longitude -180..180.
+var ErrInvalidGeoPoint = errors.New("invalid geo point")
+
+// GeoPoint adalah koordinat WGS 84 dalam derajat desimal.
+//
+// Encoding:
+//   - JSON: {"lat": -6.2, "lng": 106.816666}; string "lat,lng" juga diterima saat decode
+//   - Form/query: "lat,lng"
+//   - SQL: ditulis sebagai literal point PostgreSQL "(lng,lat)" (urutan x,y seperti earthdistance);
+//     Scan membaca point, WKT/EWKT, dan EWKB hex dari kolom PostGIS. Untuk kolom PostGIS, kirim
+//     p.EWKT() sebagai argumen. Gunakan *GeoPoint untuk kolom nullable.
+//
+// Example:
+//
+//	type CreateStoreRequest struct {
+//	    Name     string       `json:"name" validate:"required"`
+//	    Location dim.GeoPoint `json:"location" validate:"required,latlng"`
+//	}
+type GeoPoint struct {
+	Lat float64 `json:"lat"`
+	Lng float64 `json:"lng"`
+}
+
+// NewGeoPoint membuat GeoPoint dan memvalidasi rentang koordinatnya.
+func NewGeoPoint(lat, lng float64) (GeoPoint, error) {
+	p := GeoPoint{Lat: lat, Lng: lng}
+	return p, p.Validate()
+}
+
+// ParseGeoPoint mem-parse "lat,lng", misal "-6.2,106.816666". Spasi di sekitar angka diabaikan.
+//
+// Returns:
+//   - GeoPoint: koordinat yang valid
+//   - error: ErrInvalidGeoPoint jika format atau rentang tidak valid
+func ParseGeoPoint(s string) (GeoPoint, error) {
+	latStr, lngStr, ok := strings.Cut(s, ",")
+	if !ok {
+		return GeoPoint{}, fmt.Errorf("%w: expected \"lat,lng\", got %q", ErrInvalidGeoPoint, s)
+	}
+	lat, err1 := strconv.ParseFloat(strings.TrimSpace(latStr), 64)
+	lng, err2 := strconv.ParseFloat(strings.TrimSpace(lngStr), 64)
+	if err1 != nil || err2 != nil {
+		return GeoPoint{}, fmt.Errorf("%w: expected \"lat,lng\", got %q", ErrInvalidGeoPoint, s)
+	}
+	return NewGeoPoint(lat, lng)
+}
+
+// Validate memastikan latitude di -90..90 dan longitude di -180..180.
+func (p GeoPoint) Validate() error {
+	if math.IsNaN(p.Lat) || math.IsNaN(p.Lng) || p.Lat < -90 || p.Lat > 90 || p.Lng < -180 || p.Lng > 180 {
+		return fmt.Errorf("%w: latitude must be within -90..90 and longitude within -180..180", ErrInvalidGeoPoint)
+	}
+	return nil
+}
+
+// String mengembalikan "lat,lng", format yang sama dengan ParseGeoPoint.
+func (p GeoPoint) String() string {
+	return strconv.FormatFloat(p.Lat, 'f', -1, 64) + "," + strconv.FormatFloat(p.Lng, 'f', -1, 64)
+}
+
+// EWKT mengembalikan titik dalam format EWKT PostGIS, misal "SRID=4326;POINT(106.816666 -6.2)",
+// untuk disimpan ke kolom geography atau geometry.
+func (p GeoPoint) EWKT() string {
+	return "SRID=4326;POINT(" + strconv.FormatFloat(p.Lng, 'f', -1, 64) + " " + strconv.FormatFloat(p.Lat, 'f', -1, 64) + ")"
+}
+
+// DistanceKm menghitung jarak great-circle ke q dalam kilometer (rumus haversine).
+//
+// Example:
+//
+//	jakarta := dim.GeoPoint{Lat: -6.2, Lng: 106.816666}
+//	bandung := dim.GeoPoint{Lat: -6.914744, Lng: 107.60981}
+//	jakarta.DistanceKm(bandung) // ~118.3
+func (p GeoPoint) DistanceKm(q GeoPoint) float64 {
+	lat1, lat2 := p.Lat*math.Pi/180, q.Lat*math.Pi/180
+	dLat := lat2 - lat1
+	dLng := (q.Lng - p.Lng) * math.Pi / 180
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * EarthRadiusKm * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// UnmarshalJSON menerima object {"lat": ..., "lng": ...} atau string "lat,lng".
+// Rentang koordinat object tidak diperiksa di sini; gunakan tag validate:"latlng".
+func (p *GeoPoint) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		*p = GeoPoint{}
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		return p.UnmarshalText([]byte(s))
+	}
+	type geoPoint GeoPoint
+	return json.Unmarshal(data, (*geoPoint)(p))
+}
+
+// UnmarshalText mendukung binding "lat,lng" dari form dan query string.
+func (p *GeoPoint) UnmarshalText(text []byte) error {
+	parsed, err := ParseGeoPoint(string(text))
+	if err != nil {
+		return err
+	}
+	*p = parsed
+	return nil
+}
+
+// Scan mengimplementasikan sql.Scanner untuk point PostgreSQL "(lng,lat)", WKT/EWKT
+// "SRID=4326;POINT(lng lat)", dan EWKB hex (format teks default kolom PostGIS).
+func (p *GeoPoint) Scan(src interface{}) error {
+	var s string
+	switch v := src.(type) {
+	case nil:
+		*p = GeoPoint{}
+		return nil
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	default:
+		return fmt.Errorf("cannot scan %T into GeoPoint", src)
+	}
+
+	parsed, err := parseGeoSQL(strings.TrimSpace(s))
+	if err != nil {
+		return err
+	}
+	*p = parsed
+	return nil
+}
+
+// Value mengimplementasikan driver.Valuer sebagai literal point PostgreSQL "(lng,lat)".
+func (p GeoPoint) Value() (driver.Value, error) {
+	return "(" + strconv.FormatFloat(p.Lng, 'f', -1, 64) + "," + strconv.FormatFloat(p.Lat, 'f', -1, 64) + ")", nil
+}
+
+// parseGeoSQL membaca representasi titik dari database. Koordinat x adalah longitude dan y latitude.
+func parseGeoSQL(s string) (GeoPoint, error) {
+	upper := strings.ToUpper(s)
+	if _, rest, ok := strings.Cut(upper, ";"); ok && strings.HasPrefix(upper, "SRID=") {
+		upper = rest
+	}
+
+	var x, y string
+	switch {
+	case strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")"):
+		var ok bool
+		x, y, ok = strings.Cut(s[1:len(s)-1], ",")
+		if !ok {
+			return GeoPoint{}, fmt.Errorf("%w: invalid point %q", ErrInvalidGeoPoint, s)
+		}
+
+	case strings.HasPrefix(upper, "POINT(") && strings.HasSuffix(upper, ")"):
+		fields := strings.Fields(upper[len("POINT(") : len(upper)-1])
+		if len(fields) < 2 {
+			return GeoPoint{}, fmt.Errorf("%w: invalid WKT %q", ErrInvalidGeoPoint, s)
+		}
+		x, y = fields[0], fields[1]
+
+	default:
+		wkb, err := hex.DecodeString(s)
+		if err != nil {
+			return GeoPoint{}, fmt.Errorf("%w: unsupported format %q", ErrInvalidGeoPoint, s)
+		}
+		return parseGeoWKB(wkb)
+	}
+
+	lng, err1 := strconv.ParseFloat(strings.TrimSpace(x), 64)
+	lat, err2 := strconv.ParseFloat(strings.TrimSpace(y), 64)
+	if err1 != nil || err2 != nil {
+		return GeoPoint{}, fmt.Errorf("%w: invalid coordinates %q", ErrInvalidGeoPoint, s)
+	}
+	return GeoPoint{Lat: lat, Lng: lng}, nil
+}
+
+// parseGeoWKB membaca Point WKB atau EWKB (dengan SRID opsional).
+func parseGeoWKB(wkb []byte) (GeoPoint, error) {
+	if len(wkb) < 5 {
+		return GeoPoint{}, fmt.Errorf("%w: WKB too short", ErrInvalidGeoPoint)
+	}
+	var order binary.ByteOrder = binary.BigEndian
+	if wkb[0] == 1 {
+		order = binary.LittleEndian
+	}
+
+	const ewkbSRID = 0x20000000
+	geomType := order.Uint32(wkb[1:5])
+	offset := 5
+	if geomType&ewkbSRID != 0 {
+		offset += 4
+	}
+	if geomType&0xFFFF != 1 {
+		return GeoPoint{}, fmt.Errorf("%w: WKB geometry is not a point", ErrInvalidGeoPoint)
+	}
+	if len(wkb) < offset+16 {
+		return GeoPoint{}, fmt.Errorf("%w: WKB too short", ErrInvalidGeoPoint)
+	}
+	lng := math.Float64frombits(order.Uint64(wkb[offset:]))
+	lat := math.Float64frombits(order.Uint64(wkb[offset+8:]))
+	return GeoPoint{Lat: lat, Lng: lng}, nil
+}
+
+// Address adalah alamat pos dengan koordinat opsional, untuk dipakai sebagai field request
+// atau kolom JSONB.
+//
+// Example:
+//
+//	type CreateStoreRequest struct {
+//	    Name    string      `json:"name" validate:"required"`
+//	    Address dim.Address `json:"address"`
+//	}
+type Address struct {
+	Line1      string    `json:"line1" validate:"required,max=200"`
+	Line2      string    `json:"line2,omitempty" validate:"max=200"`
+	City       string    `json:"city" validate:"required,max=100"`
+	Region     string    `json:"region,omitempty" validate:"max=100"`
+	PostalCode string    `json:"postal_code,omitempty" validate:"max=20"`
+	Country    string    `json:"country" validate:"required,len=2"` // kode ISO 3166-1 alpha-2, misal "ID"
+	Location   *GeoPoint `json:"location,omitempty" validate:"omitempty,latlng"`
+}
+
+// String mengembalikan alamat dalam satu baris, misal "Jl. Sudirman 1, Jakarta, DKI Jakarta 10220, ID".
+func (a Address) String() string {
+	var parts []string
+	for _, part := range []string{a.Line1, a.Line2, a.City, strings.TrimSpace(a.Region + " " + a.PostalCode), a.Country} {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// GeoRadius adalah area pencarian "dalam radius RadiusKm dari Center", hasil parse
+// filters[near]=lat,lng,radius_km untuk field *GeoRadius.
+type GeoRadius struct {
+	Center   GeoPoint
+	RadiusKm float64
+}
+
+// ParseGeoRadius mem-parse "lat,lng,radius_km", misal "-6.2,106.8,5".
+func ParseGeoRadius(s string) (GeoRadius, error) {
+	i := strings.LastIndexByte(s, ',')
+	if i < 0 {
+		return GeoRadius{}, fmt.Errorf("%w: expected \"lat,lng,radius_km\", got %q", ErrInvalidGeoPoint, s)
+	}
+	center, err := ParseGeoPoint(s[:i])
+	if err != nil {
+		return GeoRadius{}, err
+	}
+	radius, err := strconv.ParseFloat(strings.TrimSpace(s[i+1:]), 64)
+	if err != nil || math.IsNaN(radius) || math.IsInf(radius, 0) || radius <= 0 {
+		return GeoRadius{}, fmt.Errorf("%w: radius must be a positive number of kilometers", ErrInvalidGeoPoint)
+	}
+	return GeoRadius{Center: center, RadiusKm: radius}, nil
+}
+
+// String mengembalikan "lat,lng,radius_km", format yang sama dengan ParseGeoRadius.
+func (r GeoRadius) String() string {
+	return r.Center.String() + "," + strconv.FormatFloat(r.RadiusKm, 'f', -1, 64)
+}
+
+// Contains melaporkan apakah p berada dalam radius.
+func (r GeoRadius) Contains(p GeoPoint) bool {
+	return r.Center.DistanceKm(p) <= r.RadiusKm
+}
+
+// GeoColumnType menentukan cara kolom lokasi disimpan dan fungsi SQL untuk menghitung jarak.
+type GeoColumnType string
+
+const (
+	// GeoColumnPoint adalah kolom point PostgreSQL (lng, lat) dengan extension earthdistance
+	// (CREATE EXTENSION cube; CREATE EXTENSION earthdistance). Jarak dihitung dengan operator <@>.
+	GeoColumnPoint GeoColumnType = "point"
+	// GeoColumnPostGIS adalah kolom geography(Point, 4326) PostGIS. Radius memakai ST_DWithin
+	// sehingga index GiST dapat dipakai.
+	GeoColumnPostGIS GeoColumnType = "postgis"
+)
+
+// GeoColumn adalah kolom lokasi beserta tipenya, untuk FilterSQLBuilder.WithGeo dan GeoColumn.Near.
+type GeoColumn struct {
+	Column string // kolom atau ekspresi SQL, misal "s.location"
+	Type   GeoColumnType
+}
+
+// GeoNearSQL adalah hasil GeoColumn.Near.
+type GeoNearSQL struct {
+	Where    string        // kondisi radius tanpa keyword WHERE
+	Distance string        // ekspresi jarak dalam km, untuk SELECT dan ORDER BY
+	Args     []interface{} // argumen sesuai urutan placeholder
+}
+
+// Near membuat kondisi radius dan ekspresi jarak (km) berparameter untuk query berurutan jarak.
+//
+// Parameters:
+//   - area: pusat dan radius pencarian
+//   - argOffset: jumlah placeholder yang sudah dipakai query
+//
+// Returns:
+//   - GeoNearSQL: klausa WHERE, ekspresi jarak, dan argumen
+//   - error: jika tipe kolom tidak dikenal atau area tidak valid
+//
+// Example:
+//
+//	near, err := dim.GeoColumn{Column: "location", Type: dim.GeoColumnPostGIS}.Near(area, 0)
+//	query := "SELECT id, name, " + near.Distance + " AS distance_km FROM stores WHERE " + near.Where +
+//	    " ORDER BY distance_km LIMIT 20"
+//	rows, err := db.Query(ctx, query, near.Args...)
+func (c GeoColumn) Near(area GeoRadius, argOffset int) (GeoNearSQL, error) {
+	var args []interface{}
+	placeholder := func(value interface{}) string {
+		args = append(args, value)
+		return "$" + strconv.Itoa(argOffset+len(args))
+	}
+	where, distance, err := c.nearSQL(area, placeholder)
+	if err != nil {
+		return GeoNearSQL{}, err
+	}
+	return GeoNearSQL{Where: where, Distance: distance, Args: args}, nil
+}
+
+// nearSQL menerjemahkan area ke SQL untuk tipe kolom. Placeholder pusat dipakai ulang oleh
+// ekspresi jarak, sehingga query harus dijalankan dengan placeholder bernomor ($n).
+func (c GeoColumn) nearSQL(area GeoRadius, placeholder func(interface{}) string) (string, string, error) {
+	if err := area.Center.Validate(); err != nil {
+		return "", "", err
+	}
+	if area.RadiusKm <= 0 {
+		return "", "", fmt.Errorf("%w: radius must be a positive number of kilometers", ErrInvalidGeoPoint)
+	}
+
+	switch c.Type {
+	case GeoColumnPoint:
+		// <@> menghasilkan jarak dalam statute mile
+		center := "point(" + placeholder(area.Center.Lng) + "::float8, " + placeholder(area.Center.Lat) + "::float8)"
+		distance := "(" + c.Column + " <@> " + center + ") * 1.609344"
+		return distance + " <= " + placeholder(area.RadiusKm) + "::float8", distance, nil
+
+	case GeoColumnPostGIS:
+		center := "ST_SetSRID(ST_MakePoint(" + placeholder(area.Center.Lng) + "::float8, " + placeholder(area.Center.Lat) + "::float8), 4326)::geography"
+		where := "ST_DWithin(" + c.Column + ", " + center + ", " + placeholder(area.RadiusKm) + "::float8 * 1000)"
+		return where, "ST_Distance(" + c.Column + ", " + center + ") / 1000", nil
+	}
+	return "", "", fmt.Errorf("unsupported geo column type %q", c.Type)
+}
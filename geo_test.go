@@ -0,0 +1,188 @@
+package dim
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestParseGeoPoint(t *testing.T) {
+	p, err := ParseGeoPoint(" -6.2, 106.816666 ")
+	if err != nil || p != (GeoPoint{Lat: -6.2, Lng: 106.816666}) {
+		t.Errorf("ParseGeoPoint() = %+v, %v", p, err)
+	}
+	if p.String() != "-6.2,106.816666" {
+		t.Errorf("String() = %q", p.String())
+	}
+	if p.EWKT() != "SRID=4326;POINT(106.816666 -6.2)" {
+		t.Errorf("EWKT() = %q", p.EWKT())
+	}
+
+	for _, raw := range []string{"", "-6.2", "a,b", "91,0", "0,180.5", "NaN,0"} {
+		if _, err := ParseGeoPoint(raw); !errors.Is(err, ErrInvalidGeoPoint) {
+			t.Errorf("ParseGeoPoint(%q) error = %v, want ErrInvalidGeoPoint", raw, err)
+		}
+	}
+}
+
+func TestGeoPoint_DistanceKm(t *testing.T) {
+	jakarta := GeoPoint{Lat: -6.2, Lng: 106.816666}
+	bandung := GeoPoint{Lat: -6.914744, Lng: 107.60981}
+	if d := jakarta.DistanceKm(bandung); math.Abs(d-118.3) > 0.5 {
+		t.Errorf("DistanceKm() = %.2f, want ~118.3", d)
+	}
+	if d := jakarta.DistanceKm(jakarta); d != 0 {
+		t.Errorf("DistanceKm(self) = %v", d)
+	}
+
+	area := GeoRadius{Center: jakarta, RadiusKm: 100}
+	if area.Contains(bandung) || !area.Contains(GeoPoint{Lat: -6.3, Lng: 106.9}) {
+		t.Error("Contains() mismatch")
+	}
+}
+
+func TestGeoPoint_JSON(t *testing.T) {
+	var req struct {
+		Object GeoPoint  `json:"object"`
+		Text   GeoPoint  `json:"text"`
+		Null   *GeoPoint `json:"null"`
+	}
+	body := `{"object": {"lat": 1.5, "lng": 2.5}, "text": "-6.2,106.8", "null": null}`
+	if err := json.Unmarshal([]byte(body), &req); err != nil {
+		t.Fatal(err)
+	}
+	if req.Object != (GeoPoint{1.5, 2.5}) || req.Text != (GeoPoint{-6.2, 106.8}) || req.Null != nil {
+		t.Errorf("decoded = %+v", req)
+	}
+
+	out, _ := json.Marshal(GeoPoint{Lat: -6.2, Lng: 106.8})
+	if string(out) != `{"lat":-6.2,"lng":106.8}` {
+		t.Errorf("Marshal = %s", out)
+	}
+
+	if err := json.Unmarshal([]byte(`"-6.2"`), &req.Text); err == nil {
+		t.Error("invalid string should fail")
+	}
+}
+
+func TestGeoPoint_SQL(t *testing.T) {
+	v, _ := GeoPoint{Lat: -6.2, Lng: 106.8}.Value()
+	if v != "(106.8,-6.2)" {
+		t.Errorf("Value() = %v", v)
+	}
+
+	tests := []struct {
+		src  interface{}
+		want GeoPoint
+	}{
+		{"(106.8,-6.2)", GeoPoint{Lat: -6.2, Lng: 106.8}},
+		{[]byte("(106.8, -6.2)"), GeoPoint{Lat: -6.2, Lng: 106.8}},
+		{"POINT(106.8 -6.2)", GeoPoint{Lat: -6.2, Lng: 106.8}},
+		{"SRID=4326;POINT(106.8 -6.2)", GeoPoint{Lat: -6.2, Lng: 106.8}},
+		// EWKB little-endian dengan SRID 4326: POINT(1 2)
+		{"0101000020E6100000000000000000F03F0000000000000040", GeoPoint{Lat: 2, Lng: 1}},
+		// WKB big-endian tanpa SRID: POINT(1 2)
+		{"00000000013FF00000000000004000000000000000", GeoPoint{Lat: 2, Lng: 1}},
+		{nil, GeoPoint{}},
+	}
+	for _, tt := range tests {
+		p := GeoPoint{Lat: 9, Lng: 9}
+		if err := p.Scan(tt.src); err != nil || p != tt.want {
+			t.Errorf("Scan(%v) = %+v, %v; want %+v", tt.src, p, err, tt.want)
+		}
+	}
+
+	var p GeoPoint
+	for _, src := range []interface{}{"(1)", "POINT(1)", "zz", "0102000000", 42} {
+		if err := p.Scan(src); err == nil {
+			t.Errorf("Scan(%v) should fail", src)
+		}
+	}
+}
+
+func TestParseGeoRadius(t *testing.T) {
+	area, err := ParseGeoRadius("-6.2,106.8,5.5")
+	if err != nil || area.Center != (GeoPoint{-6.2, 106.8}) || area.RadiusKm != 5.5 {
+		t.Errorf("ParseGeoRadius() = %+v, %v", area, err)
+	}
+	if area.String() != "-6.2,106.8,5.5" {
+		t.Errorf("String() = %q", area.String())
+	}
+	for _, raw := range []string{"-6.2,106.8", "-6.2,106.8,0", "-6.2,106.8,-1", "-6.2,106.8,x", "100,0,5", "5"} {
+		if _, err := ParseGeoRadius(raw); err == nil {
+			t.Errorf("ParseGeoRadius(%q) should fail", raw)
+		}
+	}
+}
+
+func TestGeoColumn_Near(t *testing.T) {
+	area := GeoRadius{Center: GeoPoint{Lat: -6.2, Lng: 106.8}, RadiusKm: 5}
+
+	near, err := GeoColumn{Column: "s.location", Type: GeoColumnPostGIS}.Near(area, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	center := "ST_SetSRID(ST_MakePoint($2::float8, $3::float8), 4326)::geography"
+	if near.Where != "ST_DWithin(s.location, "+center+", $4::float8 * 1000)" ||
+		near.Distance != "ST_Distance(s.location, "+center+") / 1000" {
+		t.Errorf("postgis = %+v", near)
+	}
+	if len(near.Args) != 3 || near.Args[0] != 106.8 || near.Args[1] != -6.2 || near.Args[2] != 5.0 {
+		t.Errorf("args = %v", near.Args)
+	}
+
+	near, err = GeoColumn{Column: "location", Type: GeoColumnPoint}.Near(area, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if near.Distance != "(location <@> point($1::float8, $2::float8)) * 1.609344" || near.Where != near.Distance+" <= $3::float8" {
+		t.Errorf("point = %+v", near)
+	}
+
+	if _, err := (GeoColumn{Column: "location", Type: "mysql"}).Near(area, 0); err == nil {
+		t.Error("unknown column type should fail")
+	}
+	if _, err := (GeoColumn{Column: "location", Type: GeoColumnPoint}).Near(GeoRadius{Center: area.Center}, 0); err == nil {
+		t.Error("zero radius should fail")
+	}
+}
+
+func TestValidate_GeoPoint(t *testing.T) {
+	type request struct {
+		Location GeoPoint  `json:"location" validate:"required,latlng"`
+		Text     string    `json:"text" validate:"omitempty,latlng"`
+		Address  Address   `json:"address"`
+		Optional *GeoPoint `json:"optional" validate:"omitempty,latlng"`
+	}
+
+	valid := request{
+		Location: GeoPoint{Lat: -6.2, Lng: 106.8},
+		Text:     "1,2",
+		Address:  Address{Line1: "Jl. Sudirman 1", City: "Jakarta", Country: "ID"},
+	}
+	if errs := ValidateStruct(valid); errs != nil {
+		t.Errorf("ValidateStruct() = %v", errs)
+	}
+
+	errs := NewValidator().WithLocale("en").Struct(request{
+		Location: GeoPoint{Lat: 91},
+		Text:     "1",
+		Address:  Address{Line1: "Jl. Sudirman 1", City: "Jakarta", Country: "ID", Location: &GeoPoint{Lng: 200}},
+	}).ErrorMap()
+	if errs["location"] != "location must be valid coordinates" || errs["text"] == "" || errs["address.location"] == "" {
+		t.Errorf("errors = %v", errs)
+	}
+
+	v := NewValidator().GeoPoint("a", GeoPoint{Lat: 1, Lng: 2}).GeoPoint("b", GeoPoint{Lat: -100})
+	if v.HasError("a") || v.GetError("b") != "b harus berupa koordinat yang valid" {
+		t.Errorf("errors = %v", v.ErrorMap())
+	}
+}
+
+func TestAddress_String(t *testing.T) {
+	a := Address{Line1: "Jl. Sudirman 1", City: "Jakarta", Region: "DKI Jakarta", PostalCode: "10220", Country: "ID"}
+	if got := a.String(); got != "Jl. Sudirman 1, Jakarta, DKI Jakarta 10220, ID" {
+		t.Errorf("String() = %q", got)
+	}
+}
@@ -0,0 +1,341 @@
+package dim
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// HealthCheckFunc memeriksa satu dependency. Mengembalikan error jika dependency tidak sehat.
+// Context dibatalkan ketika timeout check habis.
+type HealthCheckFunc func(ctx context.Context) error
+
+// HealthCheck mendeskripsikan satu pemeriksaan kesehatan komponen.
+type HealthCheck struct {
+	// Name adalah nama unik check di response (contoh: "database", "redis").
+	Name string
+	// Check menjalankan pemeriksaan.
+	Check HealthCheckFunc
+	// Timeout adalah batas waktu check. Jika 0, timeout default HealthChecker digunakan.
+	Timeout time.Duration
+	// Liveness menjalankan check juga di endpoint liveness. Biarkan false untuk dependency
+	// eksternal agar gangguan database tidak membuat orchestrator me-restart semua pod.
+	Liveness bool
+}
+
+// HealthStatus adalah status check atau status keseluruhan.
+type HealthStatus string
+
+const (
+	HealthStatusOK    HealthStatus = "ok"
+	HealthStatusError HealthStatus = "error"
+)
+
+// HealthCheckResult adalah hasil satu check di HealthReport.
+type HealthCheckResult struct {
+	Status HealthStatus `json:"status"`
+	// LatencyMs adalah durasi check dalam milidetik.
+	LatencyMs float64 `json:"latency_ms"`
+	// Error berisi pesan error jika WithErrorDetails(true); selain itu "check failed".
+	Error string `json:"error,omitempty"`
+}
+
+// HealthReport adalah response endpoint liveness dan readiness.
+type HealthReport struct {
+	Status    HealthStatus                 `json:"status"`
+	Checks    map[string]HealthCheckResult `json:"checks,omitempty"`
+	CheckedAt time.Time                    `json:"checked_at"`
+}
+
+// healthCache menyimpan report terakhir untuk satu endpoint.
+type healthCache struct {
+	mu     sync.Mutex
+	report HealthReport
+	expiry time.Time
+}
+
+// HealthChecker adalah registry pemeriksaan kesehatan komponen (database, cache, mail transport)
+// yang dilayani sebagai endpoint liveness dan readiness. Check dijalankan secara concurrent dan
+// hasilnya di-cache selama TTL agar probe yang sering tidak membebani dependency.
+// Thread-safe.
+type HealthChecker struct {
+	mu           sync.RWMutex
+	checks       []HealthCheck
+	names        map[string]bool
+	timeout      time.Duration
+	cacheTTL     time.Duration
+	errorDetails bool
+	liveness     healthCache
+	readiness    healthCache
+}
+
+// NewHealthChecker membuat HealthChecker kosong dengan timeout 2 detik per check dan cache 1 detik.
+//
+// Returns:
+//   - *HealthChecker: registry yang siap diisi dengan Add atau Register
+//
+// Example:
+//
+//	health := dim.NewHealthChecker().WithCacheTTL(5 * time.Second)
+//	health.Add("database", dim.DatabaseHealthCheck(db))
+//	health.Add("redis", func(ctx context.Context) error { return rdb.Ping(ctx).Err() })
+//	router.Get("/readyz", health.ReadinessHandler())
+func NewHealthChecker() *HealthChecker {
+	return &HealthChecker{
+		names:    make(map[string]bool),
+		timeout:  2 * time.Second,
+		cacheTTL: time.Second,
+	}
+}
+
+// WithTimeout mengatur timeout default untuk check yang tidak menentukan Timeout sendiri.
+func (h *HealthChecker) WithTimeout(timeout time.Duration) *HealthChecker {
+	h.timeout = timeout
+	return h
+}
+
+// WithCacheTTL mengatur berapa lama report di-cache. 0 berarti check dijalankan setiap request.
+func (h *HealthChecker) WithCacheTTL(ttl time.Duration) *HealthChecker {
+	h.cacheTTL = ttl
+	return h
+}
+
+// WithErrorDetails menampilkan pesan error check di response. Default false karena pesan error
+// dapat berisi host atau detail internal; aktifkan jika endpoint hanya dapat diakses internal.
+func (h *HealthChecker) WithErrorDetails(enabled bool) *HealthChecker {
+	h.errorDetails = enabled
+	return h
+}
+
+// Register mendaftarkan check. Name harus unik dan Check tidak boleh nil.
+//
+// Parameters:
+//   - check: HealthCheck yang akan didaftarkan
+//
+// Returns:
+//   - error: error jika nama kosong, sudah terdaftar, atau Check nil
+func (h *HealthChecker) Register(check HealthCheck) error {
+	if check.Name == "" {
+		return fmt.Errorf("health check name is required")
+	}
+	if check.Check == nil {
+		return fmt.Errorf("health check %s has no check function", check.Name)
+	}
+
+	h.mu.Lock()
+	if h.names[check.Name] {
+		h.mu.Unlock()
+		return fmt.Errorf("health check already registered: %s", check.Name)
+	}
+	h.names[check.Name] = true
+	h.checks = append(h.checks, check)
+	h.mu.Unlock()
+
+	h.liveness.invalidate()
+	h.readiness.invalidate()
+	return nil
+}
+
+// Add mendaftarkan check readiness dengan timeout default. Panic jika nama kosong, duplikat,
+// atau fn nil, karena merupakan kesalahan wiring saat startup.
+//
+// Example:
+//
+//	router.Health("/healthz", "/readyz").
+//	    Add("database", dim.DatabaseHealthCheck(db)).
+//	    Add("mail", mailer.Ping)
+func (h *HealthChecker) Add(name string, fn HealthCheckFunc) *HealthChecker {
+	if err := h.Register(HealthCheck{Name: name, Check: fn}); err != nil {
+		panic("dim: " + err.Error())
+	}
+	return h
+}
+
+// Liveness menjalankan check yang ditandai Liveness. Tanpa check tersebut, liveness selalu ok
+// selama proses dapat melayani request.
+func (h *HealthChecker) Liveness(ctx context.Context) HealthReport {
+	return h.report(ctx, &h.liveness, true)
+}
+
+// Readiness menjalankan semua check. Status error jika salah satu check gagal.
+func (h *HealthChecker) Readiness(ctx context.Context) HealthReport {
+	return h.report(ctx, &h.readiness, false)
+}
+
+// LivenessHandler melayani Liveness sebagai JSON: 200 jika ok, 503 jika ada check yang gagal.
+func (h *HealthChecker) LivenessHandler() HandlerFunc {
+	return h.handler(h.Liveness)
+}
+
+// ReadinessHandler melayani Readiness sebagai JSON: 200 jika ok, 503 jika ada check yang gagal.
+//
+// Response:
+//
+//	{
+//	  "status": "error",
+//	  "checks": {
+//	    "database": {"status": "ok", "latency_ms": 1.42},
+//	    "redis": {"status": "error", "latency_ms": 2000.31, "error": "check failed"}
+//	  },
+//	  "checked_at": "2024-05-01T10:00:00Z"
+//	}
+func (h *HealthChecker) ReadinessHandler() HandlerFunc {
+	return h.handler(h.Readiness)
+}
+
+func (h *HealthChecker) handler(run func(context.Context) HealthReport) HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		report := run(r.Context())
+		status := http.StatusOK
+		if report.Status != HealthStatusOK {
+			status = http.StatusServiceUnavailable
+		}
+		w.Header().Set("Cache-Control", "no-store")
+		Json(w, status, report)
+	}
+}
+
+// report mengembalikan report dari cache atau menjalankan check. Request yang datang saat check
+// berjalan menunggu hasil yang sama, sehingga satu endpoint tidak menjalankan check paralel.
+func (h *HealthChecker) report(ctx context.Context, cache *healthCache, livenessOnly bool) HealthReport {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if time.Now().Before(cache.expiry) {
+		return cache.report
+	}
+
+	h.mu.RLock()
+	checks := make([]HealthCheck, 0, len(h.checks))
+	for _, check := range h.checks {
+		if !livenessOnly || check.Liveness {
+			checks = append(checks, check)
+		}
+	}
+	h.mu.RUnlock()
+
+	report := HealthReport{Status: HealthStatusOK, CheckedAt: time.Now().UTC()}
+	if len(checks) > 0 {
+		report.Checks = make(map[string]HealthCheckResult, len(checks))
+	}
+
+	results := make([]HealthCheckResult, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = h.runCheck(context.WithoutCancel(ctx), check)
+		}()
+	}
+	wg.Wait()
+
+	for i, check := range checks {
+		report.Checks[check.Name] = results[i]
+		if results[i].Status != HealthStatusOK {
+			report.Status = HealthStatusError
+		}
+	}
+
+	if h.cacheTTL > 0 {
+		cache.report = report
+		cache.expiry = time.Now().Add(h.cacheTTL)
+	}
+	return report
+}
+
+// runCheck menjalankan satu check dengan timeout dan recovery dari panic.
+func (h *HealthChecker) runCheck(ctx context.Context, check HealthCheck) HealthCheckResult {
+	timeout := check.Timeout
+	if timeout == 0 {
+		timeout = h.timeout
+	}
+
+	start := time.Now()
+	err := callHealthCheck(ctx, check.Check, timeout)
+
+	result := HealthCheckResult{
+		Status:    HealthStatusOK,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		result.Status = HealthStatusError
+		result.Error = "check failed"
+		if h.errorDetails {
+			result.Error = err.Error()
+		}
+	}
+	return result
+}
+
+// callHealthCheck menjalankan fn dengan timeout dan mengubah panic menjadi error. Check yang
+// mengabaikan context tidak menahan pemanggil melewati timeout.
+func callHealthCheck(ctx context.Context, fn HealthCheckFunc, timeout time.Duration) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if rec := recover(); rec != nil {
+				done <- fmt.Errorf("panic: %v", rec)
+			}
+		}()
+		done <- fn(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *healthCache) invalidate() {
+	c.mu.Lock()
+	c.expiry = time.Time{}
+	c.mu.Unlock()
+}
+
+// DatabaseHealthCheck membuat HealthCheckFunc yang menjalankan "SELECT 1" pada db.
+//
+// Example:
+//
+//	health.Add("database", dim.DatabaseHealthCheck(db))
+func DatabaseHealthCheck(db Database) HealthCheckFunc {
+	return func(ctx context.Context) error {
+		var n int
+		return db.QueryRow(ctx, "SELECT 1").Scan(&n)
+	}
+}
+
+// Health mendaftarkan endpoint GET liveness dan readiness dan mengembalikan HealthChecker-nya
+// untuk mendaftarkan check komponen. Path kosong tidak didaftarkan.
+//
+// Parameters:
+//   - livenessPath: path liveness, misal "/healthz"
+//   - readinessPath: path readiness, misal "/readyz"
+//   - middleware: middleware opsional untuk kedua endpoint, misal pembatasan IP internal
+//
+// Returns:
+//   - *HealthChecker: registry check untuk kedua endpoint
+//
+// Example:
+//
+//	health := router.Health("/healthz", "/readyz")
+//	health.Add("database", dim.DatabaseHealthCheck(db))
+func (r *Router) Health(livenessPath, readinessPath string, middleware ...MiddlewareFunc) *HealthChecker {
+	checker := NewHealthChecker()
+	if livenessPath != "" {
+		r.Get(livenessPath, checker.LivenessHandler(), middleware...)
+	}
+	if readinessPath != "" {
+		r.Get(readinessPath, checker.ReadinessHandler(), middleware...)
+	}
+	return checker
+}
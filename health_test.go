@@ -0,0 +1,169 @@
+package dim
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHealthChecker_Readiness(t *testing.T) {
+	health := NewHealthChecker().WithCacheTTL(0).
+		Add("database", func(ctx context.Context) error { return nil }).
+		Add("redis", func(ctx context.Context) error { return errors.New("dial tcp 10.0.0.5:6379: refused") })
+
+	report := health.Readiness(context.Background())
+	if report.Status != HealthStatusError || report.Checks["database"].Status != HealthStatusOK ||
+		report.Checks["redis"].Status != HealthStatusError {
+		t.Errorf("report = %+v", report)
+	}
+	if report.Checks["redis"].Error != "check failed" {
+		t.Errorf("error details should be hidden by default, got %q", report.Checks["redis"].Error)
+	}
+
+	health.WithErrorDetails(true)
+	if got := health.Readiness(context.Background()).Checks["redis"].Error; !strings.Contains(got, "refused") {
+		t.Errorf("error = %q", got)
+	}
+}
+
+func TestHealthChecker_Liveness(t *testing.T) {
+	health := NewHealthChecker().WithCacheTTL(0).
+		Add("database", func(ctx context.Context) error { return errors.New("down") })
+
+	// Dependency eksternal tidak memengaruhi liveness
+	report := health.Liveness(context.Background())
+	if report.Status != HealthStatusOK || len(report.Checks) != 0 {
+		t.Errorf("liveness = %+v", report)
+	}
+
+	health.Register(HealthCheck{Name: "deadlock", Liveness: true, Check: func(ctx context.Context) error { return errors.New("stuck") }})
+	if report := health.Liveness(context.Background()); report.Status != HealthStatusError || len(report.Checks) != 1 {
+		t.Errorf("liveness = %+v", report)
+	}
+}
+
+func TestHealthChecker_TimeoutAndPanic(t *testing.T) {
+	health := NewHealthChecker().WithCacheTTL(0).WithTimeout(20 * time.Millisecond).WithErrorDetails(true)
+	health.Add("slow", func(ctx context.Context) error {
+		time.Sleep(time.Second) // mengabaikan context
+		return nil
+	})
+	health.Register(HealthCheck{Name: "custom_timeout", Timeout: 10 * time.Millisecond, Check: func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}})
+	health.Add("panic", func(ctx context.Context) error { panic("boom") })
+
+	start := time.Now()
+	report := health.Readiness(context.Background())
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Readiness took %v, checks should be bounded by their timeout", elapsed)
+	}
+	if !strings.Contains(report.Checks["slow"].Error, "deadline") || report.Checks["slow"].LatencyMs < 20 {
+		t.Errorf("slow = %+v", report.Checks["slow"])
+	}
+	if !strings.Contains(report.Checks["panic"].Error, "panic: boom") {
+		t.Errorf("panic = %+v", report.Checks["panic"])
+	}
+	if report.Checks["custom_timeout"].LatencyMs >= 20 {
+		t.Errorf("custom_timeout = %+v", report.Checks["custom_timeout"])
+	}
+}
+
+func TestHealthChecker_CacheTTL(t *testing.T) {
+	var calls atomic.Int32
+	health := NewHealthChecker().WithCacheTTL(time.Hour).Add("db", func(ctx context.Context) error {
+		calls.Add(1)
+		return nil
+	})
+
+	for range 3 {
+		health.Readiness(context.Background())
+	}
+	if calls.Load() != 1 {
+		t.Errorf("check called %d times, want 1 (cached)", calls.Load())
+	}
+
+	// Registrasi check baru mengosongkan cache
+	health.Add("redis", func(ctx context.Context) error { return nil })
+	if report := health.Readiness(context.Background()); calls.Load() != 2 || len(report.Checks) != 2 {
+		t.Errorf("calls = %d, report = %+v", calls.Load(), report)
+	}
+}
+
+func TestHealthChecker_RegisterErrors(t *testing.T) {
+	health := NewHealthChecker().Add("db", func(ctx context.Context) error { return nil })
+	if err := health.Register(HealthCheck{Name: "db", Check: func(ctx context.Context) error { return nil }}); err == nil {
+		t.Error("duplicate name should fail")
+	}
+	if err := health.Register(HealthCheck{Check: func(ctx context.Context) error { return nil }}); err == nil {
+		t.Error("empty name should fail")
+	}
+	if err := health.Register(HealthCheck{Name: "nil"}); err == nil {
+		t.Error("nil check should fail")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Add with duplicate name should panic")
+		}
+	}()
+	health.Add("db", func(ctx context.Context) error { return nil })
+}
+
+func TestRouter_Health(t *testing.T) {
+	router := NewRouter()
+	var healthy atomic.Bool
+	router.Health("/healthz", "/readyz").WithCacheTTL(0).Add("database", func(ctx context.Context) error {
+		if !healthy.Load() {
+			return errors.New("down")
+		}
+		return nil
+	})
+
+	serve := func(path string) (*httptest.ResponseRecorder, HealthReport) {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		var report HealthReport
+		json.Unmarshal(rec.Body.Bytes(), &report)
+		return rec, report
+	}
+
+	rec, report := serve("/readyz")
+	if rec.Code != http.StatusServiceUnavailable || report.Status != HealthStatusError || rec.Header().Get("Cache-Control") != "no-store" {
+		t.Errorf("readyz = %d %s", rec.Code, rec.Body.String())
+	}
+	if !strings.Contains(rec.Body.String(), `"latency_ms"`) {
+		t.Errorf("body = %s", rec.Body.String())
+	}
+
+	if rec, _ := serve("/healthz"); rec.Code != http.StatusOK {
+		t.Errorf("healthz = %d %s", rec.Code, rec.Body.String())
+	}
+
+	healthy.Store(true)
+	if rec, report := serve("/readyz"); rec.Code != http.StatusOK || report.Checks["database"].Status != HealthStatusOK {
+		t.Errorf("readyz = %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestDatabaseHealthCheck(t *testing.T) {
+	db, err := NewSQLiteDatabase(DatabaseConfig{Database: ":memory:"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	check := DatabaseHealthCheck(db)
+	if err := check(context.Background()); err != nil {
+		t.Errorf("check() error = %v", err)
+	}
+	db.Close()
+	if err := check(context.Background()); err == nil {
+		t.Error("check() on closed database should fail")
+	}
+}
@@ -0,0 +1,341 @@
+package dim
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Locale bawaan framework.
+const (
+	LocaleID = "id"
+	LocaleEN = "en"
+)
+
+const localeKey contextKey = "locale"
+
+// Translator menerjemahkan pesan ke locale tertentu.
+// Pesan memakai teks Indonesia asli sebagai key (gaya gettext), sehingga pesan yang belum
+// diterjemahkan tetap tampil dalam bahasa Indonesia. args diformat dengan fmt.Sprintf.
+type Translator interface {
+	Translate(locale, message string, args ...interface{}) string
+}
+
+// BundleTranslator adalah Translator berbasis bundle map per locale.
+// Locale "en-US" dicocokkan ke bundle "en" jika bundle spesifik tidak ada.
+type BundleTranslator struct {
+	mu       sync.RWMutex
+	fallback string
+	bundles  map[string]map[string]string
+}
+
+// NewBundleTranslator membuat BundleTranslator dengan bundle bawaan ID dan EN.
+//
+// Parameters:
+//   - fallback: locale yang dipakai jika locale request tidak memiliki bundle
+//
+// Returns:
+//   - *BundleTranslator: translator yang siap digunakan
+//
+// Example:
+//
+//	t := dim.NewBundleTranslator(dim.LocaleID).
+//	    AddBundle("en", map[string]string{"Pesanan tidak ditemukan": "Order not found"})
+//	dim.SetTranslator(t)
+func NewBundleTranslator(fallback string) *BundleTranslator {
+	t := &BundleTranslator{
+		fallback: fallback,
+		bundles:  make(map[string]map[string]string),
+	}
+	t.AddBundle(LocaleID, map[string]string{})
+	t.AddBundle(LocaleEN, englishMessages)
+	return t
+}
+
+// AddBundle menambahkan atau menggabungkan pesan untuk locale.
+// Key adalah pesan Indonesia (termasuk verb fmt seperti %s), value adalah terjemahannya.
+func (t *BundleTranslator) AddBundle(locale string, messages map[string]string) *BundleTranslator {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	locale = normalizeLocale(locale)
+	bundle, ok := t.bundles[locale]
+	if !ok {
+		bundle = make(map[string]string, len(messages))
+		t.bundles[locale] = bundle
+	}
+	for k, v := range messages {
+		bundle[k] = v
+	}
+	return t
+}
+
+// Locales mengembalikan daftar locale yang memiliki bundle, terurut.
+func (t *BundleTranslator) Locales() []string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	locales := make([]string, 0, len(t.bundles))
+	for l := range t.bundles {
+		locales = append(locales, l)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// Translate menerjemahkan pesan ke locale. Jika tidak ada terjemahan, pesan asli dipakai.
+func (t *BundleTranslator) Translate(locale, message string, args ...interface{}) string {
+	template := t.lookup(locale, message)
+	if len(args) == 0 {
+		return template
+	}
+	return fmt.Sprintf(template, args...)
+}
+
+func (t *BundleTranslator) lookup(locale, message string) string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	for _, l := range []string{normalizeLocale(locale), baseLanguage(locale), t.fallback} {
+		if l == "" {
+			continue
+		}
+		if bundle, ok := t.bundles[l]; ok {
+			if translated, ok := bundle[message]; ok {
+				return translated
+			}
+			return message
+		}
+	}
+	return message
+}
+
+var (
+	translatorMu      sync.RWMutex
+	defaultTranslator Translator = NewBundleTranslator(LocaleID)
+)
+
+// SetTranslator mengganti Translator global yang dipakai Validator, FilterParser, AuthService, dan JsonError.
+func SetTranslator(t Translator) {
+	translatorMu.Lock()
+	defer translatorMu.Unlock()
+	defaultTranslator = t
+}
+
+// GetTranslator mengembalikan Translator global.
+func GetTranslator() Translator {
+	translatorMu.RLock()
+	defer translatorMu.RUnlock()
+	return defaultTranslator
+}
+
+// Translate menerjemahkan pesan menggunakan Translator global.
+func Translate(locale, message string, args ...interface{}) string {
+	return GetTranslator().Translate(locale, message, args...)
+}
+
+// T menerjemahkan pesan ke locale yang tersimpan di context (lihat LocaleMiddleware).
+//
+// Example:
+//
+//	dim.JsonError(w, 404, dim.T(r.Context(), "Pesanan %s tidak ditemukan", id), nil)
+func T(ctx context.Context, message string, args ...interface{}) string {
+	return Translate(LocaleFromContext(ctx), message, args...)
+}
+
+// WithLocale menyimpan locale di context.
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey, normalizeLocale(locale))
+}
+
+// LocaleFromContext mengambil locale dari context, atau string kosong jika tidak di-set.
+func LocaleFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	locale, _ := ctx.Value(localeKey).(string)
+	return locale
+}
+
+// GetLocale mengambil locale request yang di-set oleh LocaleMiddleware.
+func GetLocale(r *http.Request) string {
+	return LocaleFromContext(r.Context())
+}
+
+// LocaleMiddleware mendeteksi locale dari header Accept-Language dan menyimpannya di context.
+// Locale dipilih dari supported berdasarkan q-value; jika tidak ada yang cocok, supported[0] dipakai.
+// Middleware juga menambahkan header Content-Language dan membuat JsonError menerjemahkan
+// message serta field errors statis ke locale tersebut.
+//
+// Parameters:
+//   - supported: locale yang didukung aplikasi; default "id" dan "en"
+//
+// Returns:
+//   - MiddlewareFunc: middleware deteksi locale
+//
+// Example:
+//
+//	router.Use(dim.LocaleMiddleware("id", "en"))
+func LocaleMiddleware(supported ...string) MiddlewareFunc {
+	if len(supported) == 0 {
+		supported = []string{LocaleID, LocaleEN}
+	}
+	normalized := make([]string, len(supported))
+	for i, l := range supported {
+		normalized[i] = normalizeLocale(l)
+	}
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			locale := NegotiateLocale(r.Header.Get("Accept-Language"), normalized)
+
+			w.Header().Set("Content-Language", locale)
+			w.Header().Add("Vary", "Accept-Language")
+
+			r = r.WithContext(WithLocale(r.Context(), locale))
+			next(&localeResponseWriter{ResponseWriter: w, locale: locale}, r)
+		}
+	}
+}
+
+// NegotiateLocale memilih locale terbaik dari header Accept-Language.
+// Mencocokkan tag lengkap lebih dulu ("en-US"), lalu bahasa dasar ("en").
+// Mengembalikan supported[0] jika tidak ada yang cocok.
+func NegotiateLocale(acceptLanguage string, supported []string) string {
+	if len(supported) == 0 {
+		return ""
+	}
+
+	type candidate struct {
+		tag string
+		q   float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = normalizeLocale(tag)
+		if tag == "" {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+				q = parsed
+			}
+		}
+		if q > 0 {
+			candidates = append(candidates, candidate{tag, q})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+
+	for _, c := range candidates {
+		if c.tag == "*" {
+			return supported[0]
+		}
+		for _, s := range supported {
+			if s == c.tag {
+				return s
+			}
+		}
+		for _, s := range supported {
+			if baseLanguage(s) == baseLanguage(c.tag) {
+				return s
+			}
+		}
+	}
+	return supported[0]
+}
+
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
+
+func baseLanguage(locale string) string {
+	base, _, _ := strings.Cut(normalizeLocale(locale), "-")
+	return base
+}
+
+// localeResponseWriter membawa locale request agar JsonError dapat menerjemahkan pesan.
+type localeResponseWriter struct {
+	http.ResponseWriter
+	locale string
+}
+
+// Unwrap mengembalikan ResponseWriter asli untuk http.ResponseController.
+func (w *localeResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Flush meneruskan flush ke ResponseWriter asli jika didukung (streaming/SSE).
+func (w *localeResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// writerLocale mencari locale yang dipasang LocaleMiddleware pada ResponseWriter.
+func writerLocale(w http.ResponseWriter) (string, bool) {
+	for w != nil {
+		if lw, ok := w.(*localeResponseWriter); ok {
+			return lw.locale, true
+		}
+		u, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			return "", false
+		}
+		w = u.Unwrap()
+	}
+	return "", false
+}
+
+// translateFieldErrors menerjemahkan pesan field error yang berupa teks statis.
+func translateFieldErrors(locale string, errors FieldErrors) FieldErrors {
+	if len(errors) == 0 {
+		return errors
+	}
+	translated := make(FieldErrors, len(errors))
+	for field, msg := range errors {
+		switch m := msg.(type) {
+		case string:
+			translated[field] = Translate(locale, m)
+		case []string:
+			msgs := make([]string, len(m))
+			for i, s := range m {
+				msgs[i] = Translate(locale, s)
+			}
+			translated[field] = msgs
+		default:
+			translated[field] = msg
+		}
+	}
+	return translated
+}
+
+// localizedError adalah error dengan format dan argumen terpisah agar dapat diterjemahkan saat ditampilkan.
+type localizedError struct {
+	format string
+	args   []interface{}
+}
+
+func localizedErrorf(format string, args ...interface{}) error {
+	return &localizedError{format: format, args: args}
+}
+
+func (e *localizedError) Error() string {
+	return fmt.Sprintf(e.format, e.args...)
+}
+
+// translateError menerjemahkan localizedError ke locale, atau mengembalikan pesan error apa adanya.
+func translateError(locale string, err error) string {
+	var le *localizedError
+	if errors.As(err, &le) {
+		return Translate(locale, le.format, le.args...)
+	}
+	return Translate(locale, err.Error())
+}
@@ -0,0 +1,210 @@
+package dim
+
+// englishMessages adalah bundle bawaan locale "en".
+// Key adalah pesan Indonesia yang dihasilkan framework, termasuk verb fmt-nya.
+var englishMessages = map[string]string{
+	// Validator
+	"%s wajib diisi": "%s is required",
+	"%s harus berupa alamat email yang valid":                               "%s must be a valid email address",
+	"%s harus minimal %d karakter":                                          "%s must be at least %d characters",
+	"%s tidak boleh melebihi %d karakter":                                   "%s must not exceed %d characters",
+	"%s harus tepat %d karakter":                                            "%s must be exactly %d characters",
+	"pola validasi tidak valid":                                             "invalid validation pattern",
+	"format %s tidak valid":                                                 "%s has an invalid format",
+	"%s memiliki nilai yang tidak valid":                                    "%s has an invalid value",
+	"%s harus antara %d dan %d":                                             "%s must be between %d and %d",
+	"%s tidak cocok dengan %s":                                              "%s does not match %s",
+	"%s harus berupa URL yang valid":                                        "%s must be a valid URL",
+	"%s harus berupa UUID yang valid":                                       "%s must be a valid UUID",
+	"%s harus berupa angka":                                                 "%s must be a number",
+	"%s hanya boleh berisi huruf dan angka":                                 "%s may only contain letters and numbers",
+	"%s harus minimal %s karakter":                                          "%s must be at least %s characters",
+	"%s harus minimal %s":                                                   "%s must be at least %s",
+	"%s harus berisi minimal %s item":                                       "%s must contain at least %s items",
+	"%s tidak boleh melebihi %s karakter":                                   "%s must not exceed %s characters",
+	"%s tidak boleh lebih dari %s":                                          "%s must not be greater than %s",
+	"%s tidak boleh berisi lebih dari %s item":                              "%s must not contain more than %s items",
+	"%s harus tepat %s karakter":                                            "%s must be exactly %s characters",
+	"%s harus bernilai %s":                                                  "%s must equal %s",
+	"%s harus berisi tepat %s item":                                         "%s must contain exactly %s items",
+	"%s harus berupa nomor telepon yang valid":                              "%s must be a valid phone number",
+	"%s harus berupa koordinat yang valid":                                  "%s must be valid coordinates",
+	"%s harus dikosongkan":                                                  "%s must be left empty",
+	"Validasi gagal":                                                        "Validation failed",
+	"Validasi kata sandi gagal":                                             "Password validation failed",
+	"Kata sandi harus minimal %d karakter":                                  "Password must be at least %d characters",
+	"Kata sandi maksimal %d karakter":                                       "Password must be at most %d characters",
+	"Kata sandi terlalu umum, gunakan kata sandi lain":                      "Password is too common, choose another password",
+	"Kata sandi pernah bocor dalam kebocoran data, gunakan kata sandi lain": "Password has appeared in a data breach, choose another password",
+	"Kata sandi harus mengandung minimal satu huruf besar":                  "Password must contain at least one uppercase letter",
+	"Kata sandi harus mengandung minimal satu huruf kecil":                  "Password must contain at least one lowercase letter",
+	"Kata sandi harus mengandung minimal satu angka":                        "Password must contain at least one digit",
+	"Kata sandi harus mengandung minimal satu karakter spesial":             "Password must contain at least one special character",
+
+	// Bind
+	"Content-Type tidak valid":                       "Invalid Content-Type",
+	"Content-Type tidak didukung":                    "Unsupported Content-Type",
+	"Ukuran request terlalu besar":                   "Request body too large",
+	"Format JSON tidak valid":                        "Invalid JSON format",
+	"Format form tidak valid":                        "Invalid form format",
+	"Parameter tidak valid":                          "Invalid parameter",
+	"%s harus bertipe %s":                            "%s must be of type %s",
+	"%s tidak dikenal":                               "%s is not a known field",
+	"harus berupa tanggal (RFC3339 atau YYYY-MM-DD)": "must be a date (RFC3339 or YYYY-MM-DD)",
+	"tidak valid":                                    "is invalid",
+	"harus berupa boolean":                           "must be a boolean",
+	"harus berupa durasi":                            "must be a duration",
+	"harus berupa bilangan bulat":                    "must be an integer",
+	"harus berupa bilangan bulat positif":            "must be a positive integer",
+
+	// FilterParser
+	"format tanggal tidak valid (gunakan YYYY-MM-DD atau YYYY-MM-DD,YYYY-MM-DD)": "invalid date format (use YYYY-MM-DD or YYYY-MM-DD,YYYY-MM-DD)",
+	"format amount tidak valid":                              "invalid amount format",
+	"format angka tidak valid (gunakan 100 atau 100,500)":    "invalid number format (use 100 or 100,500)",
+	"maksimal %d nilai diperbolehkan, diterima %d":           "at most %d values allowed, received %d",
+	"UUID tidak valid: %s":                                   "invalid UUID: %s",
+	"UUID tidak valid":                                       "invalid UUID",
+	"harus berupa angka: %s":                                 "must be a number: %s",
+	"harus berupa angka desimal: %s":                         "must be a decimal number: %s",
+	"harus berupa angka":                                     "must be a number",
+	"harus berupa true atau false":                           "must be true or false",
+	"constraint tidak valid: tidak ada nilai yang diizinkan": "invalid constraint: no allowed values",
+	"constraint %s tidak valid: %s":                          "invalid %s constraint: %s",
+	"nilai tidak valid: %s (diizinkan: %s)":                  "invalid value: %s (allowed: %s)",
+	"operator %s tidak didukung":                             "operator %s is not supported",
+	"operator %s tidak diizinkan untuk filter ini":           "operator %s is not allowed for this filter",
+	"nilai operator %s wajib diisi":                          "a value for operator %s is required",
+	"operator %s hanya menerima satu nilai":                  "operator %s accepts a single value",
+	"format tanggal tidak valid (gunakan YYYY-MM-DD)":        "invalid date format (use YYYY-MM-DD)",
+	"harus berupa angka atau tanggal (YYYY-MM-DD): %s":       "must be a number or a date (YYYY-MM-DD): %s",
+	"format waktu tidak valid: %s (gunakan %s)":              "invalid time format: %s (use %s)",
+	"preset filter tidak ditemukan: %s":                      "filter preset not found: %s",
+	"preset filter gagal dimuat":                             "failed to load filter preset",
+	"rentang waktu tidak valid (gunakan %s, dari <= sampai)": "invalid time range (use %s, from <= to)",
+	"format lokasi tidak valid (gunakan lat,lng,radius_km)":  "invalid location format (use lat,lng,radius_km)",
+	"radius maksimal %s km":                                  "radius must be at most %s km",
+
+	// FilterPresetHandler
+	"Gagal memuat preset filter":              "Failed to load filter presets",
+	"Gagal menyimpan preset filter":           "Failed to save filter preset",
+	"Preset filter tidak ditemukan":           "Filter preset not found",
+	"Preset filter dengan nama ini sudah ada": "A filter preset with this name already exists",
+	"Preset filter tidak valid":               "Invalid filter preset",
+	"name sudah dipakai":                      "name is already taken",
+	"name harus 1-100 karakter huruf, angka, titik, garis bawah, atau tanda hubung": "name must be 1-100 letters, digits, dots, underscores, or hyphens",
+	"filter tidak valid":  "invalid filter",
+	"filters wajib diisi": "filters is required",
+
+	// AuthService
+	"Kredensial tidak valid":                                    "Invalid credentials",
+	"Gagal membuat claims":                                      "Failed to build claims",
+	"Gagal membuat access token":                                "Failed to create access token",
+	"Gagal membuat refresh token":                               "Failed to create refresh token",
+	"Gagal menyimpan refresh token":                             "Failed to save refresh token",
+	"Refresh token tidak valid":                                 "Invalid refresh token",
+	"Token telah dibatalkan (revoked)":                          "Token has been revoked",
+	"Token telah kadaluarsa":                                    "Token has expired",
+	"Pengguna tidak ditemukan":                                  "User not found",
+	"Gagal membuat token reset":                                 "Failed to create reset token",
+	"Gagal menyimpan token reset":                               "Failed to save reset token",
+	"Token reset tidak valid atau kadaluarsa":                   "Reset token is invalid or expired",
+	"Token reset telah kadaluarsa":                              "Reset token has expired",
+	"Token reset sudah pernah digunakan":                        "Reset token has already been used",
+	"Gagal memproses password hash":                             "Failed to process password hash",
+	"Gagal memperbarui password":                                "Failed to update password",
+	"Gagal menandai token reset":                                "Failed to mark reset token",
+	"Refresh token diperlukan":                                  "Refresh token is required",
+	"Refresh token tidak valid atau expired":                    "Refresh token is invalid or expired",
+	"Gagal logout":                                              "Failed to log out",
+	"Email sudah terdaftar":                                     "Email is already registered",
+	"Registrasi tidak dikonfigurasi":                            "Registration is not configured",
+	"Gagal memeriksa email":                                     "Failed to check email",
+	"Gagal menyimpan pengguna":                                  "Failed to save user",
+	"Verifikasi email tidak dikonfigurasi":                      "Email verification is not configured",
+	"Gagal membuat token verifikasi":                            "Failed to create verification token",
+	"Gagal menyimpan token verifikasi":                          "Failed to save verification token",
+	"Gagal mengirim email verifikasi":                           "Failed to send verification email",
+	"Token verifikasi tidak valid atau kadaluarsa":              "Verification token is invalid or expired",
+	"Gagal menandai token verifikasi":                           "Failed to mark verification token",
+	"Gagal memverifikasi email":                                 "Failed to verify email",
+	"Gagal memeriksa verifikasi email":                          "Failed to check email verification",
+	"Email belum diverifikasi":                                  "Email has not been verified",
+	"Password saat ini salah":                                   "Current password is incorrect",
+	"Password baru harus berbeda dari password saat ini":        "New password must differ from the current password",
+	"Gagal mengakhiri sesi lain":                                "Failed to end other sessions",
+	"Ganti email tidak dikonfigurasi":                           "Email change is not configured",
+	"Email baru sama dengan email saat ini":                     "New email is the same as the current email",
+	"Gagal membuat token konfirmasi":                            "Failed to create confirmation token",
+	"Gagal menyimpan permintaan ganti email":                    "Failed to save email change request",
+	"Gagal mengirim email konfirmasi":                           "Failed to send confirmation email",
+	"Token konfirmasi tidak valid atau kadaluarsa":              "Confirmation token is invalid or expired",
+	"Gagal menyimpan konfirmasi":                                "Failed to save confirmation",
+	"Gagal memperbarui email":                                   "Failed to update email",
+	"Tidak dapat meng-impersonate diri sendiri":                 "You cannot impersonate yourself",
+	"Tidak dapat memulai impersonation dari sesi impersonation": "Cannot start impersonation from an impersonation session",
+	"Bukan sesi impersonation":                                  "Not an impersonation session",
+	"Gagal mengakhiri impersonation":                            "Failed to end impersonation",
+	"Aksi ini tidak tersedia selama impersonation":              "This action is not available while impersonating",
+
+	// Middleware dan response umum
+	"Header otorisasi hilang atau tidak valid":                 "Authorization header is missing or invalid",
+	"Token otorisasi hilang atau tidak valid":                  "Authorization token is missing or invalid",
+	"Token tidak valid atau telah kadaluarsa":                  "Token is invalid or expired",
+	"Token tidak valid atau telah expired":                     "Token is invalid or expired",
+	"Gagal memverifikasi status token":                         "Failed to verify token status",
+	"Sesi telah berakhir (Logged out)":                         "Session has ended (logged out)",
+	"Token tidak ditemukan":                                    "Token not found",
+	"Token CSRF diperlukan":                                    "CSRF token is required",
+	"Validasi token CSRF gagal":                                "CSRF token validation failed",
+	"Anda tidak memiliki permission untuk access resource ini": "You do not have permission to access this resource",
+	"User tidak terotentikasi":                                 "User is not authenticated",
+	"Tidak terotorisasi":                                       "Unauthorized",
+	"Tidak authorized":                                         "Unauthorized",
+	"Dilarang":                                                 "Forbidden",
+	"Tidak ditemukan":                                          "Not found",
+	"Konflik":                                                  "Conflict",
+	"Permintaan tidak valid":                                   "Bad request",
+	"Kesalahan server internal":                                "Internal server error",
+	"Terjadi kesalahan pada server":                            "An internal server error occurred",
+	"Layanan tidak tersedia sementara":                         "Service temporarily unavailable",
+	"Batas tingkat permintaan terlampaui":                      "Rate limit exceeded",
+	"Gagal membuat PDF":                                        "Failed to generate PDF",
+	"Gagal membuat gambar":                                     "Failed to generate image",
+	"Gagal memproses response":                                 "Failed to process response",
+	"Waktu permintaan habis":                                   "Request timed out",
+	"Tautan tidak valid atau telah kadaluarsa":                 "Link is invalid or expired",
+	"Konten terlalu panjang untuk QR code":                     "Content is too long for a QR code",
+	"Konten barcode tidak valid":                               "Invalid barcode content",
+	"harus png atau svg":                                       "must be png or svg",
+	"harus L, M, Q, atau H":                                    "must be L, M, Q, or H",
+	"Permintaan ditolak":                                       "Request rejected",
+	"Akses ditolak":                                            "Access denied",
+	"Response terlalu besar":                                   "Response too large",
+	"Formulir kadaluarsa, silakan muat ulang halaman":          "Form expired, please reload the page",
+	"Verifikasi CAPTCHA gagal":                                 "CAPTCHA verification failed",
+	"Route debug tidak ditemukan":                              "Debug route not found",
+	"Versi API tidak didukung":                                 "Unsupported API version",
+	"harus bernilai true atau false":                           "must be true or false",
+
+	// Organisasi dan billing
+	"Organisasi tidak ditemukan":                           "Organization not found",
+	"Anggota tidak ditemukan":                              "Member not found",
+	"Organisasi harus memiliki minimal satu owner":         "Organization must have at least one owner",
+	"Role organisasi tidak mencukupi":                      "Insufficient organization role",
+	"Anda tidak memiliki izin untuk mengundang anggota":    "You are not allowed to invite members",
+	"Anda tidak memiliki izin untuk mengubah role anggota": "You are not allowed to change member roles",
+	"Anda tidak memiliki izin untuk mengeluarkan anggota":  "You are not allowed to remove members",
+	"Gagal membuat token undangan":                         "Failed to create invitation token",
+	"Undangan tidak valid atau telah kadaluarsa":           "Invitation is invalid or expired",
+	"Undangan ini ditujukan untuk email lain":              "This invitation is addressed to another email",
+	"Fitur tidak tersedia pada paket Anda":                 "Feature not available on your plan",
+	"Gagal memeriksa paket langganan":                      "Failed to check subscription plan",
+	"Signature webhook tidak valid":                        "Invalid webhook signature",
+	"Gagal memeriksa kepemilikan resource":                 "Failed to check resource ownership",
+	"Daftar item tidak boleh kosong":                       "Item list must not be empty",
+	"Maksimal %d item per request":                         "At most %d items per request",
+	"Dibatalkan karena item lain gagal":                    "Cancelled because another item failed",
+	"Gagal menyimpan perubahan":                            "Failed to save changes",
+	"Format body tidak valid":                              "Invalid body format",
+	"Format response yang diminta tidak didukung":          "Requested response format is not supported",
+}
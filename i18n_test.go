@@ -0,0 +1,214 @@
+package dim
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBundleTranslator_Translate(t *testing.T) {
+	tr := NewBundleTranslator(LocaleID).
+		AddBundle("en", map[string]string{"Pesanan %s tidak ditemukan": "Order %s not found"}).
+		AddBundle("en-GB", map[string]string{"Validasi gagal": "Validation unsuccessful"})
+
+	tests := []struct {
+		name    string
+		locale  string
+		message string
+		args    []interface{}
+		want    string
+	}{
+		{"indonesian identity", "id", "Validasi gagal", nil, "Validasi gagal"},
+		{"built-in english", "en", "Validasi gagal", nil, "Validation failed"},
+		{"custom bundle with args", "en", "Pesanan %s tidak ditemukan", []interface{}{"A-1"}, "Order A-1 not found"},
+		{"region falls back to base language", "en-US", "Kredensial tidak valid", nil, "Invalid credentials"},
+		{"region specific bundle", "en_GB", "Validasi gagal", nil, "Validation unsuccessful"},
+		{"unknown locale uses fallback", "fr", "Validasi gagal", nil, "Validasi gagal"},
+		{"missing key returns message", "en", "Pesan khusus", nil, "Pesan khusus"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tr.Translate(tt.locale, tt.message, tt.args...); got != tt.want {
+				t.Errorf("Translate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if locales := tr.Locales(); strings.Join(locales, ",") != "en,en-gb,id" {
+		t.Errorf("Locales() = %v", locales)
+	}
+}
+
+func TestNegotiateLocale(t *testing.T) {
+	supported := []string{"id", "en"}
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", "id"},
+		{"en", "en"},
+		{"en-US,en;q=0.9", "en"},
+		{"fr-FR,en;q=0.5,id;q=0.8", "id"},
+		{"fr, de", "id"},
+		{"en;q=0, id", "id"},
+		{"*", "id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			if got := NegotiateLocale(tt.header, supported); got != tt.want {
+				t.Errorf("NegotiateLocale(%q) = %q, want %q", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocaleMiddleware_TranslatesJsonError(t *testing.T) {
+	handler := LocaleMiddleware()(func(w http.ResponseWriter, r *http.Request) {
+		v := NewValidator().WithLocale(GetLocale(r)).
+			Required("email", "").
+			MinLength("password", "abc", 8)
+		JsonError(w, http.StatusBadRequest, "Validasi gagal", v.ErrorMap())
+	})
+
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+	r.Header.Set("Accept-Language", "en-US,en;q=0.9")
+	w := httptest.NewRecorder()
+	handler(w, r)
+
+	if got := w.Header().Get("Content-Language"); got != "en" {
+		t.Errorf("Content-Language = %q, want en", got)
+	}
+	if got := w.Header().Get("Vary"); got != "Accept-Language" {
+		t.Errorf("Vary = %q, want Accept-Language", got)
+	}
+
+	var body ErrorResponse
+	if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if body.Message != "Validation failed" {
+		t.Errorf("message = %q, want Validation failed", body.Message)
+	}
+	if body.Errors["email"] != "email is required" {
+		t.Errorf("email error = %v", body.Errors["email"])
+	}
+	if body.Errors["password"] != "password must be at least 8 characters" {
+		t.Errorf("password error = %v", body.Errors["password"])
+	}
+}
+
+func TestJsonError_WithoutLocaleMiddleware(t *testing.T) {
+	w := httptest.NewRecorder()
+	JsonError(w, http.StatusUnauthorized, "Kredensial tidak valid", nil)
+
+	if !strings.Contains(w.Body.String(), "Kredensial tidak valid") {
+		t.Errorf("expected untranslated message, got %s", w.Body.String())
+	}
+}
+
+func TestValidator_MessagesFormatNumbers(t *testing.T) {
+	v := NewValidator().
+		MinLength("password", "abc", 8).
+		MaxLength("name", "abcdef", 5).
+		Length("code", "123", 6).
+		NumRange("age", 10, 18, 120)
+
+	want := map[string]string{
+		"password": "password harus minimal 8 karakter",
+		"name":     "name tidak boleh melebihi 5 karakter",
+		"code":     "code harus tepat 6 karakter",
+		"age":      "age harus antara 18 dan 120",
+	}
+	for field, msg := range want {
+		if got := v.GetError(field); got != msg {
+			t.Errorf("%s: got %q, want %q", field, got, msg)
+		}
+	}
+}
+
+func TestValidateStruct_Localized(t *testing.T) {
+	type request struct {
+		Email string `json:"email" validate:"required,email"`
+		Name  string `json:"name" validate:"min=3"`
+	}
+
+	v := NewValidator().WithLocale("en").Struct(request{Name: "ab"})
+	if got := v.GetError("email"); got != "email is required" {
+		t.Errorf("email: got %q", got)
+	}
+	if got := v.GetError("name"); got != "name must be at least 3 characters" {
+		t.Errorf("name: got %q", got)
+	}
+}
+
+func TestBind_UsesRequestLocale(t *testing.T) {
+	type request struct {
+		Age   int    `query:"age"`
+		Email string `json:"email" validate:"required"`
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/?age=abc", nil)
+	r = r.WithContext(WithLocale(r.Context(), "en"))
+
+	err := BindQuery(r, &request{})
+	appErr, ok := AsAppError(err)
+	if !ok {
+		t.Fatalf("expected AppError, got %v", err)
+	}
+	if appErr.Errors["age"] != "age must be an integer" {
+		t.Errorf("age error = %v", appErr.Errors["age"])
+	}
+}
+
+func TestFilterParser_LocalizedErrors(t *testing.T) {
+	type filters struct {
+		Status []string `filter:"status,in:active|pending"`
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/?filters[status]=deleted", nil)
+	r = r.WithContext(WithLocale(r.Context(), "en"))
+
+	var f filters
+	fp := NewFilterParser(r).Parse(&f)
+	if !fp.HasErrors() {
+		t.Fatal("expected filter error")
+	}
+	if got := fp.Errors()["filters[status]"]; !strings.HasPrefix(got, "invalid value: deleted (allowed: ") {
+		t.Errorf("error = %q", got)
+	}
+}
+
+func TestAuthService_LocalizedValidation(t *testing.T) {
+	service, err := NewAuthService(NewMockUserStore(), NewMockTokenStore(), nil, &JWTConfig{
+		SigningMethod: "HS256",
+		HMACSecret:    "test-secret",
+	})
+	if err != nil {
+		t.Fatalf("NewAuthService error: %v", err)
+	}
+
+	ctx := WithLocale(context.Background(), "en")
+	_, err = service.RequestPasswordReset(ctx, "bukan-email")
+	appErr, ok := AsAppError(err)
+	if !ok {
+		t.Fatalf("expected AppError, got %v", err)
+	}
+	if appErr.Errors["email"] != "email must be a valid email address" {
+		t.Errorf("email error = %v", appErr.Errors["email"])
+	}
+}
+
+func TestT_UsesContextLocale(t *testing.T) {
+	ctx := WithLocale(context.Background(), "EN")
+	if got := T(ctx, "Pengguna tidak ditemukan"); got != "User not found" {
+		t.Errorf("T() = %q", got)
+	}
+	if got := T(context.Background(), "Pengguna tidak ditemukan"); got != "Pengguna tidak ditemukan" {
+		t.Errorf("T() without locale = %q", got)
+	}
+}
@@ -0,0 +1,289 @@
+package dim
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// ActorClaim adalah claim access token (RFC 8693 "act") berisi user yang sedang melakukan
+// impersonation, misal {"act": {"sub": "admin-id", "email": "admin@example.com"}}.
+const ActorClaim = "act"
+
+const impersonatorKey contextKey = "impersonator"
+
+// Jenis ImpersonationEvent.
+const (
+	ImpersonationStarted = "impersonation_started"
+	ImpersonationRequest = "impersonation_request"
+	ImpersonationEnded   = "impersonation_ended"
+)
+
+// Impersonator adalah user asli (misal staf support) di balik request yang di-impersonate.
+type Impersonator struct {
+	ID    string
+	Email string
+}
+
+// ImpersonationEvent adalah entri audit impersonation. Method dan Path hanya diisi untuk
+// ImpersonationRequest.
+type ImpersonationEvent struct {
+	Type      string
+	ActorID   string
+	UserID    string
+	SessionID string
+	Method    string
+	Path      string
+	IPAddress string
+	Time      time.Time
+}
+
+// ImpersonationOptions mengatur Impersonate. Semua field opsional.
+type ImpersonationOptions struct {
+	// Authorize memutuskan apakah actor boleh meng-impersonate target; kembalikan error
+	// (misal *AppError 403) untuk menolak. Jika nil, semua user selain diri sendiri diizinkan,
+	// sehingga route Impersonate wajib dilindungi (misal RequirePermission("users.impersonate")).
+	Authorize func(ctx context.Context, actor, target Authenticatable) error
+	// Audit menerima setiap ImpersonationEvent. Jika nil, event dicatat ke logger service.
+	Audit func(ctx context.Context, event ImpersonationEvent)
+}
+
+// WithImpersonation mengatur otorisasi dan audit log impersonation lalu mengembalikan instance service.
+//
+// Example:
+//
+//	authService.WithImpersonation(dim.ImpersonationOptions{
+//	    Authorize: func(ctx context.Context, actor, target dim.Authenticatable) error {
+//	        if ok, _ := rbac.HasRole(ctx, target.GetID(), "admin"); ok {
+//	            return dim.NewAppError("Admin tidak dapat di-impersonate", http.StatusForbidden)
+//	        }
+//	        return nil
+//	    },
+//	    Audit: func(ctx context.Context, event dim.ImpersonationEvent) {
+//	        auditStore.Save(ctx, event)
+//	    },
+//	})
+func (s *AuthService) WithImpersonation(options ImpersonationOptions) *AuthService {
+	s.impersonation = options
+	return s
+}
+
+// Impersonate menerbitkan access token atas nama target untuk actor (misal staf support).
+// Token membawa claim ActorClaim dan session ID baru, tetapi tanpa refresh token: impersonation
+// berakhir saat access token kadaluarsa atau lewat ExitImpersonation. Actor tidak dapat
+// meng-impersonate diri sendiri atau memulai impersonation dari token impersonation.
+//
+// Parameters:
+//   - ctx: context request
+//   - actorID: ID user yang melakukan impersonation
+//   - targetUserID: ID user yang di-impersonate
+//
+// Returns:
+//   - string: access token impersonation
+//   - error: *AppError (400 target sama dengan actor, 403 ditolak, 404 user tidak ditemukan, 500)
+//
+// Example:
+//
+//	admin, _ := dim.GetUser(r)
+//	token, err := authService.Impersonate(r.Context(), admin.GetID(), dim.GetParam(r, "id"))
+func (s *AuthService) Impersonate(ctx context.Context, actorID, targetUserID string) (string, error) {
+	if actorID == targetUserID {
+		return "", NewAppError("Tidak dapat meng-impersonate diri sendiri", 400)
+	}
+	if user, ok := ctx.Value(userKey).(interface{ GetClaims() map[string]interface{} }); ok {
+		if _, ok := actorFromClaims(user.GetClaims()); ok {
+			return "", NewAppError("Tidak dapat memulai impersonation dari sesi impersonation", http.StatusForbidden)
+		}
+	}
+
+	actor, err := s.userStore.FindByID(ctx, actorID)
+	if err != nil {
+		return "", NewAppError("Pengguna tidak ditemukan", 404)
+	}
+	target, err := s.userStore.FindByID(ctx, targetUserID)
+	if err != nil {
+		return "", NewAppError("Pengguna tidak ditemukan", 404)
+	}
+
+	if s.impersonation.Authorize != nil {
+		if err := s.impersonation.Authorize(ctx, actor, target); err != nil {
+			if _, ok := AsAppError(err); ok {
+				return "", err
+			}
+			return "", NewAppError("Anda tidak memiliki izin untuk melakukan aksi ini", http.StatusForbidden)
+		}
+	}
+
+	extraClaims, err := s.extraClaims(ctx, target, false)
+	if err != nil {
+		return "", err
+	}
+	claims := make(map[string]interface{}, len(extraClaims)+1)
+	for k, v := range extraClaims {
+		claims[k] = v
+	}
+	claims[ActorClaim] = map[string]interface{}{"sub": actor.GetID(), "email": actor.GetEmail()}
+
+	sessionID := NewUuid().String()
+	accessToken, err := s.tokenManager.GenerateAccessToken(target.GetID(), target.GetEmail(), sessionID, claims)
+	if err != nil {
+		return "", NewAppError("Gagal membuat access token", 500)
+	}
+
+	s.auditImpersonation(ctx, ImpersonationEvent{
+		Type:      ImpersonationStarted,
+		ActorID:   actor.GetID(),
+		UserID:    target.GetID(),
+		SessionID: sessionID,
+	})
+	return accessToken, nil
+}
+
+// ExitImpersonation mengakhiri impersonation milik access token di ctx (di-set RequireAuth):
+// session ID dimasukkan ke blocklist lalu token baru diterbitkan untuk actor sehingga client
+// dapat kembali ke akun aslinya.
+//
+// Parameters:
+//   - ctx: context request dengan token impersonation
+//
+// Returns:
+//   - string: access token actor
+//   - string: refresh token actor
+//   - error: *AppError 400 jika request bukan impersonation
+//
+// Example:
+//
+//	access, refresh, err := authService.ExitImpersonation(r.Context())
+func (s *AuthService) ExitImpersonation(ctx context.Context) (string, string, error) {
+	user, ok := ctx.Value(userKey).(interface {
+		Authenticatable
+		GetClaims() map[string]interface{}
+	})
+	if !ok {
+		return "", "", NewAppError("Bukan sesi impersonation", 400)
+	}
+	claims := user.GetClaims()
+	actor, ok := actorFromClaims(claims)
+	if !ok {
+		return "", "", NewAppError("Bukan sesi impersonation", 400)
+	}
+
+	sessionID, _ := claims["sid"].(string)
+	if sessionID != "" && s.blocklist != nil {
+		if err := s.blocklist.Invalidate(ctx, sessionID, s.accessTokenExpiry()); err != nil {
+			return "", "", NewAppError("Gagal mengakhiri impersonation", 500)
+		}
+	}
+
+	actorUser, err := s.userStore.FindByID(ctx, actor.ID)
+	if err != nil {
+		return "", "", NewAppError("Pengguna tidak ditemukan", 404)
+	}
+
+	s.auditImpersonation(ctx, ImpersonationEvent{
+		Type:      ImpersonationEnded,
+		ActorID:   actor.ID,
+		UserID:    user.GetID(),
+		SessionID: sessionID,
+	})
+	return s.issueTokens(ctx, actorUser, false)
+}
+
+// ImpersonationMiddleware menyimpan Impersonator ke context untuk request dengan claim
+// ActorClaim dan mencatat ImpersonationEvent untuk setiap request tersebut. GetUser tetap
+// mengembalikan user yang di-impersonate; gunakan GetImpersonator untuk user aslinya.
+// Pasang setelah RequireAuth.
+//
+// Returns:
+//   - MiddlewareFunc: middleware impersonation
+//
+// Example:
+//
+//	api := router.Group("/api", dim.RequireAuth(jwtManager, blocklist), authService.ImpersonationMiddleware())
+func (s *AuthService) ImpersonationMiddleware() MiddlewareFunc {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			actor, ok := actorFromClaims(GetClaims(r))
+			if !ok {
+				next(w, r)
+				return
+			}
+
+			user, _ := GetUser(r)
+			sessionID, _ := GetClaims(r)["sid"].(string)
+			s.auditImpersonation(r.Context(), ImpersonationEvent{
+				Type:      ImpersonationRequest,
+				ActorID:   actor.ID,
+				UserID:    user.GetID(),
+				SessionID: sessionID,
+				Method:    r.Method,
+				Path:      r.URL.Path,
+				IPAddress: GetClientIP(r),
+			})
+
+			next(w, r.WithContext(context.WithValue(r.Context(), impersonatorKey, actor)))
+		}
+	}
+}
+
+// DenyImpersonation menolak request dari sesi impersonation dengan 403. Pasang pada route
+// sensitif seperti ganti password, ganti email, atau pembayaran.
+//
+// Example:
+//
+//	router.Post("/account/password", changePasswordHandler, auth, dim.DenyImpersonation())
+func DenyImpersonation() MiddlewareFunc {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if _, ok := actorFromClaims(GetClaims(r)); ok {
+				Forbidden(w, "Aksi ini tidak tersedia selama impersonation")
+				return
+			}
+			next(w, r)
+		}
+	}
+}
+
+// GetImpersonator mengembalikan user asli di balik request impersonation (di-set
+// ImpersonationMiddleware).
+func GetImpersonator(r *http.Request) (*Impersonator, bool) {
+	return ImpersonatorFromContext(r.Context())
+}
+
+// ImpersonatorFromContext mengembalikan Impersonator yang disimpan ImpersonationMiddleware.
+func ImpersonatorFromContext(ctx context.Context) (*Impersonator, bool) {
+	actor, ok := ctx.Value(impersonatorKey).(*Impersonator)
+	return actor, ok
+}
+
+// actorFromClaims membaca claim ActorClaim dari access token.
+func actorFromClaims(claims map[string]interface{}) (*Impersonator, bool) {
+	act, ok := claims[ActorClaim].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	id, _ := act["sub"].(string)
+	if id == "" {
+		return nil, false
+	}
+	email, _ := act["email"].(string)
+	return &Impersonator{ID: id, Email: email}, true
+}
+
+func (s *AuthService) auditImpersonation(ctx context.Context, event ImpersonationEvent) {
+	event.Time = time.Now()
+	if s.impersonation.Audit != nil {
+		s.impersonation.Audit(ctx, event)
+		return
+	}
+	if s.logger != nil {
+		s.logger.Info("Impersonation",
+			"type", event.Type,
+			"actor_id", event.ActorID,
+			"user_id", event.UserID,
+			"session_id", event.SessionID,
+			"method", event.Method,
+			"path", event.Path,
+		)
+	}
+}
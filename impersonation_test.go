@@ -0,0 +1,152 @@
+package dim
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAuthService_Impersonation(t *testing.T) {
+	ctx := context.Background()
+	userStore := NewMockUserStore()
+	userStore.AddUser(&MockUser{ID: "admin", Email: "admin@example.com"})
+	userStore.AddUser(&MockUser{ID: "1", Email: "ana@example.com"})
+	userStore.AddUser(&MockUser{ID: "2", Email: "owner@example.com"})
+	blocklist := &ttlBlocklist{InMemoryBlocklist: NewInMemoryBlocklist(), ttls: map[string]time.Duration{}}
+	service, err := NewAuthService(userStore, NewMockTokenStore(), blocklist, &JWTConfig{
+		HMACSecret:         "test-secret",
+		SigningMethod:      "HS256",
+		AccessTokenExpiry:  15 * time.Minute,
+		RefreshTokenExpiry: 7 * 24 * time.Hour,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var events []ImpersonationEvent
+	service.WithImpersonation(ImpersonationOptions{
+		Authorize: func(ctx context.Context, actor, target Authenticatable) error {
+			if target.GetID() == "2" {
+				return errors.New("owners cannot be impersonated")
+			}
+			return nil
+		},
+		Audit: func(ctx context.Context, event ImpersonationEvent) {
+			events = append(events, event)
+		},
+	})
+
+	if _, err := service.Impersonate(ctx, "admin", "admin"); err == nil {
+		t.Error("expected self impersonation to be rejected")
+	}
+	if _, err := service.Impersonate(ctx, "admin", "2"); accountAppStatus(err) != http.StatusForbidden {
+		t.Errorf("Authorize rejection error = %v, want 403", err)
+	}
+	if _, err := service.Impersonate(ctx, "admin", "missing"); accountAppStatus(err) != http.StatusNotFound {
+		t.Errorf("missing target error = %v, want 404", err)
+	}
+
+	token, err := service.Impersonate(ctx, "admin", "1")
+	if err != nil {
+		t.Fatalf("Impersonate error: %v", err)
+	}
+	claims, _ := service.tokenManager.VerifyToken(token)
+	if claims["sub"] != "1" {
+		t.Errorf("impersonation token sub = %v, want 1", claims["sub"])
+	}
+	if actor, ok := actorFromClaims(claims); !ok || actor.ID != "admin" || actor.Email != "admin@example.com" {
+		t.Errorf("act claim = %v", claims[ActorClaim])
+	}
+
+	auth := RequireAuth(service.tokenManager, blocklist)
+	router := NewRouter()
+	router.Use(auth, service.ImpersonationMiddleware())
+	router.Get("/me", func(w http.ResponseWriter, r *http.Request) {
+		user, _ := GetUser(r)
+		actorID := ""
+		if actor, ok := GetImpersonator(r); ok {
+			actorID = actor.ID
+		}
+		Json(w, http.StatusOK, map[string]string{"user": user.GetID(), "actor": actorID})
+	})
+	router.Post("/password", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}, DenyImpersonation())
+	router.Post("/impersonate/{id}", func(w http.ResponseWriter, r *http.Request) {
+		user, _ := GetUser(r)
+		if _, err := service.Impersonate(r.Context(), user.GetID(), GetParam(r, "id")); err != nil {
+			appErr, _ := AsAppError(err)
+			JsonAppError(w, appErr)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	router.Post("/impersonation/exit", func(w http.ResponseWriter, r *http.Request) {
+		access, _, err := service.ExitImpersonation(r.Context())
+		if err != nil {
+			appErr, _ := AsAppError(err)
+			JsonAppError(w, appErr)
+			return
+		}
+		w.Write([]byte(access))
+	})
+
+	request := func(method, path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := request(http.MethodGet, "/me"); rec.Code != http.StatusOK ||
+		!strings.Contains(rec.Body.String(), `"user":"1"`) || !strings.Contains(rec.Body.String(), `"actor":"admin"`) {
+		t.Errorf("GET /me = %d %s", rec.Code, rec.Body.String())
+	}
+	if rec := request(http.MethodPost, "/password"); rec.Code != http.StatusForbidden {
+		t.Errorf("POST /password during impersonation = %d, want 403", rec.Code)
+	}
+	if rec := request(http.MethodPost, "/impersonate/2"); rec.Code != http.StatusForbidden {
+		t.Errorf("nested impersonation = %d, want 403", rec.Code)
+	}
+
+	rec := request(http.MethodPost, "/impersonation/exit")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("exit = %d: %s", rec.Code, rec.Body.String())
+	}
+	adminClaims, err := service.tokenManager.VerifyToken(rec.Body.String())
+	if err != nil || adminClaims["sub"] != "admin" || adminClaims[ActorClaim] != nil {
+		t.Errorf("exit token claims = %v, %v", adminClaims, err)
+	}
+	if rec := request(http.MethodGet, "/me"); rec.Code != http.StatusUnauthorized {
+		t.Errorf("impersonation token after exit = %d, want 401", rec.Code)
+	}
+	if ttl := blocklist.ttls[claims["sid"].(string)]; ttl != 15*time.Minute {
+		t.Errorf("impersonation session blocklisted for %v, want the access token expiry", ttl)
+	}
+
+	var types []string
+	for _, event := range events {
+		if event.ActorID != "admin" || event.UserID != "1" {
+			t.Errorf("unexpected audit event: %+v", event)
+		}
+		types = append(types, event.Type)
+	}
+	want := []string{ImpersonationStarted, ImpersonationRequest, ImpersonationRequest, ImpersonationRequest, ImpersonationRequest, ImpersonationEnded}
+	if len(types) != len(want) {
+		t.Fatalf("audit events = %v, want %v", types, want)
+	}
+	for i := range want {
+		if types[i] != want[i] {
+			t.Errorf("audit events = %v, want %v", types, want)
+			break
+		}
+	}
+	if events[1].Method != http.MethodGet || events[1].Path != "/me" {
+		t.Errorf("request audit event = %+v", events[1])
+	}
+}
@@ -0,0 +1,316 @@
+package dim
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// IPReputation adalah hasil pemeriksaan reputasi sebuah IP.
+type IPReputation struct {
+	// Listed bernilai true jika IP dikenal berbahaya (ada di deny-list atau skor melewati threshold).
+	Listed bool `json:"listed"`
+	// Score adalah skor abuse 0-100 dari provider (0 jika provider tidak memberi skor).
+	Score int `json:"score,omitempty"`
+	// Source adalah nama provider yang menghasilkan verdict, misal "static", "redis", "abuseipdb".
+	Source string `json:"source,omitempty"`
+}
+
+// IPReputationProvider mendefinisikan sumber reputasi IP (file statis, Redis set, API eksternal).
+// Implementasi harus aman dipakai secara concurrent.
+type IPReputationProvider interface {
+	// Lookup memeriksa reputasi IP. Error berarti reputasi tidak dapat ditentukan.
+	Lookup(ctx context.Context, ip string) (IPReputation, error)
+}
+
+// IPReputationFunc mengadaptasi fungsi biasa menjadi IPReputationProvider.
+type IPReputationFunc func(ctx context.Context, ip string) (IPReputation, error)
+
+// Lookup memanggil f(ctx, ip).
+func (f IPReputationFunc) Lookup(ctx context.Context, ip string) (IPReputation, error) {
+	return f(ctx, ip)
+}
+
+// --- Static List ---
+
+// StaticIPList adalah deny-list IP dan CIDR di memory, dapat dimuat dari file dan di-reload.
+type StaticIPList struct {
+	mu       sync.RWMutex
+	prefixes []netip.Prefix
+}
+
+// NewStaticIPList membuat deny-list dari daftar IP atau CIDR.
+//
+// Example:
+//
+//	list, err := dim.NewStaticIPList("203.0.113.0/24", "198.51.100.7")
+func NewStaticIPList(entries ...string) (*StaticIPList, error) {
+	prefixes, err := parseIPPrefixes(entries)
+	if err != nil {
+		return nil, err
+	}
+	return &StaticIPList{prefixes: prefixes}, nil
+}
+
+// LoadStaticIPList membuat deny-list dari file berisi satu IP atau CIDR per baris.
+// Baris kosong dan komentar yang diawali "#" diabaikan.
+func LoadStaticIPList(path string) (*StaticIPList, error) {
+	list := &StaticIPList{}
+	if err := list.LoadFile(path); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// LoadFile mengganti isi deny-list dengan isi file, misal setelah feed diperbarui.
+// Jika file tidak valid, isi lama dipertahankan.
+func (l *StaticIPList) LoadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var entries []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		if line = strings.TrimSpace(line); line != "" {
+			entries = append(entries, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	prefixes, err := parseIPPrefixes(entries)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	l.mu.Lock()
+	l.prefixes = prefixes
+	l.mu.Unlock()
+	return nil
+}
+
+// Contains mengecek apakah IP termasuk dalam deny-list.
+func (l *StaticIPList) Contains(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	for _, p := range l.prefixes {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// Lookup mengimplementasikan IPReputationProvider.
+func (l *StaticIPList) Lookup(ctx context.Context, ip string) (IPReputation, error) {
+	if l.Contains(ip) {
+		return IPReputation{Listed: true, Score: 100, Source: "static"}, nil
+	}
+	return IPReputation{Source: "static"}, nil
+}
+
+// parseIPPrefixes mengonversi IP tunggal atau CIDR menjadi netip.Prefix.
+func parseIPPrefixes(entries []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(entries))
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if strings.Contains(entry, "/") {
+			p, err := netip.ParsePrefix(entry)
+			if err != nil {
+				return nil, fmt.Errorf("invalid CIDR %q: %w", entry, err)
+			}
+			prefixes = append(prefixes, p.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid IP %q: %w", entry, err)
+		}
+		addr = addr.Unmap()
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return prefixes, nil
+}
+
+// --- Redis Set ---
+
+// RedisSetClient adalah subset client Redis yang dibutuhkan RedisIPList.
+// dim tidak bergantung pada library Redis tertentu; gunakan RedisSetClientFunc sebagai adapter.
+type RedisSetClient interface {
+	SIsMember(ctx context.Context, key, member string) (bool, error)
+}
+
+// RedisSetClientFunc mengadaptasi fungsi menjadi RedisSetClient.
+//
+// Example (go-redis):
+//
+//	client := dim.RedisSetClientFunc(func(ctx context.Context, key, member string) (bool, error) {
+//	    return rdb.SIsMember(ctx, key, member).Result()
+//	})
+type RedisSetClientFunc func(ctx context.Context, key, member string) (bool, error)
+
+// SIsMember memanggil f(ctx, key, member).
+func (f RedisSetClientFunc) SIsMember(ctx context.Context, key, member string) (bool, error) {
+	return f(ctx, key, member)
+}
+
+// RedisIPList adalah deny-list yang disimpan sebagai Redis set berisi IP (bukan CIDR),
+// sehingga dapat dibagikan dan diperbarui oleh banyak instance atau proses lain.
+type RedisIPList struct {
+	client RedisSetClient
+	key    string
+}
+
+// NewRedisIPList membuat provider dari Redis set dengan key tertentu, misal "dim:ip:denylist".
+func NewRedisIPList(client RedisSetClient, key string) *RedisIPList {
+	return &RedisIPList{client: client, key: key}
+}
+
+// Lookup mengimplementasikan IPReputationProvider.
+func (l *RedisIPList) Lookup(ctx context.Context, ip string) (IPReputation, error) {
+	listed, err := l.client.SIsMember(ctx, l.key, ip)
+	if err != nil {
+		return IPReputation{}, fmt.Errorf("redis ip list lookup failed: %w", err)
+	}
+	if listed {
+		return IPReputation{Listed: true, Score: 100, Source: "redis"}, nil
+	}
+	return IPReputation{Source: "redis"}, nil
+}
+
+// --- AbuseIPDB ---
+
+// AbuseIPDBProvider memeriksa reputasi IP melalui API AbuseIPDB (endpoint /api/v2/check).
+// IP dianggap listed jika abuseConfidenceScore >= threshold.
+type AbuseIPDBProvider struct {
+	endpoint  string
+	apiKey    string
+	threshold int
+	maxAge    int
+	client    *http.Client
+}
+
+// NewAbuseIPDBProvider membuat provider AbuseIPDB dengan threshold 75, laporan 30 hari
+// terakhir, dan timeout 5 detik. Gunakan bersama cache IPReputationGuard agar kuota API hemat.
+func NewAbuseIPDBProvider(apiKey string) *AbuseIPDBProvider {
+	return &AbuseIPDBProvider{
+		endpoint:  "https://api.abuseipdb.com/api/v2/check",
+		apiKey:    apiKey,
+		threshold: 75,
+		maxAge:    30,
+		client:    &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// WithThreshold mengatur skor minimum (0-100) agar IP dianggap listed.
+func (p *AbuseIPDBProvider) WithThreshold(score int) *AbuseIPDBProvider {
+	p.threshold = score
+	return p
+}
+
+// WithMaxAge mengatur rentang hari laporan yang diperhitungkan (1-365).
+func (p *AbuseIPDBProvider) WithMaxAge(days int) *AbuseIPDBProvider {
+	p.maxAge = days
+	return p
+}
+
+// WithEndpoint mengganti URL endpoint check, misal untuk proxy internal.
+func (p *AbuseIPDBProvider) WithEndpoint(endpoint string) *AbuseIPDBProvider {
+	p.endpoint = endpoint
+	return p
+}
+
+// WithHTTPClient mengganti HTTP client yang dipakai untuk memanggil API.
+func (p *AbuseIPDBProvider) WithHTTPClient(client *http.Client) *AbuseIPDBProvider {
+	p.client = client
+	return p
+}
+
+// Lookup mengimplementasikan IPReputationProvider.
+func (p *AbuseIPDBProvider) Lookup(ctx context.Context, ip string) (IPReputation, error) {
+	query := url.Values{}
+	query.Set("ipAddress", ip)
+	query.Set("maxAgeInDays", fmt.Sprint(p.maxAge))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.endpoint+"?"+query.Encode(), nil)
+	if err != nil {
+		return IPReputation{}, err
+	}
+	req.Header.Set("Key", p.apiKey)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return IPReputation{}, fmt.Errorf("abuseipdb request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return IPReputation{}, fmt.Errorf("abuseipdb returned status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Data struct {
+			AbuseConfidenceScore int `json:"abuseConfidenceScore"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return IPReputation{}, fmt.Errorf("invalid abuseipdb response: %w", err)
+	}
+
+	score := result.Data.AbuseConfidenceScore
+	return IPReputation{Listed: score >= p.threshold, Score: score, Source: "abuseipdb"}, nil
+}
+
+// --- Chain ---
+
+// IPReputationChain memeriksa beberapa provider berurutan dan berhenti pada provider
+// pertama yang menandai IP sebagai listed. Provider yang error dilewati; error hanya
+// dikembalikan jika tidak ada provider yang berhasil.
+//
+// Example:
+//
+//	provider := dim.IPReputationChain(staticList, dim.NewRedisIPList(client, "ip:deny"), abuseIPDB)
+func IPReputationChain(providers ...IPReputationProvider) IPReputationProvider {
+	return IPReputationFunc(func(ctx context.Context, ip string) (IPReputation, error) {
+		var lastErr error
+		succeeded := false
+		for _, p := range providers {
+			rep, err := p.Lookup(ctx, ip)
+			if err != nil {
+				lastErr = err
+				continue
+			}
+			if rep.Listed {
+				return rep, nil
+			}
+			succeeded = true
+		}
+		if !succeeded && lastErr != nil {
+			return IPReputation{}, lastErr
+		}
+		return IPReputation{}, nil
+	})
+}
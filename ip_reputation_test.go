@@ -0,0 +1,147 @@
+package dim
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStaticIPList(t *testing.T) {
+	list, err := NewStaticIPList("203.0.113.0/24", "198.51.100.7", "2001:db8::/32")
+	if err != nil {
+		t.Fatalf("NewStaticIPList() error = %v", err)
+	}
+
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"203.0.113.42", true},
+		{"198.51.100.7", true},
+		{"198.51.100.8", false},
+		{"2001:db8::1", true},
+		{"::ffff:203.0.113.1", true},
+		{"not-an-ip", false},
+	}
+	for _, tt := range tests {
+		if got := list.Contains(tt.ip); got != tt.want {
+			t.Errorf("Contains(%q) = %v, want %v", tt.ip, got, tt.want)
+		}
+	}
+
+	if _, err := NewStaticIPList("300.1.1.1"); err == nil {
+		t.Error("expected error for invalid IP")
+	}
+	if _, err := NewStaticIPList("10.0.0.0/33"); err == nil {
+		t.Error("expected error for invalid CIDR")
+	}
+}
+
+func TestLoadStaticIPList(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "denylist.txt")
+	os.WriteFile(path, []byte("# feed 2024-01-01\n203.0.113.0/24\n\n198.51.100.7 # brute force\n"), 0o644)
+
+	list, err := LoadStaticIPList(path)
+	if err != nil {
+		t.Fatalf("LoadStaticIPList() error = %v", err)
+	}
+	rep, _ := list.Lookup(context.Background(), "198.51.100.7")
+	if !rep.Listed || rep.Source != "static" {
+		t.Errorf("Lookup() = %+v", rep)
+	}
+
+	// Invalid reload keeps the previous entries
+	os.WriteFile(path, []byte("garbage\n"), 0o644)
+	if err := list.LoadFile(path); err == nil {
+		t.Error("expected error for invalid file")
+	}
+	if !list.Contains("203.0.113.9") {
+		t.Error("previous entries should be kept after a failed reload")
+	}
+
+	os.WriteFile(path, []byte("192.0.2.1\n"), 0o644)
+	list.LoadFile(path)
+	if list.Contains("203.0.113.9") || !list.Contains("192.0.2.1") {
+		t.Error("reload should replace entries")
+	}
+}
+
+func TestRedisIPList(t *testing.T) {
+	set := map[string]bool{"203.0.113.7": true}
+	client := RedisSetClientFunc(func(ctx context.Context, key, member string) (bool, error) {
+		if key != "ip:deny" {
+			return false, errors.New("wrong key")
+		}
+		return set[member], nil
+	})
+
+	list := NewRedisIPList(client, "ip:deny")
+	if rep, err := list.Lookup(context.Background(), "203.0.113.7"); err != nil || !rep.Listed {
+		t.Errorf("Lookup(listed) = %+v, %v", rep, err)
+	}
+	if rep, err := list.Lookup(context.Background(), "192.0.2.1"); err != nil || rep.Listed {
+		t.Errorf("Lookup(clean) = %+v, %v", rep, err)
+	}
+	if _, err := NewRedisIPList(client, "other").Lookup(context.Background(), "192.0.2.1"); err == nil {
+		t.Error("expected client error")
+	}
+}
+
+func TestAbuseIPDBProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Key") != "api-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		score := "10"
+		if r.URL.Query().Get("ipAddress") == "203.0.113.7" {
+			score = "90"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"ipAddress":"` + r.URL.Query().Get("ipAddress") + `","abuseConfidenceScore":` + score + `}}`))
+	}))
+	defer server.Close()
+
+	p := NewAbuseIPDBProvider("api-key").WithEndpoint(server.URL)
+	ctx := context.Background()
+
+	rep, err := p.Lookup(ctx, "203.0.113.7")
+	if err != nil || !rep.Listed || rep.Score != 90 || rep.Source != "abuseipdb" {
+		t.Errorf("Lookup(bad) = %+v, %v", rep, err)
+	}
+	rep, _ = p.Lookup(ctx, "192.0.2.1")
+	if rep.Listed || rep.Score != 10 {
+		t.Errorf("Lookup(clean) = %+v", rep)
+	}
+	rep, _ = p.WithThreshold(5).Lookup(ctx, "192.0.2.1")
+	if !rep.Listed {
+		t.Error("score above custom threshold should be listed")
+	}
+
+	if _, err := NewAbuseIPDBProvider("wrong").WithEndpoint(server.URL).Lookup(ctx, "192.0.2.1"); err == nil {
+		t.Error("expected error for non-200 response")
+	}
+}
+
+func TestIPReputationChain(t *testing.T) {
+	static, _ := NewStaticIPList("203.0.113.0/24")
+	failing := IPReputationFunc(func(ctx context.Context, ip string) (IPReputation, error) {
+		return IPReputation{}, errors.New("unavailable")
+	})
+	ctx := context.Background()
+
+	rep, err := IPReputationChain(failing, static).Lookup(ctx, "203.0.113.5")
+	if err != nil || !rep.Listed || rep.Source != "static" {
+		t.Errorf("listed = %+v, %v", rep, err)
+	}
+	if rep, err := IPReputationChain(failing, static).Lookup(ctx, "192.0.2.1"); err != nil || rep.Listed {
+		t.Errorf("clean = %+v, %v", rep, err)
+	}
+	if _, err := IPReputationChain(failing).Lookup(ctx, "192.0.2.1"); err == nil {
+		t.Error("expected error when every provider fails")
+	}
+}
@@ -0,0 +1,404 @@
+package dim
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CursorMeta contains cursor pagination information
+type CursorMeta struct {
+	PerPage    int    `json:"per_page"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+}
+
+// CursorPaginationResponse is the response structure for cursor-paginated data
+type CursorPaginationResponse struct {
+	Data interface{} `json:"data"`
+	Meta CursorMeta  `json:"meta"`
+}
+
+// CursorPage holds the parsed cursor pagination request
+type CursorPage struct {
+	Limit int
+	// After holds the key values of the last item seen (forward pagination), nil if absent.
+	After []interface{}
+	// Before holds the key values of the first item seen (backward pagination), nil if absent.
+	Before []interface{}
+}
+
+// IsBackward reports whether the page was requested with a before cursor.
+func (p *CursorPage) IsBackward() bool {
+	return p.Before != nil
+}
+
+// FetchLimit returns the LIMIT to use in the query: one extra row is fetched
+// to detect whether another page exists.
+func (p *CursorPage) FetchLimit() int {
+	return p.Limit + 1
+}
+
+// CursorPaginator implements keyset pagination over an ordered set of columns.
+// Cursors are opaque base64url strings holding the key values of a boundary row,
+// optionally signed with a PayloadSigner so clients cannot forge them.
+//
+// The last key must be unique (e.g. the primary key) and all key columns must be NOT NULL,
+// otherwise rows can be skipped or repeated between pages.
+type CursorPaginator struct {
+	Keys         []SortField
+	DefaultLimit int
+	MaxLimit     int
+
+	signer *PayloadSigner
+}
+
+// NewCursorPaginator creates a new CursorPaginator.
+//
+// Parameters:
+//   - keys: SQL columns and directions defining the order, the last one must be unique
+//   - defaultLimit: page size when the request has none (default 10)
+//   - maxLimit: larger page sizes are clamped to this value (default 100)
+//
+// Example:
+//
+//	paginator := dim.NewCursorPaginator([]dim.SortField{
+//	    {Field: "created_at", Direction: dim.SortDesc},
+//	    {Field: "id", Direction: dim.SortDesc},
+//	}, 20, 100)
+func NewCursorPaginator(keys []SortField, defaultLimit, maxLimit int) *CursorPaginator {
+	if defaultLimit <= 0 {
+		defaultLimit = 10
+	}
+	if maxLimit <= 0 {
+		maxLimit = 100
+	}
+	return &CursorPaginator{
+		Keys:         keys,
+		DefaultLimit: defaultLimit,
+		MaxLimit:     maxLimit,
+	}
+}
+
+// WithSigner signs cursors with HMAC so tampered cursors are rejected by Parse.
+func (p *CursorPaginator) WithSigner(signer *PayloadSigner) *CursorPaginator {
+	p.signer = signer
+	return p
+}
+
+// Parse parses page[after], page[before] and page[size]
+// Also supports after, before and limit/size/per_page query params.
+func (p *CursorPaginator) Parse(r *http.Request) (*CursorPage, error) {
+	q := r.URL.Query()
+
+	afterStr := firstQueryValue(q.Get, "page[after]", "after")
+	beforeStr := firstQueryValue(q.Get, "page[before]", "before")
+	limitStr := firstQueryValue(q.Get, "page[size]", "limit", "size", "per_page")
+
+	if afterStr != "" && beforeStr != "" {
+		return nil, NewAppError("Only one of after or before cursor may be specified", http.StatusBadRequest)
+	}
+
+	page := &CursorPage{Limit: p.DefaultLimit}
+
+	if limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 {
+			return nil, NewAppError("Page size must be a positive integer", http.StatusBadRequest)
+		}
+		page.Limit = limit
+	}
+	if page.Limit > p.MaxLimit {
+		page.Limit = p.MaxLimit
+	}
+
+	var err error
+	if afterStr != "" {
+		if page.After, err = p.Decode(afterStr); err != nil {
+			return nil, NewAppError("Invalid pagination cursor", http.StatusBadRequest)
+		}
+	}
+	if beforeStr != "" {
+		if page.Before, err = p.Decode(beforeStr); err != nil {
+			return nil, NewAppError("Invalid pagination cursor", http.StatusBadRequest)
+		}
+	}
+
+	return page, nil
+}
+
+// Where builds the keyset condition for the page, using placeholders starting at argOffset+1.
+// Placeholders are never reused, so the query works with Rebind on SQLite.
+// Returns an empty string when the page has no cursor.
+//
+// For keys (created_at DESC, id DESC) and an after cursor the condition is:
+//
+//	(created_at < $1) OR (created_at = $2 AND id < $3)
+func (p *CursorPaginator) Where(page *CursorPage, argOffset int) (string, []interface{}) {
+	values := page.After
+	backward := false
+	if page.Before != nil {
+		values = page.Before
+		backward = true
+	}
+	if values == nil {
+		return "", nil
+	}
+
+	var args []interface{}
+	placeholder := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", argOffset+len(args))
+	}
+
+	clauses := make([]string, 0, len(p.Keys))
+	for i, key := range p.Keys {
+		parts := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			parts = append(parts, p.Keys[j].Field+" = "+placeholder(values[j]))
+		}
+
+		op := ">"
+		if (key.Direction == SortDesc) != backward {
+			op = "<"
+		}
+		parts = append(parts, key.Field+" "+op+" "+placeholder(values[i]))
+		clauses = append(clauses, "("+strings.Join(parts, " AND ")+")")
+	}
+
+	return strings.Join(clauses, " OR "), args
+}
+
+// OrderBy builds the ORDER BY fragment (without the ORDER BY keyword) for the page.
+// Directions are reversed for backward pages; PaginateCursor restores the original order.
+func (p *CursorPaginator) OrderBy(page *CursorPage) string {
+	parts := make([]string, len(p.Keys))
+	for i, key := range p.Keys {
+		desc := key.Direction == SortDesc
+		if page.IsBackward() {
+			desc = !desc
+		}
+		direction := SortAsc
+		if desc {
+			direction = SortDesc
+		}
+		parts[i] = key.Field + " " + direction
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Encode encodes key values into an opaque cursor.
+// Supported values: integers, floats, strings, bools, time.Time and fmt.Stringer (e.g. UUID).
+func (p *CursorPaginator) Encode(values []interface{}) (string, error) {
+	if len(values) != len(p.Keys) {
+		return "", fmt.Errorf("cursor has %d values, expected %d", len(values), len(p.Keys))
+	}
+
+	encoded := make([]string, len(values))
+	for i, v := range values {
+		s, err := encodeCursorValue(v)
+		if err != nil {
+			return "", fmt.Errorf("cursor key %s: %w", p.Keys[i].Field, err)
+		}
+		encoded[i] = s
+	}
+
+	data, err := json.Marshal(encoded)
+	if err != nil {
+		return "", err
+	}
+	cursor := base64.RawURLEncoding.EncodeToString(data)
+	if p.signer != nil {
+		cursor += "." + p.signer.Sign(cursor, time.Time{})
+	}
+	return cursor, nil
+}
+
+// Decode decodes a cursor produced by Encode, verifying its signature when a signer is set.
+func (p *CursorPaginator) Decode(cursor string) ([]interface{}, error) {
+	if p.signer != nil {
+		payload, signature, ok := strings.Cut(cursor, ".")
+		if !ok {
+			return nil, ErrSignatureInvalid
+		}
+		if err := p.signer.Verify(payload, time.Time{}, signature); err != nil {
+			return nil, err
+		}
+		cursor = payload
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor encoding: %w", err)
+	}
+
+	var encoded []string
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return nil, fmt.Errorf("invalid cursor payload: %w", err)
+	}
+	if len(encoded) != len(p.Keys) {
+		return nil, fmt.Errorf("cursor has %d values, expected %d", len(encoded), len(p.Keys))
+	}
+
+	values := make([]interface{}, len(encoded))
+	for i, s := range encoded {
+		v, err := decodeCursorValue(s)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+// PaginateCursor trims the extra row fetched with FetchLimit, restores the order of
+// backward pages, and builds next/prev cursors from the boundary rows.
+//
+// Parameters:
+//   - p: paginator used to build the query
+//   - page: page returned by p.Parse
+//   - rows: rows fetched with p.Where, p.OrderBy and page.FetchLimit()
+//   - key: returns the key values of a row, in the same order as p.Keys
+//
+// Returns:
+//   - []T: rows of the current page in display order
+//   - CursorMeta: next/prev cursors for JsonCursorPagination
+//   - error: error if a key value cannot be encoded
+//
+// Example:
+//
+//	items, meta, err := dim.PaginateCursor(paginator, page, posts, func(p Post) []interface{} {
+//	    return []interface{}{p.CreatedAt, p.ID}
+//	})
+//	dim.JsonCursorPagination(w, http.StatusOK, items, meta)
+func PaginateCursor[T any](p *CursorPaginator, page *CursorPage, rows []T, key func(T) []interface{}) ([]T, CursorMeta, error) {
+	meta := CursorMeta{PerPage: page.Limit}
+
+	hasMore := len(rows) > page.Limit
+	if hasMore {
+		rows = rows[:page.Limit]
+	}
+
+	if page.IsBackward() {
+		for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+			rows[i], rows[j] = rows[j], rows[i]
+		}
+	}
+
+	if len(rows) == 0 {
+		return rows, meta, nil
+	}
+
+	// Forward: more rows after the last item; a cursor means rows exist before the first item.
+	// Backward: the opposite, and the before cursor guarantees rows after the last item.
+	hasNext, hasPrev := hasMore, page.After != nil
+	if page.IsBackward() {
+		hasNext, hasPrev = true, hasMore
+	}
+	meta.HasMore = hasNext
+
+	var err error
+	if hasNext {
+		if meta.NextCursor, err = p.Encode(key(rows[len(rows)-1])); err != nil {
+			return nil, CursorMeta{}, err
+		}
+	}
+	if hasPrev {
+		if meta.PrevCursor, err = p.Encode(key(rows[0])); err != nil {
+			return nil, CursorMeta{}, err
+		}
+	}
+
+	return rows, meta, nil
+}
+
+// JsonCursorPagination menulis cursor-paginated JSON response dengan data dan cursor metadata.
+// Response format: {"data": [...], "meta": {"per_page": 20, "next_cursor": "...", "prev_cursor": "...", "has_more": true}}
+// Content-Type header otomatis di-set ke "application/json".
+//
+// Parameters:
+//   - w: http.ResponseWriter untuk menulis response
+//   - status: HTTP status code
+//   - data: data array/slice halaman saat ini
+//   - meta: CursorMeta dari PaginateCursor
+//   - opts: (opsional) override ResponseConfig global untuk response ini
+//
+// Returns:
+//   - error: error jika encoding JSON gagal
+func JsonCursorPagination(w http.ResponseWriter, status int, data interface{}, meta CursorMeta, opts ...ResponseOption) error {
+	response := CursorPaginationResponse{
+		Data: data,
+		Meta: meta,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	return encodeJSON(w, response, resolveResponseConfig(opts))
+}
+
+// firstQueryValue returns the first non-empty value among the given query parameter names.
+func firstQueryValue(get func(string) string, names ...string) string {
+	for _, name := range names {
+		if v := get(name); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// encodeCursorValue encodes a key value as a type-tagged string so it decodes
+// back to a value the database driver compares correctly.
+func encodeCursorValue(v interface{}) (string, error) {
+	switch val := v.(type) {
+	case nil:
+		return "", fmt.Errorf("nil value is not supported")
+	case time.Time:
+		return "t:" + val.Format(time.RFC3339Nano), nil
+	case fmt.Stringer:
+		return "s:" + val.String(), nil
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "i:" + strconv.FormatInt(rv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "i:" + strconv.FormatUint(rv.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return "f:" + strconv.FormatFloat(rv.Float(), 'g', -1, 64), nil
+	case reflect.String:
+		return "s:" + rv.String(), nil
+	case reflect.Bool:
+		return "b:" + strconv.FormatBool(rv.Bool()), nil
+	}
+	return "", fmt.Errorf("unsupported type %T", v)
+}
+
+// decodeCursorValue decodes a value produced by encodeCursorValue.
+func decodeCursorValue(s string) (interface{}, error) {
+	tag, raw, ok := strings.Cut(s, ":")
+	if !ok {
+		return nil, fmt.Errorf("invalid cursor value %q", s)
+	}
+
+	switch tag {
+	case "t":
+		return time.Parse(time.RFC3339Nano, raw)
+	case "s":
+		return raw, nil
+	case "i":
+		return strconv.ParseInt(raw, 10, 64)
+	case "f":
+		return strconv.ParseFloat(raw, 64)
+	case "b":
+		return strconv.ParseBool(raw)
+	}
+	return nil, fmt.Errorf("invalid cursor value %q", s)
+}
@@ -0,0 +1,266 @@
+package dim
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCursorPaginator_EncodeDecode(t *testing.T) {
+	p := NewCursorPaginator([]SortField{
+		{Field: "created_at", Direction: SortDesc},
+		{Field: "score", Direction: SortAsc},
+		{Field: "id", Direction: SortAsc},
+	}, 10, 50)
+
+	created := time.Date(2024, 5, 1, 10, 30, 0, 123456789, time.UTC)
+	cursor, err := p.Encode([]interface{}{created, 9.5, NewV4()})
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	if strings.ContainsAny(cursor, "+/=") {
+		t.Errorf("cursor %q is not URL safe", cursor)
+	}
+
+	values, err := p.Decode(cursor)
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if got, ok := values[0].(time.Time); !ok || !got.Equal(created) {
+		t.Errorf("created_at = %v", values[0])
+	}
+	if values[1] != 9.5 {
+		t.Errorf("score = %v", values[1])
+	}
+	if _, ok := values[2].(string); !ok {
+		t.Errorf("id = %T, want string", values[2])
+	}
+
+	if _, err := p.Encode([]interface{}{created, 1}); err == nil {
+		t.Error("expected error for wrong number of values")
+	}
+	if _, err := p.Encode([]interface{}{nil, 1, 2}); err == nil {
+		t.Error("expected error for nil value")
+	}
+	if _, err := p.Decode("not-base64!"); err == nil {
+		t.Error("expected error for invalid cursor")
+	}
+}
+
+func TestCursorPaginator_Signed(t *testing.T) {
+	keys := []SortField{{Field: "id", Direction: SortAsc}}
+	p := NewCursorPaginator(keys, 10, 50).WithSigner(NewPayloadSigner("secret"))
+
+	cursor, _ := p.Encode([]interface{}{42})
+	values, err := p.Decode(cursor)
+	if err != nil || values[0] != int64(42) {
+		t.Fatalf("Decode() = %v, %v", values, err)
+	}
+
+	// A cursor forged without the signature must be rejected
+	forged, _ := NewCursorPaginator(keys, 10, 50).Encode([]interface{}{1})
+	if _, err := p.Decode(forged); err == nil {
+		t.Error("expected error for unsigned cursor")
+	}
+	_, signature, _ := strings.Cut(cursor, ".")
+	if _, err := p.Decode(forged + "." + signature); err == nil {
+		t.Error("expected error for signature from another cursor")
+	}
+}
+
+func TestCursorPaginator_Parse(t *testing.T) {
+	p := NewCursorPaginator([]SortField{{Field: "id", Direction: SortAsc}}, 10, 50)
+	cursor, _ := p.Encode([]interface{}{7})
+
+	tests := []struct {
+		name        string
+		query       string
+		limit       int
+		after       []interface{}
+		before      []interface{}
+		expectError bool
+	}{
+		{name: "defaults", query: "", limit: 10},
+		{name: "JSON:API style", query: "page[after]=" + cursor + "&page[size]=20", limit: 20, after: []interface{}{int64(7)}},
+		{name: "simple style", query: "before=" + cursor + "&per_page=5", limit: 5, before: []interface{}{int64(7)}},
+		{name: "clamped", query: "limit=1000", limit: 50},
+		{name: "both cursors", query: "after=" + cursor + "&before=" + cursor, expectError: true},
+		{name: "invalid cursor", query: "after=abc", expectError: true},
+		{name: "invalid size", query: "page[size]=0", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := p.Parse(httptest.NewRequest(http.MethodGet, "/?"+tt.query, nil))
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				} else if appErr, ok := AsAppError(err); !ok || appErr.StatusCode != http.StatusBadRequest {
+					t.Errorf("error = %v, want 400 AppError", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if page.Limit != tt.limit || !reflect.DeepEqual(page.After, tt.after) || !reflect.DeepEqual(page.Before, tt.before) {
+				t.Errorf("page = %+v", page)
+			}
+		})
+	}
+}
+
+func TestCursorPaginator_WhereAndOrderBy(t *testing.T) {
+	p := NewCursorPaginator([]SortField{
+		{Field: "p.created_at", Direction: SortDesc},
+		{Field: "p.id", Direction: SortAsc},
+	}, 10, 50)
+
+	where, args := p.Where(&CursorPage{Limit: 10}, 0)
+	if where != "" || args != nil {
+		t.Errorf("first page: where = %q, args = %v", where, args)
+	}
+
+	where, args = p.Where(&CursorPage{Limit: 10, After: []interface{}{"2024-01-01", int64(5)}}, 1)
+	if where != "(p.created_at < $2) OR (p.created_at = $3 AND p.id > $4)" {
+		t.Errorf("after: where = %q", where)
+	}
+	if !reflect.DeepEqual(args, []interface{}{"2024-01-01", "2024-01-01", int64(5)}) {
+		t.Errorf("after: args = %v", args)
+	}
+
+	backward := &CursorPage{Limit: 10, Before: []interface{}{"2024-01-01", int64(5)}}
+	where, _ = p.Where(backward, 0)
+	if where != "(p.created_at > $1) OR (p.created_at = $2 AND p.id < $3)" {
+		t.Errorf("before: where = %q", where)
+	}
+
+	if got := p.OrderBy(&CursorPage{}); got != "p.created_at DESC, p.id ASC" {
+		t.Errorf("OrderBy() = %q", got)
+	}
+	if got := p.OrderBy(backward); got != "p.created_at ASC, p.id DESC" {
+		t.Errorf("OrderBy(backward) = %q", got)
+	}
+}
+
+type cursorTestPost struct {
+	ID    int64
+	Score int64
+}
+
+func TestPaginateCursor_SQLite(t *testing.T) {
+	db, err := NewSQLiteDatabase(DatabaseConfig{Database: ":memory:"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	ctx := context.Background()
+
+	if err := db.Exec(ctx, "CREATE TABLE posts (id INTEGER PRIMARY KEY, score INTEGER NOT NULL)"); err != nil {
+		t.Fatal(err)
+	}
+	// Duplicate scores make the id tiebreaker matter
+	scores := []int64{5, 3, 5, 1, 3, 5, 2}
+	for i, score := range scores {
+		if err := db.Exec(ctx, db.Rebind("INSERT INTO posts (id, score) VALUES ($1, $2)"), i+1, score); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	p := NewCursorPaginator([]SortField{
+		{Field: "score", Direction: SortDesc},
+		{Field: "id", Direction: SortAsc},
+	}, 3, 10)
+	key := func(post cursorTestPost) []interface{} { return []interface{}{post.Score, post.ID} }
+
+	fetch := func(query string) ([]int64, CursorMeta) {
+		t.Helper()
+		page, err := p.Parse(httptest.NewRequest(http.MethodGet, "/posts?"+query, nil))
+		if err != nil {
+			t.Fatalf("Parse(%q) error = %v", query, err)
+		}
+
+		sql := "SELECT id, score FROM posts"
+		where, args := p.Where(page, 0)
+		if where != "" {
+			sql += " WHERE " + where
+		}
+		sql += fmt.Sprintf(" ORDER BY %s LIMIT $%d", p.OrderBy(page), len(args)+1)
+		rows, err := db.Query(ctx, db.Rebind(sql), append(args, page.FetchLimit())...)
+		if err != nil {
+			t.Fatalf("query %q: %v", sql, err)
+		}
+		defer rows.Close()
+
+		var posts []cursorTestPost
+		for rows.Next() {
+			var post cursorTestPost
+			rows.Scan(&post.ID, &post.Score)
+			posts = append(posts, post)
+		}
+
+		items, meta, err := PaginateCursor(p, page, posts, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids := make([]int64, len(items))
+		for i, item := range items {
+			ids[i] = item.ID
+		}
+		return ids, meta
+	}
+
+	// score DESC, id ASC: (1,5) (3,5) (6,5) (2,3) (5,3) (7,2) (4,1)
+	ids, meta := fetch("")
+	if !reflect.DeepEqual(ids, []int64{1, 3, 6}) || !meta.HasMore || meta.PrevCursor != "" {
+		t.Fatalf("page 1 = %v, meta = %+v", ids, meta)
+	}
+
+	ids, meta = fetch("page[after]=" + url.QueryEscape(meta.NextCursor))
+	if !reflect.DeepEqual(ids, []int64{2, 5, 7}) || !meta.HasMore || meta.PrevCursor == "" {
+		t.Fatalf("page 2 = %v, meta = %+v", ids, meta)
+	}
+	page2Prev := meta.PrevCursor
+
+	ids, meta = fetch("page[after]=" + url.QueryEscape(meta.NextCursor))
+	if !reflect.DeepEqual(ids, []int64{4}) || meta.HasMore || meta.NextCursor != "" {
+		t.Fatalf("page 3 = %v, meta = %+v", ids, meta)
+	}
+
+	// Walking back from page 3 returns page 2, then page 1 without a prev cursor
+	ids, meta = fetch("page[before]=" + url.QueryEscape(meta.PrevCursor))
+	if !reflect.DeepEqual(ids, []int64{2, 5, 7}) || meta.PrevCursor == "" || meta.NextCursor == "" {
+		t.Fatalf("back to page 2 = %v, meta = %+v", ids, meta)
+	}
+
+	ids, meta = fetch("page[before]=" + url.QueryEscape(page2Prev))
+	if !reflect.DeepEqual(ids, []int64{1, 3, 6}) || meta.PrevCursor != "" || !meta.HasMore {
+		t.Fatalf("back to page 1 = %v, meta = %+v", ids, meta)
+	}
+}
+
+func TestJsonCursorPagination(t *testing.T) {
+	w := httptest.NewRecorder()
+	JsonCursorPagination(w, http.StatusOK, []int{1, 2}, CursorMeta{PerPage: 2, NextCursor: "abc", HasMore: true})
+
+	if w.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("Content-Type = %q", w.Header().Get("Content-Type"))
+	}
+
+	var body map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &body)
+	meta := body["meta"].(map[string]interface{})
+	if meta["next_cursor"] != "abc" || meta["has_more"] != true {
+		t.Errorf("meta = %v", meta)
+	}
+	if _, ok := meta["prev_cursor"]; ok {
+		t.Error("empty prev_cursor should be omitted")
+	}
+}
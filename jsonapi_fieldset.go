@@ -0,0 +1,134 @@
+package dim
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// Fieldsets holds the sparse fieldsets requested via ?fields[type]=a,b
+type Fieldsets map[string][]string
+
+// Fields returns the requested fields for a resource type.
+// The second return value is false when no fieldset was requested, meaning all fields.
+func (f Fieldsets) Fields(resourceType string) ([]string, bool) {
+	fields, ok := f[resourceType]
+	return fields, ok
+}
+
+// Has reports whether a field of a resource type should be serialized.
+// Always true when no fieldset was requested for the type.
+func (f Fieldsets) Has(resourceType, field string) bool {
+	fields, ok := f[resourceType]
+	return !ok || slices.Contains(fields, field)
+}
+
+// Columns maps the requested fields of a resource type to SQL columns, for a SELECT list.
+// Columns listed in always (e.g. the primary key) are added first. When no fieldset was
+// requested, all mapped columns are returned in the order of fields.
+//
+// Example:
+//
+//	cols := fieldsets.Columns("users", userFields, userColumns, "u.id")
+//	query := "SELECT " + strings.Join(cols, ", ") + " FROM users u"
+func (f Fieldsets) Columns(resourceType string, fields []string, columns map[string]string, always ...string) []string {
+	requested, ok := f[resourceType]
+	if !ok {
+		requested = fields
+	}
+
+	result := append([]string{}, always...)
+	for _, field := range requested {
+		if column, ok := columns[field]; ok && !slices.Contains(result, column) {
+			result = append(result, column)
+		}
+	}
+	return result
+}
+
+// Filter removes attributes that were not requested for a resource type.
+// The input map is returned unchanged when no fieldset was requested.
+func (f Fieldsets) Filter(resourceType string, attributes map[string]interface{}) map[string]interface{} {
+	fields, ok := f[resourceType]
+	if !ok {
+		return attributes
+	}
+	filtered := make(map[string]interface{}, len(fields))
+	for _, field := range fields {
+		if v, ok := attributes[field]; ok {
+			filtered[field] = v
+		}
+	}
+	return filtered
+}
+
+// FieldsetParser parses JSON:API sparse fieldset parameters
+type FieldsetParser struct {
+	// AllowedFields maps each resource type to its declared attributes and relationships
+	AllowedFields map[string]map[string]bool
+}
+
+// NewFieldsetParser creates a new FieldsetParser with the declared fields per resource type.
+//
+// Example:
+//
+//	parser := dim.NewFieldsetParser(map[string][]string{
+//	    "users": {"name", "email", "created_at"},
+//	    "posts": {"title", "body", "author"},
+//	})
+//	fieldsets, err := parser.Parse(r) // ?fields[users]=name,email
+func NewFieldsetParser(types map[string][]string) *FieldsetParser {
+	allowed := make(map[string]map[string]bool, len(types))
+	for resourceType, fields := range types {
+		set := make(map[string]bool, len(fields))
+		for _, field := range fields {
+			set[field] = true
+		}
+		allowed[resourceType] = set
+	}
+	return &FieldsetParser{AllowedFields: allowed}
+}
+
+// Parse parses the "fields[type]" query parameters
+// Format: ?fields[users]=name,email&fields[posts]=title
+// An empty value (?fields[users]=) requests no attributes for the type.
+func (p *FieldsetParser) Parse(r *http.Request) (Fieldsets, error) {
+	result := make(Fieldsets)
+
+	for key, values := range r.URL.Query() {
+		resourceType, ok := strings.CutPrefix(key, "fields[")
+		if !ok {
+			continue
+		}
+		resourceType, ok = strings.CutSuffix(resourceType, "]")
+		if !ok || resourceType == "" {
+			return nil, NewAppError(fmt.Sprintf("Fields parameter '%s' is invalid", key), http.StatusBadRequest)
+		}
+
+		allowed, known := p.AllowedFields[resourceType]
+		if !known {
+			return nil, NewAppError(fmt.Sprintf("Resource type '%s' is not allowed in fields", resourceType), http.StatusBadRequest)
+		}
+		if len(values) > 1 {
+			return nil, NewAppError(fmt.Sprintf("Fields for type '%s' are specified more than once", resourceType), http.StatusBadRequest)
+		}
+
+		fields := []string{}
+		for _, field := range strings.Split(values[0], ",") {
+			field = strings.TrimSpace(field)
+			if field == "" {
+				continue
+			}
+			if !allowed[field] {
+				return nil, NewAppError(fmt.Sprintf("Field '%s' is not allowed for type '%s'", field, resourceType), http.StatusBadRequest)
+			}
+			if !slices.Contains(fields, field) {
+				fields = append(fields, field)
+			}
+		}
+		result[resourceType] = fields
+	}
+
+	return result, nil
+}
@@ -0,0 +1,123 @@
+package dim
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestFieldsetParser_Parse(t *testing.T) {
+	declared := map[string][]string{
+		"users": {"name", "email", "created_at"},
+		"posts": {"title", "body", "author"},
+	}
+
+	tests := []struct {
+		name        string
+		queryString string
+		expected    Fieldsets
+		expectError bool
+	}{
+		{
+			name:        "No fields param",
+			queryString: "?sort=name",
+			expected:    Fieldsets{},
+		},
+		{
+			name:        "Single type",
+			queryString: "?fields[users]=name,email",
+			expected:    Fieldsets{"users": {"name", "email"}},
+		},
+		{
+			name:        "Multiple types",
+			queryString: "?fields[users]=name&fields[posts]=title,author",
+			expected:    Fieldsets{"users": {"name"}, "posts": {"title", "author"}},
+		},
+		{
+			name:        "Duplicates and spaces",
+			queryString: "?fields[users]=name,%20name,,email",
+			expected:    Fieldsets{"users": {"name", "email"}},
+		},
+		{
+			name:        "Empty value requests no fields",
+			queryString: "?fields[users]=",
+			expected:    Fieldsets{"users": {}},
+		},
+		{
+			name:        "Unknown type",
+			queryString: "?fields[secrets]=value",
+			expectError: true,
+		},
+		{
+			name:        "Unknown field",
+			queryString: "?fields[users]=password",
+			expectError: true,
+		},
+		{
+			name:        "Malformed key",
+			queryString: "?fields[users=name",
+			expectError: true,
+		},
+		{
+			name:        "Repeated type",
+			queryString: "?fields[users]=name&fields[users]=email",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := NewFieldsetParser(declared)
+			req := httptest.NewRequest("GET", "/"+tt.queryString, nil)
+
+			fieldsets, err := parser.Parse(req)
+			if (err != nil) != tt.expectError {
+				t.Fatalf("Parse() error = %v, expectError %v", err, tt.expectError)
+			}
+			if tt.expectError {
+				if appErr, ok := AsAppError(err); !ok || appErr.StatusCode != 400 {
+					t.Errorf("error = %v, want 400 AppError", err)
+				}
+				return
+			}
+			if !reflect.DeepEqual(fieldsets, tt.expected) {
+				t.Errorf("Parse() = %v, want %v", fieldsets, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFieldsets_Helpers(t *testing.T) {
+	fieldsets := Fieldsets{"users": {"email", "name"}}
+
+	if !fieldsets.Has("users", "name") || fieldsets.Has("users", "created_at") {
+		t.Error("Has() should follow the requested fieldset")
+	}
+	if !fieldsets.Has("posts", "title") {
+		t.Error("Has() should be true for types without a fieldset")
+	}
+	if _, ok := fieldsets.Fields("posts"); ok {
+		t.Error("Fields(posts) should report no fieldset")
+	}
+
+	attrs := map[string]interface{}{"name": "Budi", "email": "budi@example.com", "created_at": "2024-01-01"}
+	filtered := fieldsets.Filter("users", attrs)
+	if len(filtered) != 2 || filtered["created_at"] != nil {
+		t.Errorf("Filter() = %v", filtered)
+	}
+	if len(fieldsets.Filter("posts", attrs)) != 3 {
+		t.Error("Filter() should return all attributes for types without a fieldset")
+	}
+
+	all := []string{"name", "email", "created_at"}
+	columns := map[string]string{"name": "u.name", "email": "u.email", "created_at": "u.created_at"}
+
+	got := fieldsets.Columns("users", all, columns, "u.id")
+	if !reflect.DeepEqual(got, []string{"u.id", "u.email", "u.name"}) {
+		t.Errorf("Columns() = %v", got)
+	}
+	got = Fieldsets{}.Columns("users", all, columns, "u.id")
+	if !reflect.DeepEqual(got, []string{"u.id", "u.name", "u.email", "u.created_at"}) {
+		t.Errorf("Columns() without fieldset = %v", got)
+	}
+}
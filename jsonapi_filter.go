@@ -19,9 +19,9 @@ import (
 //   ?filters[fieldName]=value&filters[fieldName2]=val1,val2
 //
 // Supported types:
-//   - Basic: *string, *int, *int64, *bool, *UUID
-//   - Slices: []string, []int, []int64, []float64, []UUID
-//   - Ranges: DateRange, AmountRange, IntRange, TimestampRange (both pointer and non-pointer)
+//   - Basic: *string, *int, *int64, *bool, *UUID, time.Time, *time.Time
+//   - Slices: []string, []int, []int64, []float64, []UUID, []time.Time
+//   - Ranges: DateRange, AmountRange, IntRange, TimestampRange, TimeRange (both pointer and non-pointer)
 //
 // Core Features:
 //   - Flexible query parameter parsing with type conversion
@@ -30,6 +30,8 @@ import (
 //   - Custom timezone support for timestamp parsing
 //   - Generic range parser helper reducing code duplication
 //   - Type caching using goreus for performance optimization
+//   - Compiled parse plan per struct type (tags, constraints, parsers resolved once)
+//   - Generic ParseInto[T] / ParseWith[T] returning the parsed struct and errors
 //   - Extensible constraint validation system (ConstraintValidator interface)
 //   - Comprehensive error reporting with field-specific error keys
 //
@@ -59,6 +61,7 @@ import (
 //       // Handle errors: fp.Errors() returns map[string]string
 //       // Key format: "filters[fieldName]"
 //   }
+//   conditions := fp.Conditions() // typed FilterCondition list, including operator filters
 
 // Global Type Cache - Performance Optimization
 // Uses goreus in-memory cache for efficient type comparison caching.
@@ -109,19 +112,24 @@ func typeMatches(t1, t2 reflect.Type) bool {
 // Generic type supporting any comparable type for flexible range queries.
 //
 // Fields:
-//   - From: Start value of range
-//   - To: End value of range
+//   - From: Start value of range (zero value if HasFrom is false)
+//   - To: End value of range (zero value if HasTo is false)
+//   - HasFrom: true if the lower bound was given
+//   - HasTo: true if the upper bound was given
 //   - Valid: true if format is valid, range constraint (From <= To) satisfied, and non-empty
 //   - Present: true if parameter exists in request (even if Invalid)
 //
 // Behavior:
 //   - Single value (e.g., "100") sets both From and To to same value
 //   - Range format (e.g., "100,500") sets From and To separately
+//   - Open-ended format "100," (>= 100) or ",500" (<= 500) sets only one bound
 //   - Invalid format or From > To results in Valid=false but Present=true
-//   - Empty input results in Valid=false and Present=true
+//   - Empty input (or ",") results in Valid=false and Present=true
 type Range[T any] struct {
 	From    T
 	To      T
+	HasFrom bool // true if the lower bound is set (false for ",500")
+	HasTo   bool // true if the upper bound is set (false for "100,")
 	Valid   bool // true if format is valid and has non-empty value and From <= To
 	Present bool // true if parameter exists in request
 }
@@ -149,9 +157,16 @@ type TimestampRange = Range[int64]
 type FilterParser struct {
 	request             *http.Request
 	errors              map[string]string
-	MaxValuesPerField   int                            // Maximum number of values allowed per filter field (0 = unlimited)
-	TimestampTimezone   *time.Location                 // Timezone for parsing timestamps (nil = UTC)
-	constraintValidator map[string]ConstraintValidator // Custom constraint validators (e.g., "in", "regex")
+	MaxValuesPerField   int                               // Maximum number of values allowed per filter field (0 = unlimited)
+	TimestampTimezone   *time.Location                    // Timezone for parsing timestamps and time.Time values (nil = UTC)
+	TimeLayouts         []string                          // Layouts for time.Time fields (nil = DefaultFilterTimeLayouts)
+	Delimiter           string                            // Separator for multi-value filters ("" = ",", "none" = no splitting)
+	constraintValidator map[string]ConstraintValidator    // Custom constraint validators (e.g., "in", "regex")
+	typeParsers         map[reflect.Type]FilterTypeParser // Application value types, see RegisterType
+	conditions          []FilterCondition                 // Parsed conditions, see Conditions()
+	presetStore         FilterPresetStore                 // Saved filters for filters[preset], see WithPresets
+	presetResource      string                            // Resource (list view) of the presets
+	preset              *FilterPreset                     // Preset applied by the last Parse
 }
 
 // NewFilterParser creates a new FilterParser instance with unlimited values.
@@ -181,7 +196,8 @@ func (fp *FilterParser) WithMaxValues(max int) *FilterParser {
 }
 
 // WithTimezone sets the timezone for parsing timestamp ranges.
-// If nil, UTC is used (default). This affects parseTimestampRange only.
+// If nil, UTC is used (default). This affects TimestampRange and time.Time fields
+// whose layout has no zone.
 // Returns the receiver for method chaining.
 //
 // Example:
@@ -193,6 +209,21 @@ func (fp *FilterParser) WithTimezone(tz *time.Location) *FilterParser {
 	return fp
 }
 
+// WithDelimiter sets the separator used to split slice and "ne" filter values (default ",").
+// Use it when values legitimately contain commas, e.g. names or addresses. "none" disables
+// splitting so each repeated parameter is one value. A field can override the delimiter with
+// the tag option "delimiter:;". Range values always use a comma between from and to.
+// Returns the receiver for method chaining.
+//
+// Example:
+//
+//	// ?filters[names]=Doe, John;Roe, Jane
+//	fp.WithDelimiter(";").Parse(&filters)
+func (fp *FilterParser) WithDelimiter(delimiter string) *FilterParser {
+	fp.Delimiter = delimiter
+	return fp
+}
+
 // RegisterConstraintValidator registers a custom constraint validator.
 // Replaces any existing validator with the same name (including built-in validators).
 // Returns the receiver for method chaining.
@@ -260,15 +291,32 @@ func (fp *FilterParser) Errors() map[string]string {
 //	    Price       *IntRange     `filter:"price"`             // integer range with optional pointer
 //	    CreatedAt   TimestampRange `filter:"created_at"`       // date range "2024-01-01,2024-12-31"
 //	    Date        DateRange     `filter:"date"`              // string date range
+//
+//	    // Location radius "lat,lng,radius_km"
+//	    Near        *GeoRadius    `filter:"near,max_radius:50"`
 //	}
 //
 // Built-in Constraints:
 //   - in:val1|val2|val3 : Enum validation for strings (pipe-separated allowed values)
+//   - ops:gte|lte : Operators allowed in filters[field][op] (default depends on field type)
+//   - max_radius:50 : Maximum radius in kilometers for *GeoRadius fields
+//
+// Operators:
+//   - ?filters[price][gte]=100&filters[name][like]=jo&filters[deleted_at][null]=true
+//   - Supported: eq, ne, gt, gte, lt, lte, like, null (see FilterOp)
+//   - Operator values are type-checked but not bound to the struct; read them via Conditions()
+//   - Error keys follow format: "filters[fieldName][op]"
 //
 // Custom Constraints:
 //   - Register via RegisterConstraintValidator() to add custom constraint types
 //   - Multiple constraints per field supported
 //
+// Custom Types:
+//   - Register via RegisterType() to bind application types (T, *T, []T) such as money or enums
+//
+// Presets:
+//   - ?filters[preset]=name expands a saved FilterPreset when WithPresets is configured
+//
 // Error Handling:
 //   - Check HasErrors() before accessing filter results
 //   - Call Errors() to get map[string]string with field-specific error messages
@@ -281,48 +329,62 @@ func (fp *FilterParser) Parse(target interface{}) *FilterParser {
 	}
 
 	v = v.Elem()
-	t := v.Type()
+	plan := filterPlanFor(v.Type())
+	locale := LocaleFromContext(fp.request.Context())
+	query := fp.filterQuery(locale)
 
-	for i := 0; i < v.NumField(); i++ {
-		field := v.Field(i)
-		fieldType := t.Field(i)
+	for i := range plan.fields {
+		f := &plan.fields[i]
+		field := v.FieldByIndex(f.index)
 
-		if !field.CanSet() {
-			continue
-		}
+		filterValues := query[f.key]
+		ops := operatorValues(query, f.name)
 
-		filterTag := fieldType.Tag.Get("filter")
-		if filterTag == "" || filterTag == "-" {
-			continue
+		// Slice values are split by the delimiter; filters[name][]=a elements are taken as-is
+		if f.container == reflect.Slice {
+			filterValues = splitFilterValues(filterValues, fp.filterDelimiter(f.constraints))
 		}
-
-		// Parse filter tag: "fieldName" or "fieldName,in:val1|val2" or "fieldName,constraint1:val,constraint2:val"
-		parts := strings.Split(filterTag, ",")
-		fieldName := strings.TrimSpace(parts[0])
-		if fieldName == "" {
-			continue
+		if arr, ok := ops[filterOpArray]; ok {
+			filterValues = append(filterValues, arr...)
+			delete(ops, filterOpArray)
 		}
 
-		// Extract constraints (e.g., "in:active|pending,min:1" becomes map{in: "active|pending", min: "1"})
-		constraints := make(map[string]string)
-		for i := 1; i < len(parts); i++ {
-			part := strings.TrimSpace(parts[i])
-			if idx := strings.Index(part, ":"); idx > 0 {
-				key := part[:idx]
-				value := strings.TrimSpace(part[idx+1:])
-				if value != "" {
-					constraints[key] = value
+		// filters[name]=!value is the same as filters[name][not]=value
+		if !f.isRange && !f.isGeo {
+			var negated []string
+			filterValues, negated = splitNegatedValues(filterValues, fp.filterDelimiter(f.constraints))
+			if len(negated) > 0 {
+				if ops == nil {
+					ops = make(map[FilterOp][]string)
 				}
+				ops[FilterOpNot] = append(ops[FilterOpNot], negated...)
 			}
 		}
 
-		filterValues := fp.request.URL.Query()["filters["+fieldName+"]"]
-		if len(filterValues) == 0 {
-			continue
+		// filters[name][eq] is the same as filters[name], but never negated
+		if eq, ok := ops[FilterOpEq]; ok {
+			if f.container == reflect.Slice {
+				eq = splitFilterValues(eq, fp.filterDelimiter(f.constraints))
+			}
+			filterValues = append(filterValues, eq...)
+			delete(ops, FilterOpEq)
 		}
 
-		if err := fp.parseFieldValue(field, fieldType, filterValues, constraints); err != nil {
-			fp.errors["filters["+fieldName+"]"] = err.Error()
+		if len(filterValues) > 0 {
+			if err := fp.parseFieldValue(field, f, filterValues); err != nil {
+				fp.errors[f.key] = translateError(locale, err)
+			} else {
+				fp.conditions = append(fp.conditions, equalityCondition(f.name, field))
+			}
+		}
+
+		for _, op := range sortedOperators(ops) {
+			condition, err := fp.parseOperatorCondition(f.name, f.structField.Type, op, ops[op], f.constraints)
+			if err != nil {
+				fp.errors[f.key+"["+string(op)+"]"] = translateError(locale, err)
+				continue
+			}
+			fp.conditions = append(fp.conditions, condition)
 		}
 	}
 
@@ -346,114 +408,104 @@ func (fp *FilterParser) Parse(target interface{}) *FilterParser {
 
 // Field Value Parsing
 
-// parseFieldValue parses a field value for a given field type and value.
-// It handles different types of field values and sets the field accordingly.
+// parseFieldValue parses the values of a field using the parser selected in its plan.
 // Routes to parseSliceValue or parsePointerValue based on field kind.
-func (fp *FilterParser) parseFieldValue(field reflect.Value, fieldType reflect.StructField, values []string, constraints map[string]string) error {
-	fieldKind := field.Kind()
+func (fp *FilterParser) parseFieldValue(field reflect.Value, f *filterField, values []string) error {
+	if ok, err := fp.parseRegisteredType(field, f, values); ok {
+		return err
+	}
 
-	if fieldKind == reflect.Slice {
-		return fp.parseSliceValue(field, fieldType, values, constraints)
+	// Constraints check the raw values; Range and GeoRadius values are not constrained
+	if !f.isRange && !f.isGeo {
+		if err := fp.applyConstraints(values, f.constraints, f.structField.Type); err != nil {
+			return err
+		}
 	}
 
-	if fieldKind == reflect.Ptr {
+	switch f.container {
+	case reflect.Slice:
+		return fp.parseSliceValue(field, f, values)
+	case reflect.Ptr:
 		if len(values) == 0 {
 			return nil
 		}
-		return fp.parsePointerValue(field, fieldType, values[0], constraints)
+		return fp.parsePointerValue(field, f, values[0])
 	}
 
 	// Handle non-pointer Range types
-	if typeMatches(fieldType.Type, reflect.TypeOf(DateRange{})) {
-		if len(values) == 0 {
-			return nil
-		}
+	if len(values) == 0 && f.kind != filterKindUnsupported {
+		return nil
+	}
+
+	switch f.kind {
+	case filterKindDateRange:
 		dr := parseDateRange(values[0])
 		if dr.Present && !dr.Valid {
-			return fmt.Errorf("format tanggal tidak valid (gunakan YYYY-MM-DD atau YYYY-MM-DD,YYYY-MM-DD)")
+			return localizedErrorf("format tanggal tidak valid (gunakan YYYY-MM-DD atau YYYY-MM-DD,YYYY-MM-DD)")
 		}
 		field.Set(reflect.ValueOf(dr))
 		return nil
-	}
 
-	if typeMatches(fieldType.Type, reflect.TypeOf(AmountRange{})) {
-		if len(values) == 0 {
-			return nil
-		}
+	case filterKindAmountRange:
 		ar := parseAmountRange(values[0])
 		if ar.Present && !ar.Valid {
-			return fmt.Errorf("format amount tidak valid")
+			return localizedErrorf("format amount tidak valid")
 		}
 		field.Set(reflect.ValueOf(ar))
 		return nil
-	}
 
-	if typeMatches(fieldType.Type, reflect.TypeOf(TimestampRange{})) {
-		if len(values) == 0 {
-			return nil
-		}
+	case filterKindTimestampRange:
 		tr := parseTimestampRange(values[0], fp.TimestampTimezone)
 		if tr.Present && !tr.Valid {
-			return fmt.Errorf("format tanggal tidak valid (gunakan YYYY-MM-DD atau YYYY-MM-DD,YYYY-MM-DD)")
+			return localizedErrorf("format tanggal tidak valid (gunakan YYYY-MM-DD atau YYYY-MM-DD,YYYY-MM-DD)")
 		}
 		field.Set(reflect.ValueOf(tr))
 		return nil
-	}
 
-	if typeMatches(fieldType.Type, reflect.TypeOf(IntRange{})) {
-		if len(values) == 0 {
-			return nil
+	case filterKindTimeRange:
+		tr, err := fp.parseTimeRangeValue(values[0], f)
+		if err != nil {
+			return err
 		}
-		ir := parseIntRange(values[0])
-		if ir.Present && !ir.Valid {
-			return fmt.Errorf("format angka tidak valid (gunakan 100 atau 100,500)")
+		field.Set(reflect.ValueOf(tr))
+		return nil
+
+	case filterKindTime:
+		t, err := fp.parseFilterTime(values[0], fp.filterTimeLayouts(f.constraints))
+		if err != nil {
+			return err
 		}
-		field.Set(reflect.ValueOf(ir))
+		field.Set(reflect.ValueOf(t))
 		return nil
 	}
 
-	return fmt.Errorf("field %s must be a pointer or slice type", fieldType.Name)
+	return fmt.Errorf("field %s must be a pointer or slice type", f.structField.Name)
 }
 
 // parseSliceValue parses a slice value for a given field type and value.
 // It handles different types of slice values and sets the field accordingly.
-func (fp *FilterParser) parseSliceValue(field reflect.Value, fieldType reflect.StructField, values []string, constraints map[string]string) error {
-	elemType := field.Type().Elem()
-
-	// Support comma-separated values: if single value contains comma, split it
-	if len(values) == 1 && strings.Contains(values[0], ",") {
-		parts := strings.Split(values[0], ",")
-		values = make([]string, 0, len(parts))
-		for _, part := range parts {
-			trimmed := strings.TrimSpace(part)
-			if trimmed != "" {
-				values = append(values, trimmed)
-			}
-		}
-	}
-
+func (fp *FilterParser) parseSliceValue(field reflect.Value, f *filterField, values []string) error {
 	// Check max values limit
 	if fp.MaxValuesPerField > 0 && len(values) > fp.MaxValuesPerField {
-		return fmt.Errorf("maksimal %d nilai diperbolehkan, diterima %d", fp.MaxValuesPerField, len(values))
+		return localizedErrorf("maksimal %d nilai diperbolehkan, diterima %d", fp.MaxValuesPerField, len(values))
 	}
 
-	if typeMatches(elemType, reflect.TypeOf(UUID{})) {
+	switch f.kind {
+	case filterKindUUID:
 		uuids := make([]UUID, 0, len(values))
 		for _, v := range values {
 			parsed, err := ParseUuid(v)
 			if err != nil {
-				return fmt.Errorf("UUID tidak valid: %s", v)
+				return localizedErrorf("UUID tidak valid: %s", v)
 			}
 			uuids = append(uuids, parsed)
 		}
 		field.Set(reflect.ValueOf(uuids))
 		return nil
-	}
 
-	switch elemType.Kind() {
-	case reflect.String:
-		stringType := reflect.TypeOf("")
-		if !typeMatches(elemType, stringType) {
+	case filterKindString:
+		if f.namedString {
+			elemType := field.Type().Elem()
 			slice := reflect.MakeSlice(field.Type(), len(values), len(values))
 			for i, v := range values {
 				elem := reflect.New(elemType).Elem()
@@ -464,44 +516,51 @@ func (fp *FilterParser) parseSliceValue(field reflect.Value, fieldType reflect.S
 		} else {
 			field.Set(reflect.ValueOf(values))
 		}
-
-		// Apply registered constraint validators
-		if err := fp.applyConstraints(values, constraints, fieldType.Type); err != nil {
-			return err
-		}
-
 		return nil
 
-	case reflect.Int64:
+	case filterKindInt64:
 		ints := make([]int64, 0, len(values))
 		for _, v := range values {
 			parsed, err := strconv.ParseInt(v, 10, 64)
 			if err != nil {
-				return fmt.Errorf("harus berupa angka: %s", v)
+				return localizedErrorf("harus berupa angka: %s", v)
 			}
 			ints = append(ints, parsed)
 		}
 		field.Set(reflect.ValueOf(ints))
 		return nil
 
-	case reflect.Int:
+	case filterKindInt:
 		ints := make([]int, 0, len(values))
 		for _, v := range values {
 			parsed, err := strconv.Atoi(v)
 			if err != nil {
-				return fmt.Errorf("harus berupa angka: %s", v)
+				return localizedErrorf("harus berupa angka: %s", v)
 			}
 			ints = append(ints, parsed)
 		}
 		field.Set(reflect.ValueOf(ints))
 		return nil
 
-	case reflect.Float64:
+	case filterKindTime:
+		layouts := fp.filterTimeLayouts(f.constraints)
+		times := make([]time.Time, 0, len(values))
+		for _, v := range values {
+			t, err := fp.parseFilterTime(v, layouts)
+			if err != nil {
+				return err
+			}
+			times = append(times, t)
+		}
+		field.Set(reflect.ValueOf(times))
+		return nil
+
+	case filterKindFloat64:
 		floats := make([]float64, 0, len(values))
 		for _, v := range values {
 			parsed, err := strconv.ParseFloat(v, 64)
 			if err != nil {
-				return fmt.Errorf("harus berupa angka desimal: %s", v)
+				return localizedErrorf("harus berupa angka desimal: %s", v)
 			}
 			floats = append(floats, parsed)
 		}
@@ -509,107 +568,107 @@ func (fp *FilterParser) parseSliceValue(field reflect.Value, fieldType reflect.S
 		return nil
 
 	default:
-		return fmt.Errorf("unsupported slice element type: %s", elemType.Kind())
+		return fmt.Errorf("unsupported slice element type: %s", field.Type().Elem().Kind())
 	}
 }
 
 // parsePointerValue parses a pointer value for a given field type and value.
 // It handles different types of pointer values and sets the field accordingly.
-// Note: Constraints are validated for pointer string types.
-func (fp *FilterParser) parsePointerValue(field reflect.Value, fieldType reflect.StructField, value string, constraints map[string]string) error {
-
-	if field.Kind() != reflect.Ptr {
-		return fmt.Errorf("field %s must be a pointer type", fieldType.Name)
-	}
-
-	elemType := field.Type().Elem()
-
+func (fp *FilterParser) parsePointerValue(field reflect.Value, f *filterField, value string) error {
+	switch f.kind {
 	// Handle Range types
-	if typeMatches(elemType, reflect.TypeOf(DateRange{})) {
+	case filterKindDateRange:
 		dr := parseDateRange(value)
 		if dr.Present && !dr.Valid {
-			return fmt.Errorf("format tanggal tidak valid (gunakan YYYY-MM-DD atau YYYY-MM-DD,YYYY-MM-DD)")
+			return localizedErrorf("format tanggal tidak valid (gunakan YYYY-MM-DD atau YYYY-MM-DD,YYYY-MM-DD)")
 		}
 		field.Set(reflect.ValueOf(&dr))
 		return nil
-	}
 
-	if typeMatches(elemType, reflect.TypeOf(AmountRange{})) {
+	case filterKindAmountRange:
 		ar := parseAmountRange(value)
 		if ar.Present && !ar.Valid {
-			return fmt.Errorf("format amount tidak valid")
+			return localizedErrorf("format amount tidak valid")
 		}
 		field.Set(reflect.ValueOf(&ar))
 		return nil
-	}
 
-	if typeMatches(elemType, reflect.TypeOf(IntRange{})) {
+	case filterKindIntRange:
 		ir := parseIntRange(value)
 		if ir.Present && !ir.Valid {
-			return fmt.Errorf("format angka tidak valid (gunakan 100 atau 100,500)")
+			return localizedErrorf("format angka tidak valid (gunakan 100 atau 100,500)")
 		}
 		field.Set(reflect.ValueOf(&ir))
 		return nil
-	}
 
-	if typeMatches(elemType, reflect.TypeOf(TimestampRange{})) {
-		tr := parseTimestampRange(value, fp.TimestampTimezone)
-		if tr.Present && !tr.Valid {
-			return fmt.Errorf("format tanggal tidak valid (gunakan YYYY-MM-DD atau YYYY-MM-DD,YYYY-MM-DD)")
+	case filterKindTimeRange:
+		tr, err := fp.parseTimeRangeValue(value, f)
+		if err != nil {
+			return err
 		}
 		field.Set(reflect.ValueOf(&tr))
 		return nil
-	}
 
-	if typeMatches(elemType, reflect.TypeOf(UUID{})) {
+	case filterKindUUID:
 		parsed, err := ParseUuid(value)
 		if err != nil {
-			return fmt.Errorf("UUID tidak valid")
+			return localizedErrorf("UUID tidak valid")
 		}
 		field.Set(reflect.ValueOf(&parsed))
 		return nil
-	}
 
-	switch elemType.Kind() {
-	case reflect.String:
-		// Apply constraints for pointer string types
-		if err := fp.applyConstraints([]string{value}, constraints, fieldType.Type); err != nil {
+	case filterKindTime:
+		t, err := fp.parseFilterTime(value, fp.filterTimeLayouts(f.constraints))
+		if err != nil {
 			return err
 		}
+		field.Set(reflect.ValueOf(&t))
+		return nil
+
+	case filterKindString:
 		field.Set(reflect.ValueOf(&value))
 		return nil
 
-	case reflect.Int:
+	case filterKindInt:
 		parsed, err := strconv.Atoi(value)
 		if err != nil {
-			return fmt.Errorf("harus berupa angka")
+			return localizedErrorf("harus berupa angka")
 		}
 		field.Set(reflect.ValueOf(&parsed))
 		return nil
 
-	case reflect.Int64:
+	case filterKindInt64:
 		parsed, err := strconv.ParseInt(value, 10, 64)
 		if err != nil {
-			return fmt.Errorf("harus berupa angka")
+			return localizedErrorf("harus berupa angka")
 		}
 		field.Set(reflect.ValueOf(&parsed))
 		return nil
 
-	case reflect.Bool:
+	case filterKindBool:
 		parsed, err := strconv.ParseBool(value)
 		if err != nil {
-			return fmt.Errorf("harus berupa true atau false")
+			return localizedErrorf("harus berupa true atau false")
 		}
 		field.Set(reflect.ValueOf(&parsed))
 		return nil
 
+	case filterKindGeoRadius:
+		area, err := fp.parseGeoRadiusValue(value, f)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.ValueOf(&area))
+		return nil
+
 	default:
-		return fmt.Errorf("unsupported type: %s", elemType.Kind())
+		return fmt.Errorf("unsupported type: %s", field.Type().Elem().Kind())
 	}
 }
 
 // parseDateRange parses "YYYY-MM-DD,YYYY-MM-DD" format
 // If only one date provided, To is set to From
+// Open-ended "YYYY-MM-DD," and ",YYYY-MM-DD" set only HasFrom or HasTo
 // Empty string is considered invalid
 //
 // Note: Does not use generic parseRange helper because validation is based on
@@ -620,28 +679,24 @@ func parseDateRange(value string) DateRange {
 		return DateRange{Present: true, Valid: false}
 	}
 
-	parts := strings.Split(value, ",")
-	from := strings.TrimSpace(parts[0])
-
-	if from == "" {
+	from, to, hasTo := splitRangeValue(value)
+	if from == "" && to == "" {
 		return DateRange{Present: true, Valid: false}
 	}
 
 	// Default to = from (single date)
-	to := from
-	if len(parts) > 1 {
-		toStr := strings.TrimSpace(parts[1])
-		if toStr != "" {
-			to = toStr
-		}
+	if !hasTo {
+		to = from
 	}
+	dr := DateRange{From: from, To: to, HasFrom: from != "", HasTo: to != "", Present: true}
 
-	// Validate YYYY-MM-DD format
-	if !IsValidDateFormat(from) || !IsValidDateFormat(to) {
-		return DateRange{From: from, To: to, Present: true, Valid: false}
+	// Validate YYYY-MM-DD format of the given bounds
+	if (dr.HasFrom && !IsValidDateFormat(from)) || (dr.HasTo && !IsValidDateFormat(to)) {
+		return dr
 	}
 
-	return DateRange{From: from, To: to, Present: true, Valid: true}
+	dr.Valid = true
+	return dr
 }
 
 // parseAmountRange parses amount range in "100.50" or "100.50,500.00" format.
@@ -656,6 +711,7 @@ func parseDateRange(value string) DateRange {
 //	"100.50"         → AmountRange{From: 100.50, To: 100.50, Valid: true}
 //	"100.50,500.00"  → AmountRange{From: 100.50, To: 500.00, Valid: true}
 //	"500.00,100.50"  → AmountRange{From: 500.00, To: 100.50, Valid: false}
+//	"100.50,"        → AmountRange{From: 100.50, HasFrom: true, Valid: true}
 //	"invalid"        → AmountRange{Present: true, Valid: false}
 func parseAmountRange(value string) AmountRange {
 	return parseRange(
@@ -677,6 +733,8 @@ func parseAmountRange(value string) AmountRange {
 //	"100"    → IntRange{From: 100, To: 100, Valid: true}
 //	"100,500" → IntRange{From: 100, To: 500, Valid: true}
 //	"500,100" → IntRange{From: 500, To: 100, Valid: false}
+//	"100,"   → IntRange{From: 100, HasFrom: true, HasTo: false, Valid: true}
+//	",500"   → IntRange{To: 500, HasFrom: false, HasTo: true, Valid: true}
 //	"abc"    → IntRange{Present: true, Valid: false}
 func parseIntRange(value string) IntRange {
 	return parseRange(
@@ -736,8 +794,9 @@ func parseTimestampRange(value string, tz *time.Location) TimestampRange {
 //   - If parsing fails: returns Range with Present=true, Valid=false
 //   - If from > to: returns Range with Present=true, Valid=false (parsed values included)
 //   - If single value: sets To = From
+//   - If one side is empty ("100," or ",500"): only that bound is set (HasFrom/HasTo)
 //
-// Returns Range with From, To, Present, and Valid fields set appropriately.
+// Returns Range with From, To, HasFrom, HasTo, Present, and Valid fields set appropriately.
 //
 // Example:
 //
@@ -745,45 +804,60 @@ func parseTimestampRange(value string, tz *time.Location) TimestampRange {
 //	    func(s string) (int64, error) { return strconv.ParseInt(s, 10, 64) },
 //	    func(from, to int64) bool { return from <= to },
 //	)
-//	// Returns: Range[int64]{From: 100, To: 500, Present: true, Valid: true}
+//	// Returns: Range[int64]{From: 100, To: 500, HasFrom: true, HasTo: true, Present: true, Valid: true}
 func parseRange[T any](value string, parser func(string) (T, error), validator func(T, T) bool) Range[T] {
 	value = strings.TrimSpace(value)
 	if value == "" {
-		var zero T
-		return Range[T]{From: zero, To: zero, Present: true, Valid: false}
+		return Range[T]{Present: true, Valid: false}
 	}
 
-	parts := strings.Split(value, ",")
-	fromStr := strings.TrimSpace(parts[0])
-
-	if fromStr == "" {
-		var zero T
-		return Range[T]{From: zero, To: zero, Present: true, Valid: false}
+	fromStr, toStr, hasTo := splitRangeValue(value)
+	if fromStr == "" && toStr == "" {
+		return Range[T]{Present: true, Valid: false}
 	}
 
-	// Parse from value
-	from, err := parser(fromStr)
-	if err != nil {
-		var zero T
-		return Range[T]{From: zero, To: zero, Present: true, Valid: false}
+	r := Range[T]{Present: true}
+
+	// Parse from value (empty for ",500")
+	if fromStr != "" {
+		from, err := parser(fromStr)
+		if err != nil {
+			return r
+		}
+		r.From, r.HasFrom = from, true
 	}
 
-	// Default to = from (single value case)
-	to := from
-	if len(parts) > 1 && strings.TrimSpace(parts[1]) != "" {
-		parsed, err := parser(strings.TrimSpace(parts[1]))
+	// Default to = from (single value case); empty for "100,"
+	switch {
+	case !hasTo:
+		r.To, r.HasTo = r.From, true
+	case toStr != "":
+		to, err := parser(toStr)
 		if err != nil {
-			return Range[T]{From: from, To: to, Present: true, Valid: false}
+			r.To = r.From
+			return r
 		}
-		to = parsed
+		r.To, r.HasTo = to, true
 	}
 
-	// Validate from <= to relationship
-	if !validator(from, to) {
-		return Range[T]{From: from, To: to, Present: true, Valid: false}
+	// Validate from <= to relationship when both bounds are given
+	if r.HasFrom && r.HasTo && !validator(r.From, r.To) {
+		return r
 	}
 
-	return Range[T]{From: from, To: to, Present: true, Valid: true}
+	r.Valid = true
+	return r
+}
+
+// splitRangeValue splits "from,to" into trimmed bounds. hasTo reports whether a comma was present,
+// so "100" (single value) can be told apart from the open-ended "100,".
+func splitRangeValue(value string) (from, to string, hasTo bool) {
+	from, to, hasTo = strings.Cut(value, ",")
+	if hasTo {
+		// Extra parts ("1,2,3") are ignored, as before
+		to, _, _ = strings.Cut(to, ",")
+	}
+	return strings.TrimSpace(from), strings.TrimSpace(to), hasTo
 }
 
 // applyConstraints applies all registered constraint validators to the values.
@@ -865,11 +939,18 @@ type ConstraintValidator interface {
 }
 
 // BuiltinConstraintValidators returns a map of built-in constraint validators.
-// These validators handle common constraints like "in" for enums.
+// These validators handle common constraints: "in" for enums, numeric "min"/"max",
+// string length "len", "regex", "uuid", and "date".
 // Can be extended by adding custom validators to FilterParser.
 func BuiltinConstraintValidators() map[string]ConstraintValidator {
 	return map[string]ConstraintValidator{
-		"in": &InConstraintValidator{},
+		"in":    &InConstraintValidator{},
+		"min":   &MinConstraintValidator{},
+		"max":   &MaxConstraintValidator{},
+		"len":   &LenConstraintValidator{},
+		"regex": &RegexConstraintValidator{},
+		"uuid":  &UUIDConstraintValidator{},
+		"date":  &DateConstraintValidator{},
 	}
 }
 
@@ -923,7 +1004,7 @@ func (v *InConstraintValidator) Validate(values []string, constraint string, fie
 	}
 
 	if len(allowedValues) == 0 {
-		return fmt.Errorf("constraint tidak valid: tidak ada nilai yang diizinkan")
+		return localizedErrorf("constraint tidak valid: tidak ada nilai yang diizinkan")
 	}
 
 	// Validate each value
@@ -934,7 +1015,7 @@ func (v *InConstraintValidator) Validate(values []string, constraint string, fie
 			for k := range allowedValues {
 				allowed = append(allowed, k)
 			}
-			return fmt.Errorf("nilai tidak valid: %s (diizinkan: %s)", value, strings.Join(allowed, ", "))
+			return localizedErrorf("nilai tidak valid: %s (diizinkan: %s)", value, strings.Join(allowed, ", "))
 		}
 	}
 
@@ -0,0 +1,218 @@
+package dim
+
+import (
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// MinConstraintValidator implements ConstraintValidator for a numeric lower bound.
+//
+// Format:
+//   - Tag: "fieldName,min:18" (integer or decimal)
+//
+// Example usage:
+//
+//	type Filters struct {
+//	    Age *int `filter:"age,min:18,max:99"`
+//	}
+type MinConstraintValidator struct{}
+
+// Name returns the constraint name
+func (v *MinConstraintValidator) Name() string {
+	return "min"
+}
+
+// Validate checks that every value is a number greater than or equal to the constraint.
+func (v *MinConstraintValidator) Validate(values []string, constraint string, _ reflect.Type) error {
+	return validateNumericBound(values, "min", constraint, func(value, bound float64) bool { return value >= bound }, "%s harus minimal %s")
+}
+
+// MaxConstraintValidator implements ConstraintValidator for a numeric upper bound.
+//
+// Format:
+//   - Tag: "fieldName,max:99" (integer or decimal)
+type MaxConstraintValidator struct{}
+
+// Name returns the constraint name
+func (v *MaxConstraintValidator) Name() string {
+	return "max"
+}
+
+// Validate checks that every value is a number less than or equal to the constraint.
+func (v *MaxConstraintValidator) Validate(values []string, constraint string, _ reflect.Type) error {
+	return validateNumericBound(values, "max", constraint, func(value, bound float64) bool { return value <= bound }, "%s tidak boleh lebih dari %s")
+}
+
+// validateNumericBound parses the bound and values as numbers and applies inRange to each value.
+func validateNumericBound(values []string, name, constraint string, inRange func(value, bound float64) bool, format string) error {
+	bound, err := strconv.ParseFloat(constraint, 64)
+	if err != nil {
+		return localizedErrorf("constraint %s tidak valid: %s", name, constraint)
+	}
+	for _, value := range values {
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return localizedErrorf("harus berupa angka: %s", value)
+		}
+		if !inRange(n, bound) {
+			return localizedErrorf(format, value, constraint)
+		}
+	}
+	return nil
+}
+
+// LenConstraintValidator implements ConstraintValidator for string length, counted in characters.
+//
+// Format:
+//   - Tag: "fieldName,len:3-50" (between 3 and 50), "len:3-" (at least 3),
+//     "len:-50" (at most 50), or "len:8" (exactly 8)
+type LenConstraintValidator struct{}
+
+// Name returns the constraint name
+func (v *LenConstraintValidator) Name() string {
+	return "len"
+}
+
+// Validate checks the character count of every value against the constraint.
+func (v *LenConstraintValidator) Validate(values []string, constraint string, _ reflect.Type) error {
+	minStr, maxStr, isRange := strings.Cut(constraint, "-")
+	if !isRange {
+		maxStr = minStr
+	}
+
+	min, max := -1, -1
+	var err error
+	if minStr != "" {
+		if min, err = strconv.Atoi(minStr); err != nil || min < 0 {
+			return localizedErrorf("constraint %s tidak valid: %s", "len", constraint)
+		}
+	}
+	if maxStr != "" {
+		if max, err = strconv.Atoi(maxStr); err != nil || max < 0 {
+			return localizedErrorf("constraint %s tidak valid: %s", "len", constraint)
+		}
+	}
+	if min < 0 && max < 0 {
+		return localizedErrorf("constraint %s tidak valid: %s", "len", constraint)
+	}
+
+	for _, value := range values {
+		n := utf8.RuneCountInString(value)
+		switch {
+		case !isRange && n != min:
+			return localizedErrorf("%s harus tepat %s karakter", value, minStr)
+		case min >= 0 && n < min:
+			return localizedErrorf("%s harus minimal %s karakter", value, minStr)
+		case max >= 0 && n > max:
+			return localizedErrorf("%s tidak boleh melebihi %s karakter", value, maxStr)
+		}
+	}
+	return nil
+}
+
+// filterRegexCache holds compiled "regex" constraint patterns. Tags are static,
+// so the cache is bounded by the number of distinct patterns in the program.
+var filterRegexCache sync.Map // string -> *regexp.Regexp
+
+// RegexConstraintValidator implements ConstraintValidator for regular expression matching.
+// Patterns are compiled once and cached. Because tag options are separated by commas,
+// a pattern cannot contain a comma; use a custom validator for such patterns.
+//
+// Format:
+//   - Tag: "fieldName,regex:^[a-z0-9-]+$" (the whole value must be covered by anchors)
+type RegexConstraintValidator struct{}
+
+// Name returns the constraint name
+func (v *RegexConstraintValidator) Name() string {
+	return "regex"
+}
+
+// Validate checks that every value matches the pattern.
+func (v *RegexConstraintValidator) Validate(values []string, constraint string, _ reflect.Type) error {
+	pattern, err := compileFilterRegex(constraint)
+	if err != nil {
+		return localizedErrorf("pola validasi tidak valid")
+	}
+	for _, value := range values {
+		if !pattern.MatchString(value) {
+			return localizedErrorf("format %s tidak valid", value)
+		}
+	}
+	return nil
+}
+
+func compileFilterRegex(expr string) (*regexp.Regexp, error) {
+	if cached, ok := filterRegexCache.Load(expr); ok {
+		return cached.(*regexp.Regexp), nil
+	}
+	pattern, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	filterRegexCache.Store(expr, pattern)
+	return pattern, nil
+}
+
+// UUIDConstraintValidator implements ConstraintValidator for UUID strings, for fields
+// kept as string (e.g. external IDs) instead of UUID.
+//
+// Format:
+//   - Tag: "fieldName,uuid"
+type UUIDConstraintValidator struct{}
+
+// Name returns the constraint name
+func (v *UUIDConstraintValidator) Name() string {
+	return "uuid"
+}
+
+// Validate checks that every value is a valid UUID.
+func (v *UUIDConstraintValidator) Validate(values []string, _ string, _ reflect.Type) error {
+	for _, value := range values {
+		if !IsValidUuid(value) {
+			return localizedErrorf("UUID tidak valid: %s", value)
+		}
+	}
+	return nil
+}
+
+// DateConstraintValidator implements ConstraintValidator for date and time strings, for
+// fields kept as string instead of time.Time.
+//
+// Format:
+//   - Tag: "fieldName,date" (YYYY-MM-DD), or "date:<layout>" with a Go layout or one of
+//     the aliases date, datetime, rfc3339, unix (see the "layout" tag option)
+type DateConstraintValidator struct{}
+
+// Name returns the constraint name
+func (v *DateConstraintValidator) Name() string {
+	return "date"
+}
+
+// Validate checks that every value can be parsed with the layout.
+func (v *DateConstraintValidator) Validate(values []string, constraint string, _ reflect.Type) error {
+	layout := constraint
+	if layout == "" {
+		layout = time.DateOnly
+	}
+	if alias, ok := filterTimeLayoutAliases[layout]; ok {
+		layout = alias
+	}
+
+	for _, value := range values {
+		var err error
+		if layout == TimeLayoutUnix {
+			_, err = strconv.ParseInt(value, 10, 64)
+		} else {
+			_, err = time.Parse(layout, value)
+		}
+		if err != nil {
+			return localizedErrorf("format waktu tidak valid: %s (gunakan %s)", value, layout)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package dim
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type constraintFilters struct {
+	Age     *int     `filter:"age,min:18,max:99"`
+	Scores  []int64  `filter:"scores,min:0,max:100"`
+	Price   *int64   `filter:"price,min:0.5"`
+	Code    *string  `filter:"code,len:4"`
+	Name    *string  `filter:"name,len:2-5"`
+	Tags    []string `filter:"tags,len:-3"`
+	Slug    *string  `filter:"slug,regex:^[a-z0-9]+(-[a-z0-9]+)*$"`
+	Ref     *string  `filter:"ref,uuid"`
+	Day     *string  `filter:"day,date"`
+	Stamp   *string  `filter:"stamp,date:rfc3339"`
+	Invalid *string  `filter:"invalid,regex:[a-"`
+}
+
+func parseConstraintFilters(q url.Values) (*FilterParser, constraintFilters) {
+	var f constraintFilters
+	fp := NewFilterParser(httptest.NewRequest("GET", "/?"+q.Encode(), nil)).Parse(&f)
+	return fp, f
+}
+
+func TestBuiltinConstraintValidators_Valid(t *testing.T) {
+	q := url.Values{}
+	q.Set("filters[age]", "18")
+	q.Set("filters[scores]", "0,55,100")
+	q.Set("filters[price]", "1")
+	q.Set("filters[code]", "ÄBCD")
+	q.Set("filters[name]", "bob")
+	q.Set("filters[tags]", "go,db")
+	q.Set("filters[slug]", "hello-world")
+	q.Set("filters[ref]", "550e8400-e29b-41d4-a716-446655440000")
+	q.Set("filters[day]", "2024-02-29")
+	q.Set("filters[stamp]", "2024-02-29T10:00:00+07:00")
+
+	fp, f := parseConstraintFilters(q)
+	if fp.HasErrors() {
+		t.Fatalf("errors = %v", fp.Errors())
+	}
+	if f.Age == nil || *f.Age != 18 || !reflect.DeepEqual(f.Scores, []int64{0, 55, 100}) || f.Slug == nil {
+		t.Errorf("filters = %+v", f)
+	}
+}
+
+func TestBuiltinConstraintValidators_Errors(t *testing.T) {
+	tests := []struct {
+		key     string
+		value   string
+		wantErr string
+	}{
+		{"filters[age]", "17", "17 harus minimal 18"},
+		{"filters[age]", "100", "100 tidak boleh lebih dari 99"},
+		{"filters[age]", "old", "harus berupa angka: old"},
+		{"filters[scores]", "50,101", "101 tidak boleh lebih dari 100"},
+		{"filters[price]", "0", "0 harus minimal 0.5"},
+		{"filters[code]", "ABC", "ABC harus tepat 4 karakter"},
+		{"filters[name]", "a", "a harus minimal 2 karakter"},
+		{"filters[name]", "robert", "robert tidak boleh melebihi 5 karakter"},
+		{"filters[tags]", "go,rust", "rust tidak boleh melebihi 3 karakter"},
+		{"filters[slug]", "Hello World", "format Hello World tidak valid"},
+		{"filters[ref]", "not-a-uuid", "UUID tidak valid: not-a-uuid"},
+		{"filters[day]", "2024-02-30", "format waktu tidak valid: 2024-02-30"},
+		{"filters[stamp]", "2024-02-29", "format waktu tidak valid: 2024-02-29"},
+		{"filters[invalid]", "x", "pola validasi tidak valid"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key+"="+tt.value, func(t *testing.T) {
+			q := url.Values{}
+			q.Set(tt.key, tt.value)
+			fp, _ := parseConstraintFilters(q)
+			if got := fp.Errors()[tt.key]; !strings.HasPrefix(got, tt.wantErr) {
+				t.Errorf("error = %q, want prefix %q", got, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBuiltinConstraintValidators_InvalidConstraint(t *testing.T) {
+	tests := []struct {
+		validator  ConstraintValidator
+		constraint string
+	}{
+		{&MinConstraintValidator{}, "abc"},
+		{&MaxConstraintValidator{}, ""},
+		{&LenConstraintValidator{}, "a-b"},
+		{&LenConstraintValidator{}, "-"},
+	}
+	for _, tt := range tests {
+		err := tt.validator.Validate([]string{"1"}, tt.constraint, reflect.TypeOf(""))
+		if err == nil || !strings.HasPrefix(err.Error(), "constraint "+tt.validator.Name()+" tidak valid") {
+			t.Errorf("%s:%q error = %v", tt.validator.Name(), tt.constraint, err)
+		}
+	}
+}
+
+func TestRegexConstraintValidator_CachesPattern(t *testing.T) {
+	v := &RegexConstraintValidator{}
+	if err := v.Validate([]string{"abc"}, "^[a-c]+$", nil); err != nil {
+		t.Fatalf("Validate() = %v", err)
+	}
+	first, ok := filterRegexCache.Load("^[a-c]+$")
+	if !ok {
+		t.Fatal("pattern not cached")
+	}
+	v.Validate([]string{"abd"}, "^[a-c]+$", nil)
+	if second, _ := filterRegexCache.Load("^[a-c]+$"); first != second {
+		t.Error("pattern compiled twice")
+	}
+}
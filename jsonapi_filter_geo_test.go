@@ -0,0 +1,100 @@
+package dim
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+type geoFilters struct {
+	Category *string    `filter:"category"`
+	Near     *GeoRadius `filter:"near,max_radius:50"`
+}
+
+func parseGeoFilters(t *testing.T, q url.Values) (*FilterParser, geoFilters) {
+	t.Helper()
+	var f geoFilters
+	fp := NewFilterParser(httptest.NewRequest("GET", "/?"+q.Encode(), nil)).Parse(&f)
+	return fp, f
+}
+
+func TestFilterParser_GeoRadius(t *testing.T) {
+	q := url.Values{}
+	q.Set("filters[near]", "-6.2,106.8,5")
+	q.Set("filters[category]", "cafe")
+
+	fp, f := parseGeoFilters(t, q)
+	if fp.HasErrors() {
+		t.Fatalf("errors = %v", fp.Errors())
+	}
+	if f.Near == nil || f.Near.Center != (GeoPoint{Lat: -6.2, Lng: 106.8}) || f.Near.RadiusKm != 5 {
+		t.Errorf("Near = %+v", f.Near)
+	}
+
+	conditions := fp.Conditions()
+	if len(conditions) != 2 || conditions[1].Op != FilterOpNear || strings.Join(conditions[1].Values, ",") != "-6.2,106.8,5" {
+		t.Errorf("conditions = %v", conditions)
+	}
+}
+
+func TestFilterParser_GeoRadiusErrors(t *testing.T) {
+	tests := []struct {
+		key, value, wantErr string
+	}{
+		{"filters[near]", "-6.2,106.8", "format lokasi tidak valid"},
+		{"filters[near]", "-95,106.8,5", "format lokasi tidak valid"},
+		{"filters[near]", "-6.2,106.8,75", "radius maksimal 50 km"},
+		{"filters[near]", "!-6.2,106.8,5", "format lokasi tidak valid"},
+		{"filters[near][gte]", "5", "tidak diizinkan"},
+	}
+	for _, tt := range tests {
+		q := url.Values{}
+		q.Set(tt.key, tt.value)
+		fp, _ := parseGeoFilters(t, q)
+		if got := fp.Errors()[tt.key]; !strings.Contains(got, tt.wantErr) {
+			t.Errorf("%s=%s: error = %q, want %q", tt.key, tt.value, got, tt.wantErr)
+		}
+	}
+
+	q := url.Values{}
+	q.Set("filters[near][null]", "true")
+	if fp, _ := parseGeoFilters(t, q); fp.HasErrors() || fp.Conditions()[0].Op != FilterOpNull {
+		t.Errorf("null operator: errors = %v", fp.Errors())
+	}
+}
+
+func TestFilterSQLBuilder_WithGeo(t *testing.T) {
+	q := url.Values{}
+	q.Set("filters[category]", "cafe")
+	q.Set("filters[near]", "-6.2,106.8,5")
+	fp, _ := parseGeoFilters(t, q)
+
+	query, err := NewFilterSQLBuilder(map[string]string{"category": "s.category"}).
+		WithGeo("near", GeoColumn{Column: "s.location", Type: GeoColumnPoint}).
+		BuildQuery(fp.Conditions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	distance := "(s.location <@> point($2::float8, $3::float8)) * 1.609344"
+	if query.Where != "s.category = $1 AND "+distance+" <= $4::float8" || query.Distance != distance {
+		t.Errorf("query = %+v", query)
+	}
+	if len(query.Args) != 4 || query.Args[1] != 106.8 || query.Args[3] != 5.0 {
+		t.Errorf("args = %v", query.Args)
+	}
+
+	// Tanpa WithGeo, filter near ditolak
+	if _, err := NewFilterSQLBuilder(map[string]string{"category": "s.category", "near": "s.location"}).BuildQuery(fp.Conditions()); err == nil ||
+		!strings.Contains(err.Error(), "WithGeo") {
+		t.Errorf("BuildQuery() without WithGeo error = %v", err)
+	}
+
+	// Tanpa filter near, Distance kosong
+	query, err = NewFilterSQLBuilder(map[string]string{"category": "s.category"}).
+		WithGeo("near", GeoColumn{Column: "s.location", Type: GeoColumnPostGIS}).
+		BuildQuery([]FilterCondition{{Field: "category", Op: FilterOpEq, Values: []string{"cafe"}}})
+	if err != nil || query.Distance != "" {
+		t.Errorf("query = %+v, %v", query, err)
+	}
+}
@@ -0,0 +1,422 @@
+package dim
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FilterOp is a filter comparison operator.
+type FilterOp string
+
+// Supported filter operators.
+//
+// Query syntax: ?filters[field][op]=value. Plain ?filters[field]=value is equality (or between for Range types,
+// gte/lte for open-ended ranges such as "100," and ",500").
+const (
+	FilterOpEq      FilterOp = "eq"      // equal; multiple values mean IN
+	FilterOpNe      FilterOp = "ne"      // not equal; multiple values mean NOT IN
+	FilterOpNot     FilterOp = "not"     // query alias of ne, also written as filters[field]=!value; conditions use FilterOpNe
+	FilterOpGt      FilterOp = "gt"      // greater than
+	FilterOpGte     FilterOp = "gte"     // greater than or equal
+	FilterOpLt      FilterOp = "lt"      // less than
+	FilterOpLte     FilterOp = "lte"     // less than or equal
+	FilterOpLike    FilterOp = "like"    // substring match; the value is raw, stores add wildcards and escaping
+	FilterOpNull    FilterOp = "null"    // "true" means IS NULL, "false" means IS NOT NULL
+	FilterOpBetween FilterOp = "between" // inclusive range from Range fields; Values is [from, to]
+	FilterOpNear    FilterOp = "near"    // within a radius from *GeoRadius fields; Values is [lat, lng, radius_km]
+)
+
+// filterOpArray is the empty operator of the bracketed array syntax filters[name][]=value.
+const filterOpArray FilterOp = ""
+
+// filterOperatorOrder is the order in which operator conditions are emitted for a field.
+var filterOperatorOrder = []FilterOp{
+	FilterOpEq, FilterOpNe, FilterOpNot, FilterOpGt, FilterOpGte, FilterOpLt, FilterOpLte, FilterOpLike, FilterOpNull,
+}
+
+// FilterCondition is a parsed, type-checked filter condition.
+// Field is the filter name declared in the struct tag (never raw user input) and Op is one of
+// the FilterOp constants, so stores can map both to columns and SQL operators via a whitelist
+// and bind Values as query parameters.
+//
+// Values are normalized strings: numbers as parsed, UUIDs in canonical form, TimestampRange
+// dates as Unix seconds, time.Time values in RFC 3339 (see RFC3339TimeValue), and
+// "true"/"false" for FilterOpNull.
+type FilterCondition struct {
+	Field  string
+	Op     FilterOp
+	Values []string
+}
+
+// Value returns the first value, or an empty string if there is none.
+func (c FilterCondition) Value() string {
+	if len(c.Values) == 0 {
+		return ""
+	}
+	return c.Values[0]
+}
+
+// Relation returns the relation path of a dot-path filter, e.g. "author" for "author.name"
+// and "author.company" for "author.company.name", or an empty string for own fields.
+func (c FilterCondition) Relation() string {
+	if i := strings.LastIndexByte(c.Field, '.'); i >= 0 {
+		return c.Field[:i]
+	}
+	return ""
+}
+
+// Conditions returns the filter conditions parsed by Parse, in struct field order.
+// Only conditions without errors are included.
+//
+// Example:
+//
+//	// ?filters[price][gte]=100&filters[name][like]=jo&filters[status]=active,pending
+//	for _, c := range fp.Conditions() {
+//	    // {price gte [100]}, {name like [jo]}, {status eq [active pending]}
+//	}
+func (fp *FilterParser) Conditions() []FilterCondition {
+	return slices.Clone(fp.conditions)
+}
+
+// operatorValues collects filters[name][op] parameters for a field.
+// Unknown operators are returned too so Parse can report them.
+func operatorValues(query map[string][]string, name string) map[FilterOp][]string {
+	prefix := "filters[" + name + "]["
+	var ops map[FilterOp][]string
+	for key, values := range query {
+		if !strings.HasPrefix(key, prefix) || !strings.HasSuffix(key, "]") {
+			continue
+		}
+		// filters[name][op][]=value is the array form of filters[name][op]=value
+		op := FilterOp(strings.TrimSuffix(key[len(prefix):len(key)-1], "]["))
+		if ops == nil {
+			ops = make(map[FilterOp][]string)
+		}
+		ops[op] = append(ops[op], values...)
+	}
+	return ops
+}
+
+// splitNegatedValues separates values prefixed with "!" (exclusions) from the others.
+// A negated value is split by the delimiter, so "!a,b" on a single-value field excludes a and b.
+func splitNegatedValues(values []string, delimiter string) (plain, negated []string) {
+	for _, v := range values {
+		rest, ok := strings.CutPrefix(strings.TrimSpace(v), "!")
+		if !ok {
+			plain = append(plain, v)
+			continue
+		}
+		for _, part := range splitFilterValues([]string{rest}, delimiter) {
+			negated = append(negated, strings.TrimPrefix(part, "!"))
+		}
+	}
+	return plain, negated
+}
+
+// filterDelimiter returns the separator for multi-value filters: the "delimiter" tag option,
+// then FilterParser.Delimiter, then ",". An empty result ("none") disables splitting.
+func (fp *FilterParser) filterDelimiter(constraints map[string]string) string {
+	delimiter, ok := constraints["delimiter"]
+	if !ok {
+		delimiter = fp.Delimiter
+	}
+	switch delimiter {
+	case "":
+		return ","
+	case "none":
+		return ""
+	}
+	return delimiter
+}
+
+// splitFilterValues splits every value by the delimiter, trimming spaces and dropping empty parts.
+func splitFilterValues(values []string, delimiter string) []string {
+	parts := make([]string, 0, len(values))
+	for _, v := range values {
+		if delimiter == "" {
+			if v = strings.TrimSpace(v); v != "" {
+				parts = append(parts, v)
+			}
+			continue
+		}
+		for _, part := range strings.Split(v, delimiter) {
+			if part = strings.TrimSpace(part); part != "" {
+				parts = append(parts, part)
+			}
+		}
+	}
+	return parts
+}
+
+// sortedOperators returns the operators in a deterministic order: known operators first, then unknown ones.
+func sortedOperators(ops map[FilterOp][]string) []FilterOp {
+	sorted := make([]FilterOp, 0, len(ops))
+	for _, op := range filterOperatorOrder {
+		if _, ok := ops[op]; ok {
+			sorted = append(sorted, op)
+		}
+	}
+	var unknown []FilterOp
+	for op := range ops {
+		if !slices.Contains(filterOperatorOrder, op) {
+			unknown = append(unknown, op)
+		}
+	}
+	slices.Sort(unknown)
+	return append(sorted, unknown...)
+}
+
+// equalityCondition builds the condition for a field bound via filters[name]=value.
+func equalityCondition(name string, field reflect.Value) FilterCondition {
+	if field.Kind() == reflect.Ptr {
+		field = field.Elem()
+	}
+
+	if area, ok := field.Interface().(GeoRadius); ok {
+		return FilterCondition{Field: name, Op: FilterOpNear, Values: strings.Split(area.String(), ",")}
+	}
+
+	if isFilterRangeType(field.Type()) {
+		from, to := field.FieldByName("From"), field.FieldByName("To")
+		switch {
+		case !field.FieldByName("HasTo").Bool():
+			return FilterCondition{Field: name, Op: FilterOpGte, Values: []string{formatFilterValue(from)}}
+		case !field.FieldByName("HasFrom").Bool():
+			return FilterCondition{Field: name, Op: FilterOpLte, Values: []string{formatFilterValue(to)}}
+		}
+		return FilterCondition{
+			Field:  name,
+			Op:     FilterOpBetween,
+			Values: []string{formatFilterValue(from), formatFilterValue(to)},
+		}
+	}
+
+	if field.Kind() == reflect.Slice {
+		values := make([]string, field.Len())
+		for i := range values {
+			values[i] = formatFilterValue(field.Index(i))
+		}
+		return FilterCondition{Field: name, Op: FilterOpEq, Values: values}
+	}
+
+	return FilterCondition{Field: name, Op: FilterOpEq, Values: []string{formatFilterValue(field)}}
+}
+
+func formatFilterValue(v reflect.Value) string {
+	if t, ok := v.Interface().(time.Time); ok {
+		return formatFilterTime(t)
+	}
+	if s, ok := v.Interface().(fmt.Stringer); ok {
+		return s.String()
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// isFilterRangeType reports whether t is an instantiation of Range.
+func isFilterRangeType(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && strings.HasPrefix(t.Name(), "Range[") && t.PkgPath() == reflect.TypeOf(IntRange{}).PkgPath()
+}
+
+// filterValueType returns the scalar type operator values are checked against.
+func filterValueType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	if isFilterRangeType(t) {
+		if field, ok := t.FieldByName("From"); ok {
+			return field.Type
+		}
+	}
+	return t
+}
+
+// defaultFilterOperators returns the operators allowed when the tag has no "ops" constraint.
+func defaultFilterOperators(fieldType reflect.Type) []FilterOp {
+	ops := []FilterOp{FilterOpEq, FilterOpNe, FilterOpNull}
+
+	base := fieldType
+	for base.Kind() == reflect.Ptr || base.Kind() == reflect.Slice {
+		base = base.Elem()
+	}
+	if base == geoRadiusType {
+		return []FilterOp{FilterOpNull}
+	}
+	if isFilterRangeType(base) {
+		return append(ops, FilterOpGt, FilterOpGte, FilterOpLt, FilterOpLte)
+	}
+
+	if filterValueType(fieldType) == timeType {
+		return append(ops, FilterOpGt, FilterOpGte, FilterOpLt, FilterOpLte)
+	}
+
+	switch filterValueType(fieldType).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		ops = append(ops, FilterOpGt, FilterOpGte, FilterOpLt, FilterOpLte)
+	case reflect.String:
+		ops = append(ops, FilterOpLike)
+	}
+	return ops
+}
+
+// allowedFilterOperators reads the "ops" tag constraint (e.g. "ops:gte|lte") or falls back to the defaults.
+func allowedFilterOperators(fieldType reflect.Type, constraints map[string]string) []FilterOp {
+	raw, ok := constraints["ops"]
+	if !ok {
+		return defaultFilterOperators(fieldType)
+	}
+	var ops []FilterOp
+	for _, op := range strings.Split(raw, "|") {
+		if op = strings.TrimSpace(op); op != "" {
+			ops = append(ops, FilterOp(op))
+		}
+	}
+	return ops
+}
+
+// parseOperatorCondition validates the values of filters[name][op] against the field type.
+func (fp *FilterParser) parseOperatorCondition(name string, fieldType reflect.Type, op FilterOp, values []string, constraints map[string]string) (FilterCondition, error) {
+	if !slices.Contains(filterOperatorOrder, op) {
+		return FilterCondition{}, localizedErrorf("operator %s tidak didukung", string(op))
+	}
+	allowed := allowedFilterOperators(fieldType, constraints)
+	if !slices.Contains(allowed, op) && (op != FilterOpNot || !slices.Contains(allowed, FilterOpNe)) {
+		return FilterCondition{}, localizedErrorf("operator %s tidak diizinkan untuk filter ini", string(op))
+	}
+	if op == FilterOpNot {
+		op = FilterOpNe
+	}
+
+	parts := splitFilterValues(values, fp.filterDelimiter(constraints))
+	if len(parts) == 0 {
+		return FilterCondition{}, localizedErrorf("nilai operator %s wajib diisi", string(op))
+	}
+
+	switch op {
+	case FilterOpNull:
+		if len(parts) > 1 {
+			return FilterCondition{}, localizedErrorf("operator %s hanya menerima satu nilai", string(op))
+		}
+		isNull, err := strconv.ParseBool(parts[0])
+		if err != nil {
+			return FilterCondition{}, localizedErrorf("harus berupa true atau false")
+		}
+		return FilterCondition{Field: name, Op: op, Values: []string{strconv.FormatBool(isNull)}}, nil
+
+	case FilterOpLike:
+		// Commas are part of the search term
+		value := strings.TrimSpace(strings.Join(values, ","))
+		return FilterCondition{Field: name, Op: op, Values: []string{value}}, nil
+
+	case FilterOpNe:
+		if fp.MaxValuesPerField > 0 && len(parts) > fp.MaxValuesPerField {
+			return FilterCondition{}, localizedErrorf("maksimal %d nilai diperbolehkan, diterima %d", fp.MaxValuesPerField, len(parts))
+		}
+		if filterValueType(fieldType).Kind() == reflect.String {
+			if err := fp.applyConstraints(parts, constraints, fieldType); err != nil {
+				return FilterCondition{}, err
+			}
+		}
+
+	default:
+		if len(parts) > 1 {
+			return FilterCondition{}, localizedErrorf("operator %s hanya menerima satu nilai", string(op))
+		}
+	}
+
+	normalized := make([]string, len(parts))
+	for i, part := range parts {
+		value, err := fp.normalizeFilterValue(fieldType, part, constraints)
+		if err != nil {
+			return FilterCondition{}, err
+		}
+		normalized[i] = value
+	}
+	return FilterCondition{Field: name, Op: op, Values: normalized}, nil
+}
+
+// normalizeFilterValue checks a single operator value against the field type and returns its canonical form.
+func (fp *FilterParser) normalizeFilterValue(fieldType reflect.Type, value string, constraints map[string]string) (string, error) {
+	if normalized, ok, err := fp.normalizeRegisteredType(fieldType, value); ok {
+		return normalized, err
+	}
+
+	valueType := filterValueType(fieldType)
+
+	if valueType == timeType {
+		t, err := fp.parseFilterTime(value, fp.filterTimeLayouts(constraints))
+		if err != nil {
+			return "", err
+		}
+		return formatFilterTime(t), nil
+	}
+
+	if typeMatches(valueType, reflect.TypeOf(UUID{})) {
+		parsed, err := ParseUuid(value)
+		if err != nil {
+			return "", localizedErrorf("UUID tidak valid: %s", value)
+		}
+		return parsed.String(), nil
+	}
+
+	base := fieldType
+	for base.Kind() == reflect.Ptr || base.Kind() == reflect.Slice {
+		base = base.Elem()
+	}
+
+	switch {
+	case typeMatches(base, reflect.TypeOf(DateRange{})):
+		if !IsValidDateFormat(value) {
+			return "", localizedErrorf("format tanggal tidak valid (gunakan YYYY-MM-DD)")
+		}
+		return value, nil
+
+	case typeMatches(base, reflect.TypeOf(TimestampRange{})):
+		// TimestampRange and IntRange share a type; accept both dates and integers
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return strconv.FormatInt(n, 10), nil
+		}
+		tz := fp.TimestampTimezone
+		if tz == nil {
+			tz = time.UTC
+		}
+		t, err := time.ParseInLocation("2006-01-02", value, tz)
+		if err != nil {
+			return "", localizedErrorf("harus berupa angka atau tanggal (YYYY-MM-DD): %s", value)
+		}
+		return strconv.FormatInt(t.Unix(), 10), nil
+	}
+
+	switch valueType.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return "", localizedErrorf("harus berupa angka: %s", value)
+		}
+		return strconv.FormatInt(n, 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return "", localizedErrorf("harus berupa angka: %s", value)
+		}
+		return strconv.FormatUint(n, 10), nil
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return "", localizedErrorf("harus berupa angka desimal: %s", value)
+		}
+		return strconv.FormatFloat(n, 'f', -1, 64), nil
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return "", localizedErrorf("harus berupa true atau false")
+		}
+		return strconv.FormatBool(b), nil
+	}
+	return value, nil
+}
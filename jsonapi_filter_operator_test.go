@@ -0,0 +1,227 @@
+package dim
+
+import (
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+type operatorTestFilters struct {
+	Price     *int64      `filter:"price"`
+	Name      *string     `filter:"name"`
+	Status    []string    `filter:"status,in:active|pending"`
+	Amount    AmountRange `filter:"amount,ops:gte|lte"`
+	CreatedAt DateRange   `filter:"created_at"`
+	Active    *bool       `filter:"active"`
+	OwnerID   *UUID       `filter:"owner_id"`
+}
+
+func parseOperatorFilters(t *testing.T, q url.Values) (*FilterParser, operatorTestFilters) {
+	t.Helper()
+	req, _ := http.NewRequest("GET", "http://example.com?"+q.Encode(), nil)
+	var filters operatorTestFilters
+	return NewFilterParser(req).Parse(&filters), filters
+}
+
+// TestFilterConditions_Operators tests operator syntax and condition output
+func TestFilterConditions_Operators(t *testing.T) {
+	q := url.Values{}
+	q.Set("filters[price][gte]", "100")
+	q.Set("filters[price][lt]", "500")
+	q.Set("filters[name][like]", "jo")
+	q.Set("filters[status]", "active,pending")
+	q.Set("filters[status][ne]", "pending")
+	q.Set("filters[amount]", "10.5,20")
+	q.Set("filters[created_at][null]", "1")
+
+	fp, filters := parseOperatorFilters(t, q)
+	if fp.HasErrors() {
+		t.Fatalf("unexpected errors: %v", fp.Errors())
+	}
+
+	want := []FilterCondition{
+		{Field: "price", Op: FilterOpGte, Values: []string{"100"}},
+		{Field: "price", Op: FilterOpLt, Values: []string{"500"}},
+		{Field: "name", Op: FilterOpLike, Values: []string{"jo"}},
+		{Field: "status", Op: FilterOpEq, Values: []string{"active", "pending"}},
+		{Field: "status", Op: FilterOpNe, Values: []string{"pending"}},
+		{Field: "amount", Op: FilterOpBetween, Values: []string{"10.5", "20"}},
+		{Field: "created_at", Op: FilterOpNull, Values: []string{"true"}},
+	}
+	if got := fp.Conditions(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Conditions() =\n%v\nwant\n%v", got, want)
+	}
+
+	// Operator values are not bound to the struct
+	if filters.Price != nil || filters.Name != nil {
+		t.Errorf("operator filters should not bind struct fields, got price=%v name=%v", filters.Price, filters.Name)
+	}
+	if !reflect.DeepEqual(filters.Status, []string{"active", "pending"}) {
+		t.Errorf("Status = %v", filters.Status)
+	}
+}
+
+// TestFilterConditions_EqOperatorBindsStruct tests that [eq] behaves like plain equality
+func TestFilterConditions_EqOperatorBindsStruct(t *testing.T) {
+	q := url.Values{}
+	q.Set("filters[price][eq]", "42")
+
+	fp, filters := parseOperatorFilters(t, q)
+	if fp.HasErrors() {
+		t.Fatalf("unexpected errors: %v", fp.Errors())
+	}
+	if filters.Price == nil || *filters.Price != 42 {
+		t.Errorf("Price = %v, want 42", filters.Price)
+	}
+
+	want := []FilterCondition{{Field: "price", Op: FilterOpEq, Values: []string{"42"}}}
+	if got := fp.Conditions(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Conditions() = %v, want %v", got, want)
+	}
+}
+
+// TestFilterConditions_NormalizesValues tests type normalization of operator values
+func TestFilterConditions_NormalizesValues(t *testing.T) {
+	id := NewUuid()
+	q := url.Values{}
+	q.Set("filters[price][gt]", "007")
+	q.Set("filters[owner_id][ne]", id.String())
+	q.Set("filters[active][ne]", "1")
+
+	fp, _ := parseOperatorFilters(t, q)
+	if fp.HasErrors() {
+		t.Fatalf("unexpected errors: %v", fp.Errors())
+	}
+
+	want := []FilterCondition{
+		{Field: "price", Op: FilterOpGt, Values: []string{"7"}},
+		{Field: "active", Op: FilterOpNe, Values: []string{"true"}},
+		{Field: "owner_id", Op: FilterOpNe, Values: []string{id.String()}},
+	}
+	if got := fp.Conditions(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Conditions() = %v, want %v", got, want)
+	}
+}
+
+// TestFilterConditions_Errors tests invalid operator usage
+func TestFilterConditions_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		value   string
+		wantErr string
+	}{
+		{"unknown operator", "filters[price][foo]", "1", "operator foo tidak didukung"},
+		{"like on number", "filters[price][like]", "1", "operator like tidak diizinkan untuk filter ini"},
+		{"restricted by ops tag", "filters[amount][gt]", "1", "operator gt tidak diizinkan untuk filter ini"},
+		{"invalid number", "filters[price][gte]", "abc", "harus berupa angka: abc"},
+		{"multiple values for comparison", "filters[price][gt]", "1,2", "operator gt hanya menerima satu nilai"},
+		{"invalid null value", "filters[name][null]", "maybe", "harus berupa true atau false"},
+		{"invalid date", "filters[created_at][lt]", "2024/01/01", "format tanggal tidak valid (gunakan YYYY-MM-DD)"},
+		{"empty value", "filters[price][gt]", "", "nilai operator gt wajib diisi"},
+		{"ne respects in constraint", "filters[status][ne]", "deleted", "nilai tidak valid: deleted (diizinkan: "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := url.Values{}
+			q.Set(tt.key, tt.value)
+
+			fp, _ := parseOperatorFilters(t, q)
+			got := fp.Errors()[tt.key]
+			if len(got) < len(tt.wantErr) || got[:len(tt.wantErr)] != tt.wantErr {
+				t.Errorf("error for %s = %q, want prefix %q", tt.key, got, tt.wantErr)
+			}
+			if len(fp.Conditions()) != 0 {
+				t.Errorf("expected no conditions, got %v", fp.Conditions())
+			}
+		})
+	}
+}
+
+// TestFilterConditions_TimestampRange tests date values on TimestampRange operators
+func TestFilterConditions_TimestampRange(t *testing.T) {
+	type Filters struct {
+		CreatedAt TimestampRange `filter:"created_at"`
+	}
+
+	q := url.Values{}
+	q.Set("filters[created_at][gte]", "2024-01-15")
+	req, _ := http.NewRequest("GET", "http://example.com?"+q.Encode(), nil)
+
+	var filters Filters
+	fp := NewFilterParser(req).Parse(&filters)
+	if fp.HasErrors() {
+		t.Fatalf("unexpected errors: %v", fp.Errors())
+	}
+
+	want := []FilterCondition{{Field: "created_at", Op: FilterOpGte, Values: []string{"1705276800"}}}
+	if got := fp.Conditions(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Conditions() = %v, want %v", got, want)
+	}
+}
+
+// TestFilterConditions_Negation tests filters[field][not] and the "!" prefix
+func TestFilterConditions_Negation(t *testing.T) {
+	q := url.Values{}
+	q.Set("filters[price][not]", "5,6")
+	q.Set("filters[name]", "!bob,carol")
+	q["filters[status]"] = []string{"active,!pending"}
+	q["filters[status][]"] = []string{"!active"}
+
+	fp, filters := parseOperatorFilters(t, q)
+	if fp.HasErrors() {
+		t.Fatalf("unexpected errors: %v", fp.Errors())
+	}
+	if filters.Name != nil || !reflect.DeepEqual(filters.Status, []string{"active"}) {
+		t.Errorf("negated values should not bind, got name=%v status=%v", filters.Name, filters.Status)
+	}
+
+	want := []FilterCondition{
+		{Field: "price", Op: FilterOpNe, Values: []string{"5", "6"}},
+		{Field: "name", Op: FilterOpNe, Values: []string{"bob", "carol"}},
+		{Field: "status", Op: FilterOpEq, Values: []string{"active"}},
+		{Field: "status", Op: FilterOpNe, Values: []string{"pending", "active"}},
+	}
+	if got := fp.Conditions(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Conditions() =\n%v\nwant\n%v", got, want)
+	}
+}
+
+// TestFilterConditions_NegationEdgeCases tests literal "!" via eq, ranges, and errors
+func TestFilterConditions_NegationEdgeCases(t *testing.T) {
+	q := url.Values{}
+	q.Set("filters[name][eq]", "!important")
+	fp, filters := parseOperatorFilters(t, q)
+	if fp.HasErrors() || filters.Name == nil || *filters.Name != "!important" {
+		t.Errorf("eq should match literally: errors = %v, name = %v", fp.Errors(), filters.Name)
+	}
+
+	// Range values are never negated
+	q = url.Values{}
+	q.Set("filters[amount]", "!10,20")
+	fp, _ = parseOperatorFilters(t, q)
+	if fp.Errors()["filters[amount]"] == "" {
+		t.Errorf("expected range error, got %v", fp.Conditions())
+	}
+
+	tests := []struct {
+		key     string
+		value   string
+		errKey  string
+		wantErr string
+	}{
+		{"filters[status]", "!deleted", "filters[status][not]", "nilai tidak valid: deleted (diizinkan: "},
+		{"filters[price]", "!abc", "filters[price][not]", "harus berupa angka: abc"},
+		{"filters[amount][not]", "10", "filters[amount][not]", "operator not tidak diizinkan untuk filter ini"},
+	}
+	for _, tt := range tests {
+		q := url.Values{}
+		q.Set(tt.key, tt.value)
+		fp, _ := parseOperatorFilters(t, q)
+		if got := fp.Errors()[tt.errKey]; len(got) < len(tt.wantErr) || got[:len(tt.wantErr)] != tt.wantErr {
+			t.Errorf("%s=%s: error = %q, want prefix %q (all: %v)", tt.key, tt.value, got, tt.wantErr, fp.Errors())
+		}
+	}
+}
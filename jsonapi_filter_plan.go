@@ -0,0 +1,257 @@
+package dim
+
+import (
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// filterValueKind is the parser selected for a filter field when its plan is compiled.
+type filterValueKind int
+
+const (
+	filterKindUnsupported filterValueKind = iota
+	filterKindDateRange
+	filterKindAmountRange
+	filterKindIntRange
+	filterKindTimestampRange
+	filterKindUUID
+	filterKindString
+	filterKindInt
+	filterKindInt64
+	filterKindFloat64
+	filterKindBool
+	filterKindTime
+	filterKindTimeRange
+	filterKindGeoRadius
+)
+
+// filterField is the precomputed parse information of one tagged struct field.
+// Constraints are shared between requests and must be treated as read-only.
+type filterField struct {
+	index       []int  // field index path, longer than one for fields of relation structs
+	name        string // filter name from the tag, e.g. "status" or "author.name"
+	key         string // query key, e.g. "filters[status]"
+	structField reflect.StructField
+	constraints map[string]string
+	container   reflect.Kind // reflect.Ptr, reflect.Slice or reflect.Struct (non-pointer range)
+	kind        filterValueKind
+	namedString bool // slice element is a named string type, e.g. []Status
+	isRange     bool // Range type (pointer or not); values are "from,to" and never negated
+	isGeo       bool // *GeoRadius; the value is "lat,lng,radius_km" and never split or negated
+}
+
+// filterPlan is the compiled parse plan of a filter struct type.
+type filterPlan struct {
+	fields []filterField
+}
+
+// filterPlans caches compiled plans by struct type, so the tag parsing and type
+// matching is done once per type instead of on every request.
+var filterPlans sync.Map // reflect.Type -> *filterPlan
+
+// filterPlanFor returns the cached parse plan for a struct type, compiling it on first use.
+func filterPlanFor(t reflect.Type) *filterPlan {
+	if plan, ok := filterPlans.Load(t); ok {
+		return plan.(*filterPlan)
+	}
+	plan, _ := filterPlans.LoadOrStore(t, compileFilterPlan(t))
+	return plan.(*filterPlan)
+}
+
+// compileFilterPlan walks the struct fields once and resolves the tag, constraints,
+// and value parser of every settable field with a "filter" tag.
+func compileFilterPlan(t reflect.Type) *filterPlan {
+	plan := &filterPlan{}
+	compileFilterFields(plan, t, "", nil)
+	return plan
+}
+
+// compileFilterFields appends the filter fields of t to the plan. Fields of relation
+// structs are added with the relation name as prefix, e.g. "author.name".
+func compileFilterFields(plan *filterPlan, t reflect.Type, prefix string, index []int) {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		filterTag := sf.Tag.Get("filter")
+		if filterTag == "" || filterTag == "-" {
+			continue
+		}
+
+		// Parse filter tag: "fieldName" or "fieldName,in:val1|val2" or "fieldName,constraint1:val,constraint2:val"
+		parts := strings.Split(filterTag, ",")
+		fieldName := strings.TrimSpace(parts[0])
+		if fieldName == "" {
+			continue
+		}
+		fieldName = prefix + fieldName
+		fieldIndex := append(slices.Clone(index), i)
+
+		// Struct fields whose own fields have filter tags group the filters of a relation
+		if isFilterRelationStruct(sf.Type) {
+			compileFilterFields(plan, sf.Type, fieldName+".", fieldIndex)
+			continue
+		}
+
+		// Extract constraints (e.g., "in:active|pending,min:1,uuid" becomes map{in: "active|pending", min: "1", uuid: ""})
+		constraints := make(map[string]string)
+		for _, part := range parts[1:] {
+			part = strings.TrimSpace(part)
+			if idx := strings.Index(part, ":"); idx > 0 {
+				key := part[:idx]
+				value := strings.TrimSpace(part[idx+1:])
+				if value != "" {
+					constraints[key] = value
+				}
+			} else if part != "" && !strings.Contains(part, ":") {
+				// Flag constraint without a value, e.g. "uuid" or "date"
+				constraints[part] = ""
+			}
+		}
+
+		field := filterField{
+			index:       fieldIndex,
+			name:        fieldName,
+			key:         "filters[" + fieldName + "]",
+			structField: sf,
+			constraints: constraints,
+			container:   sf.Type.Kind(),
+		}
+		field.kind, field.namedString = classifyFilterField(sf.Type)
+		field.isGeo = field.kind == filterKindGeoRadius
+		if base := sf.Type; base.Kind() == reflect.Ptr {
+			field.isRange = isFilterRangeType(base.Elem())
+		} else {
+			field.isRange = isFilterRangeType(base)
+		}
+		plan.fields = append(plan.fields, field)
+	}
+}
+
+// isFilterRelationStruct reports whether t is a struct (not a Range or time.Time) with at
+// least one exported field that has a "filter" tag.
+func isFilterRelationStruct(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct || t == timeType || t == geoRadiusType || isFilterRangeType(t) {
+		return false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		if sf := t.Field(i); sf.IsExported() && sf.Tag.Get("filter") != "" && sf.Tag.Get("filter") != "-" {
+			return true
+		}
+	}
+	return false
+}
+
+// classifyFilterField selects the value parser for a field type.
+// IntRange and TimestampRange are the same type (Range[int64]); like before the plan
+// cache, a non-pointer Range[int64] is parsed as a timestamp range and a pointer as an
+// integer range.
+func classifyFilterField(t reflect.Type) (filterValueKind, bool) {
+	switch t.Kind() {
+	case reflect.Slice:
+		elem := t.Elem()
+		switch elem {
+		case reflect.TypeOf(UUID{}):
+			return filterKindUUID, false
+		case timeType:
+			return filterKindTime, false
+		}
+		switch elem.Kind() {
+		case reflect.String:
+			return filterKindString, elem != reflect.TypeOf("")
+		case reflect.Int64:
+			return filterKindInt64, false
+		case reflect.Int:
+			return filterKindInt, false
+		case reflect.Float64:
+			return filterKindFloat64, false
+		}
+
+	case reflect.Ptr:
+		switch elem := t.Elem(); elem {
+		case reflect.TypeOf(DateRange{}):
+			return filterKindDateRange, false
+		case reflect.TypeOf(AmountRange{}):
+			return filterKindAmountRange, false
+		case reflect.TypeOf(IntRange{}):
+			return filterKindIntRange, false
+		case reflect.TypeOf(UUID{}):
+			return filterKindUUID, false
+		case reflect.TypeOf(TimeRange{}):
+			return filterKindTimeRange, false
+		case reflect.TypeOf(GeoRadius{}):
+			return filterKindGeoRadius, false
+		case timeType:
+			return filterKindTime, false
+		default:
+			switch elem.Kind() {
+			case reflect.String:
+				return filterKindString, false
+			case reflect.Int:
+				return filterKindInt, false
+			case reflect.Int64:
+				return filterKindInt64, false
+			case reflect.Bool:
+				return filterKindBool, false
+			}
+		}
+
+	default:
+		switch t {
+		case reflect.TypeOf(DateRange{}):
+			return filterKindDateRange, false
+		case reflect.TypeOf(AmountRange{}):
+			return filterKindAmountRange, false
+		case reflect.TypeOf(TimestampRange{}):
+			return filterKindTimestampRange, false
+		case reflect.TypeOf(TimeRange{}):
+			return filterKindTimeRange, false
+		case timeType:
+			return filterKindTime, false
+		}
+	}
+
+	return filterKindUnsupported, false
+}
+
+// ParseInto parses the filter parameters of the request into a new T.
+// T must be a struct with "filter" tags. The parse plan of T is compiled on the first
+// call and cached, so hot endpoints do not walk the struct fields on every request.
+// The returned errors are nil when parsing succeeded; keys follow "filters[fieldName]".
+//
+// Example:
+//
+//	type UserFilters struct {
+//	    Status *string  `filter:"status,in:active|inactive"`
+//	    IDs    []int64  `filter:"ids"`
+//	}
+//
+//	filters, errs := dim.ParseInto[UserFilters](r)
+//	if errs != nil {
+//	    // errs["filters[status]"] = "nilai tidak valid: ..."
+//	    return
+//	}
+func ParseInto[T any](r *http.Request) (T, map[string]string) {
+	return ParseWith[T](NewFilterParser(r))
+}
+
+// ParseWith is like ParseInto but uses a configured FilterParser, e.g. with
+// WithMaxValues, WithTimezone or custom constraint validators.
+// fp.Conditions() remains available after the call.
+//
+// Example:
+//
+//	fp := dim.NewFilterParser(r).WithMaxValues(50)
+//	filters, errs := dim.ParseWith[UserFilters](fp)
+func ParseWith[T any](fp *FilterParser) (T, map[string]string) {
+	var target T
+	if fp.Parse(&target).HasErrors() {
+		return target, fp.Errors()
+	}
+	return target, nil
+}
@@ -0,0 +1,113 @@
+package dim
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+type planTestFilters struct {
+	Status    *string        `filter:"status,in:active|inactive"`
+	IDs       []int64        `filter:"ids"`
+	Price     *IntRange      `filter:"price"`
+	CreatedAt TimestampRange `filter:"created_at"`
+	Ignored   string
+	internal  *string `filter:"internal"`
+}
+
+func TestParseInto(t *testing.T) {
+	req := httptest.NewRequest("GET", "/?filters[status]=active&filters[ids]=1,2&filters[price]=10,20", nil)
+
+	filters, errs := ParseInto[planTestFilters](req)
+	if errs != nil {
+		t.Fatalf("ParseInto() errors = %v", errs)
+	}
+	if filters.Status == nil || *filters.Status != "active" {
+		t.Errorf("Status = %v", filters.Status)
+	}
+	if !reflect.DeepEqual(filters.IDs, []int64{1, 2}) {
+		t.Errorf("IDs = %v", filters.IDs)
+	}
+	if filters.Price == nil || filters.Price.From != 10 || filters.Price.To != 20 {
+		t.Errorf("Price = %+v", filters.Price)
+	}
+	if filters.internal != nil {
+		t.Error("unexported field must not be set")
+	}
+}
+
+func TestParseInto_Errors(t *testing.T) {
+	req := httptest.NewRequest("GET", "/?filters[status]=deleted&filters[ids]=x", nil)
+
+	_, errs := ParseInto[planTestFilters](req)
+	if len(errs) != 2 || errs["filters[status]"] == "" || errs["filters[ids]"] == "" {
+		t.Errorf("errors = %v", errs)
+	}
+}
+
+func TestParseWith(t *testing.T) {
+	req := httptest.NewRequest("GET", "/?filters[ids]=1,2,3&filters[price][gte]=5", nil)
+
+	fp := NewFilterParser(req).WithMaxValues(2)
+	_, errs := ParseWith[planTestFilters](fp)
+	if errs["filters[ids]"] == "" {
+		t.Errorf("expected max values error, got %v", errs)
+	}
+
+	fp = NewFilterParser(req)
+	if _, errs := ParseWith[planTestFilters](fp); errs != nil {
+		t.Fatalf("errors = %v", errs)
+	}
+	if len(fp.Conditions()) != 2 {
+		t.Errorf("Conditions() = %v", fp.Conditions())
+	}
+}
+
+func TestFilterPlanFor_Cached(t *testing.T) {
+	typ := reflect.TypeOf(planTestFilters{})
+	plan := filterPlanFor(typ)
+	if filterPlanFor(typ) != plan {
+		t.Error("plan should be cached per type")
+	}
+
+	names := make([]string, len(plan.fields))
+	for i, f := range plan.fields {
+		names[i] = f.name
+	}
+	if !reflect.DeepEqual(names, []string{"status", "ids", "price", "created_at"}) {
+		t.Errorf("plan fields = %v", names)
+	}
+	if plan.fields[0].constraints["in"] != "active|inactive" {
+		t.Errorf("constraints = %v", plan.fields[0].constraints)
+	}
+	// Range[int64] keeps its historical meaning: pointer = IntRange, value = TimestampRange
+	if plan.fields[2].kind != filterKindIntRange || plan.fields[3].kind != filterKindTimestampRange {
+		t.Errorf("range kinds = %v, %v", plan.fields[2].kind, plan.fields[3].kind)
+	}
+}
+
+func TestParseInto_Concurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req := httptest.NewRequest("GET", "/?filters[status]=inactive", nil)
+			filters, errs := ParseInto[planTestFilters](req)
+			if errs != nil || filters.Status == nil || *filters.Status != "inactive" {
+				t.Errorf("ParseInto() = %v, %v", filters.Status, errs)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func BenchmarkParseInto(b *testing.B) {
+	req := httptest.NewRequest("GET", "/?filters[status]=active&filters[ids]=1,2,3&filters[price]=10,20", nil)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = ParseInto[planTestFilters](req)
+	}
+}
@@ -0,0 +1,390 @@
+package dim
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// FilterPresetKey adalah query key untuk memakai preset, misal ?filters[preset]=my-open-tickets.
+// Saat FilterParser.WithPresets aktif, "preset" tidak dapat dipakai sebagai nama filter.
+const FilterPresetKey = "filters[preset]"
+
+var (
+	// ErrFilterPresetNotFound dikembalikan store jika preset tidak ditemukan.
+	ErrFilterPresetNotFound = errors.New("filter preset not found")
+	// ErrFilterPresetExists dikembalikan store jika user sudah memiliki preset dengan nama yang sama.
+	ErrFilterPresetExists = errors.New("filter preset already exists")
+)
+
+// filterPresetName membatasi nama preset agar aman dipakai di URL dan query string.
+var filterPresetName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]{0,99}$`)
+
+// FilterPreset adalah kumpulan filter bernama milik user untuk satu resource (list view),
+// sehingga tampilan list yang kompleks dapat di-bookmark.
+//
+// Filters memakai bentuk kanonik: key adalah query key tanpa awalan "filters", misal
+// "status" untuk filters[status] dan "price[gte]" untuk filters[price][gte].
+type FilterPreset struct {
+	ID        int64               `json:"id"`
+	UserID    string              `json:"user_id"`
+	Resource  string              `json:"resource"`
+	Name      string              `json:"name"`
+	Filters   map[string][]string `json:"filters"`
+	CreatedAt time.Time           `json:"created_at"`
+	UpdatedAt time.Time           `json:"updated_at"`
+}
+
+// Query mengembalikan filter preset sebagai query parameter, misal filters[price][gte]=100.
+func (p *FilterPreset) Query() url.Values {
+	query := make(url.Values, len(p.Filters))
+	for key, values := range p.Filters {
+		query[filterQueryKey(key)] = append([]string(nil), values...)
+	}
+	return query
+}
+
+// FilterPresetStore menyimpan preset filter per user dan resource.
+type FilterPresetStore interface {
+	// CreatePreset menyimpan preset baru; ErrFilterPresetExists jika nama sudah dipakai.
+	CreatePreset(ctx context.Context, preset *FilterPreset) error
+	// UpdatePreset mengganti filter preset; ErrFilterPresetNotFound jika tidak ada.
+	UpdatePreset(ctx context.Context, preset *FilterPreset) error
+	// FindPreset mencari preset berdasarkan user, resource, dan nama.
+	FindPreset(ctx context.Context, userID, resource, name string) (*FilterPreset, error)
+	// ListPresets mengembalikan preset user untuk resource, diurutkan berdasarkan nama.
+	ListPresets(ctx context.Context, userID, resource string) ([]*FilterPreset, error)
+	// DeletePreset menghapus preset; ErrFilterPresetNotFound jika tidak ada.
+	DeletePreset(ctx context.Context, userID, resource, name string) error
+}
+
+// CanonicalFilters mengambil parameter filters[...] dari query menjadi bentuk kanonik
+// FilterPreset.Filters. Parameter lain, nilai kosong, dan filters[preset] diabaikan.
+//
+// Example:
+//
+//	// ?filters[status]=open&filters[price][gte]=100&sort=-created_at
+//	dim.CanonicalFilters(r.URL.Query())
+//	// map[price[gte]:[100] status:[open]]
+func CanonicalFilters(query url.Values) map[string][]string {
+	filters := make(map[string][]string)
+	for key, values := range query {
+		if key == FilterPresetKey {
+			continue
+		}
+		canonical, ok := canonicalFilterKey(key)
+		if !ok {
+			continue
+		}
+		for _, value := range values {
+			if value != "" {
+				filters[canonical] = append(filters[canonical], value)
+			}
+		}
+	}
+	return filters
+}
+
+// canonicalFilterKey mengubah "filters[price][gte]" menjadi "price[gte]".
+func canonicalFilterKey(key string) (string, bool) {
+	rest, ok := strings.CutPrefix(key, "filters[")
+	if !ok {
+		return "", false
+	}
+	name, suffix, ok := strings.Cut(rest, "]")
+	if !ok || name == "" {
+		return "", false
+	}
+	return name + suffix, true
+}
+
+// filterQueryKey mengubah key kanonik "price[gte]" menjadi "filters[price][gte]".
+func filterQueryKey(canonical string) string {
+	name, suffix, _ := strings.Cut(canonical, "[")
+	if suffix != "" {
+		suffix = "[" + suffix
+	}
+	return "filters[" + name + "]" + suffix
+}
+
+// WithPresets mengaktifkan ?filters[preset]=name. Preset milik user yang sedang login (GetUser)
+// untuk resource dimuat dari store dan digabung dengan filter ad-hoc sebelum parsing; jika
+// query key yang sama ada di keduanya, nilai ad-hoc yang dipakai. Nilai preset divalidasi
+// seperti filter biasa. Preset yang tidak ditemukan dilaporkan di Errors() dengan key
+// "filters[preset]".
+// Returns the receiver for method chaining.
+//
+// Example:
+//
+//	// ?filters[preset]=my-open-tickets&filters[priority]=high
+//	fp := dim.NewFilterParser(r).WithPresets(presetStore, "tickets")
+//	filters, errs := dim.ParseWith[TicketFilters](fp)
+func (fp *FilterParser) WithPresets(store FilterPresetStore, resource string) *FilterParser {
+	fp.presetStore = store
+	fp.presetResource = resource
+	return fp
+}
+
+// Preset mengembalikan preset yang dipakai pada Parse terakhir, atau nil jika tidak ada.
+func (fp *FilterParser) Preset() *FilterPreset {
+	return fp.preset
+}
+
+// filterQuery mengembalikan query request, dengan preset yang sudah diekspansi jika WithPresets aktif.
+func (fp *FilterParser) filterQuery(locale string) url.Values {
+	query := fp.request.URL.Query()
+	if fp.presetStore == nil {
+		return query
+	}
+	name := query.Get(FilterPresetKey)
+	query.Del(FilterPresetKey)
+	if name == "" {
+		return query
+	}
+
+	preset, err := fp.loadPreset(name)
+	if err != nil {
+		fp.errors[FilterPresetKey] = translateError(locale, err)
+		return query
+	}
+	fp.preset = preset
+
+	merged := preset.Query()
+	for key, values := range query {
+		merged[key] = values
+	}
+	return merged
+}
+
+func (fp *FilterParser) loadPreset(name string) (*FilterPreset, error) {
+	user, ok := GetUser(fp.request)
+	if !ok {
+		return nil, localizedErrorf("preset filter tidak ditemukan: %s", name)
+	}
+	preset, err := fp.presetStore.FindPreset(fp.request.Context(), user.GetID(), fp.presetResource, name)
+	if errors.Is(err, ErrFilterPresetNotFound) {
+		return nil, localizedErrorf("preset filter tidak ditemukan: %s", name)
+	}
+	if err != nil {
+		return nil, localizedErrorf("preset filter gagal dimuat")
+	}
+	return preset, nil
+}
+
+// FilterPresetHandler menyediakan endpoint CRUD preset filter milik user yang sedang login
+// untuk satu resource. Pasang middleware autentikasi pada group.
+type FilterPresetHandler struct {
+	store    FilterPresetStore
+	resource string
+	validate func(query url.Values) map[string]string
+}
+
+// NewFilterPresetHandler membuat handler preset filter untuk resource.
+//
+// Parameters:
+//   - store: penyimpanan preset
+//   - resource: nama list view, misal "tickets" (harus sama dengan FilterParser.WithPresets)
+//
+// Returns:
+//   - *FilterPresetHandler: handler yang siap didaftarkan ke router
+//
+// Example:
+//
+//	presets := dim.NewFilterPresetHandler(presetStore, "tickets").
+//	    WithValidator(dim.ValidateFilterQuery[TicketFilters])
+//	presets.Register(router.Group("/tickets/presets", dim.RequireAuth(tokenManager, blocklist)))
+func NewFilterPresetHandler(store FilterPresetStore, resource string) *FilterPresetHandler {
+	return &FilterPresetHandler{store: store, resource: resource}
+}
+
+// WithValidator memvalidasi filter sebelum preset disimpan. Fungsi menerima filter sebagai
+// query parameter dan mengembalikan error per key (nil jika valid), misal ValidateFilterQuery[T].
+func (h *FilterPresetHandler) WithValidator(fn func(query url.Values) map[string]string) *FilterPresetHandler {
+	h.validate = fn
+	return h
+}
+
+// Register mendaftarkan endpoint preset ke dalam group.
+//
+// Endpoint:
+//   - GET / : daftar preset user
+//   - POST / : buat preset, body {"name": "my-open-tickets", "filters": {"status": ["open"]}}
+//   - GET /{name} : detail preset
+//   - PUT /{name} : ganti filter preset, body {"filters": {...}}
+//   - DELETE /{name} : hapus preset
+func (h *FilterPresetHandler) Register(rg *RouterGroup) {
+	rg.Get("/", h.list)
+	rg.Post("/", h.create)
+	rg.Get("/{name}", h.get)
+	rg.Put("/{name}", h.update)
+	rg.Delete("/{name}", h.delete)
+}
+
+// filterPresetRequest adalah body POST/PUT preset.
+type filterPresetRequest struct {
+	Name    string              `json:"name"`
+	Filters map[string][]string `json:"filters"`
+}
+
+func (h *FilterPresetHandler) list(w http.ResponseWriter, r *http.Request) {
+	user, ok := GetUser(r)
+	if !ok {
+		Unauthorized(w, "Tidak authorized")
+		return
+	}
+	presets, err := h.store.ListPresets(r.Context(), user.GetID(), h.resource)
+	if err != nil {
+		InternalServerError(w, "Gagal memuat preset filter")
+		return
+	}
+	if presets == nil {
+		presets = []*FilterPreset{}
+	}
+	OK(w, presets)
+}
+
+func (h *FilterPresetHandler) get(w http.ResponseWriter, r *http.Request) {
+	user, ok := GetUser(r)
+	if !ok {
+		Unauthorized(w, "Tidak authorized")
+		return
+	}
+	preset, err := h.store.FindPreset(r.Context(), user.GetID(), h.resource, GetParam(r, "name"))
+	if err != nil {
+		h.storeError(w, err)
+		return
+	}
+	OK(w, preset)
+}
+
+func (h *FilterPresetHandler) create(w http.ResponseWriter, r *http.Request) {
+	user, ok := GetUser(r)
+	if !ok {
+		Unauthorized(w, "Tidak authorized")
+		return
+	}
+	req, ok := h.decode(w, r, true)
+	if !ok {
+		return
+	}
+
+	preset := &FilterPreset{UserID: user.GetID(), Resource: h.resource, Name: req.Name, Filters: req.Filters}
+	if err := h.store.CreatePreset(r.Context(), preset); err != nil {
+		h.storeError(w, err)
+		return
+	}
+	Created(w, preset)
+}
+
+func (h *FilterPresetHandler) update(w http.ResponseWriter, r *http.Request) {
+	user, ok := GetUser(r)
+	if !ok {
+		Unauthorized(w, "Tidak authorized")
+		return
+	}
+	req, ok := h.decode(w, r, false)
+	if !ok {
+		return
+	}
+
+	preset := &FilterPreset{UserID: user.GetID(), Resource: h.resource, Name: GetParam(r, "name"), Filters: req.Filters}
+	if err := h.store.UpdatePreset(r.Context(), preset); err != nil {
+		h.storeError(w, err)
+		return
+	}
+	OK(w, preset)
+}
+
+func (h *FilterPresetHandler) delete(w http.ResponseWriter, r *http.Request) {
+	user, ok := GetUser(r)
+	if !ok {
+		Unauthorized(w, "Tidak authorized")
+		return
+	}
+	if err := h.store.DeletePreset(r.Context(), user.GetID(), h.resource, GetParam(r, "name")); err != nil {
+		h.storeError(w, err)
+		return
+	}
+	NoContent(w)
+}
+
+// decode membaca dan memvalidasi body; nama hanya diperiksa saat membuat preset.
+func (h *FilterPresetHandler) decode(w http.ResponseWriter, r *http.Request, withName bool) (*filterPresetRequest, bool) {
+	var req filterPresetRequest
+	if err := BindJSON(r, &req); err != nil {
+		appErr, _ := AsAppError(err)
+		JsonAppError(w, appErr)
+		return nil, false
+	}
+
+	fieldErrors := make(FieldErrors)
+	if withName && !filterPresetName.MatchString(req.Name) {
+		fieldErrors["name"] = "name harus 1-100 karakter huruf, angka, titik, garis bawah, atau tanda hubung"
+	}
+
+	// Normalisasi: terima key kanonik ("status") maupun query key ("filters[status]")
+	filters := make(map[string][]string, len(req.Filters))
+	for key, values := range req.Filters {
+		canonical := key
+		if c, ok := canonicalFilterKey(key); ok {
+			canonical = c
+		}
+		if canonical == "" || canonical == "preset" || strings.HasPrefix(canonical, "[") {
+			fieldErrors["filters."+key] = "filter tidak valid"
+			continue
+		}
+		for _, value := range values {
+			if value != "" {
+				filters[canonical] = append(filters[canonical], value)
+			}
+		}
+	}
+	if len(filters) == 0 && len(fieldErrors) == 0 {
+		fieldErrors["filters"] = "filters wajib diisi"
+	}
+
+	if len(fieldErrors) == 0 && h.validate != nil {
+		preset := &FilterPreset{Filters: filters}
+		for key, message := range h.validate(preset.Query()) {
+			if canonical, ok := canonicalFilterKey(key); ok {
+				key = canonical
+			}
+			fieldErrors["filters."+key] = message
+		}
+	}
+
+	if len(fieldErrors) > 0 {
+		BadRequest(w, "Preset filter tidak valid", fieldErrors)
+		return nil, false
+	}
+	req.Filters = filters
+	return &req, true
+}
+
+func (h *FilterPresetHandler) storeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrFilterPresetNotFound):
+		NotFound(w, "Preset filter tidak ditemukan")
+	case errors.Is(err, ErrFilterPresetExists):
+		Conflict(w, "Preset filter dengan nama ini sudah ada", FieldErrors{"name": "name sudah dipakai"})
+	default:
+		InternalServerError(w, "Gagal menyimpan preset filter")
+	}
+}
+
+// ValidateFilterQuery memvalidasi query filter terhadap struct filter T dengan FilterParser
+// default. Dipakai dengan FilterPresetHandler.WithValidator agar preset yang tidak valid
+// ditolak saat disimpan, bukan saat dipakai. Mengembalikan nil jika valid.
+//
+// Example:
+//
+//	handler.WithValidator(dim.ValidateFilterQuery[TicketFilters])
+func ValidateFilterQuery[T any](query url.Values) map[string]string {
+	r := &http.Request{URL: &url.URL{RawQuery: query.Encode()}}
+	_, errs := ParseWith[T](NewFilterParser(r.WithContext(context.Background())))
+	return errs
+}
@@ -0,0 +1,75 @@
+package dim
+
+import (
+	"context"
+)
+
+// GetFilterPresetMigrations mengembalikan migrasi tabel preset filter.
+// Seperti modul organisasi dan billing, migrasi ini opsional dan tidak termasuk dalam
+// GetFrameworkMigrations. Menggunakan versi 121.
+//
+// Example:
+//
+//	migrations := append(dim.GetFrameworkMigrations(), dim.GetFilterPresetMigrations()...)
+//	dim.RunMigrations(db, migrations)
+func GetFilterPresetMigrations() []Migration {
+	return []Migration{
+		{
+			Version: 121,
+			Name:    "create_filter_presets_table",
+			Up:      CreateFilterPresetsTable,
+			Down:    DropFilterPresetsTable,
+		},
+	}
+}
+
+// CreateFilterPresetsTable membuat tabel filter_presets. Filter disimpan sebagai JSON kanonik.
+func CreateFilterPresetsTable(db Database) error {
+	var query string
+	if db.DriverName() == "sqlite" {
+		query = `
+			CREATE TABLE IF NOT EXISTS filter_presets (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				user_id TEXT NOT NULL,
+				resource TEXT NOT NULL,
+				name TEXT NOT NULL,
+				filters TEXT NOT NULL,
+				created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				UNIQUE (user_id, resource, name)
+			);
+		`
+	} else if db.DriverName() == "mysql" {
+		query = `
+			CREATE TABLE IF NOT EXISTS filter_presets (
+				id BIGINT AUTO_INCREMENT PRIMARY KEY,
+				user_id VARCHAR(255) NOT NULL,
+				resource VARCHAR(100) NOT NULL,
+				name VARCHAR(100) NOT NULL,
+				filters TEXT NOT NULL,
+				created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				UNIQUE KEY uq_filter_presets_user_resource_name (user_id, resource, name)
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+		`
+	} else {
+		query = `
+			CREATE TABLE IF NOT EXISTS filter_presets (
+				id BIGSERIAL PRIMARY KEY,
+				user_id VARCHAR(255) NOT NULL,
+				resource VARCHAR(100) NOT NULL,
+				name VARCHAR(100) NOT NULL,
+				filters TEXT NOT NULL,
+				created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				UNIQUE (user_id, resource, name)
+			);
+		`
+	}
+	return db.Exec(context.Background(), query)
+}
+
+// DropFilterPresetsTable menghapus tabel filter_presets.
+func DropFilterPresetsTable(db Database) error {
+	return db.Exec(context.Background(), "DROP TABLE IF EXISTS filter_presets")
+}
@@ -0,0 +1,254 @@
+package dim
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DatabaseFilterPresetStore is the SQL implementation of FilterPresetStore (PostgreSQL, MySQL & SQLite)
+type DatabaseFilterPresetStore struct {
+	db Database
+}
+
+// NewDatabaseFilterPresetStore creates a new SQL filter preset store.
+// Requires the table created by GetFilterPresetMigrations.
+func NewDatabaseFilterPresetStore(db Database) *DatabaseFilterPresetStore {
+	return &DatabaseFilterPresetStore{db: db}
+}
+
+// CreatePreset saves a new preset. Returns ErrFilterPresetExists if the user already has
+// a preset with the same name for the resource.
+func (s *DatabaseFilterPresetStore) CreatePreset(ctx context.Context, preset *FilterPreset) error {
+	filters, err := json.Marshal(preset.Filters)
+	if err != nil {
+		return fmt.Errorf("failed to encode filter preset: %w", err)
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	conflict := `ON CONFLICT (user_id, resource, name) DO NOTHING`
+	if s.db.DriverName() == "mysql" {
+		conflict = `ON DUPLICATE KEY UPDATE id = id`
+	}
+	query := `INSERT INTO filter_presets (user_id, resource, name, filters, created_at, updated_at)
+		 VALUES ($1, $2, $3, $4, $5, $6) ` + conflict + ` RETURNING id`
+
+	err = s.db.QueryRow(ctx, s.db.Rebind(query), preset.UserID, preset.Resource, preset.Name, string(filters), now, now).Scan(&preset.ID)
+	if err != nil {
+		if isNoRows(err) {
+			return ErrFilterPresetExists
+		}
+		return fmt.Errorf("failed to create filter preset: %w", err)
+	}
+
+	preset.CreatedAt = now
+	preset.UpdatedAt = now
+	return nil
+}
+
+// UpdatePreset replaces the filters of an existing preset.
+func (s *DatabaseFilterPresetStore) UpdatePreset(ctx context.Context, preset *FilterPreset) error {
+	filters, err := json.Marshal(preset.Filters)
+	if err != nil {
+		return fmt.Errorf("failed to encode filter preset: %w", err)
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	if s.db.DriverName() == "mysql" {
+		// MySQL does not support UPDATE ... RETURNING; lock the row within a transaction instead.
+		err = s.db.WithTx(ctx, func(ctx context.Context, tx Tx) error {
+			query := `SELECT id, created_at FROM filter_presets
+				 WHERE user_id = $1 AND resource = $2 AND name = $3 FOR UPDATE`
+			if err := tx.QueryRow(ctx, query, preset.UserID, preset.Resource, preset.Name).Scan(&preset.ID, &preset.CreatedAt); err != nil {
+				return err
+			}
+			return tx.Exec(ctx, `UPDATE filter_presets SET filters = $1, updated_at = $2 WHERE id = $3`, string(filters), now, preset.ID)
+		})
+	} else {
+		query := `UPDATE filter_presets SET filters = $1, updated_at = $2
+			 WHERE user_id = $3 AND resource = $4 AND name = $5
+			 RETURNING id, created_at`
+		err = s.db.QueryRow(ctx, s.db.Rebind(query), string(filters), now, preset.UserID, preset.Resource, preset.Name).
+			Scan(&preset.ID, &preset.CreatedAt)
+	}
+	if err != nil {
+		if isNoRows(err) {
+			return ErrFilterPresetNotFound
+		}
+		return fmt.Errorf("failed to update filter preset: %w", err)
+	}
+
+	preset.UpdatedAt = now
+	return nil
+}
+
+// FindPreset finds a preset by user, resource, and name.
+func (s *DatabaseFilterPresetStore) FindPreset(ctx context.Context, userID, resource, name string) (*FilterPreset, error) {
+	query := `SELECT id, user_id, resource, name, filters, created_at, updated_at
+		 FROM filter_presets WHERE user_id = $1 AND resource = $2 AND name = $3`
+
+	preset, err := scanFilterPreset(s.db.QueryRow(ctx, s.db.Rebind(query), userID, resource, name))
+	if err != nil {
+		if isNoRows(err) {
+			return nil, ErrFilterPresetNotFound
+		}
+		return nil, fmt.Errorf("failed to find filter preset: %w", err)
+	}
+	return preset, nil
+}
+
+// ListPresets returns the presets of a user for a resource, ordered by name.
+func (s *DatabaseFilterPresetStore) ListPresets(ctx context.Context, userID, resource string) ([]*FilterPreset, error) {
+	query := `SELECT id, user_id, resource, name, filters, created_at, updated_at
+		 FROM filter_presets WHERE user_id = $1 AND resource = $2 ORDER BY name`
+
+	rows, err := s.db.Query(ctx, s.db.Rebind(query), userID, resource)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list filter presets: %w", err)
+	}
+	defer rows.Close()
+
+	var presets []*FilterPreset
+	for rows.Next() {
+		preset, err := scanFilterPreset(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan filter preset: %w", err)
+		}
+		presets = append(presets, preset)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list filter presets: %w", err)
+	}
+	return presets, nil
+}
+
+// DeletePreset deletes a preset.
+func (s *DatabaseFilterPresetStore) DeletePreset(ctx context.Context, userID, resource, name string) error {
+	var id int64
+	var err error
+	if s.db.DriverName() == "mysql" {
+		// MySQL does not support DELETE ... RETURNING; lock the row within a transaction instead.
+		err = s.db.WithTx(ctx, func(ctx context.Context, tx Tx) error {
+			query := `SELECT id FROM filter_presets WHERE user_id = $1 AND resource = $2 AND name = $3 FOR UPDATE`
+			if err := tx.QueryRow(ctx, query, userID, resource, name).Scan(&id); err != nil {
+				return err
+			}
+			return tx.Exec(ctx, `DELETE FROM filter_presets WHERE id = $1`, id)
+		})
+	} else {
+		query := `DELETE FROM filter_presets WHERE user_id = $1 AND resource = $2 AND name = $3 RETURNING id`
+		err = s.db.QueryRow(ctx, s.db.Rebind(query), userID, resource, name).Scan(&id)
+	}
+	if err != nil {
+		if isNoRows(err) {
+			return ErrFilterPresetNotFound
+		}
+		return fmt.Errorf("failed to delete filter preset: %w", err)
+	}
+	return nil
+}
+
+// scanFilterPreset scans a filter_presets row and decodes its filters.
+func scanFilterPreset(row interface{ Scan(dest ...any) error }) (*FilterPreset, error) {
+	preset := &FilterPreset{}
+	var filters string
+	if err := row.Scan(&preset.ID, &preset.UserID, &preset.Resource, &preset.Name, &filters, &preset.CreatedAt, &preset.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(filters), &preset.Filters); err != nil {
+		return nil, fmt.Errorf("invalid filters of preset %q: %w", preset.Name, err)
+	}
+	return preset, nil
+}
+
+// MockFilterPresetStore is a mock implementation for testing
+type MockFilterPresetStore struct {
+	mu      sync.RWMutex
+	nextID  int64
+	presets map[string]*FilterPreset
+}
+
+// NewMockFilterPresetStore creates a new mock filter preset store.
+func NewMockFilterPresetStore() *MockFilterPresetStore {
+	return &MockFilterPresetStore{presets: make(map[string]*FilterPreset)}
+}
+
+func mockFilterPresetKey(userID, resource, name string) string {
+	return userID + "\x00" + resource + "\x00" + name
+}
+
+// CreatePreset saves a preset in mock store.
+func (s *MockFilterPresetStore) CreatePreset(ctx context.Context, preset *FilterPreset) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := mockFilterPresetKey(preset.UserID, preset.Resource, preset.Name)
+	if _, exists := s.presets[key]; exists {
+		return ErrFilterPresetExists
+	}
+	s.nextID++
+	preset.ID = s.nextID
+	preset.CreatedAt = time.Now()
+	preset.UpdatedAt = preset.CreatedAt
+	copied := *preset
+	s.presets[key] = &copied
+	return nil
+}
+
+// UpdatePreset replaces the filters of a preset in mock store.
+func (s *MockFilterPresetStore) UpdatePreset(ctx context.Context, preset *FilterPreset) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := mockFilterPresetKey(preset.UserID, preset.Resource, preset.Name)
+	existing, exists := s.presets[key]
+	if !exists {
+		return ErrFilterPresetNotFound
+	}
+	preset.ID = existing.ID
+	preset.CreatedAt = existing.CreatedAt
+	preset.UpdatedAt = time.Now()
+	copied := *preset
+	s.presets[key] = &copied
+	return nil
+}
+
+// FindPreset finds a preset in mock store.
+func (s *MockFilterPresetStore) FindPreset(ctx context.Context, userID, resource, name string) (*FilterPreset, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	preset, exists := s.presets[mockFilterPresetKey(userID, resource, name)]
+	if !exists {
+		return nil, ErrFilterPresetNotFound
+	}
+	copied := *preset
+	return &copied, nil
+}
+
+// ListPresets lists the presets of a user in mock store, ordered by name.
+func (s *MockFilterPresetStore) ListPresets(ctx context.Context, userID, resource string) ([]*FilterPreset, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var presets []*FilterPreset
+	for _, preset := range s.presets {
+		if preset.UserID == userID && preset.Resource == resource {
+			copied := *preset
+			presets = append(presets, &copied)
+		}
+	}
+	sort.Slice(presets, func(i, j int) bool { return presets[i].Name < presets[j].Name })
+	return presets, nil
+}
+
+// DeletePreset deletes a preset in mock store.
+func (s *MockFilterPresetStore) DeletePreset(ctx context.Context, userID, resource, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := mockFilterPresetKey(userID, resource, name)
+	if _, exists := s.presets[key]; !exists {
+		return ErrFilterPresetNotFound
+	}
+	delete(s.presets, key)
+	return nil
+}
@@ -0,0 +1,388 @@
+package dim
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type presetTicketFilters struct {
+	Status   []string  `filter:"status,in:open|pending|closed"`
+	Priority *string   `filter:"priority,in:low|high"`
+	Points   *IntRange `filter:"points"`
+}
+
+func TestCanonicalFiltersRoundTrip(t *testing.T) {
+	query := url.Values{
+		"filters[status]":        {"open", ""},
+		"filters[points][gte]":   {"3"},
+		"filters[tags][]":        {"a", "b"},
+		"filters[preset]":        {"mine"},
+		"sort":                   {"-created_at"},
+		"filters[]":              {"ignored"},
+		"filters[empty][ne]":     {""},
+		"filtersx[status]":       {"ignored"},
+		"filters[author.name]":   {"john"},
+		"filters[deleted][null]": {"true"},
+	}
+
+	filters := CanonicalFilters(query)
+	want := map[string][]string{
+		"status":        {"open"},
+		"points[gte]":   {"3"},
+		"tags[]":        {"a", "b"},
+		"author.name":   {"john"},
+		"deleted[null]": {"true"},
+	}
+	if !reflect.DeepEqual(filters, want) {
+		t.Fatalf("CanonicalFilters() = %v, want %v", filters, want)
+	}
+
+	back := (&FilterPreset{Filters: filters}).Query()
+	wantQuery := url.Values{
+		"filters[status]":        {"open"},
+		"filters[points][gte]":   {"3"},
+		"filters[tags][]":        {"a", "b"},
+		"filters[author.name]":   {"john"},
+		"filters[deleted][null]": {"true"},
+	}
+	if !reflect.DeepEqual(back, wantQuery) {
+		t.Errorf("Query() = %v, want %v", back, wantQuery)
+	}
+}
+
+func newPresetRequest(rawQuery string, user Authenticatable) *http.Request {
+	r := httptest.NewRequest(http.MethodGet, "/tickets?"+rawQuery, nil)
+	if user != nil {
+		r = SetUser(r, user)
+	}
+	return r
+}
+
+func TestFilterParserWithPresets(t *testing.T) {
+	store := NewMockFilterPresetStore()
+	ctx := context.Background()
+	if err := store.CreatePreset(ctx, &FilterPreset{
+		UserID:   "user-1",
+		Resource: "tickets",
+		Name:     "my-open-tickets",
+		Filters:  map[string][]string{"status": {"open,pending"}, "priority": {"high"}, "points[gte]": {"3"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	user := &TokenUser{ID: "user-1"}
+
+	t.Run("expands preset", func(t *testing.T) {
+		fp := NewFilterParser(newPresetRequest("filters[preset]=my-open-tickets", user)).WithPresets(store, "tickets")
+		filters, errs := ParseWith[presetTicketFilters](fp)
+		if errs != nil {
+			t.Fatalf("unexpected errors: %v", errs)
+		}
+		if !reflect.DeepEqual(filters.Status, []string{"open", "pending"}) {
+			t.Errorf("Status = %v", filters.Status)
+		}
+		if filters.Priority == nil || *filters.Priority != "high" {
+			t.Errorf("Priority = %v, want high", filters.Priority)
+		}
+		if fp.Preset() == nil || fp.Preset().Name != "my-open-tickets" {
+			t.Errorf("Preset() = %v", fp.Preset())
+		}
+
+		var hasGte bool
+		for _, c := range fp.Conditions() {
+			if c.Field == "points" && c.Op == FilterOpGte && c.Value() == "3" {
+				hasGte = true
+			}
+		}
+		if !hasGte {
+			t.Errorf("Conditions() = %v, want points gte 3 from preset", fp.Conditions())
+		}
+	})
+
+	t.Run("ad-hoc filters override the same key and merge others", func(t *testing.T) {
+		fp := NewFilterParser(newPresetRequest("filters[preset]=my-open-tickets&filters[priority]=low&filters[points][lte]=8", user)).
+			WithPresets(store, "tickets")
+		filters, errs := ParseWith[presetTicketFilters](fp)
+		if errs != nil {
+			t.Fatalf("unexpected errors: %v", errs)
+		}
+		if filters.Priority == nil || *filters.Priority != "low" {
+			t.Errorf("Priority = %v, want ad-hoc low", filters.Priority)
+		}
+		if len(filters.Status) != 2 {
+			t.Errorf("Status = %v, want preset statuses", filters.Status)
+		}
+
+		ops := map[FilterOp]string{}
+		for _, c := range fp.Conditions() {
+			if c.Field == "points" {
+				ops[c.Op] = c.Value()
+			}
+		}
+		if ops[FilterOpGte] != "3" || ops[FilterOpLte] != "8" {
+			t.Errorf("points conditions = %v, want gte 3 and lte 8", ops)
+		}
+	})
+
+	t.Run("preset values are validated", func(t *testing.T) {
+		if err := store.CreatePreset(ctx, &FilterPreset{
+			UserID: "user-1", Resource: "tickets", Name: "stale",
+			Filters: map[string][]string{"status": {"archived"}},
+		}); err != nil {
+			t.Fatal(err)
+		}
+		_, errs := ParseWith[presetTicketFilters](NewFilterParser(newPresetRequest("filters[preset]=stale", user)).WithPresets(store, "tickets"))
+		if errs["filters[status]"] == "" {
+			t.Errorf("errors = %v, want filters[status] error", errs)
+		}
+	})
+
+	t.Run("unknown preset", func(t *testing.T) {
+		fp := NewFilterParser(newPresetRequest("filters[preset]=missing&filters[priority]=low", user)).WithPresets(store, "tickets")
+		filters, errs := ParseWith[presetTicketFilters](fp)
+		if !strings.Contains(errs[FilterPresetKey], "missing") {
+			t.Errorf("errors = %v, want filters[preset] error", errs)
+		}
+		if filters.Priority == nil || *filters.Priority != "low" {
+			t.Errorf("ad-hoc filters should still be parsed, Priority = %v", filters.Priority)
+		}
+	})
+
+	t.Run("presets belong to the user and resource", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			user     Authenticatable
+			resource string
+		}{
+			{"other user", &TokenUser{ID: "user-2"}, "tickets"},
+			{"other resource", user, "orders"},
+			{"anonymous", nil, "tickets"},
+		}
+		for _, tt := range tests {
+			fp := NewFilterParser(newPresetRequest("filters[preset]=my-open-tickets", tt.user)).WithPresets(store, tt.resource)
+			if _, errs := ParseWith[presetTicketFilters](fp); errs[FilterPresetKey] == "" {
+				t.Errorf("%s: errors = %v, want filters[preset] error", tt.name, errs)
+			}
+		}
+	})
+
+	t.Run("without WithPresets preset is an unknown filter", func(t *testing.T) {
+		fp := NewFilterParser(newPresetRequest("filters[preset]=my-open-tickets", user))
+		filters, errs := ParseWith[presetTicketFilters](fp)
+		if errs != nil || filters.Status != nil || fp.Preset() != nil {
+			t.Errorf("preset should be ignored, got filters=%+v errs=%v", filters, errs)
+		}
+	})
+}
+
+func TestFilterParserWithPresets_Localized(t *testing.T) {
+	r := newPresetRequest("filters[preset]=missing", &TokenUser{ID: "user-1"})
+	r = r.WithContext(WithLocale(r.Context(), "en"))
+
+	_, errs := ParseWith[presetTicketFilters](NewFilterParser(r).WithPresets(NewMockFilterPresetStore(), "tickets"))
+	if errs[FilterPresetKey] != "filter preset not found: missing" {
+		t.Errorf("error = %q", errs[FilterPresetKey])
+	}
+}
+
+func newPresetRouter(store FilterPresetStore) *Router {
+	router := NewRouter()
+	asUser := func(next HandlerFunc) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if id := r.Header.Get("X-User"); id != "" {
+				r = SetUser(r, &TokenUser{ID: id})
+			}
+			next(w, r)
+		}
+	}
+	NewFilterPresetHandler(store, "tickets").
+		WithValidator(ValidateFilterQuery[presetTicketFilters]).
+		Register(router.Group("/tickets/presets", asUser))
+	return router
+}
+
+func servePreset(t *testing.T, router *Router, method, path, user, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if user != "" {
+		req.Header.Set("X-User", user)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestFilterPresetHandler_CRUD(t *testing.T) {
+	store := NewMockFilterPresetStore()
+	router := newPresetRouter(store)
+
+	rec := servePreset(t, router, http.MethodPost, "/tickets/presets", "user-1",
+		`{"name": "my-open-tickets", "filters": {"status": ["open"], "filters[points][gte]": ["3"]}}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	var created FilterPreset
+	if err := json.Unmarshal(rec.Body.Bytes(), &created); err != nil {
+		t.Fatal(err)
+	}
+	wantFilters := map[string][]string{"status": {"open"}, "points[gte]": {"3"}}
+	if created.ID == 0 || created.UserID != "user-1" || created.Resource != "tickets" || !reflect.DeepEqual(created.Filters, wantFilters) {
+		t.Errorf("created = %+v", created)
+	}
+
+	rec = servePreset(t, router, http.MethodPost, "/tickets/presets", "user-1", `{"name": "my-open-tickets", "filters": {"status": ["closed"]}}`)
+	if rec.Code != http.StatusConflict {
+		t.Errorf("duplicate create status = %d, want 409", rec.Code)
+	}
+
+	rec = servePreset(t, router, http.MethodPut, "/tickets/presets/my-open-tickets", "user-1", `{"filters": {"status": ["pending"]}}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("update status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+
+	rec = servePreset(t, router, http.MethodGet, "/tickets/presets/my-open-tickets", "user-1", "")
+	var found FilterPreset
+	if err := json.Unmarshal(rec.Body.Bytes(), &found); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusOK || !reflect.DeepEqual(found.Filters, map[string][]string{"status": {"pending"}}) {
+		t.Errorf("get status = %d, preset = %+v", rec.Code, found)
+	}
+
+	rec = servePreset(t, router, http.MethodGet, "/tickets/presets/my-open-tickets", "user-2", "")
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("get other user's preset status = %d, want 404", rec.Code)
+	}
+
+	rec = servePreset(t, router, http.MethodGet, "/tickets/presets", "user-1", "")
+	var list []FilterPreset
+	if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusOK || len(list) != 1 {
+		t.Errorf("list status = %d, presets = %+v", rec.Code, list)
+	}
+
+	rec = servePreset(t, router, http.MethodGet, "/tickets/presets", "user-2", "")
+	if strings.TrimSpace(rec.Body.String()) != "[]" {
+		t.Errorf("empty list body = %s, want []", rec.Body.String())
+	}
+
+	rec = servePreset(t, router, http.MethodDelete, "/tickets/presets/my-open-tickets", "user-1", "")
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("delete status = %d, want 204", rec.Code)
+	}
+	rec = servePreset(t, router, http.MethodDelete, "/tickets/presets/my-open-tickets", "user-1", "")
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("second delete status = %d, want 404", rec.Code)
+	}
+}
+
+func TestFilterPresetHandler_Validation(t *testing.T) {
+	router := newPresetRouter(NewMockFilterPresetStore())
+
+	tests := []struct {
+		name     string
+		body     string
+		wantKeys []string
+	}{
+		{"invalid name", `{"name": "my presets!", "filters": {"status": ["open"]}}`, []string{"name"}},
+		{"empty filters", `{"name": "empty", "filters": {"status": [""]}}`, []string{"filters"}},
+		{"nested preset", `{"name": "nested", "filters": {"preset": ["other"]}}`, []string{"filters.preset"}},
+		{"value rejected by validator", `{"name": "bad", "filters": {"status": ["archived"], "priority": ["low"]}}`, []string{"filters.status"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := servePreset(t, router, http.MethodPost, "/tickets/presets", "user-1", tt.body)
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+			}
+			var resp ErrorResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatal(err)
+			}
+			for _, key := range tt.wantKeys {
+				if _, ok := resp.Errors[key]; !ok {
+					t.Errorf("errors = %v, want key %q", resp.Errors, key)
+				}
+			}
+		})
+	}
+
+	rec := servePreset(t, router, http.MethodGet, "/tickets/presets", "", "")
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("anonymous status = %d, want 401", rec.Code)
+	}
+}
+
+func TestDatabaseFilterPresetStore_SQLite(t *testing.T) {
+	db, err := NewSQLiteDatabase(DatabaseConfig{Database: ":memory:"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if err := CreateFilterPresetsTable(db); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	store := NewDatabaseFilterPresetStore(db)
+
+	preset := &FilterPreset{UserID: "user-1", Resource: "tickets", Name: "open", Filters: map[string][]string{"status": {"open"}}}
+	if err := store.CreatePreset(ctx, preset); err != nil {
+		t.Fatalf("CreatePreset() failed: %v", err)
+	}
+	if preset.ID == 0 || preset.CreatedAt.IsZero() {
+		t.Errorf("created preset = %+v", preset)
+	}
+	if err := store.CreatePreset(ctx, &FilterPreset{UserID: "user-1", Resource: "tickets", Name: "open", Filters: map[string][]string{}}); err != ErrFilterPresetExists {
+		t.Errorf("duplicate CreatePreset() error = %v, want ErrFilterPresetExists", err)
+	}
+	if err := store.CreatePreset(ctx, &FilterPreset{UserID: "user-1", Resource: "tickets", Name: "closed", Filters: map[string][]string{"status": {"closed"}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	update := &FilterPreset{UserID: "user-1", Resource: "tickets", Name: "open", Filters: map[string][]string{"status": {"open", "pending"}}}
+	if err := store.UpdatePreset(ctx, update); err != nil {
+		t.Fatalf("UpdatePreset() failed: %v", err)
+	}
+	if update.ID != preset.ID {
+		t.Errorf("updated ID = %d, want %d", update.ID, preset.ID)
+	}
+	if err := store.UpdatePreset(ctx, &FilterPreset{UserID: "user-2", Resource: "tickets", Name: "open"}); err != ErrFilterPresetNotFound {
+		t.Errorf("UpdatePreset() of other user error = %v, want ErrFilterPresetNotFound", err)
+	}
+
+	found, err := store.FindPreset(ctx, "user-1", "tickets", "open")
+	if err != nil {
+		t.Fatalf("FindPreset() failed: %v", err)
+	}
+	if !reflect.DeepEqual(found.Filters, map[string][]string{"status": {"open", "pending"}}) {
+		t.Errorf("found filters = %v", found.Filters)
+	}
+
+	list, err := store.ListPresets(ctx, "user-1", "tickets")
+	if err != nil {
+		t.Fatalf("ListPresets() failed: %v", err)
+	}
+	if len(list) != 2 || list[0].Name != "closed" || list[1].Name != "open" {
+		t.Errorf("ListPresets() = %+v, want closed, open", list)
+	}
+
+	if err := store.DeletePreset(ctx, "user-1", "tickets", "open"); err != nil {
+		t.Fatalf("DeletePreset() failed: %v", err)
+	}
+	if _, err := store.FindPreset(ctx, "user-1", "tickets", "open"); err != ErrFilterPresetNotFound {
+		t.Errorf("FindPreset() after delete error = %v, want ErrFilterPresetNotFound", err)
+	}
+	if err := store.DeletePreset(ctx, "user-1", "tickets", "open"); err != ErrFilterPresetNotFound {
+		t.Errorf("second DeletePreset() error = %v, want ErrFilterPresetNotFound", err)
+	}
+}
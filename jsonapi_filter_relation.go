@@ -0,0 +1,222 @@
+package dim
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// FilterRelation mendeskripsikan relasi yang boleh difilter dengan dot-path, misal
+// ?filters[author.name]=john. Hanya relasi yang didaftarkan via WithRelation yang diizinkan.
+type FilterRelation struct {
+	// Table adalah tabel relasi, misal "users".
+	Table string
+
+	// Alias adalah alias tabel relasi di query (default: path relasi dengan titik diganti "_",
+	// misal "author_company" untuk relasi "author.company").
+	Alias string
+
+	// On adalah kondisi join yang memakai alias, misal "author.id = p.author_id".
+	// Relasi bersarang mereferensikan alias relasi induknya, misal "author_company.id = author.company_id".
+	On string
+
+	// Join memakai LEFT JOIN (dikembalikan di FilterSQL.Joins) alih-alih subquery EXISTS.
+	// Gunakan hanya untuk relasi to-one; JOIN pada relasi to-many menduplikasi baris.
+	// Relasi bersarang mengikuti mode relasi paling atas.
+	Join bool
+}
+
+// FilterSQL adalah hasil FilterSQLBuilder.BuildQuery.
+type FilterSQL struct {
+	Joins string        // klausa LEFT JOIN untuk relasi dengan Join, dipisah spasi (kosong jika tidak ada)
+	Where string        // klausa WHERE tanpa keyword WHERE (kosong jika tidak ada filter)
+	Args  []interface{} // argumen sesuai urutan placeholder
+
+	// Distance adalah ekspresi jarak (km) dari filter near pertama untuk SELECT dan ORDER BY,
+	// kosong jika tidak ada filter near. Tidak diisi untuk filter near di relasi EXISTS.
+	Distance string
+}
+
+// filterSQLIdentifier membatasi nama kolom yang diturunkan dari nama filter relasi.
+var filterSQLIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// WithRelation mengizinkan filter dot-path untuk satu relasi. Kolom filter relasi diambil dari
+// allowlist columns jika ada (misal "author.name": "author.full_name"), selain itu dari
+// alias relasi dan segmen terakhir nama filter ("author.name" → "author.name").
+//
+// Secara default condition relasi menjadi subquery EXISTS yang dikelompokkan per relasi teratas,
+// sehingga beberapa filter pada relasi yang sama harus cocok dengan baris relasi yang sama.
+//
+// Parameters:
+//   - path: path relasi, misal "author" atau "author.company" (induk harus didaftarkan juga)
+//   - relation: tabel, alias, dan kondisi join
+//
+// Returns:
+//   - *FilterSQLBuilder: builder untuk method chaining
+//
+// Example:
+//
+//	query, err := dim.NewFilterSQLBuilder(map[string]string{"title": "p.title"}).
+//	    WithRelation("author", dim.FilterRelation{Table: "users", On: "author.id = p.author_id", Join: true}).
+//	    WithRelation("tags", dim.FilterRelation{Table: "post_tags", On: "tags.post_id = p.id"}).
+//	    BuildQuery(fp.Conditions())
+//	// query.Joins: LEFT JOIN users author ON author.id = p.author_id
+//	// query.Where: author.name = $1 AND EXISTS (SELECT 1 FROM post_tags tags WHERE tags.post_id = p.id AND tags.name = $2)
+func (b *FilterSQLBuilder) WithRelation(path string, relation FilterRelation) *FilterSQLBuilder {
+	if relation.Alias == "" {
+		relation.Alias = strings.ReplaceAll(path, ".", "_")
+	}
+	b.relations[path] = relation
+	return b
+}
+
+// BuildQuery seperti Build, tetapi juga mendukung relasi dengan Join dan mengembalikan
+// klausa JOIN yang harus disisipkan setelah FROM.
+//
+// Returns:
+//   - FilterSQL: klausa JOIN, WHERE, dan argumen
+//   - error: jika field tidak ada di allowlist, relasi tidak didaftarkan, operator tidak dikenal,
+//     atau konversi nilai gagal
+//
+// Example:
+//
+//	query, err := builder.BuildQuery(fp.Conditions())
+//	sql := "SELECT p.* FROM posts p " + query.Joins
+//	if query.Where != "" {
+//	    sql += " WHERE " + query.Where
+//	}
+//	rows, err := db.Query(ctx, sql, query.Args...)
+func (b *FilterSQLBuilder) BuildQuery(conditions []FilterCondition) (FilterSQL, error) {
+	var args []interface{}
+	placeholder := func(value interface{}) string {
+		args = append(args, value)
+		return "$" + strconv.Itoa(b.argOffset+len(args))
+	}
+
+	clauses := make([]string, 0, len(conditions))
+	var joins []string
+	var distance string
+	joined := make(map[string]bool)
+
+	// Condition relasi EXISTS dikumpulkan per relasi teratas; posisinya di clauses mengikuti pemakaian pertama
+	type existsGroup struct {
+		root     FilterRelation
+		position int
+		joins    []string
+		joined   map[string]bool
+		clauses  []string
+	}
+	groups := make(map[string]*existsGroup)
+
+	for _, c := range conditions {
+		chain, err := b.relationChain(c)
+		if err != nil {
+			return FilterSQL{}, err
+		}
+		column, err := b.conditionColumn(c, chain)
+		if err != nil {
+			return FilterSQL{}, err
+		}
+		var clause, distanceExpr string
+		if c.Op == FilterOpNear {
+			clause, distanceExpr, err = b.nearClause(c, column, placeholder)
+		} else {
+			clause, err = b.conditionClause(c, column, placeholder)
+		}
+		if err != nil {
+			return FilterSQL{}, err
+		}
+		if distance == "" && (len(chain) == 0 || chain[0].Join) {
+			distance = distanceExpr
+		}
+
+		if len(chain) == 0 {
+			clauses = append(clauses, clause)
+			continue
+		}
+
+		if chain[0].Join {
+			for _, rel := range chain {
+				if !joined[rel.Alias] {
+					joined[rel.Alias] = true
+					joins = append(joins, "LEFT JOIN "+rel.Table+" "+rel.Alias+" ON "+rel.On)
+				}
+			}
+			clauses = append(clauses, clause)
+			continue
+		}
+
+		root := chain[0].Alias
+		group, ok := groups[root]
+		if !ok {
+			group = &existsGroup{root: chain[0], position: len(clauses), joined: make(map[string]bool)}
+			groups[root] = group
+			clauses = append(clauses, "")
+		}
+		for _, rel := range chain[1:] {
+			if !group.joined[rel.Alias] {
+				group.joined[rel.Alias] = true
+				group.joins = append(group.joins, " JOIN "+rel.Table+" "+rel.Alias+" ON "+rel.On)
+			}
+		}
+		group.clauses = append(group.clauses, clause)
+	}
+
+	for _, group := range groups {
+		clauses[group.position] = "EXISTS (SELECT 1 FROM " + group.root.Table + " " + group.root.Alias +
+			strings.Join(group.joins, "") + " WHERE " + group.root.On + " AND " + strings.Join(group.clauses, " AND ") + ")"
+	}
+
+	return FilterSQL{
+		Joins:    strings.Join(joins, " "),
+		Where:    strings.Join(clauses, " AND "),
+		Args:     args,
+		Distance: distance,
+	}, nil
+}
+
+// relationChain mengembalikan relasi dari yang teratas hingga relasi condition, misal
+// [author, author.company] untuk "author.company.name". Kosong untuk field milik resource sendiri
+// dan untuk filter bertitik yang dipetakan langsung di columns tanpa relasi terdaftar.
+func (b *FilterSQLBuilder) relationChain(c FilterCondition) ([]FilterRelation, error) {
+	path := c.Relation()
+	if path == "" {
+		return nil, nil
+	}
+	root, _, _ := strings.Cut(path, ".")
+	if _, ok := b.relations[root]; !ok && b.columns[c.Field] != "" {
+		return nil, nil
+	}
+
+	var chain []FilterRelation
+	for i := 0; i <= len(path); i++ {
+		if i < len(path) && path[i] != '.' {
+			continue
+		}
+		rel, ok := b.relations[path[:i]]
+		if !ok {
+			return nil, fmt.Errorf("filter %q: relation %q is not allowed", c.Field, path[:i])
+		}
+		chain = append(chain, rel)
+	}
+	return chain, nil
+}
+
+// conditionColumn me-resolve kolom condition dari allowlist columns, atau untuk filter relasi
+// dari alias relasi dan segmen terakhir nama filter.
+func (b *FilterSQLBuilder) conditionColumn(c FilterCondition, chain []FilterRelation) (string, error) {
+	if geo, ok := b.geo[c.Field]; ok && geo.Column != "" {
+		return geo.Column, nil
+	}
+	if column, ok := b.columns[c.Field]; ok && column != "" {
+		return column, nil
+	}
+	if len(chain) > 0 {
+		name := c.Field[len(c.Relation())+1:]
+		if filterSQLIdentifier.MatchString(name) {
+			return chain[len(chain)-1].Alias + "." + name, nil
+		}
+	}
+	return "", fmt.Errorf("filter %q is not mapped to a column", c.Field)
+}
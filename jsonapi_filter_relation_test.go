@@ -0,0 +1,159 @@
+package dim
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type relationTestFilters struct {
+	Title  *string `filter:"title"`
+	Author struct {
+		Name    *string `filter:"name"`
+		Company struct {
+			Country *string `filter:"country,in:ID|SG"`
+		} `filter:"company"`
+	} `filter:"author"`
+	TagName   []string `filter:"tags.name"`
+	TagColor  *string  `filter:"tags.color"`
+	Untracked struct {
+		Name string
+	} `filter:"untracked"`
+}
+
+func parseRelationFilters(t *testing.T, q url.Values) (*FilterParser, relationTestFilters) {
+	t.Helper()
+	var f relationTestFilters
+	fp := NewFilterParser(httptest.NewRequest("GET", "/?"+q.Encode(), nil)).Parse(&f)
+	return fp, f
+}
+
+func TestFilterParser_RelationStructs(t *testing.T) {
+	q := url.Values{}
+	q.Set("filters[author.name][like]", "jo")
+	q.Set("filters[author.name]", "john")
+	q.Set("filters[author.company.country]", "ID")
+	q.Set("filters[tags.name]", "go,sql")
+
+	fp, f := parseRelationFilters(t, q)
+	if fp.HasErrors() {
+		t.Fatalf("errors = %v", fp.Errors())
+	}
+	if f.Author.Name == nil || *f.Author.Name != "john" || f.Author.Company.Country == nil || *f.Author.Company.Country != "ID" {
+		t.Errorf("author = %+v", f.Author)
+	}
+	if !reflect.DeepEqual(f.TagName, []string{"go", "sql"}) {
+		t.Errorf("TagName = %v", f.TagName)
+	}
+
+	want := []FilterCondition{
+		{Field: "author.name", Op: FilterOpEq, Values: []string{"john"}},
+		{Field: "author.name", Op: FilterOpLike, Values: []string{"jo"}},
+		{Field: "author.company.country", Op: FilterOpEq, Values: []string{"ID"}},
+		{Field: "tags.name", Op: FilterOpEq, Values: []string{"go", "sql"}},
+	}
+	if got := fp.Conditions(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Conditions() =\n%v\nwant\n%v", got, want)
+	}
+	if got := want[2].Relation(); got != "author.company" {
+		t.Errorf("Relation() = %q", got)
+	}
+
+	// Constraints of nested fields use the dot-path key
+	q = url.Values{}
+	q.Set("filters[author.company.country]", "US")
+	fp, _ = parseRelationFilters(t, q)
+	if fp.Errors()["filters[author.company.country]"] == "" {
+		t.Errorf("errors = %v", fp.Errors())
+	}
+}
+
+func relationTestBuilder() *FilterSQLBuilder {
+	return NewFilterSQLBuilder(map[string]string{"title": "p.title", "author.name": "author.full_name"}).
+		WithRelation("author", FilterRelation{Table: "users", On: "author.id = p.author_id", Join: true}).
+		WithRelation("author.company", FilterRelation{Table: "companies", Alias: "c", On: "c.id = author.company_id"}).
+		WithRelation("tags", FilterRelation{Table: "post_tags", Alias: "t", On: "t.post_id = p.id"})
+}
+
+func TestFilterSQLBuilder_BuildQueryRelations(t *testing.T) {
+	conditions := []FilterCondition{
+		{Field: "tags.name", Op: FilterOpEq, Values: []string{"go", "sql"}},
+		{Field: "title", Op: FilterOpLike, Values: []string{"intro"}},
+		{Field: "author.name", Op: FilterOpEq, Values: []string{"john"}},
+		{Field: "author.company.country", Op: FilterOpEq, Values: []string{"ID"}},
+		{Field: "tags.color", Op: FilterOpNe, Values: []string{"red"}},
+	}
+
+	query, err := relationTestBuilder().BuildQuery(conditions)
+	if err != nil {
+		t.Fatalf("BuildQuery() error = %v", err)
+	}
+
+	wantJoins := "LEFT JOIN users author ON author.id = p.author_id LEFT JOIN companies c ON c.id = author.company_id"
+	if query.Joins != wantJoins {
+		t.Errorf("Joins =\n%s\nwant\n%s", query.Joins, wantJoins)
+	}
+	wantWhere := `EXISTS (SELECT 1 FROM post_tags t WHERE t.post_id = p.id AND t.name IN ($1, $2) AND t.color <> $6)` +
+		` AND p.title ILIKE $3 ESCAPE '\' AND author.full_name = $4 AND c.country = $5`
+	if query.Where != wantWhere {
+		t.Errorf("Where =\n%s\nwant\n%s", query.Where, wantWhere)
+	}
+	wantArgs := []interface{}{"go", "sql", "%intro%", "john", "ID", "red"}
+	if !reflect.DeepEqual(query.Args, wantArgs) {
+		t.Errorf("Args = %v, want %v", query.Args, wantArgs)
+	}
+}
+
+func TestFilterSQLBuilder_NestedExistsRelation(t *testing.T) {
+	builder := NewFilterSQLBuilder(nil).
+		WithRelation("comments", FilterRelation{Table: "comments", On: "comments.post_id = p.id"}).
+		WithRelation("comments.author", FilterRelation{Table: "users", On: "comments_author.id = comments.user_id"})
+
+	where, args, err := builder.Build([]FilterCondition{
+		{Field: "comments.author.email", Op: FilterOpEq, Values: []string{"a@b.c"}},
+		{Field: "comments.approved", Op: FilterOpEq, Values: []string{"true"}},
+	})
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	want := "EXISTS (SELECT 1 FROM comments comments JOIN users comments_author ON comments_author.id = comments.user_id" +
+		" WHERE comments.post_id = p.id AND comments_author.email = $1 AND comments.approved = $2)"
+	if where != want || len(args) != 2 {
+		t.Errorf("where =\n%s\nwant\n%s\nargs = %v", where, want, args)
+	}
+}
+
+func TestFilterSQLBuilder_RelationErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		condition FilterCondition
+		wantErr   string
+	}{
+		{"unregistered relation", FilterCondition{Field: "editor.name", Op: FilterOpEq, Values: []string{"x"}}, `relation "editor" is not allowed`},
+		{"unregistered nested relation", FilterCondition{Field: "tags.owner.name", Op: FilterOpEq, Values: []string{"x"}}, `relation "tags.owner" is not allowed`},
+		{"invalid column name", FilterCondition{Field: "tags.na-me", Op: FilterOpEq, Values: []string{"x"}}, "is not mapped to a column"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := relationTestBuilder().BuildQuery([]FilterCondition{tt.condition})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	// Build cannot return joins
+	_, _, err := relationTestBuilder().Build([]FilterCondition{{Field: "author.name", Op: FilterOpEq, Values: []string{"x"}}})
+	if err == nil || !strings.Contains(err.Error(), "BuildQuery") {
+		t.Errorf("Build() with Join relation error = %v", err)
+	}
+
+	// Dotted names mapped in columns without a registered relation keep working
+	where, _, err := NewFilterSQLBuilder(map[string]string{"meta.source": "p.meta->>'source'"}).
+		Build([]FilterCondition{{Field: "meta.source", Op: FilterOpEq, Values: []string{"web"}}})
+	if err != nil || where != "p.meta->>'source' = $1" {
+		t.Errorf("mapped dotted column: where = %q, err = %v", where, err)
+	}
+}
@@ -0,0 +1,241 @@
+package dim
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FilterValueConverter mengubah nilai FilterCondition (string) menjadi argumen query bertipe.
+type FilterValueConverter func(value string) (interface{}, error)
+
+// FilterSQLBuilder menerjemahkan FilterCondition menjadi klausa WHERE berparameter.
+// Hanya field yang terdaftar di allowlist columns yang boleh dipakai; nama kolom tidak pernah
+// berasal dari input user dan semua nilai dikirim sebagai argumen ($1, $2, ...).
+type FilterSQLBuilder struct {
+	columns    map[string]string
+	converters map[string]FilterValueConverter
+	relations  map[string]FilterRelation
+	geo        map[string]GeoColumn
+	driver     string
+	argOffset  int
+}
+
+// NewFilterSQLBuilder membuat builder dengan allowlist nama filter ke kolom SQL.
+//
+// Parameters:
+//   - columns: map nama filter (dari tag `filter`) ke kolom atau ekspresi SQL, misal "u.created_at"
+//
+// Returns:
+//   - *FilterSQLBuilder: builder dengan driver "postgres"
+//
+// Example:
+//
+//	where, args, err := dim.NewFilterSQLBuilder(map[string]string{
+//	    "status": "u.status",
+//	    "name":   "u.name",
+//	}).Build(fp.Conditions())
+func NewFilterSQLBuilder(columns map[string]string) *FilterSQLBuilder {
+	return &FilterSQLBuilder{
+		columns:    columns,
+		converters: make(map[string]FilterValueConverter),
+		relations:  make(map[string]FilterRelation),
+		geo:        make(map[string]GeoColumn),
+		driver:     "postgres",
+	}
+}
+
+// WithDriver mengatur dialek SQL sesuai Database.DriverName().
+// Driver "postgres" memakai ILIKE untuk operator like; driver lain memakai LIKE. Driver "mysql"
+// menulis klausa ESCAPE sebagai '\\' karena backslash adalah escape di string literal MySQL.
+// Placeholder selalu $n; gunakan Database.Rebind untuk SQLite.
+func (b *FilterSQLBuilder) WithDriver(driver string) *FilterSQLBuilder {
+	b.driver = driver
+	return b
+}
+
+// WithArgOffset menggeser nomor placeholder jika query sudah memiliki argumen lain.
+//
+// Example:
+//
+//	// tenant_id = $1, filter mulai dari $2
+//	where, args, err := builder.WithArgOffset(1).Build(fp.Conditions())
+//	query := "SELECT * FROM orders WHERE tenant_id = $1 AND " + where
+//	rows, err := db.Query(ctx, query, append([]interface{}{tenantID}, args...)...)
+func (b *FilterSQLBuilder) WithArgOffset(offset int) *FilterSQLBuilder {
+	b.argOffset = offset
+	return b
+}
+
+// WithConverter mendaftarkan konversi nilai untuk satu filter, misal Unix timestamp dari
+// TimestampRange ke time.Time untuk kolom timestamptz.
+//
+// Example:
+//
+//	builder.WithConverter("created_at", dim.UnixTimeValue)
+func (b *FilterSQLBuilder) WithConverter(field string, fn FilterValueConverter) *FilterSQLBuilder {
+	b.converters[field] = fn
+	return b
+}
+
+// Build menghasilkan klausa WHERE (tanpa keyword WHERE) yang digabung dengan AND, beserta argumennya.
+// Tanpa condition, Build mengembalikan string kosong.
+//
+// Pemetaan operator:
+//   - eq: col = $1, atau col IN ($1, $2) untuk beberapa nilai
+//   - ne: col <> $1, atau col NOT IN ($1, $2)
+//   - gt, gte, lt, lte: col > $1, col >= $1, col < $1, col <= $1
+//   - like: col ILIKE $1 ESCAPE '\' dengan nilai %...% (wildcard user di-escape); di MySQL ESCAPE '\\'
+//   - null: col IS NULL atau col IS NOT NULL
+//   - between: col BETWEEN $1 AND $2
+//   - near: kondisi radius untuk kolom yang didaftarkan via WithGeo
+//
+// Returns:
+//   - string: klausa WHERE
+//   - []interface{}: argumen sesuai urutan placeholder
+//   - error: jika field tidak ada di allowlist, operator tidak dikenal, konversi nilai gagal,
+//     atau ada filter relasi dengan Join (gunakan BuildQuery)
+func (b *FilterSQLBuilder) Build(conditions []FilterCondition) (string, []interface{}, error) {
+	query, err := b.BuildQuery(conditions)
+	if err != nil {
+		return "", nil, err
+	}
+	if query.Joins != "" {
+		return "", nil, fmt.Errorf("filter relations with Join require BuildQuery")
+	}
+	return query.Where, query.Args, nil
+}
+
+// conditionClause menerjemahkan satu condition menjadi ekspresi SQL untuk kolom yang sudah di-resolve.
+func (b *FilterSQLBuilder) conditionClause(c FilterCondition, column string, placeholder func(interface{}) string) (string, error) {
+	values, err := b.convertValues(c)
+	if err != nil {
+		return "", err
+	}
+
+	switch c.Op {
+	case FilterOpEq, FilterOpNe:
+		if len(values) == 0 {
+			return "", fmt.Errorf("filter %q: operator %s requires a value", c.Field, c.Op)
+		}
+		if len(values) == 1 {
+			op := "="
+			if c.Op == FilterOpNe {
+				op = "<>"
+			}
+			return column + " " + op + " " + placeholder(values[0]), nil
+		}
+		list := make([]string, len(values))
+		for i, v := range values {
+			list[i] = placeholder(v)
+		}
+		op := "IN"
+		if c.Op == FilterOpNe {
+			op = "NOT IN"
+		}
+		return column + " " + op + " (" + strings.Join(list, ", ") + ")", nil
+
+	case FilterOpGt, FilterOpGte, FilterOpLt, FilterOpLte:
+		if len(values) != 1 {
+			return "", fmt.Errorf("filter %q: operator %s requires exactly one value", c.Field, c.Op)
+		}
+		return column + " " + filterSQLComparison[c.Op] + " " + placeholder(values[0]), nil
+
+	case FilterOpLike:
+		if len(c.Values) != 1 {
+			return "", fmt.Errorf("filter %q: operator like requires exactly one value", c.Field)
+		}
+		op := "LIKE"
+		if b.driver == "postgres" {
+			op = "ILIKE"
+		}
+		return column + " " + op + " " + placeholder("%"+escapeLikePattern(c.Values[0])+"%") + likeEscapeClause(b.driver), nil
+
+	case FilterOpNull:
+		if c.Value() == "true" {
+			return column + " IS NULL", nil
+		}
+		return column + " IS NOT NULL", nil
+
+	case FilterOpBetween:
+		if len(values) != 2 {
+			return "", fmt.Errorf("filter %q: operator between requires two values", c.Field)
+		}
+		return column + " BETWEEN " + placeholder(values[0]) + " AND " + placeholder(values[1]), nil
+	}
+
+	return "", fmt.Errorf("filter %q: unsupported operator %q", c.Field, c.Op)
+}
+
+var filterSQLComparison = map[FilterOp]string{
+	FilterOpGt:  ">",
+	FilterOpGte: ">=",
+	FilterOpLt:  "<",
+	FilterOpLte: "<=",
+}
+
+func (b *FilterSQLBuilder) convertValues(c FilterCondition) ([]interface{}, error) {
+	convert := b.converters[c.Field]
+	values := make([]interface{}, len(c.Values))
+	for i, v := range c.Values {
+		if convert == nil || c.Op == FilterOpLike || c.Op == FilterOpNull {
+			values[i] = v
+			continue
+		}
+		converted, err := convert(v)
+		if err != nil {
+			return nil, fmt.Errorf("filter %q: failed to convert value %q: %w", c.Field, v, err)
+		}
+		values[i] = converted
+	}
+	return values, nil
+}
+
+// likeEscapeClause mengembalikan klausa ESCAPE untuk pola dari escapeLikePattern. Di MySQL
+// (sql_mode default) backslash meng-escape tanda kutip, sehingga '\' menjadi string yang tidak
+// tertutup; literal backslash harus ditulis '\\'.
+func likeEscapeClause(driver string) string {
+	if driver == "mysql" {
+		return ` ESCAPE '\\'`
+	}
+	return ` ESCAPE '\'`
+}
+
+// escapeLikePattern meng-escape wildcard LIKE (%, _) dan karakter escape itu sendiri.
+func escapeLikePattern(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// UnixTimeValue adalah FilterValueConverter untuk nilai TimestampRange (Unix detik) ke time.Time UTC.
+func UnixTimeValue(value string) (interface{}, error) {
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return time.Unix(n, 0).UTC(), nil
+}
+
+// ToSQL menerjemahkan hasil Parse menjadi klausa WHERE berparameter untuk PostgreSQL (pgx).
+// Shortcut untuk NewFilterSQLBuilder(columns).Build(fp.Conditions()); gunakan
+// FilterSQLBuilder langsung untuk driver lain, offset argumen, atau konversi nilai.
+//
+// Parameters:
+//   - columns: allowlist nama filter ke kolom SQL
+//
+// Returns:
+//   - string: klausa WHERE tanpa keyword WHERE (kosong jika tidak ada filter)
+//   - []interface{}: argumen query
+//   - error: jika ada filter yang tidak terdaftar di columns
+//
+// Example:
+//
+//	where, args, err := fp.ToSQL(map[string]string{"status": "status", "price": "price"})
+//	query := "SELECT id, name FROM products"
+//	if where != "" {
+//	    query += " WHERE " + where
+//	}
+//	rows, err := db.Query(ctx, query, args...)
+func (fp *FilterParser) ToSQL(columns map[string]string) (string, []interface{}, error) {
+	return NewFilterSQLBuilder(columns).Build(fp.conditions)
+}
@@ -0,0 +1,153 @@
+package dim
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var operatorTestColumns = map[string]string{
+	"price":      "p.price",
+	"name":       "p.name",
+	"status":     "p.status",
+	"amount":     "p.amount",
+	"created_at": "p.created_at",
+	"active":     "p.active",
+	"owner_id":   "p.owner_id",
+}
+
+// TestFilterParser_ToSQL tests the WHERE clause built from parsed query parameters
+func TestFilterParser_ToSQL(t *testing.T) {
+	q := url.Values{}
+	q.Set("filters[price][gte]", "100")
+	q.Set("filters[price][lt]", "500")
+	q.Set("filters[name][like]", "jo")
+	q.Set("filters[status]", "active,pending")
+	q.Set("filters[status][ne]", "pending")
+	q.Set("filters[amount]", "10.5,20")
+	q.Set("filters[created_at][null]", "1")
+
+	fp, _ := parseOperatorFilters(t, q)
+	where, args, err := fp.ToSQL(operatorTestColumns)
+	if err != nil {
+		t.Fatalf("ToSQL error: %v", err)
+	}
+
+	wantWhere := `p.price >= $1 AND p.price < $2 AND p.name ILIKE $3 ESCAPE '\' AND p.status IN ($4, $5) AND p.status <> $6 AND p.amount BETWEEN $7 AND $8 AND p.created_at IS NULL`
+	if where != wantWhere {
+		t.Errorf("where =\n%s\nwant\n%s", where, wantWhere)
+	}
+	wantArgs := []interface{}{"100", "500", "%jo%", "active", "pending", "pending", "10.5", "20"}
+	if !reflect.DeepEqual(args, wantArgs) {
+		t.Errorf("args = %v, want %v", args, wantArgs)
+	}
+}
+
+// TestFilterParser_ToSQL_Empty tests that no filters produce an empty clause
+func TestFilterParser_ToSQL_Empty(t *testing.T) {
+	fp, _ := parseOperatorFilters(t, url.Values{})
+	where, args, err := fp.ToSQL(operatorTestColumns)
+	if where != "" || args != nil || err != nil {
+		t.Errorf("ToSQL() = %q, %v, %v", where, args, err)
+	}
+}
+
+// TestFilterSQLBuilder_Allowlist tests that unmapped fields are rejected
+func TestFilterSQLBuilder_Allowlist(t *testing.T) {
+	conditions := []FilterCondition{
+		{Field: "price", Op: FilterOpGt, Values: []string{"1"}},
+		{Field: "password_hash", Op: FilterOpEq, Values: []string{"x"}},
+	}
+
+	_, _, err := NewFilterSQLBuilder(map[string]string{"price": "price"}).Build(conditions)
+	if err == nil || !strings.Contains(err.Error(), "password_hash") {
+		t.Errorf("expected allowlist error, got %v", err)
+	}
+}
+
+// TestFilterSQLBuilder_Options tests driver, argument offset, and value converters
+func TestFilterSQLBuilder_Options(t *testing.T) {
+	conditions := []FilterCondition{
+		{Field: "name", Op: FilterOpLike, Values: []string{`50%_off\`}},
+		{Field: "status", Op: FilterOpNe, Values: []string{"archived", "deleted"}},
+		{Field: "created_at", Op: FilterOpGte, Values: []string{"1705276800"}},
+		{Field: "deleted_at", Op: FilterOpNull, Values: []string{"false"}},
+	}
+
+	where, args, err := NewFilterSQLBuilder(map[string]string{
+		"name":       "name",
+		"status":     "status",
+		"created_at": "created_at",
+		"deleted_at": "deleted_at",
+	}).
+		WithDriver("sqlite").
+		WithArgOffset(1).
+		WithConverter("created_at", UnixTimeValue).
+		Build(conditions)
+	if err != nil {
+		t.Fatalf("Build error: %v", err)
+	}
+
+	wantWhere := `name LIKE $2 ESCAPE '\' AND status NOT IN ($3, $4) AND created_at >= $5 AND deleted_at IS NOT NULL`
+	if where != wantWhere {
+		t.Errorf("where =\n%s\nwant\n%s", where, wantWhere)
+	}
+	wantArgs := []interface{}{`%50\%\_off\\%`, "archived", "deleted", time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)}
+	if !reflect.DeepEqual(args, wantArgs) {
+		t.Errorf("args = %v, want %v", args, wantArgs)
+	}
+}
+
+func TestFilterSQLBuilder_MySQLLike(t *testing.T) {
+	conditions := []FilterCondition{
+		{Field: "name", Op: FilterOpLike, Values: []string{`a_b`}},
+		{Field: "status", Op: FilterOpEq, Values: []string{"active"}},
+	}
+	where, args, err := NewFilterSQLBuilder(map[string]string{"name": "name", "status": "status"}).
+		WithDriver("mysql").
+		Build(conditions)
+	if err != nil {
+		t.Fatalf("Build error: %v", err)
+	}
+	if want := `name LIKE $1 ESCAPE '\\' AND status = $2`; where != want {
+		t.Errorf("where = %s, want %s", where, want)
+	}
+
+	// Placeholder setelah klausa ESCAPE tetap di-rewrite
+	query, bound, err := bindMySQL("SELECT id FROM users WHERE "+where, args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `SELECT id FROM users WHERE name LIKE ? ESCAPE '\\' AND status = ?`; query != want {
+		t.Errorf("bindMySQL() = %s, want %s", query, want)
+	}
+	if want := []interface{}{`%a\_b%`, "active"}; !reflect.DeepEqual(bound, want) {
+		t.Errorf("args = %v, want %v", bound, want)
+	}
+}
+
+// TestFilterSQLBuilder_Errors tests invalid conditions
+func TestFilterSQLBuilder_Errors(t *testing.T) {
+	columns := map[string]string{"price": "price", "created_at": "created_at"}
+	tests := []struct {
+		name      string
+		condition FilterCondition
+	}{
+		{"unknown operator", FilterCondition{Field: "price", Op: "regex", Values: []string{"1"}}},
+		{"comparison without value", FilterCondition{Field: "price", Op: FilterOpGt}},
+		{"between with one value", FilterCondition{Field: "price", Op: FilterOpBetween, Values: []string{"1"}}},
+		{"eq without value", FilterCondition{Field: "price", Op: FilterOpEq}},
+		{"converter failure", FilterCondition{Field: "created_at", Op: FilterOpLt, Values: []string{"soon"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := NewFilterSQLBuilder(columns).WithConverter("created_at", UnixTimeValue)
+			if _, _, err := builder.Build([]FilterCondition{tt.condition}); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
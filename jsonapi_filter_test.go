@@ -1225,3 +1225,185 @@ func TestParseRangeEquivalence(t *testing.T) {
 		})
 	}
 }
+
+// TestParseRangeOpenEnded tests "100," (lower bound only) and ",500" (upper bound only)
+func TestParseRangeOpenEnded(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  IntRange
+	}{
+		{"single value", "100", IntRange{From: 100, To: 100, HasFrom: true, HasTo: true, Valid: true, Present: true}},
+		{"closed range", "100,500", IntRange{From: 100, To: 500, HasFrom: true, HasTo: true, Valid: true, Present: true}},
+		{"lower bound only", "100,", IntRange{From: 100, HasFrom: true, Valid: true, Present: true}},
+		{"upper bound only", ",500", IntRange{To: 500, HasTo: true, Valid: true, Present: true}},
+		{"upper bound with spaces", " , 500 ", IntRange{To: 500, HasTo: true, Valid: true, Present: true}},
+		{"no bounds", ",", IntRange{Present: true}},
+		{"invalid upper bound", ",abc", IntRange{Present: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseIntRange(tt.value); got != tt.want {
+				t.Errorf("parseIntRange(%q) = %+v, want %+v", tt.value, got, tt.want)
+			}
+		})
+	}
+
+	dr := parseDateRange("2024-01-01,")
+	if !dr.Valid || !dr.HasFrom || dr.HasTo || dr.To != "" {
+		t.Errorf("parseDateRange lower bound = %+v", dr)
+	}
+	dr = parseDateRange(",2024-01-31")
+	if !dr.Valid || dr.HasFrom || !dr.HasTo || dr.To != "2024-01-31" {
+		t.Errorf("parseDateRange upper bound = %+v", dr)
+	}
+	if dr = parseDateRange(",2024/01/31"); dr.Valid {
+		t.Errorf("parseDateRange invalid upper bound = %+v", dr)
+	}
+}
+
+// TestParseOpenEndedRangeConditions tests that open-ended ranges become gte/lte conditions
+func TestParseOpenEndedRangeConditions(t *testing.T) {
+	type Filters struct {
+		Amount    AmountRange    `filter:"amount"`
+		Price     *IntRange      `filter:"price"`
+		CreatedAt TimestampRange `filter:"created_at"`
+	}
+
+	q := url.Values{}
+	q.Set("filters[amount]", "100.5,")
+	q.Set("filters[price]", ",500")
+	q.Set("filters[created_at]", "2024-01-01,2024-01-02")
+
+	req, _ := http.NewRequest("GET", "http://example.com?"+q.Encode(), nil)
+	var filters Filters
+	fp := NewFilterParser(req).Parse(&filters)
+	if fp.HasErrors() {
+		t.Fatalf("Parse errors = %v", fp.Errors())
+	}
+	if filters.Price == nil || filters.Price.HasFrom || filters.Price.To != 500 {
+		t.Errorf("Price = %+v", filters.Price)
+	}
+
+	want := map[string]FilterCondition{
+		"amount":     {Field: "amount", Op: FilterOpGte, Values: []string{"100.5"}},
+		"price":      {Field: "price", Op: FilterOpLte, Values: []string{"500"}},
+		"created_at": {Field: "created_at", Op: FilterOpBetween, Values: []string{"1704067200", "1704153600"}},
+	}
+	conditions := fp.Conditions()
+	if len(conditions) != len(want) {
+		t.Fatalf("conditions = %+v", conditions)
+	}
+	for _, c := range conditions {
+		if w := want[c.Field]; c.Op != w.Op || !reflect.DeepEqual(c.Values, w.Values) {
+			t.Errorf("condition %+v, want %+v", c, w)
+		}
+	}
+}
+
+// Slice Syntax Tests
+
+// TestParseSliceSyntax tests repeated params, bracketed arrays, and delimiters
+func TestParseSliceSyntax(t *testing.T) {
+	type Filters struct {
+		IDs    []int64  `filter:"ids"`
+		Names  []string `filter:"names"`
+		Cities []string `filter:"cities,delimiter:;"`
+		Raw    []string `filter:"raw,delimiter:none"`
+	}
+
+	tests := []struct {
+		name      string
+		query     url.Values
+		delimiter string
+		check     func(f Filters) bool
+	}{
+		{
+			name:  "comma separated",
+			query: url.Values{"filters[ids]": {"1,2,3"}},
+			check: func(f Filters) bool { return reflect.DeepEqual(f.IDs, []int64{1, 2, 3}) },
+		},
+		{
+			name:  "repeated params with commas",
+			query: url.Values{"filters[ids]": {"1,2", "3"}},
+			check: func(f Filters) bool { return reflect.DeepEqual(f.IDs, []int64{1, 2, 3}) },
+		},
+		{
+			name:  "bracketed array",
+			query: url.Values{"filters[ids][]": {"1", "2"}},
+			check: func(f Filters) bool { return reflect.DeepEqual(f.IDs, []int64{1, 2}) },
+		},
+		{
+			name:  "bracketed values are not split",
+			query: url.Values{"filters[names][]": {"Doe, John", "Roe, Jane"}},
+			check: func(f Filters) bool { return reflect.DeepEqual(f.Names, []string{"Doe, John", "Roe, Jane"}) },
+		},
+		{
+			name:      "parser delimiter",
+			query:     url.Values{"filters[names]": {"Doe, John;Roe, Jane"}},
+			delimiter: ";",
+			check:     func(f Filters) bool { return reflect.DeepEqual(f.Names, []string{"Doe, John", "Roe, Jane"}) },
+		},
+		{
+			name:  "tag delimiter",
+			query: url.Values{"filters[cities]": {"Jakarta, DKI;Bandung, Jabar"}},
+			check: func(f Filters) bool { return reflect.DeepEqual(f.Cities, []string{"Jakarta, DKI", "Bandung, Jabar"}) },
+		},
+		{
+			name:  "tag disables splitting",
+			query: url.Values{"filters[raw]": {"a,b", "c"}},
+			check: func(f Filters) bool { return reflect.DeepEqual(f.Raw, []string{"a,b", "c"}) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "http://example.com?"+tt.query.Encode(), nil)
+			var filters Filters
+			fp := NewFilterParser(req).WithDelimiter(tt.delimiter).Parse(&filters)
+			if fp.HasErrors() {
+				t.Fatalf("Parse errors = %v", fp.Errors())
+			}
+			if !tt.check(filters) {
+				t.Errorf("unexpected result: %+v", filters)
+			}
+		})
+	}
+}
+
+// TestParseSliceSyntaxConstraintsAndOperators tests that array values go through constraints and operators
+func TestParseSliceSyntaxConstraintsAndOperators(t *testing.T) {
+	type Filters struct {
+		Statuses []string `filter:"statuses,in:active|pending"`
+		Names    []string `filter:"names,delimiter:;"`
+	}
+
+	parse := func(q url.Values, maxValues int) *FilterParser {
+		req, _ := http.NewRequest("GET", "http://example.com?"+q.Encode(), nil)
+		var filters Filters
+		return NewFilterParser(req).WithMaxValues(maxValues).Parse(&filters)
+	}
+
+	fp := parse(url.Values{"filters[statuses][]": {"active", "deleted"}}, 0)
+	if fp.Errors()["filters[statuses]"] == "" {
+		t.Errorf("expected constraint error, got %v", fp.Errors())
+	}
+
+	fp = parse(url.Values{"filters[statuses][]": {"active"}, "filters[statuses]": {"pending"}}, 1)
+	if fp.Errors()["filters[statuses]"] == "" {
+		t.Errorf("bracketed values should count toward max values, got %v", fp.Errors())
+	}
+
+	fp = parse(url.Values{"filters[names][ne]": {"Doe, John;Roe, Jane"}}, 0)
+	conditions := fp.Conditions()
+	if fp.HasErrors() || len(conditions) != 1 || !reflect.DeepEqual(conditions[0].Values, []string{"Doe, John", "Roe, Jane"}) {
+		t.Errorf("ne with delimiter: errors = %v, conditions = %+v", fp.Errors(), conditions)
+	}
+
+	fp = parse(url.Values{"filters[statuses][ne][]": {"pending"}}, 0)
+	conditions = fp.Conditions()
+	if fp.HasErrors() || len(conditions) != 1 || conditions[0].Op != FilterOpNe {
+		t.Errorf("bracketed ne: errors = %v, conditions = %+v", fp.Errors(), conditions)
+	}
+}
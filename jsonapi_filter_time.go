@@ -0,0 +1,110 @@
+package dim
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TimeRange represents a range of time.Time values, e.g. filters[created_at]=2024-01-01,2024-01-31.
+// Unlike TimestampRange, values keep their time zone and can be parsed with any configured layout.
+type TimeRange = Range[time.Time]
+
+// TimeLayoutUnix is a pseudo layout that parses integer Unix seconds.
+const TimeLayoutUnix = "unix"
+
+// DefaultFilterTimeLayouts are the layouts tried, in order, for time.Time filter fields when
+// neither WithTimeLayouts nor a "layout" tag option is set.
+var DefaultFilterTimeLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05", time.DateOnly}
+
+// filterTimeLayoutAliases maps short names usable in the "layout" tag option to layouts.
+var filterTimeLayoutAliases = map[string]string{
+	"date":     time.DateOnly,
+	"datetime": "2006-01-02T15:04:05",
+	"rfc3339":  time.RFC3339Nano,
+	"unix":     TimeLayoutUnix,
+}
+
+// WithTimeLayouts sets the layouts tried, in order, when parsing time.Time, []time.Time and
+// TimeRange filter fields. Use TimeLayoutUnix to accept Unix seconds.
+// Layouts without a zone are interpreted in the WithTimezone location (UTC by default).
+// A field can override the layouts with the tag option "layout:date|unix".
+// Returns the receiver for method chaining.
+//
+// Example:
+//
+//	fp.WithTimeLayouts(time.RFC3339, time.DateOnly, dim.TimeLayoutUnix).Parse(&filters)
+func (fp *FilterParser) WithTimeLayouts(layouts ...string) *FilterParser {
+	fp.TimeLayouts = layouts
+	return fp
+}
+
+// filterTimeLayouts resolves the layouts for a field: the "layout" tag option first,
+// then FilterParser.TimeLayouts, then DefaultFilterTimeLayouts.
+func (fp *FilterParser) filterTimeLayouts(constraints map[string]string) []string {
+	if raw, ok := constraints["layout"]; ok {
+		var layouts []string
+		for _, layout := range strings.Split(raw, "|") {
+			if layout = strings.TrimSpace(layout); layout == "" {
+				continue
+			}
+			if alias, ok := filterTimeLayoutAliases[layout]; ok {
+				layout = alias
+			}
+			layouts = append(layouts, layout)
+		}
+		if len(layouts) > 0 {
+			return layouts
+		}
+	}
+	if len(fp.TimeLayouts) > 0 {
+		return fp.TimeLayouts
+	}
+	return DefaultFilterTimeLayouts
+}
+
+// parseFilterTime parses a value with the first matching layout in the parser's timezone.
+func (fp *FilterParser) parseFilterTime(value string, layouts []string) (time.Time, error) {
+	tz := fp.TimestampTimezone
+	if tz == nil {
+		tz = time.UTC
+	}
+
+	for _, layout := range layouts {
+		if layout == TimeLayoutUnix {
+			if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+				return time.Unix(n, 0).In(tz), nil
+			}
+			continue
+		}
+		if t, err := time.ParseInLocation(layout, value, tz); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, localizedErrorf("format waktu tidak valid: %s (gunakan %s)", value, strings.Join(layouts, ", "))
+}
+
+// parseTimeRangeValue parses "from,to" (or a single value) into a TimeRange.
+func (fp *FilterParser) parseTimeRangeValue(value string, f *filterField) (TimeRange, error) {
+	layouts := fp.filterTimeLayouts(f.constraints)
+	tr := parseRange(
+		value,
+		func(s string) (time.Time, error) { return fp.parseFilterTime(s, layouts) },
+		func(from, to time.Time) bool { return !to.Before(from) },
+	)
+	if tr.Present && !tr.Valid {
+		return tr, localizedErrorf("rentang waktu tidak valid (gunakan %s, dari <= sampai)", strings.Join(layouts, ", "))
+	}
+	return tr, nil
+}
+
+// formatFilterTime is the canonical form of time values in FilterCondition.
+func formatFilterTime(t time.Time) string {
+	return t.Format(time.RFC3339Nano)
+}
+
+// RFC3339TimeValue adalah FilterValueConverter untuk nilai filter time.Time/TimeRange
+// (RFC 3339) ke time.Time, untuk driver yang membutuhkan parameter bertipe waktu.
+func RFC3339TimeValue(value string) (interface{}, error) {
+	return time.Parse(time.RFC3339Nano, value)
+}
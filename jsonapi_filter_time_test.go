@@ -0,0 +1,138 @@
+package dim
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+type timeFilters struct {
+	After   *time.Time  `filter:"after"`
+	Days    []time.Time `filter:"days,layout:date"`
+	Since   time.Time   `filter:"since,layout:unix"`
+	Created TimeRange   `filter:"created"`
+	Updated *TimeRange  `filter:"updated"`
+}
+
+func timeFilterRequest(q url.Values) *FilterParser {
+	return NewFilterParser(httptest.NewRequest("GET", "/?"+q.Encode(), nil))
+}
+
+func TestFilterParser_TimeFields(t *testing.T) {
+	q := url.Values{}
+	q.Set("filters[after]", "2024-03-01T10:00:00+07:00")
+	q.Set("filters[days]", "2024-01-01,2024-01-02")
+	q.Set("filters[since]", "1704067200")
+	q.Set("filters[created]", "2024-01-01,2024-01-31")
+	q.Set("filters[updated]", "2024-02-01T00:00:00Z")
+
+	var f timeFilters
+	fp := timeFilterRequest(q).Parse(&f)
+	if fp.HasErrors() {
+		t.Fatalf("errors = %v", fp.Errors())
+	}
+
+	if f.After == nil || !f.After.Equal(time.Date(2024, 3, 1, 3, 0, 0, 0, time.UTC)) {
+		t.Errorf("After = %v", f.After)
+	}
+	if len(f.Days) != 2 || !f.Days[1].Equal(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Days = %v", f.Days)
+	}
+	if !f.Since.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Since = %v", f.Since)
+	}
+	if !f.Created.Valid || !f.Created.To.Equal(time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Created = %+v", f.Created)
+	}
+	if f.Updated == nil || !f.Updated.From.Equal(f.Updated.To) {
+		t.Errorf("Updated = %+v", f.Updated)
+	}
+}
+
+func TestFilterParser_TimeTimezoneAndLayouts(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*3600)
+
+	q := url.Values{}
+	q.Set("filters[after]", "2024-01-01")
+	var f timeFilters
+	timeFilterRequest(q).WithTimezone(jakarta).Parse(&f)
+	if f.After == nil || !f.After.Equal(time.Date(2023, 12, 31, 17, 0, 0, 0, time.UTC)) {
+		t.Errorf("After in WIB = %v", f.After)
+	}
+
+	// WithTimeLayouts restricts the accepted formats for fields without a layout tag
+	f = timeFilters{}
+	fp := timeFilterRequest(q).WithTimeLayouts(time.RFC3339).Parse(&f)
+	if fp.Errors()["filters[after]"] == "" {
+		t.Error("date-only value should be rejected with RFC3339 layout")
+	}
+
+	q = url.Values{}
+	q.Set("filters[after]", "1704067200")
+	f = timeFilters{}
+	timeFilterRequest(q).WithTimeLayouts(TimeLayoutUnix).Parse(&f)
+	if f.After == nil || f.After.Unix() != 1704067200 {
+		t.Errorf("After unix = %v", f.After)
+	}
+}
+
+func TestFilterParser_TimeErrors(t *testing.T) {
+	tests := []struct {
+		key   string
+		value string
+	}{
+		{"filters[after]", "yesterday"},
+		{"filters[days]", "2024-01-01T00:00:00Z"},
+		{"filters[since]", "2024-01-01"},
+		{"filters[created]", "2024-02-01,2024-01-01"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			q := url.Values{}
+			q.Set(tt.key, tt.value)
+			var f timeFilters
+			fp := timeFilterRequest(q).Parse(&f)
+			if fp.Errors()[tt.key] == "" {
+				t.Errorf("expected error for %s=%s, got %v", tt.key, tt.value, fp.Errors())
+			}
+		})
+	}
+}
+
+func TestFilterParser_TimeConditions(t *testing.T) {
+	q := url.Values{}
+	q.Set("filters[created]", "2024-01-01,2024-01-31")
+	q.Set("filters[after][gte]", "2024-03-01T10:00:00+07:00")
+	q.Set("filters[since][lt]", "1704067200")
+
+	var f timeFilters
+	fp := timeFilterRequest(q).Parse(&f)
+	if fp.HasErrors() {
+		t.Fatalf("errors = %v", fp.Errors())
+	}
+
+	want := map[string]FilterCondition{
+		"created": {Field: "created", Op: FilterOpBetween, Values: []string{"2024-01-01T00:00:00Z", "2024-01-31T00:00:00Z"}},
+		"after":   {Field: "after", Op: FilterOpGte, Values: []string{"2024-03-01T10:00:00+07:00"}},
+		"since":   {Field: "since", Op: FilterOpLt, Values: []string{"2024-01-01T00:00:00Z"}},
+	}
+	for _, c := range fp.Conditions() {
+		w, ok := want[c.Field]
+		if !ok || c.Op != w.Op || len(c.Values) != len(w.Values) {
+			t.Errorf("unexpected condition %+v", c)
+			continue
+		}
+		for i := range c.Values {
+			if c.Values[i] != w.Values[i] {
+				t.Errorf("%s values = %v, want %v", c.Field, c.Values, w.Values)
+			}
+		}
+	}
+
+	v, err := RFC3339TimeValue("2024-03-01T10:00:00+07:00")
+	if err != nil || !v.(time.Time).Equal(time.Date(2024, 3, 1, 3, 0, 0, 0, time.UTC)) {
+		t.Errorf("RFC3339TimeValue() = %v, %v", v, err)
+	}
+}
@@ -0,0 +1,151 @@
+package dim
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// FilterTypeParser converts the raw query values of a filter into a value of a registered type.
+// The returned value must be assignable to the registered type. Returned errors are shown
+// to the client under the filter key, so they should be user-facing messages.
+type FilterTypeParser func(values []string) (any, error)
+
+// RegisterType registers a parser for an application type (money, enums, ULID, ...) so it can
+// be used in filter structs without changes to the built-in parsers.
+// Registered types take precedence over built-in handling and are resolved per field type:
+//   - T: the parser receives all values of the filter
+//   - *T: the parser receives all values and the result is stored behind a new pointer
+//   - []T: the parser is called once per value (after delimiter splitting)
+//
+// Operator values (filters[field][gte]=...) of a registered type are checked with the same
+// parser and formatted with fmt.Stringer if the type implements it, otherwise fmt.Sprint.
+// Tag constraints such as "in" are applied to the raw values before parsing.
+// Returns the receiver for method chaining.
+//
+// Example:
+//
+//	fp.RegisterType(reflect.TypeOf(ulid.ULID{}), func(values []string) (any, error) {
+//	    id, err := ulid.Parse(values[0])
+//	    if err != nil {
+//	        return nil, fmt.Errorf("ULID tidak valid: %s", values[0])
+//	    }
+//	    return id, nil
+//	})
+//
+//	type OrderFilters struct {
+//	    IDs []ulid.ULID `filter:"ids"` // ?filters[ids]=01HV...,01HW...
+//	}
+func (fp *FilterParser) RegisterType(t reflect.Type, parser FilterTypeParser) *FilterParser {
+	if t == nil || parser == nil {
+		return fp
+	}
+	if fp.typeParsers == nil {
+		fp.typeParsers = make(map[reflect.Type]FilterTypeParser)
+	}
+	fp.typeParsers[t] = parser
+	return fp
+}
+
+// parseRegisteredType binds values with a parser registered via RegisterType.
+// Reports false if neither the field type nor its pointer or slice element type is registered.
+func (fp *FilterParser) parseRegisteredType(field reflect.Value, f *filterField, values []string) (bool, error) {
+	if len(fp.typeParsers) == 0 {
+		return false, nil
+	}
+
+	t := field.Type()
+	if parser, ok := fp.typeParsers[t]; ok {
+		return true, fp.setRegisteredType(field, f, parser, values, t)
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		parser, ok := fp.typeParsers[t.Elem()]
+		if !ok {
+			return false, nil
+		}
+		elem := reflect.New(t.Elem())
+		if err := fp.setRegisteredType(elem.Elem(), f, parser, values, t.Elem()); err != nil {
+			return true, err
+		}
+		field.Set(elem)
+		return true, nil
+
+	case reflect.Slice:
+		parser, ok := fp.typeParsers[t.Elem()]
+		if !ok {
+			return false, nil
+		}
+		if fp.MaxValuesPerField > 0 && len(values) > fp.MaxValuesPerField {
+			return true, localizedErrorf("maksimal %d nilai diperbolehkan, diterima %d", fp.MaxValuesPerField, len(values))
+		}
+		if err := fp.applyConstraints(values, f.constraints, t); err != nil {
+			return true, err
+		}
+		slice := reflect.MakeSlice(t, len(values), len(values))
+		for i, value := range values {
+			result, err := callFilterTypeParser(parser, []string{value}, t.Elem())
+			if err != nil {
+				return true, err
+			}
+			slice.Index(i).Set(result)
+		}
+		field.Set(slice)
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// setRegisteredType applies the constraints, runs the parser, and assigns the result to field.
+func (fp *FilterParser) setRegisteredType(field reflect.Value, f *filterField, parser FilterTypeParser, values []string, t reflect.Type) error {
+	if err := fp.applyConstraints(values, f.constraints, t); err != nil {
+		return err
+	}
+	result, err := callFilterTypeParser(parser, values, t)
+	if err != nil {
+		return err
+	}
+	field.Set(result)
+	return nil
+}
+
+// normalizeRegisteredType checks an operator value with the parser registered for the field's
+// value type. Reports false if the type is not registered.
+func (fp *FilterParser) normalizeRegisteredType(fieldType reflect.Type, value string) (string, bool, error) {
+	if len(fp.typeParsers) == 0 {
+		return "", false, nil
+	}
+
+	t := fieldType
+	parser, ok := fp.typeParsers[t]
+	for !ok && (t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice) {
+		t = t.Elem()
+		parser, ok = fp.typeParsers[t]
+	}
+	if !ok {
+		return "", false, nil
+	}
+
+	result, err := callFilterTypeParser(parser, []string{value}, t)
+	if err != nil {
+		return "", true, err
+	}
+	return formatFilterValue(result), true, nil
+}
+
+// callFilterTypeParser runs parser and checks that the result can be assigned to t.
+func callFilterTypeParser(parser FilterTypeParser, values []string, t reflect.Type) (reflect.Value, error) {
+	result, err := parser(values)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	if result == nil {
+		return reflect.Zero(t), nil
+	}
+	v := reflect.ValueOf(result)
+	if !v.Type().AssignableTo(t) {
+		return reflect.Value{}, fmt.Errorf("filter type parser for %s returned %s", t, v.Type())
+	}
+	return v, nil
+}
@@ -0,0 +1,152 @@
+package dim
+
+import (
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type testMoney struct {
+	Cents    int64
+	Currency string
+}
+
+func (m testMoney) String() string { return fmt.Sprintf("%d %s", m.Cents, m.Currency) }
+
+type testPriority int
+
+func parseTestMoney(values []string) (any, error) {
+	amount, currency, ok := strings.Cut(values[0], " ")
+	cents, err := strconv.ParseInt(amount, 10, 64)
+	if !ok || err != nil {
+		return nil, errors.New("format uang tidak valid")
+	}
+	return testMoney{Cents: cents, Currency: currency}, nil
+}
+
+func parseTestPriority(values []string) (any, error) {
+	switch values[0] {
+	case "low":
+		return testPriority(1), nil
+	case "high":
+		return testPriority(3), nil
+	}
+	return nil, fmt.Errorf("prioritas tidak dikenal: %s", values[0])
+}
+
+type customTypeFilters struct {
+	Price      testMoney      `filter:"price"`
+	MaxPrice   *testMoney     `filter:"max_price"`
+	Priorities []testPriority `filter:"priorities"`
+	Level      *testPriority  `filter:"level,in:low|high"`
+}
+
+func customTypeParser(q url.Values) *FilterParser {
+	return NewFilterParser(httptest.NewRequest("GET", "/?"+q.Encode(), nil)).
+		RegisterType(reflect.TypeOf(testMoney{}), parseTestMoney).
+		RegisterType(reflect.TypeOf(testPriority(0)), parseTestPriority)
+}
+
+func TestFilterParser_RegisterType(t *testing.T) {
+	q := url.Values{}
+	q.Set("filters[price]", "1500 IDR")
+	q.Set("filters[max_price]", "9000 USD")
+	q.Set("filters[priorities]", "low,high")
+	q.Set("filters[level]", "high")
+
+	var f customTypeFilters
+	fp := customTypeParser(q).Parse(&f)
+	if fp.HasErrors() {
+		t.Fatalf("errors = %v", fp.Errors())
+	}
+
+	if f.Price != (testMoney{Cents: 1500, Currency: "IDR"}) {
+		t.Errorf("Price = %+v", f.Price)
+	}
+	if f.MaxPrice == nil || *f.MaxPrice != (testMoney{Cents: 9000, Currency: "USD"}) {
+		t.Errorf("MaxPrice = %+v", f.MaxPrice)
+	}
+	if !reflect.DeepEqual(f.Priorities, []testPriority{1, 3}) {
+		t.Errorf("Priorities = %v", f.Priorities)
+	}
+	if f.Level == nil || *f.Level != 3 {
+		t.Errorf("Level = %v", f.Level)
+	}
+
+	want := map[string][]string{
+		"price":      {"1500 IDR"},
+		"max_price":  {"9000 USD"},
+		"priorities": {"1", "3"},
+		"level":      {"3"},
+	}
+	for _, c := range fp.Conditions() {
+		if c.Op != FilterOpEq || !reflect.DeepEqual(c.Values, want[c.Field]) {
+			t.Errorf("condition %+v, want values %v", c, want[c.Field])
+		}
+	}
+}
+
+func TestFilterParser_RegisterTypeErrors(t *testing.T) {
+	tests := []struct {
+		key   string
+		value string
+		want  string
+	}{
+		{"filters[price]", "abc", "format uang tidak valid"},
+		{"filters[priorities]", "low,urgent", "prioritas tidak dikenal: urgent"},
+		{"filters[level]", "medium", "nilai tidak valid: medium"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			q := url.Values{}
+			q.Set(tt.key, tt.value)
+			var f customTypeFilters
+			fp := customTypeParser(q).Parse(&f)
+			if got := fp.Errors()[tt.key]; !strings.HasPrefix(got, tt.want) {
+				t.Errorf("error = %q, want prefix %q", got, tt.want)
+			}
+		})
+	}
+
+	// Parser returning the wrong type is reported instead of panicking
+	q := url.Values{}
+	q.Set("filters[price]", "1500 IDR")
+	var f customTypeFilters
+	fp := NewFilterParser(httptest.NewRequest("GET", "/?"+q.Encode(), nil)).
+		RegisterType(reflect.TypeOf(testMoney{}), func(values []string) (any, error) { return values[0], nil }).
+		Parse(&f)
+	if !strings.Contains(fp.Errors()["filters[price]"], "returned string") {
+		t.Errorf("errors = %v", fp.Errors())
+	}
+}
+
+func TestFilterParser_RegisterTypeOperators(t *testing.T) {
+	q := url.Values{}
+	q.Set("filters[priorities][ne]", "low")
+	q.Set("filters[price][ne]", "100 IDR")
+
+	var f customTypeFilters
+	fp := customTypeParser(q).Parse(&f)
+	if fp.HasErrors() {
+		t.Fatalf("errors = %v", fp.Errors())
+	}
+	want := map[string]string{"priorities": "1", "price": "100 IDR"}
+	for _, c := range fp.Conditions() {
+		if c.Op != FilterOpNe || len(c.Values) != 1 || c.Values[0] != want[c.Field] {
+			t.Errorf("condition %+v", c)
+		}
+	}
+
+	q = url.Values{}
+	q.Set("filters[priorities][ne]", "urgent")
+	fp = customTypeParser(q).Parse(&f)
+	if fp.Errors()["filters[priorities][ne]"] == "" {
+		t.Errorf("expected operator error, got %v", fp.Errors())
+	}
+}
@@ -0,0 +1,147 @@
+package dim
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// Includes holds the relationship paths requested via ?include=
+type Includes struct {
+	paths []string
+	set   map[string]bool
+}
+
+// Has reports whether the relationship path was requested, either directly or as an
+// intermediate of a nested path (include=comments.author implies comments).
+func (inc Includes) Has(path string) bool {
+	return inc.set[path]
+}
+
+// Paths returns the requested paths as given, in request order.
+func (inc Includes) Paths() []string {
+	return inc.paths
+}
+
+// Empty reports whether no relationship was requested.
+func (inc Includes) Empty() bool {
+	return len(inc.set) == 0
+}
+
+// Nested returns the includes below a relationship, with the prefix removed.
+// For include=comments.author,comments.likes, Nested("comments") has author and likes.
+func (inc Includes) Nested(relationship string) Includes {
+	prefix := relationship + "."
+	nested := Includes{set: make(map[string]bool)}
+	for _, path := range inc.paths {
+		if rest, ok := strings.CutPrefix(path, prefix); ok {
+			nested.paths = append(nested.paths, rest)
+		}
+	}
+	for path := range inc.set {
+		if rest, ok := strings.CutPrefix(path, prefix); ok {
+			nested.set[rest] = true
+		}
+	}
+	return nested
+}
+
+// IncludeParser parses the JSON:API "include" query parameter
+type IncludeParser struct {
+	AllowedPaths map[string]bool
+
+	defaultIncludes string
+	maxDepth        int
+}
+
+// NewIncludeParser creates a new IncludeParser with the declared relationship paths.
+// Nested paths must be declared explicitly ("comments.author"); declaring a nested path
+// also allows each of its intermediates ("comments").
+//
+// Example:
+//
+//	parser := dim.NewIncludeParser([]string{"author", "comments.author"})
+//	includes, err := parser.Parse(r) // ?include=author,comments.author
+//	if includes.Has("comments") {
+//	    post.Comments, err = comments.ForPost(ctx, post.ID, includes.Nested("comments"))
+//	}
+func NewIncludeParser(allowedPaths []string) *IncludeParser {
+	allowed := make(map[string]bool)
+	for _, path := range allowedPaths {
+		for _, p := range includeAncestors(path) {
+			allowed[p] = true
+		}
+	}
+	return &IncludeParser{
+		AllowedPaths: allowed,
+		maxDepth:     3,
+	}
+}
+
+// WithDefault sets the includes used when the request has no include parameter,
+// in the same format as the query (e.g. "author").
+func (p *IncludeParser) WithDefault(include string) *IncludeParser {
+	p.defaultIncludes = include
+	return p
+}
+
+// WithMaxDepth limits the nesting depth of include paths (default 3). 0 means unlimited.
+func (p *IncludeParser) WithMaxDepth(depth int) *IncludeParser {
+	p.maxDepth = depth
+	return p
+}
+
+// Parse parses the "include" query parameter
+// Format: ?include=author,comments.author
+// An explicitly empty parameter (?include=) disables the default includes.
+func (p *IncludeParser) Parse(r *http.Request) (Includes, error) {
+	includes := Includes{set: make(map[string]bool)}
+
+	q := r.URL.Query()
+	param, present := q.Get("include"), q.Has("include")
+	if !present {
+		param = p.defaultIncludes
+	}
+	if param == "" {
+		return includes, nil
+	}
+
+	for _, path := range strings.Split(param, ",") {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+
+		if strings.HasPrefix(path, ".") || strings.HasSuffix(path, ".") || strings.Contains(path, "..") {
+			return Includes{}, NewAppError(fmt.Sprintf("Include path '%s' is invalid", path), http.StatusBadRequest)
+		}
+		if p.maxDepth > 0 && strings.Count(path, ".")+1 > p.maxDepth {
+			return Includes{}, NewAppError(fmt.Sprintf("Include path '%s' exceeds the maximum depth of %d", path, p.maxDepth), http.StatusBadRequest)
+		}
+		if !p.AllowedPaths[path] {
+			return Includes{}, NewAppError(fmt.Sprintf("Relationship '%s' is not allowed for include", path), http.StatusBadRequest)
+		}
+
+		if slices.Contains(includes.paths, path) {
+			continue
+		}
+		includes.paths = append(includes.paths, path)
+		for _, ancestor := range includeAncestors(path) {
+			includes.set[ancestor] = true
+		}
+	}
+
+	return includes, nil
+}
+
+// includeAncestors returns the path and all of its intermediate paths,
+// e.g. "a.b.c" → ["a", "a.b", "a.b.c"].
+func includeAncestors(path string) []string {
+	parts := strings.Split(path, ".")
+	result := make([]string, len(parts))
+	for i := range parts {
+		result[i] = strings.Join(parts[:i+1], ".")
+	}
+	return result
+}
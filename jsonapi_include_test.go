@@ -0,0 +1,127 @@
+package dim
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestIncludeParser_Parse(t *testing.T) {
+	allowed := []string{"author", "comments.author", "tags"}
+
+	tests := []struct {
+		name        string
+		queryString string
+		expected    []string
+		expectError bool
+	}{
+		{
+			name:        "No include param",
+			queryString: "",
+			expected:    nil,
+		},
+		{
+			name:        "Single relationship",
+			queryString: "?include=author",
+			expected:    []string{"author"},
+		},
+		{
+			name:        "Nested relationship",
+			queryString: "?include=author,comments.author",
+			expected:    []string{"author", "comments.author"},
+		},
+		{
+			name:        "Intermediate of declared path",
+			queryString: "?include=comments",
+			expected:    []string{"comments"},
+		},
+		{
+			name:        "Duplicates and spaces",
+			queryString: "?include=tags,%20tags,,author",
+			expected:    []string{"tags", "author"},
+		},
+		{
+			name:        "Unknown relationship",
+			queryString: "?include=secrets",
+			expectError: true,
+		},
+		{
+			name:        "Undeclared nested path",
+			queryString: "?include=author.posts",
+			expectError: true,
+		},
+		{
+			name:        "Malformed path",
+			queryString: "?include=comments..author",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := NewIncludeParser(allowed)
+			req := httptest.NewRequest("GET", "/"+tt.queryString, nil)
+
+			includes, err := parser.Parse(req)
+			if (err != nil) != tt.expectError {
+				t.Fatalf("Parse() error = %v, expectError %v", err, tt.expectError)
+			}
+			if tt.expectError {
+				if appErr, ok := AsAppError(err); !ok || appErr.StatusCode != 400 {
+					t.Errorf("error = %v, want 400 AppError", err)
+				}
+				return
+			}
+			if !reflect.DeepEqual(includes.Paths(), tt.expected) {
+				t.Errorf("Paths() = %v, want %v", includes.Paths(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestIncludes_HasAndNested(t *testing.T) {
+	parser := NewIncludeParser([]string{"author", "comments.author", "comments.likes"})
+	includes, err := parser.Parse(httptest.NewRequest("GET", "/?include=comments.author,comments.likes", nil))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if !includes.Has("comments") || !includes.Has("comments.author") {
+		t.Error("Has() should report requested paths and their intermediates")
+	}
+	if includes.Has("author") {
+		t.Error("Has(author) should be false")
+	}
+
+	nested := includes.Nested("comments")
+	if !reflect.DeepEqual(nested.Paths(), []string{"author", "likes"}) {
+		t.Errorf("Nested().Paths() = %v", nested.Paths())
+	}
+	if !nested.Has("author") || nested.Has("comments") {
+		t.Errorf("Nested().Has() mismatch")
+	}
+	if !includes.Nested("author").Empty() {
+		t.Error("Nested(author) should be empty")
+	}
+}
+
+func TestIncludeParser_DefaultAndMaxDepth(t *testing.T) {
+	parser := NewIncludeParser([]string{"author", "comments.author.avatar"}).WithDefault("author")
+
+	includes, _ := parser.Parse(httptest.NewRequest("GET", "/", nil))
+	if !includes.Has("author") {
+		t.Error("default include should apply when parameter is absent")
+	}
+
+	includes, _ = parser.Parse(httptest.NewRequest("GET", "/?include=", nil))
+	if !includes.Empty() {
+		t.Errorf("explicit empty include should disable default, got %v", includes.Paths())
+	}
+
+	if _, err := parser.WithMaxDepth(2).Parse(httptest.NewRequest("GET", "/?include=comments.author.avatar", nil)); err == nil {
+		t.Error("expected max depth error")
+	}
+	if _, err := parser.WithMaxDepth(0).Parse(httptest.NewRequest("GET", "/?include=comments.author.avatar", nil)); err != nil {
+		t.Errorf("unlimited depth error = %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 package dim
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
 )
@@ -16,6 +17,38 @@ func (p *Pagination) Offset() int {
 	return (p.Page - 1) * p.Limit
 }
 
+// LimitOffset returns a parameterized "LIMIT $n OFFSET $m" clause and its arguments.
+// argOffset is the number of placeholders already used by the query (e.g. from FilterSQLBuilder).
+//
+// Example:
+//
+//	where, args, _ := builder.Build(conditions)
+//	clause, pageArgs := pg.LimitOffset(len(args))
+//	query := "SELECT * FROM users WHERE " + where + " ORDER BY id " + clause
+//	rows, err := db.Query(ctx, db.Rebind(query), append(args, pageArgs...)...)
+func (p *Pagination) LimitOffset(argOffset int) (string, []interface{}) {
+	clause := fmt.Sprintf("LIMIT $%d OFFSET $%d", argOffset+1, argOffset+2)
+	return clause, []interface{}{p.Limit, p.Offset()}
+}
+
+// Meta builds PaginationMeta for JsonPagination from the total number of records.
+//
+// Example:
+//
+//	dim.JsonPagination(w, http.StatusOK, users, pg.Meta(total))
+func (p *Pagination) Meta(total int) PaginationMeta {
+	totalPages := 0
+	if p.Limit > 0 {
+		totalPages = (total + p.Limit - 1) / p.Limit
+	}
+	return PaginationMeta{
+		Page:       p.Page,
+		PerPage:    p.Limit,
+		Total:      total,
+		TotalPages: totalPages,
+	}
+}
+
 // PaginationParser parses pagination parameters
 type PaginationParser struct {
 	DefaultLimit int
@@ -37,7 +70,8 @@ func NewPaginationParser(defaultLimit, maxLimit int) *PaginationParser {
 }
 
 // Parse parses page[number] and page[size]
-// Also supports page and limit/size query params as fallback or standard simple pagination
+// Also supports page and limit/size/per_page query params as fallback or standard simple pagination.
+// Sizes above MaxLimit are clamped to MaxLimit.
 func (p *PaginationParser) Parse(r *http.Request) (*Pagination, error) {
 	q := r.URL.Query()
 
@@ -54,6 +88,9 @@ func (p *PaginationParser) Parse(r *http.Request) (*Pagination, error) {
 		if limitStr == "" {
 			limitStr = q.Get("size")
 		}
+		if limitStr == "" {
+			limitStr = q.Get("per_page")
+		}
 	}
 
 	page := 1
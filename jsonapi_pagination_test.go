@@ -38,6 +38,12 @@ func TestPaginationParser_Parse(t *testing.T) {
 			expected:    &Pagination{Page: 1, Limit: 25},
 			expectError: false,
 		},
+		{
+			name:        "Per page alias",
+			queryString: "?page=2&per_page=30",
+			expected:    &Pagination{Page: 2, Limit: 30},
+			expectError: false,
+		},
 		{
 			name:        "Exceed max limit",
 			queryString: "?page[size]=1000",
@@ -94,3 +100,36 @@ func TestPagination_Offset(t *testing.T) {
 		t.Errorf("Expected offset 20, got %d", offset)
 	}
 }
+
+func TestPagination_LimitOffset(t *testing.T) {
+	p := &Pagination{Page: 3, Limit: 10}
+
+	clause, args := p.LimitOffset(2)
+	if clause != "LIMIT $3 OFFSET $4" {
+		t.Errorf("clause = %q", clause)
+	}
+	if len(args) != 2 || args[0] != 10 || args[1] != 20 {
+		t.Errorf("args = %v", args)
+	}
+}
+
+func TestPagination_Meta(t *testing.T) {
+	tests := []struct {
+		total      int
+		totalPages int
+	}{
+		{0, 0},
+		{1, 1},
+		{25, 3},
+		{30, 3},
+	}
+
+	for _, tt := range tests {
+		p := &Pagination{Page: 2, Limit: 10}
+		meta := p.Meta(tt.total)
+		want := PaginationMeta{Page: 2, PerPage: 10, Total: tt.total, TotalPages: tt.totalPages}
+		if meta != want {
+			t.Errorf("Meta(%d) = %+v, want %+v", tt.total, meta, want)
+		}
+	}
+}
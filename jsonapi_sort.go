@@ -6,6 +6,12 @@ import (
 	"strings"
 )
 
+// Sort directions
+const (
+	SortAsc  = "ASC"
+	SortDesc = "DESC"
+)
+
 // SortField represents a single sort criterion
 type SortField struct {
 	Field     string
@@ -20,6 +26,10 @@ func (s SortField) SQL() string {
 // SortParser parses sort parameters from the request
 type SortParser struct {
 	AllowedFields map[string]bool
+
+	columns     map[string]string
+	defaultSort string
+	maxFields   int
 }
 
 // NewSortParser creates a new SortParser with allowed fields
@@ -33,16 +43,51 @@ func NewSortParser(allowedFields []string) *SortParser {
 	}
 }
 
+// WithColumns maps sort field names to SQL columns and adds them to the allowlist.
+// The same map used for FilterSQLBuilder can be reused here.
+//
+// Example:
+//
+//	parser := dim.NewSortParser(nil).WithColumns(map[string]string{
+//	    "created_at": "u.created_at",
+//	    "name":       "u.name",
+//	})
+func (p *SortParser) WithColumns(columns map[string]string) *SortParser {
+	p.columns = columns
+	for field := range columns {
+		p.AllowedFields[field] = true
+	}
+	return p
+}
+
+// WithDefault sets the sort used when the request has no sort parameter,
+// in the same format as the query (e.g. "-created_at,id").
+func (p *SortParser) WithDefault(sort string) *SortParser {
+	p.defaultSort = sort
+	return p
+}
+
+// WithMaxFields limits the number of sort fields per request. 0 means unlimited.
+func (p *SortParser) WithMaxFields(max int) *SortParser {
+	p.maxFields = max
+	return p
+}
+
 // Parse parses the "sort" query parameter
 // Format: ?sort=-created_at,title (descending created_at, ascending title)
+// The default sort (WithDefault) is used when the parameter is absent.
 func (p *SortParser) Parse(r *http.Request) ([]SortField, error) {
 	sortParam := r.URL.Query().Get("sort")
+	if sortParam == "" {
+		sortParam = p.defaultSort
+	}
 	if sortParam == "" {
 		return nil, nil
 	}
 
 	fields := strings.Split(sortParam, ",")
 	result := make([]SortField, 0, len(fields))
+	seen := make(map[string]bool, len(fields))
 
 	for _, f := range fields {
 		f = strings.TrimSpace(f)
@@ -50,23 +95,65 @@ func (p *SortParser) Parse(r *http.Request) ([]SortField, error) {
 			continue
 		}
 
-		direction := "ASC"
+		direction := SortAsc
 		fieldName := f
 
 		if strings.HasPrefix(f, "-") {
-			direction = "DESC"
+			direction = SortDesc
 			fieldName = strings.TrimPrefix(f, "-")
 		}
 
+		if fieldName == "" {
+			return nil, NewAppError("Sort field must not be empty", http.StatusBadRequest)
+		}
+
 		if len(p.AllowedFields) > 0 && !p.AllowedFields[fieldName] {
 			return nil, NewAppError(fmt.Sprintf("Sort field '%s' is not allowed", fieldName), http.StatusBadRequest)
 		}
 
+		if seen[fieldName] {
+			return nil, NewAppError(fmt.Sprintf("Sort field '%s' is specified more than once", fieldName), http.StatusBadRequest)
+		}
+		seen[fieldName] = true
+
 		result = append(result, SortField{
 			Field:     fieldName,
 			Direction: direction,
 		})
 	}
 
+	if p.maxFields > 0 && len(result) > p.maxFields {
+		return nil, NewAppError(fmt.Sprintf("At most %d sort fields are allowed", p.maxFields), http.StatusBadRequest)
+	}
+
 	return result, nil
 }
+
+// OrderBy builds an ORDER BY fragment (without the ORDER BY keyword) from parsed sort fields,
+// using the columns from WithColumns. Fields are checked against the allowlist again, so
+// SortField values built by hand cannot inject SQL. Returns an empty string for no fields.
+//
+// Example:
+//
+//	sort, err := parser.Parse(r)
+//	orderBy, err := parser.OrderBy(sort) // "u.created_at DESC, u.name ASC"
+//	if orderBy != "" {
+//	    query += " ORDER BY " + orderBy
+//	}
+func (p *SortParser) OrderBy(fields []SortField) (string, error) {
+	parts := make([]string, 0, len(fields))
+	for _, f := range fields {
+		column := f.Field
+		if mapped, ok := p.columns[f.Field]; ok {
+			column = mapped
+		} else if !p.AllowedFields[f.Field] {
+			return "", fmt.Errorf("sort field %q is not allowed", f.Field)
+		}
+
+		if f.Direction != SortAsc && f.Direction != SortDesc {
+			return "", fmt.Errorf("invalid sort direction %q for field %q", f.Direction, f.Field)
+		}
+		parts = append(parts, column+" "+f.Direction)
+	}
+	return strings.Join(parts, ", "), nil
+}
@@ -101,3 +101,109 @@ func TestSortField_SQL(t *testing.T) {
 		t.Errorf("Expected %s, got %s", expected, got)
 	}
 }
+
+func TestSortParser_Options(t *testing.T) {
+	columns := map[string]string{"created_at": "u.created_at", "name": "u.name", "id": "u.id"}
+
+	tests := []struct {
+		name        string
+		parser      *SortParser
+		queryString string
+		expected    []SortField
+		expectError bool
+	}{
+		{
+			name:        "Default sort when param is absent",
+			parser:      NewSortParser(nil).WithColumns(columns).WithDefault("-created_at,id"),
+			queryString: "",
+			expected: []SortField{
+				{Field: "created_at", Direction: SortDesc},
+				{Field: "id", Direction: SortAsc},
+			},
+		},
+		{
+			name:        "Request overrides default",
+			parser:      NewSortParser(nil).WithColumns(columns).WithDefault("-created_at"),
+			queryString: "?sort=name",
+			expected:    []SortField{{Field: "name", Direction: SortAsc}},
+		},
+		{
+			name:        "Column keys are allowed",
+			parser:      NewSortParser(nil).WithColumns(columns),
+			queryString: "?sort=password_hash",
+			expectError: true,
+		},
+		{
+			name:        "Max fields exceeded",
+			parser:      NewSortParser(nil).WithColumns(columns).WithMaxFields(2),
+			queryString: "?sort=name,id,-created_at",
+			expectError: true,
+		},
+		{
+			name:        "Duplicate field",
+			parser:      NewSortParser(nil).WithColumns(columns),
+			queryString: "?sort=name,-name",
+			expectError: true,
+		},
+		{
+			name:        "Empty field name",
+			parser:      NewSortParser(nil),
+			queryString: "?sort=-",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/"+tt.queryString, nil)
+			result, err := tt.parser.Parse(req)
+
+			if tt.expectError {
+				if _, ok := AsAppError(err); !ok {
+					t.Errorf("Expected AppError but got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestSortParser_OrderBy(t *testing.T) {
+	parser := NewSortParser([]string{"id"}).WithColumns(map[string]string{
+		"created_at": "u.created_at",
+		"name":       "u.name",
+	})
+
+	req := httptest.NewRequest("GET", "/?sort=-created_at,name,id", nil)
+	fields, err := parser.Parse(req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	orderBy, err := parser.OrderBy(fields)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if expected := "u.created_at DESC, u.name ASC, id ASC"; orderBy != expected {
+		t.Errorf("Expected %q, got %q", expected, orderBy)
+	}
+
+	if orderBy, err := parser.OrderBy(nil); orderBy != "" || err != nil {
+		t.Errorf("Expected empty fragment, got %q, %v", orderBy, err)
+	}
+	if _, err := parser.OrderBy([]SortField{{Field: "name; DROP TABLE users", Direction: SortAsc}}); err == nil {
+		t.Error("Expected error for field outside allowlist")
+	}
+	if _, err := parser.OrderBy([]SortField{{Field: "name", Direction: "ASC; --"}}); err == nil {
+		t.Error("Expected error for invalid direction")
+	}
+	if _, err := NewSortParser(nil).OrderBy([]SortField{{Field: "name", Direction: SortAsc}}); err == nil {
+		t.Error("Expected error when no allowlist is configured")
+	}
+}
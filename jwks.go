@@ -0,0 +1,322 @@
+package dim
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// JSONWebKey adalah satu entry JWKS (RFC 7517) untuk public key RSA atau EC.
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// JSONWebKeySet adalah dokumen JWKS, misal response /.well-known/jwks.json.
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// NewJSONWebKey membuat JWK dari *rsa.PublicKey atau *ecdsa.PublicKey. Jika kid kosong,
+// thumbprint RFC 7638 key dipakai sebagai kid.
+//
+// Parameters:
+//   - kid: key ID, boleh kosong
+//   - alg: algoritma JWS, misal "RS256" atau "ES256"
+//   - pub: public key RSA atau ECDSA
+//
+// Returns:
+//   - JSONWebKey: key dengan use "sig"
+//   - error: error jika tipe key tidak didukung
+func NewJSONWebKey(kid, alg string, pub interface{}) (JSONWebKey, error) {
+	key := JSONWebKey{Kid: kid, Use: "sig", Alg: alg}
+	switch pub := pub.(type) {
+	case *rsa.PublicKey:
+		key.Kty = "RSA"
+		key.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+		key.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+	case *ecdsa.PublicKey:
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		key.Kty = "EC"
+		key.Crv = pub.Curve.Params().Name
+		key.X = base64.RawURLEncoding.EncodeToString(pub.X.FillBytes(make([]byte, size)))
+		key.Y = base64.RawURLEncoding.EncodeToString(pub.Y.FillBytes(make([]byte, size)))
+	default:
+		return JSONWebKey{}, fmt.Errorf("unsupported public key type %T", pub)
+	}
+	if key.Kid == "" {
+		key.Kid = key.Thumbprint()
+	}
+	return key, nil
+}
+
+// Thumbprint mengembalikan JWK thumbprint SHA-256 (RFC 7638) dalam base64url.
+func (k JSONWebKey) Thumbprint() string {
+	// Member wajib dalam urutan leksikografis, tanpa whitespace
+	var canonical string
+	switch k.Kty {
+	case "RSA":
+		canonical = fmt.Sprintf(`{"e":%q,"kty":"RSA","n":%q}`, k.E, k.N)
+	case "EC":
+		canonical = fmt.Sprintf(`{"crv":%q,"kty":"EC","x":%q,"y":%q}`, k.Crv, k.X, k.Y)
+	default:
+		return ""
+	}
+	sum := sha256.Sum256([]byte(canonical))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// publicKey mengubah JWK menjadi *rsa.PublicKey atau *ecdsa.PublicKey.
+func (k JSONWebKey) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+// jwkSetMinRefresh membatasi pengambilan ulang JWKS saat kid tidak dikenal atau fetch gagal.
+const jwkSetMinRefresh = time.Minute
+
+// jwkSetDefaultMaxAge adalah umur cache JWKS jika response tidak membawa Cache-Control max-age
+// dan tidak ada TTL yang dikonfigurasi.
+const jwkSetDefaultMaxAge = time.Hour
+
+// jwkSet menyimpan public key dari endpoint JWKS remote (provider OIDC atau service lain).
+// Key diambil ulang setelah umur cache habis (maxAge, atau Cache-Control max-age dari response)
+// dan saat kid tidak dikenal (rotasi key di sisi penerbit), paling sering sekali per
+// jwkSetMinRefresh. Hanya satu fetch berjalan pada satu waktu dan lock tidak ditahan selama
+// request HTTP; selama fetch berjalan atau jika fetch gagal, key yang sudah di-cache tetap dipakai.
+type jwkSet struct {
+	url    string
+	maxAge time.Duration // 0 berarti mengikuti Cache-Control
+
+	mu        sync.Mutex
+	keys      map[string]interface{}
+	fetchedAt time.Time     // percobaan fetch terakhir
+	expiresAt time.Time     // cache dianggap basi setelah waktu ini
+	fetching  chan struct{} // ditutup saat fetch yang sedang berjalan selesai
+}
+
+func (s *jwkSet) key(ctx context.Context, client *http.Client, kid string) (interface{}, error) {
+	for {
+		s.mu.Lock()
+		key, found := s.lookup(kid)
+		now := time.Now()
+		if found && now.Before(s.expiresAt) {
+			s.mu.Unlock()
+			return key, nil
+		}
+
+		// Fetch sedang berjalan: pakai key lama jika ada, atau tunggu hasilnya
+		if wait := s.fetching; wait != nil {
+			s.mu.Unlock()
+			if found {
+				return key, nil
+			}
+			select {
+			case <-wait:
+				continue
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+
+		if now.Sub(s.fetchedAt) < jwkSetMinRefresh {
+			s.mu.Unlock()
+			if found {
+				return key, nil
+			}
+			return nil, fmt.Errorf("unknown signing key %q", kid)
+		}
+		done := make(chan struct{})
+		s.fetching = done
+		s.fetchedAt = now
+		s.mu.Unlock()
+
+		keys, maxAge, err := s.fetch(ctx, client)
+
+		s.mu.Lock()
+		if err == nil {
+			s.keys = keys
+			s.expiresAt = time.Now().Add(s.ttl(maxAge))
+		}
+		s.fetching = nil
+		close(done)
+		key, found = s.lookup(kid)
+		s.mu.Unlock()
+
+		if found {
+			return key, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch jwks: %w", err)
+		}
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+}
+
+// fetch mengambil dokumen JWKS beserta max-age dari header Cache-Control (0 jika tidak ada).
+func (s *jwkSet) fetch(ctx context.Context, client *http.Client) (map[string]interface{}, time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, 0, fmt.Errorf("unexpected status %d from %s", resp.StatusCode, s.url)
+	}
+	var doc JSONWebKeySet
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&doc); err != nil {
+		return nil, 0, err
+	}
+
+	keys := make(map[string]interface{}, len(doc.Keys))
+	for _, k := range doc.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		if pub, err := k.publicKey(); err == nil {
+			keys[k.Kid] = pub
+		}
+	}
+	return keys, cacheControlMaxAge(resp.Header.Get("Cache-Control")), nil
+}
+
+// ttl menentukan umur cache: maxAge yang dikonfigurasi, lalu max-age dari response, lalu
+// jwkSetDefaultMaxAge. Hasilnya tidak pernah kurang dari jwkSetMinRefresh.
+func (s *jwkSet) ttl(responseMaxAge time.Duration) time.Duration {
+	ttl := s.maxAge
+	if ttl <= 0 {
+		ttl = responseMaxAge
+	}
+	if ttl <= 0 {
+		ttl = jwkSetDefaultMaxAge
+	}
+	return max(ttl, jwkSetMinRefresh)
+}
+
+// cacheControlMaxAge membaca directive max-age dari header Cache-Control; 0 jika tidak ada.
+func cacheControlMaxAge(header string) time.Duration {
+	for _, directive := range strings.Split(header, ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(directive), "=")
+		if !strings.EqualFold(name, "max-age") {
+			continue
+		}
+		if seconds, err := strconv.Atoi(strings.Trim(value, `"`)); err == nil && seconds > 0 {
+			return time.Duration(seconds) * time.Second
+		}
+	}
+	return 0
+}
+
+// lookup mencari key berdasarkan kid; token tanpa kid diterima jika JWKS hanya berisi satu key.
+func (s *jwkSet) lookup(kid string) (interface{}, bool) {
+	if key, ok := s.keys[kid]; ok {
+		return key, true
+	}
+	if kid == "" && len(s.keys) == 1 {
+		for _, key := range s.keys {
+			return key, true
+		}
+	}
+	return nil, false
+}
+
+// JWKS mengembalikan public key yang dipakai untuk memverifikasi token manager ini: key
+// dari KeyManager (jika dipasang), key penandatangan aktif, dan key lama dari JWTConfig.PublicKeys.
+// Untuk HMAC hasilnya kosong karena secret tidak boleh dipublikasikan.
+//
+// Returns:
+//   - JSONWebKeySet: dokumen JWKS
+func (m *JWTManager) JWKS() JSONWebKeySet {
+	keys := []JSONWebKey{}
+	if m.keyManager != nil {
+		keys = append(keys, m.keyManager.JWKS().Keys...)
+	}
+	for _, key := range m.publicKeys {
+		if !slices.ContainsFunc(keys, func(k JSONWebKey) bool { return k.Kid == key.Kid }) {
+			keys = append(keys, key)
+		}
+	}
+	return JSONWebKeySet{Keys: keys}
+}
+
+// JWKSHandler mengembalikan handler yang melayani JWKS manager ini agar service lain dapat
+// memverifikasi token lewat JWT_JWKS_URL. Response di-cache client selama 5 menit.
+//
+// Returns:
+//   - HandlerFunc: handler GET JWKS
+//
+// Example:
+//
+//	router.Get("/.well-known/jwks.json", jwtManager.JWKSHandler())
+func (m *JWTManager) JWKSHandler() HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := json.Marshal(m.JWKS())
+		if err != nil {
+			InternalServerError(w, "Gagal membuat JWKS")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		w.WriteHeader(http.StatusOK)
+		w.Write(body)
+	}
+}
@@ -0,0 +1,256 @@
+package dim
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func newTestRSAPrivateKeyPEM(t *testing.T) (*rsa.PrivateKey, string) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block := &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}
+	return key, string(pem.EncodeToMemory(block))
+}
+
+func newTestRSAIssuer(t *testing.T) *JWTManager {
+	t.Helper()
+	_, privatePEM := newTestRSAPrivateKeyPEM(t)
+	manager, err := NewJWTManager(&JWTConfig{
+		SigningMethod:      "RS256",
+		PrivateKey:         privatePEM,
+		AccessTokenExpiry:  15 * time.Minute,
+		RefreshTokenExpiry: time.Hour,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return manager
+}
+
+func TestJWTManager_JWKSHandler(t *testing.T) {
+	oldKey, _ := newTestRSAPrivateKeyPEM(t)
+	oldPublic, _ := x509.MarshalPKIXPublicKey(&oldKey.PublicKey)
+	_, privatePEM := newTestRSAPrivateKeyPEM(t)
+	manager, err := NewJWTManager(&JWTConfig{
+		SigningMethod:     "RS256",
+		PrivateKey:        privatePEM,
+		PublicKeys:        map[string]string{"2024-01": string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: oldPublic}))},
+		AccessTokenExpiry: 15 * time.Minute,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := httptest.NewRecorder()
+	manager.JWKSHandler()(rec, httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil))
+	if rec.Code != http.StatusOK || rec.Header().Get("Cache-Control") == "" {
+		t.Fatalf("JWKSHandler = %d, headers %v", rec.Code, rec.Header())
+	}
+	var set JSONWebKeySet
+	if err := json.Unmarshal(rec.Body.Bytes(), &set); err != nil {
+		t.Fatal(err)
+	}
+	if len(set.Keys) != 2 || set.Keys[1].Kid != "2024-01" {
+		t.Fatalf("keys = %+v, want active key and 2024-01", set.Keys)
+	}
+	active := set.Keys[0]
+	if active.Kty != "RSA" || active.Alg != "RS256" || active.Use != "sig" || active.Kid != active.Thumbprint() {
+		t.Errorf("active key = %+v", active)
+	}
+
+	// Token membawa kid key aktif
+	token, _ := manager.GenerateAccessToken("1", "ana@example.com", "sid", nil)
+	parsed, _, _ := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
+	if parsed.Header["kid"] != active.Kid {
+		t.Errorf("token kid = %v, want %s", parsed.Header["kid"], active.Kid)
+	}
+
+	// HMAC tidak pernah mempublikasikan secret
+	hmac, _ := NewJWTManager(&JWTConfig{SigningMethod: "HS256", HMACSecret: "secret"})
+	if keys := hmac.JWKS().Keys; len(keys) != 0 {
+		t.Errorf("HMAC JWKS = %+v, want empty", keys)
+	}
+}
+
+func TestNewJSONWebKey_EC(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwk, err := NewJSONWebKey("", "ES256", &key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if jwk.Kty != "EC" || jwk.Crv != "P-256" || len(jwk.X) != 43 || jwk.Kid != jwk.Thumbprint() {
+		t.Errorf("jwk = %+v", jwk)
+	}
+	pub, err := jwk.publicKey()
+	if err != nil || !key.PublicKey.Equal(pub) {
+		t.Errorf("round trip public key mismatch: %v", err)
+	}
+	if _, err := NewJSONWebKey("k", "HS256", []byte("secret")); err == nil {
+		t.Error("expected error for symmetric key")
+	}
+}
+
+func TestJWTManager_RemoteJWKS(t *testing.T) {
+	issuer := newTestRSAIssuer(t)
+	var current atomic.Pointer[JWTManager]
+	current.Store(issuer)
+	var fetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		current.Load().JWKSHandler()(w, r)
+	}))
+	defer server.Close()
+
+	verifier, err := NewJWTManager(&JWTConfig{SigningMethod: "RS256", JWKSURL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, _ := issuer.GenerateAccessToken("1", "ana@example.com", "sid", nil)
+	for i := 0; i < 2; i++ {
+		claims, err := verifier.VerifyToken(token)
+		if err != nil || claims["sub"] != "1" {
+			t.Fatalf("VerifyToken = %v, %v", claims, err)
+		}
+	}
+	if n := fetches.Load(); n != 1 {
+		t.Errorf("JWKS fetched %d times, want 1 (cached)", n)
+	}
+
+	// Token dari key lain ditolak tanpa fetch berulang
+	rotated := newTestRSAIssuer(t)
+	rotatedToken, _ := rotated.GenerateAccessToken("2", "budi@example.com", "sid", nil)
+	if _, err := verifier.VerifyToken(rotatedToken); err == nil {
+		t.Fatal("expected token signed by an unknown key to be rejected")
+	}
+	if n := fetches.Load(); n != 1 {
+		t.Errorf("JWKS fetched %d times within the refresh interval, want 1", n)
+	}
+
+	// Setelah interval, kid baru memicu fetch ulang (rotasi key penerbit)
+	current.Store(rotated)
+	verifier.jwks.fetchedAt = time.Now().Add(-2 * jwkSetMinRefresh)
+	if claims, err := verifier.VerifyToken(rotatedToken); err != nil || claims["sub"] != "2" {
+		t.Fatalf("VerifyToken after rotation = %v, %v", claims, err)
+	}
+	if n := fetches.Load(); n != 2 {
+		t.Errorf("JWKS fetched %d times, want 2", n)
+	}
+}
+
+func TestJWTManager_RemoteJWKSMaxAge(t *testing.T) {
+	issuer := newTestRSAIssuer(t)
+	var fetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		issuer.JWKSHandler()(w, r)
+	}))
+	defer server.Close()
+
+	verifier, err := NewJWTManager(&JWTConfig{SigningMethod: "RS256", JWKSURL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, _ := issuer.GenerateAccessToken("1", "ana@example.com", "sid", nil)
+	if _, err := verifier.VerifyToken(token); err != nil {
+		t.Fatal(err)
+	}
+	if ttl := time.Until(verifier.jwks.expiresAt); ttl < 4*time.Minute || ttl > 5*time.Minute {
+		t.Errorf("cache expires in %v, want Cache-Control max-age of 5m", ttl)
+	}
+
+	// Setelah max-age habis, key yang sudah dikenal tetap di-fetch ulang (key yang dicabut hilang)
+	verifier.jwks.expiresAt = time.Now().Add(-time.Second)
+	verifier.jwks.fetchedAt = time.Now().Add(-2 * jwkSetMinRefresh)
+	if _, err := verifier.VerifyToken(token); err != nil {
+		t.Fatal(err)
+	}
+	if n := fetches.Load(); n != 2 {
+		t.Errorf("JWKS fetched %d times, want 2 after max-age", n)
+	}
+
+	// TTL yang dikonfigurasi mengalahkan Cache-Control
+	configured, _ := NewJWTManager(&JWTConfig{SigningMethod: "RS256", JWKSURL: server.URL, JWKSCacheTTL: 10 * time.Minute})
+	if _, err := configured.VerifyToken(token); err != nil {
+		t.Fatal(err)
+	}
+	if ttl := time.Until(configured.jwks.expiresAt); ttl < 9*time.Minute {
+		t.Errorf("cache expires in %v, want configured 10m", ttl)
+	}
+}
+
+func TestJWTManager_RemoteJWKSSlowFetch(t *testing.T) {
+	issuer := newTestRSAIssuer(t)
+	release := make(chan struct{})
+	var slow atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if slow.Load() {
+			<-release
+		}
+		issuer.JWKSHandler()(w, r)
+	}))
+	defer server.Close()
+	defer close(release)
+
+	verifier, err := NewJWTManager(&JWTConfig{SigningMethod: "RS256", JWKSURL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, _ := issuer.GenerateAccessToken("1", "ana@example.com", "sid", nil)
+	if _, err := verifier.VerifyToken(token); err != nil {
+		t.Fatal(err)
+	}
+
+	// Cache basi dan endpoint JWKS lambat: fetch berjalan di background request pertama
+	slow.Store(true)
+	verifier.jwks.expiresAt = time.Now().Add(-time.Second)
+	verifier.jwks.fetchedAt = time.Now().Add(-2 * jwkSetMinRefresh)
+	go verifier.VerifyToken(token)
+	for deadline := time.Now().Add(time.Second); ; {
+		verifier.jwks.mu.Lock()
+		fetching := verifier.jwks.fetching != nil
+		verifier.jwks.mu.Unlock()
+		if fetching {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected a JWKS fetch to be in flight")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// Request lain tidak menunggu fetch tersebut untuk key yang sudah di-cache
+	if _, err := verifier.VerifyToken(token); err != nil {
+		t.Errorf("expected cached key to be served during fetch, got %v", err)
+	}
+
+	// Kid yang belum dikenal menunggu fetch, tetapi berhenti saat context request dibatalkan
+	rotated := newTestRSAIssuer(t)
+	rotatedToken, _ := rotated.GenerateAccessToken("2", "budi@example.com", "sid", nil)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := verifier.VerifyTokenContext(ctx, rotatedToken); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("VerifyTokenContext error = %v, want context.DeadlineExceeded", err)
+	}
+}
@@ -1,20 +1,41 @@
 package dim
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
+	"maps"
+	"net/http"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// Error verifikasi JWT. Error dari VerifyToken dan VerifyRefreshToken membungkus salah satu
+// error ini sehingga dapat diperiksa dengan errors.Is.
+var (
+	// ErrTokenExpired dikembalikan jika token melewati exp (setelah ClockSkewLeeway).
+	ErrTokenExpired = jwt.ErrTokenExpired
+	// ErrTokenInvalidIssuer dikembalikan jika JWTConfig.Issuer diisi dan claim iss token berbeda atau kosong.
+	ErrTokenInvalidIssuer = jwt.ErrTokenInvalidIssuer
+	// ErrTokenInvalidAudience dikembalikan jika JWTConfig.Audience diisi dan claim aud token tidak
+	// memuat satu pun audience tersebut.
+	ErrTokenInvalidAudience = jwt.ErrTokenInvalidAudience
+)
+
 // JWTManager handles JWT operations
 type JWTManager struct {
 	config         *JWTConfig
 	signingKey     interface{}            // []byte for HMAC, *rsa.PrivateKey for RSA
+	signingKeyID   string                 // kid header for asymmetric tokens (RFC 7638 thumbprint)
 	validationKeys map[string]interface{} // map[kid]PublicKey (or []byte for HMAC rotation)
+	publicKeys     []JSONWebKey           // served by JWKS
+	jwks           *jwkSet                // remote keys from JWTConfig.JWKSURL
+	keyManager     *KeyManager            // rotating signing keys, takes precedence over signingKey
+	client         *http.Client
 }
 
 // NewJWTManager membuat JWT manager baru dengan konfigurasi yang diberikan.
@@ -23,6 +44,10 @@ type JWTManager struct {
 // Parameters:
 //   - config: pointer ke struct JWTConfig yang berisi preferensi signing dan kunci
 //
+// Untuk RSA/ECDSA, token diberi header kid (thumbprint public key) sehingga dapat diverifikasi
+// service lain lewat JWKS. Jika JWKSURL diisi, token dengan kid yang tidak dikenal diverifikasi
+// dengan key dari endpoint JWKS tersebut (di-cache dan diambil ulang saat kid baru muncul).
+//
 // Returns:
 //   - *JWTManager: instance manager yang siap digunakan
 //   - error: error jika parsing kunci gagal atau konfigurasi tidak valid
@@ -30,6 +55,7 @@ func NewJWTManager(config *JWTConfig) (*JWTManager, error) {
 	manager := &JWTManager{
 		config:         config,
 		validationKeys: make(map[string]interface{}),
+		client:         &http.Client{Timeout: 10 * time.Second},
 	}
 
 	// 1. Parse Signing Key based on Method
@@ -81,9 +107,74 @@ func NewJWTManager(config *JWTConfig) (*JWTManager, error) {
 		}
 	}
 
+	// 3. Publish public keys (JWKS) and tag the active key with its thumbprint
+	if key, ok := manager.validationKeys["default"]; ok && !strings.HasPrefix(config.SigningMethod, "HS") {
+		jwk, err := NewJSONWebKey("", config.SigningMethod, key)
+		if err != nil {
+			return nil, err
+		}
+		manager.signingKeyID = jwk.Kid
+		manager.validationKeys[jwk.Kid] = key
+		manager.publicKeys = append(manager.publicKeys, jwk)
+	}
+	for _, kid := range slices.Sorted(maps.Keys(config.PublicKeys)) {
+		if key, ok := manager.validationKeys[kid]; ok {
+			jwk, err := NewJSONWebKey(kid, config.SigningMethod, key)
+			if err != nil {
+				return nil, err
+			}
+			manager.publicKeys = append(manager.publicKeys, jwk)
+		}
+	}
+
+	// 4. Remote verification keys
+	if config.JWKSURL != "" && !strings.HasPrefix(config.SigningMethod, "HS") {
+		manager.jwks = &jwkSet{url: config.JWKSURL, maxAge: config.JWKSCacheTTL}
+	}
+
 	return manager, nil
 }
 
+// WithHTTPClient mengganti HTTP client untuk mengambil JWKS remote (default timeout 10 detik)
+// dan mengembalikan manager yang sama.
+func (m *JWTManager) WithHTTPClient(client *http.Client) *JWTManager {
+	m.client = client
+	return m
+}
+
+// WithKeyManager memakai key dari KeyManager untuk menandatangani token baru dan mengembalikan
+// manager yang sama. Token ditandatangani key aktif KeyManager (dengan algoritma dan kid-nya);
+// token dengan kid dari key lama tetap diterima selama masa grace, dan JWKS ikut memuat key
+// tersebut. Key dari JWTConfig tetap dipakai untuk verifikasi token yang terbit sebelum migrasi.
+//
+// Example:
+//
+//	keys, err := dim.NewKeyManager(ctx, dim.NewDatabaseKeyStore(db), dim.KeyManagerConfig{})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	jwtManager.WithKeyManager(keys)
+//	keys.Start(ctx, time.Hour)
+func (m *JWTManager) WithKeyManager(keys *KeyManager) *JWTManager {
+	m.keyManager = keys
+	return m
+}
+
+// signer returns the signing method, key, and kid for new tokens.
+func (m *JWTManager) signer() (jwt.SigningMethod, interface{}, string, error) {
+	if m.keyManager != nil {
+		if key := m.keyManager.SigningKey(); key != nil {
+			return jwt.GetSigningMethod(key.Algorithm), key.PrivateKey, key.ID, nil
+		}
+	}
+
+	method := jwt.GetSigningMethod(m.config.SigningMethod)
+	if method == nil {
+		return nil, nil, "", fmt.Errorf("invalid signing method: %s", m.config.SigningMethod)
+	}
+	return method, m.signingKey, m.signingKeyID, nil
+}
+
 // GenerateAccessToken membuat access token JWT baru untuk user dengan expiry yang sudah dikonfigurasi.
 // Token ditandatangani menggunakan metode dan kunci yang aktif saat ini.
 //
@@ -109,6 +200,7 @@ func (m *JWTManager) GenerateAccessToken(userID string, email string, sessionID
 		"exp":   expiresAt.Unix(),
 		"nbf":   now.Unix(),
 	}
+	m.setIssuerClaims(claims)
 
 	// Add extra claims
 	for k, v := range extraClaims {
@@ -116,9 +208,9 @@ func (m *JWTManager) GenerateAccessToken(userID string, email string, sessionID
 	}
 
 	// Determine Signing Method
-	method := jwt.GetSigningMethod(m.config.SigningMethod)
-	if method == nil {
-		return "", fmt.Errorf("invalid signing method: %s", m.config.SigningMethod)
+	method, key, kid, err := m.signer()
+	if err != nil {
+		return "", err
 	}
 
 	token := jwt.NewWithClaims(method, claims)
@@ -127,15 +219,12 @@ func (m *JWTManager) GenerateAccessToken(userID string, email string, sessionID
 	// Sesuai dengan RFC 9068 (JSON Web Token Profile for OAuth 2.0 Access Tokens)
 	token.Header["typ"] = "at+jwt"
 
-	// Jika menggunakan Asymmetric, tambahkan 'kid' ke header jika logika sistem membutuhkannya.
-	// Logika saat ini menggunakan "default" yang berasal dari PrivateKey. Implementasi nyata mungkin menggunakan KID tertentu.
-	// Demi kesederhanaan, kita tidak menyetel KID di header untuk saat ini kecuali kita mengimplementasikan manajemen rotasi penuh untuk Signing Key.
-	// Namun untuk pencocokan validasi, jika kita memiliki beberapa kunci validasi, kita memerlukan KID.
-	// Ini hanya jika kita berada dalam fase rotasi. Untuk saat ini, mari kita buat sederhana.
-	// Standar JWT menggunakan header 'kid'.
-	// Kita asumsikan kunci aktif saat ini tidak memiliki KID tertentu atau "default".
+	// Key asimetris diberi kid agar verifier (termasuk service lain via JWKS) dapat memilih key
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
 
-	return token.SignedString(m.signingKey)
+	return token.SignedString(key)
 }
 
 // GenerateRefreshToken membuat refresh token JWT baru untuk user dengan expiry lebih panjang.
@@ -149,8 +238,14 @@ func (m *JWTManager) GenerateAccessToken(userID string, email string, sessionID
 //   - string: signed JWT string
 //   - error: error jika signing gagal
 func (m *JWTManager) GenerateRefreshToken(userID, sessionID string) (string, error) {
+	return m.GenerateRefreshTokenWithExpiry(userID, sessionID, time.Now().Add(m.config.RefreshTokenExpiry))
+}
+
+// GenerateRefreshTokenWithExpiry membuat refresh token JWT dengan waktu kadaluarsa tertentu,
+// misal untuk session "remember me" yang lebih panjang dari RefreshTokenExpiry.
+// Mengimplementasikan RefreshTokenExpiryGenerator.
+func (m *JWTManager) GenerateRefreshTokenWithExpiry(userID, sessionID string, expiresAt time.Time) (string, error) {
 	now := time.Now()
-	expiresAt := now.Add(m.config.RefreshTokenExpiry)
 
 	// Gunakan MapClaims agar bisa menambahkan custom claim 'sid'
 	claims := jwt.MapClaims{
@@ -161,11 +256,12 @@ func (m *JWTManager) GenerateRefreshToken(userID, sessionID string) (string, err
 		"exp": expiresAt.Unix(),
 		"nbf": now.Unix(),
 	}
+	m.setIssuerClaims(claims)
 
 	// Determine Signing Method
-	method := jwt.GetSigningMethod(m.config.SigningMethod)
-	if method == nil {
-		return "", fmt.Errorf("invalid signing method: %s", m.config.SigningMethod)
+	method, key, kid, err := m.signer()
+	if err != nil {
+		return "", err
 	}
 
 	token := jwt.NewWithClaims(method, claims)
@@ -173,12 +269,73 @@ func (m *JWTManager) GenerateRefreshToken(userID, sessionID string) (string, err
 	// Set typ header to "rt+jwt" untuk menandakan ini adalah refresh token
 	// Sesuai dengan konvensi RFC 9068
 	token.Header["typ"] = "rt+jwt"
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+
+	return token.SignedString(key)
+}
+
+// setIssuerClaims sets iss and aud from the config. A single audience is encoded as a string.
+func (m *JWTManager) setIssuerClaims(claims jwt.MapClaims) {
+	if m.config.Issuer != "" {
+		claims["iss"] = m.config.Issuer
+	}
+	switch len(m.config.Audience) {
+	case 0:
+	case 1:
+		claims["aud"] = m.config.Audience[0]
+	default:
+		claims["aud"] = m.config.Audience
+	}
+}
+
+// parseToken parses and verifies a token, applying ClockSkewLeeway to exp/nbf and
+// checking iss/aud against the config.
+func (m *JWTManager) parseToken(ctx context.Context, tokenString string, claims jwt.MapClaims) (*jwt.Token, error) {
+	token, err := jwt.ParseWithClaims(tokenString, claims, m.keyFunc(ctx), jwt.WithLeeway(m.config.ClockSkewLeeway))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse token: %w", err)
+	}
+	if !token.Valid {
+		return nil, fmt.Errorf("invalid token")
+	}
+
+	if m.config.Issuer != "" {
+		if iss, _ := claims.GetIssuer(); iss != m.config.Issuer {
+			return nil, fmt.Errorf("%w: %q", ErrTokenInvalidIssuer, iss)
+		}
+	}
+	if len(m.config.Audience) > 0 {
+		aud, _ := claims.GetAudience()
+		if !slices.ContainsFunc(aud, func(a string) bool { return slices.Contains(m.config.Audience, a) }) {
+			return nil, fmt.Errorf("%w: %q", ErrTokenInvalidAudience, []string(aud))
+		}
+	}
+	return token, nil
+}
 
-	return token.SignedString(m.signingKey)
+// keyFunc returns a jwt.Keyfunc that fetches remote JWKS keys with ctx.
+func (m *JWTManager) keyFunc(ctx context.Context) jwt.Keyfunc {
+	return func(token *jwt.Token) (interface{}, error) {
+		return m.verifyKey(ctx, token)
+	}
 }
 
-// verifyKeyFunc validates the token method and selects the correct key.
-func (m *JWTManager) verifyKeyFunc(token *jwt.Token) (interface{}, error) {
+// verifyKey validates the token method and selects the correct key.
+func (m *JWTManager) verifyKey(ctx context.Context, token *jwt.Token) (interface{}, error) {
+	// 0. Rotating keys: the kid pins both the key and its exact algorithm
+	if m.keyManager != nil {
+		if kid, _ := token.Header["kid"].(string); kid != "" {
+			if key := m.keyManager.verificationKey(kid); key != nil {
+				if token.Method.Alg() != key.Algorithm {
+					return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+				}
+				return key.PublicKey(), nil
+			}
+		}
+	}
+
 	// 1. Validate Algorithm family
 	switch {
 	case strings.HasPrefix(m.config.SigningMethod, "HS"):
@@ -198,7 +355,8 @@ func (m *JWTManager) verifyKeyFunc(token *jwt.Token) (interface{}, error) {
 	}
 
 	// 2. Select Key (Support Rotation)
-	if kid, ok := token.Header["kid"].(string); ok {
+	kid, _ := token.Header["kid"].(string)
+	if kid != "" {
 		if key, ok := m.validationKeys[kid]; ok {
 			return key, nil
 		}
@@ -206,6 +364,11 @@ func (m *JWTManager) verifyKeyFunc(token *jwt.Token) (interface{}, error) {
 		// But if we only have default key and no headers, we fallback.
 	}
 
+	// 3. Unknown kid (or no local key): ask the remote JWKS, refetching on key rotation
+	if _, hasDefault := m.validationKeys["default"]; m.jwks != nil && (kid != "" || !hasDefault) {
+		return m.jwks.key(ctx, m.client, kid)
+	}
+
 	// Fallback to default key (current active key)
 	if key, ok := m.validationKeys["default"]; ok {
 		return key, nil
@@ -215,25 +378,33 @@ func (m *JWTManager) verifyKeyFunc(token *jwt.Token) (interface{}, error) {
 }
 
 // VerifyToken memverifikasi access token dan mengembalikan claims di dalamnya.
-// Mendukung rotasi kunci melalui header 'kid'.
+// Mendukung rotasi kunci melalui header 'kid'. Jika JWTConfig.Issuer/Audience diisi, claim iss/aud
+// wajib cocok; exp dan nbf diperiksa dengan toleransi JWTConfig.ClockSkewLeeway.
 //
 // Parameters:
 //   - tokenString: raw JWT string yang diterima dari client
 //
 // Returns:
 //   - TokenClaims: klaim-klaim yang ada di dalam token jika valid
-//   - error: error jika signature tidak valid, token kedaluwarsa, atau format salah
+//   - error: error jika signature tidak valid, token kedaluwarsa (ErrTokenExpired), issuer atau
+//     audience tidak cocok (ErrTokenInvalidIssuer, ErrTokenInvalidAudience), atau format salah
 func (m *JWTManager) VerifyToken(tokenString string) (TokenClaims, error) {
-	claims := jwt.MapClaims{}
+	return m.VerifyTokenContext(context.Background(), tokenString)
+}
 
-	token, err := jwt.ParseWithClaims(tokenString, claims, m.verifyKeyFunc)
+// VerifyTokenContext sama dengan VerifyToken, tetapi memakai ctx saat mengambil JWKS remote
+// (JWTConfig.JWKSURL) sehingga fetch ikut dibatalkan bersama request. RequireAuth dan
+// OptionalAuth memakai method ini dengan context request.
+//
+// Example:
+//
+//	claims, err := jwtManager.VerifyTokenContext(r.Context(), token)
+func (m *JWTManager) VerifyTokenContext(ctx context.Context, tokenString string) (TokenClaims, error) {
+	claims := jwt.MapClaims{}
 
+	token, err := m.parseToken(ctx, tokenString, claims)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse token: %w", err)
-	}
-
-	if !token.Valid {
-		return nil, fmt.Errorf("invalid token")
+		return nil, err
 	}
 
 	// Validasi header typ untuk memastikan ini adalah access token (at+jwt)
@@ -260,13 +431,9 @@ func (m *JWTManager) VerifyRefreshToken(tokenString string) (string, string, err
 	// Gunakan MapClaims karena kita menggunakan sid (custom claim)
 	claims := jwt.MapClaims{}
 
-	token, err := jwt.ParseWithClaims(tokenString, claims, m.verifyKeyFunc)
+	token, err := m.parseToken(context.Background(), tokenString, claims)
 	if err != nil {
-		return "", "", fmt.Errorf("failed to parse token: %w", err)
-	}
-
-	if !token.Valid {
-		return "", "", fmt.Errorf("invalid token")
+		return "", "", err
 	}
 
 	// Validasi header typ untuk memastikan ini adalah refresh token (rt+jwt)
@@ -301,7 +468,7 @@ func (m *JWTManager) VerifyRefreshToken(tokenString string) (string, string, err
 func (m *JWTManager) GetTokenExpiry(tokenString string) (time.Time, error) {
 	claims := &jwt.RegisteredClaims{}
 
-	_, err := jwt.ParseWithClaims(tokenString, claims, m.verifyKeyFunc)
+	_, err := jwt.ParseWithClaims(tokenString, claims, m.keyFunc(context.Background()))
 
 	// Handle both parsing errors and expired tokens
 	if err != nil {
@@ -1,6 +1,7 @@
 package dim
 
 import (
+	"errors"
 	"testing"
 	"time"
 )
@@ -291,3 +292,74 @@ func TestDifferentSecrets(t *testing.T) {
 		t.Errorf("VerifyToken() with different secret should fail")
 	}
 }
+
+func TestVerifyToken_IssuerAndAudience(t *testing.T) {
+	newManager := func(issuer string, audience ...string) *JWTManager {
+		manager, err := NewJWTManager(&JWTConfig{
+			HMACSecret:         "test-secret",
+			SigningMethod:      "HS256",
+			AccessTokenExpiry:  15 * time.Minute,
+			RefreshTokenExpiry: time.Hour,
+			Issuer:             issuer,
+			Audience:           audience,
+		})
+		if err != nil {
+			t.Fatalf("NewJWTManager error: %v", err)
+		}
+		return manager
+	}
+
+	prod := newManager("https://auth.example.com", "api")
+	token, _ := prod.GenerateAccessToken("1", "test@example.com", "sid-1", nil)
+	claims, err := prod.VerifyToken(token)
+	if err != nil {
+		t.Fatalf("VerifyToken() error = %v", err)
+	}
+	if claims["iss"] != "https://auth.example.com" || claims["aud"] != "api" {
+		t.Errorf("iss/aud = %v/%v", claims["iss"], claims["aud"])
+	}
+
+	staging := newManager("https://auth.staging.example.com", "api")
+	stagingToken, _ := staging.GenerateAccessToken("1", "test@example.com", "sid-1", nil)
+	if _, err := prod.VerifyToken(stagingToken); !errors.Is(err, ErrTokenInvalidIssuer) {
+		t.Errorf("VerifyToken(staging) error = %v, want ErrTokenInvalidIssuer", err)
+	}
+
+	// Token tanpa iss/aud ditolak saat validasi diaktifkan
+	legacyToken, _ := newManager("").GenerateAccessToken("1", "test@example.com", "sid-1", nil)
+	if _, err := prod.VerifyToken(legacyToken); !errors.Is(err, ErrTokenInvalidIssuer) {
+		t.Errorf("VerifyToken(no iss) error = %v, want ErrTokenInvalidIssuer", err)
+	}
+
+	admin := newManager("https://auth.example.com", "admin", "billing")
+	adminRefresh, _ := admin.GenerateRefreshToken("1", "sid-1")
+	if _, _, err := prod.VerifyRefreshToken(adminRefresh); !errors.Is(err, ErrTokenInvalidAudience) {
+		t.Errorf("VerifyRefreshToken(other aud) error = %v, want ErrTokenInvalidAudience", err)
+	}
+	if _, _, err := newManager("https://auth.example.com", "billing").VerifyRefreshToken(adminRefresh); err != nil {
+		t.Errorf("any matching audience should be accepted: %v", err)
+	}
+}
+
+func TestVerifyToken_ClockSkewLeeway(t *testing.T) {
+	config := &JWTConfig{
+		HMACSecret:         "test-secret",
+		SigningMethod:      "HS256",
+		AccessTokenExpiry:  -10 * time.Second,
+		RefreshTokenExpiry: time.Hour,
+	}
+	manager, err := NewJWTManager(config)
+	if err != nil {
+		t.Fatalf("NewJWTManager error: %v", err)
+	}
+	token, _ := manager.GenerateAccessToken("1", "test@example.com", "sid-1", nil)
+
+	if _, err := manager.VerifyToken(token); !errors.Is(err, ErrTokenExpired) {
+		t.Errorf("VerifyToken() error = %v, want ErrTokenExpired", err)
+	}
+
+	config.ClockSkewLeeway = 30 * time.Second
+	if _, err := manager.VerifyToken(token); err != nil {
+		t.Errorf("VerifyToken() within leeway error = %v", err)
+	}
+}
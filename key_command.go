@@ -0,0 +1,77 @@
+package dim
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// KeyRotateCommand merotasi key penandatangan JWT milik KeyManager, misal dari cron job atau
+// saat key diduga bocor. Tidak termasuk built-in command karena membutuhkan KeyManager aplikasi.
+type KeyRotateCommand struct {
+	keys  *KeyManager
+	ifDue bool
+}
+
+// NewKeyRotateCommand membuat command "jwt:rotate" untuk KeyManager.
+//
+// Example:
+//
+//	console.Register(dim.NewKeyRotateCommand(keyManager))
+//	// go run . jwt:rotate           → rotasi sekarang
+//	// go run . jwt:rotate --if-due  → rotasi hanya jika key aktif sudah melewati RotationInterval
+func NewKeyRotateCommand(keys *KeyManager) *KeyRotateCommand {
+	return &KeyRotateCommand{keys: keys}
+}
+
+func (c *KeyRotateCommand) Name() string {
+	return "jwt:rotate"
+}
+
+func (c *KeyRotateCommand) Description() string {
+	return "Rotate the JWT signing key and prune expired keys"
+}
+
+func (c *KeyRotateCommand) DefineFlags(fs *flag.FlagSet) {
+	fs.BoolVar(&c.ifDue, "if-due", false, "Only rotate when the active key is older than the rotation interval")
+}
+
+func (c *KeyRotateCommand) Execute(ctx *CommandContext) error {
+	var out io.Writer = os.Stdout
+	if ctx.Out != nil {
+		out = ctx.Out
+	}
+
+	bg := context.Background()
+	if c.ifDue {
+		rotated, err := c.keys.RotateIfDue(bg)
+		if err != nil {
+			return err
+		}
+		if !rotated {
+			fmt.Fprintf(out, "Signing key %s is not due for rotation\n", c.keys.SigningKey().ID)
+		}
+	} else {
+		if err := c.keys.Reload(bg); err != nil {
+			return err
+		}
+		if _, err := c.keys.Rotate(bg); err != nil {
+			return err
+		}
+		if err := c.keys.Prune(bg); err != nil {
+			return err
+		}
+	}
+
+	for _, key := range c.keys.Keys() {
+		status := "active"
+		if !key.Active() {
+			status = "verify-only until " + key.ExpiresAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(out, "%s  %s  created %s  %s\n", key.ID, key.Algorithm, key.CreatedAt.Format(time.RFC3339), status)
+	}
+	return nil
+}
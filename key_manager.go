@@ -0,0 +1,364 @@
+package dim
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// ErrUnsupportedKeyAlgorithm dikembalikan jika algoritma key tidak didukung KeyManager.
+var ErrUnsupportedKeyAlgorithm = errors.New("unsupported signing key algorithm")
+
+// SigningKey adalah satu key penandatangan JWT yang dikelola KeyManager.
+// Key aktif (RetiredAt nil) dipakai untuk menandatangani token baru; key yang sudah dipensiunkan
+// hanya dipakai untuk verifikasi sampai ExpiresAt, lalu dihapus.
+type SigningKey struct {
+	ID         string // kid, thumbprint RFC 7638 public key
+	Algorithm  string // RS256, RS384, RS512, ES256, ES384, atau ES512
+	PrivateKey crypto.Signer
+	CreatedAt  time.Time
+	RetiredAt  *time.Time
+	ExpiresAt  *time.Time
+}
+
+// Active mengembalikan true jika key masih dipakai untuk menandatangani token baru.
+func (k *SigningKey) Active() bool {
+	return k.RetiredAt == nil
+}
+
+// Expired mengembalikan true jika masa grace key yang dipensiunkan sudah lewat.
+func (k *SigningKey) Expired(now time.Time) bool {
+	return k.ExpiresAt != nil && !now.Before(*k.ExpiresAt)
+}
+
+// PublicKey mengembalikan public key untuk verifikasi token.
+func (k *SigningKey) PublicKey() crypto.PublicKey {
+	return k.PrivateKey.Public()
+}
+
+// KeyManagerConfig berisi konfigurasi KeyManager.
+type KeyManagerConfig struct {
+	// Algorithm adalah algoritma key baru: RS256/RS384/RS512 atau ES256/ES384/ES512 (default: RS256).
+	Algorithm string
+	// RotationInterval adalah umur key aktif sebelum diganti oleh RotateIfDue (default: 30 hari).
+	RotationInterval time.Duration
+	// GracePeriod adalah lama key lama tetap dipakai untuk verifikasi setelah dipensiunkan
+	// (default: 7 hari). Samakan minimal dengan umur refresh token terpanjang.
+	GracePeriod time.Duration
+	// RSAKeySize adalah ukuran key RSA dalam bit (default: 2048).
+	RSAKeySize int
+}
+
+// KeyManager menyimpan beberapa key penandatangan JWT dan merotasinya secara berkala.
+// Token baru selalu ditandatangani key aktif dengan header kid, sementara key lama tetap
+// diterima selama GracePeriod sehingga rotasi tidak membuat session yang berjalan invalid.
+// Key dipersist lewat KeyStore agar semua instance aplikasi berbagi key yang sama. Thread-safe.
+type KeyManager struct {
+	store  KeyStore
+	config KeyManagerConfig
+	logger *Logger
+	now    func() time.Time
+
+	mu       sync.RWMutex
+	keys     []*SigningKey // terbaru lebih dulu
+	loadedAt time.Time
+}
+
+// NewKeyManager membuat KeyManager, memuat key dari store, dan membuat key baru jika belum
+// ada key aktif.
+//
+// Parameters:
+//   - ctx: context untuk memuat dan menyimpan key
+//   - store: penyimpanan key (FileKeyStore, DatabaseKeyStore, atau implementasi sendiri)
+//   - config: konfigurasi rotasi; field kosong memakai default
+//
+// Returns:
+//   - *KeyManager: manager yang siap dipasang ke JWTManager.WithKeyManager
+//   - error: error jika algoritma tidak didukung atau store gagal
+//
+// Example:
+//
+//	store := dim.NewDatabaseKeyStore(db).WithEncryptor(encryptor)
+//	keys, err := dim.NewKeyManager(ctx, store, dim.KeyManagerConfig{Algorithm: "ES256"})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	jwtManager.WithKeyManager(keys)
+//	keys.Start(ctx, time.Hour)
+func NewKeyManager(ctx context.Context, store KeyStore, config KeyManagerConfig) (*KeyManager, error) {
+	if config.Algorithm == "" {
+		config.Algorithm = "RS256"
+	}
+	if config.RotationInterval <= 0 {
+		config.RotationInterval = 30 * 24 * time.Hour
+	}
+	if config.GracePeriod <= 0 {
+		config.GracePeriod = 7 * 24 * time.Hour
+	}
+	if config.RSAKeySize <= 0 {
+		config.RSAKeySize = 2048
+	}
+	if !slices.Contains(signingKeyAlgorithms, config.Algorithm) {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedKeyAlgorithm, config.Algorithm)
+	}
+
+	km := &KeyManager{store: store, config: config, now: time.Now}
+	if err := km.Reload(ctx); err != nil {
+		return nil, err
+	}
+	if km.SigningKey() == nil {
+		if _, err := km.Rotate(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return km, nil
+}
+
+// WithLogger mengatur logger untuk rotasi otomatis dan mengembalikan instance manager.
+func (km *KeyManager) WithLogger(logger *Logger) *KeyManager {
+	km.logger = logger
+	return km
+}
+
+// Reload memuat ulang key dari store, misal setelah instance lain melakukan rotasi.
+// Jika store berisi lebih dari satu key aktif (rotasi bersamaan), hanya key terbaru yang
+// dipakai untuk menandatangani; key lainnya diperlakukan sebagai key lama.
+func (km *KeyManager) Reload(ctx context.Context) error {
+	keys, err := km.store.LoadKeys(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load signing keys: %w", err)
+	}
+
+	slices.SortFunc(keys, func(a, b *SigningKey) int { return b.CreatedAt.Compare(a.CreatedAt) })
+	var newest *SigningKey
+	for _, key := range keys {
+		if !key.Active() {
+			continue
+		}
+		if newest == nil {
+			newest = key
+			continue
+		}
+		retiredAt := newest.CreatedAt
+		expiresAt := retiredAt.Add(km.config.GracePeriod)
+		key.RetiredAt, key.ExpiresAt = &retiredAt, &expiresAt
+	}
+
+	km.mu.Lock()
+	km.keys = keys
+	km.loadedAt = km.now()
+	km.mu.Unlock()
+	return nil
+}
+
+// Rotate membuat key aktif baru dan mempensiunkan key aktif sebelumnya menjadi verify-only
+// selama GracePeriod. Dapat dipanggil manual, misal saat key diduga bocor.
+//
+// Returns:
+//   - *SigningKey: key aktif yang baru
+//   - error: error jika pembuatan atau penyimpanan key gagal
+func (km *KeyManager) Rotate(ctx context.Context) (*SigningKey, error) {
+	signer, err := generateSigningKey(km.config.Algorithm, km.config.RSAKeySize)
+	if err != nil {
+		return nil, err
+	}
+	jwk, err := NewJSONWebKey("", km.config.Algorithm, signer.Public())
+	if err != nil {
+		return nil, err
+	}
+
+	now := km.now().UTC().Truncate(time.Second)
+	key := &SigningKey{ID: jwk.Kid, Algorithm: km.config.Algorithm, PrivateKey: signer, CreatedAt: now}
+
+	km.mu.Lock()
+	defer km.mu.Unlock()
+
+	// Simpan key baru lebih dulu: jika mempensiunkan key lama gagal, Reload tetap memilih key terbaru
+	if err := km.store.SaveKey(ctx, key); err != nil {
+		return nil, fmt.Errorf("failed to save signing key: %w", err)
+	}
+	for _, old := range km.keys {
+		if !old.Active() {
+			continue
+		}
+		expiresAt := now.Add(km.config.GracePeriod)
+		old.RetiredAt, old.ExpiresAt = &now, &expiresAt
+		if err := km.store.SaveKey(ctx, old); err != nil {
+			return nil, fmt.Errorf("failed to retire signing key %s: %w", old.ID, err)
+		}
+	}
+	km.keys = append([]*SigningKey{key}, km.keys...)
+	return key, nil
+}
+
+// RotateIfDue memuat ulang key dari store, merotasi key aktif jika umurnya sudah mencapai
+// RotationInterval, lalu menghapus key lama yang masa grace-nya sudah lewat.
+//
+// Returns:
+//   - bool: true jika rotasi dilakukan
+//   - error: error jika store gagal
+func (km *KeyManager) RotateIfDue(ctx context.Context) (bool, error) {
+	if err := km.Reload(ctx); err != nil {
+		return false, err
+	}
+
+	rotated := false
+	if active := km.SigningKey(); active == nil || !km.now().Before(active.CreatedAt.Add(km.config.RotationInterval)) {
+		if _, err := km.Rotate(ctx); err != nil {
+			return false, err
+		}
+		rotated = true
+	}
+	return rotated, km.Prune(ctx)
+}
+
+// Prune menghapus key lama yang masa grace-nya sudah lewat dari store dan memori.
+func (km *KeyManager) Prune(ctx context.Context) error {
+	km.mu.Lock()
+	defer km.mu.Unlock()
+
+	now := km.now()
+	kept := make([]*SigningKey, 0, len(km.keys))
+	var err error
+	for _, key := range km.keys {
+		if key.Expired(now) && err == nil {
+			if err = km.store.DeleteKey(ctx, key.ID); err == nil {
+				continue
+			}
+			err = fmt.Errorf("failed to delete signing key %s: %w", key.ID, err)
+		}
+		kept = append(kept, key)
+	}
+	km.keys = kept
+	return err
+}
+
+// Start menjalankan RotateIfDue segera lalu setiap interval di background sampai ctx dibatalkan.
+// Interval menentukan seberapa cepat instance melihat rotasi dari instance lain; 1 jam cukup
+// untuk RotationInterval dalam hitungan hari. Error dicatat ke logger.
+//
+// Parameters:
+//   - ctx: context yang menghentikan rotasi otomatis saat dibatalkan
+//   - interval: jeda antar pemeriksaan
+func (km *KeyManager) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			rotated, err := km.RotateIfDue(ctx)
+			if km.logger != nil {
+				if err != nil {
+					km.logger.Error("JWT signing key rotation failed", "error", err.Error())
+				} else if rotated {
+					km.logger.Info("JWT signing key rotated", "kid", km.SigningKey().ID)
+				}
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// SigningKey mengembalikan key aktif untuk menandatangani token baru, atau nil jika belum ada.
+func (km *KeyManager) SigningKey() *SigningKey {
+	km.mu.RLock()
+	defer km.mu.RUnlock()
+	for _, key := range km.keys {
+		if key.Active() {
+			return key
+		}
+	}
+	return nil
+}
+
+// Keys mengembalikan semua key yang masih dapat dipakai untuk verifikasi, terbaru lebih dulu.
+func (km *KeyManager) Keys() []*SigningKey {
+	km.mu.RLock()
+	defer km.mu.RUnlock()
+	now := km.now()
+	keys := make([]*SigningKey, 0, len(km.keys))
+	for _, key := range km.keys {
+		if !key.Expired(now) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// JWKS mengembalikan public key dari semua key yang masih dapat dipakai untuk verifikasi.
+func (km *KeyManager) JWKS() JSONWebKeySet {
+	set := JSONWebKeySet{Keys: []JSONWebKey{}}
+	for _, key := range km.Keys() {
+		if jwk, err := NewJSONWebKey(key.ID, key.Algorithm, key.PublicKey()); err == nil {
+			set.Keys = append(set.Keys, jwk)
+		}
+	}
+	return set
+}
+
+// verificationKey mencari key berdasarkan kid. Kid yang tidak dikenal memicu Reload (paling
+// sering sekali per jwkSetMinRefresh) karena instance lain mungkin sudah merotasi key.
+func (km *KeyManager) verificationKey(kid string) *SigningKey {
+	if key := km.lookup(kid); key != nil {
+		return key
+	}
+
+	km.mu.RLock()
+	stale := km.now().Sub(km.loadedAt) >= jwkSetMinRefresh
+	km.mu.RUnlock()
+	if !stale || km.Reload(context.Background()) != nil {
+		return nil
+	}
+	return km.lookup(kid)
+}
+
+func (km *KeyManager) lookup(kid string) *SigningKey {
+	km.mu.RLock()
+	defer km.mu.RUnlock()
+	for _, key := range km.keys {
+		if key.ID == kid && !key.Expired(km.now()) {
+			return key
+		}
+	}
+	return nil
+}
+
+// signingKeyAlgorithms adalah algoritma JWS yang didukung KeyManager.
+var signingKeyAlgorithms = []string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}
+
+// generateSigningKey membuat private key untuk algoritma JWS. bits hanya dipakai untuk RSA.
+func generateSigningKey(alg string, bits int) (crypto.Signer, error) {
+	var curve elliptic.Curve
+	switch alg {
+	case "RS256", "RS384", "RS512":
+		key, err := rsa.GenerateKey(rand.Reader, bits)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate RSA key: %w", err)
+		}
+		return key, nil
+	case "ES256":
+		curve = elliptic.P256()
+	case "ES384":
+		curve = elliptic.P384()
+	case "ES512":
+		curve = elliptic.P521()
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedKeyAlgorithm, alg)
+	}
+	key, err := ecdsa.GenerateKey(curve, rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate ECDSA key: %w", err)
+	}
+	return key, nil
+}
@@ -0,0 +1,284 @@
+package dim
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type testClock struct{ t time.Time }
+
+func (c *testClock) now() time.Time { return c.t }
+
+func newTestKeyManager(t *testing.T, store KeyStore, clock *testClock) *KeyManager {
+	t.Helper()
+	km, err := NewKeyManager(context.Background(), store, KeyManagerConfig{
+		Algorithm:        "ES256",
+		RotationInterval: 24 * time.Hour,
+		GracePeriod:      time.Hour,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clock != nil {
+		km.now = clock.now
+	}
+	return km
+}
+
+func newTestKeyManagerJWT(t *testing.T, km *KeyManager) *JWTManager {
+	t.Helper()
+	manager, err := NewJWTManager(&JWTConfig{
+		SigningMethod:      "HS256",
+		HMACSecret:         "legacy-secret",
+		AccessTokenExpiry:  15 * time.Minute,
+		RefreshTokenExpiry: time.Hour,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return manager.WithKeyManager(km)
+}
+
+func tokenKid(t *testing.T, token string) string {
+	t.Helper()
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	kid, _ := parsed.Header["kid"].(string)
+	return kid
+}
+
+func TestKeyManager_RotateKeepsOldKeyDuringGrace(t *testing.T) {
+	ctx := context.Background()
+	clock := &testClock{t: time.Now()}
+	km := newTestKeyManager(t, NewMockKeyStore(), clock)
+	manager := newTestKeyManagerJWT(t, km)
+
+	oldToken, err := manager.GenerateAccessToken("u-1", "a@example.com", "s-1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldKid := tokenKid(t, oldToken)
+	if oldKid != km.SigningKey().ID {
+		t.Fatalf("kid = %q, want active key %q", oldKid, km.SigningKey().ID)
+	}
+
+	if _, err := km.Rotate(ctx); err != nil {
+		t.Fatal(err)
+	}
+	newToken, _ := manager.GenerateAccessToken("u-1", "a@example.com", "s-1", nil)
+	if tokenKid(t, newToken) == oldKid {
+		t.Fatal("new token should use the rotated key")
+	}
+	for _, token := range []string{oldToken, newToken} {
+		if _, err := manager.VerifyToken(token); err != nil {
+			t.Errorf("VerifyToken during grace: %v", err)
+		}
+	}
+	if got := len(manager.JWKS().Keys); got != 2 {
+		t.Errorf("JWKS keys = %d, want 2", got)
+	}
+
+	clock.t = clock.t.Add(2 * time.Hour)
+	if err := km.Prune(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := manager.VerifyToken(oldToken); err == nil {
+		t.Error("token signed by expired key should be rejected")
+	}
+	if _, err := manager.VerifyToken(newToken); err != nil {
+		t.Errorf("VerifyToken(new) = %v", err)
+	}
+	if keys := km.Keys(); len(keys) != 1 || !keys[0].Active() {
+		t.Errorf("Keys after prune = %+v", keys)
+	}
+}
+
+func TestKeyManager_RotateIfDue(t *testing.T) {
+	ctx := context.Background()
+	clock := &testClock{t: time.Now()}
+	km := newTestKeyManager(t, NewMockKeyStore(), clock)
+	first := km.SigningKey().ID
+
+	if rotated, err := km.RotateIfDue(ctx); err != nil || rotated {
+		t.Fatalf("RotateIfDue = %v, %v; want false", rotated, err)
+	}
+
+	clock.t = clock.t.Add(25 * time.Hour)
+	if rotated, err := km.RotateIfDue(ctx); err != nil || !rotated {
+		t.Fatalf("RotateIfDue = %v, %v; want true", rotated, err)
+	}
+	if km.SigningKey().ID == first {
+		t.Error("active key should change after rotation")
+	}
+}
+
+func TestKeyManager_SharedStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewMockKeyStore()
+	clock := &testClock{t: time.Now()}
+	km1 := newTestKeyManager(t, store, clock)
+	km2 := newTestKeyManager(t, store, nil)
+	if km1.SigningKey().ID != km2.SigningKey().ID {
+		t.Fatal("second instance should reuse the stored key")
+	}
+
+	if _, err := km2.Rotate(ctx); err != nil {
+		t.Fatal(err)
+	}
+	token, _ := newTestKeyManagerJWT(t, km2).GenerateAccessToken("u-1", "a@example.com", "s-1", nil)
+
+	// kid yang tidak dikenal memicu reload setelah jwkSetMinRefresh
+	clock.t = clock.t.Add(2 * jwkSetMinRefresh)
+	if _, err := newTestKeyManagerJWT(t, km1).VerifyToken(token); err != nil {
+		t.Fatalf("instance 1 should verify tokens signed after rotation elsewhere: %v", err)
+	}
+	if km1.SigningKey().ID != km2.SigningKey().ID {
+		t.Error("instance 1 should sign with the rotated key after reload")
+	}
+}
+
+func TestKeyManager_ConcurrentActiveKeys(t *testing.T) {
+	ctx := context.Background()
+	store := NewMockKeyStore()
+	km := newTestKeyManager(t, store, nil)
+	older := km.SigningKey()
+
+	// Simulasi dua instance yang merotasi bersamaan: dua key aktif di store
+	signer, _ := generateSigningKey("ES256", 0)
+	jwk, _ := NewJSONWebKey("", "ES256", signer.Public())
+	store.SaveKey(ctx, &SigningKey{ID: jwk.Kid, Algorithm: "ES256", PrivateKey: signer, CreatedAt: older.CreatedAt.Add(time.Second)})
+
+	if err := km.Reload(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if km.SigningKey().ID != jwk.Kid {
+		t.Errorf("active key = %q, want newest %q", km.SigningKey().ID, jwk.Kid)
+	}
+	if len(km.Keys()) != 2 {
+		t.Errorf("older key should remain verify-only, got %d keys", len(km.Keys()))
+	}
+}
+
+func TestKeyManager_RejectsAlgorithmMismatch(t *testing.T) {
+	km := newTestKeyManager(t, NewMockKeyStore(), nil)
+	manager := newTestKeyManagerJWT(t, km)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "u-1", "exp": time.Now().Add(time.Minute).Unix()})
+	token.Header["typ"] = "at+jwt"
+	token.Header["kid"] = km.SigningKey().ID
+	signed, _ := token.SignedString([]byte("legacy-secret"))
+
+	if _, err := manager.VerifyToken(signed); err == nil {
+		t.Error("HS256 token carrying a rotating key kid should be rejected")
+	}
+}
+
+func TestNewKeyManager_UnsupportedAlgorithm(t *testing.T) {
+	_, err := NewKeyManager(context.Background(), NewMockKeyStore(), KeyManagerConfig{Algorithm: "HS256"})
+	if !errors.Is(err, ErrUnsupportedKeyAlgorithm) {
+		t.Errorf("err = %v, want ErrUnsupportedKeyAlgorithm", err)
+	}
+}
+
+func TestFileKeyStore_Encrypted(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "jwt-keys.json")
+	encryptor, err := NewAESKeyEncryptor(bytes.Repeat([]byte{7}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := NewFileKeyStore(path).WithEncryptor(encryptor)
+	km := newTestKeyManager(t, store, nil)
+	if _, err := km.Rotate(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("mode = %v, want 0600", info.Mode().Perm())
+	}
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "PRIVATE KEY") {
+		t.Error("key file should not contain plaintext PEM")
+	}
+
+	keys, err := store.LoadKeys(ctx)
+	if err != nil || len(keys) != 2 {
+		t.Fatalf("LoadKeys = %d keys, %v", len(keys), err)
+	}
+	if _, err := NewFileKeyStore(path).LoadKeys(ctx); err == nil {
+		t.Error("loading encrypted keys without encryptor should fail")
+	}
+
+	if err := store.DeleteKey(ctx, keys[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	if keys, _ := store.LoadKeys(ctx); len(keys) != 1 {
+		t.Errorf("keys after delete = %d, want 1", len(keys))
+	}
+}
+
+func TestDatabaseKeyStore_SQLite(t *testing.T) {
+	ctx := context.Background()
+	db := newTestSQLiteDB(t)
+	if err := RunMigrations(db, GetSigningKeyMigrations()); err != nil {
+		t.Fatal(err)
+	}
+	store := NewDatabaseKeyStore(db)
+
+	km := newTestKeyManager(t, store, nil)
+	first := km.SigningKey().ID
+	if _, err := km.Rotate(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	keys, err := store.LoadKeys(ctx)
+	if err != nil || len(keys) != 2 {
+		t.Fatalf("LoadKeys = %d keys, %v", len(keys), err)
+	}
+	for _, key := range keys {
+		if key.ID == first && (key.RetiredAt == nil || key.ExpiresAt == nil) {
+			t.Errorf("first key should be retired: %+v", key)
+		}
+		if key.ID != first && !key.Active() {
+			t.Errorf("new key should be active: %+v", key)
+		}
+	}
+
+	if err := store.DeleteKey(ctx, first); err != nil {
+		t.Fatal(err)
+	}
+	if keys, _ := store.LoadKeys(ctx); len(keys) != 1 {
+		t.Errorf("keys after delete = %d, want 1", len(keys))
+	}
+}
+
+func TestKeyRotateCommand(t *testing.T) {
+	km := newTestKeyManager(t, NewMockKeyStore(), nil)
+	first := km.SigningKey().ID
+
+	var out bytes.Buffer
+	cmd := NewKeyRotateCommand(km)
+	if err := cmd.Execute(&CommandContext{Out: &out}); err != nil {
+		t.Fatal(err)
+	}
+	if km.SigningKey().ID == first {
+		t.Error("jwt:rotate should rotate the active key")
+	}
+	if !strings.Contains(out.String(), "verify-only") || !strings.Contains(out.String(), "active") {
+		t.Errorf("output = %q", out.String())
+	}
+}
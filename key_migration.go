@@ -0,0 +1,71 @@
+package dim
+
+import (
+	"context"
+)
+
+// GetSigningKeyMigrations mengembalikan migrasi tabel jwt_signing_keys yang dipakai
+// DatabaseKeyStore. Modul ini opsional sehingga tidak termasuk dalam GetFrameworkMigrations.
+// Menggunakan versi 201 agar tidak bentrok dengan migrasi framework maupun aplikasi.
+//
+// Example:
+//
+//	migrations := append(dim.GetFrameworkMigrations(), dim.GetSigningKeyMigrations()...)
+//	dim.RunMigrations(db, migrations)
+func GetSigningKeyMigrations() []Migration {
+	return []Migration{
+		{
+			Version: 201,
+			Name:    "create_jwt_signing_keys_table",
+			Up:      CreateSigningKeysTable,
+			Down:    DropSigningKeysTable,
+		},
+	}
+}
+
+// CreateSigningKeysTable membuat tabel jwt_signing_keys. Kolom private_key berisi PEM PKCS#8
+// atau ciphertext KeyEncryptor.
+func CreateSigningKeysTable(db Database) error {
+	var query string
+	switch db.DriverName() {
+	case "sqlite":
+		query = `
+			CREATE TABLE IF NOT EXISTS jwt_signing_keys (
+				id TEXT PRIMARY KEY,
+				algorithm TEXT NOT NULL,
+				private_key TEXT NOT NULL,
+				created_at TIMESTAMP NOT NULL,
+				retired_at TIMESTAMP NULL,
+				expires_at TIMESTAMP NULL
+			)
+		`
+	case "mysql":
+		query = `
+			CREATE TABLE IF NOT EXISTS jwt_signing_keys (
+				id VARCHAR(64) PRIMARY KEY,
+				algorithm VARCHAR(10) NOT NULL,
+				private_key TEXT NOT NULL,
+				created_at DATETIME NOT NULL,
+				retired_at DATETIME NULL,
+				expires_at DATETIME NULL
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+		`
+	default:
+		query = `
+			CREATE TABLE IF NOT EXISTS jwt_signing_keys (
+				id VARCHAR(64) PRIMARY KEY,
+				algorithm VARCHAR(10) NOT NULL,
+				private_key TEXT NOT NULL,
+				created_at TIMESTAMP NOT NULL,
+				retired_at TIMESTAMP NULL,
+				expires_at TIMESTAMP NULL
+			)
+		`
+	}
+	return db.Exec(context.Background(), query)
+}
+
+// DropSigningKeysTable menghapus tabel jwt_signing_keys.
+func DropSigningKeysTable(db Database) error {
+	return db.Exec(context.Background(), "DROP TABLE IF EXISTS jwt_signing_keys")
+}
@@ -0,0 +1,400 @@
+package dim
+
+import (
+	"context"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// KeyStore persists JWT signing keys for KeyManager. Implementations must be safe for
+// concurrent use and are typically shared by every instance of the application.
+type KeyStore interface {
+	// LoadKeys returns all stored keys, including retired ones.
+	LoadKeys(ctx context.Context) ([]*SigningKey, error)
+	// SaveKey inserts a new key or updates RetiredAt/ExpiresAt of an existing one.
+	SaveKey(ctx context.Context, key *SigningKey) error
+	// DeleteKey removes a key. Deleting an unknown key is not an error.
+	DeleteKey(ctx context.Context, id string) error
+}
+
+// KeyEncryptor encrypts private keys before a KeyStore persists them. Implement it on top of
+// a KMS (AWS KMS, GCP KMS, Vault Transit) so the store never holds plaintext key material.
+type KeyEncryptor interface {
+	EncryptKey(ctx context.Context, plaintext []byte) ([]byte, error)
+	DecryptKey(ctx context.Context, ciphertext []byte) ([]byte, error)
+}
+
+// AESKeyEncryptor is a KeyEncryptor using AES-GCM with a local master key, e.g. loaded from a
+// secret manager via ResolveSecret.
+type AESKeyEncryptor struct {
+	aead cipher.AEAD
+}
+
+// NewAESKeyEncryptor creates an AESKeyEncryptor. The master key must be 16, 24, or 32 bytes.
+func NewAESKeyEncryptor(masterKey []byte) (*AESKeyEncryptor, error) {
+	block, err := aes.NewCipher(masterKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key encryption key: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &AESKeyEncryptor{aead: aead}, nil
+}
+
+// EncryptKey implements KeyEncryptor. The random nonce is prepended to the ciphertext.
+func (e *AESKeyEncryptor) EncryptKey(ctx context.Context, plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, e.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return e.aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// DecryptKey implements KeyEncryptor.
+func (e *AESKeyEncryptor) DecryptKey(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	if len(ciphertext) < e.aead.NonceSize() {
+		return nil, errors.New("encrypted key too short")
+	}
+	nonce, sealed := ciphertext[:e.aead.NonceSize()], ciphertext[e.aead.NonceSize():]
+	return e.aead.Open(nil, nonce, sealed, nil)
+}
+
+// encryptedKeyPrefix marks private keys encrypted by a KeyEncryptor; plaintext keys are PEM.
+const encryptedKeyPrefix = "enc:"
+
+// encodePrivateKey serializes a private key as PKCS#8 PEM, or as encrypted base64 when an
+// encryptor is set.
+func encodePrivateKey(ctx context.Context, encryptor KeyEncryptor, key crypto.Signer) (string, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal signing key: %w", err)
+	}
+	if encryptor == nil {
+		return string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})), nil
+	}
+	ciphertext, err := encryptor.EncryptKey(ctx, der)
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt signing key: %w", err)
+	}
+	return encryptedKeyPrefix + base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+// decodePrivateKey reverses encodePrivateKey.
+func decodePrivateKey(ctx context.Context, encryptor KeyEncryptor, encoded string) (crypto.Signer, error) {
+	var der []byte
+	if rest, ok := strings.CutPrefix(encoded, encryptedKeyPrefix); ok {
+		if encryptor == nil {
+			return nil, errors.New("signing key is encrypted but no KeyEncryptor is configured")
+		}
+		ciphertext, err := base64.StdEncoding.DecodeString(rest)
+		if err != nil {
+			return nil, fmt.Errorf("invalid encrypted signing key: %w", err)
+		}
+		if der, err = encryptor.DecryptKey(ctx, ciphertext); err != nil {
+			return nil, fmt.Errorf("failed to decrypt signing key: %w", err)
+		}
+	} else {
+		block, _ := pem.Decode([]byte(encoded))
+		if block == nil {
+			return nil, errors.New("invalid signing key PEM")
+		}
+		der = block.Bytes
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse signing key: %w", err)
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported signing key type %T", key)
+	}
+	return signer, nil
+}
+
+// storedSigningKey is the serialized form of a SigningKey.
+type storedSigningKey struct {
+	ID         string     `json:"id"`
+	Algorithm  string     `json:"alg"`
+	PrivateKey string     `json:"private_key"`
+	CreatedAt  time.Time  `json:"created_at"`
+	RetiredAt  *time.Time `json:"retired_at,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+}
+
+// FileKeyStore stores signing keys in a JSON file with 0600 permissions. Suitable for a single
+// instance or a shared volume; use DatabaseKeyStore when instances do not share a filesystem.
+type FileKeyStore struct {
+	path      string
+	encryptor KeyEncryptor
+	mu        sync.Mutex
+}
+
+// NewFileKeyStore creates a file key store. The file is created on the first SaveKey.
+func NewFileKeyStore(path string) *FileKeyStore {
+	return &FileKeyStore{path: path}
+}
+
+// WithEncryptor encrypts private keys before writing them to the file.
+func (s *FileKeyStore) WithEncryptor(encryptor KeyEncryptor) *FileKeyStore {
+	s.encryptor = encryptor
+	return s
+}
+
+// LoadKeys reads all keys from the file. A missing file yields no keys.
+func (s *FileKeyStore) LoadKeys(ctx context.Context) ([]*SigningKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]*SigningKey, 0, len(records))
+	for _, record := range records {
+		signer, err := decodePrivateKey(ctx, s.encryptor, record.PrivateKey)
+		if err != nil {
+			return nil, fmt.Errorf("signing key %s: %w", record.ID, err)
+		}
+		keys = append(keys, &SigningKey{
+			ID:         record.ID,
+			Algorithm:  record.Algorithm,
+			PrivateKey: signer,
+			CreatedAt:  record.CreatedAt,
+			RetiredAt:  record.RetiredAt,
+			ExpiresAt:  record.ExpiresAt,
+		})
+	}
+	return keys, nil
+}
+
+// SaveKey inserts or updates a key and rewrites the file atomically.
+func (s *FileKeyStore) SaveKey(ctx context.Context, key *SigningKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records, err := s.read()
+	if err != nil {
+		return err
+	}
+	encoded, err := encodePrivateKey(ctx, s.encryptor, key.PrivateKey)
+	if err != nil {
+		return err
+	}
+	record := storedSigningKey{
+		ID:         key.ID,
+		Algorithm:  key.Algorithm,
+		PrivateKey: encoded,
+		CreatedAt:  key.CreatedAt,
+		RetiredAt:  key.RetiredAt,
+		ExpiresAt:  key.ExpiresAt,
+	}
+
+	replaced := false
+	for i := range records {
+		if records[i].ID == key.ID {
+			records[i], replaced = record, true
+		}
+	}
+	if !replaced {
+		records = append(records, record)
+	}
+	return s.write(records)
+}
+
+// DeleteKey removes a key from the file.
+func (s *FileKeyStore) DeleteKey(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records, err := s.read()
+	if err != nil {
+		return err
+	}
+	kept := records[:0]
+	for _, record := range records {
+		if record.ID != id {
+			kept = append(kept, record)
+		}
+	}
+	return s.write(kept)
+}
+
+func (s *FileKeyStore) read() ([]storedSigningKey, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file: %w", err)
+	}
+	var records []storedSigningKey
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("failed to parse key file: %w", err)
+	}
+	return records, nil
+}
+
+// write replaces the file via a temporary file and rename so readers never see a partial file.
+func (s *FileKeyStore) write(records []storedSigningKey) error {
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write key file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write key file: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write key file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write key file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to write key file: %w", err)
+	}
+	return nil
+}
+
+// DatabaseKeyStore is the SQL implementation of KeyStore (PostgreSQL, MySQL & SQLite).
+type DatabaseKeyStore struct {
+	db        Database
+	encryptor KeyEncryptor
+}
+
+// NewDatabaseKeyStore creates a new SQL key store.
+// Requires the table created by GetSigningKeyMigrations.
+func NewDatabaseKeyStore(db Database) *DatabaseKeyStore {
+	return &DatabaseKeyStore{db: db}
+}
+
+// WithEncryptor encrypts private keys before writing them to the database.
+func (s *DatabaseKeyStore) WithEncryptor(encryptor KeyEncryptor) *DatabaseKeyStore {
+	s.encryptor = encryptor
+	return s
+}
+
+// LoadKeys returns all stored keys.
+func (s *DatabaseKeyStore) LoadKeys(ctx context.Context) ([]*SigningKey, error) {
+	query := `SELECT id, algorithm, private_key, created_at, retired_at, expires_at FROM jwt_signing_keys`
+
+	rows, err := s.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load signing keys: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []*SigningKey
+	for rows.Next() {
+		key := &SigningKey{}
+		var encoded string
+		if err := rows.Scan(&key.ID, &key.Algorithm, &encoded, &key.CreatedAt, &key.RetiredAt, &key.ExpiresAt); err != nil {
+			return nil, fmt.Errorf("failed to scan signing key: %w", err)
+		}
+		if key.PrivateKey, err = decodePrivateKey(ctx, s.encryptor, encoded); err != nil {
+			return nil, fmt.Errorf("signing key %s: %w", key.ID, err)
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+// SaveKey inserts a new key or updates the retirement timestamps of an existing one.
+func (s *DatabaseKeyStore) SaveKey(ctx context.Context, key *SigningKey) error {
+	encoded, err := encodePrivateKey(ctx, s.encryptor, key.PrivateKey)
+	if err != nil {
+		return err
+	}
+
+	return InTx(ctx, s.db, func(ctx context.Context) error {
+		if err := s.db.Exec(ctx, s.db.Rebind(`DELETE FROM jwt_signing_keys WHERE id = $1`), key.ID); err != nil {
+			return fmt.Errorf("failed to save signing key: %w", err)
+		}
+
+		query := `INSERT INTO jwt_signing_keys (id, algorithm, private_key, created_at, retired_at, expires_at)
+			 VALUES ($1, $2, $3, $4, $5, $6)`
+		err := s.db.Exec(ctx, s.db.Rebind(query),
+			key.ID,
+			key.Algorithm,
+			encoded,
+			key.CreatedAt.UTC().Truncate(time.Second),
+			utcTimePtr(key.RetiredAt),
+			utcTimePtr(key.ExpiresAt),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to save signing key: %w", err)
+		}
+		return nil
+	})
+}
+
+// DeleteKey deletes a key.
+func (s *DatabaseKeyStore) DeleteKey(ctx context.Context, id string) error {
+	if err := s.db.Exec(ctx, s.db.Rebind(`DELETE FROM jwt_signing_keys WHERE id = $1`), id); err != nil {
+		return fmt.Errorf("failed to delete signing key: %w", err)
+	}
+	return nil
+}
+
+// MockKeyStore is an in-memory KeyStore for testing.
+type MockKeyStore struct {
+	mu   sync.Mutex
+	keys map[string]SigningKey
+}
+
+// NewMockKeyStore creates a new in-memory key store.
+func NewMockKeyStore() *MockKeyStore {
+	return &MockKeyStore{keys: make(map[string]SigningKey)}
+}
+
+// LoadKeys returns copies of all stored keys.
+func (s *MockKeyStore) LoadKeys(ctx context.Context) ([]*SigningKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	keys := make([]*SigningKey, 0, len(s.keys))
+	for _, key := range s.keys {
+		keys = append(keys, &key)
+	}
+	return keys, nil
+}
+
+// SaveKey stores a copy of the key.
+func (s *MockKeyStore) SaveKey(ctx context.Context, key *SigningKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys[key.ID] = *key
+	return nil
+}
+
+// DeleteKey removes a key.
+func (s *MockKeyStore) DeleteKey(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.keys, id)
+	return nil
+}
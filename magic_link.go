@@ -0,0 +1,242 @@
+package dim
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ErrMagicLinkNotFound dikembalikan MagicLinkStore jika token magic link tidak ditemukan.
+var ErrMagicLinkNotFound = errors.New("magic link token not found")
+
+// MagicLinkToken adalah token login sekali pakai yang dikirim lewat email. Hanya hash token
+// yang disimpan.
+type MagicLinkToken struct {
+	ID        int64      `json:"id"`
+	UserID    string     `json:"user_id"`
+	TokenHash string     `json:"-"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// MagicLinkStore mendefinisikan penyimpanan token magic link, terpisah dari token reset password.
+// DatabaseTokenStore dan MockTokenStore mengimplementasikan interface ini.
+type MagicLinkStore interface {
+	SaveMagicLinkToken(ctx context.Context, token *MagicLinkToken) error
+	FindMagicLinkToken(ctx context.Context, tokenHash string) (*MagicLinkToken, error) // ErrMagicLinkNotFound jika tidak ada
+	// ConsumeMagicLinkToken menandai token yang belum dipakai dan belum kadaluarsa pada now
+	// sebagai terpakai secara atomik, lalu mengembalikan user_id pemiliknya.
+	// ErrMagicLinkNotFound jika token tidak ada, sudah dipakai, atau kadaluarsa.
+	ConsumeMagicLinkToken(ctx context.Context, tokenHash string, now time.Time) (string, error)
+}
+
+// MagicLinkEmail adalah data untuk template email magic link.
+type MagicLinkEmail struct {
+	Email     string
+	URL       string
+	ExpiresAt time.Time
+}
+
+// MagicLinkOptions mengatur pengiriman dan pembatasan magic link. Semua field opsional.
+type MagicLinkOptions struct {
+	// Mailer mengirim email berisi link. Jika nil, RequestMagicLink hanya mengembalikan token
+	// untuk dikirim sendiri oleh pemanggil (seperti RequestPasswordReset).
+	Mailer Mailer
+	// URL adalah format URL halaman login dengan %s untuk token,
+	// misal "https://app.example.com/auth/magic?token=%s".
+	URL string
+	// Subject email (default: "Link login Anda").
+	Subject string
+	// Template mengganti isi email default, misal EmailRenderer.MagicLinkTemplate.
+	// Mengembalikan nil berarti memakai isi email default.
+	Template func(data MagicLinkEmail) *MailMessage
+	// Expiry adalah masa berlaku link (default: 15 menit).
+	Expiry time.Duration
+	// RateLimiter membatasi permintaan per email. Jika nil, tidak ada pembatasan.
+	RateLimiter RateLimitStore
+	// RateLimit adalah jumlah permintaan per email dalam RateWindow (default: 3 per 15 menit).
+	RateLimit  int
+	RateWindow time.Duration
+}
+
+// WithMagicLink mengaktifkan login tanpa password lewat link email dan mengembalikan instance
+// service. Token disimpan di MagicLinkStore (bukan tabel reset password), berlaku singkat, dan
+// hanya dapat dipakai sekali.
+//
+// Parameters:
+//   - store: penyimpanan token magic link, biasanya token store yang sama (DatabaseTokenStore)
+//   - options: mailer, URL, masa berlaku, dan rate limit
+//
+// Example:
+//
+//	tokenStore := dim.NewDatabaseTokenStore(db)
+//	authService.WithMagicLink(tokenStore, dim.MagicLinkOptions{
+//	    Mailer:      mailer,
+//	    URL:         "https://app.example.com/auth/magic?token=%s",
+//	    RateLimiter: dim.NewDatabaseRateLimitStore(db),
+//	})
+func (s *AuthService) WithMagicLink(store MagicLinkStore, options MagicLinkOptions) *AuthService {
+	if options.Subject == "" {
+		options.Subject = "Link login Anda"
+	}
+	if options.Expiry <= 0 {
+		options.Expiry = 15 * time.Minute
+	}
+	if options.RateLimit <= 0 {
+		options.RateLimit = 3
+	}
+	if options.RateWindow <= 0 {
+		options.RateWindow = 15 * time.Minute
+	}
+	s.magicLinks = store
+	s.magicLink = options
+	return s
+}
+
+// RequestMagicLink membuat token magic link dan mengirimkannya lewat email jika Mailer
+// dikonfigurasi. Sama seperti RequestPasswordReset, email yang tidak terdaftar tidak
+// menghasilkan error agar keberadaan akun tidak terungkap. Rate limit dihitung per email,
+// termasuk untuk email yang tidak terdaftar.
+//
+// Parameters:
+//   - ctx: context request
+//   - email: email user
+//
+// Returns:
+//   - string: token magic link (belum di-hash), kosong jika email tidak terdaftar
+//   - error: *AppError (400 validasi, 429 rate limit, 500 gagal menyimpan/mengirim)
+//
+// Example:
+//
+//	if _, err := authService.RequestMagicLink(r.Context(), req.Email); err != nil {
+//	    return err
+//	}
+//	dim.Json(w, http.StatusAccepted, map[string]string{"message": "Periksa email Anda"})
+func (s *AuthService) RequestMagicLink(ctx context.Context, email string) (string, error) {
+	if s.magicLinks == nil {
+		return "", NewAppError("Magic link tidak dikonfigurasi", http.StatusInternalServerError)
+	}
+
+	v := NewValidator().WithLocale(LocaleFromContext(ctx)).
+		Required("email", email).
+		Email("email", email)
+
+	if !v.IsValid() {
+		err := NewAppError("Validasi gagal", 400)
+		err.Errors = v.ErrorMap()
+		return "", err
+	}
+
+	if limiter := s.magicLink.RateLimiter; limiter != nil {
+		key := "magic_link:" + strings.ToLower(strings.TrimSpace(email))
+		allowed, err := limiter.Allow(ctx, key, s.magicLink.RateLimit, s.magicLink.RateWindow)
+		if err != nil {
+			return "", NewAppError("Gagal memeriksa rate limit", 500)
+		}
+		if !allowed {
+			return "", NewAppError("Terlalu banyak permintaan link login, coba lagi nanti", http.StatusTooManyRequests)
+		}
+	}
+
+	user, err := s.userStore.FindByEmail(ctx, email)
+	if err != nil {
+		// Don't reveal if email exists (security best practice)
+		return "", nil
+	}
+
+	token, err := GenerateSecureToken(32)
+	if err != nil {
+		return "", NewAppError("Gagal membuat link login", 500)
+	}
+
+	magicLink := &MagicLinkToken{
+		UserID:    user.GetID(),
+		TokenHash: GenerateTokenHash(token),
+		ExpiresAt: time.Now().Add(s.magicLink.Expiry),
+	}
+	if err := s.magicLinks.SaveMagicLinkToken(ctx, magicLink); err != nil {
+		return "", NewAppError("Gagal menyimpan link login", 500)
+	}
+
+	if s.magicLink.Mailer != nil {
+		msg := s.magicLinkMessage(MagicLinkEmail{
+			Email:     user.GetEmail(),
+			URL:       fmt.Sprintf(s.magicLink.URL, token),
+			ExpiresAt: magicLink.ExpiresAt,
+		})
+		if err := s.magicLink.Mailer.Send(ctx, msg); err != nil {
+			if s.logger != nil {
+				s.logger.Error("Failed to send magic link email", "error", err.Error())
+			}
+			return "", NewAppError("Gagal mengirim email login", 500)
+		}
+	}
+
+	return token, nil
+}
+
+func (s *AuthService) magicLinkMessage(data MagicLinkEmail) *MailMessage {
+	if s.magicLink.Template != nil {
+		if msg := s.magicLink.Template(data); msg != nil {
+			return msg
+		}
+	}
+
+	msg := NewMailMessage([]string{data.Email}, s.magicLink.Subject)
+	msg.HTML = fmt.Sprintf(
+		`<p>Klik link berikut untuk masuk ke akun Anda.</p><p><a href="%s">Masuk</a></p><p>Link hanya dapat dipakai sekali dan berlaku hingga %s. Abaikan email ini jika Anda tidak meminta link login.</p>`,
+		html.EscapeString(data.URL), data.ExpiresAt.Format("2 Jan 2006 15:04 MST"),
+	)
+	msg.PlainText = fmt.Sprintf(
+		"Klik link berikut untuk masuk ke akun Anda:\n\n%s\n\nLink hanya dapat dipakai sekali dan berlaku hingga %s. Abaikan email ini jika Anda tidak meminta link login.\n",
+		data.URL, data.ExpiresAt.Format("2 Jan 2006 15:04 MST"),
+	)
+	return msg
+}
+
+// ConsumeMagicLink memverifikasi token magic link lalu menerbitkan access & refresh token
+// seperti Login. Token ditandai terpakai secara atomik sebelum token sesi diterbitkan sehingga
+// link yang sama tidak dapat dipakai dua kali, termasuk oleh request bersamaan. User dengan MFA
+// aktif mendapat *MFARequiredError.
+//
+// Parameters:
+//   - ctx: context request
+//   - token: token dari link email
+//
+// Returns:
+//   - string: access token
+//   - string: refresh token
+//   - error: *AppError (400 jika token tidak valid, kadaluarsa, atau sudah dipakai) atau *MFARequiredError
+func (s *AuthService) ConsumeMagicLink(ctx context.Context, token string) (string, string, error) {
+	if s.magicLinks == nil {
+		return "", "", NewAppError("Magic link tidak dikonfigurasi", http.StatusInternalServerError)
+	}
+
+	userID, err := s.magicLinks.ConsumeMagicLinkToken(ctx, GenerateTokenHash(token), time.Now())
+	if err != nil {
+		if errors.Is(err, ErrMagicLinkNotFound) {
+			return "", "", NewAppError("Link login tidak valid atau kadaluarsa", 400)
+		}
+		return "", "", NewAppError("Gagal menandai link login", 500)
+	}
+
+	user, err := s.userStore.FindByID(ctx, userID)
+	if err != nil {
+		return "", "", NewAppError("Link login tidak valid atau kadaluarsa", 400)
+	}
+
+	challenge, err := s.mfaChallenge(ctx, user)
+	if err != nil {
+		return "", "", err
+	}
+	if challenge != nil {
+		return "", "", challenge
+	}
+
+	return s.login(ctx, user, LoginMethodMagicLink, false)
+}
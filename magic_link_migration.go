@@ -0,0 +1,72 @@
+package dim
+
+import (
+	"context"
+)
+
+// GetMagicLinkMigrations mengembalikan migrasi tabel magic_link_tokens yang dipakai
+// DatabaseTokenStore sebagai MagicLinkStore. Modul ini opsional sehingga tidak termasuk dalam
+// GetFrameworkMigrations; gabungkan secara manual setelah migrasi users.
+// Menggunakan versi 161 agar tidak bentrok dengan migrasi framework maupun aplikasi.
+//
+// Example:
+//
+//	migrations := append(dim.GetFrameworkMigrations(), dim.GetMagicLinkMigrations()...)
+//	dim.RunMigrations(db, migrations)
+func GetMagicLinkMigrations() []Migration {
+	return []Migration{
+		{
+			Version: 161,
+			Name:    "create_magic_link_tokens_table",
+			Up:      CreateMagicLinkTokensTable,
+			Down:    DropMagicLinkTokensTable,
+		},
+	}
+}
+
+// CreateMagicLinkTokensTable membuat tabel magic_link_tokens. Hanya hash token yang disimpan.
+func CreateMagicLinkTokensTable(db Database) error {
+	var query string
+	switch db.DriverName() {
+	case "sqlite":
+		query = `
+			CREATE TABLE IF NOT EXISTS magic_link_tokens (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+				token_hash TEXT UNIQUE NOT NULL,
+				expires_at TIMESTAMP NOT NULL,
+				used_at TIMESTAMP NULL,
+				created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+			)
+		`
+	case "mysql":
+		query = `
+			CREATE TABLE IF NOT EXISTS magic_link_tokens (
+				id BIGINT AUTO_INCREMENT PRIMARY KEY,
+				user_id CHAR(36) NOT NULL,
+				token_hash VARCHAR(64) UNIQUE NOT NULL,
+				expires_at DATETIME NOT NULL,
+				used_at DATETIME NULL,
+				created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				CONSTRAINT fk_magic_link_tokens_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+		`
+	default:
+		query = `
+			CREATE TABLE IF NOT EXISTS magic_link_tokens (
+				id BIGSERIAL PRIMARY KEY,
+				user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+				token_hash VARCHAR(64) UNIQUE NOT NULL,
+				expires_at TIMESTAMP NOT NULL,
+				used_at TIMESTAMP NULL,
+				created_at TIMESTAMP NOT NULL DEFAULT NOW()
+			)
+		`
+	}
+	return db.Exec(context.Background(), query)
+}
+
+// DropMagicLinkTokensTable menghapus tabel magic_link_tokens.
+func DropMagicLinkTokensTable(db Database) error {
+	return db.Exec(context.Background(), "DROP TABLE IF EXISTS magic_link_tokens")
+}
@@ -0,0 +1,107 @@
+package dim
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// SaveMagicLinkToken saves a magic link token to the database.
+func (s *DatabaseTokenStore) SaveMagicLinkToken(ctx context.Context, token *MagicLinkToken) error {
+	now := time.Now().UTC().Truncate(time.Second)
+	query := `INSERT INTO magic_link_tokens (user_id, token_hash, expires_at, created_at)
+		 VALUES ($1, $2, $3, $4)
+		 RETURNING id, created_at`
+
+	err := s.db.QueryRow(ctx, s.db.Rebind(query),
+		token.UserID,
+		token.TokenHash,
+		token.ExpiresAt.UTC().Truncate(time.Second),
+		now,
+	).Scan(&token.ID, &token.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to save magic link token: %w", err)
+	}
+
+	return nil
+}
+
+// FindMagicLinkToken finds a magic link token by hash.
+func (s *DatabaseTokenStore) FindMagicLinkToken(ctx context.Context, tokenHash string) (*MagicLinkToken, error) {
+	token := &MagicLinkToken{}
+	query := `SELECT id, user_id, token_hash, expires_at, created_at, used_at
+		 FROM magic_link_tokens WHERE token_hash = $1`
+
+	err := s.db.QueryRow(ctx, s.db.Rebind(query), tokenHash).Scan(
+		&token.ID, &token.UserID, &token.TokenHash, &token.ExpiresAt, &token.CreatedAt, &token.UsedAt,
+	)
+	if err != nil {
+		if isNoRows(err) {
+			return nil, ErrMagicLinkNotFound
+		}
+		return nil, fmt.Errorf("failed to find magic link token: %w", err)
+	}
+
+	return token, nil
+}
+
+// ConsumeMagicLinkToken atomically marks an unused, unexpired magic link token as used and
+// returns its user ID. Returns ErrMagicLinkNotFound if no such token exists.
+func (s *DatabaseTokenStore) ConsumeMagicLinkToken(ctx context.Context, tokenHash string, now time.Time) (string, error) {
+	now = now.UTC().Truncate(time.Second)
+	var userID string
+	var err error
+	if s.db.DriverName() == "mysql" {
+		// MySQL does not support UPDATE ... RETURNING; lock the row within a transaction instead.
+		err = s.db.WithTx(ctx, func(ctx context.Context, tx Tx) error {
+			query := `SELECT user_id FROM magic_link_tokens
+				 WHERE token_hash = $1 AND used_at IS NULL AND expires_at > $2 FOR UPDATE`
+			if err := tx.QueryRow(ctx, query, tokenHash, now).Scan(&userID); err != nil {
+				return err
+			}
+			return tx.Exec(ctx, `UPDATE magic_link_tokens SET used_at = $1 WHERE token_hash = $2`, now, tokenHash)
+		})
+	} else {
+		query := `UPDATE magic_link_tokens SET used_at = $1
+			 WHERE token_hash = $2 AND used_at IS NULL AND expires_at > $3
+			 RETURNING user_id`
+		err = s.db.QueryRow(ctx, s.db.Rebind(query), now, tokenHash, now).Scan(&userID)
+	}
+	if err != nil {
+		if isNoRows(err) {
+			return "", ErrMagicLinkNotFound
+		}
+		return "", fmt.Errorf("failed to consume magic link token: %w", err)
+	}
+
+	return userID, nil
+}
+
+// SaveMagicLinkToken saves a magic link token in mock store.
+func (s *MockTokenStore) SaveMagicLinkToken(ctx context.Context, token *MagicLinkToken) error {
+	token.ID = int64(len(s.magicLinks) + 1)
+	token.CreatedAt = time.Now()
+	s.magicLinks[token.TokenHash] = token
+	return nil
+}
+
+// FindMagicLinkToken finds a magic link token in mock store.
+func (s *MockTokenStore) FindMagicLinkToken(ctx context.Context, tokenHash string) (*MagicLinkToken, error) {
+	token, exists := s.magicLinks[tokenHash]
+	if !exists {
+		return nil, ErrMagicLinkNotFound
+	}
+	copied := *token
+	return &copied, nil
+}
+
+// ConsumeMagicLinkToken marks an unused, unexpired magic link token as used in mock store.
+func (s *MockTokenStore) ConsumeMagicLinkToken(ctx context.Context, tokenHash string, now time.Time) (string, error) {
+	token, exists := s.magicLinks[tokenHash]
+	if !exists || token.UsedAt != nil || !now.Before(token.ExpiresAt) {
+		return "", ErrMagicLinkNotFound
+	}
+	token.UsedAt = &now
+	return token.UserID, nil
+}
@@ -0,0 +1,157 @@
+package dim
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestMagicLinkService(t *testing.T, tokenStore interface {
+	TokenStore
+	MFAStore
+	MagicLinkStore
+}, options MagicLinkOptions) *AuthService {
+	t.Helper()
+	userStore := NewMockUserStore()
+	userStore.AddUser(&MockUser{ID: "1", Email: "test@example.com"})
+	return newTestMFAAuthService(t, userStore, tokenStore).WithMagicLink(tokenStore, options)
+}
+
+func TestAuthService_MagicLink(t *testing.T) {
+	ctx := context.Background()
+	mailer := &captureOrgMailer{}
+	service := newTestMagicLinkService(t, NewMockTokenStore(), MagicLinkOptions{
+		Mailer: mailer,
+		URL:    "https://app.test/auth/magic?token=%s",
+	})
+
+	token, err := service.RequestMagicLink(ctx, "test@example.com")
+	if err != nil || token == "" {
+		t.Fatalf("RequestMagicLink = %q, %v", token, err)
+	}
+	if len(mailer.sent) != 1 {
+		t.Fatalf("expected 1 email, got %d", len(mailer.sent))
+	}
+	msg := mailer.sent[0]
+	if msg.To[0] != "test@example.com" || msg.Subject != "Link login Anda" ||
+		!strings.Contains(msg.HTML, "https://app.test/auth/magic?token="+token) ||
+		!strings.Contains(msg.PlainText, "https://app.test/auth/magic?token="+token) {
+		t.Errorf("unexpected email: %+v", msg)
+	}
+
+	access, refresh, err := service.ConsumeMagicLink(ctx, token)
+	if err != nil || access == "" || refresh == "" {
+		t.Fatalf("ConsumeMagicLink error: %v", err)
+	}
+	if claims, _ := service.tokenManager.VerifyToken(access); claims["sub"] != "1" {
+		t.Errorf("access token claims = %v", claims)
+	}
+	if _, _, err := service.ConsumeMagicLink(ctx, token); err == nil {
+		t.Error("expected magic link to be single use")
+	}
+	if _, _, err := service.ConsumeMagicLink(ctx, "unknown"); err == nil {
+		t.Error("expected unknown token to be rejected")
+	}
+
+	// Email yang tidak terdaftar tidak menghasilkan error maupun email
+	if token, err := service.RequestMagicLink(ctx, "nobody@example.com"); err != nil || token != "" {
+		t.Errorf("RequestMagicLink unknown email = %q, %v", token, err)
+	}
+	if len(mailer.sent) != 1 {
+		t.Errorf("expected no email for unknown address, got %d", len(mailer.sent))
+	}
+	if _, err := service.RequestMagicLink(ctx, "not-an-email"); err == nil {
+		t.Error("expected invalid email to be rejected")
+	}
+}
+
+func TestAuthService_MagicLinkExpiryAndMFA(t *testing.T) {
+	ctx := context.Background()
+	store := NewMockTokenStore()
+	service := newTestMagicLinkService(t, store, MagicLinkOptions{Expiry: time.Minute})
+
+	token, err := service.RequestMagicLink(ctx, "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.magicLinks[GenerateTokenHash(token)].ExpiresAt = time.Now().Add(-time.Second)
+	if _, _, err := service.ConsumeMagicLink(ctx, token); err == nil {
+		t.Error("expected expired magic link to be rejected")
+	}
+
+	user, _ := service.userStore.FindByID(ctx, "1")
+	enableTestMFA(t, service, user)
+	token, _ = service.RequestMagicLink(ctx, "test@example.com")
+	_, _, err = service.ConsumeMagicLink(ctx, token)
+	var mfa *MFARequiredError
+	if !errors.As(err, &mfa) {
+		t.Errorf("ConsumeMagicLink error = %v, want MFARequiredError", err)
+	}
+}
+
+func TestAuthService_MagicLinkRateLimit(t *testing.T) {
+	ctx := context.Background()
+	limiter := NewInMemoryRateLimitStore(time.Minute)
+	defer limiter.Close()
+	service := newTestMagicLinkService(t, NewMockTokenStore(), MagicLinkOptions{
+		RateLimiter: limiter,
+		RateLimit:   2,
+		RateWindow:  time.Minute,
+	})
+
+	for i := 0; i < 2; i++ {
+		if _, err := service.RequestMagicLink(ctx, "test@example.com"); err != nil {
+			t.Fatalf("request %d error: %v", i, err)
+		}
+	}
+	_, err := service.RequestMagicLink(ctx, "TEST@example.com")
+	if appErr, ok := AsAppError(err); !ok || appErr.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("third request error = %v, want 429", err)
+	}
+	if _, err := service.RequestMagicLink(ctx, "other@example.com"); err != nil {
+		t.Errorf("other email should have its own limit: %v", err)
+	}
+}
+
+func TestAuthService_MagicLinkDatabaseStore(t *testing.T) {
+	db := newTestSQLiteAuthDB(t)
+	if err := RunMigrations(db, append(GetMFAMigrations(), GetMagicLinkMigrations()...)); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if err := db.Exec(ctx, db.Rebind("INSERT INTO users (id, email, password) VALUES ($1, $2, $3)"), "1", "test@example.com", "hash"); err != nil {
+		t.Fatal(err)
+	}
+
+	store := NewDatabaseTokenStore(db)
+	service := newTestMFAAuthService(t, NewDatabaseAuthUserStore(db), store).
+		WithMagicLink(store, MagicLinkOptions{})
+	token, err := service.RequestMagicLink(ctx, "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := service.ConsumeMagicLink(ctx, token); err != nil {
+		t.Fatalf("ConsumeMagicLink error: %v", err)
+	}
+	if _, _, err := service.ConsumeMagicLink(ctx, token); err == nil {
+		t.Error("expected magic link to be single use")
+	}
+	if _, err := store.FindMagicLinkToken(ctx, "missing"); !errors.Is(err, ErrMagicLinkNotFound) {
+		t.Errorf("FindMagicLinkToken error = %v", err)
+	}
+
+	// Token kadaluarsa tidak dapat dipakai dan tetap tidak ditandai terpakai
+	expired := &MagicLinkToken{UserID: "1", TokenHash: "expired", ExpiresAt: time.Now().Add(-time.Minute)}
+	if err := store.SaveMagicLinkToken(ctx, expired); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.ConsumeMagicLinkToken(ctx, "expired", time.Now()); !errors.Is(err, ErrMagicLinkNotFound) {
+		t.Errorf("ConsumeMagicLinkToken(expired) error = %v, want ErrMagicLinkNotFound", err)
+	}
+	if found, _ := store.FindMagicLinkToken(ctx, "expired"); found == nil || found.UsedAt != nil {
+		t.Errorf("expired token should stay unused, got %+v", found)
+	}
+}
@@ -0,0 +1,265 @@
+package dim
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CapturedMail adalah email yang ditangkap oleh CaptureMailer.
+type CapturedMail struct {
+	ID      int64        `json:"id"`
+	SentAt  time.Time    `json:"sent_at"`
+	Message *MailMessage `json:"message"`
+}
+
+// CaptureMailer adalah Mailer untuk development yang tidak mengirim email, melainkan
+// menyimpan email terbaru di memory (seperti Mailpit) dan opsional menulis ringkasannya ke
+// output. Email dapat dilihat di browser lewat Handler. Dipakai oleh MAIL_TRANSPORT=log.
+type CaptureMailer struct {
+	mu       sync.RWMutex
+	limit    int
+	nextID   int64
+	messages []CapturedMail
+	output   io.Writer
+}
+
+// NewCaptureMailer membuat CaptureMailer yang menyimpan maksimal limit email terbaru.
+// Email tertua dibuang ketika batas tercapai.
+//
+// Parameters:
+//   - limit: jumlah email yang disimpan; <= 0 berarti 50
+//
+// Returns:
+//   - *CaptureMailer: mailer siap digunakan
+//
+// Example:
+//
+//	mailer := dim.NewCaptureMailer(50).WithOutput(os.Stdout)
+//	authService.WithRegistration(userStore, dim.RegistrationOptions{Mailer: mailer, URL: verifyURL})
+func NewCaptureMailer(limit int) *CaptureMailer {
+	if limit <= 0 {
+		limit = 50
+	}
+	return &CaptureMailer{limit: limit}
+}
+
+// WithOutput menulis ringkasan setiap email (penerima, subject, dan isi teks) ke w,
+// sehingga link verifikasi atau reset password terlihat langsung di terminal.
+func (m *CaptureMailer) WithOutput(w io.Writer) *CaptureMailer {
+	m.output = w
+	return m
+}
+
+// Send menyimpan salinan email. Tidak pernah gagal kecuali ctx sudah dibatalkan.
+func (m *CaptureMailer) Send(ctx context.Context, msg *MailMessage) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if msg == nil {
+		return errors.New("capture mailer: nil message")
+	}
+
+	copied := *msg
+	copied.To = slices.Clone(msg.To)
+	copied.Cc = slices.Clone(msg.Cc)
+	copied.Bcc = slices.Clone(msg.Bcc)
+	copied.ReplyTo = slices.Clone(msg.ReplyTo)
+	copied.Headers = maps.Clone(msg.Headers)
+	copied.Tags = maps.Clone(msg.Tags)
+	copied.Attachments = slices.Clone(msg.Attachments)
+
+	m.mu.Lock()
+	m.nextID++
+	mail := CapturedMail{ID: m.nextID, SentAt: time.Now(), Message: &copied}
+	m.messages = append(m.messages, mail)
+	if len(m.messages) > m.limit {
+		m.messages = slices.Delete(m.messages, 0, len(m.messages)-m.limit)
+	}
+	output := m.output
+	m.mu.Unlock()
+
+	if output != nil {
+		writeCapturedMail(output, mail)
+	}
+	return nil
+}
+
+// Messages mengembalikan email yang tersimpan, terbaru lebih dulu.
+func (m *CaptureMailer) Messages() []CapturedMail {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	messages := slices.Clone(m.messages)
+	slices.Reverse(messages)
+	return messages
+}
+
+// Get mengembalikan email dengan ID tertentu.
+func (m *CaptureMailer) Get(id int64) (CapturedMail, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, mail := range m.messages {
+		if mail.ID == id {
+			return mail, true
+		}
+	}
+	return CapturedMail{}, false
+}
+
+// Last mengembalikan email terakhir yang dikirim ke recipient (case-insensitive), berguna
+// di test untuk mengambil link dari email verifikasi atau reset password. Recipient kosong
+// berarti email terakhir apa pun.
+func (m *CaptureMailer) Last(recipient string) (CapturedMail, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for i := len(m.messages) - 1; i >= 0; i-- {
+		mail := m.messages[i]
+		if recipient == "" || slices.ContainsFunc(mail.Message.To, func(to string) bool {
+			return strings.Contains(strings.ToLower(to), strings.ToLower(recipient))
+		}) {
+			return mail, true
+		}
+	}
+	return CapturedMail{}, false
+}
+
+// Clear menghapus semua email yang tersimpan.
+func (m *CaptureMailer) Clear() {
+	m.mu.Lock()
+	m.messages = nil
+	m.mu.Unlock()
+}
+
+// Handler mengembalikan halaman preview email untuk development:
+//   - GET: daftar email terbaru
+//   - GET ?id=N: detail email dengan HTML di iframe sandbox, teks, header, dan lampiran
+//   - GET ?id=N&format=html: body HTML saja
+//   - GET ?format=json: daftar email sebagai JSON
+//   - DELETE: menghapus semua email
+//
+// Handler ini menampilkan isi email termasuk token, jadi jangan daftarkan di production.
+//
+// Example:
+//
+//	if capture, ok := mailer.(*dim.CaptureMailer); ok && !cfg.IsProduction() {
+//	    router.Get("/_dev/mail", capture.Handler())
+//	    router.Delete("/_dev/mail", capture.Handler())
+//	}
+func (m *CaptureMailer) Handler() HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "no-store")
+
+		if r.Method == http.MethodDelete {
+			m.Clear()
+			NoContent(w)
+			return
+		}
+
+		query := r.URL.Query()
+		if query.Get("format") == "json" {
+			Json(w, http.StatusOK, m.Messages())
+			return
+		}
+		if query.Get("id") == "" {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			mailPreviewTemplate.ExecuteTemplate(w, "list", m.Messages()) //nolint:errcheck
+			return
+		}
+
+		id, _ := strconv.ParseInt(query.Get("id"), 10, 64)
+		mail, ok := m.Get(id)
+		if !ok {
+			NotFound(w, "Email tidak ditemukan")
+			return
+		}
+		if query.Get("format") == "html" {
+			// Body email ditampilkan apa adanya tanpa menjalankan script
+			w.Header().Set("Content-Security-Policy", "sandbox")
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			io.WriteString(w, mail.Message.HTML) //nolint:errcheck
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		mailPreviewTemplate.ExecuteTemplate(w, "detail", mail) //nolint:errcheck
+	}
+}
+
+// writeCapturedMail menulis ringkasan email ke output CaptureMailer.
+func writeCapturedMail(w io.Writer, mail CapturedMail) {
+	msg := mail.Message
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- mail #%d %s\n", mail.ID, mail.SentAt.Format(time.RFC3339))
+	fmt.Fprintf(&b, "To: %s\n", strings.Join(msg.To, ", "))
+	if msg.From != "" {
+		fmt.Fprintf(&b, "From: %s\n", msg.From)
+	}
+	fmt.Fprintf(&b, "Subject: %s\n", msg.Subject)
+	for _, a := range msg.Attachments {
+		fmt.Fprintf(&b, "Attachment: %s (%d bytes)\n", a.Filename, len(a.Data))
+	}
+	if msg.PlainText != "" {
+		fmt.Fprintf(&b, "\n%s\n", strings.TrimSpace(msg.PlainText))
+	}
+	io.WriteString(w, b.String()) //nolint:errcheck
+}
+
+var mailPreviewTemplate = template.Must(template.New("").Parse(`
+{{define "style"}}<style>
+body{margin:0;font-family:-apple-system,'Segoe UI',Roboto,Helvetica,Arial,sans-serif;color:#1f2933;background:#f4f5f7}
+main{max-width:960px;margin:0 auto;padding:24px}
+table{width:100%;border-collapse:collapse;background:#fff}
+th,td{padding:8px 12px;border-bottom:1px solid #e4e7eb;text-align:left;font-size:14px;vertical-align:top}
+a{color:#2563eb}
+iframe{width:100%;height:600px;border:1px solid #e4e7eb;background:#fff}
+pre{white-space:pre-wrap;background:#fff;border:1px solid #e4e7eb;padding:12px}
+</style>{{end}}
+
+{{define "list"}}<!DOCTYPE html>
+<html lang="id"><head><meta charset="utf-8"><title>Mail Preview</title>{{template "style"}}</head>
+<body><main>
+<h1>Mail Preview</h1>
+{{if .}}<table>
+<tr><th>#</th><th>Waktu</th><th>Kepada</th><th>Subject</th></tr>
+{{range .}}<tr>
+<td>{{.ID}}</td>
+<td>{{.SentAt.Format "15:04:05"}}</td>
+<td>{{range $i, $to := .Message.To}}{{if $i}}, {{end}}{{$to}}{{end}}</td>
+<td><a href="?id={{.ID}}">{{or .Message.Subject "(tanpa subject)"}}</a></td>
+</tr>{{end}}
+</table>{{else}}<p>Belum ada email yang dikirim.</p>{{end}}
+</main></body></html>{{end}}
+
+{{define "detail"}}<!DOCTYPE html>
+<html lang="id"><head><meta charset="utf-8"><title>{{.Message.Subject}} - Mail Preview</title>{{template "style"}}</head>
+<body><main>
+<p><a href="?">&larr; Semua email</a></p>
+<h1>{{.Message.Subject}}</h1>
+<table>
+<tr><th>Waktu</th><td>{{.SentAt.Format "2006-01-02 15:04:05"}}</td></tr>
+{{with .Message.From}}<tr><th>From</th><td>{{.}}</td></tr>{{end}}
+<tr><th>To</th><td>{{range $i, $to := .Message.To}}{{if $i}}, {{end}}{{$to}}{{end}}</td></tr>
+{{with .Message.Cc}}<tr><th>Cc</th><td>{{range $i, $to := .}}{{if $i}}, {{end}}{{$to}}{{end}}</td></tr>{{end}}
+{{with .Message.Bcc}}<tr><th>Bcc</th><td>{{range $i, $to := .}}{{if $i}}, {{end}}{{$to}}{{end}}</td></tr>{{end}}
+{{with .Message.ReplyTo}}<tr><th>Reply-To</th><td>{{range $i, $to := .}}{{if $i}}, {{end}}{{$to}}{{end}}</td></tr>{{end}}
+{{range $k, $v := .Message.Headers}}<tr><th>{{$k}}</th><td>{{$v}}</td></tr>{{end}}
+{{range $k, $v := .Message.Tags}}<tr><th>Tag {{$k}}</th><td>{{$v}}</td></tr>{{end}}
+{{range .Message.Attachments}}<tr><th>Lampiran</th><td>{{.Filename}} ({{.ContentType}}, {{len .Data}} bytes)</td></tr>{{end}}
+</table>
+{{if .Message.HTML}}<h2>HTML</h2>
+<iframe sandbox src="?id={{.ID}}&amp;format=html" title="HTML"></iframe>{{end}}
+{{if .Message.PlainText}}<h2>Teks</h2>
+<pre>{{.Message.PlainText}}</pre>{{end}}
+</main></body></html>{{end}}
+`))
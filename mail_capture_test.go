@@ -0,0 +1,132 @@
+package dim
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCaptureMailer_Send(t *testing.T) {
+	var out strings.Builder
+	mailer := NewCaptureMailer(2).WithOutput(&out)
+
+	for i := 1; i <= 3; i++ {
+		msg := NewMailMessage([]string{fmt.Sprintf("user%d@example.com", i)}, fmt.Sprintf("Email %d", i))
+		msg.PlainText = "Klik https://app.example.com/verify?token=abc"
+		if err := mailer.Send(context.Background(), msg); err != nil {
+			t.Fatal(err)
+		}
+		msg.Subject = "changed after send"
+	}
+
+	messages := mailer.Messages()
+	if len(messages) != 2 || messages[0].ID != 3 || messages[1].ID != 2 {
+		t.Fatalf("messages = %+v", messages)
+	}
+	if messages[0].Message.Subject != "Email 3" {
+		t.Errorf("captured message was mutated: %q", messages[0].Message.Subject)
+	}
+	if _, ok := mailer.Get(1); ok {
+		t.Error("oldest email should be evicted")
+	}
+	if mail, ok := mailer.Last("USER2@example.com"); !ok || mail.ID != 2 {
+		t.Errorf("Last = %+v, %v", mail, ok)
+	}
+	if !strings.Contains(out.String(), "Subject: Email 1") || !strings.Contains(out.String(), "token=abc") {
+		t.Errorf("output = %q", out.String())
+	}
+
+	mailer.Clear()
+	if _, ok := mailer.Last(""); ok {
+		t.Error("Clear should remove all emails")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := mailer.Send(ctx, NewMailMessage(nil, "x")); err == nil {
+		t.Error("canceled context should fail")
+	}
+}
+
+func TestCaptureMailer_Handler(t *testing.T) {
+	mailer := NewCaptureMailer(10)
+	msg := NewMailMessage([]string{"ana@example.com"}, "Reset <password>")
+	msg.HTML = `<p>Halo</p><script>alert(1)</script>`
+	msg.PlainText = "Halo"
+	msg.Attachments = []Attachment{NewAttachment("invoice.txt", []byte("total"))}
+	mailer.Send(context.Background(), msg)
+	handler := mailer.Handler()
+
+	get := func(target string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler(w, httptest.NewRequest(http.MethodGet, "/_dev/mail"+target, nil))
+		return w
+	}
+
+	w := get("")
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `href="?id=1"`) || !strings.Contains(w.Body.String(), "Reset &lt;password&gt;") {
+		t.Errorf("list: %d %s", w.Code, w.Body.String())
+	}
+
+	w = get("?id=1")
+	body := w.Body.String()
+	if !strings.Contains(body, `<iframe sandbox src="?id=1&amp;format=html"`) || !strings.Contains(body, "invoice.txt") || strings.Contains(body, "<script>") {
+		t.Errorf("detail: %s", body)
+	}
+
+	w = get("?id=1&format=html")
+	if w.Body.String() != msg.HTML || w.Header().Get("Content-Security-Policy") != "sandbox" {
+		t.Errorf("html: %q, csp = %q", w.Body.String(), w.Header().Get("Content-Security-Policy"))
+	}
+
+	w = get("?format=json")
+	var list []CapturedMail
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil || len(list) != 1 || list[0].Message.Subject != "Reset <password>" {
+		t.Errorf("json: %v %s", err, w.Body.String())
+	}
+
+	if w = get("?id=99"); w.Code != http.StatusNotFound {
+		t.Errorf("missing id status = %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	handler(w, httptest.NewRequest(http.MethodDelete, "/_dev/mail", nil))
+	if w.Code != http.StatusNoContent || len(mailer.Messages()) != 0 {
+		t.Errorf("delete: %d, %d messages left", w.Code, len(mailer.Messages()))
+	}
+}
+
+func TestNewMailerFromConfig_Log(t *testing.T) {
+	var out strings.Builder
+	mailer, err := NewMailerFromConfig(&EmailConfig{Transport: "log", CaptureLimit: 5}, &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	capture, ok := mailer.(*CaptureMailer)
+	if !ok || capture.limit != 5 {
+		t.Fatalf("mailer = %T", mailer)
+	}
+	capture.Send(context.Background(), NewMailMessage([]string{"ana@example.com"}, "Halo"))
+	if !strings.Contains(out.String(), "To: ana@example.com") {
+		t.Errorf("output = %q", out.String())
+	}
+}
+
+func TestValidate_LogTransport(t *testing.T) {
+	cfg := &Config{
+		JWT:      JWTConfig{HMACSecret: "secret", SigningMethod: "HS256"},
+		Database: DatabaseConfig{Driver: "sqlite", Database: ":memory:"},
+		Email:    EmailConfig{Transport: "log"},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("log transport without MAIL_FROM: %v", err)
+	}
+	cfg.Server.Env = "production"
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "MAIL_TRANSPORT=log") {
+		t.Errorf("production err = %v", err)
+	}
+}
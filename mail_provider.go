@@ -0,0 +1,540 @@
+package dim
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/mail"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MailProviderError adalah respons gagal dari API provider email (Mailgun, SendGrid, Postmark,
+// Resend). Status 4xx selain 408 dan 429 dianggap permanen sehingga errors.Is(err,
+// ErrMailPermanent) bernilai true dan MailQueue tidak mengulang pengiriman.
+//
+// Example:
+//
+//	var perr *dim.MailProviderError
+//	if errors.As(err, &perr) && perr.StatusCode == http.StatusUnauthorized {
+//	    logger.Error("API key provider email tidak valid", "provider", perr.Provider)
+//	}
+type MailProviderError struct {
+	Provider   string
+	StatusCode int
+	// Code adalah kode error dari provider jika ada (misal ErrorCode Postmark atau name Resend).
+	Code    string
+	Message string
+}
+
+func (e *MailProviderError) Error() string {
+	msg := fmt.Sprintf("%s: status %d", e.Provider, e.StatusCode)
+	if e.Code != "" {
+		msg += " (" + e.Code + ")"
+	}
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	return msg
+}
+
+// Permanent mengembalikan true jika mengulang request yang sama tidak akan berhasil: alamat atau
+// payload ditolak, atau kredensial salah. Rate limit (429), timeout (408), dan 5xx dapat diulang.
+func (e *MailProviderError) Permanent() bool {
+	return e.StatusCode >= 400 && e.StatusCode < 500 &&
+		e.StatusCode != http.StatusRequestTimeout && e.StatusCode != http.StatusTooManyRequests
+}
+
+// Is mencocokkan ErrMailPermanent untuk error permanen.
+func (e *MailProviderError) Is(target error) bool {
+	return target == ErrMailPermanent && e.Permanent()
+}
+
+// sendMailRequest mengirim request ke API provider dan memetakan respons non-2xx ke MailProviderError.
+// Error jaringan dikembalikan apa adanya sehingga dapat diulang MailQueue.
+func sendMailRequest(client *http.Client, provider string, req *http.Request) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s request failed: %w", provider, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+		return nil
+	}
+
+	perr := &MailProviderError{Provider: provider, StatusCode: resp.StatusCode}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+
+	// Format error tiap provider: Mailgun {"message"}, SendGrid {"errors":[{"message"}]},
+	// Postmark {"ErrorCode","Message"}, Resend {"name","message"}.
+	var result struct {
+		Message   string `json:"message"`
+		Name      string `json:"name"`
+		ErrorCode int    `json:"ErrorCode"`
+		Errors    []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if json.Unmarshal(body, &result) == nil {
+		perr.Message = result.Message
+		if perr.Message == "" && len(result.Errors) > 0 {
+			messages := make([]string, len(result.Errors))
+			for i, e := range result.Errors {
+				messages[i] = e.Message
+			}
+			perr.Message = strings.Join(messages, "; ")
+		}
+		perr.Code = result.Name
+		if result.ErrorCode != 0 {
+			perr.Code = strconv.Itoa(result.ErrorCode)
+		}
+	} else {
+		perr.Message = strings.TrimSpace(string(body))
+	}
+	return perr
+}
+
+func newMailHTTPClient() *http.Client {
+	return &http.Client{Timeout: 30 * time.Second}
+}
+
+// mailFrom mengembalikan pengirim pesan atau pengirim default transport.
+func mailFrom(msg *MailMessage, defaultFrom string) (string, error) {
+	from := strings.TrimSpace(msg.From)
+	if from == "" {
+		from = defaultFrom
+	}
+	if from == "" {
+		return "", PermanentMailError(fmt.Errorf("mail: sender address is empty"))
+	}
+	return from, nil
+}
+
+// --- Mailgun ---
+
+// MailgunMailer mengirim email melalui Mailgun Messages API (POST /v3/{domain}/messages).
+// Tags dikirim sebagai custom variable (v:key) sehingga ikut muncul di event webhook.
+type MailgunMailer struct {
+	baseURL     string
+	domain      string
+	apiKey      string
+	defaultFrom string
+	client      *http.Client
+}
+
+// NewMailgunMailer membuat transport Mailgun dengan base URL region US dan timeout 30 detik.
+//
+// Example:
+//
+//	mailer := dim.NewMailgunMailer("mg.example.com", os.Getenv("MAILGUN_API_KEY"), "no-reply@example.com").
+//	    WithBaseURL("https://api.eu.mailgun.net")
+func NewMailgunMailer(domain, apiKey, defaultFrom string) *MailgunMailer {
+	return &MailgunMailer{
+		baseURL:     "https://api.mailgun.net",
+		domain:      domain,
+		apiKey:      apiKey,
+		defaultFrom: defaultFrom,
+		client:      newMailHTTPClient(),
+	}
+}
+
+// WithBaseURL mengganti base URL API, misal https://api.eu.mailgun.net untuk region EU.
+func (m *MailgunMailer) WithBaseURL(baseURL string) *MailgunMailer {
+	m.baseURL = strings.TrimRight(baseURL, "/")
+	return m
+}
+
+// WithHTTPClient mengganti HTTP client yang dipakai untuk memanggil API.
+func (m *MailgunMailer) WithHTTPClient(client *http.Client) *MailgunMailer {
+	m.client = client
+	return m
+}
+
+// Send mengimplementasikan Mailer.
+func (m *MailgunMailer) Send(ctx context.Context, msg *MailMessage) error {
+	from, err := mailFrom(msg, m.defaultFrom)
+	if err != nil {
+		return err
+	}
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	field := func(key, value string) {
+		if value != "" {
+			_ = form.WriteField(key, value)
+		}
+	}
+	field("from", from)
+	for _, to := range msg.To {
+		field("to", to)
+	}
+	for _, cc := range msg.Cc {
+		field("cc", cc)
+	}
+	for _, bcc := range msg.Bcc {
+		field("bcc", bcc)
+	}
+	field("subject", msg.Subject)
+	field("text", msg.PlainText)
+	field("html", msg.HTML)
+	if len(msg.ReplyTo) > 0 {
+		field("h:Reply-To", strings.Join(msg.ReplyTo, ", "))
+	}
+	if msg.MessageID != "" {
+		field("h:Message-Id", msg.MessageID)
+	}
+	for k, v := range msg.Headers {
+		field("h:"+k, v)
+	}
+	for k, v := range msg.Tags {
+		field("v:"+k, v)
+	}
+	for _, a := range msg.Attachments {
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="attachment"; filename=%q`, a.Filename))
+		if a.ContentType != "" {
+			header.Set("Content-Type", a.ContentType)
+		}
+		part, err := form.CreatePart(header)
+		if err != nil {
+			return err
+		}
+		if _, err := part.Write(a.Data); err != nil {
+			return err
+		}
+	}
+	if err := form.Close(); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, m.baseURL+"/v3/"+m.domain+"/messages", &body)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth("api", m.apiKey)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	return sendMailRequest(m.client, "mailgun", req)
+}
+
+// --- SendGrid ---
+
+// SendGridMailer mengirim email melalui SendGrid Mail Send API v3 (POST /v3/mail/send).
+// Tags dikirim sebagai custom_args sehingga ikut muncul di Event Webhook.
+type SendGridMailer struct {
+	baseURL     string
+	apiKey      string
+	defaultFrom string
+	client      *http.Client
+}
+
+// NewSendGridMailer membuat transport SendGrid dengan timeout 30 detik.
+func NewSendGridMailer(apiKey, defaultFrom string) *SendGridMailer {
+	return &SendGridMailer{
+		baseURL:     "https://api.sendgrid.com",
+		apiKey:      apiKey,
+		defaultFrom: defaultFrom,
+		client:      newMailHTTPClient(),
+	}
+}
+
+// WithBaseURL mengganti base URL API, misal https://api.eu.sendgrid.com untuk data residency EU.
+func (m *SendGridMailer) WithBaseURL(baseURL string) *SendGridMailer {
+	m.baseURL = strings.TrimRight(baseURL, "/")
+	return m
+}
+
+// WithHTTPClient mengganti HTTP client yang dipakai untuk memanggil API.
+func (m *SendGridMailer) WithHTTPClient(client *http.Client) *SendGridMailer {
+	m.client = client
+	return m
+}
+
+type sendGridAddress struct {
+	Email string `json:"email"`
+	Name  string `json:"name,omitempty"`
+}
+
+// sendGridAddresses memecah "Nama <email>" karena SendGrid menerima nama dan email terpisah.
+func sendGridAddresses(list []string) []sendGridAddress {
+	if len(list) == 0 {
+		return nil
+	}
+	out := make([]sendGridAddress, 0, len(list))
+	for _, s := range list {
+		if addr, err := mail.ParseAddress(s); err == nil {
+			out = append(out, sendGridAddress{Email: addr.Address, Name: addr.Name})
+		} else {
+			out = append(out, sendGridAddress{Email: strings.TrimSpace(s)})
+		}
+	}
+	return out
+}
+
+// Send mengimplementasikan Mailer.
+func (m *SendGridMailer) Send(ctx context.Context, msg *MailMessage) error {
+	from, err := mailFrom(msg, m.defaultFrom)
+	if err != nil {
+		return err
+	}
+
+	type content struct {
+		Type  string `json:"type"`
+		Value string `json:"value"`
+	}
+	type attachment struct {
+		Content     []byte `json:"content"`
+		Type        string `json:"type,omitempty"`
+		Filename    string `json:"filename"`
+		Disposition string `json:"disposition"`
+	}
+	type personalization struct {
+		To  []sendGridAddress `json:"to"`
+		Cc  []sendGridAddress `json:"cc,omitempty"`
+		Bcc []sendGridAddress `json:"bcc,omitempty"`
+	}
+	payload := struct {
+		Personalizations []personalization `json:"personalizations"`
+		From             sendGridAddress   `json:"from"`
+		ReplyToList      []sendGridAddress `json:"reply_to_list,omitempty"`
+		Subject          string            `json:"subject"`
+		Content          []content         `json:"content"`
+		Attachments      []attachment      `json:"attachments,omitempty"`
+		Headers          map[string]string `json:"headers,omitempty"`
+		CustomArgs       map[string]string `json:"custom_args,omitempty"`
+	}{
+		Personalizations: []personalization{{
+			To:  sendGridAddresses(msg.To),
+			Cc:  sendGridAddresses(msg.Cc),
+			Bcc: sendGridAddresses(msg.Bcc),
+		}},
+		From:        sendGridAddresses([]string{from})[0],
+		ReplyToList: sendGridAddresses(msg.ReplyTo),
+		Subject:     msg.Subject,
+		Headers:     msg.Headers,
+		CustomArgs:  msg.Tags,
+	}
+	// SendGrid mewajibkan text/plain sebelum text/html
+	if msg.PlainText != "" {
+		payload.Content = append(payload.Content, content{Type: "text/plain", Value: msg.PlainText})
+	}
+	if msg.HTML != "" {
+		payload.Content = append(payload.Content, content{Type: "text/html", Value: msg.HTML})
+	}
+	for _, a := range msg.Attachments {
+		payload.Attachments = append(payload.Attachments, attachment{Content: a.Data, Type: a.ContentType, Filename: a.Filename, Disposition: "attachment"})
+	}
+
+	req, err := newMailJSONRequest(ctx, m.baseURL+"/v3/mail/send", payload)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+m.apiKey)
+	return sendMailRequest(m.client, "sendgrid", req)
+}
+
+// --- Postmark ---
+
+// PostmarkMailer mengirim email melalui Postmark Email API (POST /email).
+// Tags dikirim sebagai Metadata sehingga ikut muncul di webhook.
+type PostmarkMailer struct {
+	baseURL       string
+	serverToken   string
+	defaultFrom   string
+	messageStream string
+	client        *http.Client
+}
+
+// NewPostmarkMailer membuat transport Postmark dengan message stream "outbound" dan timeout 30 detik.
+func NewPostmarkMailer(serverToken, defaultFrom string) *PostmarkMailer {
+	return &PostmarkMailer{
+		baseURL:       "https://api.postmarkapp.com",
+		serverToken:   serverToken,
+		defaultFrom:   defaultFrom,
+		messageStream: "outbound",
+		client:        newMailHTTPClient(),
+	}
+}
+
+// WithMessageStream mengatur message stream, misal "broadcast" untuk email non-transaksional.
+func (m *PostmarkMailer) WithMessageStream(stream string) *PostmarkMailer {
+	m.messageStream = stream
+	return m
+}
+
+// WithBaseURL mengganti base URL API.
+func (m *PostmarkMailer) WithBaseURL(baseURL string) *PostmarkMailer {
+	m.baseURL = strings.TrimRight(baseURL, "/")
+	return m
+}
+
+// WithHTTPClient mengganti HTTP client yang dipakai untuk memanggil API.
+func (m *PostmarkMailer) WithHTTPClient(client *http.Client) *PostmarkMailer {
+	m.client = client
+	return m
+}
+
+// Send mengimplementasikan Mailer.
+func (m *PostmarkMailer) Send(ctx context.Context, msg *MailMessage) error {
+	from, err := mailFrom(msg, m.defaultFrom)
+	if err != nil {
+		return err
+	}
+
+	type header struct {
+		Name  string `json:"Name"`
+		Value string `json:"Value"`
+	}
+	type attachment struct {
+		Name        string `json:"Name"`
+		Content     []byte `json:"Content"`
+		ContentType string `json:"ContentType"`
+	}
+	payload := struct {
+		From          string            `json:"From"`
+		To            string            `json:"To"`
+		Cc            string            `json:"Cc,omitempty"`
+		Bcc           string            `json:"Bcc,omitempty"`
+		ReplyTo       string            `json:"ReplyTo,omitempty"`
+		Subject       string            `json:"Subject"`
+		HtmlBody      string            `json:"HtmlBody,omitempty"`
+		TextBody      string            `json:"TextBody,omitempty"`
+		Headers       []header          `json:"Headers,omitempty"`
+		Metadata      map[string]string `json:"Metadata,omitempty"`
+		MessageStream string            `json:"MessageStream,omitempty"`
+		Attachments   []attachment      `json:"Attachments,omitempty"`
+	}{
+		From:          from,
+		To:            strings.Join(msg.To, ","),
+		Cc:            strings.Join(msg.Cc, ","),
+		Bcc:           strings.Join(msg.Bcc, ","),
+		ReplyTo:       strings.Join(msg.ReplyTo, ","),
+		Subject:       msg.Subject,
+		HtmlBody:      msg.HTML,
+		TextBody:      msg.PlainText,
+		Metadata:      msg.Tags,
+		MessageStream: m.messageStream,
+	}
+	for k, v := range msg.Headers {
+		payload.Headers = append(payload.Headers, header{Name: k, Value: v})
+	}
+	for _, a := range msg.Attachments {
+		payload.Attachments = append(payload.Attachments, attachment{Name: a.Filename, Content: a.Data, ContentType: a.ContentType})
+	}
+
+	req, err := newMailJSONRequest(ctx, m.baseURL+"/email", payload)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Postmark-Server-Token", m.serverToken)
+	return sendMailRequest(m.client, "postmark", req)
+}
+
+// --- Resend ---
+
+// ResendMailer mengirim email melalui Resend API (POST /emails).
+type ResendMailer struct {
+	baseURL     string
+	apiKey      string
+	defaultFrom string
+	client      *http.Client
+}
+
+// NewResendMailer membuat transport Resend dengan timeout 30 detik.
+func NewResendMailer(apiKey, defaultFrom string) *ResendMailer {
+	return &ResendMailer{
+		baseURL:     "https://api.resend.com",
+		apiKey:      apiKey,
+		defaultFrom: defaultFrom,
+		client:      newMailHTTPClient(),
+	}
+}
+
+// WithBaseURL mengganti base URL API.
+func (m *ResendMailer) WithBaseURL(baseURL string) *ResendMailer {
+	m.baseURL = strings.TrimRight(baseURL, "/")
+	return m
+}
+
+// WithHTTPClient mengganti HTTP client yang dipakai untuk memanggil API.
+func (m *ResendMailer) WithHTTPClient(client *http.Client) *ResendMailer {
+	m.client = client
+	return m
+}
+
+// Send mengimplementasikan Mailer.
+func (m *ResendMailer) Send(ctx context.Context, msg *MailMessage) error {
+	from, err := mailFrom(msg, m.defaultFrom)
+	if err != nil {
+		return err
+	}
+
+	type tag struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	}
+	type attachment struct {
+		Filename    string `json:"filename"`
+		Content     []byte `json:"content"`
+		ContentType string `json:"content_type,omitempty"`
+	}
+	payload := struct {
+		From        string            `json:"from"`
+		To          []string          `json:"to"`
+		Cc          []string          `json:"cc,omitempty"`
+		Bcc         []string          `json:"bcc,omitempty"`
+		ReplyTo     []string          `json:"reply_to,omitempty"`
+		Subject     string            `json:"subject"`
+		HTML        string            `json:"html,omitempty"`
+		Text        string            `json:"text,omitempty"`
+		Headers     map[string]string `json:"headers,omitempty"`
+		Tags        []tag             `json:"tags,omitempty"`
+		Attachments []attachment      `json:"attachments,omitempty"`
+	}{
+		From:    from,
+		To:      msg.To,
+		Cc:      msg.Cc,
+		Bcc:     msg.Bcc,
+		ReplyTo: msg.ReplyTo,
+		Subject: msg.Subject,
+		HTML:    msg.HTML,
+		Text:    msg.PlainText,
+		Headers: msg.Headers,
+	}
+	for k, v := range msg.Tags {
+		payload.Tags = append(payload.Tags, tag{Name: k, Value: v})
+	}
+	for _, a := range msg.Attachments {
+		payload.Attachments = append(payload.Attachments, attachment{Filename: a.Filename, Content: a.Data, ContentType: a.ContentType})
+	}
+
+	req, err := newMailJSONRequest(ctx, m.baseURL+"/emails", payload)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+m.apiKey)
+	return sendMailRequest(m.client, "resend", req)
+}
+
+// newMailJSONRequest membuat request POST dengan body JSON. []byte di-encode sebagai base64
+// oleh encoding/json, sesuai format lampiran semua provider.
+func newMailJSONRequest(ctx context.Context, url string, payload any) (*http.Request, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode mail payload: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	return req, nil
+}
@@ -0,0 +1,275 @@
+package dim
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// capturedMailRequest mencatat request terakhir yang diterima server provider palsu.
+type capturedMailRequest struct {
+	path   string
+	header http.Header
+	body   []byte
+	form   map[string][]string
+	files  map[string]string
+}
+
+func newFakeMailProvider(t *testing.T, status int, response string) (*httptest.Server, *capturedMailRequest) {
+	t.Helper()
+	captured := &capturedMailRequest{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		captured.path = r.URL.Path
+		captured.header = r.Header.Clone()
+		if r.ParseMultipartForm(1<<20) == nil {
+			captured.form = r.MultipartForm.Value
+			captured.files = make(map[string]string)
+			for name, files := range r.MultipartForm.File {
+				f, _ := files[0].Open()
+				data, _ := io.ReadAll(f)
+				f.Close()
+				captured.files[name] = files[0].Filename + ":" + string(data)
+			}
+		} else {
+			captured.body, _ = io.ReadAll(r.Body)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		io.WriteString(w, response)
+	}))
+	t.Cleanup(server.Close)
+	return server, captured
+}
+
+func testProviderMessage() *MailMessage {
+	msg := NewMailMessage([]string{"Ana <ana@example.com>"}, "Invoice")
+	msg.HTML = "<p>Halo</p>"
+	msg.PlainText = "Halo"
+	msg.ReplyTo = []string{"support@example.com"}
+	msg.Tags = map[string]string{"category": "invoice"}
+	msg.Attachments = []Attachment{NewAttachment("invoice.txt", []byte("total 10"))}
+	return msg
+}
+
+func TestMailgunMailer_Send(t *testing.T) {
+	server, captured := newFakeMailProvider(t, http.StatusOK, `{"id":"<1@mg.example.com>","message":"Queued"}`)
+	mailer := NewMailgunMailer("mg.example.com", "key-123", "no-reply@example.com").WithBaseURL(server.URL + "/")
+
+	if err := mailer.Send(context.Background(), testProviderMessage()); err != nil {
+		t.Fatal(err)
+	}
+	if captured.path != "/v3/mg.example.com/messages" {
+		t.Errorf("path = %s", captured.path)
+	}
+	if user, pass, _ := (&http.Request{Header: captured.header}).BasicAuth(); user != "api" || pass != "key-123" {
+		t.Errorf("basic auth = %s:%s", user, pass)
+	}
+	form := captured.form
+	if form["from"][0] != "no-reply@example.com" || form["to"][0] != "Ana <ana@example.com>" || form["h:Reply-To"][0] != "support@example.com" || form["v:category"][0] != "invoice" {
+		t.Errorf("form = %v", form)
+	}
+	if captured.files["attachment"] != "invoice.txt:total 10" {
+		t.Errorf("attachment = %q", captured.files["attachment"])
+	}
+}
+
+func TestSendGridMailer_Send(t *testing.T) {
+	server, captured := newFakeMailProvider(t, http.StatusAccepted, "")
+	mailer := NewSendGridMailer("SG.key", "App <no-reply@example.com>").WithBaseURL(server.URL)
+
+	if err := mailer.Send(context.Background(), testProviderMessage()); err != nil {
+		t.Fatal(err)
+	}
+	if captured.path != "/v3/mail/send" || captured.header.Get("Authorization") != "Bearer SG.key" {
+		t.Errorf("path = %s, auth = %s", captured.path, captured.header.Get("Authorization"))
+	}
+
+	var payload struct {
+		Personalizations []struct {
+			To []sendGridAddress `json:"to"`
+		} `json:"personalizations"`
+		From    sendGridAddress `json:"from"`
+		Content []struct {
+			Type string `json:"type"`
+		} `json:"content"`
+		Attachments []struct {
+			Content  []byte `json:"content"`
+			Filename string `json:"filename"`
+		} `json:"attachments"`
+		CustomArgs map[string]string `json:"custom_args"`
+	}
+	if err := json.Unmarshal(captured.body, &payload); err != nil {
+		t.Fatal(err)
+	}
+	if to := payload.Personalizations[0].To[0]; to.Email != "ana@example.com" || to.Name != "Ana" {
+		t.Errorf("to = %+v", to)
+	}
+	if payload.From.Email != "no-reply@example.com" || payload.From.Name != "App" {
+		t.Errorf("from = %+v", payload.From)
+	}
+	if len(payload.Content) != 2 || payload.Content[0].Type != "text/plain" {
+		t.Errorf("content = %+v, want text/plain first", payload.Content)
+	}
+	if string(payload.Attachments[0].Content) != "total 10" || payload.CustomArgs["category"] != "invoice" {
+		t.Errorf("attachments = %+v, custom_args = %v", payload.Attachments, payload.CustomArgs)
+	}
+}
+
+func TestPostmarkMailer_Send(t *testing.T) {
+	server, captured := newFakeMailProvider(t, http.StatusOK, `{"ErrorCode":0,"Message":"OK"}`)
+	mailer := NewPostmarkMailer("pm-token", "no-reply@example.com").WithBaseURL(server.URL).WithMessageStream("broadcast")
+
+	if err := mailer.Send(context.Background(), testProviderMessage()); err != nil {
+		t.Fatal(err)
+	}
+	if captured.path != "/email" || captured.header.Get("X-Postmark-Server-Token") != "pm-token" {
+		t.Errorf("path = %s, token = %s", captured.path, captured.header.Get("X-Postmark-Server-Token"))
+	}
+	var payload map[string]any
+	if err := json.Unmarshal(captured.body, &payload); err != nil {
+		t.Fatal(err)
+	}
+	if payload["To"] != "Ana <ana@example.com>" || payload["MessageStream"] != "broadcast" || payload["TextBody"] != "Halo" {
+		t.Errorf("payload = %v", payload)
+	}
+}
+
+func TestResendMailer_Send(t *testing.T) {
+	server, captured := newFakeMailProvider(t, http.StatusOK, `{"id":"re_1"}`)
+	mailer := NewResendMailer("re_key", "no-reply@example.com").WithBaseURL(server.URL)
+
+	msg := testProviderMessage()
+	msg.From = "billing@example.com"
+	if err := mailer.Send(context.Background(), msg); err != nil {
+		t.Fatal(err)
+	}
+	if captured.path != "/emails" || captured.header.Get("Authorization") != "Bearer re_key" {
+		t.Errorf("path = %s, auth = %s", captured.path, captured.header.Get("Authorization"))
+	}
+	var payload struct {
+		From string `json:"from"`
+		Tags []struct {
+			Name  string `json:"name"`
+			Value string `json:"value"`
+		} `json:"tags"`
+		Attachments []struct {
+			Filename string `json:"filename"`
+			Content  []byte `json:"content"`
+		} `json:"attachments"`
+	}
+	if err := json.Unmarshal(captured.body, &payload); err != nil {
+		t.Fatal(err)
+	}
+	if payload.From != "billing@example.com" || payload.Tags[0].Name != "category" || string(payload.Attachments[0].Content) != "total 10" {
+		t.Errorf("payload = %+v", payload)
+	}
+}
+
+func TestMailProviderError_Mapping(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		response  string
+		send      func(url string) error
+		permanent bool
+		code      string
+		message   string
+	}{
+		{
+			name: "mailgun invalid recipient", status: http.StatusBadRequest, response: `{"message":"to parameter is not a valid address"}`,
+			send: func(url string) error {
+				return NewMailgunMailer("mg.example.com", "k", "a@example.com").WithBaseURL(url).Send(context.Background(), testProviderMessage())
+			},
+			permanent: true, message: "to parameter is not a valid address",
+		},
+		{
+			name: "sendgrid unauthorized", status: http.StatusUnauthorized, response: `{"errors":[{"message":"bad key"},{"message":"denied"}]}`,
+			send: func(url string) error {
+				return NewSendGridMailer("k", "a@example.com").WithBaseURL(url).Send(context.Background(), testProviderMessage())
+			},
+			permanent: true, message: "bad key; denied",
+		},
+		{
+			name: "postmark inactive recipient", status: http.StatusUnprocessableEntity, response: `{"ErrorCode":406,"Message":"inactive recipient"}`,
+			send: func(url string) error {
+				return NewPostmarkMailer("k", "a@example.com").WithBaseURL(url).Send(context.Background(), testProviderMessage())
+			},
+			permanent: true, code: "406", message: "inactive recipient",
+		},
+		{
+			name: "resend rate limited", status: http.StatusTooManyRequests, response: `{"statusCode":429,"name":"rate_limit_exceeded","message":"slow down"}`,
+			send: func(url string) error {
+				return NewResendMailer("k", "a@example.com").WithBaseURL(url).Send(context.Background(), testProviderMessage())
+			},
+			permanent: false, code: "rate_limit_exceeded", message: "slow down",
+		},
+		{
+			name: "server error", status: http.StatusBadGateway, response: `upstream down`,
+			send: func(url string) error {
+				return NewResendMailer("k", "a@example.com").WithBaseURL(url).Send(context.Background(), testProviderMessage())
+			},
+			permanent: false, message: "upstream down",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, _ := newFakeMailProvider(t, tt.status, tt.response)
+			err := tt.send(server.URL)
+
+			var perr *MailProviderError
+			if !errors.As(err, &perr) {
+				t.Fatalf("err = %v, want MailProviderError", err)
+			}
+			if perr.StatusCode != tt.status || perr.Code != tt.code || perr.Message != tt.message {
+				t.Errorf("error = %+v", perr)
+			}
+			if errors.Is(err, ErrMailPermanent) != tt.permanent {
+				t.Errorf("permanent = %v, want %v", !tt.permanent, tt.permanent)
+			}
+		})
+	}
+}
+
+func TestNewMailerFromConfig_Providers(t *testing.T) {
+	configs := []EmailConfig{
+		{Transport: "mailgun", MailgunDomain: "mg.example.com", MailgunAPIKey: "k", MailgunAPIBase: "https://api.eu.mailgun.net"},
+		{Transport: "sendgrid", SendGridAPIKey: "k"},
+		{Transport: "postmark", PostmarkServerToken: "k"},
+		{Transport: "resend", ResendAPIKey: "k"},
+	}
+	for _, cfg := range configs {
+		mailer, err := NewMailerFromConfig(&cfg, nil)
+		if err != nil {
+			t.Fatalf("%s: %v", cfg.Transport, err)
+		}
+		switch m := mailer.(type) {
+		case *MailgunMailer:
+			if m.baseURL != "https://api.eu.mailgun.net" {
+				t.Errorf("mailgun baseURL = %s", m.baseURL)
+			}
+		case *SendGridMailer, *PostmarkMailer, *ResendMailer:
+		default:
+			t.Errorf("%s: mailer = %T", cfg.Transport, mailer)
+		}
+	}
+}
+
+func TestValidate_MailProviderCredentials(t *testing.T) {
+	cfg := &Config{
+		JWT:      JWTConfig{HMACSecret: "secret", SigningMethod: "HS256"},
+		Database: DatabaseConfig{Driver: "sqlite", Database: ":memory:"},
+		Email:    EmailConfig{Transport: "postmark", From: "no-reply@example.com"},
+	}
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected error for missing POSTMARK_SERVER_TOKEN")
+	}
+	cfg.Email.PostmarkServerToken = "pm-token"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() = %v", err)
+	}
+}
@@ -0,0 +1,302 @@
+package dim
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrMailQueueFull dikembalikan MailQueue.Send jika buffer antrian penuh.
+	ErrMailQueueFull = errors.New("mail queue is full")
+	// ErrMailQueueClosed dikembalikan MailQueue.Send setelah Shutdown, dan dilaporkan ke hook
+	// untuk email yang dibatalkan karena Shutdown melewati batas waktunya.
+	ErrMailQueueClosed = errors.New("mail queue is closed")
+	// ErrMailPermanent menandai kegagalan yang tidak akan berhasil jika diulang (misal alamat
+	// penerima ditolak). Bungkus error transport dengan PermanentMailError.
+	ErrMailPermanent = errors.New("permanent mail delivery failure")
+)
+
+// PermanentMailError menandai err sebagai kegagalan permanen sehingga MailQueue tidak mengulang
+// pengiriman. errors.Is(err, ErrMailPermanent) bernilai true dan error asli tetap dapat diperiksa.
+func PermanentMailError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentMailError{err: err}
+}
+
+type permanentMailError struct {
+	err error
+}
+
+func (e *permanentMailError) Error() string        { return e.err.Error() }
+func (e *permanentMailError) Unwrap() error        { return e.err }
+func (e *permanentMailError) Is(target error) bool { return target == ErrMailPermanent }
+
+// ExponentialBackoff mengembalikan fungsi jeda retry: base dikali dua setiap percobaan (attempt
+// mulai dari 1), dibatasi max, dengan jitter hingga 50% agar retry tidak terjadi bersamaan.
+//
+// Example:
+//
+//	backoff := dim.ExponentialBackoff(time.Second, time.Minute) // ~1s, ~2s, ~4s, ... ~1m
+func ExponentialBackoff(base, max time.Duration) func(attempt int) time.Duration {
+	return func(attempt int) time.Duration {
+		d := max
+		if attempt < 1 {
+			attempt = 1
+		}
+		if attempt < 31 {
+			d = min(base<<(attempt-1), max)
+		}
+		return d/2 + rand.N(d/2+1)
+	}
+}
+
+// MailQueueOptions mengatur worker dan retry MailQueue. Nilai 0 memakai default.
+type MailQueueOptions struct {
+	// Workers adalah jumlah goroutine pengirim (default: 2).
+	Workers int
+	// Size adalah kapasitas buffer antrian (default: 100).
+	Size int
+	// MaxAttempts adalah jumlah percobaan kirim per email termasuk yang pertama (default: 3).
+	MaxAttempts int
+	// Backoff menentukan jeda sebelum percobaan berikutnya
+	// (default: ExponentialBackoff(time.Second, time.Minute)).
+	Backoff func(attempt int) time.Duration
+	// Timeout adalah batas waktu setiap percobaan kirim (default: 30 detik).
+	Timeout time.Duration
+}
+
+// MailDelivery adalah hasil satu percobaan pengiriman email, diteruskan ke hook OnDelivery.
+type MailDelivery struct {
+	Message  *MailMessage
+	Attempt  int
+	Duration time.Duration
+	// Err bernilai nil jika email terkirim.
+	Err error
+	// Final bernilai true jika email tidak akan dicoba lagi: terkirim, gagal permanen,
+	// MaxAttempts habis, atau dibatalkan Shutdown.
+	Final bool
+}
+
+// MailQueue mengirim email secara asynchronous dengan worker pool, retry, dan backoff sehingga
+// request handler tidak menunggu SMTP/API provider. MailQueue mengimplementasikan Mailer, jadi
+// dapat dipasang di mana pun Mailer diterima (RegistrationOptions, MagicLinkOptions, dll.);
+// Send hanya mengembalikan error antrian, sedangkan hasil pengiriman dilaporkan lewat OnDelivery.
+type MailQueue struct {
+	mailer    Mailer
+	opts      MailQueueOptions
+	jobs      chan mailJob
+	logger    *Logger
+	metrics   *MailerMetrics
+	transport string
+
+	mu     sync.RWMutex
+	closed bool
+	hooks  []func(ctx context.Context, delivery MailDelivery)
+
+	stop   context.Context // dibatalkan saat Shutdown melewati batas waktunya
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+type mailJob struct {
+	ctx context.Context
+	msg *MailMessage
+}
+
+// NewMailQueue membuat MailQueue di atas mailer dan langsung menjalankan worker-nya.
+//
+// Parameters:
+//   - mailer: transport sebenarnya, biasanya dari NewMailerFromConfig
+//   - opts: jumlah worker, ukuran antrian, dan kebijakan retry
+//
+// Returns:
+//   - *MailQueue: antrian yang siap menerima email
+//
+// Example:
+//
+//	transport, _ := dim.NewMailerFromConfig(&cfg.Email, nil)
+//	mailer := dim.NewMailQueue(transport, dim.MailQueueOptions{Workers: 4}).WithLogger(logger)
+//	server.OnShutdown("mail", mailer.Shutdown)
+func NewMailQueue(mailer Mailer, opts MailQueueOptions) *MailQueue {
+	if opts.Workers <= 0 {
+		opts.Workers = 2
+	}
+	if opts.Size <= 0 {
+		opts.Size = 100
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 3
+	}
+	if opts.Backoff == nil {
+		opts.Backoff = ExponentialBackoff(time.Second, time.Minute)
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 30 * time.Second
+	}
+
+	q := &MailQueue{
+		mailer: mailer,
+		opts:   opts,
+		jobs:   make(chan mailJob, opts.Size),
+	}
+	q.stop, q.cancel = context.WithCancel(context.Background())
+	for range opts.Workers {
+		q.wg.Add(1)
+		go q.work()
+	}
+	return q
+}
+
+// WithLogger mencatat percobaan yang gagal (Warn) dan email yang akhirnya tidak terkirim (Error).
+func (q *MailQueue) WithLogger(logger *Logger) *MailQueue {
+	q.logger = logger
+	return q
+}
+
+// WithMetrics mencatat setiap percobaan kirim ke MailerMetrics dengan label transport.
+//
+// Example:
+//
+//	mailer.WithMetrics(dim.NewMailerMetrics(metrics), cfg.Email.Transport)
+func (q *MailQueue) WithMetrics(metrics *MailerMetrics, transport string) *MailQueue {
+	q.metrics = metrics
+	q.transport = transport
+	return q
+}
+
+// OnDelivery mendaftarkan hook yang dipanggil setelah setiap percobaan kirim, misal untuk audit
+// log atau menandai email gagal di database. Hook dipanggil berurutan di goroutine worker.
+//
+// Example:
+//
+//	mailer.OnDelivery(func(ctx context.Context, d dim.MailDelivery) {
+//	    if d.Final && d.Err != nil {
+//	        logger.Error("email gagal", "to", d.Message.To, "attempts", d.Attempt, "error", d.Err)
+//	    }
+//	})
+func (q *MailQueue) OnDelivery(fn func(ctx context.Context, delivery MailDelivery)) *MailQueue {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.hooks = append(q.hooks, fn)
+	return q
+}
+
+// Send memasukkan msg ke antrian tanpa menunggu pengiriman. Nilai ctx (misal request ID)
+// diteruskan ke transport dan hook, tetapi pembatalannya diabaikan karena pengiriman berjalan
+// setelah request selesai.
+//
+// Returns:
+//   - error: ErrMailQueueFull jika antrian penuh, ErrMailQueueClosed setelah Shutdown
+func (q *MailQueue) Send(ctx context.Context, msg *MailMessage) error {
+	if msg == nil {
+		return fmt.Errorf("mail queue: nil message")
+	}
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	if q.closed {
+		return ErrMailQueueClosed
+	}
+	select {
+	case q.jobs <- mailJob{ctx: context.WithoutCancel(ctx), msg: msg}:
+		return nil
+	default:
+		return ErrMailQueueFull
+	}
+}
+
+// Len mengembalikan jumlah email yang menunggu di antrian (belum diambil worker).
+func (q *MailQueue) Len() int {
+	return len(q.jobs)
+}
+
+// Shutdown berhenti menerima email baru lalu menunggu antrian terkirim, termasuk retry yang
+// sedang menunggu backoff. Jika ctx selesai lebih dulu, percobaan yang berjalan dibatalkan dan
+// email yang tersisa dilaporkan ke hook dengan ErrMailQueueClosed. Signature-nya sesuai
+// ShutdownFunc untuk Server.OnShutdown.
+func (q *MailQueue) Shutdown(ctx context.Context) error {
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.jobs)
+	}
+	q.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		q.cancel()
+		return ctx.Err()
+	}
+}
+
+func (q *MailQueue) work() {
+	defer q.wg.Done()
+	for job := range q.jobs {
+		q.deliver(job)
+	}
+}
+
+// deliver mengirim satu email sampai berhasil, gagal permanen, atau MaxAttempts habis.
+func (q *MailQueue) deliver(job mailJob) {
+	for attempt := 1; ; attempt++ {
+		if q.stop.Err() != nil {
+			q.emit(job.ctx, MailDelivery{Message: job.msg, Attempt: attempt - 1, Err: ErrMailQueueClosed, Final: true})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(job.ctx, q.opts.Timeout)
+		stopAttempt := context.AfterFunc(q.stop, cancel)
+		start := time.Now()
+		err := q.mailer.Send(ctx, job.msg)
+		duration := time.Since(start)
+		stopAttempt()
+		cancel()
+
+		final := err == nil || errors.Is(err, ErrMailPermanent) || attempt >= q.opts.MaxAttempts
+		if q.metrics != nil {
+			q.metrics.ObserveSend(q.transport, err)
+		}
+		q.emit(job.ctx, MailDelivery{Message: job.msg, Attempt: attempt, Duration: duration, Err: err, Final: final})
+		if final {
+			return
+		}
+
+		timer := time.NewTimer(q.opts.Backoff(attempt))
+		select {
+		case <-timer.C:
+		case <-q.stop.Done():
+			timer.Stop()
+		}
+	}
+}
+
+func (q *MailQueue) emit(ctx context.Context, delivery MailDelivery) {
+	if q.logger != nil && delivery.Err != nil {
+		args := []any{"to", delivery.Message.To, "subject", delivery.Message.Subject, "attempt", delivery.Attempt, "error", delivery.Err.Error()}
+		if delivery.Final {
+			q.logger.Error("Email delivery failed", args...)
+		} else {
+			q.logger.Warn("Email delivery failed, retrying", args...)
+		}
+	}
+
+	q.mu.RLock()
+	hooks := q.hooks
+	q.mu.RUnlock()
+	for _, hook := range hooks {
+		hook(ctx, delivery)
+	}
+}
@@ -0,0 +1,163 @@
+package dim
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// flakyMailer gagal sebanyak failures kali sebelum berhasil, atau selalu mengembalikan err jika di-set.
+type flakyMailer struct {
+	failures atomic.Int32
+	err      error
+	block    chan struct{}
+	sent     atomic.Int32
+}
+
+func (m *flakyMailer) Send(ctx context.Context, msg *MailMessage) error {
+	if m.block != nil {
+		select {
+		case <-m.block:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if m.err != nil {
+		return m.err
+	}
+	if m.failures.Add(-1) >= 0 {
+		return errors.New("451 temporary failure")
+	}
+	m.sent.Add(1)
+	return nil
+}
+
+type deliveryRecorder struct {
+	mu         sync.Mutex
+	deliveries []MailDelivery
+}
+
+func (r *deliveryRecorder) record(ctx context.Context, d MailDelivery) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.deliveries = append(r.deliveries, d)
+}
+
+func (r *deliveryRecorder) all() []MailDelivery {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]MailDelivery(nil), r.deliveries...)
+}
+
+func noBackoff(int) time.Duration { return time.Millisecond }
+
+func TestMailQueue_RetriesUntilSent(t *testing.T) {
+	mailer := &flakyMailer{}
+	mailer.failures.Store(2)
+	recorder := &deliveryRecorder{}
+	metrics := NewMetricsRegistry()
+	q := NewMailQueue(mailer, MailQueueOptions{Workers: 1, Backoff: noBackoff}).
+		WithMetrics(NewMailerMetrics(metrics), "smtp").
+		OnDelivery(recorder.record)
+
+	if err := q.Send(context.Background(), NewMailMessage([]string{"ana@example.com"}, "Halo")); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	deliveries := recorder.all()
+	if len(deliveries) != 3 || mailer.sent.Load() != 1 {
+		t.Fatalf("deliveries = %+v, sent %d", deliveries, mailer.sent.Load())
+	}
+	if d := deliveries[0]; d.Err == nil || d.Final || d.Attempt != 1 {
+		t.Errorf("first attempt = %+v", d)
+	}
+	if d := deliveries[2]; d.Err != nil || !d.Final || d.Attempt != 3 {
+		t.Errorf("last attempt = %+v", d)
+	}
+	var out strings.Builder
+	metrics.WriteTo(&out)
+	for _, want := range []string{`dim_mailer_sent_total{transport="smtp",status="failure"} 2`, `dim_mailer_sent_total{transport="smtp",status="success"} 1`} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("metrics missing %s:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestMailQueue_GivesUp(t *testing.T) {
+	recorder := &deliveryRecorder{}
+	q := NewMailQueue(&flakyMailer{err: PermanentMailError(errors.New("550 mailbox unavailable"))}, MailQueueOptions{Backoff: noBackoff}).
+		OnDelivery(recorder.record)
+	q.Send(context.Background(), NewMailMessage([]string{"nobody@example.com"}, "Halo"))
+
+	failing := &flakyMailer{err: errors.New("connection refused")}
+	q2 := NewMailQueue(failing, MailQueueOptions{MaxAttempts: 2, Backoff: noBackoff}).OnDelivery(recorder.record)
+	q2.Send(context.Background(), NewMailMessage([]string{"ana@example.com"}, "Halo"))
+
+	q.Shutdown(context.Background())
+	q2.Shutdown(context.Background())
+
+	deliveries := recorder.all()
+	if len(deliveries) != 3 {
+		t.Fatalf("deliveries = %+v, want 1 permanent + 2 attempts", deliveries)
+	}
+	for _, d := range deliveries {
+		if d.Message.To[0] == "nobody@example.com" && (!d.Final || !errors.Is(d.Err, ErrMailPermanent) || d.Attempt != 1) {
+			t.Errorf("permanent failure should not be retried: %+v", d)
+		}
+	}
+}
+
+func TestMailQueue_FullAndClosed(t *testing.T) {
+	mailer := &flakyMailer{block: make(chan struct{})}
+	q := NewMailQueue(mailer, MailQueueOptions{Workers: 1, Size: 1})
+	msg := NewMailMessage([]string{"ana@example.com"}, "Halo")
+
+	q.Send(context.Background(), msg) // diambil worker dan tertahan
+	deadline := time.Now().Add(time.Second)
+	for q.Len() != 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if err := q.Send(context.Background(), msg); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Send(context.Background(), msg); !errors.Is(err, ErrMailQueueFull) {
+		t.Errorf("err = %v, want ErrMailQueueFull", err)
+	}
+
+	recorder := &deliveryRecorder{}
+	q.OnDelivery(recorder.record)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := q.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Shutdown = %v, want deadline exceeded", err)
+	}
+	if err := q.Send(context.Background(), msg); !errors.Is(err, ErrMailQueueClosed) {
+		t.Errorf("err = %v, want ErrMailQueueClosed", err)
+	}
+
+	// Percobaan yang tertahan dibatalkan dan email yang tersisa dilaporkan sebagai ditutup
+	deadline = time.Now().Add(time.Second)
+	for len(recorder.all()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	deliveries := recorder.all()
+	if len(deliveries) < 2 || !errors.Is(deliveries[len(deliveries)-1].Err, ErrMailQueueClosed) {
+		t.Errorf("deliveries = %+v", deliveries)
+	}
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	backoff := ExponentialBackoff(time.Second, 5*time.Second)
+	for attempt, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 4: 5 * time.Second, 100: 5 * time.Second} {
+		if d := backoff(attempt); d < want/2 || d > want {
+			t.Errorf("backoff(%d) = %v, want within [%v, %v]", attempt, d, want/2, want)
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package dim
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testResendSecret = "whsec_MfKQ9r8GKYqrTwjUPD8ILPZIo2LaLaSw"
+
+func mailgunWebhookPayload(signingKey string, timestamp time.Time, event string) []byte {
+	token := "a8ce0edb2dd8301dee6c2405235584e45aa91d1e9f979f3de0"
+	return fmt.Appendf(nil, `{
+  "signature": {"timestamp": "%d", "token": %q, "signature": %q},
+  "event-data": {
+    "event": %q, "severity": "permanent", "recipient": "ana@example.com", "timestamp": 1521472262.908181,
+    "reason": "bounce",
+    "delivery-status": {"code": 550, "description": "mailbox does not exist"},
+    "message": {"headers": {"message-id": "20180319.1@mg.example.com"}},
+    "user-variables": {"user_id": 42}
+  }
+}`, timestamp.Unix(), token, SignMailgunWebhook(signingKey, timestamp, token), event)
+}
+
+func TestVerifyMailgunSignature(t *testing.T) {
+	now := time.Unix(1718000000, 0)
+	sig := SignMailgunWebhook("key", now, "tok")
+	ts := strconv.FormatInt(now.Unix(), 10)
+
+	tests := []struct {
+		name      string
+		timestamp string
+		signature string
+		now       time.Time
+		wantErr   error
+	}{
+		{"valid", ts, sig, now, nil},
+		{"missing", ts, "", now, ErrMailWebhookSignatureMissing},
+		{"wrong key", ts, SignMailgunWebhook("other", now, "tok"), now, ErrMailWebhookSignatureInvalid},
+		{"tampered timestamp", "1718000001", sig, now, ErrMailWebhookSignatureInvalid},
+		{"expired", ts, sig, now.Add(10 * time.Minute), ErrMailWebhookSignatureExpired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyMailgunSignature(tt.timestamp, "tok", tt.signature, "key", 5*time.Minute, tt.now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMailWebhook_Mailgun(t *testing.T) {
+	registry := NewMetricsRegistry()
+	var got []*MailEvent
+	webhook := NewMailWebhook(MailWebhookConfig{Provider: "mailgun", Secret: "key"}).
+		WithMetrics(NewMailerMetrics(registry)).
+		On(MailEventBounced, func(ctx context.Context, event *MailEvent) error {
+			got = append(got, event)
+			return nil
+		})
+
+	events, err := webhook.ParseEvents(http.Header{}, mailgunWebhookPayload("other", time.Now(), "failed"))
+	if !errors.Is(err, ErrMailWebhookSignatureInvalid) || events != nil {
+		t.Fatalf("forged payload: events = %v, err = %v", events, err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/mail", bytes.NewReader(mailgunWebhookPayload("key", time.Now(), "failed")))
+	w := httptest.NewRecorder()
+	webhook.Handler()(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if len(got) != 1 {
+		t.Fatalf("handled %d events", len(got))
+	}
+	event := got[0]
+	if event.Recipient != "ana@example.com" || event.Reason != "mailbox does not exist" || event.MessageID != "20180319.1@mg.example.com" || event.Tags["user_id"] != "42" {
+		t.Errorf("event = %+v", event)
+	}
+	if event.Timestamp.Unix() != 1521472262 {
+		t.Errorf("timestamp = %v", event.Timestamp)
+	}
+
+	var b strings.Builder
+	registry.WriteTo(&b)
+	if !strings.Contains(b.String(), `dim_mailer_bounces_total{transport="mailgun"} 1`) {
+		t.Errorf("bounce metric missing:\n%s", b.String())
+	}
+}
+
+func signSendGridPayload(t *testing.T, key *ecdsa.PrivateKey, payload []byte, timestamp string) string {
+	t.Helper()
+	digest := sha256.Sum256(append([]byte(timestamp), payload...))
+	sig, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return base64.StdEncoding.EncodeToString(sig)
+}
+
+func TestMailWebhook_SendGrid(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	der, _ := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	webhook := NewMailWebhook(MailWebhookConfig{Provider: "sendgrid", Secret: base64.StdEncoding.EncodeToString(der)})
+
+	payload := []byte(`[
+  {"email":"ana@example.com","timestamp":1718000000,"event":"bounce","type":"bounce","reason":"550 5.1.1 unknown user","sg_message_id":"sg-1"},
+  {"email":"budi@example.com","timestamp":1718000001,"event":"bounce","type":"blocked","reason":"ip blocked"},
+  {"email":"citra@example.com","timestamp":1718000002,"event":"spamreport"},
+  {"email":"dewi@example.com","timestamp":1718000003,"event":"open"}
+]`)
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	header := http.Header{}
+	header.Set(SendGridSignatureHeader, signSendGridPayload(t, key, payload, ts))
+	header.Set(SendGridTimestampHeader, ts)
+
+	events, err := webhook.ParseEvents(header, payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []MailEventType{MailEventBounced, MailEventDeferred, MailEventComplained, MailEventOther}
+	for i, event := range events {
+		if event.Type != want[i] {
+			t.Errorf("event %d type = %s, want %s", i, event.Type, want[i])
+		}
+	}
+	if events[0].MessageID != "sg-1" || events[0].Reason != "550 5.1.1 unknown user" {
+		t.Errorf("event = %+v", events[0])
+	}
+
+	if _, err := webhook.ParseEvents(header, append(payload, ' ')); !errors.Is(err, ErrMailWebhookSignatureInvalid) {
+		t.Errorf("tampered payload err = %v", err)
+	}
+	header.Del(SendGridSignatureHeader)
+	if _, err := webhook.ParseEvents(header, payload); !errors.Is(err, ErrMailWebhookSignatureMissing) {
+		t.Errorf("missing signature err = %v", err)
+	}
+}
+
+func TestMailWebhook_Postmark(t *testing.T) {
+	webhook, err := NewMailWebhookFromConfig(&EmailConfig{Transport: "postmark", PostmarkWebhookUsername: "hook", PostmarkWebhookPassword: "s3cret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload := []byte(`{"RecordType":"Bounce","Type":"HardBounce","MessageID":"pm-1","Email":"ana@example.com","Description":"The server was unable to deliver your message","Inactive":true,"BouncedAt":"2024-06-10T06:13:20Z","Metadata":{"category":"invoice"}}`)
+
+	header := http.Header{}
+	if _, err := webhook.ParseEvents(header, payload); !errors.Is(err, ErrMailWebhookSignatureMissing) {
+		t.Errorf("no auth err = %v", err)
+	}
+	req := http.Request{Header: header}
+	req.SetBasicAuth("hook", "wrong")
+	if _, err := webhook.ParseEvents(header, payload); !errors.Is(err, ErrMailWebhookSignatureInvalid) {
+		t.Errorf("wrong password err = %v", err)
+	}
+
+	req.SetBasicAuth("hook", "s3cret")
+	events, err := webhook.ParseEvents(header, payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	event := events[0]
+	if event.Type != MailEventBounced || event.Recipient != "ana@example.com" || event.Tags["category"] != "invoice" || !event.Timestamp.Equal(time.Unix(1718000000, 0)) {
+		t.Errorf("event = %+v", event)
+	}
+
+	events, _ = webhook.ParseEvents(header, []byte(`{"RecordType":"SpamComplaint","Email":"ana@example.com"}`))
+	if events[0].Type != MailEventComplained {
+		t.Errorf("spam complaint type = %s", events[0].Type)
+	}
+}
+
+func TestMailWebhook_Resend(t *testing.T) {
+	webhook, err := NewMailWebhookFromConfig(&EmailConfig{Transport: "resend", ResendWebhookSecret: testResendSecret})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var handled int
+	webhook.On("*", func(ctx context.Context, event *MailEvent) error {
+		handled++
+		return errors.New("database down")
+	})
+
+	payload := []byte(`{"type":"email.complained","created_at":"2024-06-10T06:13:20.000Z","data":{"email_id":"re-1","to":["ana@example.com"],"tags":{"category":"invoice"}}}`)
+	header, err := SignResendPayload(payload, testResendSecret, "msg_1", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := webhook.ParseEvents(header, payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event := events[0]; event.Type != MailEventComplained || event.Recipient != "ana@example.com" || event.MessageID != "re-1" || event.Tags["category"] != "invoice" {
+		t.Errorf("event = %+v", event)
+	}
+
+	// Handler yang gagal menghasilkan 500 agar Resend mengirim ulang
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/mail", bytes.NewReader(payload))
+	req.Header = header
+	w := httptest.NewRecorder()
+	webhook.Handler()(w, req)
+	if w.Code != http.StatusInternalServerError || handled != 1 {
+		t.Errorf("status = %d, handled = %d", w.Code, handled)
+	}
+
+	stale, _ := SignResendPayload(payload, testResendSecret, "msg_1", time.Now().Add(-time.Hour))
+	if _, err := webhook.ParseEvents(stale, payload); !errors.Is(err, ErrMailWebhookSignatureExpired) {
+		t.Errorf("stale err = %v", err)
+	}
+	header.Set(ResendSignatureHeader, "v1,bm9wZQ== v2,abc")
+	if _, err := webhook.ParseEvents(header, payload); !errors.Is(err, ErrMailWebhookSignatureInvalid) {
+		t.Errorf("forged err = %v", err)
+	}
+}
+
+func TestNewMailWebhookFromConfig_Errors(t *testing.T) {
+	for _, cfg := range []EmailConfig{
+		{Transport: "smtp"},
+		{Transport: "mailgun"},
+		{Transport: "postmark", PostmarkWebhookUsername: "hook"},
+	} {
+		if _, err := NewMailWebhookFromConfig(&cfg); err == nil {
+			t.Errorf("%s: expected error", cfg.Transport)
+		}
+	}
+}
@@ -0,0 +1,341 @@
+package dim
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultHistogramBuckets adalah bucket default (dalam detik) untuk metric durasi.
+var DefaultHistogramBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// metricKind adalah tipe metric dalam format exposition Prometheus.
+type metricKind string
+
+const (
+	metricCounter   metricKind = "counter"
+	metricGauge     metricKind = "gauge"
+	metricHistogram metricKind = "histogram"
+)
+
+// MetricsRegistry menyimpan metric aplikasi dan mengeksposnya dalam format text Prometheus.
+// Tidak bergantung pada client library eksternal; output kompatibel dengan scraper Prometheus,
+// VictoriaMetrics, Grafana Agent, dsb. Thread-safe.
+type MetricsRegistry struct {
+	mu      sync.RWMutex
+	metrics map[string]*metricFamily
+}
+
+// NewMetricsRegistry membuat MetricsRegistry kosong.
+//
+// Returns:
+//   - *MetricsRegistry: registry yang siap digunakan
+//
+// Example:
+//
+//	metrics := dim.NewMetricsRegistry()
+//	router.Get("/metrics", metrics.Handler())
+func NewMetricsRegistry() *MetricsRegistry {
+	return &MetricsRegistry{
+		metrics: make(map[string]*metricFamily),
+	}
+}
+
+// metricFamily menyimpan semua series untuk satu nama metric.
+type metricFamily struct {
+	name    string
+	help    string
+	kind    metricKind
+	labels  []string
+	buckets []float64
+
+	mu     sync.Mutex
+	series map[string]*metricSeries
+}
+
+// metricSeries adalah nilai untuk satu kombinasi label.
+type metricSeries struct {
+	labelValues []string
+	value       float64
+	count       uint64
+	sum         float64
+	bucketCount []uint64
+}
+
+// Counter adalah metric yang hanya bisa bertambah (misal jumlah job yang diproses).
+type Counter struct {
+	family *metricFamily
+}
+
+// Gauge adalah metric yang bisa naik dan turun (misal kedalaman queue).
+type Gauge struct {
+	family *metricFamily
+}
+
+// Histogram mengukur distribusi nilai (misal durasi job) dalam bucket.
+type Histogram struct {
+	family *metricFamily
+}
+
+// Counter mendaftarkan (atau mengambil yang sudah ada) counter dengan nama dan label tertentu.
+// Nama counter sebaiknya diakhiri dengan "_total".
+//
+// Parameters:
+//   - name: nama metric (snake_case, misal "dim_queue_jobs_processed_total")
+//   - help: deskripsi singkat metric
+//   - labels: nama label (misal "queue", "status")
+//
+// Returns:
+//   - *Counter: counter yang siap digunakan
+//
+// Example:
+//
+//	processed := metrics.Counter("app_orders_total", "Total order dibuat", "channel")
+//	processed.Inc("web")
+func (r *MetricsRegistry) Counter(name, help string, labels ...string) *Counter {
+	return &Counter{family: r.register(name, help, metricCounter, labels, nil)}
+}
+
+// Gauge mendaftarkan (atau mengambil yang sudah ada) gauge dengan nama dan label tertentu.
+//
+// Example:
+//
+//	depth := metrics.Gauge("app_queue_depth", "Jumlah job menunggu", "queue")
+//	depth.Set(12, "emails")
+func (r *MetricsRegistry) Gauge(name, help string, labels ...string) *Gauge {
+	return &Gauge{family: r.register(name, help, metricGauge, labels, nil)}
+}
+
+// Histogram mendaftarkan (atau mengambil yang sudah ada) histogram dengan nama dan label tertentu.
+// Jika buckets nil, DefaultHistogramBuckets digunakan. Nama histogram durasi sebaiknya
+// diakhiri dengan "_seconds".
+//
+// Example:
+//
+//	duration := metrics.Histogram("app_export_duration_seconds", "Durasi export", nil, "format")
+//	duration.Observe(time.Since(start).Seconds(), "csv")
+func (r *MetricsRegistry) Histogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	if buckets == nil {
+		buckets = DefaultHistogramBuckets
+	}
+	sorted := append([]float64(nil), buckets...)
+	sort.Float64s(sorted)
+	return &Histogram{family: r.register(name, help, metricHistogram, labels, sorted)}
+}
+
+// register mendaftarkan metric family. Panic jika nama yang sama didaftarkan dengan tipe
+// atau label berbeda, karena itu adalah kesalahan programmer yang harus terlihat saat startup.
+func (r *MetricsRegistry) register(name, help string, kind metricKind, labels []string, buckets []float64) *metricFamily {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if existing, ok := r.metrics[name]; ok {
+		if existing.kind != kind || strings.Join(existing.labels, ",") != strings.Join(labels, ",") {
+			panic(fmt.Sprintf("dim: metric %q already registered with different type or labels", name))
+		}
+		return existing
+	}
+
+	family := &metricFamily{
+		name:    name,
+		help:    help,
+		kind:    kind,
+		labels:  append([]string(nil), labels...),
+		buckets: buckets,
+		series:  make(map[string]*metricSeries),
+	}
+	r.metrics[name] = family
+	return family
+}
+
+// getSeries mengambil atau membuat series untuk label values. Caller harus memegang lock family.
+func (f *metricFamily) getSeries(labelValues []string) *metricSeries {
+	if len(labelValues) != len(f.labels) {
+		panic(fmt.Sprintf("dim: metric %q expects %d label values, got %d", f.name, len(f.labels), len(labelValues)))
+	}
+
+	key := strings.Join(labelValues, "\xff")
+	s, ok := f.series[key]
+	if !ok {
+		s = &metricSeries{labelValues: append([]string(nil), labelValues...)}
+		if f.kind == metricHistogram {
+			s.bucketCount = make([]uint64, len(f.buckets))
+		}
+		f.series[key] = s
+	}
+	return s
+}
+
+// Inc menambah counter sebesar 1.
+func (c *Counter) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add menambah counter sebesar v. Nilai negatif diabaikan.
+func (c *Counter) Add(v float64, labelValues ...string) {
+	if v < 0 {
+		return
+	}
+	c.family.mu.Lock()
+	defer c.family.mu.Unlock()
+	c.family.getSeries(labelValues).value += v
+}
+
+// Set mengatur nilai gauge.
+func (g *Gauge) Set(v float64, labelValues ...string) {
+	g.family.mu.Lock()
+	defer g.family.mu.Unlock()
+	g.family.getSeries(labelValues).value = v
+}
+
+// Add menambah (atau mengurangi jika negatif) nilai gauge.
+func (g *Gauge) Add(v float64, labelValues ...string) {
+	g.family.mu.Lock()
+	defer g.family.mu.Unlock()
+	g.family.getSeries(labelValues).value += v
+}
+
+// Inc menambah gauge sebesar 1.
+func (g *Gauge) Inc(labelValues ...string) {
+	g.Add(1, labelValues...)
+}
+
+// Dec mengurangi gauge sebesar 1.
+func (g *Gauge) Dec(labelValues ...string) {
+	g.Add(-1, labelValues...)
+}
+
+// Observe mencatat satu observasi ke histogram.
+func (h *Histogram) Observe(v float64, labelValues ...string) {
+	h.family.mu.Lock()
+	defer h.family.mu.Unlock()
+
+	s := h.family.getSeries(labelValues)
+	s.count++
+	s.sum += v
+	for i, upper := range h.family.buckets {
+		if v <= upper {
+			s.bucketCount[i]++
+		}
+	}
+}
+
+// WriteTo menulis semua metric dalam format text exposition Prometheus (version 0.0.4).
+// Metric diurutkan berdasarkan nama agar output deterministik.
+func (r *MetricsRegistry) WriteTo(w io.Writer) (int64, error) {
+	r.mu.RLock()
+	names := make([]string, 0, len(r.metrics))
+	for name := range r.metrics {
+		names = append(names, name)
+	}
+	families := make([]*metricFamily, 0, len(names))
+	sort.Strings(names)
+	for _, name := range names {
+		families = append(families, r.metrics[name])
+	}
+	r.mu.RUnlock()
+
+	var b strings.Builder
+	for _, f := range families {
+		f.writeTo(&b)
+	}
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+func (f *metricFamily) writeTo(b *strings.Builder) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	fmt.Fprintf(b, "# HELP %s %s\n", f.name, escapeMetricHelp(f.help))
+	fmt.Fprintf(b, "# TYPE %s %s\n", f.name, f.kind)
+
+	keys := make([]string, 0, len(f.series))
+	for key := range f.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		s := f.series[key]
+		switch f.kind {
+		case metricHistogram:
+			for i, upper := range f.buckets {
+				fmt.Fprintf(b, "%s_bucket%s %d\n", f.name, formatMetricLabels(f.labels, s.labelValues, "le", formatMetricValue(upper)), s.bucketCount[i])
+			}
+			fmt.Fprintf(b, "%s_bucket%s %d\n", f.name, formatMetricLabels(f.labels, s.labelValues, "le", "+Inf"), s.count)
+			fmt.Fprintf(b, "%s_sum%s %s\n", f.name, formatMetricLabels(f.labels, s.labelValues, "", ""), formatMetricValue(s.sum))
+			fmt.Fprintf(b, "%s_count%s %d\n", f.name, formatMetricLabels(f.labels, s.labelValues, "", ""), s.count)
+		default:
+			fmt.Fprintf(b, "%s%s %s\n", f.name, formatMetricLabels(f.labels, s.labelValues, "", ""), formatMetricValue(s.value))
+		}
+	}
+}
+
+// Handler mengembalikan HandlerFunc yang mengekspos metric untuk di-scrape.
+//
+// Example:
+//
+//	router.Get("/metrics", metrics.Handler())
+func (r *MetricsRegistry) Handler() HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		r.WriteTo(w) //nolint:errcheck
+	}
+}
+
+// formatMetricLabels membentuk blok label {a="x",b="y"}, dengan label tambahan opsional (misal "le").
+func formatMetricLabels(names, values []string, extraName, extraValue string) string {
+	if len(names) == 0 && extraName == "" {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, name := range names {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, "%s=\"%s\"", name, escapeMetricLabel(values[i]))
+	}
+	if extraName != "" {
+		if len(names) > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, "%s=\"%s\"", extraName, extraValue)
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+func formatMetricValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var (
+	metricLabelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+	metricHelpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+func escapeMetricLabel(s string) string {
+	return metricLabelEscaper.Replace(s)
+}
+
+func escapeMetricHelp(s string) string {
+	return metricHelpEscaper.Replace(s)
+}
@@ -0,0 +1,139 @@
+package dim
+
+import "time"
+
+// Konvensi metric untuk subsystem background dim:
+//   - Semua nama diawali prefix "dim_<subsystem>_" dan ditulis dalam snake_case.
+//   - Counter diakhiri "_total", durasi dalam detik dan diakhiri "_seconds".
+//   - Label "status" hanya bernilai MetricStatusSuccess atau MetricStatusFailure.
+//   - Label nama (queue, job, task, transport) memakai nama yang didaftarkan aplikasi,
+//     bukan ID dinamis, agar cardinality tetap terkendali.
+const (
+	MetricStatusSuccess = "success"
+	MetricStatusFailure = "failure"
+)
+
+// Nama metric queue.
+const (
+	// MetricQueueDepth (gauge, label: queue) — jumlah job yang menunggu diproses.
+	MetricQueueDepth = "dim_queue_depth"
+	// MetricQueueJobDuration (histogram, label: queue, job, status) — durasi eksekusi job.
+	MetricQueueJobDuration = "dim_queue_job_duration_seconds"
+	// MetricQueueJobsProcessed (counter, label: queue, job, status) — job yang selesai diproses.
+	MetricQueueJobsProcessed = "dim_queue_jobs_processed_total"
+	// MetricQueueJobRetries (counter, label: queue, job) — job yang dijadwalkan ulang setelah gagal.
+	MetricQueueJobRetries = "dim_queue_job_retries_total"
+)
+
+// Nama metric scheduler.
+const (
+	// MetricSchedulerRunDuration (histogram, label: task, status) — durasi setiap run task terjadwal.
+	MetricSchedulerRunDuration = "dim_scheduler_run_duration_seconds"
+	// MetricSchedulerMissedRuns (counter, label: task) — run yang terlewat (misal run sebelumnya masih berjalan).
+	MetricSchedulerMissedRuns = "dim_scheduler_missed_runs_total"
+)
+
+// Nama metric mailer.
+const (
+	// MetricMailerSent (counter, label: transport, status) — email yang dikirim, sukses maupun gagal.
+	MetricMailerSent = "dim_mailer_sent_total"
+	// MetricMailerBounces (counter, label: transport) — bounce yang dilaporkan oleh transport/webhook.
+	MetricMailerBounces = "dim_mailer_bounces_total"
+)
+
+// metricStatus mengonversi error menjadi nilai label status.
+func metricStatus(err error) string {
+	if err != nil {
+		return MetricStatusFailure
+	}
+	return MetricStatusSuccess
+}
+
+// QueueMetrics mencatat metric job queue ke MetricsRegistry.
+type QueueMetrics struct {
+	depth     *Gauge
+	duration  *Histogram
+	processed *Counter
+	retries   *Counter
+}
+
+// NewQueueMetrics mendaftarkan metric queue ke registry.
+//
+// Example:
+//
+//	metrics := dim.NewMetricsRegistry()
+//	qm := dim.NewQueueMetrics(metrics)
+//	qm.ObserveJob("default", "send_welcome_email", time.Since(start), err)
+func NewQueueMetrics(r *MetricsRegistry) *QueueMetrics {
+	return &QueueMetrics{
+		depth:     r.Gauge(MetricQueueDepth, "Number of jobs waiting to be processed.", "queue"),
+		duration:  r.Histogram(MetricQueueJobDuration, "Job execution duration in seconds.", nil, "queue", "job", "status"),
+		processed: r.Counter(MetricQueueJobsProcessed, "Total number of processed jobs.", "queue", "job", "status"),
+		retries:   r.Counter(MetricQueueJobRetries, "Total number of job retries.", "queue", "job"),
+	}
+}
+
+// SetDepth mengatur jumlah job yang menunggu pada queue.
+func (m *QueueMetrics) SetDepth(queue string, depth int) {
+	m.depth.Set(float64(depth), queue)
+}
+
+// ObserveJob mencatat durasi dan hasil eksekusi satu job.
+func (m *QueueMetrics) ObserveJob(queue, job string, duration time.Duration, err error) {
+	status := metricStatus(err)
+	m.duration.Observe(duration.Seconds(), queue, job, status)
+	m.processed.Inc(queue, job, status)
+}
+
+// IncRetry mencatat job yang dijadwalkan ulang.
+func (m *QueueMetrics) IncRetry(queue, job string) {
+	m.retries.Inc(queue, job)
+}
+
+// SchedulerMetrics mencatat metric scheduler ke MetricsRegistry.
+type SchedulerMetrics struct {
+	duration *Histogram
+	missed   *Counter
+}
+
+// NewSchedulerMetrics mendaftarkan metric scheduler ke registry.
+func NewSchedulerMetrics(r *MetricsRegistry) *SchedulerMetrics {
+	return &SchedulerMetrics{
+		duration: r.Histogram(MetricSchedulerRunDuration, "Scheduled task run duration in seconds.", nil, "task", "status"),
+		missed:   r.Counter(MetricSchedulerMissedRuns, "Total number of missed scheduled runs.", "task"),
+	}
+}
+
+// ObserveRun mencatat durasi dan hasil satu run task.
+func (m *SchedulerMetrics) ObserveRun(task string, duration time.Duration, err error) {
+	m.duration.Observe(duration.Seconds(), task, metricStatus(err))
+}
+
+// IncMissed mencatat run task yang terlewat.
+func (m *SchedulerMetrics) IncMissed(task string) {
+	m.missed.Inc(task)
+}
+
+// MailerMetrics mencatat metric pengiriman email ke MetricsRegistry.
+type MailerMetrics struct {
+	sent    *Counter
+	bounces *Counter
+}
+
+// NewMailerMetrics mendaftarkan metric mailer ke registry.
+func NewMailerMetrics(r *MetricsRegistry) *MailerMetrics {
+	return &MailerMetrics{
+		sent:    r.Counter(MetricMailerSent, "Total number of emails sent.", "transport", "status"),
+		bounces: r.Counter(MetricMailerBounces, "Total number of bounced emails.", "transport"),
+	}
+}
+
+// ObserveSend mencatat hasil pengiriman satu email.
+func (m *MailerMetrics) ObserveSend(transport string, err error) {
+	m.sent.Inc(transport, metricStatus(err))
+}
+
+// IncBounce mencatat email yang bounce.
+func (m *MailerMetrics) IncBounce(transport string) {
+	m.bounces.Inc(transport)
+}
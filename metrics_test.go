@@ -0,0 +1,154 @@
+package dim
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMetricsRegistry_Counter(t *testing.T) {
+	reg := NewMetricsRegistry()
+	c := reg.Counter("app_orders_total", "Total orders.", "channel")
+
+	c.Inc("web")
+	c.Add(2, "web")
+	c.Add(-5, "web") // ignored
+	c.Inc("mobile")
+
+	var b strings.Builder
+	reg.WriteTo(&b)
+	out := b.String()
+
+	for _, want := range []string{
+		"# HELP app_orders_total Total orders.",
+		"# TYPE app_orders_total counter",
+		`app_orders_total{channel="mobile"} 1`,
+		`app_orders_total{channel="web"} 3`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestMetricsRegistry_Gauge(t *testing.T) {
+	reg := NewMetricsRegistry()
+	g := reg.Gauge("app_in_flight", "In flight requests.")
+
+	g.Inc()
+	g.Inc()
+	g.Dec()
+
+	var b strings.Builder
+	reg.WriteTo(&b)
+	if !strings.Contains(b.String(), "app_in_flight 1\n") {
+		t.Errorf("unexpected output:\n%s", b.String())
+	}
+}
+
+func TestMetricsRegistry_Histogram(t *testing.T) {
+	reg := NewMetricsRegistry()
+	h := reg.Histogram("app_duration_seconds", "Duration.", []float64{1, 0.1}, "op")
+
+	h.Observe(0.0625, "read")
+	h.Observe(0.5, "read")
+	h.Observe(3, "read")
+
+	var b strings.Builder
+	reg.WriteTo(&b)
+	out := b.String()
+
+	for _, want := range []string{
+		`app_duration_seconds_bucket{op="read",le="0.1"} 1`,
+		`app_duration_seconds_bucket{op="read",le="1"} 2`,
+		`app_duration_seconds_bucket{op="read",le="+Inf"} 3`,
+		`app_duration_seconds_sum{op="read"} 3.5625`,
+		`app_duration_seconds_count{op="read"} 3`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestMetricsRegistry_EscapesLabels(t *testing.T) {
+	reg := NewMetricsRegistry()
+	reg.Counter("app_errors_total", "Errors.", "msg").Inc("say \"hi\"\n")
+
+	var b strings.Builder
+	reg.WriteTo(&b)
+	if !strings.Contains(b.String(), `app_errors_total{msg="say \"hi\"\n"} 1`) {
+		t.Errorf("expected escaped label, got:\n%s", b.String())
+	}
+}
+
+func TestMetricsRegistry_ReRegister(t *testing.T) {
+	reg := NewMetricsRegistry()
+	a := reg.Counter("app_total", "Total.", "x")
+	b := reg.Counter("app_total", "Total.", "x")
+	if a.family != b.family {
+		t.Error("expected same family for identical registration")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic when re-registering with different type")
+		}
+	}()
+	reg.Gauge("app_total", "Total.", "x")
+}
+
+func TestMetricsRegistry_Handler(t *testing.T) {
+	reg := NewMetricsRegistry()
+	reg.Counter("app_hits_total", "Hits.").Inc()
+
+	rec := httptest.NewRecorder()
+	reg.Handler()(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("unexpected content type %q", ct)
+	}
+	if !strings.Contains(rec.Body.String(), "app_hits_total 1") {
+		t.Errorf("unexpected body:\n%s", rec.Body.String())
+	}
+}
+
+func TestSubsystemMetrics(t *testing.T) {
+	reg := NewMetricsRegistry()
+
+	qm := NewQueueMetrics(reg)
+	qm.SetDepth("default", 4)
+	qm.ObserveJob("default", "send_email", 20*time.Millisecond, nil)
+	qm.ObserveJob("default", "send_email", 20*time.Millisecond, errors.New("smtp down"))
+	qm.IncRetry("default", "send_email")
+
+	sm := NewSchedulerMetrics(reg)
+	sm.ObserveRun("cleanup", time.Second, nil)
+	sm.IncMissed("cleanup")
+
+	mm := NewMailerMetrics(reg)
+	mm.ObserveSend("smtp", nil)
+	mm.IncBounce("smtp")
+
+	var b strings.Builder
+	reg.WriteTo(&b)
+	out := b.String()
+
+	for _, want := range []string{
+		`dim_queue_depth{queue="default"} 4`,
+		`dim_queue_jobs_processed_total{queue="default",job="send_email",status="failure"} 1`,
+		`dim_queue_jobs_processed_total{queue="default",job="send_email",status="success"} 1`,
+		`dim_queue_job_retries_total{queue="default",job="send_email"} 1`,
+		`dim_scheduler_run_duration_seconds_count{task="cleanup",status="success"} 1`,
+		`dim_scheduler_missed_runs_total{task="cleanup"} 1`,
+		`dim_mailer_sent_total{transport="smtp",status="success"} 1`,
+		`dim_mailer_bounces_total{transport="smtp"} 1`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
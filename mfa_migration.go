@@ -0,0 +1,172 @@
+package dim
+
+import (
+	"context"
+)
+
+// GetMFAMigrations mengembalikan migrasi tabel MFA (secret TOTP, recovery code, dan token
+// mfa_pending) yang dipakai DatabaseTokenStore sebagai MFAStore. Modul ini opsional sehingga
+// tidak termasuk dalam GetFrameworkMigrations; gabungkan secara manual setelah migrasi users.
+// Menggunakan versi 141-143 agar tidak bentrok dengan migrasi framework maupun aplikasi.
+//
+// Example:
+//
+//	migrations := append(dim.GetFrameworkMigrations(), dim.GetMFAMigrations()...)
+//	dim.RunMigrations(db, migrations)
+func GetMFAMigrations() []Migration {
+	return []Migration{
+		{
+			Version: 141,
+			Name:    "create_mfa_secrets_table",
+			Up:      CreateMFASecretsTable,
+			Down:    DropMFASecretsTable,
+		},
+		{
+			Version: 142,
+			Name:    "create_mfa_recovery_codes_table",
+			Up:      CreateMFARecoveryCodesTable,
+			Down:    DropMFARecoveryCodesTable,
+		},
+		{
+			Version: 143,
+			Name:    "create_mfa_challenges_table",
+			Up:      CreateMFAChallengesTable,
+			Down:    DropMFAChallengesTable,
+		},
+	}
+}
+
+// CreateMFASecretsTable membuat tabel mfa_secrets (satu secret TOTP per user).
+func CreateMFASecretsTable(db Database) error {
+	var query string
+	switch db.DriverName() {
+	case "sqlite":
+		query = `
+			CREATE TABLE IF NOT EXISTS mfa_secrets (
+				user_id TEXT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+				secret TEXT NOT NULL,
+				confirmed_at TIMESTAMP NULL,
+				last_used_step INTEGER NOT NULL DEFAULT 0,
+				created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+			)
+		`
+	case "mysql":
+		query = `
+			CREATE TABLE IF NOT EXISTS mfa_secrets (
+				user_id CHAR(36) PRIMARY KEY,
+				secret VARCHAR(64) NOT NULL,
+				confirmed_at DATETIME NULL,
+				last_used_step BIGINT NOT NULL DEFAULT 0,
+				created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				CONSTRAINT fk_mfa_secrets_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+		`
+	default:
+		query = `
+			CREATE TABLE IF NOT EXISTS mfa_secrets (
+				user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+				secret VARCHAR(64) NOT NULL,
+				confirmed_at TIMESTAMP NULL,
+				last_used_step BIGINT NOT NULL DEFAULT 0,
+				created_at TIMESTAMP NOT NULL DEFAULT NOW()
+			)
+		`
+	}
+	return db.Exec(context.Background(), query)
+}
+
+// DropMFASecretsTable menghapus tabel mfa_secrets.
+func DropMFASecretsTable(db Database) error {
+	return db.Exec(context.Background(), "DROP TABLE IF EXISTS mfa_secrets")
+}
+
+// CreateMFARecoveryCodesTable membuat tabel mfa_recovery_codes. Hanya hash code yang disimpan.
+func CreateMFARecoveryCodesTable(db Database) error {
+	var query string
+	switch db.DriverName() {
+	case "sqlite":
+		query = `
+			CREATE TABLE IF NOT EXISTS mfa_recovery_codes (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+				code_hash TEXT NOT NULL,
+				used_at TIMESTAMP NULL,
+				created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+			);
+			CREATE INDEX IF NOT EXISTS idx_mfa_recovery_codes_user_id ON mfa_recovery_codes(user_id);
+		`
+	case "mysql":
+		query = `
+			CREATE TABLE IF NOT EXISTS mfa_recovery_codes (
+				id BIGINT AUTO_INCREMENT PRIMARY KEY,
+				user_id CHAR(36) NOT NULL,
+				code_hash VARCHAR(64) NOT NULL,
+				used_at DATETIME NULL,
+				created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				KEY idx_mfa_recovery_codes_user_id (user_id),
+				CONSTRAINT fk_mfa_recovery_codes_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+		`
+	default:
+		query = `
+			CREATE TABLE IF NOT EXISTS mfa_recovery_codes (
+				id BIGSERIAL PRIMARY KEY,
+				user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+				code_hash VARCHAR(64) NOT NULL,
+				used_at TIMESTAMP NULL,
+				created_at TIMESTAMP NOT NULL DEFAULT NOW()
+			);
+			CREATE INDEX IF NOT EXISTS idx_mfa_recovery_codes_user_id ON mfa_recovery_codes(user_id);
+		`
+	}
+	return db.Exec(context.Background(), query)
+}
+
+// DropMFARecoveryCodesTable menghapus tabel mfa_recovery_codes.
+func DropMFARecoveryCodesTable(db Database) error {
+	return db.Exec(context.Background(), "DROP TABLE IF EXISTS mfa_recovery_codes")
+}
+
+// CreateMFAChallengesTable membuat tabel mfa_challenges untuk token mfa_pending.
+func CreateMFAChallengesTable(db Database) error {
+	var query string
+	switch db.DriverName() {
+	case "sqlite":
+		query = `
+			CREATE TABLE IF NOT EXISTS mfa_challenges (
+				token_hash TEXT PRIMARY KEY,
+				user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+				attempts INTEGER NOT NULL DEFAULT 0,
+				expires_at TIMESTAMP NOT NULL,
+				created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+			)
+		`
+	case "mysql":
+		query = `
+			CREATE TABLE IF NOT EXISTS mfa_challenges (
+				token_hash VARCHAR(64) PRIMARY KEY,
+				user_id CHAR(36) NOT NULL,
+				attempts INT NOT NULL DEFAULT 0,
+				expires_at DATETIME NOT NULL,
+				created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				CONSTRAINT fk_mfa_challenges_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+		`
+	default:
+		query = `
+			CREATE TABLE IF NOT EXISTS mfa_challenges (
+				token_hash VARCHAR(64) PRIMARY KEY,
+				user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+				attempts INT NOT NULL DEFAULT 0,
+				expires_at TIMESTAMP NOT NULL,
+				created_at TIMESTAMP NOT NULL DEFAULT NOW()
+			)
+		`
+	}
+	return db.Exec(context.Background(), query)
+}
+
+// DropMFAChallengesTable menghapus tabel mfa_challenges.
+func DropMFAChallengesTable(db Database) error {
+	return db.Exec(context.Background(), "DROP TABLE IF EXISTS mfa_challenges")
+}
@@ -0,0 +1,333 @@
+package dim
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// FindMFASecret finds the TOTP secret of a user.
+func (s *DatabaseTokenStore) FindMFASecret(ctx context.Context, userID string) (*MFASecret, error) {
+	secret := &MFASecret{}
+	query := `SELECT user_id, secret, confirmed_at, last_used_step, created_at
+		 FROM mfa_secrets WHERE user_id = $1`
+
+	err := s.db.QueryRow(ctx, s.db.Rebind(query), userID).Scan(
+		&secret.UserID, &secret.Secret, &secret.ConfirmedAt, &secret.LastUsedStep, &secret.CreatedAt,
+	)
+	if err != nil {
+		if isNoRows(err) {
+			return nil, ErrMFANotEnrolled
+		}
+		return nil, fmt.Errorf("failed to find mfa secret: %w", err)
+	}
+
+	return secret, nil
+}
+
+// SaveMFASecret inserts or replaces the TOTP secret of a user.
+func (s *DatabaseTokenStore) SaveMFASecret(ctx context.Context, secret *MFASecret) error {
+	if secret.CreatedAt.IsZero() {
+		secret.CreatedAt = time.Now().UTC().Truncate(time.Second)
+	}
+	var confirmedAt *time.Time
+	if secret.ConfirmedAt != nil {
+		t := secret.ConfirmedAt.UTC().Truncate(time.Second)
+		confirmedAt = &t
+	}
+
+	err := InTx(ctx, s.db, func(ctx context.Context) error {
+		if err := s.db.Exec(ctx, s.db.Rebind(`DELETE FROM mfa_secrets WHERE user_id = $1`), secret.UserID); err != nil {
+			return err
+		}
+		query := `INSERT INTO mfa_secrets (user_id, secret, confirmed_at, last_used_step, created_at)
+			 VALUES ($1, $2, $3, $4, $5)`
+		return s.db.Exec(ctx, s.db.Rebind(query),
+			secret.UserID, secret.Secret, confirmedAt, secret.LastUsedStep, secret.CreatedAt.UTC().Truncate(time.Second))
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save mfa secret: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteMFA deletes the TOTP secret and recovery codes of a user.
+func (s *DatabaseTokenStore) DeleteMFA(ctx context.Context, userID string) error {
+	err := InTx(ctx, s.db, func(ctx context.Context) error {
+		if err := s.db.Exec(ctx, s.db.Rebind(`DELETE FROM mfa_recovery_codes WHERE user_id = $1`), userID); err != nil {
+			return err
+		}
+		return s.db.Exec(ctx, s.db.Rebind(`DELETE FROM mfa_secrets WHERE user_id = $1`), userID)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete mfa: %w", err)
+	}
+
+	return nil
+}
+
+// ReplaceRecoveryCodes replaces all recovery codes of a user with the given hashes.
+func (s *DatabaseTokenStore) ReplaceRecoveryCodes(ctx context.Context, userID string, codeHashes []string) error {
+	now := time.Now().UTC().Truncate(time.Second)
+	err := InTx(ctx, s.db, func(ctx context.Context) error {
+		if err := s.db.Exec(ctx, s.db.Rebind(`DELETE FROM mfa_recovery_codes WHERE user_id = $1`), userID); err != nil {
+			return err
+		}
+		query := s.db.Rebind(`INSERT INTO mfa_recovery_codes (user_id, code_hash, created_at) VALUES ($1, $2, $3)`)
+		for _, hash := range codeHashes {
+			if err := s.db.Exec(ctx, query, userID, hash, now); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save recovery codes: %w", err)
+	}
+
+	return nil
+}
+
+// UseRecoveryCode marks an unused recovery code as used.
+func (s *DatabaseTokenStore) UseRecoveryCode(ctx context.Context, userID, codeHash string) error {
+	var id int64
+	query := `SELECT id FROM mfa_recovery_codes WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL`
+
+	if err := s.db.QueryRow(ctx, s.db.Rebind(query), userID, codeHash).Scan(&id); err != nil {
+		if isNoRows(err) {
+			return ErrRecoveryCodeInvalid
+		}
+		return fmt.Errorf("failed to find recovery code: %w", err)
+	}
+
+	update := `UPDATE mfa_recovery_codes SET used_at = $1 WHERE id = $2`
+	if err := s.db.Exec(ctx, s.db.Rebind(update), time.Now().UTC().Truncate(time.Second), id); err != nil {
+		return fmt.Errorf("failed to use recovery code: %w", err)
+	}
+
+	return nil
+}
+
+// CountRecoveryCodes counts the unused recovery codes of a user.
+func (s *DatabaseTokenStore) CountRecoveryCodes(ctx context.Context, userID string) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM mfa_recovery_codes WHERE user_id = $1 AND used_at IS NULL`
+
+	if err := s.db.QueryRow(ctx, s.db.Rebind(query), userID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count recovery codes: %w", err)
+	}
+
+	return count, nil
+}
+
+// SaveMFAChallenge saves a pending MFA challenge.
+func (s *DatabaseTokenStore) SaveMFAChallenge(ctx context.Context, challenge *MFAChallenge) error {
+	challenge.CreatedAt = time.Now().UTC().Truncate(time.Second)
+	query := `INSERT INTO mfa_challenges (token_hash, user_id, attempts, expires_at, created_at)
+		 VALUES ($1, $2, $3, $4, $5)`
+
+	err := s.db.Exec(ctx, s.db.Rebind(query),
+		challenge.TokenHash,
+		challenge.UserID,
+		challenge.Attempts,
+		challenge.ExpiresAt.UTC().Truncate(time.Second),
+		challenge.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save mfa challenge: %w", err)
+	}
+
+	return nil
+}
+
+// FindMFAChallenge finds a pending MFA challenge by token hash.
+func (s *DatabaseTokenStore) FindMFAChallenge(ctx context.Context, tokenHash string) (*MFAChallenge, error) {
+	challenge := &MFAChallenge{}
+	query := `SELECT token_hash, user_id, attempts, expires_at, created_at
+		 FROM mfa_challenges WHERE token_hash = $1`
+
+	err := s.db.QueryRow(ctx, s.db.Rebind(query), tokenHash).Scan(
+		&challenge.TokenHash, &challenge.UserID, &challenge.Attempts, &challenge.ExpiresAt, &challenge.CreatedAt,
+	)
+	if err != nil {
+		if isNoRows(err) {
+			return nil, ErrMFAChallengeNotFound
+		}
+		return nil, fmt.Errorf("failed to find mfa challenge: %w", err)
+	}
+
+	return challenge, nil
+}
+
+// IncrementMFAChallengeAttempts atomically increments the attempts of a challenge while it is
+// below max and returns the new count.
+func (s *DatabaseTokenStore) IncrementMFAChallengeAttempts(ctx context.Context, tokenHash string, max int) (int, error) {
+	var attempts int
+	var err error
+	if s.db.DriverName() == "mysql" {
+		// MySQL does not support UPDATE ... RETURNING; lock the row within a transaction instead.
+		err = s.db.WithTx(ctx, func(ctx context.Context, tx Tx) error {
+			if err := tx.QueryRow(ctx, `SELECT attempts FROM mfa_challenges WHERE token_hash = $1 FOR UPDATE`, tokenHash).Scan(&attempts); err != nil {
+				return err
+			}
+			if attempts >= max {
+				return ErrMFAChallengeNotFound
+			}
+			attempts++
+			return tx.Exec(ctx, `UPDATE mfa_challenges SET attempts = $1 WHERE token_hash = $2`, attempts, tokenHash)
+		})
+	} else {
+		query := `UPDATE mfa_challenges SET attempts = attempts + 1
+			 WHERE token_hash = $1 AND attempts < $2 RETURNING attempts`
+		err = s.db.QueryRow(ctx, s.db.Rebind(query), tokenHash, max).Scan(&attempts)
+	}
+	if err != nil {
+		if isNoRows(err) || errors.Is(err, ErrMFAChallengeNotFound) {
+			return 0, ErrMFAChallengeNotFound
+		}
+		return 0, fmt.Errorf("failed to update mfa challenge: %w", err)
+	}
+
+	return attempts, nil
+}
+
+// ConsumeMFAChallenge atomically deletes a challenge so that only one caller can use it.
+func (s *DatabaseTokenStore) ConsumeMFAChallenge(ctx context.Context, tokenHash string) error {
+	var err error
+	if s.db.DriverName() == "mysql" {
+		// MySQL does not support DELETE ... RETURNING; lock the row within a transaction instead.
+		err = s.db.WithTx(ctx, func(ctx context.Context, tx Tx) error {
+			var found string
+			if err := tx.QueryRow(ctx, `SELECT token_hash FROM mfa_challenges WHERE token_hash = $1 FOR UPDATE`, tokenHash).Scan(&found); err != nil {
+				return err
+			}
+			return tx.Exec(ctx, `DELETE FROM mfa_challenges WHERE token_hash = $1`, tokenHash)
+		})
+	} else {
+		var deleted string
+		query := `DELETE FROM mfa_challenges WHERE token_hash = $1 RETURNING token_hash`
+		err = s.db.QueryRow(ctx, s.db.Rebind(query), tokenHash).Scan(&deleted)
+	}
+	if err != nil {
+		if isNoRows(err) {
+			return ErrMFAChallengeNotFound
+		}
+		return fmt.Errorf("failed to consume mfa challenge: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteMFAChallenge deletes a challenge after use or expiry.
+func (s *DatabaseTokenStore) DeleteMFAChallenge(ctx context.Context, tokenHash string) error {
+	query := `DELETE FROM mfa_challenges WHERE token_hash = $1`
+
+	if err := s.db.Exec(ctx, s.db.Rebind(query), tokenHash); err != nil {
+		return fmt.Errorf("failed to delete mfa challenge: %w", err)
+	}
+
+	return nil
+}
+
+// FindMFASecret finds a TOTP secret in mock store.
+func (s *MockTokenStore) FindMFASecret(ctx context.Context, userID string) (*MFASecret, error) {
+	secret, exists := s.mfaSecrets[userID]
+	if !exists {
+		return nil, ErrMFANotEnrolled
+	}
+	copied := *secret
+	return &copied, nil
+}
+
+// SaveMFASecret saves a TOTP secret in mock store.
+func (s *MockTokenStore) SaveMFASecret(ctx context.Context, secret *MFASecret) error {
+	if secret.CreatedAt.IsZero() {
+		secret.CreatedAt = time.Now()
+	}
+	copied := *secret
+	s.mfaSecrets[secret.UserID] = &copied
+	return nil
+}
+
+// DeleteMFA deletes MFA data in mock store.
+func (s *MockTokenStore) DeleteMFA(ctx context.Context, userID string) error {
+	delete(s.mfaSecrets, userID)
+	delete(s.recoveryCodes, userID)
+	return nil
+}
+
+// ReplaceRecoveryCodes replaces recovery codes in mock store.
+func (s *MockTokenStore) ReplaceRecoveryCodes(ctx context.Context, userID string, codeHashes []string) error {
+	codes := make(map[string]bool, len(codeHashes))
+	for _, hash := range codeHashes {
+		codes[hash] = false
+	}
+	s.recoveryCodes[userID] = codes
+	return nil
+}
+
+// UseRecoveryCode marks a recovery code as used in mock store.
+func (s *MockTokenStore) UseRecoveryCode(ctx context.Context, userID, codeHash string) error {
+	used, exists := s.recoveryCodes[userID][codeHash]
+	if !exists || used {
+		return ErrRecoveryCodeInvalid
+	}
+	s.recoveryCodes[userID][codeHash] = true
+	return nil
+}
+
+// CountRecoveryCodes counts unused recovery codes in mock store.
+func (s *MockTokenStore) CountRecoveryCodes(ctx context.Context, userID string) (int, error) {
+	count := 0
+	for _, used := range s.recoveryCodes[userID] {
+		if !used {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// SaveMFAChallenge saves a challenge in mock store.
+func (s *MockTokenStore) SaveMFAChallenge(ctx context.Context, challenge *MFAChallenge) error {
+	challenge.CreatedAt = time.Now()
+	copied := *challenge
+	s.mfaChallenges[challenge.TokenHash] = &copied
+	return nil
+}
+
+// FindMFAChallenge finds a challenge in mock store.
+func (s *MockTokenStore) FindMFAChallenge(ctx context.Context, tokenHash string) (*MFAChallenge, error) {
+	challenge, exists := s.mfaChallenges[tokenHash]
+	if !exists {
+		return nil, ErrMFAChallengeNotFound
+	}
+	copied := *challenge
+	return &copied, nil
+}
+
+// IncrementMFAChallengeAttempts increments challenge attempts in mock store.
+func (s *MockTokenStore) IncrementMFAChallengeAttempts(ctx context.Context, tokenHash string, max int) (int, error) {
+	challenge, exists := s.mfaChallenges[tokenHash]
+	if !exists || challenge.Attempts >= max {
+		return 0, ErrMFAChallengeNotFound
+	}
+	challenge.Attempts++
+	return challenge.Attempts, nil
+}
+
+// ConsumeMFAChallenge deletes a challenge in mock store, failing if it was already deleted.
+func (s *MockTokenStore) ConsumeMFAChallenge(ctx context.Context, tokenHash string) error {
+	if _, exists := s.mfaChallenges[tokenHash]; !exists {
+		return ErrMFAChallengeNotFound
+	}
+	delete(s.mfaChallenges, tokenHash)
+	return nil
+}
+
+// DeleteMFAChallenge deletes a challenge in mock store.
+func (s *MockTokenStore) DeleteMFAChallenge(ctx context.Context, tokenHash string) error {
+	delete(s.mfaChallenges, tokenHash)
+	return nil
+}
@@ -0,0 +1,328 @@
+package dim
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestMFAAuthService(t *testing.T, userStore AuthUserStore, tokenStore interface {
+	TokenStore
+	MFAStore
+}) *AuthService {
+	t.Helper()
+	service, err := NewAuthService(userStore, tokenStore, nil, &JWTConfig{
+		HMACSecret:         "test-secret",
+		SigningMethod:      "HS256",
+		AccessTokenExpiry:  15 * time.Minute,
+		RefreshTokenExpiry: 7 * 24 * time.Hour,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Clock tetap agar kode TOTP tidak berpindah step di tengah test
+	now := time.Now()
+	service.now = func() time.Time { return now }
+	return service.WithMFA(tokenStore, "Acme")
+}
+
+// enableTestMFA mendaftarkan dan mengonfirmasi MFA, lalu mengembalikan secret, kode TOTP yang
+// dipakai untuk konfirmasi, dan recovery code.
+func enableTestMFA(t *testing.T, service *AuthService, user Authenticatable) (string, string, []string) {
+	t.Helper()
+	ctx := context.Background()
+	enrollment, err := service.EnrollMFA(ctx, user)
+	if err != nil {
+		t.Fatalf("EnrollMFA error: %v", err)
+	}
+	if enrollment.ProvisioningURI != TOTPProvisioningURI("Acme", user.GetEmail(), enrollment.Secret) {
+		t.Errorf("ProvisioningURI = %s", enrollment.ProvisioningURI)
+	}
+
+	if _, err := service.ConfirmMFA(ctx, user.GetID(), "000000"); err == nil {
+		t.Error("expected wrong confirmation code to fail")
+	}
+	code, _ := TOTPCode(enrollment.Secret, service.now())
+	codes, err := service.ConfirmMFA(ctx, user.GetID(), code)
+	if err != nil {
+		t.Fatalf("ConfirmMFA error: %v", err)
+	}
+	if len(codes) != 10 || len(codes[0]) != 11 {
+		t.Fatalf("recovery codes = %v", codes)
+	}
+	return enrollment.Secret, code, codes
+}
+
+func loginExpectMFA(t *testing.T, service *AuthService) *MFARequiredError {
+	t.Helper()
+	_, _, err := service.Login(context.Background(), "test@example.com", "ValidPass123!")
+	var mfa *MFARequiredError
+	if !errors.As(err, &mfa) || !errors.Is(err, ErrMFARequired) || mfa.MFAToken == "" {
+		t.Fatalf("Login error = %v, want MFARequiredError", err)
+	}
+	return mfa
+}
+
+func TestAuthService_MFALogin(t *testing.T) {
+	ctx := context.Background()
+	userStore := NewMockUserStore()
+	hashed, _ := HashPassword("ValidPass123!")
+	user := &MockUser{ID: "1", Email: "test@example.com", Password: hashed}
+	userStore.AddUser(user)
+	service := newTestMFAAuthService(t, userStore, NewMockTokenStore())
+
+	// Tanpa MFA aktif, Login langsung menerbitkan token tanpa claim mfa
+	access, _, err := service.Login(ctx, "test@example.com", "ValidPass123!")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims, _ := service.tokenManager.VerifyToken(access); claims[MFAClaim] != nil {
+		t.Errorf("unexpected mfa claim before enrollment")
+	}
+
+	secret, usedCode, recovery := enableTestMFA(t, service, user)
+	if _, err := service.EnrollMFA(ctx, user); err == nil {
+		t.Error("expected EnrollMFA to fail when MFA is already active")
+	}
+
+	// Kode yang sudah dipakai untuk konfirmasi ditolak (replay)
+	mfa := loginExpectMFA(t, service)
+	if _, _, err := service.VerifyMFA(ctx, mfa.MFAToken, usedCode); err == nil {
+		t.Fatal("expected reused TOTP code to be rejected")
+	}
+
+	next, _ := TOTPCode(secret, service.now().Add(30*time.Second))
+	access, refresh, err := service.VerifyMFA(ctx, mfa.MFAToken, next)
+	if err != nil {
+		t.Fatalf("VerifyMFA error: %v", err)
+	}
+	if claims, _ := service.tokenManager.VerifyToken(access); claims[MFAClaim] != true {
+		t.Errorf("access token claims = %v, want mfa claim", claims)
+	}
+	if _, _, err := service.VerifyMFA(ctx, mfa.MFAToken, next); err == nil {
+		t.Error("expected mfa token to be single use")
+	}
+
+	// Refresh tetap membawa claim mfa
+	access, _, err = service.RefreshToken(ctx, refresh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims, _ := service.tokenManager.VerifyToken(access); claims[MFAClaim] != true {
+		t.Errorf("refreshed token claims = %v, want mfa claim", claims)
+	}
+
+	// Recovery code hanya dapat dipakai sekali
+	mfa = loginExpectMFA(t, service)
+	if _, _, err := service.VerifyMFA(ctx, mfa.MFAToken, recovery[0]); err != nil {
+		t.Fatalf("VerifyMFA with recovery code error: %v", err)
+	}
+	mfa = loginExpectMFA(t, service)
+	if _, _, err := service.VerifyMFA(ctx, mfa.MFAToken, recovery[0]); err == nil {
+		t.Error("expected used recovery code to be rejected")
+	}
+	if n, _ := service.RemainingRecoveryCodes(ctx, "1"); n != 9 {
+		t.Errorf("RemainingRecoveryCodes = %d, want 9", n)
+	}
+
+	// DisableMFA membutuhkan kode valid
+	if err := service.DisableMFA(ctx, "1", "123"); err == nil {
+		t.Error("expected DisableMFA without valid code to fail")
+	}
+	if err := service.DisableMFA(ctx, "1", recovery[1]); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := service.Login(ctx, "test@example.com", "ValidPass123!"); err != nil {
+		t.Errorf("Login after DisableMFA error = %v", err)
+	}
+}
+
+func TestAuthService_MFAAttemptLimit(t *testing.T) {
+	ctx := context.Background()
+	userStore := NewMockUserStore()
+	hashed, _ := HashPassword("ValidPass123!")
+	user := &MockUser{ID: "1", Email: "test@example.com", Password: hashed}
+	userStore.AddUser(user)
+	service := newTestMFAAuthService(t, userStore, NewMockTokenStore())
+	secret, _, _ := enableTestMFA(t, service, user)
+
+	mfa := loginExpectMFA(t, service)
+	for i := 0; i < mfaMaxAttempts; i++ {
+		if _, _, err := service.VerifyMFA(ctx, mfa.MFAToken, "000000"); err == nil {
+			t.Fatal("expected wrong code to fail")
+		}
+	}
+	next, _ := TOTPCode(secret, service.now().Add(30*time.Second))
+	if _, _, err := service.VerifyMFA(ctx, mfa.MFAToken, next); err == nil {
+		t.Error("expected mfa token to be invalid after too many attempts")
+	}
+}
+
+func TestAuthService_MFADatabaseStore(t *testing.T) {
+	db := newTestSQLiteAuthDB(t)
+	if err := RunMigrations(db, GetMFAMigrations()); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	hashed, _ := HashPassword("ValidPass123!")
+	if err := db.Exec(ctx, db.Rebind("INSERT INTO users (id, email, password) VALUES ($1, $2, $3)"), "1", "test@example.com", hashed); err != nil {
+		t.Fatal(err)
+	}
+
+	userStore := NewDatabaseAuthUserStore(db)
+	service := newTestMFAAuthService(t, userStore, NewDatabaseTokenStore(db))
+	user, err := userStore.FindByID(ctx, "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret, _, recovery := enableTestMFA(t, service, user)
+
+	mfa := loginExpectMFA(t, service)
+	if _, _, err := service.VerifyMFA(ctx, mfa.MFAToken, "000000"); err == nil {
+		t.Fatal("expected wrong code to fail")
+	}
+	next, _ := TOTPCode(secret, service.now().Add(30*time.Second))
+	if _, _, err := service.VerifyMFA(ctx, mfa.MFAToken, next); err != nil {
+		t.Fatalf("VerifyMFA error: %v", err)
+	}
+
+	mfa = loginExpectMFA(t, service)
+	if _, _, err := service.VerifyMFA(ctx, mfa.MFAToken, " "+recovery[2]+" "); err != nil {
+		t.Fatalf("VerifyMFA with recovery code error: %v", err)
+	}
+	if n, err := service.RemainingRecoveryCodes(ctx, "1"); err != nil || n != 9 {
+		t.Errorf("RemainingRecoveryCodes = %d, %v", n, err)
+	}
+
+	if err := service.DisableMFA(ctx, "1", recovery[3]); err != nil {
+		t.Fatal(err)
+	}
+	if enabled, err := service.MFAEnabled(ctx, "1"); err != nil || enabled {
+		t.Errorf("MFAEnabled = %v, %v", enabled, err)
+	}
+}
+
+func TestDatabaseTokenStore_MFAChallengeConcurrency(t *testing.T) {
+	db := newTestSQLiteAuthDB(t)
+	if err := RunMigrations(db, GetMFAMigrations()); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if err := db.Exec(ctx, db.Rebind("INSERT INTO users (id, email, password) VALUES ($1, $2, $3)"), "1", "test@example.com", "hash"); err != nil {
+		t.Fatal(err)
+	}
+	store := NewDatabaseTokenStore(db)
+	if err := store.SaveMFAChallenge(ctx, &MFAChallenge{TokenHash: "h1", UserID: "1", ExpiresAt: time.Now().Add(time.Minute)}); err != nil {
+		t.Fatal(err)
+	}
+
+	var incremented, consumed atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := store.IncrementMFAChallengeAttempts(ctx, "h1", mfaMaxAttempts)
+			if err == nil {
+				incremented.Add(1)
+			} else if !errors.Is(err, ErrMFAChallengeNotFound) {
+				t.Errorf("IncrementMFAChallengeAttempts error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if got := incremented.Load(); got != mfaMaxAttempts {
+		t.Errorf("%d increments succeeded, want %d", got, mfaMaxAttempts)
+	}
+	if challenge, _ := store.FindMFAChallenge(ctx, "h1"); challenge == nil || challenge.Attempts != mfaMaxAttempts {
+		t.Errorf("challenge = %+v, want %d attempts", challenge, mfaMaxAttempts)
+	}
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := store.ConsumeMFAChallenge(ctx, "h1")
+			if err == nil {
+				consumed.Add(1)
+			} else if !errors.Is(err, ErrMFAChallengeNotFound) {
+				t.Errorf("ConsumeMFAChallenge error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if got := consumed.Load(); got != 1 {
+		t.Errorf("%d consumes succeeded, want 1", got)
+	}
+}
+
+func TestAuthService_VerifyMFAConcurrent(t *testing.T) {
+	db := newTestSQLiteAuthDB(t)
+	if err := RunMigrations(db, GetMFAMigrations()); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	hashed, _ := HashPassword("ValidPass123!")
+	if err := db.Exec(ctx, db.Rebind("INSERT INTO users (id, email, password) VALUES ($1, $2, $3)"), "1", "test@example.com", hashed); err != nil {
+		t.Fatal(err)
+	}
+	userStore := NewDatabaseAuthUserStore(db)
+	service := newTestMFAAuthService(t, userStore, NewDatabaseTokenStore(db))
+	user, err := userStore.FindByID(ctx, "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, recovery := enableTestMFA(t, service, user)
+
+	// Setiap request membawa recovery code valid yang berbeda, tetapi token mfa_pending hanya
+	// boleh menerbitkan satu session.
+	mfa := loginExpectMFA(t, service)
+	var succeeded atomic.Int32
+	var wg sync.WaitGroup
+	for _, code := range recovery {
+		wg.Add(1)
+		go func(code string) {
+			defer wg.Done()
+			if _, _, err := service.VerifyMFA(ctx, mfa.MFAToken, code); err == nil {
+				succeeded.Add(1)
+			}
+		}(code)
+	}
+	wg.Wait()
+	if got := succeeded.Load(); got != 1 {
+		t.Errorf("%d concurrent VerifyMFA calls succeeded, want 1", got)
+	}
+}
+
+func TestRequireMFA(t *testing.T) {
+	handler := RequireMFA()(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	for name, tc := range map[string]struct {
+		claims map[string]interface{}
+		want   int
+	}{
+		"no mfa claim":    {map[string]interface{}{}, http.StatusForbidden},
+		"mfa false":       {map[string]interface{}{MFAClaim: false}, http.StatusForbidden},
+		"mfa verified":    {map[string]interface{}{MFAClaim: true}, http.StatusOK},
+		"unauthenticated": {nil, http.StatusForbidden},
+	} {
+		t.Run(name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/admin", nil)
+			if tc.claims != nil {
+				r = SetUser(r, &TokenUser{ID: "1", Claims: tc.claims})
+			}
+			w := httptest.NewRecorder()
+			handler(w, r)
+			if w.Code != tc.want {
+				t.Errorf("status = %d, want %d", w.Code, tc.want)
+			}
+		})
+	}
+}
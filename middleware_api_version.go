@@ -0,0 +1,318 @@
+package dim
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const apiVersionKey contextKey = "api_version"
+
+// DefaultAPIVersionHeader adalah header yang dipakai client untuk meminta versi API.
+const DefaultAPIVersionHeader = "X-API-Version"
+
+// APIVersionDeprecation menjelaskan kebijakan deprecation sebuah versi API.
+// Dikirim ke client melalui header Deprecation, Sunset, dan Link.
+type APIVersionDeprecation struct {
+	// Date adalah waktu versi dinyatakan deprecated (opsional).
+	// Jika kosong, header dikirim sebagai "Deprecation: true".
+	Date time.Time
+	// Sunset adalah waktu versi berhenti didukung (opsional, header Sunset RFC 8594).
+	Sunset time.Time
+	// Link adalah URL panduan migrasi (opsional, dikirim sebagai Link rel="deprecation").
+	Link string
+}
+
+// APIVersionConfig mengatur middleware APIVersionMiddleware.
+type APIVersionConfig struct {
+	// Versions adalah versi yang didukung, berurutan dari yang terlama ke yang terbaru (wajib),
+	// misal []string{"1", "2", "3"} atau []string{"2024-01-15", "2025-03-01"}.
+	Versions []string
+	// Default adalah versi untuk request yang tidak meminta versi (default: versi terbaru).
+	Default string
+	// Header adalah nama header versi (default: DefaultAPIVersionHeader).
+	Header string
+	// MediaTypeParam adalah parameter media type di header Accept, misal
+	// "Accept: application/json; version=2" (default: "version").
+	MediaTypeParam string
+	// Deprecated berisi kebijakan deprecation per versi (opsional).
+	Deprecated map[string]APIVersionDeprecation
+	// OnDeprecated dipanggil untuk setiap request yang memakai versi deprecated,
+	// misal untuk mencatat client yang belum migrasi (opsional).
+	OnDeprecated func(r *http.Request, version string)
+}
+
+// ResponseTransformer mengubah data response dari bentuk versi yang lebih baru ke bentuk versi
+// tempat transformer didaftarkan. Menerima data yang diberikan handler ke Json/JsonPagination
+// (atau hasil transformer versi yang lebih baru).
+type ResponseTransformer func(data interface{}) (interface{}, error)
+
+// APIVersionMiddleware menegosiasikan versi API setiap request dan menyimpannya di context.
+// Versi dibaca dari header (X-API-Version), lalu parameter media type di header Accept
+// (application/json; version=2), lalu Config.Default. Versi yang tidak didukung ditolak dengan 400.
+//
+// Response selalu berisi header versi yang dipakai dan Vary untuk header versi dan Accept.
+// Untuk versi deprecated, middleware menambahkan header Deprecation, Sunset, dan Link.
+// Response transformer per route didaftarkan dengan VersionTransform.
+//
+// Parameters:
+//   - config: APIVersionConfig berisi versi yang didukung dan kebijakan deprecation
+//
+// Returns:
+//   - MiddlewareFunc: middleware negosiasi versi
+//
+// Example:
+//
+//	router.Use(dim.APIVersionMiddleware(dim.APIVersionConfig{
+//	    Versions: []string{"1", "2"},
+//	    Deprecated: map[string]dim.APIVersionDeprecation{
+//	        "1": {Sunset: time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC), Link: "https://docs.example.com/migrate-v2"},
+//	    },
+//	}))
+func APIVersionMiddleware(config APIVersionConfig) MiddlewareFunc {
+	if len(config.Versions) == 0 {
+		panic("dim: APIVersionMiddleware requires at least one version")
+	}
+	if config.Default == "" {
+		config.Default = config.Versions[len(config.Versions)-1]
+	}
+	if config.Header == "" {
+		config.Header = DefaultAPIVersionHeader
+	}
+	if config.MediaTypeParam == "" {
+		config.MediaTypeParam = "version"
+	}
+
+	order := make(map[string]int, len(config.Versions))
+	for i, v := range config.Versions {
+		order[v] = i
+	}
+	if _, ok := order[config.Default]; !ok {
+		panic("dim: APIVersionMiddleware default version " + strconv.Quote(config.Default) + " is not in Versions")
+	}
+
+	// Header deprecation dihitung sekali saat startup
+	deprecations := make(map[string]map[string]string, len(config.Deprecated))
+	for version, dep := range config.Deprecated {
+		if _, ok := order[version]; !ok {
+			panic("dim: APIVersionMiddleware deprecated version " + strconv.Quote(version) + " is not in Versions")
+		}
+		headers := map[string]string{"Deprecation": "true"}
+		if !dep.Date.IsZero() {
+			headers["Deprecation"] = "@" + strconv.FormatInt(dep.Date.Unix(), 10)
+		}
+		if !dep.Sunset.IsZero() {
+			headers["Sunset"] = dep.Sunset.UTC().Format(http.TimeFormat)
+		}
+		if dep.Link != "" {
+			headers["Link"] = "<" + dep.Link + `>; rel="deprecation"`
+		}
+		deprecations[version] = headers
+	}
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", config.Header)
+			w.Header().Add("Vary", "Accept")
+
+			version := negotiateAPIVersion(r, config)
+			index, ok := order[version]
+			if !ok {
+				BadRequest(w, "Versi API tidak didukung", FieldErrors{
+					"supported_versions": config.Versions,
+				})
+				return
+			}
+
+			w.Header().Set(config.Header, version)
+			if headers, ok := deprecations[version]; ok {
+				for key, value := range headers {
+					w.Header().Add(key, value)
+				}
+				if config.OnDeprecated != nil {
+					config.OnDeprecated(r, version)
+				}
+			}
+
+			r = r.WithContext(context.WithValue(r.Context(), apiVersionKey, version))
+			next(&versionResponseWriter{ResponseWriter: w, index: index, order: order}, r)
+		}
+	}
+}
+
+// negotiateAPIVersion membaca versi yang diminta dari header, lalu parameter media type Accept.
+func negotiateAPIVersion(r *http.Request, config APIVersionConfig) string {
+	if version := strings.TrimSpace(r.Header.Get(config.Header)); version != "" {
+		return version
+	}
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		if _, params, err := mime.ParseMediaType(strings.TrimSpace(part)); err == nil {
+			if version := params[config.MediaTypeParam]; version != "" {
+				return version
+			}
+		}
+	}
+	return config.Default
+}
+
+// GetAPIVersion mengambil versi API request yang di-set oleh APIVersionMiddleware.
+// Mengembalikan string kosong jika middleware tidak dipasang.
+//
+// Example:
+//
+//	if dim.GetAPIVersion(r) == "1" {
+//	    // perilaku lama
+//	}
+func GetAPIVersion(r *http.Request) string {
+	version, _ := r.Context().Value(apiVersionKey).(string)
+	return version
+}
+
+// VersionTransform mendaftarkan response transformer untuk sebuah versi pada route.
+// Transformer untuk versi V mengubah response dari bentuk versi setelah V ke bentuk V.
+// Request dengan versi V menjalankan semua transformer untuk versi >= V, dari yang terbaru,
+// sehingga handler cukup menulis response dalam bentuk versi terbaru.
+//
+// Transformer hanya diterapkan pada data yang ditulis Json dan JsonPagination (bukan
+// error response), dan hanya jika APIVersionMiddleware terpasang. Versi yang tidak terdaftar
+// di APIVersionConfig.Versions diabaikan.
+//
+// Parameters:
+//   - version: versi tujuan transformer
+//   - transform: fungsi yang mengubah data ke bentuk versi tersebut
+//
+// Returns:
+//   - MiddlewareFunc: middleware untuk route atau group
+//
+// Example:
+//
+//	// v2 memecah "name" menjadi "first_name" dan "last_name"; client v1 tetap menerima "name"
+//	router.Get("/users/{id}", getUser, dim.VersionTransform("1", dim.TransformJSON(func(user map[string]interface{}) {
+//	    user["name"] = fmt.Sprint(user["first_name"], " ", user["last_name"])
+//	    delete(user, "first_name")
+//	    delete(user, "last_name")
+//	})))
+func VersionTransform(version string, transform ResponseTransformer) MiddlewareFunc {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if vw, ok := writerAPIVersion(w); ok {
+				if index, known := vw.order[version]; known && index >= vw.index {
+					vw.transforms = append(vw.transforms, versionTransform{index: index, fn: transform})
+				}
+			}
+			next(w, r)
+		}
+	}
+}
+
+// TransformJSON membuat ResponseTransformer yang bekerja pada bentuk JSON generik dari data:
+// data di-encode (mengikuti ResponseConfig global) dan setiap object di-decode menjadi map.
+// Jika data berupa array, fn dipanggil untuk setiap object di dalamnya, sehingga transformer
+// yang sama dapat dipakai untuk endpoint detail dan list. Angka dipertahankan sebagai json.Number.
+//
+// Parameters:
+//   - fn: fungsi yang memodifikasi object secara langsung
+//
+// Returns:
+//   - ResponseTransformer: transformer untuk VersionTransform
+//
+// Example:
+//
+//	dim.VersionTransform("1", dim.TransformJSON(func(obj map[string]interface{}) {
+//	    obj["id"] = fmt.Sprint(obj["id"]) // v1 mengirim id sebagai string
+//	}))
+func TransformJSON(fn func(obj map[string]interface{})) ResponseTransformer {
+	return func(data interface{}) (interface{}, error) {
+		var buf bytes.Buffer
+		if err := encodeJSON(&buf, data, GetResponseConfig()); err != nil {
+			return nil, err
+		}
+
+		dec := json.NewDecoder(&buf)
+		dec.UseNumber()
+		var generic interface{}
+		if err := dec.Decode(&generic); err != nil {
+			return nil, err
+		}
+
+		switch v := generic.(type) {
+		case map[string]interface{}:
+			fn(v)
+		case []interface{}:
+			for _, item := range v {
+				if obj, ok := item.(map[string]interface{}); ok {
+					fn(obj)
+				}
+			}
+		}
+		return generic, nil
+	}
+}
+
+type versionTransform struct {
+	index int
+	fn    ResponseTransformer
+}
+
+// versionResponseWriter membawa versi request dan transformer route agar Json dapat
+// menulis response dalam bentuk versi yang diminta.
+type versionResponseWriter struct {
+	http.ResponseWriter
+	index      int
+	order      map[string]int
+	transforms []versionTransform
+}
+
+// Unwrap mengembalikan ResponseWriter asli untuk http.ResponseController.
+func (w *versionResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Flush meneruskan flush ke ResponseWriter asli jika didukung (streaming/SSE).
+func (w *versionResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// writerAPIVersion mencari versionResponseWriter yang dipasang APIVersionMiddleware.
+func writerAPIVersion(w http.ResponseWriter) (*versionResponseWriter, bool) {
+	for w != nil {
+		if vw, ok := w.(*versionResponseWriter); ok {
+			return vw, true
+		}
+		u, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			return nil, false
+		}
+		w = u.Unwrap()
+	}
+	return nil, false
+}
+
+// transformVersionedResponse menjalankan transformer route untuk versi request,
+// dari versi terbaru ke versi yang diminta.
+func transformVersionedResponse(w http.ResponseWriter, data interface{}) (interface{}, error) {
+	vw, ok := writerAPIVersion(w)
+	if !ok || len(vw.transforms) == 0 {
+		return data, nil
+	}
+
+	transforms := make([]versionTransform, len(vw.transforms))
+	copy(transforms, vw.transforms)
+	sort.SliceStable(transforms, func(i, j int) bool { return transforms[i].index > transforms[j].index })
+
+	for _, t := range transforms {
+		var err error
+		if data, err = t.fn(data); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
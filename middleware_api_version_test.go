@@ -0,0 +1,258 @@
+package dim
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type versionedUser struct {
+	ID        int64  `json:"id"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Email     string `json:"email"`
+}
+
+// versionedRouter membuat router dengan tiga versi: v3 (bentuk handler), v2 menggabungkan nama,
+// dan v1 juga menghapus email.
+func versionedRouter(config APIVersionConfig) *Router {
+	router := NewRouter()
+	router.Use(APIVersionMiddleware(config))
+
+	toV2 := VersionTransform("2", TransformJSON(func(obj map[string]interface{}) {
+		obj["name"] = fmt.Sprint(obj["first_name"], " ", obj["last_name"])
+		delete(obj, "first_name")
+		delete(obj, "last_name")
+	}))
+	toV1 := VersionTransform("1", TransformJSON(func(obj map[string]interface{}) {
+		delete(obj, "email")
+	}))
+
+	user := versionedUser{ID: 1, FirstName: "John", LastName: "Doe", Email: "john@example.com"}
+	router.Get("/users/1", func(w http.ResponseWriter, r *http.Request) {
+		OK(w, user)
+	}, toV1, toV2)
+	router.Get("/users", func(w http.ResponseWriter, r *http.Request) {
+		JsonPagination(w, http.StatusOK, []versionedUser{user}, PaginationMeta{Page: 1, PerPage: 10, Total: 1, TotalPages: 1})
+	}, toV2, toV1)
+	router.Get("/version", func(w http.ResponseWriter, r *http.Request) {
+		OK(w, map[string]string{"version": GetAPIVersion(r)})
+	})
+	router.Get("/fail", func(w http.ResponseWriter, r *http.Request) {
+		NotFound(w, "User tidak ditemukan")
+	}, toV1)
+	return router
+}
+
+func serveVersioned(router *Router, path string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestAPIVersionMiddleware_Negotiation(t *testing.T) {
+	router := versionedRouter(APIVersionConfig{Versions: []string{"1", "2", "3"}, Default: "2"})
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    string
+	}{
+		{"default", nil, "2"},
+		{"header", map[string]string{"X-API-Version": "1"}, "1"},
+		{"media type", map[string]string{"Accept": "application/json; version=3"}, "3"},
+		{"media type in list", map[string]string{"Accept": "text/html, application/vnd.api+json; version=1"}, "1"},
+		{"header wins", map[string]string{"X-API-Version": "3", "Accept": "application/json; version=1"}, "3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serveVersioned(router, "/version", tt.headers)
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+			}
+			var body map[string]string
+			json.Unmarshal(w.Body.Bytes(), &body)
+			if body["version"] != tt.want {
+				t.Errorf("GetAPIVersion() = %q, want %q", body["version"], tt.want)
+			}
+			if got := w.Header().Get("X-API-Version"); got != tt.want {
+				t.Errorf("X-API-Version header = %q, want %q", got, tt.want)
+			}
+			if vary := strings.Join(w.Header().Values("Vary"), ","); !strings.Contains(vary, "X-API-Version") || !strings.Contains(vary, "Accept") {
+				t.Errorf("Vary = %q", vary)
+			}
+		})
+	}
+}
+
+func TestAPIVersionMiddleware_Unsupported(t *testing.T) {
+	router := versionedRouter(APIVersionConfig{Versions: []string{"1", "2", "3"}})
+
+	w := serveVersioned(router, "/version", map[string]string{"X-API-Version": "9"})
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", w.Code)
+	}
+	var body struct {
+		Message string              `json:"message"`
+		Errors  map[string][]string `json:"errors"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &body)
+	if body.Message != "Versi API tidak didukung" || strings.Join(body.Errors["supported_versions"], ",") != "1,2,3" {
+		t.Errorf("body = %s", w.Body.String())
+	}
+}
+
+func TestAPIVersionMiddleware_Deprecation(t *testing.T) {
+	deprecatedAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	sunset := time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)
+	var notified string
+
+	router := versionedRouter(APIVersionConfig{
+		Versions: []string{"1", "2", "3"},
+		Deprecated: map[string]APIVersionDeprecation{
+			"1": {Date: deprecatedAt, Sunset: sunset, Link: "https://docs.example.com/migrate"},
+			"2": {},
+		},
+		OnDeprecated: func(r *http.Request, version string) { notified = version },
+	})
+
+	w := serveVersioned(router, "/version", map[string]string{"X-API-Version": "1"})
+	if got := w.Header().Get("Deprecation"); got != fmt.Sprintf("@%d", deprecatedAt.Unix()) {
+		t.Errorf("Deprecation = %q", got)
+	}
+	if got := w.Header().Get("Sunset"); got != "Thu, 31 Dec 2026 00:00:00 GMT" {
+		t.Errorf("Sunset = %q", got)
+	}
+	if got := w.Header().Get("Link"); got != `<https://docs.example.com/migrate>; rel="deprecation"` {
+		t.Errorf("Link = %q", got)
+	}
+	if notified != "1" {
+		t.Errorf("OnDeprecated version = %q, want 1", notified)
+	}
+
+	w = serveVersioned(router, "/version", map[string]string{"X-API-Version": "2"})
+	if got := w.Header().Get("Deprecation"); got != "true" {
+		t.Errorf("Deprecation = %q, want true", got)
+	}
+	if w.Header().Get("Sunset") != "" || w.Header().Get("Link") != "" {
+		t.Errorf("unexpected Sunset/Link headers: %v", w.Header())
+	}
+
+	// Default (latest) version is not deprecated
+	w = serveVersioned(router, "/version", nil)
+	if w.Header().Get("Deprecation") != "" {
+		t.Errorf("latest version should not be deprecated, headers = %v", w.Header())
+	}
+}
+
+func TestVersionTransform(t *testing.T) {
+	router := versionedRouter(APIVersionConfig{Versions: []string{"1", "2", "3"}})
+
+	tests := []struct {
+		version string
+		want    string
+	}{
+		{"3", `{"id":1,"first_name":"John","last_name":"Doe","email":"john@example.com"}`},
+		{"2", `{"email":"john@example.com","id":1,"name":"John Doe"}`},
+		{"1", `{"id":1,"name":"John Doe"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run("v"+tt.version, func(t *testing.T) {
+			w := serveVersioned(router, "/users/1", map[string]string{"X-API-Version": tt.version})
+			if got := strings.TrimSpace(w.Body.String()); got != tt.want {
+				t.Errorf("body = %s, want %s", got, tt.want)
+			}
+
+			// List endpoint: transformer applied to each item, meta untouched
+			w = serveVersioned(router, "/users", map[string]string{"X-API-Version": tt.version})
+			var body struct {
+				Data []json.RawMessage `json:"data"`
+				Meta PaginationMeta    `json:"meta"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("invalid JSON: %v", err)
+			}
+			if len(body.Data) != 1 || string(body.Data[0]) != tt.want || body.Meta.Total != 1 {
+				t.Errorf("list body = %s", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestVersionTransform_ErrorResponseUntouched(t *testing.T) {
+	router := versionedRouter(APIVersionConfig{Versions: []string{"1", "2", "3"}})
+
+	w := serveVersioned(router, "/fail", map[string]string{"X-API-Version": "1"})
+	if w.Code != http.StatusNotFound || !strings.Contains(w.Body.String(), `"message":"User tidak ditemukan"`) {
+		t.Errorf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+}
+
+func TestVersionTransform_WithoutMiddleware(t *testing.T) {
+	handler := VersionTransform("1", func(data interface{}) (interface{}, error) {
+		t.Error("transformer should not run without APIVersionMiddleware")
+		return data, nil
+	})(func(w http.ResponseWriter, r *http.Request) {
+		OK(w, map[string]int{"id": 1})
+	})
+
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if strings.TrimSpace(w.Body.String()) != `{"id":1}` {
+		t.Errorf("body = %s", w.Body.String())
+	}
+}
+
+func TestVersionTransform_Error(t *testing.T) {
+	errTransform := errors.New("transform failed")
+	handler := APIVersionMiddleware(APIVersionConfig{Versions: []string{"1", "2"}})(
+		VersionTransform("1", func(data interface{}) (interface{}, error) {
+			return nil, errTransform
+		})(func(w http.ResponseWriter, r *http.Request) {
+			if err := OK(w, map[string]int{"id": 1}); !errors.Is(err, errTransform) {
+				t.Errorf("OK() error = %v, want transform error", err)
+			}
+		}),
+	)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-API-Version", "1")
+	handler(w, req)
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want 500", w.Code)
+	}
+}
+
+func TestAPIVersionMiddleware_InvalidConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		config APIVersionConfig
+	}{
+		{"no versions", APIVersionConfig{}},
+		{"unknown default", APIVersionConfig{Versions: []string{"1"}, Default: "2"}},
+		{"unknown deprecated", APIVersionConfig{Versions: []string{"1"}, Deprecated: map[string]APIVersionDeprecation{"0": {}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("expected panic")
+				}
+			}()
+			APIVersionMiddleware(tt.config)
+		})
+	}
+}
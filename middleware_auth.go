@@ -120,7 +120,9 @@ func AllowBearerToken() MiddlewareFunc {
 
 // RequireAuth adalah middleware yang aman dan direkomendasikan untuk mewajibkan dan memverifikasi token yang valid.
 // Middleware ini menggunakan TokenManager untuk memvalidasi token dan menempatkan info pengguna ke dalam konteks.
-// Juga dapat mengecek TokenBlocklist jika disediakan (opsional).
+// Juga dapat mengecek TokenBlocklist jika disediakan (opsional): token ditolak jika session (sid)
+// atau token itu sendiri (jti) sudah dicabut. Bungkus dengan NewCachedBlocklist untuk menghindari
+// lookup storage di setiap request.
 // Mengembalikan 401 Unauthorized jika token tidak ada, tidak valid, atau kedaluwarsa.
 //
 // Parameters:
@@ -156,7 +158,7 @@ func RequireAuth(tokenManager TokenManager, blocklist TokenBlocklist, opts ...Au
 			}
 
 			// Verify token
-			claims, err := tokenManager.VerifyToken(token)
+			claims, err := verifyAccessToken(r.Context(), tokenManager, token)
 			if err != nil {
 				// Log internal error jika logger tersedia
 				if cfg.logger != nil {
@@ -183,30 +185,30 @@ func RequireAuth(tokenManager TokenManager, blocklist TokenBlocklist, opts ...Au
 				userID = fmt.Sprintf("%v", v)
 			}
 
-			// Check Blocklist if provided
+			// Check Blocklist if provided: session (logout) dan jti (token dicabut satu per satu)
 			if blocklist != nil {
-				// Coba ambil sessionID ('sid') dari claims
-				if sid, ok := claims["sid"].(string); ok && sid != "" {
-					revoked, err := blocklist.IsRevoked(r.Context(), sid)
-					if err != nil {
-						// Log internal error jika logger tersedia
-						if cfg.logger != nil {
-							cfg.logger.Error("Failed to check token blocklist",
-								"error", err.Error(),
-								"session_id", sid,
-							)
-						}
-						w.Header().Set("Content-Type", "application/json")
-						w.WriteHeader(http.StatusInternalServerError)
-						JsonError(w, http.StatusInternalServerError, "Gagal memverifikasi status token", nil)
-						return
-					}
-					if revoked {
-						w.Header().Set("Content-Type", "application/json")
-						w.WriteHeader(http.StatusUnauthorized)
-						JsonError(w, http.StatusUnauthorized, "Sesi telah berakhir (Logged out)", nil)
-						return
+				sid, _ := claims["sid"].(string)
+				jti, _ := claims["jti"].(string)
+				revoked, err := anyRevoked(r.Context(), blocklist, sid, jti)
+				if err != nil {
+					// Log internal error jika logger tersedia
+					if cfg.logger != nil {
+						cfg.logger.Error("Failed to check token blocklist",
+							"error", err.Error(),
+							"session_id", sid,
+							"jti", jti,
+						)
 					}
+					w.Header().Set("Content-Type", "application/json")
+					w.WriteHeader(http.StatusInternalServerError)
+					JsonError(w, http.StatusInternalServerError, "Gagal memverifikasi status token", nil)
+					return
+				}
+				if revoked {
+					w.Header().Set("Content-Type", "application/json")
+					w.WriteHeader(http.StatusUnauthorized)
+					JsonError(w, http.StatusUnauthorized, "Sesi telah berakhir (Logged out)", nil)
+					return
 				}
 			}
 
@@ -253,7 +255,7 @@ func OptionalAuth(tokenManager TokenManager, opts ...AuthMiddlewareOption) Middl
 			token, ok := getConfiguredToken(r, cfg)
 			if ok {
 				// Try to verify token
-				if claims, err := tokenManager.VerifyToken(token); err == nil {
+				if claims, err := verifyAccessToken(r.Context(), tokenManager, token); err == nil {
 					// Token is valid, set user in context
 
 					// Extract UserID from claims
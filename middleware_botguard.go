@@ -0,0 +1,352 @@
+package dim
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Alasan penolakan yang diteruskan ke BotGuardConfig.OnBlocked.
+const (
+	BotBlockThrottled    = "throttled"
+	BotBlockHoneypot     = "honeypot"
+	BotBlockTooFast      = "too_fast"
+	BotBlockFormExpired  = "form_expired"
+	BotBlockInvalidToken = "invalid_form_token"
+	BotBlockCaptcha      = "captcha"
+)
+
+// ErrCaptchaFailed dikembalikan CaptchaVerifier jika token CAPTCHA ditolak provider.
+var ErrCaptchaFailed = errors.New("captcha verification failed")
+
+// CaptchaVerifier memverifikasi token CAPTCHA dari client ke provider (Turnstile, hCaptcha, dll).
+type CaptchaVerifier interface {
+	// Verify mengembalikan nil jika token valid, error yang membungkus ErrCaptchaFailed
+	// jika ditolak, atau error lain jika provider tidak dapat dihubungi.
+	Verify(ctx context.Context, token, remoteIP string) error
+}
+
+// SiteVerifyCaptcha adalah CaptchaVerifier untuk provider dengan API "siteverify"
+// (Cloudflare Turnstile, hCaptcha, Google reCAPTCHA): POST form secret/response/remoteip
+// dan response JSON {"success": bool, "error-codes": [...]}.
+type SiteVerifyCaptcha struct {
+	endpoint string
+	secret   string
+	client   *http.Client
+}
+
+// NewTurnstileVerifier membuat verifier Cloudflare Turnstile.
+// Widget Turnstile mengirim token di field "cf-turnstile-response".
+func NewTurnstileVerifier(secret string) *SiteVerifyCaptcha {
+	return NewSiteVerifyCaptcha("https://challenges.cloudflare.com/turnstile/v0/siteverify", secret)
+}
+
+// NewHCaptchaVerifier membuat verifier hCaptcha.
+// Widget hCaptcha mengirim token di field "h-captcha-response".
+func NewHCaptchaVerifier(secret string) *SiteVerifyCaptcha {
+	return NewSiteVerifyCaptcha("https://api.hcaptcha.com/siteverify", secret)
+}
+
+// NewSiteVerifyCaptcha membuat verifier untuk endpoint siteverify custom dengan timeout 5 detik.
+func NewSiteVerifyCaptcha(endpoint, secret string) *SiteVerifyCaptcha {
+	return &SiteVerifyCaptcha{
+		endpoint: endpoint,
+		secret:   secret,
+		client:   &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// WithHTTPClient mengganti HTTP client yang dipakai untuk memanggil provider.
+func (c *SiteVerifyCaptcha) WithHTTPClient(client *http.Client) *SiteVerifyCaptcha {
+	c.client = client
+	return c
+}
+
+// Verify mengirim token ke endpoint siteverify provider.
+func (c *SiteVerifyCaptcha) Verify(ctx context.Context, token, remoteIP string) error {
+	if token == "" {
+		return fmt.Errorf("%w: missing token", ErrCaptchaFailed)
+	}
+
+	form := url.Values{}
+	form.Set("secret", c.secret)
+	form.Set("response", token)
+	if remoteIP != "" {
+		form.Set("remoteip", remoteIP)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("captcha request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("captcha provider returned status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Success    bool     `json:"success"`
+		ErrorCodes []string `json:"error-codes"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&result); err != nil {
+		return fmt.Errorf("captcha response invalid: %w", err)
+	}
+	if !result.Success {
+		return fmt.Errorf("%w: %s", ErrCaptchaFailed, strings.Join(result.ErrorCodes, ","))
+	}
+	return nil
+}
+
+// BotGuardConfig mengatur proteksi anti-bot untuk form publik (registrasi, login, kontak).
+// Setiap pemeriksaan aktif hanya jika field konfigurasinya diisi.
+type BotGuardConfig struct {
+	// Name membedakan counter throttle antar endpoint, misal "register" atau "contact".
+	Name string
+
+	// Limit adalah jumlah submit maksimum per IP dalam Window. 0 menonaktifkan throttle.
+	Limit int
+	// Window adalah periode throttle (default: 1 jam).
+	Window time.Duration
+	// Store adalah backend counter. Jika nil, InMemoryRateLimitStore digunakan.
+	Store RateLimitStore
+
+	// HoneypotField adalah nama field tersembunyi yang harus kosong. Kosong menonaktifkan honeypot.
+	HoneypotField string
+
+	// Signer menandatangani timestamp form (lihat BotGuardToken). Nil menonaktifkan cek waktu submit.
+	Signer *PayloadSigner
+	// TimestampField adalah nama field token timestamp (default: "_form_ts").
+	TimestampField string
+	// MinSubmitTime adalah waktu minimum antara form ditampilkan dan di-submit (default: 3 detik).
+	MinSubmitTime time.Duration
+	// MaxFormAge adalah umur maksimum form sebelum dianggap kadaluarsa (default: 1 jam).
+	MaxFormAge time.Duration
+
+	// Captcha memverifikasi token CAPTCHA. Nil menonaktifkan CAPTCHA.
+	Captcha CaptchaVerifier
+	// CaptchaField adalah nama field token CAPTCHA (default: "captcha_token").
+	// Header X-Captcha-Token juga diterima untuk client JSON.
+	CaptchaField string
+	// CaptchaFailOpen mengizinkan request jika provider CAPTCHA tidak dapat dihubungi.
+	CaptchaFailOpen bool
+
+	// OnBlocked dipanggil setiap kali request ditolak, untuk logging atau metrics (opsional).
+	OnBlocked func(r *http.Request, reason string)
+}
+
+// BotGuardToken membuat token timestamp bertanda tangan untuk field tersembunyi form.
+// Sertakan di form yang dilindungi BotGuard dengan nama TimestampField.
+//
+// Example:
+//
+//	data := map[string]interface{}{"FormToken": dim.BotGuardToken(signer)}
+//	// <input type="hidden" name="_form_ts" value="{{.FormToken}}">
+func BotGuardToken(signer *PayloadSigner) string {
+	ts := strconv.FormatInt(signer.now().Unix(), 10)
+	return ts + "." + signer.Sign(ts, time.Time{})
+}
+
+// BotGuard membuat middleware anti-bot untuk endpoint form publik.
+// Pemeriksaan dijalankan berurutan: throttle per IP, honeypot, waktu submit minimum, lalu CAPTCHA.
+// Request dengan method aman (GET, HEAD, OPTIONS) selalu diteruskan.
+// Field dibaca dari form (urlencoded/multipart) maupun body JSON; body dikembalikan
+// sehingga handler tetap dapat memanggil Bind.
+//
+// Parameters:
+//   - config: BotGuardConfig berisi pemeriksaan yang diaktifkan
+//
+// Returns:
+//   - MiddlewareFunc: middleware untuk route form publik
+//
+// Example:
+//
+//	guard := dim.BotGuard(dim.BotGuardConfig{
+//	    Name:          "register",
+//	    Limit:         5,
+//	    Window:        time.Hour,
+//	    HoneypotField: "website",
+//	    Signer:        signer,
+//	    MinSubmitTime: 3 * time.Second,
+//	    Captcha:       dim.NewTurnstileVerifier(cfg.TurnstileSecret),
+//	    CaptchaField:  "cf-turnstile-response",
+//	})
+//	router.Post("/register", registerHandler, guard)
+func BotGuard(config BotGuardConfig) MiddlewareFunc {
+	if config.Window <= 0 {
+		config.Window = time.Hour
+	}
+	if config.Limit > 0 && config.Store == nil {
+		config.Store = NewInMemoryRateLimitStore(config.Window)
+	}
+	if config.TimestampField == "" {
+		config.TimestampField = "_form_ts"
+	}
+	if config.MinSubmitTime <= 0 {
+		config.MinSubmitTime = 3 * time.Second
+	}
+	if config.MaxFormAge <= 0 {
+		config.MaxFormAge = time.Hour
+	}
+	if config.CaptchaField == "" {
+		config.CaptchaField = "captcha_token"
+	}
+
+	needsFields := config.HoneypotField != "" || config.Signer != nil || config.Captcha != nil
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if IsSafeHttpMethod(r.Method) {
+				next(w, r)
+				return
+			}
+
+			blocked := func(reason string) {
+				if config.OnBlocked != nil {
+					config.OnBlocked(r, reason)
+				}
+			}
+			clientIP := GetClientIP(r)
+
+			if config.Limit > 0 {
+				key := fmt.Sprintf("botguard:%s:%s", config.Name, clientIP)
+				// Fail open seperti RateLimit: store error tidak boleh mematikan form
+				if allowed, err := config.Store.Allow(r.Context(), key, config.Limit, config.Window); err == nil && !allowed {
+					blocked(BotBlockThrottled)
+					TooManyRequests(w, int(config.Window.Seconds()))
+					return
+				}
+			}
+
+			if !needsFields {
+				next(w, r)
+				return
+			}
+
+			fields, err := readBotGuardFields(r)
+			if err != nil {
+				BadRequest(w, "Format form tidak valid", nil)
+				return
+			}
+
+			if config.HoneypotField != "" && fields(config.HoneypotField) != "" {
+				blocked(BotBlockHoneypot)
+				BadRequest(w, "Permintaan ditolak", nil)
+				return
+			}
+
+			if config.Signer != nil {
+				if reason := checkBotGuardToken(config, fields(config.TimestampField)); reason != "" {
+					blocked(reason)
+					if reason == BotBlockFormExpired {
+						BadRequest(w, "Formulir kadaluarsa, silakan muat ulang halaman", nil)
+					} else {
+						BadRequest(w, "Permintaan ditolak", nil)
+					}
+					return
+				}
+			}
+
+			if config.Captcha != nil {
+				token := r.Header.Get("X-Captcha-Token")
+				if token == "" {
+					token = fields(config.CaptchaField)
+				}
+				if err := config.Captcha.Verify(r.Context(), token, clientIP); err != nil {
+					if !errors.Is(err, ErrCaptchaFailed) {
+						if config.CaptchaFailOpen {
+							slog.Warn("captcha verification skipped", "error", err)
+							next(w, r)
+							return
+						}
+						slog.Error("captcha verification unavailable", "error", err)
+					}
+					blocked(BotBlockCaptcha)
+					BadRequest(w, "Verifikasi CAPTCHA gagal", nil)
+					return
+				}
+			}
+
+			next(w, r)
+		}
+	}
+}
+
+// checkBotGuardToken memvalidasi token BotGuardToken dan mengembalikan alasan penolakan, atau "" jika valid.
+func checkBotGuardToken(config BotGuardConfig, token string) string {
+	ts, signature, ok := strings.Cut(token, ".")
+	if !ok || config.Signer.Verify(ts, time.Time{}, signature) != nil {
+		return BotBlockInvalidToken
+	}
+	issued, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return BotBlockInvalidToken
+	}
+
+	elapsed := config.Signer.now().Sub(time.Unix(issued, 0))
+	if elapsed < config.MinSubmitTime {
+		return BotBlockTooFast
+	}
+	if elapsed > config.MaxFormAge {
+		return BotBlockFormExpired
+	}
+	return ""
+}
+
+// readBotGuardFields mengembalikan fungsi pembaca field dari form atau body JSON.
+// Body JSON dibaca penuh (maksimal DefaultBindMaxBytes) lalu dikembalikan ke r.Body.
+func readBotGuardFields(r *http.Request) (func(string) string, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
+	switch mediaType {
+	case "application/json":
+		data, err := io.ReadAll(io.LimitReader(r.Body, DefaultBindMaxBytes+1))
+		if err != nil {
+			return nil, err
+		}
+		r.Body = io.NopCloser(bytes.NewReader(data))
+
+		var values map[string]interface{}
+		if len(data) > 0 && json.Unmarshal(data, &values) != nil {
+			// Biarkan Bind di handler yang melaporkan JSON tidak valid
+			values = nil
+		}
+		return func(name string) string {
+			if s, ok := values[name].(string); ok {
+				return s
+			}
+			if v, ok := values[name]; ok && v != nil {
+				return fmt.Sprint(v)
+			}
+			return ""
+		}, nil
+
+	case "multipart/form-data":
+		if err := r.ParseMultipartForm(DefaultBindMaxBytes); err != nil {
+			return nil, err
+		}
+		return r.PostFormValue, nil
+
+	default:
+		if err := r.ParseForm(); err != nil {
+			return nil, err
+		}
+		return r.PostFormValue, nil
+	}
+}
@@ -0,0 +1,275 @@
+package dim
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func botGuardHandler(called *bool) HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		*called = true
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+func postForm(values url.Values) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/register", strings.NewReader(values.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.RemoteAddr = "203.0.113.7:1234"
+	return r
+}
+
+type captchaFunc func(ctx context.Context, token, remoteIP string) error
+
+func (f captchaFunc) Verify(ctx context.Context, token, remoteIP string) error {
+	return f(ctx, token, remoteIP)
+}
+
+func TestBotGuard_Honeypot(t *testing.T) {
+	var reasons []string
+	guard := BotGuard(BotGuardConfig{
+		HoneypotField: "website",
+		OnBlocked:     func(r *http.Request, reason string) { reasons = append(reasons, reason) },
+	})
+
+	called := false
+	w := httptest.NewRecorder()
+	guard(botGuardHandler(&called))(w, postForm(url.Values{"email": {"a@b.co"}, "website": {""}}))
+	if !called || w.Code != http.StatusOK {
+		t.Errorf("empty honeypot should pass, status = %d", w.Code)
+	}
+
+	called = false
+	w = httptest.NewRecorder()
+	guard(botGuardHandler(&called))(w, postForm(url.Values{"email": {"a@b.co"}, "website": {"http://spam"}}))
+	if called || w.Code != http.StatusBadRequest {
+		t.Errorf("filled honeypot should be blocked, status = %d", w.Code)
+	}
+	if len(reasons) != 1 || reasons[0] != BotBlockHoneypot {
+		t.Errorf("reasons = %v", reasons)
+	}
+
+	// Safe methods are never checked
+	called = false
+	guard(botGuardHandler(&called))(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/register?website=x", nil))
+	if !called {
+		t.Error("GET should pass through")
+	}
+}
+
+func TestBotGuard_JSONBodyIsRestored(t *testing.T) {
+	guard := BotGuard(BotGuardConfig{HoneypotField: "website"})
+
+	var body string
+	handler := guard(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+	})
+
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"email":"a@b.co"}`))
+	r.Header.Set("Content-Type", "application/json")
+	handler(httptest.NewRecorder(), r)
+	if body != `{"email":"a@b.co"}` {
+		t.Errorf("handler body = %q", body)
+	}
+
+	r = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"email":"a@b.co","website":"spam"}`))
+	r.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	handler(w, r)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("JSON honeypot status = %d", w.Code)
+	}
+}
+
+func TestBotGuard_MinSubmitTime(t *testing.T) {
+	signer := NewPayloadSigner("secret")
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	signer.now = func() time.Time { return now }
+
+	var reasons []string
+	guard := BotGuard(BotGuardConfig{
+		Signer:        signer,
+		MinSubmitTime: 3 * time.Second,
+		MaxFormAge:    time.Hour,
+		OnBlocked:     func(r *http.Request, reason string) { reasons = append(reasons, reason) },
+	})
+	token := BotGuardToken(signer)
+
+	tests := []struct {
+		name       string
+		token      string
+		elapsed    time.Duration
+		wantStatus int
+		wantReason string
+	}{
+		{"too fast", token, time.Second, http.StatusBadRequest, BotBlockTooFast},
+		{"human speed", token, 10 * time.Second, http.StatusOK, ""},
+		{"expired", token, 2 * time.Hour, http.StatusBadRequest, BotBlockFormExpired},
+		{"missing token", "", 10 * time.Second, http.StatusBadRequest, BotBlockInvalidToken},
+		{"forged timestamp", "1704110000.abc", 10 * time.Second, http.StatusBadRequest, BotBlockInvalidToken},
+	}
+
+	start := now
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reasons = nil
+			now = start.Add(tt.elapsed)
+
+			called := false
+			w := httptest.NewRecorder()
+			guard(botGuardHandler(&called))(w, postForm(url.Values{"_form_ts": {tt.token}}))
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if tt.wantReason != "" && (len(reasons) != 1 || reasons[0] != tt.wantReason) {
+				t.Errorf("reasons = %v, want %s", reasons, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestBotGuard_Captcha(t *testing.T) {
+	var gotToken, gotIP string
+	guard := BotGuard(BotGuardConfig{
+		Captcha: captchaFunc(func(ctx context.Context, token, remoteIP string) error {
+			gotToken, gotIP = token, remoteIP
+			if token != "valid" {
+				return ErrCaptchaFailed
+			}
+			return nil
+		}),
+		CaptchaField: "cf-turnstile-response",
+	})
+
+	called := false
+	w := httptest.NewRecorder()
+	guard(botGuardHandler(&called))(w, postForm(url.Values{"cf-turnstile-response": {"valid"}}))
+	if !called || gotToken != "valid" || gotIP != "203.0.113.7" {
+		t.Errorf("valid captcha: status = %d, token = %q, ip = %q", w.Code, gotToken, gotIP)
+	}
+
+	called = false
+	w = httptest.NewRecorder()
+	guard(botGuardHandler(&called))(w, postForm(url.Values{"cf-turnstile-response": {"bad"}}))
+	if called || w.Code != http.StatusBadRequest {
+		t.Errorf("invalid captcha: status = %d", w.Code)
+	}
+
+	// JSON clients can send the token as a header
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{}`))
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("X-Captcha-Token", "valid")
+	called = false
+	guard(botGuardHandler(&called))(httptest.NewRecorder(), r)
+	if !called {
+		t.Error("header token should be accepted")
+	}
+}
+
+func TestBotGuard_CaptchaUnavailable(t *testing.T) {
+	unavailable := captchaFunc(func(ctx context.Context, token, remoteIP string) error {
+		return errors.New("dial tcp: connection refused")
+	})
+
+	called := false
+	w := httptest.NewRecorder()
+	BotGuard(BotGuardConfig{Captcha: unavailable})(botGuardHandler(&called))(w, postForm(url.Values{"captcha_token": {"x"}}))
+	if called || w.Code != http.StatusBadRequest {
+		t.Errorf("fail closed: status = %d", w.Code)
+	}
+
+	called = false
+	BotGuard(BotGuardConfig{Captcha: unavailable, CaptchaFailOpen: true})(botGuardHandler(&called))(httptest.NewRecorder(), postForm(url.Values{"captcha_token": {"x"}}))
+	if !called {
+		t.Error("fail open should pass the request")
+	}
+}
+
+func TestBotGuard_Throttle(t *testing.T) {
+	guard := BotGuard(BotGuardConfig{Name: "register", Limit: 2, Window: time.Minute})
+
+	for i := 0; i < 2; i++ {
+		called := false
+		guard(botGuardHandler(&called))(httptest.NewRecorder(), postForm(nil))
+		if !called {
+			t.Fatalf("request %d should pass", i+1)
+		}
+	}
+
+	called := false
+	w := httptest.NewRecorder()
+	guard(botGuardHandler(&called))(w, postForm(nil))
+	if called || w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") != "60" {
+		t.Errorf("third request: status = %d, Retry-After = %q", w.Code, w.Header().Get("Retry-After"))
+	}
+
+	// Another IP has its own counter
+	r := postForm(nil)
+	r.RemoteAddr = "198.51.100.1:1234"
+	called = false
+	guard(botGuardHandler(&called))(httptest.NewRecorder(), r)
+	if !called {
+		t.Error("different IP should pass")
+	}
+}
+
+func TestSiteVerifyCaptcha(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.PostForm.Get("secret") != "site-secret" || r.PostForm.Get("remoteip") != "203.0.113.7" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.PostForm.Get("response") == "good" {
+			w.Write([]byte(`{"success":true}`))
+		} else {
+			w.Write([]byte(`{"success":false,"error-codes":["invalid-input-response"]}`))
+		}
+	}))
+	defer server.Close()
+
+	v := NewSiteVerifyCaptcha(server.URL, "site-secret")
+	ctx := context.Background()
+
+	if err := v.Verify(ctx, "good", "203.0.113.7"); err != nil {
+		t.Errorf("Verify(good) error = %v", err)
+	}
+	err := v.Verify(ctx, "bad", "203.0.113.7")
+	if !errors.Is(err, ErrCaptchaFailed) || !strings.Contains(err.Error(), "invalid-input-response") {
+		t.Errorf("Verify(bad) error = %v", err)
+	}
+	if err := v.Verify(ctx, "", "203.0.113.7"); !errors.Is(err, ErrCaptchaFailed) {
+		t.Errorf("Verify(empty) error = %v", err)
+	}
+
+	// Provider errors are not reported as a rejected token
+	err = NewSiteVerifyCaptcha(server.URL, "wrong").Verify(ctx, "good", "203.0.113.7")
+	if err == nil || errors.Is(err, ErrCaptchaFailed) {
+		t.Errorf("provider error = %v", err)
+	}
+}
+
+func TestValidateStruct_Honeypot(t *testing.T) {
+	type signup struct {
+		Email   string `form:"email" validate:"required,email"`
+		Website string `form:"website" validate:"honeypot"`
+	}
+
+	if errs := ValidateStruct(signup{Email: "a@b.co"}); errs != nil {
+		t.Errorf("empty honeypot errors = %v", errs)
+	}
+	errs := ValidateStruct(signup{Email: "a@b.co", Website: "spam"})
+	if _, ok := errs["website"]; !ok {
+		t.Errorf("filled honeypot errors = %v", errs)
+	}
+}
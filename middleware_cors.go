@@ -10,6 +10,7 @@ import (
 // Middleware ini set CORS headers untuk allow cross-origin requests dari specified origins.
 // Support preflight requests (OPTIONS method) dan credential requests.
 // Origin checking dilakukan dengan exact match atau wildcard (*).
+// Jika AllowedHeaders berisi "*", header dari Access-Control-Request-Headers dipantulkan.
 //
 // Parameters:
 //   - config: CORSConfig yang berisi allowed origins, methods, headers, credentials setting
@@ -44,7 +45,13 @@ func CORS(config CORSConfig) MiddlewareFunc {
 				}
 
 				w.Header().Set("Access-Control-Allow-Methods", strings.Join(config.AllowedMethods, ", "))
-				w.Header().Set("Access-Control-Allow-Headers", strings.Join(config.AllowedHeaders, ", "))
+				allowHeaders := strings.Join(config.AllowedHeaders, ", ")
+				// "*" tidak berlaku untuk request dengan credentials, jadi header yang diminta dipantulkan
+				if requested := r.Header.Get("Access-Control-Request-Headers"); requested != "" && containsHeader(config.AllowedHeaders, "*") {
+					allowHeaders = requested
+					w.Header().Add("Vary", "Access-Control-Request-Headers")
+				}
+				w.Header().Set("Access-Control-Allow-Headers", allowHeaders)
 
 				if len(config.ExposedHeaders) > 0 {
 					w.Header().Set("Access-Control-Expose-Headers", strings.Join(config.ExposedHeaders, ", "))
@@ -0,0 +1,227 @@
+package dim
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/netip"
+	"time"
+
+	"github.com/atfromhome/goreus/pkg/cache"
+)
+
+// IPReputationAction menentukan tindakan terhadap request dari IP yang listed.
+type IPReputationAction string
+
+const (
+	// IPActionBlock menolak request dengan 403.
+	IPActionBlock IPReputationAction = "block"
+	// IPActionTarpit menahan request selama TarpitDelay sebelum diteruskan,
+	// memperlambat serangan credential stuffing tanpa memberi sinyal penolakan.
+	IPActionTarpit IPReputationAction = "tarpit"
+	// IPActionFlag hanya menandai request; handler membaca hasilnya via GetIPReputation.
+	IPActionFlag IPReputationAction = "flag"
+)
+
+// Nama metric IP reputation.
+const (
+	// MetricIPReputationLookups (counter, label: result, cache) — pemeriksaan reputasi IP.
+	// result: clean, listed, error; cache: hit, miss.
+	MetricIPReputationLookups = "dim_ip_reputation_lookups_total"
+	// MetricIPReputationActions (counter, label: action) — tindakan terhadap IP yang listed.
+	MetricIPReputationActions = "dim_ip_reputation_actions_total"
+)
+
+const ipReputationKey contextKey = "ip_reputation"
+
+// IPReputationConfig mengatur middleware IPReputationGuard.
+type IPReputationConfig struct {
+	// Provider adalah sumber reputasi IP (wajib).
+	Provider IPReputationProvider
+	// Action adalah tindakan untuk IP yang listed (default: IPActionBlock).
+	Action IPReputationAction
+	// TarpitDelay adalah lama penahanan untuk IPActionTarpit (default: 5 detik).
+	TarpitDelay time.Duration
+
+	// CacheTTL adalah lama hasil lookup di-cache per IP (default: 10 menit). Negatif menonaktifkan cache.
+	// Error dari provider tidak pernah di-cache.
+	CacheTTL time.Duration
+	// CacheSize adalah jumlah IP maksimum di cache (default: 10000).
+	CacheSize int
+
+	// FailClosed menolak request jika provider error. Default fail open seperti RateLimit.
+	FailClosed bool
+	// Trusted adalah IP atau CIDR yang tidak pernah diperiksa, misal jaringan kantor atau health check.
+	Trusted []string
+
+	// Metrics mencatat lookup dan tindakan ke registry (opsional).
+	Metrics *MetricsRegistry
+	// OnListed dipanggil setiap kali request dari IP listed terdeteksi, untuk logging atau alert (opsional).
+	OnListed func(r *http.Request, ip string, rep IPReputation)
+}
+
+// IPReputationGuard membuat middleware yang memeriksa reputasi IP client sebelum request
+// diproses, untuk melindungi endpoint auth dari kampanye credential stuffing.
+// Hasil lookup di-cache per IP; hasilnya juga disimpan di context (lihat GetIPReputation).
+//
+// Parameters:
+//   - config: IPReputationConfig berisi provider dan tindakan
+//
+// Returns:
+//   - MiddlewareFunc: middleware untuk route yang dilindungi
+//
+// Example:
+//
+//	denylist, _ := dim.LoadStaticIPList("/etc/dim/denylist.txt")
+//	guard := dim.IPReputationGuard(dim.IPReputationConfig{
+//	    Provider: dim.IPReputationChain(denylist, dim.NewAbuseIPDBProvider(cfg.AbuseIPDBKey)),
+//	    Action:   dim.IPActionTarpit,
+//	    Metrics:  metrics,
+//	})
+//	router.Post("/auth/login", loginHandler, guard)
+func IPReputationGuard(config IPReputationConfig) MiddlewareFunc {
+	if config.Provider == nil {
+		panic("dim: IPReputationGuard requires a Provider")
+	}
+	if config.Action == "" {
+		config.Action = IPActionBlock
+	}
+	if config.TarpitDelay <= 0 {
+		config.TarpitDelay = 5 * time.Second
+	}
+	if config.CacheTTL == 0 {
+		config.CacheTTL = 10 * time.Minute
+	}
+	if config.CacheSize <= 0 {
+		config.CacheSize = 10000
+	}
+
+	trusted, err := parseIPPrefixes(config.Trusted)
+	if err != nil {
+		panic("dim: IPReputationGuard: " + err.Error())
+	}
+
+	var results cache.Cache[string, IPReputation]
+	if config.CacheTTL > 0 {
+		results = cache.NewInMemoryCache[string, IPReputation](config.CacheSize, config.CacheTTL)
+	}
+
+	var lookups, actions *Counter
+	if config.Metrics != nil {
+		lookups = config.Metrics.Counter(MetricIPReputationLookups, "IP reputation lookups by result and cache status.", "result", "cache")
+		actions = config.Metrics.Counter(MetricIPReputationActions, "Actions taken against requests from listed IPs.", "action")
+	}
+
+	lookup := func(ctx context.Context, ip string) (IPReputation, error) {
+		if results != nil {
+			if rep, ok := results.Get(ctx, ip); ok {
+				if lookups != nil {
+					lookups.Inc(ipReputationResult(rep, nil), "hit")
+				}
+				return rep, nil
+			}
+		}
+
+		rep, err := config.Provider.Lookup(ctx, ip)
+		if lookups != nil {
+			lookups.Inc(ipReputationResult(rep, err), "miss")
+		}
+		if err == nil && results != nil {
+			results.Set(ctx, ip, rep)
+		}
+		return rep, err
+	}
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			ip := GetClientIP(r)
+			if isTrustedIP(ip, trusted) {
+				next(w, r)
+				return
+			}
+
+			rep, err := lookup(r.Context(), ip)
+			if err != nil {
+				slog.Warn("IP reputation lookup failed", "ip", ip, "error", err)
+				if config.FailClosed {
+					ServiceUnavailable(w, 30)
+					return
+				}
+				next(w, r)
+				return
+			}
+
+			r = r.WithContext(context.WithValue(r.Context(), ipReputationKey, rep))
+			if !rep.Listed {
+				next(w, r)
+				return
+			}
+
+			if config.OnListed != nil {
+				config.OnListed(r, ip, rep)
+			}
+			if actions != nil {
+				actions.Inc(string(config.Action))
+			}
+
+			switch config.Action {
+			case IPActionTarpit:
+				timer := time.NewTimer(config.TarpitDelay)
+				defer timer.Stop()
+				select {
+				case <-timer.C:
+				case <-r.Context().Done():
+					return
+				}
+				next(w, r)
+			case IPActionFlag:
+				next(w, r)
+			default:
+				Forbidden(w, "Akses ditolak")
+			}
+		}
+	}
+}
+
+// GetIPReputation mengambil hasil pemeriksaan IPReputationGuard dari request context.
+// Returns false jika guard tidak dipasang, IP trusted, atau lookup gagal.
+//
+// Example:
+//
+//	if rep, ok := dim.GetIPReputation(r); ok && rep.Listed {
+//	    // Minta CAPTCHA atau verifikasi tambahan
+//	}
+func GetIPReputation(r *http.Request) (IPReputation, bool) {
+	rep, ok := r.Context().Value(ipReputationKey).(IPReputation)
+	return rep, ok
+}
+
+// ipReputationResult mengonversi hasil lookup menjadi nilai label result.
+func ipReputationResult(rep IPReputation, err error) string {
+	switch {
+	case err != nil:
+		return "error"
+	case rep.Listed:
+		return "listed"
+	default:
+		return "clean"
+	}
+}
+
+// isTrustedIP mengecek apakah IP termasuk dalam daftar trusted.
+func isTrustedIP(ip string, trusted []netip.Prefix) bool {
+	if len(trusted) == 0 {
+		return false
+	}
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, p := range trusted {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
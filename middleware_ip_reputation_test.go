@@ -0,0 +1,157 @@
+package dim
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func ipRequest(ip string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/auth/login", nil)
+	r.RemoteAddr = ip + ":4321"
+	return r
+}
+
+func TestIPReputationGuard_Block(t *testing.T) {
+	list, _ := NewStaticIPList("203.0.113.0/24")
+	var listed []string
+	guard := IPReputationGuard(IPReputationConfig{
+		Provider: list,
+		OnListed: func(r *http.Request, ip string, rep IPReputation) { listed = append(listed, ip) },
+	})
+
+	called := false
+	w := httptest.NewRecorder()
+	guard(botGuardHandler(&called))(w, ipRequest("203.0.113.7"))
+	if called || w.Code != http.StatusForbidden {
+		t.Errorf("listed IP: called = %v, status = %d", called, w.Code)
+	}
+	if len(listed) != 1 || listed[0] != "203.0.113.7" {
+		t.Errorf("OnListed = %v", listed)
+	}
+
+	called = false
+	guard(botGuardHandler(&called))(httptest.NewRecorder(), ipRequest("192.0.2.1"))
+	if !called {
+		t.Error("clean IP should pass")
+	}
+}
+
+func TestIPReputationGuard_FlagAndContext(t *testing.T) {
+	list, _ := NewStaticIPList("203.0.113.7")
+	guard := IPReputationGuard(IPReputationConfig{Provider: list, Action: IPActionFlag})
+
+	var got IPReputation
+	var ok bool
+	handler := guard(func(w http.ResponseWriter, r *http.Request) {
+		got, ok = GetIPReputation(r)
+	})
+
+	handler(httptest.NewRecorder(), ipRequest("203.0.113.7"))
+	if !ok || !got.Listed {
+		t.Errorf("flagged request reputation = %+v, %v", got, ok)
+	}
+	handler(httptest.NewRecorder(), ipRequest("192.0.2.1"))
+	if !ok || got.Listed {
+		t.Errorf("clean request reputation = %+v, %v", got, ok)
+	}
+
+	if _, ok := GetIPReputation(httptest.NewRequest(http.MethodGet, "/", nil)); ok {
+		t.Error("GetIPReputation without guard should return false")
+	}
+}
+
+func TestIPReputationGuard_Tarpit(t *testing.T) {
+	list, _ := NewStaticIPList("203.0.113.7")
+	guard := IPReputationGuard(IPReputationConfig{Provider: list, Action: IPActionTarpit, TarpitDelay: 50 * time.Millisecond})
+
+	called := false
+	start := time.Now()
+	guard(botGuardHandler(&called))(httptest.NewRecorder(), ipRequest("203.0.113.7"))
+	if !called || time.Since(start) < 50*time.Millisecond {
+		t.Errorf("tarpit: called = %v, elapsed = %v", called, time.Since(start))
+	}
+
+	// A cancelled request is dropped without reaching the handler
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	called = false
+	guard(botGuardHandler(&called))(httptest.NewRecorder(), ipRequest("203.0.113.7").WithContext(ctx))
+	if called {
+		t.Error("cancelled tarpit request should not reach handler")
+	}
+}
+
+func TestIPReputationGuard_CacheAndMetrics(t *testing.T) {
+	calls := 0
+	provider := IPReputationFunc(func(ctx context.Context, ip string) (IPReputation, error) {
+		calls++
+		return IPReputation{Listed: ip == "203.0.113.7"}, nil
+	})
+	metrics := NewMetricsRegistry()
+	guard := IPReputationGuard(IPReputationConfig{Provider: provider, Metrics: metrics})
+
+	for i := 0; i < 3; i++ {
+		called := false
+		guard(botGuardHandler(&called))(httptest.NewRecorder(), ipRequest("203.0.113.7"))
+	}
+	if calls != 1 {
+		t.Errorf("provider calls = %d, want 1 (cached)", calls)
+	}
+
+	var out strings.Builder
+	metrics.WriteTo(&out)
+	for _, want := range []string{
+		`dim_ip_reputation_lookups_total{result="listed",cache="miss"} 1`,
+		`dim_ip_reputation_lookups_total{result="listed",cache="hit"} 2`,
+		`dim_ip_reputation_actions_total{action="block"} 3`,
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("metrics missing %s\n%s", want, out.String())
+		}
+	}
+
+	// Cache disabled
+	calls = 0
+	uncached := IPReputationGuard(IPReputationConfig{Provider: provider, CacheTTL: -1})
+	for i := 0; i < 2; i++ {
+		called := false
+		uncached(botGuardHandler(&called))(httptest.NewRecorder(), ipRequest("192.0.2.1"))
+	}
+	if calls != 2 {
+		t.Errorf("provider calls without cache = %d, want 2", calls)
+	}
+}
+
+func TestIPReputationGuard_ProviderErrorAndTrusted(t *testing.T) {
+	calls := 0
+	failing := IPReputationFunc(func(ctx context.Context, ip string) (IPReputation, error) {
+		calls++
+		return IPReputation{}, errors.New("connection refused")
+	})
+
+	called := false
+	IPReputationGuard(IPReputationConfig{Provider: failing})(botGuardHandler(&called))(httptest.NewRecorder(), ipRequest("192.0.2.1"))
+	if !called {
+		t.Error("fail open should pass the request")
+	}
+
+	called = false
+	w := httptest.NewRecorder()
+	IPReputationGuard(IPReputationConfig{Provider: failing, FailClosed: true})(botGuardHandler(&called))(w, ipRequest("192.0.2.1"))
+	if called || w.Code != http.StatusServiceUnavailable {
+		t.Errorf("fail closed: status = %d", w.Code)
+	}
+
+	calls = 0
+	called = false
+	guard := IPReputationGuard(IPReputationConfig{Provider: failing, FailClosed: true, Trusted: []string{"10.0.0.0/8"}})
+	guard(botGuardHandler(&called))(httptest.NewRecorder(), ipRequest("10.1.2.3"))
+	if !called || calls != 0 {
+		t.Errorf("trusted IP: called = %v, provider calls = %d", called, calls)
+	}
+}
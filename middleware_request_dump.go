@@ -0,0 +1,672 @@
+package dim
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// DefaultDumpRedactHeaders adalah header yang selalu disamarkan oleh RequestDump.
+var DefaultDumpRedactHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"Set-Cookie",
+	"X-API-Key",
+	"X-CSRF-Token",
+}
+
+// ErrDebugRouteNotFound dikembalikan ketika pattern tidak terdaftar di DebugRegistry.
+var ErrDebugRouteNotFound = errors.New("debug route not found")
+
+const dumpRedacted = "*****"
+
+// RequestDump menangkap pasangan request/response lengkap untuk satu route selama periode
+// tertentu, untuk debugging masalah yang hanya terjadi di production. Nonaktif secara default;
+// aktifkan dengan Enable (atau lewat DebugHandler) dan ambil hasilnya sebagai HAR.
+// Thread-safe.
+type RequestDump struct {
+	mu       sync.Mutex
+	pattern  string
+	limit    int
+	maxBody  int64
+	redact   map[string]bool
+	until    time.Time
+	entries  []HAREntry
+	captured int
+	now      func() time.Time
+}
+
+// DumpRequests membuat RequestDump yang menyimpan hingga limit pasangan request/response
+// terbaru (entry lama dibuang). Body dibatasi 64 KB per arah dan header di
+// DefaultDumpRedactHeaders disamarkan.
+//
+// Parameters:
+//   - limit: jumlah maksimum entry yang disimpan (default 100 jika <= 0)
+//
+// Returns:
+//   - *RequestDump: dump nonaktif yang didaftarkan ke route dengan Debug
+//
+// Example:
+//
+//	router.Get("/orders/{id}", showOrder, dim.Debug("/orders/{id}", dim.DumpRequests(50)))
+func DumpRequests(limit int) *RequestDump {
+	if limit <= 0 {
+		limit = 100
+	}
+	d := &RequestDump{
+		limit:   limit,
+		maxBody: 64 << 10,
+		redact:  make(map[string]bool),
+		now:     time.Now,
+	}
+	d.WithRedactHeaders(DefaultDumpRedactHeaders...)
+	return d
+}
+
+// WithRedactHeaders menambahkan header yang nilainya disamarkan di request dan response.
+func (d *RequestDump) WithRedactHeaders(headers ...string) *RequestDump {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, h := range headers {
+		d.redact[http.CanonicalHeaderKey(h)] = true
+	}
+	return d
+}
+
+// WithMaxBodyBytes mengatur batas body yang disimpan per request dan per response.
+// Body yang lebih besar dipotong dan ditandai _truncated.
+func (d *RequestDump) WithMaxBodyBytes(n int64) *RequestDump {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.maxBody = n
+	return d
+}
+
+// Enable mengaktifkan capture selama duration. Entry yang sudah ada dipertahankan.
+func (d *RequestDump) Enable(duration time.Duration) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.until = d.now().Add(duration)
+}
+
+// Disable menghentikan capture. Entry yang sudah ada dipertahankan.
+func (d *RequestDump) Disable() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.until = time.Time{}
+}
+
+// Active mengembalikan true jika capture sedang berjalan.
+func (d *RequestDump) Active() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.activeLocked()
+}
+
+func (d *RequestDump) activeLocked() bool {
+	return !d.until.IsZero() && d.now().Before(d.until)
+}
+
+// Clear menghapus semua entry yang tersimpan.
+func (d *RequestDump) Clear() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.entries = nil
+}
+
+// Entries mengembalikan salinan entry yang tersimpan, dari yang terlama.
+func (d *RequestDump) Entries() []HAREntry {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	entries := make([]HAREntry, len(d.entries))
+	copy(entries, d.entries)
+	return entries
+}
+
+// HAR mengembalikan entry yang tersimpan dalam format HAR 1.2, yang dapat dibuka di
+// browser devtools atau dilampirkan ke bug report.
+func (d *RequestDump) HAR() HAR {
+	return HAR{Log: HARLog{
+		Version: "1.2",
+		Creator: HARCreator{Name: "dim", Version: "1"},
+		Entries: d.Entries(),
+	}}
+}
+
+// DumpStatus adalah ringkasan status RequestDump untuk endpoint admin.
+type DumpStatus struct {
+	Route     string     `json:"route"`
+	Active    bool       `json:"active"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Entries   int        `json:"entries"`
+	Captured  int        `json:"captured"`
+	Limit     int        `json:"limit"`
+}
+
+// Status mengembalikan ringkasan status capture.
+func (d *RequestDump) Status() DumpStatus {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	status := DumpStatus{
+		Route:    d.pattern,
+		Active:   d.activeLocked(),
+		Entries:  len(d.entries),
+		Captured: d.captured,
+		Limit:    d.limit,
+	}
+	if status.Active {
+		until := d.until
+		status.ExpiresAt = &until
+	}
+	return status
+}
+
+// capture membungkus handler: body request dibaca hingga batas dan diputar ulang untuk handler,
+// sedangkan response dicatat sambil diteruskan ke client.
+func (d *RequestDump) capture(next HandlerFunc) HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		d.mu.Lock()
+		active, maxBody := d.activeLocked(), d.maxBody
+		d.mu.Unlock()
+		if !active {
+			next(w, r)
+			return
+		}
+
+		started := time.Now()
+		var reqBody []byte
+		var reqBodySize int64
+		reqTruncated := false
+		if r.Body != nil && r.Body != http.NoBody {
+			buf, _ := io.ReadAll(io.LimitReader(r.Body, maxBody+1))
+			reqBodySize = int64(len(buf))
+			if int64(len(buf)) > maxBody {
+				reqTruncated = true
+				reqBody = buf[:maxBody]
+			} else {
+				reqBody = buf
+			}
+			r.Body = replayBody{Reader: io.MultiReader(bytes.NewReader(buf), r.Body), Closer: r.Body}
+		}
+
+		dw := &dumpResponseWriter{ResponseWriter: w, max: maxBody}
+		next(dw, r)
+
+		if !dw.wrote {
+			dw.status = http.StatusOK
+			dw.header = w.Header().Clone()
+		}
+		if reqTruncated {
+			// Ukuran asli tidak diketahui tanpa membaca sisa body; -1 sesuai konvensi HAR
+			reqBodySize = -1
+		}
+
+		entry := HAREntry{
+			StartedDateTime: started,
+			Time:            float64(time.Since(started).Microseconds()) / 1000,
+			Request: HARRequest{
+				Method:      r.Method,
+				URL:         dumpRequestURL(r),
+				HTTPVersion: r.Proto,
+				Cookies:     []HARCookie{},
+				Headers:     d.harHeaders(r.Header),
+				QueryString: harQueryString(r),
+				HeadersSize: -1,
+				BodySize:    reqBodySize,
+			},
+			Response: HARResponse{
+				Status:      dw.status,
+				StatusText:  http.StatusText(dw.status),
+				HTTPVersion: r.Proto,
+				Cookies:     []HARCookie{},
+				Headers:     d.harHeaders(dw.header),
+				Content: HARContent{
+					Size:      dw.size,
+					MimeType:  dw.header.Get("Content-Type"),
+					Truncated: dw.truncated,
+				},
+				RedirectURL: dw.header.Get("Location"),
+				HeadersSize: -1,
+				BodySize:    dw.size,
+			},
+		}
+		entry.Timings.Wait = entry.Time
+		if len(reqBody) > 0 || reqTruncated {
+			text, encoding := harBody(reqBody)
+			entry.Request.PostData = &HARPostData{
+				MimeType:  r.Header.Get("Content-Type"),
+				Text:      text,
+				Encoding:  encoding,
+				Truncated: reqTruncated,
+			}
+		}
+		entry.Response.Content.Text, entry.Response.Content.Encoding = harBody(dw.body.Bytes())
+
+		d.mu.Lock()
+		d.entries = append(d.entries, entry)
+		if len(d.entries) > d.limit {
+			d.entries = d.entries[len(d.entries)-d.limit:]
+		}
+		d.captured++
+		d.mu.Unlock()
+	}
+}
+
+// harHeaders mengubah header menjadi daftar HAR terurut dengan nilai sensitif disamarkan.
+func (d *RequestDump) harHeaders(header http.Header) []HARNameValue {
+	keys := make([]string, 0, len(header))
+	for k := range header {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	headers := make([]HARNameValue, 0, len(keys))
+	for _, k := range keys {
+		for _, v := range header[k] {
+			if d.redact[http.CanonicalHeaderKey(k)] {
+				v = dumpRedacted
+			}
+			headers = append(headers, HARNameValue{Name: k, Value: v})
+		}
+	}
+	return headers
+}
+
+// harBody mengembalikan body sebagai teks jika UTF-8 valid, atau base64 untuk data biner.
+func harBody(body []byte) (text, encoding string) {
+	if utf8.Valid(body) {
+		return string(body), ""
+	}
+	return base64.StdEncoding.EncodeToString(body), "base64"
+}
+
+func harQueryString(r *http.Request) []HARNameValue {
+	query := r.URL.Query()
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	params := make([]HARNameValue, 0, len(keys))
+	for _, k := range keys {
+		for _, v := range query[k] {
+			params = append(params, HARNameValue{Name: k, Value: v})
+		}
+	}
+	return params
+}
+
+func dumpRequestURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host + r.URL.RequestURI()
+}
+
+// replayBody memutar ulang bagian body yang sudah dibaca sebelum sisa body asli.
+type replayBody struct {
+	io.Reader
+	io.Closer
+}
+
+// dumpResponseWriter mencatat status, header, dan body response (hingga max byte)
+// sambil meneruskan semuanya ke ResponseWriter asli.
+type dumpResponseWriter struct {
+	http.ResponseWriter
+	max       int64
+	status    int
+	header    http.Header
+	body      bytes.Buffer
+	size      int64
+	truncated bool
+	wrote     bool
+}
+
+// WriteHeader menyimpan salinan header pada saat response dikirim.
+func (w *dumpResponseWriter) WriteHeader(statusCode int) {
+	if !w.wrote {
+		w.wrote = true
+		w.status = statusCode
+		w.header = w.ResponseWriter.Header().Clone()
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Write mencatat body hingga batas lalu meneruskannya ke client.
+func (w *dumpResponseWriter) Write(b []byte) (int, error) {
+	if !w.wrote {
+		w.WriteHeader(http.StatusOK)
+	}
+	w.size += int64(len(b))
+	if remaining := w.max - int64(w.body.Len()); remaining > 0 {
+		if int64(len(b)) > remaining {
+			w.body.Write(b[:remaining])
+			w.truncated = true
+		} else {
+			w.body.Write(b)
+		}
+	} else if len(b) > 0 {
+		w.truncated = true
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush meneruskan flush ke ResponseWriter asli jika didukung (streaming/SSE).
+func (w *dumpResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap mengembalikan ResponseWriter asli (dipakai http.ResponseController dan locale lookup).
+func (w *dumpResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// DebugRegistry menyimpan RequestDump per route pattern agar dapat dikendalikan saat runtime
+// melalui DebugHandler. Thread-safe.
+type DebugRegistry struct {
+	mu    sync.RWMutex
+	dumps map[string]*RequestDump
+}
+
+// NewDebugRegistry membuat registry debug kosong.
+func NewDebugRegistry() *DebugRegistry {
+	return &DebugRegistry{dumps: make(map[string]*RequestDump)}
+}
+
+// DefaultDebugRegistry adalah registry yang dipakai oleh Debug.
+var DefaultDebugRegistry = NewDebugRegistry()
+
+// Debug mendaftarkan dump untuk pattern dan mengembalikan middleware capture untuk route tersebut.
+// Pattern yang sama boleh dipakai untuk beberapa method dengan dump yang sama; mendaftarkan
+// dump berbeda untuk pattern yang sudah ada menyebabkan panic.
+//
+// Parameters:
+//   - pattern: nama route, biasanya path pattern route (misal "/orders/{id}")
+//   - dump: RequestDump dari DumpRequests
+//
+// Returns:
+//   - MiddlewareFunc: middleware capture (tanpa overhead berarti saat nonaktif)
+//
+// Example:
+//
+//	ordersDump := dim.DumpRequests(50)
+//	router.Get("/orders/{id}", showOrder, debug.Debug("/orders/{id}", ordersDump))
+//	router.Put("/orders/{id}", updateOrder, debug.Debug("/orders/{id}", ordersDump))
+func (reg *DebugRegistry) Debug(pattern string, dump *RequestDump) MiddlewareFunc {
+	reg.mu.Lock()
+	if existing, ok := reg.dumps[pattern]; ok && existing != dump {
+		reg.mu.Unlock()
+		panic(fmt.Sprintf("dim: debug route %q already registered with a different dump", pattern))
+	}
+	reg.dumps[pattern] = dump
+	reg.mu.Unlock()
+
+	dump.mu.Lock()
+	dump.pattern = pattern
+	dump.mu.Unlock()
+
+	return dump.capture
+}
+
+// Lookup mengembalikan dump untuk pattern.
+func (reg *DebugRegistry) Lookup(pattern string) (*RequestDump, error) {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+	dump, ok := reg.dumps[pattern]
+	if !ok {
+		return nil, ErrDebugRouteNotFound
+	}
+	return dump, nil
+}
+
+// Statuses mengembalikan status semua dump, terurut berdasarkan pattern.
+func (reg *DebugRegistry) Statuses() []DumpStatus {
+	reg.mu.RLock()
+	dumps := make([]*RequestDump, 0, len(reg.dumps))
+	for _, dump := range reg.dumps {
+		dumps = append(dumps, dump)
+	}
+	reg.mu.RUnlock()
+
+	statuses := make([]DumpStatus, len(dumps))
+	for i, dump := range dumps {
+		statuses[i] = dump.Status()
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Route < statuses[j].Route })
+	return statuses
+}
+
+// Debug mendaftarkan dump ke DefaultDebugRegistry. Lihat DebugRegistry.Debug.
+//
+// Example:
+//
+//	router.Get("/orders/{id}", showOrder, dim.Debug("/orders/{id}", dim.DumpRequests(50)))
+func Debug(pattern string, dump *RequestDump) MiddlewareFunc {
+	return DefaultDebugRegistry.Debug(pattern, dump)
+}
+
+// DebugHandler menyediakan endpoint admin untuk mengendalikan RequestDump saat runtime.
+// Endpoint ini mengekspos isi request/response; selalu pasang di belakang autentikasi admin.
+type DebugHandler struct {
+	registry   *DebugRegistry
+	maxMinutes int
+}
+
+// NewDebugHandler membuat handler admin untuk registry (nil berarti DefaultDebugRegistry).
+// Capture dibatasi maksimal 60 menit per aktivasi.
+//
+// Example:
+//
+//	admin := router.Group("/admin/debug", dim.RequireAuth(jwtManager, blocklist), requireAdmin)
+//	dim.NewDebugHandler(nil).Register(admin)
+func NewDebugHandler(registry *DebugRegistry) *DebugHandler {
+	if registry == nil {
+		registry = DefaultDebugRegistry
+	}
+	return &DebugHandler{registry: registry, maxMinutes: 60}
+}
+
+// WithMaxMinutes mengubah durasi maksimum satu aktivasi capture.
+func (h *DebugHandler) WithMaxMinutes(minutes int) *DebugHandler {
+	h.maxMinutes = minutes
+	return h
+}
+
+// Register mendaftarkan endpoint debug ke dalam group.
+//
+// Endpoint:
+//   - GET / : status semua route yang dapat di-debug
+//   - POST /enable : aktifkan capture, body {"route": "/orders/{id}", "minutes": 10}
+//   - POST /disable : hentikan capture, body {"route": "/orders/{id}"}
+//   - GET /dump?route=/orders/{id} : hasil capture sebagai HAR JSON
+//   - DELETE /dump?route=/orders/{id} : hapus hasil capture
+func (h *DebugHandler) Register(rg *RouterGroup) {
+	rg.Get("/", h.list)
+	rg.Post("/enable", h.enable)
+	rg.Post("/disable", h.disable)
+	rg.Get("/dump", h.dump)
+	rg.Delete("/dump", h.clear)
+}
+
+// debugRouteRequest adalah body POST enable/disable.
+type debugRouteRequest struct {
+	Route   string `json:"route"`
+	Minutes int    `json:"minutes"`
+}
+
+func (h *DebugHandler) list(w http.ResponseWriter, r *http.Request) {
+	OK(w, h.registry.Statuses())
+}
+
+func (h *DebugHandler) enable(w http.ResponseWriter, r *http.Request) {
+	req, dump, ok := h.decode(w, r)
+	if !ok {
+		return
+	}
+	if req.Minutes == 0 {
+		req.Minutes = 10
+	}
+	if req.Minutes < 1 || req.Minutes > h.maxMinutes {
+		BadRequest(w, "Validasi gagal", FieldErrors{
+			"minutes": translateError(GetLocale(r), localizedErrorf("%s harus antara %d dan %d", "minutes", 1, h.maxMinutes)),
+		})
+		return
+	}
+	dump.Enable(time.Duration(req.Minutes) * time.Minute)
+	OK(w, dump.Status())
+}
+
+func (h *DebugHandler) disable(w http.ResponseWriter, r *http.Request) {
+	_, dump, ok := h.decode(w, r)
+	if !ok {
+		return
+	}
+	dump.Disable()
+	OK(w, dump.Status())
+}
+
+func (h *DebugHandler) dump(w http.ResponseWriter, r *http.Request) {
+	dump, ok := h.lookup(w, r, r.URL.Query().Get("route"))
+	if !ok {
+		return
+	}
+	OK(w, dump.HAR())
+}
+
+func (h *DebugHandler) clear(w http.ResponseWriter, r *http.Request) {
+	dump, ok := h.lookup(w, r, r.URL.Query().Get("route"))
+	if !ok {
+		return
+	}
+	dump.Clear()
+	NoContent(w)
+}
+
+func (h *DebugHandler) decode(w http.ResponseWriter, r *http.Request) (*debugRouteRequest, *RequestDump, bool) {
+	var req debugRouteRequest
+	if err := BindJSON(r, &req); err != nil {
+		appErr, _ := AsAppError(err)
+		JsonAppError(w, appErr)
+		return nil, nil, false
+	}
+	dump, ok := h.lookup(w, r, req.Route)
+	return &req, dump, ok
+}
+
+func (h *DebugHandler) lookup(w http.ResponseWriter, r *http.Request, route string) (*RequestDump, bool) {
+	if strings.TrimSpace(route) == "" {
+		BadRequest(w, "Validasi gagal", FieldErrors{"route": translateError(GetLocale(r), localizedErrorf("%s wajib diisi", "route"))})
+		return nil, false
+	}
+	dump, err := h.registry.Lookup(route)
+	if err != nil {
+		NotFound(w, "Route debug tidak ditemukan")
+		return nil, false
+	}
+	return dump, true
+}
+
+// HAR adalah dokumen HTTP Archive 1.2 (http://www.softwareishard.com/blog/har-12-spec/).
+type HAR struct {
+	Log HARLog `json:"log"`
+}
+
+// HARLog adalah root log HAR.
+type HARLog struct {
+	Version string     `json:"version"`
+	Creator HARCreator `json:"creator"`
+	Entries []HAREntry `json:"entries"`
+}
+
+// HARCreator mengidentifikasi aplikasi pembuat HAR.
+type HARCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// HAREntry adalah satu pasangan request/response.
+type HAREntry struct {
+	StartedDateTime time.Time   `json:"startedDateTime"`
+	Time            float64     `json:"time"` // milidetik
+	Request         HARRequest  `json:"request"`
+	Response        HARResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         HARTimings  `json:"timings"`
+}
+
+// HARRequest adalah request yang ditangkap.
+type HARRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []HARCookie    `json:"cookies"`
+	Headers     []HARNameValue `json:"headers"`
+	QueryString []HARNameValue `json:"queryString"`
+	PostData    *HARPostData   `json:"postData,omitempty"`
+	HeadersSize int64          `json:"headersSize"`
+	BodySize    int64          `json:"bodySize"`
+}
+
+// HARResponse adalah response yang ditangkap.
+type HARResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []HARCookie    `json:"cookies"`
+	Headers     []HARNameValue `json:"headers"`
+	Content     HARContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int64          `json:"headersSize"`
+	BodySize    int64          `json:"bodySize"`
+}
+
+// HARNameValue adalah pasangan nama/nilai untuk header dan query string.
+type HARNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// HARCookie adalah cookie HAR. Cookie tidak diurai karena header Cookie dan Set-Cookie
+// disamarkan secara default.
+type HARCookie struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// HARPostData adalah body request. Body biner di-encode base64 dengan _encoding "base64".
+type HARPostData struct {
+	MimeType  string `json:"mimeType"`
+	Text      string `json:"text"`
+	Encoding  string `json:"_encoding,omitempty"`
+	Truncated bool   `json:"_truncated,omitempty"`
+}
+
+// HARContent adalah body response. Body biner di-encode base64 dengan encoding "base64".
+type HARContent struct {
+	Size      int64  `json:"size"`
+	MimeType  string `json:"mimeType"`
+	Text      string `json:"text"`
+	Encoding  string `json:"encoding,omitempty"`
+	Truncated bool   `json:"_truncated,omitempty"`
+}
+
+// HARTimings berisi durasi fase request dalam milidetik. Seluruh durasi handler dicatat sebagai wait.
+type HARTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
@@ -0,0 +1,273 @@
+package dim
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newDumpRouter(reg *DebugRegistry, dump *RequestDump) *Router {
+	router := NewRouter()
+	capture := reg.Debug("/orders/{id}", dump)
+	router.Put("/orders/{id}", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Set-Cookie", "session=secret")
+		w.Header().Set("X-Order-ID", GetParam(r, "id"))
+		if bytes.HasPrefix(body, []byte{0xff}) {
+			w.Header().Set("Content-Type", "application/octet-stream")
+			w.WriteHeader(http.StatusAccepted)
+			w.Write([]byte{0x00, 0xfe, 0xff})
+			return
+		}
+		Json(w, http.StatusOK, map[string]interface{}{"id": GetParam(r, "id"), "received": len(body)})
+	}, capture)
+	router.Get("/orders/{id}", func(w http.ResponseWriter, r *http.Request) {
+		OK(w, map[string]string{"id": GetParam(r, "id")})
+	}, capture)
+
+	NewDebugHandler(reg).Register(router.Group("/admin/debug"))
+	return router
+}
+
+func TestRequestDump_InactiveByDefault(t *testing.T) {
+	dump := DumpRequests(10)
+	router := newDumpRouter(NewDebugRegistry(), dump)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/orders/1", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d", w.Code)
+	}
+	if len(dump.Entries()) != 0 {
+		t.Errorf("inactive dump captured %d entries", len(dump.Entries()))
+	}
+}
+
+func TestRequestDump_Capture(t *testing.T) {
+	dump := DumpRequests(10).WithRedactHeaders("X-Tenant-Secret")
+	router := newDumpRouter(NewDebugRegistry(), dump)
+	dump.Enable(time.Minute)
+
+	req := httptest.NewRequest(http.MethodPut, "/orders/42?expand=items&expand=customer", strings.NewReader(`{"qty":3}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer token")
+	req.Header.Set("X-Tenant-Secret", "s3cr3t")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Handler still receives the full body
+	if !strings.Contains(w.Body.String(), `"received":9`) {
+		t.Fatalf("handler body = %s", w.Body.String())
+	}
+
+	entries := dump.Entries()
+	if len(entries) != 1 {
+		t.Fatalf("entries = %d, want 1", len(entries))
+	}
+	e := entries[0]
+
+	if e.Request.Method != http.MethodPut || e.Request.URL != "http://example.com/orders/42?expand=items&expand=customer" {
+		t.Errorf("request = %s %s", e.Request.Method, e.Request.URL)
+	}
+	if e.Request.PostData == nil || e.Request.PostData.Text != `{"qty":3}` || e.Request.PostData.MimeType != "application/json" {
+		t.Errorf("postData = %+v", e.Request.PostData)
+	}
+	if len(e.Request.QueryString) != 2 || e.Request.QueryString[0].Value != "items" {
+		t.Errorf("queryString = %+v", e.Request.QueryString)
+	}
+	assertHARHeader(t, e.Request.Headers, "Authorization", "*****")
+	assertHARHeader(t, e.Request.Headers, "X-Tenant-Secret", "*****")
+	assertHARHeader(t, e.Request.Headers, "Content-Type", "application/json")
+
+	if e.Response.Status != http.StatusOK || e.Response.StatusText != "OK" {
+		t.Errorf("response status = %d %q", e.Response.Status, e.Response.StatusText)
+	}
+	if strings.TrimSpace(e.Response.Content.Text) != `{"id":"42","received":9}` || e.Response.Content.Encoding != "" {
+		t.Errorf("response content = %+v", e.Response.Content)
+	}
+	assertHARHeader(t, e.Response.Headers, "Set-Cookie", "*****")
+	assertHARHeader(t, e.Response.Headers, "X-Order-Id", "42")
+}
+
+func TestRequestDump_BinaryAndTruncated(t *testing.T) {
+	dump := DumpRequests(10).WithMaxBodyBytes(4)
+	router := newDumpRouter(NewDebugRegistry(), dump)
+	dump.Enable(time.Minute)
+
+	payload := []byte{0xff, 0x01, 0x02, 0x03, 0x04, 0x05}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/orders/7", bytes.NewReader(payload)))
+	if w.Code != http.StatusAccepted || !bytes.Equal(w.Body.Bytes(), []byte{0x00, 0xfe, 0xff}) {
+		t.Fatalf("status = %d, body = %v", w.Code, w.Body.Bytes())
+	}
+
+	e := dump.Entries()[0]
+	post := e.Request.PostData
+	if post == nil || !post.Truncated || post.Encoding != "base64" || e.Request.BodySize != -1 {
+		t.Fatalf("postData = %+v, bodySize = %d", post, e.Request.BodySize)
+	}
+	if decoded, _ := base64.StdEncoding.DecodeString(post.Text); !bytes.Equal(decoded, payload[:4]) {
+		t.Errorf("decoded request body = %v", decoded)
+	}
+
+	content := e.Response.Content
+	if content.Encoding != "base64" || content.Truncated || content.Size != 3 || e.Response.Status != http.StatusAccepted {
+		t.Errorf("content = %+v", content)
+	}
+	if decoded, _ := base64.StdEncoding.DecodeString(content.Text); !bytes.Equal(decoded, []byte{0x00, 0xfe, 0xff}) {
+		t.Errorf("decoded response body = %v", decoded)
+	}
+}
+
+func TestRequestDump_LimitAndExpiry(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	dump := DumpRequests(2)
+	dump.now = func() time.Time { return now }
+	router := newDumpRouter(NewDebugRegistry(), dump)
+	dump.Enable(5 * time.Minute)
+
+	for _, id := range []string{"1", "2", "3"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/orders/"+id, nil))
+	}
+	entries := dump.Entries()
+	if len(entries) != 2 || !strings.HasSuffix(entries[0].Request.URL, "/orders/2") || !strings.HasSuffix(entries[1].Request.URL, "/orders/3") {
+		t.Fatalf("entries should keep the latest 2, got %d", len(entries))
+	}
+	if status := dump.Status(); status.Captured != 3 || status.Entries != 2 || !status.Active {
+		t.Errorf("status = %+v", status)
+	}
+
+	now = now.Add(6 * time.Minute)
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/orders/4", nil))
+	if dump.Active() || len(dump.Entries()) != 2 {
+		t.Errorf("expired dump should stop capturing, active = %v, entries = %d", dump.Active(), len(dump.Entries()))
+	}
+}
+
+func TestDebugRegistry_DuplicatePattern(t *testing.T) {
+	reg := NewDebugRegistry()
+	dump := DumpRequests(1)
+	reg.Debug("/orders/{id}", dump)
+	reg.Debug("/orders/{id}", dump) // same dump shared between methods is fine
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for a different dump on the same pattern")
+		}
+	}()
+	reg.Debug("/orders/{id}", DumpRequests(1))
+}
+
+func TestDebugHandler(t *testing.T) {
+	reg := NewDebugRegistry()
+	dump := DumpRequests(10)
+	router := newDumpRouter(reg, dump)
+
+	call := func(method, target, body string) *httptest.ResponseRecorder {
+		var r io.Reader
+		if body != "" {
+			r = strings.NewReader(body)
+		}
+		req := httptest.NewRequest(method, target, r)
+		if body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := call(http.MethodPost, "/admin/debug/enable", `{"route":"/orders/{id}","minutes":5}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("enable status = %d, body = %s", w.Code, w.Body.String())
+	}
+	var status DumpStatus
+	json.Unmarshal(w.Body.Bytes(), &status)
+	if !status.Active || status.Route != "/orders/{id}" || status.ExpiresAt == nil {
+		t.Errorf("enable status = %+v", status)
+	}
+
+	call(http.MethodGet, "/orders/9", "")
+
+	w = call(http.MethodGet, "/admin/debug", "")
+	var statuses []DumpStatus
+	json.Unmarshal(w.Body.Bytes(), &statuses)
+	if len(statuses) != 1 || statuses[0].Entries != 1 {
+		t.Errorf("list = %s", w.Body.String())
+	}
+
+	w = call(http.MethodGet, "/admin/debug/dump?route=/orders/{id}", "")
+	var har HAR
+	if err := json.Unmarshal(w.Body.Bytes(), &har); err != nil {
+		t.Fatalf("invalid HAR: %v", err)
+	}
+	if har.Log.Version != "1.2" || len(har.Log.Entries) != 1 || har.Log.Entries[0].Response.Status != http.StatusOK {
+		t.Errorf("HAR = %s", w.Body.String())
+	}
+
+	w = call(http.MethodPost, "/admin/debug/disable", `{"route":"/orders/{id}"}`)
+	if w.Code != http.StatusOK || dump.Active() {
+		t.Errorf("disable status = %d, active = %v", w.Code, dump.Active())
+	}
+
+	w = call(http.MethodDelete, "/admin/debug/dump?route=/orders/{id}", "")
+	if w.Code != http.StatusNoContent || len(dump.Entries()) != 0 {
+		t.Errorf("clear status = %d, entries = %d", w.Code, len(dump.Entries()))
+	}
+}
+
+func TestDebugHandler_Errors(t *testing.T) {
+	router := newDumpRouter(NewDebugRegistry(), DumpRequests(10))
+
+	tests := []struct {
+		name      string
+		method    string
+		target    string
+		body      string
+		wantCode  int
+		wantField string
+	}{
+		{"missing route", http.MethodPost, "/admin/debug/enable", `{"minutes":5}`, http.StatusBadRequest, "route"},
+		{"unknown route", http.MethodGet, "/admin/debug/dump?route=/nope", "", http.StatusNotFound, ""},
+		{"too long", http.MethodPost, "/admin/debug/enable", `{"route":"/orders/{id}","minutes":120}`, http.StatusBadRequest, "minutes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body io.Reader
+			if tt.body != "" {
+				body = strings.NewReader(tt.body)
+			}
+			req := httptest.NewRequest(tt.method, tt.target, body)
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d, body = %s", w.Code, tt.wantCode, w.Body.String())
+			}
+			if tt.wantField != "" && !strings.Contains(w.Body.String(), `"`+tt.wantField+`"`) {
+				t.Errorf("body = %s, want error for %s", w.Body.String(), tt.wantField)
+			}
+		})
+	}
+}
+
+func assertHARHeader(t *testing.T, headers []HARNameValue, name, want string) {
+	t.Helper()
+	for _, h := range headers {
+		if h.Name == name {
+			if h.Value != want {
+				t.Errorf("header %s = %q, want %q", name, h.Value, want)
+			}
+			return
+		}
+	}
+	t.Errorf("header %s not found in %+v", name, headers)
+}
@@ -0,0 +1,239 @@
+package dim
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+// ErrResponseTooLarge dikembalikan oleh Write ketika response melewati batas ResponseSizeConfig.MaxBytes.
+// Encoder yang menerima error ini berhenti menulis, sehingga sisa payload tidak diproses.
+var ErrResponseTooLarge = errors.New("response exceeds the maximum size")
+
+// Nama metric ukuran response.
+const (
+	// MetricResponseSize (histogram, label: route) — ukuran body response dalam byte.
+	MetricResponseSize = "dim_http_response_size_bytes"
+	// MetricResponseSizeExceeded (counter, label: route, action) — response di atas threshold.
+	// action: warn (melewati WarnBytes), reject (melewati MaxBytes).
+	MetricResponseSizeExceeded = "dim_http_response_size_exceeded_total"
+)
+
+// ResponseSizeConfig mengatur middleware ResponseSizeGuard.
+type ResponseSizeConfig struct {
+	// Name adalah label route untuk log dan metric, misal "users.list" (default: "default").
+	// Gunakan nama tetap, bukan path, agar cardinality metric terkendali.
+	Name string
+
+	// WarnBytes adalah ukuran response yang memicu warning log dan metric (default: 5 MB).
+	// Negatif menonaktifkan warning.
+	WarnBytes int64
+
+	// MaxBytes adalah ukuran maksimum response. Response yang lebih besar diganti dengan 500
+	// berisi diagnosa. 0 menonaktifkan penolakan (response hanya diukur).
+	// Jika aktif, body di-buffer hingga MaxBytes sebelum dikirim ke client.
+	MaxBytes int64
+
+	// Metrics mencatat ukuran response ke registry (opsional).
+	Metrics *MetricsRegistry
+
+	// OnExceeded dipanggil ketika response melewati WarnBytes atau MaxBytes (opsional).
+	// rejected bernilai true jika response diganti dengan 500.
+	OnExceeded func(r *http.Request, size int64, rejected bool)
+}
+
+// ResponseSizeGuard membuat middleware yang mengukur ukuran body response, mencatat warning
+// jika melewati WarnBytes, dan (opsional) menolak response patologis yang melewati MaxBytes
+// dengan 500 sebelum dikirim, agar endpoint list yang tidak terbatas tidak menghabiskan memory.
+//
+// Response yang di-stream (handler memanggil Flush) tidak dapat ditolak setelah flush;
+// ukurannya tetap diukur dan dilaporkan.
+//
+// Parameters:
+//   - config: ResponseSizeConfig berisi threshold
+//
+// Returns:
+//   - MiddlewareFunc: middleware untuk router atau route tertentu
+//
+// Example:
+//
+//	router.Use(dim.ResponseSizeGuard(dim.ResponseSizeConfig{
+//	    WarnBytes: 5 << 20,  // 5 MB
+//	    MaxBytes:  50 << 20, // 50 MB
+//	    Metrics:   metrics,
+//	}))
+func ResponseSizeGuard(config ResponseSizeConfig) MiddlewareFunc {
+	if config.Name == "" {
+		config.Name = "default"
+	}
+	if config.WarnBytes == 0 {
+		config.WarnBytes = 5 << 20
+	}
+
+	var sizes *Histogram
+	var exceeded *Counter
+	if config.Metrics != nil {
+		buckets := []float64{1 << 10, 10 << 10, 100 << 10, 1 << 20, 5 << 20, 10 << 20, 50 << 20, 100 << 20}
+		sizes = config.Metrics.Histogram(MetricResponseSize, "HTTP response body size in bytes.", buckets, "route")
+		exceeded = config.Metrics.Counter(MetricResponseSizeExceeded, "HTTP responses above the configured size threshold.", "route", "action")
+	}
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			sw := &sizeGuardWriter{ResponseWriter: w, max: config.MaxBytes, status: http.StatusOK}
+			next(sw, r)
+
+			if sw.overflow {
+				if exceeded != nil {
+					exceeded.Inc(config.Name, "reject")
+				}
+				if config.OnExceeded != nil {
+					config.OnExceeded(r, sw.size, true)
+				}
+				slog.Error("response rejected: size limit exceeded",
+					"route", config.Name,
+					"method", r.Method,
+					"path", r.URL.Path,
+					"status", sw.status,
+					"size_bytes", sw.size,
+					"limit_bytes", config.MaxBytes,
+					"request_id", GetRequestID(r),
+				)
+				sw.reject(config.MaxBytes)
+				return
+			}
+
+			sw.flushBuffer()
+
+			if sizes != nil {
+				sizes.Observe(float64(sw.size), config.Name)
+			}
+			if config.WarnBytes > 0 && sw.size > config.WarnBytes {
+				if exceeded != nil {
+					exceeded.Inc(config.Name, "warn")
+				}
+				if config.OnExceeded != nil {
+					config.OnExceeded(r, sw.size, false)
+				}
+				slog.Warn("response size exceeds threshold",
+					"route", config.Name,
+					"method", r.Method,
+					"path", r.URL.Path,
+					"size_bytes", sw.size,
+					"threshold_bytes", config.WarnBytes,
+					"request_id", GetRequestID(r),
+				)
+			}
+		}
+	}
+}
+
+// sizeGuardWriter menghitung ukuran body. Jika max > 0, header dan body ditahan di buffer
+// hingga handler selesai (atau Flush dipanggil) agar response dapat diganti dengan 500.
+type sizeGuardWriter struct {
+	http.ResponseWriter
+	max      int64
+	size     int64
+	status   int
+	buf      bytes.Buffer
+	wrote    bool // WriteHeader sudah dipanggil oleh handler
+	passed   bool // header sudah diteruskan ke ResponseWriter asli
+	overflow bool
+}
+
+// WriteHeader menahan status code jika buffering aktif.
+func (w *sizeGuardWriter) WriteHeader(statusCode int) {
+	if w.wrote {
+		return
+	}
+	w.wrote = true
+	w.status = statusCode
+
+	// Content-Length yang sudah diketahui langsung ditolak tanpa menunggu body
+	if w.max > 0 {
+		if n, err := strconv.ParseInt(w.Header().Get("Content-Length"), 10, 64); err == nil && n > w.max {
+			w.size = n
+			w.overflow = true
+			return
+		}
+	}
+	if w.max <= 0 {
+		w.passHeader()
+	}
+}
+
+// Write menghitung byte dan mem-buffer body selama masih di bawah batas.
+func (w *sizeGuardWriter) Write(b []byte) (int, error) {
+	if !w.wrote {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.overflow {
+		return 0, ErrResponseTooLarge
+	}
+
+	w.size += int64(len(b))
+	if w.passed {
+		return w.ResponseWriter.Write(b)
+	}
+	if w.size > w.max {
+		w.overflow = true
+		w.buf = bytes.Buffer{}
+		return 0, ErrResponseTooLarge
+	}
+	return w.buf.Write(b)
+}
+
+// Flush mengirim buffer ke client dan beralih ke mode streaming.
+func (w *sizeGuardWriter) Flush() {
+	if w.overflow {
+		return
+	}
+	w.flushBuffer()
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap mengembalikan ResponseWriter asli (dipakai http.ResponseController dan locale lookup).
+func (w *sizeGuardWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *sizeGuardWriter) passHeader() {
+	if !w.passed {
+		w.passed = true
+		w.ResponseWriter.WriteHeader(w.status)
+	}
+}
+
+// flushBuffer meneruskan header dan body yang ditahan ke ResponseWriter asli.
+func (w *sizeGuardWriter) flushBuffer() {
+	if w.passed {
+		return
+	}
+	if !w.wrote {
+		// Handler tidak menulis apa pun; biarkan net/http mengirim 200 default
+		if w.buf.Len() == 0 {
+			w.passed = true
+			return
+		}
+	}
+	w.passHeader()
+	if w.buf.Len() > 0 {
+		w.ResponseWriter.Write(w.buf.Bytes())
+		w.buf = bytes.Buffer{}
+	}
+}
+
+// reject mengganti response yang ditahan dengan 500 berisi diagnosa.
+func (w *sizeGuardWriter) reject(limit int64) {
+	header := w.Header()
+	for _, h := range []string{"Content-Length", "Content-Encoding", "Content-Disposition", "ETag", "Last-Modified"} {
+		header.Del(h)
+	}
+	JsonError(w.ResponseWriter, http.StatusInternalServerError, "Response terlalu besar", FieldErrors{
+		"limit_bytes": limit,
+	})
+}
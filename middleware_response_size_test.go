@@ -0,0 +1,144 @@
+package dim
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func sizedHandler(size int) HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Set("X-Custom", "kept")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(strings.Repeat("x", size)))
+	}
+}
+
+func TestResponseSizeGuard_PassThrough(t *testing.T) {
+	guard := ResponseSizeGuard(ResponseSizeConfig{MaxBytes: 100})
+
+	w := httptest.NewRecorder()
+	guard(sizedHandler(100))(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if w.Code != http.StatusCreated || w.Body.Len() != 100 || w.Header().Get("X-Custom") != "kept" {
+		t.Errorf("status = %d, len = %d, headers = %v", w.Code, w.Body.Len(), w.Header())
+	}
+
+	// Handler without body keeps its status
+	w = httptest.NewRecorder()
+	guard(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNoContent) })(w, httptest.NewRequest(http.MethodDelete, "/", nil))
+	if w.Code != http.StatusNoContent {
+		t.Errorf("no content status = %d", w.Code)
+	}
+}
+
+func TestResponseSizeGuard_Reject(t *testing.T) {
+	var rejectedSize int64
+	guard := ResponseSizeGuard(ResponseSizeConfig{
+		MaxBytes:   100,
+		OnExceeded: func(r *http.Request, size int64, rejected bool) { rejectedSize = size },
+	})
+
+	var writeErr error
+	handler := guard(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(strings.Repeat("x", 60)))
+		_, writeErr = w.Write([]byte(strings.Repeat("x", 60)))
+	})
+
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest(http.MethodGet, "/users", nil))
+
+	if !errors.Is(writeErr, ErrResponseTooLarge) {
+		t.Errorf("Write() error = %v, want ErrResponseTooLarge", writeErr)
+	}
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", w.Code)
+	}
+	var body struct {
+		Message string         `json:"message"`
+		Errors  map[string]any `json:"errors"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("body = %s", w.Body.String())
+	}
+	if body.Message != "Response terlalu besar" || body.Errors["limit_bytes"] != float64(100) {
+		t.Errorf("body = %+v", body)
+	}
+	if rejectedSize != 120 {
+		t.Errorf("OnExceeded size = %d, want 120", rejectedSize)
+	}
+}
+
+func TestResponseSizeGuard_RejectJSONAndContentLength(t *testing.T) {
+	guard := ResponseSizeGuard(ResponseSizeConfig{MaxBytes: 64})
+
+	w := httptest.NewRecorder()
+	guard(func(w http.ResponseWriter, r *http.Request) {
+		Json(w, http.StatusOK, map[string]string{"data": strings.Repeat("a", 200)})
+	})(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if w.Code != http.StatusInternalServerError || strings.Contains(w.Body.String(), "aaaa") {
+		t.Errorf("oversized Json: status = %d, body = %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	guard(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "1000")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("partial"))
+	})(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if w.Code != http.StatusInternalServerError || w.Header().Get("Content-Length") != "" {
+		t.Errorf("declared length: status = %d, headers = %v", w.Code, w.Header())
+	}
+}
+
+func TestResponseSizeGuard_WarnAndMetrics(t *testing.T) {
+	metrics := NewMetricsRegistry()
+	var warned []int64
+	guard := ResponseSizeGuard(ResponseSizeConfig{
+		Name:       "users.list",
+		WarnBytes:  50,
+		Metrics:    metrics,
+		OnExceeded: func(r *http.Request, size int64, rejected bool) { warned = append(warned, size) },
+	})
+
+	for _, size := range []int{10, 80} {
+		w := httptest.NewRecorder()
+		guard(sizedHandler(size))(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		if w.Body.Len() != size {
+			t.Errorf("warn-only response truncated: %d", w.Body.Len())
+		}
+	}
+	if len(warned) != 1 || warned[0] != 80 {
+		t.Errorf("warnings = %v", warned)
+	}
+
+	var out strings.Builder
+	metrics.WriteTo(&out)
+	for _, want := range []string{
+		`dim_http_response_size_exceeded_total{route="users.list",action="warn"} 1`,
+		`dim_http_response_size_bytes_count{route="users.list"} 2`,
+		`dim_http_response_size_bytes_sum{route="users.list"} 90`,
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("metrics missing %s\n%s", want, out.String())
+		}
+	}
+}
+
+func TestResponseSizeGuard_Flush(t *testing.T) {
+	guard := ResponseSizeGuard(ResponseSizeConfig{MaxBytes: 10})
+
+	w := httptest.NewRecorder()
+	guard(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("event: 1\n"))
+		http.NewResponseController(w).Flush()
+		w.Write([]byte("event: 2\n"))
+	})(w, httptest.NewRequest(http.MethodGet, "/stream", nil))
+
+	if !w.Flushed || w.Body.String() != "event: 1\nevent: 2\n" {
+		t.Errorf("flushed = %v, body = %q", w.Flushed, w.Body.String())
+	}
+}
@@ -0,0 +1,300 @@
+package dim
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Sumber CSP yang umum digunakan.
+const (
+	CSPSelf          = "'self'"
+	CSPNone          = "'none'"
+	CSPUnsafeInline  = "'unsafe-inline'"
+	CSPUnsafeEval    = "'unsafe-eval'"
+	CSPStrictDynamic = "'strict-dynamic'"
+	CSPData          = "data:"
+	CSPBlob          = "blob:"
+	CSPHTTPS         = "https:"
+)
+
+// CSPNoncePlaceholder adalah placeholder di index.html SPA yang diganti dengan nonce CSP per request.
+//
+// Example (index.html):
+//
+//	<script nonce="__CSP_NONCE__" src="/assets/app.js"></script>
+const CSPNoncePlaceholder = "__CSP_NONCE__"
+
+const cspNonceKey contextKey = "csp_nonce"
+
+// cspDirective adalah satu directive CSP beserta sumbernya.
+type cspDirective struct {
+	name    string
+	sources []string
+}
+
+// CSPBuilder membangun header Content-Security-Policy secara fluent.
+// Urutan directive pada output mengikuti urutan pemanggilan.
+type CSPBuilder struct {
+	directives      []cspDirective
+	nonceTargets    map[string]bool
+	upgradeInsecure bool
+}
+
+// NewCSPBuilder membuat CSPBuilder kosong.
+//
+// Example:
+//
+//	csp := dim.NewCSPBuilder().
+//	    DefaultSrc(dim.CSPSelf).
+//	    ScriptSrc(dim.CSPSelf).
+//	    StyleSrc(dim.CSPSelf, "https://fonts.googleapis.com").
+//	    ImgSrc(dim.CSPSelf, dim.CSPData).
+//	    FrameAncestors(dim.CSPNone).
+//	    WithNonce("script-src")
+func NewCSPBuilder() *CSPBuilder {
+	return &CSPBuilder{nonceTargets: make(map[string]bool)}
+}
+
+// Directive menambahkan sumber ke directive dengan nama tertentu.
+// Pemanggilan berulang untuk directive yang sama menggabungkan sumbernya.
+func (b *CSPBuilder) Directive(name string, sources ...string) *CSPBuilder {
+	for i := range b.directives {
+		if b.directives[i].name == name {
+			b.directives[i].sources = append(b.directives[i].sources, sources...)
+			return b
+		}
+	}
+	b.directives = append(b.directives, cspDirective{name: name, sources: sources})
+	return b
+}
+
+// DefaultSrc mengatur directive default-src.
+func (b *CSPBuilder) DefaultSrc(sources ...string) *CSPBuilder {
+	return b.Directive("default-src", sources...)
+}
+
+// ScriptSrc mengatur directive script-src.
+func (b *CSPBuilder) ScriptSrc(sources ...string) *CSPBuilder {
+	return b.Directive("script-src", sources...)
+}
+
+// StyleSrc mengatur directive style-src.
+func (b *CSPBuilder) StyleSrc(sources ...string) *CSPBuilder {
+	return b.Directive("style-src", sources...)
+}
+
+// ImgSrc mengatur directive img-src.
+func (b *CSPBuilder) ImgSrc(sources ...string) *CSPBuilder {
+	return b.Directive("img-src", sources...)
+}
+
+// ConnectSrc mengatur directive connect-src.
+func (b *CSPBuilder) ConnectSrc(sources ...string) *CSPBuilder {
+	return b.Directive("connect-src", sources...)
+}
+
+// FontSrc mengatur directive font-src.
+func (b *CSPBuilder) FontSrc(sources ...string) *CSPBuilder {
+	return b.Directive("font-src", sources...)
+}
+
+// ObjectSrc mengatur directive object-src.
+func (b *CSPBuilder) ObjectSrc(sources ...string) *CSPBuilder {
+	return b.Directive("object-src", sources...)
+}
+
+// FrameAncestors mengatur directive frame-ancestors.
+func (b *CSPBuilder) FrameAncestors(sources ...string) *CSPBuilder {
+	return b.Directive("frame-ancestors", sources...)
+}
+
+// BaseURI mengatur directive base-uri.
+func (b *CSPBuilder) BaseURI(sources ...string) *CSPBuilder {
+	return b.Directive("base-uri", sources...)
+}
+
+// FormAction mengatur directive form-action.
+func (b *CSPBuilder) FormAction(sources ...string) *CSPBuilder {
+	return b.Directive("form-action", sources...)
+}
+
+// ReportURI mengatur directive report-uri.
+func (b *CSPBuilder) ReportURI(uri string) *CSPBuilder {
+	return b.Directive("report-uri", uri)
+}
+
+// UpgradeInsecureRequests menambahkan directive upgrade-insecure-requests.
+func (b *CSPBuilder) UpgradeInsecureRequests() *CSPBuilder {
+	b.upgradeInsecure = true
+	return b
+}
+
+// WithNonce menandai directive yang akan mendapatkan sumber 'nonce-...' per request.
+// Jika tidak ada directive yang disebutkan, nonce ditambahkan ke script-src.
+func (b *CSPBuilder) WithNonce(directives ...string) *CSPBuilder {
+	if len(directives) == 0 {
+		directives = []string{"script-src"}
+	}
+	for _, d := range directives {
+		b.nonceTargets[d] = true
+		// Pastikan directive ada agar nonce selalu dapat dipasang
+		b.Directive(d)
+	}
+	return b
+}
+
+// UsesNonce mengembalikan true jika ada directive yang membutuhkan nonce.
+func (b *CSPBuilder) UsesNonce() bool {
+	return len(b.nonceTargets) > 0
+}
+
+// Build menghasilkan nilai header Content-Security-Policy.
+// Parameter nonce diabaikan jika tidak ada directive yang memakai WithNonce.
+//
+// Parameters:
+//   - nonce: nonce per request (kosongkan jika tidak memakai nonce)
+//
+// Returns:
+//   - string: nilai header, misal "default-src 'self'; script-src 'self' 'nonce-abc'"
+func (b *CSPBuilder) Build(nonce string) string {
+	parts := make([]string, 0, len(b.directives)+1)
+	for _, d := range b.directives {
+		sources := d.sources
+		if nonce != "" && b.nonceTargets[d.name] {
+			sources = append(append([]string(nil), sources...), fmt.Sprintf("'nonce-%s'", nonce))
+		}
+		if len(sources) == 0 {
+			parts = append(parts, d.name)
+			continue
+		}
+		parts = append(parts, d.name+" "+strings.Join(sources, " "))
+	}
+	if b.upgradeInsecure {
+		parts = append(parts, "upgrade-insecure-requests")
+	}
+	return strings.Join(parts, "; ")
+}
+
+// SecureHeadersConfig berisi konfigurasi header keamanan.
+// Field string kosong atau HSTSMaxAge 0 berarti header tersebut tidak di-set.
+type SecureHeadersConfig struct {
+	HSTSMaxAge            int    // Strict-Transport-Security max-age dalam detik
+	HSTSIncludeSubdomains bool   // Tambahkan includeSubDomains pada HSTS
+	HSTSPreload           bool   // Tambahkan preload pada HSTS
+	FrameOptions          string // X-Frame-Options: "DENY" atau "SAMEORIGIN"
+	ContentTypeNosniff    bool   // X-Content-Type-Options: nosniff
+	ReferrerPolicy        string // Referrer-Policy, misal "strict-origin-when-cross-origin"
+	PermissionsPolicy     string // Permissions-Policy, misal "camera=(), microphone=()"
+	CrossOriginOpener     string // Cross-Origin-Opener-Policy, misal "same-origin"
+	CSP                   *CSPBuilder
+	CSPReportOnly         bool // Kirim sebagai Content-Security-Policy-Report-Only
+}
+
+// DefaultSecureHeadersConfig mengembalikan konfigurasi header keamanan yang aman untuk API dan SPA.
+// CSP tidak di-set secara default karena kebutuhannya spesifik per aplikasi.
+func DefaultSecureHeadersConfig() SecureHeadersConfig {
+	return SecureHeadersConfig{
+		HSTSMaxAge:            31536000,
+		HSTSIncludeSubdomains: true,
+		FrameOptions:          "DENY",
+		ContentTypeNosniff:    true,
+		ReferrerPolicy:        "strict-origin-when-cross-origin",
+		PermissionsPolicy:     "camera=(), microphone=(), geolocation=()",
+		CrossOriginOpener:     "same-origin",
+	}
+}
+
+// SecureHeaders membuat middleware yang memasang header keamanan (HSTS, X-Frame-Options,
+// Referrer-Policy, Permissions-Policy, dan Content-Security-Policy).
+// Jika CSP memakai nonce, nonce acak dibuat per request dan dapat diambil via GetCSPNonce;
+// SPA() juga mengganti CSPNoncePlaceholder di index.html dengan nonce tersebut.
+//
+// Parameters:
+//   - config: SecureHeadersConfig berisi header yang akan dipasang
+//
+// Returns:
+//   - MiddlewareFunc: middleware yang memasang header keamanan
+//
+// Example:
+//
+//	cfg := dim.DefaultSecureHeadersConfig()
+//	cfg.CSP = dim.NewCSPBuilder().DefaultSrc(dim.CSPSelf).WithNonce()
+//	router.Use(dim.SecureHeaders(cfg))
+//
+//	// Atau khusus untuk SPA
+//	router.SPA(distFS, "index.html", dim.SecureHeaders(cfg))
+func SecureHeaders(config SecureHeadersConfig) MiddlewareFunc {
+	hsts := ""
+	if config.HSTSMaxAge > 0 {
+		hsts = fmt.Sprintf("max-age=%d", config.HSTSMaxAge)
+		if config.HSTSIncludeSubdomains {
+			hsts += "; includeSubDomains"
+		}
+		if config.HSTSPreload {
+			hsts += "; preload"
+		}
+	}
+
+	cspHeader := "Content-Security-Policy"
+	if config.CSPReportOnly {
+		cspHeader = "Content-Security-Policy-Report-Only"
+	}
+
+	// Tanpa nonce, nilai CSP statis dan cukup dibangun sekali
+	staticCSP := ""
+	if config.CSP != nil && !config.CSP.UsesNonce() {
+		staticCSP = config.CSP.Build("")
+	}
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			h := w.Header()
+			if hsts != "" {
+				h.Set("Strict-Transport-Security", hsts)
+			}
+			if config.FrameOptions != "" {
+				h.Set("X-Frame-Options", config.FrameOptions)
+			}
+			if config.ContentTypeNosniff {
+				h.Set("X-Content-Type-Options", "nosniff")
+			}
+			if config.ReferrerPolicy != "" {
+				h.Set("Referrer-Policy", config.ReferrerPolicy)
+			}
+			if config.PermissionsPolicy != "" {
+				h.Set("Permissions-Policy", config.PermissionsPolicy)
+			}
+			if config.CrossOriginOpener != "" {
+				h.Set("Cross-Origin-Opener-Policy", config.CrossOriginOpener)
+			}
+
+			if config.CSP != nil {
+				if staticCSP != "" {
+					h.Set(cspHeader, staticCSP)
+				} else {
+					nonce, err := GenerateSecureToken(16)
+					if err != nil {
+						InternalServerError(w, "Kesalahan server internal")
+						return
+					}
+					h.Set(cspHeader, config.CSP.Build(nonce))
+					r = r.WithContext(context.WithValue(r.Context(), cspNonceKey, nonce))
+				}
+			}
+
+			next(w, r)
+		}
+	}
+}
+
+// GetCSPNonce mengambil nonce CSP untuk request saat ini.
+// Gunakan nilai ini pada atribut nonce di tag <script> atau <style> yang di-render server.
+//
+// Returns:
+//   - string: nonce, atau string kosong jika SecureHeaders tidak memakai nonce
+func GetCSPNonce(r *http.Request) string {
+	nonce, _ := r.Context().Value(cspNonceKey).(string)
+	return nonce
+}
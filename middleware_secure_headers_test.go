@@ -0,0 +1,127 @@
+package dim
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestCSPBuilder_Build(t *testing.T) {
+	csp := NewCSPBuilder().
+		DefaultSrc(CSPSelf).
+		ScriptSrc(CSPSelf).
+		ImgSrc(CSPSelf, CSPData).
+		ScriptSrc("https://cdn.example.com").
+		FrameAncestors(CSPNone).
+		UpgradeInsecureRequests()
+
+	want := "default-src 'self'; script-src 'self' https://cdn.example.com; img-src 'self' data:; frame-ancestors 'none'; upgrade-insecure-requests"
+	if got := csp.Build(""); got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+}
+
+func TestCSPBuilder_Nonce(t *testing.T) {
+	csp := NewCSPBuilder().DefaultSrc(CSPSelf).WithNonce()
+
+	if !csp.UsesNonce() {
+		t.Fatal("expected UsesNonce to be true")
+	}
+
+	want := "default-src 'self'; script-src 'nonce-abc123'"
+	if got := csp.Build("abc123"); got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+}
+
+func TestSecureHeaders_Defaults(t *testing.T) {
+	handler := SecureHeaders(DefaultSecureHeadersConfig())(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	expected := map[string]string{
+		"Strict-Transport-Security":  "max-age=31536000; includeSubDomains",
+		"X-Frame-Options":            "DENY",
+		"X-Content-Type-Options":     "nosniff",
+		"Referrer-Policy":            "strict-origin-when-cross-origin",
+		"Permissions-Policy":         "camera=(), microphone=(), geolocation=()",
+		"Cross-Origin-Opener-Policy": "same-origin",
+	}
+	for header, want := range expected {
+		if got := rec.Header().Get(header); got != want {
+			t.Errorf("%s: got %q, want %q", header, got, want)
+		}
+	}
+	if rec.Header().Get("Content-Security-Policy") != "" {
+		t.Error("expected no CSP header by default")
+	}
+}
+
+func TestSecureHeaders_NonceInContext(t *testing.T) {
+	cfg := SecureHeadersConfig{CSP: NewCSPBuilder().DefaultSrc(CSPSelf).WithNonce()}
+
+	var nonce string
+	handler := SecureHeaders(cfg)(func(w http.ResponseWriter, r *http.Request) {
+		nonce = GetCSPNonce(r)
+	})
+
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if nonce == "" {
+		t.Fatal("expected nonce in request context")
+	}
+	if !strings.Contains(rec.Header().Get("Content-Security-Policy"), "'nonce-"+nonce+"'") {
+		t.Errorf("expected CSP header to contain nonce, got %q", rec.Header().Get("Content-Security-Policy"))
+	}
+}
+
+func TestSecureHeaders_ReportOnly(t *testing.T) {
+	cfg := SecureHeadersConfig{CSP: NewCSPBuilder().DefaultSrc(CSPSelf), CSPReportOnly: true}
+	handler := SecureHeaders(cfg)(func(w http.ResponseWriter, r *http.Request) {})
+
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if got := rec.Header().Get("Content-Security-Policy-Report-Only"); got != "default-src 'self'" {
+		t.Errorf("unexpected report-only header %q", got)
+	}
+	if rec.Header().Get("Content-Security-Policy") != "" {
+		t.Error("expected enforcing CSP header to be absent")
+	}
+}
+
+func TestRouter_SPA_CSPNonce(t *testing.T) {
+	mockFS := fstest.MapFS{
+		"index.html": &fstest.MapFile{
+			Data: []byte(`<script nonce="` + CSPNoncePlaceholder + `"></script>`),
+		},
+	}
+
+	cfg := SecureHeadersConfig{CSP: NewCSPBuilder().DefaultSrc(CSPSelf).WithNonce()}
+
+	router := NewRouter()
+	router.SPA(mockFS, "index.html", SecureHeaders(cfg))
+	router.Build()
+
+	for _, path := range []string{"/", "/dashboard"} {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+		body := rec.Body.String()
+		if strings.Contains(body, CSPNoncePlaceholder) {
+			t.Errorf("%s: expected placeholder to be replaced, got %s", path, body)
+		}
+		csp := rec.Header().Get("Content-Security-Policy")
+		start := strings.Index(body, `nonce="`) + len(`nonce="`)
+		nonce := body[start : start+strings.Index(body[start:], `"`)]
+		if !strings.Contains(csp, "'nonce-"+nonce+"'") {
+			t.Errorf("%s: nonce %q in body does not match CSP %q", path, nonce, csp)
+		}
+	}
+}
@@ -5,6 +5,9 @@ import (
 	"fmt"
 	"log/slog"
 	"regexp"
+	"sort"
+	"strings"
+	"time"
 )
 
 // Migration represents a single migration
@@ -13,12 +16,18 @@ type Migration struct {
 	Name    string
 	Up      func(Database) error
 	Down    func(Database) error
+	// Checksum (opsional) disimpan saat migration dijalankan. Jika berbeda dengan checksum
+	// yang tersimpan, RunMigrations gagal karena migration diedit setelah dijalankan.
+	// Diisi otomatis oleh LoadSQLMigrations.
+	Checksum string
 }
 
 // MigrationHistory represents the migration history table
 type MigrationHistory struct {
-	Version int64
-	Name    string
+	Version   int64
+	Name      string
+	Checksum  string
+	AppliedAt time.Time
 }
 
 var migrationRegistry []Migration
@@ -55,6 +64,10 @@ func GetRegisteredMigrations() []Migration {
 // 3. Password Reset Tokens
 // 4. Token Blocklist
 // 5. Rate Limits
+// 6. Users deleted_at (soft delete)
+// 7. Users version (optimistic locking)
+// 8. Refresh Tokens session_id
+// 9. Refresh Tokens remember_me dan session_expires_at (sliding session)
 func GetFrameworkMigrations() []Migration {
 	if !includeFrameworkMigrations {
 		return []Migration{}
@@ -85,6 +98,13 @@ func GetFrameworkMigrations() []Migration {
 //	  log.Fatal(err)
 //	}
 func RunMigrations(db Database, migrations []Migration) error {
+	return withMigrationLock(db, func() error {
+		return runMigrations(db, migrations)
+	})
+}
+
+// runMigrations applies pending migrations; the caller must hold the migration lock.
+func runMigrations(db Database, migrations []Migration) error {
 	// Create migrations table if it doesn't exist
 	if err := ensureMigrationsTable(db); err != nil {
 		return fmt.Errorf("failed to ensure migrations table: %w", err)
@@ -96,6 +116,24 @@ func RunMigrations(db Database, migrations []Migration) error {
 		return fmt.Errorf("failed to get applied migrations: %w", err)
 	}
 
+	// Detect applied migrations that were edited afterwards
+	for _, migration := range migrations {
+		history, exists := applied[migration.Version]
+		if !exists || migration.Checksum == "" {
+			continue
+		}
+		if history.Checksum == "" {
+			// Applied before checksum tracking: remember the current checksum
+			if err := updateMigrationChecksum(db, migration); err != nil {
+				return fmt.Errorf("failed to record checksum of migration %d: %w", migration.Version, err)
+			}
+			continue
+		}
+		if err := checkMigrationChecksum(migration, history); err != nil {
+			return err
+		}
+	}
+
 	// Apply pending migrations
 	for _, migration := range migrations {
 		if _, exists := applied[migration.Version]; exists {
@@ -150,6 +188,177 @@ func RollbackMigration(db Database, migration Migration) error {
 	return nil
 }
 
+// RollbackMigrations me-rollback beberapa migration sesuai urutan slice (biasanya versi terbaru
+// lebih dulu) sambil memegang migration lock, sehingga tidak bentrok dengan runner di replica lain.
+//
+// Parameters:
+//   - db: Database instance untuk execute rollback queries
+//   - migrations: migration yang akan di-rollback, berurutan
+//
+// Returns:
+//   - error: error lock atau error pertama dari RollbackMigration; migration setelahnya tidak dijalankan
+//
+// Example:
+//
+//	toRollback, err := dim.AppliedMigrationsAfter(db, migrations, 20240101000000)
+//	if err != nil {
+//	  log.Fatal(err)
+//	}
+//	err = dim.RollbackMigrations(db, toRollback)
+func RollbackMigrations(db Database, migrations []Migration) error {
+	return withMigrationLock(db, func() error {
+		for _, migration := range migrations {
+			if err := RollbackMigration(db, migration); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// AppliedMigrationsAfter mengembalikan migration yang sudah dijalankan dengan versi lebih besar dari
+// version, diurutkan dari versi terbaru. Hasilnya siap diteruskan ke RollbackMigrations untuk
+// rollback ke version tertentu (version 0 berarti rollback semua).
+//
+// Parameters:
+//   - db: Database instance
+//   - migrations: semua migration yang dikenal aplikasi
+//   - version: versi tujuan; migration dengan versi <= version tidak disentuh
+//
+// Returns:
+//   - []Migration: migration yang perlu di-rollback
+//   - error: error jika ada migration yang sudah dijalankan tetapi tidak ada di migrations
+func AppliedMigrationsAfter(db Database, migrations []Migration, version int64) ([]Migration, error) {
+	applied, err := appliedMigrationsIfExists(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get applied migrations: %w", err)
+	}
+
+	known := make(map[int64]Migration, len(migrations))
+	for _, migration := range migrations {
+		known[migration.Version] = migration
+	}
+
+	var result []Migration
+	for v, history := range applied {
+		if v <= version {
+			continue
+		}
+		migration, ok := known[v]
+		if !ok {
+			return nil, fmt.Errorf("migration %d (%s) is applied but not registered", v, history.Name)
+		}
+		result = append(result, migration)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Version > result[j].Version
+	})
+	return result, nil
+}
+
+// FreshMigrations menghapus SEMUA tabel di database (termasuk tabel yang tidak dibuat oleh migration)
+// lalu menjalankan ulang semua migration dari awal. Hanya untuk development dan testing.
+//
+// Parameters:
+//   - db: Database instance
+//   - migrations: migration yang dijalankan setelah semua tabel dihapus
+//
+// Returns:
+//   - error: error lock, error saat menghapus tabel, atau error dari migration
+//
+// Example:
+//
+//	err := dim.FreshMigrations(db, migrations)
+func FreshMigrations(db Database, migrations []Migration) error {
+	return withMigrationLock(db, func() error {
+		tables, err := listTables(db)
+		if err != nil {
+			return fmt.Errorf("failed to list tables: %w", err)
+		}
+		if err := dropTables(db, tables); err != nil {
+			return err
+		}
+		return runMigrations(db, migrations)
+	})
+}
+
+// Status migration yang dikembalikan oleh MigrationStatus.
+const (
+	MigrationStatusApplied  = "applied"  // sudah dijalankan
+	MigrationStatusPending  = "pending"  // belum dijalankan
+	MigrationStatusModified = "modified" // sudah dijalankan, tetapi checksum berubah
+	MigrationStatusMissing  = "missing"  // tercatat di database, tetapi tidak terdaftar di aplikasi
+)
+
+// MigrationState adalah status satu migration untuk migrate:status.
+type MigrationState struct {
+	Version   int64
+	Name      string
+	Status    string
+	AppliedAt time.Time // zero jika belum dijalankan
+}
+
+// MigrationStatus membandingkan migration yang terdaftar dengan tabel migrations.
+// Tidak mengubah database; jika tabel migrations belum ada, semua migration berstatus pending.
+//
+// Parameters:
+//   - db: Database instance
+//   - migrations: semua migration yang dikenal aplikasi
+//
+// Returns:
+//   - []MigrationState: status setiap migration, diurutkan berdasarkan Version
+//   - error: error jika tabel migrations tidak dapat dibaca
+//
+// Example:
+//
+//	states, err := dim.MigrationStatus(db, migrations)
+//	for _, s := range states {
+//	    fmt.Println(s.Version, s.Name, s.Status)
+//	}
+func MigrationStatus(db Database, migrations []Migration) ([]MigrationState, error) {
+	applied, err := appliedMigrationsIfExists(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get applied migrations: %w", err)
+	}
+
+	states := make([]MigrationState, 0, len(migrations))
+	known := make(map[int64]bool, len(migrations))
+	for _, migration := range migrations {
+		known[migration.Version] = true
+		state := MigrationState{Version: migration.Version, Name: migration.Name, Status: MigrationStatusPending}
+		if history, ok := applied[migration.Version]; ok {
+			state.Status = MigrationStatusApplied
+			state.AppliedAt = history.AppliedAt
+			if checkMigrationChecksum(migration, history) != nil {
+				state.Status = MigrationStatusModified
+			}
+		}
+		states = append(states, state)
+	}
+	for version, history := range applied {
+		if !known[version] {
+			states = append(states, MigrationState{
+				Version:   version,
+				Name:      history.Name,
+				Status:    MigrationStatusMissing,
+				AppliedAt: history.AppliedAt,
+			})
+		}
+	}
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].Version < states[j].Version
+	})
+	return states, nil
+}
+
+// checkMigrationChecksum reports an applied migration whose checksum no longer matches.
+func checkMigrationChecksum(migration Migration, history MigrationHistory) error {
+	if migration.Checksum == "" || history.Checksum == "" || migration.Checksum == history.Checksum {
+		return nil
+	}
+	return fmt.Errorf("migration %d (%s) was modified after it was applied: checksum mismatch", migration.Version, migration.Name)
+}
+
 // ensureMigrationsTable creates the migrations history table
 func ensureMigrationsTable(db Database) error {
 	var query string
@@ -158,24 +367,79 @@ func ensureMigrationsTable(db Database) error {
 			CREATE TABLE IF NOT EXISTS migrations (
 				version INTEGER PRIMARY KEY,
 				name TEXT NOT NULL,
+				checksum TEXT,
 				applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 			)
 		`
+	} else if db.DriverName() == "mysql" {
+		query = `
+			CREATE TABLE IF NOT EXISTS migrations (
+				version BIGINT PRIMARY KEY,
+				name VARCHAR(255) NOT NULL,
+				checksum VARCHAR(64),
+				applied_at DATETIME DEFAULT CURRENT_TIMESTAMP
+			) ENGINE=InnoDB
+		`
 	} else {
 		query = `
 			CREATE TABLE IF NOT EXISTS migrations (
 				version BIGINT PRIMARY KEY,
 				name VARCHAR(255) NOT NULL,
+				checksum VARCHAR(64),
 				applied_at TIMESTAMP DEFAULT NOW()
 			)
 		`
 	}
-	return db.Exec(context.Background(), query)
+	if err := db.Exec(context.Background(), query); err != nil {
+		return err
+	}
+	return ensureChecksumColumn(db)
+}
+
+// ensureChecksumColumn adds the checksum column to migrations tables created by older versions.
+func ensureChecksumColumn(db Database) error {
+	rows, err := db.Query(context.Background(), "SELECT checksum FROM migrations WHERE 1 = 0")
+	if err == nil {
+		// Some drivers only report a missing column once the rows are read
+		for rows.Next() {
+		}
+		rows.Close()
+		if err = rows.Err(); err == nil {
+			return nil
+		}
+	}
+
+	columnType := "VARCHAR(64)"
+	if db.DriverName() == "sqlite" {
+		columnType = "TEXT"
+	}
+	return db.Exec(context.Background(), "ALTER TABLE migrations ADD COLUMN checksum "+columnType)
+}
+
+// migrationsTableExists reports whether the migrations history table has been created.
+func migrationsTableExists(db Database) bool {
+	rows, err := db.Query(context.Background(), "SELECT version FROM migrations WHERE 1 = 0")
+	if err != nil {
+		return false
+	}
+	for rows.Next() {
+	}
+	rows.Close()
+	return rows.Err() == nil
+}
+
+// appliedMigrationsIfExists is getAppliedMigrations without creating the table: a missing
+// migrations table means nothing has been applied yet.
+func appliedMigrationsIfExists(db Database) (map[int64]MigrationHistory, error) {
+	if !migrationsTableExists(db) {
+		return map[int64]MigrationHistory{}, nil
+	}
+	return getAppliedMigrations(db)
 }
 
 // getAppliedMigrations retrieves all applied migrations
 func getAppliedMigrations(db Database) (map[int64]MigrationHistory, error) {
-	rows, err := db.Query(context.Background(), "SELECT version, name FROM migrations ORDER BY version")
+	rows, err := db.Query(context.Background(), "SELECT version, name, COALESCE(checksum, ''), applied_at FROM migrations ORDER BY version")
 	if err != nil {
 		return nil, err
 	}
@@ -184,15 +448,18 @@ func getAppliedMigrations(db Database) (map[int64]MigrationHistory, error) {
 	applied := make(map[int64]MigrationHistory)
 	for rows.Next() {
 		var version int64
-		var name string
+		var name, checksum string
+		var appliedAt time.Time
 
-		if err := rows.Scan(&version, &name); err != nil {
+		if err := rows.Scan(&version, &name, &checksum, &appliedAt); err != nil {
 			return nil, err
 		}
 
 		applied[version] = MigrationHistory{
-			Version: version,
-			Name:    name,
+			Version:   version,
+			Name:      name,
+			Checksum:  checksum,
+			AppliedAt: appliedAt,
 		}
 	}
 
@@ -201,11 +468,24 @@ func getAppliedMigrations(db Database) (map[int64]MigrationHistory, error) {
 
 // recordMigration records a migration as applied
 func recordMigration(db Database, migration Migration) error {
-	query := "INSERT INTO migrations (version, name) VALUES ($1, $2)"
+	query := "INSERT INTO migrations (version, name, checksum) VALUES ($1, $2, $3)"
 	if db.DriverName() == "sqlite" {
 		query = rebind(query)
 	}
-	return db.Exec(context.Background(), query, migration.Version, migration.Name)
+	var checksum interface{}
+	if migration.Checksum != "" {
+		checksum = migration.Checksum
+	}
+	return db.Exec(context.Background(), query, migration.Version, migration.Name, checksum)
+}
+
+// updateMigrationChecksum stores the checksum of an already applied migration
+func updateMigrationChecksum(db Database, migration Migration) error {
+	query := "UPDATE migrations SET checksum = $1 WHERE version = $2"
+	if db.DriverName() == "sqlite" {
+		query = rebind(query)
+	}
+	return db.Exec(context.Background(), query, migration.Checksum, migration.Version)
 }
 
 // removeMigration removes a migration record
@@ -217,6 +497,71 @@ func removeMigration(db Database, migration Migration) error {
 	return db.Exec(context.Background(), query, migration.Version)
 }
 
+// listTables returns the tables of the current database/schema, except the migration lock table.
+func listTables(db Database) ([]string, error) {
+	var query string
+	switch db.DriverName() {
+	case "sqlite":
+		query = "SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%'"
+	case "mysql":
+		query = "SELECT table_name FROM information_schema.tables WHERE table_schema = DATABASE() AND table_type = 'BASE TABLE'"
+	default:
+		query = "SELECT tablename FROM pg_tables WHERE schemaname = current_schema()"
+	}
+
+	rows, err := db.Query(context.Background(), query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tables []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		if name != migrationLockTable {
+			tables = append(tables, name)
+		}
+	}
+	return tables, rows.Err()
+}
+
+// dropTableQuery builds the DROP TABLE statement for the driver.
+func dropTableQuery(db Database, table string) string {
+	if db.DriverName() == "mysql" {
+		return "DROP TABLE IF EXISTS `" + strings.ReplaceAll(table, "`", "``") + "`"
+	}
+	query := `DROP TABLE IF EXISTS "` + strings.ReplaceAll(table, `"`, `""`) + `"`
+	if db.DriverName() != "sqlite" {
+		query += " CASCADE"
+	}
+	return query
+}
+
+// dropTables drops the given tables. Without CASCADE (SQLite, MySQL) a table still referenced by a
+// foreign key fails to drop, so failed tables are retried until a pass makes no progress.
+func dropTables(db Database, tables []string) error {
+	for len(tables) > 0 {
+		var failed []string
+		var lastErr error
+		for _, table := range tables {
+			if err := db.Exec(context.Background(), dropTableQuery(db, table)); err != nil {
+				failed = append(failed, table)
+				lastErr = err
+				continue
+			}
+			slog.Info("table dropped", "table", table)
+		}
+		if len(failed) == len(tables) {
+			return fmt.Errorf("failed to drop table %s: %w", failed[0], lastErr)
+		}
+		tables = failed
+	}
+	return nil
+}
+
 // rebind replaces $1, $2, etc with ? for SQLite compatibility
 func rebind(query string) string {
 	re := regexp.MustCompile(`\$[0-9]+`)
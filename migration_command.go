@@ -1,11 +1,13 @@
 package dim
 
 import (
-	"context"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"text/template"
 	"time"
@@ -19,6 +21,31 @@ func migrationConn(ctx *CommandContext) Database {
 	return ctx.DB
 }
 
+// commandOut returns ctx.Out, falling back to stdout.
+func commandOut(ctx *CommandContext) io.Writer {
+	if ctx.Out != nil {
+		return ctx.Out
+	}
+	return os.Stdout
+}
+
+// allMigrations returns framework migrations followed by registered migrations.
+func allMigrations() []Migration {
+	migrations := GetFrameworkMigrations()
+	// Combine with registered migrations (from auto-discovery)
+	return append(migrations, GetRegisteredMigrations()...)
+}
+
+// confirm asks a yes/no question on stdin.
+func confirm(question string) bool {
+	fmt.Print(question + " (yes/no): ")
+	var response string
+	fmt.Scanln(&response)
+
+	response = strings.ToLower(strings.TrimSpace(response))
+	return response == "yes" || response == "y"
+}
+
 // ============================================================================
 // MigrateCommand - Run pending migrations
 // ============================================================================
@@ -26,6 +53,7 @@ func migrationConn(ctx *CommandContext) Database {
 // MigrateCommand menjalankan semua pending database migrations.
 type MigrateCommand struct {
 	verbose bool
+	dryRun  bool
 }
 
 func (c *MigrateCommand) Name() string {
@@ -38,6 +66,7 @@ func (c *MigrateCommand) Description() string {
 
 func (c *MigrateCommand) DefineFlags(fs *flag.FlagSet) {
 	fs.BoolVar(&c.verbose, "v", false, "Show detailed migration output")
+	fs.BoolVar(&c.dryRun, "dry-run", false, "Print the SQL of pending migrations without executing it")
 }
 
 func (c *MigrateCommand) Execute(ctx *CommandContext) error {
@@ -54,14 +83,23 @@ func (c *MigrateCommand) Execute(ctx *CommandContext) error {
 		fmt.Println("Running migrations in verbose mode...")
 	}
 
-	migrations := GetFrameworkMigrations()
-	// Combine with registered migrations (from auto-discovery)
-	migrations = append(migrations, GetRegisteredMigrations()...)
+	migrations := allMigrations()
 
 	if c.verbose {
 		fmt.Printf("Found %d total migrations\n", len(migrations))
 	}
 
+	if c.dryRun {
+		plans, err := PlanMigrations(db, migrations)
+		if err != nil {
+			return fmt.Errorf("migration failed: %w", err)
+		}
+		out := commandOut(ctx)
+		fmt.Fprintf(out, "-- Dry run: %d pending migration(s), nothing was executed\n\n", len(plans))
+		WriteMigrationPlan(out, plans)
+		return nil
+	}
+
 	if err := RunMigrations(db, migrations); err != nil {
 		return fmt.Errorf("migration failed: %w", err)
 	}
@@ -76,8 +114,10 @@ func (c *MigrateCommand) Execute(ctx *CommandContext) error {
 
 // MigrateRollbackCommand membatalkan migration yang sudah dijalankan.
 type MigrateRollbackCommand struct {
-	steps int
-	force bool
+	steps  int
+	to     *int64
+	force  bool
+	dryRun bool
 }
 
 func (c *MigrateRollbackCommand) Name() string {
@@ -90,7 +130,16 @@ func (c *MigrateRollbackCommand) Description() string {
 
 func (c *MigrateRollbackCommand) DefineFlags(fs *flag.FlagSet) {
 	fs.IntVar(&c.steps, "step", 1, "Number of migrations to rollback")
+	fs.Func("to", "Rollback every migration newer than this version (0 rolls back all; overrides -step)", func(value string) error {
+		version, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid version %q", value)
+		}
+		c.to = &version
+		return nil
+	})
 	fs.BoolVar(&c.force, "force", false, "Skip confirmation prompt")
+	fs.BoolVar(&c.dryRun, "dry-run", false, "Print the rollback SQL without executing it")
 }
 
 func (c *MigrateRollbackCommand) Execute(ctx *CommandContext) error {
@@ -98,48 +147,18 @@ func (c *MigrateRollbackCommand) Execute(ctx *CommandContext) error {
 		return fmt.Errorf("database connection required")
 	}
 
-	if c.steps <= 0 {
+	if c.to == nil && c.steps <= 0 {
 		return fmt.Errorf("steps must be greater than 0")
 	}
+	if c.to != nil && *c.to < 0 {
+		return fmt.Errorf("target version must not be negative")
+	}
 
 	db := migrationConn(ctx)
 
-	fmt.Printf("Rolling back %d migration(s)...\n", c.steps)
-
-	// Get applied migrations
-	query := `SELECT version, name FROM migrations ORDER BY version DESC LIMIT $1`
-	rows, err := db.Query(context.Background(), query, c.steps)
+	migrationsToRollback, err := c.migrationsToRollback(db)
 	if err != nil {
-		return fmt.Errorf("failed to query migrations: %w", err)
-	}
-	defer rows.Close()
-
-	// Collect migrations to rollback
-	var migrationsToRollback []Migration
-	frameworkMigrations := GetFrameworkMigrations()
-	// Add registered migrations
-	frameworkMigrations = append(frameworkMigrations, GetRegisteredMigrations()...)
-
-	for rows.Next() {
-		var version int64
-		var name string
-		if err := rows.Scan(&version, &name); err != nil {
-			return err
-		}
-
-		// Find migration in registered migrations
-		found := false
-		for _, migration := range frameworkMigrations {
-			if migration.Version == version {
-				migrationsToRollback = append(migrationsToRollback, migration)
-				found = true
-				break
-			}
-		}
-
-		if !found {
-			fmt.Printf("⚠ Warning: Migration '%s' (version %d) not found in registered migrations\n", name, version)
-		}
+		return err
 	}
 
 	if len(migrationsToRollback) == 0 {
@@ -147,6 +166,17 @@ func (c *MigrateRollbackCommand) Execute(ctx *CommandContext) error {
 		return nil
 	}
 
+	if c.dryRun {
+		plans, err := PlanRollback(db, migrationsToRollback)
+		if err != nil {
+			return fmt.Errorf("rollback failed: %w", err)
+		}
+		out := commandOut(ctx)
+		fmt.Fprintf(out, "-- Dry run: %d migration(s) to rollback, nothing was executed\n\n", len(plans))
+		WriteMigrationPlan(out, plans)
+		return nil
+	}
+
 	// Display migrations that will be rolled back
 	fmt.Println("\nThe following migrations will be rolled back:")
 	for _, migration := range migrationsToRollback {
@@ -156,31 +186,124 @@ func (c *MigrateRollbackCommand) Execute(ctx *CommandContext) error {
 
 	// Confirmation prompt (unless -force flag is set)
 	if !c.force {
-		fmt.Print("Are you sure you want to proceed? (yes/no): ")
-		var response string
-		fmt.Scanln(&response)
-
-		response = strings.ToLower(strings.TrimSpace(response))
-		if response != "yes" && response != "y" {
+		if !confirm("Are you sure you want to proceed?") {
 			fmt.Println("Rollback cancelled")
 			return nil
 		}
 		fmt.Println()
 	}
 
-	// Rollback each migration
-	for _, migration := range migrationsToRollback {
-		fmt.Printf("Rolling back: %s (version %d)\n", migration.Name, migration.Version)
-		if err := RollbackMigration(db, migration); err != nil {
-			return fmt.Errorf("rollback failed for %s: %w", migration.Name, err)
-		}
-		fmt.Printf("✓ Rolled back: %s\n", migration.Name)
+	if err := RollbackMigrations(db, migrationsToRollback); err != nil {
+		return fmt.Errorf("rollback failed: %w", err)
 	}
 
 	fmt.Printf("\n✓ Successfully rolled back %d migration(s)\n", len(migrationsToRollback))
 	return nil
 }
 
+// migrationsToRollback resolves -to or -step into the applied migrations to roll back, newest first.
+func (c *MigrateRollbackCommand) migrationsToRollback(db Database) ([]Migration, error) {
+	var target int64
+	if c.to != nil {
+		target = *c.to
+	} else {
+		applied, err := appliedMigrationsIfExists(db)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query migrations: %w", err)
+		}
+		versions := make([]int64, 0, len(applied))
+		for version := range applied {
+			versions = append(versions, version)
+		}
+		sort.Slice(versions, func(i, j int) bool { return versions[i] > versions[j] })
+
+		// Roll back everything newer than the first migration that stays applied
+		target = -1
+		if len(versions) > c.steps {
+			target = versions[c.steps]
+		}
+	}
+
+	migrations, err := AppliedMigrationsAfter(db, allMigrations(), target)
+	if err != nil {
+		return nil, err
+	}
+	return migrations, nil
+}
+
+// ============================================================================
+// MigrateFreshCommand - Drop all tables and re-run migrations
+// ============================================================================
+
+// MigrateFreshCommand menghapus semua tabel lalu menjalankan ulang semua migrations.
+type MigrateFreshCommand struct {
+	force  bool
+	dryRun bool
+}
+
+func (c *MigrateFreshCommand) Name() string {
+	return "migrate:fresh"
+}
+
+func (c *MigrateFreshCommand) Description() string {
+	return "Drop all tables and re-run all migrations"
+}
+
+func (c *MigrateFreshCommand) DefineFlags(fs *flag.FlagSet) {
+	fs.BoolVar(&c.force, "force", false, "Skip confirmation prompt")
+	fs.BoolVar(&c.dryRun, "dry-run", false, "Print the SQL without executing it")
+}
+
+func (c *MigrateFreshCommand) Execute(ctx *CommandContext) error {
+	if ctx.DB == nil {
+		return fmt.Errorf("database connection required")
+	}
+
+	db := migrationConn(ctx)
+	migrations := allMigrations()
+
+	tables, err := listTables(db)
+	if err != nil {
+		return fmt.Errorf("failed to list tables: %w", err)
+	}
+
+	if c.dryRun {
+		out := commandOut(ctx)
+		fmt.Fprintf(out, "-- Dry run: drop %d table(s) and run %d migration(s), nothing was executed\n\n", len(tables), len(migrations))
+		for _, table := range tables {
+			fmt.Fprintln(out, dropTableQuery(db, table)+";")
+		}
+		fmt.Fprintln(out)
+
+		plans := make([]MigrationPlan, 0, len(migrations))
+		for _, migration := range migrations {
+			plan, err := planMigration(db, migration, "up", migration.Up)
+			if err != nil {
+				return fmt.Errorf("migration failed: %w", err)
+			}
+			plans = append(plans, plan)
+		}
+		WriteMigrationPlan(out, plans)
+		return nil
+	}
+
+	fmt.Printf("⚠ This will drop ALL %d table(s) in the database and re-run %d migration(s).\n", len(tables), len(migrations))
+	if !c.force {
+		if !confirm("Are you sure you want to proceed?") {
+			fmt.Println("Fresh migration cancelled")
+			return nil
+		}
+		fmt.Println()
+	}
+
+	if err := FreshMigrations(db, migrations); err != nil {
+		return fmt.Errorf("fresh migration failed: %w", err)
+	}
+
+	fmt.Println("✓ Database recreated and all migrations completed successfully")
+	return nil
+}
+
 // ============================================================================
 // MakeMigrationCommand - Create a new migration file
 // ============================================================================
@@ -189,6 +312,7 @@ func (c *MigrateRollbackCommand) Execute(ctx *CommandContext) error {
 type MakeMigrationCommand struct {
 	dir string
 	pkg string
+	sql bool
 }
 
 func (c *MakeMigrationCommand) Name() string {
@@ -202,6 +326,7 @@ func (c *MakeMigrationCommand) Description() string {
 func (c *MakeMigrationCommand) DefineFlags(fs *flag.FlagSet) {
 	fs.StringVar(&c.dir, "dir", "migrations", "Directory to store migration files")
 	fs.StringVar(&c.pkg, "pkg", "", "Go package name (default: directory name)")
+	fs.BoolVar(&c.sql, "sql", false, "Create .up.sql/.down.sql files for LoadSQLMigrations instead of a Go file")
 }
 
 func (c *MakeMigrationCommand) Execute(ctx *CommandContext) error {
@@ -230,6 +355,10 @@ func (c *MakeMigrationCommand) Execute(ctx *CommandContext) error {
 	timestamp := time.Now()
 	version := timestamp.Format("20060102150405")
 
+	if c.sql {
+		return c.writeSQLFiles(version, name)
+	}
+
 	// Construct filename: YYYYMMDDHHMMSS_name.go
 	filename := fmt.Sprintf("%s_%s.go", version, name)
 	filepath := filepath.Join(c.dir, filename)
@@ -270,6 +399,29 @@ func (c *MakeMigrationCommand) Execute(ctx *CommandContext) error {
 	return nil
 }
 
+// writeSQLFiles creates the <version>_<name>.up.sql and .down.sql pair read by LoadSQLMigrations.
+func (c *MakeMigrationCommand) writeSQLFiles(version, name string) error {
+	files := []struct {
+		suffix  string
+		content string
+	}{
+		{"up", "-- Write your migration SQL here\n"},
+		{"down", "-- Write your rollback SQL here\n"},
+	}
+	for _, file := range files {
+		path := filepath.Join(c.dir, fmt.Sprintf("%s_%s.%s.sql", version, name, file.suffix))
+		if err := os.WriteFile(path, []byte(file.content), 0644); err != nil {
+			return fmt.Errorf("failed to create file: %w", err)
+		}
+		fmt.Printf("✓ Migration created: %s\n", path)
+	}
+
+	fmt.Printf("  Version: %s\n", version)
+	fmt.Println("\nLoad the SQL files with dim.LoadSQLMigrations or dim.RegisterSQLMigrations, e.g.:")
+	fmt.Printf("  //go:embed %s/*.sql\n", filepath.ToSlash(c.dir))
+	return nil
+}
+
 type migrationTemplateData struct {
 	Package   string
 	Version   string
@@ -318,54 +470,39 @@ func Down{{.FuncName}}(db dim.Database) error {
 `
 
 // ============================================================================
-// MigrateListCommand - List migration status
+// MigrateStatusCommand - Show migration status
 // ============================================================================
 
-// MigrateListCommand menampilkan status semua migrations (applied dan pending).
-type MigrateListCommand struct{}
+// MigrateStatusCommand menampilkan status semua migrations (applied, pending, modified, missing).
+type MigrateStatusCommand struct{}
 
-func (c *MigrateListCommand) Name() string {
-	return "migrate:list"
+func (c *MigrateStatusCommand) Name() string {
+	return "migrate:status"
 }
 
-func (c *MigrateListCommand) Description() string {
+func (c *MigrateStatusCommand) Description() string {
 	return "Show migration status"
 }
 
-func (c *MigrateListCommand) Execute(ctx *CommandContext) error {
+func (c *MigrateStatusCommand) Execute(ctx *CommandContext) error {
 	if ctx.DB == nil {
 		return fmt.Errorf("database connection required")
 	}
 
 	db := migrationConn(ctx)
+	out := commandOut(ctx)
 
-	// Get all framework migrations
-	frameworkMigrations := GetFrameworkMigrations()
-	// Add registered migrations
-	frameworkMigrations = append(frameworkMigrations, GetRegisteredMigrations()...)
-
-	// Get applied migrations from database
-	appliedMap := make(map[int64]time.Time)
-	query := `SELECT version, applied_at FROM migrations ORDER BY version`
-	rows, err := db.Query(context.Background(), query)
+	states, err := MigrationStatus(db, allMigrations())
 	if err != nil {
-		// Table might not exist yet
-		fmt.Println("⚠ Migrations table does not exist yet. Run 'migrate' first.")
-	} else {
-		defer rows.Close()
-		for rows.Next() {
-			var version int64
-			var appliedAt time.Time
-			if err := rows.Scan(&version, &appliedAt); err != nil {
-				return err
-			}
-			appliedMap[version] = appliedAt
-		}
+		return err
+	}
+	if !migrationsTableExists(db) {
+		fmt.Fprintln(out, "⚠ Migrations table does not exist yet. Run 'migrate' first.")
 	}
 
 	// Display header with 80-column friendly layout
-	fmt.Println("Migration Status:")
-	fmt.Println()
+	fmt.Fprintln(out, "Migration Status:")
+	fmt.Fprintln(out)
 
 	// Column widths (total ~78 chars with spacing)
 	const (
@@ -378,33 +515,58 @@ func (c *MigrateListCommand) Execute(ctx *CommandContext) error {
 	// Calculate separator width
 	separatorWidth := versionWidth + nameWidth + statusWidth + dateWidth + 6 // 6 for spacing
 
-	fmt.Printf("%-*s %-*s %-*s %s\n", versionWidth, "Version", nameWidth, "Name", statusWidth, "Status", "Applied At")
-	fmt.Println(strings.Repeat("-", separatorWidth))
+	fmt.Fprintf(out, "%-*s %-*s %-*s %s\n", versionWidth, "Version", nameWidth, "Name", statusWidth, "Status", "Applied At")
+	fmt.Fprintln(out, strings.Repeat("-", separatorWidth))
 
-	// Display each migration
-	for _, migration := range frameworkMigrations {
-		status := "Pending"
-		appliedAt := "-"
+	counts := make(map[string]int)
+	for _, state := range states {
+		counts[state.Status]++
 
-		if t, applied := appliedMap[migration.Version]; applied {
-			status = "Applied"
-			appliedAt = t.Format("2006-01-02 15:04:05")
+		appliedAt := "-"
+		if !state.AppliedAt.IsZero() {
+			appliedAt = state.AppliedAt.Format("2006-01-02 15:04:05")
 		}
 
 		// Truncate name if too long
-		name := migration.Name
+		name := state.Name
 		if len(name) > nameWidth {
 			name = name[:nameWidth-3] + "..."
 		}
 
-		fmt.Printf("%-*d %-*s %-*s %s\n", versionWidth, migration.Version, nameWidth, name, statusWidth, status, appliedAt)
+		status := strings.ToUpper(state.Status[:1]) + state.Status[1:]
+		fmt.Fprintf(out, "%-*d %-*s %-*s %s\n", versionWidth, state.Version, nameWidth, name, statusWidth, status, appliedAt)
 	}
 
 	// Summary
-	appliedCount := len(appliedMap)
-	pendingCount := len(frameworkMigrations) - appliedCount
-	fmt.Println()
-	fmt.Printf("Total: %d | Applied: %d | Pending: %d\n", len(frameworkMigrations), appliedCount, pendingCount)
+	fmt.Fprintln(out)
+	fmt.Fprintf(out, "Total: %d | Applied: %d | Pending: %d", len(states),
+		counts[MigrationStatusApplied]+counts[MigrationStatusModified]+counts[MigrationStatusMissing], counts[MigrationStatusPending])
+	if counts[MigrationStatusModified] > 0 {
+		fmt.Fprintf(out, " | Modified: %d", counts[MigrationStatusModified])
+	}
+	if counts[MigrationStatusMissing] > 0 {
+		fmt.Fprintf(out, " | Missing: %d", counts[MigrationStatusMissing])
+	}
+	fmt.Fprintln(out)
 
 	return nil
 }
+
+// ============================================================================
+// MigrateListCommand - Alias of migrate:status
+// ============================================================================
+
+// MigrateListCommand adalah alias migrate:status yang dipertahankan untuk kompatibilitas.
+type MigrateListCommand struct{}
+
+func (c *MigrateListCommand) Name() string {
+	return "migrate:list"
+}
+
+func (c *MigrateListCommand) Description() string {
+	return "Show migration status (alias of migrate:status)"
+}
+
+func (c *MigrateListCommand) Execute(ctx *CommandContext) error {
+	return (&MigrateStatusCommand{}).Execute(ctx)
+}
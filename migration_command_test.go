@@ -1,7 +1,10 @@
 package dim
 
 import (
+	"bytes"
+	"context"
 	"flag"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -396,3 +399,137 @@ func TestMakeMigrationCommand_Execute_GeneratesValidFile(t *testing.T) {
 		t.Error("Generated file contains deprecated pgxpool import")
 	}
 }
+
+// ============================================================================
+// migrate:status, migrate:fresh, -to and -dry-run Tests
+// ============================================================================
+
+// withTestMigrations mengganti registry global dengan migrations dan menonaktifkan migrasi framework.
+func withTestMigrations(t *testing.T, migrations ...Migration) {
+	t.Helper()
+	oldRegistry, oldFramework := migrationRegistry, includeFrameworkMigrations
+	migrationRegistry, includeFrameworkMigrations = migrations, false
+	t.Cleanup(func() {
+		migrationRegistry, includeFrameworkMigrations = oldRegistry, oldFramework
+	})
+}
+
+func TestMigrateRollbackCommand_ToFlag(t *testing.T) {
+	cmd := &MigrateRollbackCommand{}
+	fs := flag.NewFlagSet("migrate:rollback", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	cmd.DefineFlags(fs)
+
+	if err := fs.Parse([]string{"-to", "20240101000000", "-dry-run"}); err != nil {
+		t.Fatal(err)
+	}
+	if cmd.to == nil || *cmd.to != 20240101000000 || !cmd.dryRun {
+		t.Errorf("to = %v, dryRun = %v", cmd.to, cmd.dryRun)
+	}
+	if err := fs.Parse([]string{"-to", "latest"}); err == nil {
+		t.Error("expected error for non-numeric -to")
+	}
+}
+
+func TestMigrateRollbackCommand_ToVersion(t *testing.T) {
+	withTestMigrations(t, testTableMigration(1, "notes"), testTableMigration(2, "tags"), testTableMigration(3, "links"))
+	db := newTestSQLiteDB(t)
+	if err := RunMigrations(db, GetRegisteredMigrations()); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	to := int64(1)
+	dryRun := &MigrateRollbackCommand{to: &to, dryRun: true}
+	if err := dryRun.Execute(&CommandContext{DB: db, Out: &out}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "DROP TABLE links;") || !strings.Contains(out.String(), "DROP TABLE tags;") || !tableExists(t, db, "links") {
+		t.Errorf("dry-run output = %q", out.String())
+	}
+
+	cmd := &MigrateRollbackCommand{to: &to, force: true}
+	if err := cmd.Execute(&CommandContext{DB: db}); err != nil {
+		t.Fatal(err)
+	}
+	if !tableExists(t, db, "notes") || tableExists(t, db, "tags") || tableExists(t, db, "links") {
+		t.Error("unexpected tables after migrate:rollback -to 1")
+	}
+
+	step := &MigrateRollbackCommand{steps: 1, force: true}
+	if err := step.Execute(&CommandContext{DB: db}); err != nil {
+		t.Fatal(err)
+	}
+	if tableExists(t, db, "notes") {
+		t.Error("notes still exists after migrate:rollback -step 1")
+	}
+}
+
+func TestMigrateCommand_DryRun(t *testing.T) {
+	withTestMigrations(t, testTableMigration(1, "notes"))
+	db := newTestSQLiteDB(t)
+
+	var out bytes.Buffer
+	cmd := &MigrateCommand{dryRun: true}
+	if err := cmd.Execute(&CommandContext{DB: db, Out: &out}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "-- [up] 1 create_notes\nCREATE TABLE notes (id TEXT PRIMARY KEY);") {
+		t.Errorf("output = %q", out.String())
+	}
+	if tableExists(t, db, "notes") || tableExists(t, db, "migrations") {
+		t.Error("dry run touched the database")
+	}
+}
+
+func TestMigrateStatusCommand(t *testing.T) {
+	withTestMigrations(t, testTableMigration(1, "notes"), testTableMigration(2, "tags"))
+	db := newTestSQLiteDB(t)
+	if err := RunMigrations(db, GetRegisteredMigrations()[:1]); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, cmd := range []Command{&MigrateStatusCommand{}, &MigrateListCommand{}} {
+		var out bytes.Buffer
+		if err := cmd.Execute(&CommandContext{DB: db, Out: &out}); err != nil {
+			t.Fatalf("%s: %v", cmd.Name(), err)
+		}
+		got := out.String()
+		if !strings.Contains(got, "create_notes") || !strings.Contains(got, "Applied") || !strings.Contains(got, "Total: 2 | Applied: 1 | Pending: 1") {
+			t.Errorf("%s output = %q", cmd.Name(), got)
+		}
+	}
+}
+
+func TestMigrateFreshCommand(t *testing.T) {
+	cmd := &MigrateFreshCommand{}
+	if cmd.Name() != "migrate:fresh" {
+		t.Errorf("Expected name 'migrate:fresh', got '%s'", cmd.Name())
+	}
+	if err := cmd.Execute(&CommandContext{}); err == nil || err.Error() != "database connection required" {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	withTestMigrations(t, testTableMigration(1, "notes"))
+	db := newTestSQLiteDB(t)
+	if err := db.Exec(context.Background(), "CREATE TABLE leftovers (id TEXT)"); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	dryRun := &MigrateFreshCommand{dryRun: true}
+	if err := dryRun.Execute(&CommandContext{DB: db, Out: &out}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), `DROP TABLE IF EXISTS "leftovers";`) || !tableExists(t, db, "leftovers") {
+		t.Errorf("dry-run output = %q", out.String())
+	}
+
+	fresh := &MigrateFreshCommand{force: true}
+	if err := fresh.Execute(&CommandContext{DB: db}); err != nil {
+		t.Fatal(err)
+	}
+	if tableExists(t, db, "leftovers") || !tableExists(t, db, "notes") {
+		t.Error("unexpected tables after migrate:fresh")
+	}
+}
@@ -0,0 +1,201 @@
+package dim
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// MigrationPlan adalah SQL yang akan dijalankan satu migration, hasil PlanMigrations atau PlanRollback.
+type MigrationPlan struct {
+	Version int64
+	Name    string
+	// Direction bernilai "up" atau "down".
+	Direction  string
+	Statements []string
+}
+
+// PlanMigrations menjalankan Up setiap pending migration terhadap database perekam: statement Exec
+// dicatat tanpa dijalankan, sedangkan query baca (SELECT) diteruskan ke db agar migration yang
+// memeriksa skema tetap berjalan. Query tulis melalui Query/QueryRow (misal INSERT ... RETURNING)
+// tidak dapat disimulasikan dan membuat plan gagal.
+//
+// Parameters:
+//   - db: Database instance (tidak diubah)
+//   - migrations: semua migration yang dikenal aplikasi
+//
+// Returns:
+//   - []MigrationPlan: statement setiap pending migration, berurutan
+//   - error: checksum mismatch atau error dari Up
+//
+// Example:
+//
+//	plans, err := dim.PlanMigrations(db, migrations)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	dim.WriteMigrationPlan(os.Stdout, plans)
+func PlanMigrations(db Database, migrations []Migration) ([]MigrationPlan, error) {
+	applied, err := appliedMigrationsIfExists(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get applied migrations: %w", err)
+	}
+	for _, migration := range migrations {
+		if history, ok := applied[migration.Version]; ok {
+			if err := checkMigrationChecksum(migration, history); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var plans []MigrationPlan
+	for _, migration := range migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+		plan, err := planMigration(db, migration, "up", migration.Up)
+		if err != nil {
+			return nil, err
+		}
+		plans = append(plans, plan)
+	}
+	return plans, nil
+}
+
+// PlanRollback seperti PlanMigrations, tetapi mencatat statement Down dari migrations
+// sesuai urutan slice (misal hasil AppliedMigrationsAfter).
+//
+// Parameters:
+//   - db: Database instance (tidak diubah)
+//   - migrations: migration yang akan di-rollback, berurutan
+//
+// Returns:
+//   - []MigrationPlan: statement Down setiap migration
+//   - error: error dari Down
+func PlanRollback(db Database, migrations []Migration) ([]MigrationPlan, error) {
+	plans := make([]MigrationPlan, 0, len(migrations))
+	for _, migration := range migrations {
+		plan, err := planMigration(db, migration, "down", migration.Down)
+		if err != nil {
+			return nil, err
+		}
+		plans = append(plans, plan)
+	}
+	return plans, nil
+}
+
+// WriteMigrationPlan menulis plan sebagai SQL yang dapat dibaca (dan dijalankan manual), dengan
+// komentar berisi versi dan nama setiap migration.
+//
+// Parameters:
+//   - w: tujuan output
+//   - plans: hasil PlanMigrations atau PlanRollback
+func WriteMigrationPlan(w io.Writer, plans []MigrationPlan) {
+	for _, plan := range plans {
+		fmt.Fprintf(w, "-- [%s] %d %s\n", plan.Direction, plan.Version, plan.Name)
+		if len(plan.Statements) == 0 {
+			fmt.Fprintln(w, "-- (no SQL statements)")
+		}
+		for _, stmt := range plan.Statements {
+			fmt.Fprintln(w, stmt)
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+// planMigration runs one Up or Down function against a recording database.
+func planMigration(db Database, migration Migration, direction string, fn func(Database) error) (MigrationPlan, error) {
+	plan := MigrationPlan{Version: migration.Version, Name: migration.Name, Direction: direction}
+	if fn == nil {
+		return plan, fmt.Errorf("migration %d (%s) has no %s function", migration.Version, migration.Name, direction)
+	}
+	recorder := &dryRunDatabase{db: db}
+	if err := fn(recorder); err != nil {
+		return plan, fmt.Errorf("migration %d (%s) failed: %w", migration.Version, migration.Name, err)
+	}
+	plan.Statements = recorder.statements
+	return plan, nil
+}
+
+// dryRunDatabase records writes instead of executing them and passes reads through to db.
+type dryRunDatabase struct {
+	db         Database
+	statements []string
+}
+
+// record stores a statement, terminated with ";" and followed by its arguments as a comment.
+func (d *dryRunDatabase) record(query string, args []interface{}) {
+	stmt := strings.TrimSpace(query)
+	if !strings.HasSuffix(stmt, ";") {
+		stmt += ";"
+	}
+	if len(args) > 0 {
+		stmt += fmt.Sprintf(" -- args: %v", args)
+	}
+	d.statements = append(d.statements, stmt)
+}
+
+func (d *dryRunDatabase) Exec(ctx context.Context, query string, args ...interface{}) error {
+	d.record(query, args)
+	return nil
+}
+
+func (d *dryRunDatabase) Query(ctx context.Context, query string, args ...interface{}) (Rows, error) {
+	if !IsSafeRead(query) {
+		return nil, fmt.Errorf("dry-run cannot simulate write query: %s", strings.TrimSpace(query))
+	}
+	return d.db.Query(ctx, query, args...)
+}
+
+func (d *dryRunDatabase) QueryRow(ctx context.Context, query string, args ...interface{}) Row {
+	if !IsSafeRead(query) {
+		return errRow{err: fmt.Errorf("dry-run cannot simulate write query: %s", strings.TrimSpace(query))}
+	}
+	return d.db.QueryRow(ctx, query, args...)
+}
+
+func (d *dryRunDatabase) Begin(ctx context.Context) (Tx, error) {
+	return &dryRunTx{d: d}, nil
+}
+
+func (d *dryRunDatabase) WithTx(ctx context.Context, fn TransactionFunc) error {
+	return fn(ctx, &dryRunTx{d: d})
+}
+
+func (d *dryRunDatabase) Close() error {
+	return nil
+}
+
+func (d *dryRunDatabase) DriverName() string {
+	return d.db.DriverName()
+}
+
+func (d *dryRunDatabase) Rebind(query string) string {
+	return d.db.Rebind(query)
+}
+
+// dryRunTx is the transaction handed out by dryRunDatabase; commit and rollback are no-ops.
+type dryRunTx struct {
+	d *dryRunDatabase
+}
+
+func (t *dryRunTx) Exec(ctx context.Context, query string, args ...interface{}) error {
+	return t.d.Exec(ctx, query, args...)
+}
+
+func (t *dryRunTx) Query(ctx context.Context, query string, args ...interface{}) (Rows, error) {
+	return t.d.Query(ctx, query, args...)
+}
+
+func (t *dryRunTx) QueryRow(ctx context.Context, query string, args ...interface{}) Row {
+	return t.d.QueryRow(ctx, query, args...)
+}
+
+func (t *dryRunTx) Commit(ctx context.Context) error {
+	return nil
+}
+
+func (t *dryRunTx) Rollback(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,89 @@
+package dim
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestPlanMigrations(t *testing.T) {
+	db := newTestSQLiteDB(t)
+	migrations := []Migration{
+		testTableMigration(1, "notes"),
+		{
+			Version: 2,
+			Name:    "seed_notes",
+			Up: func(db Database) error {
+				var count int
+				// Query baca tetap diteruskan ke database
+				if err := db.QueryRow(context.Background(), "SELECT COUNT(*) FROM sqlite_master").Scan(&count); err != nil {
+					return err
+				}
+				return db.WithTx(context.Background(), func(ctx context.Context, tx Tx) error {
+					return tx.Exec(ctx, "INSERT INTO notes (id) VALUES (?)", "n-1")
+				})
+			},
+		},
+	}
+	if err := RunMigrations(db, migrations[:1]); err != nil {
+		t.Fatal(err)
+	}
+
+	plans, err := PlanMigrations(db, migrations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plans) != 1 || plans[0].Version != 2 || plans[0].Direction != "up" {
+		t.Fatalf("plans = %+v", plans)
+	}
+	if got := plans[0].Statements; len(got) != 1 || got[0] != "INSERT INTO notes (id) VALUES (?); -- args: [n-1]" {
+		t.Errorf("statements = %q", got)
+	}
+
+	var count int
+	if err := db.QueryRow(context.Background(), "SELECT COUNT(*) FROM notes").Scan(&count); err != nil || count != 0 {
+		t.Errorf("dry run wrote rows: %d, %v", count, err)
+	}
+
+	var out bytes.Buffer
+	WriteMigrationPlan(&out, plans)
+	if !strings.Contains(out.String(), "-- [up] 2 seed_notes\nINSERT INTO notes") {
+		t.Errorf("output = %q", out.String())
+	}
+}
+
+func TestPlanMigrations_RejectsWriteQueries(t *testing.T) {
+	db := newTestSQLiteDB(t)
+	migrations := []Migration{{
+		Version: 1,
+		Name:    "returning",
+		Up: func(db Database) error {
+			var id string
+			return db.QueryRow(context.Background(), "INSERT INTO notes (id) VALUES ('x') RETURNING id").Scan(&id)
+		},
+	}}
+
+	if _, err := PlanMigrations(db, migrations); err == nil || !strings.Contains(err.Error(), "dry-run cannot simulate write query") {
+		t.Errorf("error = %v", err)
+	}
+}
+
+func TestPlanRollback(t *testing.T) {
+	db := newTestSQLiteDB(t)
+	migrations := []Migration{testTableMigration(1, "notes"), testTableMigration(2, "tags")}
+	if err := RunMigrations(db, migrations); err != nil {
+		t.Fatal(err)
+	}
+
+	plans, err := PlanRollback(db, []Migration{migrations[1], migrations[0]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plans) != 2 || plans[0].Statements[0] != "DROP TABLE tags;" || plans[1].Direction != "down" {
+		t.Errorf("plans = %+v", plans)
+	}
+	if !tableExists(t, db, "tags") {
+		t.Error("dry run dropped table")
+	}
+}
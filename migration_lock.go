@@ -0,0 +1,160 @@
+package dim
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+)
+
+// migrationLockTable menyimpan satu baris (id = 1) selama ada runner migration yang aktif.
+const migrationLockTable = "migrations_lock"
+
+// ErrMigrationLocked dikembalikan jika migration lock masih dipegang runner lain setelah
+// MigrationLockTimeout berlalu.
+var ErrMigrationLocked = errors.New("migration lock is held by another runner")
+
+// MigrationLockTimeout adalah lama RunMigrations, RollbackMigrations, dan FreshMigrations menunggu
+// runner lain (misal replica lain yang start bersamaan) melepas migration lock.
+var MigrationLockTimeout = 5 * time.Minute
+
+// MigrationLockStaleAfter adalah umur lock tanpa heartbeat yang dianggap milik runner yang crash.
+// Lock yang lebih tua dari ini diambil alih. Runner yang aktif memperbarui lock secara berkala,
+// sehingga migration yang berjalan lama tidak dianggap stale.
+var MigrationLockStaleAfter = 2 * time.Minute
+
+// migrationLockPoll is the wait between attempts while another runner holds the lock.
+var migrationLockPoll = time.Second
+
+// withMigrationLock runs fn while holding the migration lock.
+func withMigrationLock(db Database, fn func() error) error {
+	release, err := acquireMigrationLock(db)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return fn()
+}
+
+// acquireMigrationLock inserts the lock row, waiting for other runners and taking over stale locks.
+// The lock is a plain table row rather than an advisory lock so it works the same on every driver
+// and does not depend on the pool handing back the same connection for unlock.
+func acquireMigrationLock(db Database) (func(), error) {
+	ctx := context.Background()
+	if err := ensureMigrationLockTable(db); err != nil {
+		return nil, fmt.Errorf("failed to ensure migration lock table: %w", err)
+	}
+
+	owner := migrationLockOwner()
+	deadline := time.Now().Add(MigrationLockTimeout)
+	insert := db.Rebind("INSERT INTO " + migrationLockTable + " (id, owner, locked_at) VALUES (1, $1, $2)")
+
+	for {
+		insertErr := db.Exec(ctx, insert, owner, time.Now().UTC())
+		if insertErr == nil {
+			break
+		}
+
+		var holder string
+		var lockedAt time.Time
+		err := db.QueryRow(ctx, "SELECT owner, locked_at FROM "+migrationLockTable+" WHERE id = 1").Scan(&holder, &lockedAt)
+		if isNoRows(err) {
+			// Released between our insert and select
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to acquire migration lock: %w", insertErr)
+		}
+
+		if time.Since(lockedAt) > MigrationLockStaleAfter {
+			slog.Warn("taking over stale migration lock", "owner", holder, "locked_at", lockedAt)
+			if err := db.Exec(ctx, db.Rebind("DELETE FROM "+migrationLockTable+" WHERE id = 1 AND owner = $1"), holder); err != nil {
+				return nil, fmt.Errorf("failed to remove stale migration lock: %w", err)
+			}
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("%w: held by %s since %s", ErrMigrationLocked, holder, lockedAt.Format(time.RFC3339))
+		}
+
+		slog.Info("waiting for migration lock", "owner", holder)
+		time.Sleep(migrationLockPoll)
+	}
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go migrationLockHeartbeat(db, owner, stop, done)
+
+	return func() {
+		close(stop)
+		<-done
+		if err := db.Exec(ctx, db.Rebind("DELETE FROM "+migrationLockTable+" WHERE id = 1 AND owner = $1"), owner); err != nil {
+			slog.Error("failed to release migration lock", "error", err)
+		}
+	}, nil
+}
+
+// migrationLockHeartbeat refreshes locked_at so a long migration is not mistaken for a stale lock.
+func migrationLockHeartbeat(db Database, owner string, stop, done chan struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(MigrationLockStaleAfter / 4)
+	defer ticker.Stop()
+
+	query := db.Rebind("UPDATE " + migrationLockTable + " SET locked_at = $1 WHERE id = 1 AND owner = $2")
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if err := db.Exec(context.Background(), query, time.Now().UTC(), owner); err != nil {
+				slog.Warn("failed to refresh migration lock", "error", err)
+			}
+		}
+	}
+}
+
+// ensureMigrationLockTable creates the migration lock table
+func ensureMigrationLockTable(db Database) error {
+	var query string
+	if db.DriverName() == "sqlite" {
+		query = `
+			CREATE TABLE IF NOT EXISTS migrations_lock (
+				id INTEGER PRIMARY KEY,
+				owner TEXT NOT NULL,
+				locked_at TIMESTAMP NOT NULL
+			)
+		`
+	} else if db.DriverName() == "mysql" {
+		query = `
+			CREATE TABLE IF NOT EXISTS migrations_lock (
+				id INT PRIMARY KEY,
+				owner VARCHAR(255) NOT NULL,
+				locked_at DATETIME NOT NULL
+			) ENGINE=InnoDB
+		`
+	} else {
+		query = `
+			CREATE TABLE IF NOT EXISTS migrations_lock (
+				id INTEGER PRIMARY KEY,
+				owner VARCHAR(255) NOT NULL,
+				locked_at TIMESTAMP NOT NULL
+			)
+		`
+	}
+	return db.Exec(context.Background(), query)
+}
+
+// migrationLockOwner identifies this runner in the lock row (hostname, pid and a random suffix).
+func migrationLockOwner() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	suffix := make([]byte, 4)
+	rand.Read(suffix)
+	return fmt.Sprintf("%s:%d:%s", host, os.Getpid(), hex.EncodeToString(suffix))
+}
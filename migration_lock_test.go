@@ -0,0 +1,62 @@
+package dim
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func withMigrationLockTimings(t *testing.T, timeout, staleAfter time.Duration) {
+	t.Helper()
+	oldTimeout, oldStale, oldPoll := MigrationLockTimeout, MigrationLockStaleAfter, migrationLockPoll
+	MigrationLockTimeout, MigrationLockStaleAfter, migrationLockPoll = timeout, staleAfter, 10*time.Millisecond
+	t.Cleanup(func() {
+		MigrationLockTimeout, MigrationLockStaleAfter, migrationLockPoll = oldTimeout, oldStale, oldPoll
+	})
+}
+
+func TestMigrationLock_BlocksConcurrentRunner(t *testing.T) {
+	withMigrationLockTimings(t, 50*time.Millisecond, time.Hour)
+	db := newTestSQLiteDB(t)
+
+	release, err := acquireMigrationLock(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = RunMigrations(db, []Migration{testTableMigration(1, "notes")})
+	if !errors.Is(err, ErrMigrationLocked) {
+		t.Fatalf("RunMigrations while locked error = %v, want ErrMigrationLocked", err)
+	}
+	if tableExists(t, db, "notes") {
+		t.Error("migration ran while lock was held")
+	}
+
+	release()
+	if err := RunMigrations(db, []Migration{testTableMigration(1, "notes")}); err != nil {
+		t.Fatalf("RunMigrations after release: %v", err)
+	}
+}
+
+func TestMigrationLock_TakesOverStaleLock(t *testing.T) {
+	withMigrationLockTimings(t, time.Second, time.Minute)
+	db := newTestSQLiteDB(t)
+	if err := ensureMigrationLockTable(db); err != nil {
+		t.Fatal(err)
+	}
+	// Lock dari runner yang crash satu jam lalu
+	err := db.Exec(context.Background(), "INSERT INTO migrations_lock (id, owner, locked_at) VALUES (1, 'crashed', ?)", time.Now().UTC().Add(-time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := RunMigrations(db, []Migration{testTableMigration(1, "notes")}); err != nil {
+		t.Fatalf("RunMigrations with stale lock: %v", err)
+	}
+
+	var count int
+	if err := db.QueryRow(context.Background(), "SELECT COUNT(*) FROM migrations_lock").Scan(&count); err != nil || count != 0 {
+		t.Errorf("lock rows after run = %d, %v", count, err)
+	}
+}
@@ -0,0 +1,150 @@
+package dim
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// sqlMigrationFile mencocokkan nama file migration SQL: <version>_<name>.up.sql atau .down.sql.
+var sqlMigrationFile = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// LoadSQLMigrations membaca migration berbasis file SQL dari fsys (biasanya embed.FS).
+// Setiap migration terdiri dari <version>_<name>.up.sql dan (opsional) <version>_<name>.down.sql,
+// misal 0001_create_users.up.sql. Isi file dijalankan dengan satu Exec, sehingga file boleh
+// berisi beberapa statement. Checksum SHA-256 file up disimpan di tabel migrations agar
+// RunMigrations dapat mendeteksi migration yang diedit setelah dijalankan.
+//
+// Parameters:
+//   - fsys: file system berisi file migration
+//   - dir: direktori di dalam fsys, misal "migrations" (gunakan "." untuk root)
+//
+// Returns:
+//   - []Migration: migration yang diurutkan berdasarkan Version
+//   - error: error jika direktori tidak dapat dibaca, nama file tidak valid, versi duplikat,
+//     atau file up tidak ada
+//
+// Example:
+//
+//	//go:embed migrations/*.sql
+//	var migrationFiles embed.FS
+//
+//	migrations, err := dim.LoadSQLMigrations(migrationFiles, "migrations")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	err = dim.RunMigrations(db, migrations)
+func LoadSQLMigrations(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migration directory %s: %w", dir, err)
+	}
+
+	type sqlPair struct {
+		name     string
+		up, down string
+		hasUp    bool
+		hasDown  bool
+	}
+	pairs := make(map[int64]*sqlPair)
+
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".sql" {
+			continue
+		}
+		match := sqlMigrationFile.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name %s: expected <version>_<name>.up.sql or .down.sql", entry.Name())
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %s: %w", entry.Name(), err)
+		}
+
+		content, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", entry.Name(), err)
+		}
+
+		pair, ok := pairs[version]
+		if !ok {
+			pair = &sqlPair{name: match[2]}
+			pairs[version] = pair
+		} else if pair.name != match[2] {
+			return nil, fmt.Errorf("duplicate migration version %d: %s and %s", version, pair.name, match[2])
+		}
+
+		if match[3] == "up" {
+			pair.up, pair.hasUp = string(content), true
+		} else {
+			pair.down, pair.hasDown = string(content), true
+		}
+	}
+
+	migrations := make([]Migration, 0, len(pairs))
+	for version, pair := range pairs {
+		if !pair.hasUp {
+			return nil, fmt.Errorf("migration %d (%s) has no .up.sql file", version, pair.name)
+		}
+		migrations = append(migrations, sqlMigration(version, pair.name, pair.up, pair.down, pair.hasDown))
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// RegisterSQLMigrations memuat migration SQL dari fsys dan mendaftarkannya ke registry global,
+// sehingga dijalankan oleh command migrate bersama migration Go.
+//
+// Parameters:
+//   - fsys: file system berisi file migration
+//   - dir: direktori di dalam fsys
+//
+// Returns:
+//   - error: error dari LoadSQLMigrations
+func RegisterSQLMigrations(fsys fs.FS, dir string) error {
+	migrations, err := LoadSQLMigrations(fsys, dir)
+	if err != nil {
+		return err
+	}
+	for _, m := range migrations {
+		Register(m)
+	}
+	return nil
+}
+
+// sqlMigration membuat Migration yang menjalankan isi file SQL.
+func sqlMigration(version int64, name, up, down string, hasDown bool) Migration {
+	m := Migration{
+		Version:  version,
+		Name:     name,
+		Checksum: MigrationChecksum(up),
+		Up: func(db Database) error {
+			return db.Exec(context.Background(), up)
+		},
+	}
+	if hasDown {
+		m.Down = func(db Database) error {
+			return db.Exec(context.Background(), down)
+		}
+	} else {
+		m.Down = func(db Database) error {
+			return fmt.Errorf("migration %d (%s) has no .down.sql file", version, name)
+		}
+	}
+	return m
+}
+
+// MigrationChecksum menghitung checksum SHA-256 (hex) dari SQL migration.
+// Dapat dipakai untuk mengisi Migration.Checksum pada migration Go yang menjalankan SQL statis.
+func MigrationChecksum(sql string) string {
+	sum := sha256.Sum256([]byte(sql))
+	return hex.EncodeToString(sum[:])
+}
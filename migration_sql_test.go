@@ -0,0 +1,148 @@
+package dim
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func newTestSQLiteDB(t *testing.T) *SQLiteDatabase {
+	t.Helper()
+	db, err := NewSQLiteDatabase(DatabaseConfig{Database: ":memory:"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestLoadSQLMigrations(t *testing.T) {
+	fsys := fstest.MapFS{
+		"migrations/0002_add_posts.up.sql":      {Data: []byte("CREATE TABLE posts (id TEXT PRIMARY KEY);")},
+		"migrations/0001_create_users.up.sql":   {Data: []byte("CREATE TABLE app_users (id TEXT PRIMARY KEY);\nCREATE INDEX idx_app_users ON app_users (id);")},
+		"migrations/0001_create_users.down.sql": {Data: []byte("DROP TABLE app_users;")},
+		"migrations/README.md":                  {Data: []byte("ignored")},
+	}
+
+	migrations, err := LoadSQLMigrations(fsys, "migrations")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(migrations) != 2 || migrations[0].Version != 1 || migrations[0].Name != "create_users" || migrations[1].Version != 2 {
+		t.Fatalf("migrations = %+v", migrations)
+	}
+	if migrations[0].Checksum != MigrationChecksum(string(fsys["migrations/0001_create_users.up.sql"].Data)) {
+		t.Errorf("checksum = %q", migrations[0].Checksum)
+	}
+
+	db := newTestSQLiteDB(t)
+	if err := RunMigrations(db, migrations); err != nil {
+		t.Fatalf("RunMigrations: %v", err)
+	}
+	applied, err := getAppliedMigrations(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if applied[1].Checksum != migrations[0].Checksum {
+		t.Errorf("stored checksum = %q, want %q", applied[1].Checksum, migrations[0].Checksum)
+	}
+
+	if err := RollbackMigration(db, migrations[0]); err != nil {
+		t.Fatalf("RollbackMigration: %v", err)
+	}
+	if err := RollbackMigration(db, migrations[1]); err == nil || !strings.Contains(err.Error(), "no .down.sql") {
+		t.Errorf("rollback without down file error = %v", err)
+	}
+}
+
+func TestLoadSQLMigrations_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		fsys fstest.MapFS
+		want string
+	}{
+		{"invalid name", fstest.MapFS{"m/create_users.sql": {}}, "invalid migration file name"},
+		{"missing up", fstest.MapFS{"m/0001_create_users.down.sql": {}}, "has no .up.sql"},
+		{"duplicate version", fstest.MapFS{"m/0001_a.up.sql": {}, "m/0001_b.up.sql": {}}, "duplicate migration version 1"},
+		{"missing dir", fstest.MapFS{}, "failed to read migration directory"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadSQLMigrations(tt.fsys, "m")
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunMigrations_ChecksumMismatch(t *testing.T) {
+	db := newTestSQLiteDB(t)
+	load := func(sql string) []Migration {
+		migrations, err := LoadSQLMigrations(fstest.MapFS{"0001_create_notes.up.sql": {Data: []byte(sql)}}, ".")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return migrations
+	}
+
+	if err := RunMigrations(db, load("CREATE TABLE notes (id TEXT);")); err != nil {
+		t.Fatal(err)
+	}
+	if err := RunMigrations(db, load("CREATE TABLE notes (id TEXT);")); err != nil {
+		t.Errorf("unchanged migration rerun: %v", err)
+	}
+	err := RunMigrations(db, load("CREATE TABLE notes (id TEXT, body TEXT);"))
+	if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Errorf("edited migration error = %v, want checksum mismatch", err)
+	}
+}
+
+func TestEnsureMigrationsTable_AddsChecksumColumn(t *testing.T) {
+	db := newTestSQLiteDB(t)
+	ctx := context.Background()
+	// Tabel migrations dari versi lama tanpa kolom checksum
+	if err := db.Exec(ctx, "CREATE TABLE migrations (version INTEGER PRIMARY KEY, name TEXT NOT NULL, applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP)"); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Exec(ctx, "INSERT INTO migrations (version, name) VALUES (1, 'create_notes')"); err != nil {
+		t.Fatal(err)
+	}
+
+	migrations := []Migration{{
+		Version:  1,
+		Name:     "create_notes",
+		Up:       func(Database) error { return nil },
+		Checksum: MigrationChecksum("CREATE TABLE notes (id TEXT);"),
+	}}
+	if err := RunMigrations(db, migrations); err != nil {
+		t.Fatalf("RunMigrations on legacy table: %v", err)
+	}
+	applied, err := getAppliedMigrations(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if applied[1].Checksum != migrations[0].Checksum {
+		t.Errorf("backfilled checksum = %q, want %q", applied[1].Checksum, migrations[0].Checksum)
+	}
+}
+
+func TestMakeMigrationCommand_SQLFlag(t *testing.T) {
+	dir := t.TempDir()
+	cmd := &MakeMigrationCommand{dir: dir, sql: true}
+	if err := cmd.Execute(&CommandContext{Args: []string{"create_posts"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	ups, _ := filepath.Glob(filepath.Join(dir, "*_create_posts.up.sql"))
+	downs, _ := filepath.Glob(filepath.Join(dir, "*_create_posts.down.sql"))
+	if len(ups) != 1 || len(downs) != 1 {
+		t.Fatalf("generated files: up=%v down=%v", ups, downs)
+	}
+	if _, err := LoadSQLMigrations(os.DirFS(dir), "."); err != nil {
+		t.Errorf("generated files not loadable: %v", err)
+	}
+}
@@ -1,6 +1,8 @@
 package dim
 
 import (
+	"context"
+	"strings"
 	"testing"
 )
 
@@ -42,3 +44,126 @@ func TestFrameworkMigrationsToggle(t *testing.T) {
 		}
 	})
 }
+
+// testTableMigration membuat migration sederhana yang membuat (dan menghapus) satu tabel.
+func testTableMigration(version int64, table string) Migration {
+	return Migration{
+		Version: version,
+		Name:    "create_" + table,
+		Up: func(db Database) error {
+			return db.Exec(context.Background(), "CREATE TABLE "+table+" (id TEXT PRIMARY KEY)")
+		},
+		Down: func(db Database) error {
+			return db.Exec(context.Background(), "DROP TABLE "+table)
+		},
+	}
+}
+
+func tableExists(t *testing.T, db Database, table string) bool {
+	t.Helper()
+	var count int
+	err := db.QueryRow(context.Background(), "SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?", table).Scan(&count)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return count == 1
+}
+
+func TestMigrationStatus(t *testing.T) {
+	db := newTestSQLiteDB(t)
+	migrations := []Migration{testTableMigration(1, "notes"), testTableMigration(2, "tags")}
+
+	states, err := MigrationStatus(db, migrations)
+	if err != nil {
+		t.Fatalf("status before migrations table exists: %v", err)
+	}
+	if len(states) != 2 || states[0].Status != MigrationStatusPending || states[1].Status != MigrationStatusPending {
+		t.Fatalf("states = %+v", states)
+	}
+
+	if err := RunMigrations(db, migrations[:1]); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Exec(context.Background(), "INSERT INTO migrations (version, name, checksum) VALUES (3, 'removed', NULL)"); err != nil {
+		t.Fatal(err)
+	}
+	modified := testTableMigration(1, "notes")
+	if err := updateMigrationChecksum(db, Migration{Version: 1, Checksum: "old"}); err != nil {
+		t.Fatal(err)
+	}
+	modified.Checksum = "new"
+
+	states, err = MigrationStatus(db, []Migration{modified, migrations[1]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{MigrationStatusModified, MigrationStatusPending, MigrationStatusMissing}
+	for i, state := range states {
+		if state.Status != want[i] {
+			t.Errorf("states[%d] = %+v, want status %s", i, state, want[i])
+		}
+	}
+	if states[0].AppliedAt.IsZero() || !states[1].AppliedAt.IsZero() {
+		t.Errorf("applied_at = %v / %v", states[0].AppliedAt, states[1].AppliedAt)
+	}
+}
+
+func TestRollbackMigrations_ToVersion(t *testing.T) {
+	db := newTestSQLiteDB(t)
+	migrations := []Migration{testTableMigration(1, "notes"), testTableMigration(2, "tags"), testTableMigration(3, "links")}
+	if err := RunMigrations(db, migrations); err != nil {
+		t.Fatal(err)
+	}
+
+	toRollback, err := AppliedMigrationsAfter(db, migrations, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(toRollback) != 2 || toRollback[0].Version != 3 || toRollback[1].Version != 2 {
+		t.Fatalf("toRollback = %+v", toRollback)
+	}
+	if err := RollbackMigrations(db, toRollback); err != nil {
+		t.Fatal(err)
+	}
+	if !tableExists(t, db, "notes") || tableExists(t, db, "tags") || tableExists(t, db, "links") {
+		t.Error("unexpected tables after rollback to version 1")
+	}
+
+	if _, err := AppliedMigrationsAfter(db, migrations[1:], 0); err == nil || !strings.Contains(err.Error(), "not registered") {
+		t.Errorf("unregistered applied migration error = %v", err)
+	}
+}
+
+func TestFreshMigrations(t *testing.T) {
+	db := newTestSQLiteDB(t)
+	ctx := context.Background()
+	migrations := []Migration{testTableMigration(1, "notes")}
+	if err := RunMigrations(db, migrations); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Exec(ctx, "INSERT INTO notes (id) VALUES ('n-1')"); err != nil {
+		t.Fatal(err)
+	}
+	// Tabel di luar migration dengan foreign key ke notes ikut dihapus
+	if err := db.Exec(ctx, "CREATE TABLE comments (id TEXT, note_id TEXT REFERENCES notes (id))"); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Exec(ctx, "INSERT INTO comments (id, note_id) VALUES ('c-1', 'n-1')"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := FreshMigrations(db, migrations); err != nil {
+		t.Fatalf("FreshMigrations: %v", err)
+	}
+	if tableExists(t, db, "comments") || !tableExists(t, db, "notes") {
+		t.Error("unexpected tables after fresh")
+	}
+	var count int
+	if err := db.QueryRow(ctx, "SELECT COUNT(*) FROM notes").Scan(&count); err != nil || count != 0 {
+		t.Errorf("notes rows = %d, %v", count, err)
+	}
+	states, err := MigrationStatus(db, migrations)
+	if err != nil || states[0].Status != MigrationStatusApplied {
+		t.Errorf("states = %+v, %v", states, err)
+	}
+}
@@ -0,0 +1,456 @@
+package dim
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrOAuthAccountNotFound dikembalikan OAuthAccountStore jika akun provider belum ditautkan.
+	ErrOAuthAccountNotFound = errors.New("oauth account not found")
+	// ErrOAuthProviderNotFound dikembalikan jika nama provider tidak terdaftar di OAuthService.
+	ErrOAuthProviderNotFound = errors.New("oauth provider not found")
+	// ErrOAuthStateInvalid dikembalikan callback jika cookie state tidak ada, kadaluarsa,
+	// atau tidak cocok dengan parameter state (kemungkinan CSRF atau login dibuka di tab lain).
+	ErrOAuthStateInvalid = errors.New("oauth state is invalid or expired")
+)
+
+// OAuthStateCookie adalah nama cookie yang menyimpan state, PKCE verifier, dan nonce
+// selama redirect ke provider.
+const OAuthStateCookie = "dim_oauth_state"
+
+// OAuthAccount menautkan identitas provider (provider + subject) ke user.
+type OAuthAccount struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"user_id"`
+	Provider  string    `json:"provider"`
+	Subject   string    `json:"subject"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// OAuthAccountStore mendefinisikan penyimpanan tautan akun OAuth.
+// FindOAuthAccount mengembalikan ErrOAuthAccountNotFound (boleh di-wrap) jika tidak ada.
+type OAuthAccountStore interface {
+	FindOAuthAccount(ctx context.Context, provider, subject string) (*OAuthAccount, error)
+	SaveOAuthAccount(ctx context.Context, account *OAuthAccount) error // Insert atau update email
+	ListUserOAuthAccounts(ctx context.Context, userID string) ([]*OAuthAccount, error)
+}
+
+// OAuthUserCreator membuat user baru untuk identitas OAuth yang belum terhubung ke user mana pun.
+// Kembalikan error untuk menolak pendaftaran (misal domain email tidak diizinkan); gunakan
+// *AppError agar pesan dan status dikirim ke client.
+type OAuthUserCreator func(ctx context.Context, identity *OAuthIdentity) (Authenticatable, error)
+
+// OAuthLoginResult adalah hasil login OAuth yang berhasil.
+type OAuthLoginResult struct {
+	User         Authenticatable
+	Identity     *OAuthIdentity
+	AccessToken  string
+	RefreshToken string
+	// MFA diisi (dan token kosong) jika user sudah mengaktifkan MFA; lanjutkan dengan
+	// AuthService.VerifyMFA.
+	MFA *MFARequiredError
+	// Created bernilai true jika user baru dibuat oleh OAuthUserCreator.
+	Created bool
+	// Linked bernilai true jika akun provider baru saja ditautkan (termasuk saat Created).
+	Linked bool
+	// ReturnTo adalah path relatif dari parameter ?redirect= saat login dimulai (boleh kosong).
+	ReturnTo string
+}
+
+// OAuthService menambahkan login OAuth2 / OpenID Connect ke AuthService: registry provider,
+// state dan PKCE, callback yang menautkan atau membuat user, dan penerbitan token melalui
+// TokenManager milik AuthService (sama seperti Login biasa, termasuk ClaimsProvider).
+type OAuthService struct {
+	auth        *AuthService
+	accounts    OAuthAccountStore
+	signer      *PayloadSigner
+	redirectURL string
+	providers   map[string]*OAuthProvider
+	client      *http.Client
+	createUser  OAuthUserCreator
+	linkByEmail bool
+	stateTTL    time.Duration
+	onSuccess   func(w http.ResponseWriter, r *http.Request, result *OAuthLoginResult)
+}
+
+// NewOAuthService membuat OAuthService. Secara default identitas dengan email terverifikasi
+// ditautkan ke user dengan email yang sama, user baru tidak dibuat (lihat WithUserCreator),
+// state berlaku 10 menit, dan callback mengembalikan TokenResponse sebagai JSON.
+//
+// Parameters:
+//   - auth: AuthService yang menerbitkan token
+//   - accounts: penyimpanan tautan akun, misal DatabaseOAuthAccountStore
+//   - signer: PayloadSigner untuk menandatangani cookie state
+//   - redirectURL: URL callback absolut dengan placeholder {provider}
+//
+// Returns:
+//   - *OAuthService: service yang siap digunakan
+//
+// Example:
+//
+//	oauth := dim.NewOAuthService(authService, dim.NewDatabaseOAuthAccountStore(db),
+//	    dim.NewPayloadSigner(os.Getenv("OAUTH_STATE_SECRET")),
+//	    "https://api.example.com/auth/oauth/{provider}/callback").
+//	    WithProvider(dim.GoogleOAuthProvider(googleID, googleSecret)).
+//	    WithProvider(dim.GitHubOAuthProvider(githubID, githubSecret))
+//
+//	router.Get("/auth/oauth/{provider}", oauth.LoginHandler())
+//	router.Get("/auth/oauth/{provider}/callback", oauth.CallbackHandler())
+func NewOAuthService(auth *AuthService, accounts OAuthAccountStore, signer *PayloadSigner, redirectURL string) *OAuthService {
+	return &OAuthService{
+		auth:        auth,
+		accounts:    accounts,
+		signer:      signer,
+		redirectURL: redirectURL,
+		providers:   make(map[string]*OAuthProvider),
+		client:      defaultOAuthClient,
+		linkByEmail: true,
+		stateTTL:    10 * time.Minute,
+	}
+}
+
+// WithProvider mendaftarkan provider berdasarkan Name dan mengembalikan instance service.
+func (s *OAuthService) WithProvider(provider *OAuthProvider) *OAuthService {
+	s.providers[provider.Name] = provider
+	return s
+}
+
+// WithHTTPClient mengatur HTTP client untuk request ke provider (default timeout 10 detik).
+func (s *OAuthService) WithHTTPClient(client *http.Client) *OAuthService {
+	s.client = client
+	return s
+}
+
+// WithUserCreator mengaktifkan pendaftaran via OAuth: creator dipanggil untuk identitas yang
+// belum terhubung ke user mana pun.
+//
+// Example:
+//
+//	oauth.WithUserCreator(func(ctx context.Context, id *dim.OAuthIdentity) (dim.Authenticatable, error) {
+//	    user := &dim.User{Email: id.Email, Name: id.Name}
+//	    return user, userStore.CreateBatch(ctx, []*dim.User{user})
+//	})
+func (s *OAuthService) WithUserCreator(creator OAuthUserCreator) *OAuthService {
+	s.createUser = creator
+	return s
+}
+
+// WithEmailLinking mengatur apakah identitas baru dengan email terverifikasi ditautkan ke user
+// yang sudah ada dengan email yang sama (default: true). Matikan jika provider tidak dapat
+// dipercaya untuk memverifikasi email.
+func (s *OAuthService) WithEmailLinking(enabled bool) *OAuthService {
+	s.linkByEmail = enabled
+	return s
+}
+
+// WithStateTTL mengatur batas waktu antara LoginHandler dan callback (default: 10 menit).
+func (s *OAuthService) WithStateTTL(ttl time.Duration) *OAuthService {
+	s.stateTTL = ttl
+	return s
+}
+
+// WithSuccessHandler mengganti response callback yang berhasil, misal untuk menyimpan token di
+// cookie lalu redirect ke frontend (result.ReturnTo).
+func (s *OAuthService) WithSuccessHandler(fn func(w http.ResponseWriter, r *http.Request, result *OAuthLoginResult)) *OAuthService {
+	s.onSuccess = fn
+	return s
+}
+
+// Provider mengembalikan provider yang terdaftar dengan nama tersebut.
+func (s *OAuthService) Provider(name string) (*OAuthProvider, bool) {
+	p, ok := s.providers[name]
+	return p, ok
+}
+
+// callbackURL mengembalikan redirect_uri untuk provider.
+func (s *OAuthService) callbackURL(provider string) string {
+	return strings.ReplaceAll(s.redirectURL, "{provider}", provider)
+}
+
+// oauthState adalah isi cookie state.
+type oauthState struct {
+	Provider string `json:"p"`
+	State    string `json:"s"`
+	Verifier string `json:"v"`
+	Nonce    string `json:"n"`
+	ReturnTo string `json:"r,omitempty"`
+}
+
+// Begin memulai login: membuat state, PKCE verifier, dan nonce, menyimpannya di cookie
+// bertanda tangan, lalu mengembalikan URL authorization provider.
+//
+// Parameters:
+//   - w: response untuk menulis cookie state
+//   - r: request saat ini
+//   - provider: nama provider
+//   - returnTo: path relatif tujuan setelah login (diabaikan jika bukan path relatif)
+//
+// Returns:
+//   - string: URL authorization untuk redirect
+//   - error: ErrOAuthProviderNotFound atau kegagalan membuat nilai acak
+func (s *OAuthService) Begin(w http.ResponseWriter, r *http.Request, provider, returnTo string) (string, error) {
+	p, ok := s.providers[provider]
+	if !ok {
+		return "", ErrOAuthProviderNotFound
+	}
+
+	st := oauthState{Provider: provider}
+	for _, v := range []*string{&st.State, &st.Verifier, &st.Nonce} {
+		token, err := GenerateSecureToken(32)
+		if err != nil {
+			return "", err
+		}
+		*v = token
+	}
+	if isRelativePath(returnTo) {
+		st.ReturnTo = returnTo
+	}
+
+	payload, err := json.Marshal(st)
+	if err != nil {
+		return "", err
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	expiresAt := time.Now().Add(s.stateTTL)
+	SetCookie(w, &http.Cookie{
+		Name:     OAuthStateCookie,
+		Value:    encoded + "." + strconv.FormatInt(expiresAt.Unix(), 10) + "." + s.signer.Sign(encoded, expiresAt),
+		Path:     "/",
+		MaxAge:   int(s.stateTTL.Seconds()),
+		HttpOnly: true,
+		Secure:   r.TLS != nil || strings.HasPrefix(s.redirectURL, "https://"),
+		// Lax agar cookie ikut terkirim pada redirect top-level dari provider
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	return p.AuthCodeURL(s.callbackURL(provider), st.State, pkceChallenge(st.Verifier), st.Nonce), nil
+}
+
+// Complete memproses callback provider: memverifikasi state, menukar code, membaca identitas,
+// lalu menjalankan Login. Cookie state selalu dihapus.
+//
+// Returns:
+//   - *OAuthLoginResult: user dan token
+//   - error: *AppError yang siap dikirim ke client
+func (s *OAuthService) Complete(w http.ResponseWriter, r *http.Request, provider string) (*OAuthLoginResult, error) {
+	ctx := requestClientContext(r)
+	p, ok := s.providers[provider]
+	if !ok {
+		return nil, NewAppError("Provider OAuth tidak ditemukan", http.StatusNotFound)
+	}
+
+	st, err := s.readState(r)
+	SetCookie(w, &http.Cookie{Name: OAuthStateCookie, Value: "", Path: "/", MaxAge: -1, HttpOnly: true})
+	q := r.URL.Query()
+	if providerErr := q.Get("error"); providerErr != "" {
+		// User membatalkan consent atau provider menolak request
+		return nil, NewAppError("Login dibatalkan: "+providerErr, http.StatusUnauthorized)
+	}
+	if err != nil || st.Provider != provider || subtle.ConstantTimeCompare([]byte(st.State), []byte(q.Get("state"))) != 1 {
+		return nil, NewAppError("State OAuth tidak valid atau kadaluarsa, silakan ulangi login", http.StatusBadRequest)
+	}
+	code := q.Get("code")
+	if code == "" {
+		return nil, NewAppError("Authorization code tidak ditemukan", http.StatusBadRequest)
+	}
+
+	token, err := p.Exchange(ctx, s.client, code, s.callbackURL(provider), st.Verifier)
+	if err != nil {
+		s.logWarn("OAuth code exchange failed", provider, err)
+		return nil, NewAppError("Gagal login dengan "+provider, http.StatusUnauthorized)
+	}
+	identity, err := p.Identity(ctx, s.client, token, st.Nonce)
+	if err != nil {
+		s.logWarn("OAuth identity verification failed", provider, err)
+		return nil, NewAppError("Gagal login dengan "+provider, http.StatusUnauthorized)
+	}
+
+	result, err := s.Login(ctx, identity)
+	if err != nil {
+		return nil, err
+	}
+	result.ReturnTo = st.ReturnTo
+	return result, nil
+}
+
+// Login mencari atau membuat user untuk identitas yang sudah diverifikasi lalu menerbitkan token.
+// Urutan pencarian: akun yang sudah ditautkan (provider + subject), lalu user dengan email yang
+// sama jika email terverifikasi dan WithEmailLinking aktif, lalu OAuthUserCreator. Untuk user
+// dengan MFA aktif, token tidak diterbitkan dan result.MFA berisi token mfa_pending.
+// Dapat dipanggil langsung untuk alur native (misal id_token dari SDK mobile yang sudah diverifikasi).
+//
+// Returns:
+//   - *OAuthLoginResult: user dan token (ReturnTo kosong)
+//   - error: *AppError (403 jika pendaftaran dimatikan)
+func (s *OAuthService) Login(ctx context.Context, identity *OAuthIdentity) (*OAuthLoginResult, error) {
+	result := &OAuthLoginResult{Identity: identity}
+
+	account, err := s.accounts.FindOAuthAccount(ctx, identity.Provider, identity.Subject)
+	switch {
+	case err == nil:
+		user, err := s.auth.userStore.FindByID(ctx, account.UserID)
+		if err != nil {
+			return nil, NewAppError("Pengguna tidak ditemukan", http.StatusUnauthorized)
+		}
+		result.User = user
+		if identity.Email != "" && identity.Email != account.Email {
+			account.Email = identity.Email
+			if err := s.accounts.SaveOAuthAccount(ctx, account); err != nil {
+				s.logWarn("Failed to update OAuth account email", identity.Provider, err)
+			}
+		}
+	case errors.Is(err, ErrOAuthAccountNotFound):
+		user, created, err := s.findOrCreateUser(ctx, identity)
+		if err != nil {
+			return nil, err
+		}
+		account := &OAuthAccount{
+			UserID:   user.GetID(),
+			Provider: identity.Provider,
+			Subject:  identity.Subject,
+			Email:    identity.Email,
+		}
+		if err := s.accounts.SaveOAuthAccount(ctx, account); err != nil {
+			return nil, NewAppError("Gagal menautkan akun", http.StatusInternalServerError)
+		}
+		result.User, result.Created, result.Linked = user, created, true
+	default:
+		return nil, NewAppError("Gagal memuat akun OAuth", http.StatusInternalServerError)
+	}
+
+	result.MFA, err = s.auth.mfaChallenge(ctx, result.User)
+	if err != nil || result.MFA != nil {
+		return result, err
+	}
+	result.AccessToken, result.RefreshToken, err = s.auth.login(ctx, result.User, LoginMethodOAuth, false)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// findOrCreateUser menautkan identitas ke user dengan email terverifikasi yang sama, atau
+// membuat user baru dengan OAuthUserCreator.
+func (s *OAuthService) findOrCreateUser(ctx context.Context, identity *OAuthIdentity) (Authenticatable, bool, error) {
+	if s.linkByEmail && identity.EmailVerified && identity.Email != "" {
+		if user, err := s.auth.userStore.FindByEmail(ctx, identity.Email); err == nil {
+			return user, false, nil
+		}
+	}
+
+	if s.createUser == nil {
+		return nil, false, NewAppError("Akun belum terdaftar", http.StatusForbidden)
+	}
+	user, err := s.createUser(ctx, identity)
+	if err != nil {
+		if appErr, ok := AsAppError(err); ok {
+			return nil, false, appErr
+		}
+		s.logWarn("OAuth user creation failed", identity.Provider, err)
+		return nil, false, NewAppError("Gagal membuat akun", http.StatusInternalServerError)
+	}
+	return user, true, nil
+}
+
+// readState membaca dan memverifikasi cookie state.
+func (s *OAuthService) readState(r *http.Request) (*oauthState, error) {
+	parts := strings.Split(GetCookie(r, OAuthStateCookie), ".")
+	if len(parts) != 3 {
+		return nil, ErrOAuthStateInvalid
+	}
+	expires, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return nil, ErrOAuthStateInvalid
+	}
+	if err := s.signer.Verify(parts[0], time.Unix(expires, 0), parts[2]); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrOAuthStateInvalid, err)
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, ErrOAuthStateInvalid
+	}
+	st := &oauthState{}
+	if err := json.Unmarshal(payload, st); err != nil {
+		return nil, ErrOAuthStateInvalid
+	}
+	return st, nil
+}
+
+func (s *OAuthService) logWarn(msg, provider string, err error) {
+	if s.auth.logger != nil {
+		s.auth.logger.Warn(msg, "provider", provider, "error", err.Error())
+	}
+}
+
+// LoginHandler membuat handler GET /auth/oauth/{provider} yang me-redirect browser ke provider.
+// Parameter ?redirect=/path opsional diteruskan ke OAuthLoginResult.ReturnTo.
+func (s *OAuthService) LoginHandler() HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		target, err := s.Begin(w, r, GetParam(r, "provider"), r.URL.Query().Get("redirect"))
+		if err != nil {
+			if errors.Is(err, ErrOAuthProviderNotFound) {
+				NotFound(w, "Provider OAuth tidak ditemukan")
+				return
+			}
+			InternalServerError(w, "Gagal memulai login")
+			return
+		}
+		http.Redirect(w, r, target, http.StatusFound)
+	}
+}
+
+// CallbackHandler membuat handler GET /auth/oauth/{provider}/callback. Secara default response
+// berisi TokenResponse (JSON), atau MFARequiredError jika user harus memasukkan kode MFA;
+// ganti dengan WithSuccessHandler.
+func (s *OAuthService) CallbackHandler() HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		result, err := s.Complete(w, r, GetParam(r, "provider"))
+		if err != nil {
+			if appErr, ok := AsAppError(err); ok {
+				JsonAppError(w, appErr)
+				return
+			}
+			InternalServerError(w, "Gagal login")
+			return
+		}
+
+		if s.onSuccess != nil {
+			s.onSuccess(w, r, result)
+			return
+		}
+		if result.MFA != nil {
+			Json(w, http.StatusOK, result.MFA)
+			return
+		}
+		Json(w, http.StatusOK, TokenResponse{
+			AccessToken:  result.AccessToken,
+			RefreshToken: result.RefreshToken,
+			TokenType:    "Bearer",
+		})
+	}
+}
+
+// pkceChallenge menghitung code_challenge S256 dari verifier (RFC 7636).
+func pkceChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// isRelativePath melaporkan apakah target adalah path relatif yang aman untuk redirect
+// (bukan URL absolut atau protocol-relative //host).
+func isRelativePath(target string) bool {
+	return strings.HasPrefix(target, "/") && !strings.HasPrefix(target, "//") && !strings.HasPrefix(target, "/\\")
+}
@@ -0,0 +1,81 @@
+package dim
+
+import (
+	"context"
+)
+
+// GetOAuthMigrations mengembalikan migrasi tabel oauth_accounts untuk OAuthService.
+// Modul ini opsional sehingga tidak termasuk dalam GetFrameworkMigrations; gabungkan secara
+// manual setelah migrasi users. Menggunakan versi 131 agar tidak bentrok dengan migrasi
+// framework maupun aplikasi.
+//
+// Example:
+//
+//	migrations := append(dim.GetFrameworkMigrations(), dim.GetOAuthMigrations()...)
+//	dim.RunMigrations(db, migrations)
+func GetOAuthMigrations() []Migration {
+	return []Migration{
+		{
+			Version: 131,
+			Name:    "create_oauth_accounts_table",
+			Up:      CreateOAuthAccountsTable,
+			Down:    DropOAuthAccountsTable,
+		},
+	}
+}
+
+// CreateOAuthAccountsTable membuat tabel oauth_accounts dengan kunci unik (provider, subject).
+func CreateOAuthAccountsTable(db Database) error {
+	var query string
+	switch db.DriverName() {
+	case "sqlite":
+		query = `
+			CREATE TABLE IF NOT EXISTS oauth_accounts (
+				id TEXT PRIMARY KEY,
+				user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+				provider TEXT NOT NULL,
+				subject TEXT NOT NULL,
+				email TEXT NOT NULL DEFAULT '',
+				created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				UNIQUE (provider, subject)
+			);
+			CREATE INDEX IF NOT EXISTS idx_oauth_accounts_user_id ON oauth_accounts(user_id);
+		`
+	case "mysql":
+		query = `
+			CREATE TABLE IF NOT EXISTS oauth_accounts (
+				id CHAR(36) PRIMARY KEY,
+				user_id CHAR(36) NOT NULL,
+				provider VARCHAR(50) NOT NULL,
+				subject VARCHAR(255) NOT NULL,
+				email VARCHAR(255) NOT NULL DEFAULT '',
+				created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				UNIQUE KEY uq_oauth_accounts_provider_subject (provider, subject),
+				KEY idx_oauth_accounts_user_id (user_id),
+				CONSTRAINT fk_oauth_accounts_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+		`
+	default:
+		query = `
+			CREATE TABLE IF NOT EXISTS oauth_accounts (
+				id UUID PRIMARY KEY,
+				user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+				provider VARCHAR(50) NOT NULL,
+				subject VARCHAR(255) NOT NULL,
+				email VARCHAR(255) NOT NULL DEFAULT '',
+				created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+				updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+				UNIQUE (provider, subject)
+			);
+			CREATE INDEX IF NOT EXISTS idx_oauth_accounts_user_id ON oauth_accounts(user_id);
+		`
+	}
+	return db.Exec(context.Background(), query)
+}
+
+// DropOAuthAccountsTable menghapus tabel oauth_accounts.
+func DropOAuthAccountsTable(db Database) error {
+	return db.Exec(context.Background(), "DROP TABLE IF EXISTS oauth_accounts")
+}
@@ -0,0 +1,406 @@
+package dim
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// defaultOAuthClient dipakai untuk request ke provider jika OAuthService tidak diberi HTTP client.
+var defaultOAuthClient = &http.Client{Timeout: 10 * time.Second}
+
+// githubAPIURL adalah base URL API GitHub untuk GitHubOAuthProvider. Berupa variabel agar dapat
+// diarahkan ke server test.
+var githubAPIURL = "https://api.github.com"
+
+// OAuthProvider adalah konfigurasi satu provider OAuth2 / OpenID Connect.
+// Gunakan GoogleOAuthProvider, GitHubOAuthProvider, atau DiscoverOIDCProvider, atau isi field
+// secara manual untuk provider OAuth2 lain.
+type OAuthProvider struct {
+	// Name adalah nama provider di URL, misal "google" untuk /auth/oauth/google.
+	Name string
+
+	ClientID     string
+	ClientSecret string
+
+	// AuthURL, TokenURL, dan UserInfoURL adalah endpoint authorization, token, dan userinfo.
+	AuthURL     string
+	TokenURL    string
+	UserInfoURL string
+
+	// Scopes yang diminta saat login.
+	Scopes []string
+
+	// Issuer dan JWKSURL diisi untuk provider OpenID Connect. Jika id_token dikembalikan,
+	// signature-nya diverifikasi dengan JWKS, lalu iss, aud, exp, dan nonce diperiksa.
+	Issuer  string
+	JWKSURL string
+
+	// AuthParams adalah parameter tambahan authorization URL (misal prompt=select_account).
+	AuthParams map[string]string
+
+	// DisablePKCE mematikan PKCE (S256) untuk provider yang menolak parameter code_challenge.
+	DisablePKCE bool
+
+	// FetchIdentity mengganti cara membaca identitas dari token (opsional). Default: claims
+	// id_token, dilengkapi userinfo jika email tidak ada di id_token.
+	FetchIdentity func(ctx context.Context, client *http.Client, token *OAuthToken) (*OAuthIdentity, error)
+
+	// issuerAliases adalah nilai iss lain yang diterima (Google memakai dua bentuk).
+	issuerAliases []string
+
+	keysOnce sync.Once
+	keys     *jwkSet
+}
+
+// OAuthToken adalah response token endpoint.
+type OAuthToken struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	IDToken      string `json:"id_token,omitempty"`
+	ExpiresIn    int    `json:"expires_in,omitempty"`
+	Scope        string `json:"scope,omitempty"`
+}
+
+// OAuthIdentity adalah identitas user menurut provider.
+type OAuthIdentity struct {
+	// Provider adalah OAuthProvider.Name.
+	Provider string `json:"provider"`
+	// Subject adalah ID user yang stabil di provider (claim sub, atau ID numerik GitHub).
+	Subject string `json:"subject"`
+	Email   string `json:"email"`
+	// EmailVerified bernilai true jika provider menyatakan email sudah diverifikasi. Hanya email
+	// terverifikasi yang dipakai untuk menautkan ke user yang sudah ada.
+	EmailVerified bool   `json:"email_verified"`
+	Name          string `json:"name"`
+	AvatarURL     string `json:"avatar_url"`
+	// Claims berisi claims/response mentah dari provider.
+	Claims map[string]interface{} `json:"-"`
+}
+
+// GoogleOAuthProvider membuat provider Google (OpenID Connect) dengan scope openid, email, profile.
+//
+// Example:
+//
+//	oauth.WithProvider(dim.GoogleOAuthProvider(os.Getenv("GOOGLE_CLIENT_ID"), os.Getenv("GOOGLE_CLIENT_SECRET")))
+func GoogleOAuthProvider(clientID, clientSecret string) *OAuthProvider {
+	return &OAuthProvider{
+		Name:          "google",
+		ClientID:      clientID,
+		ClientSecret:  clientSecret,
+		AuthURL:       "https://accounts.google.com/o/oauth2/v2/auth",
+		TokenURL:      "https://oauth2.googleapis.com/token",
+		UserInfoURL:   "https://openidconnect.googleapis.com/v1/userinfo",
+		Scopes:        []string{"openid", "email", "profile"},
+		Issuer:        "https://accounts.google.com",
+		JWKSURL:       "https://www.googleapis.com/oauth2/v3/certs",
+		issuerAliases: []string{"accounts.google.com"},
+	}
+}
+
+// GitHubOAuthProvider membuat provider GitHub (OAuth2) dengan scope read:user dan user:email.
+// Email diambil dari email utama yang sudah diverifikasi di /user/emails.
+func GitHubOAuthProvider(clientID, clientSecret string) *OAuthProvider {
+	return &OAuthProvider{
+		Name:          "github",
+		ClientID:      clientID,
+		ClientSecret:  clientSecret,
+		AuthURL:       "https://github.com/login/oauth/authorize",
+		TokenURL:      "https://github.com/login/oauth/access_token",
+		Scopes:        []string{"read:user", "user:email"},
+		FetchIdentity: fetchGitHubIdentity,
+	}
+}
+
+// DiscoverOIDCProvider membuat provider OpenID Connect dari dokumen discovery
+// <issuer>/.well-known/openid-configuration (Keycloak, Auth0, Okta, Azure AD, dsb.).
+//
+// Parameters:
+//   - ctx: context untuk request discovery
+//   - name: nama provider di URL
+//   - issuer: URL issuer, harus sama dengan nilai issuer di dokumen discovery
+//   - clientID, clientSecret: kredensial client
+//
+// Returns:
+//   - *OAuthProvider: provider dengan scope openid, email, profile
+//   - error: jika dokumen tidak dapat diambil atau issuer tidak cocok
+//
+// Example:
+//
+//	keycloak, err := dim.DiscoverOIDCProvider(ctx, "keycloak", "https://sso.example.com/realms/main", clientID, secret)
+func DiscoverOIDCProvider(ctx context.Context, name, issuer, clientID, clientSecret string) (*OAuthProvider, error) {
+	var doc struct {
+		Issuer           string `json:"issuer"`
+		AuthEndpoint     string `json:"authorization_endpoint"`
+		TokenEndpoint    string `json:"token_endpoint"`
+		UserInfoEndpoint string `json:"userinfo_endpoint"`
+		JWKSURI          string `json:"jwks_uri"`
+	}
+	discoveryURL := strings.TrimSuffix(issuer, "/") + "/.well-known/openid-configuration"
+	if err := oauthGetJSON(ctx, defaultOAuthClient, discoveryURL, "", &doc); err != nil {
+		return nil, fmt.Errorf("oidc discovery failed: %w", err)
+	}
+	if strings.TrimSuffix(doc.Issuer, "/") != strings.TrimSuffix(issuer, "/") {
+		return nil, fmt.Errorf("oidc discovery issuer mismatch: got %q, want %q", doc.Issuer, issuer)
+	}
+	if doc.AuthEndpoint == "" || doc.TokenEndpoint == "" || doc.JWKSURI == "" {
+		return nil, errors.New("oidc discovery document is missing required endpoints")
+	}
+
+	return &OAuthProvider{
+		Name:         name,
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		AuthURL:      doc.AuthEndpoint,
+		TokenURL:     doc.TokenEndpoint,
+		UserInfoURL:  doc.UserInfoEndpoint,
+		Scopes:       []string{"openid", "email", "profile"},
+		Issuer:       doc.Issuer,
+		JWKSURL:      doc.JWKSURI,
+	}, nil
+}
+
+// AuthCodeURL membuat URL authorization untuk redirect browser.
+// codeChallenge diabaikan jika DisablePKCE; nonce hanya dikirim untuk provider OIDC.
+func (p *OAuthProvider) AuthCodeURL(redirectURL, state, codeChallenge, nonce string) string {
+	q := url.Values{}
+	q.Set("response_type", "code")
+	q.Set("client_id", p.ClientID)
+	q.Set("redirect_uri", redirectURL)
+	q.Set("state", state)
+	if len(p.Scopes) > 0 {
+		q.Set("scope", strings.Join(p.Scopes, " "))
+	}
+	if !p.DisablePKCE && codeChallenge != "" {
+		q.Set("code_challenge", codeChallenge)
+		q.Set("code_challenge_method", "S256")
+	}
+	if p.Issuer != "" && nonce != "" {
+		q.Set("nonce", nonce)
+	}
+	for k, v := range p.AuthParams {
+		q.Set(k, v)
+	}
+
+	sep := "?"
+	if strings.Contains(p.AuthURL, "?") {
+		sep = "&"
+	}
+	return p.AuthURL + sep + q.Encode()
+}
+
+// Exchange menukar authorization code dengan token di token endpoint.
+func (p *OAuthProvider) Exchange(ctx context.Context, client *http.Client, code, redirectURL, codeVerifier string) (*OAuthToken, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", redirectURL)
+	form.Set("client_id", p.ClientID)
+	form.Set("client_secret", p.ClientSecret)
+	if !p.DisablePKCE && codeVerifier != "" {
+		form.Set("code_verifier", codeVerifier)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("oauth token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		OAuthToken
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body); err != nil {
+		return nil, fmt.Errorf("oauth token response (status %d) is not valid JSON: %w", resp.StatusCode, err)
+	}
+	// GitHub mengembalikan error dengan status 200
+	if body.Error != "" {
+		return nil, fmt.Errorf("oauth token error: %s: %s", body.Error, body.ErrorDescription)
+	}
+	if resp.StatusCode != http.StatusOK || body.AccessToken == "" {
+		return nil, fmt.Errorf("oauth token request failed with status %d", resp.StatusCode)
+	}
+	return &body.OAuthToken, nil
+}
+
+// Identity membaca identitas user dari token. Untuk provider OIDC, id_token diverifikasi
+// (signature, iss, aud, exp, dan nonce) sebelum claims-nya dipakai.
+func (p *OAuthProvider) Identity(ctx context.Context, client *http.Client, token *OAuthToken, nonce string) (*OAuthIdentity, error) {
+	if p.FetchIdentity != nil {
+		identity, err := p.FetchIdentity(ctx, client, token)
+		if err != nil {
+			return nil, err
+		}
+		identity.Provider = p.Name
+		return identity, nil
+	}
+
+	var claims map[string]interface{}
+	if token.IDToken != "" && p.JWKSURL != "" {
+		verified, err := p.verifyIDToken(ctx, client, token.IDToken, nonce)
+		if err != nil {
+			return nil, err
+		}
+		claims = verified
+	} else if p.Issuer != "" {
+		return nil, errors.New("oidc provider did not return an id_token")
+	}
+
+	if (claims == nil || claims["email"] == nil) && p.UserInfoURL != "" {
+		var info map[string]interface{}
+		if err := oauthGetJSON(ctx, client, p.UserInfoURL, token.AccessToken, &info); err != nil {
+			return nil, fmt.Errorf("oauth userinfo request failed: %w", err)
+		}
+		if claims == nil {
+			claims = info
+		} else {
+			if sub, _ := info["sub"].(string); sub != claims["sub"] {
+				return nil, errors.New("oauth userinfo subject does not match id_token")
+			}
+			for k, v := range info {
+				if _, exists := claims[k]; !exists {
+					claims[k] = v
+				}
+			}
+		}
+	}
+	if claims == nil {
+		return nil, errors.New("oauth provider has no id_token or userinfo endpoint")
+	}
+
+	identity := identityFromClaims(claims)
+	identity.Provider = p.Name
+	if identity.Subject == "" {
+		return nil, errors.New("oauth identity has no subject")
+	}
+	return identity, nil
+}
+
+// identityFromClaims memetakan claims standar OIDC ke OAuthIdentity.
+func identityFromClaims(claims map[string]interface{}) *OAuthIdentity {
+	identity := &OAuthIdentity{Claims: claims}
+	identity.Subject, _ = claims["sub"].(string)
+	identity.Email, _ = claims["email"].(string)
+	identity.Name, _ = claims["name"].(string)
+	identity.AvatarURL, _ = claims["picture"].(string)
+	switch v := claims["email_verified"].(type) {
+	case bool:
+		identity.EmailVerified = v
+	case string:
+		// Beberapa provider (misal AWS Cognito) mengirim string
+		identity.EmailVerified = v == "true"
+	}
+	return identity
+}
+
+// verifyIDToken memverifikasi id_token dan mengembalikan claims-nya.
+func (p *OAuthProvider) verifyIDToken(ctx context.Context, client *http.Client, idToken, nonce string) (map[string]interface{}, error) {
+	p.keysOnce.Do(func() {
+		p.keys = &jwkSet{url: p.JWKSURL}
+	})
+
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(idToken, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		return p.keys.key(ctx, client, kid)
+	},
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}),
+		jwt.WithAudience(p.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid id_token: %w", err)
+	}
+
+	iss, _ := claims["iss"].(string)
+	if iss != p.Issuer && !slices.Contains(p.issuerAliases, iss) {
+		return nil, fmt.Errorf("invalid id_token: unexpected issuer %q", iss)
+	}
+	if got, _ := claims["nonce"].(string); nonce != "" && got != nonce {
+		return nil, errors.New("invalid id_token: nonce mismatch")
+	}
+	return claims, nil
+}
+
+// fetchGitHubIdentity membaca profil GitHub dan email utama yang sudah diverifikasi.
+func fetchGitHubIdentity(ctx context.Context, client *http.Client, token *OAuthToken) (*OAuthIdentity, error) {
+	var profile map[string]interface{}
+	if err := oauthGetJSON(ctx, client, githubAPIURL+"/user", token.AccessToken, &profile); err != nil {
+		return nil, fmt.Errorf("github user request failed: %w", err)
+	}
+	id, ok := profile["id"].(float64)
+	if !ok {
+		return nil, errors.New("github user response has no id")
+	}
+
+	identity := &OAuthIdentity{
+		Subject: strconv.FormatInt(int64(id), 10),
+		Claims:  profile,
+	}
+	identity.Name, _ = profile["name"].(string)
+	if identity.Name == "" {
+		identity.Name, _ = profile["login"].(string)
+	}
+	identity.AvatarURL, _ = profile["avatar_url"].(string)
+
+	var emails []struct {
+		Email    string `json:"email"`
+		Primary  bool   `json:"primary"`
+		Verified bool   `json:"verified"`
+	}
+	if err := oauthGetJSON(ctx, client, githubAPIURL+"/user/emails", token.AccessToken, &emails); err != nil {
+		return nil, fmt.Errorf("github emails request failed: %w", err)
+	}
+	for _, e := range emails {
+		if e.Primary {
+			identity.Email = e.Email
+			identity.EmailVerified = e.Verified
+			break
+		}
+	}
+	return identity, nil
+}
+
+// oauthGetJSON melakukan GET dan men-decode response JSON. bearer boleh kosong.
+func oauthGetJSON(ctx context.Context, client *http.Client, target, bearer string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if bearer != "" {
+		req.Header.Set("Authorization", "Bearer "+bearer)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d from %s", resp.StatusCode, target)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(v)
+}
@@ -0,0 +1,142 @@
+package dim
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DatabaseOAuthAccountStore is the SQL implementation of OAuthAccountStore (PostgreSQL, MySQL & SQLite)
+type DatabaseOAuthAccountStore struct {
+	db Database
+}
+
+// NewDatabaseOAuthAccountStore creates a new SQL OAuth account store.
+// Requires the table created by GetOAuthMigrations.
+func NewDatabaseOAuthAccountStore(db Database) *DatabaseOAuthAccountStore {
+	return &DatabaseOAuthAccountStore{db: db}
+}
+
+// FindOAuthAccount finds the account linked to a provider subject.
+func (s *DatabaseOAuthAccountStore) FindOAuthAccount(ctx context.Context, provider, subject string) (*OAuthAccount, error) {
+	a := &OAuthAccount{}
+	query := `SELECT id, user_id, provider, subject, email, created_at, updated_at
+		 FROM oauth_accounts WHERE provider = $1 AND subject = $2`
+
+	err := s.db.QueryRow(ctx, s.db.Rebind(query), provider, subject).
+		Scan(&a.ID, &a.UserID, &a.Provider, &a.Subject, &a.Email, &a.CreatedAt, &a.UpdatedAt)
+	if err != nil {
+		if isNoRows(err) {
+			return nil, ErrOAuthAccountNotFound
+		}
+		return nil, fmt.Errorf("failed to find oauth account: %w", err)
+	}
+
+	return a, nil
+}
+
+// SaveOAuthAccount inserts a new account (empty ID) or updates the email of an existing one.
+func (s *DatabaseOAuthAccountStore) SaveOAuthAccount(ctx context.Context, account *OAuthAccount) error {
+	now := time.Now().UTC().Truncate(time.Second)
+
+	if account.ID != "" {
+		query := `UPDATE oauth_accounts SET email = $1, updated_at = $2 WHERE id = $3`
+		if err := s.db.Exec(ctx, s.db.Rebind(query), account.Email, now, account.ID); err != nil {
+			return fmt.Errorf("failed to update oauth account: %w", err)
+		}
+		account.UpdatedAt = now
+		return nil
+	}
+
+	id := NewUuid().String()
+	query := `INSERT INTO oauth_accounts (id, user_id, provider, subject, email, created_at, updated_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	if err := s.db.Exec(ctx, s.db.Rebind(query), id, account.UserID, account.Provider, account.Subject, account.Email, now, now); err != nil {
+		return fmt.Errorf("failed to create oauth account: %w", err)
+	}
+	account.ID, account.CreatedAt, account.UpdatedAt = id, now, now
+	return nil
+}
+
+// ListUserOAuthAccounts lists the accounts linked to a user, oldest first.
+func (s *DatabaseOAuthAccountStore) ListUserOAuthAccounts(ctx context.Context, userID string) ([]*OAuthAccount, error) {
+	query := `SELECT id, user_id, provider, subject, email, created_at, updated_at
+		 FROM oauth_accounts WHERE user_id = $1 ORDER BY created_at, provider`
+
+	rows, err := s.db.Query(ctx, s.db.Rebind(query), userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list oauth accounts: %w", err)
+	}
+	defer rows.Close()
+
+	accounts := make([]*OAuthAccount, 0)
+	for rows.Next() {
+		a := &OAuthAccount{}
+		if err := rows.Scan(&a.ID, &a.UserID, &a.Provider, &a.Subject, &a.Email, &a.CreatedAt, &a.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan oauth account: %w", err)
+		}
+		accounts = append(accounts, a)
+	}
+	return accounts, rows.Err()
+}
+
+// MockOAuthAccountStore is a mock implementation for testing
+type MockOAuthAccountStore struct {
+	mu       sync.RWMutex
+	accounts map[string]*OAuthAccount
+}
+
+// NewMockOAuthAccountStore creates a new mock OAuth account store.
+func NewMockOAuthAccountStore() *MockOAuthAccountStore {
+	return &MockOAuthAccountStore{accounts: make(map[string]*OAuthAccount)}
+}
+
+func mockOAuthAccountKey(provider, subject string) string {
+	return provider + "\x00" + subject
+}
+
+// FindOAuthAccount finds an account in mock store.
+func (s *MockOAuthAccountStore) FindOAuthAccount(ctx context.Context, provider, subject string) (*OAuthAccount, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	a, exists := s.accounts[mockOAuthAccountKey(provider, subject)]
+	if !exists {
+		return nil, ErrOAuthAccountNotFound
+	}
+	copied := *a
+	return &copied, nil
+}
+
+// SaveOAuthAccount inserts or updates an account in mock store.
+func (s *MockOAuthAccountStore) SaveOAuthAccount(ctx context.Context, account *OAuthAccount) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	if account.ID == "" {
+		account.ID = NewUuid().String()
+		account.CreatedAt = now
+	}
+	account.UpdatedAt = now
+	copied := *account
+	s.accounts[mockOAuthAccountKey(account.Provider, account.Subject)] = &copied
+	return nil
+}
+
+// ListUserOAuthAccounts lists a user's accounts in mock store.
+func (s *MockOAuthAccountStore) ListUserOAuthAccounts(ctx context.Context, userID string) ([]*OAuthAccount, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	accounts := make([]*OAuthAccount, 0)
+	for _, a := range s.accounts {
+		if a.UserID == userID {
+			copied := *a
+			accounts = append(accounts, &copied)
+		}
+	}
+	sort.Slice(accounts, func(i, j int) bool {
+		return accounts[i].CreatedAt.Before(accounts[j].CreatedAt)
+	})
+	return accounts, nil
+}
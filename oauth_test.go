@@ -0,0 +1,332 @@
+package dim
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// fakeOIDCServer adalah provider OpenID Connect minimal untuk test.
+type fakeOIDCServer struct {
+	*httptest.Server
+	key       *rsa.PrivateKey
+	subject   string
+	email     string
+	verified  bool
+	nonce     string
+	challenge string
+}
+
+func newFakeOIDCServer(t *testing.T) *fakeOIDCServer {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &fakeOIDCServer{key: key, subject: "sub-123", email: "ana@example.com", verified: true}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 f.URL,
+			"authorization_endpoint": f.URL + "/authorize",
+			"token_endpoint":         f.URL + "/token",
+			"jwks_uri":               f.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "k1",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.Form.Get("code") != "good-code" || pkceChallenge(r.Form.Get("code_verifier")) != f.challenge {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+			"iss":            f.URL,
+			"aud":            "client-id",
+			"sub":            f.subject,
+			"email":          f.email,
+			"email_verified": f.verified,
+			"name":           "Ana",
+			"nonce":          f.nonce,
+			"exp":            time.Now().Add(time.Hour).Unix(),
+			"iat":            time.Now().Unix(),
+		})
+		token.Header["kid"] = "k1"
+		idToken, err := token.SignedString(key)
+		if err != nil {
+			t.Error(err)
+		}
+		json.NewEncoder(w).Encode(map[string]string{"access_token": "at", "token_type": "Bearer", "id_token": idToken})
+	})
+	f.Server = httptest.NewServer(mux)
+	t.Cleanup(f.Close)
+	return f
+}
+
+func newTestOAuthService(t *testing.T, f *fakeOIDCServer, users *MockUserStore) (*OAuthService, *MockOAuthAccountStore) {
+	t.Helper()
+	auth, err := NewAuthService(users, NewMockTokenStore(), nil, &JWTConfig{
+		HMACSecret:         "test-secret",
+		SigningMethod:      "HS256",
+		AccessTokenExpiry:  15 * time.Minute,
+		RefreshTokenExpiry: 7 * 24 * time.Hour,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	provider, err := DiscoverOIDCProvider(context.Background(), "sso", f.URL, "client-id", "client-secret")
+	if err != nil {
+		t.Fatalf("DiscoverOIDCProvider error: %v", err)
+	}
+	accounts := NewMockOAuthAccountStore()
+	service := NewOAuthService(auth, accounts, NewPayloadSigner("state-secret"), "http://app.test/auth/oauth/{provider}/callback").
+		WithProvider(provider)
+	return service, accounts
+}
+
+// runOAuthFlow menjalankan LoginHandler lalu CallbackHandler dan mengembalikan response callback.
+func runOAuthFlow(t *testing.T, service *OAuthService, f *fakeOIDCServer, tamperState bool) *httptest.ResponseRecorder {
+	t.Helper()
+	router := NewRouter()
+	router.Get("/auth/oauth/{provider}", service.LoginHandler())
+	router.Get("/auth/oauth/{provider}/callback", service.CallbackHandler())
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/auth/oauth/sso?redirect=/dashboard", nil))
+	if w.Code != http.StatusFound {
+		t.Fatalf("login status = %d, want 302", w.Code)
+	}
+	location, err := url.Parse(w.Header().Get("Location"))
+	if err != nil || !strings.HasPrefix(location.String(), f.URL+"/authorize?") {
+		t.Fatalf("unexpected redirect %q", w.Header().Get("Location"))
+	}
+	q := location.Query()
+	if q.Get("redirect_uri") != "http://app.test/auth/oauth/sso/callback" || q.Get("code_challenge_method") != "S256" {
+		t.Fatalf("unexpected authorization params: %v", q)
+	}
+	f.nonce, f.challenge = q.Get("nonce"), q.Get("code_challenge")
+
+	state := q.Get("state")
+	if tamperState {
+		state += "x"
+	}
+	req := httptest.NewRequest(http.MethodGet, "/auth/oauth/sso/callback?code=good-code&state="+url.QueryEscape(state), nil)
+	for _, c := range w.Result().Cookies() {
+		req.AddCookie(c)
+	}
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestOAuthService_LinksVerifiedEmail(t *testing.T) {
+	f := newFakeOIDCServer(t)
+	users := NewMockUserStore()
+	users.AddUser(&MockUser{ID: "u-1", Email: "ana@example.com"})
+	service, accounts := newTestOAuthService(t, f, users)
+
+	w := runOAuthFlow(t, service, f, false)
+	if w.Code != http.StatusOK {
+		t.Fatalf("callback status = %d, body %s", w.Code, w.Body.String())
+	}
+	var resp TokenResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil || resp.AccessToken == "" || resp.RefreshToken == "" {
+		t.Fatalf("token response = %+v, %v", resp, err)
+	}
+
+	account, err := accounts.FindOAuthAccount(context.Background(), "sso", "sub-123")
+	if err != nil || account.UserID != "u-1" {
+		t.Fatalf("linked account = %+v, %v", account, err)
+	}
+
+	// Login kedua memakai akun yang sudah ditautkan walaupun email berubah
+	f.email = "ana.baru@example.com"
+	if w := runOAuthFlow(t, service, f, false); w.Code != http.StatusOK {
+		t.Fatalf("second callback status = %d, body %s", w.Code, w.Body.String())
+	}
+	if account, _ := accounts.FindOAuthAccount(context.Background(), "sso", "sub-123"); account.Email != "ana.baru@example.com" {
+		t.Errorf("account email = %q, want updated", account.Email)
+	}
+}
+
+func TestOAuthService_StateMismatch(t *testing.T) {
+	f := newFakeOIDCServer(t)
+	service, _ := newTestOAuthService(t, f, NewMockUserStore())
+
+	if w := runOAuthFlow(t, service, f, true); w.Code != http.StatusBadRequest {
+		t.Errorf("callback status = %d, want 400", w.Code)
+	}
+}
+
+func TestOAuthService_Registration(t *testing.T) {
+	f := newFakeOIDCServer(t)
+	f.verified = false
+	users := NewMockUserStore()
+	users.AddUser(&MockUser{ID: "u-1", Email: "ana@example.com"})
+	service, _ := newTestOAuthService(t, f, users)
+
+	// Email belum terverifikasi tidak ditautkan dan pendaftaran dimatikan
+	if w := runOAuthFlow(t, service, f, false); w.Code != http.StatusForbidden {
+		t.Fatalf("callback status = %d, want 403", w.Code)
+	}
+
+	service.WithUserCreator(func(ctx context.Context, identity *OAuthIdentity) (Authenticatable, error) {
+		user := &MockUser{ID: "u-2", Email: identity.Email}
+		users.AddUser(user)
+		return user, nil
+	})
+	var result *OAuthLoginResult
+	service.WithSuccessHandler(func(w http.ResponseWriter, r *http.Request, res *OAuthLoginResult) {
+		result = res
+		http.Redirect(w, r, res.ReturnTo, http.StatusFound)
+	})
+	w := runOAuthFlow(t, service, f, false)
+	if w.Code != http.StatusFound || w.Header().Get("Location") != "/dashboard" {
+		t.Fatalf("callback status = %d, location %q", w.Code, w.Header().Get("Location"))
+	}
+	if result == nil || !result.Created || !result.Linked || result.User.GetID() != "u-2" {
+		t.Errorf("result = %+v", result)
+	}
+}
+
+func TestOAuthService_UnknownProvider(t *testing.T) {
+	f := newFakeOIDCServer(t)
+	service, _ := newTestOAuthService(t, f, NewMockUserStore())
+	router := NewRouter()
+	router.Get("/auth/oauth/{provider}", service.LoginHandler())
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/auth/oauth/unknown", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", w.Code)
+	}
+}
+
+func TestGitHubOAuthProvider_Identity(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer gh-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/user":
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": 42, "login": "ana", "name": "Ana", "avatar_url": "https://avatars.test/42"})
+		case "/user/emails":
+			json.NewEncoder(w).Encode([]map[string]interface{}{
+				{"email": "old@example.com", "primary": false, "verified": true},
+				{"email": "ana@example.com", "primary": true, "verified": true},
+			})
+		}
+	}))
+	defer api.Close()
+	githubAPIURL = api.URL
+	t.Cleanup(func() { githubAPIURL = "https://api.github.com" })
+
+	provider := GitHubOAuthProvider("id", "secret")
+	identity, err := provider.Identity(context.Background(), api.Client(), &OAuthToken{AccessToken: "gh-token"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if identity.Provider != "github" || identity.Subject != "42" || identity.Email != "ana@example.com" || !identity.EmailVerified {
+		t.Errorf("identity = %+v", identity)
+	}
+}
+
+func TestDatabaseOAuthAccountStore_SQLite(t *testing.T) {
+	db := newTestSQLiteAuthDB(t)
+	if err := RunMigrations(db, GetOAuthMigrations()); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if err := db.Exec(ctx, db.Rebind("INSERT INTO users (id, email, password) VALUES ($1, $2, $3)"), "u-1", "ana@example.com", "hash"); err != nil {
+		t.Fatal(err)
+	}
+
+	store := NewDatabaseOAuthAccountStore(db)
+	if _, err := store.FindOAuthAccount(ctx, "google", "sub-1"); err != ErrOAuthAccountNotFound {
+		t.Fatalf("FindOAuthAccount error = %v, want ErrOAuthAccountNotFound", err)
+	}
+
+	account := &OAuthAccount{UserID: "u-1", Provider: "google", Subject: "sub-1", Email: "ana@example.com"}
+	if err := store.SaveOAuthAccount(ctx, account); err != nil || account.ID == "" {
+		t.Fatalf("SaveOAuthAccount = %+v, %v", account, err)
+	}
+	if err := store.SaveOAuthAccount(ctx, &OAuthAccount{UserID: "u-1", Provider: "google", Subject: "sub-1"}); err == nil {
+		t.Error("expected unique violation for duplicate provider subject")
+	}
+
+	account.Email = "ana.baru@example.com"
+	if err := store.SaveOAuthAccount(ctx, account); err != nil {
+		t.Fatal(err)
+	}
+	found, err := store.FindOAuthAccount(ctx, "google", "sub-1")
+	if err != nil || found.UserID != "u-1" || found.Email != "ana.baru@example.com" {
+		t.Fatalf("FindOAuthAccount = %+v, %v", found, err)
+	}
+
+	if err := store.SaveOAuthAccount(ctx, &OAuthAccount{UserID: "u-1", Provider: "github", Subject: "42"}); err != nil {
+		t.Fatal(err)
+	}
+	list, err := store.ListUserOAuthAccounts(ctx, "u-1")
+	if err != nil || len(list) != 2 {
+		t.Fatalf("ListUserOAuthAccounts = %d accounts, %v", len(list), err)
+	}
+}
+
+func TestPKCEChallenge(t *testing.T) {
+	// Contoh dari RFC 7636 Appendix B
+	if got := pkceChallenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"); got != "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM" {
+		t.Errorf("pkceChallenge = %q", got)
+	}
+}
+
+func TestIsRelativePath(t *testing.T) {
+	for target, want := range map[string]bool{
+		"/dashboard":          true,
+		"/a?b=c":              true,
+		"":                    false,
+		"dashboard":           false,
+		"//evil.test":         false,
+		"/\\evil.test":        false,
+		"https://evil.test/x": false,
+	} {
+		if got := isRelativePath(target); got != want {
+			t.Errorf("isRelativePath(%q) = %v, want %v", target, got, want)
+		}
+	}
+}
+
+func TestCacheControlMaxAge(t *testing.T) {
+	for header, want := range map[string]time.Duration{
+		"public, max-age=300, must-revalidate": 5 * time.Minute,
+		"Max-Age=\"60\"":                       time.Minute,
+		"no-cache":                             0,
+		"max-age=abc":                          0,
+		"":                                     0,
+	} {
+		if got := cacheControlMaxAge(header); got != want {
+			t.Errorf("cacheControlMaxAge(%q) = %v, want %v", header, got, want)
+		}
+	}
+}
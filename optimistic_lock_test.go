@@ -0,0 +1,80 @@
+package dim
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestDatabaseAuthUserStore_UpdateWithVersion_SQLite(t *testing.T) {
+	db := newTestSQLiteAuthDB(t)
+	ctx := context.Background()
+	store := NewDatabaseAuthUserStore(db)
+	if err := store.CreateBatch(ctx, []*User{{ID: "u-1", Email: "ana@example.com", Name: "Ana", Password: "hash"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Dua admin membaca user yang sama
+	list, _, err := store.List(ctx, ListUsersQuery{})
+	if err != nil || len(list) != 1 || list[0].Version != 1 {
+		t.Fatalf("List = %+v, %v", list, err)
+	}
+	first := *list[0]
+	second := *list[0]
+
+	first.Name = "Ana Maria"
+	if err := store.UpdateWithVersion(ctx, &first); err != nil {
+		t.Fatal(err)
+	}
+	if first.Version != 2 {
+		t.Errorf("Version = %d, want 2", first.Version)
+	}
+
+	second.Name = "Ana Lestari"
+	if err := store.UpdateWithVersion(ctx, &second); !errors.Is(err, ErrStaleObject) {
+		t.Fatalf("stale update error = %v, want ErrStaleObject", err)
+	}
+	list, _, _ = store.List(ctx, ListUsersQuery{})
+	if list[0].Name != "Ana Maria" || list[0].Version != 2 {
+		t.Errorf("stored user = %+v", list[0])
+	}
+
+	// Update biasa (misal ganti password) juga menaikkan versi
+	if err := store.Update(ctx, &first); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.UpdateWithVersion(ctx, &first); !errors.Is(err, ErrStaleObject) {
+		t.Errorf("update after Update error = %v, want ErrStaleObject", err)
+	}
+
+	if err := store.UpdateWithVersion(ctx, &User{ID: "missing"}); err == nil || errors.Is(err, ErrStaleObject) {
+		t.Errorf("missing user error = %v", err)
+	}
+}
+
+func TestAddVersionColumn_SQLite(t *testing.T) {
+	db, err := NewSQLiteDatabase(DatabaseConfig{Database: ":memory:"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	ctx := context.Background()
+	if err := db.Exec(ctx, "CREATE TABLE posts (id TEXT PRIMARY KEY); INSERT INTO posts (id) VALUES ('p-1')"); err != nil {
+		t.Fatal(err)
+	}
+
+	migration := AddVersionColumn(10, "posts")
+	if migration.Name != "add_version_to_posts" {
+		t.Errorf("Name = %q", migration.Name)
+	}
+	if err := migration.Up(db); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+	var version int64
+	if err := db.QueryRow(ctx, "SELECT version FROM posts WHERE id = 'p-1'").Scan(&version); err != nil || version != 1 {
+		t.Errorf("existing row version = %d, %v", version, err)
+	}
+	if err := migration.Down(db); err != nil {
+		t.Fatalf("Down: %v", err)
+	}
+}
@@ -0,0 +1,537 @@
+package dim
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Role bawaan organisasi, diurutkan dari hak akses terendah ke tertinggi.
+const (
+	OrgRoleMember = "member"
+	OrgRoleAdmin  = "admin"
+	OrgRoleOwner  = "owner"
+)
+
+// Nama claim JWT untuk organisasi aktif.
+const (
+	OrgIDClaim   = "org_id"
+	OrgRoleClaim = "org_role"
+)
+
+const activeOrgKey contextKey = "active_org"
+
+var (
+	// ErrOrganizationNotFound dikembalikan store jika organisasi tidak ditemukan.
+	ErrOrganizationNotFound = errors.New("organization not found")
+	// ErrMembershipNotFound dikembalikan store jika user bukan anggota organisasi.
+	ErrMembershipNotFound = errors.New("organization membership not found")
+	// ErrInvitationNotFound dikembalikan store jika token undangan tidak ditemukan.
+	ErrInvitationNotFound = errors.New("organization invitation not found")
+)
+
+// Organization merepresentasikan akun/tim yang dimiliki bersama oleh beberapa user.
+type Organization struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// OrgMembership merepresentasikan keanggotaan user di organisasi beserta role-nya.
+type OrgMembership struct {
+	OrgID     string    `json:"org_id"`
+	UserID    string    `json:"user_id"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// OrgInvitation merepresentasikan undangan bergabung ke organisasi.
+// Hanya hash token yang disimpan; token asli dikirim ke email penerima.
+type OrgInvitation struct {
+	ID         int64      `json:"id"`
+	OrgID      string     `json:"org_id"`
+	Email      string     `json:"email"`
+	Role       string     `json:"role"`
+	InvitedBy  string     `json:"invited_by"`
+	TokenHash  string     `json:"-"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	AcceptedAt *time.Time `json:"accepted_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// OrganizationStore mendefinisikan penyimpanan organisasi, keanggotaan, dan undangan.
+// Implementasi mengembalikan ErrOrganizationNotFound, ErrMembershipNotFound, atau
+// ErrInvitationNotFound (boleh di-wrap) jika data tidak ditemukan.
+type OrganizationStore interface {
+	CreateOrganization(ctx context.Context, org *Organization) error
+	FindOrganization(ctx context.Context, id string) (*Organization, error)
+
+	SaveMembership(ctx context.Context, membership *OrgMembership) error // Insert atau update role
+	FindMembership(ctx context.Context, orgID, userID string) (*OrgMembership, error)
+	ListMembers(ctx context.Context, orgID string) ([]*OrgMembership, error)
+	ListUserMemberships(ctx context.Context, userID string) ([]*OrgMembership, error) // Urut berdasarkan created_at
+	RemoveMembership(ctx context.Context, orgID, userID string) error
+
+	SaveInvitation(ctx context.Context, invitation *OrgInvitation) error
+	FindInvitation(ctx context.Context, tokenHash string) (*OrgInvitation, error)
+	// ConsumeInvitation menandai undangan diterima secara atomik hanya jika belum diterima dan
+	// belum kadaluarsa pada now. Mengembalikan ErrInvitationNotFound jika tidak ada undangan yang
+	// memenuhi syarat, sehingga request paralel dengan token yang sama hanya berhasil sekali.
+	ConsumeInvitation(ctx context.Context, tokenHash string, now time.Time) error
+}
+
+// OrganizationService menangani pembuatan organisasi, undangan, keanggotaan,
+// dan claims organisasi aktif di token.
+type OrganizationService struct {
+	store          OrganizationStore
+	roles          []string
+	mailer         Mailer
+	acceptURL      string
+	inviteExpiry   time.Duration
+	inviteSubject  string
+	inviteTemplate func(org *Organization, invitation *OrgInvitation, acceptURL string) *MailMessage
+}
+
+// NewOrganizationService membuat OrganizationService dengan role default member < admin < owner
+// dan masa berlaku undangan 7 hari.
+//
+// Parameters:
+//   - store: OrganizationStore untuk menyimpan data organisasi
+//
+// Returns:
+//   - *OrganizationService: service yang siap digunakan
+//
+// Example:
+//
+//	orgs := dim.NewOrganizationService(dim.NewDatabaseOrganizationStore(db)).
+//	    WithInvitationMailer(mailer, "https://app.example.com/invitations/accept?token=%s")
+//	authService.WithClaimsProvider(orgs.ClaimsProvider())
+func NewOrganizationService(store OrganizationStore) *OrganizationService {
+	return &OrganizationService{
+		store:         store,
+		roles:         []string{OrgRoleMember, OrgRoleAdmin, OrgRoleOwner},
+		inviteExpiry:  7 * 24 * time.Hour,
+		inviteSubject: "Undangan bergabung ke %s",
+	}
+}
+
+// WithRoles mengganti hierarki role, diurutkan dari hak akses terendah ke tertinggi.
+// Role terakhir dianggap sebagai owner (pembuat organisasi).
+func (s *OrganizationService) WithRoles(roles ...string) *OrganizationService {
+	if len(roles) > 0 {
+		s.roles = roles
+	}
+	return s
+}
+
+// WithInvitationMailer mengaktifkan pengiriman email undangan.
+// acceptURL adalah format URL halaman penerimaan undangan dengan %s untuk token.
+func (s *OrganizationService) WithInvitationMailer(mailer Mailer, acceptURL string) *OrganizationService {
+	s.mailer = mailer
+	s.acceptURL = acceptURL
+	return s
+}
+
+// WithInvitationTemplate mengganti isi email undangan default.
+func (s *OrganizationService) WithInvitationTemplate(fn func(org *Organization, invitation *OrgInvitation, acceptURL string) *MailMessage) *OrganizationService {
+	s.inviteTemplate = fn
+	return s
+}
+
+// WithInvitationExpiry mengatur masa berlaku token undangan.
+func (s *OrganizationService) WithInvitationExpiry(d time.Duration) *OrganizationService {
+	s.inviteExpiry = d
+	return s
+}
+
+// Roles mengembalikan hierarki role yang dipakai service.
+func (s *OrganizationService) Roles() []string {
+	return s.roles
+}
+
+// HasRole memeriksa apakah role memenuhi role minimum pada hierarki service.
+// Role di luar hierarki hanya cocok dengan dirinya sendiri.
+func (s *OrganizationService) HasRole(role, required string) bool {
+	return orgRoleSatisfies(s.roles, role, required)
+}
+
+// CreateOrganization membuat organisasi baru dan menjadikan pembuatnya owner.
+//
+// Parameters:
+//   - ctx: context request
+//   - name: nama organisasi
+//   - ownerID: ID user pembuat
+//
+// Returns:
+//   - *Organization: organisasi yang dibuat
+//   - error: AppError 400 jika nama kosong, atau error store
+func (s *OrganizationService) CreateOrganization(ctx context.Context, name, ownerID string) (*Organization, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, NewAppError("Validasi gagal", 400).WithFieldError("name", "name wajib diisi")
+	}
+
+	org := &Organization{ID: NewUuid().String(), Name: name}
+	if err := s.store.CreateOrganization(ctx, org); err != nil {
+		return nil, fmt.Errorf("failed to create organization: %w", err)
+	}
+
+	membership := &OrgMembership{OrgID: org.ID, UserID: ownerID, Role: s.ownerRole()}
+	if err := s.store.SaveMembership(ctx, membership); err != nil {
+		return nil, fmt.Errorf("failed to add organization owner: %w", err)
+	}
+
+	return org, nil
+}
+
+// Invite membuat undangan dan mengirim email jika mailer dikonfigurasi.
+// Pengundang wajib anggota dengan role minimal admin dan tidak dapat mengundang
+// dengan role yang lebih tinggi dari role-nya sendiri.
+//
+// Parameters:
+//   - ctx: context request
+//   - orgID: ID organisasi
+//   - inviterID: ID user yang mengundang
+//   - email: email penerima undangan
+//   - role: role yang akan diberikan saat undangan diterima
+//
+// Returns:
+//   - string: token undangan (belum di-hash) untuk dikirim manual jika tidak memakai mailer
+//   - error: AppError 400/403/404, atau error store/mailer
+func (s *OrganizationService) Invite(ctx context.Context, orgID, inviterID, email, role string) (string, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	v := NewValidator().
+		Required("email", email).
+		Email("email", email).
+		In("role", role, s.roles...)
+	if !v.IsValid() {
+		return "", NewAppError("Validasi gagal", 400).WithFieldErrors(v.ErrorMap())
+	}
+
+	org, err := s.store.FindOrganization(ctx, orgID)
+	if err != nil {
+		if errors.Is(err, ErrOrganizationNotFound) {
+			return "", NewAppError("Organisasi tidak ditemukan", 404)
+		}
+		return "", fmt.Errorf("failed to find organization: %w", err)
+	}
+
+	inviter, err := s.store.FindMembership(ctx, orgID, inviterID)
+	if err != nil || !s.HasRole(inviter.Role, s.adminRole()) || !s.HasRole(inviter.Role, role) {
+		return "", NewAppError("Anda tidak memiliki izin untuk mengundang anggota", 403)
+	}
+
+	token, err := GenerateSecureToken(32)
+	if err != nil {
+		return "", NewAppError("Gagal membuat token undangan", 500)
+	}
+
+	invitation := &OrgInvitation{
+		OrgID:     orgID,
+		Email:     email,
+		Role:      role,
+		InvitedBy: inviterID,
+		TokenHash: GenerateTokenHash(token),
+		ExpiresAt: time.Now().Add(s.inviteExpiry).UTC().Truncate(time.Second),
+	}
+	if err := s.store.SaveInvitation(ctx, invitation); err != nil {
+		return "", fmt.Errorf("failed to save invitation: %w", err)
+	}
+
+	if s.mailer != nil {
+		acceptURL := fmt.Sprintf(s.acceptURL, token)
+		msg := s.invitationMessage(org, invitation, acceptURL)
+		if err := s.mailer.Send(ctx, msg); err != nil {
+			return "", fmt.Errorf("failed to send invitation email: %w", err)
+		}
+	}
+
+	return token, nil
+}
+
+func (s *OrganizationService) invitationMessage(org *Organization, invitation *OrgInvitation, acceptURL string) *MailMessage {
+	if s.inviteTemplate != nil {
+		return s.inviteTemplate(org, invitation, acceptURL)
+	}
+
+	msg := NewMailMessage([]string{invitation.Email}, fmt.Sprintf(s.inviteSubject, org.Name))
+	msg.HTML = fmt.Sprintf(
+		`<p>Anda diundang bergabung ke <strong>%s</strong> sebagai %s.</p><p><a href="%s">Terima undangan</a></p><p>Undangan berlaku hingga %s.</p>`,
+		html.EscapeString(org.Name), html.EscapeString(invitation.Role), html.EscapeString(acceptURL),
+		invitation.ExpiresAt.Format("2 Jan 2006 15:04 MST"),
+	)
+	msg.PlainText = fmt.Sprintf(
+		"Anda diundang bergabung ke %s sebagai %s.\n\nTerima undangan: %s\n",
+		org.Name, invitation.Role, acceptURL,
+	)
+	return msg
+}
+
+// AcceptInvitation menerima undangan untuk user yang sedang login.
+// Email user harus sama dengan email undangan (case-insensitive). Jika user sudah menjadi
+// anggota, role hanya dinaikkan (tidak pernah diturunkan).
+//
+// Parameters:
+//   - ctx: context request
+//   - token: token undangan dari email
+//   - user: user yang menerima undangan
+//
+// Returns:
+//   - *OrgMembership: keanggotaan hasil penerimaan undangan
+//   - error: AppError 400 jika token tidak valid, kadaluarsa, sudah dipakai, atau email tidak cocok
+func (s *OrganizationService) AcceptInvitation(ctx context.Context, token string, user Authenticatable) (*OrgMembership, error) {
+	tokenHash := GenerateTokenHash(token)
+	invitation, err := s.store.FindInvitation(ctx, tokenHash)
+	if err != nil {
+		return nil, NewAppError("Undangan tidak valid atau telah kadaluarsa", 400)
+	}
+
+	if invitation.AcceptedAt != nil || time.Now().After(invitation.ExpiresAt) {
+		return nil, NewAppError("Undangan tidak valid atau telah kadaluarsa", 400)
+	}
+
+	if !strings.EqualFold(invitation.Email, user.GetEmail()) {
+		return nil, NewAppError("Undangan ini ditujukan untuk email lain", 403)
+	}
+
+	// Undangan dikonsumsi sebelum keanggotaan disimpan agar request paralel dengan token
+	// yang sama tidak dapat menerima undangan dua kali
+	if err := s.store.ConsumeInvitation(ctx, tokenHash, time.Now()); err != nil {
+		if errors.Is(err, ErrInvitationNotFound) {
+			return nil, NewAppError("Undangan tidak valid atau telah kadaluarsa", 400)
+		}
+		return nil, fmt.Errorf("failed to accept invitation: %w", err)
+	}
+
+	role := invitation.Role
+	if existing, err := s.store.FindMembership(ctx, invitation.OrgID, user.GetID()); err == nil && s.HasRole(existing.Role, role) {
+		role = existing.Role
+	}
+
+	membership := &OrgMembership{OrgID: invitation.OrgID, UserID: user.GetID(), Role: role}
+	if err := s.store.SaveMembership(ctx, membership); err != nil {
+		return nil, fmt.Errorf("failed to save membership: %w", err)
+	}
+
+	return membership, nil
+}
+
+// SetMemberRole mengubah role anggota. Hanya anggota dengan role minimal admin yang boleh
+// mengubah role, tidak boleh memberi role lebih tinggi dari role-nya sendiri, dan owner
+// terakhir tidak dapat diturunkan.
+func (s *OrganizationService) SetMemberRole(ctx context.Context, orgID, actorID, userID, role string) error {
+	if !slices.Contains(s.roles, role) {
+		return NewAppError("Validasi gagal", 400).WithFieldError("role", "role tidak valid")
+	}
+
+	actor, err := s.store.FindMembership(ctx, orgID, actorID)
+	if err != nil || !s.HasRole(actor.Role, s.adminRole()) || !s.HasRole(actor.Role, role) {
+		return NewAppError("Anda tidak memiliki izin untuk mengubah role anggota", 403)
+	}
+
+	member, err := s.store.FindMembership(ctx, orgID, userID)
+	if err != nil {
+		return NewAppError("Anggota tidak ditemukan", 404)
+	}
+	if !s.HasRole(actor.Role, member.Role) {
+		return NewAppError("Anda tidak memiliki izin untuk mengubah role anggota", 403)
+	}
+
+	if member.Role == s.ownerRole() && role != s.ownerRole() {
+		if err := s.ensureAnotherOwner(ctx, orgID, userID); err != nil {
+			return err
+		}
+	}
+
+	member.Role = role
+	return s.store.SaveMembership(ctx, member)
+}
+
+// RemoveMember mengeluarkan anggota dari organisasi. Anggota boleh keluar sendiri;
+// mengeluarkan anggota lain membutuhkan role minimal admin dan minimal setara role target.
+// Owner terakhir tidak dapat dikeluarkan.
+func (s *OrganizationService) RemoveMember(ctx context.Context, orgID, actorID, userID string) error {
+	member, err := s.store.FindMembership(ctx, orgID, userID)
+	if err != nil {
+		return NewAppError("Anggota tidak ditemukan", 404)
+	}
+
+	if actorID != userID {
+		actor, err := s.store.FindMembership(ctx, orgID, actorID)
+		if err != nil || !s.HasRole(actor.Role, s.adminRole()) || !s.HasRole(actor.Role, member.Role) {
+			return NewAppError("Anda tidak memiliki izin untuk mengeluarkan anggota", 403)
+		}
+	}
+
+	if member.Role == s.ownerRole() {
+		if err := s.ensureAnotherOwner(ctx, orgID, userID); err != nil {
+			return err
+		}
+	}
+
+	return s.store.RemoveMembership(ctx, orgID, userID)
+}
+
+func (s *OrganizationService) ensureAnotherOwner(ctx context.Context, orgID, userID string) error {
+	members, err := s.store.ListMembers(ctx, orgID)
+	if err != nil {
+		return fmt.Errorf("failed to list members: %w", err)
+	}
+	for _, m := range members {
+		if m.UserID != userID && m.Role == s.ownerRole() {
+			return nil
+		}
+	}
+	return NewAppError("Organisasi harus memiliki minimal satu owner", 409)
+}
+
+// ClaimsProvider mengembalikan ClaimsProvider yang menambahkan org_id dan org_role ke token.
+// Organisasi aktif diambil dari WithActiveOrganization pada context; jika tidak di-set,
+// organisasi yang paling lama diikuti user dipakai (created_at keanggotaan terlama, lalu ID
+// organisasi terkecil jika sama), sehingga claims default selalu sama di setiap login.
+// User tanpa organisasi tidak mendapat claims organisasi.
+// Jika organisasi yang diminta bukan milik user, AuthService mengembalikan error 500 "Gagal membuat claims"
+// sehingga endpoint switch sebaiknya memvalidasi keanggotaan terlebih dahulu via Membership.
+//
+// Example:
+//
+//	authService.WithClaimsProvider(orgs.ClaimsProvider())
+//
+//	// Endpoint switch organisasi
+//	ctx := dim.WithActiveOrganization(r.Context(), orgID)
+//	access, refresh, err := authService.RefreshToken(ctx, refreshToken)
+func (s *OrganizationService) ClaimsProvider() ClaimsProvider {
+	return func(ctx context.Context, user Authenticatable) (map[string]interface{}, error) {
+		var membership *OrgMembership
+
+		if orgID, ok := ActiveOrganization(ctx); ok {
+			m, err := s.store.FindMembership(ctx, orgID, user.GetID())
+			if err != nil {
+				return nil, fmt.Errorf("user is not a member of organization %s: %w", orgID, err)
+			}
+			membership = m
+		} else {
+			memberships, err := s.store.ListUserMemberships(ctx, user.GetID())
+			if err != nil {
+				return nil, fmt.Errorf("failed to list memberships: %w", err)
+			}
+			if len(memberships) == 0 {
+				return nil, nil
+			}
+			membership = oldestMembership(memberships)
+		}
+
+		return map[string]interface{}{
+			OrgIDClaim:   membership.OrgID,
+			OrgRoleClaim: membership.Role,
+		}, nil
+	}
+}
+
+// oldestMembership memilih keanggotaan dengan created_at terlama tanpa bergantung pada urutan dari store.
+func oldestMembership(memberships []*OrgMembership) *OrgMembership {
+	oldest := memberships[0]
+	for _, m := range memberships[1:] {
+		if m.CreatedAt.Before(oldest.CreatedAt) || (m.CreatedAt.Equal(oldest.CreatedAt) && m.OrgID < oldest.OrgID) {
+			oldest = m
+		}
+	}
+	return oldest
+}
+
+// Membership mengambil keanggotaan user di organisasi.
+func (s *OrganizationService) Membership(ctx context.Context, orgID, userID string) (*OrgMembership, error) {
+	return s.store.FindMembership(ctx, orgID, userID)
+}
+
+// Memberships mengembalikan semua organisasi yang diikuti user.
+func (s *OrganizationService) Memberships(ctx context.Context, userID string) ([]*OrgMembership, error) {
+	return s.store.ListUserMemberships(ctx, userID)
+}
+
+func (s *OrganizationService) ownerRole() string {
+	return s.roles[len(s.roles)-1]
+}
+
+// adminRole adalah role minimum untuk mengelola anggota: "admin" jika ada di hierarki, selain itu owner.
+func (s *OrganizationService) adminRole() string {
+	if slices.Contains(s.roles, OrgRoleAdmin) {
+		return OrgRoleAdmin
+	}
+	return s.ownerRole()
+}
+
+// WithActiveOrganization menyimpan ID organisasi aktif di context untuk ClaimsProvider.
+func WithActiveOrganization(ctx context.Context, orgID string) context.Context {
+	return context.WithValue(ctx, activeOrgKey, orgID)
+}
+
+// ActiveOrganization mengambil ID organisasi aktif yang di-set oleh WithActiveOrganization.
+func ActiveOrganization(ctx context.Context) (string, bool) {
+	orgID, ok := ctx.Value(activeOrgKey).(string)
+	return orgID, ok && orgID != ""
+}
+
+// GetOrgID mengambil ID organisasi aktif dari claims token request.
+func GetOrgID(r *http.Request) string {
+	orgID, _ := GetClaims(r)[OrgIDClaim].(string)
+	return orgID
+}
+
+// GetOrgRole mengambil role user di organisasi aktif dari claims token request.
+func GetOrgRole(r *http.Request) string {
+	role, _ := GetClaims(r)[OrgRoleClaim].(string)
+	return role
+}
+
+// DefaultOrgRoles adalah hierarki role yang dipakai RequireOrgRole, dari terendah ke tertinggi.
+// Samakan dengan OrganizationService.WithRoles jika memakai role custom.
+var DefaultOrgRoles = []string{OrgRoleMember, OrgRoleAdmin, OrgRoleOwner}
+
+// RequireOrgRole membuat middleware yang mewajibkan role organisasi minimal tertentu
+// berdasarkan claim org_role (hierarki DefaultOrgRoles). Pasang setelah RequireAuth.
+// Mengembalikan 403 jika token tidak memiliki organisasi aktif atau role tidak mencukupi.
+// Karena role dibaca dari token, perubahan role berlaku setelah token di-refresh.
+//
+// Parameters:
+//   - role: role minimum, misal "admin" atau "owner"
+//
+// Returns:
+//   - MiddlewareFunc: middleware pemeriksa role organisasi
+//
+// Example:
+//
+//	api := router.Group("/api", dim.RequireAuth(jwtManager, blocklist))
+//	api.Delete("/org", deleteOrgHandler, dim.RequireOrgRole("owner"))
+func RequireOrgRole(role string) MiddlewareFunc {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if GetOrgID(r) == "" || !orgRoleSatisfies(DefaultOrgRoles, GetOrgRole(r), role) {
+				Forbidden(w, "Role organisasi tidak mencukupi")
+				return
+			}
+			next(w, r)
+		}
+	}
+}
+
+func orgRoleSatisfies(roles []string, role, required string) bool {
+	have, want := -1, -1
+	for i, r := range roles {
+		if r == role {
+			have = i
+		}
+		if r == required {
+			want = i
+		}
+	}
+	if have < 0 || want < 0 {
+		return role != "" && role == required
+	}
+	return have >= want
+}
@@ -0,0 +1,190 @@
+package dim
+
+import (
+	"context"
+)
+
+// GetOrganizationMigrations mengembalikan daftar migrasi modul organisasi.
+// Modul ini opsional sehingga tidak termasuk dalam GetFrameworkMigrations;
+// gabungkan secara manual jika menggunakan OrganizationService.
+// Menggunakan versi 101-103 agar tidak bentrok dengan migrasi framework maupun aplikasi.
+//
+// Example:
+//
+//	migrations := append(dim.GetFrameworkMigrations(), dim.GetOrganizationMigrations()...)
+//	dim.RunMigrations(db, migrations)
+func GetOrganizationMigrations() []Migration {
+	return []Migration{
+		{
+			Version: 101,
+			Name:    "create_organizations_table",
+			Up:      CreateOrganizationsTable,
+			Down:    DropOrganizationsTable,
+		},
+		{
+			Version: 102,
+			Name:    "create_organization_members_table",
+			Up:      CreateOrganizationMembersTable,
+			Down:    DropOrganizationMembersTable,
+		},
+		{
+			Version: 103,
+			Name:    "create_organization_invitations_table",
+			Up:      CreateOrganizationInvitationsTable,
+			Down:    DropOrganizationInvitationsTable,
+		},
+	}
+}
+
+// CreateOrganizationsTable membuat tabel organizations.
+func CreateOrganizationsTable(db Database) error {
+	var query string
+	if db.DriverName() == "sqlite" {
+		query = `
+			CREATE TABLE IF NOT EXISTS organizations (
+				id TEXT PRIMARY KEY,
+				name TEXT NOT NULL,
+				created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+			);
+		`
+	} else if db.DriverName() == "mysql" {
+		query = `
+			CREATE TABLE IF NOT EXISTS organizations (
+				id CHAR(36) PRIMARY KEY,
+				name VARCHAR(255) NOT NULL,
+				created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+		`
+	} else {
+		query = `
+			CREATE TABLE IF NOT EXISTS organizations (
+				id UUID PRIMARY KEY,
+				name VARCHAR(255) NOT NULL,
+				created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+			);
+		`
+	}
+	return db.Exec(context.Background(), query)
+}
+
+// DropOrganizationsTable menghapus tabel organizations.
+func DropOrganizationsTable(db Database) error {
+	query := "DROP TABLE IF EXISTS organizations CASCADE"
+	if db.DriverName() == "sqlite" {
+		query = "DROP TABLE IF EXISTS organizations"
+	}
+	return db.Exec(context.Background(), query)
+}
+
+// CreateOrganizationMembersTable membuat tabel organization_members.
+func CreateOrganizationMembersTable(db Database) error {
+	var query string
+	if db.DriverName() == "sqlite" {
+		query = `
+			CREATE TABLE IF NOT EXISTS organization_members (
+				organization_id TEXT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+				user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+				role TEXT NOT NULL,
+				created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				PRIMARY KEY (organization_id, user_id)
+			);
+			CREATE INDEX IF NOT EXISTS idx_organization_members_user_id ON organization_members(user_id);
+		`
+	} else if db.DriverName() == "mysql" {
+		query = `
+			CREATE TABLE IF NOT EXISTS organization_members (
+				organization_id CHAR(36) NOT NULL,
+				user_id CHAR(36) NOT NULL,
+				role VARCHAR(50) NOT NULL,
+				created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				PRIMARY KEY (organization_id, user_id),
+				INDEX idx_organization_members_user_id (user_id),
+				FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE,
+				FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+		`
+	} else {
+		query = `
+			CREATE TABLE IF NOT EXISTS organization_members (
+				organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+				user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+				role VARCHAR(50) NOT NULL,
+				created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				PRIMARY KEY (organization_id, user_id)
+			);
+			CREATE INDEX IF NOT EXISTS idx_organization_members_user_id ON organization_members(user_id);
+		`
+	}
+	return db.Exec(context.Background(), query)
+}
+
+// DropOrganizationMembersTable menghapus tabel organization_members.
+func DropOrganizationMembersTable(db Database) error {
+	query := "DROP TABLE IF EXISTS organization_members CASCADE"
+	if db.DriverName() == "sqlite" {
+		query = "DROP TABLE IF EXISTS organization_members"
+	}
+	return db.Exec(context.Background(), query)
+}
+
+// CreateOrganizationInvitationsTable membuat tabel organization_invitations.
+func CreateOrganizationInvitationsTable(db Database) error {
+	var query string
+	if db.DriverName() == "sqlite" {
+		query = `
+			CREATE TABLE IF NOT EXISTS organization_invitations (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				organization_id TEXT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+				email TEXT NOT NULL,
+				role TEXT NOT NULL,
+				invited_by TEXT NOT NULL,
+				token_hash TEXT NOT NULL UNIQUE,
+				expires_at TIMESTAMP NOT NULL,
+				accepted_at TIMESTAMP NULL,
+				created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+			);
+			CREATE INDEX IF NOT EXISTS idx_organization_invitations_org_id ON organization_invitations(organization_id);
+		`
+	} else if db.DriverName() == "mysql" {
+		query = `
+			CREATE TABLE IF NOT EXISTS organization_invitations (
+				id BIGINT AUTO_INCREMENT PRIMARY KEY,
+				organization_id CHAR(36) NOT NULL,
+				email VARCHAR(255) NOT NULL,
+				role VARCHAR(50) NOT NULL,
+				invited_by CHAR(36) NOT NULL,
+				token_hash VARCHAR(255) NOT NULL UNIQUE,
+				expires_at DATETIME NOT NULL,
+				accepted_at DATETIME NULL,
+				created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				INDEX idx_organization_invitations_org_id (organization_id),
+				FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+		`
+	} else {
+		query = `
+			CREATE TABLE IF NOT EXISTS organization_invitations (
+				id BIGSERIAL PRIMARY KEY,
+				organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+				email VARCHAR(255) NOT NULL,
+				role VARCHAR(50) NOT NULL,
+				invited_by UUID NOT NULL,
+				token_hash VARCHAR(255) NOT NULL UNIQUE,
+				expires_at TIMESTAMP NOT NULL,
+				accepted_at TIMESTAMP NULL,
+				created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+			);
+			CREATE INDEX IF NOT EXISTS idx_organization_invitations_org_id ON organization_invitations(organization_id);
+		`
+	}
+	return db.Exec(context.Background(), query)
+}
+
+// DropOrganizationInvitationsTable menghapus tabel organization_invitations.
+func DropOrganizationInvitationsTable(db Database) error {
+	query := "DROP TABLE IF EXISTS organization_invitations CASCADE"
+	if db.DriverName() == "sqlite" {
+		query = "DROP TABLE IF EXISTS organization_invitations"
+	}
+	return db.Exec(context.Background(), query)
+}
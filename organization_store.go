@@ -0,0 +1,365 @@
+package dim
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DatabaseOrganizationStore is the SQL implementation of OrganizationStore (PostgreSQL, MySQL & SQLite)
+type DatabaseOrganizationStore struct {
+	db Database
+}
+
+// NewDatabaseOrganizationStore creates a new SQL organization store.
+// Requires the tables created by GetOrganizationMigrations.
+func NewDatabaseOrganizationStore(db Database) *DatabaseOrganizationStore {
+	return &DatabaseOrganizationStore{db: db}
+}
+
+// CreateOrganization saves a new organization to the database.
+func (s *DatabaseOrganizationStore) CreateOrganization(ctx context.Context, org *Organization) error {
+	org.CreatedAt = time.Now().UTC().Truncate(time.Second)
+	query := `INSERT INTO organizations (id, name, created_at) VALUES ($1, $2, $3)`
+
+	if err := s.db.Exec(ctx, s.db.Rebind(query), org.ID, org.Name, org.CreatedAt); err != nil {
+		return fmt.Errorf("failed to create organization: %w", err)
+	}
+
+	return nil
+}
+
+// FindOrganization finds an organization by ID.
+func (s *DatabaseOrganizationStore) FindOrganization(ctx context.Context, id string) (*Organization, error) {
+	org := &Organization{}
+	query := `SELECT id, name, created_at FROM organizations WHERE id = $1`
+
+	err := s.db.QueryRow(ctx, s.db.Rebind(query), id).Scan(&org.ID, &org.Name, &org.CreatedAt)
+	if err != nil {
+		if isNoRows(err) {
+			return nil, ErrOrganizationNotFound
+		}
+		return nil, fmt.Errorf("failed to find organization: %w", err)
+	}
+
+	return org, nil
+}
+
+// SaveMembership inserts a membership or updates the role of an existing one.
+func (s *DatabaseOrganizationStore) SaveMembership(ctx context.Context, membership *OrgMembership) error {
+	now := time.Now().UTC().Truncate(time.Second)
+	var err error
+	if s.db.DriverName() == "mysql" {
+		// organization_members has no AUTO_INCREMENT id to emulate RETURNING; read created_at back instead.
+		err = s.db.WithTx(ctx, func(ctx context.Context, tx Tx) error {
+			query := `INSERT INTO organization_members (organization_id, user_id, role, created_at)
+				 VALUES ($1, $2, $3, $4)
+				 ON DUPLICATE KEY UPDATE role = $3`
+			if err := tx.Exec(ctx, query, membership.OrgID, membership.UserID, membership.Role, now); err != nil {
+				return err
+			}
+			query = `SELECT created_at FROM organization_members WHERE organization_id = $1 AND user_id = $2`
+			return tx.QueryRow(ctx, query, membership.OrgID, membership.UserID).Scan(&membership.CreatedAt)
+		})
+	} else {
+		query := `INSERT INTO organization_members (organization_id, user_id, role, created_at)
+			 VALUES ($1, $2, $3, $4)
+			 ON CONFLICT (organization_id, user_id) DO UPDATE SET role = excluded.role
+			 RETURNING created_at`
+		err = s.db.QueryRow(ctx, s.db.Rebind(query),
+			membership.OrgID,
+			membership.UserID,
+			membership.Role,
+			now,
+		).Scan(&membership.CreatedAt)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to save membership: %w", err)
+	}
+
+	return nil
+}
+
+// FindMembership finds a user's membership in an organization.
+func (s *DatabaseOrganizationStore) FindMembership(ctx context.Context, orgID, userID string) (*OrgMembership, error) {
+	m := &OrgMembership{}
+	query := `SELECT organization_id, user_id, role, created_at
+		 FROM organization_members WHERE organization_id = $1 AND user_id = $2`
+
+	err := s.db.QueryRow(ctx, s.db.Rebind(query), orgID, userID).Scan(&m.OrgID, &m.UserID, &m.Role, &m.CreatedAt)
+	if err != nil {
+		if isNoRows(err) {
+			return nil, ErrMembershipNotFound
+		}
+		return nil, fmt.Errorf("failed to find membership: %w", err)
+	}
+
+	return m, nil
+}
+
+// ListMembers lists all members of an organization.
+func (s *DatabaseOrganizationStore) ListMembers(ctx context.Context, orgID string) ([]*OrgMembership, error) {
+	query := `SELECT organization_id, user_id, role, created_at
+		 FROM organization_members WHERE organization_id = $1 ORDER BY created_at, user_id`
+	return s.listMemberships(ctx, query, orgID)
+}
+
+// ListUserMemberships lists all organizations a user belongs to, oldest first.
+func (s *DatabaseOrganizationStore) ListUserMemberships(ctx context.Context, userID string) ([]*OrgMembership, error) {
+	query := `SELECT organization_id, user_id, role, created_at
+		 FROM organization_members WHERE user_id = $1 ORDER BY created_at, organization_id`
+	return s.listMemberships(ctx, query, userID)
+}
+
+func (s *DatabaseOrganizationStore) listMemberships(ctx context.Context, query string, arg string) ([]*OrgMembership, error) {
+	rows, err := s.db.Query(ctx, s.db.Rebind(query), arg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list memberships: %w", err)
+	}
+	defer rows.Close()
+
+	var memberships []*OrgMembership
+	for rows.Next() {
+		m := &OrgMembership{}
+		if err := rows.Scan(&m.OrgID, &m.UserID, &m.Role, &m.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan membership: %w", err)
+		}
+		memberships = append(memberships, m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list memberships: %w", err)
+	}
+
+	return memberships, nil
+}
+
+// RemoveMembership removes a user from an organization.
+func (s *DatabaseOrganizationStore) RemoveMembership(ctx context.Context, orgID, userID string) error {
+	query := `DELETE FROM organization_members WHERE organization_id = $1 AND user_id = $2`
+
+	if err := s.db.Exec(ctx, s.db.Rebind(query), orgID, userID); err != nil {
+		return fmt.Errorf("failed to remove membership: %w", err)
+	}
+
+	return nil
+}
+
+// SaveInvitation saves an organization invitation to the database.
+func (s *DatabaseOrganizationStore) SaveInvitation(ctx context.Context, invitation *OrgInvitation) error {
+	now := time.Now().UTC().Truncate(time.Second)
+	query := `INSERT INTO organization_invitations (organization_id, email, role, invited_by, token_hash, expires_at, created_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7)
+		 RETURNING id, created_at`
+
+	err := s.db.QueryRow(ctx, s.db.Rebind(query),
+		invitation.OrgID,
+		invitation.Email,
+		invitation.Role,
+		invitation.InvitedBy,
+		invitation.TokenHash,
+		invitation.ExpiresAt.UTC().Truncate(time.Second),
+		now,
+	).Scan(&invitation.ID, &invitation.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to save invitation: %w", err)
+	}
+
+	return nil
+}
+
+// FindInvitation finds an invitation by token hash.
+func (s *DatabaseOrganizationStore) FindInvitation(ctx context.Context, tokenHash string) (*OrgInvitation, error) {
+	inv := &OrgInvitation{}
+	query := `SELECT id, organization_id, email, role, invited_by, token_hash, expires_at, accepted_at, created_at
+		 FROM organization_invitations WHERE token_hash = $1`
+
+	err := s.db.QueryRow(ctx, s.db.Rebind(query), tokenHash).Scan(
+		&inv.ID, &inv.OrgID, &inv.Email, &inv.Role, &inv.InvitedBy, &inv.TokenHash,
+		&inv.ExpiresAt, &inv.AcceptedAt, &inv.CreatedAt,
+	)
+	if err != nil {
+		if isNoRows(err) {
+			return nil, ErrInvitationNotFound
+		}
+		return nil, fmt.Errorf("failed to find invitation: %w", err)
+	}
+
+	return inv, nil
+}
+
+// ConsumeInvitation atomically marks a pending, unexpired invitation as accepted.
+// Returns ErrInvitationNotFound if no such invitation exists.
+func (s *DatabaseOrganizationStore) ConsumeInvitation(ctx context.Context, tokenHash string, now time.Time) error {
+	now = now.UTC().Truncate(time.Second)
+	var id int64
+	var err error
+	if s.db.DriverName() == "mysql" {
+		// MySQL does not support UPDATE ... RETURNING; lock the row within a transaction instead.
+		err = s.db.WithTx(ctx, func(ctx context.Context, tx Tx) error {
+			query := `SELECT id FROM organization_invitations
+				 WHERE token_hash = $1 AND accepted_at IS NULL AND expires_at > $2 FOR UPDATE`
+			if err := tx.QueryRow(ctx, query, tokenHash, now).Scan(&id); err != nil {
+				return err
+			}
+			return tx.Exec(ctx, `UPDATE organization_invitations SET accepted_at = $1 WHERE id = $2`, now, id)
+		})
+	} else {
+		query := `UPDATE organization_invitations SET accepted_at = $1
+			 WHERE token_hash = $2 AND accepted_at IS NULL AND expires_at > $3
+			 RETURNING id`
+		err = s.db.QueryRow(ctx, s.db.Rebind(query), now, tokenHash, now).Scan(&id)
+	}
+	if err != nil {
+		if isNoRows(err) {
+			return ErrInvitationNotFound
+		}
+		return fmt.Errorf("failed to accept invitation: %w", err)
+	}
+
+	return nil
+}
+
+// MockOrganizationStore is a mock implementation for testing
+type MockOrganizationStore struct {
+	mu          sync.RWMutex
+	orgs        map[string]*Organization
+	members     map[string]*OrgMembership
+	invitations map[string]*OrgInvitation
+}
+
+// NewMockOrganizationStore creates a new mock organization store.
+func NewMockOrganizationStore() *MockOrganizationStore {
+	return &MockOrganizationStore{
+		orgs:        make(map[string]*Organization),
+		members:     make(map[string]*OrgMembership),
+		invitations: make(map[string]*OrgInvitation),
+	}
+}
+
+func mockMembershipKey(orgID, userID string) string {
+	return orgID + "\x00" + userID
+}
+
+// CreateOrganization saves an organization in mock store.
+func (s *MockOrganizationStore) CreateOrganization(ctx context.Context, org *Organization) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	org.CreatedAt = time.Now()
+	s.orgs[org.ID] = org
+	return nil
+}
+
+// FindOrganization finds an organization in mock store.
+func (s *MockOrganizationStore) FindOrganization(ctx context.Context, id string) (*Organization, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	org, exists := s.orgs[id]
+	if !exists {
+		return nil, ErrOrganizationNotFound
+	}
+	return org, nil
+}
+
+// SaveMembership inserts or updates a membership in mock store.
+func (s *MockOrganizationStore) SaveMembership(ctx context.Context, membership *OrgMembership) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := mockMembershipKey(membership.OrgID, membership.UserID)
+	if existing, exists := s.members[key]; exists {
+		membership.CreatedAt = existing.CreatedAt
+	} else {
+		membership.CreatedAt = time.Now()
+	}
+	copied := *membership
+	s.members[key] = &copied
+	return nil
+}
+
+// FindMembership finds a membership in mock store.
+func (s *MockOrganizationStore) FindMembership(ctx context.Context, orgID, userID string) (*OrgMembership, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	m, exists := s.members[mockMembershipKey(orgID, userID)]
+	if !exists {
+		return nil, ErrMembershipNotFound
+	}
+	copied := *m
+	return &copied, nil
+}
+
+// ListMembers lists organization members in mock store.
+func (s *MockOrganizationStore) ListMembers(ctx context.Context, orgID string) ([]*OrgMembership, error) {
+	return s.filterMemberships(func(m *OrgMembership) bool { return m.OrgID == orgID }), nil
+}
+
+// ListUserMemberships lists a user's memberships in mock store.
+func (s *MockOrganizationStore) ListUserMemberships(ctx context.Context, userID string) ([]*OrgMembership, error) {
+	return s.filterMemberships(func(m *OrgMembership) bool { return m.UserID == userID }), nil
+}
+
+func (s *MockOrganizationStore) filterMemberships(match func(m *OrgMembership) bool) []*OrgMembership {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var result []*OrgMembership
+	for _, m := range s.members {
+		if match(m) {
+			copied := *m
+			result = append(result, &copied)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].CreatedAt.Equal(result[j].CreatedAt) {
+			return result[i].CreatedAt.Before(result[j].CreatedAt)
+		}
+		return mockMembershipKey(result[i].OrgID, result[i].UserID) < mockMembershipKey(result[j].OrgID, result[j].UserID)
+	})
+	return result
+}
+
+// RemoveMembership removes a membership in mock store.
+func (s *MockOrganizationStore) RemoveMembership(ctx context.Context, orgID, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.members, mockMembershipKey(orgID, userID))
+	return nil
+}
+
+// SaveInvitation saves an invitation in mock store.
+func (s *MockOrganizationStore) SaveInvitation(ctx context.Context, invitation *OrgInvitation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	invitation.ID = int64(len(s.invitations) + 1)
+	invitation.CreatedAt = time.Now()
+	invitation.Email = strings.ToLower(invitation.Email)
+	s.invitations[invitation.TokenHash] = invitation
+	return nil
+}
+
+// FindInvitation finds an invitation in mock store.
+func (s *MockOrganizationStore) FindInvitation(ctx context.Context, tokenHash string) (*OrgInvitation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	inv, exists := s.invitations[tokenHash]
+	if !exists {
+		return nil, ErrInvitationNotFound
+	}
+	return inv, nil
+}
+
+// ConsumeInvitation marks a pending, unexpired invitation as accepted in mock store.
+func (s *MockOrganizationStore) ConsumeInvitation(ctx context.Context, tokenHash string, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	inv, exists := s.invitations[tokenHash]
+	if !exists || inv.AcceptedAt != nil || !now.Before(inv.ExpiresAt) {
+		return ErrInvitationNotFound
+	}
+	inv.AcceptedAt = &now
+	return nil
+}
@@ -0,0 +1,333 @@
+package dim
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+type captureOrgMailer struct {
+	sent []*MailMessage
+}
+
+func (m *captureOrgMailer) Send(ctx context.Context, msg *MailMessage) error {
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+func orgAppStatus(t *testing.T, err error) int {
+	t.Helper()
+	appErr, ok := AsAppError(err)
+	if !ok {
+		t.Fatalf("expected *AppError, got %T: %v", err, err)
+	}
+	return appErr.StatusCode
+}
+
+func TestOrganizationService_CreateOrganization(t *testing.T) {
+	store := NewMockOrganizationStore()
+	svc := NewOrganizationService(store)
+	ctx := context.Background()
+
+	org, err := svc.CreateOrganization(ctx, "  Acme  ", "user-1")
+	if err != nil {
+		t.Fatalf("CreateOrganization error: %v", err)
+	}
+	if org.Name != "Acme" || org.ID == "" {
+		t.Errorf("unexpected organization %+v", org)
+	}
+
+	m, err := store.FindMembership(ctx, org.ID, "user-1")
+	if err != nil || m.Role != OrgRoleOwner {
+		t.Errorf("expected creator to be owner, got %+v, %v", m, err)
+	}
+
+	if _, err := svc.CreateOrganization(ctx, " ", "user-1"); orgAppStatus(t, err) != 400 {
+		t.Errorf("expected 400 for empty name")
+	}
+}
+
+func TestOrganizationService_InviteAndAccept(t *testing.T) {
+	store := NewMockOrganizationStore()
+	mailer := &captureOrgMailer{}
+	svc := NewOrganizationService(store).
+		WithInvitationMailer(mailer, "https://app.test/invite?token=%s")
+	ctx := context.Background()
+
+	org, _ := svc.CreateOrganization(ctx, "Acme", "owner-1")
+
+	token, err := svc.Invite(ctx, org.ID, "owner-1", "Bob@Example.com", OrgRoleAdmin)
+	if err != nil {
+		t.Fatalf("Invite error: %v", err)
+	}
+	if len(mailer.sent) != 1 {
+		t.Fatalf("expected 1 email, got %d", len(mailer.sent))
+	}
+	msg := mailer.sent[0]
+	if msg.To[0] != "bob@example.com" || !strings.Contains(msg.Subject, "Acme") {
+		t.Errorf("unexpected message %+v", msg)
+	}
+	if !strings.Contains(msg.HTML, "https://app.test/invite?token="+token) {
+		t.Errorf("expected accept URL in email, got %q", msg.HTML)
+	}
+
+	// Email berbeda ditolak
+	other := &MockUser{ID: "user-9", Email: "eve@example.com"}
+	if _, err := svc.AcceptInvitation(ctx, token, other); orgAppStatus(t, err) != 403 {
+		t.Errorf("expected 403 for mismatched email")
+	}
+
+	bob := &MockUser{ID: "user-2", Email: "bob@example.com"}
+	m, err := svc.AcceptInvitation(ctx, token, bob)
+	if err != nil {
+		t.Fatalf("AcceptInvitation error: %v", err)
+	}
+	if m.Role != OrgRoleAdmin || m.OrgID != org.ID {
+		t.Errorf("unexpected membership %+v", m)
+	}
+
+	// Token tidak dapat dipakai dua kali
+	if _, err := svc.AcceptInvitation(ctx, token, bob); orgAppStatus(t, err) != 400 {
+		t.Errorf("expected 400 for reused invitation")
+	}
+}
+
+func TestOrganizationService_InvitePermissions(t *testing.T) {
+	store := NewMockOrganizationStore()
+	svc := NewOrganizationService(store)
+	ctx := context.Background()
+
+	org, _ := svc.CreateOrganization(ctx, "Acme", "owner-1")
+	store.SaveMembership(ctx, &OrgMembership{OrgID: org.ID, UserID: "admin-1", Role: OrgRoleAdmin})
+	store.SaveMembership(ctx, &OrgMembership{OrgID: org.ID, UserID: "member-1", Role: OrgRoleMember})
+
+	tests := []struct {
+		name      string
+		inviterID string
+		email     string
+		role      string
+		want      int
+	}{
+		{"member cannot invite", "member-1", "a@example.com", OrgRoleMember, 403},
+		{"admin cannot invite owner", "admin-1", "a@example.com", OrgRoleOwner, 403},
+		{"non member cannot invite", "stranger", "a@example.com", OrgRoleMember, 403},
+		{"invalid email", "owner-1", "not-an-email", OrgRoleMember, 400},
+		{"invalid role", "owner-1", "a@example.com", "superuser", 400},
+		{"admin invites member", "admin-1", "a@example.com", OrgRoleMember, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.Invite(ctx, org.ID, tt.inviterID, tt.email, tt.role)
+			if tt.want == 0 {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if got := orgAppStatus(t, err); got != tt.want {
+				t.Errorf("status = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := svc.Invite(ctx, "missing", "owner-1", "a@example.com", OrgRoleMember); orgAppStatus(t, err) != 404 {
+		t.Errorf("expected 404 for unknown organization")
+	}
+}
+
+func TestOrganizationService_ExpiredInvitation(t *testing.T) {
+	store := NewMockOrganizationStore()
+	svc := NewOrganizationService(store).WithInvitationExpiry(-time.Minute)
+	ctx := context.Background()
+
+	org, _ := svc.CreateOrganization(ctx, "Acme", "owner-1")
+	token, err := svc.Invite(ctx, org.ID, "owner-1", "bob@example.com", OrgRoleMember)
+	if err != nil {
+		t.Fatalf("Invite error: %v", err)
+	}
+
+	bob := &MockUser{ID: "user-2", Email: "bob@example.com"}
+	if _, err := svc.AcceptInvitation(ctx, token, bob); orgAppStatus(t, err) != 400 {
+		t.Errorf("expected 400 for expired invitation")
+	}
+}
+
+func TestOrganizationService_LastOwnerProtection(t *testing.T) {
+	store := NewMockOrganizationStore()
+	svc := NewOrganizationService(store)
+	ctx := context.Background()
+
+	org, _ := svc.CreateOrganization(ctx, "Acme", "owner-1")
+	store.SaveMembership(ctx, &OrgMembership{OrgID: org.ID, UserID: "admin-1", Role: OrgRoleAdmin})
+
+	if err := svc.RemoveMember(ctx, org.ID, "owner-1", "owner-1"); orgAppStatus(t, err) != 409 {
+		t.Errorf("expected 409 when last owner leaves")
+	}
+	if err := svc.SetMemberRole(ctx, org.ID, "owner-1", "owner-1", OrgRoleMember); orgAppStatus(t, err) != 409 {
+		t.Errorf("expected 409 when demoting last owner")
+	}
+	if err := svc.RemoveMember(ctx, org.ID, "admin-1", "owner-1"); orgAppStatus(t, err) != 403 {
+		t.Errorf("expected 403 when admin removes owner")
+	}
+
+	if err := svc.SetMemberRole(ctx, org.ID, "owner-1", "admin-1", OrgRoleOwner); err != nil {
+		t.Fatalf("SetMemberRole error: %v", err)
+	}
+	if err := svc.RemoveMember(ctx, org.ID, "owner-1", "owner-1"); err != nil {
+		t.Errorf("expected owner to leave once another owner exists, got %v", err)
+	}
+	if _, err := store.FindMembership(ctx, org.ID, "owner-1"); err == nil {
+		t.Error("expected membership to be removed")
+	}
+}
+
+func TestOrganizationService_ClaimsProvider(t *testing.T) {
+	store := NewMockOrganizationStore()
+	svc := NewOrganizationService(store)
+	ctx := context.Background()
+
+	first, _ := svc.CreateOrganization(ctx, "First", "user-1")
+	second, _ := svc.CreateOrganization(ctx, "Second", "other")
+	store.SaveMembership(ctx, &OrgMembership{OrgID: second.ID, UserID: "user-1", Role: OrgRoleMember})
+
+	provider := svc.ClaimsProvider()
+	user := &MockUser{ID: "user-1", Email: "user@example.com"}
+
+	claims, err := provider(ctx, user)
+	if err != nil {
+		t.Fatalf("provider error: %v", err)
+	}
+	if claims[OrgIDClaim] != first.ID || claims[OrgRoleClaim] != OrgRoleOwner {
+		t.Errorf("expected default organization claims, got %v", claims)
+	}
+
+	claims, err = provider(WithActiveOrganization(ctx, second.ID), user)
+	if err != nil {
+		t.Fatalf("provider error: %v", err)
+	}
+	if claims[OrgIDClaim] != second.ID || claims[OrgRoleClaim] != OrgRoleMember {
+		t.Errorf("expected switched organization claims, got %v", claims)
+	}
+
+	if _, err := provider(WithActiveOrganization(ctx, "unknown"), user); err == nil {
+		t.Error("expected error when switching to organization without membership")
+	}
+
+	claims, err = provider(ctx, &MockUser{ID: "loner"})
+	if err != nil || claims != nil {
+		t.Errorf("expected no claims for user without organization, got %v, %v", claims, err)
+	}
+}
+
+func TestRequireOrgRole(t *testing.T) {
+	tests := []struct {
+		name     string
+		claims   map[string]interface{}
+		required string
+		want     int
+	}{
+		{"owner satisfies admin", map[string]interface{}{OrgIDClaim: "org-1", OrgRoleClaim: OrgRoleOwner}, OrgRoleAdmin, http.StatusOK},
+		{"admin satisfies admin", map[string]interface{}{OrgIDClaim: "org-1", OrgRoleClaim: OrgRoleAdmin}, OrgRoleAdmin, http.StatusOK},
+		{"member rejected", map[string]interface{}{OrgIDClaim: "org-1", OrgRoleClaim: OrgRoleMember}, OrgRoleOwner, http.StatusForbidden},
+		{"custom role exact match", map[string]interface{}{OrgIDClaim: "org-1", OrgRoleClaim: "billing"}, "billing", http.StatusOK},
+		{"no organization", map[string]interface{}{}, OrgRoleMember, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := RequireOrgRole(tt.required)(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r = SetUser(r, &TokenUser{ID: "user-1", Claims: tt.claims})
+			w := httptest.NewRecorder()
+			handler(w, r)
+
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
+
+// unorderedOrgStore mengembalikan keanggotaan user dalam urutan terbalik.
+type unorderedOrgStore struct {
+	*MockOrganizationStore
+}
+
+func (s unorderedOrgStore) ListUserMemberships(ctx context.Context, userID string) ([]*OrgMembership, error) {
+	memberships, err := s.MockOrganizationStore.ListUserMemberships(ctx, userID)
+	slices.Reverse(memberships)
+	return memberships, err
+}
+
+func TestOrganizationService_ClaimsProviderPicksOldestMembership(t *testing.T) {
+	store := unorderedOrgStore{NewMockOrganizationStore()}
+	svc := NewOrganizationService(store)
+	ctx := context.Background()
+
+	joined := time.Now().Add(-time.Hour)
+	for _, m := range []*OrgMembership{
+		{OrgID: "org-b", UserID: "user-1", Role: OrgRoleMember},
+		{OrgID: "org-a", UserID: "user-1", Role: OrgRoleAdmin},
+		{OrgID: "org-c", UserID: "user-1", Role: OrgRoleOwner},
+	} {
+		store.SaveMembership(ctx, m)
+	}
+	// org-a dan org-b bergabung pada waktu yang sama; org-c lebih baru
+	store.members[mockMembershipKey("org-a", "user-1")].CreatedAt = joined
+	store.members[mockMembershipKey("org-b", "user-1")].CreatedAt = joined
+
+	claims, err := svc.ClaimsProvider()(ctx, &MockUser{ID: "user-1"})
+	if err != nil {
+		t.Fatalf("provider error: %v", err)
+	}
+	if claims[OrgIDClaim] != "org-a" || claims[OrgRoleClaim] != OrgRoleAdmin {
+		t.Errorf("expected oldest membership org-a, got %v", claims)
+	}
+}
+
+func TestDatabaseOrganizationStore_ConsumeInvitation(t *testing.T) {
+	db, err := NewSQLiteDatabase(DatabaseConfig{Database: ":memory:"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := RunMigrations(db, append(GetUserMigrations(), GetOrganizationMigrations()...)); err != nil {
+		t.Fatalf("Migration failed: %v", err)
+	}
+
+	store := NewDatabaseOrganizationStore(db)
+	ctx := context.Background()
+	now := time.Now()
+	if err := store.CreateOrganization(ctx, &Organization{ID: "org-1", Name: "Acme"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, inv := range []*OrgInvitation{
+		{OrgID: "org-1", Email: "bob@example.com", Role: OrgRoleMember, InvitedBy: "owner-1", TokenHash: "valid", ExpiresAt: now.Add(time.Hour)},
+		{OrgID: "org-1", Email: "eve@example.com", Role: OrgRoleMember, InvitedBy: "owner-1", TokenHash: "expired", ExpiresAt: now.Add(-time.Hour)},
+	} {
+		if err := store.SaveInvitation(ctx, inv); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := store.ConsumeInvitation(ctx, "valid", now); err != nil {
+		t.Fatalf("ConsumeInvitation error: %v", err)
+	}
+	if inv, err := store.FindInvitation(ctx, "valid"); err != nil || inv.AcceptedAt == nil {
+		t.Errorf("expected invitation to be accepted, got %+v, %v", inv, err)
+	}
+	for _, hash := range []string{"valid", "expired", "missing"} {
+		if err := store.ConsumeInvitation(ctx, hash, now); !errors.Is(err, ErrInvitationNotFound) {
+			t.Errorf("ConsumeInvitation(%s) error = %v, want ErrInvitationNotFound", hash, err)
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package dim
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/atfromhome/goreus/pkg/cache"
+)
+
+// PermissionsClaim adalah claim token berisi daftar permission user, misal ["posts:manage"].
+// Nilai boleh berupa array atau string yang dipisah spasi (seperti claim OAuth "scope").
+const PermissionsClaim = "permissions"
+
+// ErrOwnerNotFound dikembalikan OwnerResolver jika resource tidak ada. RequireOwnerOr
+// menjawab 404 untuk error ini (juga untuk sql.ErrNoRows dan pgx.ErrNoRows).
+var ErrOwnerNotFound = errors.New("resource not found")
+
+// OwnerResolver mengembalikan ID pemilik resource dengan ID tertentu.
+type OwnerResolver func(ctx context.Context, id string) (ownerID string, err error)
+
+// Ownership mendeklarasikan cara menemukan pemilik sebuah resource dari path parameter,
+// sehingga pemeriksaan "apakah ini record saya?" tidak perlu ditulis ulang di setiap handler.
+// Hasil lookup di-cache per ID resource; panggil Invalidate saat pemilik resource berubah
+// atau resource dihapus.
+type Ownership struct {
+	param   string
+	resolve OwnerResolver
+	cache   *cache.InMemoryCache[string, string]
+}
+
+// NewOwnership membuat Ownership yang membaca ID resource dari path parameter param dan
+// mencari pemiliknya dengan resolve. Cache default menyimpan 1000 entri selama 1 menit.
+//
+// Parameters:
+//   - param: nama path parameter berisi ID resource, misal "id"
+//   - resolve: fungsi lookup pemilik, misal OwnerColumn atau query ke store aplikasi
+//
+// Returns:
+//   - *Ownership: deklarasi ownership yang siap dipakai dengan RequireOwnerOr
+//
+// Example:
+//
+//	posts := dim.NewOwnership("id", dim.OwnerColumn(db, "posts", "user_id"))
+//	api.Put("/posts/{id}", updatePostHandler, posts.RequireOwnerOr("posts:manage"))
+func NewOwnership(param string, resolve OwnerResolver) *Ownership {
+	if resolve == nil {
+		panic("dim: NewOwnership requires a non-nil resolver")
+	}
+	return &Ownership{
+		param:   param,
+		resolve: resolve,
+		cache:   cache.NewInMemoryCache[string, string](1000, time.Minute),
+	}
+}
+
+// WithCache mengganti kapasitas dan TTL cache pemilik. TTL <= 0 menonaktifkan cache,
+// sehingga setiap request melakukan lookup.
+//
+// Returns:
+//   - *Ownership: ownership yang sama untuk chaining
+func (o *Ownership) WithCache(capacity int, ttl time.Duration) *Ownership {
+	if ttl <= 0 {
+		o.cache = nil
+		return o
+	}
+	o.cache = cache.NewInMemoryCache[string, string](capacity, ttl)
+	return o
+}
+
+// Invalidate menghapus pemilik resource id dari cache, misal setelah transfer kepemilikan
+// atau penghapusan resource.
+func (o *Ownership) Invalidate(ctx context.Context, id string) {
+	if o.cache != nil {
+		o.cache.Delete(ctx, id)
+	}
+}
+
+// Owner mengembalikan ID pemilik resource id, dari cache jika tersedia.
+//
+// Returns:
+//   - string: ID pemilik
+//   - error: error dari resolver, misal ErrOwnerNotFound
+func (o *Ownership) Owner(ctx context.Context, id string) (string, error) {
+	if o.cache != nil {
+		if ownerID, ok := o.cache.Get(ctx, id); ok {
+			return ownerID, nil
+		}
+	}
+	ownerID, err := o.resolve(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	if o.cache != nil {
+		o.cache.Set(ctx, id, ownerID)
+	}
+	return ownerID, nil
+}
+
+// IsOwner melaporkan apakah user terotentikasi pada request adalah pemilik resource yang ID-nya
+// ada di path parameter.
+//
+// Returns:
+//   - bool: true jika user adalah pemilik
+//   - error: error dari resolver, misal ErrOwnerNotFound
+func (o *Ownership) IsOwner(r *http.Request) (bool, error) {
+	user, ok := GetUser(r)
+	if !ok {
+		return false, nil
+	}
+	ownerID, err := o.Owner(r.Context(), GetParam(r, o.param))
+	if err != nil {
+		return false, err
+	}
+	return ownerID != "" && ownerID == user.GetID(), nil
+}
+
+// RequireOwnerOr membuat middleware yang hanya meneruskan request jika user adalah pemilik
+// resource, atau jika token user memiliki permission (misal admin). Permission kosong berarti
+// hanya pemilik yang diizinkan. Pasang setelah RequireAuth.
+// Mengembalikan 401 tanpa user, 404 jika resource tidak ada, dan 403 jika bukan pemilik.
+// Pemeriksaan permission dilakukan lebih dulu sehingga admin tidak memicu lookup.
+//
+// Parameters:
+//   - permission: permission yang melewati pemeriksaan pemilik, lihat HasPermission
+//
+// Returns:
+//   - MiddlewareFunc: middleware pemeriksa kepemilikan
+//
+// Example:
+//
+//	api := router.Group("/api", dim.RequireAuth(jwtManager, blocklist))
+//	api.Delete("/posts/{id}", deletePostHandler, posts.RequireOwnerOr("posts:manage"))
+func (o *Ownership) RequireOwnerOr(permission string) MiddlewareFunc {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if _, ok := GetUser(r); !ok {
+				JsonError(w, http.StatusUnauthorized, "User tidak terotentikasi", nil)
+				return
+			}
+			if permission != "" && HasPermission(r, permission) {
+				next(w, r)
+				return
+			}
+
+			isOwner, err := o.IsOwner(r)
+			if err != nil {
+				if errors.Is(err, ErrOwnerNotFound) || isNoRows(err) {
+					NotFound(w, "Tidak ditemukan")
+					return
+				}
+				JsonError(w, http.StatusInternalServerError, "Gagal memeriksa kepemilikan resource", nil)
+				return
+			}
+			if !isOwner {
+				Forbidden(w, "Anda tidak memiliki permission untuk access resource ini")
+				return
+			}
+			next(w, r)
+		}
+	}
+}
+
+// HasPermission melaporkan apakah claim permissions pada token request berisi permission,
+// atau wildcard yang mencakupnya ("*" atau "posts:*", lihat PermissionMatches).
+//
+// Parameters:
+//   - r: request yang sudah melewati RequireAuth
+//   - permission: nama permission, misal "posts:manage"
+//
+// Returns:
+//   - bool: true jika permission dimiliki
+func HasPermission(r *http.Request, permission string) bool {
+	return hasPermission(claimStrings(GetClaims(r)[PermissionsClaim]), permission)
+}
+
+// claimStrings mengubah nilai claim (array JSON, []string, atau string dipisah spasi) menjadi slice.
+func claimStrings(v interface{}) []string {
+	switch v := v.(type) {
+	case string:
+		return strings.Fields(v)
+	case []string:
+		return v
+	case []interface{}:
+		out := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// OwnerColumn membuat OwnerResolver yang membaca kolom pemilik dari tabel berdasarkan kolom id.
+// Nama tabel dan kolom disisipkan langsung ke query, jadi jangan berasal dari input user.
+//
+// Parameters:
+//   - db: database
+//   - table: nama tabel, misal "posts"
+//   - ownerColumn: kolom berisi ID pemilik, misal "user_id"
+//
+// Returns:
+//   - OwnerResolver: resolver yang mengembalikan ErrOwnerNotFound jika baris tidak ada
+//
+// Example:
+//
+//	posts := dim.NewOwnership("id", dim.OwnerColumn(db, "posts", "user_id"))
+func OwnerColumn(db Database, table, ownerColumn string) OwnerResolver {
+	query := db.Rebind(fmt.Sprintf("SELECT %s FROM %s WHERE id = $1", ownerColumn, table))
+	return func(ctx context.Context, id string) (string, error) {
+		var ownerID string
+		if err := db.QueryRow(ctx, query, id).Scan(&ownerID); err != nil {
+			if isNoRows(err) {
+				return "", ErrOwnerNotFound
+			}
+			return "", fmt.Errorf("failed to resolve owner of %s %s: %w", table, id, err)
+		}
+		return ownerID, nil
+	}
+}
@@ -0,0 +1,166 @@
+package dim
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// newOwnershipRouter memasang RequireOwnerOr pada /posts/{id}; user (jika tidak nil) diset ke context lebih dulu.
+func newOwnershipRouter(o *Ownership, permission string, user *TokenUser) *Router {
+	router := NewRouter()
+	setUser := func(next HandlerFunc) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if user != nil {
+				r = SetUser(r, user)
+			}
+			next(w, r)
+		}
+	}
+	router.Get("/posts/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}, setUser, o.RequireOwnerOr(permission))
+	return router
+}
+
+func TestOwnershipRequireOwnerOr(t *testing.T) {
+	owners := map[string]string{"p-1": "user-1", "p-2": "user-2"}
+	resolve := func(ctx context.Context, id string) (string, error) {
+		if id == "broken" {
+			return "", errors.New("db down")
+		}
+		owner, ok := owners[id]
+		if !ok {
+			return "", ErrOwnerNotFound
+		}
+		return owner, nil
+	}
+
+	tests := []struct {
+		name       string
+		user       *TokenUser
+		permission string
+		path       string
+		want       int
+	}{
+		{"owner allowed", &TokenUser{ID: "user-1"}, "posts:manage", "/posts/p-1", http.StatusOK},
+		{"other user rejected", &TokenUser{ID: "user-1"}, "posts:manage", "/posts/p-2", http.StatusForbidden},
+		{"permission bypasses owner", &TokenUser{ID: "user-1", Claims: map[string]interface{}{PermissionsClaim: []interface{}{"posts:manage"}}}, "posts:manage", "/posts/p-2", http.StatusOK},
+		{"wildcard permission", &TokenUser{ID: "user-1", Claims: map[string]interface{}{PermissionsClaim: "*"}}, "posts:manage", "/posts/p-2", http.StatusOK},
+		{"other permission ignored", &TokenUser{ID: "user-1", Claims: map[string]interface{}{PermissionsClaim: "posts:read"}}, "posts:manage", "/posts/p-2", http.StatusForbidden},
+		{"owner only", &TokenUser{ID: "user-1", Claims: map[string]interface{}{PermissionsClaim: "*"}}, "", "/posts/p-2", http.StatusForbidden},
+		{"not found", &TokenUser{ID: "user-1"}, "posts:manage", "/posts/p-9", http.StatusNotFound},
+		{"permission skips lookup of missing resource", &TokenUser{ID: "user-1", Claims: map[string]interface{}{PermissionsClaim: "posts:manage"}}, "posts:manage", "/posts/p-9", http.StatusOK},
+		{"resolver error", &TokenUser{ID: "user-1"}, "posts:manage", "/posts/broken", http.StatusInternalServerError},
+		{"unauthenticated", nil, "posts:manage", "/posts/p-1", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newOwnershipRouter(NewOwnership("id", resolve), tt.permission, tt.user)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d (body %s)", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestOwnershipCache(t *testing.T) {
+	calls := 0
+	owner := "user-1"
+	o := NewOwnership("id", func(ctx context.Context, id string) (string, error) {
+		calls++
+		return owner, nil
+	})
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if got, err := o.Owner(ctx, "p-1"); err != nil || got != "user-1" {
+			t.Fatalf("Owner() = %q, %v", got, err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("resolver calls = %d, want 1", calls)
+	}
+
+	owner = "user-2"
+	o.Invalidate(ctx, "p-1")
+	if got, _ := o.Owner(ctx, "p-1"); got != "user-2" {
+		t.Errorf("Owner() after Invalidate = %q, want user-2", got)
+	}
+
+	o.WithCache(0, 0)
+	o.Owner(ctx, "p-1")
+	o.Owner(ctx, "p-1")
+	if calls != 4 {
+		t.Errorf("resolver calls without cache = %d, want 4", calls)
+	}
+
+	o.WithCache(10, time.Minute)
+	o.Owner(ctx, "p-1")
+	o.Owner(ctx, "p-1")
+	if calls != 5 {
+		t.Errorf("resolver calls with new cache = %d, want 5", calls)
+	}
+}
+
+func TestOwnershipNotFoundIsNotCached(t *testing.T) {
+	calls := 0
+	o := NewOwnership("id", func(ctx context.Context, id string) (string, error) {
+		calls++
+		return "", ErrOwnerNotFound
+	})
+	o.Owner(context.Background(), "p-1")
+	o.Owner(context.Background(), "p-1")
+	if calls != 2 {
+		t.Errorf("resolver calls = %d, want 2", calls)
+	}
+}
+
+func TestHasPermission(t *testing.T) {
+	tests := []struct {
+		name   string
+		claims map[string]interface{}
+		want   bool
+	}{
+		{"array claim", map[string]interface{}{PermissionsClaim: []interface{}{"posts:read", "posts:manage"}}, true},
+		{"string slice claim", map[string]interface{}{PermissionsClaim: []string{"posts:manage"}}, true},
+		{"space separated claim", map[string]interface{}{PermissionsClaim: "posts:read posts:manage"}, true},
+		{"wildcard", map[string]interface{}{PermissionsClaim: []interface{}{"*"}}, true},
+		{"missing", map[string]interface{}{PermissionsClaim: "posts:read"}, false},
+		{"no claims", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := SetUser(httptest.NewRequest(http.MethodGet, "/", nil), &TokenUser{ID: "user-1", Claims: tt.claims})
+			if got := HasPermission(r, "posts:manage"); got != tt.want {
+				t.Errorf("HasPermission() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOwnerColumn_SQLite(t *testing.T) {
+	db := newTestSQLiteAuthDB(t)
+	ctx := context.Background()
+	if err := db.Exec(ctx, "CREATE TABLE posts (id TEXT PRIMARY KEY, user_id TEXT NOT NULL)"); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Exec(ctx, db.Rebind("INSERT INTO posts (id, user_id) VALUES ($1, $2)"), "p-1", "user-1"); err != nil {
+		t.Fatal(err)
+	}
+
+	resolve := OwnerColumn(db, "posts", "user_id")
+	if got, err := resolve(ctx, "p-1"); err != nil || got != "user-1" {
+		t.Errorf("resolve(p-1) = %q, %v; want user-1", got, err)
+	}
+	if _, err := resolve(ctx, "p-9"); !errors.Is(err, ErrOwnerNotFound) {
+		t.Errorf("resolve(p-9) error = %v, want ErrOwnerNotFound", err)
+	}
+}
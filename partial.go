@@ -0,0 +1,376 @@
+package dim
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// PartialStatus adalah status satu section pada response parsial.
+type PartialStatus string
+
+const (
+	// PartialOK: section berhasil dimuat.
+	PartialOK PartialStatus = "ok"
+	// PartialTimeout: section melewati deadline-nya.
+	PartialTimeout PartialStatus = "timeout"
+	// PartialError: section gagal karena error atau panic.
+	PartialError PartialStatus = "error"
+)
+
+// PartialHeader berisi nama section yang tidak lengkap (dipisah koma) pada response parsial.
+const PartialHeader = "X-Partial-Response"
+
+// PartialFunc memuat data satu section. Context dibatalkan ketika deadline section habis.
+type PartialFunc func(ctx context.Context) (interface{}, error)
+
+// PartialSection berisi hasil satu section. Hanya Status dan Data yang dikirim ke client;
+// Err tidak pernah di-serialize agar detail internal tidak bocor.
+type PartialSection struct {
+	Status PartialStatus `json:"status"`
+	Data   interface{}   `json:"data,omitempty"`
+
+	Name     string        `json:"-"`
+	Required bool          `json:"-"`
+	Err      error         `json:"-"`
+	Duration time.Duration `json:"-"`
+	// Hedged bernilai true jika hasil berasal dari percobaan hedge (percobaan kedua).
+	Hedged bool `json:"-"`
+}
+
+type partialTask struct {
+	name       string
+	fetch      PartialFunc
+	timeout    time.Duration
+	hedgeDelay time.Duration
+	required   bool
+}
+
+// Partial menjalankan beberapa sub-fetch secara concurrent, masing-masing dengan deadline sendiri,
+// untuk endpoint agregat (dashboard, halaman ringkasan) yang menggabungkan beberapa store/downstream call.
+// Section yang timeout atau gagal ditandai di response alih-alih menggagalkan seluruh request.
+type Partial struct {
+	ctx     context.Context
+	tasks   []partialTask
+	names   map[string]bool
+	timeout time.Duration
+}
+
+// NewPartial membuat Partial baru. Semua section mewarisi ctx (biasanya r.Context()),
+// sehingga deadline request (misal dari DeadlineBudget) tetap menjadi batas atas.
+//
+// Parameters:
+//   - ctx: context induk
+//
+// Returns:
+//   - *Partial: builder yang siap diisi dengan Add, Require, dan Hedge
+//
+// Example:
+//
+//	result := dim.NewPartial(r.Context()).
+//	    Require("profile", 500*time.Millisecond, loadProfile).
+//	    Add("stats", 300*time.Millisecond, loadStats).
+//	    Hedge("recommendations", time.Second, 150*time.Millisecond, loadRecommendations).
+//	    Run()
+//	dim.JsonPartial(w, result)
+func NewPartial(ctx context.Context) *Partial {
+	return &Partial{ctx: ctx, names: make(map[string]bool)}
+}
+
+// WithTimeout mengatur timeout default untuk section yang didaftarkan dengan timeout 0.
+// Jika keduanya 0, section hanya dibatasi deadline context induk.
+func (p *Partial) WithTimeout(timeout time.Duration) *Partial {
+	p.timeout = timeout
+	return p
+}
+
+// Add mendaftarkan section opsional. Jika timeout atau gagal, section ditandai
+// dengan status "timeout"/"error" dan response tetap dikirim.
+//
+// Parameters:
+//   - name: nama section, menjadi key di response JSON
+//   - timeout: deadline section (0 = timeout default)
+//   - fetch: fungsi yang memuat data section
+//
+// Returns:
+//   - *Partial: builder untuk chaining
+func (p *Partial) Add(name string, timeout time.Duration, fetch PartialFunc) *Partial {
+	return p.add(partialTask{name: name, fetch: fetch, timeout: timeout})
+}
+
+// Require mendaftarkan section wajib. Jika section ini gagal, JsonPartial menulis
+// error response (504 untuk timeout, 500 untuk error lain) alih-alih response parsial.
+//
+// Parameters:
+//   - name: nama section
+//   - timeout: deadline section (0 = timeout default)
+//   - fetch: fungsi yang memuat data section
+//
+// Returns:
+//   - *Partial: builder untuk chaining
+func (p *Partial) Require(name string, timeout time.Duration, fetch PartialFunc) *Partial {
+	return p.add(partialTask{name: name, fetch: fetch, timeout: timeout, required: true})
+}
+
+// Hedge mendaftarkan section opsional dengan hedged request: jika percobaan pertama belum
+// selesai setelah delay, percobaan kedua dijalankan dan hasil sukses pertama yang dipakai.
+// Berguna untuk downstream dengan tail latency tinggi. fetch harus idempotent.
+//
+// Parameters:
+//   - name: nama section
+//   - timeout: deadline section untuk kedua percobaan (0 = timeout default)
+//   - delay: jeda sebelum percobaan kedua dijalankan
+//   - fetch: fungsi yang memuat data section
+//
+// Returns:
+//   - *Partial: builder untuk chaining
+//
+// Example:
+//
+//	// p95 search ~100ms; kirim percobaan kedua jika belum selesai setelah 150ms
+//	partial.Hedge("search", time.Second, 150*time.Millisecond, searchFn)
+func (p *Partial) Hedge(name string, timeout, delay time.Duration, fetch PartialFunc) *Partial {
+	return p.add(partialTask{name: name, fetch: fetch, timeout: timeout, hedgeDelay: delay})
+}
+
+func (p *Partial) add(task partialTask) *Partial {
+	if task.name == "" {
+		panic("dim: Partial section requires a name")
+	}
+	if task.fetch == nil {
+		panic(fmt.Sprintf("dim: Partial section %q requires a fetch function", task.name))
+	}
+	if p.names[task.name] {
+		panic(fmt.Sprintf("dim: Partial section %q already registered", task.name))
+	}
+	p.names[task.name] = true
+	p.tasks = append(p.tasks, task)
+	return p
+}
+
+// Run menjalankan semua section secara concurrent dan menunggu sampai semuanya selesai
+// atau melewati deadline masing-masing. Panic di dalam fetch ditangkap dan diperlakukan
+// sebagai error section. Section yang tidak berhasil di-log dengan level warning.
+//
+// Returns:
+//   - *PartialResult: hasil setiap section, terurut sesuai urutan registrasi
+func (p *Partial) Run() *PartialResult {
+	sections := make([]*PartialSection, len(p.tasks))
+	done := make(chan struct{}, len(p.tasks))
+
+	for i, task := range p.tasks {
+		go func(i int, task partialTask) {
+			sections[i] = p.runTask(task)
+			done <- struct{}{}
+		}(i, task)
+	}
+	for range p.tasks {
+		<-done
+	}
+
+	for _, section := range sections {
+		if section.Status != PartialOK {
+			slog.Warn("partial section incomplete",
+				"section", section.Name,
+				"status", string(section.Status),
+				"required", section.Required,
+				"duration", section.Duration,
+				"error", section.Err,
+			)
+		}
+	}
+	return &PartialResult{sections: sections}
+}
+
+type partialAttempt struct {
+	data   interface{}
+	err    error
+	hedged bool
+}
+
+// runTask menjalankan satu section dengan timeout, hedging, dan recovery dari panic.
+func (p *Partial) runTask(task partialTask) *PartialSection {
+	timeout := task.timeout
+	if timeout == 0 {
+		timeout = p.timeout
+	}
+
+	var (
+		ctx    context.Context
+		cancel context.CancelFunc
+	)
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(p.ctx, timeout)
+	} else {
+		ctx, cancel = context.WithCancel(p.ctx)
+	}
+	defer cancel()
+
+	start := time.Now()
+	section := &PartialSection{Name: task.name, Required: task.required}
+
+	attempts := make(chan partialAttempt, 2)
+	launch := func(hedged bool) {
+		go func() {
+			defer func() {
+				if rec := recover(); rec != nil {
+					attempts <- partialAttempt{err: fmt.Errorf("panic: %v", rec), hedged: hedged}
+				}
+			}()
+			data, err := task.fetch(ctx)
+			attempts <- partialAttempt{data: data, err: err, hedged: hedged}
+		}()
+	}
+	launch(false)
+	pending := 1
+
+	var hedge <-chan time.Time
+	if task.hedgeDelay > 0 {
+		timer := time.NewTimer(task.hedgeDelay)
+		defer timer.Stop()
+		hedge = timer.C
+	}
+
+	// Fetch yang mengabaikan context tidak boleh menahan response melewati deadline section.
+	var lastErr error
+	for {
+		select {
+		case attempt := <-attempts:
+			pending--
+			if attempt.err == nil {
+				section.Status, section.Data, section.Hedged = PartialOK, attempt.data, attempt.hedged
+				section.Duration = time.Since(start)
+				return section
+			}
+			lastErr = attempt.err
+			if pending > 0 || hedge != nil {
+				// Tunggu percobaan lain yang masih berjalan, atau jalankan hedge lebih awal.
+				if pending == 0 {
+					hedge = nil
+					launch(true)
+					pending++
+				}
+				continue
+			}
+			section.setError(lastErr)
+		case <-hedge:
+			hedge = nil
+			launch(true)
+			pending++
+			continue
+		case <-ctx.Done():
+			section.setError(ctx.Err())
+		}
+		section.Duration = time.Since(start)
+		return section
+	}
+}
+
+func (s *PartialSection) setError(err error) {
+	s.Err = err
+	if errors.Is(err, context.DeadlineExceeded) {
+		s.Status = PartialTimeout
+	} else {
+		s.Status = PartialError
+	}
+}
+
+// PartialResult berisi hasil Partial.Run.
+type PartialResult struct {
+	sections []*PartialSection
+}
+
+// Section mengembalikan hasil section berdasarkan nama, atau nil jika tidak terdaftar.
+func (r *PartialResult) Section(name string) *PartialSection {
+	for _, section := range r.sections {
+		if section.Name == name {
+			return section
+		}
+	}
+	return nil
+}
+
+// Sections mengembalikan hasil semua section, terurut sesuai urutan registrasi.
+func (r *PartialResult) Sections() []*PartialSection {
+	return r.sections
+}
+
+// Complete mengembalikan true jika semua section berstatus ok.
+func (r *PartialResult) Complete() bool {
+	return len(r.Incomplete()) == 0
+}
+
+// Incomplete mengembalikan nama section yang tidak berstatus ok.
+func (r *PartialResult) Incomplete() []string {
+	var names []string
+	for _, section := range r.sections {
+		if section.Status != PartialOK {
+			names = append(names, section.Name)
+		}
+	}
+	return names
+}
+
+// Err mengembalikan error section wajib pertama yang gagal, atau nil jika semua section wajib berhasil.
+func (r *PartialResult) Err() error {
+	for _, section := range r.sections {
+		if section.Required && section.Status != PartialOK {
+			return fmt.Errorf("partial section %s: %w", section.Name, section.Err)
+		}
+	}
+	return nil
+}
+
+// Data mengembalikan semua section sebagai map nama → section, bentuk yang ditulis JsonPartial.
+func (r *PartialResult) Data() map[string]*PartialSection {
+	data := make(map[string]*PartialSection, len(r.sections))
+	for _, section := range r.sections {
+		data[section.Name] = section
+	}
+	return data
+}
+
+// JsonPartial menulis hasil Partial sebagai response JSON.
+// Jika section wajib gagal, menulis 504 "Waktu permintaan habis" (timeout) atau
+// 500 "Terjadi kesalahan pada server" (error lain). Selain itu menulis 200 dengan setiap
+// section beserta statusnya; jika ada section yang tidak lengkap, header X-Partial-Response
+// berisi nama section tersebut.
+//
+// Parameters:
+//   - w: http.ResponseWriter untuk menulis response
+//   - result: hasil Partial.Run
+//   - opts: opsi response seperti pada Json
+//
+// Returns:
+//   - error: error section wajib atau error encoding JSON
+//
+// Example:
+//
+//	result := dim.NewPartial(r.Context()).
+//	    Add("orders", 300*time.Millisecond, loadOrders).
+//	    Add("stats", 200*time.Millisecond, loadStats).
+//	    Run()
+//	dim.JsonPartial(w, result)
+//	// 200 {"orders": {"status": "ok", "data": [...]}, "stats": {"status": "timeout"}}
+func JsonPartial(w http.ResponseWriter, result *PartialResult, opts ...ResponseOption) error {
+	for _, section := range result.sections {
+		if !section.Required || section.Status == PartialOK {
+			continue
+		}
+		if section.Status == PartialTimeout {
+			JsonError(w, http.StatusGatewayTimeout, "Waktu permintaan habis", nil)
+		} else {
+			InternalServerError(w, "Terjadi kesalahan pada server")
+		}
+		return result.Err()
+	}
+
+	if incomplete := result.Incomplete(); len(incomplete) > 0 {
+		w.Header().Set(PartialHeader, strings.Join(incomplete, ","))
+	}
+	return Json(w, http.StatusOK, result.Data(), opts...)
+}
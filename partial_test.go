@@ -0,0 +1,233 @@
+package dim
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func partialValue(v interface{}) PartialFunc {
+	return func(ctx context.Context) (interface{}, error) {
+		return v, nil
+	}
+}
+
+func partialSlow(d time.Duration) PartialFunc {
+	return func(ctx context.Context) (interface{}, error) {
+		select {
+		case <-time.After(d):
+			return "late", nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func TestPartial_Run(t *testing.T) {
+	result := NewPartial(context.Background()).
+		Add("orders", time.Second, partialValue([]int{1, 2})).
+		Add("stats", 20*time.Millisecond, partialSlow(time.Second)).
+		Add("feed", time.Second, func(ctx context.Context) (interface{}, error) {
+			return nil, errors.New("feed down")
+		}).
+		Add("panics", time.Second, func(ctx context.Context) (interface{}, error) {
+			panic("boom")
+		}).
+		Run()
+
+	tests := []struct {
+		name   string
+		status PartialStatus
+	}{
+		{"orders", PartialOK},
+		{"stats", PartialTimeout},
+		{"feed", PartialError},
+		{"panics", PartialError},
+	}
+	for _, tt := range tests {
+		if got := result.Section(tt.name).Status; got != tt.status {
+			t.Errorf("section %s status = %s, want %s", tt.name, got, tt.status)
+		}
+	}
+
+	if result.Complete() {
+		t.Error("result should not be complete")
+	}
+	if got := result.Incomplete(); len(got) != 3 || got[0] != "stats" {
+		t.Errorf("Incomplete() = %v", got)
+	}
+	if result.Err() != nil {
+		t.Errorf("Err() = %v, want nil without required sections", result.Err())
+	}
+	if result.Section("missing") != nil {
+		t.Error("unknown section should be nil")
+	}
+}
+
+func TestPartial_IgnoresContextStillTimesOut(t *testing.T) {
+	start := time.Now()
+	result := NewPartial(context.Background()).
+		WithTimeout(20*time.Millisecond).
+		Add("stubborn", 0, func(ctx context.Context) (interface{}, error) {
+			time.Sleep(200 * time.Millisecond)
+			return "done", nil
+		}).
+		Run()
+
+	if result.Section("stubborn").Status != PartialTimeout {
+		t.Errorf("status = %s", result.Section("stubborn").Status)
+	}
+	if elapsed := time.Since(start); elapsed > 150*time.Millisecond {
+		t.Errorf("Run waited %v for a fetch that ignores its context", elapsed)
+	}
+}
+
+func TestPartial_ParentDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	result := NewPartial(ctx).Add("slow", time.Second, partialSlow(time.Second)).Run()
+	if result.Section("slow").Status != PartialTimeout {
+		t.Errorf("section should inherit the parent deadline, status = %s", result.Section("slow").Status)
+	}
+}
+
+func TestPartial_Hedge(t *testing.T) {
+	var calls atomic.Int32
+	fetch := func(ctx context.Context) (interface{}, error) {
+		if calls.Add(1) == 1 {
+			// First attempt hits the slow tail
+			return partialSlow(time.Second)(ctx)
+		}
+		return "fast", nil
+	}
+
+	result := NewPartial(context.Background()).
+		Hedge("search", 500*time.Millisecond, 10*time.Millisecond, fetch).
+		Run()
+
+	section := result.Section("search")
+	if section.Status != PartialOK || section.Data != "fast" || !section.Hedged {
+		t.Errorf("section = %+v", section)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("calls = %d, want 2", calls.Load())
+	}
+}
+
+func TestPartial_HedgeNotNeeded(t *testing.T) {
+	var calls atomic.Int32
+	result := NewPartial(context.Background()).
+		Hedge("search", time.Second, 50*time.Millisecond, func(ctx context.Context) (interface{}, error) {
+			calls.Add(1)
+			return "first", nil
+		}).
+		Run()
+
+	if section := result.Section("search"); section.Data != "first" || section.Hedged {
+		t.Errorf("section = %+v", section)
+	}
+	time.Sleep(70 * time.Millisecond)
+	if calls.Load() != 1 {
+		t.Errorf("hedge should not fire after success, calls = %d", calls.Load())
+	}
+}
+
+func TestPartial_HedgeAfterFastFailure(t *testing.T) {
+	var calls atomic.Int32
+	result := NewPartial(context.Background()).
+		Hedge("search", time.Second, time.Minute, func(ctx context.Context) (interface{}, error) {
+			if calls.Add(1) == 1 {
+				return nil, errors.New("connection reset")
+			}
+			return "retry", nil
+		}).
+		Run()
+
+	if section := result.Section("search"); section.Status != PartialOK || section.Data != "retry" {
+		t.Errorf("failed first attempt should trigger the hedge immediately, section = %+v", section)
+	}
+}
+
+func TestPartial_DuplicateName(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for duplicate section")
+		}
+	}()
+	NewPartial(context.Background()).
+		Add("stats", 0, partialValue(1)).
+		Add("stats", 0, partialValue(2))
+}
+
+func TestJsonPartial(t *testing.T) {
+	result := NewPartial(context.Background()).
+		Require("profile", time.Second, partialValue(map[string]string{"name": "Ani"})).
+		Add("stats", 10*time.Millisecond, partialSlow(time.Second)).
+		Run()
+
+	w := httptest.NewRecorder()
+	if err := JsonPartial(w, result); err != nil {
+		t.Fatalf("JsonPartial() error = %v", err)
+	}
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d", w.Code)
+	}
+	if got := w.Header().Get(PartialHeader); got != "stats" {
+		t.Errorf("%s = %q", PartialHeader, got)
+	}
+
+	var body map[string]map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &body)
+	if body["stats"]["status"] != "timeout" || body["stats"]["data"] != nil {
+		t.Errorf("stats = %v", body["stats"])
+	}
+	if body["profile"]["status"] != "ok" || body["profile"]["data"].(map[string]interface{})["name"] != "Ani" {
+		t.Errorf("profile = %v", body["profile"])
+	}
+}
+
+func TestJsonPartial_Complete(t *testing.T) {
+	result := NewPartial(context.Background()).Add("a", 0, partialValue(1)).Run()
+
+	w := httptest.NewRecorder()
+	JsonPartial(w, result)
+	if w.Header().Get(PartialHeader) != "" {
+		t.Errorf("complete response should not set %s", PartialHeader)
+	}
+}
+
+func TestJsonPartial_RequiredFailure(t *testing.T) {
+	tests := []struct {
+		name     string
+		fetch    PartialFunc
+		wantCode int
+	}{
+		{"timeout", partialSlow(time.Second), http.StatusGatewayTimeout},
+		{"error", func(ctx context.Context) (interface{}, error) { return nil, errors.New("db down") }, http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := NewPartial(context.Background()).
+				Require("profile", 10*time.Millisecond, tt.fetch).
+				Add("stats", 0, partialValue(1)).
+				Run()
+
+			w := httptest.NewRecorder()
+			err := JsonPartial(w, result)
+			if err == nil || !strings.HasPrefix(err.Error(), "partial section profile:") {
+				t.Errorf("error = %v", err)
+			}
+			if w.Code != tt.wantCode {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantCode)
+			}
+		})
+	}
+}
@@ -1,15 +1,22 @@
 package dim
 
 import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
 	"fmt"
 	"strings"
 
+	"golang.org/x/crypto/argon2"
 	"golang.org/x/crypto/bcrypt"
 )
 
 const (
 	// MinPasswordLength is the minimum required password length
 	MinPasswordLength = 8
+	// MaxPasswordLength is the default maximum password length (bcrypt only uses 72 bytes)
+	MaxPasswordLength = 72
 	// BcryptCost is the bcrypt cost factor
 	BcryptCost = 12
 )
@@ -39,7 +46,8 @@ func HashPassword(password string) (string, error) {
 }
 
 // VerifyPassword memverifikasi plaintext password terhadap hash yang tersimpan.
-// Menggunakan bcrypt untuk aman time-constant comparison.
+// Algoritma dipilih dari prefix hash (bcrypt "$2" atau argon2id "$argon2id$") dan
+// perbandingan dilakukan secara constant-time.
 //
 // Parameters:
 //   - hashedPassword: hashed password dari database
@@ -55,20 +63,246 @@ func HashPassword(password string) (string, error) {
 //	  return "password tidak valid"
 //	}
 func VerifyPassword(hashedPassword, password string) error {
+	if strings.HasPrefix(hashedPassword, argon2idPrefix) {
+		return verifyArgon2id(hashedPassword, password)
+	}
 	return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
 }
 
+// PasswordHasher membuat dan memverifikasi hash password. AuthService memakai hasher yang
+// dikonfigurasi lewat WithPasswordHasher untuk Register, ResetPassword, dan verifikasi Login.
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	// Verify mengembalikan error jika password tidak cocok dengan hash
+	Verify(hash, password string) error
+	// NeedsRehash melaporkan apakah hash dibuat dengan algoritma atau parameter yang berbeda
+	// dari hasher ini. LocalCredentialVerifier memakainya untuk rehash saat login berhasil.
+	NeedsRehash(hash string) bool
+}
+
+// Algoritma PasswordConfig.Algorithm.
+const (
+	PasswordAlgorithmBcrypt   = "bcrypt"
+	PasswordAlgorithmArgon2id = "argon2id"
+)
+
+// ErrInvalidPasswordHash dikembalikan saat hash tersimpan tidak dapat di-parse.
+var ErrInvalidPasswordHash = errors.New("invalid password hash")
+
+// NewPasswordHasher membuat PasswordHasher sesuai PasswordConfig (PASSWORD_HASH_ALGORITHM dan
+// parameternya). Hash lama dengan algoritma lain tetap dapat diverifikasi dan akan di-rehash
+// saat user berhasil login.
+//
+// Parameters:
+//   - cfg: konfigurasi hashing password
+//
+// Returns:
+//   - PasswordHasher: hasher sesuai algoritma
+//   - error: error jika algoritma tidak dikenal
+//
+// Example:
+//
+//	hasher, err := dim.NewPasswordHasher(cfg.Password)
+//	if err != nil {
+//	    return err
+//	}
+//	authService.WithPasswordHasher(hasher)
+func NewPasswordHasher(cfg PasswordConfig) (PasswordHasher, error) {
+	switch cfg.Algorithm {
+	case "", PasswordAlgorithmBcrypt:
+		return NewBcryptHasher(cfg.BcryptCost), nil
+	case PasswordAlgorithmArgon2id:
+		return NewArgon2idHasher(Argon2idParams{
+			Memory:      cfg.Argon2Memory,
+			Iterations:  cfg.Argon2Iterations,
+			Parallelism: cfg.Argon2Parallelism,
+		}), nil
+	default:
+		return nil, fmt.Errorf("unsupported password hash algorithm %q", cfg.Algorithm)
+	}
+}
+
+// BcryptHasher adalah PasswordHasher berbasis bcrypt. Ini adalah hasher default AuthService.
+type BcryptHasher struct {
+	cost int
+}
+
+// NewBcryptHasher membuat BcryptHasher dengan cost tertentu. Cost di luar rentang bcrypt
+// (4-31) diganti dengan BcryptCost.
+//
+// Example:
+//
+//	authService.WithPasswordHasher(dim.NewBcryptHasher(14))
+func NewBcryptHasher(cost int) *BcryptHasher {
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		cost = BcryptCost
+	}
+	return &BcryptHasher{cost: cost}
+}
+
+// Hash melakukan hash password dengan bcrypt.
+func (h *BcryptHasher) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+	return string(hash), nil
+}
+
+// Verify memverifikasi password terhadap hash bcrypt atau argon2id.
+func (h *BcryptHasher) Verify(hash, password string) error {
+	return VerifyPassword(hash, password)
+}
+
+// NeedsRehash mengembalikan true jika hash bukan bcrypt atau cost-nya berbeda.
+func (h *BcryptHasher) NeedsRehash(hash string) bool {
+	cost, err := bcrypt.Cost([]byte(hash))
+	return err != nil || cost != h.cost
+}
+
+const argon2idPrefix = "$argon2id$"
+
+// Argon2idParams adalah parameter argon2id. Field bernilai nol diganti dengan nilai dari
+// DefaultArgon2idParams.
+type Argon2idParams struct {
+	// Memory dalam KiB
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// DefaultArgon2idParams mengembalikan parameter argon2id yang direkomendasikan OWASP
+// (19 MiB, 2 iterasi, parallelism 1).
+func DefaultArgon2idParams() Argon2idParams {
+	return Argon2idParams{
+		Memory:      19 * 1024,
+		Iterations:  2,
+		Parallelism: 1,
+		SaltLength:  16,
+		KeyLength:   32,
+	}
+}
+
+// Argon2idHasher adalah PasswordHasher berbasis argon2id. Hash disimpan dalam format PHC
+// ($argon2id$v=19$m=...,t=...,p=...$salt$key) sehingga parameter lama tetap dapat diverifikasi.
+type Argon2idHasher struct {
+	params Argon2idParams
+}
+
+// NewArgon2idHasher membuat Argon2idHasher. Parameter bernilai nol memakai DefaultArgon2idParams.
+//
+// Example:
+//
+//	authService.WithPasswordHasher(dim.NewArgon2idHasher(dim.Argon2idParams{Memory: 64 * 1024}))
+func NewArgon2idHasher(params Argon2idParams) *Argon2idHasher {
+	defaults := DefaultArgon2idParams()
+	if params.Memory == 0 {
+		params.Memory = defaults.Memory
+	}
+	if params.Iterations == 0 {
+		params.Iterations = defaults.Iterations
+	}
+	if params.Parallelism == 0 {
+		params.Parallelism = defaults.Parallelism
+	}
+	if params.SaltLength == 0 {
+		params.SaltLength = defaults.SaltLength
+	}
+	if params.KeyLength == 0 {
+		params.KeyLength = defaults.KeyLength
+	}
+	return &Argon2idHasher{params: params}
+}
+
+// Hash melakukan hash password dengan argon2id dan salt acak.
+func (h *Argon2idHasher) Hash(password string) (string, error) {
+	salt := make([]byte, h.params.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+	key := argon2.IDKey([]byte(password), salt, h.params.Iterations, h.params.Memory, h.params.Parallelism, h.params.KeyLength)
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s", argon2idPrefix, argon2.Version,
+		h.params.Memory, h.params.Iterations, h.params.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// Verify memverifikasi password terhadap hash argon2id atau bcrypt.
+func (h *Argon2idHasher) Verify(hash, password string) error {
+	return VerifyPassword(hash, password)
+}
+
+// NeedsRehash mengembalikan true jika hash bukan argon2id atau parameternya berbeda.
+func (h *Argon2idHasher) NeedsRehash(hash string) bool {
+	params, salt, key, err := decodeArgon2id(hash)
+	if err != nil {
+		return true
+	}
+	return params.Memory != h.params.Memory ||
+		params.Iterations != h.params.Iterations ||
+		params.Parallelism != h.params.Parallelism ||
+		uint32(len(salt)) != h.params.SaltLength ||
+		uint32(len(key)) != h.params.KeyLength
+}
+
+func verifyArgon2id(hash, password string) error {
+	params, salt, key, err := decodeArgon2id(hash)
+	if err != nil {
+		return err
+	}
+	candidate := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, uint32(len(key)))
+	if subtle.ConstantTimeCompare(key, candidate) != 1 {
+		return bcrypt.ErrMismatchedHashAndPassword
+	}
+	return nil
+}
+
+// decodeArgon2id mem-parse hash berformat PHC argon2id.
+func decodeArgon2id(hash string) (Argon2idParams, []byte, []byte, error) {
+	var params Argon2idParams
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return params, nil, nil, ErrInvalidPasswordHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, ErrInvalidPasswordHash
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return params, nil, nil, ErrInvalidPasswordHash
+	}
+	if params.Memory == 0 || params.Iterations == 0 || params.Parallelism == 0 {
+		return params, nil, nil, ErrInvalidPasswordHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, ErrInvalidPasswordHash
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return params, nil, nil, ErrInvalidPasswordHash
+	}
+	params.SaltLength = uint32(len(salt))
+	params.KeyLength = uint32(len(key))
+	return params, salt, key, nil
+}
+
 // PasswordValidator provides password validation utilities
 type PasswordValidator struct {
 	minLength    int
+	maxLength    int
 	requireUpper bool
 	requireLower bool
 	requireDigit bool
 	requireSpec  bool
+	denyList     map[string]struct{}
 }
 
 // NewPasswordValidator membuat PasswordValidator baru dengan default settings.
-// Default settings: minLength=8, require uppercase, lowercase, digit, dan special char.
+// Default settings: minLength=8, maxLength=72, require uppercase, lowercase, digit, dan special char.
 //
 // Returns:
 //   - *PasswordValidator: validator instance dengan default rules
@@ -80,6 +314,7 @@ type PasswordValidator struct {
 func NewPasswordValidator() *PasswordValidator {
 	return &PasswordValidator{
 		minLength:    MinPasswordLength,
+		maxLength:    MaxPasswordLength,
 		requireUpper: true,
 		requireLower: true,
 		requireDigit: true,
@@ -93,6 +328,12 @@ func (pv *PasswordValidator) SetMinLength(length int) *PasswordValidator {
 	return pv
 }
 
+// SetMaxLength sets the maximum password length in bytes (0 disables the limit)
+func (pv *PasswordValidator) SetMaxLength(length int) *PasswordValidator {
+	pv.maxLength = length
+	return pv
+}
+
 // RequireUppercase sets whether uppercase letters are required
 func (pv *PasswordValidator) RequireUppercase(required bool) *PasswordValidator {
 	pv.requireUpper = required
@@ -117,6 +358,42 @@ func (pv *PasswordValidator) RequireSpecial(required bool) *PasswordValidator {
 	return pv
 }
 
+// DenyPasswords menambahkan password yang ditolak (misal "Password123!" atau nama aplikasi).
+// Perbandingan tidak case-sensitive.
+//
+// Example:
+//
+//	validator := NewPasswordValidator().DenyPasswords("Password123!", "Qwerty123!")
+func (pv *PasswordValidator) DenyPasswords(passwords ...string) *PasswordValidator {
+	if pv.denyList == nil {
+		pv.denyList = make(map[string]struct{}, len(passwords))
+	}
+	for _, password := range passwords {
+		password = strings.ToLower(strings.TrimSpace(password))
+		if password != "" {
+			pv.denyList[password] = struct{}{}
+		}
+	}
+	return pv
+}
+
+// NewPasswordValidatorFromConfig membuat PasswordValidator dari PasswordConfig
+// (PASSWORD_MIN_LENGTH, PASSWORD_MAX_LENGTH, PASSWORD_REQUIRE_*, PASSWORD_DENY_LIST).
+//
+// Example:
+//
+//	authService.WithPasswordValidator(dim.NewPasswordValidatorFromConfig(cfg.Password))
+func NewPasswordValidatorFromConfig(cfg PasswordConfig) *PasswordValidator {
+	return NewPasswordValidator().
+		SetMinLength(cfg.MinLength).
+		SetMaxLength(cfg.MaxLength).
+		RequireUppercase(cfg.RequireUppercase).
+		RequireLowercase(cfg.RequireLowercase).
+		RequireDigit(cfg.RequireDigit).
+		RequireSpecial(cfg.RequireSpecial).
+		DenyPasswords(cfg.DenyList...)
+}
+
 // Validate memvalidasi password terhadap semua configured rules.
 // Return error dengan detail field error jika validasi gagal.
 //
@@ -133,41 +410,60 @@ func (pv *PasswordValidator) RequireSpecial(required bool) *PasswordValidator {
 //	  // handle validation error
 //	}
 func (pv *PasswordValidator) Validate(password string) error {
+	return pv.validate("", password)
+}
+
+// validate memvalidasi password dengan pesan error dalam locale tertentu.
+func (pv *PasswordValidator) validate(locale, password string) error {
 	password = strings.TrimSpace(password)
 
 	if len(password) < pv.minLength {
 		return NewAppError(
 			"Validasi kata sandi gagal",
 			400,
-		).WithFieldError("password", fmt.Sprintf("Kata sandi harus minimal %d karakter", pv.minLength))
+		).WithFieldError("password", Translate(locale, "Kata sandi harus minimal %d karakter", pv.minLength))
+	}
+
+	if pv.maxLength > 0 && len(password) > pv.maxLength {
+		return NewAppError(
+			"Validasi kata sandi gagal",
+			400,
+		).WithFieldError("password", Translate(locale, "Kata sandi maksimal %d karakter", pv.maxLength))
+	}
+
+	if _, denied := pv.denyList[strings.ToLower(password)]; denied {
+		return NewAppError(
+			"Validasi kata sandi gagal",
+			400,
+		).WithFieldError("password", Translate(locale, "Kata sandi terlalu umum, gunakan kata sandi lain"))
 	}
 
 	if pv.requireUpper && !ContainsUppercase(password) {
 		return NewAppError(
 			"Validasi kata sandi gagal",
 			400,
-		).WithFieldError("password", "Kata sandi harus mengandung minimal satu huruf besar")
+		).WithFieldError("password", Translate(locale, "Kata sandi harus mengandung minimal satu huruf besar"))
 	}
 
 	if pv.requireLower && !ContainsLowercase(password) {
 		return NewAppError(
 			"Validasi kata sandi gagal",
 			400,
-		).WithFieldError("password", "Kata sandi harus mengandung minimal satu huruf kecil")
+		).WithFieldError("password", Translate(locale, "Kata sandi harus mengandung minimal satu huruf kecil"))
 	}
 
 	if pv.requireDigit && !ContainsDigit(password) {
 		return NewAppError(
 			"Validasi kata sandi gagal",
 			400,
-		).WithFieldError("password", "Kata sandi harus mengandung minimal satu angka")
+		).WithFieldError("password", Translate(locale, "Kata sandi harus mengandung minimal satu angka"))
 	}
 
 	if pv.requireSpec && !ContainsSpecial(password) {
 		return NewAppError(
 			"Validasi kata sandi gagal",
 			400,
-		).WithFieldError("password", "Kata sandi harus mengandung minimal satu karakter spesial")
+		).WithFieldError("password", Translate(locale, "Kata sandi harus mengandung minimal satu karakter spesial"))
 	}
 
 	return nil
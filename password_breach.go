@@ -0,0 +1,152 @@
+package dim
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BreachChecker memeriksa apakah password pernah muncul dalam kebocoran data.
+// AuthService memanggilnya saat Register, ResetPassword, dan ChangePassword jika dipasang
+// lewat WithBreachChecker.
+type BreachChecker interface {
+	// Breached mengembalikan true jika password tercatat bocor. Error berarti pemeriksaan
+	// tidak dapat dilakukan (misal API tidak dapat dihubungi).
+	Breached(ctx context.Context, password string) (bool, error)
+}
+
+// BreachCheckerFunc adalah adapter agar function biasa dapat digunakan sebagai BreachChecker.
+type BreachCheckerFunc func(ctx context.Context, password string) (bool, error)
+
+// Breached memanggil f(ctx, password).
+func (f BreachCheckerFunc) Breached(ctx context.Context, password string) (bool, error) {
+	return f(ctx, password)
+}
+
+// PwnedPasswordsChecker adalah BreachChecker untuk API range Have I Been Pwned
+// (https://haveibeenpwned.com/API/v3#PwnedPasswords). Menggunakan k-anonymity: hanya 5 karakter
+// pertama hash SHA-1 password yang dikirim, lalu suffix dicocokkan secara lokal.
+type PwnedPasswordsChecker struct {
+	endpoint  string
+	threshold int
+	client    *http.Client
+}
+
+// NewPwnedPasswordsChecker membuat checker Pwned Passwords dengan threshold 1 (password yang
+// pernah muncul sekali pun ditolak) dan timeout 5 detik.
+//
+// Example:
+//
+//	authService.WithBreachChecker(dim.NewPwnedPasswordsChecker())
+func NewPwnedPasswordsChecker() *PwnedPasswordsChecker {
+	return &PwnedPasswordsChecker{
+		endpoint:  "https://api.pwnedpasswords.com/range/",
+		threshold: 1,
+		client:    &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// WithThreshold mengatur jumlah kemunculan minimum agar password dianggap bocor.
+func (c *PwnedPasswordsChecker) WithThreshold(count int) *PwnedPasswordsChecker {
+	c.threshold = count
+	return c
+}
+
+// WithEndpoint mengganti URL endpoint range (prefix hash ditambahkan di akhir), misal untuk
+// mirror internal.
+func (c *PwnedPasswordsChecker) WithEndpoint(endpoint string) *PwnedPasswordsChecker {
+	c.endpoint = endpoint
+	return c
+}
+
+// WithHTTPClient mengganti HTTP client yang dipakai untuk memanggil API.
+func (c *PwnedPasswordsChecker) WithHTTPClient(client *http.Client) *PwnedPasswordsChecker {
+	c.client = client
+	return c
+}
+
+// Breached mengimplementasikan BreachChecker.
+func (c *PwnedPasswordsChecker) Breached(ctx context.Context, password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:5], hash[5:]
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.endpoint+prefix, nil)
+	if err != nil {
+		return false, err
+	}
+	// Padding menyamarkan ukuran response sehingga prefix tidak dapat ditebak dari trafik
+	req.Header.Set("Add-Padding", "true")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("pwned passwords request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("pwned passwords returned status %d", resp.StatusCode)
+	}
+
+	scanner := bufio.NewScanner(io.LimitReader(resp.Body, 4<<20))
+	for scanner.Scan() {
+		candidate, count, ok := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if !ok || !strings.EqualFold(candidate, suffix) {
+			continue
+		}
+		n, err := strconv.Atoi(count)
+		if err != nil {
+			return false, fmt.Errorf("pwned passwords response invalid: %w", err)
+		}
+		return n >= c.threshold, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return false, fmt.Errorf("pwned passwords response invalid: %w", err)
+	}
+	return false, nil
+}
+
+// WithBreachChecker memasang BreachChecker yang dipanggil setelah validasi kekuatan password
+// pada Register, ResetPassword, dan ChangePassword, lalu mengembalikan instance service.
+// Jika checker gagal (misal API tidak dapat dihubungi), error dicatat ke logger dan password
+// tetap diterima agar gangguan layanan eksternal tidak memblokir user.
+//
+// Example:
+//
+//	if cfg.Password.BreachCheck {
+//	    authService.WithBreachChecker(dim.NewPwnedPasswordsChecker())
+//	}
+func (s *AuthService) WithBreachChecker(checker BreachChecker) *AuthService {
+	s.breachChecker = checker
+	return s
+}
+
+// checkPassword menjalankan PasswordValidator lalu BreachChecker (jika dipasang).
+func (s *AuthService) checkPassword(ctx context.Context, locale, password string) error {
+	if err := s.pwValidator.validate(locale, password); err != nil {
+		return err
+	}
+	if s.breachChecker == nil {
+		return nil
+	}
+
+	breached, err := s.breachChecker.Breached(ctx, password)
+	if err != nil {
+		if s.logger != nil {
+			s.logger.Warn("Password breach check failed", "error", err.Error())
+		}
+		return nil
+	}
+	if breached {
+		return NewAppError("Validasi kata sandi gagal", 400).
+			WithFieldError("password", Translate(locale, "Kata sandi pernah bocor dalam kebocoran data, gunakan kata sandi lain"))
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package dim
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPwnedPasswordsChecker(t *testing.T) {
+	sum := sha1.Sum([]byte("ValidPass123!"))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	var gotPath, gotPadding string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotPadding = r.Header.Get("Add-Padding")
+		if r.URL.Path == "/range/00000" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintf(w, "0018A45C4D1DEF81644B54AB7F969B88D65:0\r\n%s:3\r\n", strings.ToLower(hash[5:]))
+	}))
+	defer server.Close()
+
+	checker := NewPwnedPasswordsChecker().WithEndpoint(server.URL + "/range/")
+	breached, err := checker.Breached(context.Background(), "ValidPass123!")
+	if err != nil || !breached {
+		t.Fatalf("Breached = %v, %v; want true", breached, err)
+	}
+	if gotPath != "/range/"+hash[:5] || gotPadding != "true" {
+		t.Errorf("request path = %s, Add-Padding = %q; only the hash prefix should be sent", gotPath, gotPadding)
+	}
+
+	if breached, _ := checker.WithThreshold(5).Breached(context.Background(), "ValidPass123!"); breached {
+		t.Error("count below threshold should not be reported as breached")
+	}
+	if breached, err := checker.Breached(context.Background(), "AnotherPass123!"); err != nil || breached {
+		t.Errorf("unknown password Breached = %v, %v; want false", breached, err)
+	}
+
+	checker.WithEndpoint(server.URL + "/range/00000?")
+	if _, err := checker.Breached(context.Background(), "ValidPass123!"); err == nil {
+		t.Error("expected error for non-200 response")
+	}
+}
+
+func TestAuthService_BreachChecker(t *testing.T) {
+	ctx := context.Background()
+	service, _, _ := newTestRegisterService(t, RegistrationOptions{})
+
+	checkerErr := error(nil)
+	service.WithBreachChecker(BreachCheckerFunc(func(ctx context.Context, password string) (bool, error) {
+		return password == "Breached123!", checkerErr
+	}))
+
+	_, err := service.Register(ctx, RegisterRequest{Email: "ana@example.com", Password: "Breached123!"})
+	appErr, ok := AsAppError(err)
+	if !ok || appErr.StatusCode != http.StatusBadRequest || appErr.Errors["password"] == nil {
+		t.Fatalf("breached password error = %v, want 400 with password field error", err)
+	}
+	if _, err := service.Register(ctx, RegisterRequest{Email: "ana@example.com", Password: "ValidPass123!"}); err != nil {
+		t.Errorf("Register error: %v", err)
+	}
+
+	// Checker yang gagal tidak memblokir user
+	checkerErr = errors.New("api unavailable")
+	if _, err := service.Register(ctx, RegisterRequest{Email: "budi@example.com", Password: "Breached123!"}); err != nil {
+		t.Errorf("Register with failing checker error: %v", err)
+	}
+}
@@ -1,6 +1,8 @@
 package dim
 
 import (
+	"context"
+	"strings"
 	"testing"
 )
 
@@ -168,3 +170,138 @@ func TestHashPasswordDifferentHashes(t *testing.T) {
 		t.Errorf("VerifyPassword() failed for hash2")
 	}
 }
+
+func TestArgon2idHasher(t *testing.T) {
+	hasher := NewArgon2idHasher(Argon2idParams{Memory: 1024, Iterations: 1})
+	hash, err := hasher.Hash("ValidPass123!")
+	if err != nil {
+		t.Fatalf("Hash error: %v", err)
+	}
+	if !strings.HasPrefix(hash, "$argon2id$v=19$m=1024,t=1,p=1$") {
+		t.Errorf("unexpected hash format: %s", hash)
+	}
+	if err := hasher.Verify(hash, "ValidPass123!"); err != nil {
+		t.Errorf("Verify error: %v", err)
+	}
+	if err := hasher.Verify(hash, "WrongPass123!"); err == nil {
+		t.Error("expected mismatch for wrong password")
+	}
+	if err := hasher.Verify("$argon2id$v=19$m=0,t=1,p=1$AAAA$AAAA", "x"); err == nil {
+		t.Error("expected error for malformed hash")
+	}
+
+	// Hash dengan algoritma lain tetap bisa diverifikasi oleh hasher mana pun
+	bcryptHasher := NewBcryptHasher(4)
+	bcryptHash, _ := bcryptHasher.Hash("ValidPass123!")
+	if err := hasher.Verify(bcryptHash, "ValidPass123!"); err != nil {
+		t.Errorf("argon2id hasher should verify bcrypt hash: %v", err)
+	}
+	if err := bcryptHasher.Verify(hash, "ValidPass123!"); err != nil {
+		t.Errorf("bcrypt hasher should verify argon2id hash: %v", err)
+	}
+}
+
+func TestPasswordHasher_NeedsRehash(t *testing.T) {
+	argon := NewArgon2idHasher(Argon2idParams{Memory: 1024, Iterations: 1})
+	argonHash, _ := argon.Hash("ValidPass123!")
+	bcryptHash, _ := NewBcryptHasher(4).Hash("ValidPass123!")
+
+	tests := []struct {
+		name   string
+		hasher PasswordHasher
+		hash   string
+		want   bool
+	}{
+		{"bcrypt same cost", NewBcryptHasher(4), bcryptHash, false},
+		{"bcrypt cost changed", NewBcryptHasher(5), bcryptHash, true},
+		{"bcrypt from argon2id", NewBcryptHasher(4), argonHash, true},
+		{"argon2id same params", argon, argonHash, false},
+		{"argon2id params changed", NewArgon2idHasher(Argon2idParams{Memory: 2048, Iterations: 1}), argonHash, true},
+		{"argon2id from bcrypt", argon, bcryptHash, true},
+	}
+	for _, tt := range tests {
+		if got := tt.hasher.NeedsRehash(tt.hash); got != tt.want {
+			t.Errorf("%s: NeedsRehash = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestNewPasswordHasher(t *testing.T) {
+	hasher, err := NewPasswordHasher(PasswordConfig{Algorithm: PasswordAlgorithmArgon2id, Argon2Memory: 1024, Argon2Iterations: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := hasher.(*Argon2idHasher); !ok {
+		t.Errorf("hasher = %T, want *Argon2idHasher", hasher)
+	}
+	if hasher, _ := NewPasswordHasher(PasswordConfig{BcryptCost: 4}); hasher.(*BcryptHasher).cost != 4 {
+		t.Errorf("bcrypt cost = %d, want 4", hasher.(*BcryptHasher).cost)
+	}
+	if _, err := NewPasswordHasher(PasswordConfig{Algorithm: "md5"}); err == nil {
+		t.Error("expected error for unsupported algorithm")
+	}
+}
+
+func TestLocalCredentialVerifier_RehashOnLogin(t *testing.T) {
+	ctx := context.Background()
+	bcryptHash, _ := NewBcryptHasher(4).Hash("ValidPass123!")
+	userStore := NewMockUserStore()
+	userStore.AddUser(&MockUser{ID: "1", Email: "ana@example.com", Password: bcryptHash})
+	argon := NewArgon2idHasher(Argon2idParams{Memory: 1024, Iterations: 1})
+	verifier := NewLocalCredentialVerifier(userStore).WithHasher(argon)
+
+	if _, err := verifier.Verify(ctx, "ana@example.com", "WrongPass123!"); err == nil {
+		t.Fatal("expected wrong password to be rejected")
+	}
+	if user, _ := userStore.FindByID(ctx, "1"); user.GetPassword() != bcryptHash {
+		t.Error("hash should not change after a failed login")
+	}
+
+	if _, err := verifier.Verify(ctx, "ana@example.com", "ValidPass123!"); err != nil {
+		t.Fatalf("Verify error: %v", err)
+	}
+	user, _ := userStore.FindByID(ctx, "1")
+	if !strings.HasPrefix(user.GetPassword(), argon2idPrefix) {
+		t.Fatalf("hash after login = %s, want argon2id", user.GetPassword())
+	}
+	if _, err := verifier.Verify(ctx, "ana@example.com", "ValidPass123!"); err != nil {
+		t.Errorf("Verify with rehashed password error: %v", err)
+	}
+}
+
+func TestPasswordValidatorMaxLengthAndDenyList(t *testing.T) {
+	validator := NewPasswordValidator().DenyPasswords("Password123!", " Qwerty123! ")
+
+	if err := validator.Validate(strings.Repeat("Aa1!", 19)); err == nil {
+		t.Error("expected password longer than 72 bytes to be rejected")
+	}
+	if err := validator.Validate("password123!A"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	for _, password := range []string{"Password123!", "PASSWORD123!", "qwerty123!"} {
+		if err := validator.Validate(password); err == nil {
+			t.Errorf("expected denied password %q to be rejected", password)
+		}
+	}
+	if err := validator.SetMaxLength(0).Validate(strings.Repeat("Aa1!", 19)); err != nil {
+		t.Errorf("SetMaxLength(0) should disable the limit: %v", err)
+	}
+}
+
+func TestNewPasswordValidatorFromConfig(t *testing.T) {
+	validator := NewPasswordValidatorFromConfig(PasswordConfig{
+		MinLength:        12,
+		MaxLength:        64,
+		RequireLowercase: true,
+		DenyList:         []string{"correcthorsebattery"},
+	})
+	if err := validator.Validate("short"); err == nil {
+		t.Error("expected min length 12 to be enforced")
+	}
+	if err := validator.Validate("correcthorsebattery"); err == nil {
+		t.Error("expected deny list to be enforced")
+	}
+	if err := validator.Validate("long lowercase passphrase"); err != nil {
+		t.Errorf("disabled character classes should not be required: %v", err)
+	}
+}
@@ -0,0 +1,260 @@
+package dim
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"mime"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// PDFOptions mengatur layout halaman PDF.
+// Nilai kosong menggunakan default backend.
+type PDFOptions struct {
+	PageSize  string // misal "A4", "Letter"
+	Landscape bool
+	Margin    string // margin semua sisi, misal "10mm"
+}
+
+// PDFBackend mengubah dokumen HTML menjadi PDF.
+// Implementasi bawaan: WkhtmltopdfBackend. Backend lain (misal chromedp/headless Chrome)
+// cukup mengimplementasikan interface ini.
+type PDFBackend interface {
+	Render(ctx context.Context, html []byte, opts PDFOptions) ([]byte, error)
+}
+
+// PDFBackendFunc adalah adapter agar fungsi biasa dapat dipakai sebagai PDFBackend.
+type PDFBackendFunc func(ctx context.Context, html []byte, opts PDFOptions) ([]byte, error)
+
+// Render memanggil f(ctx, html, opts).
+func (f PDFBackendFunc) Render(ctx context.Context, html []byte, opts PDFOptions) ([]byte, error) {
+	return f(ctx, html, opts)
+}
+
+// WkhtmltopdfBackend merender PDF dengan menjalankan binary wkhtmltopdf.
+// HTML dikirim via stdin dan PDF dibaca dari stdout, tanpa file sementara.
+type WkhtmltopdfBackend struct {
+	path string
+	args []string
+}
+
+// NewWkhtmltopdfBackend membuat backend wkhtmltopdf.
+//
+// Parameters:
+//   - path: path binary; kosong berarti "wkhtmltopdf" dari PATH
+//   - args: argumen tambahan, misal "--enable-local-file-access"
+//
+// Returns:
+//   - *WkhtmltopdfBackend: backend siap digunakan
+//
+// Example:
+//
+//	backend := dim.NewWkhtmltopdfBackend("", "--quiet")
+func NewWkhtmltopdfBackend(path string, args ...string) *WkhtmltopdfBackend {
+	if path == "" {
+		path = "wkhtmltopdf"
+	}
+	return &WkhtmltopdfBackend{path: path, args: args}
+}
+
+// Render menjalankan wkhtmltopdf dan mengembalikan isi PDF.
+func (b *WkhtmltopdfBackend) Render(ctx context.Context, html []byte, opts PDFOptions) ([]byte, error) {
+	args := append([]string{}, b.args...)
+	if opts.PageSize != "" {
+		args = append(args, "--page-size", opts.PageSize)
+	}
+	if opts.Landscape {
+		args = append(args, "--orientation", "Landscape")
+	}
+	if opts.Margin != "" {
+		args = append(args,
+			"--margin-top", opts.Margin,
+			"--margin-right", opts.Margin,
+			"--margin-bottom", opts.Margin,
+			"--margin-left", opts.Margin,
+		)
+	}
+	args = append(args, "-", "-")
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, b.path, args...)
+	cmd.Stdin = bytes.NewReader(html)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("failed to run wkhtmltopdf: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// PDFRenderer merender html/template menjadi PDF melalui PDFBackend.
+type PDFRenderer struct {
+	backend   PDFBackend
+	templates *template.Template
+	options   PDFOptions
+}
+
+// NewPDFRenderer membuat renderer PDF.
+//
+// Parameters:
+//   - backend: PDFBackend yang mengubah HTML menjadi PDF
+//   - templates: kumpulan template HTML (boleh nil jika hanya memakai RenderHTML)
+//
+// Returns:
+//   - *PDFRenderer: renderer siap digunakan
+//
+// Example:
+//
+//	templates := template.Must(template.ParseFS(templatesFS, "templates/pdf/*.html"))
+//	pdf := dim.NewPDFRenderer(dim.NewWkhtmltopdfBackend(""), templates).
+//	    WithOptions(dim.PDFOptions{PageSize: "A4", Margin: "15mm"})
+func NewPDFRenderer(backend PDFBackend, templates *template.Template) *PDFRenderer {
+	return &PDFRenderer{backend: backend, templates: templates}
+}
+
+// WithOptions mengatur PDFOptions default untuk semua render.
+func (p *PDFRenderer) WithOptions(opts PDFOptions) *PDFRenderer {
+	p.options = opts
+	return p
+}
+
+// Render mengeksekusi template bernama name dengan data lalu mengubahnya menjadi PDF.
+//
+// Example:
+//
+//	data, err := pdf.Render(ctx, "invoice.html", invoice)
+func (p *PDFRenderer) Render(ctx context.Context, name string, data interface{}) ([]byte, error) {
+	if p.templates == nil {
+		return nil, errors.New("pdf renderer has no templates")
+	}
+
+	var html bytes.Buffer
+	if err := p.templates.ExecuteTemplate(&html, name, data); err != nil {
+		return nil, fmt.Errorf("failed to execute pdf template %s: %w", name, err)
+	}
+
+	return p.RenderHTML(ctx, html.Bytes())
+}
+
+// RenderHTML mengubah dokumen HTML yang sudah jadi menjadi PDF.
+func (p *PDFRenderer) RenderHTML(ctx context.Context, html []byte) ([]byte, error) {
+	pdf, err := p.backend.Render(ctx, html, p.options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render pdf: %w", err)
+	}
+	return pdf, nil
+}
+
+// Serve merender template menjadi PDF dan mengirimnya sebagai attachment.
+// Jika render gagal, response 500 dikirim dan error dikembalikan untuk di-log.
+//
+// Example:
+//
+//	router.Get("/invoices/{id}/pdf", func(w http.ResponseWriter, r *http.Request) {
+//	    invoice, _ := invoices.Find(r.Context(), dim.GetParam(r, "id"))
+//	    pdf.Serve(w, r, "invoice-"+invoice.Number+".pdf", "invoice.html", invoice)
+//	})
+func (p *PDFRenderer) Serve(w http.ResponseWriter, r *http.Request, filename, name string, data interface{}) error {
+	pdf, err := p.Render(r.Context(), name, data)
+	if err != nil {
+		InternalServerError(w, "Gagal membuat PDF")
+		return err
+	}
+	return ServePDF(w, r, filename, pdf)
+}
+
+// PDFSink menerima hasil render PDF dari job background (file, object storage, email, dll).
+type PDFSink func(ctx context.Context, pdf []byte) error
+
+// Job mengembalikan fungsi job yang merender template dan menyerahkan hasilnya ke sink.
+// Fungsi ini dapat dijalankan oleh job queue atau goroutine untuk laporan berat
+// sehingga request tidak menunggu proses render.
+//
+// Example:
+//
+//	job := pdf.Job("report.html", report, dim.PDFFileSink("/var/reports/"+id+".pdf"))
+//	go func() {
+//	    if err := job(context.Background()); err != nil {
+//	        logger.Error("report gagal", "error", err)
+//	    }
+//	}()
+func (p *PDFRenderer) Job(name string, data interface{}, sink PDFSink) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		pdf, err := p.Render(ctx, name, data)
+		if err != nil {
+			return err
+		}
+		return sink(ctx, pdf)
+	}
+}
+
+// PDFFileSink menulis PDF ke path secara atomik (file sementara lalu rename),
+// sehingga pembaca tidak pernah melihat file yang setengah tertulis.
+func PDFFileSink(path string) PDFSink {
+	return func(ctx context.Context, pdf []byte) error {
+		tmp, err := os.CreateTemp(filepath.Dir(path), ".pdf-*")
+		if err != nil {
+			return fmt.Errorf("failed to create pdf file: %w", err)
+		}
+		defer os.Remove(tmp.Name())
+
+		if _, err := tmp.Write(pdf); err != nil {
+			tmp.Close()
+			return fmt.Errorf("failed to write pdf file: %w", err)
+		}
+		if err := tmp.Close(); err != nil {
+			return fmt.Errorf("failed to write pdf file: %w", err)
+		}
+		if err := os.Rename(tmp.Name(), path); err != nil {
+			return fmt.Errorf("failed to move pdf file: %w", err)
+		}
+		return nil
+	}
+}
+
+// ServePDF mengirim PDF sebagai attachment (diunduh browser).
+// Seperti ServeFile, Content-Type dan Content-Disposition di-set otomatis; selain itu
+// Content-Length, HEAD, dan Range request ditangani oleh http.ServeContent.
+//
+// Parameters:
+//   - w: http.ResponseWriter
+//   - r: request asal (untuk Range dan HEAD)
+//   - filename: nama file yang disarankan ke browser
+//   - pdf: isi PDF
+//
+// Returns:
+//   - error: selalu nil, disediakan untuk konsistensi dengan ServeFile
+//
+// Example:
+//
+//	dim.ServePDF(w, r, "invoice-2024-001.pdf", pdfBytes)
+func ServePDF(w http.ResponseWriter, r *http.Request, filename string, pdf []byte) error {
+	return servePDF(w, r, "attachment", filename, pdf)
+}
+
+// ServePDFInline mengirim PDF untuk ditampilkan langsung di browser.
+//
+// Example:
+//
+//	dim.ServePDFInline(w, r, "report.pdf", pdfBytes)
+func ServePDFInline(w http.ResponseWriter, r *http.Request, filename string, pdf []byte) error {
+	return servePDF(w, r, "inline", filename, pdf)
+}
+
+func servePDF(w http.ResponseWriter, r *http.Request, disposition, filename string, pdf []byte) error {
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": filename}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+
+	http.ServeContent(w, r, filename, time.Time{}, bytes.NewReader(pdf))
+	return nil
+}
@@ -0,0 +1,176 @@
+package dim
+
+import (
+	"context"
+	"errors"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// echoPDFBackend returns the HTML prefixed with a fake PDF header.
+var echoPDFBackend = PDFBackendFunc(func(ctx context.Context, html []byte, opts PDFOptions) ([]byte, error) {
+	return append([]byte("%PDF-"+opts.PageSize+"\n"), html...), nil
+})
+
+func testPDFTemplates() *template.Template {
+	return template.Must(template.New("invoice.html").Parse(`<h1>Invoice {{.Number}}</h1><p>{{.Note}}</p>`))
+}
+
+func TestPDFRenderer_Render(t *testing.T) {
+	renderer := NewPDFRenderer(echoPDFBackend, testPDFTemplates()).WithOptions(PDFOptions{PageSize: "A4"})
+
+	pdf, err := renderer.Render(context.Background(), "invoice.html", map[string]string{
+		"Number": "INV-001",
+		"Note":   "<script>x</script>",
+	})
+	if err != nil {
+		t.Fatalf("Render error: %v", err)
+	}
+
+	got := string(pdf)
+	if !strings.HasPrefix(got, "%PDF-A4\n<h1>Invoice INV-001</h1>") {
+		t.Errorf("unexpected pdf %q", got)
+	}
+	if strings.Contains(got, "<script>") {
+		t.Error("expected template data to be HTML escaped")
+	}
+}
+
+func TestPDFRenderer_RenderErrors(t *testing.T) {
+	failing := PDFBackendFunc(func(ctx context.Context, html []byte, opts PDFOptions) ([]byte, error) {
+		return nil, errors.New("backend down")
+	})
+
+	if _, err := NewPDFRenderer(echoPDFBackend, testPDFTemplates()).Render(context.Background(), "missing.html", nil); err == nil {
+		t.Error("expected error for missing template")
+	}
+	if _, err := NewPDFRenderer(echoPDFBackend, nil).Render(context.Background(), "invoice.html", nil); err == nil {
+		t.Error("expected error without templates")
+	}
+	if _, err := NewPDFRenderer(failing, testPDFTemplates()).Render(context.Background(), "invoice.html", nil); err == nil {
+		t.Error("expected backend error")
+	}
+}
+
+func TestServePDF(t *testing.T) {
+	pdf := []byte("%PDF-1.7 test document")
+
+	tests := []struct {
+		name        string
+		serve       func(w http.ResponseWriter, r *http.Request, filename string, pdf []byte) error
+		disposition string
+	}{
+		{"attachment", ServePDF, `attachment; filename=invoice-001.pdf`},
+		{"inline", ServePDFInline, `inline; filename=invoice-001.pdf`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			tt.serve(w, httptest.NewRequest(http.MethodGet, "/invoice.pdf", nil), "invoice-001.pdf", pdf)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d", w.Code)
+			}
+			if got := w.Header().Get("Content-Type"); got != "application/pdf" {
+				t.Errorf("Content-Type = %q", got)
+			}
+			if got := w.Header().Get("Content-Disposition"); got != tt.disposition {
+				t.Errorf("Content-Disposition = %q, want %q", got, tt.disposition)
+			}
+			if got := w.Header().Get("Content-Length"); got != "22" {
+				t.Errorf("Content-Length = %q", got)
+			}
+			if w.Body.String() != string(pdf) {
+				t.Errorf("body = %q", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestServePDF_RangeAndFilenameEscaping(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/report.pdf", nil)
+	r.Header.Set("Range", "bytes=0-3")
+	w := httptest.NewRecorder()
+
+	ServePDF(w, r, `laporan "Q1".pdf`, []byte("%PDF-1.7"))
+
+	if w.Code != http.StatusPartialContent || w.Body.String() != "%PDF" {
+		t.Errorf("status = %d, body = %q", w.Code, w.Body.String())
+	}
+	if got := w.Header().Get("Content-Disposition"); got != `attachment; filename="laporan \"Q1\".pdf"` {
+		t.Errorf("Content-Disposition = %q", got)
+	}
+}
+
+func TestPDFRenderer_Serve(t *testing.T) {
+	renderer := NewPDFRenderer(echoPDFBackend, testPDFTemplates())
+
+	w := httptest.NewRecorder()
+	err := renderer.Serve(w, httptest.NewRequest(http.MethodGet, "/", nil), "a.pdf", "invoice.html", map[string]string{"Number": "1"})
+	if err != nil || w.Code != http.StatusOK || !strings.HasPrefix(w.Body.String(), "%PDF-") {
+		t.Errorf("err = %v, status = %d, body = %q", err, w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	err = renderer.Serve(w, httptest.NewRequest(http.MethodGet, "/", nil), "a.pdf", "missing.html", nil)
+	if err == nil || w.Code != http.StatusInternalServerError {
+		t.Errorf("expected 500 on render failure, got err = %v, status = %d", err, w.Code)
+	}
+}
+
+func TestPDFRenderer_JobWritesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.pdf")
+	job := NewPDFRenderer(echoPDFBackend, testPDFTemplates()).
+		Job("invoice.html", map[string]string{"Number": "R-9"}, PDFFileSink(path))
+
+	if err := job(context.Background()); err != nil {
+		t.Fatalf("job error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil || !strings.Contains(string(data), "Invoice R-9") {
+		t.Errorf("unexpected file content %q, %v", data, err)
+	}
+
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("expected temp file to be cleaned up, found %d entries", len(entries))
+	}
+}
+
+func TestWkhtmltopdfBackend(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell script stub requires a POSIX shell")
+	}
+
+	// Stub binary: writes its arguments, then echoes stdin to stdout
+	dir := t.TempDir()
+	bin := filepath.Join(dir, "wkhtmltopdf")
+	script := "#!/bin/sh\necho \"$@\"\ncat\n"
+	if err := os.WriteFile(bin, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	backend := NewWkhtmltopdfBackend(bin, "--quiet")
+	out, err := backend.Render(context.Background(), []byte("<p>hi</p>"), PDFOptions{PageSize: "A4", Landscape: true, Margin: "10mm"})
+	if err != nil {
+		t.Fatalf("Render error: %v", err)
+	}
+
+	want := "--quiet --page-size A4 --orientation Landscape --margin-top 10mm --margin-right 10mm --margin-bottom 10mm --margin-left 10mm - -\n<p>hi</p>"
+	if string(out) != want {
+		t.Errorf("output = %q, want %q", out, want)
+	}
+
+	failing := NewWkhtmltopdfBackend(filepath.Join(dir, "missing"))
+	if _, err := failing.Render(context.Background(), []byte("x"), PDFOptions{}); err == nil {
+		t.Error("expected error for missing binary")
+	}
+}
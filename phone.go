@@ -0,0 +1,381 @@
+package dim
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultPhoneRegion adalah kode negara ISO 3166-1 alpha-2 yang dipakai untuk nomor tanpa kode
+// negara (misal "0812...") jika region tidak diberikan.
+var DefaultPhoneRegion = "ID"
+
+// ErrInvalidPhone dikembalikan jika nomor telepon tidak dapat di-parse atau panjangnya tidak valid.
+var ErrInvalidPhone = errors.New("invalid phone number")
+
+// phoneRegion berisi metadata penomoran satu negara: kode negara, prefix trunk nasional,
+// dan rentang panjang nomor nasional (tanpa kode negara dan prefix trunk).
+type phoneRegion struct {
+	code   int
+	trunk  string
+	minLen int
+	maxLen int
+	groups []int // pengelompokan digit untuk tampilan; nil berarti pengelompokan umum
+}
+
+// phoneRegions adalah metadata negara yang didukung. Negara yang berbagi kode (misal US/CA)
+// diurutkan dengan yang pertama sebagai default saat inferensi dari kode negara.
+var phoneRegions = map[string]phoneRegion{
+	"ID": {code: 62, trunk: "0", minLen: 8, maxLen: 12},
+	"MY": {code: 60, trunk: "0", minLen: 8, maxLen: 10},
+	"SG": {code: 65, minLen: 8, maxLen: 8, groups: []int{4, 4}},
+	"PH": {code: 63, trunk: "0", minLen: 8, maxLen: 10},
+	"TH": {code: 66, trunk: "0", minLen: 8, maxLen: 9},
+	"VN": {code: 84, trunk: "0", minLen: 9, maxLen: 10},
+	"BN": {code: 673, minLen: 7, maxLen: 7},
+	"TL": {code: 670, minLen: 7, maxLen: 8},
+	"HK": {code: 852, minLen: 8, maxLen: 8, groups: []int{4, 4}},
+	"TW": {code: 886, trunk: "0", minLen: 8, maxLen: 9},
+	"CN": {code: 86, trunk: "0", minLen: 10, maxLen: 11, groups: []int{3, 4, 4}},
+	"JP": {code: 81, trunk: "0", minLen: 9, maxLen: 10},
+	"KR": {code: 82, trunk: "0", minLen: 8, maxLen: 10},
+	"IN": {code: 91, trunk: "0", minLen: 10, maxLen: 10, groups: []int{5, 5}},
+	"AU": {code: 61, trunk: "0", minLen: 9, maxLen: 9, groups: []int{3, 3, 3}},
+	"NZ": {code: 64, trunk: "0", minLen: 8, maxLen: 10},
+	"US": {code: 1, trunk: "1", minLen: 10, maxLen: 10, groups: []int{3, 3, 4}},
+	"CA": {code: 1, trunk: "1", minLen: 10, maxLen: 10, groups: []int{3, 3, 4}},
+	"GB": {code: 44, trunk: "0", minLen: 9, maxLen: 10, groups: []int{4, 6}},
+	"DE": {code: 49, trunk: "0", minLen: 6, maxLen: 13},
+	"FR": {code: 33, trunk: "0", minLen: 9, maxLen: 9, groups: []int{1, 2, 2, 2, 2}},
+	"NL": {code: 31, trunk: "0", minLen: 9, maxLen: 9},
+	"SA": {code: 966, trunk: "0", minLen: 8, maxLen: 9},
+	"AE": {code: 971, trunk: "0", minLen: 8, maxLen: 9},
+	"TR": {code: 90, trunk: "0", minLen: 10, maxLen: 10, groups: []int{3, 3, 4}},
+	"BR": {code: 55, trunk: "0", minLen: 10, maxLen: 11},
+}
+
+// phoneRegionsByCode memetakan kode negara ke region, region utama lebih dulu.
+var phoneRegionsByCode = func() map[int][]string {
+	byCode := map[int][]string{}
+	for region, meta := range phoneRegions {
+		byCode[meta.code] = append(byCode[meta.code], region)
+	}
+	for _, regions := range byCode {
+		sort.Slice(regions, func(i, j int) bool {
+			// US menjadi region utama untuk +1; selain itu urut alfabet agar deterministik
+			if regions[i] == "US" || regions[j] == "US" {
+				return regions[i] == "US"
+			}
+			return regions[i] < regions[j]
+		})
+	}
+	return byCode
+}()
+
+// PhoneNumber adalah nomor telepon yang sudah di-parse.
+type PhoneNumber struct {
+	// CountryCode adalah kode negara, misal 62.
+	CountryCode int
+	// NationalNumber adalah nomor nasional tanpa kode negara dan prefix trunk, misal "81234567890".
+	NationalNumber string
+	// Region adalah kode negara ISO 3166-1 alpha-2, misal "ID".
+	Region string
+}
+
+// ParsePhone mem-parse nomor telepon dari input pengguna. Spasi, tanda hubung, titik, dan kurung
+// diabaikan. Nomor dengan "+" atau "00" dibaca sebagai nomor internasional dan negaranya disimpulkan
+// dari kode negara; nomor lain dibaca sebagai nomor nasional di region (misal "0812..." di "ID").
+// Nomor yang diawali kode negara region tanpa "+" (misal "62812...") juga diterima.
+//
+// Parameters:
+//   - raw: nomor telepon, misal "0812-3456-7890" atau "+62 812 3456 7890"
+//   - region: kode negara ISO untuk nomor nasional; kosong berarti DefaultPhoneRegion
+//
+// Returns:
+//   - PhoneNumber: nomor yang sudah di-parse
+//   - error: ErrInvalidPhone jika nomor tidak valid atau region tidak dikenal
+//
+// Example:
+//
+//	p, err := dim.ParsePhone("0812-3456-7890", "ID")
+//	p.E164()          // "+6281234567890"
+//	p.International() // "+62 812-3456-7890"
+//	p.National()      // "0812-3456-7890"
+func ParsePhone(raw, region string) (PhoneNumber, error) {
+	if region == "" {
+		region = DefaultPhoneRegion
+	}
+	region = strings.ToUpper(region)
+	meta, ok := phoneRegions[region]
+	if !ok {
+		return PhoneNumber{}, fmt.Errorf("%w: unknown region %q", ErrInvalidPhone, region)
+	}
+
+	digits, international, err := phoneDigits(raw)
+	if err != nil {
+		return PhoneNumber{}, err
+	}
+
+	if international {
+		return parseInternationalPhone(digits, region)
+	}
+
+	// Nomor nasional dengan prefix trunk, misal "0812..." atau "1-415..." untuk US
+	if meta.trunk != "" && strings.HasPrefix(digits, meta.trunk) {
+		if p, err := newPhoneNumber(region, digits[len(meta.trunk):]); err == nil {
+			return p, nil
+		}
+	}
+	// Nomor dengan kode negara tanpa "+", misal "6281234567890"
+	code := strconv.Itoa(meta.code)
+	if strings.HasPrefix(digits, code) {
+		if p, err := newPhoneNumber(region, digits[len(code):]); err == nil {
+			return p, nil
+		}
+	}
+	return newPhoneNumber(region, digits)
+}
+
+// NormalizePhone mengembalikan nomor dalam format E.164, misal "+6281234567890".
+// Lihat ParsePhone untuk format input yang diterima.
+func NormalizePhone(raw, region string) (string, error) {
+	p, err := ParsePhone(raw, region)
+	if err != nil {
+		return "", err
+	}
+	return p.E164(), nil
+}
+
+// IsValidPhone mengecek apakah raw adalah nomor telepon yang valid untuk region.
+func IsValidPhone(raw, region string) bool {
+	_, err := ParsePhone(raw, region)
+	return err == nil
+}
+
+// E164 mengembalikan nomor dalam format E.164, misal "+6281234567890".
+func (p PhoneNumber) E164() string {
+	if p.CountryCode == 0 {
+		return ""
+	}
+	return "+" + strconv.Itoa(p.CountryCode) + p.NationalNumber
+}
+
+// International mengembalikan nomor untuk tampilan internasional, misal "+62 812-3456-7890".
+func (p PhoneNumber) International() string {
+	if p.CountryCode == 0 {
+		return ""
+	}
+	return "+" + strconv.Itoa(p.CountryCode) + " " + p.groupedNumber()
+}
+
+// National mengembalikan nomor untuk tampilan dalam negeri dengan prefix trunk,
+// misal "0812-3456-7890". Nomor US/CA ditampilkan tanpa prefix trunk, misal "415-555-2671".
+func (p PhoneNumber) National() string {
+	if p.CountryCode == 0 {
+		return ""
+	}
+	trunk := phoneRegions[p.Region].trunk
+	if p.CountryCode == 1 {
+		trunk = ""
+	}
+	return trunk + p.groupedNumber()
+}
+
+// String mengembalikan format E.164.
+func (p PhoneNumber) String() string {
+	return p.E164()
+}
+
+// groupedNumber mengelompokkan digit nomor nasional dengan tanda hubung.
+func (p PhoneNumber) groupedNumber() string {
+	number := p.NationalNumber
+	groups := phoneRegions[p.Region].groups
+	if phoneGroupsLen(groups) != len(number) {
+		groups = phoneGroups(len(number))
+	}
+
+	parts := make([]string, 0, len(groups))
+	for _, size := range groups {
+		parts = append(parts, number[:size])
+		number = number[size:]
+	}
+	return strings.Join(parts, "-")
+}
+
+// phoneGroups mengembalikan pengelompokan umum untuk nomor n digit, misal 11 → 3-4-4.
+func phoneGroups(n int) []int {
+	switch n {
+	case 6:
+		return []int{3, 3}
+	case 7:
+		return []int{3, 4}
+	case 8:
+		return []int{4, 4}
+	case 9:
+		return []int{3, 3, 3}
+	case 10:
+		return []int{3, 3, 4}
+	case 11:
+		return []int{3, 4, 4}
+	case 12:
+		return []int{4, 4, 4}
+	case 13:
+		return []int{3, 3, 3, 4}
+	}
+	return []int{n}
+}
+
+func phoneGroupsLen(values []int) int {
+	total := 0
+	for _, v := range values {
+		total += v
+	}
+	return total
+}
+
+// phoneDigits membersihkan input dan menandai apakah nomor internasional ("+" atau "00").
+func phoneDigits(raw string) (digits string, international bool, err error) {
+	s := strings.TrimSpace(raw)
+	if strings.HasPrefix(s, "+") {
+		international = true
+		s = s[1:]
+	}
+
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == ' ' || r == '-' || r == '.' || r == '(' || r == ')' || r == '/':
+		default:
+			return "", false, fmt.Errorf("%w: unexpected character %q", ErrInvalidPhone, r)
+		}
+	}
+	digits = b.String()
+
+	if !international && strings.HasPrefix(digits, "00") {
+		international = true
+		digits = digits[2:]
+	}
+	if digits == "" {
+		return "", false, fmt.Errorf("%w: no digits", ErrInvalidPhone)
+	}
+	return digits, international, nil
+}
+
+// parseInternationalPhone mencari kode negara (1-3 digit) dari digits. Untuk kode yang dipakai
+// beberapa negara, region default dipilih jika cocok.
+func parseInternationalPhone(digits, defaultRegion string) (PhoneNumber, error) {
+	for size := 1; size <= 3 && size < len(digits); size++ {
+		code, _ := strconv.Atoi(digits[:size])
+		regions, ok := phoneRegionsByCode[code]
+		if !ok {
+			continue
+		}
+		region := regions[0]
+		for _, r := range regions {
+			if r == defaultRegion {
+				region = r
+			}
+		}
+		return newPhoneNumber(region, digits[size:])
+	}
+	return PhoneNumber{}, fmt.Errorf("%w: unknown country code in +%s", ErrInvalidPhone, digits)
+}
+
+// newPhoneNumber memvalidasi panjang nomor nasional untuk region.
+func newPhoneNumber(region, national string) (PhoneNumber, error) {
+	meta := phoneRegions[region]
+	// Sebagian pengguna tetap menulis prefix trunk setelah kode negara, misal "+62 0812..."
+	if meta.trunk == "0" && strings.HasPrefix(national, "0") {
+		national = national[1:]
+	}
+	if len(national) < meta.minLen || len(national) > meta.maxLen {
+		return PhoneNumber{}, fmt.Errorf("%w: %s numbers must have %d-%d digits after the country code", ErrInvalidPhone, region, meta.minLen, meta.maxLen)
+	}
+	return PhoneNumber{CountryCode: meta.code, NationalNumber: national, Region: region}, nil
+}
+
+// Phone adalah nomor telepon yang disimpan dalam format E.164. Saat di-decode dari JSON atau
+// database, nomor yang valid dinormalisasi dengan DefaultPhoneRegion; nomor yang tidak valid
+// disimpan apa adanya agar dapat ditolak oleh tag validate:"phone" dengan pesan per field.
+// Dapat dipakai sebagai JsonNull[Phone] untuk field nullable.
+//
+// Example:
+//
+//	type RegisterRequest struct {
+//	    Phone dim.Phone `json:"phone" validate:"required,phone"`
+//	}
+//	// {"phone": "0812 3456 7890"} → req.Phone == "+6281234567890"
+type Phone string
+
+// NewPhone menormalisasi raw menjadi Phone.
+func NewPhone(raw, region string) (Phone, error) {
+	normalized, err := NormalizePhone(raw, region)
+	return Phone(normalized), err
+}
+
+// Parse mem-parse Phone menjadi PhoneNumber untuk format tampilan.
+func (p Phone) Parse() (PhoneNumber, error) {
+	return ParsePhone(string(p), "")
+}
+
+// String mengembalikan nomor sebagai string.
+func (p Phone) String() string {
+	return string(p)
+}
+
+// UnmarshalJSON mendecode string JSON dan menormalisasinya ke E.164 jika valid.
+func (p *Phone) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*p = ""
+		return nil
+	}
+	var raw string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*p = normalizePhoneLenient(raw)
+	return nil
+}
+
+// UnmarshalText mendukung binding dari form dan query string.
+func (p *Phone) UnmarshalText(text []byte) error {
+	*p = normalizePhoneLenient(string(text))
+	return nil
+}
+
+// Scan mengimplementasikan sql.Scanner.
+func (p *Phone) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*p = ""
+	case string:
+		*p = normalizePhoneLenient(v)
+	case []byte:
+		*p = normalizePhoneLenient(string(v))
+	default:
+		return fmt.Errorf("cannot scan %T into Phone", src)
+	}
+	return nil
+}
+
+// Value mengimplementasikan driver.Valuer; nomor kosong disimpan sebagai NULL.
+func (p Phone) Value() (driver.Value, error) {
+	if p == "" {
+		return nil, nil
+	}
+	return string(p), nil
+}
+
+func normalizePhoneLenient(raw string) Phone {
+	if normalized, err := NormalizePhone(raw, ""); err == nil {
+		return Phone(normalized)
+	}
+	return Phone(strings.TrimSpace(raw))
+}
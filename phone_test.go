@@ -0,0 +1,191 @@
+package dim
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestParsePhone(t *testing.T) {
+	tests := []struct {
+		raw, region string
+		e164        string
+		wantRegion  string
+	}{
+		{"0812-3456-7890", "ID", "+6281234567890", "ID"},
+		{"0812 3456 7890", "", "+6281234567890", "ID"},
+		{"+62 812 3456 7890", "ID", "+6281234567890", "ID"},
+		{"6281234567890", "ID", "+6281234567890", "ID"},
+		{"006281234567890", "ID", "+6281234567890", "ID"},
+		{"+62 0812 3456 7890", "ID", "+6281234567890", "ID"},
+		{"(021) 555-1234", "ID", "+62215551234", "ID"},
+		{"+60 12-345 6789", "ID", "+60123456789", "MY"},
+		{"9123 4567", "SG", "+6591234567", "SG"},
+		{"+1 (415) 555-2671", "ID", "+14155552671", "US"},
+		{"+1 416 555 0199", "CA", "+14165550199", "CA"},
+		{"1-415-555-2671", "US", "+14155552671", "US"},
+		{"415.555.2671", "us", "+14155552671", "US"},
+		{"07911 123456", "GB", "+447911123456", "GB"},
+		{"+971 50 123 4567", "", "+971501234567", "AE"},
+	}
+
+	for _, tt := range tests {
+		p, err := ParsePhone(tt.raw, tt.region)
+		if err != nil {
+			t.Errorf("ParsePhone(%q, %q) error = %v", tt.raw, tt.region, err)
+			continue
+		}
+		if p.E164() != tt.e164 || p.Region != tt.wantRegion {
+			t.Errorf("ParsePhone(%q, %q) = %s (%s), want %s (%s)", tt.raw, tt.region, p.E164(), p.Region, tt.e164, tt.wantRegion)
+		}
+	}
+}
+
+func TestParsePhone_Invalid(t *testing.T) {
+	tests := []struct{ raw, region string }{
+		{"", "ID"},
+		{"abc", "ID"},
+		{"0812", "ID"},
+		{"0812-3456-7890-1234", "ID"},
+		{"+999 1234567", "ID"},
+		{"9123 456", "SG"},
+		{"+1 415 555", "US"},
+		{"0812345678", "XX"},
+		{"+62 812 3456 7890 ext 12", "ID"},
+	}
+	for _, tt := range tests {
+		if _, err := ParsePhone(tt.raw, tt.region); !errors.Is(err, ErrInvalidPhone) {
+			t.Errorf("ParsePhone(%q, %q) error = %v, want ErrInvalidPhone", tt.raw, tt.region, err)
+		}
+		if IsValidPhone(tt.raw, tt.region) {
+			t.Errorf("IsValidPhone(%q, %q) = true", tt.raw, tt.region)
+		}
+	}
+}
+
+func TestPhoneNumber_Format(t *testing.T) {
+	tests := []struct {
+		raw, region, international, national string
+	}{
+		{"081234567890", "ID", "+62 812-3456-7890", "0812-3456-7890"},
+		{"0812345678", "ID", "+62 812-345-678", "0812-345-678"},
+		{"+14155552671", "", "+1 415-555-2671", "415-555-2671"},
+		{"+6591234567", "", "+65 9123-4567", "9123-4567"},
+		{"0612345678", "FR", "+33 6-12-34-56-78", "06-12-34-56-78"},
+	}
+	for _, tt := range tests {
+		p, err := ParsePhone(tt.raw, tt.region)
+		if err != nil {
+			t.Fatalf("ParsePhone(%q) error = %v", tt.raw, err)
+		}
+		if p.International() != tt.international || p.National() != tt.national {
+			t.Errorf("%s: International() = %q, National() = %q; want %q, %q", tt.raw, p.International(), p.National(), tt.international, tt.national)
+		}
+	}
+
+	if (PhoneNumber{}).E164() != "" || (PhoneNumber{}).International() != "" {
+		t.Error("zero PhoneNumber should format as empty string")
+	}
+}
+
+func TestNormalizePhone(t *testing.T) {
+	got, err := NormalizePhone("0812-3456-7890", "")
+	if err != nil || got != "+6281234567890" {
+		t.Errorf("NormalizePhone() = %q, %v", got, err)
+	}
+
+	defer func(region string) { DefaultPhoneRegion = region }(DefaultPhoneRegion)
+	DefaultPhoneRegion = "MY"
+	if got, _ := NormalizePhone("012-345 6789", ""); got != "+60123456789" {
+		t.Errorf("NormalizePhone() with DefaultPhoneRegion=MY = %q", got)
+	}
+}
+
+func TestPhone_JSONAndSQL(t *testing.T) {
+	var req struct {
+		Phone    Phone           `json:"phone"`
+		Backup   JsonNull[Phone] `json:"backup"`
+		Invalid  Phone           `json:"invalid"`
+		Optional JsonNull[Phone] `json:"optional"`
+	}
+	body := `{"phone": "0812 3456 7890", "backup": null, "invalid": "12ab"}`
+	if err := json.Unmarshal([]byte(body), &req); err != nil {
+		t.Fatal(err)
+	}
+	if req.Phone != "+6281234567890" {
+		t.Errorf("Phone = %q", req.Phone)
+	}
+	if !req.Backup.Present || req.Backup.Valid {
+		t.Errorf("Backup = %+v, want explicit null", req.Backup)
+	}
+	if req.Invalid != "12ab" {
+		t.Errorf("invalid phone should be kept as-is, got %q", req.Invalid)
+	}
+	if req.Optional.Present {
+		t.Error("Optional should not be present")
+	}
+
+	out, _ := json.Marshal(req.Phone)
+	if string(out) != `"+6281234567890"` {
+		t.Errorf("Marshal = %s", out)
+	}
+
+	var scanned Phone
+	if err := scanned.Scan([]byte("+62 812-3456-7890")); err != nil || scanned != "+6281234567890" {
+		t.Errorf("Scan() = %q, %v", scanned, err)
+	}
+	if err := scanned.Scan(nil); err != nil || scanned != "" {
+		t.Errorf("Scan(nil) = %q, %v", scanned, err)
+	}
+	if err := scanned.Scan(42); err == nil {
+		t.Error("Scan(int) should fail")
+	}
+	if v, _ := Phone("").Value(); v != nil {
+		t.Errorf("empty Value() = %v, want nil", v)
+	}
+	if v, _ := Phone("+6281234567890").Value(); v != "+6281234567890" {
+		t.Errorf("Value() = %v", v)
+	}
+
+	p, err := Phone("+6281234567890").Parse()
+	if err != nil || p.National() != "0812-3456-7890" {
+		t.Errorf("Parse() = %+v, %v", p, err)
+	}
+}
+
+func TestValidateStruct_Phone(t *testing.T) {
+	type request struct {
+		Phone   Phone            `json:"phone" validate:"required,phone"`
+		Office  string           `json:"office" validate:"omitempty,phone=SG"`
+		Backup  JsonNull[string] `json:"backup" validate:"phone"`
+		Mobiles []Phone          `json:"mobiles"`
+	}
+
+	if errs := ValidateStruct(request{Phone: "+6281234567890", Office: "9123 4567"}); errs != nil {
+		t.Errorf("ValidateStruct() = %v", errs)
+	}
+
+	errs := NewValidator().WithLocale("en").Struct(request{
+		Phone:  "12ab",
+		Office: "0812-3456-7890",
+		Backup: NewJsonNull("0812"),
+	}).ErrorMap()
+	if errs["phone"] != "phone must be a valid phone number" || errs["office"] == "" || errs["backup"] == "" {
+		t.Errorf("errors = %v", errs)
+	}
+}
+
+func TestValidator_Phone(t *testing.T) {
+	v := NewValidator().
+		Phone("phone", "0812-3456-7890").
+		Phone("office", "0812-3456-7890", "SG").
+		OptionalPhone("backup", NewJsonNull("+60123456789")).
+		OptionalPhone("other", JsonNull[string]{})
+
+	if !v.HasError("office") || v.HasError("phone") || v.HasError("backup") || v.HasError("other") {
+		t.Errorf("errors = %v", v.ErrorMap())
+	}
+	if got := v.GetError("office"); got != "office harus berupa nomor telepon yang valid" {
+		t.Errorf("message = %q", got)
+	}
+}
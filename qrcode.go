@@ -0,0 +1,583 @@
+package dim
+
+import (
+	"errors"
+	"fmt"
+)
+
+// QRLevel adalah tingkat error correction QR code.
+// Level lebih tinggi tahan terhadap kerusakan/logo di tengah, tetapi kapasitasnya lebih kecil.
+// Nilai kosong berarti QRLevelM.
+type QRLevel string
+
+// Tingkat error correction QR code.
+const (
+	QRLevelL QRLevel = "L" // ~7% codeword dapat dipulihkan
+	QRLevelM QRLevel = "M" // ~15% (default)
+	QRLevelQ QRLevel = "Q" // ~25%
+	QRLevelH QRLevel = "H" // ~30%
+)
+
+// index mengembalikan indeks level pada tabel ECC.
+func (l QRLevel) index() (int, bool) {
+	switch l {
+	case QRLevelL:
+		return 0, true
+	case QRLevelM, "":
+		return 1, true
+	case QRLevelQ:
+		return 2, true
+	case QRLevelH:
+		return 3, true
+	}
+	return 0, false
+}
+
+// ErrQRTooLong dikembalikan jika konten melebihi kapasitas QR code versi 40 pada level yang dipilih.
+var ErrQRTooLong = errors.New("qrcode: content too long")
+
+// qrEccCodewordsPerBlock dan qrNumEccBlocks diindeks [level][version] (ISO/IEC 18004 tabel 9).
+var qrEccCodewordsPerBlock = [4][41]int{
+	{-1, 7, 10, 15, 20, 26, 18, 20, 24, 30, 18, 20, 24, 26, 30, 22, 24, 28, 30, 28, 28, 28, 28, 30, 30, 26, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{-1, 10, 16, 26, 18, 24, 16, 18, 22, 22, 26, 30, 22, 22, 24, 24, 28, 28, 26, 26, 26, 26, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28},
+	{-1, 13, 22, 18, 26, 18, 24, 18, 22, 20, 24, 28, 26, 24, 20, 30, 24, 28, 28, 26, 30, 28, 30, 30, 30, 30, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{-1, 17, 28, 22, 16, 22, 28, 26, 26, 24, 28, 24, 28, 22, 24, 24, 30, 28, 28, 26, 28, 30, 24, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+}
+
+var qrNumEccBlocks = [4][41]int{
+	{-1, 1, 1, 1, 1, 1, 2, 2, 2, 2, 4, 4, 4, 4, 4, 6, 6, 6, 6, 7, 8, 8, 9, 9, 10, 12, 12, 12, 13, 14, 15, 16, 17, 18, 19, 19, 20, 21, 22, 24, 25},
+	{-1, 1, 1, 1, 2, 2, 4, 4, 4, 5, 5, 5, 8, 9, 9, 10, 10, 11, 13, 14, 16, 17, 17, 18, 20, 21, 23, 25, 26, 28, 29, 31, 33, 35, 37, 38, 40, 43, 45, 47, 49},
+	{-1, 1, 1, 2, 2, 4, 4, 6, 6, 8, 8, 8, 10, 12, 16, 12, 17, 16, 18, 21, 20, 23, 23, 25, 27, 29, 34, 34, 35, 38, 40, 43, 45, 48, 51, 53, 56, 59, 62, 65, 68},
+	{-1, 1, 1, 2, 4, 4, 4, 5, 6, 8, 8, 11, 11, 16, 16, 18, 16, 19, 21, 25, 25, 25, 34, 30, 32, 35, 37, 40, 42, 45, 48, 51, 54, 57, 60, 63, 66, 70, 74, 77, 81},
+}
+
+// qrFormatLevelBits adalah nilai 2-bit level pada format information (L=01, M=00, Q=11, H=10).
+var qrFormatLevelBits = [4]int{1, 0, 3, 2}
+
+// QRCode adalah matriks QR code hasil encode.
+// Konten selalu di-encode dalam byte mode (UTF-8), sehingga URL, otpauth:// URI,
+// dan payload pembayaran (misal QRIS) dapat dipakai apa adanya.
+type QRCode struct {
+	Version int     // 1-40
+	Level   QRLevel // level error correction
+	Size    int     // jumlah modul per sisi (17 + 4*Version)
+
+	level      int // indeks level pada tabel ECC
+	modules    [][]bool
+	isFunction [][]bool
+}
+
+// NewQRCode meng-encode content menjadi QR code dengan versi terkecil yang muat.
+//
+// Parameters:
+//   - content: teks yang di-encode (UTF-8)
+//   - level: tingkat error correction (kosong berarti QRLevelM)
+//
+// Returns:
+//   - *QRCode: matriks QR code
+//   - error: ErrQRTooLong jika content tidak muat
+//
+// Example:
+//
+//	qr, err := dim.NewQRCode("https://example.com/pay/INV-001", dim.QRLevelM)
+func NewQRCode(content string, level QRLevel) (*QRCode, error) {
+	lvl, ok := level.index()
+	if !ok {
+		return nil, fmt.Errorf("qrcode: invalid level %q", level)
+	}
+	if level == "" {
+		level = QRLevelM
+	}
+
+	data := []byte(content)
+	version := 0
+	for v := 1; v <= 40; v++ {
+		countBits := 8
+		if v >= 10 {
+			countBits = 16
+		}
+		if len(data) < 1<<countBits && 4+countBits+8*len(data) <= qrNumDataCodewords(v, lvl)*8 {
+			version = v
+			break
+		}
+	}
+	if version == 0 {
+		return nil, ErrQRTooLong
+	}
+
+	qr := &QRCode{Version: version, Level: level, Size: version*4 + 17, level: lvl}
+	qr.modules = make([][]bool, qr.Size)
+	qr.isFunction = make([][]bool, qr.Size)
+	for i := range qr.modules {
+		qr.modules[i] = make([]bool, qr.Size)
+		qr.isFunction[i] = make([]bool, qr.Size)
+	}
+
+	qr.drawFunctionPatterns()
+	qr.drawCodewords(qr.addEccAndInterleave(qr.dataCodewords(data)))
+
+	// Pilih mask dengan penalty terkecil
+	bestMask, bestPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		qr.applyMask(mask)
+		qr.drawFormatBits(mask)
+		if penalty := qr.penalty(); bestPenalty < 0 || penalty < bestPenalty {
+			bestMask, bestPenalty = mask, penalty
+		}
+		qr.applyMask(mask) // XOR kedua kali membatalkan mask
+	}
+	qr.applyMask(bestMask)
+	qr.drawFormatBits(bestMask)
+	qr.isFunction = nil
+
+	return qr, nil
+}
+
+// Dark melaporkan apakah modul pada kolom x dan baris y berwarna gelap.
+// Koordinat di luar matriks dianggap terang (quiet zone).
+func (qr *QRCode) Dark(x, y int) bool {
+	return x >= 0 && y >= 0 && x < qr.Size && y < qr.Size && qr.modules[y][x]
+}
+
+// QROptions mengatur pembuatan gambar QR code.
+type QROptions struct {
+	Level  QRLevel // tingkat error correction; default QRLevelM
+	Size   int     // lebar dan tinggi minimal dalam pixel; default 256
+	Margin int     // quiet zone dalam modul; default 4 sesuai spesifikasi
+}
+
+func (o QROptions) layout(qr *QRCode) (margin, cols, scale int) {
+	margin = o.Margin
+	if margin <= 0 {
+		margin = 4
+	}
+	size := o.Size
+	if size <= 0 {
+		size = 256
+	}
+	cols = qr.Size + 2*margin
+	return margin, cols, max(1, (size+cols-1)/cols)
+}
+
+// QRCodePNG meng-encode content menjadi QR code dalam format PNG.
+// Ukuran gambar dibulatkan ke kelipatan jumlah modul agar setiap modul tajam.
+//
+// Parameters:
+//   - content: teks yang di-encode, misal URL pembayaran atau otpauth:// URI
+//   - opts: level, ukuran, dan quiet zone
+//
+// Returns:
+//   - []byte: gambar PNG
+//   - error: ErrQRTooLong atau error encoding
+//
+// Example:
+//
+//	img, err := dim.QRCodePNG(otpauthURL, dim.QROptions{Size: 200})
+//	src := "data:image/png;base64," + base64.StdEncoding.EncodeToString(img)
+func QRCodePNG(content string, opts QROptions) ([]byte, error) {
+	qr, err := NewQRCode(content, opts.Level)
+	if err != nil {
+		return nil, err
+	}
+	margin, cols, scale := opts.layout(qr)
+	return encodeModulesPNG(cols, cols, scale, scale, func(x, y int) bool {
+		return qr.Dark(x-margin, y-margin)
+	})
+}
+
+// QRCodeSVG meng-encode content menjadi QR code dalam format SVG.
+//
+// Example:
+//
+//	svg, err := dim.QRCodeSVG("https://example.com/pay/INV-001", dim.QROptions{Level: dim.QRLevelH})
+func QRCodeSVG(content string, opts QROptions) ([]byte, error) {
+	qr, err := NewQRCode(content, opts.Level)
+	if err != nil {
+		return nil, err
+	}
+	margin, cols, scale := opts.layout(qr)
+	return encodeModulesSVG(cols, cols, cols*scale, cols*scale, func(x, y int) bool {
+		return qr.Dark(x-margin, y-margin)
+	}), nil
+}
+
+// dataCodewords membentuk bit stream byte mode beserta terminator dan padding.
+func (qr *QRCode) dataCodewords(data []byte) []byte {
+	var bits qrBitBuffer
+	bits.append(0x4, 4) // byte mode
+	if qr.Version < 10 {
+		bits.append(len(data), 8)
+	} else {
+		bits.append(len(data), 16)
+	}
+	for _, b := range data {
+		bits.append(int(b), 8)
+	}
+
+	capacity := qrNumDataCodewords(qr.Version, qr.level) * 8
+	bits.append(0, min(4, capacity-len(bits)))
+	bits.append(0, (8-len(bits)%8)%8)
+	for pad := 0xEC; len(bits) < capacity; pad ^= 0xEC ^ 0x11 {
+		bits.append(pad, 8)
+	}
+
+	codewords := make([]byte, len(bits)/8)
+	for i, bit := range bits {
+		if bit {
+			codewords[i>>3] |= 1 << (7 - i&7)
+		}
+	}
+	return codewords
+}
+
+// addEccAndInterleave membagi data ke blok, menambahkan Reed-Solomon ECC, lalu meng-interleave.
+func (qr *QRCode) addEccAndInterleave(data []byte) []byte {
+	numBlocks := qrNumEccBlocks[qr.level][qr.Version]
+	eccLen := qrEccCodewordsPerBlock[qr.level][qr.Version]
+	rawCodewords := qrNumRawDataModules(qr.Version) / 8
+	numShortBlocks := numBlocks - rawCodewords%numBlocks
+	shortBlockLen := rawCodewords / numBlocks
+
+	divisor := reedSolomonDivisor(eccLen)
+	blocks := make([][]byte, numBlocks)
+	for i, k := 0, 0; i < numBlocks; i++ {
+		n := shortBlockLen - eccLen
+		if i >= numShortBlocks {
+			n++
+		}
+		block := append([]byte{}, data[k:k+n]...)
+		k += n
+		ecc := reedSolomonRemainder(block, divisor)
+		if i < numShortBlocks {
+			block = append(block, 0) // placeholder agar semua blok sama panjang
+		}
+		blocks[i] = append(block, ecc...)
+	}
+
+	result := make([]byte, 0, rawCodewords)
+	for i := range blocks[0] {
+		for j, block := range blocks {
+			if i != shortBlockLen-eccLen || j >= numShortBlocks {
+				result = append(result, block[i])
+			}
+		}
+	}
+	return result
+}
+
+func (qr *QRCode) setFunction(x, y int, dark bool) {
+	qr.modules[y][x] = dark
+	qr.isFunction[y][x] = true
+}
+
+func (qr *QRCode) drawFunctionPatterns() {
+	for i := 0; i < qr.Size; i++ {
+		qr.setFunction(6, i, i%2 == 0)
+		qr.setFunction(i, 6, i%2 == 0)
+	}
+
+	qr.drawFinderPattern(3, 3)
+	qr.drawFinderPattern(qr.Size-4, 3)
+	qr.drawFinderPattern(3, qr.Size-4)
+
+	positions := qrAlignmentPositions(qr.Version)
+	last := len(positions) - 1
+	for i, x := range positions {
+		for j, y := range positions {
+			// Lewati posisi yang bertumpuk dengan finder pattern
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue
+			}
+			qr.drawAlignmentPattern(x, y)
+		}
+	}
+
+	// Reservasi area format sebelum codeword ditulis; nilainya diisi ulang setelah mask dipilih
+	qr.drawFormatBits(0)
+	qr.drawVersionBits()
+}
+
+func (qr *QRCode) drawFinderPattern(cx, cy int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			x, y := cx+dx, cy+dy
+			if x < 0 || y < 0 || x >= qr.Size || y >= qr.Size {
+				continue
+			}
+			dist := max(absInt(dx), absInt(dy))
+			qr.setFunction(x, y, dist != 2 && dist != 4)
+		}
+	}
+}
+
+func (qr *QRCode) drawAlignmentPattern(cx, cy int) {
+	for dy := -2; dy <= 2; dy++ {
+		for dx := -2; dx <= 2; dx++ {
+			qr.setFunction(cx+dx, cy+dy, max(absInt(dx), absInt(dy)) != 1)
+		}
+	}
+}
+
+// drawFormatBits menulis 15 bit format information (level + mask, BCH(15,5)) di kedua salinan.
+func (qr *QRCode) drawFormatBits(mask int) {
+	bits := qrFormatBits(qr.level, mask)
+
+	for i := 0; i <= 5; i++ {
+		qr.setFunction(8, i, qrBit(bits, i))
+	}
+	qr.setFunction(8, 7, qrBit(bits, 6))
+	qr.setFunction(8, 8, qrBit(bits, 7))
+	qr.setFunction(7, 8, qrBit(bits, 8))
+	for i := 9; i < 15; i++ {
+		qr.setFunction(14-i, 8, qrBit(bits, i))
+	}
+
+	for i := 0; i < 8; i++ {
+		qr.setFunction(qr.Size-1-i, 8, qrBit(bits, i))
+	}
+	for i := 8; i < 15; i++ {
+		qr.setFunction(8, qr.Size-15+i, qrBit(bits, i))
+	}
+	qr.setFunction(8, qr.Size-8, true) // dark module
+}
+
+// drawVersionBits menulis 18 bit version information (BCH(18,6)) untuk versi 7 ke atas.
+func (qr *QRCode) drawVersionBits() {
+	if qr.Version < 7 {
+		return
+	}
+	bits := qrVersionBits(qr.Version)
+
+	for i := 0; i < 18; i++ {
+		a, b := qr.Size-11+i%3, i/3
+		qr.setFunction(a, b, qrBit(bits, i))
+		qr.setFunction(b, a, qrBit(bits, i))
+	}
+}
+
+// drawCodewords menempatkan codeword dengan pola zigzag dua kolom dari kanan bawah.
+func (qr *QRCode) drawCodewords(data []byte) {
+	i := 0
+	for right := qr.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5 // lewati timing pattern vertikal
+		}
+		for vert := 0; vert < qr.Size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				y := vert
+				if (right+1)&2 == 0 {
+					y = qr.Size - 1 - vert
+				}
+				if !qr.isFunction[y][x] && i < len(data)*8 {
+					qr.modules[y][x] = qrBit(int(data[i>>3]), 7-i&7)
+					i++
+				}
+			}
+		}
+	}
+}
+
+// applyMask meng-XOR modul data dengan pola mask. Memanggil dua kali mengembalikan keadaan semula.
+func (qr *QRCode) applyMask(mask int) {
+	for y := 0; y < qr.Size; y++ {
+		for x := 0; x < qr.Size; x++ {
+			if qr.isFunction[y][x] {
+				continue
+			}
+			var invert bool
+			switch mask {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if invert {
+				qr.modules[y][x] = !qr.modules[y][x]
+			}
+		}
+	}
+}
+
+// penalty menghitung skor penalty ISO/IEC 18004 (N1-N4) untuk pemilihan mask.
+func (qr *QRCode) penalty() int {
+	n := qr.Size
+	result := 0
+
+	line := func(get func(i int) bool) {
+		run := 1
+		for i := 1; i <= n; i++ {
+			if i < n && get(i) == get(i-1) {
+				run++
+				continue
+			}
+			if run >= 5 {
+				result += 3 + run - 5
+			}
+			run = 1
+		}
+		// Pola menyerupai finder (1:1:3:1:1) dengan 4 modul terang di salah satu sisi
+		for i := 0; i+11 <= n; i++ {
+			if qrMatchesFinderLike(get, i) {
+				result += 40
+			}
+		}
+	}
+
+	dark := 0
+	for y := 0; y < n; y++ {
+		line(func(x int) bool { return qr.modules[y][x] })
+		line(func(x int) bool { return qr.modules[x][y] })
+		for x := 0; x < n; x++ {
+			c := qr.modules[y][x]
+			if c {
+				dark++
+			}
+			if x+1 < n && y+1 < n && c == qr.modules[y][x+1] && c == qr.modules[y+1][x] && c == qr.modules[y+1][x+1] {
+				result += 3
+			}
+		}
+	}
+
+	total := n * n
+	k := (absInt(dark*20-total*10)+total-1)/total - 1
+	result += k * 10
+	return result
+}
+
+var (
+	qrFinderLikeA = [11]bool{true, false, true, true, true, false, true, false, false, false, false}
+	qrFinderLikeB = [11]bool{false, false, false, false, true, false, true, true, true, false, true}
+)
+
+func qrMatchesFinderLike(get func(i int) bool, start int) bool {
+	matchA, matchB := true, true
+	for j := 0; j < 11; j++ {
+		v := get(start + j)
+		matchA = matchA && v == qrFinderLikeA[j]
+		matchB = matchB && v == qrFinderLikeB[j]
+	}
+	return matchA || matchB
+}
+
+// qrFormatBits menghitung 15 bit format information yang sudah di-XOR dengan 0x5412.
+func qrFormatBits(level, mask int) int {
+	data := qrFormatLevelBits[level]<<3 | mask
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+	return (data<<10 | rem) ^ 0x5412
+}
+
+// qrVersionBits menghitung 18 bit version information (6 bit versi + 12 bit BCH).
+func qrVersionBits(version int) int {
+	rem := version
+	for i := 0; i < 12; i++ {
+		rem = (rem << 1) ^ ((rem >> 11) * 0x1F25)
+	}
+	return version<<12 | rem
+}
+
+// qrAlignmentPositions mengembalikan koordinat tengah alignment pattern untuk versi tertentu.
+func qrAlignmentPositions(version int) []int {
+	if version == 1 {
+		return nil
+	}
+	numAlign := version/7 + 2
+	step := (version*8 + numAlign*3 + 5) / (numAlign*4 - 4) * 2
+	positions := make([]int, numAlign)
+	positions[0] = 6
+	for i, pos := numAlign-1, version*4+17-7; i >= 1; i, pos = i-1, pos-step {
+		positions[i] = pos
+	}
+	return positions
+}
+
+// qrNumRawDataModules menghitung jumlah modul yang tersedia untuk data dan ECC.
+func qrNumRawDataModules(version int) int {
+	result := (16*version+128)*version + 64
+	if version >= 2 {
+		numAlign := version/7 + 2
+		result -= (25*numAlign-10)*numAlign - 55
+		if version >= 7 {
+			result -= 36
+		}
+	}
+	return result
+}
+
+func qrNumDataCodewords(version, level int) int {
+	return qrNumRawDataModules(version)/8 - qrEccCodewordsPerBlock[level][version]*qrNumEccBlocks[level][version]
+}
+
+func qrBit(x, i int) bool {
+	return (x>>i)&1 != 0
+}
+
+type qrBitBuffer []bool
+
+func (b *qrBitBuffer) append(value, length int) {
+	for i := length - 1; i >= 0; i-- {
+		*b = append(*b, qrBit(value, i))
+	}
+}
+
+// reedSolomonDivisor membentuk generator polynomial berderajat degree di GF(2^8/0x11D).
+func reedSolomonDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := range result {
+			result[j] = gfMultiply(result[j], root)
+			if j+1 < len(result) {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = gfMultiply(root, 0x02)
+	}
+	return result
+}
+
+// reedSolomonRemainder menghitung codeword ECC untuk data.
+func reedSolomonRemainder(data, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i, d := range divisor {
+			result[i] ^= gfMultiply(d, factor)
+		}
+	}
+	return result
+}
+
+func gfMultiply(x, y byte) byte {
+	z := 0
+	for i := 7; i >= 0; i-- {
+		z = (z << 1) ^ ((z >> 7) * 0x11D)
+		z ^= int((y>>i)&1) * int(x)
+	}
+	return byte(z)
+}
+
+func absInt(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,291 @@
+package dim
+
+import (
+	"bytes"
+	"errors"
+	"image/png"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// decodeQRForTest reads a QRCode matrix back into its content: format bits, unmasking,
+// zigzag placement, de-interleaving, Reed-Solomon check, and byte mode parsing.
+func decodeQRForTest(t *testing.T, qr *QRCode) string {
+	t.Helper()
+
+	format := 0
+	for i := 0; i <= 5; i++ {
+		if qr.Dark(8, i) {
+			format |= 1 << i
+		}
+	}
+	for i, pos := range [][2]int{{8, 7}, {8, 8}, {7, 8}} {
+		if qr.Dark(pos[0], pos[1]) {
+			format |= 1 << (6 + i)
+		}
+	}
+	for i := 9; i < 15; i++ {
+		if qr.Dark(14-i, 8) {
+			format |= 1 << i
+		}
+	}
+
+	data := (format ^ 0x5412) >> 10
+	level := -1
+	for i, bits := range qrFormatLevelBits {
+		if bits == data>>3 {
+			level = i
+		}
+	}
+	mask := data & 7
+	if level < 0 || qrFormatBits(level, mask) != format {
+		t.Fatalf("invalid format bits %015b", format)
+	}
+
+	ref := &QRCode{Version: qr.Version, Size: qr.Size, level: level}
+	ref.modules = make([][]bool, ref.Size)
+	ref.isFunction = make([][]bool, ref.Size)
+	for i := range ref.modules {
+		ref.modules[i] = make([]bool, ref.Size)
+		ref.isFunction[i] = make([]bool, ref.Size)
+	}
+	ref.drawFunctionPatterns()
+	for y := range ref.modules {
+		copy(ref.modules[y], qr.modules[y])
+	}
+	ref.applyMask(mask)
+
+	rawCodewords := qrNumRawDataModules(qr.Version) / 8
+	codewords := make([]byte, rawCodewords)
+	i := 0
+	for right := ref.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < ref.Size; vert++ {
+			for j := 0; j < 2; j++ {
+				x, y := right-j, vert
+				if (right+1)&2 == 0 {
+					y = ref.Size - 1 - vert
+				}
+				if !ref.isFunction[y][x] && i < rawCodewords*8 {
+					if ref.modules[y][x] {
+						codewords[i>>3] |= 1 << (7 - i&7)
+					}
+					i++
+				}
+			}
+		}
+	}
+
+	numBlocks := qrNumEccBlocks[level][qr.Version]
+	eccLen := qrEccCodewordsPerBlock[level][qr.Version]
+	numShortBlocks := numBlocks - rawCodewords%numBlocks
+	shortBlockLen := rawCodewords / numBlocks
+	blocks := make([][]byte, numBlocks)
+	for j := range blocks {
+		blocks[j] = make([]byte, shortBlockLen+1)
+	}
+	k := 0
+	for i := 0; i <= shortBlockLen; i++ {
+		for j := range blocks {
+			if i != shortBlockLen-eccLen || j >= numShortBlocks {
+				blocks[j][i] = codewords[k]
+				k++
+			}
+		}
+	}
+
+	divisor := reedSolomonDivisor(eccLen)
+	var dataCodewords []byte
+	for j, block := range blocks {
+		n := shortBlockLen - eccLen
+		ecc := block[n+1:]
+		if j >= numShortBlocks {
+			n++
+		}
+		if got := reedSolomonRemainder(block[:n], divisor); !bytes.Equal(got, ecc) {
+			t.Fatalf("block %d: ecc mismatch", j)
+		}
+		dataCodewords = append(dataCodewords, block[:n]...)
+	}
+
+	pos := 0
+	read := func(n int) int {
+		v := 0
+		for ; n > 0; n-- {
+			v = v<<1 | int(dataCodewords[pos>>3]>>(7-pos&7)&1)
+			pos++
+		}
+		return v
+	}
+	if mode := read(4); mode != 0x4 {
+		t.Fatalf("mode = %d, want byte mode", mode)
+	}
+	countBits := 8
+	if qr.Version >= 10 {
+		countBits = 16
+	}
+	content := make([]byte, read(countBits))
+	for i := range content {
+		content[i] = byte(read(8))
+	}
+	return string(content)
+}
+
+// TestReedSolomon tests ECC against the ISO/IEC 18004 "HELLO WORLD" 1-M example
+func TestReedSolomon(t *testing.T) {
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	want := []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}
+
+	if got := reedSolomonRemainder(data, reedSolomonDivisor(10)); !bytes.Equal(got, want) {
+		t.Errorf("ecc = %v, want %v", got, want)
+	}
+}
+
+// TestQRTables tests format/version information and layout tables against the specification
+func TestQRTables(t *testing.T) {
+	if got := qrFormatBits(0, 0); got != 0x77C4 {
+		t.Errorf("format L/0 = %#x, want 0x77c4", got)
+	}
+	if got := qrFormatBits(3, 7); got != 0x083B {
+		t.Errorf("format H/7 = %#x, want 0x083b", got)
+	}
+	if got := qrVersionBits(7); got != 0x07C94 {
+		t.Errorf("version 7 bits = %#x, want 0x07c94", got)
+	}
+
+	alignments := map[int][]int{
+		1:  nil,
+		2:  {6, 18},
+		7:  {6, 22, 38},
+		32: {6, 34, 60, 86, 112, 138},
+		40: {6, 30, 58, 86, 114, 142, 170},
+	}
+	for version, want := range alignments {
+		if got := qrAlignmentPositions(version); !reflect.DeepEqual(got, want) {
+			t.Errorf("alignment v%d = %v, want %v", version, got, want)
+		}
+	}
+
+	capacities := []struct {
+		version, level, want int
+	}{
+		{1, 0, 19}, {1, 3, 9}, {5, 2, 62}, {10, 3, 122}, {40, 0, 2956}, {40, 3, 1276},
+	}
+	for _, c := range capacities {
+		if got := qrNumDataCodewords(c.version, c.level); got != c.want {
+			t.Errorf("data codewords v%d level %d = %d, want %d", c.version, c.level, got, c.want)
+		}
+	}
+}
+
+// TestNewQRCode_RoundTrip tests that encoded content can be decoded back for all levels and sizes
+func TestNewQRCode_RoundTrip(t *testing.T) {
+	contents := []string{
+		"a",
+		"https://example.com",
+		"otpauth://totp/dim:user@example.com?secret=JBSWY3DPEHPK3PXP&issuer=dim",
+		strings.Repeat("Invoice INV-2024-000123 ", 12),
+		strings.Repeat("0123456789abcdef", 60),
+		"Pembayaran Rp 150.000 — terima kasih",
+	}
+
+	for _, level := range []QRLevel{QRLevelL, QRLevelM, QRLevelQ, QRLevelH} {
+		for _, content := range contents {
+			qr, err := NewQRCode(content, level)
+			if err != nil {
+				t.Fatalf("level %s, len %d: %v", level, len(content), err)
+			}
+			if qr.Size != qr.Version*4+17 || qr.Level != level {
+				t.Errorf("unexpected size %d for version %d", qr.Size, qr.Version)
+			}
+			if got := decodeQRForTest(t, qr); got != content {
+				t.Errorf("level %s, version %d: decoded %q, want %q", level, qr.Version, got, content)
+			}
+		}
+	}
+}
+
+// TestNewQRCode_VersionSelection tests that the smallest fitting version is chosen
+func TestNewQRCode_VersionSelection(t *testing.T) {
+	tests := []struct {
+		length  int
+		level   QRLevel
+		version int
+	}{
+		{17, QRLevelL, 1},
+		{18, QRLevelL, 2},
+		{100, QRLevelM, 6},
+		{2953, QRLevelL, 40},
+	}
+	for _, tt := range tests {
+		qr, err := NewQRCode(strings.Repeat("x", tt.length), tt.level)
+		if err != nil {
+			t.Fatalf("length %d: %v", tt.length, err)
+		}
+		if qr.Version != tt.version {
+			t.Errorf("length %d level %s: version = %d, want %d", tt.length, tt.level, qr.Version, tt.version)
+		}
+	}
+
+	qr, _ := NewQRCode("x", "")
+	if qr.Level != QRLevelM {
+		t.Errorf("default level = %q, want M", qr.Level)
+	}
+}
+
+// TestNewQRCode_Errors tests capacity and level validation
+func TestNewQRCode_Errors(t *testing.T) {
+	if _, err := NewQRCode(strings.Repeat("x", 1274), QRLevelH); !errors.Is(err, ErrQRTooLong) {
+		t.Errorf("expected ErrQRTooLong, got %v", err)
+	}
+	if _, err := NewQRCode("x", "X"); err == nil {
+		t.Error("expected error for invalid level")
+	}
+}
+
+// TestQRCodePNG tests image size, quiet zone, and finder pattern pixels
+func TestQRCodePNG(t *testing.T) {
+	data, err := QRCodePNG("https://example.com", QROptions{Size: 256})
+	if err != nil {
+		t.Fatalf("QRCodePNG error: %v", err)
+	}
+
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("invalid png: %v", err)
+	}
+
+	// Version 2 (25 modules) + 2*4 quiet zone = 33 modules, 8px each
+	if b := img.Bounds(); b.Dx() != 264 || b.Dy() != 264 {
+		t.Fatalf("size = %v, want 264x264", b)
+	}
+	isDark := func(x, y int) bool {
+		r, _, _, _ := img.At(x, y).RGBA()
+		return r == 0
+	}
+	if isDark(0, 0) {
+		t.Error("expected light quiet zone")
+	}
+	if !isDark(4*8, 4*8) || isDark(5*8, 5*8) || !isDark(6*8, 6*8) {
+		t.Error("expected finder pattern in the top-left corner")
+	}
+}
+
+// TestQRCodeSVG tests SVG output
+func TestQRCodeSVG(t *testing.T) {
+	svg, err := QRCodeSVG("hello", QROptions{Size: 100, Margin: 2})
+	if err != nil {
+		t.Fatalf("QRCodeSVG error: %v", err)
+	}
+
+	s := string(svg)
+	if !strings.HasPrefix(s, "<svg") || !strings.Contains(s, `viewBox="0 0 25 25"`) || !strings.Contains(s, `width="100"`) {
+		t.Errorf("unexpected svg %q", s[:min(len(s), 200)])
+	}
+	if !strings.Contains(s, "M2 2h7v1h-7z") {
+		t.Error("expected merged run for the finder pattern top edge")
+	}
+}
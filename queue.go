@@ -0,0 +1,545 @@
+package dim
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrJobNotFound dikembalikan QueueDriver.Requeue jika job tidak ada di dead-letter.
+	ErrJobNotFound = errors.New("job not found")
+	// ErrJobHandlerNotFound dikembalikan untuk job yang tipenya tidak memiliki handler di Queue.
+	// Job tersebut tetap di-retry, sehingga instance lain (misal versi baru saat rolling deploy)
+	// masih dapat memprosesnya.
+	ErrJobHandlerNotFound = errors.New("no handler registered for job type")
+	// ErrJobPermanent menandai kegagalan yang tidak akan berhasil jika diulang (misal payload
+	// tidak valid). Bungkus error handler dengan PermanentJobError.
+	ErrJobPermanent = errors.New("permanent job failure")
+	// ErrQueueClosed dikembalikan Queue.Enqueue setelah Shutdown.
+	ErrQueueClosed = errors.New("queue is closed")
+)
+
+// PermanentJobError menandai err sebagai kegagalan permanen sehingga job langsung dipindahkan ke
+// dead-letter tanpa retry. errors.Is(err, ErrJobPermanent) bernilai true dan error asli tetap dapat
+// diperiksa. Error yang cocok dengan ErrMailPermanent juga tidak di-retry.
+func PermanentJobError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentJobError{err: err}
+}
+
+type permanentJobError struct {
+	err error
+}
+
+func (e *permanentJobError) Error() string        { return e.err.Error() }
+func (e *permanentJobError) Unwrap() error        { return e.err }
+func (e *permanentJobError) Is(target error) bool { return target == ErrJobPermanent }
+
+// Job adalah satu unit pekerjaan di Queue. Payload disimpan sebagai JSON sehingga job dapat
+// diproses oleh proses lain.
+type Job struct {
+	ID    string `json:"id"`
+	Queue string `json:"queue"`
+	Type  string `json:"type"`
+	// Payload adalah data job dalam JSON; gunakan JobType untuk encode/decode bertipe.
+	Payload json.RawMessage `json:"payload"`
+	// Attempts adalah jumlah percobaan yang sudah dimulai, termasuk yang sedang berjalan.
+	Attempts    int `json:"attempts"`
+	MaxAttempts int `json:"max_attempts"`
+	// RunAt adalah waktu paling awal job boleh dijalankan.
+	RunAt     time.Time `json:"run_at"`
+	CreatedAt time.Time `json:"created_at"`
+	// LastError adalah pesan error dari percobaan terakhir yang gagal.
+	LastError string `json:"last_error,omitempty"`
+	// FailedAt diisi saat job dipindahkan ke dead-letter.
+	FailedAt time.Time `json:"failed_at,omitzero"`
+}
+
+// QueueDriver menyimpan job untuk Queue. Driver bawaan: MemoryQueueDriver (satu proses, untuk
+// development dan test), DatabaseQueueDriver (PostgreSQL, MySQL, SQLite), dan RedisQueueDriver.
+type QueueDriver interface {
+	// Push menyimpan job baru.
+	Push(ctx context.Context, job *Job) error
+	// Reserve mengambil satu job dari queue yang RunAt-nya sudah lewat, menaikkan Attempts, dan
+	// menguncinya selama lease. Job yang lease-nya habis (misal worker crash) dapat diambil lagi.
+	// Mengembalikan nil, nil jika tidak ada job yang siap.
+	Reserve(ctx context.Context, queue string, lease time.Duration) (*Job, error)
+	// Complete menghapus job yang berhasil diproses.
+	Complete(ctx context.Context, job *Job) error
+	// Retry mengembalikan job ke antrian untuk dijalankan pada runAt, menyimpan Attempts dan LastError.
+	Retry(ctx context.Context, job *Job, runAt time.Time) error
+	// Bury memindahkan job ke dead-letter, menyimpan Attempts, LastError, dan FailedAt.
+	Bury(ctx context.Context, job *Job) error
+	// Dead mengembalikan job di dead-letter queue, yang terakhir gagal lebih dulu.
+	Dead(ctx context.Context, queue string, limit int) ([]*Job, error)
+	// Requeue memindahkan job dari dead-letter kembali ke antrian dengan Attempts 0.
+	// Mengembalikan ErrJobNotFound jika job tidak ada di dead-letter.
+	Requeue(ctx context.Context, queue, id string) error
+	// Len mengembalikan jumlah job yang menunggu di queue, termasuk yang tertunda.
+	Len(ctx context.Context, queue string) (int, error)
+}
+
+// JobHandler memproses satu job. Error menyebabkan job di-retry dengan backoff sampai
+// MaxAttempts habis, lalu dipindahkan ke dead-letter.
+type JobHandler func(ctx context.Context, job *Job) error
+
+// QueueOptions mengatur worker dan retry Queue. Nilai 0 memakai default.
+type QueueOptions struct {
+	// Name adalah nama queue di driver (default: "default"). Beberapa Queue dengan nama berbeda
+	// dapat berbagi satu driver, misal queue "mail" dengan worker terpisah.
+	Name string
+	// Workers adalah jumlah goroutine pemroses (default: 4).
+	Workers int
+	// MaxAttempts adalah jumlah percobaan default per job termasuk yang pertama (default: 5).
+	MaxAttempts int
+	// Backoff menentukan jeda sebelum percobaan berikutnya
+	// (default: ExponentialBackoff(time.Second, 10*time.Minute)).
+	Backoff func(attempt int) time.Duration
+	// Timeout adalah batas waktu setiap percobaan (default: 5 menit).
+	Timeout time.Duration
+	// PollInterval adalah jeda worker memeriksa driver saat antrian kosong (default: 1 detik).
+	// Job yang di-enqueue dari proses yang sama langsung membangunkan worker.
+	PollInterval time.Duration
+}
+
+// JobOption mengubah job sebelum di-enqueue.
+type JobOption func(job *Job)
+
+// WithJobDelay menunda job selama d.
+func WithJobDelay(d time.Duration) JobOption {
+	return func(job *Job) { job.RunAt = job.RunAt.Add(d) }
+}
+
+// WithJobRunAt menjadwalkan job pada waktu t.
+func WithJobRunAt(t time.Time) JobOption {
+	return func(job *Job) { job.RunAt = t.UTC() }
+}
+
+// WithJobMaxAttempts mengganti MaxAttempts default untuk job ini.
+func WithJobMaxAttempts(n int) JobOption {
+	return func(job *Job) {
+		if n > 0 {
+			job.MaxAttempts = n
+		}
+	}
+}
+
+const jobContextKey contextKey = "queue_job"
+
+// JobFromContext mengembalikan job yang sedang diproses, misal untuk membaca Attempts di handler
+// JobType yang hanya menerima payload.
+func JobFromContext(ctx context.Context) (*Job, bool) {
+	job, ok := ctx.Value(jobContextKey).(*Job)
+	return job, ok
+}
+
+// Queue menjalankan job di background dengan worker pool, retry dengan backoff, dan dead-letter,
+// sehingga pekerjaan lambat (kirim email, post-processing upload, webhook keluar) tidak menahan
+// request handler. Penyimpanan job ditentukan QueueDriver.
+type Queue struct {
+	driver  QueueDriver
+	opts    QueueOptions
+	logger  *Logger
+	metrics *QueueMetrics
+
+	mu       sync.RWMutex
+	handlers map[string]JobHandler
+	onDead   []func(ctx context.Context, job *Job, err error)
+	started  bool
+	closed   bool
+
+	wake        chan struct{}
+	closing     context.Context // dibatalkan saat Shutdown: worker berhenti mengambil job baru
+	stopWorkers context.CancelFunc
+	stop        context.Context // dibatalkan saat Shutdown melewati batas waktunya: job berjalan dibatalkan
+	cancel      context.CancelFunc
+	wg          sync.WaitGroup
+}
+
+// NewQueue membuat Queue di atas driver. Daftarkan handler lalu panggil Start untuk menjalankan
+// worker; Enqueue dapat dipakai tanpa Start, misal di proses API yang worker-nya berjalan terpisah.
+//
+// Parameters:
+//   - driver: penyimpanan job
+//   - opts: nama queue, jumlah worker, dan kebijakan retry
+//
+// Returns:
+//   - *Queue: queue yang siap menerima handler dan job
+//
+// Example:
+//
+//	queue := dim.NewQueue(dim.NewDatabaseQueueDriver(db), dim.QueueOptions{Workers: 8}).
+//	    WithLogger(logger).
+//	    WithMetrics(dim.NewQueueMetrics(metrics))
+//	sendWelcome.Handle(queue, handleSendWelcome)
+//	queue.Start()
+//	server.OnShutdown("queue", queue.Shutdown)
+func NewQueue(driver QueueDriver, opts QueueOptions) *Queue {
+	if opts.Name == "" {
+		opts.Name = "default"
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = 4
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 5
+	}
+	if opts.Backoff == nil {
+		opts.Backoff = ExponentialBackoff(time.Second, 10*time.Minute)
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 5 * time.Minute
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = time.Second
+	}
+
+	q := &Queue{
+		driver:   driver,
+		opts:     opts,
+		handlers: make(map[string]JobHandler),
+		wake:     make(chan struct{}, 1),
+	}
+	q.closing, q.stopWorkers = context.WithCancel(context.Background())
+	q.stop, q.cancel = context.WithCancel(context.Background())
+	return q
+}
+
+// WithLogger mencatat job yang gagal dan di-retry (Warn), job yang masuk dead-letter (Error),
+// serta error driver.
+func (q *Queue) WithLogger(logger *Logger) *Queue {
+	q.logger = logger
+	return q
+}
+
+// WithMetrics mencatat durasi dan hasil setiap job, retry, dan kedalaman queue ke QueueMetrics.
+func (q *Queue) WithMetrics(metrics *QueueMetrics) *Queue {
+	q.metrics = metrics
+	return q
+}
+
+// Name mengembalikan nama queue di driver.
+func (q *Queue) Name() string {
+	return q.opts.Name
+}
+
+// Handle mendaftarkan handler untuk tipe job. Untuk payload bertipe, gunakan JobType.Handle.
+//
+// Example:
+//
+//	queue.Handle("cleanup_upload", func(ctx context.Context, job *dim.Job) error {
+//	    var path string
+//	    if err := json.Unmarshal(job.Payload, &path); err != nil {
+//	        return dim.PermanentJobError(err)
+//	    }
+//	    return disk.Delete(ctx, path)
+//	})
+func (q *Queue) Handle(jobType string, handler JobHandler) *Queue {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.handlers[jobType] = handler
+	return q
+}
+
+// OnDeadLetter mendaftarkan hook yang dipanggil saat job dipindahkan ke dead-letter, misal untuk
+// alert atau menandai data terkait sebagai gagal. Hook dipanggil di goroutine worker.
+func (q *Queue) OnDeadLetter(fn func(ctx context.Context, job *Job, err error)) *Queue {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.onDead = append(q.onDead, fn)
+	return q
+}
+
+// Enqueue menyimpan job baru dengan payload yang di-encode sebagai JSON. Dengan
+// DatabaseQueueDriver, Enqueue di dalam InTx ikut transaksi tersebut, sehingga job hanya tersimpan
+// jika transaksi di-commit.
+//
+// Parameters:
+//   - ctx: context request
+//   - jobType: nama tipe job, sesuai handler yang didaftarkan
+//   - payload: data job, harus dapat di-encode ke JSON
+//   - opts: WithJobDelay, WithJobRunAt, WithJobMaxAttempts
+//
+// Returns:
+//   - *Job: job yang tersimpan
+//   - error: error encode payload, driver, atau ErrQueueClosed setelah Shutdown
+//
+// Example:
+//
+//	_, err := queue.Enqueue(r.Context(), "cleanup_upload", upload.Path, dim.WithJobDelay(time.Hour))
+func (q *Queue) Enqueue(ctx context.Context, jobType string, payload any, opts ...JobOption) (*Job, error) {
+	if q.closing.Err() != nil {
+		return nil, ErrQueueClosed
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode job payload: %w", err)
+	}
+
+	now := time.Now().UTC()
+	job := &Job{
+		ID:          NewUuid().String(),
+		Queue:       q.opts.Name,
+		Type:        jobType,
+		Payload:     data,
+		MaxAttempts: q.opts.MaxAttempts,
+		RunAt:       now,
+		CreatedAt:   now,
+	}
+	for _, opt := range opts {
+		opt(job)
+	}
+	if err := q.driver.Push(ctx, job); err != nil {
+		return nil, fmt.Errorf("failed to enqueue job: %w", err)
+	}
+
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+	return job, nil
+}
+
+// Len mengembalikan jumlah job yang menunggu di queue.
+func (q *Queue) Len(ctx context.Context) (int, error) {
+	return q.driver.Len(ctx, q.opts.Name)
+}
+
+// DeadLetters mengembalikan maksimal limit job di dead-letter, yang terakhir gagal lebih dulu.
+func (q *Queue) DeadLetters(ctx context.Context, limit int) ([]*Job, error) {
+	return q.driver.Dead(ctx, q.opts.Name, limit)
+}
+
+// Requeue memindahkan job dari dead-letter kembali ke antrian, misal setelah bug di handler
+// diperbaiki.
+func (q *Queue) Requeue(ctx context.Context, id string) error {
+	if err := q.driver.Requeue(ctx, q.opts.Name, id); err != nil {
+		return err
+	}
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Start menjalankan worker. Pemanggilan berikutnya tidak berpengaruh.
+func (q *Queue) Start() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.started || q.closed {
+		return
+	}
+	q.started = true
+	for range q.opts.Workers {
+		q.wg.Add(1)
+		go q.work()
+	}
+}
+
+// Shutdown berhenti menerima dan mengambil job baru lalu menunggu job yang sedang berjalan selesai.
+// Jika ctx selesai lebih dulu, job yang berjalan dibatalkan dan dikembalikan ke antrian tanpa
+// menghitung percobaan tersebut. Signature-nya sesuai ShutdownFunc untuk Server.OnShutdown.
+func (q *Queue) Shutdown(ctx context.Context) error {
+	q.mu.Lock()
+	q.closed = true
+	q.mu.Unlock()
+	q.stopWorkers()
+
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		q.cancel()
+		return ctx.Err()
+	}
+}
+
+func (q *Queue) work() {
+	defer q.wg.Done()
+	for q.closing.Err() == nil {
+		job, err := q.driver.Reserve(q.closing, q.opts.Name, q.opts.Timeout+30*time.Second)
+		if err != nil && q.closing.Err() == nil && q.logger != nil {
+			q.logger.Error("Failed to reserve job", "queue", q.opts.Name, "error", err.Error())
+		}
+		if job != nil {
+			q.process(job)
+			continue
+		}
+		if q.metrics != nil {
+			if n, err := q.driver.Len(q.closing, q.opts.Name); err == nil {
+				q.metrics.SetDepth(q.opts.Name, n)
+			}
+		}
+
+		timer := time.NewTimer(q.opts.PollInterval)
+		select {
+		case <-timer.C:
+		case <-q.wake:
+			timer.Stop()
+		case <-q.closing.Done():
+			timer.Stop()
+		}
+	}
+}
+
+// process menjalankan satu job lalu menandainya selesai, retry, atau dead-letter.
+func (q *Queue) process(job *Job) {
+	ctx, cancel := context.WithTimeout(context.WithValue(q.stop, jobContextKey, job), q.opts.Timeout)
+	start := time.Now()
+	err := q.run(ctx, job)
+	duration := time.Since(start)
+	cancel()
+
+	if q.metrics != nil {
+		q.metrics.ObserveJob(q.opts.Name, job.Type, duration, err)
+	}
+
+	// Operasi driver tetap dijalankan walaupun Shutdown sudah membatalkan job.
+	storeCtx, storeCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer storeCancel()
+
+	var storeErr error
+	switch {
+	case err == nil:
+		storeErr = q.driver.Complete(storeCtx, job)
+	case q.stop.Err() != nil:
+		job.Attempts--
+		job.LastError = err.Error()
+		storeErr = q.driver.Retry(storeCtx, job, time.Now().UTC())
+	case errors.Is(err, ErrJobPermanent) || errors.Is(err, ErrMailPermanent) || job.Attempts >= job.MaxAttempts:
+		job.LastError = err.Error()
+		job.FailedAt = time.Now().UTC()
+		storeErr = q.driver.Bury(storeCtx, job)
+		if q.logger != nil {
+			q.logger.Error("Job moved to dead-letter", "queue", q.opts.Name, "job", job.Type, "id", job.ID, "attempts", job.Attempts, "error", err.Error())
+		}
+		q.mu.RLock()
+		hooks := q.onDead
+		q.mu.RUnlock()
+		for _, hook := range hooks {
+			hook(storeCtx, job, err)
+		}
+	default:
+		job.LastError = err.Error()
+		storeErr = q.driver.Retry(storeCtx, job, time.Now().UTC().Add(q.opts.Backoff(job.Attempts)))
+		if q.metrics != nil {
+			q.metrics.IncRetry(q.opts.Name, job.Type)
+		}
+		if q.logger != nil {
+			q.logger.Warn("Job failed, retrying", "queue", q.opts.Name, "job", job.Type, "id", job.ID, "attempt", job.Attempts, "error", err.Error())
+		}
+	}
+	if storeErr != nil && q.logger != nil {
+		q.logger.Error("Failed to update job", "queue", q.opts.Name, "job", job.Type, "id", job.ID, "error", storeErr.Error())
+	}
+}
+
+// run memanggil handler job dan mengubah panic menjadi error.
+func (q *Queue) run(ctx context.Context, job *Job) (err error) {
+	q.mu.RLock()
+	handler, ok := q.handlers[job.Type]
+	q.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrJobHandlerNotFound, job.Type)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("job panicked: %v", p)
+		}
+	}()
+	return handler(ctx, job)
+}
+
+// JobType menghubungkan nama tipe job dengan tipe payload-nya, sehingga Enqueue dan handler
+// memakai tipe yang sama saat compile time.
+//
+// Example:
+//
+//	type WelcomePayload struct {
+//	    UserID string `json:"user_id"`
+//	}
+//
+//	var SendWelcome = dim.NewJobType[WelcomePayload]("send_welcome")
+//
+//	SendWelcome.Handle(queue, func(ctx context.Context, p WelcomePayload) error {
+//	    return sendWelcomeEmail(ctx, p.UserID)
+//	})
+//	SendWelcome.Enqueue(r.Context(), queue, WelcomePayload{UserID: user.ID})
+type JobType[T any] struct {
+	name string
+}
+
+// NewJobType membuat JobType dengan nama name.
+func NewJobType[T any](name string) JobType[T] {
+	return JobType[T]{name: name}
+}
+
+// Name mengembalikan nama tipe job.
+func (t JobType[T]) Name() string {
+	return t.name
+}
+
+// Handle mendaftarkan handler bertipe ke queue. Payload yang gagal di-decode dipindahkan langsung
+// ke dead-letter.
+func (t JobType[T]) Handle(q *Queue, fn func(ctx context.Context, payload T) error) {
+	q.Handle(t.name, func(ctx context.Context, job *Job) error {
+		var payload T
+		if err := json.Unmarshal(job.Payload, &payload); err != nil {
+			return PermanentJobError(fmt.Errorf("failed to decode %s payload: %w", t.name, err))
+		}
+		return fn(ctx, payload)
+	})
+}
+
+// Enqueue menyimpan job dengan payload bertipe ke queue.
+func (t JobType[T]) Enqueue(ctx context.Context, q *Queue, payload T, opts ...JobOption) (*Job, error) {
+	return q.Enqueue(ctx, t.name, payload, opts...)
+}
+
+// SendMailJob adalah tipe job yang dipakai QueueMailer.
+var SendMailJob = NewJobType[*MailMessage]("dim.send_mail")
+
+// QueueMailer adalah Mailer yang menyimpan email sebagai job SendMailJob, sehingga email tetap
+// terkirim walaupun proses restart (berbeda dengan MailQueue yang hanya di memory). Kegagalan
+// permanen transport (ErrMailPermanent) langsung masuk dead-letter.
+type QueueMailer struct {
+	queue *Queue
+}
+
+// NewQueueMailer mendaftarkan handler SendMailJob yang mengirim lewat transport dan mengembalikan
+// Mailer yang meng-enqueue email ke queue.
+//
+// Example:
+//
+//	transport, _ := dim.NewMailerFromConfig(&cfg.Email, nil)
+//	mailer := dim.NewQueueMailer(queue, transport)
+//	authService.WithRegistration(userStore, dim.RegistrationOptions{Mailer: mailer, URL: verifyURL})
+func NewQueueMailer(q *Queue, transport Mailer) *QueueMailer {
+	SendMailJob.Handle(q, func(ctx context.Context, msg *MailMessage) error {
+		return transport.Send(ctx, msg)
+	})
+	return &QueueMailer{queue: q}
+}
+
+// Send meng-enqueue msg tanpa menunggu pengiriman.
+func (m *QueueMailer) Send(ctx context.Context, msg *MailMessage) error {
+	if msg == nil {
+		return fmt.Errorf("queue mailer: nil message")
+	}
+	_, err := SendMailJob.Enqueue(ctx, m.queue, msg)
+	return err
+}
@@ -0,0 +1,178 @@
+package dim
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DatabaseQueueDriver menyimpan job di tabel queue_jobs (lihat GetQueueMigrations). Mendukung
+// PostgreSQL, MySQL, dan SQLite; di PostgreSQL dan MySQL worker memakai FOR UPDATE SKIP LOCKED
+// sehingga banyak instance dapat mengambil job bersamaan tanpa saling menunggu.
+type DatabaseQueueDriver struct {
+	db Database
+}
+
+// NewDatabaseQueueDriver membuat DatabaseQueueDriver di atas db.
+//
+// Example:
+//
+//	dim.RunMigrations(db, append(dim.GetFrameworkMigrations(), dim.GetQueueMigrations()...))
+//	queue := dim.NewQueue(dim.NewDatabaseQueueDriver(db), dim.QueueOptions{})
+func NewDatabaseQueueDriver(db Database) *DatabaseQueueDriver {
+	return &DatabaseQueueDriver{db: db}
+}
+
+const queueJobColumns = `id, queue, job_type, payload, attempts, max_attempts, run_at, created_at, last_error, failed_at`
+
+func (d *DatabaseQueueDriver) Push(ctx context.Context, job *Job) error {
+	query := `INSERT INTO queue_jobs (id, queue, job_type, payload, status, attempts, max_attempts, run_at, created_at)
+		VALUES ($1, $2, $3, $4, 'pending', $5, $6, $7, $8)`
+	err := d.db.Exec(ctx, d.db.Rebind(query), job.ID, job.Queue, job.Type, string(job.Payload),
+		job.Attempts, job.MaxAttempts, job.RunAt.UTC(), job.CreatedAt.UTC())
+	if err != nil {
+		return fmt.Errorf("failed to push job: %w", err)
+	}
+	return nil
+}
+
+// Reserve mengunci job dengan lease_token acak: UPDATE hanya berhasil jika job masih siap, lalu
+// job dibaca kembali berdasarkan token. Di SQLite yang tidak mendukung SKIP LOCKED, worker yang
+// kalah cepat tidak menemukan token-nya dan mencoba job berikutnya pada poll selanjutnya.
+func (d *DatabaseQueueDriver) Reserve(ctx context.Context, queue string, lease time.Duration) (*Job, error) {
+	token, err := GenerateSecureToken(16)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	lock := " FOR UPDATE SKIP LOCKED"
+	if d.db.DriverName() == "sqlite" {
+		lock = ""
+	}
+
+	var job *Job
+	err = InTx(ctx, d.db, func(ctx context.Context) error {
+		var id string
+		query := `SELECT id FROM queue_jobs
+			WHERE queue = $1 AND ((status = 'pending' AND run_at <= $2) OR (status = 'active' AND locked_until <= $3))
+			ORDER BY run_at, id LIMIT 1` + lock
+		if err := d.db.QueryRow(ctx, d.db.Rebind(query), queue, now, now).Scan(&id); err != nil {
+			if isNoRows(err) {
+				return nil
+			}
+			return err
+		}
+
+		update := `UPDATE queue_jobs SET status = 'active', attempts = attempts + 1, lease_token = $1, locked_until = $2
+			WHERE id = $3 AND ((status = 'pending' AND run_at <= $4) OR (status = 'active' AND locked_until <= $5))`
+		if err := d.db.Exec(ctx, d.db.Rebind(update), token, now.Add(lease), id, now, now); err != nil {
+			return err
+		}
+
+		reserved, err := d.scan(d.db.QueryRow(ctx, d.db.Rebind(`SELECT `+queueJobColumns+` FROM queue_jobs WHERE id = $1 AND lease_token = $2`), id, token))
+		if err != nil && !isNoRows(err) {
+			return err
+		}
+		job = reserved
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to reserve job: %w", err)
+	}
+	return job, nil
+}
+
+func (d *DatabaseQueueDriver) Complete(ctx context.Context, job *Job) error {
+	if err := d.db.Exec(ctx, d.db.Rebind(`DELETE FROM queue_jobs WHERE id = $1`), job.ID); err != nil {
+		return fmt.Errorf("failed to complete job: %w", err)
+	}
+	return nil
+}
+
+func (d *DatabaseQueueDriver) Retry(ctx context.Context, job *Job, runAt time.Time) error {
+	query := `UPDATE queue_jobs SET status = 'pending', attempts = $1, last_error = $2, run_at = $3, lease_token = NULL, locked_until = NULL
+		WHERE id = $4`
+	if err := d.db.Exec(ctx, d.db.Rebind(query), job.Attempts, job.LastError, runAt.UTC(), job.ID); err != nil {
+		return fmt.Errorf("failed to retry job: %w", err)
+	}
+	return nil
+}
+
+func (d *DatabaseQueueDriver) Bury(ctx context.Context, job *Job) error {
+	query := `UPDATE queue_jobs SET status = 'dead', attempts = $1, last_error = $2, failed_at = $3, lease_token = NULL, locked_until = NULL
+		WHERE id = $4`
+	if err := d.db.Exec(ctx, d.db.Rebind(query), job.Attempts, job.LastError, job.FailedAt.UTC(), job.ID); err != nil {
+		return fmt.Errorf("failed to bury job: %w", err)
+	}
+	return nil
+}
+
+func (d *DatabaseQueueDriver) Dead(ctx context.Context, queue string, limit int) ([]*Job, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	query := `SELECT ` + queueJobColumns + ` FROM queue_jobs WHERE queue = $1 AND status = 'dead'
+		ORDER BY failed_at DESC, id DESC LIMIT $2`
+	rows, err := d.db.Query(ctx, d.db.Rebind(query), queue, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dead jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []*Job
+	for rows.Next() {
+		job, err := d.scan(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan dead job: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, rows.Err()
+}
+
+func (d *DatabaseQueueDriver) Requeue(ctx context.Context, queue, id string) error {
+	var status string
+	err := d.db.QueryRow(ctx, d.db.Rebind(`SELECT status FROM queue_jobs WHERE id = $1 AND queue = $2`), id, queue).Scan(&status)
+	if isNoRows(err) || (err == nil && status != "dead") {
+		return ErrJobNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to find dead job: %w", err)
+	}
+
+	query := `UPDATE queue_jobs SET status = 'pending', attempts = 0, failed_at = NULL, run_at = $1
+		WHERE id = $2 AND status = 'dead'`
+	if err := d.db.Exec(ctx, d.db.Rebind(query), time.Now().UTC(), id); err != nil {
+		return fmt.Errorf("failed to requeue job: %w", err)
+	}
+	return nil
+}
+
+func (d *DatabaseQueueDriver) Len(ctx context.Context, queue string) (int, error) {
+	var n int
+	err := d.db.QueryRow(ctx, d.db.Rebind(`SELECT COUNT(*) FROM queue_jobs WHERE queue = $1 AND status = 'pending'`), queue).Scan(&n)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count jobs: %w", err)
+	}
+	return n, nil
+}
+
+func (d *DatabaseQueueDriver) scan(row Row) (*Job, error) {
+	job := &Job{}
+	var payload string
+	var lastError *string
+	var failedAt *time.Time
+	err := row.Scan(&job.ID, &job.Queue, &job.Type, &payload, &job.Attempts, &job.MaxAttempts,
+		&job.RunAt, &job.CreatedAt, &lastError, &failedAt)
+	if err != nil {
+		return nil, err
+	}
+	job.Payload = []byte(payload)
+	if lastError != nil {
+		job.LastError = *lastError
+	}
+	if failedAt != nil {
+		job.FailedAt = *failedAt
+	}
+	return job, nil
+}
@@ -0,0 +1,146 @@
+package dim
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// MemoryQueueDriver menyimpan job di memory proses. Job hilang saat proses berhenti, jadi gunakan
+// untuk development, test, atau pekerjaan yang boleh hilang; gunakan DatabaseQueueDriver atau
+// RedisQueueDriver untuk production dan multi-instance.
+type MemoryQueueDriver struct {
+	mu   sync.Mutex
+	jobs map[string]*memoryQueueEntry
+}
+
+type memoryQueueEntry struct {
+	job         Job
+	status      string // "pending", "active", atau "dead"
+	lockedUntil time.Time
+}
+
+// NewMemoryQueueDriver membuat MemoryQueueDriver kosong.
+//
+// Example:
+//
+//	queue := dim.NewQueue(dim.NewMemoryQueueDriver(), dim.QueueOptions{})
+func NewMemoryQueueDriver() *MemoryQueueDriver {
+	return &MemoryQueueDriver{jobs: make(map[string]*memoryQueueEntry)}
+}
+
+func (d *MemoryQueueDriver) Push(ctx context.Context, job *Job) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.jobs[job.ID] = &memoryQueueEntry{job: *job, status: "pending"}
+	return nil
+}
+
+func (d *MemoryQueueDriver) Reserve(ctx context.Context, queue string, lease time.Duration) (*Job, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := time.Now()
+	var next *memoryQueueEntry
+	for _, e := range d.jobs {
+		ready := (e.status == "pending" && !e.job.RunAt.After(now)) || (e.status == "active" && !e.lockedUntil.After(now))
+		if e.job.Queue != queue || !ready {
+			continue
+		}
+		if next == nil || e.job.RunAt.Before(next.job.RunAt) || (e.job.RunAt.Equal(next.job.RunAt) && e.job.ID < next.job.ID) {
+			next = e
+		}
+	}
+	if next == nil {
+		return nil, nil
+	}
+
+	next.status = "active"
+	next.lockedUntil = now.Add(lease)
+	next.job.Attempts++
+	job := next.job
+	return &job, nil
+}
+
+func (d *MemoryQueueDriver) Complete(ctx context.Context, job *Job) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.jobs, job.ID)
+	return nil
+}
+
+func (d *MemoryQueueDriver) Retry(ctx context.Context, job *Job, runAt time.Time) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	e, ok := d.jobs[job.ID]
+	if !ok {
+		return ErrJobNotFound
+	}
+	e.job.Attempts = job.Attempts
+	e.job.LastError = job.LastError
+	e.job.RunAt = runAt
+	e.status = "pending"
+	return nil
+}
+
+func (d *MemoryQueueDriver) Bury(ctx context.Context, job *Job) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	e, ok := d.jobs[job.ID]
+	if !ok {
+		return ErrJobNotFound
+	}
+	e.job.Attempts = job.Attempts
+	e.job.LastError = job.LastError
+	e.job.FailedAt = job.FailedAt
+	e.status = "dead"
+	return nil
+}
+
+func (d *MemoryQueueDriver) Dead(ctx context.Context, queue string, limit int) ([]*Job, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var jobs []*Job
+	for _, e := range d.jobs {
+		if e.job.Queue == queue && e.status == "dead" {
+			job := e.job
+			jobs = append(jobs, &job)
+		}
+	}
+	slices.SortFunc(jobs, func(a, b *Job) int {
+		return cmp.Or(b.FailedAt.Compare(a.FailedAt), cmp.Compare(b.ID, a.ID))
+	})
+	if limit > 0 && len(jobs) > limit {
+		jobs = jobs[:limit]
+	}
+	return jobs, nil
+}
+
+func (d *MemoryQueueDriver) Requeue(ctx context.Context, queue, id string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	e, ok := d.jobs[id]
+	if !ok || e.job.Queue != queue || e.status != "dead" {
+		return ErrJobNotFound
+	}
+	e.status = "pending"
+	e.job.Attempts = 0
+	e.job.FailedAt = time.Time{}
+	e.job.RunAt = time.Now().UTC()
+	return nil
+}
+
+func (d *MemoryQueueDriver) Len(ctx context.Context, queue string) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	n := 0
+	for _, e := range d.jobs {
+		if e.job.Queue == queue && e.status == "pending" {
+			n++
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,95 @@
+package dim
+
+import (
+	"context"
+)
+
+// GetQueueMigrations mengembalikan migrasi tabel queue_jobs yang dipakai DatabaseQueueDriver.
+// Modul ini opsional sehingga tidak termasuk dalam GetFrameworkMigrations.
+// Menggunakan versi 211 agar tidak bentrok dengan migrasi framework maupun aplikasi.
+//
+// Example:
+//
+//	migrations := append(dim.GetFrameworkMigrations(), dim.GetQueueMigrations()...)
+//	dim.RunMigrations(db, migrations)
+func GetQueueMigrations() []Migration {
+	return []Migration{
+		{
+			Version: 211,
+			Name:    "create_queue_jobs_table",
+			Up:      CreateQueueJobsTable,
+			Down:    DropQueueJobsTable,
+		},
+	}
+}
+
+// CreateQueueJobsTable membuat tabel queue_jobs. Index (queue, status, run_at) dipakai worker
+// untuk mengambil job berikutnya.
+func CreateQueueJobsTable(db Database) error {
+	var query string
+	switch db.DriverName() {
+	case "sqlite":
+		query = `
+			CREATE TABLE IF NOT EXISTS queue_jobs (
+				id TEXT PRIMARY KEY,
+				queue TEXT NOT NULL,
+				job_type TEXT NOT NULL,
+				payload TEXT NOT NULL,
+				status TEXT NOT NULL DEFAULT 'pending',
+				attempts INT NOT NULL DEFAULT 0,
+				max_attempts INT NOT NULL,
+				run_at TIMESTAMP NOT NULL,
+				locked_until TIMESTAMP NULL,
+				lease_token TEXT NULL,
+				last_error TEXT NULL,
+				created_at TIMESTAMP NOT NULL,
+				failed_at TIMESTAMP NULL
+			);
+			CREATE INDEX IF NOT EXISTS idx_queue_jobs_ready ON queue_jobs(queue, status, run_at);
+		`
+	case "mysql":
+		query = `
+			CREATE TABLE IF NOT EXISTS queue_jobs (
+				id CHAR(36) PRIMARY KEY,
+				queue VARCHAR(100) NOT NULL,
+				job_type VARCHAR(100) NOT NULL,
+				payload LONGTEXT NOT NULL,
+				status VARCHAR(10) NOT NULL DEFAULT 'pending',
+				attempts INT NOT NULL DEFAULT 0,
+				max_attempts INT NOT NULL,
+				run_at DATETIME(3) NOT NULL,
+				locked_until DATETIME(3) NULL,
+				lease_token VARCHAR(64) NULL,
+				last_error TEXT NULL,
+				created_at DATETIME(3) NOT NULL,
+				failed_at DATETIME(3) NULL,
+				INDEX idx_queue_jobs_ready (queue, status, run_at)
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+		`
+	default:
+		query = `
+			CREATE TABLE IF NOT EXISTS queue_jobs (
+				id UUID PRIMARY KEY,
+				queue VARCHAR(100) NOT NULL,
+				job_type VARCHAR(100) NOT NULL,
+				payload JSONB NOT NULL,
+				status VARCHAR(10) NOT NULL DEFAULT 'pending',
+				attempts INT NOT NULL DEFAULT 0,
+				max_attempts INT NOT NULL,
+				run_at TIMESTAMP NOT NULL,
+				locked_until TIMESTAMP NULL,
+				lease_token VARCHAR(64) NULL,
+				last_error TEXT NULL,
+				created_at TIMESTAMP NOT NULL,
+				failed_at TIMESTAMP NULL
+			);
+			CREATE INDEX IF NOT EXISTS idx_queue_jobs_ready ON queue_jobs(queue, status, run_at);
+		`
+	}
+	return db.Exec(context.Background(), query)
+}
+
+// DropQueueJobsTable menghapus tabel queue_jobs.
+func DropQueueJobsTable(db Database) error {
+	return db.Exec(context.Background(), "DROP TABLE IF EXISTS queue_jobs")
+}
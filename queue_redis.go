@@ -0,0 +1,249 @@
+package dim
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// RedisQueueDriver menyimpan job di Redis tanpa Lua script:
+//   - <prefix><queue>:pending: sorted set ID job dengan score waktu RunAt (milidetik)
+//   - <prefix><queue>:active: sorted set job yang sedang diproses dengan score batas lease
+//   - <prefix><queue>:dead: sorted set dead-letter dengan score waktu gagal
+//   - <prefix>job:<id>: data job dalam JSON
+//
+// Worker mengklaim job dengan ZREM: hanya satu worker yang mendapat balasan 1 untuk ID yang sama.
+// Job di active yang lease-nya habis dikembalikan ke pending pada Reserve berikutnya.
+type RedisQueueDriver struct {
+	client RedisCommander
+	prefix string
+}
+
+// NewRedisQueueDriver membuat RedisQueueDriver dengan prefix key "dim:queue:".
+//
+// Parameters:
+//   - client: *RedisClient atau client lain yang diadaptasi dengan RedisCommanderFunc
+//
+// Example:
+//
+//	rdb, _ := dim.NewRedisClient(cfg.Redis)
+//	queue := dim.NewQueue(dim.NewRedisQueueDriver(rdb), dim.QueueOptions{})
+func NewRedisQueueDriver(client RedisCommander) *RedisQueueDriver {
+	return &RedisQueueDriver{client: client, prefix: "dim:queue:"}
+}
+
+// WithKeyPrefix mengganti prefix key, misal untuk memisahkan beberapa aplikasi di satu Redis.
+func (d *RedisQueueDriver) WithKeyPrefix(prefix string) *RedisQueueDriver {
+	d.prefix = prefix
+	return d
+}
+
+func (d *RedisQueueDriver) key(queue, set string) string {
+	return d.prefix + queue + ":" + set
+}
+
+func (d *RedisQueueDriver) jobKey(id string) string {
+	return d.prefix + "job:" + id
+}
+
+func redisScore(t time.Time) string {
+	return strconv.FormatInt(t.UnixMilli(), 10)
+}
+
+func (d *RedisQueueDriver) save(ctx context.Context, job *Job) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	_, err = d.client.Do(ctx, "SET", d.jobKey(job.ID), string(data))
+	return err
+}
+
+func (d *RedisQueueDriver) load(ctx context.Context, id string) (*Job, error) {
+	reply, err := d.client.Do(ctx, "GET", d.jobKey(id))
+	if err != nil || reply == nil {
+		return nil, err
+	}
+	data, _ := reply.(string)
+	job := &Job{}
+	if err := json.Unmarshal([]byte(data), job); err != nil {
+		return nil, fmt.Errorf("invalid job data %s: %w", id, err)
+	}
+	return job, nil
+}
+
+// move memindahkan id dari sorted set from ke to dengan score. Mengembalikan false jika id sudah
+// tidak ada di from (diambil worker lain).
+func (d *RedisQueueDriver) move(ctx context.Context, from, to, id string, score time.Time) (bool, error) {
+	reply, err := d.client.Do(ctx, "ZREM", from, id)
+	if err != nil {
+		return false, err
+	}
+	if n, _ := reply.(int64); n == 0 {
+		return false, nil
+	}
+	_, err = d.client.Do(ctx, "ZADD", to, redisScore(score), id)
+	return err == nil, err
+}
+
+func (d *RedisQueueDriver) Push(ctx context.Context, job *Job) error {
+	if err := d.save(ctx, job); err != nil {
+		return fmt.Errorf("failed to push job: %w", err)
+	}
+	if _, err := d.client.Do(ctx, "ZADD", d.key(job.Queue, "pending"), redisScore(job.RunAt), job.ID); err != nil {
+		return fmt.Errorf("failed to push job: %w", err)
+	}
+	return nil
+}
+
+func (d *RedisQueueDriver) Reserve(ctx context.Context, queue string, lease time.Duration) (*Job, error) {
+	now := time.Now()
+	pending, active := d.key(queue, "pending"), d.key(queue, "active")
+
+	expired, err := d.client.Do(ctx, "ZRANGEBYSCORE", active, "-inf", redisScore(now), "LIMIT", "0", "10")
+	if err != nil {
+		return nil, fmt.Errorf("failed to reserve job: %w", err)
+	}
+	for _, id := range redisStrings(expired) {
+		if _, err := d.move(ctx, active, pending, id, now); err != nil {
+			return nil, fmt.Errorf("failed to reserve job: %w", err)
+		}
+	}
+
+	ready, err := d.client.Do(ctx, "ZRANGEBYSCORE", pending, "-inf", redisScore(now), "LIMIT", "0", "10")
+	if err != nil {
+		return nil, fmt.Errorf("failed to reserve job: %w", err)
+	}
+	for _, id := range redisStrings(ready) {
+		claimed, err := d.move(ctx, pending, active, id, now.Add(lease))
+		if err != nil {
+			return nil, fmt.Errorf("failed to reserve job: %w", err)
+		}
+		if !claimed {
+			continue
+		}
+
+		job, err := d.load(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to reserve job: %w", err)
+		}
+		if job == nil {
+			// Data job hilang (misal key dihapus manual); buang ID-nya.
+			d.client.Do(ctx, "ZREM", active, id) //nolint:errcheck
+			continue
+		}
+		job.Attempts++
+		if err := d.save(ctx, job); err != nil {
+			return nil, fmt.Errorf("failed to reserve job: %w", err)
+		}
+		return job, nil
+	}
+	return nil, nil
+}
+
+func (d *RedisQueueDriver) Complete(ctx context.Context, job *Job) error {
+	if _, err := d.client.Do(ctx, "ZREM", d.key(job.Queue, "active"), job.ID); err != nil {
+		return fmt.Errorf("failed to complete job: %w", err)
+	}
+	if _, err := d.client.Do(ctx, "DEL", d.jobKey(job.ID)); err != nil {
+		return fmt.Errorf("failed to complete job: %w", err)
+	}
+	return nil
+}
+
+func (d *RedisQueueDriver) Retry(ctx context.Context, job *Job, runAt time.Time) error {
+	job.RunAt = runAt.UTC()
+	if err := d.save(ctx, job); err != nil {
+		return fmt.Errorf("failed to retry job: %w", err)
+	}
+	if _, err := d.move(ctx, d.key(job.Queue, "active"), d.key(job.Queue, "pending"), job.ID, runAt); err != nil {
+		return fmt.Errorf("failed to retry job: %w", err)
+	}
+	return nil
+}
+
+func (d *RedisQueueDriver) Bury(ctx context.Context, job *Job) error {
+	if err := d.save(ctx, job); err != nil {
+		return fmt.Errorf("failed to bury job: %w", err)
+	}
+	if _, err := d.move(ctx, d.key(job.Queue, "active"), d.key(job.Queue, "dead"), job.ID, job.FailedAt); err != nil {
+		return fmt.Errorf("failed to bury job: %w", err)
+	}
+	return nil
+}
+
+func (d *RedisQueueDriver) Dead(ctx context.Context, queue string, limit int) ([]*Job, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	reply, err := d.client.Do(ctx, "ZREVRANGE", d.key(queue, "dead"), "0", strconv.Itoa(limit-1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dead jobs: %w", err)
+	}
+	var jobs []*Job
+	for _, id := range redisStrings(reply) {
+		job, err := d.load(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list dead jobs: %w", err)
+		}
+		if job != nil {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs, nil
+}
+
+func (d *RedisQueueDriver) Requeue(ctx context.Context, queue, id string) error {
+	score, err := d.client.Do(ctx, "ZSCORE", d.key(queue, "dead"), id)
+	if err != nil {
+		return fmt.Errorf("failed to requeue job: %w", err)
+	}
+	if score == nil {
+		return ErrJobNotFound
+	}
+	job, err := d.load(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to requeue job: %w", err)
+	}
+	if job == nil {
+		return ErrJobNotFound
+	}
+	now := time.Now().UTC()
+	job.Attempts = 0
+	job.FailedAt = time.Time{}
+	job.RunAt = now
+	if err := d.save(ctx, job); err != nil {
+		return fmt.Errorf("failed to requeue job: %w", err)
+	}
+	moved, err := d.move(ctx, d.key(queue, "dead"), d.key(queue, "pending"), id, now)
+	if err != nil {
+		return fmt.Errorf("failed to requeue job: %w", err)
+	}
+	if !moved {
+		return ErrJobNotFound
+	}
+	return nil
+}
+
+func (d *RedisQueueDriver) Len(ctx context.Context, queue string) (int, error) {
+	reply, err := d.client.Do(ctx, "ZCARD", d.key(queue, "pending"))
+	if err != nil {
+		return 0, fmt.Errorf("failed to count jobs: %w", err)
+	}
+	n, _ := reply.(int64)
+	return int(n), nil
+}
+
+// redisStrings mengubah array reply Redis menjadi []string.
+func redisStrings(reply any) []string {
+	items, _ := reply.([]any)
+	out := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,424 @@
+package dim
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testQueueDriver menjalankan skenario yang sama untuk setiap QueueDriver.
+func testQueueDriver(t *testing.T, d QueueDriver) {
+	t.Helper()
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	newJob := func(id, queue string, runAt time.Time) *Job {
+		return &Job{ID: id, Queue: queue, Type: "send", Payload: []byte(`{"n":1}`), MaxAttempts: 3, RunAt: runAt, CreatedAt: now}
+	}
+	for _, job := range []*Job{
+		newJob("00000000-0000-0000-0000-00000000000a", "q", now.Add(-time.Second)),
+		newJob("00000000-0000-0000-0000-00000000000b", "q", now.Add(time.Hour)),
+		newJob("00000000-0000-0000-0000-00000000000c", "other", now.Add(-time.Second)),
+	} {
+		if err := d.Push(ctx, job); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n, err := d.Len(ctx, "q"); err != nil || n != 2 {
+		t.Fatalf("Len = %d, %v", n, err)
+	}
+
+	job, err := d.Reserve(ctx, "q", time.Minute)
+	if err != nil || job == nil {
+		t.Fatalf("Reserve = %v, %v", job, err)
+	}
+	if !strings.HasSuffix(job.ID, "a") || job.Attempts != 1 || job.Type != "send" || string(job.Payload) != `{"n":1}` || job.MaxAttempts != 3 {
+		t.Fatalf("reserved = %+v", job)
+	}
+	if next, err := d.Reserve(ctx, "q", time.Minute); err != nil || next != nil {
+		t.Fatalf("delayed or locked job reserved: %+v, %v", next, err)
+	}
+
+	job.LastError = "boom"
+	if err := d.Retry(ctx, job, now.Add(-time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	job, _ = d.Reserve(ctx, "q", 10*time.Millisecond)
+	if job == nil || job.Attempts != 2 || job.LastError != "boom" {
+		t.Fatalf("retried = %+v", job)
+	}
+
+	// Lease yang habis (worker crash) membuat job dapat diambil lagi
+	time.Sleep(30 * time.Millisecond)
+	job, _ = d.Reserve(ctx, "q", time.Minute)
+	if job == nil || job.Attempts != 3 {
+		t.Fatalf("expired lease = %+v", job)
+	}
+
+	job.LastError = "still failing"
+	job.FailedAt = time.Now().UTC()
+	if err := d.Bury(ctx, job); err != nil {
+		t.Fatal(err)
+	}
+	dead, err := d.Dead(ctx, "q", 10)
+	if err != nil || len(dead) != 1 || dead[0].ID != job.ID || dead[0].LastError != "still failing" || dead[0].FailedAt.IsZero() {
+		t.Fatalf("Dead = %+v, %v", dead, err)
+	}
+	if n, _ := d.Len(ctx, "q"); n != 1 {
+		t.Errorf("Len after bury = %d", n)
+	}
+
+	if err := d.Requeue(ctx, "other", job.ID); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("Requeue wrong queue err = %v", err)
+	}
+	if err := d.Requeue(ctx, "q", job.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Requeue(ctx, "q", job.ID); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("Requeue twice err = %v", err)
+	}
+	job, _ = d.Reserve(ctx, "q", time.Minute)
+	if job == nil || job.Attempts != 1 {
+		t.Fatalf("requeued = %+v", job)
+	}
+	if err := d.Complete(ctx, job); err != nil {
+		t.Fatal(err)
+	}
+	if next, _ := d.Reserve(ctx, "q", time.Minute); next != nil {
+		t.Errorf("completed job reserved again: %+v", next)
+	}
+	if dead, _ := d.Dead(ctx, "q", 10); len(dead) != 0 {
+		t.Errorf("Dead after complete = %+v", dead)
+	}
+	if other, _ := d.Reserve(ctx, "other", time.Minute); other == nil || !strings.HasSuffix(other.ID, "c") {
+		t.Errorf("other queue = %+v", other)
+	}
+}
+
+func TestMemoryQueueDriver(t *testing.T) {
+	testQueueDriver(t, NewMemoryQueueDriver())
+}
+
+func TestDatabaseQueueDriver_SQLite(t *testing.T) {
+	db, err := NewSQLiteDatabase(DatabaseConfig{Database: ":memory:"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := RunMigrations(db, GetQueueMigrations()); err != nil {
+		t.Fatal(err)
+	}
+	testQueueDriver(t, NewDatabaseQueueDriver(db))
+}
+
+func TestRedisQueueDriver(t *testing.T) {
+	testQueueDriver(t, NewRedisQueueDriver(newFakeSortedSetRedis()).WithKeyPrefix("test:"))
+}
+
+// newFakeSortedSetRedis adalah RedisCommander in-memory untuk command yang dipakai
+// RedisQueueDriver: SET, GET, DEL, ZADD, ZREM, ZSCORE, ZCARD, ZRANGEBYSCORE, dan ZREVRANGE.
+func newFakeSortedSetRedis() RedisCommanderFunc {
+	var mu sync.Mutex
+	values := make(map[string]string)
+	sets := make(map[string]map[string]float64)
+
+	sorted := func(key string) []string {
+		members := make([]string, 0, len(sets[key]))
+		for m := range sets[key] {
+			members = append(members, m)
+		}
+		slices.SortFunc(members, func(a, b string) int {
+			return cmp.Or(cmp.Compare(sets[key][a], sets[key][b]), cmp.Compare(a, b))
+		})
+		return members
+	}
+
+	return func(ctx context.Context, args ...string) (any, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		key := args[1]
+		switch args[0] {
+		case "SET":
+			values[key] = args[2]
+			return "OK", nil
+		case "GET":
+			if v, ok := values[key]; ok {
+				return v, nil
+			}
+			return nil, nil
+		case "DEL":
+			delete(values, key)
+			return int64(1), nil
+		case "ZADD":
+			if sets[key] == nil {
+				sets[key] = make(map[string]float64)
+			}
+			score, _ := strconv.ParseFloat(args[2], 64)
+			sets[key][args[3]] = score
+			return int64(1), nil
+		case "ZREM":
+			if _, ok := sets[key][args[2]]; !ok {
+				return int64(0), nil
+			}
+			delete(sets[key], args[2])
+			return int64(1), nil
+		case "ZSCORE":
+			if score, ok := sets[key][args[2]]; ok {
+				return strconv.FormatFloat(score, 'f', -1, 64), nil
+			}
+			return nil, nil
+		case "ZCARD":
+			return int64(len(sets[key])), nil
+		case "ZRANGEBYSCORE":
+			max, _ := strconv.ParseFloat(args[3], 64)
+			limit, _ := strconv.Atoi(args[6])
+			var out []any
+			for _, m := range sorted(key) {
+				if sets[key][m] <= max && len(out) < limit {
+					out = append(out, m)
+				}
+			}
+			return out, nil
+		case "ZREVRANGE":
+			stop, _ := strconv.Atoi(args[3])
+			members := sorted(key)
+			slices.Reverse(members)
+			var out []any
+			for i, m := range members {
+				if i <= stop {
+					out = append(out, m)
+				}
+			}
+			return out, nil
+		}
+		return nil, errors.New("unsupported command " + args[0])
+	}
+}
+
+type testJobPayload struct {
+	UserID string `json:"user_id"`
+}
+
+var testJob = NewJobType[testJobPayload]("test_job")
+
+func newTestQueue(opts QueueOptions) *Queue {
+	opts.Workers = cmp.Or(opts.Workers, 2)
+	opts.PollInterval = cmp.Or(opts.PollInterval, 5*time.Millisecond)
+	if opts.Backoff == nil {
+		opts.Backoff = func(int) time.Duration { return 0 }
+	}
+	return NewQueue(NewMemoryQueueDriver(), opts)
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(2 * time.Millisecond)
+	}
+}
+
+func TestQueue_ProcessesTypedJobs(t *testing.T) {
+	registry := NewMetricsRegistry()
+	queue := newTestQueue(QueueOptions{}).WithMetrics(NewQueueMetrics(registry))
+
+	var calls atomic.Int32
+	var mu sync.Mutex
+	var got []string
+	testJob.Handle(queue, func(ctx context.Context, p testJobPayload) error {
+		job, _ := JobFromContext(ctx)
+		if calls.Add(1) == 1 {
+			return errors.New("smtp down")
+		}
+		mu.Lock()
+		got = append(got, p.UserID+"@"+strconv.Itoa(job.Attempts))
+		mu.Unlock()
+		return nil
+	})
+	queue.Start()
+	t.Cleanup(func() { queue.Shutdown(context.Background()) })
+
+	if _, err := testJob.Enqueue(context.Background(), queue, testJobPayload{UserID: "u-1"}); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "job processed", func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(got) == 1
+	})
+	if got[0] != "u-1@2" {
+		t.Errorf("processed = %v, want retry on attempt 2", got)
+	}
+
+	var b strings.Builder
+	registry.WriteTo(&b)
+	for _, want := range []string{
+		`dim_queue_job_retries_total{queue="default",job="test_job"} 1`,
+		`dim_queue_jobs_processed_total{queue="default",job="test_job",status="success"} 1`,
+		`dim_queue_jobs_processed_total{queue="default",job="test_job",status="failure"} 1`,
+	} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("metrics missing %s:\n%s", want, b.String())
+		}
+	}
+}
+
+func TestQueue_DeadLetter(t *testing.T) {
+	queue := newTestQueue(QueueOptions{MaxAttempts: 2})
+	var dead []string
+	var mu sync.Mutex
+	queue.OnDeadLetter(func(ctx context.Context, job *Job, err error) {
+		mu.Lock()
+		dead = append(dead, job.Type+":"+err.Error())
+		mu.Unlock()
+	})
+
+	var fixed atomic.Bool
+	queue.Handle("flaky", func(ctx context.Context, job *Job) error {
+		if fixed.Load() {
+			return nil
+		}
+		return errors.New("upstream down")
+	})
+	queue.Handle("invalid", func(ctx context.Context, job *Job) error {
+		return PermanentJobError(errors.New("bad payload"))
+	})
+	queue.Handle("panics", func(ctx context.Context, job *Job) error {
+		panic("nil map")
+	})
+	queue.Start()
+	t.Cleanup(func() { queue.Shutdown(context.Background()) })
+
+	ctx := context.Background()
+	flaky, _ := queue.Enqueue(ctx, "flaky", nil)
+	queue.Enqueue(ctx, "invalid", nil, WithJobMaxAttempts(5))
+	queue.Enqueue(ctx, "panics", nil, WithJobMaxAttempts(1))
+
+	var jobs []*Job
+	waitFor(t, "dead letters", func() bool {
+		jobs, _ = queue.DeadLetters(ctx, 10)
+		return len(jobs) == 3
+	})
+	for _, job := range jobs {
+		switch job.Type {
+		case "flaky":
+			if job.Attempts != 2 || job.LastError != "upstream down" {
+				t.Errorf("flaky = %+v", job)
+			}
+		case "invalid":
+			if job.Attempts != 1 {
+				t.Errorf("permanent error retried: %+v", job)
+			}
+		case "panics":
+			if !strings.Contains(job.LastError, "job panicked: nil map") {
+				t.Errorf("panic = %+v", job)
+			}
+		}
+	}
+	mu.Lock()
+	if len(dead) != 3 {
+		t.Errorf("OnDeadLetter calls = %v", dead)
+	}
+	mu.Unlock()
+
+	fixed.Store(true)
+	if err := queue.Requeue(ctx, flaky.ID); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "requeued job processed", func() bool {
+		jobs, _ = queue.DeadLetters(ctx, 10)
+		n, _ := queue.Len(ctx)
+		return len(jobs) == 2 && n == 0
+	})
+}
+
+func TestQueue_Delay(t *testing.T) {
+	queue := newTestQueue(QueueOptions{})
+	done := make(chan time.Time, 1)
+	queue.Handle("later", func(ctx context.Context, job *Job) error {
+		done <- time.Now()
+		return nil
+	})
+	queue.Start()
+	t.Cleanup(func() { queue.Shutdown(context.Background()) })
+
+	start := time.Now()
+	queue.Enqueue(context.Background(), "later", nil, WithJobDelay(50*time.Millisecond))
+	select {
+	case at := <-done:
+		if at.Sub(start) < 50*time.Millisecond {
+			t.Errorf("job ran after %v, before its delay", at.Sub(start))
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("delayed job never ran")
+	}
+}
+
+func TestQueue_Shutdown(t *testing.T) {
+	driver := NewMemoryQueueDriver()
+	queue := NewQueue(driver, QueueOptions{Workers: 1, PollInterval: 5 * time.Millisecond})
+	started := make(chan struct{})
+	queue.Handle("slow", func(ctx context.Context, job *Job) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	queue.Start()
+
+	ctx := context.Background()
+	queue.Enqueue(ctx, "slow", nil)
+	<-started
+
+	shutdownCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if err := queue.Shutdown(shutdownCtx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Shutdown err = %v", err)
+	}
+	if _, err := queue.Enqueue(ctx, "slow", nil); !errors.Is(err, ErrQueueClosed) {
+		t.Errorf("Enqueue after shutdown err = %v", err)
+	}
+
+	// Job yang dibatalkan Shutdown kembali ke antrian tanpa menghitung percobaan
+	var job *Job
+	waitFor(t, "job released", func() bool {
+		job, _ = driver.Reserve(ctx, "default", time.Minute)
+		return job != nil
+	})
+	if job.Attempts != 1 || job.LastError != context.Canceled.Error() {
+		t.Errorf("released job = %+v", job)
+	}
+}
+
+func TestQueueMailer(t *testing.T) {
+	queue := newTestQueue(QueueOptions{})
+	transport := NewCaptureMailer(10)
+	mailer := NewQueueMailer(queue, transport)
+	queue.Start()
+	t.Cleanup(func() { queue.Shutdown(context.Background()) })
+
+	msg := NewMailMessage([]string{"ana@example.com"}, "Invoice")
+	msg.Attachments = []Attachment{NewAttachment("invoice.txt", []byte("total 10"))}
+	if err := mailer.Send(context.Background(), msg); err != nil {
+		t.Fatal(err)
+	}
+
+	var sent CapturedMail
+	waitFor(t, "mail sent", func() bool {
+		var ok bool
+		sent, ok = transport.Last("ana@example.com")
+		return ok
+	})
+	if sent.Message.Subject != "Invoice" || string(sent.Message.Attachments[0].Data) != "total 10" {
+		t.Errorf("sent = %+v", sent.Message)
+	}
+}
@@ -29,6 +29,15 @@ func CreateRateLimitsTable(db Database) error {
 			);
 			CREATE INDEX IF NOT EXISTS idx_rate_limits_expires_at ON rate_limits(expires_at);
 		`
+	} else if db.DriverName() == "mysql" {
+		query = `
+			CREATE TABLE IF NOT EXISTS rate_limits (
+				` + "`key`" + ` VARCHAR(255) PRIMARY KEY,
+				count INT NOT NULL DEFAULT 0,
+				expires_at DATETIME NOT NULL,
+				INDEX idx_rate_limits_expires_at (expires_at)
+			) ENGINE=InnoDB
+		`
 	} else {
 		query = `
 			CREATE UNLOGGED TABLE IF NOT EXISTS rate_limits (
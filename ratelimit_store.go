@@ -101,6 +101,15 @@ func (s *DatabaseRateLimitStore) InitSchema(ctx context.Context) error {
 			);
 			CREATE INDEX IF NOT EXISTS idx_rate_limits_expires_at ON rate_limits(expires_at);
 		`
+	} else if s.db.DriverName() == "mysql" {
+		query = `
+			CREATE TABLE IF NOT EXISTS rate_limits (
+				` + "`key`" + ` VARCHAR(255) PRIMARY KEY,
+				count INT NOT NULL DEFAULT 0,
+				expires_at DATETIME NOT NULL,
+				INDEX idx_rate_limits_expires_at (expires_at)
+			) ENGINE=InnoDB
+		`
 	} else {
 		query = `
 			CREATE UNLOGGED TABLE IF NOT EXISTS rate_limits (
@@ -119,6 +128,10 @@ func (s *DatabaseRateLimitStore) Allow(ctx context.Context, key string, limit in
 	now := time.Now().UTC().Truncate(time.Second)
 	expiresAt := now.Add(window)
 
+	if s.db.DriverName() == "mysql" {
+		return s.allowMySQL(ctx, key, limit, now, expiresAt)
+	}
+
 	// Atomic UPSERT (Insert or Update) dengan logika sliding window.
 	// Jika record ada tapi expired (expires_at < now), reset count ke 1 dan update expires_at.
 	// Jika record ada dan valid, increment count.
@@ -150,6 +163,29 @@ func (s *DatabaseRateLimitStore) Allow(ctx context.Context, key string, limit in
 	return count <= limit, nil
 }
 
+// allowMySQL menjalankan UPSERT yang sama dengan ON DUPLICATE KEY UPDATE. MySQL tidak mendukung
+// RETURNING, sehingga count dibaca ulang di dalam transaksi yang sama.
+func (s *DatabaseRateLimitStore) allowMySQL(ctx context.Context, key string, limit int, now, expiresAt time.Time) (bool, error) {
+	var count int
+	err := s.db.WithTx(ctx, func(ctx context.Context, tx Tx) error {
+		// MySQL mengevaluasi assignment dari kiri ke kanan: count ditulis lebih dulu agar
+		// kondisinya masih membaca expires_at yang lama.
+		query := "INSERT INTO rate_limits (`key`, count, expires_at) VALUES ($1, 1, $2) " +
+			"ON DUPLICATE KEY UPDATE " +
+			"count = IF(expires_at < $3, 1, count + 1), " +
+			"expires_at = IF(expires_at < $3, $2, expires_at)"
+		if err := tx.Exec(ctx, query, key, expiresAt, now); err != nil {
+			return err
+		}
+		return tx.QueryRow(ctx, "SELECT count FROM rate_limits WHERE `key` = $1", key).Scan(&count)
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return count <= limit, nil
+}
+
 // Close menutup koneksi (no-op untuk implementasi ini karena DB dikelola di luar).
 func (s *DatabaseRateLimitStore) Close() error {
 	return nil
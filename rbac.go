@@ -0,0 +1,430 @@
+package dim
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/atfromhome/goreus/pkg/cache"
+)
+
+// RolesClaim adalah claim token berisi daftar role user, misal ["admin", "editor"].
+// Seperti PermissionsClaim, nilai boleh berupa array atau string yang dipisah spasi.
+const RolesClaim = "roles"
+
+// ErrRoleNotFound dikembalikan PermissionStore jika role tidak ditemukan.
+var ErrRoleNotFound = errors.New("role not found")
+
+// Role adalah kumpulan permission yang dapat diberikan ke user.
+type Role struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// PermissionStore mendefinisikan penyimpanan role, permission per role, dan role per user.
+// Implementasi mengembalikan ErrRoleNotFound (boleh di-wrap) jika role tidak ditemukan.
+type PermissionStore interface {
+	SaveRole(ctx context.Context, role *Role) error // Insert atau update description
+	FindRole(ctx context.Context, name string) (*Role, error)
+	ListRoles(ctx context.Context) ([]*Role, error)
+	DeleteRole(ctx context.Context, name string) error // Juga menghapus permission dan assignment role
+
+	GrantPermission(ctx context.Context, role, permission string) error
+	RevokePermission(ctx context.Context, role, permission string) error
+	RolePermissions(ctx context.Context, role string) ([]string, error)
+
+	AssignRole(ctx context.Context, userID, role string) error
+	RemoveRole(ctx context.Context, userID, role string) error
+	UserRoles(ctx context.Context, userID string) ([]string, error)
+	RoleUsers(ctx context.Context, role string) ([]string, error)         // ID user yang memiliki role
+	UserPermissions(ctx context.Context, userID string) ([]string, error) // Gabungan permission semua role user
+}
+
+// rbacEntry adalah role dan permission user yang di-cache.
+type rbacEntry struct {
+	roles       []string
+	permissions []string
+}
+
+// RBAC mengelola role dan permission user di atas PermissionStore, menyisipkannya ke token
+// lewat ClaimsProvider, dan memeriksanya dengan cache sehingga middleware tidak query
+// database di setiap request.
+type RBAC struct {
+	store      PermissionStore
+	cache      *cache.InMemoryCache[string, rbacEntry]
+	generation atomic.Uint64
+}
+
+// NewRBAC membuat RBAC dengan cache role dan permission per user berkapasitas 10000 entri
+// selama 1 menit.
+//
+// Parameters:
+//   - store: PermissionStore untuk menyimpan role dan permission
+//
+// Returns:
+//   - *RBAC: RBAC yang siap digunakan
+//
+// Example:
+//
+//	rbac := dim.NewRBAC(dim.NewDatabasePermissionStore(db))
+//	authService.WithClaimsProvider(rbac.ClaimsProvider())
+//
+//	admin := router.Group("/admin", dim.RequireAuth(jwtManager, blocklist), dim.RequireRole("admin"))
+//	admin.Delete("/users/{id}", deleteUserHandler, dim.RequirePermission("users.delete"))
+func NewRBAC(store PermissionStore) *RBAC {
+	return &RBAC{
+		store: store,
+		cache: cache.NewInMemoryCache[string, rbacEntry](10000, time.Minute),
+	}
+}
+
+// WithCache mengganti kapasitas dan TTL cache. TTL <= 0 menonaktifkan cache, sehingga setiap
+// pemeriksaan membaca PermissionStore.
+//
+// Returns:
+//   - *RBAC: RBAC yang sama untuk chaining
+func (a *RBAC) WithCache(capacity int, ttl time.Duration) *RBAC {
+	if ttl <= 0 {
+		a.cache = nil
+		return a
+	}
+	a.cache = cache.NewInMemoryCache[string, rbacEntry](capacity, ttl)
+	return a
+}
+
+// cacheKey menyertakan generasi cache sehingga perubahan permission sebuah role langsung
+// mengabaikan semua entri lama tanpa harus mengosongkan cache.
+func (a *RBAC) cacheKey(userID string) string {
+	return fmt.Sprintf("%d:%s", a.generation.Load(), userID)
+}
+
+// Invalidate menghapus role dan permission user dari cache.
+func (a *RBAC) Invalidate(ctx context.Context, userID string) {
+	if a.cache != nil {
+		a.cache.Delete(ctx, a.cacheKey(userID))
+	}
+}
+
+// invalidateAll mengabaikan seluruh entri cache, dipakai saat definisi role berubah.
+func (a *RBAC) invalidateAll() {
+	a.generation.Add(1)
+}
+
+// SaveRole membuat role atau memperbarui deskripsinya.
+func (a *RBAC) SaveRole(ctx context.Context, name, description string) (*Role, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, NewAppError("Nama role diperlukan", http.StatusBadRequest)
+	}
+	role := &Role{Name: name, Description: description}
+	if err := a.store.SaveRole(ctx, role); err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+// DeleteRole menghapus role beserta permission dan assignment-nya.
+func (a *RBAC) DeleteRole(ctx context.Context, name string) error {
+	if err := a.store.DeleteRole(ctx, name); err != nil {
+		return err
+	}
+	a.invalidateAll()
+	return nil
+}
+
+// Roles mengembalikan semua role.
+func (a *RBAC) Roles(ctx context.Context) ([]*Role, error) {
+	return a.store.ListRoles(ctx)
+}
+
+// GrantPermission menambahkan permission ke role. Mengembalikan ErrRoleNotFound jika role
+// belum dibuat dengan SaveRole.
+//
+// Example:
+//
+//	rbac.GrantPermission(ctx, "admin", "users.delete")
+//	rbac.GrantPermission(ctx, "editor", "posts.*")
+func (a *RBAC) GrantPermission(ctx context.Context, role, permission string) error {
+	if _, err := a.store.FindRole(ctx, role); err != nil {
+		return err
+	}
+	if err := a.store.GrantPermission(ctx, role, permission); err != nil {
+		return err
+	}
+	a.invalidateAll()
+	return nil
+}
+
+// RevokePermission menghapus permission dari role.
+func (a *RBAC) RevokePermission(ctx context.Context, role, permission string) error {
+	if err := a.store.RevokePermission(ctx, role, permission); err != nil {
+		return err
+	}
+	a.invalidateAll()
+	return nil
+}
+
+// RolePermissions mengembalikan permission milik role.
+func (a *RBAC) RolePermissions(ctx context.Context, role string) ([]string, error) {
+	return a.store.RolePermissions(ctx, role)
+}
+
+// AssignRole memberikan role ke user. Mengembalikan ErrRoleNotFound jika role belum dibuat.
+// Claims di token berubah setelah token di-refresh; pemeriksaan lewat RBAC langsung berlaku.
+func (a *RBAC) AssignRole(ctx context.Context, userID, role string) error {
+	if _, err := a.store.FindRole(ctx, role); err != nil {
+		return err
+	}
+	if err := a.store.AssignRole(ctx, userID, role); err != nil {
+		return err
+	}
+	a.Invalidate(ctx, userID)
+	return nil
+}
+
+// RemoveRole mencabut role dari user.
+func (a *RBAC) RemoveRole(ctx context.Context, userID, role string) error {
+	if err := a.store.RemoveRole(ctx, userID, role); err != nil {
+		return err
+	}
+	a.Invalidate(ctx, userID)
+	return nil
+}
+
+// entry mengambil role dan permission user dari cache, atau dari store jika belum ada.
+func (a *RBAC) entry(ctx context.Context, userID string) (rbacEntry, error) {
+	key := a.cacheKey(userID)
+	if a.cache != nil {
+		if entry, ok := a.cache.Get(ctx, key); ok {
+			return entry, nil
+		}
+	}
+
+	roles, err := a.store.UserRoles(ctx, userID)
+	if err != nil {
+		return rbacEntry{}, err
+	}
+	permissions, err := a.store.UserPermissions(ctx, userID)
+	if err != nil {
+		return rbacEntry{}, err
+	}
+	entry := rbacEntry{roles: roles, permissions: permissions}
+	if a.cache != nil {
+		a.cache.Set(ctx, key, entry)
+	}
+	return entry, nil
+}
+
+// UserRoles mengembalikan role user (dari cache jika tersedia).
+func (a *RBAC) UserRoles(ctx context.Context, userID string) ([]string, error) {
+	entry, err := a.entry(ctx, userID)
+	return slices.Clone(entry.roles), err
+}
+
+// RoleUsers mengembalikan ID user yang memiliki role (langsung dari store, tanpa cache).
+func (a *RBAC) RoleUsers(ctx context.Context, role string) ([]string, error) {
+	return a.store.RoleUsers(ctx, role)
+}
+
+// UserPermissions mengembalikan gabungan permission dari semua role user (dari cache jika tersedia).
+func (a *RBAC) UserPermissions(ctx context.Context, userID string) ([]string, error) {
+	entry, err := a.entry(ctx, userID)
+	return slices.Clone(entry.permissions), err
+}
+
+// HasRole melaporkan apakah user memiliki salah satu role.
+func (a *RBAC) HasRole(ctx context.Context, userID string, roles ...string) (bool, error) {
+	entry, err := a.entry(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return hasAnyRole(entry.roles, roles), nil
+}
+
+// Can melaporkan apakah user memiliki permission, lihat PermissionMatches untuk wildcard.
+//
+// Example:
+//
+//	if ok, err := rbac.Can(ctx, user.GetID(), "users.delete"); err != nil || !ok {
+//	    return dim.NewAppError("Forbidden", http.StatusForbidden)
+//	}
+func (a *RBAC) Can(ctx context.Context, userID, permission string) (bool, error) {
+	entry, err := a.entry(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return hasPermission(entry.permissions, permission), nil
+}
+
+// ClaimsProvider mengembalikan ClaimsProvider yang menyisipkan claim roles dan permissions,
+// sehingga RequireRole dan RequirePermission dapat memeriksa token tanpa query database.
+// Gabungkan dengan provider lain menggunakan MergeClaimsProviders.
+//
+// Example:
+//
+//	authService.WithClaimsProvider(dim.MergeClaimsProviders(orgs.ClaimsProvider(), rbac.ClaimsProvider()))
+func (a *RBAC) ClaimsProvider() ClaimsProvider {
+	return func(ctx context.Context, user Authenticatable) (map[string]interface{}, error) {
+		entry, err := a.entry(ctx, user.GetID())
+		if err != nil {
+			return nil, fmt.Errorf("failed to load roles: %w", err)
+		}
+		return map[string]interface{}{
+			RolesClaim:       nonNilStrings(entry.roles),
+			PermissionsClaim: nonNilStrings(entry.permissions),
+		}, nil
+	}
+}
+
+// RequireRole membuat middleware yang memeriksa role user lewat RBAC (dengan cache), sehingga
+// perubahan role berlaku tanpa menunggu token di-refresh. Pasang setelah RequireAuth.
+// Lihat fungsi RequireRole untuk pemeriksaan berbasis claim token.
+func (a *RBAC) RequireRole(roles ...string) MiddlewareFunc {
+	return a.require(func(ctx context.Context, userID string) (bool, error) {
+		return a.HasRole(ctx, userID, roles...)
+	}, "Role tidak mencukupi")
+}
+
+// RequirePermission membuat middleware yang mewajibkan semua permission lewat RBAC (dengan
+// cache). Pasang setelah RequireAuth.
+func (a *RBAC) RequirePermission(permissions ...string) MiddlewareFunc {
+	return a.require(func(ctx context.Context, userID string) (bool, error) {
+		entry, err := a.entry(ctx, userID)
+		if err != nil {
+			return false, err
+		}
+		return hasAllPermissions(entry.permissions, permissions), nil
+	}, "Permission tidak mencukupi")
+}
+
+func (a *RBAC) require(allowed func(ctx context.Context, userID string) (bool, error), message string) MiddlewareFunc {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			user, ok := GetUser(r)
+			if !ok {
+				JsonError(w, http.StatusUnauthorized, "User tidak terotentikasi", nil)
+				return
+			}
+			ok, err := allowed(r.Context(), user.GetID())
+			if err != nil {
+				JsonError(w, http.StatusInternalServerError, "Gagal memeriksa hak akses", nil)
+				return
+			}
+			if !ok {
+				Forbidden(w, message)
+				return
+			}
+			next(w, r)
+		}
+	}
+}
+
+// RequireRole membuat middleware yang mewajibkan salah satu role berdasarkan claim roles di
+// token (lihat RBAC.ClaimsProvider), tanpa query database. Pasang setelah RequireAuth.
+// Mengembalikan 401 tanpa user dan 403 jika role tidak dimiliki. Karena dibaca dari token,
+// perubahan role berlaku setelah token di-refresh; gunakan RBAC.RequireRole jika harus langsung.
+//
+// Parameters:
+//   - roles: role yang diizinkan, cukup salah satu
+//
+// Returns:
+//   - MiddlewareFunc: middleware pemeriksa role
+//
+// Example:
+//
+//	admin := router.Group("/admin", dim.RequireAuth(jwtManager, blocklist), dim.RequireRole("admin"))
+func RequireRole(roles ...string) MiddlewareFunc {
+	return requireClaims(func(r *http.Request) bool {
+		return HasRole(r, roles...)
+	}, "Role tidak mencukupi")
+}
+
+// RequirePermission membuat middleware yang mewajibkan semua permission berdasarkan claim
+// permissions di token, tanpa query database. Pasang setelah RequireAuth.
+//
+// Parameters:
+//   - permissions: permission yang wajib dimiliki, misal "users.delete"
+//
+// Returns:
+//   - MiddlewareFunc: middleware pemeriksa permission
+//
+// Example:
+//
+//	api.Delete("/users/{id}", deleteUserHandler, dim.RequirePermission("users.delete"))
+func RequirePermission(permissions ...string) MiddlewareFunc {
+	return requireClaims(func(r *http.Request) bool {
+		return hasAllPermissions(claimStrings(GetClaims(r)[PermissionsClaim]), permissions)
+	}, "Permission tidak mencukupi")
+}
+
+func requireClaims(allowed func(r *http.Request) bool, message string) MiddlewareFunc {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if _, ok := GetUser(r); !ok {
+				JsonError(w, http.StatusUnauthorized, "User tidak terotentikasi", nil)
+				return
+			}
+			if !allowed(r) {
+				Forbidden(w, message)
+				return
+			}
+			next(w, r)
+		}
+	}
+}
+
+// HasRole melaporkan apakah claim roles pada token request berisi salah satu role.
+func HasRole(r *http.Request, roles ...string) bool {
+	return hasAnyRole(claimStrings(GetClaims(r)[RolesClaim]), roles)
+}
+
+// PermissionMatches melaporkan apakah permission yang dimiliki (granted) mencakup permission
+// yang diminta. "*" mencakup semua permission, dan akhiran "*" mencakup prefix-nya
+// ("users.*" mencakup "users.delete").
+func PermissionMatches(granted, required string) bool {
+	if granted == required || granted == "*" {
+		return true
+	}
+	prefix, ok := strings.CutSuffix(granted, "*")
+	return ok && strings.HasPrefix(required, prefix)
+}
+
+func hasPermission(granted []string, required string) bool {
+	for _, g := range granted {
+		if PermissionMatches(g, required) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasAllPermissions(granted, required []string) bool {
+	for _, p := range required {
+		if !hasPermission(granted, p) {
+			return false
+		}
+	}
+	return true
+}
+
+func hasAnyRole(have, roles []string) bool {
+	for _, role := range roles {
+		if slices.Contains(have, role) {
+			return true
+		}
+	}
+	return false
+}
+
+// nonNilStrings memastikan claim dikodekan sebagai array kosong, bukan null.
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
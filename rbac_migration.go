@@ -0,0 +1,166 @@
+package dim
+
+import (
+	"context"
+)
+
+// GetRBACMigrations mengembalikan daftar migrasi modul RBAC (roles, permission per role, dan
+// role per user). Modul ini opsional sehingga tidak termasuk dalam GetFrameworkMigrations;
+// gabungkan secara manual jika menggunakan RBAC.
+// Menggunakan versi 171-173 agar tidak bentrok dengan migrasi framework maupun aplikasi.
+//
+// Example:
+//
+//	migrations := append(dim.GetFrameworkMigrations(), dim.GetRBACMigrations()...)
+//	dim.RunMigrations(db, migrations)
+func GetRBACMigrations() []Migration {
+	return []Migration{
+		{
+			Version: 171,
+			Name:    "create_roles_table",
+			Up:      CreateRolesTable,
+			Down:    DropRolesTable,
+		},
+		{
+			Version: 172,
+			Name:    "create_role_permissions_table",
+			Up:      CreateRolePermissionsTable,
+			Down:    DropRolePermissionsTable,
+		},
+		{
+			Version: 173,
+			Name:    "create_user_roles_table",
+			Up:      CreateUserRolesTable,
+			Down:    DropUserRolesTable,
+		},
+	}
+}
+
+// CreateRolesTable membuat tabel roles.
+func CreateRolesTable(db Database) error {
+	var query string
+	if db.DriverName() == "sqlite" {
+		query = `
+			CREATE TABLE IF NOT EXISTS roles (
+				name TEXT PRIMARY KEY,
+				description TEXT NOT NULL DEFAULT '',
+				created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+			);
+		`
+	} else if db.DriverName() == "mysql" {
+		query = `
+			CREATE TABLE IF NOT EXISTS roles (
+				name VARCHAR(100) PRIMARY KEY,
+				description TEXT NOT NULL,
+				created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+		`
+	} else {
+		query = `
+			CREATE TABLE IF NOT EXISTS roles (
+				name VARCHAR(100) PRIMARY KEY,
+				description TEXT NOT NULL DEFAULT '',
+				created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+			);
+		`
+	}
+	return db.Exec(context.Background(), query)
+}
+
+// DropRolesTable menghapus tabel roles.
+func DropRolesTable(db Database) error {
+	query := "DROP TABLE IF EXISTS roles CASCADE"
+	if db.DriverName() == "sqlite" {
+		query = "DROP TABLE IF EXISTS roles"
+	}
+	return db.Exec(context.Background(), query)
+}
+
+// CreateRolePermissionsTable membuat tabel role_permissions.
+func CreateRolePermissionsTable(db Database) error {
+	var query string
+	if db.DriverName() == "sqlite" {
+		query = `
+			CREATE TABLE IF NOT EXISTS role_permissions (
+				role TEXT NOT NULL REFERENCES roles(name) ON DELETE CASCADE,
+				permission TEXT NOT NULL,
+				PRIMARY KEY (role, permission)
+			);
+		`
+	} else if db.DriverName() == "mysql" {
+		query = `
+			CREATE TABLE IF NOT EXISTS role_permissions (
+				role VARCHAR(100) NOT NULL,
+				permission VARCHAR(255) NOT NULL,
+				PRIMARY KEY (role, permission),
+				FOREIGN KEY (role) REFERENCES roles(name) ON DELETE CASCADE
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+		`
+	} else {
+		query = `
+			CREATE TABLE IF NOT EXISTS role_permissions (
+				role VARCHAR(100) NOT NULL REFERENCES roles(name) ON DELETE CASCADE,
+				permission VARCHAR(255) NOT NULL,
+				PRIMARY KEY (role, permission)
+			);
+		`
+	}
+	return db.Exec(context.Background(), query)
+}
+
+// DropRolePermissionsTable menghapus tabel role_permissions.
+func DropRolePermissionsTable(db Database) error {
+	query := "DROP TABLE IF EXISTS role_permissions CASCADE"
+	if db.DriverName() == "sqlite" {
+		query = "DROP TABLE IF EXISTS role_permissions"
+	}
+	return db.Exec(context.Background(), query)
+}
+
+// CreateUserRolesTable membuat tabel user_roles.
+func CreateUserRolesTable(db Database) error {
+	var query string
+	if db.DriverName() == "sqlite" {
+		query = `
+			CREATE TABLE IF NOT EXISTS user_roles (
+				user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+				role TEXT NOT NULL REFERENCES roles(name) ON DELETE CASCADE,
+				created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				PRIMARY KEY (user_id, role)
+			);
+			CREATE INDEX IF NOT EXISTS idx_user_roles_role ON user_roles(role);
+		`
+	} else if db.DriverName() == "mysql" {
+		query = `
+			CREATE TABLE IF NOT EXISTS user_roles (
+				user_id CHAR(36) NOT NULL,
+				role VARCHAR(100) NOT NULL,
+				created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				PRIMARY KEY (user_id, role),
+				INDEX idx_user_roles_role (role),
+				FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+				FOREIGN KEY (role) REFERENCES roles(name) ON DELETE CASCADE
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+		`
+	} else {
+		query = `
+			CREATE TABLE IF NOT EXISTS user_roles (
+				user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+				role VARCHAR(100) NOT NULL REFERENCES roles(name) ON DELETE CASCADE,
+				created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				PRIMARY KEY (user_id, role)
+			);
+			CREATE INDEX IF NOT EXISTS idx_user_roles_role ON user_roles(role);
+		`
+	}
+	return db.Exec(context.Background(), query)
+}
+
+// DropUserRolesTable menghapus tabel user_roles.
+func DropUserRolesTable(db Database) error {
+	query := "DROP TABLE IF EXISTS user_roles CASCADE"
+	if db.DriverName() == "sqlite" {
+		query = "DROP TABLE IF EXISTS user_roles"
+	}
+	return db.Exec(context.Background(), query)
+}
@@ -0,0 +1,143 @@
+package dim
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// rbacSCIMGroupStore memetakan Group SCIM ke role RBAC, lihat RBAC.SCIMGroups.
+type rbacSCIMGroupStore struct {
+	rbac *RBAC
+}
+
+// SCIMGroups mengembalikan SCIMGroupStore yang memetakan setiap Group SCIM ke satu role.
+// ID dan displayName group adalah nama role, dan members adalah user yang memiliki role
+// tersebut, sehingga assignment group di identity provider langsung menjadi role di RBAC
+// (termasuk invalidasi cache). Role yang dibuat lewat SCIM belum memiliki permission; beri
+// permission dengan GrantPermission. Mengganti displayName group (rename role) tidak didukung.
+//
+// Example:
+//
+//	scim := dim.NewSCIMServer(dim.SCIMConfig{
+//	    Token:  os.Getenv("SCIM_TOKEN"),
+//	    Users:  dim.NewDatabaseSCIMUserStore(db, userStore),
+//	    Groups: rbac.SCIMGroups(),
+//	})
+func (a *RBAC) SCIMGroups() SCIMGroupStore {
+	return &rbacSCIMGroupStore{rbac: a}
+}
+
+// ListGroups mengembalikan role yang cocok dengan filter, diurutkan berdasarkan nama.
+func (s *rbacSCIMGroupStore) ListGroups(ctx context.Context, query SCIMListQuery) ([]*SCIMGroup, int, error) {
+	roles, err := s.rbac.Roles(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var matched []*SCIMGroup
+	for _, role := range roles {
+		group, err := s.group(ctx, role.Name)
+		if err != nil {
+			return nil, 0, err
+		}
+		if query.Filter.Match(group.Lookup) {
+			matched = append(matched, group)
+		}
+	}
+
+	start, end := query.Window(len(matched))
+	return matched[start:end], len(matched), nil
+}
+
+// GetGroup mengambil role berdasarkan nama.
+func (s *rbacSCIMGroupStore) GetGroup(ctx context.Context, id string) (*SCIMGroup, error) {
+	if _, err := s.rbac.store.FindRole(ctx, id); err != nil {
+		return nil, scimRoleError(err)
+	}
+	return s.group(ctx, id)
+}
+
+// CreateGroup membuat role baru bernama displayName dan memberikannya ke semua member.
+func (s *rbacSCIMGroupStore) CreateGroup(ctx context.Context, group *SCIMGroup) error {
+	name := strings.TrimSpace(group.DisplayName)
+	if _, err := s.rbac.store.FindRole(ctx, name); err == nil {
+		return ErrSCIMConflict
+	} else if !errors.Is(err, ErrRoleNotFound) {
+		return err
+	}
+
+	if _, err := s.rbac.SaveRole(ctx, name, ""); err != nil {
+		return err
+	}
+	group.ID = name
+	return s.syncMembers(ctx, name, group.Members)
+}
+
+// ReplaceGroup menyamakan user yang memiliki role dengan members group.
+func (s *rbacSCIMGroupStore) ReplaceGroup(ctx context.Context, group *SCIMGroup) error {
+	if _, err := s.rbac.store.FindRole(ctx, group.ID); err != nil {
+		return scimRoleError(err)
+	}
+	if group.DisplayName != group.ID {
+		return fmt.Errorf("scim: renaming role %q to %q is not supported", group.ID, group.DisplayName)
+	}
+	return s.syncMembers(ctx, group.ID, group.Members)
+}
+
+// DeleteGroup menghapus role beserta permission dan assignment-nya.
+func (s *rbacSCIMGroupStore) DeleteGroup(ctx context.Context, id string) error {
+	if _, err := s.rbac.store.FindRole(ctx, id); err != nil {
+		return scimRoleError(err)
+	}
+	return s.rbac.DeleteRole(ctx, id)
+}
+
+func (s *rbacSCIMGroupStore) group(ctx context.Context, role string) (*SCIMGroup, error) {
+	userIDs, err := s.rbac.RoleUsers(ctx, role)
+	if err != nil {
+		return nil, err
+	}
+	group := &SCIMGroup{Schemas: []string{SCIMSchemaGroup}, ID: role, DisplayName: role}
+	for _, userID := range userIDs {
+		group.Members = append(group.Members, SCIMMultiValue{Value: userID})
+	}
+	return group, nil
+}
+
+// syncMembers memberikan role ke member baru dan mencabutnya dari user yang tidak lagi menjadi member.
+func (s *rbacSCIMGroupStore) syncMembers(ctx context.Context, role string, members []SCIMMultiValue) error {
+	current, err := s.rbac.RoleUsers(ctx, role)
+	if err != nil {
+		return err
+	}
+
+	desired := make([]string, 0, len(members))
+	for _, member := range members {
+		desired = append(desired, member.Value)
+	}
+	for _, userID := range desired {
+		if !slices.Contains(current, userID) {
+			if err := s.rbac.AssignRole(ctx, userID, role); err != nil {
+				return err
+			}
+		}
+	}
+	for _, userID := range current {
+		if !slices.Contains(desired, userID) {
+			if err := s.rbac.RemoveRole(ctx, userID, role); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func scimRoleError(err error) error {
+	if errors.Is(err, ErrRoleNotFound) {
+		return ErrSCIMNotFound
+	}
+	return err
+}
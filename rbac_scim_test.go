@@ -0,0 +1,91 @@
+package dim
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestRBAC_SCIMGroups(t *testing.T) {
+	db, err := NewSQLiteDatabase(DatabaseConfig{Database: ":memory:"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	migrations := append(GetUserMigrations(), GetSCIMMigrations()...)
+	if err := RunMigrations(db, append(migrations, GetRBACMigrations()...)); err != nil {
+		t.Fatalf("Migration failed: %v", err)
+	}
+
+	ctx := context.Background()
+	users := NewDatabaseSCIMUserStore(db, NewDatabaseAuthUserStore(db))
+	rbac := NewRBAC(NewDatabasePermissionStore(db))
+	router := NewRouter()
+	NewSCIMServer(SCIMConfig{Token: scimTestToken, Users: users, Groups: rbac.SCIMGroups()}).Register(router.Group("/scim/v2"))
+	router.Build()
+
+	alice := &SCIMUser{UserName: "alice@example.com", Active: true}
+	bob := &SCIMUser{UserName: "bob@example.com", Active: true}
+	if err := users.CreateUser(ctx, alice); err != nil {
+		t.Fatal(err)
+	}
+	if err := users.CreateUser(ctx, bob); err != nil {
+		t.Fatal(err)
+	}
+
+	rec, group := scimRequest(t, router, http.MethodPost, "/scim/v2/Groups", `{"displayName": "admins", "members": [{"value": "`+alice.ID+`"}]}`)
+	if rec.Code != http.StatusCreated || group["id"] != "admins" {
+		t.Fatalf("expected 201 with role name as id, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if ok, err := rbac.HasRole(ctx, alice.ID, "admins"); err != nil || !ok {
+		t.Fatalf("expected alice to have role admins, got %v %v", ok, err)
+	}
+
+	rec, _ = scimRequest(t, router, http.MethodPost, "/scim/v2/Groups", `{"displayName": "admins"}`)
+	if rec.Code != http.StatusConflict {
+		t.Errorf("expected 409 for existing role, got %d", rec.Code)
+	}
+
+	rec, _ = scimRequest(t, router, http.MethodPatch, "/scim/v2/Groups/admins", `{
+		"Operations": [
+			{"op": "add", "path": "members", "value": [{"value": "`+bob.ID+`"}]},
+			{"op": "remove", "path": "members[value eq \"`+alice.ID+`\"]"}
+		]
+	}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if roles, _ := rbac.UserRoles(ctx, alice.ID); len(roles) != 0 {
+		t.Errorf("expected alice to lose admins, got %v", roles)
+	}
+	if roles, _ := rbac.UserRoles(ctx, bob.ID); len(roles) != 1 || roles[0] != "admins" {
+		t.Errorf("expected bob to have admins, got %v", roles)
+	}
+
+	rec, list := scimRequest(t, router, http.MethodGet, `/scim/v2/Groups?filter=displayName%20eq%20%22admins%22`, "")
+	resources, _ := list["Resources"].([]interface{})
+	if rec.Code != http.StatusOK || len(resources) != 1 {
+		t.Fatalf("expected one group, got %d %v", rec.Code, list)
+	}
+	members, _ := resources[0].(map[string]interface{})["members"].([]interface{})
+	if len(members) != 1 || members[0].(map[string]interface{})["value"] != bob.ID {
+		t.Errorf("unexpected members: %v", members)
+	}
+
+	rec, _ = scimRequest(t, router, http.MethodPut, "/scim/v2/Groups/admins", `{"displayName": "superadmins"}`)
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("expected rename to be rejected, got %d", rec.Code)
+	}
+
+	rec, _ = scimRequest(t, router, http.MethodDelete, "/scim/v2/Groups/admins", "")
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", rec.Code)
+	}
+	if ok, _ := rbac.HasRole(ctx, bob.ID, "admins"); ok {
+		t.Error("expected role to be removed with the group")
+	}
+	rec, _ = scimRequest(t, router, http.MethodGet, "/scim/v2/Groups/admins", "")
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 after delete, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,349 @@
+package dim
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DatabasePermissionStore is the SQL implementation of PermissionStore (PostgreSQL, MySQL & SQLite).
+type DatabasePermissionStore struct {
+	db Database
+}
+
+// NewDatabasePermissionStore creates a new SQL permission store.
+// Requires the tables created by GetRBACMigrations.
+func NewDatabasePermissionStore(db Database) *DatabasePermissionStore {
+	return &DatabasePermissionStore{db: db}
+}
+
+// SaveRole inserts a role or updates the description of an existing one.
+func (s *DatabasePermissionStore) SaveRole(ctx context.Context, role *Role) error {
+	now := time.Now().UTC().Truncate(time.Second)
+	var err error
+	if s.db.DriverName() == "mysql" {
+		// roles has no AUTO_INCREMENT id to emulate RETURNING; read created_at back instead.
+		err = s.db.WithTx(ctx, func(ctx context.Context, tx Tx) error {
+			query := `INSERT INTO roles (name, description, created_at)
+				 VALUES ($1, $2, $3)
+				 ON DUPLICATE KEY UPDATE description = $2`
+			if err := tx.Exec(ctx, query, role.Name, role.Description, now); err != nil {
+				return err
+			}
+			return tx.QueryRow(ctx, `SELECT created_at FROM roles WHERE name = $1`, role.Name).Scan(&role.CreatedAt)
+		})
+	} else {
+		query := `INSERT INTO roles (name, description, created_at)
+			 VALUES ($1, $2, $3)
+			 ON CONFLICT (name) DO UPDATE SET description = excluded.description
+			 RETURNING created_at`
+		err = s.db.QueryRow(ctx, s.db.Rebind(query), role.Name, role.Description, now).Scan(&role.CreatedAt)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save role: %w", err)
+	}
+
+	return nil
+}
+
+// FindRole finds a role by name.
+func (s *DatabasePermissionStore) FindRole(ctx context.Context, name string) (*Role, error) {
+	role := &Role{}
+	query := `SELECT name, description, created_at FROM roles WHERE name = $1`
+
+	err := s.db.QueryRow(ctx, s.db.Rebind(query), name).Scan(&role.Name, &role.Description, &role.CreatedAt)
+	if err != nil {
+		if isNoRows(err) {
+			return nil, ErrRoleNotFound
+		}
+		return nil, fmt.Errorf("failed to find role: %w", err)
+	}
+
+	return role, nil
+}
+
+// ListRoles lists all roles ordered by name.
+func (s *DatabasePermissionStore) ListRoles(ctx context.Context) ([]*Role, error) {
+	rows, err := s.db.Query(ctx, `SELECT name, description, created_at FROM roles ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list roles: %w", err)
+	}
+	defer rows.Close()
+
+	var roles []*Role
+	for rows.Next() {
+		role := &Role{}
+		if err := rows.Scan(&role.Name, &role.Description, &role.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan role: %w", err)
+		}
+		roles = append(roles, role)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list roles: %w", err)
+	}
+
+	return roles, nil
+}
+
+// DeleteRole deletes a role together with its permissions and user assignments.
+func (s *DatabasePermissionStore) DeleteRole(ctx context.Context, name string) error {
+	if err := s.db.Exec(ctx, s.db.Rebind(`DELETE FROM roles WHERE name = $1`), name); err != nil {
+		return fmt.Errorf("failed to delete role: %w", err)
+	}
+	return nil
+}
+
+// GrantPermission adds a permission to a role. Granting an existing permission is a no-op.
+func (s *DatabasePermissionStore) GrantPermission(ctx context.Context, role, permission string) error {
+	query := `INSERT INTO role_permissions (role, permission) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+	if s.db.DriverName() == "mysql" {
+		query = `INSERT INTO role_permissions (role, permission) VALUES ($1, $2) ON DUPLICATE KEY UPDATE role = role`
+	}
+	if err := s.db.Exec(ctx, s.db.Rebind(query), role, permission); err != nil {
+		return fmt.Errorf("failed to grant permission: %w", err)
+	}
+	return nil
+}
+
+// RevokePermission removes a permission from a role.
+func (s *DatabasePermissionStore) RevokePermission(ctx context.Context, role, permission string) error {
+	query := `DELETE FROM role_permissions WHERE role = $1 AND permission = $2`
+	if err := s.db.Exec(ctx, s.db.Rebind(query), role, permission); err != nil {
+		return fmt.Errorf("failed to revoke permission: %w", err)
+	}
+	return nil
+}
+
+// RolePermissions lists the permissions of a role ordered by name.
+func (s *DatabasePermissionStore) RolePermissions(ctx context.Context, role string) ([]string, error) {
+	query := `SELECT permission FROM role_permissions WHERE role = $1 ORDER BY permission`
+	return s.listStrings(ctx, "role permissions", query, role)
+}
+
+// AssignRole gives a role to a user. Assigning an existing role is a no-op.
+func (s *DatabasePermissionStore) AssignRole(ctx context.Context, userID, role string) error {
+	query := `INSERT INTO user_roles (user_id, role, created_at) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`
+	if s.db.DriverName() == "mysql" {
+		query = `INSERT INTO user_roles (user_id, role, created_at) VALUES ($1, $2, $3) ON DUPLICATE KEY UPDATE role = role`
+	}
+	if err := s.db.Exec(ctx, s.db.Rebind(query), userID, role, time.Now().UTC().Truncate(time.Second)); err != nil {
+		return fmt.Errorf("failed to assign role: %w", err)
+	}
+	return nil
+}
+
+// RemoveRole takes a role away from a user.
+func (s *DatabasePermissionStore) RemoveRole(ctx context.Context, userID, role string) error {
+	query := `DELETE FROM user_roles WHERE user_id = $1 AND role = $2`
+	if err := s.db.Exec(ctx, s.db.Rebind(query), userID, role); err != nil {
+		return fmt.Errorf("failed to remove role: %w", err)
+	}
+	return nil
+}
+
+// UserRoles lists the roles of a user ordered by name.
+func (s *DatabasePermissionStore) UserRoles(ctx context.Context, userID string) ([]string, error) {
+	query := `SELECT role FROM user_roles WHERE user_id = $1 ORDER BY role`
+	return s.listStrings(ctx, "user roles", query, userID)
+}
+
+// RoleUsers lists the IDs of the users holding a role.
+func (s *DatabasePermissionStore) RoleUsers(ctx context.Context, role string) ([]string, error) {
+	query := `SELECT user_id FROM user_roles WHERE role = $1 ORDER BY user_id`
+	return s.listStrings(ctx, "role users", query, role)
+}
+
+// UserPermissions lists the permissions granted to a user through all of its roles.
+func (s *DatabasePermissionStore) UserPermissions(ctx context.Context, userID string) ([]string, error) {
+	query := `SELECT DISTINCT rp.permission FROM role_permissions rp
+		 JOIN user_roles ur ON ur.role = rp.role
+		 WHERE ur.user_id = $1 ORDER BY rp.permission`
+	return s.listStrings(ctx, "user permissions", query, userID)
+}
+
+func (s *DatabasePermissionStore) listStrings(ctx context.Context, what, query string, arg string) ([]string, error) {
+	rows, err := s.db.Query(ctx, s.db.Rebind(query), arg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", what, err)
+	}
+	defer rows.Close()
+
+	var values []string
+	for rows.Next() {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			return nil, fmt.Errorf("failed to scan %s: %w", what, err)
+		}
+		values = append(values, value)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", what, err)
+	}
+
+	return values, nil
+}
+
+// MockPermissionStore is a mock implementation for testing
+type MockPermissionStore struct {
+	mu          sync.RWMutex
+	roles       map[string]*Role
+	permissions map[string]map[string]bool // role -> permission
+	userRoles   map[string]map[string]bool // userID -> role
+}
+
+// NewMockPermissionStore creates a new mock permission store.
+func NewMockPermissionStore() *MockPermissionStore {
+	return &MockPermissionStore{
+		roles:       make(map[string]*Role),
+		permissions: make(map[string]map[string]bool),
+		userRoles:   make(map[string]map[string]bool),
+	}
+}
+
+// SaveRole inserts or updates a role in mock store.
+func (s *MockPermissionStore) SaveRole(ctx context.Context, role *Role) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if existing, exists := s.roles[role.Name]; exists {
+		role.CreatedAt = existing.CreatedAt
+	} else {
+		role.CreatedAt = time.Now()
+	}
+	copied := *role
+	s.roles[role.Name] = &copied
+	return nil
+}
+
+// FindRole finds a role in mock store.
+func (s *MockPermissionStore) FindRole(ctx context.Context, name string) (*Role, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	role, exists := s.roles[name]
+	if !exists {
+		return nil, ErrRoleNotFound
+	}
+	copied := *role
+	return &copied, nil
+}
+
+// ListRoles lists roles in mock store ordered by name.
+func (s *MockPermissionStore) ListRoles(ctx context.Context) ([]*Role, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	roles := make([]*Role, 0, len(s.roles))
+	for _, role := range s.roles {
+		copied := *role
+		roles = append(roles, &copied)
+	}
+	sort.Slice(roles, func(i, j int) bool { return roles[i].Name < roles[j].Name })
+	return roles, nil
+}
+
+// DeleteRole deletes a role and its assignments in mock store.
+func (s *MockPermissionStore) DeleteRole(ctx context.Context, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.roles, name)
+	delete(s.permissions, name)
+	for _, roles := range s.userRoles {
+		delete(roles, name)
+	}
+	return nil
+}
+
+// GrantPermission adds a permission to a role in mock store.
+func (s *MockPermissionStore) GrantPermission(ctx context.Context, role, permission string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.roles[role]; !exists {
+		return ErrRoleNotFound
+	}
+	if s.permissions[role] == nil {
+		s.permissions[role] = make(map[string]bool)
+	}
+	s.permissions[role][permission] = true
+	return nil
+}
+
+// RevokePermission removes a permission from a role in mock store.
+func (s *MockPermissionStore) RevokePermission(ctx context.Context, role, permission string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.permissions[role], permission)
+	return nil
+}
+
+// RolePermissions lists the permissions of a role in mock store.
+func (s *MockPermissionStore) RolePermissions(ctx context.Context, role string) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return sortedKeys(s.permissions[role]), nil
+}
+
+// AssignRole gives a role to a user in mock store.
+func (s *MockPermissionStore) AssignRole(ctx context.Context, userID, role string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.roles[role]; !exists {
+		return ErrRoleNotFound
+	}
+	if s.userRoles[userID] == nil {
+		s.userRoles[userID] = make(map[string]bool)
+	}
+	s.userRoles[userID][role] = true
+	return nil
+}
+
+// RemoveRole takes a role away from a user in mock store.
+func (s *MockPermissionStore) RemoveRole(ctx context.Context, userID, role string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.userRoles[userID], role)
+	return nil
+}
+
+// UserRoles lists the roles of a user in mock store.
+func (s *MockPermissionStore) UserRoles(ctx context.Context, userID string) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return sortedKeys(s.userRoles[userID]), nil
+}
+
+// RoleUsers lists the users holding a role in mock store.
+func (s *MockPermissionStore) RoleUsers(ctx context.Context, role string) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	users := make(map[string]bool)
+	for userID, roles := range s.userRoles {
+		if roles[role] {
+			users[userID] = true
+		}
+	}
+	return sortedKeys(users), nil
+}
+
+// UserPermissions lists the permissions of a user through its roles in mock store.
+func (s *MockPermissionStore) UserPermissions(ctx context.Context, userID string) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	permissions := make(map[string]bool)
+	for role := range s.userRoles[userID] {
+		for permission := range s.permissions[role] {
+			permissions[permission] = true
+		}
+	}
+	return sortedKeys(permissions), nil
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,243 @@
+package dim
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+)
+
+// countingPermissionStore menghitung query UserRoles untuk memeriksa cache RBAC.
+type countingPermissionStore struct {
+	*MockPermissionStore
+	lookups int
+}
+
+func (s *countingPermissionStore) UserRoles(ctx context.Context, userID string) ([]string, error) {
+	s.lookups++
+	return s.MockPermissionStore.UserRoles(ctx, userID)
+}
+
+func newTestRBAC(t *testing.T, store PermissionStore) *RBAC {
+	t.Helper()
+	ctx := context.Background()
+	rbac := NewRBAC(store)
+	for _, role := range []string{"admin", "editor"} {
+		if _, err := rbac.SaveRole(ctx, role, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := rbac.GrantPermission(ctx, "admin", "users.*"); err != nil {
+		t.Fatal(err)
+	}
+	if err := rbac.GrantPermission(ctx, "editor", "posts.update"); err != nil {
+		t.Fatal(err)
+	}
+	if err := rbac.AssignRole(ctx, "1", "editor"); err != nil {
+		t.Fatal(err)
+	}
+	return rbac
+}
+
+func TestRBAC_RolesAndPermissions(t *testing.T) {
+	ctx := context.Background()
+	store := &countingPermissionStore{MockPermissionStore: NewMockPermissionStore()}
+	rbac := newTestRBAC(t, store)
+
+	if ok, _ := rbac.Can(ctx, "1", "posts.update"); !ok {
+		t.Error("editor should be able to update posts")
+	}
+	if ok, _ := rbac.Can(ctx, "1", "users.delete"); ok {
+		t.Error("editor should not be able to delete users")
+	}
+	if ok, _ := rbac.HasRole(ctx, "1", "admin", "editor"); !ok {
+		t.Error("HasRole should match any of the roles")
+	}
+	if store.lookups != 1 {
+		t.Errorf("store lookups = %d, want 1 (cached)", store.lookups)
+	}
+
+	// Assign role langsung berlaku meskipun entri lama masih di cache
+	if err := rbac.AssignRole(ctx, "1", "admin"); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := rbac.Can(ctx, "1", "users.delete"); !ok {
+		t.Error("admin wildcard should grant users.delete")
+	}
+
+	// Perubahan permission role mengabaikan cache semua user
+	if err := rbac.RevokePermission(ctx, "admin", "users.*"); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := rbac.Can(ctx, "1", "users.delete"); ok {
+		t.Error("revoked permission should not be granted from cache")
+	}
+
+	if err := rbac.AssignRole(ctx, "1", "missing"); !errors.Is(err, ErrRoleNotFound) {
+		t.Errorf("AssignRole unknown role error = %v", err)
+	}
+	if err := rbac.GrantPermission(ctx, "missing", "x"); !errors.Is(err, ErrRoleNotFound) {
+		t.Errorf("GrantPermission unknown role error = %v", err)
+	}
+
+	if err := rbac.DeleteRole(ctx, "editor"); err != nil {
+		t.Fatal(err)
+	}
+	if roles, _ := rbac.UserRoles(ctx, "1"); !slices.Equal(roles, []string{"admin"}) {
+		t.Errorf("roles after DeleteRole = %v", roles)
+	}
+}
+
+func TestRBAC_Middleware(t *testing.T) {
+	ctx := context.Background()
+	rbac := newTestRBAC(t, NewMockPermissionStore())
+
+	userStore := NewMockUserStore()
+	userStore.AddUser(&MockUser{ID: "1", Email: "editor@example.com"})
+	service, err := NewAuthService(userStore, NewMockTokenStore(), nil, &JWTConfig{
+		HMACSecret:         "test-secret",
+		SigningMethod:      "HS256",
+		AccessTokenExpiry:  15 * time.Minute,
+		RefreshTokenExpiry: 7 * 24 * time.Hour,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	service.WithClaimsProvider(MergeClaimsProviders(
+		func(ctx context.Context, user Authenticatable) (map[string]interface{}, error) {
+			return map[string]interface{}{"tenant": "acme"}, nil
+		},
+		rbac.ClaimsProvider(),
+	))
+	user, _ := userStore.FindByID(ctx, "1")
+	access, _, err := service.issueTokens(ctx, user, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, _ := service.tokenManager.VerifyToken(access)
+	if claims["tenant"] != "acme" || !slices.Equal(claimStrings(claims[RolesClaim]), []string{"editor"}) {
+		t.Fatalf("unexpected claims: %v", claims)
+	}
+
+	ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }
+	auth := RequireAuth(service.tokenManager, nil)
+	router := NewRouter()
+	router.Get("/posts", ok, auth, RequireRole("admin", "editor"), RequirePermission("posts.update"))
+	router.Get("/admin", ok, auth, RequireRole("admin"))
+	router.Get("/users", ok, auth, RequirePermission("users.delete"))
+	router.Get("/live", ok, auth, rbac.RequireRole("admin"))
+	router.Get("/anonymous", ok, RequirePermission("posts.update"))
+
+	request := func(path string) int {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Authorization", "Bearer "+access)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	for path, want := range map[string]int{
+		"/posts":     http.StatusOK,
+		"/admin":     http.StatusForbidden,
+		"/users":     http.StatusForbidden,
+		"/live":      http.StatusForbidden,
+		"/anonymous": http.StatusUnauthorized,
+	} {
+		if got := request(path); got != want {
+			t.Errorf("GET %s = %d, want %d", path, got, want)
+		}
+	}
+
+	// RBAC.RequireRole membaca store (via cache), bukan token
+	if err := rbac.AssignRole(ctx, "1", "admin"); err != nil {
+		t.Fatal(err)
+	}
+	if got := request("/live"); got != http.StatusOK {
+		t.Errorf("GET /live after AssignRole = %d, want 200", got)
+	}
+	if got := request("/admin"); got != http.StatusForbidden {
+		t.Errorf("GET /admin with old token = %d, want 403", got)
+	}
+}
+
+func TestPermissionMatches(t *testing.T) {
+	cases := []struct {
+		granted, required string
+		want              bool
+	}{
+		{"users.delete", "users.delete", true},
+		{"*", "users.delete", true},
+		{"users.*", "users.delete", true},
+		{"posts:*", "posts:manage", true},
+		{"users.*", "posts.delete", false},
+		{"users.delete", "users.update", false},
+	}
+	for _, c := range cases {
+		if got := PermissionMatches(c.granted, c.required); got != c.want {
+			t.Errorf("PermissionMatches(%q, %q) = %v, want %v", c.granted, c.required, got, c.want)
+		}
+	}
+}
+
+func TestDatabasePermissionStore_SQLite(t *testing.T) {
+	db := newTestSQLiteAuthDB(t)
+	if err := RunMigrations(db, GetRBACMigrations()); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if err := db.Exec(ctx, "INSERT INTO users (id, email, password) VALUES ('u-1', 'ana@example.com', 'hash')"); err != nil {
+		t.Fatal(err)
+	}
+	store := NewDatabasePermissionStore(db)
+	rbac := NewRBAC(store)
+
+	role, err := rbac.SaveRole(ctx, "admin", "Administrator")
+	if err != nil || role.CreatedAt.IsZero() {
+		t.Fatalf("SaveRole = %+v, %v", role, err)
+	}
+	rbac.SaveRole(ctx, "editor", "")
+	rbac.GrantPermission(ctx, "admin", "users.delete")
+	rbac.GrantPermission(ctx, "admin", "posts.update")
+	rbac.GrantPermission(ctx, "admin", "posts.update")
+	rbac.GrantPermission(ctx, "editor", "posts.update")
+	if err := rbac.AssignRole(ctx, "u-1", "admin"); err != nil {
+		t.Fatal(err)
+	}
+	if err := rbac.AssignRole(ctx, "u-1", "editor"); err != nil {
+		t.Fatal(err)
+	}
+
+	if roles, err := store.UserRoles(ctx, "u-1"); err != nil || !slices.Equal(roles, []string{"admin", "editor"}) {
+		t.Errorf("UserRoles = %v, %v", roles, err)
+	}
+	if permissions, err := store.UserPermissions(ctx, "u-1"); err != nil || !slices.Equal(permissions, []string{"posts.update", "users.delete"}) {
+		t.Errorf("UserPermissions = %v, %v", permissions, err)
+	}
+	if roles, err := store.ListRoles(ctx); err != nil || len(roles) != 2 || roles[0].Description != "Administrator" {
+		t.Errorf("ListRoles = %+v, %v", roles, err)
+	}
+	if _, err := store.FindRole(ctx, "missing"); !errors.Is(err, ErrRoleNotFound) {
+		t.Errorf("FindRole error = %v", err)
+	}
+
+	if err := rbac.RevokePermission(ctx, "admin", "users.delete"); err != nil {
+		t.Fatal(err)
+	}
+	if err := rbac.RemoveRole(ctx, "u-1", "editor"); err != nil {
+		t.Fatal(err)
+	}
+	if permissions, _ := rbac.UserPermissions(ctx, "u-1"); !slices.Equal(permissions, []string{"posts.update"}) {
+		t.Errorf("UserPermissions after revoke = %v", permissions)
+	}
+
+	// Menghapus role juga menghapus assignment-nya
+	if err := rbac.DeleteRole(ctx, "admin"); err != nil {
+		t.Fatal(err)
+	}
+	if roles, _ := rbac.UserRoles(ctx, "u-1"); len(roles) != 0 {
+		t.Errorf("UserRoles after DeleteRole = %v", roles)
+	}
+}
@@ -0,0 +1,378 @@
+package dim
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// ErrRedisClosed dikembalikan RedisClient.Do setelah client ditutup.
+var ErrRedisClosed = errors.New("redis: client is closed")
+
+// RedisError adalah error reply dari server Redis (misal "WRONGTYPE ..."). Koneksi tetap
+// dapat dipakai kembali setelah error jenis ini.
+type RedisError string
+
+func (e RedisError) Error() string { return "redis: " + string(e) }
+
+// RedisCommander menjalankan satu command Redis. Reply dikembalikan sebagai string (simple dan
+// bulk string), int64 (integer), []any (array), atau nil (nil reply).
+// *RedisClient mengimplementasikan interface ini; client lain (misal go-redis) dapat diadaptasi
+// dengan RedisCommanderFunc.
+type RedisCommander interface {
+	Do(ctx context.Context, args ...string) (any, error)
+}
+
+// RedisCommanderFunc mengadaptasi fungsi menjadi RedisCommander.
+//
+// Example (go-redis):
+//
+//	client := dim.RedisCommanderFunc(func(ctx context.Context, args ...string) (any, error) {
+//	    cmd := make([]any, len(args))
+//	    for i, arg := range args {
+//	        cmd[i] = arg
+//	    }
+//	    reply, err := rdb.Do(ctx, cmd...).Result()
+//	    if errors.Is(err, redis.Nil) {
+//	        return nil, nil
+//	    }
+//	    return reply, err
+//	})
+type RedisCommanderFunc func(ctx context.Context, args ...string) (any, error)
+
+// Do memanggil f(ctx, args...).
+func (f RedisCommanderFunc) Do(ctx context.Context, args ...string) (any, error) {
+	return f(ctx, args...)
+}
+
+// RedisPoolStats adalah ringkasan kondisi connection pool RedisClient.
+type RedisPoolStats struct {
+	// Open adalah jumlah koneksi yang sedang terbuka (dipakai maupun idle).
+	Open int
+	// Idle adalah jumlah koneksi yang menunggu di pool.
+	Idle int
+	// Dials adalah jumlah koneksi baru yang pernah dibuka.
+	Dials uint64
+}
+
+// RedisClient adalah client Redis (protokol RESP2) dengan connection pool, tanpa dependency
+// eksternal. Koneksi dibuka saat dibutuhkan sampai PoolSize, dipakai ulang setelah command
+// selesai, dan ditutup jika idle lebih lama dari IdleTimeout. Thread-safe.
+type RedisClient struct {
+	config RedisConfig
+	idle   chan *redisConn
+	slots  chan struct{} // satu slot per koneksi terbuka, membatasi jumlah koneksi ke PoolSize
+	dials  atomic.Uint64
+	closed atomic.Bool
+}
+
+type redisConn struct {
+	conn   net.Conn
+	rd     *bufio.Reader
+	wr     *bufio.Writer
+	usedAt time.Time
+}
+
+// NewRedisClient membuat RedisClient dari RedisConfig. Koneksi belum dibuka sampai command
+// pertama; gunakan Ping untuk memastikan Redis dapat dijangkau saat startup.
+//
+// Parameters:
+//   - config: konfigurasi koneksi, biasanya cfg.Redis dari LoadConfig
+//
+// Returns:
+//   - *RedisClient: client yang siap dipakai
+//   - error: error jika REDIS_ADDR kosong
+//
+// Example:
+//
+//	rdb, err := dim.NewRedisClient(cfg.Redis)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer rdb.Close()
+//	if err := rdb.Ping(ctx); err != nil {
+//	    log.Fatal(err)
+//	}
+func NewRedisClient(config RedisConfig) (*RedisClient, error) {
+	if config.Addr == "" {
+		return nil, fmt.Errorf("REDIS_ADDR is required")
+	}
+	if config.PoolSize < 1 {
+		config.PoolSize = 10
+	}
+	if config.DialTimeout == 0 {
+		config.DialTimeout = 5 * time.Second
+	}
+	return &RedisClient{
+		config: config,
+		idle:   make(chan *redisConn, config.PoolSize),
+		slots:  make(chan struct{}, config.PoolSize),
+	}, nil
+}
+
+// Do mengimplementasikan RedisCommander. Jika pool penuh, Do menunggu koneksi kembali ke pool
+// sampai ctx selesai.
+//
+// Example:
+//
+//	reply, err := rdb.Do(ctx, "SET", "greeting", "halo", "EX", "60")
+func (c *RedisClient) Do(ctx context.Context, args ...string) (any, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("redis: empty command")
+	}
+	if c.closed.Load() {
+		return nil, ErrRedisClosed
+	}
+
+	cn, err := c.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Batalnya ctx memutus I/O yang sedang menunggu; koneksi lalu dibuang karena state-nya tidak pasti.
+	stop := context.AfterFunc(ctx, func() { cn.conn.SetDeadline(time.Unix(1, 0)) })
+	reply, err := c.roundTrip(ctx, cn, args)
+	canceled := !stop()
+
+	var redisErr RedisError
+	c.put(cn, !canceled && (err == nil || errors.As(err, &redisErr)))
+	if err != nil && canceled {
+		return nil, ctx.Err()
+	}
+	return reply, err
+}
+
+// Ping mengirim PING dan memeriksa balasan PONG.
+func (c *RedisClient) Ping(ctx context.Context) error {
+	return RedisHealthCheck(c)(ctx)
+}
+
+// Stats mengembalikan kondisi connection pool saat ini.
+func (c *RedisClient) Stats() RedisPoolStats {
+	return RedisPoolStats{
+		Open:  len(c.slots),
+		Idle:  len(c.idle),
+		Dials: c.dials.Load(),
+	}
+}
+
+// Close menutup koneksi idle. Koneksi yang sedang dipakai ditutup saat command-nya selesai.
+func (c *RedisClient) Close() error {
+	if c.closed.Swap(true) {
+		return nil
+	}
+	for {
+		select {
+		case cn := <-c.idle:
+			c.discard(cn)
+		default:
+			return nil
+		}
+	}
+}
+
+// get mengambil koneksi idle yang masih segar atau membuka koneksi baru jika slot tersedia.
+func (c *RedisClient) get(ctx context.Context) (*redisConn, error) {
+	for {
+		select {
+		case cn := <-c.idle:
+			if c.stale(cn) {
+				c.discard(cn)
+				continue
+			}
+			return cn, nil
+		default:
+		}
+
+		select {
+		case cn := <-c.idle:
+			if c.stale(cn) {
+				c.discard(cn)
+				continue
+			}
+			return cn, nil
+		case c.slots <- struct{}{}:
+			cn, err := c.dial(ctx)
+			if err != nil {
+				<-c.slots
+				return nil, err
+			}
+			return cn, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// put mengembalikan koneksi ke pool, atau menutupnya jika tidak sehat atau client sudah ditutup.
+func (c *RedisClient) put(cn *redisConn, healthy bool) {
+	if !healthy || c.closed.Load() {
+		c.discard(cn)
+		return
+	}
+	cn.usedAt = time.Now()
+	c.idle <- cn
+}
+
+func (c *RedisClient) discard(cn *redisConn) {
+	cn.conn.Close()
+	<-c.slots
+}
+
+func (c *RedisClient) stale(cn *redisConn) bool {
+	return c.config.IdleTimeout > 0 && time.Since(cn.usedAt) > c.config.IdleTimeout
+}
+
+// dial membuka koneksi baru lalu menjalankan AUTH dan SELECT sesuai konfigurasi.
+func (c *RedisClient) dial(ctx context.Context) (*redisConn, error) {
+	dialer := &net.Dialer{Timeout: c.config.DialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", c.config.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("redis: dial %s: %w", c.config.Addr, err)
+	}
+	if c.config.TLS {
+		host, _, _ := net.SplitHostPort(c.config.Addr)
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12})
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("redis: tls handshake: %w", err)
+		}
+		conn = tlsConn
+	}
+	c.dials.Add(1)
+
+	cn := &redisConn{conn: conn, rd: bufio.NewReader(conn), wr: bufio.NewWriter(conn)}
+	var setup [][]string
+	if c.config.Password != "" {
+		if c.config.Username != "" {
+			setup = append(setup, []string{"AUTH", c.config.Username, c.config.Password})
+		} else {
+			setup = append(setup, []string{"AUTH", c.config.Password})
+		}
+	}
+	if c.config.DB != 0 {
+		setup = append(setup, []string{"SELECT", strconv.Itoa(c.config.DB)})
+	}
+	for _, cmd := range setup {
+		if _, err := c.roundTrip(ctx, cn, cmd); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("redis: %s failed: %w", cmd[0], err)
+		}
+	}
+	return cn, nil
+}
+
+// roundTrip menulis satu command lalu membaca reply-nya dengan batas waktu dari konfigurasi
+// atau deadline ctx, mana yang lebih dulu.
+func (c *RedisClient) roundTrip(ctx context.Context, cn *redisConn, args []string) (any, error) {
+	cn.conn.SetWriteDeadline(c.deadline(ctx, c.config.WriteTimeout))
+	if err := writeRedisCommand(cn.wr, args); err != nil {
+		return nil, err
+	}
+	cn.conn.SetReadDeadline(c.deadline(ctx, c.config.ReadTimeout))
+	return readRedisReply(cn.rd)
+}
+
+func (c *RedisClient) deadline(ctx context.Context, timeout time.Duration) time.Time {
+	var deadline time.Time
+	if timeout > 0 {
+		deadline = time.Now().Add(timeout)
+	}
+	if d, ok := ctx.Deadline(); ok && (deadline.IsZero() || d.Before(deadline)) {
+		deadline = d
+	}
+	return deadline
+}
+
+// writeRedisCommand meng-encode command sebagai RESP array of bulk strings.
+func writeRedisCommand(w *bufio.Writer, args []string) error {
+	fmt.Fprintf(w, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(w, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	return w.Flush()
+}
+
+// readRedisReply membaca satu reply RESP2.
+func readRedisReply(r *bufio.Reader) (any, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, fmt.Errorf("redis: malformed reply %q", line)
+	}
+	kind, body := line[0], line[1:len(line)-2]
+
+	switch kind {
+	case '+':
+		return body, nil
+	case '-':
+		return nil, RedisError(body)
+	case ':':
+		return strconv.ParseInt(body, 10, 64)
+	case '$':
+		n, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, fmt.Errorf("redis: malformed bulk length %q", body)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		return string(buf[:n]), nil
+	case '*':
+		n, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, fmt.Errorf("redis: malformed array length %q", body)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		items := make([]any, n)
+		for i := range items {
+			// Error di dalam array (misal hasil EXEC) dikembalikan sebagai elemen, bukan error Do
+			item, err := readRedisReply(r)
+			var redisErr RedisError
+			if errors.As(err, &redisErr) {
+				items[i] = redisErr
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			items[i] = item
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("redis: unknown reply type %q", kind)
+	}
+}
+
+// RedisHealthCheck membuat HealthCheckFunc yang mengirim PING ke Redis.
+//
+// Example:
+//
+//	health.Add("redis", dim.RedisHealthCheck(rdb))
+func RedisHealthCheck(client RedisCommander) HealthCheckFunc {
+	return func(ctx context.Context) error {
+		reply, err := client.Do(ctx, "PING")
+		if err != nil {
+			return err
+		}
+		if reply != "PONG" {
+			return fmt.Errorf("redis: unexpected PING reply %v", reply)
+		}
+		return nil
+	}
+}
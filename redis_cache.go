@@ -0,0 +1,136 @@
+package dim
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/atfromhome/goreus/pkg/cache"
+)
+
+// RedisCache mengimplementasikan cache.Cache dari goreus di atas Redis, sehingga store dan
+// WarmupCache yang memakai cache.InMemoryCache dapat berbagi cache antar replica cukup dengan
+// mengganti konstruktornya. Key diformat dengan fmt.Sprint dan value di-encode sebagai JSON.
+//
+// Karena interface cache.Cache tidak mengembalikan error, kegagalan Redis diperlakukan sebagai
+// cache miss (Get) atau diabaikan (Set, Delete) dan dicatat ke logger jika dipasang.
+type RedisCache[K comparable, V any] struct {
+	client     RedisCommander
+	prefix     string
+	defaultTTL time.Duration
+	logger     *Logger
+	hits       atomic.Uint64
+	misses     atomic.Uint64
+}
+
+var _ cache.Cache[string, any] = (*RedisCache[string, any])(nil)
+
+// NewRedisCache membuat RedisCache dengan prefix key dan TTL default untuk Set tanpa opsi TTL.
+//
+// Parameters:
+//   - client: *RedisClient atau client lain yang diadaptasi dengan RedisCommanderFunc
+//   - prefix: prefix key, misal "myapp:countries:"
+//   - defaultTTL: TTL default; 0 berarti entry tidak kadaluarsa
+//
+// Example:
+//
+//	countries := dim.NewRedisCache[string, Country](rdb, "myapp:countries:", 24*time.Hour)
+//	warmup.Register(dim.WarmupTask{
+//	    Name: "countries",
+//	    Load: dim.WarmupCache(countries, countryStore.LoadAll),
+//	})
+func NewRedisCache[K comparable, V any](client RedisCommander, prefix string, defaultTTL time.Duration) *RedisCache[K, V] {
+	return &RedisCache[K, V]{client: client, prefix: prefix, defaultTTL: defaultTTL}
+}
+
+// WithLogger mencatat kegagalan Redis yang tidak dapat dikembalikan lewat interface cache.Cache.
+func (c *RedisCache[K, V]) WithLogger(logger *Logger) *RedisCache[K, V] {
+	c.logger = logger
+	return c
+}
+
+// Get mengambil value untuk key. Key yang tidak ada, sudah kadaluarsa, atau gagal di-decode
+// dihitung sebagai miss.
+func (c *RedisCache[K, V]) Get(ctx context.Context, key K) (V, bool) {
+	var value V
+	reply, err := c.client.Do(ctx, "GET", c.key(key))
+	if err != nil {
+		c.logError("get", key, err)
+	}
+	data, ok := reply.(string)
+	if err != nil || !ok {
+		c.misses.Add(1)
+		return value, false
+	}
+	if err := json.Unmarshal([]byte(data), &value); err != nil {
+		c.logError("decode", key, err)
+		c.misses.Add(1)
+		return value, false
+	}
+	c.hits.Add(1)
+	return value, true
+}
+
+// Set menyimpan value dengan kadaluarsa dari cache.WithExpiresAt, cache.WithTTL, atau TTL default.
+func (c *RedisCache[K, V]) Set(ctx context.Context, key K, value V, opts ...cache.SetOption) {
+	var options cache.SetOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	ttl := c.defaultTTL
+	if !options.ExpiresAt.IsZero() {
+		ttl = time.Until(options.ExpiresAt)
+	} else if options.TTL > 0 {
+		ttl = options.TTL
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		c.logError("encode", key, err)
+		return
+	}
+	args := []string{"SET", c.key(key), string(data)}
+	if ttl != 0 {
+		ms := ttl.Milliseconds()
+		if ms <= 0 {
+			// Sudah kadaluarsa: hapus value lama agar tidak tersaji lagi
+			c.Delete(ctx, key)
+			return
+		}
+		args = append(args, "PX", strconv.FormatInt(ms, 10))
+	}
+	if _, err := c.client.Do(ctx, args...); err != nil {
+		c.logError("set", key, err)
+	}
+}
+
+// Delete menghapus key.
+func (c *RedisCache[K, V]) Delete(ctx context.Context, key K) {
+	if _, err := c.client.Do(ctx, "DEL", c.key(key)); err != nil {
+		c.logError("delete", key, err)
+	}
+}
+
+// Stats mengembalikan hit dan miss dari instance ini. Items, Capacity, dan Evictions tidak
+// diketahui karena dikelola Redis dan selalu 0.
+func (c *RedisCache[K, V]) Stats() cache.Stats {
+	return cache.Stats{Hits: c.hits.Load(), Misses: c.misses.Load()}
+}
+
+// Close tidak menutup client karena client biasanya dipakai bersama; tutup client secara terpisah.
+func (c *RedisCache[K, V]) Close() error {
+	return nil
+}
+
+func (c *RedisCache[K, V]) key(key K) string {
+	return c.prefix + fmt.Sprint(key)
+}
+
+func (c *RedisCache[K, V]) logError(op string, key K, err error) {
+	if c.logger != nil {
+		c.logger.Warn("Redis cache operation failed", "op", op, "key", c.key(key), "error", err.Error())
+	}
+}
@@ -0,0 +1,304 @@
+package dim
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/atfromhome/goreus/pkg/cache"
+)
+
+// fakeRedisServer adalah server RESP minimal untuk test: PING, AUTH, SELECT, SET (PX), GET,
+// DEL, dan EXISTS.
+type fakeRedisServer struct {
+	addr     string
+	password string
+
+	mu     sync.Mutex
+	values map[string]string
+	expiry map[string]time.Time
+	cmds   []string
+}
+
+func newFakeRedisServer(t *testing.T, password string) *fakeRedisServer {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	s := &fakeRedisServer{
+		addr:     ln.Addr().String(),
+		password: password,
+		values:   make(map[string]string),
+		expiry:   make(map[string]time.Time),
+	}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *fakeRedisServer) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	authed := s.password == ""
+	for {
+		reply, err := readRedisReply(r)
+		if err != nil {
+			return
+		}
+		items, _ := reply.([]any)
+		args := make([]string, len(items))
+		for i, item := range items {
+			args[i], _ = item.(string)
+		}
+		if len(args) == 0 {
+			return
+		}
+
+		cmd := strings.ToUpper(args[0])
+		s.mu.Lock()
+		s.cmds = append(s.cmds, cmd)
+		var out string
+		switch {
+		case cmd == "AUTH":
+			authed = args[len(args)-1] == s.password
+			out = "+OK\r\n"
+			if !authed {
+				out = "-WRONGPASS invalid password\r\n"
+			}
+		case !authed:
+			out = "-NOAUTH Authentication required.\r\n"
+		case cmd == "PING":
+			out = "+PONG\r\n"
+		case cmd == "SELECT":
+			out = "+OK\r\n"
+		case cmd == "SET":
+			s.values[args[1]] = args[2]
+			delete(s.expiry, args[1])
+			if len(args) == 5 && strings.ToUpper(args[3]) == "PX" {
+				ms, _ := strconv.Atoi(args[4])
+				s.expiry[args[1]] = time.Now().Add(time.Duration(ms) * time.Millisecond)
+			}
+			out = "+OK\r\n"
+		case cmd == "GET":
+			if value, ok := s.lookup(args[1]); ok {
+				out = fmt.Sprintf("$%d\r\n%s\r\n", len(value), value)
+			} else {
+				out = "$-1\r\n"
+			}
+		case cmd == "DEL" || cmd == "EXISTS":
+			n := 0
+			for _, key := range args[1:] {
+				if _, ok := s.lookup(key); ok {
+					n++
+					if cmd == "DEL" {
+						delete(s.values, key)
+					}
+				}
+			}
+			out = fmt.Sprintf(":%d\r\n", n)
+		default:
+			out = "-ERR unknown command '" + args[0] + "'\r\n"
+		}
+		s.mu.Unlock()
+		if _, err := conn.Write([]byte(out)); err != nil {
+			return
+		}
+	}
+}
+
+// lookup harus dipanggil dengan s.mu terkunci.
+func (s *fakeRedisServer) lookup(key string) (string, bool) {
+	if exp, ok := s.expiry[key]; ok && !time.Now().Before(exp) {
+		delete(s.values, key)
+		delete(s.expiry, key)
+	}
+	value, ok := s.values[key]
+	return value, ok
+}
+
+func (s *fakeRedisServer) ttl(key string) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if exp, ok := s.expiry[key]; ok {
+		return time.Until(exp)
+	}
+	return 0
+}
+
+func newTestRedisClient(t *testing.T, server *fakeRedisServer, poolSize int) *RedisClient {
+	t.Helper()
+	client, err := NewRedisClient(RedisConfig{
+		Addr:        server.addr,
+		Password:    server.password,
+		DB:          2,
+		PoolSize:    poolSize,
+		ReadTimeout: time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestRedisClient_PoolReusesConnections(t *testing.T) {
+	server := newFakeRedisServer(t, "s3cret")
+	client := newTestRedisClient(t, server, 2)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.Do(ctx, "SET", fmt.Sprintf("k%d", i), "v"); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	stats := client.Stats()
+	if stats.Dials > 2 || stats.Open > 2 {
+		t.Errorf("stats = %+v, want at most 2 connections", stats)
+	}
+	counts := map[string]uint64{}
+	server.mu.Lock()
+	for _, cmd := range server.cmds {
+		counts[cmd]++
+	}
+	server.mu.Unlock()
+	if counts["AUTH"] != stats.Dials || counts["SELECT"] != stats.Dials || counts["SET"] != 20 {
+		t.Errorf("commands = %v, want AUTH and SELECT once per connection", counts)
+	}
+}
+
+func TestRedisClient_Replies(t *testing.T) {
+	client := newTestRedisClient(t, newFakeRedisServer(t, ""), 1)
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Fatalf("Ping() = %v", err)
+	}
+	if reply, err := client.Do(ctx, "GET", "missing"); err != nil || reply != nil {
+		t.Errorf("GET missing = %v, %v; want nil", reply, err)
+	}
+
+	var redisErr RedisError
+	if _, err := client.Do(ctx, "BOGUS"); !errors.As(err, &redisErr) {
+		t.Errorf("err = %v, want RedisError", err)
+	}
+	// Error reply tidak membuang koneksi
+	if err := client.Ping(ctx); err != nil || client.Stats().Dials != 1 {
+		t.Errorf("Ping after error = %v, dials %d", err, client.Stats().Dials)
+	}
+
+	client.Close()
+	if _, err := client.Do(ctx, "PING"); !errors.Is(err, ErrRedisClosed) {
+		t.Errorf("err = %v, want ErrRedisClosed", err)
+	}
+}
+
+func TestRedisClient_AuthFailure(t *testing.T) {
+	server := newFakeRedisServer(t, "s3cret")
+	client, _ := NewRedisClient(RedisConfig{Addr: server.addr, Password: "wrong"})
+	defer client.Close()
+
+	if err := RedisHealthCheck(client)(context.Background()); err == nil {
+		t.Fatal("health check should fail with wrong password")
+	}
+	if open := client.Stats().Open; open != 0 {
+		t.Errorf("failed dial should release its slot, open = %d", open)
+	}
+}
+
+func TestRedisClient_Unreachable(t *testing.T) {
+	ln, _ := net.Listen("tcp", "127.0.0.1:0")
+	addr := ln.Addr().String()
+	ln.Close()
+
+	client, _ := NewRedisClient(RedisConfig{Addr: addr, DialTimeout: time.Second})
+	if err := client.Ping(context.Background()); err == nil {
+		t.Error("Ping should fail when Redis is down")
+	}
+	if _, err := NewRedisClient(RedisConfig{}); err == nil {
+		t.Error("empty Addr should be rejected")
+	}
+}
+
+func TestRedisBlocklist(t *testing.T) {
+	server := newFakeRedisServer(t, "")
+	blocklist := NewRedisBlocklist(newTestRedisClient(t, server, 2))
+	ctx := context.Background()
+
+	if err := blocklist.Invalidate(ctx, "jti-1", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if ttl := server.ttl("dim:blocklist:jti-1"); ttl <= 59*time.Minute || ttl > time.Hour {
+		t.Errorf("ttl = %v, want ~1h", ttl)
+	}
+	if revoked, err := blocklist.AnyRevoked(ctx, "sid-1", "jti-1"); err != nil || !revoked {
+		t.Errorf("AnyRevoked = %v, %v; want true", revoked, err)
+	}
+	if revoked, _ := blocklist.IsRevoked(ctx, "sid-1"); revoked {
+		t.Error("sid-1 should not be revoked")
+	}
+
+	_ = blocklist.Invalidate(ctx, "short", 20*time.Millisecond)
+	time.Sleep(40 * time.Millisecond)
+	if revoked, _ := blocklist.IsRevoked(ctx, "short"); revoked {
+		t.Error("entry should expire with its TTL")
+	}
+}
+
+func TestRedisCache(t *testing.T) {
+	type country struct {
+		Code string `json:"code"`
+		Name string `json:"name"`
+	}
+	server := newFakeRedisServer(t, "")
+	c := NewRedisCache[int, country](newTestRedisClient(t, server, 2), "test:countries:", time.Hour)
+	ctx := context.Background()
+
+	c.Set(ctx, 62, country{Code: "ID", Name: "Indonesia"})
+	if got, ok := c.Get(ctx, 62); !ok || got.Name != "Indonesia" {
+		t.Errorf("Get = %+v, %v", got, ok)
+	}
+	if ttl := server.ttl("test:countries:62"); ttl <= 59*time.Minute {
+		t.Errorf("default ttl = %v, want ~1h", ttl)
+	}
+
+	c.Set(ctx, 65, country{Code: "SG"}, cache.WithTTL(time.Minute))
+	if ttl := server.ttl("test:countries:65"); ttl <= 0 || ttl > time.Minute {
+		t.Errorf("WithTTL ttl = %v", ttl)
+	}
+	c.Set(ctx, 65, country{Code: "SG"}, cache.WithExpiresAt(time.Now().Add(-time.Second)))
+	if _, ok := c.Get(ctx, 65); ok {
+		t.Error("value set with past ExpiresAt should not be served")
+	}
+
+	c.Delete(ctx, 62)
+	if _, ok := c.Get(ctx, 62); ok {
+		t.Error("deleted key should miss")
+	}
+	if stats := c.Stats(); stats.Hits != 1 || stats.Misses != 2 {
+		t.Errorf("stats = %+v, want 1 hit 2 misses", stats)
+	}
+}
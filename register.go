@@ -0,0 +1,349 @@
+package dim
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ErrEmailVerificationNotFound dikembalikan EmailVerificationStore jika token verifikasi email
+// tidak ditemukan.
+var ErrEmailVerificationNotFound = errors.New("email verification token not found")
+
+// RegisterRequest merepresentasikan data yang dibutuhkan untuk registrasi user baru.
+type RegisterRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+	Name     string `json:"name"`
+}
+
+// RegisterResult adalah hasil Register. Jika verifikasi email diwajibkan, token sesi kosong dan
+// VerificationToken berisi token yang dikirim ke email user.
+type RegisterResult struct {
+	User              *User  `json:"user"`
+	AccessToken       string `json:"access_token,omitempty"`
+	RefreshToken      string `json:"refresh_token,omitempty"`
+	VerificationToken string `json:"-"`
+}
+
+// VerificationRequired melaporkan apakah user harus memverifikasi email sebelum dapat login.
+func (r *RegisterResult) VerificationRequired() bool {
+	return r.AccessToken == ""
+}
+
+// UserRegistrationStore mendefinisikan penyimpanan yang dibutuhkan Register.
+// DatabaseAuthUserStore mengimplementasikan interface ini.
+type UserRegistrationStore interface {
+	// Exists melaporkan apakah email sudah dipakai, termasuk oleh user yang di-soft delete
+	Exists(ctx context.Context, email string) (bool, error)
+	// Create menyimpan user baru dan mengisi ID (jika kosong), CreatedAt, dan UpdatedAt
+	Create(ctx context.Context, user *User) error
+	MarkEmailVerified(ctx context.Context, userID string) error
+	IsEmailVerified(ctx context.Context, userID string) (bool, error)
+}
+
+// EmailVerificationToken adalah token sekali pakai untuk mengonfirmasi email user baru.
+// Hanya hash token yang disimpan.
+type EmailVerificationToken struct {
+	ID        int64      `json:"id"`
+	UserID    string     `json:"user_id"`
+	TokenHash string     `json:"-"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// EmailVerificationStore mendefinisikan penyimpanan token verifikasi email.
+// DatabaseTokenStore dan MockTokenStore mengimplementasikan interface ini.
+type EmailVerificationStore interface {
+	SaveEmailVerificationToken(ctx context.Context, token *EmailVerificationToken) error
+	FindEmailVerificationToken(ctx context.Context, tokenHash string) (*EmailVerificationToken, error) // ErrEmailVerificationNotFound jika tidak ada
+	MarkEmailVerificationUsed(ctx context.Context, tokenHash string) error
+}
+
+// EmailVerificationEmail adalah data untuk template email verifikasi.
+type EmailVerificationEmail struct {
+	Email     string
+	Name      string
+	URL       string
+	ExpiresAt time.Time
+}
+
+// RegistrationOptions mengatur perilaku Register. Semua field opsional kecuali Verifications
+// yang wajib diisi jika RequireEmailVerification aktif.
+type RegistrationOptions struct {
+	// RequireEmailVerification membuat Register tidak menerbitkan token sesi dan Login menolak
+	// user yang belum memverifikasi email (403).
+	RequireEmailVerification bool
+	// Verifications menyimpan token verifikasi, biasanya DatabaseTokenStore.
+	Verifications EmailVerificationStore
+	// Mailer mengirim email verifikasi. Jika nil, token dikembalikan di RegisterResult untuk
+	// dikirim sendiri oleh pemanggil.
+	Mailer Mailer
+	// URL adalah format URL halaman verifikasi dengan %s untuk token,
+	// misal "https://app.example.com/verify-email?token=%s".
+	URL string
+	// Subject email (default: "Verifikasi email Anda").
+	Subject string
+	// Template mengganti isi email default, misal EmailRenderer.VerificationTemplate.
+	// Mengembalikan nil berarti memakai isi email default.
+	Template func(data EmailVerificationEmail) *MailMessage
+	// Expiry adalah masa berlaku token verifikasi (default: 24 jam).
+	Expiry time.Duration
+}
+
+// WithRegistration mengaktifkan Register dan mengembalikan instance service.
+//
+// Parameters:
+//   - store: penyimpanan user, biasanya DatabaseAuthUserStore yang sama dengan userStore
+//   - options: verifikasi email dan pengiriman email
+//
+// Example:
+//
+//	userStore := dim.NewDatabaseAuthUserStore(db)
+//	tokenStore := dim.NewDatabaseTokenStore(db)
+//	authService.WithRegistration(userStore, dim.RegistrationOptions{
+//	    RequireEmailVerification: true,
+//	    Verifications:            tokenStore,
+//	    Mailer:                   mailer,
+//	    URL:                      "https://app.example.com/verify-email?token=%s",
+//	})
+func (s *AuthService) WithRegistration(store UserRegistrationStore, options RegistrationOptions) *AuthService {
+	if options.Subject == "" {
+		options.Subject = "Verifikasi email Anda"
+	}
+	if options.Expiry <= 0 {
+		options.Expiry = 24 * time.Hour
+	}
+	s.registration = store
+	s.registerOpts = options
+	return s
+}
+
+// Register mendaftarkan user baru dalam satu langkah: validasi input dan kekuatan password,
+// pemeriksaan email duplikat, hash password dengan PasswordHasher service, lalu penyimpanan
+// user. Jika verifikasi email diwajibkan, token verifikasi dibuat (dan dikirim jika Mailer
+// dikonfigurasi) tanpa menerbitkan token sesi; jika tidak, user langsung login.
+// Email disimpan dalam huruf kecil tanpa spasi di awal/akhir.
+//
+// Parameters:
+//   - ctx: context request
+//   - req: email, password, dan nama user
+//
+// Returns:
+//   - *RegisterResult: user baru beserta token sesi atau token verifikasi
+//   - error: *AppError (400 validasi, 409 email sudah terdaftar, 500 gagal menyimpan/mengirim)
+//
+// Example:
+//
+//	var req dim.RegisterRequest
+//	if err := dim.Bind(r, &req); err != nil { ... }
+//	result, err := authService.Register(r.Context(), req)
+//	if err != nil {
+//	    appErr, _ := dim.AsAppError(err)
+//	    dim.JsonAppError(w, appErr)
+//	    return
+//	}
+//	dim.Created(w, result)
+func (s *AuthService) Register(ctx context.Context, req RegisterRequest) (*RegisterResult, error) {
+	if s.registration == nil {
+		return nil, NewAppError("Registrasi tidak dikonfigurasi", http.StatusInternalServerError)
+	}
+
+	locale := LocaleFromContext(ctx)
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	name := strings.TrimSpace(req.Name)
+
+	v := NewValidator().WithLocale(locale).
+		Required("email", email).
+		Email("email", email).
+		MaxLength("email", email, 255).
+		Required("password", req.Password).
+		MaxLength("name", name, 100)
+
+	if !v.IsValid() {
+		err := NewAppError("Validasi gagal", 400)
+		err.Errors = v.ErrorMap()
+		return nil, err
+	}
+
+	if err := s.checkPassword(ctx, locale, req.Password); err != nil {
+		return nil, err
+	}
+
+	exists, err := s.registration.Exists(ctx, email)
+	if err != nil {
+		s.logRegistrationError("Failed to check email", err)
+		return nil, NewAppError("Gagal memeriksa email", 500)
+	}
+	if exists {
+		return nil, NewAppError("Email sudah terdaftar", http.StatusConflict).
+			WithFieldError("email", Translate(locale, "Email sudah terdaftar"))
+	}
+
+	passwordHash, err := s.hasher.Hash(req.Password)
+	if err != nil {
+		return nil, NewAppError("Gagal memproses password hash", 500)
+	}
+
+	user := &User{Email: email, Name: name, Password: passwordHash}
+	if err := s.registration.Create(ctx, user); err != nil {
+		s.logRegistrationError("Failed to create user", err)
+		return nil, NewAppError("Gagal menyimpan pengguna", 500)
+	}
+	s.emit(ctx, UserRegistered{
+		User:                 user,
+		VerificationRequired: s.registerOpts.RequireEmailVerification,
+		Time:                 time.Now(),
+	})
+
+	result := &RegisterResult{User: user}
+	if s.registerOpts.RequireEmailVerification {
+		token, err := s.sendEmailVerification(ctx, user)
+		if err != nil {
+			return nil, err
+		}
+		result.VerificationToken = token
+		return result, nil
+	}
+
+	result.AccessToken, result.RefreshToken, err = s.issueTokens(ctx, user, false)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// sendEmailVerification membuat token verifikasi untuk user dan mengirimkannya jika Mailer
+// dikonfigurasi.
+func (s *AuthService) sendEmailVerification(ctx context.Context, user *User) (string, error) {
+	store := s.registerOpts.Verifications
+	if store == nil {
+		return "", NewAppError("Verifikasi email tidak dikonfigurasi", http.StatusInternalServerError)
+	}
+
+	token, err := GenerateSecureToken(32)
+	if err != nil {
+		return "", NewAppError("Gagal membuat token verifikasi", 500)
+	}
+
+	verification := &EmailVerificationToken{
+		UserID:    user.ID,
+		TokenHash: GenerateTokenHash(token),
+		ExpiresAt: time.Now().Add(s.registerOpts.Expiry),
+	}
+	if err := store.SaveEmailVerificationToken(ctx, verification); err != nil {
+		s.logRegistrationError("Failed to save email verification token", err)
+		return "", NewAppError("Gagal menyimpan token verifikasi", 500)
+	}
+
+	if s.registerOpts.Mailer != nil {
+		msg := s.emailVerificationMessage(EmailVerificationEmail{
+			Email:     user.Email,
+			Name:      user.Name,
+			URL:       fmt.Sprintf(s.registerOpts.URL, token),
+			ExpiresAt: verification.ExpiresAt,
+		})
+		if err := s.registerOpts.Mailer.Send(ctx, msg); err != nil {
+			s.logRegistrationError("Failed to send email verification", err)
+			return "", NewAppError("Gagal mengirim email verifikasi", 500)
+		}
+	}
+
+	return token, nil
+}
+
+func (s *AuthService) emailVerificationMessage(data EmailVerificationEmail) *MailMessage {
+	if s.registerOpts.Template != nil {
+		if msg := s.registerOpts.Template(data); msg != nil {
+			return msg
+		}
+	}
+
+	msg := NewMailMessage([]string{data.Email}, s.registerOpts.Subject)
+	msg.HTML = fmt.Sprintf(
+		`<p>Klik link berikut untuk memverifikasi email Anda.</p><p><a href="%s">Verifikasi email</a></p><p>Link berlaku hingga %s. Abaikan email ini jika Anda tidak mendaftar.</p>`,
+		html.EscapeString(data.URL), data.ExpiresAt.Format("2 Jan 2006 15:04 MST"),
+	)
+	msg.PlainText = fmt.Sprintf(
+		"Klik link berikut untuk memverifikasi email Anda:\n\n%s\n\nLink berlaku hingga %s. Abaikan email ini jika Anda tidak mendaftar.\n",
+		data.URL, data.ExpiresAt.Format("2 Jan 2006 15:04 MST"),
+	)
+	return msg
+}
+
+// VerifyEmail memverifikasi token dari email verifikasi, menandai email user terverifikasi, lalu
+// menerbitkan access & refresh token seperti Login sehingga user tidak perlu login ulang.
+// Token hanya dapat dipakai sekali. User dengan MFA aktif mendapat *MFARequiredError.
+//
+// Parameters:
+//   - ctx: context request
+//   - token: token dari link email
+//
+// Returns:
+//   - string: access token
+//   - string: refresh token
+//   - error: *AppError (400 jika token tidak valid, kadaluarsa, atau sudah dipakai) atau *MFARequiredError
+func (s *AuthService) VerifyEmail(ctx context.Context, token string) (string, string, error) {
+	if s.registration == nil || s.registerOpts.Verifications == nil {
+		return "", "", NewAppError("Verifikasi email tidak dikonfigurasi", http.StatusInternalServerError)
+	}
+	store := s.registerOpts.Verifications
+
+	tokenHash := GenerateTokenHash(token)
+	verification, err := store.FindEmailVerificationToken(ctx, tokenHash)
+	if err != nil || verification.UsedAt != nil || time.Now().After(verification.ExpiresAt) {
+		return "", "", NewAppError("Token verifikasi tidak valid atau kadaluarsa", 400)
+	}
+
+	if err := store.MarkEmailVerificationUsed(ctx, tokenHash); err != nil {
+		return "", "", NewAppError("Gagal menandai token verifikasi", 500)
+	}
+
+	if err := s.registration.MarkEmailVerified(ctx, verification.UserID); err != nil {
+		s.logRegistrationError("Failed to mark email verified", err)
+		return "", "", NewAppError("Gagal memverifikasi email", 500)
+	}
+
+	user, err := s.userStore.FindByID(ctx, verification.UserID)
+	if err != nil {
+		return "", "", NewAppError("Token verifikasi tidak valid atau kadaluarsa", 400)
+	}
+
+	challenge, err := s.mfaChallenge(ctx, user)
+	if err != nil {
+		return "", "", err
+	}
+	if challenge != nil {
+		return "", "", challenge
+	}
+
+	return s.issueTokens(ctx, user, false)
+}
+
+// requireVerifiedEmail menolak login user yang belum memverifikasi email jika
+// RegistrationOptions.RequireEmailVerification aktif.
+func (s *AuthService) requireVerifiedEmail(ctx context.Context, user Authenticatable) error {
+	if s.registration == nil || !s.registerOpts.RequireEmailVerification {
+		return nil
+	}
+	verified, err := s.registration.IsEmailVerified(ctx, user.GetID())
+	if err != nil {
+		s.logRegistrationError("Failed to check email verification", err)
+		return NewAppError("Gagal memeriksa verifikasi email", 500)
+	}
+	if !verified {
+		return NewAppError("Email belum diverifikasi", http.StatusForbidden)
+	}
+	return nil
+}
+
+func (s *AuthService) logRegistrationError(msg string, err error) {
+	if s.logger != nil {
+		s.logger.Error(msg, "error", err.Error())
+	}
+}
@@ -0,0 +1,102 @@
+package dim
+
+import (
+	"context"
+)
+
+// GetEmailVerificationMigrations mengembalikan migrasi kolom users.email_verified_at dan tabel
+// email_verification_tokens yang dipakai Register dengan RequireEmailVerification. Modul ini
+// opsional sehingga tidak termasuk dalam GetFrameworkMigrations; gabungkan secara manual setelah
+// migrasi users. Menggunakan versi 181-182 agar tidak bentrok dengan migrasi framework maupun aplikasi.
+//
+// Example:
+//
+//	migrations := append(dim.GetFrameworkMigrations(), dim.GetEmailVerificationMigrations()...)
+//	dim.RunMigrations(db, migrations)
+func GetEmailVerificationMigrations() []Migration {
+	return []Migration{
+		{
+			Version: 181,
+			Name:    "add_email_verified_at_to_users",
+			Up:      AddEmailVerifiedAtColumn,
+			Down:    DropEmailVerifiedAtColumn,
+		},
+		{
+			Version: 182,
+			Name:    "create_email_verification_tokens_table",
+			Up:      CreateEmailVerificationTokensTable,
+			Down:    DropEmailVerificationTokensTable,
+		},
+	}
+}
+
+// AddEmailVerifiedAtColumn menambahkan kolom email_verified_at (nullable) ke tabel users.
+// User yang sudah ada sebelum migrasi dianggap terverifikasi agar tetap dapat login.
+func AddEmailVerifiedAtColumn(db Database) error {
+	var query string
+	switch db.DriverName() {
+	case "sqlite":
+		query = `ALTER TABLE users ADD COLUMN email_verified_at TIMESTAMP NULL`
+	case "mysql":
+		query = `ALTER TABLE users ADD COLUMN email_verified_at DATETIME NULL`
+	default:
+		query = `ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMP NULL`
+	}
+	if err := db.Exec(context.Background(), query); err != nil {
+		return err
+	}
+	return db.Exec(context.Background(), `UPDATE users SET email_verified_at = created_at WHERE email_verified_at IS NULL`)
+}
+
+// DropEmailVerifiedAtColumn menghapus kolom email_verified_at dari tabel users.
+func DropEmailVerifiedAtColumn(db Database) error {
+	return db.Exec(context.Background(), "ALTER TABLE users DROP COLUMN email_verified_at")
+}
+
+// CreateEmailVerificationTokensTable membuat tabel email_verification_tokens. Hanya hash token
+// yang disimpan.
+func CreateEmailVerificationTokensTable(db Database) error {
+	var query string
+	switch db.DriverName() {
+	case "sqlite":
+		query = `
+			CREATE TABLE IF NOT EXISTS email_verification_tokens (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+				token_hash TEXT UNIQUE NOT NULL,
+				expires_at TIMESTAMP NOT NULL,
+				used_at TIMESTAMP NULL,
+				created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+			)
+		`
+	case "mysql":
+		query = `
+			CREATE TABLE IF NOT EXISTS email_verification_tokens (
+				id BIGINT AUTO_INCREMENT PRIMARY KEY,
+				user_id CHAR(36) NOT NULL,
+				token_hash VARCHAR(64) UNIQUE NOT NULL,
+				expires_at DATETIME NOT NULL,
+				used_at DATETIME NULL,
+				created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				CONSTRAINT fk_email_verification_tokens_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+		`
+	default:
+		query = `
+			CREATE TABLE IF NOT EXISTS email_verification_tokens (
+				id BIGSERIAL PRIMARY KEY,
+				user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+				token_hash VARCHAR(64) UNIQUE NOT NULL,
+				expires_at TIMESTAMP NOT NULL,
+				used_at TIMESTAMP NULL,
+				created_at TIMESTAMP NOT NULL DEFAULT NOW()
+			)
+		`
+	}
+	return db.Exec(context.Background(), query)
+}
+
+// DropEmailVerificationTokensTable menghapus tabel email_verification_tokens.
+func DropEmailVerificationTokensTable(db Database) error {
+	return db.Exec(context.Background(), "DROP TABLE IF EXISTS email_verification_tokens")
+}
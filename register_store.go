@@ -0,0 +1,136 @@
+package dim
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Exists reports whether a user with the email exists, including soft-deleted users since
+// their rows still hold the unique email.
+func (s *DatabaseAuthUserStore) Exists(ctx context.Context, email string) (bool, error) {
+	var count int
+	query := s.db.Rebind(`SELECT COUNT(*) FROM users WHERE email = $1`)
+	if err := s.db.QueryRow(ctx, query, email).Scan(&count); err != nil {
+		return false, fmt.Errorf("failed to check user email: %w", err)
+	}
+	return count > 0, nil
+}
+
+// Create inserts a new user, filling ID (if empty), CreatedAt, UpdatedAt and Version.
+func (s *DatabaseAuthUserStore) Create(ctx context.Context, user *User) error {
+	if user.ID == "" {
+		user.ID = NewUuid().String()
+	}
+	now := time.Now().UTC().Truncate(time.Second)
+	user.CreatedAt, user.UpdatedAt = now, now
+	user.Version = 1
+
+	query := s.db.Rebind(`INSERT INTO users (id, email, name, password, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6)`)
+	if err := s.db.Exec(ctx, query, user.ID, user.Email, user.Name, user.Password, user.CreatedAt, user.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to create user: %w", err)
+	}
+	return nil
+}
+
+// MarkEmailVerified sets email_verified_at for the user. Requires GetEmailVerificationMigrations.
+// Verifying an already verified user keeps the original timestamp.
+func (s *DatabaseAuthUserStore) MarkEmailVerified(ctx context.Context, userID string) error {
+	query := s.db.Rebind(`UPDATE users SET email_verified_at = $1 WHERE id = $2 AND email_verified_at IS NULL`)
+	if err := s.db.Exec(ctx, query, time.Now().UTC().Truncate(time.Second), userID); err != nil {
+		return fmt.Errorf("failed to mark email verified: %w", err)
+	}
+	return nil
+}
+
+// IsEmailVerified reports whether the user has verified their email. Requires
+// GetEmailVerificationMigrations.
+func (s *DatabaseAuthUserStore) IsEmailVerified(ctx context.Context, userID string) (bool, error) {
+	var verifiedAt *time.Time
+	query := s.db.Rebind(`SELECT email_verified_at FROM users WHERE id = $1`)
+	if err := s.db.QueryRow(ctx, query, userID).Scan(&verifiedAt); err != nil {
+		if isNoRows(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to check email verification: %w", err)
+	}
+	return verifiedAt != nil, nil
+}
+
+// SaveEmailVerificationToken saves an email verification token to the database.
+func (s *DatabaseTokenStore) SaveEmailVerificationToken(ctx context.Context, token *EmailVerificationToken) error {
+	now := time.Now().UTC().Truncate(time.Second)
+	query := `INSERT INTO email_verification_tokens (user_id, token_hash, expires_at, created_at)
+		 VALUES ($1, $2, $3, $4)
+		 RETURNING id, created_at`
+
+	err := s.db.QueryRow(ctx, s.db.Rebind(query),
+		token.UserID,
+		token.TokenHash,
+		token.ExpiresAt.UTC().Truncate(time.Second),
+		now,
+	).Scan(&token.ID, &token.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to save email verification token: %w", err)
+	}
+
+	return nil
+}
+
+// FindEmailVerificationToken finds an email verification token by hash.
+func (s *DatabaseTokenStore) FindEmailVerificationToken(ctx context.Context, tokenHash string) (*EmailVerificationToken, error) {
+	token := &EmailVerificationToken{}
+	query := `SELECT id, user_id, token_hash, expires_at, created_at, used_at
+		 FROM email_verification_tokens WHERE token_hash = $1`
+
+	err := s.db.QueryRow(ctx, s.db.Rebind(query), tokenHash).Scan(
+		&token.ID, &token.UserID, &token.TokenHash, &token.ExpiresAt, &token.CreatedAt, &token.UsedAt,
+	)
+	if err != nil {
+		if isNoRows(err) {
+			return nil, ErrEmailVerificationNotFound
+		}
+		return nil, fmt.Errorf("failed to find email verification token: %w", err)
+	}
+
+	return token, nil
+}
+
+// MarkEmailVerificationUsed marks an email verification token as used.
+func (s *DatabaseTokenStore) MarkEmailVerificationUsed(ctx context.Context, tokenHash string) error {
+	query := `UPDATE email_verification_tokens SET used_at = $1 WHERE token_hash = $2`
+
+	if err := s.db.Exec(ctx, s.db.Rebind(query), time.Now().UTC().Truncate(time.Second), tokenHash); err != nil {
+		return fmt.Errorf("failed to mark email verification token as used: %w", err)
+	}
+
+	return nil
+}
+
+// SaveEmailVerificationToken saves an email verification token in mock store.
+func (s *MockTokenStore) SaveEmailVerificationToken(ctx context.Context, token *EmailVerificationToken) error {
+	token.ID = int64(len(s.verifications) + 1)
+	token.CreatedAt = time.Now()
+	s.verifications[token.TokenHash] = token
+	return nil
+}
+
+// FindEmailVerificationToken finds an email verification token in mock store.
+func (s *MockTokenStore) FindEmailVerificationToken(ctx context.Context, tokenHash string) (*EmailVerificationToken, error) {
+	token, exists := s.verifications[tokenHash]
+	if !exists {
+		return nil, ErrEmailVerificationNotFound
+	}
+	copied := *token
+	return &copied, nil
+}
+
+// MarkEmailVerificationUsed marks an email verification token as used in mock store.
+func (s *MockTokenStore) MarkEmailVerificationUsed(ctx context.Context, tokenHash string) error {
+	if token, exists := s.verifications[tokenHash]; exists {
+		now := time.Now()
+		token.UsedAt = &now
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package dim
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestRegisterService(t *testing.T, options RegistrationOptions) (*AuthService, *DatabaseAuthUserStore, *DatabaseTokenStore) {
+	t.Helper()
+	db := newTestSQLiteAuthDB(t)
+	if err := RunMigrations(db, GetEmailVerificationMigrations()); err != nil {
+		t.Fatal(err)
+	}
+	userStore := NewDatabaseAuthUserStore(db)
+	tokenStore := NewDatabaseTokenStore(db)
+	service, err := NewAuthService(userStore, tokenStore, nil, &JWTConfig{
+		HMACSecret:         "test-secret",
+		SigningMethod:      "HS256",
+		AccessTokenExpiry:  15 * time.Minute,
+		RefreshTokenExpiry: 7 * 24 * time.Hour,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if options.RequireEmailVerification {
+		options.Verifications = tokenStore
+	}
+	service.WithPasswordHasher(NewBcryptHasher(4)).WithRegistration(userStore, options)
+	return service, userStore, tokenStore
+}
+
+func TestAuthService_Register(t *testing.T) {
+	ctx := context.Background()
+	service, userStore, _ := newTestRegisterService(t, RegistrationOptions{})
+
+	result, err := service.Register(ctx, RegisterRequest{Email: " Ana@Example.com ", Password: "ValidPass123!", Name: "Ana"})
+	if err != nil {
+		t.Fatalf("Register error: %v", err)
+	}
+	if result.User.ID == "" || result.User.Email != "ana@example.com" || result.VerificationRequired() {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if claims, _ := service.tokenManager.VerifyToken(result.AccessToken); claims["sub"] != result.User.ID {
+		t.Errorf("access token claims = %v", claims)
+	}
+	stored, err := userStore.FindByEmail(ctx, "ana@example.com")
+	if err != nil || stored.GetPassword() == "ValidPass123!" {
+		t.Fatalf("stored user = %+v, %v", stored, err)
+	}
+	if _, _, err := service.Login(ctx, "ana@example.com", "ValidPass123!"); err != nil {
+		t.Errorf("Login after Register error: %v", err)
+	}
+
+	cases := []struct {
+		name   string
+		req    RegisterRequest
+		status int
+		field  string
+	}{
+		{"duplicate", RegisterRequest{Email: "ANA@example.com", Password: "ValidPass123!"}, http.StatusConflict, "email"},
+		{"invalid email", RegisterRequest{Email: "ana", Password: "ValidPass123!"}, http.StatusBadRequest, "email"},
+		{"missing password", RegisterRequest{Email: "budi@example.com"}, http.StatusBadRequest, "password"},
+		{"weak password", RegisterRequest{Email: "budi@example.com", Password: "password"}, http.StatusBadRequest, "password"},
+	}
+	for _, c := range cases {
+		_, err := service.Register(ctx, c.req)
+		appErr, ok := AsAppError(err)
+		if !ok || appErr.StatusCode != c.status {
+			t.Errorf("%s: error = %v, want status %d", c.name, err, c.status)
+			continue
+		}
+		if _, ok := appErr.Errors[c.field]; !ok {
+			t.Errorf("%s: missing field error %q in %v", c.name, c.field, appErr.Errors)
+		}
+	}
+
+	// Aturan password dapat dilonggarkan lewat WithPasswordValidator
+	service.WithPasswordValidator(NewPasswordValidator().RequireUppercase(false).RequireDigit(false).RequireSpecial(false))
+	if _, err := service.Register(ctx, RegisterRequest{Email: "budi@example.com", Password: "password"}); err != nil {
+		t.Errorf("Register with relaxed validator error: %v", err)
+	}
+}
+
+func TestAuthService_RegisterEmailVerification(t *testing.T) {
+	ctx := context.Background()
+	mailer := &captureOrgMailer{}
+	service, userStore, tokenStore := newTestRegisterService(t, RegistrationOptions{
+		RequireEmailVerification: true,
+		Mailer:                   mailer,
+		URL:                      "https://app.test/verify-email?token=%s",
+	})
+
+	result, err := service.Register(ctx, RegisterRequest{Email: "ana@example.com", Password: "ValidPass123!"})
+	if err != nil {
+		t.Fatalf("Register error: %v", err)
+	}
+	if !result.VerificationRequired() || result.RefreshToken != "" || result.VerificationToken == "" {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if len(mailer.sent) != 1 {
+		t.Fatalf("expected 1 email, got %d", len(mailer.sent))
+	}
+	msg := mailer.sent[0]
+	if msg.To[0] != "ana@example.com" || msg.Subject != "Verifikasi email Anda" ||
+		!strings.Contains(msg.PlainText, "https://app.test/verify-email?token="+result.VerificationToken) {
+		t.Errorf("unexpected email: %+v", msg)
+	}
+
+	// Login ditolak sampai email diverifikasi
+	_, _, err = service.Login(ctx, "ana@example.com", "ValidPass123!")
+	if appErr, ok := AsAppError(err); !ok || appErr.StatusCode != http.StatusForbidden {
+		t.Fatalf("Login before verification error = %v, want 403", err)
+	}
+
+	access, refresh, err := service.VerifyEmail(ctx, result.VerificationToken)
+	if err != nil || access == "" || refresh == "" {
+		t.Fatalf("VerifyEmail error: %v", err)
+	}
+	if verified, err := userStore.IsEmailVerified(ctx, result.User.ID); err != nil || !verified {
+		t.Errorf("IsEmailVerified = %v, %v", verified, err)
+	}
+	if _, _, err := service.VerifyEmail(ctx, result.VerificationToken); err == nil {
+		t.Error("expected verification token to be single use")
+	}
+	if _, _, err := service.Login(ctx, "ana@example.com", "ValidPass123!"); err != nil {
+		t.Errorf("Login after verification error: %v", err)
+	}
+
+	// Token kadaluarsa ditolak
+	result, err = service.Register(ctx, RegisterRequest{Email: "budi@example.com", Password: "ValidPass123!"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expired := time.Now().Add(-time.Minute).UTC()
+	if err := tokenStore.db.Exec(ctx, "UPDATE email_verification_tokens SET expires_at = ? WHERE user_id = ?", expired, result.User.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := service.VerifyEmail(ctx, result.VerificationToken); err == nil {
+		t.Error("expected expired verification token to be rejected")
+	}
+}
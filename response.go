@@ -27,63 +27,106 @@ type ErrorResponse struct {
 }
 
 // Json menulis JSON response dengan status code dan data yang diberikan.
-// Content-Type header otomatis di-set ke "application/json".
+// Content-Type header otomatis di-set ke "application/json". Jika ContentNegotiationMiddleware
+// memilih media type lain dari header Accept, data di-encode dengan serializer media type tersebut
+// (lihat RegisterSerializer).
 // Untuk single objects, write langsung tanpa wrapper: {"id": 1, "name": "John"}
 // Untuk arrays, write langsung tanpa wrapper: [{"id": 1, "name": "John"}]
+// Jika route memakai VersionTransform, data diubah ke bentuk versi API request sebelum di-encode.
 //
 // Parameters:
 //   - w: http.ResponseWriter untuk menulis response
 //   - status: HTTP status code (contoh: 200, 400, 500)
 //   - data: data yang akan di-encode sebagai JSON
+//   - opts: (opsional) override ResponseConfig global untuk response ini
 //
 // Returns:
-//   - error: error jika encoding JSON gagal
+//   - error: error jika transformasi versi atau encoding JSON gagal
 //
 // Example:
 //
 //	user := User{ID: 1, Name: "John", Email: "john@example.com"}
 //	Json(w, 200, user)
-func Json(w http.ResponseWriter, status int, data interface{}) error {
-	w.Header().Set("Content-Type", "application/json")
+//
+//	// Override per pemanggilan
+//	Json(w, 200, user, WithInt64AsString(true))
+func Json(w http.ResponseWriter, status int, data interface{}, opts ...ResponseOption) error {
+	data, err := transformVersionedResponse(w, data)
+	if err != nil {
+		InternalServerError(w, "Gagal memproses response")
+		return err
+	}
+
+	mediaType, serializer := responseSerializer(w)
+	if serializer != nil {
+		body, err := serialize(serializer, data)
+		if err != nil {
+			InternalServerError(w, "Gagal memproses response")
+			return err
+		}
+		return writeBody(w, status, mediaType, body)
+	}
+
+	w.Header().Set("Content-Type", mediaType)
 	w.WriteHeader(status)
 
-	return json.NewEncoder(w).Encode(data)
+	return encodeJSON(w, data, resolveResponseConfig(opts))
 }
 
 // JsonPagination menulis paginated JSON response dengan data dan pagination metadata.
 // Response format: {"data": [...], "meta": {"page": 1, "per_page": 10, "total": 100, "total_pages": 10}}
 // Content-Type header otomatis di-set ke "application/json".
+// Transformer VersionTransform diterapkan pada data, bukan pada meta.
 //
 // Parameters:
 //   - w: http.ResponseWriter untuk menulis response
 //   - status: HTTP status code
 //   - data: data array/slice yang akan dipaginate
 //   - meta: PaginationMeta berisi pagination information
+//   - opts: (opsional) override ResponseConfig global untuk response ini
 //
 // Returns:
-//   - error: error jika encoding JSON gagal
+//   - error: error jika transformasi versi atau encoding JSON gagal
 //
 // Example:
 //
 //	users := []User{{ID: 1, Name: "John"}, {ID: 2, Name: "Jane"}}
 //	meta := PaginationMeta{Page: 1, PerPage: 10, Total: 100, TotalPages: 10}
 //	JsonPagination(w, 200, users, meta)
-func JsonPagination(w http.ResponseWriter, status int, data interface{}, meta PaginationMeta) error {
+func JsonPagination(w http.ResponseWriter, status int, data interface{}, meta PaginationMeta, opts ...ResponseOption) error {
+	data, err := transformVersionedResponse(w, data)
+	if err != nil {
+		InternalServerError(w, "Gagal memproses response")
+		return err
+	}
+
 	response := PaginationResponse{
 		Data: data,
 		Meta: meta,
 	}
 
-	w.Header().Set("Content-Type", "application/json")
+	mediaType, serializer := responseSerializer(w)
+	if serializer != nil {
+		body, err := serialize(serializer, response)
+		if err != nil {
+			InternalServerError(w, "Gagal memproses response")
+			return err
+		}
+		return writeBody(w, status, mediaType, body)
+	}
+
+	w.Header().Set("Content-Type", mediaType)
 	w.WriteHeader(status)
 
-	return json.NewEncoder(w).Encode(response)
+	return encodeJSON(w, response, resolveResponseConfig(opts))
 }
 
 // JsonError menulis error JSON response dengan message dan optional field errors.
 // Response format: {"message": "error message", "errors": {"field": "error message"}}
 // Content-Type header otomatis di-set ke "application/json".
 // Gunakan untuk standard error responses dengan field-level error details.
+// Jika request melewati LocaleMiddleware, message dan field errors statis diterjemahkan
+// ke locale request menggunakan Translator global.
 //
 // Parameters:
 //   - w: http.ResponseWriter untuk menulis response
@@ -101,12 +144,26 @@ func JsonPagination(w http.ResponseWriter, status int, data interface{}, meta Pa
 //	  "password": []string{"minimal 8 karakter", "butuh angka"},
 //	})
 func JsonError(w http.ResponseWriter, status int, message string, errors FieldErrors) error {
+	if locale, ok := writerLocale(w); ok {
+		message = Translate(locale, message)
+		errors = translateFieldErrors(locale, errors)
+	}
+
 	response := ErrorResponse{
 		Message: message,
 		Errors:  errors,
 	}
 
-	w.Header().Set("Content-Type", "application/json")
+	mediaType, serializer := responseSerializer(w)
+	if serializer != nil {
+		// Serializer yang tidak dapat meng-encode ErrorResponse (misal protobuf) jatuh ke JSON
+		if body, err := serialize(serializer, response); err == nil {
+			return writeBody(w, status, mediaType, body)
+		}
+		mediaType = "application/json"
+	}
+
+	w.Header().Set("Content-Type", mediaType)
 	w.WriteHeader(status)
 
 	return json.NewEncoder(w).Encode(response)
@@ -265,7 +322,7 @@ func Created(w http.ResponseWriter, data interface{}) error {
 //   - data: response data
 //
 // Returns:
-//   - error: error jika encoding JSON gagal
+//   - error: error jika transformasi versi atau encoding JSON gagal
 //
 // Example:
 //
@@ -402,3 +459,22 @@ func TooManyRequests(w http.ResponseWriter, retryAfterSeconds int) error {
 	w.Header().Set("Retry-After", fmt.Sprintf("%d", retryAfterSeconds))
 	return JsonError(w, http.StatusTooManyRequests, "Batas tingkat permintaan terlampaui", nil)
 }
+
+// ServiceUnavailable menulis 503 Service Unavailable response.
+// Mengatur header Retry-After dan mengirim pesan error standar.
+// Berguna untuk circuit breaker dan maintenance mode.
+//
+// Parameters:
+//   - w: http.ResponseWriter untuk menulis response
+//   - retryAfterSeconds: jumlah detik yang harus ditunggu client sebelum retry
+//
+// Returns:
+//   - error: error jika encoding JSON gagal
+//
+// Example:
+//
+//	ServiceUnavailable(w, 30)
+func ServiceUnavailable(w http.ResponseWriter, retryAfterSeconds int) error {
+	w.Header().Set("Retry-After", fmt.Sprintf("%d", retryAfterSeconds))
+	return JsonError(w, http.StatusServiceUnavailable, "Layanan tidak tersedia sementara", nil)
+}
@@ -0,0 +1,462 @@
+package dim
+
+import (
+	"encoding"
+	"encoding/json"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ResponseConfig mengatur bagaimana Json dan JsonPagination meng-encode data.
+// Zero value berarti perilaku standar encoding/json tanpa transformasi apapun.
+type ResponseConfig struct {
+	// Int64AsString meng-encode nilai dengan kind int64/uint64 sebagai string JSON
+	// untuk menghindari kehilangan presisi di JavaScript (Number.MAX_SAFE_INTEGER = 2^53-1).
+	Int64AsString bool
+	// TimeLocation mengonversi time.Time ke timezone ini sebelum di-format.
+	// Jika nil, timezone asli dari nilai dipertahankan.
+	TimeLocation *time.Location
+	// TimeFormat adalah layout untuk time.Time (default: time.RFC3339).
+	// Hanya dipakai jika TimeLocation atau TimeFormat di-set.
+	TimeFormat string
+	// OmitZeroTime menghilangkan field struct bertipe time.Time yang bernilai zero,
+	// dan meng-encode zero time di slice/map sebagai null.
+	OmitZeroTime bool
+}
+
+// ResponseOption meng-override ResponseConfig global untuk satu pemanggilan Json/JsonPagination.
+type ResponseOption func(*ResponseConfig)
+
+// WithInt64AsString meng-override ResponseConfig.Int64AsString untuk satu response.
+func WithInt64AsString(enabled bool) ResponseOption {
+	return func(c *ResponseConfig) {
+		c.Int64AsString = enabled
+	}
+}
+
+// WithTimeLocation meng-override ResponseConfig.TimeLocation untuk satu response.
+func WithTimeLocation(loc *time.Location) ResponseOption {
+	return func(c *ResponseConfig) {
+		c.TimeLocation = loc
+	}
+}
+
+// WithTimeFormat meng-override ResponseConfig.TimeFormat untuk satu response.
+func WithTimeFormat(layout string) ResponseOption {
+	return func(c *ResponseConfig) {
+		c.TimeFormat = layout
+	}
+}
+
+// WithOmitZeroTime meng-override ResponseConfig.OmitZeroTime untuk satu response.
+func WithOmitZeroTime(enabled bool) ResponseOption {
+	return func(c *ResponseConfig) {
+		c.OmitZeroTime = enabled
+	}
+}
+
+var (
+	responseConfigMu sync.RWMutex
+	responseConfig   ResponseConfig
+)
+
+// SetResponseConfig mengatur ResponseConfig global yang dipakai oleh Json dan JsonPagination.
+// Sebaiknya dipanggil sekali saat startup sebelum server menerima request.
+//
+// Parameters:
+//   - config: ResponseConfig yang akan dipakai secara global
+//
+// Example:
+//
+//	jakarta, _ := time.LoadLocation("Asia/Jakarta")
+//	dim.SetResponseConfig(dim.ResponseConfig{
+//	    Int64AsString: true,
+//	    TimeLocation:  jakarta,
+//	    OmitZeroTime:  true,
+//	})
+func SetResponseConfig(config ResponseConfig) {
+	responseConfigMu.Lock()
+	defer responseConfigMu.Unlock()
+	responseConfig = config
+}
+
+// GetResponseConfig mengembalikan ResponseConfig global saat ini.
+func GetResponseConfig() ResponseConfig {
+	responseConfigMu.RLock()
+	defer responseConfigMu.RUnlock()
+	return responseConfig
+}
+
+// resolveResponseConfig menggabungkan config global dengan override per pemanggilan.
+func resolveResponseConfig(opts []ResponseOption) ResponseConfig {
+	config := GetResponseConfig()
+	for _, opt := range opts {
+		opt(&config)
+	}
+	return config
+}
+
+// isDefault mengembalikan true jika tidak ada transformasi yang perlu dilakukan.
+func (c ResponseConfig) isDefault() bool {
+	return !c.Int64AsString && c.TimeLocation == nil && c.TimeFormat == "" && !c.OmitZeroTime
+}
+
+// encodeJSON meng-encode data ke w sesuai ResponseConfig.
+// Jika config default, langsung menggunakan json.Encoder tanpa overhead reflection.
+func encodeJSON(w io.Writer, data interface{}, config ResponseConfig) error {
+	if config.isDefault() {
+		return json.NewEncoder(w).Encode(data)
+	}
+
+	enc := &responseEncoder{config: config}
+	return json.NewEncoder(w).Encode(enc.transform(reflect.ValueOf(data)))
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// responseEncoder mentransformasi nilai Go menjadi tree yang siap di-encode oleh encoding/json
+// dengan menerapkan aturan ResponseConfig. Nilai yang mengimplementasikan json.Marshaler
+// atau encoding.TextMarshaler diteruskan apa adanya.
+type responseEncoder struct {
+	config ResponseConfig
+}
+
+// orderedObject adalah JSON object yang mempertahankan urutan field struct.
+type orderedObject []orderedField
+
+type orderedField struct {
+	key   string
+	value interface{}
+}
+
+// MarshalJSON meng-encode object dengan urutan field sesuai deklarasi struct.
+func (o orderedObject) MarshalJSON() ([]byte, error) {
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, f := range o {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		key, err := json.Marshal(f.key)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(key)
+		b.WriteByte(':')
+		val, err := json.Marshal(f.value)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(val)
+	}
+	b.WriteByte('}')
+	return []byte(b.String()), nil
+}
+
+func (e *responseEncoder) transform(v reflect.Value) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+
+	if v.Type() == timeType {
+		return e.formatTime(v.Interface().(time.Time))
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return e.transform(v.Elem())
+	}
+
+	if v.Type().Implements(jsonMarshalerType) || v.Type().Implements(textMarshalerType) {
+		return v.Interface()
+	}
+	if v.CanAddr() && (v.Addr().Type().Implements(jsonMarshalerType) || v.Addr().Type().Implements(textMarshalerType)) {
+		return v.Addr().Interface()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		return e.transformStruct(v)
+	case reflect.Map:
+		return e.transformMap(v)
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return v.Interface()
+		}
+		return e.transformList(v)
+	case reflect.Array:
+		return e.transformList(v)
+	case reflect.Int64:
+		if e.config.Int64AsString {
+			return strconv.FormatInt(v.Int(), 10)
+		}
+	case reflect.Uint64:
+		if e.config.Int64AsString {
+			return strconv.FormatUint(v.Uint(), 10)
+		}
+	}
+
+	return v.Interface()
+}
+
+// formatTime mem-format time.Time sesuai TimeLocation/TimeFormat.
+// Zero time menjadi null jika OmitZeroTime aktif.
+func (e *responseEncoder) formatTime(t time.Time) interface{} {
+	if t.IsZero() && e.config.OmitZeroTime {
+		return nil
+	}
+	if e.config.TimeLocation == nil && e.config.TimeFormat == "" {
+		return t
+	}
+	if e.config.TimeLocation != nil {
+		t = t.In(e.config.TimeLocation)
+	}
+	layout := e.config.TimeFormat
+	if layout == "" {
+		layout = time.RFC3339
+	}
+	return t.Format(layout)
+}
+
+func (e *responseEncoder) transformList(v reflect.Value) interface{} {
+	out := make([]interface{}, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		out[i] = e.transform(v.Index(i))
+	}
+	return out
+}
+
+func (e *responseEncoder) transformMap(v reflect.Value) interface{} {
+	if v.IsNil() {
+		return nil
+	}
+
+	out := make(map[string]interface{}, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		key, ok := mapKeyString(iter.Key())
+		if !ok {
+			// Key yang tidak didukung encoding/json: serahkan ke encoder standar
+			return v.Interface()
+		}
+		out[key] = e.transform(iter.Value())
+	}
+	return out
+}
+
+func (e *responseEncoder) transformStruct(v reflect.Value) interface{} {
+	fields := cachedJSONFields(v.Type())
+	out := make(orderedObject, 0, len(fields))
+
+	for _, f := range fields {
+		fv, ok := fieldByIndex(v, f.index)
+		if !ok {
+			continue
+		}
+		if f.omitEmpty && isEmptyJSONValue(fv) {
+			continue
+		}
+		if f.omitZero && fv.IsZero() {
+			continue
+		}
+		if e.config.OmitZeroTime && fv.Type() == timeType && fv.Interface().(time.Time).IsZero() {
+			continue
+		}
+
+		if f.quoted {
+			out = append(out, orderedField{key: f.name, value: quoteScalar(fv)})
+			continue
+		}
+
+		out = append(out, orderedField{key: f.name, value: e.transform(fv)})
+	}
+
+	return out
+}
+
+// jsonField menyimpan metadata field struct hasil parsing tag json.
+type jsonField struct {
+	name      string
+	index     []int
+	omitEmpty bool
+	omitZero  bool
+	quoted    bool
+}
+
+var jsonFieldCache sync.Map // map[reflect.Type][]jsonField
+
+// cachedJSONFields mengembalikan daftar field yang di-encode untuk tipe struct t,
+// mengikuti aturan tag `json` dan promosi field dari embedded struct.
+func cachedJSONFields(t reflect.Type) []jsonField {
+	if cached, ok := jsonFieldCache.Load(t); ok {
+		return cached.([]jsonField)
+	}
+
+	fields := collectJSONFields(t, nil)
+	jsonFieldCache.Store(t, fields)
+	return fields
+}
+
+func collectJSONFields(t reflect.Type, parentIndex []int) []jsonField {
+	var fields []jsonField
+	positions := make(map[string]int)
+
+	add := func(f jsonField) {
+		if pos, exists := positions[f.name]; exists {
+			// Field yang lebih dangkal menang, sesuai aturan encoding/json
+			if len(fields[pos].index) <= len(f.index) {
+				return
+			}
+			fields[pos] = f
+			return
+		}
+		positions[f.name] = len(fields)
+		fields = append(fields, f)
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		index := make([]int, len(parentIndex)+1)
+		copy(index, parentIndex)
+		index[len(parentIndex)] = i
+
+		name, opts, _ := strings.Cut(tag, ",")
+
+		if sf.Anonymous && name == "" {
+			ft := sf.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				for _, f := range collectJSONFields(ft, index) {
+					add(f)
+				}
+				continue
+			}
+		}
+
+		if !sf.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = sf.Name
+		}
+
+		f := jsonField{name: name, index: index}
+		for _, opt := range strings.Split(opts, ",") {
+			switch opt {
+			case "omitempty":
+				f.omitEmpty = true
+			case "omitzero":
+				f.omitZero = true
+			case "string":
+				f.quoted = true
+			}
+		}
+		add(f)
+	}
+
+	sort.SliceStable(fields, func(i, j int) bool {
+		return lessIndex(fields[i].index, fields[j].index)
+	})
+	return fields
+}
+
+// lessIndex membandingkan index path field untuk mempertahankan urutan deklarasi.
+func lessIndex(a, b []int) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return len(a) < len(b)
+}
+
+// fieldByIndex seperti reflect.Value.FieldByIndex tetapi aman untuk embedded pointer nil.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, idx := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(idx)
+	}
+	return v, true
+}
+
+// isEmptyJSONValue mengikuti definisi "empty" dari opsi omitempty encoding/json.
+func isEmptyJSONValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.Interface, reflect.Pointer:
+		return v.IsZero()
+	}
+	return false
+}
+
+// quoteScalar menerapkan opsi tag `json:",string"` untuk nilai skalar.
+func quoteScalar(v reflect.Value) interface{} {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits())
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.String:
+		b, _ := json.Marshal(v.String())
+		return string(b)
+	}
+	return v.Interface()
+}
+
+// mapKeyString mengonversi key map menjadi string seperti encoding/json.
+func mapKeyString(k reflect.Value) (string, bool) {
+	if k.Kind() == reflect.String {
+		return k.String(), true
+	}
+	if tm, ok := k.Interface().(encoding.TextMarshaler); ok {
+		if k.Kind() == reflect.Pointer && k.IsNil() {
+			return "", true
+		}
+		b, err := tm.MarshalText()
+		return string(b), err == nil
+	}
+	switch k.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(k.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(k.Uint(), 10), true
+	}
+	return "", false
+}
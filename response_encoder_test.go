@@ -0,0 +1,133 @@
+package dim
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+type encoderTestBase struct {
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type encoderTestOrder struct {
+	ID        int64      `json:"id"`
+	Quantity  int        `json:"quantity"`
+	Name      string     `json:"name"`
+	Note      string     `json:"note,omitempty"`
+	Secret    string     `json:"-"`
+	PaidAt    time.Time  `json:"paid_at"`
+	ShippedAt *time.Time `json:"shipped_at"`
+	Code      int32      `json:"code,string"`
+	encoderTestBase
+}
+
+func encodeToString(t *testing.T, data interface{}, config ResponseConfig) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := encodeJSON(&buf, data, config); err != nil {
+		t.Fatalf("encodeJSON failed: %v", err)
+	}
+	return strings.TrimSpace(buf.String())
+}
+
+func TestEncodeJSON_DefaultConfig(t *testing.T) {
+	got := encodeToString(t, map[string]int64{"id": 1}, ResponseConfig{})
+	if got != `{"id":1}` {
+		t.Errorf("unexpected output: %s", got)
+	}
+}
+
+func TestEncodeJSON_Int64AsString(t *testing.T) {
+	order := encoderTestOrder{ID: 9007199254740993, Quantity: 2, Name: "kopi", Code: 7}
+
+	got := encodeToString(t, order, ResponseConfig{Int64AsString: true, OmitZeroTime: true})
+	want := `{"id":"9007199254740993","quantity":2,"name":"kopi","shipped_at":null,"code":"7"}`
+	if got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+}
+
+func TestEncodeJSON_TimeLocationAndFormat(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*3600)
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	order := encoderTestOrder{
+		ID:              1,
+		PaidAt:          created,
+		encoderTestBase: encoderTestBase{CreatedAt: created},
+	}
+
+	got := encodeToString(t, order, ResponseConfig{TimeLocation: jakarta})
+	if !strings.Contains(got, `"paid_at":"2026-01-01T07:00:00+07:00"`) {
+		t.Errorf("expected paid_at converted to WIB, got %s", got)
+	}
+	if !strings.Contains(got, `"created_at":"2026-01-01T07:00:00+07:00"`) {
+		t.Errorf("expected embedded created_at promoted and converted, got %s", got)
+	}
+
+	got = encodeToString(t, order, ResponseConfig{TimeFormat: "2006-01-02"})
+	if !strings.Contains(got, `"paid_at":"2026-01-01"`) {
+		t.Errorf("expected custom time format, got %s", got)
+	}
+}
+
+func TestEncodeJSON_OmitZeroTime(t *testing.T) {
+	order := encoderTestOrder{ID: 1}
+
+	got := encodeToString(t, order, ResponseConfig{OmitZeroTime: true})
+	if strings.Contains(got, "paid_at") || strings.Contains(got, "created_at") {
+		t.Errorf("expected zero times to be omitted, got %s", got)
+	}
+
+	got = encodeToString(t, []time.Time{{}}, ResponseConfig{OmitZeroTime: true})
+	if got != `[null]` {
+		t.Errorf("expected zero time in slice to be null, got %s", got)
+	}
+}
+
+type encoderTestCents int64
+
+func (c encoderTestCents) MarshalJSON() ([]byte, error) {
+	return []byte(`"Rp` + strconv.FormatInt(int64(c), 10) + `"`), nil
+}
+
+func TestEncodeJSON_PreservesMarshalers(t *testing.T) {
+	data := map[string]interface{}{"price": encoderTestCents(1500), "count": int64(5)}
+
+	got := encodeToString(t, data, ResponseConfig{Int64AsString: true})
+	want := `{"count":"5","price":"Rp1500"}`
+	if got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+}
+
+func TestJson_PerCallOverride(t *testing.T) {
+	SetResponseConfig(ResponseConfig{Int64AsString: true})
+	defer SetResponseConfig(ResponseConfig{})
+
+	rec := httptest.NewRecorder()
+	Json(rec, 200, map[string]int64{"id": 42})
+	if got := strings.TrimSpace(rec.Body.String()); got != `{"id":"42"}` {
+		t.Errorf("expected global config applied, got %s", got)
+	}
+
+	rec = httptest.NewRecorder()
+	Json(rec, 200, map[string]int64{"id": 42}, WithInt64AsString(false))
+	if got := strings.TrimSpace(rec.Body.String()); got != `{"id":42}` {
+		t.Errorf("expected per-call override, got %s", got)
+	}
+}
+
+func TestJsonPagination_AppliesConfig(t *testing.T) {
+	rec := httptest.NewRecorder()
+	JsonPagination(rec, 200, []int64{1, 2}, PaginationMeta{Page: 1, PerPage: 2, Total: 2, TotalPages: 1}, WithInt64AsString(true))
+
+	want := `{"data":["1","2"],"meta":{"page":1,"per_page":2,"total":2,"total_pages":1}}`
+	if got := strings.TrimSpace(rec.Body.String()); got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+}
@@ -1,6 +1,7 @@
 package dim
 
 import (
+	"bytes"
 	"context"
 	"io/fs"
 	"net/http"
@@ -21,6 +22,7 @@ type RouteInfo struct {
 	Path        string   // URL path pattern
 	Handler     string   // Nama handler function
 	Middlewares []string // Daftar nama middleware yang diterapkan
+	Internal    bool     // true jika route didaftarkan lewat Internal (dilayani di INTERNAL_PORT)
 }
 
 // staticEntry holds per-method handlers for a static (parameter-free) route path.
@@ -42,6 +44,8 @@ type Router struct {
 	lock          sync.RWMutex
 	routes        []RouteInfo                               // Semua route yang terdaftar
 	routeCache    *cache.InMemoryCache[string, []RouteInfo] // Cache untuk GetRoutes()
+	internal      *Router                                   // Router untuk route Internal(), dibuat saat pertama dipakai
+	isInternal    bool
 }
 
 // NewRouter membuat instance router baru menggunakan stdlib http.ServeMux.
@@ -86,10 +90,74 @@ func (r *Router) Use(middleware ...MiddlewareFunc) {
 	r.initialized = false
 }
 
-// Build membuild handler chain secara eksplisit.
+// UseShared menambahkan middleware global ke router publik dan router Internal sekaligus,
+// misal Recovery, request ID, atau logger. Middleware yang hanya relevan untuk API publik
+// (CORS, CSRF, rate limit) cukup didaftarkan dengan Use.
+//
+// Parameter:
+//   - middleware: daftar variadic dari MiddlewareFunc yang akan ditambahkan
+//
+// Contoh:
+//
+//	router.UseShared(dim.Recovery(logger), dim.LoggerMiddleware(logger))
+//	router.Use(dim.CORS(cfg.CORS))
+func (r *Router) UseShared(middleware ...MiddlewareFunc) {
+	r.Use(middleware...)
+	if !r.isInternal {
+		r.Internal().Use(middleware...)
+	}
+}
+
+// Internal mengembalikan router untuk route internal (metrics, health check, admin, pprof)
+// yang hanya dilayani di INTERNAL_PORT oleh Server, bukan di port publik. Router internal
+// memiliki middleware global sendiri (Use) dan dibuat sekali saat pertama dipanggil.
+// Middleware yang didaftarkan dengan Use di router publik tidak diterapkan; gunakan UseShared.
+//
+// Mengembalikan:
+//   - *Router: router internal; memanggil Internal di router internal mengembalikan router itu sendiri
+//
+// Contoh:
+//
+//	internal := router.Internal()
+//	internal.Get("/metrics", metrics.Handler())
+//	internal.Health("/healthz", "/readyz")
+//
+//	// INTERNAL_PORT=9090
+//	dim.NewServer(cfg.Server, router).Run(ctx)
+func (r *Router) Internal() *Router {
+	if r.isInternal {
+		return r
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.internal == nil {
+		r.internal = NewRouter()
+		r.internal.isInternal = true
+	}
+	return r.internal
+}
+
+// InternalHandler mengembalikan handler route Internal, atau nil jika Internal belum dipakai.
+// Server memanggilnya otomatis; gunakan langsung untuk melayani route internal dengan server sendiri.
+func (r *Router) InternalHandler() http.Handler {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	if r.internal == nil {
+		return nil
+	}
+	return r.internal
+}
+
+// Build membuild handler chain secara eksplisit, termasuk router Internal jika ada.
 // Disarankan dipanggil di main() sebelum http.ListenAndServe untuk performa terbaik (menghindari locking saat request).
 // Jika tidak dipanggil, handler akan dibangun secara lazy pada request pertama (dengan sedikit overhead locking).
+// Build juga mencatat konflik route (lihat Conflicts) sebagai warning lewat slog.
 func (r *Router) Build() {
+	if internal := r.InternalHandler(); internal != nil {
+		internal.(*Router).Build()
+	}
+	r.logRouteConflicts()
+
 	r.lock.Lock()
 	defer r.lock.Unlock()
 	r.cachedHandler = r.buildHandler()
@@ -248,6 +316,7 @@ func (r *Router) Static(prefix string, root fs.FS, middleware ...MiddlewareFunc)
 
 // SPA (Single Page Application) melayani aplikasi frontend modern dengan fallback ke index.html.
 // Secara otomatis menambahkan header keamanan dan mematikan cache untuk file index agar user selalu mendapat versi terbaru.
+// Jika middleware SecureHeaders dengan CSP nonce dipasang, CSPNoncePlaceholder di index.html diganti dengan nonce per request.
 func (r *Router) SPA(root fs.FS, index string, middleware ...MiddlewareFunc) {
 	baseHandler := func(w http.ResponseWriter, req *http.Request) {
 		path := strings.TrimPrefix(req.URL.Path, "/")
@@ -266,7 +335,8 @@ func (r *Router) SPA(root fs.FS, index string, middleware ...MiddlewareFunc) {
 			f.Close()
 		}
 
-		if err != nil || isDir {
+		// index.html selalu disajikan lewat jalur ini agar header anti-cache dan nonce CSP konsisten
+		if err != nil || isDir || path == index {
 			// SPA Fallback: Sajikan index.html
 			indexContent, errRead := fs.ReadFile(root, index)
 			if errRead != nil {
@@ -281,6 +351,11 @@ func (r *Router) SPA(root fs.FS, index string, middleware ...MiddlewareFunc) {
 			w.Header().Set("Pragma", "no-cache")
 			w.Header().Set("Expires", "0")
 
+			// Sisipkan nonce CSP jika SecureHeaders memasang nonce untuk request ini
+			if nonce := GetCSPNonce(req); nonce != "" {
+				indexContent = bytes.ReplaceAll(indexContent, []byte(CSPNoncePlaceholder), []byte(nonce))
+			}
+
 			w.Write(indexContent)
 			return
 		}
@@ -464,7 +539,8 @@ func (r *Router) buildHandler() http.Handler {
 	return base
 }
 
-// GetRoutes mengembalikan semua route yang terdaftar dengan caching.
+// GetRoutes mengembalikan semua route yang terdaftar dengan caching, diikuti route Internal
+// (ditandai RouteInfo.Internal).
 // Thread-safe dan menggunakan in-memory cache untuk performa optimal.
 //
 // Mengembalikan:
@@ -477,6 +553,18 @@ func (r *Router) buildHandler() http.Handler {
 //	    fmt.Printf("%s %s -> %s\n", route.Method, route.Path, route.Handler)
 //	}
 func (r *Router) GetRoutes() []RouteInfo {
+	routes := r.cachedRoutes()
+	if internal := r.InternalHandler(); internal != nil {
+		for _, route := range internal.(*Router).GetRoutes() {
+			route.Internal = true
+			routes = append(routes, route)
+		}
+	}
+	return routes
+}
+
+// cachedRoutes mengembalikan copy route router ini (tanpa route Internal) dari cache.
+func (r *Router) cachedRoutes() []RouteInfo {
 	r.lock.RLock()
 	defer r.lock.RUnlock()
 
@@ -44,6 +44,9 @@ func (c *RouteListCommand) Execute(ctx *CommandContext) error {
 		if len(route.Middlewares) > 0 {
 			middlewareStr = fmt.Sprintf(" [%s]", strings.Join(route.Middlewares, ", "))
 		}
+		if route.Internal {
+			middlewareStr += " (internal)"
+		}
 
 		fmt.Printf("%-7s %-35s -> %-45s%s\n",
 			route.Method,
@@ -53,6 +56,14 @@ func (c *RouteListCommand) Execute(ctx *CommandContext) error {
 		)
 	}
 
+	if conflicts := ctx.Router.Conflicts(); len(conflicts) > 0 {
+		fmt.Println()
+		fmt.Printf("⚠ Warning: %d route conflict(s) detected:\n", len(conflicts))
+		for _, conflict := range conflicts {
+			fmt.Printf("  %s\n", conflict)
+		}
+	}
+
 	// Display warning if binary is stripped
 	if strippedCount > 0 {
 		fmt.Println()
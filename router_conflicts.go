@@ -0,0 +1,298 @@
+package dim
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// RouteConflictKind mengelompokkan jenis konflik route yang ditemukan oleh Router.Conflicts.
+type RouteConflictKind string
+
+const (
+	// RouteConflictDuplicate: method dan path yang sama didaftarkan lebih dari sekali;
+	// handler yang terakhir didaftarkan menggantikan handler sebelumnya.
+	RouteConflictDuplicate RouteConflictKind = "duplicate"
+	// RouteConflictUnreachable: route tidak pernah cocok karena parameter di posisi yang sama
+	// sudah memakai nama lain (router hanya mencoba satu parameter per posisi).
+	RouteConflictUnreachable RouteConflictKind = "unreachable"
+	// RouteConflictShadowed: route yang lebih spesifik untuk method lain menutupi route ini,
+	// sehingga request ke path tersebut mendapat 405 alih-alih diteruskan ke route ini.
+	RouteConflictShadowed RouteConflictKind = "shadowed"
+	// RouteConflictInvalid: pola route tidak dapat dicocokkan dengan benar, misal parameter
+	// yang tidak menempati satu segmen penuh atau catch-all yang bukan segmen terakhir.
+	RouteConflictInvalid RouteConflictKind = "invalid"
+)
+
+// RouteConflict menjelaskan satu route yang bertabrakan, tertutup, atau tidak valid.
+type RouteConflict struct {
+	Kind          RouteConflictKind // Jenis konflik
+	Method        string            // HTTP method route yang terdampak
+	Path          string            // Pola path route yang terdampak
+	ConflictsWith string            // Pola path route lain penyebab konflik (kosong untuk invalid)
+	Message       string            // Penjelasan konflik
+	Suggestion    string            // Saran perbaikan
+	Internal      bool              // true jika route didaftarkan lewat Internal
+}
+
+// String memformat konflik dalam satu baris untuk log dan output CLI.
+func (c RouteConflict) String() string {
+	s := fmt.Sprintf("[%s] %s %s: %s", c.Kind, c.Method, c.Path, c.Message)
+	if c.Suggestion != "" {
+		s += " (" + c.Suggestion + ")"
+	}
+	return s
+}
+
+// Conflicts menganalisis route yang terdaftar (termasuk route Internal) dan mengembalikan
+// route yang duplikat, tidak pernah tercapai, tertutup route lain, atau polanya tidak valid,
+// beserta saran perbaikannya. Route dengan pola tidak valid, duplikat, atau tidak tercapai
+// tidak dianalisis lebih lanjut.
+// Build mencatat hasil ini sebagai warning; gunakan CheckRoutes untuk menggagalkan startup atau test.
+//
+// Mengembalikan:
+//   - []RouteConflict: konflik yang ditemukan, kosong jika semua route aman
+//
+// Contoh:
+//
+//	router.Post("/users/new", createUser)
+//	router.Get("/users/{id}", getUser)
+//	for _, c := range router.Conflicts() {
+//	    fmt.Println(c) // [shadowed] GET /users/{id}: GET /users/new responds 405 ...
+//	}
+func (r *Router) Conflicts() []RouteConflict {
+	conflicts := analyzeRouteConflicts(r.cachedRoutes())
+	if internal := r.InternalHandler(); internal != nil {
+		for _, c := range analyzeRouteConflicts(internal.(*Router).cachedRoutes()) {
+			c.Internal = true
+			conflicts = append(conflicts, c)
+		}
+	}
+	return conflicts
+}
+
+// CheckRoutes mengembalikan error yang merangkum semua konflik dari Conflicts, atau nil jika
+// tidak ada. Cocok dipanggil di main() sebelum server dijalankan atau di test aplikasi.
+//
+// Mengembalikan:
+//   - error: daftar konflik route, atau nil
+//
+// Contoh:
+//
+//	if err := router.CheckRoutes(); err != nil {
+//	    log.Fatal(err)
+//	}
+func (r *Router) CheckRoutes() error {
+	conflicts := r.Conflicts()
+	if len(conflicts) == 0 {
+		return nil
+	}
+	lines := make([]string, len(conflicts))
+	for i, c := range conflicts {
+		lines[i] = c.String()
+	}
+	return fmt.Errorf("dim: %d route conflict(s):\n  %s", len(conflicts), strings.Join(lines, "\n  "))
+}
+
+// logRouteConflicts writes the router's own conflicts (not Internal, which logs on its own Build) as warnings.
+func (r *Router) logRouteConflicts() {
+	for _, c := range analyzeRouteConflicts(r.cachedRoutes()) {
+		slog.Warn("route conflict",
+			"kind", string(c.Kind),
+			"method", c.Method,
+			"path", c.Path,
+			"conflicts_with", c.ConflictsWith,
+			"message", c.Message,
+			"suggestion", c.Suggestion,
+			"internal", r.isInternal,
+		)
+	}
+}
+
+// routeSegment is one "/"-separated part of a route pattern.
+type routeSegment struct {
+	typ  nodeTyp
+	name string // static text, or parameter name for ntParam / ntCatchAll
+}
+
+// parseRouteSegments splits pattern into segments, reporting why the pattern is invalid if
+// the radix tree would not match it the way it reads.
+func parseRouteSegments(pattern string) ([]routeSegment, string) {
+	parts := strings.Split(strings.TrimPrefix(pattern, "/"), "/")
+	segments := make([]routeSegment, len(parts))
+	seen := make(map[string]bool)
+	for i, part := range parts {
+		if !strings.ContainsAny(part, "{}") {
+			segments[i] = routeSegment{typ: ntStatic, name: part}
+			continue
+		}
+		if part[0] != '{' || part[len(part)-1] != '}' || strings.Count(part, "{") != 1 || strings.Count(part, "}") != 1 {
+			return nil, fmt.Sprintf("parameter segment %q must span the whole path segment", part)
+		}
+
+		name := part[1 : len(part)-1]
+		typ := ntParam
+		if strings.HasSuffix(name, "...") {
+			name = strings.TrimSuffix(name, "...")
+			typ = ntCatchAll
+			if i != len(parts)-1 {
+				return nil, fmt.Sprintf("catch-all %q must be the last path segment", part)
+			}
+		}
+		if name == "" {
+			return nil, fmt.Sprintf("parameter segment %q has no name", part)
+		}
+		if seen[name] {
+			return nil, fmt.Sprintf("parameter {%s} is used more than once", name)
+		}
+		seen[name] = true
+		segments[i] = routeSegment{typ: typ, name: name}
+	}
+	return segments, ""
+}
+
+// routeShape returns the pattern with parameter names erased, identifying routes the tree treats as the same path.
+func routeShape(segments []routeSegment) string {
+	parts := make([]string, len(segments))
+	for i, s := range segments {
+		switch s.typ {
+		case ntParam:
+			parts[i] = "{}"
+		case ntCatchAll:
+			parts[i] = "{...}"
+		default:
+			parts[i] = s.name
+		}
+	}
+	return "/" + strings.Join(parts, "/")
+}
+
+// routeCovers reports whether every path matched by a is also matched by b.
+func routeCovers(b, a []routeSegment) bool {
+	for i, sa := range a {
+		if i >= len(b) {
+			return false
+		}
+		switch sb := b[i]; sb.typ {
+		case ntCatchAll:
+			return true
+		case ntParam:
+			if sa.typ == ntCatchAll || (sa.typ == ntStatic && sa.name == "") {
+				return false
+			}
+		default:
+			if sa.typ != ntStatic || sa.name != sb.name {
+				return false
+			}
+		}
+	}
+	return len(a) == len(b)
+}
+
+// analyzeRouteConflicts mirrors the matching rules of serveTree and treeNode.match to find
+// routes that silently lose to other routes.
+func analyzeRouteConflicts(routes []RouteInfo) []RouteConflict {
+	type parsedRoute struct {
+		RouteInfo
+		segments []routeSegment
+		shape    string
+	}
+
+	var conflicts []RouteConflict
+	var parsed []parsedRoute
+	registered := make(map[string]bool)   // method + path
+	paramNames := make(map[string]string) // shape prefix + position → first pattern
+	shapeMethods := make(map[string]map[string]bool)
+
+	for _, route := range routes {
+		segments, problem := parseRouteSegments(route.Path)
+		if problem != "" {
+			conflicts = append(conflicts, RouteConflict{
+				Kind:       RouteConflictInvalid,
+				Method:     route.Method,
+				Path:       route.Path,
+				Message:    problem,
+				Suggestion: "use whole segments such as /files/{name} or /files/{path...}",
+			})
+			continue
+		}
+
+		key := route.Method + " " + route.Path
+		if registered[key] {
+			conflicts = append(conflicts, RouteConflict{
+				Kind:          RouteConflictDuplicate,
+				Method:        route.Method,
+				Path:          route.Path,
+				ConflictsWith: route.Path,
+				Message:       "registered more than once; the last registration replaces the earlier handler",
+				Suggestion:    "remove one registration or give it a distinct path",
+			})
+			continue
+		}
+		registered[key] = true
+
+		// The tree keeps one parameter child per position; later names get a node that is never tried.
+		reachable := true
+		for i, s := range segments {
+			if s.typ == ntStatic {
+				continue
+			}
+			position := routeShape(segments[:i]) + fmt.Sprintf("#%d", s.typ)
+			first, ok := paramNames[position]
+			if !ok {
+				paramNames[position] = route.Path
+				continue
+			}
+			firstSegments, _ := parseRouteSegments(first)
+			if want := firstSegments[i].name; want != s.name {
+				conflicts = append(conflicts, RouteConflict{
+					Kind:          RouteConflictUnreachable,
+					Method:        route.Method,
+					Path:          route.Path,
+					ConflictsWith: first,
+					Message:       fmt.Sprintf("parameter {%s} never matches because %s uses {%s} at the same position", s.name, first, want),
+					Suggestion:    fmt.Sprintf("rename {%s} to {%s}", s.name, want),
+				})
+				reachable = false
+				break
+			}
+		}
+		if !reachable {
+			continue
+		}
+
+		shape := routeShape(segments)
+		if shapeMethods[shape] == nil {
+			shapeMethods[shape] = make(map[string]bool)
+		}
+		shapeMethods[shape][route.Method] = true
+		parsed = append(parsed, parsedRoute{RouteInfo: route, segments: segments, shape: shape})
+	}
+
+	// A more specific path without the request method answers 405 instead of falling back.
+	reported := make(map[string]bool)
+	for _, general := range parsed {
+		for _, specific := range parsed {
+			if specific.shape == general.shape || shapeMethods[specific.shape][general.Method] {
+				continue
+			}
+			if !routeCovers(general.segments, specific.segments) || routeCovers(specific.segments, general.segments) {
+				continue
+			}
+			key := general.Method + " " + general.Path + " " + specific.shape
+			if reported[key] {
+				continue
+			}
+			reported[key] = true
+			conflicts = append(conflicts, RouteConflict{
+				Kind:          RouteConflictShadowed,
+				Method:        general.Method,
+				Path:          general.Path,
+				ConflictsWith: specific.Path,
+				Message:       fmt.Sprintf("%s %s responds 405 instead of reaching this route because %s has no %s handler", general.Method, specific.Path, specific.Path, general.Method),
+				Suggestion:    fmt.Sprintf("register %s %s as well, or move one of the routes to a distinct path", general.Method, specific.Path),
+			})
+		}
+	}
+	return conflicts
+}
@@ -0,0 +1,138 @@
+package dim
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func conflictTestHandler(w http.ResponseWriter, r *http.Request) {}
+
+func TestRouterConflicts_Shadowed(t *testing.T) {
+	router := NewRouter()
+	router.Post("/users/new", conflictTestHandler)
+	router.Get("/users/{id}", conflictTestHandler)
+	router.Get("/users", conflictTestHandler)
+
+	conflicts := router.Conflicts()
+	if len(conflicts) != 1 {
+		t.Fatalf("conflicts = %v", conflicts)
+	}
+	c := conflicts[0]
+	if c.Kind != RouteConflictShadowed || c.Method != "GET" || c.Path != "/users/{id}" || c.ConflictsWith != "/users/new" {
+		t.Errorf("conflict = %+v", c)
+	}
+	if !strings.Contains(c.Suggestion, "register GET /users/new") {
+		t.Errorf("suggestion = %q", c.Suggestion)
+	}
+
+	// Analisis sesuai perilaku router: GET /users/new mendapat 405
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/new", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("GET /users/new status = %d", rec.Code)
+	}
+
+	// Route spesifik dengan method yang sama tidak dilaporkan, tanpa peduli urutan pendaftaran
+	router.Get("/users/new", conflictTestHandler)
+	if conflicts := router.Conflicts(); len(conflicts) != 0 {
+		t.Errorf("conflicts after GET /users/new = %v", conflicts)
+	}
+}
+
+func TestRouterConflicts_ShadowedInTree(t *testing.T) {
+	router := NewRouter()
+	router.Get("/files/{path...}", conflictTestHandler)
+	router.Post("/files/{name}", conflictTestHandler)
+	router.Get("/posts/{id}/{action}", conflictTestHandler)
+	router.Delete("/posts/{id}/comments", conflictTestHandler)
+
+	conflicts := router.Conflicts()
+	if len(conflicts) != 2 {
+		t.Fatalf("conflicts = %v", conflicts)
+	}
+	for i, want := range [][2]string{{"/files/{path...}", "/files/{name}"}, {"/posts/{id}/{action}", "/posts/{id}/comments"}} {
+		if conflicts[i].Kind != RouteConflictShadowed || conflicts[i].Path != want[0] || conflicts[i].ConflictsWith != want[1] {
+			t.Errorf("conflict %d = %+v", i, conflicts[i])
+		}
+	}
+
+	for _, path := range []string{"/files/readme", "/posts/1/comments"} {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != http.StatusMethodNotAllowed {
+			t.Errorf("GET %s status = %d", path, rec.Code)
+		}
+	}
+}
+
+func TestRouterConflicts_UnreachableParamName(t *testing.T) {
+	router := NewRouter()
+	router.Get("/users/{id}", conflictTestHandler)
+	api := router.Group("/users")
+	api.Get("/{userID}/posts", conflictTestHandler)
+
+	conflicts := router.Conflicts()
+	if len(conflicts) != 1 {
+		t.Fatalf("conflicts = %v", conflicts)
+	}
+	c := conflicts[0]
+	if c.Kind != RouteConflictUnreachable || c.Path != "/users/{userID}/posts" || c.ConflictsWith != "/users/{id}" || c.Suggestion != "rename {userID} to {id}" {
+		t.Errorf("conflict = %+v", c)
+	}
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/1/posts", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("GET /users/1/posts status = %d", rec.Code)
+	}
+}
+
+func TestRouterConflicts_DuplicateAndInvalid(t *testing.T) {
+	router := NewRouter()
+	router.Get("/health", conflictTestHandler)
+	router.Group("/").Get("/health", conflictTestHandler)
+	router.Get("/files/{name}.json", conflictTestHandler)
+	router.Get("/assets/{path...}/meta", conflictTestHandler)
+	router.Get("/orgs/{id}/users/{id}", conflictTestHandler)
+
+	conflicts := router.Conflicts()
+	want := []struct {
+		kind    RouteConflictKind
+		path    string
+		message string
+	}{
+		{RouteConflictDuplicate, "/health", "registered more than once"},
+		{RouteConflictInvalid, "/files/{name}.json", "must span the whole path segment"},
+		{RouteConflictInvalid, "/assets/{path...}/meta", "must be the last path segment"},
+		{RouteConflictInvalid, "/orgs/{id}/users/{id}", "used more than once"},
+	}
+	if len(conflicts) != len(want) {
+		t.Fatalf("conflicts = %v", conflicts)
+	}
+	for i, w := range want {
+		if conflicts[i].Kind != w.kind || conflicts[i].Path != w.path || !strings.Contains(conflicts[i].Message, w.message) {
+			t.Errorf("conflict %d = %+v, want %s %s", i, conflicts[i], w.kind, w.path)
+		}
+	}
+}
+
+func TestRouterCheckRoutes(t *testing.T) {
+	router := NewRouter()
+	router.Get("/users/{id}", conflictTestHandler)
+	router.Get("/users", conflictTestHandler)
+	router.Internal().Get("/metrics", conflictTestHandler)
+	if err := router.CheckRoutes(); err != nil {
+		t.Fatalf("CheckRoutes() = %v", err)
+	}
+
+	router.Internal().Get("/metrics", conflictTestHandler)
+	err := router.CheckRoutes()
+	if err == nil || !strings.Contains(err.Error(), "1 route conflict(s)") || !strings.Contains(err.Error(), "[duplicate] GET /metrics") {
+		t.Fatalf("CheckRoutes() = %v", err)
+	}
+	if conflicts := router.Conflicts(); !conflicts[0].Internal {
+		t.Errorf("conflict = %+v, want Internal", conflicts[0])
+	}
+}
@@ -3,6 +3,7 @@ package dim
 import (
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -504,3 +505,73 @@ func TestRouter_GetRoutes_CleanerNames(t *testing.T) {
 		t.Errorf("Expected handler name '%s', got '%s'", expectedHandler, route.Handler)
 	}
 }
+
+func TestRouter_Internal(t *testing.T) {
+	router := NewRouter()
+	tag := func(name string) MiddlewareFunc {
+		return func(next HandlerFunc) HandlerFunc {
+			return func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Add("X-Middleware", name)
+				next(w, r)
+			}
+		}
+	}
+	router.UseShared(tag("shared"))
+	router.Use(tag("public"))
+	router.Internal().Use(tag("internal"))
+
+	router.Get("/api/users", func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("users")) })
+	router.Internal().Get("/metrics", func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("metrics")) })
+	if router.Internal() != router.Internal() || router.Internal().Internal() != router.Internal() {
+		t.Fatal("Internal() should return the same router")
+	}
+	router.Build()
+
+	// Route internal tidak dilayani di router publik
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("public /metrics status = %d, want 404", rec.Code)
+	}
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest("GET", "/api/users", nil))
+	if got := rec.Header().Values("X-Middleware"); rec.Body.String() != "users" || strings.Join(got, ",") != "shared,public" {
+		t.Errorf("public response = %q, middleware = %v", rec.Body.String(), got)
+	}
+
+	internal := router.InternalHandler()
+	rec = httptest.NewRecorder()
+	internal.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if got := rec.Header().Values("X-Middleware"); rec.Body.String() != "metrics" || strings.Join(got, ",") != "shared,internal" {
+		t.Errorf("internal response = %q, middleware = %v", rec.Body.String(), got)
+	}
+	rec = httptest.NewRecorder()
+	internal.ServeHTTP(rec, httptest.NewRequest("GET", "/api/users", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("internal /api/users status = %d, want 404", rec.Code)
+	}
+
+	routes := router.GetRoutes()
+	if len(routes) != 2 || routes[0].Internal || !routes[1].Internal || routes[1].Path != "/metrics" {
+		t.Errorf("GetRoutes() = %+v", routes)
+	}
+}
+
+func TestRouter_InternalHandler_Unused(t *testing.T) {
+	if handler := NewRouter().InternalHandler(); handler != nil {
+		t.Errorf("InternalHandler() = %v, want nil", handler)
+	}
+}
+
+func TestRouter_ParamRouteRequiresFullPath(t *testing.T) {
+	router := NewRouter()
+	router.Get("/users/{id}", func(w http.ResponseWriter, r *http.Request) {})
+
+	for path, want := range map[string]int{"/users/1": http.StatusOK, "/users/1/posts": http.StatusNotFound} {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+		if rec.Code != want {
+			t.Errorf("GET %s status = %d, want %d", path, rec.Code, want)
+		}
+	}
+}
@@ -213,6 +213,9 @@ func (n *treeNode) matchInternal(method, path string, keys, vals *[]string) (Han
 	}
 
 	// 4. Endpoint on the current node (exact match after prefix consumed).
+	if path != "" {
+		return nil, "", false
+	}
 	if ep, ok := n.endpoints[method]; ok {
 		return ep.handler, "", true
 	}
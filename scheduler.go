@@ -0,0 +1,387 @@
+package dim
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	// ErrTaskNotFound dikembalikan Scheduler.RunNow untuk nama task yang tidak terdaftar.
+	ErrTaskNotFound = errors.New("scheduled task not found")
+	// ErrTaskExists dikembalikan saat mendaftarkan task dengan nama yang sudah dipakai.
+	ErrTaskExists = errors.New("scheduled task already registered")
+	// ErrTaskRunning dikembalikan Scheduler.RunNow jika run sebelumnya masih berjalan dan task
+	// tidak mengizinkan overlap.
+	ErrTaskRunning = errors.New("scheduled task is already running")
+)
+
+// TaskFunc adalah pekerjaan yang dijalankan Scheduler. ctx dibatalkan saat timeout task habis
+// atau Shutdown melewati batas waktunya.
+type TaskFunc func(ctx context.Context) error
+
+// SchedulerLocker memastikan setiap run terjadwal hanya dijalankan oleh satu instance aplikasi.
+// Implementasi bawaan: DatabaseSchedulerLocker.
+type SchedulerLocker interface {
+	// Acquire mengklaim run task untuk waktu slot. ok bernilai false jika slot tersebut sudah
+	// diklaim instance lain atau run sebelumnya masih memegang lock. Lock kedaluwarsa setelah ttl
+	// sehingga instance yang crash tidak menahan task selamanya; release dipanggil setelah run selesai.
+	Acquire(ctx context.Context, task string, slot time.Time, ttl time.Duration) (release func(), ok bool, err error)
+}
+
+// SchedulerOptions mengatur Scheduler. Nilai 0 memakai default.
+type SchedulerOptions struct {
+	// Location adalah timezone untuk menghitung ekspresi cron (default: time.Local).
+	Location *time.Location
+	// Timeout adalah batas waktu default setiap run (default: 10 menit). Dapat diganti per task
+	// dengan WithTaskTimeout.
+	Timeout time.Duration
+	// Locker mengaktifkan distributed lock sehingga task yang terdaftar di banyak instance hanya
+	// berjalan sekali per jadwal. Nil berarti setiap instance menjalankan task-nya sendiri.
+	Locker SchedulerLocker
+}
+
+// TaskOption mengubah pengaturan satu task.
+type TaskOption func(task *scheduledTask)
+
+// WithTaskTimeout mengganti batas waktu run task ini.
+func WithTaskTimeout(d time.Duration) TaskOption {
+	return func(task *scheduledTask) {
+		if d > 0 {
+			task.timeout = d
+		}
+	}
+}
+
+// WithTaskOverlap mengizinkan run baru dimulai walaupun run sebelumnya masih berjalan. Secara
+// default run tersebut dilewati dan dicatat sebagai missed.
+func WithTaskOverlap() TaskOption {
+	return func(task *scheduledTask) { task.overlap = true }
+}
+
+// WithoutTaskLock menjalankan task di setiap instance walaupun SchedulerOptions.Locker diisi,
+// misal untuk membersihkan cache lokal.
+func WithoutTaskLock() TaskOption {
+	return func(task *scheduledTask) { task.noLock = true }
+}
+
+// TaskRun adalah hasil satu run task yang diteruskan ke hook OnRun.
+type TaskRun struct {
+	Task string
+	// ScheduledAt adalah waktu jadwal run; untuk RunNow sama dengan StartedAt.
+	ScheduledAt time.Time
+	StartedAt   time.Time
+	Duration    time.Duration
+	Err         error
+	// Missed bernilai true jika run dilewati karena run sebelumnya masih berjalan.
+	Missed bool
+}
+
+type scheduledTask struct {
+	name     string
+	schedule Schedule
+	fn       TaskFunc
+	timeout  time.Duration
+	overlap  bool
+	noLock   bool
+	running  atomic.Bool
+}
+
+// Scheduler menjalankan task berulang berdasarkan ekspresi cron atau interval, misal menghapus
+// token kedaluwarsa atau merotasi key. Run yang masih berjalan tidak ditumpuk, setiap run dibatasi
+// timeout, dan dengan SchedulerLocker satu jadwal hanya dijalankan oleh satu instance.
+type Scheduler struct {
+	opts    SchedulerOptions
+	logger  *Logger
+	metrics *SchedulerMetrics
+
+	mu      sync.Mutex
+	tasks   map[string]*scheduledTask
+	onRun   []func(ctx context.Context, run TaskRun)
+	started bool
+	closed  bool
+
+	closing    context.Context // dibatalkan saat Shutdown: tidak ada run baru yang dimulai
+	stopTimers context.CancelFunc
+	stop       context.Context // dibatalkan saat Shutdown melewati batas waktunya: run berjalan dibatalkan
+	cancel     context.CancelFunc
+	loops      sync.WaitGroup
+	runs       sync.WaitGroup
+}
+
+// NewScheduler membuat Scheduler. Daftarkan task dengan Cron, Every, atau Add lalu panggil Start.
+//
+// Parameters:
+//   - opts: timezone, timeout default, dan distributed lock
+//
+// Returns:
+//   - *Scheduler: scheduler yang siap menerima task
+//
+// Example:
+//
+//	scheduler := dim.NewScheduler(dim.SchedulerOptions{
+//	    Locker: dim.NewDatabaseSchedulerLocker(db),
+//	}).WithLogger(logger).WithMetrics(dim.NewSchedulerMetrics(metrics))
+//
+//	scheduler.Cron("rotate_keys", "0 3 * * *", func(ctx context.Context) error {
+//	    _, err := keyManager.RotateIfDue(ctx)
+//	    return err
+//	})
+//	scheduler.Start()
+//	server.OnShutdown("scheduler", scheduler.Shutdown)
+func NewScheduler(opts SchedulerOptions) *Scheduler {
+	if opts.Location == nil {
+		opts.Location = time.Local
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Minute
+	}
+
+	s := &Scheduler{
+		opts:  opts,
+		tasks: make(map[string]*scheduledTask),
+	}
+	s.closing, s.stopTimers = context.WithCancel(context.Background())
+	s.stop, s.cancel = context.WithCancel(context.Background())
+	return s
+}
+
+// WithLogger mencatat run yang gagal (Error), run yang dilewati karena overlap (Warn), dan error
+// distributed lock.
+func (s *Scheduler) WithLogger(logger *Logger) *Scheduler {
+	s.logger = logger
+	return s
+}
+
+// WithMetrics mencatat durasi dan hasil setiap run serta run yang terlewat ke SchedulerMetrics.
+func (s *Scheduler) WithMetrics(metrics *SchedulerMetrics) *Scheduler {
+	s.metrics = metrics
+	return s
+}
+
+// OnRun mendaftarkan hook yang dipanggil setelah setiap run selesai atau dilewati, misal untuk
+// audit atau alert. Hook tidak dipanggil untuk jadwal yang dijalankan instance lain.
+func (s *Scheduler) OnRun(fn func(ctx context.Context, run TaskRun)) *Scheduler {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onRun = append(s.onRun, fn)
+	return s
+}
+
+// Cron mendaftarkan task dengan ekspresi cron (lihat ParseCron).
+//
+// Parameters:
+//   - name: nama unik task, dipakai untuk lock, log, dan label metric
+//   - spec: ekspresi cron, misal "*/15 * * * *" atau "@daily"
+//   - fn: pekerjaan yang dijalankan
+//   - opts: WithTaskTimeout, WithTaskOverlap, WithoutTaskLock
+//
+// Returns:
+//   - error: ekspresi tidak valid atau ErrTaskExists
+//
+// Example:
+//
+//	err := scheduler.Cron("cleanup_blocklist", "0 * * * *", blocklist.Cleanup,
+//	    dim.WithTaskTimeout(5*time.Minute))
+func (s *Scheduler) Cron(name, spec string, fn TaskFunc, opts ...TaskOption) error {
+	schedule, err := ParseCron(spec)
+	if err != nil {
+		return err
+	}
+	return s.add(name, schedule, fn, opts)
+}
+
+// Every mendaftarkan task yang berjalan setiap interval (minimal 1 detik). Waktu run disejajarkan
+// ke kelipatan interval (lihat Every), bukan dihitung dari waktu Start.
+func (s *Scheduler) Every(name string, interval time.Duration, fn TaskFunc, opts ...TaskOption) error {
+	if interval < time.Second {
+		return fmt.Errorf("invalid interval %s for task %s: must be at least 1s", interval, name)
+	}
+	return s.add(name, Every(interval), fn, opts)
+}
+
+// Add mendaftarkan task dengan Schedule custom.
+func (s *Scheduler) Add(name string, schedule Schedule, fn TaskFunc, opts ...TaskOption) error {
+	return s.add(name, schedule, fn, opts)
+}
+
+func (s *Scheduler) add(name string, schedule Schedule, fn TaskFunc, opts []TaskOption) error {
+	task := &scheduledTask{
+		name:     name,
+		schedule: schedule,
+		fn:       fn,
+		timeout:  s.opts.Timeout,
+	}
+	for _, opt := range opts {
+		opt(task)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.tasks[name]; ok {
+		return fmt.Errorf("%w: %s", ErrTaskExists, name)
+	}
+	s.tasks[name] = task
+	if s.started && !s.closed {
+		s.loops.Add(1)
+		go s.loop(task)
+	}
+	return nil
+}
+
+// Start menjalankan semua task yang terdaftar. Task yang didaftarkan setelah Start langsung
+// dijadwalkan. Pemanggilan berikutnya tidak berpengaruh.
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started || s.closed {
+		return
+	}
+	s.started = true
+	for _, task := range s.tasks {
+		s.loops.Add(1)
+		go s.loop(task)
+	}
+}
+
+// RunNow menjalankan task saat ini juga dan menunggu hasilnya, tanpa distributed lock, misal dari
+// command CLI atau endpoint admin.
+//
+// Returns:
+//   - error: error dari task, ErrTaskNotFound, atau ErrTaskRunning
+func (s *Scheduler) RunNow(ctx context.Context, name string) error {
+	s.mu.Lock()
+	task, ok := s.tasks[name]
+	s.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrTaskNotFound, name)
+	}
+	if !task.overlap && !task.running.CompareAndSwap(false, true) {
+		return fmt.Errorf("%w: %s", ErrTaskRunning, name)
+	}
+	if !task.overlap {
+		defer task.running.Store(false)
+	}
+	return s.run(ctx, task, time.Now())
+}
+
+// Shutdown berhenti memulai run baru lalu menunggu run yang sedang berjalan selesai. Jika ctx selesai
+// lebih dulu, run yang berjalan dibatalkan. Signature-nya sesuai ShutdownFunc untuk Server.OnShutdown.
+func (s *Scheduler) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+	s.stopTimers()
+
+	done := make(chan struct{})
+	go func() {
+		s.loops.Wait()
+		s.runs.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.cancel()
+		return ctx.Err()
+	}
+}
+
+// loop menunggu jadwal berikutnya task sampai Shutdown.
+func (s *Scheduler) loop(task *scheduledTask) {
+	defer s.loops.Done()
+	for {
+		next := task.schedule.Next(time.Now().In(s.opts.Location))
+		if next.IsZero() {
+			return
+		}
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-s.closing.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		s.dispatch(task, next)
+	}
+}
+
+// dispatch memulai run di goroutine terpisah agar jadwal berikutnya tetap dihitung tepat waktu.
+func (s *Scheduler) dispatch(task *scheduledTask, slot time.Time) {
+	if !task.overlap && !task.running.CompareAndSwap(false, true) {
+		if s.metrics != nil {
+			s.metrics.IncMissed(task.name)
+		}
+		if s.logger != nil {
+			s.logger.Warn("Scheduled run skipped, previous run still running", "task", task.name, "scheduled_at", slot)
+		}
+		s.notify(s.stop, TaskRun{Task: task.name, ScheduledAt: slot, Missed: true})
+		return
+	}
+
+	s.runs.Add(1)
+	go func() {
+		defer s.runs.Done()
+		if !task.overlap {
+			defer task.running.Store(false)
+		}
+
+		if s.opts.Locker != nil && !task.noLock {
+			release, ok, err := s.opts.Locker.Acquire(s.stop, task.name, slot, task.timeout+30*time.Second)
+			if err != nil {
+				if s.logger != nil {
+					s.logger.Error("Failed to acquire scheduler lock", "task", task.name, "error", err.Error())
+				}
+				return
+			}
+			if !ok {
+				return
+			}
+			defer release()
+		}
+		s.run(s.stop, task, slot) //nolint:errcheck
+	}()
+}
+
+// run menjalankan task dengan timeout lalu mencatat metric, log, dan hook.
+func (s *Scheduler) run(ctx context.Context, task *scheduledTask, slot time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, task.timeout)
+	start := time.Now()
+	err := s.call(ctx, task)
+	duration := time.Since(start)
+	cancel()
+
+	if s.metrics != nil {
+		s.metrics.ObserveRun(task.name, duration, err)
+	}
+	if err != nil && s.logger != nil {
+		s.logger.Error("Scheduled task failed", "task", task.name, "duration", duration.String(), "error", err.Error())
+	}
+	s.notify(ctx, TaskRun{Task: task.name, ScheduledAt: slot, StartedAt: start, Duration: duration, Err: err})
+	return err
+}
+
+// call memanggil fn task dan mengubah panic menjadi error.
+func (s *Scheduler) call(ctx context.Context, task *scheduledTask) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("scheduled task panicked: %v", p)
+		}
+	}()
+	return task.fn(ctx)
+}
+
+func (s *Scheduler) notify(ctx context.Context, run TaskRun) {
+	s.mu.Lock()
+	hooks := s.onRun
+	s.mu.Unlock()
+	for _, hook := range hooks {
+		hook(context.WithoutCancel(ctx), run)
+	}
+}
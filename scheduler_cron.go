@@ -0,0 +1,224 @@
+package dim
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule menentukan kapan task terjadwal berjalan berikutnya.
+type Schedule interface {
+	// Next mengembalikan waktu run pertama setelah after, atau zero time jika tidak ada lagi.
+	Next(after time.Time) time.Time
+}
+
+// cronSchedule adalah ekspresi cron 5 field dalam bentuk bitmask per field.
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	domStar, dowStar              bool
+	loc                           *time.Location
+}
+
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var cronMonthNames = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+var cronDayNames = map[string]int{
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+// ParseCron mem-parse ekspresi cron standar 5 field: menit, jam, tanggal, bulan, dan hari
+// (0-7, 0 dan 7 = Minggu). Setiap field mendukung "*", daftar "1,15", rentang "1-5", step "*/15"
+// atau "0-30/10", serta nama bulan dan hari ("jan", "mon"). Seperti cron pada umumnya, jika field
+// tanggal dan hari sama-sama dibatasi, task berjalan saat salah satunya cocok.
+//
+// Descriptor yang didukung: @yearly, @annually, @monthly, @weekly, @daily, @midnight, @hourly,
+// dan "@every <durasi>" (lihat Every). Prefix "CRON_TZ=<zona>" atau "TZ=<zona>" mengganti
+// timezone ekspresi; tanpa prefix, waktu dihitung di timezone Scheduler.
+//
+// Parameters:
+//   - spec: ekspresi cron
+//
+// Returns:
+//   - Schedule: jadwal yang dapat dipakai Scheduler.Add
+//   - error: ekspresi tidak valid
+//
+// Example:
+//
+//	schedule, err := dim.ParseCron("30 2 * * mon-fri")       // 02:30 setiap hari kerja
+//	schedule, err := dim.ParseCron("CRON_TZ=Asia/Jakarta @daily")
+func ParseCron(spec string) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	var loc *time.Location
+	if strings.HasPrefix(spec, "CRON_TZ=") || strings.HasPrefix(spec, "TZ=") {
+		tz, rest, _ := strings.Cut(spec, " ")
+		_, name, _ := strings.Cut(tz, "=")
+		l, err := time.LoadLocation(name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron timezone %q: %w", name, err)
+		}
+		loc, spec = l, strings.TrimSpace(rest)
+	}
+
+	if rest, ok := strings.CutPrefix(spec, "@every "); ok {
+		d, err := time.ParseDuration(strings.TrimSpace(rest))
+		if err != nil || d < time.Second {
+			return nil, fmt.Errorf("invalid cron spec %q: interval must be a duration of at least 1s", spec)
+		}
+		return Every(d), nil
+	}
+	if expr, ok := cronDescriptors[strings.ToLower(spec)]; ok {
+		spec = expr
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid cron spec %q: expected 5 fields, got %d", spec, len(fields))
+	}
+
+	s := &cronSchedule{loc: loc}
+	var err error
+	if s.minute, _, err = parseCronField(fields[0], 0, 59, nil); err != nil {
+		return nil, fmt.Errorf("invalid cron minute: %w", err)
+	}
+	if s.hour, _, err = parseCronField(fields[1], 0, 23, nil); err != nil {
+		return nil, fmt.Errorf("invalid cron hour: %w", err)
+	}
+	if s.dom, s.domStar, err = parseCronField(fields[2], 1, 31, nil); err != nil {
+		return nil, fmt.Errorf("invalid cron day of month: %w", err)
+	}
+	if s.month, _, err = parseCronField(fields[3], 1, 12, cronMonthNames); err != nil {
+		return nil, fmt.Errorf("invalid cron month: %w", err)
+	}
+	if s.dow, s.dowStar, err = parseCronField(fields[4], 0, 7, cronDayNames); err != nil {
+		return nil, fmt.Errorf("invalid cron day of week: %w", err)
+	}
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	return s, nil
+}
+
+// parseCronField mengubah satu field cron menjadi bitmask. star bernilai true jika field tidak
+// membatasi apa pun ("*" atau "?").
+func parseCronField(field string, lo, hi int, names map[string]int) (bits uint64, star bool, err error) {
+	for part := range strings.SplitSeq(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			step, err = strconv.Atoi(stepPart)
+			if err != nil || step <= 0 {
+				return 0, false, fmt.Errorf("invalid step %q", part)
+			}
+		}
+
+		var start, end int
+		switch {
+		case rangePart == "*" || rangePart == "?":
+			start, end = lo, hi
+			star = star || !hasStep
+		case strings.Contains(rangePart, "-"):
+			a, b, _ := strings.Cut(rangePart, "-")
+			if start, err = parseCronValue(a, names); err != nil {
+				return 0, false, err
+			}
+			if end, err = parseCronValue(b, names); err != nil {
+				return 0, false, err
+			}
+		default:
+			if start, err = parseCronValue(rangePart, names); err != nil {
+				return 0, false, err
+			}
+			end = start
+			if hasStep {
+				end = hi
+			}
+		}
+		if start < lo || end > hi || start > end {
+			return 0, false, fmt.Errorf("value %q out of range %d-%d", part, lo, hi)
+		}
+		for v := start; v <= end; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, star, nil
+}
+
+func parseCronValue(s string, names map[string]int) (int, error) {
+	if v, ok := names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+	return v, nil
+}
+
+// Next mengembalikan menit pertama setelah after yang cocok dengan ekspresi. Pencarian dibatasi
+// 5 tahun sehingga ekspresi yang tidak pernah cocok (misal 30 Februari) mengembalikan zero time.
+func (s *cronSchedule) Next(after time.Time) time.Time {
+	if s.loc != nil {
+		after = after.In(s.loc)
+	}
+	loc := after.Location()
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := t.Year() + 5
+
+	for t.Year() <= limit {
+		switch {
+		case s.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (s *cronSchedule) dayMatches(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// intervalSchedule berjalan setiap kelipatan interval.
+type intervalSchedule struct {
+	interval time.Duration
+}
+
+// Every membuat Schedule yang berjalan setiap interval. Waktu run disejajarkan ke kelipatan
+// interval sejak zero time (misal Every(time.Hour) selalu tepat di awal jam), sehingga semua
+// instance aplikasi menghitung waktu run yang sama dan distributed lock Scheduler dapat
+// memastikan hanya satu instance yang menjalankannya.
+func Every(interval time.Duration) Schedule {
+	return intervalSchedule{interval: interval}
+}
+
+func (s intervalSchedule) Next(after time.Time) time.Time {
+	if s.interval <= 0 {
+		return time.Time{}
+	}
+	return after.Truncate(s.interval).Add(s.interval)
+}
@@ -0,0 +1,95 @@
+package dim
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCron_Next(t *testing.T) {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		t.Skip("timezone data not available")
+	}
+	base := time.Date(2026, 3, 14, 10, 17, 30, 0, time.UTC) // Sabtu
+
+	tests := []struct {
+		spec  string
+		after time.Time
+		want  time.Time
+	}{
+		{"* * * * *", base, time.Date(2026, 3, 14, 10, 18, 0, 0, time.UTC)},
+		{"*/15 * * * *", base, time.Date(2026, 3, 14, 10, 30, 0, 0, time.UTC)},
+		{"0 * * * *", base, time.Date(2026, 3, 14, 11, 0, 0, 0, time.UTC)},
+		{"30 2 * * *", base, time.Date(2026, 3, 15, 2, 30, 0, 0, time.UTC)},
+		{"0 9 * * mon-fri", base, time.Date(2026, 3, 16, 9, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", base, time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1,15 * *", base, time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 jan *", base, time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"0-10/5 12 * * *", base, time.Date(2026, 3, 14, 12, 0, 0, 0, time.UTC)},
+		// Tanggal dan hari sama-sama dibatasi: cocok jika salah satunya cocok
+		{"0 0 20 * mon", base, time.Date(2026, 3, 16, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", base, time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", base, time.Date(2026, 3, 14, 11, 0, 0, 0, time.UTC)},
+		{"@daily", base, time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)},
+		{"@weekly", base, time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", base, time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{"@every 10m", base, time.Date(2026, 3, 14, 10, 20, 0, 0, time.UTC)},
+		{"CRON_TZ=Asia/Jakarta 0 9 * * *", base, time.Date(2026, 3, 15, 9, 0, 0, 0, jakarta)},
+		{"0 9 * * *", base.In(jakarta), time.Date(2026, 3, 15, 9, 0, 0, 0, jakarta)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			schedule, err := ParseCron(tt.spec)
+			if err != nil {
+				t.Fatalf("ParseCron() error = %v", err)
+			}
+			if got := schedule.Next(tt.after); !got.Equal(tt.want) {
+				t.Errorf("Next() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCron_Invalid(t *testing.T) {
+	specs := []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"x * * * *",
+		"@every 10",
+		"@every 100ms",
+		"TZ=Mars/Olympus * * * * *",
+	}
+	for _, spec := range specs {
+		if _, err := ParseCron(spec); err == nil {
+			t.Errorf("ParseCron(%q) expected error", spec)
+		}
+	}
+}
+
+func TestParseCron_NeverMatches(t *testing.T) {
+	schedule, err := ParseCron("0 0 30 2 *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := schedule.Next(time.Now()); !got.IsZero() {
+		t.Errorf("Next() = %v, want zero time", got)
+	}
+}
+
+func TestEvery_AlignsToInterval(t *testing.T) {
+	after := time.Date(2026, 3, 14, 10, 17, 30, 0, time.UTC)
+	if got, want := Every(time.Hour).Next(after), time.Date(2026, 3, 14, 11, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("Next() = %v, want %v", got, want)
+	}
+	if got, want := Every(15*time.Second).Next(after), time.Date(2026, 3, 14, 10, 17, 45, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("Next() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,72 @@
+package dim
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DatabaseSchedulerLocker menyimpan lock task di tabel scheduler_locks (lihat
+// GetSchedulerMigrations). Setiap baris mencatat jadwal terakhir yang sudah diklaim, sehingga
+// instance yang terlambat bangun (misal karena clock skew) tidak menjalankan jadwal yang sama dua
+// kali, dan batas lock sehingga run yang masih berjalan tidak ditumpuk instance lain.
+type DatabaseSchedulerLocker struct {
+	db    Database
+	owner string
+}
+
+// NewDatabaseSchedulerLocker membuat DatabaseSchedulerLocker di atas db.
+//
+// Example:
+//
+//	dim.RunMigrations(db, append(dim.GetFrameworkMigrations(), dim.GetSchedulerMigrations()...))
+//	scheduler := dim.NewScheduler(dim.SchedulerOptions{Locker: dim.NewDatabaseSchedulerLocker(db)})
+func NewDatabaseSchedulerLocker(db Database) *DatabaseSchedulerLocker {
+	return &DatabaseSchedulerLocker{db: db, owner: migrationLockOwner()}
+}
+
+// Acquire mengklaim slot dengan UPDATE bersyarat lalu membaca kembali owner-nya, karena Database.Exec
+// tidak mengembalikan jumlah baris yang berubah. Setiap klaim memakai token unik sehingga hanya
+// instance yang UPDATE-nya berhasil yang melihat token miliknya.
+func (l *DatabaseSchedulerLocker) Acquire(ctx context.Context, task string, slot time.Time, ttl time.Duration) (func(), bool, error) {
+	var exists int
+	err := l.db.QueryRow(ctx, l.db.Rebind(`SELECT 1 FROM scheduler_locks WHERE name = $1`), task).Scan(&exists)
+	if isNoRows(err) {
+		epoch := time.Unix(0, 0).UTC()
+		// Insert bisa gagal karena instance lain membuat baris yang sama lebih dulu; UPDATE di bawah
+		// tetap menentukan siapa yang menang.
+		l.db.Exec(ctx, l.db.Rebind(`INSERT INTO scheduler_locks (name, owner, locked_until, last_run_at) VALUES ($1, '', $2, $3)`), //nolint:errcheck
+			task, epoch, epoch)
+	} else if err != nil {
+		return nil, false, fmt.Errorf("failed to acquire scheduler lock: %w", err)
+	}
+
+	suffix, err := GenerateSecureToken(8)
+	if err != nil {
+		return nil, false, err
+	}
+	token := l.owner + "/" + suffix
+	now := time.Now().UTC()
+	slot = slot.UTC()
+
+	update := `UPDATE scheduler_locks SET owner = $1, locked_until = $2, last_run_at = $3
+		WHERE name = $4 AND locked_until <= $5 AND last_run_at < $6`
+	if err := l.db.Exec(ctx, l.db.Rebind(update), token, now.Add(ttl), slot, task, now, slot); err != nil {
+		return nil, false, fmt.Errorf("failed to acquire scheduler lock: %w", err)
+	}
+
+	var owner string
+	if err := l.db.QueryRow(ctx, l.db.Rebind(`SELECT owner FROM scheduler_locks WHERE name = $1`), task).Scan(&owner); err != nil {
+		return nil, false, fmt.Errorf("failed to acquire scheduler lock: %w", err)
+	}
+	if owner != token {
+		return nil, false, nil
+	}
+
+	release := func() {
+		// last_run_at tetap tersimpan sehingga slot yang sama tidak dijalankan lagi.
+		query := `UPDATE scheduler_locks SET locked_until = $1 WHERE name = $2 AND owner = $3`
+		l.db.Exec(context.Background(), l.db.Rebind(query), time.Now().UTC(), task, token) //nolint:errcheck
+	}
+	return release, true, nil
+}
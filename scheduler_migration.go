@@ -0,0 +1,64 @@
+package dim
+
+import (
+	"context"
+)
+
+// GetSchedulerMigrations mengembalikan migrasi tabel scheduler_locks yang dipakai
+// DatabaseSchedulerLocker. Modul ini opsional sehingga tidak termasuk dalam GetFrameworkMigrations.
+// Menggunakan versi 221 agar tidak bentrok dengan migrasi framework maupun aplikasi.
+//
+// Example:
+//
+//	migrations := append(dim.GetFrameworkMigrations(), dim.GetSchedulerMigrations()...)
+//	dim.RunMigrations(db, migrations)
+func GetSchedulerMigrations() []Migration {
+	return []Migration{
+		{
+			Version: 221,
+			Name:    "create_scheduler_locks_table",
+			Up:      CreateSchedulerLocksTable,
+			Down:    DropSchedulerLocksTable,
+		},
+	}
+}
+
+// CreateSchedulerLocksTable membuat tabel scheduler_locks dengan satu baris per task.
+func CreateSchedulerLocksTable(db Database) error {
+	var query string
+	switch db.DriverName() {
+	case "sqlite":
+		query = `
+			CREATE TABLE IF NOT EXISTS scheduler_locks (
+				name TEXT PRIMARY KEY,
+				owner TEXT NOT NULL,
+				locked_until TIMESTAMP NOT NULL,
+				last_run_at TIMESTAMP NOT NULL
+			)
+		`
+	case "mysql":
+		query = `
+			CREATE TABLE IF NOT EXISTS scheduler_locks (
+				name VARCHAR(100) PRIMARY KEY,
+				owner VARCHAR(255) NOT NULL,
+				locked_until DATETIME(3) NOT NULL,
+				last_run_at DATETIME(3) NOT NULL
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+		`
+	default:
+		query = `
+			CREATE TABLE IF NOT EXISTS scheduler_locks (
+				name VARCHAR(100) PRIMARY KEY,
+				owner VARCHAR(255) NOT NULL,
+				locked_until TIMESTAMP NOT NULL,
+				last_run_at TIMESTAMP NOT NULL
+			)
+		`
+	}
+	return db.Exec(context.Background(), query)
+}
+
+// DropSchedulerLocksTable menghapus tabel scheduler_locks.
+func DropSchedulerLocksTable(db Database) error {
+	return db.Exec(context.Background(), "DROP TABLE IF EXISTS scheduler_locks")
+}
@@ -0,0 +1,272 @@
+package dim
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestSchedulerDB(t *testing.T) *SQLiteDatabase {
+	t.Helper()
+	db, err := NewSQLiteDatabase(DatabaseConfig{Database: ":memory:"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := RunMigrations(db, GetSchedulerMigrations()); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestScheduler_RunsTasks(t *testing.T) {
+	registry := NewMetricsRegistry()
+	scheduler := NewScheduler(SchedulerOptions{}).WithMetrics(NewSchedulerMetrics(registry))
+
+	var calls atomic.Int32
+	var mu sync.Mutex
+	var runs []TaskRun
+	scheduler.OnRun(func(ctx context.Context, run TaskRun) {
+		mu.Lock()
+		runs = append(runs, run)
+		mu.Unlock()
+	})
+	if err := scheduler.Add("tick", Every(10*time.Millisecond), func(ctx context.Context) error {
+		calls.Add(1)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	scheduler.Start()
+	waitFor(t, "scheduled runs", func() bool { return calls.Load() >= 3 })
+	if err := scheduler.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, run := range runs {
+		if run.Task != "tick" || run.Err != nil || run.Missed {
+			t.Errorf("unexpected run %+v", run)
+		}
+		if run.ScheduledAt.Truncate(10*time.Millisecond) != run.ScheduledAt {
+			t.Errorf("ScheduledAt %v not aligned to interval", run.ScheduledAt)
+		}
+	}
+
+	var out strings.Builder
+	registry.WriteTo(&out)
+	if !strings.Contains(out.String(), `dim_scheduler_run_duration_seconds_count{task="tick",status="success"}`) {
+		t.Errorf("metrics missing run duration:\n%s", out.String())
+	}
+
+	// Tidak ada run baru setelah Shutdown
+	after := calls.Load()
+	time.Sleep(30 * time.Millisecond)
+	if calls.Load() != after {
+		t.Error("task ran after Shutdown")
+	}
+}
+
+func TestScheduler_SkipsOverlappingRuns(t *testing.T) {
+	registry := NewMetricsRegistry()
+	scheduler := NewScheduler(SchedulerOptions{}).WithMetrics(NewSchedulerMetrics(registry))
+
+	var calls, missed atomic.Int32
+	release := make(chan struct{})
+	scheduler.OnRun(func(ctx context.Context, run TaskRun) {
+		if run.Missed {
+			missed.Add(1)
+		}
+	})
+	scheduler.Add("slow", Every(5*time.Millisecond), func(ctx context.Context) error {
+		calls.Add(1)
+		<-release
+		return nil
+	})
+	scheduler.Start()
+
+	waitFor(t, "missed runs", func() bool { return missed.Load() >= 2 })
+	if got := calls.Load(); got != 1 {
+		t.Errorf("calls = %d while first run still running, want 1", got)
+	}
+	close(release)
+	scheduler.Shutdown(context.Background())
+
+	var out strings.Builder
+	registry.WriteTo(&out)
+	if !strings.Contains(out.String(), `dim_scheduler_missed_runs_total{task="slow"}`) {
+		t.Errorf("metrics missing missed runs:\n%s", out.String())
+	}
+}
+
+func TestScheduler_RunNow(t *testing.T) {
+	scheduler := NewScheduler(SchedulerOptions{})
+	errBoom := errors.New("boom")
+
+	scheduler.Cron("fail", "@daily", func(ctx context.Context) error { return errBoom })
+	scheduler.Cron("panic", "@daily", func(ctx context.Context) error { panic("oops") })
+	scheduler.Cron("slow", "@daily", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}, WithTaskTimeout(10*time.Millisecond))
+
+	ctx := context.Background()
+	if err := scheduler.RunNow(ctx, "fail"); !errors.Is(err, errBoom) {
+		t.Errorf("RunNow(fail) = %v, want %v", err, errBoom)
+	}
+	if err := scheduler.RunNow(ctx, "panic"); err == nil || !strings.Contains(err.Error(), "oops") {
+		t.Errorf("RunNow(panic) = %v, want panic error", err)
+	}
+	if err := scheduler.RunNow(ctx, "slow"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("RunNow(slow) = %v, want deadline exceeded", err)
+	}
+	if err := scheduler.RunNow(ctx, "missing"); !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("RunNow(missing) = %v, want ErrTaskNotFound", err)
+	}
+}
+
+func TestScheduler_RunNowRejectsOverlap(t *testing.T) {
+	scheduler := NewScheduler(SchedulerOptions{})
+	started := make(chan struct{})
+	release := make(chan struct{})
+	scheduler.Cron("job", "@daily", func(ctx context.Context) error {
+		close(started)
+		<-release
+		return nil
+	})
+
+	done := make(chan error)
+	go func() { done <- scheduler.RunNow(context.Background(), "job") }()
+	<-started
+	if err := scheduler.RunNow(context.Background(), "job"); !errors.Is(err, ErrTaskRunning) {
+		t.Errorf("RunNow() = %v, want ErrTaskRunning", err)
+	}
+	close(release)
+	if err := <-done; err != nil {
+		t.Errorf("first RunNow() = %v", err)
+	}
+}
+
+func TestScheduler_Register(t *testing.T) {
+	scheduler := NewScheduler(SchedulerOptions{})
+	noop := func(ctx context.Context) error { return nil }
+
+	if err := scheduler.Cron("job", "0 * * * *", noop); err != nil {
+		t.Fatal(err)
+	}
+	if err := scheduler.Cron("job", "0 * * * *", noop); !errors.Is(err, ErrTaskExists) {
+		t.Errorf("duplicate Cron() = %v, want ErrTaskExists", err)
+	}
+	if err := scheduler.Cron("bad", "61 * * * *", noop); err == nil {
+		t.Error("Cron() with invalid spec expected error")
+	}
+	if err := scheduler.Every("fast", 10*time.Millisecond, noop); err == nil {
+		t.Error("Every() below 1s expected error")
+	}
+}
+
+func TestScheduler_ShutdownCancelsRunningTask(t *testing.T) {
+	scheduler := NewScheduler(SchedulerOptions{})
+	started := make(chan struct{}, 1)
+	var cancelled atomic.Bool
+	scheduler.Add("stuck", Every(5*time.Millisecond), func(ctx context.Context) error {
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		<-ctx.Done()
+		cancelled.Store(true)
+		return ctx.Err()
+	})
+	scheduler.Start()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := scheduler.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Shutdown() = %v, want deadline exceeded", err)
+	}
+	waitFor(t, "task cancellation", cancelled.Load)
+}
+
+func TestDatabaseSchedulerLocker_SQLite(t *testing.T) {
+	db := newTestSchedulerDB(t)
+	ctx := context.Background()
+	a := NewDatabaseSchedulerLocker(db)
+	b := NewDatabaseSchedulerLocker(db)
+	slot := time.Date(2026, 3, 14, 10, 0, 0, 0, time.UTC)
+
+	releaseA, ok, err := a.Acquire(ctx, "cleanup", slot, time.Minute)
+	if err != nil || !ok {
+		t.Fatalf("first Acquire() = %v, %v", ok, err)
+	}
+	if _, ok, err := b.Acquire(ctx, "cleanup", slot, time.Minute); err != nil || ok {
+		t.Errorf("Acquire() for claimed slot = %v, %v, want false", ok, err)
+	}
+	// Run sebelumnya masih berjalan: slot berikutnya belum boleh dimulai
+	if _, ok, _ := b.Acquire(ctx, "cleanup", slot.Add(time.Hour), time.Minute); ok {
+		t.Error("Acquire() succeeded while previous run holds the lock")
+	}
+	if _, ok, _ := b.Acquire(ctx, "other", slot, time.Minute); !ok {
+		t.Error("Acquire() for a different task should succeed")
+	}
+
+	releaseA()
+	if _, ok, _ := b.Acquire(ctx, "cleanup", slot, time.Minute); ok {
+		t.Error("Acquire() ran the same slot twice")
+	}
+	releaseB, ok, err := b.Acquire(ctx, "cleanup", slot.Add(time.Hour), time.Minute)
+	if err != nil || !ok {
+		t.Fatalf("Acquire() next slot = %v, %v", ok, err)
+	}
+	releaseB()
+
+	// Lock dari instance yang crash kedaluwarsa setelah ttl
+	if _, ok, _ := a.Acquire(ctx, "cleanup", slot.Add(2*time.Hour), time.Millisecond); !ok {
+		t.Fatal("Acquire() expected success")
+	}
+	time.Sleep(5 * time.Millisecond)
+	if _, ok, _ := b.Acquire(ctx, "cleanup", slot.Add(3*time.Hour), time.Minute); !ok {
+		t.Error("Acquire() should take over an expired lock")
+	}
+}
+
+func TestScheduler_DistributedLockRunsEachSlotOnce(t *testing.T) {
+	db := newTestSchedulerDB(t)
+
+	var mu sync.Mutex
+	slots := map[time.Time]int{}
+	newInstance := func() *Scheduler {
+		s := NewScheduler(SchedulerOptions{Locker: NewDatabaseSchedulerLocker(db)})
+		s.Add("report", Every(20*time.Millisecond), func(ctx context.Context) error { return nil })
+		s.OnRun(func(ctx context.Context, run TaskRun) {
+			mu.Lock()
+			slots[run.ScheduledAt.UTC()]++
+			mu.Unlock()
+		})
+		s.Start()
+		return s
+	}
+	first, second := newInstance(), newInstance()
+
+	waitFor(t, "scheduled runs", func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(slots) >= 4
+	})
+	first.Shutdown(context.Background())
+	second.Shutdown(context.Background())
+
+	mu.Lock()
+	defer mu.Unlock()
+	for slot, n := range slots {
+		if n != 1 {
+			t.Errorf("slot %v ran %d times, want 1", slot, n)
+		}
+	}
+}
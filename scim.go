@@ -0,0 +1,1006 @@
+package dim
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schema URN SCIM 2.0 (RFC 7643 dan RFC 7644).
+const (
+	SCIMSchemaUser                  = "urn:ietf:params:scim:schemas:core:2.0:User"
+	SCIMSchemaGroup                 = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	SCIMSchemaEnterpriseUser        = "urn:ietf:params:scim:schemas:extension:enterprise:2.0:User"
+	SCIMSchemaListResponse          = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	SCIMSchemaPatchOp               = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	SCIMSchemaError                 = "urn:ietf:params:scim:api:messages:2.0:Error"
+	SCIMSchemaServiceProviderConfig = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	SCIMSchemaResourceType          = "urn:ietf:params:scim:schemas:core:2.0:ResourceType"
+)
+
+// SCIMContentType adalah media type untuk request dan response SCIM.
+const SCIMContentType = "application/scim+json"
+
+var (
+	// ErrSCIMNotFound dikembalikan store jika resource tidak ditemukan (dipetakan ke 404).
+	ErrSCIMNotFound = errors.New("scim: resource not found")
+	// ErrSCIMConflict dikembalikan store jika userName atau displayName sudah dipakai (dipetakan ke 409).
+	ErrSCIMConflict = errors.New("scim: resource already exists")
+)
+
+// SCIMName adalah komponen nama user SCIM.
+type SCIMName struct {
+	Formatted  string `json:"formatted,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+}
+
+// SCIMMultiValue adalah attribute multi-valued SCIM seperti emails, groups, dan members.
+type SCIMMultiValue struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+	Ref     string `json:"$ref,omitempty"`
+}
+
+// SCIMMeta berisi metadata resource SCIM.
+type SCIMMeta struct {
+	ResourceType string     `json:"resourceType,omitempty"`
+	Created      *time.Time `json:"created,omitempty"`
+	LastModified *time.Time `json:"lastModified,omitempty"`
+	Location     string     `json:"location,omitempty"`
+}
+
+// SCIMUser adalah resource User SCIM.
+// Attribute yang tidak dikenal (misal extension enterprise) disimpan di Attributes
+// dengan key sesuai nama top-level di payload.
+type SCIMUser struct {
+	Schemas     []string               `json:"schemas"`
+	ID          string                 `json:"id"`
+	ExternalID  string                 `json:"externalId,omitempty"`
+	UserName    string                 `json:"userName"`
+	Name        *SCIMName              `json:"name,omitempty"`
+	DisplayName string                 `json:"displayName,omitempty"`
+	Emails      []SCIMMultiValue       `json:"emails,omitempty"`
+	Active      bool                   `json:"active"`
+	Groups      []SCIMMultiValue       `json:"groups,omitempty"`
+	Meta        *SCIMMeta              `json:"meta,omitempty"`
+	Attributes  map[string]interface{} `json:"-"`
+	// Password berisi password plaintext dari payload. Attribute ini write-only: tidak pernah
+	// dikirim di response, dan kosong berarti password tidak diubah.
+	Password string `json:"-"`
+}
+
+// SCIMGroup adalah resource Group SCIM.
+type SCIMGroup struct {
+	Schemas     []string         `json:"schemas"`
+	ID          string           `json:"id"`
+	ExternalID  string           `json:"externalId,omitempty"`
+	DisplayName string           `json:"displayName"`
+	Members     []SCIMMultiValue `json:"members,omitempty"`
+	Meta        *SCIMMeta        `json:"meta,omitempty"`
+}
+
+// SCIMListQuery berisi parameter list request yang sudah di-parse.
+// StartIndex berbasis 1 sesuai spesifikasi; Count 0 berarti hanya totalResults yang diminta.
+type SCIMListQuery struct {
+	Filter     *SCIMFilter
+	StartIndex int
+	Count      int
+}
+
+// Window mengembalikan rentang index slice [start, end) untuk total hasil tertentu.
+// Berguna untuk store yang memfilter data di memori.
+func (q SCIMListQuery) Window(total int) (int, int) {
+	start := q.StartIndex - 1
+	if start < 0 {
+		start = 0
+	}
+	if start > total {
+		start = total
+	}
+	end := start + q.Count
+	if end > total {
+		end = total
+	}
+	return start, end
+}
+
+// SCIMUserStore adalah penyimpanan user untuk SCIMServer.
+// Implementasikan di atas tabel user aplikasi; kembalikan ErrSCIMNotFound dan
+// ErrSCIMConflict agar status HTTP yang tepat dikirim ke identity provider.
+type SCIMUserStore interface {
+	ListUsers(ctx context.Context, query SCIMListQuery) ([]*SCIMUser, int, error)
+	GetUser(ctx context.Context, id string) (*SCIMUser, error)
+	CreateUser(ctx context.Context, user *SCIMUser) error // Wajib mengisi user.ID
+	ReplaceUser(ctx context.Context, user *SCIMUser) error
+	DeleteUser(ctx context.Context, id string) error
+}
+
+// SCIMGroupStore adalah penyimpanan group untuk SCIMServer (biasanya dipetakan ke role).
+type SCIMGroupStore interface {
+	ListGroups(ctx context.Context, query SCIMListQuery) ([]*SCIMGroup, int, error)
+	GetGroup(ctx context.Context, id string) (*SCIMGroup, error)
+	CreateGroup(ctx context.Context, group *SCIMGroup) error // Wajib mengisi group.ID
+	ReplaceGroup(ctx context.Context, group *SCIMGroup) error
+	DeleteGroup(ctx context.Context, id string) error
+}
+
+// SCIMConfig berisi konfigurasi SCIMServer.
+type SCIMConfig struct {
+	// Token adalah bearer token yang dikonfigurasi di Okta/Azure AD (wajib).
+	Token string
+	// BaseURL dipakai untuk meta.location, misal "https://api.example.com/scim/v2".
+	BaseURL string
+	// Users adalah store user (wajib).
+	Users SCIMUserStore
+	// Groups adalah store group; jika nil, endpoint /Groups tidak didaftarkan.
+	Groups SCIMGroupStore
+	// MaxResults membatasi jumlah resource per halaman (default 100).
+	MaxResults int
+	// MapUserIn dipanggil setelah payload user di-decode (POST/PUT/PATCH) untuk memetakan
+	// attribute custom, misal enterprise extension, ke field aplikasi.
+	MapUserIn func(ctx context.Context, raw map[string]interface{}, user *SCIMUser) error
+	// MapUserOut dipanggil sebelum user dikirim untuk menambah atau mengubah attribute response.
+	MapUserOut func(ctx context.Context, user *SCIMUser, resource map[string]interface{})
+}
+
+// SCIMServer menyediakan endpoint provisioning SCIM 2.0 untuk Users dan Groups.
+type SCIMServer struct {
+	config SCIMConfig
+}
+
+// NewSCIMServer membuat SCIMServer baru.
+//
+// Parameters:
+//   - config: SCIMConfig dengan Token dan Users wajib diisi
+//
+// Returns:
+//   - *SCIMServer: server SCIM yang siap didaftarkan ke router
+//
+// Example:
+//
+//	scim := dim.NewSCIMServer(dim.SCIMConfig{
+//	    Token:   os.Getenv("SCIM_TOKEN"),
+//	    BaseURL: "https://api.example.com/scim/v2",
+//	    Users:   userProvisioner,
+//	    Groups:  roleProvisioner,
+//	})
+//	scim.Register(router.Group("/scim/v2"))
+func NewSCIMServer(config SCIMConfig) *SCIMServer {
+	if config.MaxResults <= 0 {
+		config.MaxResults = 100
+	}
+	config.BaseURL = strings.TrimSuffix(config.BaseURL, "/")
+	return &SCIMServer{config: config}
+}
+
+// Register mendaftarkan endpoint SCIM ke dalam group. Semua endpoint dilindungi bearer token.
+//
+// Endpoint:
+//   - GET /ServiceProviderConfig, GET /ResourceTypes
+//   - GET/POST /Users, GET/PUT/PATCH/DELETE /Users/{id}
+//   - GET/POST /Groups, GET/PUT/PATCH/DELETE /Groups/{id} (jika Groups di-set)
+func (s *SCIMServer) Register(rg *RouterGroup) {
+	auth := s.Middleware()
+
+	rg.Get("/ServiceProviderConfig", s.serviceProviderConfig, auth)
+	rg.Get("/ResourceTypes", s.resourceTypes, auth)
+
+	rg.Get("/Users", s.listUsers, auth)
+	rg.Post("/Users", s.createUser, auth)
+	rg.Get("/Users/{id}", s.getUser, auth)
+	rg.Put("/Users/{id}", s.replaceUser, auth)
+	rg.Patch("/Users/{id}", s.patchUser, auth)
+	rg.Delete("/Users/{id}", s.deleteUser, auth)
+
+	if s.config.Groups != nil {
+		rg.Get("/Groups", s.listGroups, auth)
+		rg.Post("/Groups", s.createGroup, auth)
+		rg.Get("/Groups/{id}", s.getGroup, auth)
+		rg.Put("/Groups/{id}", s.replaceGroup, auth)
+		rg.Patch("/Groups/{id}", s.patchGroup, auth)
+		rg.Delete("/Groups/{id}", s.deleteGroup, auth)
+	}
+}
+
+// Middleware memvalidasi bearer token SCIM dengan perbandingan constant-time.
+// Token kosong di konfigurasi selalu menolak request.
+func (s *SCIMServer) Middleware() MiddlewareFunc {
+	expected := []byte(s.config.Token)
+	return func(next HandlerFunc) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			token, ok := GetAuthToken(r)
+			if !ok || len(expected) == 0 || subtle.ConstantTimeCompare([]byte(token), expected) != 1 {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="scim"`)
+				scimError(w, http.StatusUnauthorized, "", "Authorization failure")
+				return
+			}
+			next(w, r)
+		}
+	}
+}
+
+// --- Users ---
+
+func (s *SCIMServer) listUsers(w http.ResponseWriter, r *http.Request) {
+	query, ok := s.parseListQuery(w, r)
+	if !ok {
+		return
+	}
+
+	users, total, err := s.config.Users.ListUsers(r.Context(), query)
+	if err != nil {
+		scimStoreError(w, err)
+		return
+	}
+
+	resources := make([]interface{}, 0, len(users))
+	for _, u := range users {
+		resources = append(resources, s.userResource(r.Context(), u))
+	}
+	scimList(w, query, total, resources)
+}
+
+func (s *SCIMServer) getUser(w http.ResponseWriter, r *http.Request) {
+	user, err := s.config.Users.GetUser(r.Context(), GetParam(r, "id"))
+	if err != nil {
+		scimStoreError(w, err)
+		return
+	}
+	scimJSON(w, http.StatusOK, s.userResource(r.Context(), user))
+}
+
+func (s *SCIMServer) createUser(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.decodeUser(w, r)
+	if !ok {
+		return
+	}
+
+	now := time.Now().UTC()
+	user.ID = ""
+	user.Meta = &SCIMMeta{ResourceType: "User", Created: &now, LastModified: &now}
+	if err := s.config.Users.CreateUser(r.Context(), user); err != nil {
+		scimStoreError(w, err)
+		return
+	}
+
+	resource := s.userResource(r.Context(), user)
+	w.Header().Set("Location", s.location("Users", user.ID))
+	scimJSON(w, http.StatusCreated, resource)
+}
+
+func (s *SCIMServer) replaceUser(w http.ResponseWriter, r *http.Request) {
+	existing, err := s.config.Users.GetUser(r.Context(), GetParam(r, "id"))
+	if err != nil {
+		scimStoreError(w, err)
+		return
+	}
+
+	user, ok := s.decodeUser(w, r)
+	if !ok {
+		return
+	}
+	user.ID = existing.ID
+	user.Meta = touchSCIMMeta(existing.Meta, "User")
+
+	if err := s.config.Users.ReplaceUser(r.Context(), user); err != nil {
+		scimStoreError(w, err)
+		return
+	}
+	scimJSON(w, http.StatusOK, s.userResource(r.Context(), user))
+}
+
+func (s *SCIMServer) patchUser(w http.ResponseWriter, r *http.Request) {
+	user, err := s.config.Users.GetUser(r.Context(), GetParam(r, "id"))
+	if err != nil {
+		scimStoreError(w, err)
+		return
+	}
+
+	ops, ok := decodeSCIMPatch(w, r)
+	if !ok {
+		return
+	}
+
+	raw := make(map[string]interface{})
+	for _, op := range ops {
+		if err := applySCIMUserPatch(user, op, raw); err != nil {
+			scimError(w, http.StatusBadRequest, "invalidPath", err.Error())
+			return
+		}
+	}
+
+	if s.config.MapUserIn != nil {
+		if err := s.config.MapUserIn(r.Context(), raw, user); err != nil {
+			scimError(w, http.StatusBadRequest, "invalidValue", err.Error())
+			return
+		}
+	}
+
+	user.Meta = touchSCIMMeta(user.Meta, "User")
+	if err := s.config.Users.ReplaceUser(r.Context(), user); err != nil {
+		scimStoreError(w, err)
+		return
+	}
+	scimJSON(w, http.StatusOK, s.userResource(r.Context(), user))
+}
+
+func (s *SCIMServer) deleteUser(w http.ResponseWriter, r *http.Request) {
+	if err := s.config.Users.DeleteUser(r.Context(), GetParam(r, "id")); err != nil {
+		scimStoreError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// decodeUser membaca payload user; attribute yang tidak dikenal disimpan di Attributes.
+func (s *SCIMServer) decodeUser(w http.ResponseWriter, r *http.Request) (*SCIMUser, bool) {
+	var raw map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&raw); err != nil {
+		scimError(w, http.StatusBadRequest, "invalidSyntax", "Request body is not valid JSON")
+		return nil, false
+	}
+
+	user := &SCIMUser{Active: true}
+	encoded, _ := json.Marshal(raw)
+	if err := json.Unmarshal(encoded, user); err != nil {
+		scimError(w, http.StatusBadRequest, "invalidValue", err.Error())
+		return nil, false
+	}
+	if user.UserName == "" {
+		scimError(w, http.StatusBadRequest, "invalidValue", "userName is required")
+		return nil, false
+	}
+	user.Password, _ = raw["password"].(string)
+
+	for key, value := range raw {
+		if !scimUserCoreAttributes[strings.ToLower(key)] {
+			if user.Attributes == nil {
+				user.Attributes = make(map[string]interface{})
+			}
+			user.Attributes[key] = value
+		}
+	}
+
+	if s.config.MapUserIn != nil {
+		if err := s.config.MapUserIn(r.Context(), raw, user); err != nil {
+			scimError(w, http.StatusBadRequest, "invalidValue", err.Error())
+			return nil, false
+		}
+	}
+	return user, true
+}
+
+var scimUserCoreAttributes = map[string]bool{
+	"schemas": true, "id": true, "externalid": true, "username": true, "name": true,
+	"displayname": true, "emails": true, "active": true, "groups": true, "meta": true,
+	"password": true,
+}
+
+func (s *SCIMServer) userResource(ctx context.Context, user *SCIMUser) map[string]interface{} {
+	out := *user
+	out.Schemas = []string{SCIMSchemaUser}
+	for key := range user.Attributes {
+		if strings.HasPrefix(key, "urn:") {
+			out.Schemas = append(out.Schemas, key)
+		}
+	}
+	out.Meta = s.meta(user.Meta, "User", user.ID)
+
+	resource := scimToMap(out)
+	for key, value := range user.Attributes {
+		resource[key] = value
+	}
+	if s.config.MapUserOut != nil {
+		s.config.MapUserOut(ctx, user, resource)
+	}
+	return resource
+}
+
+// --- Groups ---
+
+func (s *SCIMServer) listGroups(w http.ResponseWriter, r *http.Request) {
+	query, ok := s.parseListQuery(w, r)
+	if !ok {
+		return
+	}
+
+	groups, total, err := s.config.Groups.ListGroups(r.Context(), query)
+	if err != nil {
+		scimStoreError(w, err)
+		return
+	}
+
+	resources := make([]interface{}, 0, len(groups))
+	for _, g := range groups {
+		resources = append(resources, s.groupResource(g))
+	}
+	scimList(w, query, total, resources)
+}
+
+func (s *SCIMServer) getGroup(w http.ResponseWriter, r *http.Request) {
+	group, err := s.config.Groups.GetGroup(r.Context(), GetParam(r, "id"))
+	if err != nil {
+		scimStoreError(w, err)
+		return
+	}
+	scimJSON(w, http.StatusOK, s.groupResource(group))
+}
+
+func (s *SCIMServer) createGroup(w http.ResponseWriter, r *http.Request) {
+	group, ok := decodeSCIMGroup(w, r)
+	if !ok {
+		return
+	}
+
+	now := time.Now().UTC()
+	group.ID = ""
+	group.Meta = &SCIMMeta{ResourceType: "Group", Created: &now, LastModified: &now}
+	if err := s.config.Groups.CreateGroup(r.Context(), group); err != nil {
+		scimStoreError(w, err)
+		return
+	}
+
+	w.Header().Set("Location", s.location("Groups", group.ID))
+	scimJSON(w, http.StatusCreated, s.groupResource(group))
+}
+
+func (s *SCIMServer) replaceGroup(w http.ResponseWriter, r *http.Request) {
+	existing, err := s.config.Groups.GetGroup(r.Context(), GetParam(r, "id"))
+	if err != nil {
+		scimStoreError(w, err)
+		return
+	}
+
+	group, ok := decodeSCIMGroup(w, r)
+	if !ok {
+		return
+	}
+	group.ID = existing.ID
+	group.Meta = touchSCIMMeta(existing.Meta, "Group")
+
+	if err := s.config.Groups.ReplaceGroup(r.Context(), group); err != nil {
+		scimStoreError(w, err)
+		return
+	}
+	scimJSON(w, http.StatusOK, s.groupResource(group))
+}
+
+func (s *SCIMServer) patchGroup(w http.ResponseWriter, r *http.Request) {
+	group, err := s.config.Groups.GetGroup(r.Context(), GetParam(r, "id"))
+	if err != nil {
+		scimStoreError(w, err)
+		return
+	}
+
+	ops, ok := decodeSCIMPatch(w, r)
+	if !ok {
+		return
+	}
+
+	for _, op := range ops {
+		if err := applySCIMGroupPatch(group, op); err != nil {
+			scimError(w, http.StatusBadRequest, "invalidPath", err.Error())
+			return
+		}
+	}
+
+	group.Meta = touchSCIMMeta(group.Meta, "Group")
+	if err := s.config.Groups.ReplaceGroup(r.Context(), group); err != nil {
+		scimStoreError(w, err)
+		return
+	}
+	scimJSON(w, http.StatusOK, s.groupResource(group))
+}
+
+func (s *SCIMServer) deleteGroup(w http.ResponseWriter, r *http.Request) {
+	if err := s.config.Groups.DeleteGroup(r.Context(), GetParam(r, "id")); err != nil {
+		scimStoreError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func decodeSCIMGroup(w http.ResponseWriter, r *http.Request) (*SCIMGroup, bool) {
+	group := &SCIMGroup{}
+	if err := json.NewDecoder(r.Body).Decode(group); err != nil {
+		scimError(w, http.StatusBadRequest, "invalidSyntax", "Request body is not valid JSON")
+		return nil, false
+	}
+	if group.DisplayName == "" {
+		scimError(w, http.StatusBadRequest, "invalidValue", "displayName is required")
+		return nil, false
+	}
+	return group, true
+}
+
+func (s *SCIMServer) groupResource(group *SCIMGroup) *SCIMGroup {
+	out := *group
+	out.Schemas = []string{SCIMSchemaGroup}
+	out.Meta = s.meta(group.Meta, "Group", group.ID)
+	return &out
+}
+
+// --- Discovery ---
+
+func (s *SCIMServer) serviceProviderConfig(w http.ResponseWriter, r *http.Request) {
+	scimJSON(w, http.StatusOK, map[string]interface{}{
+		"schemas":        []string{SCIMSchemaServiceProviderConfig},
+		"patch":          map[string]bool{"supported": true},
+		"bulk":           map[string]interface{}{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+		"filter":         map[string]interface{}{"supported": true, "maxResults": s.config.MaxResults},
+		"changePassword": map[string]bool{"supported": false},
+		"sort":           map[string]bool{"supported": false},
+		"etag":           map[string]bool{"supported": false},
+		"authenticationSchemes": []map[string]interface{}{{
+			"type":        "oauthbearertoken",
+			"name":        "OAuth Bearer Token",
+			"description": "Authentication using a static bearer token",
+		}},
+	})
+}
+
+func (s *SCIMServer) resourceTypes(w http.ResponseWriter, r *http.Request) {
+	resources := []interface{}{
+		map[string]interface{}{
+			"schemas":          []string{SCIMSchemaResourceType},
+			"id":               "User",
+			"name":             "User",
+			"endpoint":         "/Users",
+			"schema":           SCIMSchemaUser,
+			"schemaExtensions": []map[string]interface{}{{"schema": SCIMSchemaEnterpriseUser, "required": false}},
+		},
+	}
+	if s.config.Groups != nil {
+		resources = append(resources, map[string]interface{}{
+			"schemas":  []string{SCIMSchemaResourceType},
+			"id":       "Group",
+			"name":     "Group",
+			"endpoint": "/Groups",
+			"schema":   SCIMSchemaGroup,
+		})
+	}
+	scimList(w, SCIMListQuery{StartIndex: 1, Count: len(resources)}, len(resources), resources)
+}
+
+// --- Helpers ---
+
+// parseListQuery mem-parsing filter, startIndex, dan count dari query string.
+func (s *SCIMServer) parseListQuery(w http.ResponseWriter, r *http.Request) (SCIMListQuery, bool) {
+	query := SCIMListQuery{StartIndex: 1, Count: s.config.MaxResults}
+	values := r.URL.Query()
+
+	filter, err := ParseSCIMFilter(values.Get("filter"))
+	if err != nil {
+		scimError(w, http.StatusBadRequest, "invalidFilter", err.Error())
+		return query, false
+	}
+	query.Filter = filter
+
+	if v := values.Get("startIndex"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			scimError(w, http.StatusBadRequest, "invalidValue", "startIndex must be an integer")
+			return query, false
+		}
+		if n > 1 {
+			query.StartIndex = n
+		}
+	}
+
+	if v := values.Get("count"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			scimError(w, http.StatusBadRequest, "invalidValue", "count must be an integer")
+			return query, false
+		}
+		if n < 0 {
+			n = 0
+		}
+		if n < s.config.MaxResults {
+			query.Count = n
+		}
+	}
+
+	return query, true
+}
+
+func (s *SCIMServer) meta(meta *SCIMMeta, resourceType, id string) *SCIMMeta {
+	out := SCIMMeta{}
+	if meta != nil {
+		out = *meta
+	}
+	out.ResourceType = resourceType
+	if out.Location == "" {
+		out.Location = s.location(resourceType+"s", id)
+	}
+	return &out
+}
+
+func (s *SCIMServer) location(endpoint, id string) string {
+	return fmt.Sprintf("%s/%s/%s", s.config.BaseURL, endpoint, id)
+}
+
+func touchSCIMMeta(meta *SCIMMeta, resourceType string) *SCIMMeta {
+	out := SCIMMeta{ResourceType: resourceType}
+	if meta != nil {
+		out = *meta
+	}
+	now := time.Now().UTC()
+	out.LastModified = &now
+	return &out
+}
+
+func scimList(w http.ResponseWriter, query SCIMListQuery, total int, resources []interface{}) {
+	scimJSON(w, http.StatusOK, map[string]interface{}{
+		"schemas":      []string{SCIMSchemaListResponse},
+		"totalResults": total,
+		"startIndex":   query.StartIndex,
+		"itemsPerPage": len(resources),
+		"Resources":    resources,
+	})
+}
+
+func scimJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", SCIMContentType)
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+// scimError menulis error dengan format SCIM (RFC 7644 section 3.12).
+func scimError(w http.ResponseWriter, status int, scimType, detail string) {
+	body := map[string]interface{}{
+		"schemas": []string{SCIMSchemaError},
+		"status":  strconv.Itoa(status),
+		"detail":  detail,
+	}
+	if scimType != "" {
+		body["scimType"] = scimType
+	}
+	scimJSON(w, status, body)
+}
+
+func scimStoreError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrSCIMNotFound):
+		scimError(w, http.StatusNotFound, "", "Resource not found")
+	case errors.Is(err, ErrSCIMConflict):
+		scimError(w, http.StatusConflict, "uniqueness", "Resource already exists")
+	default:
+		scimError(w, http.StatusInternalServerError, "", "Internal server error")
+	}
+}
+
+func scimToMap(v interface{}) map[string]interface{} {
+	encoded, _ := json.Marshal(v)
+	out := make(map[string]interface{})
+	json.Unmarshal(encoded, &out)
+	return out
+}
+
+// --- PATCH ---
+
+// scimPatchOp adalah satu operasi di PatchOp request.
+type scimPatchOp struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value"`
+}
+
+func decodeSCIMPatch(w http.ResponseWriter, r *http.Request) ([]scimPatchOp, bool) {
+	var body struct {
+		Schemas    []string      `json:"schemas"`
+		Operations []scimPatchOp `json:"Operations"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		scimError(w, http.StatusBadRequest, "invalidSyntax", "Request body is not valid JSON")
+		return nil, false
+	}
+	if len(body.Operations) == 0 {
+		scimError(w, http.StatusBadRequest, "invalidValue", "Operations is required")
+		return nil, false
+	}
+	for i := range body.Operations {
+		op := strings.ToLower(body.Operations[i].Op)
+		if op != "add" && op != "replace" && op != "remove" {
+			scimError(w, http.StatusBadRequest, "invalidSyntax", fmt.Sprintf("unsupported patch op %q", body.Operations[i].Op))
+			return nil, false
+		}
+		body.Operations[i].Op = op
+	}
+	return body.Operations, true
+}
+
+// applySCIMUserPatch menerapkan satu operasi PATCH ke user.
+// Operasi tanpa path (format Azure AD) berisi map attribute → nilai.
+// Nilai yang diterapkan dicatat di raw agar dapat diteruskan ke MapUserIn.
+func applySCIMUserPatch(user *SCIMUser, op scimPatchOp, raw map[string]interface{}) error {
+	var value interface{}
+	if len(op.Value) > 0 {
+		if err := json.Unmarshal(op.Value, &value); err != nil {
+			return fmt.Errorf("invalid patch value: %w", err)
+		}
+	}
+
+	if op.Path == "" {
+		attrs, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("patch without path requires an object value")
+		}
+		for key, v := range attrs {
+			if err := setSCIMUserAttribute(user, key, v, op.Op == "remove"); err != nil {
+				return err
+			}
+			raw[key] = v
+		}
+		return nil
+	}
+
+	raw[op.Path] = value
+	return setSCIMUserAttribute(user, op.Path, value, op.Op == "remove")
+}
+
+func setSCIMUserAttribute(user *SCIMUser, path string, value interface{}, remove bool) error {
+	str, _ := value.(string)
+
+	switch strings.ToLower(path) {
+	case "active":
+		if remove {
+			user.Active = false
+			return nil
+		}
+		switch v := value.(type) {
+		case bool:
+			user.Active = v
+		case string:
+			// Azure AD mengirim "True"/"False" sebagai string
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return fmt.Errorf("active must be a boolean")
+			}
+			user.Active = b
+		default:
+			return fmt.Errorf("active must be a boolean")
+		}
+	case "username":
+		if remove || str == "" {
+			return fmt.Errorf("userName is required")
+		}
+		user.UserName = str
+	case "displayname":
+		user.DisplayName = str
+	case "externalid":
+		user.ExternalID = str
+	case "password":
+		user.Password = str
+	case "name.givenname", "name.familyname", "name.formatted":
+		if user.Name == nil {
+			user.Name = &SCIMName{}
+		}
+		switch strings.ToLower(path) {
+		case "name.givenname":
+			user.Name.GivenName = str
+		case "name.familyname":
+			user.Name.FamilyName = str
+		default:
+			user.Name.Formatted = str
+		}
+	case "name":
+		user.Name = nil
+		if !remove {
+			name := &SCIMName{}
+			if err := remarshalSCIM(value, name); err != nil {
+				return err
+			}
+			user.Name = name
+		}
+	case "emails":
+		user.Emails = nil
+		if !remove {
+			if err := remarshalSCIM(value, &user.Emails); err != nil {
+				return err
+			}
+		}
+	default:
+		if remove {
+			delete(user.Attributes, path)
+			return nil
+		}
+		if user.Attributes == nil {
+			user.Attributes = make(map[string]interface{})
+		}
+		user.Attributes[path] = value
+	}
+	return nil
+}
+
+// applySCIMGroupPatch menerapkan satu operasi PATCH ke group.
+// Mendukung displayName dan members, termasuk path `members[value eq "id"]` untuk remove.
+func applySCIMGroupPatch(group *SCIMGroup, op scimPatchOp) error {
+	path := op.Path
+	if path == "" {
+		var attrs map[string]json.RawMessage
+		if err := json.Unmarshal(op.Value, &attrs); err != nil {
+			return fmt.Errorf("patch without path requires an object value")
+		}
+		for key, v := range attrs {
+			if err := applySCIMGroupPatch(group, scimPatchOp{Op: op.Op, Path: key, Value: v}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	lower := strings.ToLower(path)
+	switch {
+	case lower == "displayname":
+		var name string
+		if err := json.Unmarshal(op.Value, &name); err != nil || name == "" {
+			return fmt.Errorf("displayName must be a non-empty string")
+		}
+		group.DisplayName = name
+	case lower == "externalid":
+		var id string
+		json.Unmarshal(op.Value, &id)
+		group.ExternalID = id
+	case lower == "members":
+		var members []SCIMMultiValue
+		if len(op.Value) > 0 {
+			if err := json.Unmarshal(op.Value, &members); err != nil {
+				return fmt.Errorf("members must be an array: %w", err)
+			}
+		}
+		switch op.Op {
+		case "replace":
+			group.Members = members
+		case "add":
+			for _, m := range members {
+				if !scimHasMember(group.Members, m.Value) {
+					group.Members = append(group.Members, m)
+				}
+			}
+		case "remove":
+			if len(members) == 0 {
+				group.Members = nil
+				return nil
+			}
+			for _, m := range members {
+				group.Members = scimRemoveMembers(group.Members, func(existing SCIMMultiValue) bool {
+					return existing.Value == m.Value
+				})
+			}
+		}
+	case strings.HasPrefix(lower, "members[") && strings.HasSuffix(lower, "]"):
+		if op.Op != "remove" {
+			return fmt.Errorf("only remove is supported for filtered member paths")
+		}
+		filter, err := ParseSCIMFilter(path[len("members[") : len(path)-1])
+		if err != nil {
+			return err
+		}
+		group.Members = scimRemoveMembers(group.Members, func(m SCIMMultiValue) bool {
+			return filter.Match(m.lookup)
+		})
+	default:
+		return fmt.Errorf("unsupported group attribute %q", path)
+	}
+	return nil
+}
+
+func scimHasMember(members []SCIMMultiValue, value string) bool {
+	for _, m := range members {
+		if m.Value == value {
+			return true
+		}
+	}
+	return false
+}
+
+func scimRemoveMembers(members []SCIMMultiValue, match func(SCIMMultiValue) bool) []SCIMMultiValue {
+	kept := members[:0]
+	for _, m := range members {
+		if !match(m) {
+			kept = append(kept, m)
+		}
+	}
+	return kept
+}
+
+func remarshalSCIM(value interface{}, dst interface{}) error {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(encoded, dst)
+}
+
+// --- Filter lookup ---
+
+// Lookup mengembalikan nilai attribute user untuk evaluasi SCIMFilter.
+// Mendukung attribute core (userName, emails.value, name.givenName, dll) dan
+// key di Attributes untuk attribute custom.
+//
+// Example:
+//
+//	if query.Filter.Match(user.Lookup) { ... }
+func (u *SCIMUser) Lookup(attr string) []interface{} {
+	switch strings.ToLower(attr) {
+	case "id":
+		return []interface{}{u.ID}
+	case "externalid":
+		return []interface{}{u.ExternalID}
+	case "username":
+		return []interface{}{u.UserName}
+	case "displayname":
+		return []interface{}{u.DisplayName}
+	case "active":
+		return []interface{}{u.Active}
+	case "name.givenname", "name.familyname", "name.formatted":
+		if u.Name == nil {
+			return nil
+		}
+		return []interface{}{map[string]string{
+			"name.givenname":  u.Name.GivenName,
+			"name.familyname": u.Name.FamilyName,
+			"name.formatted":  u.Name.Formatted,
+		}[strings.ToLower(attr)]}
+	case "emails", "emails.value":
+		return scimMultiValues(u.Emails, "value")
+	case "emails.type":
+		return scimMultiValues(u.Emails, "type")
+	case "groups", "groups.value":
+		return scimMultiValues(u.Groups, "value")
+	}
+
+	for key, value := range u.Attributes {
+		if strings.EqualFold(key, attr) {
+			return []interface{}{value}
+		}
+	}
+	return nil
+}
+
+// Lookup mengembalikan nilai attribute group untuk evaluasi SCIMFilter.
+func (g *SCIMGroup) Lookup(attr string) []interface{} {
+	switch strings.ToLower(attr) {
+	case "id":
+		return []interface{}{g.ID}
+	case "externalid":
+		return []interface{}{g.ExternalID}
+	case "displayname":
+		return []interface{}{g.DisplayName}
+	case "members", "members.value":
+		return scimMultiValues(g.Members, "value")
+	}
+	return nil
+}
+
+func (m SCIMMultiValue) lookup(attr string) []interface{} {
+	switch strings.ToLower(attr) {
+	case "value":
+		return []interface{}{m.Value}
+	case "display":
+		return []interface{}{m.Display}
+	case "type":
+		return []interface{}{m.Type}
+	case "primary":
+		return []interface{}{m.Primary}
+	}
+	return nil
+}
+
+func scimMultiValues(values []SCIMMultiValue, field string) []interface{} {
+	out := make([]interface{}, 0, len(values))
+	for _, v := range values {
+		out = append(out, v.lookup(field)...)
+	}
+	return out
+}
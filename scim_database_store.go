@@ -0,0 +1,357 @@
+package dim
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DatabaseSCIMUserStore adalah SCIMUserStore di atas tabel users yang dibaca AuthUserStore.
+// User hasil provisioning disimpan sebagai row users biasa (email dari email primary atau
+// userName, password dari attribute password jika dikirim identity provider) sehingga dapat
+// langsung login lewat AuthService. externalId, status active, dan attribute SCIM lainnya
+// disimpan di tabel scim_users dari GetSCIMMigrations.
+//
+// Store ini juga mengimplementasikan AuthUserStore dengan mendelegasikan ke store user
+// aplikasi, tetapi menolak user yang dinonaktifkan (active=false) dengan ErrUserNotFound.
+// Gunakan store ini sebagai user store AuthService agar deprovisioning langsung memblokir login.
+type DatabaseSCIMUserStore struct {
+	db    Database
+	users AuthUserStore
+}
+
+// NewDatabaseSCIMUserStore membuat DatabaseSCIMUserStore.
+//
+// Parameters:
+//   - db: database dengan tabel users dan scim_users
+//   - users: AuthUserStore aplikasi, dipakai untuk lookup login dan Update
+//
+// Returns:
+//   - *DatabaseSCIMUserStore: store yang siap dipakai SCIMServer dan AuthService
+//
+// Example:
+//
+//	users := dim.NewDatabaseSCIMUserStore(db, dim.NewDatabaseAuthUserStore(db))
+//	scim := dim.NewSCIMServer(dim.SCIMConfig{Token: token, Users: users})
+//	authService, _ := dim.NewAuthService(users, tokenStore, blocklist, jwtConfig)
+func NewDatabaseSCIMUserStore(db Database, users AuthUserStore) *DatabaseSCIMUserStore {
+	return &DatabaseSCIMUserStore{db: db, users: users}
+}
+
+// scimStoredUser adalah attribute SCIM yang disimpan sebagai JSON di kolom resource.
+type scimStoredUser struct {
+	Name        *SCIMName              `json:"name,omitempty"`
+	DisplayName string                 `json:"displayName,omitempty"`
+	Emails      []SCIMMultiValue       `json:"emails,omitempty"`
+	Meta        *SCIMMeta              `json:"meta,omitempty"`
+	Attributes  map[string]interface{} `json:"attributes,omitempty"`
+}
+
+const scimUserColumns = `user_id, external_id, user_name, active, resource`
+
+// ListUsers mengembalikan user yang cocok dengan filter, diurutkan berdasarkan userName.
+// Filter dievaluasi di memori sehingga cocok untuk jumlah user skala direktori perusahaan.
+func (s *DatabaseSCIMUserStore) ListUsers(ctx context.Context, query SCIMListQuery) ([]*SCIMUser, int, error) {
+	rows, err := s.db.Query(ctx, `SELECT `+scimUserColumns+` FROM scim_users`)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list scim users: %w", err)
+	}
+	defer rows.Close()
+
+	var matched []*SCIMUser
+	for rows.Next() {
+		user, err := scanSCIMUser(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		if query.Filter.Match(user.Lookup) {
+			matched = append(matched, user)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to list scim users: %w", err)
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		return strings.ToLower(matched[i].UserName) < strings.ToLower(matched[j].UserName)
+	})
+
+	start, end := query.Window(len(matched))
+	return matched[start:end], len(matched), nil
+}
+
+// GetUser mengambil user berdasarkan ID (sama dengan users.id).
+func (s *DatabaseSCIMUserStore) GetUser(ctx context.Context, id string) (*SCIMUser, error) {
+	query := s.db.Rebind(`SELECT ` + scimUserColumns + ` FROM scim_users WHERE user_id = $1`)
+	return scanSCIMUserRow(s.db.QueryRow(ctx, query, id))
+}
+
+// FindByExternalID mengambil user berdasarkan externalId dari identity provider.
+// Mengembalikan ErrSCIMNotFound jika tidak ada user dengan externalId tersebut.
+func (s *DatabaseSCIMUserStore) FindByExternalID(ctx context.Context, externalID string) (*SCIMUser, error) {
+	query := s.db.Rebind(`SELECT ` + scimUserColumns + ` FROM scim_users WHERE external_id = $1`)
+	return scanSCIMUserRow(s.db.QueryRow(ctx, query, externalID))
+}
+
+// CreateUser membuat row users dan scim_users dalam satu transaksi. userName, externalId,
+// dan email harus unik; jika tidak, ErrSCIMConflict dikembalikan.
+func (s *DatabaseSCIMUserStore) CreateUser(ctx context.Context, user *SCIMUser) error {
+	password, err := scimPasswordHash(user.Password)
+	if err != nil {
+		return err
+	}
+	resource, err := encodeSCIMUser(user)
+	if err != nil {
+		return err
+	}
+
+	id := NewV4().String()
+	err = s.db.WithTx(ctx, func(ctx context.Context, tx Tx) error {
+		if err := s.checkUnique(ctx, tx, user, id); err != nil {
+			return err
+		}
+		query := s.db.Rebind(`INSERT INTO users (id, email, name, password) VALUES ($1, $2, $3, $4)`)
+		if err := tx.Exec(ctx, query, id, scimUserEmail(user), scimUserName(user), password); err != nil {
+			return fmt.Errorf("failed to create user: %w", err)
+		}
+		query = s.db.Rebind(`INSERT INTO scim_users (` + scimUserColumns + `) VALUES ($1, $2, $3, $4, $5)`)
+		if err := tx.Exec(ctx, query, id, scimExternalID(user), user.UserName, user.Active, resource); err != nil {
+			return fmt.Errorf("failed to create scim user: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	user.ID = id
+	user.Password = ""
+	return nil
+}
+
+// ReplaceUser memperbarui user. Password hanya diganti jika payload membawa attribute password;
+// set Active=false untuk menonaktifkan user tanpa menghapus datanya.
+func (s *DatabaseSCIMUserStore) ReplaceUser(ctx context.Context, user *SCIMUser) error {
+	password, err := scimPasswordHash(user.Password)
+	if err != nil {
+		return err
+	}
+	resource, err := encodeSCIMUser(user)
+	if err != nil {
+		return err
+	}
+
+	err = s.db.WithTx(ctx, func(ctx context.Context, tx Tx) error {
+		if err := s.checkExists(ctx, tx, user.ID); err != nil {
+			return err
+		}
+		if err := s.checkUnique(ctx, tx, user, user.ID); err != nil {
+			return err
+		}
+
+		var err error
+		if password != "" {
+			query := s.db.Rebind(`UPDATE users SET email = $1, name = $2, password = $3, updated_at = CURRENT_TIMESTAMP WHERE id = $4`)
+			err = tx.Exec(ctx, query, scimUserEmail(user), scimUserName(user), password, user.ID)
+		} else {
+			query := s.db.Rebind(`UPDATE users SET email = $1, name = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $3`)
+			err = tx.Exec(ctx, query, scimUserEmail(user), scimUserName(user), user.ID)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to update user: %w", err)
+		}
+
+		query := s.db.Rebind(`UPDATE scim_users SET external_id = $1, user_name = $2, active = $3, resource = $4 WHERE user_id = $5`)
+		if err := tx.Exec(ctx, query, scimExternalID(user), user.UserName, user.Active, resource, user.ID); err != nil {
+			return fmt.Errorf("failed to update scim user: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	user.Password = ""
+	return nil
+}
+
+// DeleteUser menghapus row scim_users dan users milik user.
+func (s *DatabaseSCIMUserStore) DeleteUser(ctx context.Context, id string) error {
+	return s.db.WithTx(ctx, func(ctx context.Context, tx Tx) error {
+		if err := s.checkExists(ctx, tx, id); err != nil {
+			return err
+		}
+		if err := tx.Exec(ctx, s.db.Rebind(`DELETE FROM scim_users WHERE user_id = $1`), id); err != nil {
+			return fmt.Errorf("failed to delete scim user: %w", err)
+		}
+		if err := tx.Exec(ctx, s.db.Rebind(`DELETE FROM users WHERE id = $1`), id); err != nil {
+			return fmt.Errorf("failed to delete user: %w", err)
+		}
+		return nil
+	})
+}
+
+// FindByEmail mencari user lewat AuthUserStore aplikasi dan menolak user SCIM yang dinonaktifkan.
+func (s *DatabaseSCIMUserStore) FindByEmail(ctx context.Context, email string) (Authenticatable, error) {
+	user, err := s.users.FindByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	return s.activeOnly(ctx, user)
+}
+
+// FindByID mencari user lewat AuthUserStore aplikasi dan menolak user SCIM yang dinonaktifkan.
+func (s *DatabaseSCIMUserStore) FindByID(ctx context.Context, id string) (Authenticatable, error) {
+	user, err := s.users.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.activeOnly(ctx, user)
+}
+
+// Update mendelegasikan ke AuthUserStore aplikasi.
+func (s *DatabaseSCIMUserStore) Update(ctx context.Context, user Authenticatable) error {
+	return s.users.Update(ctx, user)
+}
+
+// activeOnly mengembalikan user apa adanya kecuali user dikelola SCIM dan sedang nonaktif.
+// User lokal yang tidak punya row scim_users tidak terpengaruh.
+func (s *DatabaseSCIMUserStore) activeOnly(ctx context.Context, user Authenticatable) (Authenticatable, error) {
+	var active bool
+	query := s.db.Rebind(`SELECT active FROM scim_users WHERE user_id = $1`)
+	err := s.db.QueryRow(ctx, query, user.GetID()).Scan(&active)
+	if isNoRows(err) {
+		return user, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to check scim user status: %w", err)
+	}
+	if !active {
+		return nil, fmt.Errorf("%w: user is deactivated", ErrUserNotFound)
+	}
+	return user, nil
+}
+
+func (s *DatabaseSCIMUserStore) checkExists(ctx context.Context, tx Tx, id string) error {
+	var count int
+	query := s.db.Rebind(`SELECT COUNT(*) FROM scim_users WHERE user_id = $1`)
+	if err := tx.QueryRow(ctx, query, id).Scan(&count); err != nil {
+		return fmt.Errorf("failed to find scim user: %w", err)
+	}
+	if count == 0 {
+		return ErrSCIMNotFound
+	}
+	return nil
+}
+
+// checkUnique memastikan userName (case-insensitive), externalId, dan email belum dipakai user lain.
+func (s *DatabaseSCIMUserStore) checkUnique(ctx context.Context, tx Tx, user *SCIMUser, exceptID string) error {
+	var count int
+	query := s.db.Rebind(`SELECT COUNT(*) FROM scim_users WHERE user_id <> $1 AND (LOWER(user_name) = LOWER($2) OR external_id = $3)`)
+	if err := tx.QueryRow(ctx, query, exceptID, user.UserName, scimExternalID(user)).Scan(&count); err != nil {
+		return fmt.Errorf("failed to check scim user uniqueness: %w", err)
+	}
+	if count == 0 {
+		query = s.db.Rebind(`SELECT COUNT(*) FROM users WHERE id <> $1 AND email = $2`)
+		if err := tx.QueryRow(ctx, query, exceptID, scimUserEmail(user)).Scan(&count); err != nil {
+			return fmt.Errorf("failed to check user email uniqueness: %w", err)
+		}
+	}
+	if count > 0 {
+		return ErrSCIMConflict
+	}
+	return nil
+}
+
+func scanSCIMUserRow(row Row) (*SCIMUser, error) {
+	user, err := scanSCIMUser(row)
+	if isNoRows(err) {
+		return nil, ErrSCIMNotFound
+	}
+	return user, err
+}
+
+func scanSCIMUser(row Row) (*SCIMUser, error) {
+	var externalID *string
+	var resource string
+	user := &SCIMUser{Schemas: []string{SCIMSchemaUser}}
+	if err := row.Scan(&user.ID, &externalID, &user.UserName, &user.Active, &resource); err != nil {
+		return nil, err
+	}
+	if externalID != nil {
+		user.ExternalID = *externalID
+	}
+
+	var stored scimStoredUser
+	if err := json.Unmarshal([]byte(resource), &stored); err != nil {
+		return nil, fmt.Errorf("failed to decode scim user %s: %w", user.ID, err)
+	}
+	user.Name = stored.Name
+	user.DisplayName = stored.DisplayName
+	user.Emails = stored.Emails
+	user.Meta = stored.Meta
+	user.Attributes = stored.Attributes
+	return user, nil
+}
+
+func encodeSCIMUser(user *SCIMUser) (string, error) {
+	encoded, err := json.Marshal(scimStoredUser{
+		Name:        user.Name,
+		DisplayName: user.DisplayName,
+		Emails:      user.Emails,
+		Meta:        user.Meta,
+		Attributes:  user.Attributes,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode scim user: %w", err)
+	}
+	return string(encoded), nil
+}
+
+func scimPasswordHash(password string) (string, error) {
+	if password == "" {
+		return "", nil
+	}
+	hashed, err := HashPassword(password)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash scim password: %w", err)
+	}
+	return hashed, nil
+}
+
+// scimExternalID mengembalikan nil untuk externalId kosong agar unique index mengizinkan banyak NULL.
+func scimExternalID(user *SCIMUser) interface{} {
+	if user.ExternalID == "" {
+		return nil
+	}
+	return user.ExternalID
+}
+
+// scimUserEmail memilih email login: email primary, email pertama, lalu userName.
+func scimUserEmail(user *SCIMUser) string {
+	for _, email := range user.Emails {
+		if email.Primary && email.Value != "" {
+			return email.Value
+		}
+	}
+	for _, email := range user.Emails {
+		if email.Value != "" {
+			return email.Value
+		}
+	}
+	return user.UserName
+}
+
+func scimUserName(user *SCIMUser) string {
+	if user.DisplayName != "" {
+		return user.DisplayName
+	}
+	if user.Name != nil {
+		if user.Name.Formatted != "" {
+			return user.Name.Formatted
+		}
+		return strings.TrimSpace(user.Name.GivenName + " " + user.Name.FamilyName)
+	}
+	return ""
+}
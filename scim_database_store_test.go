@@ -0,0 +1,126 @@
+package dim
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func newTestSCIMDatabaseStore(t *testing.T) (*Router, *DatabaseSCIMUserStore) {
+	t.Helper()
+	db, err := NewSQLiteDatabase(DatabaseConfig{Database: ":memory:"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := RunMigrations(db, append(GetUserMigrations(), GetSCIMMigrations()...)); err != nil {
+		t.Fatalf("Migration failed: %v", err)
+	}
+
+	store := NewDatabaseSCIMUserStore(db, NewDatabaseAuthUserStore(db))
+	router := NewRouter()
+	NewSCIMServer(SCIMConfig{
+		Token:   scimTestToken,
+		BaseURL: "https://api.example.com/scim/v2",
+		Users:   store,
+	}).Register(router.Group("/scim/v2"))
+	router.Build()
+	return router, store
+}
+
+func TestDatabaseSCIMUserStore_ProvisionedUserCanLogin(t *testing.T) {
+	router, store := newTestSCIMDatabaseStore(t)
+	ctx := context.Background()
+
+	rec, created := scimRequest(t, router, http.MethodPost, "/scim/v2/Users", `{
+		"schemas": ["urn:ietf:params:scim:schemas:core:2.0:User"],
+		"externalId": "00u1abcd",
+		"userName": "bjensen",
+		"password": "ValidPass123!",
+		"name": {"givenName": "Barbara", "familyName": "Jensen"},
+		"emails": [{"value": "bjensen@example.com", "type": "work", "primary": true}]
+	}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if _, ok := created["password"]; ok {
+		t.Error("password must never be returned")
+	}
+	id, _ := created["id"].(string)
+
+	found, err := store.FindByExternalID(ctx, "00u1abcd")
+	if err != nil || found.ID != id || found.UserName != "bjensen" || found.Name.GivenName != "Barbara" {
+		t.Fatalf("FindByExternalID = %+v, %v", found, err)
+	}
+	if _, err := store.FindByExternalID(ctx, "missing"); !errors.Is(err, ErrSCIMNotFound) {
+		t.Errorf("FindByExternalID(missing) error = %v, want ErrSCIMNotFound", err)
+	}
+
+	service, err := NewAuthService(store, NewMockTokenStore(), nil, &JWTConfig{
+		HMACSecret:         "test-secret",
+		SigningMethod:      "HS256",
+		AccessTokenExpiry:  15 * time.Minute,
+		RefreshTokenExpiry: 7 * 24 * time.Hour,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := service.Login(ctx, "bjensen@example.com", "ValidPass123!"); err != nil {
+		t.Fatalf("Login after provisioning: %v", err)
+	}
+
+	// Duplicate userName (case-insensitive) and externalId
+	rec, body := scimRequest(t, router, http.MethodPost, "/scim/v2/Users", `{"userName": "BJENSEN"}`)
+	if rec.Code != http.StatusConflict || body["scimType"] != "uniqueness" {
+		t.Errorf("expected 409 for duplicate userName, got %d %v", rec.Code, body)
+	}
+	rec, _ = scimRequest(t, router, http.MethodPost, "/scim/v2/Users", `{"userName": "other", "externalId": "00u1abcd"}`)
+	if rec.Code != http.StatusConflict {
+		t.Errorf("expected 409 for duplicate externalId, got %d", rec.Code)
+	}
+
+	// Deactivation blocks login without touching the password
+	rec, _ = scimRequest(t, router, http.MethodPatch, "/scim/v2/Users/"+id, `{
+		"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"],
+		"Operations": [{"op": "Replace", "value": {"active": "False"}}]
+	}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 on deactivate, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if _, _, err := service.Login(ctx, "bjensen@example.com", "ValidPass123!"); err == nil {
+		t.Error("expected login to fail for deactivated user")
+	}
+	if _, err := store.FindByID(ctx, id); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("FindByID(deactivated) error = %v, want ErrUserNotFound", err)
+	}
+
+	rec, _ = scimRequest(t, router, http.MethodPatch, "/scim/v2/Users/"+id, `{
+		"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"],
+		"Operations": [{"op": "replace", "path": "active", "value": true}]
+	}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 on reactivate, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if _, _, err := service.Login(ctx, "bjensen@example.com", "ValidPass123!"); err != nil {
+		t.Errorf("Login after reactivation: %v", err)
+	}
+
+	rec, list := scimRequest(t, router, http.MethodGet, `/scim/v2/Users?filter=externalId%20eq%20%2200u1abcd%22`, "")
+	if rec.Code != http.StatusOK || list["totalResults"] != float64(1) {
+		t.Errorf("expected one user for externalId filter, got %d %v", rec.Code, list)
+	}
+
+	rec, _ = scimRequest(t, router, http.MethodDelete, "/scim/v2/Users/"+id, "")
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", rec.Code)
+	}
+	if _, err := store.FindByEmail(ctx, "bjensen@example.com"); err == nil {
+		t.Error("expected deleted user to be gone from the users table")
+	}
+	rec, _ = scimRequest(t, router, http.MethodDelete, "/scim/v2/Users/"+id, "")
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 deleting twice, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,332 @@
+package dim
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SCIMFilter adalah AST hasil parsing parameter "filter" SCIM 2.0 (RFC 7644 section 3.4.2.2).
+// Node logika memakai Op "and", "or", atau "not" dengan Children; node perbandingan memakai
+// Op "eq", "ne", "co", "sw", "ew", "gt", "ge", "lt", "le", atau "pr" dengan Attr dan Value.
+type SCIMFilter struct {
+	Op       string
+	Attr     string
+	Value    interface{} // string, float64, bool, atau nil
+	Children []*SCIMFilter
+}
+
+var scimCompareOps = map[string]bool{
+	"eq": true, "ne": true, "co": true, "sw": true, "ew": true,
+	"gt": true, "ge": true, "lt": true, "le": true,
+}
+
+// ParseSCIMFilter mem-parsing ekspresi filter SCIM.
+// Mendukung operator perbandingan, "pr", "and", "or", "not", dan tanda kurung.
+// Filter multi-valued dengan kurung siku (emails[type eq "work"]) tidak didukung.
+//
+// Parameters:
+//   - input: ekspresi filter, misal `userName eq "bjensen" and active eq true`
+//
+// Returns:
+//   - *SCIMFilter: AST filter, atau nil jika input kosong
+//   - error: error jika sintaks filter tidak valid
+func ParseSCIMFilter(input string) (*SCIMFilter, error) {
+	if strings.TrimSpace(input) == "" {
+		return nil, nil
+	}
+
+	tokens, err := tokenizeSCIMFilter(input)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &scimFilterParser{tokens: tokens}
+	f, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected token %q in filter", p.tokens[p.pos].text)
+	}
+	return f, nil
+}
+
+// Match mengevaluasi filter terhadap sebuah resource.
+// lookup mengembalikan semua nilai untuk attribute path (case-insensitive), misal
+// "emails.value" dapat mengembalikan beberapa nilai. Perbandingan string bersifat case-insensitive.
+func (f *SCIMFilter) Match(lookup func(attr string) []interface{}) bool {
+	if f == nil {
+		return true
+	}
+
+	switch f.Op {
+	case "and":
+		for _, c := range f.Children {
+			if !c.Match(lookup) {
+				return false
+			}
+		}
+		return true
+	case "or":
+		for _, c := range f.Children {
+			if c.Match(lookup) {
+				return true
+			}
+		}
+		return false
+	case "not":
+		return !f.Children[0].Match(lookup)
+	}
+
+	values := lookup(f.Attr)
+	if f.Op == "pr" {
+		for _, v := range values {
+			if v != nil && v != "" {
+				return true
+			}
+		}
+		return false
+	}
+
+	if f.Op == "ne" {
+		for _, v := range values {
+			if compareSCIMValue("eq", v, f.Value) {
+				return false
+			}
+		}
+		return true
+	}
+
+	for _, v := range values {
+		if compareSCIMValue(f.Op, v, f.Value) {
+			return true
+		}
+	}
+	return false
+}
+
+func compareSCIMValue(op string, actual, expected interface{}) bool {
+	switch exp := expected.(type) {
+	case nil:
+		return op == "eq" && actual == nil
+	case bool:
+		act, ok := actual.(bool)
+		return ok && op == "eq" && act == exp
+	case float64:
+		act, ok := toSCIMNumber(actual)
+		if !ok {
+			return false
+		}
+		switch op {
+		case "eq":
+			return act == exp
+		case "gt":
+			return act > exp
+		case "ge":
+			return act >= exp
+		case "lt":
+			return act < exp
+		case "le":
+			return act <= exp
+		}
+		return false
+	case string:
+		act, ok := actual.(string)
+		if !ok {
+			return false
+		}
+		act, exp = strings.ToLower(act), strings.ToLower(exp)
+		switch op {
+		case "eq":
+			return act == exp
+		case "co":
+			return strings.Contains(act, exp)
+		case "sw":
+			return strings.HasPrefix(act, exp)
+		case "ew":
+			return strings.HasSuffix(act, exp)
+		case "gt":
+			return act > exp
+		case "ge":
+			return act >= exp
+		case "lt":
+			return act < exp
+		case "le":
+			return act <= exp
+		}
+	}
+	return false
+}
+
+func toSCIMNumber(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+type scimToken struct {
+	text   string
+	quoted bool
+}
+
+func tokenizeSCIMFilter(input string) ([]scimToken, error) {
+	var tokens []scimToken
+	i := 0
+	for i < len(input) {
+		c := input[i]
+		switch {
+		case c == ' ' || c == '\t':
+			i++
+		case c == '(' || c == ')':
+			tokens = append(tokens, scimToken{text: string(c)})
+			i++
+		case c == '"':
+			j := i + 1
+			for j < len(input) && input[j] != '"' {
+				if input[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(input) {
+				return nil, fmt.Errorf("unterminated string in filter")
+			}
+			var s string
+			if err := json.Unmarshal([]byte(input[i:j+1]), &s); err != nil {
+				return nil, fmt.Errorf("invalid string in filter: %w", err)
+			}
+			tokens = append(tokens, scimToken{text: s, quoted: true})
+			i = j + 1
+		default:
+			j := i
+			for j < len(input) && input[j] != ' ' && input[j] != '\t' && input[j] != '(' && input[j] != ')' {
+				j++
+			}
+			tokens = append(tokens, scimToken{text: input[i:j]})
+			i = j
+		}
+	}
+	return tokens, nil
+}
+
+type scimFilterParser struct {
+	tokens []scimToken
+	pos    int
+}
+
+func (p *scimFilterParser) peekKeyword() string {
+	if p.pos >= len(p.tokens) || p.tokens[p.pos].quoted {
+		return ""
+	}
+	return strings.ToLower(p.tokens[p.pos].text)
+}
+
+func (p *scimFilterParser) parseOr() (*SCIMFilter, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekKeyword() == "or" {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &SCIMFilter{Op: "or", Children: []*SCIMFilter{left, right}}
+	}
+	return left, nil
+}
+
+func (p *scimFilterParser) parseAnd() (*SCIMFilter, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekKeyword() == "and" {
+		p.pos++
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &SCIMFilter{Op: "and", Children: []*SCIMFilter{left, right}}
+	}
+	return left, nil
+}
+
+func (p *scimFilterParser) parseUnary() (*SCIMFilter, error) {
+	switch p.peekKeyword() {
+	case "not":
+		p.pos++
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &SCIMFilter{Op: "not", Children: []*SCIMFilter{inner}}, nil
+	case "(":
+		p.pos++
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peekKeyword() != ")" {
+			return nil, fmt.Errorf("missing closing parenthesis in filter")
+		}
+		p.pos++
+		return inner, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *scimFilterParser) parseComparison() (*SCIMFilter, error) {
+	if p.pos+1 >= len(p.tokens) {
+		return nil, fmt.Errorf("incomplete filter expression")
+	}
+
+	attr := p.tokens[p.pos]
+	if attr.quoted || strings.Contains(attr.text, "[") {
+		return nil, fmt.Errorf("invalid attribute path %q in filter", attr.text)
+	}
+	op := strings.ToLower(p.tokens[p.pos+1].text)
+	p.pos += 2
+
+	if op == "pr" {
+		return &SCIMFilter{Op: "pr", Attr: attr.text}, nil
+	}
+	if !scimCompareOps[op] {
+		return nil, fmt.Errorf("unsupported filter operator %q", op)
+	}
+	if p.pos >= len(p.tokens) {
+		return nil, fmt.Errorf("missing value for operator %q", op)
+	}
+
+	tok := p.tokens[p.pos]
+	p.pos++
+
+	var value interface{}
+	switch {
+	case tok.quoted:
+		value = tok.text
+	case tok.text == "true":
+		value = true
+	case tok.text == "false":
+		value = false
+	case tok.text == "null":
+		value = nil
+	default:
+		n, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid filter value %q", tok.text)
+		}
+		value = n
+	}
+
+	return &SCIMFilter{Op: op, Attr: attr.text, Value: value}, nil
+}
@@ -0,0 +1,81 @@
+package dim
+
+import (
+	"testing"
+)
+
+func TestParseSCIMFilter(t *testing.T) {
+	user := &SCIMUser{
+		UserName: "bjensen@example.com",
+		Active:   true,
+		Name:     &SCIMName{GivenName: "Barbara", FamilyName: "Jensen"},
+		Emails: []SCIMMultiValue{
+			{Value: "bjensen@example.com", Type: "work"},
+			{Value: "babs@home.example", Type: "home"},
+		},
+		Attributes: map[string]interface{}{"employeeNumber": float64(701984)},
+	}
+
+	tests := []struct {
+		filter string
+		want   bool
+	}{
+		{`userName eq "BJensen@example.com"`, true},
+		{`userName ne "bjensen@example.com"`, false},
+		{`userName sw "bjen"`, true},
+		{`userName ew "@example.com"`, true},
+		{`name.familyName co "ens"`, true},
+		{`emails.value eq "babs@home.example"`, true},
+		{`emails.type eq "other"`, false},
+		{`active eq true`, true},
+		{`active eq false`, false},
+		{`employeeNumber gt 700000`, true},
+		{`externalId pr`, false},
+		{`name.givenName pr`, true},
+		{`userName eq "x" or active eq true`, true},
+		{`userName sw "b" and not (active eq true)`, false},
+		{`(userName eq "x" or userName eq "bjensen@example.com") and emails.type eq "work"`, true},
+		{`userName EQ "bjensen@example.com"`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			f, err := ParseSCIMFilter(tt.filter)
+			if err != nil {
+				t.Fatalf("parse error: %v", err)
+			}
+			if got := f.Match(user.Lookup); got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSCIMFilter_Empty(t *testing.T) {
+	f, err := ParseSCIMFilter("  ")
+	if err != nil || f != nil {
+		t.Fatalf("expected nil filter, got %v, %v", f, err)
+	}
+	if !f.Match(func(string) []interface{} { return nil }) {
+		t.Error("nil filter should match everything")
+	}
+}
+
+func TestParseSCIMFilter_Errors(t *testing.T) {
+	invalid := []string{
+		`userName`,
+		`userName eq`,
+		`userName foo "x"`,
+		`userName eq "unterminated`,
+		`(userName eq "x"`,
+		`userName eq "x" extra`,
+		`emails[type eq "work"]`,
+		`userName eq bareword`,
+	}
+
+	for _, input := range invalid {
+		if _, err := ParseSCIMFilter(input); err == nil {
+			t.Errorf("expected error for %q", input)
+		}
+	}
+}
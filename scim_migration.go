@@ -0,0 +1,68 @@
+package dim
+
+import (
+	"context"
+)
+
+// GetSCIMMigrations mengembalikan migrasi tabel scim_users untuk DatabaseSCIMUserStore.
+// Tabel ini menyimpan externalId, status active, dan attribute SCIM user; data login tetap
+// berada di tabel users sehingga migrasi users harus dijalankan lebih dulu.
+// Migrasi ini opsional dan tidak termasuk dalam GetFrameworkMigrations. Menggunakan versi 91.
+//
+// Example:
+//
+//	migrations := append(dim.GetFrameworkMigrations(), dim.GetSCIMMigrations()...)
+//	dim.RunMigrations(db, migrations)
+func GetSCIMMigrations() []Migration {
+	return []Migration{
+		{
+			Version: 91,
+			Name:    "create_scim_users_table",
+			Up:      CreateSCIMUsersTable,
+			Down:    DropSCIMUsersTable,
+		},
+	}
+}
+
+// CreateSCIMUsersTable membuat tabel scim_users.
+func CreateSCIMUsersTable(db Database) error {
+	var query string
+	if db.DriverName() == "sqlite" {
+		query = `
+			CREATE TABLE IF NOT EXISTS scim_users (
+				user_id TEXT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+				external_id TEXT UNIQUE,
+				user_name TEXT NOT NULL UNIQUE,
+				active BOOLEAN NOT NULL DEFAULT 1,
+				resource TEXT NOT NULL
+			);
+		`
+	} else if db.DriverName() == "mysql" {
+		query = `
+			CREATE TABLE IF NOT EXISTS scim_users (
+				user_id CHAR(36) PRIMARY KEY,
+				external_id VARCHAR(255) UNIQUE,
+				user_name VARCHAR(255) NOT NULL UNIQUE,
+				active BOOLEAN NOT NULL DEFAULT TRUE,
+				resource TEXT NOT NULL,
+				FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+		`
+	} else {
+		query = `
+			CREATE TABLE IF NOT EXISTS scim_users (
+				user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+				external_id VARCHAR(255) UNIQUE,
+				user_name VARCHAR(255) NOT NULL UNIQUE,
+				active BOOLEAN NOT NULL DEFAULT TRUE,
+				resource TEXT NOT NULL
+			);
+		`
+	}
+	return db.Exec(context.Background(), query)
+}
+
+// DropSCIMUsersTable menghapus tabel scim_users.
+func DropSCIMUsersTable(db Database) error {
+	return db.Exec(context.Background(), "DROP TABLE IF EXISTS scim_users")
+}
@@ -0,0 +1,231 @@
+package dim
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// MemorySCIMStore adalah implementasi SCIMUserStore dan SCIMGroupStore di memori.
+// Cocok untuk testing dan development; gunakan store berbasis database di production.
+// Attribute groups pada user dihitung dari keanggotaan group.
+type MemorySCIMStore struct {
+	mu     sync.RWMutex
+	users  map[string]*SCIMUser
+	groups map[string]*SCIMGroup
+}
+
+// NewMemorySCIMStore membuat MemorySCIMStore kosong.
+func NewMemorySCIMStore() *MemorySCIMStore {
+	return &MemorySCIMStore{
+		users:  make(map[string]*SCIMUser),
+		groups: make(map[string]*SCIMGroup),
+	}
+}
+
+// ListUsers mengembalikan user yang cocok dengan filter, diurutkan berdasarkan userName.
+func (s *MemorySCIMStore) ListUsers(ctx context.Context, query SCIMListQuery) ([]*SCIMUser, int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var matched []*SCIMUser
+	for _, u := range s.users {
+		user := s.userWithGroups(u)
+		if query.Filter.Match(user.Lookup) {
+			matched = append(matched, user)
+		}
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		return strings.ToLower(matched[i].UserName) < strings.ToLower(matched[j].UserName)
+	})
+
+	start, end := query.Window(len(matched))
+	return matched[start:end], len(matched), nil
+}
+
+// GetUser mengambil user berdasarkan ID.
+func (s *MemorySCIMStore) GetUser(ctx context.Context, id string) (*SCIMUser, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	u, ok := s.users[id]
+	if !ok {
+		return nil, ErrSCIMNotFound
+	}
+	return s.userWithGroups(u), nil
+}
+
+// CreateUser menyimpan user baru dengan ID UUID v4. userName harus unik (case-insensitive).
+func (s *MemorySCIMStore) CreateUser(ctx context.Context, user *SCIMUser) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.userNameTaken(user.UserName, "") {
+		return ErrSCIMConflict
+	}
+	user.ID = NewV4().String()
+	s.users[user.ID] = copySCIMUser(user)
+	return nil
+}
+
+// ReplaceUser mengganti user yang sudah ada.
+func (s *MemorySCIMStore) ReplaceUser(ctx context.Context, user *SCIMUser) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.users[user.ID]; !ok {
+		return ErrSCIMNotFound
+	}
+	if s.userNameTaken(user.UserName, user.ID) {
+		return ErrSCIMConflict
+	}
+	s.users[user.ID] = copySCIMUser(user)
+	return nil
+}
+
+// DeleteUser menghapus user dan keanggotaannya di semua group.
+func (s *MemorySCIMStore) DeleteUser(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.users[id]; !ok {
+		return ErrSCIMNotFound
+	}
+	delete(s.users, id)
+	for _, g := range s.groups {
+		g.Members = scimRemoveMembers(g.Members, func(m SCIMMultiValue) bool { return m.Value == id })
+	}
+	return nil
+}
+
+// ListGroups mengembalikan group yang cocok dengan filter, diurutkan berdasarkan displayName.
+func (s *MemorySCIMStore) ListGroups(ctx context.Context, query SCIMListQuery) ([]*SCIMGroup, int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var matched []*SCIMGroup
+	for _, g := range s.groups {
+		if query.Filter.Match(g.Lookup) {
+			matched = append(matched, copySCIMGroup(g))
+		}
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		return strings.ToLower(matched[i].DisplayName) < strings.ToLower(matched[j].DisplayName)
+	})
+
+	start, end := query.Window(len(matched))
+	return matched[start:end], len(matched), nil
+}
+
+// GetGroup mengambil group berdasarkan ID.
+func (s *MemorySCIMStore) GetGroup(ctx context.Context, id string) (*SCIMGroup, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	g, ok := s.groups[id]
+	if !ok {
+		return nil, ErrSCIMNotFound
+	}
+	return copySCIMGroup(g), nil
+}
+
+// CreateGroup menyimpan group baru dengan ID UUID v4. displayName harus unik (case-insensitive).
+func (s *MemorySCIMStore) CreateGroup(ctx context.Context, group *SCIMGroup) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.groupNameTaken(group.DisplayName, "") {
+		return ErrSCIMConflict
+	}
+	group.ID = NewV4().String()
+	s.groups[group.ID] = copySCIMGroup(group)
+	return nil
+}
+
+// ReplaceGroup mengganti group yang sudah ada.
+func (s *MemorySCIMStore) ReplaceGroup(ctx context.Context, group *SCIMGroup) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.groups[group.ID]; !ok {
+		return ErrSCIMNotFound
+	}
+	if s.groupNameTaken(group.DisplayName, group.ID) {
+		return ErrSCIMConflict
+	}
+	s.groups[group.ID] = copySCIMGroup(group)
+	return nil
+}
+
+// DeleteGroup menghapus group.
+func (s *MemorySCIMStore) DeleteGroup(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.groups[id]; !ok {
+		return ErrSCIMNotFound
+	}
+	delete(s.groups, id)
+	return nil
+}
+
+func (s *MemorySCIMStore) userNameTaken(userName, exceptID string) bool {
+	for id, u := range s.users {
+		if id != exceptID && strings.EqualFold(u.UserName, userName) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *MemorySCIMStore) groupNameTaken(name, exceptID string) bool {
+	for id, g := range s.groups {
+		if id != exceptID && strings.EqualFold(g.DisplayName, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *MemorySCIMStore) userWithGroups(u *SCIMUser) *SCIMUser {
+	user := copySCIMUser(u)
+	user.Groups = nil
+	for _, g := range s.groups {
+		if scimHasMember(g.Members, u.ID) {
+			user.Groups = append(user.Groups, SCIMMultiValue{Value: g.ID, Display: g.DisplayName})
+		}
+	}
+	sort.Slice(user.Groups, func(i, j int) bool { return user.Groups[i].Display < user.Groups[j].Display })
+	return user
+}
+
+func copySCIMUser(u *SCIMUser) *SCIMUser {
+	out := *u
+	if u.Name != nil {
+		name := *u.Name
+		out.Name = &name
+	}
+	if u.Meta != nil {
+		meta := *u.Meta
+		out.Meta = &meta
+	}
+	out.Emails = append([]SCIMMultiValue(nil), u.Emails...)
+	if u.Attributes != nil {
+		out.Attributes = make(map[string]interface{}, len(u.Attributes))
+		for k, v := range u.Attributes {
+			out.Attributes[k] = v
+		}
+	}
+	return &out
+}
+
+func copySCIMGroup(g *SCIMGroup) *SCIMGroup {
+	out := *g
+	if g.Meta != nil {
+		meta := *g.Meta
+		out.Meta = &meta
+	}
+	out.Members = append([]SCIMMultiValue(nil), g.Members...)
+	return &out
+}
@@ -0,0 +1,259 @@
+package dim
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const scimTestToken = "scim-secret"
+
+func newSCIMTestRouter(t *testing.T, config SCIMConfig) (*Router, *MemorySCIMStore) {
+	t.Helper()
+	store := NewMemorySCIMStore()
+	config.Token = scimTestToken
+	config.BaseURL = "https://api.example.com/scim/v2"
+	config.Users = store
+	config.Groups = store
+
+	router := NewRouter()
+	NewSCIMServer(config).Register(router.Group("/scim/v2"))
+	router.Build()
+	return router, store
+}
+
+func scimRequest(t *testing.T, router *Router, method, path, body string) (*httptest.ResponseRecorder, map[string]interface{}) {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+scimTestToken)
+	req.Header.Set("Content-Type", SCIMContentType)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	var decoded map[string]interface{}
+	if rec.Body.Len() > 0 {
+		if err := json.Unmarshal(rec.Body.Bytes(), &decoded); err != nil {
+			t.Fatalf("invalid JSON response: %v (%s)", err, rec.Body.String())
+		}
+	}
+	return rec, decoded
+}
+
+func TestSCIM_RequiresBearerToken(t *testing.T) {
+	router, _ := newSCIMTestRouter(t, SCIMConfig{})
+
+	for _, auth := range []string{"", "Bearer wrong"} {
+		req := httptest.NewRequest(http.MethodGet, "/scim/v2/Users", nil)
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("auth %q: expected 401, got %d", auth, rec.Code)
+		}
+		if !strings.Contains(rec.Body.String(), SCIMSchemaError) {
+			t.Errorf("auth %q: expected SCIM error body, got %s", auth, rec.Body.String())
+		}
+	}
+}
+
+func TestSCIM_UserLifecycle(t *testing.T) {
+	router, _ := newSCIMTestRouter(t, SCIMConfig{})
+
+	rec, created := scimRequest(t, router, http.MethodPost, "/scim/v2/Users", `{
+		"schemas": ["urn:ietf:params:scim:schemas:core:2.0:User"],
+		"userName": "bjensen@example.com",
+		"name": {"givenName": "Barbara", "familyName": "Jensen"},
+		"emails": [{"value": "bjensen@example.com", "type": "work", "primary": true}]
+	}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec.Header().Get("Content-Type") != SCIMContentType {
+		t.Errorf("unexpected content type %q", rec.Header().Get("Content-Type"))
+	}
+
+	id, _ := created["id"].(string)
+	if id == "" {
+		t.Fatal("expected id in response")
+	}
+	if created["active"] != true {
+		t.Error("expected active to default to true")
+	}
+	if loc := rec.Header().Get("Location"); loc != "https://api.example.com/scim/v2/Users/"+id {
+		t.Errorf("unexpected Location %q", loc)
+	}
+
+	// Duplicate userName
+	rec, body := scimRequest(t, router, http.MethodPost, "/scim/v2/Users", `{"userName": "BJENSEN@example.com"}`)
+	if rec.Code != http.StatusConflict || body["scimType"] != "uniqueness" {
+		t.Errorf("expected 409 uniqueness, got %d %v", rec.Code, body)
+	}
+
+	// Azure AD style PATCH without path
+	rec, patched := scimRequest(t, router, http.MethodPatch, "/scim/v2/Users/"+id, `{
+		"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"],
+		"Operations": [{"op": "Replace", "value": {"active": "False", "displayName": "Babs"}}]
+	}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if patched["active"] != false || patched["displayName"] != "Babs" {
+		t.Errorf("patch not applied: %v", patched)
+	}
+
+	// Okta style PATCH with path
+	rec, patched = scimRequest(t, router, http.MethodPatch, "/scim/v2/Users/"+id, `{
+		"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"],
+		"Operations": [{"op": "replace", "path": "active", "value": true}]
+	}`)
+	if rec.Code != http.StatusOK || patched["active"] != true {
+		t.Errorf("expected active true, got %d %v", rec.Code, patched)
+	}
+
+	rec, _ = scimRequest(t, router, http.MethodPut, "/scim/v2/Users/"+id, `{"userName": "barbara@example.com", "active": false}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 on PUT, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec, fetched := scimRequest(t, router, http.MethodGet, "/scim/v2/Users/"+id, "")
+	if rec.Code != http.StatusOK || fetched["userName"] != "barbara@example.com" {
+		t.Errorf("unexpected user after PUT: %d %v", rec.Code, fetched)
+	}
+
+	rec, _ = scimRequest(t, router, http.MethodDelete, "/scim/v2/Users/"+id, "")
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("expected 204, got %d", rec.Code)
+	}
+
+	rec, body = scimRequest(t, router, http.MethodGet, "/scim/v2/Users/"+id, "")
+	if rec.Code != http.StatusNotFound || body["status"] != "404" {
+		t.Errorf("expected 404 SCIM error, got %d %v", rec.Code, body)
+	}
+}
+
+func TestSCIM_ListUsers_FilterAndPagination(t *testing.T) {
+	router, store := newSCIMTestRouter(t, SCIMConfig{})
+	ctx := context.Background()
+	for _, name := range []string{"alice@example.com", "bob@example.com", "carol@corp.example", "dave@example.com"} {
+		store.CreateUser(ctx, &SCIMUser{UserName: name, Active: true})
+	}
+
+	rec, body := scimRequest(t, router, http.MethodGet, `/scim/v2/Users?filter=userName+ew+%22%40example.com%22&startIndex=2&count=1`, "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if body["totalResults"] != float64(3) || body["startIndex"] != float64(2) || body["itemsPerPage"] != float64(1) {
+		t.Errorf("unexpected list metadata: %v", body)
+	}
+	resources := body["Resources"].([]interface{})
+	if len(resources) != 1 || resources[0].(map[string]interface{})["userName"] != "bob@example.com" {
+		t.Errorf("unexpected resources: %v", resources)
+	}
+
+	rec, body = scimRequest(t, router, http.MethodGet, `/scim/v2/Users?filter=userName+xx+%22a%22`, "")
+	if rec.Code != http.StatusBadRequest || body["scimType"] != "invalidFilter" {
+		t.Errorf("expected invalidFilter, got %d %v", rec.Code, body)
+	}
+}
+
+func TestSCIM_GroupMembership(t *testing.T) {
+	router, store := newSCIMTestRouter(t, SCIMConfig{})
+	ctx := context.Background()
+	alice := &SCIMUser{UserName: "alice@example.com", Active: true}
+	bob := &SCIMUser{UserName: "bob@example.com", Active: true}
+	store.CreateUser(ctx, alice)
+	store.CreateUser(ctx, bob)
+
+	rec, group := scimRequest(t, router, http.MethodPost, "/scim/v2/Groups", `{"displayName": "Admins", "members": [{"value": "`+alice.ID+`"}]}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	groupID := group["id"].(string)
+
+	rec, _ = scimRequest(t, router, http.MethodPatch, "/scim/v2/Groups/"+groupID, `{
+		"Operations": [
+			{"op": "add", "path": "members", "value": [{"value": "`+bob.ID+`"}]},
+			{"op": "remove", "path": "members[value eq \"`+alice.ID+`\"]"}
+		]
+	}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	g, _ := store.GetGroup(ctx, groupID)
+	if len(g.Members) != 1 || g.Members[0].Value != bob.ID {
+		t.Errorf("unexpected members: %v", g.Members)
+	}
+
+	_, user := scimRequest(t, router, http.MethodGet, "/scim/v2/Users/"+bob.ID, "")
+	groups, _ := user["groups"].([]interface{})
+	if len(groups) != 1 || groups[0].(map[string]interface{})["display"] != "Admins" {
+		t.Errorf("expected bob to be in Admins, got %v", user["groups"])
+	}
+}
+
+func TestSCIM_CustomAttributeHooks(t *testing.T) {
+	var mappedDepartment string
+	router, store := newSCIMTestRouter(t, SCIMConfig{
+		MapUserIn: func(ctx context.Context, raw map[string]interface{}, user *SCIMUser) error {
+			if ext, ok := raw[SCIMSchemaEnterpriseUser].(map[string]interface{}); ok {
+				mappedDepartment, _ = ext["department"].(string)
+			}
+			return nil
+		},
+		MapUserOut: func(ctx context.Context, user *SCIMUser, resource map[string]interface{}) {
+			resource["tenant"] = "acme"
+		},
+	})
+
+	rec, created := scimRequest(t, router, http.MethodPost, "/scim/v2/Users", `{
+		"userName": "erin@example.com",
+		"urn:ietf:params:scim:schemas:extension:enterprise:2.0:User": {"department": "Finance"}
+	}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if mappedDepartment != "Finance" {
+		t.Errorf("MapUserIn did not receive extension, got %q", mappedDepartment)
+	}
+	if created["tenant"] != "acme" {
+		t.Errorf("MapUserOut not applied: %v", created)
+	}
+	if _, ok := created[SCIMSchemaEnterpriseUser]; !ok {
+		t.Error("expected extension attributes to round-trip")
+	}
+
+	schemas := created["schemas"].([]interface{})
+	if len(schemas) != 2 || schemas[1] != SCIMSchemaEnterpriseUser {
+		t.Errorf("expected extension schema in schemas, got %v", schemas)
+	}
+
+	stored, _ := store.GetUser(context.Background(), created["id"].(string))
+	if stored.Attributes[SCIMSchemaEnterpriseUser] == nil {
+		t.Error("expected extension to be stored in Attributes")
+	}
+}
+
+func TestSCIM_ServiceProviderConfig(t *testing.T) {
+	router, _ := newSCIMTestRouter(t, SCIMConfig{})
+
+	rec, body := scimRequest(t, router, http.MethodGet, "/scim/v2/ServiceProviderConfig", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if patch := body["patch"].(map[string]interface{}); patch["supported"] != true {
+		t.Errorf("expected patch supported, got %v", body["patch"])
+	}
+
+	_, types := scimRequest(t, router, http.MethodGet, "/scim/v2/ResourceTypes", "")
+	if types["totalResults"] != float64(2) {
+		t.Errorf("expected User and Group resource types, got %v", types)
+	}
+}
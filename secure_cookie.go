@@ -0,0 +1,308 @@
+package dim
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Error yang dikembalikan SecureCookieCodec.
+var (
+	// ErrCookieInvalid dikembalikan jika cookie rusak, diubah, atau dibuat dengan key yang tidak dikenal.
+	ErrCookieInvalid = errors.New("secure cookie: invalid value")
+	// ErrCookieExpired dikembalikan jika waktu kadaluarsa di dalam cookie sudah lewat.
+	ErrCookieExpired = errors.New("secure cookie: expired")
+	// ErrCookieTooLarge dikembalikan jika hasil encode melebihi batas 4096 byte browser.
+	ErrCookieTooLarge = errors.New("secure cookie: value too large")
+)
+
+const (
+	secureCookieMinSecret = 32
+	secureCookieMaxSize   = 4096
+	secureCookieMACSize   = sha256.Size
+)
+
+// CookieOptions mengatur atribut cookie yang ditulis oleh SecureCookieCodec.
+// Zero value menghasilkan cookie yang aman: Path "/", HttpOnly, Secure, SameSite=Lax, session cookie.
+type CookieOptions struct {
+	Path   string
+	Domain string
+	// MaxAge adalah umur cookie. 0 berarti session cookie (tanpa kadaluarsa di server).
+	// Waktu kadaluarsa juga disimpan terenkripsi di dalam cookie, sehingga cookie yang
+	// disimpan client melewati MaxAge tetap ditolak.
+	MaxAge time.Duration
+	// SameSite default http.SameSiteLaxMode.
+	SameSite http.SameSite
+	// DisableSecure mengizinkan cookie dikirim lewat HTTP biasa (hanya untuk development).
+	DisableSecure bool
+	// DisableHttpOnly membuat cookie dapat dibaca JavaScript.
+	DisableHttpOnly bool
+}
+
+// secureCookieKey berisi key turunan dari satu secret.
+type secureCookieKey struct {
+	aead   cipher.AEAD
+	macKey []byte
+}
+
+// SecureCookieCodec mengenkripsi (AES-256-GCM) dan menandatangani (HMAC-SHA256) nilai cookie.
+// Nilai apa pun yang dapat di-encode JSON (string, struct kecil) dapat disimpan.
+// Nama cookie ikut diautentikasi sehingga nilai tidak dapat dipindah ke cookie lain.
+// Mendukung rotasi key: cookie baru selalu memakai secret utama, cookie lama dari
+// secret sebelumnya tetap diterima. Thread-safe.
+type SecureCookieCodec struct {
+	keys []secureCookieKey
+	now  func() time.Time
+}
+
+// SecureCookies membuat SecureCookieCodec dari secret utama.
+// Key enkripsi dan key HMAC diturunkan dari secret dengan HKDF-SHA256.
+//
+// Parameters:
+//   - secret: secret minimal 32 byte, contoh: openssl rand -hex 32
+//   - previous: secret lama yang masih diterima saat rotasi key (opsional)
+//
+// Returns:
+//   - *SecureCookieCodec: codec yang siap digunakan
+//   - error: error jika ada secret yang kurang dari 32 byte
+//
+// Example:
+//
+//	cookies, err := dim.SecureCookies(os.Getenv("COOKIE_SECRET"), os.Getenv("COOKIE_SECRET_OLD"))
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+func SecureCookies(secret string, previous ...string) (*SecureCookieCodec, error) {
+	c := &SecureCookieCodec{now: time.Now}
+
+	for i, s := range append([]string{secret}, previous...) {
+		if i > 0 && s == "" {
+			continue // allow an unset previous secret from env
+		}
+		key, err := deriveSecureCookieKey(s)
+		if err != nil {
+			return nil, err
+		}
+		c.keys = append(c.keys, key)
+	}
+	return c, nil
+}
+
+func deriveSecureCookieKey(secret string) (secureCookieKey, error) {
+	if len(secret) < secureCookieMinSecret {
+		return secureCookieKey{}, fmt.Errorf("secure cookie: secret must be at least %d bytes", secureCookieMinSecret)
+	}
+
+	encKey, err := hkdf.Key(sha256.New, []byte(secret), nil, "dim secure cookie encryption", 32)
+	if err != nil {
+		return secureCookieKey{}, err
+	}
+	macKey, err := hkdf.Key(sha256.New, []byte(secret), nil, "dim secure cookie authentication", 32)
+	if err != nil {
+		return secureCookieKey{}, err
+	}
+
+	block, err := aes.NewCipher(encKey)
+	if err != nil {
+		return secureCookieKey{}, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return secureCookieKey{}, err
+	}
+	return secureCookieKey{aead: aead, macKey: macKey}, nil
+}
+
+// Set meng-encode value dan menulis cookie ke response.
+//
+// Parameters:
+//   - w: http.ResponseWriter untuk menulis cookie
+//   - name: nama cookie
+//   - value: nilai yang dapat di-encode JSON (string, struct, map)
+//   - opts: atribut cookie, nil untuk default yang aman
+//
+// Returns:
+//   - error: error jika encoding gagal atau cookie melebihi 4096 byte
+//
+// Example:
+//
+//	type flash struct{ Type, Message string }
+//	cookies.Set(w, "flash", flash{"success", "Profil disimpan"}, &dim.CookieOptions{MaxAge: time.Minute})
+func (c *SecureCookieCodec) Set(w http.ResponseWriter, name string, value interface{}, opts *CookieOptions) error {
+	if opts == nil {
+		opts = &CookieOptions{}
+	}
+
+	var expiresAt time.Time
+	if opts.MaxAge > 0 {
+		expiresAt = c.now().Add(opts.MaxAge)
+	}
+
+	encoded, err := c.Encode(name, value, expiresAt)
+	if err != nil {
+		return err
+	}
+
+	cookie := secureCookieHeader(name, encoded, opts)
+	if len(cookie.String()) > secureCookieMaxSize {
+		return ErrCookieTooLarge
+	}
+	http.SetCookie(w, cookie)
+	return nil
+}
+
+// Get membaca cookie dari request dan men-decode nilainya ke dst.
+//
+// Parameters:
+//   - r: *http.Request sumber cookie
+//   - name: nama cookie
+//   - dst: pointer tujuan decode
+//
+// Returns:
+//   - error: http.ErrNoCookie jika cookie tidak ada, ErrCookieInvalid atau ErrCookieExpired
+//
+// Example:
+//
+//	var f flash
+//	if err := cookies.Get(r, "flash", &f); err == nil {
+//	    cookies.Delete(w, "flash", nil)
+//	}
+func (c *SecureCookieCodec) Get(r *http.Request, name string, dst interface{}) error {
+	cookie, err := r.Cookie(name)
+	if err != nil {
+		return err
+	}
+	return c.Decode(name, cookie.Value, dst)
+}
+
+// Delete menghapus cookie di browser. opts harus memiliki Path dan Domain yang sama dengan saat Set.
+func (c *SecureCookieCodec) Delete(w http.ResponseWriter, name string, opts *CookieOptions) {
+	if opts == nil {
+		opts = &CookieOptions{}
+	}
+	cookie := secureCookieHeader(name, "", opts)
+	cookie.MaxAge = -1
+	cookie.Expires = time.Unix(0, 0)
+	http.SetCookie(w, cookie)
+}
+
+// Encode mengenkripsi dan menandatangani value untuk cookie bernama name.
+// expiresAt zero berarti tidak kadaluarsa. Berguna untuk menyimpan nilai terenkripsi
+// di luar cookie (misal hidden form field).
+//
+// Format: base64url(nonce || AES-GCM(expires || json(value)) || HMAC-SHA256(name || ...))
+func (c *SecureCookieCodec) Encode(name string, value interface{}, expiresAt time.Time) (string, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("secure cookie: encode value: %w", err)
+	}
+
+	var expires int64
+	if !expiresAt.IsZero() {
+		expires = expiresAt.Unix()
+	}
+	plaintext := make([]byte, 8, 8+len(data))
+	binary.BigEndian.PutUint64(plaintext, uint64(expires))
+	plaintext = append(plaintext, data...)
+
+	key := c.keys[0]
+	nonce := make([]byte, key.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("secure cookie: generate nonce: %w", err)
+	}
+
+	blob := key.aead.Seal(nonce, nonce, plaintext, []byte(name))
+	blob = append(blob, secureCookieMAC(key.macKey, name, blob)...)
+
+	encoded := base64.RawURLEncoding.EncodeToString(blob)
+	if len(encoded) > secureCookieMaxSize {
+		return "", ErrCookieTooLarge
+	}
+	return encoded, nil
+}
+
+// Decode memverifikasi dan mendekripsi nilai hasil Encode ke dst.
+// Semua key (utama dan sebelumnya) dicoba, sehingga cookie lama tetap valid selama rotasi.
+func (c *SecureCookieCodec) Decode(name, encoded string, dst interface{}) error {
+	if len(encoded) > secureCookieMaxSize {
+		return ErrCookieInvalid
+	}
+	blob, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return ErrCookieInvalid
+	}
+
+	for _, key := range c.keys {
+		if len(blob) < key.aead.NonceSize()+key.aead.Overhead()+secureCookieMACSize {
+			return ErrCookieInvalid
+		}
+
+		sealed, mac := blob[:len(blob)-secureCookieMACSize], blob[len(blob)-secureCookieMACSize:]
+		if !hmac.Equal(mac, secureCookieMAC(key.macKey, name, sealed)) {
+			continue
+		}
+
+		nonce, ciphertext := sealed[:key.aead.NonceSize()], sealed[key.aead.NonceSize():]
+		plaintext, err := key.aead.Open(nil, nonce, ciphertext, []byte(name))
+		if err != nil {
+			return ErrCookieInvalid
+		}
+
+		expires := int64(binary.BigEndian.Uint64(plaintext[:8]))
+		if expires != 0 && c.now().Unix() > expires {
+			return ErrCookieExpired
+		}
+
+		if err := json.Unmarshal(plaintext[8:], dst); err != nil {
+			return fmt.Errorf("secure cookie: decode value: %w", err)
+		}
+		return nil
+	}
+
+	return ErrCookieInvalid
+}
+
+// secureCookieMAC menghitung HMAC atas nama cookie dan data terenkripsi.
+func secureCookieMAC(key []byte, name string, sealed []byte) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(name))
+	h.Write([]byte{0})
+	h.Write(sealed)
+	return h.Sum(nil)
+}
+
+// secureCookieHeader membangun http.Cookie dari CookieOptions dengan default yang aman.
+func secureCookieHeader(name, value string, opts *CookieOptions) *http.Cookie {
+	path := opts.Path
+	if path == "" {
+		path = "/"
+	}
+	sameSite := opts.SameSite
+	if sameSite == 0 {
+		sameSite = http.SameSiteLaxMode
+	}
+
+	cookie := &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     path,
+		Domain:   opts.Domain,
+		Secure:   !opts.DisableSecure,
+		HttpOnly: !opts.DisableHttpOnly,
+		SameSite: sameSite,
+	}
+	if opts.MaxAge > 0 {
+		cookie.MaxAge = int(opts.MaxAge / time.Second)
+	}
+	return cookie
+}
@@ -0,0 +1,178 @@
+package dim
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	testCookieSecret    = "0123456789abcdef0123456789abcdef"
+	testCookieSecretOld = "fedcba9876543210fedcba9876543210"
+)
+
+type testFlash struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+// cookieRequest builds a request carrying the cookies set on the recorder.
+func cookieRequest(w *httptest.ResponseRecorder) *http.Request {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	for _, c := range w.Result().Cookies() {
+		r.AddCookie(c)
+	}
+	return r
+}
+
+func TestSecureCookies_InvalidSecret(t *testing.T) {
+	if _, err := SecureCookies("short"); err == nil {
+		t.Error("expected error for short secret")
+	}
+	if _, err := SecureCookies(testCookieSecret, "short"); err == nil {
+		t.Error("expected error for short previous secret")
+	}
+	if _, err := SecureCookies(testCookieSecret, ""); err != nil {
+		t.Errorf("empty previous secret should be ignored: %v", err)
+	}
+}
+
+func TestSecureCookieCodec_SetGet(t *testing.T) {
+	c, _ := SecureCookies(testCookieSecret)
+
+	w := httptest.NewRecorder()
+	if err := c.Set(w, "flash", testFlash{"success", "Profil disimpan"}, nil); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	cookie := w.Result().Cookies()[0]
+	if !cookie.HttpOnly || !cookie.Secure || cookie.SameSite != http.SameSiteLaxMode || cookie.Path != "/" {
+		t.Errorf("cookie attributes = %+v, want secure defaults", cookie)
+	}
+	if strings.Contains(cookie.Value, "Profil") {
+		t.Error("cookie value must be encrypted")
+	}
+
+	var got testFlash
+	if err := c.Get(cookieRequest(w), "flash", &got); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got != (testFlash{"success", "Profil disimpan"}) {
+		t.Errorf("Get() = %+v", got)
+	}
+
+	var missing string
+	if err := c.Get(httptest.NewRequest(http.MethodGet, "/", nil), "flash", &missing); !errors.Is(err, http.ErrNoCookie) {
+		t.Errorf("missing cookie error = %v", err)
+	}
+}
+
+func TestSecureCookieCodec_Tampering(t *testing.T) {
+	c, _ := SecureCookies(testCookieSecret)
+	value, _ := c.Encode("session", "user-1", time.Time{})
+
+	var got string
+	// Value moved to another cookie name
+	if err := c.Decode("remember", value, &got); !errors.Is(err, ErrCookieInvalid) {
+		t.Errorf("renamed cookie error = %v", err)
+	}
+
+	// Flip one character in the middle of the value
+	b := []byte(value)
+	if b[20] == 'A' {
+		b[20] = 'B'
+	} else {
+		b[20] = 'A'
+	}
+	if err := c.Decode("session", string(b), &got); !errors.Is(err, ErrCookieInvalid) {
+		t.Errorf("tampered cookie error = %v", err)
+	}
+
+	if err := c.Decode("session", "abc", &got); !errors.Is(err, ErrCookieInvalid) {
+		t.Errorf("short cookie error = %v", err)
+	}
+
+	other, _ := SecureCookies(testCookieSecretOld)
+	if err := other.Decode("session", value, &got); !errors.Is(err, ErrCookieInvalid) {
+		t.Errorf("foreign key error = %v", err)
+	}
+}
+
+func TestSecureCookieCodec_KeyRotation(t *testing.T) {
+	old, _ := SecureCookies(testCookieSecretOld)
+	value, _ := old.Encode("session", "user-1", time.Time{})
+
+	rotated, _ := SecureCookies(testCookieSecret, testCookieSecretOld)
+	var got string
+	if err := rotated.Decode("session", value, &got); err != nil || got != "user-1" {
+		t.Fatalf("Decode() with previous key = %q, %v", got, err)
+	}
+
+	// New cookies use the primary secret only
+	fresh, _ := rotated.Encode("session", "user-2", time.Time{})
+	if err := old.Decode("session", fresh, &got); !errors.Is(err, ErrCookieInvalid) {
+		t.Errorf("new cookie should not be signed with the old key: %v", err)
+	}
+	primaryOnly, _ := SecureCookies(testCookieSecret)
+	if err := primaryOnly.Decode("session", fresh, &got); err != nil || got != "user-2" {
+		t.Errorf("Decode() with primary key = %q, %v", got, err)
+	}
+}
+
+func TestSecureCookieCodec_Expiry(t *testing.T) {
+	c, _ := SecureCookies(testCookieSecret)
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+
+	w := httptest.NewRecorder()
+	c.Set(w, "remember", "user-1", &CookieOptions{MaxAge: time.Hour})
+	if cookie := w.Result().Cookies()[0]; cookie.MaxAge != 3600 {
+		t.Errorf("MaxAge = %d, want 3600", cookie.MaxAge)
+	}
+
+	var got string
+	if err := c.Get(cookieRequest(w), "remember", &got); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	// The browser may ignore Max-Age; the server-side expiry still applies
+	now = now.Add(2 * time.Hour)
+	if err := c.Get(cookieRequest(w), "remember", &got); !errors.Is(err, ErrCookieExpired) {
+		t.Errorf("expired cookie error = %v", err)
+	}
+}
+
+func TestSecureCookieCodec_OptionsAndDelete(t *testing.T) {
+	c, _ := SecureCookies(testCookieSecret)
+
+	w := httptest.NewRecorder()
+	opts := &CookieOptions{Path: "/admin", Domain: "example.com", SameSite: http.SameSiteStrictMode, DisableSecure: true, DisableHttpOnly: true}
+	c.Set(w, "prefs", map[string]string{"theme": "dark"}, opts)
+	cookie := w.Result().Cookies()[0]
+	if cookie.Path != "/admin" || cookie.Domain != "example.com" || cookie.SameSite != http.SameSiteStrictMode || cookie.Secure || cookie.HttpOnly {
+		t.Errorf("cookie attributes = %+v", cookie)
+	}
+
+	w = httptest.NewRecorder()
+	c.Delete(w, "prefs", opts)
+	cookie = w.Result().Cookies()[0]
+	if cookie.MaxAge != -1 || cookie.Value != "" || cookie.Path != "/admin" {
+		t.Errorf("deleted cookie = %+v", cookie)
+	}
+}
+
+func TestSecureCookieCodec_TooLarge(t *testing.T) {
+	c, _ := SecureCookies(testCookieSecret)
+
+	w := httptest.NewRecorder()
+	err := c.Set(w, "big", strings.Repeat("x", 4000), nil)
+	if !errors.Is(err, ErrCookieTooLarge) {
+		t.Errorf("error = %v, want ErrCookieTooLarge", err)
+	}
+	if len(w.Result().Cookies()) != 0 {
+		t.Error("oversized cookie must not be written")
+	}
+}
@@ -0,0 +1,99 @@
+package dim
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// SeedCommand menjalankan seeder yang terdaftar untuk environment aplikasi.
+type SeedCommand struct {
+	env   string
+	only  string
+	force bool
+}
+
+func (c *SeedCommand) Name() string {
+	return "db:seed"
+}
+
+func (c *SeedCommand) Description() string {
+	return "Seed the database with registered seeders"
+}
+
+func (c *SeedCommand) DefineFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.env, "env", "", "Environment whose seed set to run (default: APP_ENV from config)")
+	fs.StringVar(&c.only, "only", "", "Comma-separated seeder names to run")
+	fs.BoolVar(&c.force, "force", false, "Skip confirmation prompt in production")
+}
+
+func (c *SeedCommand) Execute(ctx *CommandContext) error {
+	if ctx.DB == nil {
+		return fmt.Errorf("database connection required")
+	}
+
+	env := c.env
+	if env == "" && ctx.Config != nil {
+		env = ctx.Config.Server.Env
+	}
+	if env == "" {
+		env = "development"
+	}
+
+	seeders := GetSeeders(env)
+	if c.only != "" {
+		var err error
+		if seeders, err = selectSeeders(seeders, c.only); err != nil {
+			return err
+		}
+	}
+
+	if len(seeders) == 0 {
+		fmt.Printf("No seeders registered for environment %q\n", env)
+		return nil
+	}
+
+	fmt.Printf("Seeding %d seeder(s) for environment %q:\n", len(seeders), env)
+	for _, seeder := range seeders {
+		fmt.Printf("  - %s\n", seeder.Name())
+	}
+	fmt.Println()
+
+	if env == "production" && !c.force {
+		if !confirm("You are seeding a production database. Are you sure you want to proceed?") {
+			fmt.Println("Seeding cancelled")
+			return nil
+		}
+		fmt.Println()
+	}
+
+	if err := RunSeeders(context.Background(), ctx.DB, seeders); err != nil {
+		return fmt.Errorf("seeding failed: %w", err)
+	}
+
+	fmt.Printf("✓ Successfully ran %d seeder(s)\n", len(seeders))
+	return nil
+}
+
+// selectSeeders keeps the seeders named in the comma-separated list, in execution order.
+func selectSeeders(seeders []Seeder, only string) ([]Seeder, error) {
+	wanted := make(map[string]bool)
+	for _, name := range strings.Split(only, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			wanted[name] = true
+		}
+	}
+
+	var selected []Seeder
+	for _, seeder := range seeders {
+		if wanted[seeder.Name()] {
+			selected = append(selected, seeder)
+			delete(wanted, seeder.Name())
+		}
+	}
+	for name := range wanted {
+		return nil, fmt.Errorf("seeder %q is not registered for this environment", name)
+	}
+	return selected, nil
+}
@@ -0,0 +1,290 @@
+package dim
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Seeder mengisi data awal (role, master data, data demo) ke database.
+// Daftarkan dengan RegisterSeeders lalu jalankan via command db:seed atau RunSeeders.
+// Seeder sebaiknya idempotent (lihat FirstOrCreate dan UpdateOrCreate) agar aman dijalankan ulang.
+type Seeder interface {
+	// Name adalah nama unik seeder, dipakai oleh flag -only pada db:seed.
+	Name() string
+	// Run mengisi data. Semua query dengan ctx otomatis berjalan di transaksi seeder.
+	Run(ctx context.Context, db Database) error
+}
+
+// seederFunc mengadaptasi fungsi menjadi Seeder.
+type seederFunc struct {
+	name string
+	fn   func(ctx context.Context, db Database) error
+}
+
+func (s seederFunc) Name() string                               { return s.name }
+func (s seederFunc) Run(ctx context.Context, db Database) error { return s.fn(ctx, db) }
+
+// NewSeeder membuat Seeder dari nama dan fungsi.
+//
+// Parameters:
+//   - name: nama unik seeder
+//   - fn: fungsi yang mengisi data
+//
+// Returns:
+//   - Seeder: seeder siap didaftarkan
+//
+// Example:
+//
+//	dim.RegisterSeeders("", dim.NewSeeder("roles", func(ctx context.Context, db dim.Database) error {
+//	    _, err := dim.FirstOrCreate(ctx, db, "roles", map[string]interface{}{"name": "admin"}, nil)
+//	    return err
+//	}))
+func NewSeeder(name string, fn func(ctx context.Context, db Database) error) Seeder {
+	return seederFunc{name: name, fn: fn}
+}
+
+// seedSet adalah kumpulan seeder untuk satu environment ("" berarti semua environment).
+type seedSet struct {
+	env     string
+	seeders []Seeder
+}
+
+var seederRegistry []seedSet
+
+// RegisterSeeders mendaftarkan seed set untuk environment tertentu (nilai APP_ENV, misal
+// "development" atau "staging"). Environment kosong berarti seeder dijalankan di semua environment.
+// Seeder dijalankan sesuai urutan pendaftaran: seeder semua environment lebih dulu, lalu seeder
+// environment yang dipilih. Biasanya dipanggil di fungsi init(), seperti Register untuk migration.
+//
+// Parameters:
+//   - env: environment tujuan, atau "" untuk semua environment
+//   - seeders: seeder yang dijalankan berurutan
+//
+// Example:
+//
+//	func init() {
+//	    dim.RegisterSeeders("", &RolesSeeder{}, &CountriesSeeder{})
+//	    dim.RegisterSeeders("development", &DemoUsersSeeder{})
+//	    dim.RegisterSeeders("staging", &DemoUsersSeeder{}, &LoadTestSeeder{})
+//	}
+func RegisterSeeders(env string, seeders ...Seeder) {
+	seederRegistry = append(seederRegistry, seedSet{env: env, seeders: seeders})
+}
+
+// GetSeeders mengembalikan seeder untuk environment env sesuai urutan eksekusi.
+// Seeder dengan nama yang sama hanya muncul sekali (pendaftaran pertama).
+//
+// Parameters:
+//   - env: environment aplikasi
+//
+// Returns:
+//   - []Seeder: seeder yang dijalankan untuk env
+func GetSeeders(env string) []Seeder {
+	var seeders []Seeder
+	seen := make(map[string]bool)
+	add := func(set seedSet) {
+		for _, s := range set.seeders {
+			if !seen[s.Name()] {
+				seen[s.Name()] = true
+				seeders = append(seeders, s)
+			}
+		}
+	}
+
+	for _, set := range seederRegistry {
+		if set.env == "" {
+			add(set)
+		}
+	}
+	for _, set := range seederRegistry {
+		if set.env != "" && strings.EqualFold(set.env, env) {
+			add(set)
+		}
+	}
+	return seeders
+}
+
+// RunSeeders menjalankan seeders berurutan. Setiap seeder berjalan di transaksinya sendiri
+// (lihat InTx): seeder yang gagal di-rollback dan seeder setelahnya tidak dijalankan.
+//
+// Parameters:
+//   - ctx: context induk
+//   - db: Database instance
+//   - seeders: seeder yang dijalankan, biasanya dari GetSeeders
+//
+// Returns:
+//   - error: error pertama dari seeder, dibungkus dengan nama seeder
+//
+// Example:
+//
+//	err := dim.RunSeeders(ctx, db, dim.GetSeeders(config.Server.Env))
+func RunSeeders(ctx context.Context, db Database, seeders []Seeder) error {
+	for _, seeder := range seeders {
+		slog.Info("running seeder", "name", seeder.Name())
+		start := time.Now()
+
+		err := InTx(ctx, db, func(ctx context.Context) error {
+			return seeder.Run(ctx, db)
+		})
+		if err != nil {
+			return fmt.Errorf("seeder %s failed: %w", seeder.Name(), err)
+		}
+
+		slog.Info("seeder completed", "name", seeder.Name(), "duration", time.Since(start))
+	}
+	return nil
+}
+
+// FirstOrCreate menyisipkan baris ke table hanya jika belum ada baris yang cocok dengan match.
+// Kolom baris baru berasal dari match ditambah values. Nilai nil di match dicocokkan dengan IS NULL.
+//
+// Parameters:
+//   - ctx: context (boleh berisi transaksi dari InTx)
+//   - db: Database instance
+//   - table: nama tabel (opsional dengan schema)
+//   - match: kolom dan nilai yang mengidentifikasi baris
+//   - values: kolom tambahan untuk baris baru (boleh nil)
+//
+// Returns:
+//   - bool: true jika baris baru dibuat
+//   - error: error validasi nama tabel/kolom atau error query
+//
+// Example:
+//
+//	created, err := dim.FirstOrCreate(ctx, db, "countries",
+//	    map[string]interface{}{"code": "ID"},
+//	    map[string]interface{}{"name": "Indonesia"})
+func FirstOrCreate(ctx context.Context, db Database, table string, match, values map[string]interface{}) (bool, error) {
+	exists, err := seedRowExists(ctx, db, table, match)
+	if err != nil || exists {
+		return false, err
+	}
+	return true, seedInsert(ctx, db, table, match, values)
+}
+
+// UpdateOrCreate seperti FirstOrCreate, tetapi baris yang sudah ada diperbarui dengan values.
+//
+// Parameters:
+//   - ctx: context (boleh berisi transaksi dari InTx)
+//   - db: Database instance
+//   - table: nama tabel (opsional dengan schema)
+//   - match: kolom dan nilai yang mengidentifikasi baris
+//   - values: kolom yang diisi pada baris baru atau diperbarui pada baris yang ada
+//
+// Returns:
+//   - bool: true jika baris baru dibuat
+//   - error: error validasi nama tabel/kolom atau error query
+func UpdateOrCreate(ctx context.Context, db Database, table string, match, values map[string]interface{}) (bool, error) {
+	exists, err := seedRowExists(ctx, db, table, match)
+	if err != nil {
+		return false, err
+	}
+	if !exists {
+		return true, seedInsert(ctx, db, table, match, values)
+	}
+	if len(values) == 0 {
+		return false, nil
+	}
+
+	setColumns, err := seedColumns(values)
+	if err != nil {
+		return false, err
+	}
+	sets := make([]string, len(setColumns))
+	args := make([]interface{}, 0, len(values)+len(match))
+	for i, column := range setColumns {
+		args = append(args, values[column])
+		sets[i] = fmt.Sprintf("%s = $%d", column, len(args))
+	}
+	where, args, err := seedWhere(match, args)
+	if err != nil {
+		return false, err
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE %s", table, strings.Join(sets, ", "), where)
+	return false, db.Exec(ctx, db.Rebind(query), args...)
+}
+
+// seedRowExists reports whether table has a row matching every column in match.
+func seedRowExists(ctx context.Context, db Database, table string, match map[string]interface{}) (bool, error) {
+	if !slugSQLIdentifier.MatchString(table) {
+		return false, fmt.Errorf("invalid seed table name: %q", table)
+	}
+	if len(match) == 0 {
+		return false, fmt.Errorf("seed match columns are required")
+	}
+	where, args, err := seedWhere(match, nil)
+	if err != nil {
+		return false, err
+	}
+
+	var one int
+	err = db.QueryRow(ctx, db.Rebind(fmt.Sprintf("SELECT 1 FROM %s WHERE %s LIMIT 1", table, where)), args...).Scan(&one)
+	if isNoRows(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// seedInsert inserts match and values as one row.
+func seedInsert(ctx context.Context, db Database, table string, match, values map[string]interface{}) error {
+	row := make(map[string]interface{}, len(match)+len(values))
+	for column, value := range match {
+		row[column] = value
+	}
+	for column, value := range values {
+		row[column] = value
+	}
+
+	columns, err := seedColumns(row)
+	if err != nil {
+		return err
+	}
+	placeholders := make([]string, len(columns))
+	args := make([]interface{}, len(columns))
+	for i, column := range columns {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+		args[i] = row[column]
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", table, strings.Join(columns, ", "), strings.Join(placeholders, ", "))
+	return db.Exec(ctx, db.Rebind(query), args...)
+}
+
+// seedWhere builds "a = $n AND b IS NULL" for match, appending arguments to args.
+func seedWhere(match map[string]interface{}, args []interface{}) (string, []interface{}, error) {
+	columns, err := seedColumns(match)
+	if err != nil {
+		return "", nil, err
+	}
+	conditions := make([]string, len(columns))
+	for i, column := range columns {
+		if match[column] == nil {
+			conditions[i] = column + " IS NULL"
+			continue
+		}
+		args = append(args, match[column])
+		conditions[i] = fmt.Sprintf("%s = $%d", column, len(args))
+	}
+	return strings.Join(conditions, " AND "), args, nil
+}
+
+// seedColumns returns the map keys sorted, so generated SQL is deterministic, after validating them.
+func seedColumns(row map[string]interface{}) ([]string, error) {
+	columns := make([]string, 0, len(row))
+	for column := range row {
+		if !slugSQLIdentifier.MatchString(column) || strings.Contains(column, ".") {
+			return nil, fmt.Errorf("invalid seed column name: %q", column)
+		}
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+	return columns, nil
+}
@@ -0,0 +1,201 @@
+package dim
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func withTestSeeders(t *testing.T) {
+	t.Helper()
+	old := seederRegistry
+	seederRegistry = nil
+	t.Cleanup(func() { seederRegistry = old })
+}
+
+func newSeederTestDB(t *testing.T) *SQLiteDatabase {
+	t.Helper()
+	db := newTestSQLiteDB(t)
+	if err := db.Exec(context.Background(), "CREATE TABLE countries (code TEXT PRIMARY KEY, name TEXT, region TEXT)"); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func countryNames(t *testing.T, db Database) map[string]string {
+	t.Helper()
+	rows, err := db.Query(context.Background(), "SELECT code, name FROM countries")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	names := map[string]string{}
+	for rows.Next() {
+		var code, name string
+		if err := rows.Scan(&code, &name); err != nil {
+			t.Fatal(err)
+		}
+		names[code] = name
+	}
+	return names
+}
+
+func TestGetSeeders_EnvironmentSets(t *testing.T) {
+	withTestSeeders(t)
+	noop := func(name string) Seeder {
+		return NewSeeder(name, func(context.Context, Database) error { return nil })
+	}
+	RegisterSeeders("", noop("roles"), noop("countries"))
+	RegisterSeeders("development", noop("demo_users"), noop("roles"))
+	RegisterSeeders("staging", noop("load_test"))
+
+	names := func(seeders []Seeder) string {
+		var out []string
+		for _, s := range seeders {
+			out = append(out, s.Name())
+		}
+		return strings.Join(out, ",")
+	}
+	if got := names(GetSeeders("development")); got != "roles,countries,demo_users" {
+		t.Errorf("development seeders = %s", got)
+	}
+	if got := names(GetSeeders("Staging")); got != "roles,countries,load_test" {
+		t.Errorf("staging seeders = %s", got)
+	}
+	if got := names(GetSeeders("production")); got != "roles,countries" {
+		t.Errorf("production seeders = %s", got)
+	}
+}
+
+func TestRunSeeders_RollsBackFailedSeeder(t *testing.T) {
+	db := newSeederTestDB(t)
+	seeders := []Seeder{
+		NewSeeder("indonesia", func(ctx context.Context, db Database) error {
+			_, err := FirstOrCreate(ctx, db, "countries", map[string]interface{}{"code": "ID"}, map[string]interface{}{"name": "Indonesia"})
+			return err
+		}),
+		NewSeeder("broken", func(ctx context.Context, db Database) error {
+			if err := db.Exec(ctx, "INSERT INTO countries (code, name) VALUES ('MY', 'Malaysia')"); err != nil {
+				return err
+			}
+			return errors.New("boom")
+		}),
+		NewSeeder("never", func(ctx context.Context, db Database) error {
+			t.Error("seeder after failure must not run")
+			return nil
+		}),
+	}
+
+	err := RunSeeders(context.Background(), db, seeders)
+	if err == nil || !strings.Contains(err.Error(), "seeder broken failed: boom") {
+		t.Fatalf("error = %v", err)
+	}
+	if names := countryNames(t, db); len(names) != 1 || names["ID"] != "Indonesia" {
+		t.Errorf("countries = %v", names)
+	}
+}
+
+func TestFirstOrCreate(t *testing.T) {
+	db := newSeederTestDB(t)
+	ctx := context.Background()
+	match := map[string]interface{}{"code": "ID"}
+
+	created, err := FirstOrCreate(ctx, db, "countries", match, map[string]interface{}{"name": "Indonesia"})
+	if err != nil || !created {
+		t.Fatalf("first call = %v, %v", created, err)
+	}
+	created, err = FirstOrCreate(ctx, db, "countries", match, map[string]interface{}{"name": "Changed"})
+	if err != nil || created {
+		t.Fatalf("second call = %v, %v", created, err)
+	}
+	if names := countryNames(t, db); names["ID"] != "Indonesia" {
+		t.Errorf("existing row modified: %v", names)
+	}
+
+	// nil dicocokkan dengan IS NULL
+	if _, err := FirstOrCreate(ctx, db, "countries", map[string]interface{}{"code": "SG", "region": nil}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if created, err := FirstOrCreate(ctx, db, "countries", map[string]interface{}{"code": "SG", "region": nil}, nil); err != nil || created {
+		t.Errorf("IS NULL match = %v, %v", created, err)
+	}
+}
+
+func TestUpdateOrCreate(t *testing.T) {
+	db := newSeederTestDB(t)
+	ctx := context.Background()
+	match := map[string]interface{}{"code": "ID"}
+
+	if created, err := UpdateOrCreate(ctx, db, "countries", match, map[string]interface{}{"name": "Indonesia"}); err != nil || !created {
+		t.Fatalf("create = %v, %v", created, err)
+	}
+	if created, err := UpdateOrCreate(ctx, db, "countries", match, map[string]interface{}{"name": "Republik Indonesia"}); err != nil || created {
+		t.Fatalf("update = %v, %v", created, err)
+	}
+	if names := countryNames(t, db); len(names) != 1 || names["ID"] != "Republik Indonesia" {
+		t.Errorf("countries = %v", names)
+	}
+}
+
+func TestFirstOrCreate_InvalidIdentifiers(t *testing.T) {
+	db := newSeederTestDB(t)
+	ctx := context.Background()
+	tests := []struct {
+		table string
+		match map[string]interface{}
+		want  string
+	}{
+		{"countries; DROP TABLE x", map[string]interface{}{"code": "ID"}, "invalid seed table name"},
+		{"countries", map[string]interface{}{"code = 1 OR 1": "ID"}, "invalid seed column name"},
+		{"countries", nil, "match columns are required"},
+	}
+	for _, tt := range tests {
+		if _, err := FirstOrCreate(ctx, db, tt.table, tt.match, nil); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("FirstOrCreate(%q, %v) error = %v, want %q", tt.table, tt.match, err, tt.want)
+		}
+	}
+}
+
+func TestSeedCommand(t *testing.T) {
+	withTestSeeders(t)
+	seed := func(code, name string) Seeder {
+		return NewSeeder(strings.ToLower(code), func(ctx context.Context, db Database) error {
+			_, err := FirstOrCreate(ctx, db, "countries", map[string]interface{}{"code": code}, map[string]interface{}{"name": name})
+			return err
+		})
+	}
+	RegisterSeeders("", seed("ID", "Indonesia"))
+	RegisterSeeders("staging", seed("MY", "Malaysia"), seed("SG", "Singapore"))
+
+	cmd := &SeedCommand{}
+	if cmd.Name() != "db:seed" || cmd.Description() == "" {
+		t.Errorf("name = %q, description = %q", cmd.Name(), cmd.Description())
+	}
+	if err := cmd.Execute(&CommandContext{}); err == nil || err.Error() != "database connection required" {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	db := newSeederTestDB(t)
+	config := &Config{Server: ServerConfig{Env: "staging"}}
+	only := &SeedCommand{only: "id, sg"}
+	if err := only.Execute(&CommandContext{DB: db, Config: config}); err != nil {
+		t.Fatal(err)
+	}
+	if names := countryNames(t, db); len(names) != 2 || names["MY"] != "" {
+		t.Errorf("countries after -only = %v", names)
+	}
+
+	all := &SeedCommand{env: "staging"}
+	if err := all.Execute(&CommandContext{DB: db}); err != nil {
+		t.Fatal(err)
+	}
+	if names := countryNames(t, db); len(names) != 3 {
+		t.Errorf("countries after rerun = %v", names)
+	}
+
+	unknown := &SeedCommand{env: "development", only: "my"}
+	if err := unknown.Execute(&CommandContext{DB: db}); err == nil || !strings.Contains(err.Error(), `seeder "my" is not registered`) {
+		t.Errorf("unknown seeder error = %v", err)
+	}
+}
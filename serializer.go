@@ -0,0 +1,266 @@
+package dim
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Serializer meng-encode dan men-decode body untuk satu media type, misal msgpack atau protobuf.
+// Daftarkan dengan RegisterSerializer agar dipakai oleh Bind dan response helpers.
+type Serializer interface {
+	// Encode menulis v ke w.
+	Encode(w io.Writer, v interface{}) error
+	// Decode membaca body dari r ke v (pointer).
+	Decode(r io.Reader, v interface{}) error
+}
+
+// serializerFuncs mengadaptasi sepasang fungsi menjadi Serializer.
+type serializerFuncs struct {
+	encode func(w io.Writer, v interface{}) error
+	decode func(r io.Reader, v interface{}) error
+}
+
+func (s serializerFuncs) Encode(w io.Writer, v interface{}) error { return s.encode(w, v) }
+func (s serializerFuncs) Decode(r io.Reader, v interface{}) error { return s.decode(r, v) }
+
+// NewSerializer membuat Serializer dari fungsi encode dan decode.
+//
+// Parameters:
+//   - encode: menulis nilai ke writer
+//   - decode: membaca body ke pointer tujuan
+//
+// Returns:
+//   - Serializer: serializer siap didaftarkan
+//
+// Example:
+//
+//	dim.RegisterSerializer("application/msgpack", dim.NewSerializer(
+//	    func(w io.Writer, v interface{}) error { return msgpack.NewEncoder(w).Encode(v) },
+//	    func(r io.Reader, v interface{}) error { return msgpack.NewDecoder(r).Decode(v) },
+//	))
+func NewSerializer(encode func(w io.Writer, v interface{}) error, decode func(r io.Reader, v interface{}) error) Serializer {
+	return serializerFuncs{encode: encode, decode: decode}
+}
+
+var (
+	serializersMu sync.RWMutex
+	serializers   = map[string]Serializer{}
+)
+
+// RegisterSerializer mendaftarkan serializer untuk media type (tanpa parameter, misal
+// "application/vnd.company+json" atau "application/x-protobuf"). Bind memakainya untuk request
+// dengan Content-Type tersebut, dan response helpers memakainya jika ContentNegotiationMiddleware
+// memilih media type tersebut dari header Accept. Media type dengan suffix yang belum terdaftar
+// (misal "application/vnd.company+msgpack") memakai serializer suffix-nya ("application/msgpack").
+// Mendaftarkan "application/json" mengganti encoding/json bawaan (ResponseConfig tidak lagi berlaku).
+// Sebaiknya dipanggil saat startup sebelum server menerima request.
+//
+// Parameters:
+//   - mediaType: media type yang ditangani
+//   - s: serializer; nil menghapus pendaftaran
+func RegisterSerializer(mediaType string, s Serializer) {
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+	serializersMu.Lock()
+	defer serializersMu.Unlock()
+	if s == nil {
+		delete(serializers, mediaType)
+		return
+	}
+	serializers[mediaType] = s
+}
+
+// LookupSerializer mencari serializer terdaftar untuk media type, termasuk fallback suffix
+// ("application/vnd.company+msgpack" ke "application/msgpack").
+//
+// Returns:
+//   - Serializer: serializer terdaftar
+//   - bool: false jika tidak ada serializer untuk media type tersebut
+func LookupSerializer(mediaType string) (Serializer, bool) {
+	mediaType = strings.ToLower(mediaType)
+	serializersMu.RLock()
+	defer serializersMu.RUnlock()
+	if s, ok := serializers[mediaType]; ok {
+		return s, true
+	}
+	if i := strings.LastIndex(mediaType, "+"); i >= 0 {
+		s, ok := serializers["application/"+mediaType[i+1:]]
+		return s, ok
+	}
+	return nil, false
+}
+
+// isJSONMediaType melaporkan media type yang ditangani encoding/json bawaan.
+func isJSONMediaType(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// NegotiateMediaType memilih media type response terbaik dari header Accept di antara JSON dan
+// media type yang terdaftar via RegisterSerializer, mengikuti nilai q. Header kosong atau */*
+// memilih "application/json".
+//
+// Parameters:
+//   - accept: nilai header Accept
+//
+// Returns:
+//   - string: media type terpilih
+//   - bool: false jika tidak ada media type yang dapat dilayani
+func NegotiateMediaType(accept string) (string, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return "application/json", true
+	}
+
+	type candidate struct {
+		mediaType string
+		q         float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+				q = parsed
+			}
+		}
+		if q > 0 {
+			candidates = append(candidates, candidate{mediaType, q})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+
+	for _, c := range candidates {
+		switch {
+		case c.mediaType == "*/*" || c.mediaType == "application/*":
+			return "application/json", true
+		case isJSONMediaType(c.mediaType):
+			return c.mediaType, true
+		case strings.HasSuffix(c.mediaType, "/*"):
+			if mediaType, ok := registeredMediaTypeWithPrefix(strings.TrimSuffix(c.mediaType, "*")); ok {
+				return mediaType, true
+			}
+		default:
+			if _, ok := LookupSerializer(c.mediaType); ok {
+				return c.mediaType, true
+			}
+		}
+	}
+	return "", false
+}
+
+// registeredMediaTypeWithPrefix returns the first (sorted) registered media type starting with prefix.
+func registeredMediaTypeWithPrefix(prefix string) (string, bool) {
+	serializersMu.RLock()
+	defer serializersMu.RUnlock()
+	var matches []string
+	for mediaType := range serializers {
+		if strings.HasPrefix(mediaType, prefix) {
+			matches = append(matches, mediaType)
+		}
+	}
+	if len(matches) == 0 {
+		return "", false
+	}
+	sort.Strings(matches)
+	return matches[0], true
+}
+
+// ContentNegotiationMiddleware memilih format response dari header Accept (lihat NegotiateMediaType).
+// Json, JsonPagination, dan JsonError kemudian meng-encode response dengan serializer media type
+// terpilih dan mengisi Content-Type sesuai. Request yang hanya menerima media type yang tidak
+// didukung ditolak dengan 406. Response selalu berisi Vary: Accept.
+//
+// Returns:
+//   - MiddlewareFunc: middleware negosiasi content type
+//
+// Example:
+//
+//	dim.RegisterSerializer("application/msgpack", msgpackSerializer)
+//	router.Use(dim.ContentNegotiationMiddleware())
+func ContentNegotiationMiddleware() MiddlewareFunc {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept")
+
+			mediaType, ok := NegotiateMediaType(r.Header.Get("Accept"))
+			if !ok {
+				JsonError(w, http.StatusNotAcceptable, "Format response yang diminta tidak didukung", nil)
+				return
+			}
+			next(&negotiatedResponseWriter{ResponseWriter: w, mediaType: mediaType}, r)
+		}
+	}
+}
+
+// negotiatedResponseWriter membawa media type hasil negosiasi agar response helpers dapat
+// memilih serializer.
+type negotiatedResponseWriter struct {
+	http.ResponseWriter
+	mediaType string
+}
+
+// Unwrap mengembalikan ResponseWriter asli untuk http.ResponseController.
+func (w *negotiatedResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Flush meneruskan flush ke ResponseWriter asli jika didukung (streaming/SSE).
+func (w *negotiatedResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// writerMediaType mencari media type yang dipasang ContentNegotiationMiddleware pada ResponseWriter.
+func writerMediaType(w http.ResponseWriter) (string, bool) {
+	for w != nil {
+		if nw, ok := w.(*negotiatedResponseWriter); ok {
+			return nw.mediaType, true
+		}
+		u, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			return "", false
+		}
+		w = u.Unwrap()
+	}
+	return "", false
+}
+
+// responseSerializer menentukan media type dan serializer untuk response. Serializer nil berarti
+// encoding/json bawaan (dengan ResponseConfig).
+func responseSerializer(w http.ResponseWriter) (string, Serializer) {
+	mediaType, ok := writerMediaType(w)
+	if !ok {
+		mediaType = "application/json"
+	}
+	if s, ok := LookupSerializer(mediaType); ok {
+		return mediaType, s
+	}
+	return mediaType, nil
+}
+
+// serialize meng-encode data dengan s ke buffer lebih dulu, sehingga error encode dapat
+// ditangani sebelum status code terkirim.
+func serialize(s Serializer, data interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := s.Encode(&buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeBody menulis body yang sudah di-encode beserta Content-Type dan status code.
+func writeBody(w http.ResponseWriter, status int, mediaType string, body []byte) error {
+	w.Header().Set("Content-Type", mediaType)
+	w.WriteHeader(status)
+	_, err := w.Write(body)
+	return err
+}
@@ -0,0 +1,178 @@
+package dim
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type serializerTestItem struct {
+	Name  string `json:"name" validate:"required"`
+	Count int    `json:"count"`
+}
+
+// gobSerializer adalah serializer non-JSON dari standard library untuk pengujian.
+var gobSerializer = NewSerializer(
+	func(w io.Writer, v interface{}) error { return gob.NewEncoder(w).Encode(v) },
+	func(r io.Reader, v interface{}) error { return gob.NewDecoder(r).Decode(v) },
+)
+
+func withSerializer(t *testing.T, mediaType string, s Serializer) {
+	t.Helper()
+	RegisterSerializer(mediaType, s)
+	t.Cleanup(func() { RegisterSerializer(mediaType, nil) })
+}
+
+func TestLookupSerializer(t *testing.T) {
+	withSerializer(t, "application/x-gob", gobSerializer)
+
+	for _, mediaType := range []string{"application/x-gob", "Application/X-Gob", "application/vnd.company+x-gob"} {
+		if _, ok := LookupSerializer(mediaType); !ok {
+			t.Errorf("LookupSerializer(%q) not found", mediaType)
+		}
+	}
+	if _, ok := LookupSerializer("application/xml"); ok {
+		t.Error("unexpected serializer for application/xml")
+	}
+}
+
+func TestNegotiateMediaType(t *testing.T) {
+	withSerializer(t, "application/x-gob", gobSerializer)
+
+	tests := []struct {
+		accept string
+		want   string
+		wantOK bool
+	}{
+		{"", "application/json", true},
+		{"*/*", "application/json", true},
+		{"application/x-gob", "application/x-gob", true},
+		{"application/json;q=0.5, application/x-gob", "application/x-gob", true},
+		{"application/x-gob;q=0.2, application/json", "application/json", true},
+		{"application/vnd.company+json", "application/vnd.company+json", true},
+		{"text/html, application/x-gob;q=0.1", "application/x-gob", true},
+		{"text/html", "", false},
+		{"application/x-gob;q=0", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := NegotiateMediaType(tt.accept)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("NegotiateMediaType(%q) = %q, %v; want %q, %v", tt.accept, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestBind_RegisteredSerializer(t *testing.T) {
+	withSerializer(t, "application/x-gob", gobSerializer)
+
+	var body bytes.Buffer
+	if err := gob.NewEncoder(&body).Encode(serializerTestItem{Name: "widget", Count: 3}); err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/items", &body)
+	req.Header.Set("Content-Type", "application/x-gob")
+
+	var item serializerTestItem
+	if err := Bind(req, &item); err != nil {
+		t.Fatalf("Bind: %v", err)
+	}
+	if item.Name != "widget" || item.Count != 3 {
+		t.Errorf("item = %+v", item)
+	}
+
+	// Body rusak menghasilkan 400, validasi tetap berjalan setelah decode
+	req = httptest.NewRequest(http.MethodPost, "/items", strings.NewReader("not gob"))
+	req.Header.Set("Content-Type", "application/x-gob")
+	err := Bind(req, &serializerTestItem{})
+	if appErr, ok := AsAppError(err); !ok || appErr.StatusCode != http.StatusBadRequest || appErr.Message != "Format body tidak valid" {
+		t.Errorf("invalid body error = %v", err)
+	}
+
+	body.Reset()
+	gob.NewEncoder(&body).Encode(serializerTestItem{Count: 1})
+	req = httptest.NewRequest(http.MethodPost, "/items", &body)
+	req.Header.Set("Content-Type", "application/x-gob")
+	err = Bind(req, &serializerTestItem{})
+	if appErr, ok := AsAppError(err); !ok || appErr.Errors["name"] == nil {
+		t.Errorf("validation error = %v", err)
+	}
+}
+
+func TestContentNegotiationMiddleware(t *testing.T) {
+	withSerializer(t, "application/x-gob", gobSerializer)
+	handler := ContentNegotiationMiddleware()(func(w http.ResponseWriter, r *http.Request) {
+		Json(w, http.StatusOK, serializerTestItem{Name: "widget", Count: 3})
+	})
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/items/1", nil)
+	req.Header.Set("Accept", "application/x-gob")
+	handler(rec, req)
+
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/x-gob" || rec.Header().Get("Vary") != "Accept" {
+		t.Fatalf("status = %d, headers = %v", rec.Code, rec.Header())
+	}
+	var item serializerTestItem
+	if err := gob.NewDecoder(rec.Body).Decode(&item); err != nil || item.Name != "widget" {
+		t.Errorf("decoded = %+v, %v", item, err)
+	}
+
+	rec = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/items/1", nil)
+	handler(rec, req)
+	if rec.Header().Get("Content-Type") != "application/json" || !strings.Contains(rec.Body.String(), `"name":"widget"`) {
+		t.Errorf("default response = %v %s", rec.Header(), rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/items/1", nil)
+	req.Header.Set("Accept", "text/html")
+	handler(rec, req)
+	if rec.Code != http.StatusNotAcceptable {
+		t.Errorf("unsupported Accept status = %d", rec.Code)
+	}
+}
+
+func TestJsonError_SerializerFallsBackToJSON(t *testing.T) {
+	// Serializer yang hanya mengenal tipe tertentu, seperti protobuf
+	withSerializer(t, "application/x-item", NewSerializer(
+		func(w io.Writer, v interface{}) error {
+			item, ok := v.(serializerTestItem)
+			if !ok {
+				return errors.New("unsupported type")
+			}
+			_, err := io.WriteString(w, item.Name)
+			return err
+		},
+		func(r io.Reader, v interface{}) error { return errors.New("not implemented") },
+	))
+	handler := ContentNegotiationMiddleware()(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("fail") != "" {
+			NotFound(w, "Item tidak ditemukan")
+			return
+		}
+		Json(w, http.StatusOK, map[string]string{"name": "widget"})
+	})
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/items/1?fail=1", nil)
+	req.Header.Set("Accept", "application/x-item")
+	handler(rec, req)
+	if rec.Code != http.StatusNotFound || rec.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("error response = %d %v", rec.Code, rec.Header())
+	}
+
+	// Error encode data menghasilkan 500 sebelum status code terkirim
+	rec = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/items/1", nil)
+	req.Header.Set("Accept", "application/x-item")
+	handler(rec, req)
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("encode failure status = %d", rec.Code)
+	}
+}
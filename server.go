@@ -2,6 +2,7 @@ package dim
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"log/slog"
@@ -10,40 +11,59 @@ import (
 	"os"
 	"os/signal"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 )
 
-// StartServer starts the HTTP server with graceful shutdown support.
-// It listens on the specified port and serves requests using the provided handler.
-// When a SIGINT or SIGTERM signal is received (or context cancelled), it will attempt to shut down
-// the server gracefully.
+// ShutdownFunc adalah hook yang dijalankan Server setelah request yang sedang berjalan selesai,
+// misal untuk menutup koneksi database atau menghentikan worker queue.
+type ShutdownFunc func(ctx context.Context) error
+
+type shutdownHook struct {
+	name string
+	fn   ShutdownFunc
+}
+
+// Server adalah HTTP server dengan timeout dari ServerConfig, graceful shutdown saat SIGINT/SIGTERM
+// atau context dibatalkan, dan hook OnShutdown untuk membersihkan resource.
+type Server struct {
+	config  ServerConfig
+	addr    string
+	http    *http.Server
+	signals []os.Signal
+
+	tlsConfig *tls.Config
+	redirect  *http.Server
+	listeners []ServerListener
+	bound     []*boundListener
+	internal  http.Handler
+
+	mu       sync.Mutex
+	hooks    []shutdownHook
+	listener net.Listener
+	ready    chan struct{}
+}
+
+// NewServer membuat Server dari ServerConfig. Timeout yang bernilai 0 diisi default yang aman
+// (Read/Write 10s, Idle 2m, Shutdown 10s) untuk mencegah serangan Slowloris.
 //
 // Parameters:
-//   - ctx: context to control the server (e.g., from main)
-//   - config: ServerConfig containing port and timeouts.
-//   - handler: http.Handler to serve (usually the Router).
+//   - config: ServerConfig berisi port dan timeout, biasanya cfg.Server dari LoadConfig
+//   - handler: http.Handler yang dilayani (biasanya Router)
 //
 // Returns:
-//   - error: error if server fails to start or shutdown error.
+//   - *Server: server yang siap dijalankan dengan Run
 //
 // Example:
 //
-//	ctx := context.Background()
-//	config := dim.ServerConfig{Port: "8080"}
-//	router := dim.NewRouter()
-//	// ... register routes ...
-//	if err := dim.StartServer(ctx, config, router); err != nil {
+//	server := dim.NewServer(cfg.Server, router).
+//	    OnShutdown("database", func(ctx context.Context) error { return db.Close() })
+//	if err := server.Run(context.Background()); err != nil {
 //	    log.Fatal(err)
 //	}
-func StartServer(ctx context.Context, config ServerConfig, handler http.Handler) error {
-	addr := config.Port
-	// Automatic port formatting if needed
-	if addr == "" {
-		addr = ":8080" // Default port
-	} else if !strings.Contains(addr, ":") {
-		addr = ":" + addr
-	}
+func NewServer(config ServerConfig, handler http.Handler) *Server {
+	addr := serverAddr(config.Port)
 
 	// Safety: Apply default timeouts if not set to prevent Slowloris attacks
 	// Default: 10s for Read/Write, 2m for Idle, 10s for Shutdown
@@ -60,58 +80,266 @@ func StartServer(ctx context.Context, config ServerConfig, handler http.Handler)
 		config.ShutdownTimeout = 10 * time.Second
 	}
 
-	srv := &http.Server{
-		Addr:         addr,
-		Handler:      handler,
-		ReadTimeout:  config.ReadTimeout,
-		WriteTimeout: config.WriteTimeout,
-		IdleTimeout:  config.IdleTimeout,
+	return &Server{
+		config: config,
+		addr:   addr,
+		http: &http.Server{
+			Addr:         addr,
+			Handler:      handler,
+			ReadTimeout:  config.ReadTimeout,
+			WriteTimeout: config.WriteTimeout,
+			IdleTimeout:  config.IdleTimeout,
+		},
+		signals: []os.Signal{os.Interrupt, syscall.SIGTERM},
+		ready:   make(chan struct{}),
+	}
+}
+
+// serverAddr mengubah port dari konfigurasi menjadi alamat listen: "8080" menjadi ":8080",
+// alamat lengkap seperti "127.0.0.1:9090" dipakai apa adanya, dan kosong menjadi ":8080".
+func serverAddr(port string) string {
+	if port == "" {
+		return ":8080" // Default port
+	}
+	if !strings.Contains(port, ":") {
+		return ":" + port
+	}
+	return port
+}
+
+// OnShutdown mendaftarkan hook yang dijalankan setelah server berhenti menerima request dan
+// request yang sedang berjalan selesai (atau ShutdownTimeout habis). Hook dijalankan berurutan
+// dari yang terakhir didaftarkan (seperti defer), sehingga resource yang dibuka belakangan ditutup
+// lebih dulu. Semua hook tetap dijalankan walaupun ada yang gagal atau panic.
+//
+// Parameters:
+//   - name: nama hook untuk log, misal "database"
+//   - fn: hook yang menerima context dengan sisa ShutdownTimeout
+//
+// Returns:
+//   - *Server: server yang sama untuk chaining
+func (s *Server) OnShutdown(name string, fn ShutdownFunc) *Server {
+	if fn == nil {
+		panic("dim: OnShutdown requires a non-nil hook")
 	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.hooks = append(s.hooks, shutdownHook{name: name, fn: fn})
+	return s
+}
+
+// WithSignals mengganti signal yang memicu graceful shutdown (default SIGINT dan SIGTERM).
+// Tanpa argumen, server hanya berhenti saat context dibatalkan.
+func (s *Server) WithSignals(sigs ...os.Signal) *Server {
+	s.signals = sigs
+	return s
+}
 
+// HTTPServer mengembalikan *http.Server yang digunakan, untuk pengaturan lanjutan
+// (misal ReadHeaderTimeout atau ErrorLog) sebelum Run dipanggil.
+func (s *Server) HTTPServer() *http.Server {
+	return s.http
+}
+
+// Addr mengembalikan alamat listener setelah server berjalan (berguna dengan port "0"),
+// atau alamat dari konfigurasi sebelum Run.
+func (s *Server) Addr() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.listener != nil {
+		return s.listener.Addr().String()
+	}
+	return s.addr
+}
+
+// Ready mengembalikan channel yang ditutup setelah port berhasil di-bind.
+func (s *Server) Ready() <-chan struct{} {
+	return s.ready
+}
+
+// Run membuka port dan melayani request sampai menerima signal shutdown, ctx dibatalkan,
+// atau server gagal. Saat shutdown, server berhenti menerima koneksi baru, menunggu request yang
+// sedang berjalan hingga ShutdownTimeout, lalu menjalankan hook OnShutdown. Run hanya dapat
+// dipanggil sekali per Server.
+//
+// Parameters:
+//   - ctx: context untuk mengontrol server (misal dari main)
+//
+// Returns:
+//   - error: error bind port, error server, error shutdown, atau gabungan error hook
+func (s *Server) Run(ctx context.Context) error {
 	// Use net.Listen explicitly to confirm port binding before logging
-	ln, err := net.Listen("tcp", addr)
+	ln, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("failed to bind port %s: %w", s.addr, err)
+	}
+	return s.Serve(ctx, ln)
+}
+
+// Serve seperti Run tetapi memakai listener yang sudah dibuka, misal dari systemd socket
+// activation atau test.
+func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	tlsConfig, redirectHandler, err := s.buildTLS()
 	if err != nil {
-		return fmt.Errorf("failed to bind port %s: %w", addr, err)
+		ln.Close()
+		return err
 	}
 
-	// Channel to listen for errors coming from the listener.
-	serverErrors := make(chan error, 1)
+	bound, err := s.bindListeners()
+	if err != nil {
+		ln.Close()
+		return err
+	}
+
+	if tlsConfig != nil && s.config.HTTPRedirectAddr != "" {
+		redirectLn, err := net.Listen("tcp", s.config.HTTPRedirectAddr)
+		if err != nil {
+			ln.Close()
+			closeBoundListeners(bound)
+			return fmt.Errorf("failed to bind port %s: %w", s.config.HTTPRedirectAddr, err)
+		}
+		s.redirect = &http.Server{
+			Handler:      redirectHandler,
+			ReadTimeout:  s.config.ReadTimeout,
+			WriteTimeout: s.config.WriteTimeout,
+			IdleTimeout:  s.config.IdleTimeout,
+		}
+		bound = append(bound, &boundListener{config: ServerListener{Network: "tcp", Addr: s.config.HTTPRedirectAddr}, ln: redirectLn, http: s.redirect})
+	}
+
+	s.mu.Lock()
+	s.listener = ln
+	s.bound = bound
+	s.mu.Unlock()
+	close(s.ready)
 
-	// Start the server in a goroutine
-	go func() {
-		slog.Info("server listening", "addr", addr)
-		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+	// Channel to listen for errors coming from the listeners.
+	serverErrors := make(chan error, len(bound)+1)
+	serve := func(serveFn func() error) {
+		if err := serveFn(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			serverErrors <- err
 		}
-	}()
+	}
+
+	// Start the servers in goroutines
+	if s.http.Protocols == nil {
+		s.http.Protocols = serverProtocols(s.config.H2C)
+	}
+	if tlsConfig != nil {
+		s.http.TLSConfig = tlsConfig
+		slog.Info("server listening", "addr", ln.Addr().String(), "tls", true)
+		go serve(func() error { return s.http.ServeTLS(ln, "", "") })
+	} else {
+		slog.Info("server listening", "addr", ln.Addr().String(), "h2c", s.config.H2C)
+		go serve(func() error { return s.http.Serve(ln) })
+	}
+	for _, b := range bound {
+		slog.Info("server listening", "network", b.config.Network, "addr", b.ln.Addr().String(), "h2c", b.config.H2C)
+		go serve(func() error { return b.http.Serve(b.ln) })
+	}
 
 	// Channel to listen for shutdown signals
 	shutdown := make(chan os.Signal, 1)
-	signal.Notify(shutdown, os.Interrupt, syscall.SIGTERM)
+	if len(s.signals) > 0 {
+		signal.Notify(shutdown, s.signals...)
+		defer signal.Stop(shutdown)
+	}
 
 	// Blocking wait: either for a server error, a shutdown signal, or context cancellation
+	var serveErr error
 	select {
 	case err := <-serverErrors:
-		return fmt.Errorf("server error: %w", err)
+		serveErr = fmt.Errorf("server error: %w", err)
 
 	case sig := <-shutdown:
-		signal.Stop(shutdown)
 		slog.Info("shutdown signal received", "signal", sig.String())
 
 	case <-ctx.Done():
 		slog.Info("context cancelled, shutting down server")
 	}
 
-	// Shutdown process
-	shutdownCtx, cancel := context.WithTimeout(context.Background(), config.ShutdownTimeout)
+	// Shutdown process: all listeners drain concurrently within ShutdownTimeout
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.config.ShutdownTimeout)
 	defer cancel()
 
-	if err := srv.Shutdown(shutdownCtx); err != nil {
-		// Just log the error, don't force Close() defensively unless critical
-		// srv.Shutdown already closes the listener on context expiry/error
-		return fmt.Errorf("shutdown error: %w", err)
+	servers := []*http.Server{s.http}
+	for _, b := range bound {
+		servers = append(servers, b.http)
+	}
+	shutdownErrs := make([]error, len(servers))
+	var wg sync.WaitGroup
+	for i, srv := range servers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if serveErr != nil {
+				srv.Close()
+				return
+			}
+			if err := srv.Shutdown(shutdownCtx); err != nil {
+				// srv.Shutdown already closes the listener on context expiry/error
+				shutdownErrs[i] = fmt.Errorf("shutdown error: %w", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	hookErr := s.runShutdownHooks(shutdownCtx)
+	if err := errors.Join(serveErr, errors.Join(shutdownErrs...), hookErr); err != nil {
+		return err
 	}
 
 	slog.Info("server stopped gracefully")
 	return nil
 }
+
+// runShutdownHooks menjalankan hook OnShutdown dalam urutan terbalik dan menggabungkan error-nya.
+func (s *Server) runShutdownHooks(ctx context.Context) error {
+	s.mu.Lock()
+	hooks := append([]shutdownHook(nil), s.hooks...)
+	s.mu.Unlock()
+
+	var errs []error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		if err := runShutdownHook(ctx, hooks[i]); err != nil {
+			slog.Error("shutdown hook failed", "hook", hooks[i].name, "error", err)
+			errs = append(errs, fmt.Errorf("shutdown hook %s: %w", hooks[i].name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func runShutdownHook(ctx context.Context, hook shutdownHook) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("panic: %v", p)
+		}
+	}()
+	return hook.fn(ctx)
+}
+
+// StartServer starts the HTTP server with graceful shutdown support.
+// It listens on the specified port and serves requests using the provided handler.
+// When a SIGINT or SIGTERM signal is received (or context cancelled), it will attempt to shut down
+// the server gracefully. Use NewServer to register OnShutdown hooks.
+//
+// Parameters:
+//   - ctx: context to control the server (e.g., from main)
+//   - config: ServerConfig containing port and timeouts.
+//   - handler: http.Handler to serve (usually the Router).
+//
+// Returns:
+//   - error: error if server fails to start or shutdown error.
+//
+// Example:
+//
+//	ctx := context.Background()
+//	config := dim.ServerConfig{Port: "8080"}
+//	router := dim.NewRouter()
+//	// ... register routes ...
+//	if err := dim.StartServer(ctx, config, router); err != nil {
+//	    log.Fatal(err)
+//	}
+func StartServer(ctx context.Context, config ServerConfig, handler http.Handler) error {
+	return NewServer(config, handler).Run(ctx)
+}
@@ -45,6 +45,13 @@ func (c *ServeCommand) Execute(ctx *CommandContext) error {
 		config.Port = "8080"
 	}
 
+	// Populate cache before the listener is opened so the first requests hit warm data
+	if ctx.Warmup != nil {
+		if _, err := ctx.Warmup.Run(context.Background()); err != nil {
+			return fmt.Errorf("warmup failed: %w", err)
+		}
+	}
+
 	slog.Info("starting server", "port", config.Port)
 	return StartServer(context.Background(), config, ctx.Router)
 }
@@ -0,0 +1,199 @@
+package dim
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+)
+
+// ServerListener adalah listener tambahan dim.Server, misal unix socket untuk reverse proxy lokal
+// atau port internal untuk health check dan metrics. Setiap listener melayani handler Server yang
+// sama dengan middleware tambahannya sendiri.
+type ServerListener struct {
+	// Network adalah "tcp" (default) atau "unix".
+	Network string
+	// Addr adalah alamat listener, misal ":9090" atau "/run/myapp.sock".
+	Addr string
+	// H2C mengaktifkan HTTP/2 tanpa TLS (prior knowledge), misal untuk gRPC-gateway
+	// atau load balancer yang berbicara HTTP/2 ke backend.
+	H2C bool
+	// Middleware diterapkan hanya untuk request di listener ini, di luar handler Server.
+	Middleware []MiddlewareFunc
+	// Handler menggantikan handler Server untuk listener ini (opsional), misal router internal.
+	Handler http.Handler
+}
+
+// boundListener adalah listener tambahan yang sudah dibuka beserta http.Server-nya.
+type boundListener struct {
+	config ServerListener
+	ln     net.Listener
+	http   *http.Server
+}
+
+// AddListener menambahkan listener yang dilayani bersamaan dengan listener utama. Listener dibuka
+// saat Run/Serve dan ikut graceful shutdown. File unix socket lama dihapus sebelum listen.
+//
+// Parameters:
+//   - listener: konfigurasi listener
+//
+// Returns:
+//   - *Server: server yang sama untuk chaining
+//
+// Example:
+//
+//	server := dim.NewServer(cfg.Server, router).
+//	    AddListener(dim.ServerListener{Network: "unix", Addr: "/run/myapp.sock"}).
+//	    AddListener(dim.ServerListener{Addr: "127.0.0.1:9090", Middleware: []dim.MiddlewareFunc{internalOnly}})
+func (s *Server) AddListener(listener ServerListener) *Server {
+	if listener.Addr == "" {
+		panic("dim: AddListener requires an address")
+	}
+	if listener.Network == "" {
+		listener.Network = "tcp"
+	}
+	if listener.Network != "tcp" && listener.Network != "unix" {
+		panic(fmt.Sprintf("dim: AddListener requires network \"tcp\" or \"unix\", got %q", listener.Network))
+	}
+	s.listeners = append(s.listeners, listener)
+	return s
+}
+
+// WithInternalHandler mengatur handler yang dilayani di INTERNAL_PORT. Tanpa pemanggilan ini,
+// Server memakai Router.Internal jika handler Server adalah *Router; gunakan method ini jika
+// router dibungkus handler lain.
+//
+// Parameters:
+//   - handler: handler route internal, biasanya router.InternalHandler()
+//
+// Returns:
+//   - *Server: server yang sama untuk chaining
+func (s *Server) WithInternalHandler(handler http.Handler) *Server {
+	s.internal = handler
+	return s
+}
+
+// internalHandler mengembalikan handler route internal, atau nil jika tidak ada.
+func (s *Server) internalHandler() http.Handler {
+	if s.internal != nil {
+		return s.internal
+	}
+	if router, ok := s.http.Handler.(*Router); ok {
+		return router.InternalHandler()
+	}
+	return nil
+}
+
+// WithH2C mengaktifkan HTTP/2 cleartext (h2c) di listener utama, untuk deployment di belakang
+// proxy yang meneruskan HTTP/2 tanpa TLS. HTTP/1.1 tetap dilayani.
+func (s *Server) WithH2C() *Server {
+	s.config.H2C = true
+	return s
+}
+
+// Addrs mengembalikan alamat semua listener yang berjalan: listener utama, SERVER_UNIX_SOCKET,
+// INTERNAL_PORT, listener dari AddListener sesuai urutan, lalu listener redirect HTTPS.
+func (s *Server) Addrs() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	addrs := []string{s.addr}
+	if s.listener != nil {
+		addrs[0] = s.listener.Addr().String()
+	}
+	for _, bound := range s.bound {
+		addrs = append(addrs, bound.ln.Addr().String())
+	}
+	return addrs
+}
+
+// serverProtocols mengembalikan protokol HTTP yang dilayani; nil berarti default net/http.
+func serverProtocols(h2c bool) *http.Protocols {
+	if !h2c {
+		return nil
+	}
+	protocols := new(http.Protocols)
+	protocols.SetHTTP1(true)
+	protocols.SetHTTP2(true)
+	protocols.SetUnencryptedHTTP2(true)
+	return protocols
+}
+
+// bindListeners membuka semua listener tambahan. Jika salah satu gagal, listener yang sudah
+// terbuka ditutup kembali.
+func (s *Server) bindListeners() ([]*boundListener, error) {
+	var listeners []ServerListener
+	if s.config.UnixSocket != "" {
+		listeners = append(listeners, ServerListener{Network: "unix", Addr: s.config.UnixSocket, H2C: s.config.H2C})
+	}
+	if internal := s.internalHandler(); internal != nil {
+		if s.config.InternalPort != "" {
+			listeners = append(listeners, ServerListener{Network: "tcp", Addr: serverAddr(s.config.InternalPort), Handler: internal})
+		} else {
+			slog.Warn("internal routes are not served because INTERNAL_PORT is not set")
+		}
+	}
+	listeners = append(listeners, s.listeners...)
+
+	bound := make([]*boundListener, 0, len(listeners))
+	for _, config := range listeners {
+		if config.Network == "unix" {
+			if err := removeStaleSocket(config.Addr); err != nil {
+				closeBoundListeners(bound)
+				return nil, err
+			}
+		}
+		ln, err := net.Listen(config.Network, config.Addr)
+		if err != nil {
+			closeBoundListeners(bound)
+			return nil, fmt.Errorf("failed to bind %s %s: %w", config.Network, config.Addr, err)
+		}
+
+		handler := config.Handler
+		if handler == nil {
+			handler = s.http.Handler
+		}
+		if handler == nil {
+			handler = http.DefaultServeMux
+		}
+		if len(config.Middleware) > 0 {
+			handler = Chain(handler.ServeHTTP, config.Middleware...)
+		}
+		bound = append(bound, &boundListener{
+			config: config,
+			ln:     ln,
+			http: &http.Server{
+				Handler:      handler,
+				ReadTimeout:  s.config.ReadTimeout,
+				WriteTimeout: s.config.WriteTimeout,
+				IdleTimeout:  s.config.IdleTimeout,
+				Protocols:    serverProtocols(config.H2C),
+			},
+		})
+	}
+	return bound, nil
+}
+
+func closeBoundListeners(bound []*boundListener) {
+	for _, b := range bound {
+		b.ln.Close()
+	}
+}
+
+// removeStaleSocket menghapus file unix socket sisa proses sebelumnya. File yang bukan socket
+// tidak dihapus agar salah konfigurasi tidak menghapus data.
+func removeStaleSocket(path string) error {
+	info, err := os.Lstat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("failed to bind unix %s: file exists and is not a socket", path)
+	}
+	return os.Remove(path)
+}
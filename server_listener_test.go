@@ -0,0 +1,205 @@
+package dim
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func protoHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "%s %s", r.Proto, w.Header().Get("X-Listener"))
+	})
+}
+
+func runTestServer(t *testing.T, s *Server) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- s.WithSignals().Run(ctx) }()
+	select {
+	case <-s.Ready():
+	case err := <-done:
+		t.Fatalf("Run() error = %v", err)
+	}
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("Run() error = %v", err)
+		}
+	})
+}
+
+func getBody(t *testing.T, client *http.Client, url string) string {
+	t.Helper()
+	resp, err := client.Get(url)
+	if err != nil {
+		t.Fatalf("GET %s error = %v", url, err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return string(body)
+}
+
+func TestServer_MultipleListeners(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("unix sockets are not supported on windows")
+	}
+	socket := filepath.Join(t.TempDir(), "app.sock")
+	tagInternal := func(next HandlerFunc) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Listener", "internal")
+			next(w, r)
+		}
+	}
+
+	s := NewServer(ServerConfig{Port: "127.0.0.1:0", UnixSocket: socket}, protoHandler()).
+		AddListener(ServerListener{Addr: "127.0.0.1:0", Middleware: []MiddlewareFunc{tagInternal}})
+	runTestServer(t, s)
+
+	addrs := s.Addrs()
+	if len(addrs) != 3 || addrs[1] != socket {
+		t.Fatalf("Addrs() = %v", addrs)
+	}
+
+	if got := getBody(t, http.DefaultClient, "http://"+addrs[0]+"/"); got != "HTTP/1.1 " {
+		t.Errorf("main listener = %q", got)
+	}
+	if got := getBody(t, http.DefaultClient, "http://"+addrs[2]+"/"); got != "HTTP/1.1 internal" {
+		t.Errorf("internal listener = %q, want middleware applied", got)
+	}
+
+	unixClient := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", socket)
+		},
+	}}
+	if got := getBody(t, unixClient, "http://unix/"); got != "HTTP/1.1 " {
+		t.Errorf("unix listener = %q", got)
+	}
+}
+
+func TestServer_H2C(t *testing.T) {
+	s := NewServer(ServerConfig{Port: "127.0.0.1:0"}, protoHandler()).
+		WithH2C().
+		AddListener(ServerListener{Addr: "127.0.0.1:0"})
+	runTestServer(t, s)
+
+	protocols := new(http.Protocols)
+	protocols.SetUnencryptedHTTP2(true)
+	h2cClient := &http.Client{Transport: &http.Transport{Protocols: protocols}}
+
+	if got := getBody(t, h2cClient, "http://"+s.Addrs()[0]+"/"); got != "HTTP/2.0 " {
+		t.Errorf("h2c main listener = %q", got)
+	}
+	// HTTP/1.1 tetap dilayani di listener h2c
+	if got := getBody(t, http.DefaultClient, "http://"+s.Addrs()[0]+"/"); got != "HTTP/1.1 " {
+		t.Errorf("http/1.1 on h2c listener = %q", got)
+	}
+	// Listener tambahan tanpa H2C menolak prior-knowledge HTTP/2
+	if _, err := h2cClient.Get("http://" + s.Addrs()[1] + "/"); err == nil {
+		t.Error("listener without H2C should not accept HTTP/2 cleartext")
+	}
+}
+
+func TestServer_BindListenerError(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	err = NewServer(ServerConfig{Port: "127.0.0.1:0"}, nil).
+		WithSignals().
+		AddListener(ServerListener{Addr: ln.Addr().String()}).
+		Run(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "failed to bind tcp") {
+		t.Errorf("Run() error = %v", err)
+	}
+}
+
+func TestRemoveStaleSocket(t *testing.T) {
+	dir := t.TempDir()
+	regular := filepath.Join(dir, "data.txt")
+	os.WriteFile(regular, []byte("keep"), 0644)
+	if err := removeStaleSocket(regular); err == nil || !strings.Contains(err.Error(), "not a socket") {
+		t.Errorf("removeStaleSocket(regular) error = %v", err)
+	}
+	if _, err := os.Stat(regular); err != nil {
+		t.Error("regular file must not be removed")
+	}
+	if err := removeStaleSocket(filepath.Join(dir, "missing.sock")); err != nil {
+		t.Errorf("removeStaleSocket(missing) error = %v", err)
+	}
+}
+
+func TestServer_AddListenerValidation(t *testing.T) {
+	for _, listener := range []ServerListener{{}, {Network: "udp", Addr: ":53"}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("AddListener(%+v) should panic", listener)
+				}
+			}()
+			NewServer(ServerConfig{}, nil).AddListener(listener)
+		}()
+	}
+}
+
+func TestServer_InternalPort(t *testing.T) {
+	router := NewRouter()
+	router.Get("/api", func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("api")) })
+	router.Internal().Get("/metrics", func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("metrics")) })
+
+	s := NewServer(ServerConfig{Port: "127.0.0.1:0", InternalPort: "127.0.0.1:0"}, router)
+	runTestServer(t, s)
+
+	addrs := s.Addrs()
+	if len(addrs) != 2 {
+		t.Fatalf("Addrs() = %v", addrs)
+	}
+	if got := getBody(t, http.DefaultClient, "http://"+addrs[1]+"/metrics"); got != "metrics" {
+		t.Errorf("internal /metrics = %q", got)
+	}
+	if got := getBody(t, http.DefaultClient, "http://"+addrs[0]+"/api"); got != "api" {
+		t.Errorf("public /api = %q", got)
+	}
+	if got := getBody(t, http.DefaultClient, "http://"+addrs[0]+"/metrics"); got == "metrics" {
+		t.Error("internal route should not be served on the public port")
+	}
+}
+
+func TestServer_InternalPortDisabled(t *testing.T) {
+	router := NewRouter()
+	router.Internal().Get("/metrics", func(w http.ResponseWriter, r *http.Request) {})
+
+	// Tanpa INTERNAL_PORT route internal tidak dilayani di port mana pun
+	s := NewServer(ServerConfig{Port: "127.0.0.1:0"}, router)
+	runTestServer(t, s)
+	if addrs := s.Addrs(); len(addrs) != 1 {
+		t.Errorf("Addrs() = %v, want only the public listener", addrs)
+	}
+}
+
+func TestServer_WithInternalHandler(t *testing.T) {
+	router := NewRouter()
+	router.Internal().Get("/metrics", func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("metrics")) })
+
+	// Router yang dibungkus handler lain tidak terdeteksi otomatis
+	wrapped := http.TimeoutHandler(router, time.Second, "timeout")
+	s := NewServer(ServerConfig{Port: "127.0.0.1:0", InternalPort: "127.0.0.1:0"}, wrapped).
+		WithInternalHandler(router.InternalHandler())
+	runTestServer(t, s)
+
+	if got := getBody(t, http.DefaultClient, "http://"+s.Addrs()[1]+"/metrics"); got != "metrics" {
+		t.Errorf("internal /metrics = %q", got)
+	}
+}
@@ -0,0 +1,143 @@
+package dim
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNewServer_Defaults(t *testing.T) {
+	s := NewServer(ServerConfig{Port: "9090"}, http.NotFoundHandler())
+	srv := s.HTTPServer()
+	if s.Addr() != ":9090" || srv.Addr != ":9090" {
+		t.Errorf("Addr() = %q, http addr = %q", s.Addr(), srv.Addr)
+	}
+	if srv.ReadTimeout != 10*time.Second || srv.WriteTimeout != 10*time.Second || srv.IdleTimeout != 120*time.Second {
+		t.Errorf("timeouts = %v/%v/%v", srv.ReadTimeout, srv.WriteTimeout, srv.IdleTimeout)
+	}
+
+	s = NewServer(ServerConfig{Port: "127.0.0.1:0", ReadTimeout: time.Second, WriteTimeout: 2 * time.Second, IdleTimeout: 3 * time.Second}, nil)
+	srv = s.HTTPServer()
+	if srv.Addr != "127.0.0.1:0" || srv.ReadTimeout != time.Second || srv.WriteTimeout != 2*time.Second || srv.IdleTimeout != 3*time.Second {
+		t.Errorf("configured server = %+v", srv)
+	}
+}
+
+func TestServer_GracefulShutdown(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		io.WriteString(w, "done")
+	})
+
+	var mu sync.Mutex
+	var order []string
+	record := func(name string) ShutdownFunc {
+		return func(ctx context.Context) error {
+			mu.Lock()
+			defer mu.Unlock()
+			order = append(order, name)
+			return nil
+		}
+	}
+
+	s := NewServer(ServerConfig{Port: "127.0.0.1:0", ShutdownTimeout: 5 * time.Second}, handler).
+		WithSignals().
+		OnShutdown("database", record("database")).
+		OnShutdown("queue", record("queue"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- s.Run(ctx) }()
+	<-s.Ready()
+
+	body := make(chan string, 1)
+	go func() {
+		resp, err := http.Get("http://" + s.Addr() + "/slow")
+		if err != nil {
+			body <- "error: " + err.Error()
+			return
+		}
+		defer resp.Body.Close()
+		b, _ := io.ReadAll(resp.Body)
+		body <- string(b)
+	}()
+
+	<-started
+	cancel()
+
+	// Hook tidak boleh berjalan selama request masih diproses
+	time.Sleep(50 * time.Millisecond)
+	mu.Lock()
+	if len(order) != 0 {
+		t.Errorf("hooks ran before in-flight request finished: %v", order)
+	}
+	mu.Unlock()
+
+	close(release)
+	if got := <-body; got != "done" {
+		t.Errorf("in-flight response = %q", got)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if strings.Join(order, ",") != "queue,database" {
+		t.Errorf("hook order = %v, want queue,database", order)
+	}
+}
+
+func TestServer_ShutdownHookErrors(t *testing.T) {
+	var closed bool
+	s := NewServer(ServerConfig{Port: "127.0.0.1:0"}, http.NotFoundHandler()).
+		WithSignals().
+		OnShutdown("database", func(ctx context.Context) error {
+			closed = true
+			return nil
+		}).
+		OnShutdown("cache", func(ctx context.Context) error {
+			return errors.New("connection reset")
+		}).
+		OnShutdown("queue", func(ctx context.Context) error {
+			panic("worker stuck")
+		})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := s.Run(ctx)
+	if err == nil || !strings.Contains(err.Error(), "shutdown hook cache: connection reset") || !strings.Contains(err.Error(), "shutdown hook queue: panic: worker stuck") {
+		t.Errorf("Run() error = %v", err)
+	}
+	if !closed {
+		t.Error("remaining hooks should run after a failing hook")
+	}
+}
+
+func TestServer_BindError(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	err = NewServer(ServerConfig{Port: ln.Addr().String()}, nil).WithSignals().Run(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "failed to bind port") {
+		t.Errorf("Run() error = %v", err)
+	}
+}
+
+func TestServer_OnShutdownNilPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic")
+		}
+	}()
+	NewServer(ServerConfig{}, nil).OnShutdown("nil", nil)
+}
@@ -0,0 +1,135 @@
+package dim
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// defaultAutoTLSCacheDir adalah direktori cache sertifikat ACME jika SERVER_AUTOTLS_CACHE_DIR kosong.
+const defaultAutoTLSCacheDir = "autocert-cache"
+
+// TLSEnabled mengembalikan true jika sertifikat file atau AutoTLS dikonfigurasi.
+func (c ServerConfig) TLSEnabled() bool {
+	return c.TLSCertFile != "" || len(c.AutoTLSDomains) > 0
+}
+
+// validateTLS memastikan kombinasi opsi TLS konsisten.
+func (c ServerConfig) validateTLS() error {
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		return fmt.Errorf("SERVER_TLS_CERT_FILE and SERVER_TLS_KEY_FILE must be set together")
+	}
+	if c.TLSCertFile != "" && len(c.AutoTLSDomains) > 0 {
+		return fmt.Errorf("SERVER_TLS_CERT_FILE cannot be combined with SERVER_AUTOTLS_DOMAINS; use one certificate source")
+	}
+	if c.HTTPRedirectAddr != "" && !c.TLSEnabled() {
+		return fmt.Errorf("SERVER_HTTP_REDIRECT_ADDR requires SERVER_TLS_CERT_FILE or SERVER_AUTOTLS_DOMAINS")
+	}
+	return nil
+}
+
+// WithTLS mengaktifkan HTTPS dengan sertifikat dan private key dari file PEM.
+//
+// Example:
+//
+//	dim.NewServer(cfg.Server, router).WithTLS("/etc/ssl/app.crt", "/etc/ssl/app.key")
+func (s *Server) WithTLS(certFile, keyFile string) *Server {
+	s.config.TLSCertFile = certFile
+	s.config.TLSKeyFile = keyFile
+	return s
+}
+
+// WithAutoTLS mengaktifkan sertifikat otomatis dari Let's Encrypt untuk domains. Sertifikat disimpan
+// di cacheDir agar tidak diminta ulang setiap restart. Server harus dapat dijangkau dari internet
+// di port 443 (challenge TLS-ALPN-01) atau port 80 lewat WithHTTPRedirect (challenge HTTP-01).
+//
+// Example:
+//
+//	dim.NewServer(cfg.Server, router).
+//	    WithAutoTLS("/var/lib/myapp/certs", "example.com", "www.example.com").
+//	    WithHTTPRedirect(":80")
+func (s *Server) WithAutoTLS(cacheDir string, domains ...string) *Server {
+	s.config.AutoTLSCacheDir = cacheDir
+	s.config.AutoTLSDomains = domains
+	return s
+}
+
+// WithHTTPRedirect membuka listener HTTP di addr yang me-redirect semua request ke HTTPS (308)
+// dan melayani challenge ACME HTTP-01 saat AutoTLS aktif.
+func (s *Server) WithHTTPRedirect(addr string) *Server {
+	s.config.HTTPRedirectAddr = addr
+	return s
+}
+
+// WithTLSConfig mengganti *tls.Config dasar, misal untuk cipher suite atau client certificate (mTLS).
+// Sertifikat dari WithTLS/WithAutoTLS tetap ditambahkan ke config ini.
+func (s *Server) WithTLSConfig(config *tls.Config) *Server {
+	s.tlsConfig = config
+	return s
+}
+
+// buildTLS menyiapkan *tls.Config dan handler untuk listener redirect. Mengembalikan nil jika TLS
+// tidak dikonfigurasi.
+func (s *Server) buildTLS() (*tls.Config, http.Handler, error) {
+	if err := s.config.validateTLS(); err != nil {
+		return nil, nil, err
+	}
+	if !s.config.TLSEnabled() && s.tlsConfig == nil {
+		return nil, nil, nil
+	}
+
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+	if s.tlsConfig != nil {
+		config = s.tlsConfig.Clone()
+	}
+
+	var redirect http.Handler = httpsRedirectHandler(s.addr)
+	switch {
+	case s.config.TLSCertFile != "":
+		cert, err := tls.LoadX509KeyPair(s.config.TLSCertFile, s.config.TLSKeyFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load TLS certificate: %w", err)
+		}
+		config.Certificates = append(config.Certificates, cert)
+
+	case len(s.config.AutoTLSDomains) > 0:
+		cacheDir := s.config.AutoTLSCacheDir
+		if cacheDir == "" {
+			cacheDir = defaultAutoTLSCacheDir
+		}
+		manager := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(s.config.AutoTLSDomains...),
+			Cache:      autocert.DirCache(cacheDir),
+			Email:      s.config.AutoTLSEmail,
+		}
+		config.GetCertificate = manager.GetCertificate
+		config.NextProtos = append(config.NextProtos, "acme-tls/1")
+		redirect = manager.HTTPHandler(redirect)
+	}
+
+	return config, redirect, nil
+}
+
+// httpsRedirectHandler me-redirect request ke skema https pada host yang sama. Port ditambahkan
+// jika alamat HTTPS bukan port 443.
+func httpsRedirectHandler(httpsAddr string) http.Handler {
+	_, port, _ := net.SplitHostPort(httpsAddr)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if strings.Contains(host, ":") {
+			host = "[" + host + "]" // IPv6 literal
+		}
+		if port != "" && port != "443" && port != "0" {
+			host += ":" + port
+		}
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusPermanentRedirect)
+	})
+}
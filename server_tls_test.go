@@ -0,0 +1,210 @@
+package dim
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeTestCertificate membuat sertifikat self-signed untuk 127.0.0.1 dan menulis file PEM-nya.
+func writeTestCertificate(t *testing.T) (certFile, keyFile string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	certFile = filepath.Join(dir, "cert.pem")
+	keyFile = filepath.Join(dir, "key.pem")
+	os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)
+	os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600)
+	return certFile, keyFile
+}
+
+func TestServer_TLS(t *testing.T) {
+	certFile, keyFile := writeTestCertificate(t)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, r.Proto)
+	})
+
+	s := NewServer(ServerConfig{Port: "127.0.0.1:0"}, handler).WithSignals().WithTLS(certFile, keyFile)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- s.Run(ctx) }()
+	<-s.Ready()
+
+	client := &http.Client{Transport: &http.Transport{
+		TLSClientConfig:   &tls.Config{InsecureSkipVerify: true},
+		ForceAttemptHTTP2: true,
+	}}
+	resp, err := client.Get("https://" + s.Addr() + "/")
+	if err != nil {
+		t.Fatalf("GET error = %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.TLS == nil || string(body) != "HTTP/2.0" {
+		t.Errorf("TLS = %v, proto = %s; want TLS with HTTP/2", resp.TLS != nil, body)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Run() error = %v", err)
+	}
+}
+
+func TestServer_TLSInvalidCertificate(t *testing.T) {
+	err := NewServer(ServerConfig{Port: "127.0.0.1:0"}, nil).
+		WithSignals().
+		WithTLS("missing.crt", "missing.key").
+		Run(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "failed to load TLS certificate") {
+		t.Errorf("Run() error = %v", err)
+	}
+}
+
+func TestServer_AutoTLSConfig(t *testing.T) {
+	s := NewServer(ServerConfig{Port: ":443"}, nil).WithAutoTLS(t.TempDir(), "example.com")
+	config, redirect, err := s.buildTLS()
+	if err != nil {
+		t.Fatalf("buildTLS() error = %v", err)
+	}
+	if config.GetCertificate == nil || !slices.Contains(config.NextProtos, "acme-tls/1") {
+		t.Errorf("autocert TLS config not applied: %+v", config)
+	}
+
+	// Listener redirect melayani challenge HTTP-01 dan me-redirect request lain
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/posts?page=2", nil)
+	rec := httptest.NewRecorder()
+	redirect.ServeHTTP(rec, req)
+	if rec.Code != http.StatusPermanentRedirect || rec.Header().Get("Location") != "https://example.com/posts?page=2" {
+		t.Errorf("redirect = %d %s", rec.Code, rec.Header().Get("Location"))
+	}
+
+	// Domain di luar whitelist tidak mendapat sertifikat
+	if _, err := config.GetCertificate(&tls.ClientHelloInfo{ServerName: "evil.example.net"}); err == nil {
+		t.Error("GetCertificate should reject hosts outside AutoTLSDomains")
+	}
+}
+
+func TestHTTPSRedirectHandler(t *testing.T) {
+	tests := []struct {
+		addr, host, target, want string
+	}{
+		{":443", "example.com", "/a?b=c", "https://example.com/a?b=c"},
+		{":443", "example.com:80", "/", "https://example.com/"},
+		{":8443", "example.com:8080", "/login", "https://example.com:8443/login"},
+		{":8443", "[::1]:8080", "/", "https://[::1]:8443/"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, tt.target, nil)
+		req.Host = tt.host
+		rec := httptest.NewRecorder()
+		httpsRedirectHandler(tt.addr).ServeHTTP(rec, req)
+		if got := rec.Header().Get("Location"); got != tt.want || rec.Code != http.StatusPermanentRedirect {
+			t.Errorf("redirect(%s, %s) = %d %q, want %q", tt.addr, tt.host, rec.Code, got, tt.want)
+		}
+	}
+}
+
+func TestServer_HTTPRedirectListener(t *testing.T) {
+	certFile, keyFile := writeTestCertificate(t)
+	ln, _ := net.Listen("tcp", "127.0.0.1:0")
+	redirectAddr := ln.Addr().String()
+	ln.Close()
+
+	s := NewServer(ServerConfig{Port: "127.0.0.1:0"}, http.NotFoundHandler()).
+		WithSignals().
+		WithTLS(certFile, keyFile).
+		WithHTTPRedirect(redirectAddr)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- s.Run(ctx) }()
+	<-s.Ready()
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	resp, err := client.Get("http://" + redirectAddr + "/orders")
+	if err != nil {
+		t.Fatalf("GET error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusPermanentRedirect || !strings.HasPrefix(resp.Header.Get("Location"), "https://127.0.0.1") {
+		t.Errorf("redirect = %d %s", resp.StatusCode, resp.Header.Get("Location"))
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Run() error = %v", err)
+	}
+}
+
+func TestServerConfig_ValidateTLS(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  ServerConfig
+		wantErr string
+	}{
+		{"none", ServerConfig{}, ""},
+		{"files", ServerConfig{TLSCertFile: "a.crt", TLSKeyFile: "a.key", HTTPRedirectAddr: ":80"}, ""},
+		{"autotls", ServerConfig{AutoTLSDomains: []string{"example.com"}}, ""},
+		{"cert without key", ServerConfig{TLSCertFile: "a.crt"}, "must be set together"},
+		{"both sources", ServerConfig{TLSCertFile: "a.crt", TLSKeyFile: "a.key", AutoTLSDomains: []string{"example.com"}}, "cannot be combined"},
+		{"redirect without tls", ServerConfig{HTTPRedirectAddr: ":80"}, "SERVER_HTTP_REDIRECT_ADDR requires"},
+	}
+	for _, tt := range tests {
+		err := tt.config.validateTLS()
+		if (tt.wantErr == "" && err != nil) || (tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr))) {
+			t.Errorf("%s: validateTLS() error = %v, want %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestLoadServerConfig_TLS(t *testing.T) {
+	values := map[string]string{
+		"SERVER_AUTOTLS_DOMAINS":    "example.com, www.example.com,",
+		"SERVER_AUTOTLS_EMAIL":      "ops@example.com",
+		"SERVER_HTTP_REDIRECT_ADDR": ":80",
+	}
+	cfg, err := loadServerConfig(func(key string) string { return values[key] })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(cfg.AutoTLSDomains, []string{"example.com", "www.example.com"}) || cfg.AutoTLSCacheDir != defaultAutoTLSCacheDir ||
+		cfg.AutoTLSEmail != "ops@example.com" || cfg.HTTPRedirectAddr != ":80" || !cfg.TLSEnabled() {
+		t.Errorf("server config = %+v", cfg)
+	}
+}
@@ -0,0 +1,233 @@
+package dim
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrSessionNotFound dikembalikan TokenStore.RevokeSession jika session tidak aktif atau bukan
+// milik user.
+var ErrSessionNotFound = errors.New("session not found")
+
+const clientInfoKey contextKey = "client_info"
+
+// Batas panjang yang disimpan di refresh_tokens (ip_address adalah VARCHAR(45)).
+const (
+	maxSessionUserAgent = 512
+	maxSessionIPAddress = 45
+)
+
+// ClientInfo adalah informasi device yang disimpan bersama refresh token saat login dan refresh,
+// lalu ditampilkan sebagai daftar "device yang sedang login".
+type ClientInfo struct {
+	UserAgent string
+	IPAddress string
+}
+
+// WithClientInfo menyimpan informasi device ke context. AuthService membaca nilai ini saat
+// menerbitkan refresh token (Login, VerifyMFA, RefreshToken, magic link, OAuth, passkey).
+//
+// Example:
+//
+//	ctx := dim.WithClientInfo(r.Context(), dim.ClientInfoFromRequest(r))
+//	access, refresh, err := authService.Login(ctx, req.Email, req.Password)
+func WithClientInfo(ctx context.Context, info ClientInfo) context.Context {
+	return context.WithValue(ctx, clientInfoKey, info)
+}
+
+// ClientInfoFromContext mengambil informasi device yang disimpan WithClientInfo atau
+// ClientInfoMiddleware.
+func ClientInfoFromContext(ctx context.Context) (ClientInfo, bool) {
+	if ctx == nil {
+		return ClientInfo{}, false
+	}
+	info, ok := ctx.Value(clientInfoKey).(ClientInfo)
+	return info, ok
+}
+
+// ClientInfoFromRequest membaca User-Agent dan IP client (via GetClientIP) dari request.
+// Nilai dipotong agar muat di kolom refresh_tokens.
+func ClientInfoFromRequest(r *http.Request) ClientInfo {
+	return ClientInfo{
+		UserAgent: truncateString(r.UserAgent(), maxSessionUserAgent),
+		IPAddress: truncateString(GetClientIP(r), maxSessionIPAddress),
+	}
+}
+
+// ClientInfoMiddleware menyimpan User-Agent dan IP client ke context setiap request sehingga
+// session yang dibuat AuthService mencatat device-nya. Pasang pada route login dan refresh,
+// atau secara global.
+//
+// Returns:
+//   - MiddlewareFunc: middleware informasi device
+//
+// Example:
+//
+//	router.Use(dim.ClientInfoMiddleware())
+func ClientInfoMiddleware() MiddlewareFunc {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			next(w, r.WithContext(WithClientInfo(r.Context(), ClientInfoFromRequest(r))))
+		}
+	}
+}
+
+// requestClientContext mengembalikan context request dengan ClientInfo, kecuali sudah di-set
+// oleh ClientInfoMiddleware. Dipakai handler bawaan yang menerbitkan session.
+func requestClientContext(r *http.Request) context.Context {
+	if _, ok := ClientInfoFromContext(r.Context()); ok {
+		return r.Context()
+	}
+	return WithClientInfo(r.Context(), ClientInfoFromRequest(r))
+}
+
+func truncateString(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return strings.ToValidUTF8(s[:max], "")
+}
+
+// SessionInfo adalah satu device yang sedang login, sebagaimana dikembalikan SessionsHandler.
+type SessionInfo struct {
+	ID        int64  `json:"id"`
+	UserAgent string `json:"user_agent"`
+	IPAddress string `json:"ip_address"`
+	// LastActiveAt adalah waktu login atau refresh terakhir session ini.
+	LastActiveAt time.Time `json:"last_active_at"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	// Current bernilai true untuk session milik access token request.
+	Current bool `json:"current"`
+}
+
+// Sessions mengembalikan session aktif milik user (satu refresh token aktif per device),
+// terbaru lebih dulu.
+//
+// Parameters:
+//   - ctx: context request
+//   - userID: ID user
+//
+// Returns:
+//   - []*RefreshToken: session aktif; ID dipakai untuk RevokeSession
+//   - error: *AppError jika penyimpanan gagal
+func (s *AuthService) Sessions(ctx context.Context, userID string) ([]*RefreshToken, error) {
+	sessions, err := s.tokenStore.ListActiveSessions(ctx, userID)
+	if err != nil {
+		return nil, NewAppError("Gagal mengambil daftar sesi", 500)
+	}
+	return sessions, nil
+}
+
+// RevokeSession mengeluarkan satu device: refresh token session dibatalkan dan session ID
+// dimasukkan ke blocklist agar access token yang masih berlaku langsung ditolak RequireAuth.
+//
+// Parameters:
+//   - ctx: context request
+//   - userID: ID user pemilik session
+//   - id: ID session dari Sessions
+//
+// Returns:
+//   - error: *AppError 404 jika session tidak aktif atau bukan milik user
+//
+// Example:
+//
+//	err := authService.RevokeSession(ctx, user.GetID(), sessionID)
+func (s *AuthService) RevokeSession(ctx context.Context, userID string, id int64) error {
+	sessions, err := s.Sessions(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	var session *RefreshToken
+	for _, candidate := range sessions {
+		if candidate.ID == id {
+			session = candidate
+			break
+		}
+	}
+	if session == nil {
+		return NewAppError("Sesi tidak ditemukan", http.StatusNotFound)
+	}
+
+	if err := s.tokenStore.RevokeSession(ctx, userID, id); err != nil {
+		if errors.Is(err, ErrSessionNotFound) {
+			return NewAppError("Sesi tidak ditemukan", http.StatusNotFound)
+		}
+		return NewAppError("Gagal mengakhiri sesi", 500)
+	}
+
+	if session.SessionID != "" && s.blocklist != nil {
+		if err := s.blocklist.Invalidate(ctx, session.SessionID, s.accessTokenExpiry()); err != nil && s.logger != nil {
+			s.logger.Warn("Failed to blacklist session", "session_id", session.SessionID, "error", err.Error())
+		}
+	}
+
+	return nil
+}
+
+// SessionsHandler membuat handler yang mengembalikan daftar SessionInfo milik user yang login.
+// Session milik access token request ditandai current. Pasang di belakang RequireAuth.
+//
+// Example:
+//
+//	auth := dim.RequireAuth(jwtManager, blocklist)
+//	router.Get("/auth/sessions", authService.SessionsHandler(), auth)
+//	router.Delete("/auth/sessions/{id}", authService.RevokeSessionHandler(), auth)
+func (s *AuthService) SessionsHandler() HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, ok := GetUser(r)
+		if !ok {
+			Unauthorized(w, "Tidak terotentikasi")
+			return
+		}
+		sessions, err := s.Sessions(r.Context(), user.GetID())
+		if err != nil {
+			InternalServerError(w, "Gagal mengambil daftar sesi")
+			return
+		}
+
+		currentSID, _ := GetClaims(r)["sid"].(string)
+		result := make([]SessionInfo, 0, len(sessions))
+		for _, session := range sessions {
+			result = append(result, SessionInfo{
+				ID:           session.ID,
+				UserAgent:    session.UserAgent,
+				IPAddress:    session.IPAddress,
+				LastActiveAt: session.CreatedAt,
+				ExpiresAt:    session.ExpiresAt,
+				Current:      currentSID != "" && session.SessionID == currentSID,
+			})
+		}
+		Json(w, http.StatusOK, result)
+	}
+}
+
+// RevokeSessionHandler membuat handler yang mengeluarkan session dengan path parameter {id}
+// dan mengembalikan 204. Pasang di belakang RequireAuth.
+func (s *AuthService) RevokeSessionHandler() HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, ok := GetUser(r)
+		if !ok {
+			Unauthorized(w, "Tidak terotentikasi")
+			return
+		}
+		id, err := strconv.ParseInt(GetParam(r, "id"), 10, 64)
+		if err != nil {
+			BadRequest(w, "ID sesi tidak valid", nil)
+			return
+		}
+		if err := s.RevokeSession(r.Context(), user.GetID(), id); err != nil {
+			if appErr, ok := AsAppError(err); ok {
+				JsonAppError(w, appErr)
+				return
+			}
+			InternalServerError(w, "Gagal mengakhiri sesi")
+			return
+		}
+		NoContent(w)
+	}
+}
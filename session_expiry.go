@@ -0,0 +1,135 @@
+package dim
+
+import (
+	"context"
+	"time"
+)
+
+const rememberMeKey contextKey = "remember_me"
+
+// defaultRefreshTokenExpiry dipakai jika TTL refresh token tidak diketahui dari konfigurasi.
+const defaultRefreshTokenExpiry = 7 * 24 * time.Hour
+
+// SessionOptions mengatur masa berlaku session (refresh token) yang diterbitkan AuthService.
+//
+// Setiap RefreshToken menggeser kadaluarsa session ke sekarang + TTL (sliding expiration),
+// tetapi tidak pernah melewati login + MaxLifetime. Dengan MaxLifetime <= TTL session menjadi
+// tetap (tidak bergeser); dengan MaxLifetime 0 session aktif selama user refresh sebelum TTL habis.
+type SessionOptions struct {
+	// RefreshTokenExpiry adalah TTL refresh token untuk login biasa. 0 berarti 7 hari.
+	RefreshTokenExpiry time.Duration
+	// RememberMeExpiry adalah TTL refresh token untuk login dengan WithRememberMe.
+	// 0 berarti sama dengan RefreshTokenExpiry.
+	RememberMeExpiry time.Duration
+	// MaxLifetime adalah batas absolut session sejak login, berlaku juga untuk session
+	// remember-me. 0 berarti tanpa batas.
+	MaxLifetime time.Duration
+}
+
+// SessionOptionsFromJWTConfig membuat SessionOptions dari JWT_REFRESH_TOKEN_EXPIRY,
+// JWT_REMEMBER_ME_EXPIRY, dan JWT_SESSION_MAX_LIFETIME. NewAuthService memakainya secara otomatis.
+func SessionOptionsFromJWTConfig(cfg *JWTConfig) SessionOptions {
+	return SessionOptions{
+		RefreshTokenExpiry: cfg.RefreshTokenExpiry,
+		RememberMeExpiry:   cfg.RememberMeExpiry,
+		MaxLifetime:        cfg.SessionMaxLifetime,
+	}
+}
+
+// sessionOptionsFromManager mengambil SessionOptions dari konfigurasi TokenManager bawaan.
+func sessionOptionsFromManager(manager TokenManager) SessionOptions {
+	switch m := manager.(type) {
+	case *JWTManager:
+		return SessionOptionsFromJWTConfig(m.config)
+	case *BrancaManager:
+		return SessionOptions{RefreshTokenExpiry: m.config.RefreshTokenExpiry}
+	}
+	return SessionOptions{}
+}
+
+// ttl mengembalikan TTL refresh token untuk login biasa atau remember-me.
+func (o SessionOptions) ttl(rememberMe bool) time.Duration {
+	ttl := o.RefreshTokenExpiry
+	if ttl <= 0 {
+		ttl = defaultRefreshTokenExpiry
+	}
+	if rememberMe && o.RememberMeExpiry > 0 {
+		ttl = o.RememberMeExpiry
+	}
+	return ttl
+}
+
+// WithSessionOptions mengganti masa berlaku session dan mengembalikan instance service.
+// Secara default nilainya diambil dari JWTConfig (NewAuthService) atau dari konfigurasi
+// TokenManager yang diberikan ke NewAuthServiceWithManager.
+//
+// Example:
+//
+//	authService.WithSessionOptions(dim.SessionOptions{
+//	    RefreshTokenExpiry: 24 * time.Hour,
+//	    RememberMeExpiry:   30 * 24 * time.Hour,
+//	    MaxLifetime:        90 * 24 * time.Hour,
+//	})
+func (s *AuthService) WithSessionOptions(opts SessionOptions) *AuthService {
+	s.sessionOpts = opts
+	return s
+}
+
+// WithRememberMe menandai login pada ctx sebagai "remember me" sehingga refresh token yang
+// diterbitkan (Login, VerifyMFA, magic link, OAuth, passkey) memakai SessionOptions.RememberMeExpiry.
+// Session mengingat pilihan ini sehingga RefreshToken tidak perlu ctx yang sama.
+//
+// Example:
+//
+//	ctx := r.Context()
+//	if req.RememberMe {
+//	    ctx = dim.WithRememberMe(ctx)
+//	}
+//	access, refresh, err := authService.Login(ctx, req.Email, req.Password)
+func WithRememberMe(ctx context.Context) context.Context {
+	return context.WithValue(ctx, rememberMeKey, true)
+}
+
+// RememberMeFromContext mengembalikan true jika ctx ditandai dengan WithRememberMe.
+func RememberMeFromContext(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	rememberMe, _ := ctx.Value(rememberMeKey).(bool)
+	return rememberMe
+}
+
+// newSessionExpiry menghitung kadaluarsa refresh token pertama sebuah session beserta batas
+// absolutnya (nil jika MaxLifetime 0).
+func (s *AuthService) newSessionExpiry(rememberMe bool) (time.Time, *time.Time) {
+	now := time.Now().UTC().Truncate(time.Second)
+	expiresAt := now.Add(s.sessionOpts.ttl(rememberMe))
+	if s.sessionOpts.MaxLifetime <= 0 {
+		return expiresAt, nil
+	}
+	sessionExpiresAt := now.Add(s.sessionOpts.MaxLifetime)
+	if expiresAt.After(sessionExpiresAt) {
+		expiresAt = sessionExpiresAt
+	}
+	return expiresAt, &sessionExpiresAt
+}
+
+// slideSessionExpiry menghitung kadaluarsa refresh token hasil rotasi: sekarang + TTL session,
+// dibatasi SessionExpiresAt dari login.
+func (s *AuthService) slideSessionExpiry(token *RefreshToken) time.Time {
+	expiresAt := time.Now().UTC().Truncate(time.Second).Add(s.sessionOpts.ttl(token.RememberMe))
+	if token.SessionExpiresAt != nil && expiresAt.After(*token.SessionExpiresAt) {
+		expiresAt = token.SessionExpiresAt.UTC()
+	}
+	return expiresAt
+}
+
+// generateRefreshToken membuat refresh token yang kadaluarsa pada expiresAt. TokenManager yang
+// tidak mengimplementasikan RefreshTokenExpiryGenerator memakai TTL-nya sendiri; session tetap
+// berakhir pada expiresAt karena RefreshToken memeriksa ExpiresAt di TokenStore.
+func (s *AuthService) generateRefreshToken(userID, sessionID string, expiresAt time.Time) (string, error) {
+	if generator, ok := s.tokenManager.(RefreshTokenExpiryGenerator); ok {
+		return generator.GenerateRefreshTokenWithExpiry(userID, sessionID, expiresAt)
+	}
+	return s.tokenManager.GenerateRefreshToken(userID, sessionID)
+}
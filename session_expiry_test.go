@@ -0,0 +1,144 @@
+package dim
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func newTestSessionExpiryService(t *testing.T, tokenStore TokenStore, maxLifetime time.Duration) *AuthService {
+	t.Helper()
+	service, err := NewAuthService(newTestSessionUserStore(), tokenStore, nil, &JWTConfig{
+		HMACSecret:         "test-secret",
+		SigningMethod:      "HS256",
+		AccessTokenExpiry:  15 * time.Minute,
+		RefreshTokenExpiry: time.Hour,
+		RememberMeExpiry:   30 * 24 * time.Hour,
+		SessionMaxLifetime: maxLifetime,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return service
+}
+
+func storedRefreshToken(t *testing.T, store TokenStore, token string) *RefreshToken {
+	t.Helper()
+	stored, err := store.FindRefreshToken(context.Background(), GenerateTokenHash(token))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return stored
+}
+
+func TestLogin_RememberMe(t *testing.T) {
+	tokenStore := NewMockTokenStore()
+	service := newTestSessionExpiryService(t, tokenStore, 0)
+
+	_, refresh, err := service.Login(context.Background(), "test@example.com", "ValidPass123!")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored := storedRefreshToken(t, tokenStore, refresh)
+	if d := time.Until(stored.ExpiresAt); stored.RememberMe || d <= 59*time.Minute || d > time.Hour {
+		t.Errorf("regular session expires in %v, remember_me %v", d, stored.RememberMe)
+	}
+
+	_, refresh, err = service.Login(WithRememberMe(context.Background()), "test@example.com", "ValidPass123!")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored = storedRefreshToken(t, tokenStore, refresh)
+	if d := time.Until(stored.ExpiresAt); !stored.RememberMe || d <= 29*24*time.Hour {
+		t.Errorf("remember-me session expires in %v, remember_me %v", d, stored.RememberMe)
+	}
+	if exp, err := service.tokenManager.GetTokenExpiry(refresh); err != nil || !exp.Equal(stored.ExpiresAt) {
+		t.Errorf("token exp = %v, stored %v", exp, stored.ExpiresAt)
+	}
+
+	// Remember-me is kept across rotation without the flag on the refresh context
+	_, refreshed, err := service.RefreshToken(context.Background(), refresh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next := storedRefreshToken(t, tokenStore, refreshed); !next.RememberMe || time.Until(next.ExpiresAt) <= 29*24*time.Hour {
+		t.Errorf("rotated session = %+v", next)
+	}
+}
+
+func TestRefreshToken_SlidingExpiryCapped(t *testing.T) {
+	tokenStore := NewMockTokenStore()
+	service := newTestSessionExpiryService(t, tokenStore, 90*time.Minute)
+	ctx := context.Background()
+
+	_, refresh, err := service.Login(ctx, "test@example.com", "ValidPass123!")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored := storedRefreshToken(t, tokenStore, refresh)
+	if stored.SessionExpiresAt == nil || time.Until(*stored.SessionExpiresAt) <= 89*time.Minute {
+		t.Fatalf("SessionExpiresAt = %v", stored.SessionExpiresAt)
+	}
+
+	// Session still far from the cap: refresh slides by the full TTL
+	_, refresh, err = service.RefreshToken(ctx, refresh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored = storedRefreshToken(t, tokenStore, refresh)
+	if d := time.Until(stored.ExpiresAt); d <= 59*time.Minute || d > time.Hour {
+		t.Errorf("slid expiry = %v, want ~1h", d)
+	}
+
+	// Simulate a login made 80 minutes ago: only 10 minutes remain before the cap
+	sessionCap := time.Now().Add(10 * time.Minute).UTC().Truncate(time.Second)
+	stored.SessionExpiresAt = &sessionCap
+	_, refresh, err = service.RefreshToken(ctx, refresh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored = storedRefreshToken(t, tokenStore, refresh)
+	if !stored.ExpiresAt.Equal(sessionCap) || !stored.SessionExpiresAt.Equal(sessionCap) {
+		t.Errorf("expiry = %v, cap %v; want capped", stored.ExpiresAt, sessionCap)
+	}
+}
+
+func TestSessionOptions_Defaults(t *testing.T) {
+	manager, err := NewBrancaManager(&BrancaConfig{Key: testBrancaKey(), RefreshTokenExpiry: 2 * time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	service, _ := NewAuthServiceWithManager(nil, nil, nil, manager)
+	if ttl := service.sessionOpts.ttl(true); ttl != 2*time.Hour {
+		t.Errorf("branca remember-me ttl = %v, want fallback to refresh expiry", ttl)
+	}
+	if ttl := (SessionOptions{}).ttl(false); ttl != 7*24*time.Hour {
+		t.Errorf("zero options ttl = %v, want 7 days", ttl)
+	}
+}
+
+func TestDatabaseTokenStore_SessionExpiry(t *testing.T) {
+	db := newTestSQLiteAuthDB(t)
+	ctx := context.Background()
+	if err := db.Exec(ctx, "INSERT INTO users (id, email, password) VALUES ('u-1', 'ana@example.com', 'hash')"); err != nil {
+		t.Fatal(err)
+	}
+	store := NewDatabaseTokenStore(db)
+
+	sessionCap := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+	if err := store.SaveRefreshToken(ctx, &RefreshToken{UserID: "u-1", TokenHash: "r1", SessionID: "sid-1", ExpiresAt: time.Now().Add(time.Hour), RememberMe: true, SessionExpiresAt: &sessionCap}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SaveRefreshToken(ctx, &RefreshToken{UserID: "u-1", TokenHash: "r2", SessionID: "sid-2", ExpiresAt: time.Now().Add(time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+
+	r1, err := store.FindRefreshToken(ctx, "r1")
+	if err != nil || !r1.RememberMe || r1.SessionExpiresAt == nil || !r1.SessionExpiresAt.Equal(sessionCap) {
+		t.Fatalf("r1 = %+v, %v", r1, err)
+	}
+	r2, err := store.FindRefreshToken(ctx, "r2")
+	if err != nil || r2.RememberMe || r2.SessionExpiresAt != nil {
+		t.Errorf("r2 = %+v, %v", r2, err)
+	}
+}
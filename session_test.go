@@ -0,0 +1,210 @@
+package dim
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newTestSessionService(t *testing.T, userStore AuthUserStore, tokenStore TokenStore, blocklist TokenBlocklist) *AuthService {
+	t.Helper()
+	service, err := NewAuthService(userStore, tokenStore, blocklist, &JWTConfig{
+		HMACSecret:         "test-secret",
+		SigningMethod:      "HS256",
+		AccessTokenExpiry:  15 * time.Minute,
+		RefreshTokenExpiry: 7 * 24 * time.Hour,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return service
+}
+
+func newTestSessionUserStore() *MockUserStore {
+	userStore := NewMockUserStore()
+	hashedPassword, _ := HashPassword("ValidPass123!")
+	userStore.AddUser(&MockUser{ID: "1", Email: "test@example.com", Password: hashedPassword})
+	return userStore
+}
+
+func TestAuthService_Sessions(t *testing.T) {
+	tokenStore := NewMockTokenStore()
+	blocklist := &ttlBlocklist{InMemoryBlocklist: NewInMemoryBlocklist(), ttls: map[string]time.Duration{}}
+	service := newTestSessionService(t, newTestSessionUserStore(), tokenStore, blocklist)
+
+	laptop := WithClientInfo(context.Background(), ClientInfo{UserAgent: "Firefox", IPAddress: "10.0.0.1"})
+	phone := WithClientInfo(context.Background(), ClientInfo{UserAgent: "Safari", IPAddress: "10.0.0.2"})
+	_, laptopRefresh, err := service.Login(laptop, "test@example.com", "ValidPass123!")
+	if err != nil {
+		t.Fatal(err)
+	}
+	phoneAccess, _, err := service.Login(phone, "test@example.com", "ValidPass123!")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Refresh tanpa ClientInfo mempertahankan device session
+	if _, laptopRefresh, err = service.RefreshToken(context.Background(), laptopRefresh); err != nil {
+		t.Fatal(err)
+	}
+
+	sessions, err := service.Sessions(context.Background(), "1")
+	if err != nil || len(sessions) != 2 {
+		t.Fatalf("Sessions = %+v, %v", sessions, err)
+	}
+	devices := map[string]string{}
+	for _, session := range sessions {
+		devices[session.UserAgent] = session.IPAddress
+	}
+	if devices["Firefox"] != "10.0.0.1" || devices["Safari"] != "10.0.0.2" {
+		t.Errorf("unexpected devices: %v", devices)
+	}
+
+	var phoneSession *RefreshToken
+	for _, session := range sessions {
+		if session.UserAgent == "Safari" {
+			phoneSession = session
+		}
+	}
+	if err := service.RevokeSession(context.Background(), "2", phoneSession.ID); err == nil {
+		t.Error("expected session of another user to be rejected")
+	}
+	if err := service.RevokeSession(context.Background(), "1", phoneSession.ID); err != nil {
+		t.Fatalf("RevokeSession error: %v", err)
+	}
+	err = service.RevokeSession(context.Background(), "1", phoneSession.ID)
+	if appErr, ok := AsAppError(err); !ok || appErr.StatusCode != http.StatusNotFound {
+		t.Errorf("second RevokeSession error = %v, want 404", err)
+	}
+
+	claims, _ := service.tokenManager.VerifyToken(phoneAccess)
+	if revoked, _ := blocklist.IsRevoked(context.Background(), claims["sid"].(string)); !revoked {
+		t.Error("expected revoked session to be blocklisted")
+	}
+	if ttl := blocklist.ttls[claims["sid"].(string)]; ttl != 15*time.Minute {
+		t.Errorf("session blocklisted for %v, want the access token expiry", ttl)
+	}
+	if _, _, err := service.RefreshToken(context.Background(), laptopRefresh); err != nil {
+		t.Errorf("other session should stay valid: %v", err)
+	}
+}
+
+func TestAuthService_SessionHandlers(t *testing.T) {
+	tokenStore := NewMockTokenStore()
+	blocklist := NewInMemoryBlocklist()
+	service := newTestSessionService(t, newTestSessionUserStore(), tokenStore, blocklist)
+
+	router := NewRouter()
+	router.Use(ClientInfoMiddleware())
+	auth := RequireAuth(service.tokenManager, blocklist)
+	router.Get("/auth/sessions", service.SessionsHandler(), auth)
+	router.Delete("/auth/sessions/{id}", service.RevokeSessionHandler(), auth)
+	router.Post("/auth/login", func(w http.ResponseWriter, r *http.Request) {
+		access, refresh, err := service.Login(r.Context(), "test@example.com", "ValidPass123!")
+		if err != nil {
+			InternalServerError(w, err.Error())
+			return
+		}
+		Json(w, http.StatusOK, TokenResponse{AccessToken: access, RefreshToken: refresh})
+	})
+
+	login := func(userAgent string) string {
+		req := httptest.NewRequest(http.MethodPost, "/auth/login", nil)
+		req.Header.Set("User-Agent", userAgent)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		var tokens TokenResponse
+		if err := json.NewDecoder(rec.Body).Decode(&tokens); err != nil {
+			t.Fatalf("login: %d %v", rec.Code, err)
+		}
+		return tokens.AccessToken
+	}
+	request := func(method, path, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	laptop := login("Firefox")
+	phone := login("Safari")
+
+	rec := request(http.MethodGet, "/auth/sessions", laptop)
+	var sessions []SessionInfo
+	if err := json.NewDecoder(rec.Body).Decode(&sessions); err != nil || len(sessions) != 2 {
+		t.Fatalf("sessions = %d %+v, %v", rec.Code, sessions, err)
+	}
+	var phoneID int64
+	for _, session := range sessions {
+		if session.Current != (session.UserAgent == "Firefox") {
+			t.Errorf("session %+v has wrong current flag", session)
+		}
+		if session.IPAddress == "" {
+			t.Errorf("session %+v has no IP address", session)
+		}
+		if session.UserAgent == "Safari" {
+			phoneID = session.ID
+		}
+	}
+
+	if rec := request(http.MethodDelete, "/auth/sessions/abc", laptop); rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid id status = %d", rec.Code)
+	}
+	if rec := request(http.MethodDelete, fmt.Sprintf("/auth/sessions/%d", phoneID), laptop); rec.Code != http.StatusNoContent {
+		t.Fatalf("revoke status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	if rec := request(http.MethodGet, "/auth/sessions", phone); rec.Code != http.StatusUnauthorized {
+		t.Errorf("revoked session status = %d, want 401", rec.Code)
+	}
+	if rec := request(http.MethodDelete, fmt.Sprintf("/auth/sessions/%d", phoneID), laptop); rec.Code != http.StatusNotFound {
+		t.Errorf("second revoke status = %d, want 404", rec.Code)
+	}
+}
+
+func TestDatabaseTokenStore_Sessions(t *testing.T) {
+	db := newTestSQLiteAuthDB(t)
+	ctx := context.Background()
+	if err := db.Exec(ctx, "INSERT INTO users (id, email, password) VALUES ('u-1', 'ana@example.com', 'hash')"); err != nil {
+		t.Fatal(err)
+	}
+	store := NewDatabaseTokenStore(db)
+
+	active := &RefreshToken{UserID: "u-1", TokenHash: "r1", SessionID: "sid-1", UserAgent: "Firefox", IPAddress: "10.0.0.1", ExpiresAt: time.Now().Add(time.Hour)}
+	expired := &RefreshToken{UserID: "u-1", TokenHash: "r2", SessionID: "sid-2", ExpiresAt: time.Now().Add(-time.Hour)}
+	revoked := &RefreshToken{UserID: "u-1", TokenHash: "r3", SessionID: "sid-3", ExpiresAt: time.Now().Add(time.Hour)}
+	for _, token := range []*RefreshToken{active, expired, revoked} {
+		if err := store.SaveRefreshToken(ctx, token); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.RevokeRefreshToken(ctx, "r3"); err != nil {
+		t.Fatal(err)
+	}
+
+	sessions, err := store.ListActiveSessions(ctx, "u-1")
+	if err != nil || len(sessions) != 1 {
+		t.Fatalf("ListActiveSessions = %+v, %v", sessions, err)
+	}
+	if s := sessions[0]; s.ID != active.ID || s.SessionID != "sid-1" || s.UserAgent != "Firefox" || s.IPAddress != "10.0.0.1" {
+		t.Errorf("unexpected session: %+v", s)
+	}
+
+	if err := store.RevokeSession(ctx, "u-2", active.ID); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("RevokeSession other user error = %v", err)
+	}
+	if err := store.RevokeSession(ctx, "u-1", active.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.RevokeSession(ctx, "u-1", active.ID); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("RevokeSession twice error = %v", err)
+	}
+	if sessions, _ := store.ListActiveSessions(ctx, "u-1"); len(sessions) != 0 {
+		t.Errorf("sessions after revoke = %+v", sessions)
+	}
+}
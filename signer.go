@@ -0,0 +1,122 @@
+package dim
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+var (
+	// ErrSignatureInvalid dikembalikan jika signature tidak cocok dengan payload.
+	ErrSignatureInvalid = errors.New("signature is invalid")
+	// ErrSignatureExpired dikembalikan jika signature valid tetapi sudah melewati waktu kadaluarsa.
+	ErrSignatureExpired = errors.New("signature has expired")
+)
+
+// Nama parameter query yang dipakai SignQuery dan VerifyQuery.
+const (
+	SignedPayloadParam   = "data"
+	SignedExpiresParam   = "expires"
+	SignedSignatureParam = "signature"
+)
+
+// PayloadSigner menandatangani payload dengan HMAC-SHA256 dan waktu kadaluarsa opsional.
+// Dipakai untuk URL yang hanya boleh dibuat oleh server, misal QRHandler agar endpoint
+// tidak bisa dipakai sebagai generator QR code terbuka.
+type PayloadSigner struct {
+	secret []byte
+	now    func() time.Time
+}
+
+// NewPayloadSigner membuat signer dengan secret HMAC.
+//
+// Parameters:
+//   - secret: secret HMAC, minimal 32 karakter acak
+//
+// Returns:
+//   - *PayloadSigner: signer siap digunakan
+//
+// Example:
+//
+//	signer := dim.NewPayloadSigner(os.Getenv("QR_SIGNING_SECRET"))
+func NewPayloadSigner(secret string) *PayloadSigner {
+	return &PayloadSigner{secret: []byte(secret), now: time.Now}
+}
+
+// Sign menghasilkan signature base64url untuk payload.
+// expiresAt zero berarti signature tidak pernah kadaluarsa.
+func (s *PayloadSigner) Sign(payload string, expiresAt time.Time) string {
+	return base64.RawURLEncoding.EncodeToString(s.mac(payload, signedExpiry(expiresAt)))
+}
+
+// Verify memeriksa signature payload dengan perbandingan constant-time.
+//
+// Returns:
+//   - error: nil jika valid, ErrSignatureInvalid atau ErrSignatureExpired
+func (s *PayloadSigner) Verify(payload string, expiresAt time.Time, signature string) error {
+	return s.verify(payload, signedExpiry(expiresAt), signature)
+}
+
+// SignQuery menandatangani payload dan mengembalikan parameter query
+// data, expires, dan signature. ttl 0 berarti tidak kadaluarsa.
+//
+// Example:
+//
+//	q := signer.SignQuery("https://example.com/pay/INV-001", 15*time.Minute)
+//	src := "/qr?" + q.Encode()
+func (s *PayloadSigner) SignQuery(payload string, ttl time.Duration) url.Values {
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = s.now().Add(ttl)
+	}
+	expires := signedExpiry(expiresAt)
+
+	q := url.Values{}
+	q.Set(SignedPayloadParam, payload)
+	q.Set(SignedExpiresParam, strconv.FormatInt(expires, 10))
+	q.Set(SignedSignatureParam, base64.RawURLEncoding.EncodeToString(s.mac(payload, expires)))
+	return q
+}
+
+// VerifyQuery memverifikasi parameter hasil SignQuery dan mengembalikan payload.
+func (s *PayloadSigner) VerifyQuery(q url.Values) (string, error) {
+	expires, err := strconv.ParseInt(q.Get(SignedExpiresParam), 10, 64)
+	if err != nil {
+		return "", ErrSignatureInvalid
+	}
+	payload := q.Get(SignedPayloadParam)
+	if err := s.verify(payload, expires, q.Get(SignedSignatureParam)); err != nil {
+		return "", err
+	}
+	return payload, nil
+}
+
+func (s *PayloadSigner) verify(payload string, expires int64, signature string) error {
+	got, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(got, s.mac(payload, expires)) {
+		return ErrSignatureInvalid
+	}
+	if expires != 0 && s.now().Unix() > expires {
+		return ErrSignatureExpired
+	}
+	return nil
+}
+
+func (s *PayloadSigner) mac(payload string, expires int64) []byte {
+	h := hmac.New(sha256.New, s.secret)
+	h.Write([]byte(strconv.FormatInt(expires, 10)))
+	h.Write([]byte{0})
+	h.Write([]byte(payload))
+	return h.Sum(nil)
+}
+
+func signedExpiry(expiresAt time.Time) int64 {
+	if expiresAt.IsZero() {
+		return 0
+	}
+	return expiresAt.Unix()
+}
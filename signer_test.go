@@ -0,0 +1,75 @@
+package dim
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPayloadSigner_SignVerify(t *testing.T) {
+	signer := NewPayloadSigner("test-secret")
+	expiresAt := time.Now().Add(time.Hour)
+
+	sig := signer.Sign("hello", expiresAt)
+	if err := signer.Verify("hello", expiresAt, sig); err != nil {
+		t.Errorf("expected valid signature, got %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		payload   string
+		expiresAt time.Time
+		signature string
+	}{
+		{"tampered payload", "hellO", expiresAt, sig},
+		{"tampered expiry", "hello", expiresAt.Add(time.Hour), sig},
+		{"garbage signature", "hello", expiresAt, "!!"},
+		{"other secret", "hello", expiresAt, NewPayloadSigner("other").Sign("hello", expiresAt)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := signer.Verify(tt.payload, tt.expiresAt, tt.signature); !errors.Is(err, ErrSignatureInvalid) {
+				t.Errorf("expected ErrSignatureInvalid, got %v", err)
+			}
+		})
+	}
+}
+
+func TestPayloadSigner_Expiry(t *testing.T) {
+	signer := NewPayloadSigner("test-secret")
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	signer.now = func() time.Time { return now }
+
+	q := signer.SignQuery("https://example.com/pay", time.Minute)
+	if payload, err := signer.VerifyQuery(q); err != nil || payload != "https://example.com/pay" {
+		t.Fatalf("VerifyQuery = %q, %v", payload, err)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, err := signer.VerifyQuery(q); !errors.Is(err, ErrSignatureExpired) {
+		t.Errorf("expected ErrSignatureExpired, got %v", err)
+	}
+
+	// ttl 0 never expires
+	q = signer.SignQuery("forever", 0)
+	now = now.Add(24 * 365 * time.Hour)
+	if _, err := signer.VerifyQuery(q); err != nil {
+		t.Errorf("expected non-expiring signature, got %v", err)
+	}
+}
+
+func TestPayloadSigner_VerifyQueryInvalid(t *testing.T) {
+	signer := NewPayloadSigner("test-secret")
+
+	q := signer.SignQuery("data", time.Hour)
+	q.Set(SignedExpiresParam, "soon")
+	if _, err := signer.VerifyQuery(q); !errors.Is(err, ErrSignatureInvalid) {
+		t.Errorf("expected ErrSignatureInvalid for bad expires, got %v", err)
+	}
+
+	q = signer.SignQuery("data", time.Hour)
+	q.Del(SignedSignatureParam)
+	if _, err := signer.VerifyQuery(q); !errors.Is(err, ErrSignatureInvalid) {
+		t.Errorf("expected ErrSignatureInvalid for missing signature, got %v", err)
+	}
+}
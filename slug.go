@@ -0,0 +1,168 @@
+package dim
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// SlugMaxLength adalah panjang maksimum default slug yang dihasilkan Slug.
+const SlugMaxLength = 80
+
+// slugTransliterations memetakan huruf yang tidak terurai menjadi huruf ASCII + diakritik oleh NFKD.
+var slugTransliterations = map[rune]string{
+	'ß': "ss", 'æ': "ae", 'Æ': "ae", 'œ': "oe", 'Œ': "oe", 'ø': "o", 'Ø': "o",
+	'đ': "d", 'Đ': "d", 'ð': "d", 'Ð': "d", 'ł': "l", 'Ł': "l", 'þ': "th", 'Þ': "th",
+	'ı': "i", 'ħ': "h", 'Ħ': "h",
+}
+
+// slugSQLIdentifier membatasi nama tabel (opsional dengan schema) dan kolom untuk EnsureUniqueSlug.
+var slugSQLIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// Slug mengubah teks menjadi slug URL: huruf beraksen ditransliterasi ke ASCII ("Café" → "cafe"),
+// huruf kecil semua, karakter selain huruf/angka menjadi satu tanda "-", dan apostrof dihapus.
+// Slug yang melebihi panjang maksimum dipotong pada batas kata jika memungkinkan.
+//
+// Parameters:
+//   - text: teks sumber, misal judul artikel
+//   - maxLength: panjang maksimum opsional (default SlugMaxLength, <= 0 berarti tanpa batas)
+//
+// Returns:
+//   - string: slug, kosong jika teks tidak memiliki huruf atau angka yang dapat ditransliterasi
+//
+// Example:
+//
+//	dim.Slug("Résumé & Cover Letter: 2024 Edition!") // "resume-cover-letter-2024-edition"
+//	dim.Slug("Don't Panic", 4)                       // "dont"
+func Slug(text string, maxLength ...int) string {
+	limit := SlugMaxLength
+	if len(maxLength) > 0 {
+		limit = maxLength[0]
+	}
+
+	var b strings.Builder
+	pendingDash := false
+	write := func(s string) {
+		if pendingDash && b.Len() > 0 {
+			b.WriteByte('-')
+		}
+		pendingDash = false
+		b.WriteString(s)
+	}
+
+	for _, r := range norm.NFKD.String(text) {
+		switch {
+		case unicode.Is(unicode.Mn, r), r == '\'', r == '’':
+			// Diakritik dan apostrof dihapus tanpa memisahkan kata
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			write(string(unicode.ToLower(r)))
+		default:
+			if mapped, ok := slugTransliterations[r]; ok {
+				write(mapped)
+				continue
+			}
+			pendingDash = true
+		}
+	}
+
+	slug := b.String()
+	if limit > 0 && len(slug) > limit {
+		slug = slug[:limit]
+		// Potong pada batas kata terakhir jika tidak membuang lebih dari separuh slug
+		if i := strings.LastIndexByte(slug, '-'); i > limit/2 {
+			slug = slug[:i]
+		}
+		slug = strings.TrimRight(slug, "-")
+	}
+	return slug
+}
+
+// EnsureUniqueSlug mengembalikan base jika belum dipakai di table.column, atau base dengan
+// akhiran angka berikutnya ("post", "post-2", "post-3", ...) jika sudah ada.
+//
+// Dijalankan di dalam transaksi yang sama dengan INSERT/UPDATE baris agar pengecekan dan penulisan
+// atomik. Di PostgreSQL, transaksi mengambil advisory lock per table/column/base hingga commit, sehingga
+// request bersamaan untuk slug yang sama menunggu bergantian. Tetap pasang UNIQUE constraint pada
+// kolom sebagai pengaman akhir (misal untuk SQLite atau penulisan di luar helper ini).
+//
+// Parameters:
+//   - ctx: context untuk query
+//   - tx: transaksi aktif
+//   - table: nama tabel, boleh dengan schema (misal "blog.posts")
+//   - column: nama kolom slug
+//   - base: slug dasar, biasanya hasil Slug(title)
+//
+// Returns:
+//   - string: slug unik
+//   - error: jika nama tabel/kolom tidak valid, base kosong, atau query gagal
+//
+// Example:
+//
+//	err := db.WithTx(ctx, func(ctx context.Context, tx dim.Tx) error {
+//	    slug, err := dim.EnsureUniqueSlug(ctx, tx, "posts", "slug", dim.Slug(input.Title))
+//	    if err != nil {
+//	        return err
+//	    }
+//	    return tx.Exec(ctx, `INSERT INTO posts (title, slug) VALUES ($1, $2)`, input.Title, slug)
+//	})
+func EnsureUniqueSlug(ctx context.Context, tx Tx, table, column, base string) (string, error) {
+	if !slugSQLIdentifier.MatchString(table) {
+		return "", fmt.Errorf("invalid slug table name: %q", table)
+	}
+	if !slugSQLIdentifier.MatchString(column) || strings.Contains(column, ".") {
+		return "", fmt.Errorf("invalid slug column name: %q", column)
+	}
+	if base == "" {
+		return "", fmt.Errorf("slug base is required")
+	}
+
+	driver := txDriverName(tx)
+	if driver == "postgres" {
+		lockKey := table + "." + column + ":" + base
+		if err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, lockKey); err != nil {
+			return "", fmt.Errorf("failed to lock slug %s: %w", base, err)
+		}
+	}
+
+	rows, err := tx.Query(ctx, uniqueSlugQuery(driver, table, column), base, escapeLikePattern(base)+"-%")
+	if err != nil {
+		return "", fmt.Errorf("failed to query slugs: %w", err)
+	}
+	defer rows.Close()
+
+	taken := false
+	highest := 1
+	for rows.Next() {
+		var existing string
+		if err := rows.Scan(&existing); err != nil {
+			return "", fmt.Errorf("failed to scan slug: %w", err)
+		}
+		if existing == base {
+			taken = true
+			continue
+		}
+		suffix := strings.TrimPrefix(existing, base+"-")
+		if n, err := strconv.Atoi(suffix); err == nil && n > highest && strconv.Itoa(n) == suffix {
+			highest = n
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return "", fmt.Errorf("failed to query slugs: %w", err)
+	}
+
+	if !taken {
+		return base, nil
+	}
+	return base + "-" + strconv.Itoa(highest+1), nil
+}
+
+// uniqueSlugQuery membuat query slug yang sama dengan base atau berakhiran "-n" dengan klausa
+// ESCAPE sesuai driver.
+func uniqueSlugQuery(driver, table, column string) string {
+	return fmt.Sprintf(`SELECT %s FROM %s WHERE %s = $1 OR %s LIKE $2`, column, table, column, column) + likeEscapeClause(driver)
+}
@@ -0,0 +1,143 @@
+package dim
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestSlug(t *testing.T) {
+	tests := []struct {
+		text string
+		max  []int
+		want string
+	}{
+		{"Hello World", nil, "hello-world"},
+		{"  Résumé & Cover Letter: 2024 Edition!  ", nil, "resume-cover-letter-2024-edition"},
+		{"Don't Panic", nil, "dont-panic"},
+		{"Straße in Łódź", nil, "strasse-in-lodz"},
+		{"Ærøskøbing Þór", nil, "aeroskobing-thor"},
+		{"Crème brûlée --- naïve café", nil, "creme-brulee-naive-cafe"},
+		{"ＡＢＣ１２３", nil, "abc123"},
+		{"日本語", nil, ""},
+		{"Go 日本 Lang", nil, "go-lang"},
+		{"snake_case.and/slash", nil, "snake-case-and-slash"},
+		{"the quick brown fox jumps", []int{18}, "the-quick-brown"},
+		{"supercalifragilistic word", []int{10}, "supercalif"},
+		{"ab-cd", []int{3}, "ab"},
+		{strings.Repeat("a", 100), nil, strings.Repeat("a", SlugMaxLength)},
+		{strings.Repeat("a", 100), []int{0}, strings.Repeat("a", 100)},
+	}
+
+	for _, tt := range tests {
+		if got := Slug(tt.text, tt.max...); got != tt.want {
+			t.Errorf("Slug(%q, %v) = %q, want %q", tt.text, tt.max, got, tt.want)
+		}
+	}
+}
+
+func TestEnsureUniqueSlug(t *testing.T) {
+	db, err := NewSQLiteDatabase(DatabaseConfig{Database: ":memory:"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	ctx := context.Background()
+
+	if err := db.Exec(ctx, `CREATE TABLE posts (id INTEGER PRIMARY KEY, slug TEXT UNIQUE)`); err != nil {
+		t.Fatal(err)
+	}
+
+	insert := func(base string) string {
+		t.Helper()
+		var slug string
+		err := db.WithTx(ctx, func(ctx context.Context, tx Tx) error {
+			var err error
+			slug, err = EnsureUniqueSlug(ctx, tx, "posts", "slug", base)
+			if err != nil {
+				return err
+			}
+			return tx.Exec(ctx, `INSERT INTO posts (slug) VALUES ($1)`, slug)
+		})
+		if err != nil {
+			t.Fatalf("EnsureUniqueSlug(%q) error = %v", base, err)
+		}
+		return slug
+	}
+
+	for i, want := range []string{"hello", "hello-2", "hello-3"} {
+		if got := insert("hello"); got != want {
+			t.Errorf("insert #%d = %q, want %q", i+1, got, want)
+		}
+	}
+
+	// Slug lain yang berawalan sama atau berakhiran bukan angka tidak memengaruhi nomor
+	for _, slug := range []string{"hello-world", "hello-10x", "hello-07", "hello_9"} {
+		db.Exec(ctx, `INSERT INTO posts (slug) VALUES ($1)`, slug)
+	}
+	if got := insert("hello"); got != "hello-4" {
+		t.Errorf("insert after unrelated slugs = %q, want hello-4", got)
+	}
+
+	// Wildcard LIKE pada base di-escape
+	db.Exec(ctx, `INSERT INTO posts (slug) VALUES ('a_b'), ('axb-5')`)
+	if got := insert("a_b"); got != "a_b-2" {
+		t.Errorf("insert a_b = %q, want a_b-2", got)
+	}
+
+	if got := insert("fresh"); got != "fresh" {
+		t.Errorf("insert fresh = %q", got)
+	}
+}
+
+func TestEnsureUniqueSlug_InvalidInput(t *testing.T) {
+	tests := []struct {
+		table, column, base string
+		wantErr             string
+	}{
+		{"posts; DROP TABLE users", "slug", "a", "invalid slug table"},
+		{"posts", "slug = slug OR 1=1 --", "a", "invalid slug column"},
+		{"posts", "p.slug", "a", "invalid slug column"},
+		{"posts", "slug", "", "base is required"},
+	}
+	for _, tt := range tests {
+		_, err := EnsureUniqueSlug(context.Background(), nil, tt.table, tt.column, tt.base)
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("EnsureUniqueSlug(%q, %q) error = %v, want %q", tt.table, tt.column, err, tt.wantErr)
+		}
+	}
+}
+
+func TestUniqueSlugQuery_MySQL(t *testing.T) {
+	query, args, err := bindMySQL(uniqueSlugQuery("mysql", "posts", "slug"), []interface{}{"post", `post-%`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `SELECT slug FROM posts WHERE slug = ? OR slug LIKE ? ESCAPE '\\'`
+	if query != want || len(args) != 2 {
+		t.Errorf("query = %q %v, want %q", query, args, want)
+	}
+	if got := uniqueSlugQuery("sqlite", "posts", "slug"); !strings.HasSuffix(got, `ESCAPE '\'`) {
+		t.Errorf("sqlite query = %q", got)
+	}
+}
+
+func TestTxDriverName(t *testing.T) {
+	db, err := NewSQLiteDatabase(DatabaseConfig{Database: ":memory:"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	for _, d := range []Database{db, NewInstrumentedDatabase(db, DBInstrumentationConfig{})} {
+		d.WithTx(context.Background(), func(ctx context.Context, tx Tx) error {
+			if got := txDriverName(tx); got != "sqlite" {
+				t.Errorf("txDriverName(%T) = %q, want sqlite", tx, got)
+			}
+			return nil
+		})
+	}
+	if got := txDriverName(&MySQLTx{}); got != "mysql" {
+		t.Errorf("txDriverName(MySQLTx) = %q", got)
+	}
+}
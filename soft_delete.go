@@ -0,0 +1,118 @@
+package dim
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// DeletedAtColumn adalah nama kolom soft delete yang dipakai store bawaan dan AddSoftDeleteColumn.
+const DeletedAtColumn = "deleted_at"
+
+const withTrashedKey contextKey = "with_trashed"
+
+// WithTrashed mengembalikan context yang membuat finder store ikut mengembalikan row yang sudah
+// di-soft delete. Berguna untuk halaman admin atau sebelum Restore.
+//
+// Example:
+//
+//	user, err := userStore.FindByID(dim.WithTrashed(ctx), id)
+func WithTrashed(ctx context.Context) context.Context {
+	return context.WithValue(ctx, withTrashedKey, true)
+}
+
+// IsWithTrashed melaporkan apakah ctx dibuat dengan WithTrashed.
+func IsWithTrashed(ctx context.Context) bool {
+	trashed, _ := ctx.Value(withTrashedKey).(bool)
+	return trashed
+}
+
+// SoftDeleteScope mengembalikan kondisi WHERE "column IS NULL" untuk menyaring row yang sudah
+// di-soft delete, atau string kosong jika ctx dibuat dengan WithTrashed. Column boleh memakai
+// alias tabel (misal "u.deleted_at") dan harus berupa konstanta, bukan input user.
+//
+// Parameters:
+//   - ctx: context query
+//   - column: kolom soft delete, kosong berarti DeletedAtColumn
+//
+// Returns:
+//   - string: kondisi WHERE tanpa "AND"/"WHERE", atau "" jika tidak perlu disaring
+//
+// Example:
+//
+//	query := "SELECT id, title FROM posts WHERE author_id = $1"
+//	if scope := dim.SoftDeleteScope(ctx, ""); scope != "" {
+//	    query += " AND " + scope
+//	}
+func SoftDeleteScope(ctx context.Context, column string) string {
+	if IsWithTrashed(ctx) {
+		return ""
+	}
+	if column == "" {
+		column = DeletedAtColumn
+	}
+	return column + " IS NULL"
+}
+
+// softDeleteAnd menambahkan SoftDeleteScope ke query yang sudah memiliki klausa WHERE.
+func softDeleteAnd(ctx context.Context, query string) string {
+	if scope := SoftDeleteScope(ctx, ""); scope != "" {
+		return query + " AND " + scope
+	}
+	return query
+}
+
+// AddSoftDeleteColumn membuat migration yang menambahkan kolom deleted_at (nullable) beserta
+// index ke tabel yang sudah ada, untuk PostgreSQL, MySQL, dan SQLite. Down menghapus kolom tersebut.
+//
+// Parameters:
+//   - version: versi migration
+//   - table: nama tabel, boleh dengan schema (misal "blog.posts")
+//
+// Returns:
+//   - Migration: migration bernama "add_deleted_at_to_<table>"
+//
+// Example:
+//
+//	dim.Register(dim.AddSoftDeleteColumn(20260301090000, "posts"))
+func AddSoftDeleteColumn(version int64, table string) Migration {
+	index := "idx_" + strings.ReplaceAll(table, ".", "_") + "_" + DeletedAtColumn
+	return Migration{
+		Version: version,
+		Name:    "add_" + DeletedAtColumn + "_to_" + strings.ReplaceAll(table, ".", "_"),
+		Up: func(db Database) error {
+			if !slugSQLIdentifier.MatchString(table) {
+				return fmt.Errorf("invalid soft delete table name: %q", table)
+			}
+			var query string
+			switch db.DriverName() {
+			case "sqlite":
+				query = fmt.Sprintf(`
+					ALTER TABLE %s ADD COLUMN %s TIMESTAMP NULL;
+					CREATE INDEX IF NOT EXISTS %s ON %s(%s);
+				`, table, DeletedAtColumn, index, table, DeletedAtColumn)
+			case "mysql":
+				query = fmt.Sprintf(`ALTER TABLE %s ADD COLUMN %s DATETIME NULL, ADD INDEX %s (%s)`,
+					table, DeletedAtColumn, index, DeletedAtColumn)
+			default:
+				query = fmt.Sprintf(`
+					ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s TIMESTAMP NULL;
+					CREATE INDEX IF NOT EXISTS %s ON %s(%s);
+				`, table, DeletedAtColumn, index, table, DeletedAtColumn)
+			}
+			return db.Exec(context.Background(), query)
+		},
+		Down: func(db Database) error {
+			if !slugSQLIdentifier.MatchString(table) {
+				return fmt.Errorf("invalid soft delete table name: %q", table)
+			}
+			if db.DriverName() == "sqlite" {
+				// SQLite menolak DROP COLUMN pada kolom yang masih memiliki index
+				if err := db.Exec(context.Background(), "DROP INDEX IF EXISTS "+index); err != nil {
+					return err
+				}
+			}
+			return db.Exec(context.Background(), fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", table, DeletedAtColumn))
+		},
+	}
+}
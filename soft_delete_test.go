@@ -0,0 +1,89 @@
+package dim
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+)
+
+func TestSoftDeleteScope(t *testing.T) {
+	ctx := context.Background()
+	if got := SoftDeleteScope(ctx, ""); got != "deleted_at IS NULL" {
+		t.Errorf("SoftDeleteScope() = %q", got)
+	}
+	if got := SoftDeleteScope(ctx, "u.deleted_at"); got != "u.deleted_at IS NULL" {
+		t.Errorf("SoftDeleteScope(alias) = %q", got)
+	}
+	if IsWithTrashed(ctx) || !IsWithTrashed(WithTrashed(ctx)) {
+		t.Error("IsWithTrashed mismatch")
+	}
+	if got := SoftDeleteScope(WithTrashed(ctx), ""); got != "" {
+		t.Errorf("SoftDeleteScope(WithTrashed) = %q, want empty", got)
+	}
+}
+
+func TestDatabaseAuthUserStore_SoftDelete_SQLite(t *testing.T) {
+	db := newTestSQLiteAuthDB(t)
+	ctx := context.Background()
+	if err := db.Exec(ctx, "INSERT INTO users (id, email, password) VALUES ('u-1', 'ana@example.com', 'hash')"); err != nil {
+		t.Fatal(err)
+	}
+	store := NewDatabaseAuthUserStore(db)
+
+	if err := store.SoftDelete(ctx, "u-1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.FindByID(ctx, "u-1"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("FindByID(deleted) error = %v, want sql.ErrNoRows", err)
+	}
+	if _, err := store.FindByEmail(ctx, "ana@example.com"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("FindByEmail(deleted) error = %v, want sql.ErrNoRows", err)
+	}
+
+	user, err := store.FindByEmail(WithTrashed(ctx), "ana@example.com")
+	if err != nil || user.(*TokenUser).DeletedAt == nil {
+		t.Fatalf("FindByEmail(WithTrashed) = %+v, %v", user, err)
+	}
+
+	if err := store.Restore(ctx, "u-1"); err != nil {
+		t.Fatal(err)
+	}
+	user, err = store.FindByID(ctx, "u-1")
+	if err != nil || user.(*TokenUser).DeletedAt != nil {
+		t.Errorf("FindByID(restored) = %+v, %v", user, err)
+	}
+}
+
+func TestAddSoftDeleteColumn_SQLite(t *testing.T) {
+	db, err := NewSQLiteDatabase(DatabaseConfig{Database: ":memory:"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	ctx := context.Background()
+	if err := db.Exec(ctx, "CREATE TABLE posts (id TEXT PRIMARY KEY)"); err != nil {
+		t.Fatal(err)
+	}
+
+	migration := AddSoftDeleteColumn(10, "posts")
+	if migration.Name != "add_deleted_at_to_posts" {
+		t.Errorf("Name = %q", migration.Name)
+	}
+	if err := migration.Up(db); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+	if err := db.Exec(ctx, "INSERT INTO posts (id, deleted_at) VALUES ('p-1', CURRENT_TIMESTAMP)"); err != nil {
+		t.Fatalf("insert deleted_at: %v", err)
+	}
+	if err := migration.Down(db); err != nil {
+		t.Fatalf("Down: %v", err)
+	}
+	if err := db.Exec(ctx, "SELECT deleted_at FROM posts"); err == nil {
+		t.Error("deleted_at still present after Down")
+	}
+
+	if err := AddSoftDeleteColumn(11, "posts; DROP TABLE posts").Up(db); err == nil {
+		t.Error("expected error for invalid table name")
+	}
+}
@@ -0,0 +1,400 @@
+package dim
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrBatchRowNotFound adalah error row pada UpdateBatch atau DeleteBatch ketika ID tidak ditemukan.
+var ErrBatchRowNotFound = errors.New("batch row not found")
+
+// BatchRowError adalah kegagalan satu row pada operasi batch store.
+type BatchRowError struct {
+	// Index adalah posisi row pada slice input.
+	Index int
+	Err   error
+}
+
+func (e BatchRowError) Error() string {
+	return fmt.Sprintf("row %d: %v", e.Index, e.Err)
+}
+
+func (e BatchRowError) Unwrap() error {
+	return e.Err
+}
+
+// BatchError dikembalikan oleh operasi batch store ketika sebagian row gagal. Row lain tetap
+// tersimpan; gunakan errors.As untuk membaca kegagalan per row.
+//
+// Example:
+//
+//	err := userStore.CreateBatch(ctx, users)
+//	var batchErr *dim.BatchError
+//	if errors.As(err, &batchErr) {
+//	    for _, row := range batchErr.Rows {
+//	        log.Printf("baris %d gagal diimpor: %v", row.Index+1, row.Err)
+//	    }
+//	}
+type BatchError struct {
+	// Total adalah jumlah row pada input.
+	Total int
+	// Rows berisi row yang gagal, urut sesuai Index.
+	Rows []BatchRowError
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("%d of %d batch rows failed, first: %v", len(e.Rows), e.Total, e.Rows[0])
+}
+
+// Unwrap mengembalikan error semua row sehingga errors.Is dapat memeriksa ErrBatchRowNotFound.
+func (e *BatchError) Unwrap() []error {
+	errs := make([]error, len(e.Rows))
+	for i, row := range e.Rows {
+		errs[i] = row
+	}
+	return errs
+}
+
+// Failed mengembalikan error row pada index, atau nil jika row tersebut berhasil.
+func (e *BatchError) Failed(index int) error {
+	for _, row := range e.Rows {
+		if row.Index == index {
+			return row.Err
+		}
+	}
+	return nil
+}
+
+// batchResult mengubah kegagalan row menjadi *BatchError, atau nil jika semua row berhasil.
+func batchResult(total int, rows []BatchRowError) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	return &BatchError{Total: total, Rows: rows}
+}
+
+// runBatchRows menjalankan fn untuk setiap row di satu transaksi, masing-masing di SAVEPOINT
+// sendiri, sehingga row yang gagal hanya membatalkan dirinya. Dipakai oleh semua driver dan
+// sebagai fallback jalur cepat PostgreSQL untuk menentukan row mana yang gagal.
+func runBatchRows(ctx context.Context, db Database, total int, fn func(ctx context.Context, i int) error) error {
+	var failed []BatchRowError
+	err := InTx(ctx, db, func(ctx context.Context) error {
+		for i := 0; i < total; i++ {
+			err := InTx(ctx, db, func(ctx context.Context) error { return fn(ctx, i) })
+			if err == nil {
+				continue
+			}
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			failed = append(failed, BatchRowError{Index: i, Err: err})
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return batchResult(total, failed)
+}
+
+// pgxBatchTx menjalankan fn pada pgx.Tx untuk jalur cepat PostgreSQL (CopyFrom dan pgx.Batch).
+// Jika ctx sudah berada di transaksi db, fn dijalankan di SAVEPOINT agar kegagalan tidak
+// membatalkan transaksi luar. Mengembalikan false jika db (setelah dilepas dari pembungkus
+// seperti InstrumentedDatabase) bukan *PostgresDatabase.
+func pgxBatchTx(ctx context.Context, db Database, fn func(tx pgx.Tx) error) (bool, error) {
+	pg, ok := unwrapDatabase(db).(*PostgresDatabase)
+	if !ok {
+		return false, nil
+	}
+
+	var tx pgx.Tx
+	if outer, ok := contextTx(ctx, pg); ok {
+		pgTx, ok := outer.(*PostgresTx)
+		if !ok {
+			return false, nil
+		}
+		nested, err := pgTx.PgxTx().Begin(ctx)
+		if err != nil {
+			return true, err
+		}
+		tx = nested
+	} else {
+		begun, err := pg.Begin(ctx)
+		if err != nil {
+			return true, err
+		}
+		tx = begun.(*PostgresTx).PgxTx()
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+
+	if err := fn(tx); err != nil {
+		return true, err
+	}
+	return true, tx.Commit(ctx)
+}
+
+// sendPgxBatch mengirim batch dan mengembalikan jumlah row yang terpengaruh per statement.
+func sendPgxBatch(ctx context.Context, tx pgx.Tx, batch *pgx.Batch) ([]int64, error) {
+	results := tx.SendBatch(ctx, batch)
+	affected := make([]int64, batch.Len())
+	for i := range affected {
+		tag, err := results.Exec()
+		if err != nil {
+			results.Close()
+			return nil, err
+		}
+		affected[i] = tag.RowsAffected()
+	}
+	return affected, results.Close()
+}
+
+// notFoundRows mengubah statement yang tidak mengenai row apa pun menjadi ErrBatchRowNotFound.
+func notFoundRows(affected []int64) []BatchRowError {
+	var failed []BatchRowError
+	for i, n := range affected {
+		if n == 0 {
+			failed = append(failed, BatchRowError{Index: i, Err: ErrBatchRowNotFound})
+		}
+	}
+	return failed
+}
+
+// CreateBatch menyimpan banyak user sekaligus, misal untuk job impor. ID kosong diisi UUID baru,
+// CreatedAt dan UpdatedAt diisi waktu sekarang; Password harus sudah di-hash. Di PostgreSQL
+// semua row dikirim dengan COPY; jika ada row yang gagal (misal email duplikat), batch diulang
+// per row agar row yang valid tetap tersimpan.
+//
+// Parameters:
+//   - ctx: context request; jika berada di InTx, batch ikut transaksi tersebut
+//   - users: user yang akan disimpan
+//
+// Returns:
+//   - error: *BatchError jika sebagian row gagal, atau error lain jika batch tidak dapat dijalankan
+func (s *DatabaseAuthUserStore) CreateBatch(ctx context.Context, users []*User) error {
+	if len(users) == 0 {
+		return nil
+	}
+	now := time.Now().UTC().Truncate(time.Second)
+	for _, user := range users {
+		if user.ID == "" {
+			user.ID = NewUuid().String()
+		}
+		user.CreatedAt, user.UpdatedAt = now, now
+		user.Version = 1
+	}
+
+	handled, err := pgxBatchTx(ctx, s.db, func(tx pgx.Tx) error {
+		_, err := tx.CopyFrom(ctx, pgx.Identifier{"users"},
+			[]string{"id", "email", "name", "password", "created_at", "updated_at"},
+			pgx.CopyFromSlice(len(users), func(i int) ([]any, error) {
+				u := users[i]
+				return []any{u.ID, u.Email, u.Name, u.Password, u.CreatedAt, u.UpdatedAt}, nil
+			}))
+		return err
+	})
+	if handled && err == nil {
+		return nil
+	}
+
+	query := s.db.Rebind(`INSERT INTO users (id, email, name, password, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6)`)
+	return runBatchRows(ctx, s.db, len(users), func(ctx context.Context, i int) error {
+		u := users[i]
+		return s.db.Exec(ctx, query, u.ID, u.Email, u.Name, u.Password, u.CreatedAt, u.UpdatedAt)
+	})
+}
+
+// UpdateBatch memperbarui email, name, dan password banyak user berdasarkan ID, mengisi UpdatedAt,
+// dan menaikkan version di database tanpa memeriksa Version (gunakan UpdateWithVersion untuk
+// edit interaktif). User yang tidak ditemukan (termasuk yang di-soft delete) dilaporkan sebagai
+// ErrBatchRowNotFound. Di PostgreSQL semua UPDATE dikirim dalam satu pgx.Batch.
+//
+// Returns:
+//   - error: *BatchError jika sebagian row gagal, atau error lain jika batch tidak dapat dijalankan
+func (s *DatabaseAuthUserStore) UpdateBatch(ctx context.Context, users []*User) error {
+	if len(users) == 0 {
+		return nil
+	}
+	now := time.Now().UTC().Truncate(time.Second)
+	for _, user := range users {
+		user.UpdatedAt = now
+	}
+	query := `UPDATE users SET email = $1, name = $2, password = $3, updated_at = $4, version = version + 1 WHERE id = $5 AND deleted_at IS NULL`
+
+	var failed []BatchRowError
+	handled, err := pgxBatchTx(ctx, s.db, func(tx pgx.Tx) error {
+		batch := &pgx.Batch{}
+		for _, u := range users {
+			batch.Queue(query, u.Email, u.Name, u.Password, u.UpdatedAt, u.ID)
+		}
+		affected, err := sendPgxBatch(ctx, tx, batch)
+		failed = notFoundRows(affected)
+		return err
+	})
+	if handled && err == nil {
+		return batchResult(len(users), failed)
+	}
+
+	exists := s.db.Rebind(`SELECT COUNT(*) FROM users WHERE id = $1 AND deleted_at IS NULL`)
+	return runBatchRows(ctx, s.db, len(users), func(ctx context.Context, i int) error {
+		u := users[i]
+		if err := requireBatchRow(ctx, s.db, exists, u.ID); err != nil {
+			return err
+		}
+		return s.db.Exec(ctx, s.db.Rebind(query), u.Email, u.Name, u.Password, u.UpdatedAt, u.ID)
+	})
+}
+
+// DeleteBatch menghapus permanen banyak user berdasarkan ID, misal untuk membersihkan hasil impor.
+// Gunakan SoftDelete untuk penghapusan yang dapat di-Restore. ID yang tidak ditemukan dilaporkan
+// sebagai ErrBatchRowNotFound.
+//
+// Returns:
+//   - error: *BatchError jika sebagian row gagal, atau error lain jika batch tidak dapat dijalankan
+func (s *DatabaseAuthUserStore) DeleteBatch(ctx context.Context, ids []string) error {
+	return deleteBatch(ctx, s.db, `DELETE FROM users WHERE id = $1`, `SELECT COUNT(*) FROM users WHERE id = $1`, ids)
+}
+
+// CreateBatch menyimpan banyak refresh token sekaligus dan mengisi ID serta CreatedAt setiap
+// token. Di PostgreSQL semua INSERT dikirim dalam satu pgx.Batch; jika ada row yang gagal
+// (misal user_id tidak ada), batch diulang per row agar row yang valid tetap tersimpan.
+//
+// Returns:
+//   - error: *BatchError jika sebagian row gagal, atau error lain jika batch tidak dapat dijalankan
+func (s *DatabaseTokenStore) CreateBatch(ctx context.Context, tokens []*RefreshToken) error {
+	if len(tokens) == 0 {
+		return nil
+	}
+	now := time.Now().UTC().Truncate(time.Second)
+	query := `INSERT INTO refresh_tokens (user_id, token_hash, session_id, user_agent, ip_address, expires_at, created_at, remember_me, session_expires_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		 RETURNING id, created_at`
+	args := func(t *RefreshToken) []any {
+		return []any{t.UserID, t.TokenHash, t.SessionID, t.UserAgent, t.IPAddress, t.ExpiresAt.UTC().Truncate(time.Second), now, t.RememberMe, utcTimePtr(t.SessionExpiresAt)}
+	}
+
+	ids := make([]int64, len(tokens))
+	created := make([]time.Time, len(tokens))
+	handled, err := pgxBatchTx(ctx, s.db, func(tx pgx.Tx) error {
+		batch := &pgx.Batch{}
+		for _, t := range tokens {
+			batch.Queue(query, args(t)...)
+		}
+		results := tx.SendBatch(ctx, batch)
+		for i := range tokens {
+			if err := results.QueryRow().Scan(&ids[i], &created[i]); err != nil {
+				results.Close()
+				return err
+			}
+		}
+		return results.Close()
+	})
+	if handled && err == nil {
+		for i, t := range tokens {
+			t.ID, t.CreatedAt = ids[i], created[i]
+		}
+		return nil
+	}
+
+	return runBatchRows(ctx, s.db, len(tokens), func(ctx context.Context, i int) error {
+		t := tokens[i]
+		return s.db.QueryRow(ctx, s.db.Rebind(query), args(t)...).Scan(&t.ID, &t.CreatedAt)
+	})
+}
+
+// UpdateBatch memperbarui user_agent, ip_address, expires_at, dan revoked_at banyak refresh
+// token berdasarkan ID. ID yang tidak ditemukan dilaporkan sebagai ErrBatchRowNotFound.
+// Di PostgreSQL semua UPDATE dikirim dalam satu pgx.Batch.
+//
+// Returns:
+//   - error: *BatchError jika sebagian row gagal, atau error lain jika batch tidak dapat dijalankan
+func (s *DatabaseTokenStore) UpdateBatch(ctx context.Context, tokens []*RefreshToken) error {
+	if len(tokens) == 0 {
+		return nil
+	}
+	query := `UPDATE refresh_tokens SET user_agent = $1, ip_address = $2, expires_at = $3, revoked_at = $4 WHERE id = $5`
+	args := func(t *RefreshToken) []any {
+		var revokedAt *time.Time
+		if t.RevokedAt != nil {
+			utc := t.RevokedAt.UTC().Truncate(time.Second)
+			revokedAt = &utc
+		}
+		return []any{t.UserAgent, t.IPAddress, t.ExpiresAt.UTC().Truncate(time.Second), revokedAt, t.ID}
+	}
+
+	var failed []BatchRowError
+	handled, err := pgxBatchTx(ctx, s.db, func(tx pgx.Tx) error {
+		batch := &pgx.Batch{}
+		for _, t := range tokens {
+			batch.Queue(query, args(t)...)
+		}
+		affected, err := sendPgxBatch(ctx, tx, batch)
+		failed = notFoundRows(affected)
+		return err
+	})
+	if handled && err == nil {
+		return batchResult(len(tokens), failed)
+	}
+
+	exists := s.db.Rebind(`SELECT COUNT(*) FROM refresh_tokens WHERE id = $1`)
+	return runBatchRows(ctx, s.db, len(tokens), func(ctx context.Context, i int) error {
+		t := tokens[i]
+		if err := requireBatchRow(ctx, s.db, exists, t.ID); err != nil {
+			return err
+		}
+		return s.db.Exec(ctx, s.db.Rebind(query), args(t)...)
+	})
+}
+
+// DeleteBatch menghapus banyak refresh token berdasarkan ID. ID yang tidak ditemukan dilaporkan
+// sebagai ErrBatchRowNotFound.
+//
+// Returns:
+//   - error: *BatchError jika sebagian row gagal, atau error lain jika batch tidak dapat dijalankan
+func (s *DatabaseTokenStore) DeleteBatch(ctx context.Context, ids []int64) error {
+	return deleteBatch(ctx, s.db, `DELETE FROM refresh_tokens WHERE id = $1`, `SELECT COUNT(*) FROM refresh_tokens WHERE id = $1`, ids)
+}
+
+// deleteBatch adalah implementasi DeleteBatch bersama: pgx.Batch di PostgreSQL, per row di driver lain.
+func deleteBatch[ID any](ctx context.Context, db Database, query, exists string, ids []ID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	var failed []BatchRowError
+	handled, err := pgxBatchTx(ctx, db, func(tx pgx.Tx) error {
+		batch := &pgx.Batch{}
+		for _, id := range ids {
+			batch.Queue(query, id)
+		}
+		affected, err := sendPgxBatch(ctx, tx, batch)
+		failed = notFoundRows(affected)
+		return err
+	})
+	if handled && err == nil {
+		return batchResult(len(ids), failed)
+	}
+
+	return runBatchRows(ctx, db, len(ids), func(ctx context.Context, i int) error {
+		if err := requireBatchRow(ctx, db, db.Rebind(exists), ids[i]); err != nil {
+			return err
+		}
+		return db.Exec(ctx, db.Rebind(query), ids[i])
+	})
+}
+
+// requireBatchRow mengembalikan ErrBatchRowNotFound jika query COUNT(*) untuk id bernilai nol.
+// Dipakai driver yang tidak melaporkan jumlah row terpengaruh lewat Database.Exec.
+func requireBatchRow(ctx context.Context, db Database, query string, id any) error {
+	var count int
+	if err := db.QueryRow(ctx, query, id).Scan(&count); err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrBatchRowNotFound
+	}
+	return nil
+}
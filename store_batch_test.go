@@ -0,0 +1,147 @@
+package dim
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDatabaseAuthUserStore_Batch_SQLite(t *testing.T) {
+	db := newTestSQLiteAuthDB(t)
+	ctx := context.Background()
+	store := NewDatabaseAuthUserStore(db)
+
+	users := []*User{
+		{ID: "u-1", Email: "ana@example.com", Name: "Ana", Password: "hash"},
+		{Email: "budi@example.com", Name: "Budi", Password: "hash"},
+		{ID: "u-3", Email: "ana@example.com", Password: "hash"}, // email duplikat
+	}
+	err := store.CreateBatch(ctx, users)
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) || batchErr.Total != 3 || len(batchErr.Rows) != 1 || batchErr.Rows[0].Index != 2 {
+		t.Fatalf("CreateBatch error = %v", err)
+	}
+	if batchErr.Failed(2) == nil || batchErr.Failed(0) != nil {
+		t.Errorf("Failed() mismatch: %v", batchErr.Rows)
+	}
+	if users[1].ID == "" || users[1].CreatedAt.IsZero() {
+		t.Errorf("generated fields not set: %+v", users[1])
+	}
+	if _, total, _ := store.List(ctx, ListUsersQuery{}); total != 2 {
+		t.Errorf("users stored = %d, want 2", total)
+	}
+
+	users[0].Name = "Ana Maria"
+	err = store.UpdateBatch(ctx, []*User{users[0], {ID: "missing", Email: "x@example.com"}})
+	if !errors.As(err, &batchErr) || len(batchErr.Rows) != 1 || batchErr.Rows[0].Index != 1 || !errors.Is(err, ErrBatchRowNotFound) {
+		t.Fatalf("UpdateBatch error = %v", err)
+	}
+	list, _, _ := store.List(ctx, ListUsersQuery{Filters: []FilterCondition{{Field: "id", Op: FilterOpEq, Values: []string{"u-1"}}}})
+	if len(list) != 1 || list[0].Name != "Ana Maria" {
+		t.Errorf("updated user = %+v", list)
+	}
+
+	if err := store.DeleteBatch(ctx, []string{"u-1", users[1].ID}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.DeleteBatch(ctx, []string{"u-1"}); !errors.Is(err, ErrBatchRowNotFound) {
+		t.Errorf("DeleteBatch(missing) error = %v", err)
+	}
+	if err := store.CreateBatch(ctx, nil); err != nil {
+		t.Errorf("CreateBatch(nil) error = %v", err)
+	}
+}
+
+func TestDatabaseTokenStore_Batch_SQLite(t *testing.T) {
+	db := newTestSQLiteAuthDB(t)
+	ctx := context.Background()
+	if err := db.Exec(ctx, "INSERT INTO users (id, email, password) VALUES ('u-1', 'ana@example.com', 'hash')"); err != nil {
+		t.Fatal(err)
+	}
+	store := NewDatabaseTokenStore(db)
+
+	expires := time.Now().Add(time.Hour)
+	tokens := []*RefreshToken{
+		{UserID: "u-1", TokenHash: "h1", ExpiresAt: expires},
+		{UserID: "u-1", TokenHash: "h2", ExpiresAt: expires},
+	}
+	if err := store.CreateBatch(ctx, tokens); err != nil {
+		t.Fatal(err)
+	}
+	if tokens[0].ID == 0 || tokens[1].ID == tokens[0].ID || tokens[1].CreatedAt.IsZero() {
+		t.Fatalf("tokens = %+v, %+v", tokens[0], tokens[1])
+	}
+
+	revoked := time.Now()
+	tokens[1].RevokedAt = &revoked
+	if err := store.UpdateBatch(ctx, tokens); err != nil {
+		t.Fatal(err)
+	}
+	found, err := store.FindRefreshToken(ctx, "h2")
+	if err != nil || found.RevokedAt == nil {
+		t.Errorf("FindRefreshToken = %+v, %v", found, err)
+	}
+
+	err = store.DeleteBatch(ctx, []int64{tokens[0].ID, 999})
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) || batchErr.Failed(1) != ErrBatchRowNotFound {
+		t.Fatalf("DeleteBatch error = %v", err)
+	}
+	if _, err := store.FindRefreshToken(ctx, "h1"); err == nil {
+		t.Error("h1 still present after DeleteBatch")
+	}
+}
+
+func TestDatabaseAuthUserStore_Batch_InTx(t *testing.T) {
+	db := newTestSQLiteAuthDB(t)
+	store := NewDatabaseAuthUserStore(db)
+
+	// Batch ikut transaksi luar: rollback membatalkan row yang berhasil
+	err := InTx(context.Background(), db, func(ctx context.Context) error {
+		if err := store.CreateBatch(ctx, []*User{{Email: "ana@example.com", Password: "hash"}}); err != nil {
+			return err
+		}
+		return errors.New("abort import")
+	})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if _, total, _ := store.List(context.Background(), ListUsersQuery{}); total != 0 {
+		t.Errorf("users stored = %d, want 0", total)
+	}
+}
+
+func TestDatabaseAuthUserStore_Batch_Postgres(t *testing.T) {
+	db := newTestPostgresDB(t)
+	ctx := context.Background()
+	migrations := append(GetUserMigrations(), GetTokenMigrations()...)
+	for i := len(migrations) - 1; i >= 0; i-- {
+		migrations[i].Down(db)
+	}
+	db.Exec(ctx, "DROP TABLE IF EXISTS migrations")
+	if err := RunMigrations(db, migrations); err != nil {
+		t.Fatalf("RunMigrations: %v", err)
+	}
+	store := NewDatabaseAuthUserStore(db)
+
+	// Jalur COPY
+	if err := store.CreateBatch(ctx, []*User{{Email: "ana@example.com", Password: "hash"}, {Email: "budi@example.com", Password: "hash"}}); err != nil {
+		t.Fatal(err)
+	}
+	// COPY gagal karena email duplikat, fallback per row
+	users := []*User{{Email: "citra@example.com", Password: "hash"}, {Email: "ana@example.com", Password: "hash"}}
+	err := store.CreateBatch(ctx, users)
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) || len(batchErr.Rows) != 1 || batchErr.Rows[0].Index != 1 {
+		t.Fatalf("CreateBatch error = %v", err)
+	}
+
+	err = store.UpdateBatch(ctx, []*User{users[0], {ID: NewUuid().String(), Email: "x@example.com"}})
+	if !errors.As(err, &batchErr) || batchErr.Failed(1) != ErrBatchRowNotFound {
+		t.Errorf("UpdateBatch error = %v", err)
+	}
+	if err := store.DeleteBatch(ctx, []string{users[0].ID}); err != nil {
+		t.Errorf("DeleteBatch error = %v", err)
+	}
+}
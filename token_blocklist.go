@@ -3,6 +3,8 @@ package dim
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/atfromhome/goreus/pkg/cache"
@@ -20,6 +22,37 @@ type TokenBlocklist interface {
 	IsRevoked(ctx context.Context, identifier string) (bool, error)
 }
 
+// BatchTokenBlocklist adalah TokenBlocklist yang dapat memeriksa beberapa identifier sekaligus,
+// sehingga RequireAuth cukup satu lookup untuk sid dan jti access token.
+type BatchTokenBlocklist interface {
+	TokenBlocklist
+
+	// AnyRevoked mengembalikan true jika salah satu identifier ada di daftar hitam.
+	AnyRevoked(ctx context.Context, identifiers ...string) (bool, error)
+}
+
+// anyRevoked memeriksa identifier yang tidak kosong, lewat AnyRevoked jika blocklist mendukungnya.
+func anyRevoked(ctx context.Context, blocklist TokenBlocklist, identifiers ...string) (bool, error) {
+	ids := make([]string, 0, len(identifiers))
+	for _, id := range identifiers {
+		if id != "" {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return false, nil
+	}
+	if batch, ok := blocklist.(BatchTokenBlocklist); ok {
+		return batch.AnyRevoked(ctx, ids...)
+	}
+	for _, id := range ids {
+		if revoked, err := blocklist.IsRevoked(ctx, id); err != nil || revoked {
+			return revoked, err
+		}
+	}
+	return false, nil
+}
+
 // --- InMemory Implementation ---
 
 // InMemoryBlocklist implementasi TokenBlocklist menggunakan goreus/cache.
@@ -60,6 +93,16 @@ func (m *InMemoryBlocklist) IsRevoked(ctx context.Context, identifier string) (b
 	return true, nil
 }
 
+// AnyRevoked mengimplementasikan BatchTokenBlocklist.
+func (m *InMemoryBlocklist) AnyRevoked(ctx context.Context, identifiers ...string) (bool, error) {
+	for _, id := range identifiers {
+		if revoked, _ := m.IsRevoked(ctx, id); revoked {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // --- Database Implementation ---
 
 // DatabaseBlocklist implementasi TokenBlocklist menggunakan database SQL.
@@ -89,6 +132,14 @@ func (p *DatabaseBlocklist) InitSchema(ctx context.Context) error {
 			);
 			CREATE INDEX IF NOT EXISTS idx_token_blocklist_expires_at ON token_blocklist(expires_at);
 		`
+	} else if p.db.DriverName() == "mysql" {
+		query = `
+			CREATE TABLE IF NOT EXISTS token_blocklist (
+				identifier VARCHAR(255) PRIMARY KEY,
+				expires_at DATETIME NOT NULL,
+				INDEX idx_token_blocklist_expires_at (expires_at)
+			) ENGINE=InnoDB
+		`
 	} else {
 		query = `
 			CREATE UNLOGGED TABLE IF NOT EXISTS token_blocklist (
@@ -101,9 +152,15 @@ func (p *DatabaseBlocklist) InitSchema(ctx context.Context) error {
 	return p.db.Exec(ctx, query)
 }
 
+// Invalidate menyimpan identifier; memanggil ulang untuk identifier yang sama memperbarui expires_at.
 func (p *DatabaseBlocklist) Invalidate(ctx context.Context, identifier string, expiresIn time.Duration) error {
-	query := p.db.Rebind(`INSERT INTO token_blocklist (identifier, expires_at) VALUES ($1, $2)`)
-	return p.db.Exec(ctx, query, identifier, time.Now().UTC().Add(expiresIn).Truncate(time.Second))
+	return InTx(ctx, p.db, func(ctx context.Context) error {
+		if err := p.db.Exec(ctx, p.db.Rebind(`DELETE FROM token_blocklist WHERE identifier = $1`), identifier); err != nil {
+			return err
+		}
+		query := p.db.Rebind(`INSERT INTO token_blocklist (identifier, expires_at) VALUES ($1, $2)`)
+		return p.db.Exec(ctx, query, identifier, time.Now().UTC().Add(expiresIn).Truncate(time.Second))
+	})
 }
 
 func (p *DatabaseBlocklist) IsRevoked(ctx context.Context, identifier string) (bool, error) {
@@ -122,6 +179,31 @@ func (p *DatabaseBlocklist) IsRevoked(ctx context.Context, identifier string) (b
 	return exists, nil
 }
 
+// AnyRevoked mengimplementasikan BatchTokenBlocklist dengan satu query.
+func (p *DatabaseBlocklist) AnyRevoked(ctx context.Context, identifiers ...string) (bool, error) {
+	if len(identifiers) == 0 {
+		return false, nil
+	}
+	placeholders := make([]string, len(identifiers))
+	args := make([]interface{}, len(identifiers))
+	for i, id := range identifiers {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+		args[i] = id
+	}
+	now := "NOW()"
+	if p.db.DriverName() == "sqlite" {
+		now = "CURRENT_TIMESTAMP"
+	}
+	query := p.db.Rebind(`SELECT EXISTS(SELECT 1 FROM token_blocklist WHERE identifier IN (` +
+		strings.Join(placeholders, ", ") + `) AND expires_at > ` + now + `)`)
+
+	var exists bool
+	if err := p.db.QueryRow(ctx, query, args...).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check blocklist: %w", err)
+	}
+	return exists, nil
+}
+
 // Cleanup menghapus token yang sudah expired dari database.
 func (p *DatabaseBlocklist) Cleanup(ctx context.Context) error {
 	var query string
@@ -132,3 +214,152 @@ func (p *DatabaseBlocklist) Cleanup(ctx context.Context) error {
 	}
 	return p.db.Exec(ctx, query)
 }
+
+// --- Redis Implementation ---
+
+// RedisBlocklist implementasi TokenBlocklist menggunakan Redis, sehingga pencabutan token
+// berlaku di semua replica. Setiap identifier disimpan sebagai key dengan TTL sisa umurnya,
+// jadi entry kadaluarsa dihapus oleh Redis tanpa perlu Cleanup.
+type RedisBlocklist struct {
+	client RedisCommander
+	prefix string
+}
+
+// NewRedisBlocklist membuat RedisBlocklist dengan prefix key "dim:blocklist:".
+//
+// Parameters:
+//   - client: *RedisClient atau client lain yang diadaptasi dengan RedisCommanderFunc
+//
+// Example:
+//
+//	rdb, _ := dim.NewRedisClient(cfg.Redis)
+//	blocklist := dim.NewRedisBlocklist(rdb)
+//	api.Use(dim.RequireAuth(jwtManager, blocklist))
+func NewRedisBlocklist(client RedisCommander) *RedisBlocklist {
+	return &RedisBlocklist{client: client, prefix: "dim:blocklist:"}
+}
+
+// WithKeyPrefix mengganti prefix key, misal jika beberapa aplikasi berbagi satu database Redis.
+func (r *RedisBlocklist) WithKeyPrefix(prefix string) *RedisBlocklist {
+	r.prefix = prefix
+	return r
+}
+
+// Invalidate menyimpan identifier dengan TTL expiresIn. expiresIn <= 0 tidak menyimpan apa pun
+// karena entry tersebut sudah kadaluarsa.
+func (r *RedisBlocklist) Invalidate(ctx context.Context, identifier string, expiresIn time.Duration) error {
+	ms := expiresIn.Milliseconds()
+	if ms <= 0 {
+		return nil
+	}
+	if _, err := r.client.Do(ctx, "SET", r.prefix+identifier, "1", "PX", strconv.FormatInt(ms, 10)); err != nil {
+		return fmt.Errorf("failed to invalidate token: %w", err)
+	}
+	return nil
+}
+
+func (r *RedisBlocklist) IsRevoked(ctx context.Context, identifier string) (bool, error) {
+	return r.AnyRevoked(ctx, identifier)
+}
+
+// AnyRevoked mengimplementasikan BatchTokenBlocklist dengan satu command EXISTS.
+func (r *RedisBlocklist) AnyRevoked(ctx context.Context, identifiers ...string) (bool, error) {
+	if len(identifiers) == 0 {
+		return false, nil
+	}
+	args := make([]string, 0, len(identifiers)+1)
+	args = append(args, "EXISTS")
+	for _, id := range identifiers {
+		args = append(args, r.prefix+id)
+	}
+	reply, err := r.client.Do(ctx, args...)
+	if err != nil {
+		return false, fmt.Errorf("failed to check blocklist: %w", err)
+	}
+	count, ok := reply.(int64)
+	if !ok {
+		return false, fmt.Errorf("failed to check blocklist: unexpected EXISTS reply %v", reply)
+	}
+	return count > 0, nil
+}
+
+// --- Cached Implementation ---
+
+// CachedBlocklist membungkus TokenBlocklist (misal DatabaseBlocklist) dengan cache lokal agar
+// RequireAuth tidak melakukan lookup ke storage pada setiap request. Hasil "tidak dicabut"
+// di-cache selama ttl, sehingga pencabutan dari instance lain terlihat paling lambat setelah ttl;
+// pencabutan lewat instance yang sama langsung berlaku.
+type CachedBlocklist struct {
+	inner   TokenBlocklist
+	ttl     time.Duration
+	entries *cache.InMemoryCache[string, time.Time] // identifier -> revoked until (zero = not revoked)
+}
+
+// NewCachedBlocklist membuat CachedBlocklist dengan kapasitas 100.000 identifier.
+//
+// Parameters:
+//   - inner: blocklist sumber kebenaran
+//   - ttl: lama hasil lookup di-cache, misal 5 detik
+//
+// Example:
+//
+//	blocklist := dim.NewCachedBlocklist(dim.NewDatabaseBlocklist(db), 5*time.Second)
+//	api.Use(dim.RequireAuth(jwtManager, blocklist))
+func NewCachedBlocklist(inner TokenBlocklist, ttl time.Duration) *CachedBlocklist {
+	return &CachedBlocklist{
+		inner:   inner,
+		ttl:     ttl,
+		entries: cache.NewInMemoryCache[string, time.Time](100000, ttl),
+	}
+}
+
+// Invalidate meneruskan ke blocklist sumber lalu mencatat identifier di cache lokal.
+func (c *CachedBlocklist) Invalidate(ctx context.Context, identifier string, expiresIn time.Duration) error {
+	if err := c.inner.Invalidate(ctx, identifier, expiresIn); err != nil {
+		return err
+	}
+	until := time.Now().UTC().Add(expiresIn)
+	c.entries.Set(ctx, identifier, until, cache.WithExpiresAt(until))
+	return nil
+}
+
+// IsRevoked mengimplementasikan TokenBlocklist.
+func (c *CachedBlocklist) IsRevoked(ctx context.Context, identifier string) (bool, error) {
+	return c.AnyRevoked(ctx, identifier)
+}
+
+// AnyRevoked memeriksa cache lebih dulu; identifier yang belum di-cache diperiksa ke blocklist
+// sumber dalam satu lookup jika sumber mengimplementasikan BatchTokenBlocklist.
+func (c *CachedBlocklist) AnyRevoked(ctx context.Context, identifiers ...string) (bool, error) {
+	now := time.Now().UTC()
+	var missing []string
+	for _, id := range identifiers {
+		until, ok := c.entries.Get(ctx, id)
+		if !ok {
+			missing = append(missing, id)
+			continue
+		}
+		if now.Before(until) {
+			return true, nil
+		}
+	}
+	if len(missing) == 0 {
+		return false, nil
+	}
+
+	revoked, err := anyRevoked(ctx, c.inner, missing...)
+	if err != nil {
+		return false, err
+	}
+	if revoked {
+		// Lookup batch tidak memberi tahu identifier mana yang dicabut, kecuali hanya ada satu
+		if len(missing) == 1 {
+			c.entries.Set(ctx, missing[0], now.Add(c.ttl))
+		}
+		return true, nil
+	}
+	for _, id := range missing {
+		c.entries.Set(ctx, id, time.Time{})
+	}
+	return false, nil
+}
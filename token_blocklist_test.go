@@ -0,0 +1,129 @@
+package dim
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// countingBlocklist menghitung lookup ke blocklist sumber.
+type countingBlocklist struct {
+	*InMemoryBlocklist
+	lookups int
+	err     error
+}
+
+func (c *countingBlocklist) AnyRevoked(ctx context.Context, identifiers ...string) (bool, error) {
+	c.lookups++
+	if c.err != nil {
+		return false, c.err
+	}
+	return c.InMemoryBlocklist.AnyRevoked(ctx, identifiers...)
+}
+
+func TestAnyRevoked_SkipsEmptyIdentifiers(t *testing.T) {
+	ctx := context.Background()
+	inner := &countingBlocklist{InMemoryBlocklist: NewInMemoryBlocklist()}
+	if revoked, err := anyRevoked(ctx, inner, "", ""); err != nil || revoked || inner.lookups != 0 {
+		t.Errorf("anyRevoked = %v, %v (lookups %d)", revoked, err, inner.lookups)
+	}
+
+	_ = inner.Invalidate(ctx, "jti-1", time.Minute)
+	if revoked, _ := anyRevoked(ctx, inner, "sid-1", "jti-1"); !revoked || inner.lookups != 1 {
+		t.Errorf("revoked = %v with %d lookups, want true with 1", revoked, inner.lookups)
+	}
+}
+
+func TestCachedBlocklist(t *testing.T) {
+	ctx := context.Background()
+	inner := &countingBlocklist{InMemoryBlocklist: NewInMemoryBlocklist()}
+	blocklist := NewCachedBlocklist(inner, time.Minute)
+
+	for range 3 {
+		if revoked, err := blocklist.AnyRevoked(ctx, "sid-1", "jti-1"); err != nil || revoked {
+			t.Fatalf("AnyRevoked = %v, %v", revoked, err)
+		}
+	}
+	if inner.lookups != 1 {
+		t.Errorf("lookups = %d, want 1 (negative result cached)", inner.lookups)
+	}
+
+	// Pencabutan lewat instance lain belum terlihat sampai cache kadaluarsa
+	_ = inner.InMemoryBlocklist.Invalidate(ctx, "jti-1", time.Minute)
+	if revoked, _ := blocklist.IsRevoked(ctx, "jti-1"); revoked {
+		t.Error("cached negative result should be served")
+	}
+
+	// Pencabutan lewat CachedBlocklist langsung berlaku
+	if err := blocklist.Invalidate(ctx, "sid-1", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if revoked, _ := blocklist.AnyRevoked(ctx, "sid-1", "jti-2"); !revoked {
+		t.Error("identifier invalidated through the cache should be revoked")
+	}
+}
+
+func TestCachedBlocklist_Error(t *testing.T) {
+	inner := &countingBlocklist{InMemoryBlocklist: NewInMemoryBlocklist(), err: errors.New("db down")}
+	blocklist := NewCachedBlocklist(inner, time.Minute)
+	if _, err := blocklist.IsRevoked(context.Background(), "jti-1"); err == nil {
+		t.Fatal("error from inner blocklist should be returned")
+	}
+	inner.err = nil
+	if _, err := blocklist.IsRevoked(context.Background(), "jti-1"); err != nil || inner.lookups != 2 {
+		t.Errorf("failed lookup should not be cached (lookups %d, err %v)", inner.lookups, err)
+	}
+}
+
+func TestDatabaseBlocklist_AnyRevoked(t *testing.T) {
+	ctx := context.Background()
+	blocklist := NewDatabaseBlocklist(newTestSQLiteAuthDB(t))
+
+	if err := blocklist.Invalidate(ctx, "jti-1", -time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if revoked, err := blocklist.AnyRevoked(ctx, "sid-1", "jti-1"); err != nil || revoked {
+		t.Fatalf("expired entry: AnyRevoked = %v, %v", revoked, err)
+	}
+
+	// Invalidate ulang untuk identifier yang sama memperbarui expires_at
+	if err := blocklist.Invalidate(ctx, "jti-1", time.Hour); err != nil {
+		t.Fatalf("second Invalidate should not fail: %v", err)
+	}
+	if revoked, err := blocklist.AnyRevoked(ctx, "sid-1", "jti-1"); err != nil || !revoked {
+		t.Errorf("AnyRevoked = %v, %v; want true", revoked, err)
+	}
+	if revoked, _ := blocklist.AnyRevoked(ctx, "sid-1", "jti-2"); revoked {
+		t.Error("unrelated identifiers should not be revoked")
+	}
+}
+
+func TestRequireAuth_RevokedJTI(t *testing.T) {
+	jwtManager, _ := NewJWTManager(&JWTConfig{
+		HMACSecret:        "test-secret",
+		SigningMethod:     "HS256",
+		AccessTokenExpiry: 15 * time.Minute,
+	})
+	revokedToken, _ := jwtManager.GenerateAccessToken("1", "test@example.com", "sid-123", nil)
+	otherToken, _ := jwtManager.GenerateAccessToken("1", "test@example.com", "sid-123", nil)
+
+	blocklist := NewInMemoryBlocklist()
+	claims, _ := jwtManager.VerifyToken(revokedToken)
+	_ = blocklist.Invalidate(context.Background(), claims["jti"].(string), time.Minute)
+
+	handler := RequireAuth(jwtManager, blocklist)(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	for token, want := range map[string]int{revokedToken: http.StatusUnauthorized, otherToken: http.StatusOK} {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set("Authorization", "Bearer "+token)
+		handler(w, r)
+		if w.Code != want {
+			t.Errorf("status = %d, want %d", w.Code, want)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package dim
+
+import (
+	"context"
+	"time"
+)
+
+// TokenCleanupTaskName adalah nama yang disarankan untuk task TokenCleanupTask di Scheduler.
+const TokenCleanupTaskName = "dim.token_cleanup"
+
+// TokenCleanupTask mengembalikan TaskFunc yang menghapus refresh token dan token reset password
+// yang kedaluwarsa lebih dari retention yang lalu (lihat TokenStore.DeleteExpired). Tanpa cleanup,
+// tabel refresh_tokens dan password_reset_tokens terus bertambah karena setiap login dan refresh
+// menyimpan baris baru.
+//
+// Parameters:
+//   - store: token store, misal DatabaseTokenStore
+//   - retention: lama token kedaluwarsa tetap disimpan, misal untuk investigasi; 0 berarti langsung dihapus
+//
+// Returns:
+//   - TaskFunc: task untuk Scheduler
+//
+// Example:
+//
+//	scheduler.Cron(dim.TokenCleanupTaskName, "@hourly", dim.TokenCleanupTask(tokenStore, 24*time.Hour))
+func TokenCleanupTask(store TokenStore, retention time.Duration) TaskFunc {
+	return func(ctx context.Context) error {
+		return store.DeleteExpired(ctx, time.Now().Add(-retention))
+	}
+}
@@ -1,6 +1,9 @@
 package dim
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 // TokenClaims represents decoded claims from any token type (JWT, Branca, etc.).
 // Defined as a type alias so it is directly interchangeable with map[string]interface{}.
@@ -22,3 +25,25 @@ type TokenManager interface {
 	GetTokenExpiry(tokenString string) (time.Time, error)
 	IsTokenExpired(tokenString string) (bool, error)
 }
+
+// RefreshTokenExpiryGenerator is implemented by token managers that can issue a refresh token
+// with an explicit expiry. AuthService uses it for remember-me sessions and sessions capped by
+// SessionOptions.MaxLifetime; other managers fall back to GenerateRefreshToken.
+type RefreshTokenExpiryGenerator interface {
+	GenerateRefreshTokenWithExpiry(userID, sessionID string, expiresAt time.Time) (string, error)
+}
+
+// ContextTokenVerifier is implemented by token managers whose verification can do I/O (such as
+// JWTManager fetching a remote JWKS). RequireAuth and OptionalAuth pass the request context to
+// it so the fetch is cancelled with the request; other managers fall back to VerifyToken.
+type ContextTokenVerifier interface {
+	VerifyTokenContext(ctx context.Context, tokenString string) (TokenClaims, error)
+}
+
+// verifyAccessToken verifies tokenString with ctx when tm supports it.
+func verifyAccessToken(ctx context.Context, tm TokenManager, tokenString string) (TokenClaims, error) {
+	if v, ok := tm.(ContextTokenVerifier); ok {
+		return v.VerifyTokenContext(ctx, tokenString)
+	}
+	return tm.VerifyToken(tokenString)
+}
@@ -5,7 +5,8 @@ import (
 )
 
 // GetTokenMigrations mengembalikan daftar migrasi terkait token (refresh, reset, blocklist).
-// Dimulai dari versi 2 (asumsi versi 1 adalah users).
+// Dimulai dari versi 2 (asumsi versi 1 adalah users); versi 8 menambahkan session_id ke refresh_tokens
+// dan versi 9 menambahkan remember_me serta session_expires_at untuk sliding session.
 func GetTokenMigrations() []Migration {
 	return []Migration{
 		{
@@ -26,6 +27,18 @@ func GetTokenMigrations() []Migration {
 			Up:      CreateTokenBlocklistTable,
 			Down:    DropTokenBlocklistTable,
 		},
+		{
+			Version: 8,
+			Name:    "add_session_id_to_refresh_tokens",
+			Up:      AddRefreshTokenSessionID,
+			Down:    DropRefreshTokenSessionID,
+		},
+		{
+			Version: 9,
+			Name:    "add_session_expiry_to_refresh_tokens",
+			Up:      AddRefreshTokenSessionExpiry,
+			Down:    DropRefreshTokenSessionExpiry,
+		},
 	}
 }
 
@@ -45,6 +58,20 @@ func CreateRefreshTokensTable(db Database) error {
 				revoked_at TIMESTAMP
 			)
 		`
+	} else if db.DriverName() == "mysql" {
+		query = `
+			CREATE TABLE IF NOT EXISTS refresh_tokens (
+				id BIGINT AUTO_INCREMENT PRIMARY KEY,
+				user_id CHAR(36) NOT NULL,
+				token_hash VARCHAR(255) UNIQUE NOT NULL,
+				user_agent TEXT NOT NULL,
+				ip_address VARCHAR(45) NOT NULL,
+				expires_at DATETIME NOT NULL,
+				created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+				revoked_at DATETIME NULL,
+				FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+		`
 	} else {
 		query = `
 			CREATE TABLE IF NOT EXISTS refresh_tokens (
@@ -85,6 +112,18 @@ func CreatePasswordResetTokensTable(db Database) error {
 				created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 			)
 		`
+	} else if db.DriverName() == "mysql" {
+		query = `
+			CREATE TABLE IF NOT EXISTS password_reset_tokens (
+				id BIGINT AUTO_INCREMENT PRIMARY KEY,
+				user_id CHAR(36) NOT NULL,
+				token_hash VARCHAR(255) UNIQUE NOT NULL,
+				expires_at DATETIME NOT NULL,
+				used_at DATETIME NULL,
+				created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+				FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+		`
 	} else {
 		query = `
 			CREATE TABLE IF NOT EXISTS password_reset_tokens (
@@ -120,6 +159,14 @@ func CreateTokenBlocklistTable(db Database) error {
 			);
 			CREATE INDEX IF NOT EXISTS idx_token_blocklist_expires_at ON token_blocklist(expires_at);
 		`
+	} else if db.DriverName() == "mysql" {
+		query = `
+			CREATE TABLE IF NOT EXISTS token_blocklist (
+				identifier VARCHAR(255) PRIMARY KEY,
+				expires_at DATETIME NOT NULL,
+				INDEX idx_token_blocklist_expires_at (expires_at)
+			) ENGINE=InnoDB
+		`
 	} else {
 		query = `
 			CREATE UNLOGGED TABLE IF NOT EXISTS token_blocklist (
@@ -140,3 +187,68 @@ func DropTokenBlocklistTable(db Database) error {
 	}
 	return db.Exec(context.Background(), query)
 }
+
+// AddRefreshTokenSessionID menambahkan kolom session_id (sid) ke refresh_tokens agar semua token
+// hasil rotasi dari satu login dapat dibatalkan sekaligus. Baris lama berisi string kosong.
+func AddRefreshTokenSessionID(db Database) error {
+	var query string
+	switch db.DriverName() {
+	case "sqlite":
+		query = `
+			ALTER TABLE refresh_tokens ADD COLUMN session_id TEXT NOT NULL DEFAULT '';
+			CREATE INDEX IF NOT EXISTS idx_refresh_tokens_session_id ON refresh_tokens(session_id);
+		`
+	case "mysql":
+		query = `ALTER TABLE refresh_tokens ADD COLUMN session_id VARCHAR(64) NOT NULL DEFAULT '', ADD INDEX idx_refresh_tokens_session_id (session_id)`
+	default:
+		query = `
+			ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS session_id VARCHAR(64) NOT NULL DEFAULT '';
+			CREATE INDEX IF NOT EXISTS idx_refresh_tokens_session_id ON refresh_tokens(session_id);
+		`
+	}
+	return db.Exec(context.Background(), query)
+}
+
+// DropRefreshTokenSessionID menghapus kolom session_id dari refresh_tokens.
+func DropRefreshTokenSessionID(db Database) error {
+	if db.DriverName() == "sqlite" {
+		// SQLite menolak DROP COLUMN pada kolom yang masih memiliki index
+		if err := db.Exec(context.Background(), "DROP INDEX IF EXISTS idx_refresh_tokens_session_id"); err != nil {
+			return err
+		}
+	}
+	return db.Exec(context.Background(), "ALTER TABLE refresh_tokens DROP COLUMN session_id")
+}
+
+// AddRefreshTokenSessionExpiry menambahkan kolom remember_me dan session_expires_at ke
+// refresh_tokens. session_expires_at adalah batas absolut session sejak login; baris lama
+// berisi NULL (tanpa batas) dan remember_me false.
+func AddRefreshTokenSessionExpiry(db Database) error {
+	var query string
+	switch db.DriverName() {
+	case "sqlite":
+		query = `
+			ALTER TABLE refresh_tokens ADD COLUMN remember_me BOOLEAN NOT NULL DEFAULT FALSE;
+			ALTER TABLE refresh_tokens ADD COLUMN session_expires_at TIMESTAMP;
+		`
+	case "mysql":
+		query = `ALTER TABLE refresh_tokens ADD COLUMN remember_me BOOLEAN NOT NULL DEFAULT FALSE, ADD COLUMN session_expires_at DATETIME NULL`
+	default:
+		query = `
+			ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS remember_me BOOLEAN NOT NULL DEFAULT FALSE;
+			ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS session_expires_at TIMESTAMP;
+		`
+	}
+	return db.Exec(context.Background(), query)
+}
+
+// DropRefreshTokenSessionExpiry menghapus kolom remember_me dan session_expires_at dari refresh_tokens.
+func DropRefreshTokenSessionExpiry(db Database) error {
+	if db.DriverName() == "mysql" {
+		return db.Exec(context.Background(), "ALTER TABLE refresh_tokens DROP COLUMN remember_me, DROP COLUMN session_expires_at")
+	}
+	if err := db.Exec(context.Background(), "ALTER TABLE refresh_tokens DROP COLUMN remember_me"); err != nil {
+		return err
+	}
+	return db.Exec(context.Background(), "ALTER TABLE refresh_tokens DROP COLUMN session_expires_at")
+}
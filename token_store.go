@@ -2,20 +2,33 @@ package dim
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"time"
 )
 
+// ErrRefreshTokenRevoked is returned by RevokeRefreshToken when the token does not exist or was
+// already revoked, e.g. because a concurrent refresh rotated it first.
+var ErrRefreshTokenRevoked = errors.New("refresh token not found or already revoked")
+
 // RefreshToken represents a refresh token entity
 type RefreshToken struct {
 	ID        int64      `json:"id"`
 	UserID    string     `json:"user_id"`
 	TokenHash string     `json:"-"`
+	SessionID string     `json:"session_id"`
 	UserAgent string     `json:"user_agent"`
 	IPAddress string     `json:"ip_address"`
 	ExpiresAt time.Time  `json:"expires_at"`
 	CreatedAt time.Time  `json:"created_at"`
 	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	// RememberMe menandai session dari login "remember me" sehingga refresh berikutnya tetap
+	// memakai TTL remember-me.
+	RememberMe bool `json:"remember_me"`
+	// SessionExpiresAt adalah batas absolut session sejak login (SessionOptions.MaxLifetime);
+	// refresh tidak pernah memperpanjang ExpiresAt melewati waktu ini. Nil berarti tanpa batas.
+	SessionExpiresAt *time.Time `json:"session_expires_at,omitempty"`
 }
 
 // PasswordResetToken represents a password reset token entity
@@ -32,15 +45,22 @@ type PasswordResetToken struct {
 type TokenStore interface {
 	SaveRefreshToken(ctx context.Context, token *RefreshToken) error
 	FindRefreshToken(ctx context.Context, tokenHash string) (*RefreshToken, error)
-	RevokeRefreshToken(ctx context.Context, tokenHash string) error
+	RevokeRefreshToken(ctx context.Context, tokenHash string) error // ErrRefreshTokenRevoked if not found or already revoked
 	RevokeAllUserTokens(ctx context.Context, userID string) error
+	RevokeBySession(ctx context.Context, sessionID string) error
+	ListActiveSessions(ctx context.Context, userID string) ([]*RefreshToken, error)
+	RevokeSession(ctx context.Context, userID string, id int64) error // ErrSessionNotFound if not active or not owned by userID
 
 	SavePasswordResetToken(ctx context.Context, token *PasswordResetToken) error
 	FindPasswordResetToken(ctx context.Context, tokenHash string) (*PasswordResetToken, error)
 	MarkPasswordResetUsed(ctx context.Context, tokenHash string) error
+
+	// DeleteExpired removes refresh tokens and password reset tokens whose expires_at is before
+	// the given time, including revoked and used ones. See TokenCleanupTask.
+	DeleteExpired(ctx context.Context, before time.Time) error
 }
 
-// DatabaseTokenStore is the SQL implementation of TokenStore (PostgreSQL & SQLite)
+// DatabaseTokenStore is the SQL implementation of TokenStore (PostgreSQL, MySQL & SQLite)
 type DatabaseTokenStore struct {
 	db Database
 }
@@ -59,17 +79,20 @@ func NewPostgresTokenStore(db Database) *DatabaseTokenStore {
 // SaveRefreshToken saves a refresh token to the database.
 func (s *DatabaseTokenStore) SaveRefreshToken(ctx context.Context, token *RefreshToken) error {
 	now := time.Now().UTC().Truncate(time.Second)
-	query := `INSERT INTO refresh_tokens (user_id, token_hash, user_agent, ip_address, expires_at, created_at)
-		 VALUES ($1, $2, $3, $4, $5, $6)
+	query := `INSERT INTO refresh_tokens (user_id, token_hash, session_id, user_agent, ip_address, expires_at, created_at, remember_me, session_expires_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 		 RETURNING id, created_at`
 
 	err := s.db.QueryRow(ctx, s.db.Rebind(query),
 		token.UserID,
 		token.TokenHash,
+		token.SessionID,
 		token.UserAgent,
 		token.IPAddress,
 		token.ExpiresAt.UTC().Truncate(time.Second),
 		now,
+		token.RememberMe,
+		utcTimePtr(token.SessionExpiresAt),
 	).Scan(&token.ID, &token.CreatedAt)
 
 	if err != nil {
@@ -82,12 +105,12 @@ func (s *DatabaseTokenStore) SaveRefreshToken(ctx context.Context, token *Refres
 // FindRefreshToken finds a refresh token by hash.
 func (s *DatabaseTokenStore) FindRefreshToken(ctx context.Context, tokenHash string) (*RefreshToken, error) {
 	token := &RefreshToken{}
-	query := `SELECT id, user_id, token_hash, user_agent, ip_address, expires_at, created_at, revoked_at
+	query := `SELECT id, user_id, token_hash, session_id, user_agent, ip_address, expires_at, created_at, revoked_at, remember_me, session_expires_at
 		 FROM refresh_tokens WHERE token_hash = $1`
 
 	err := s.db.QueryRow(ctx, s.db.Rebind(query), tokenHash).Scan(
-		&token.ID, &token.UserID, &token.TokenHash, &token.UserAgent, &token.IPAddress,
-		&token.ExpiresAt, &token.CreatedAt, &token.RevokedAt,
+		&token.ID, &token.UserID, &token.TokenHash, &token.SessionID, &token.UserAgent, &token.IPAddress,
+		&token.ExpiresAt, &token.CreatedAt, &token.RevokedAt, &token.RememberMe, &token.SessionExpiresAt,
 	)
 
 	if err != nil {
@@ -97,13 +120,32 @@ func (s *DatabaseTokenStore) FindRefreshToken(ctx context.Context, tokenHash str
 	return token, nil
 }
 
-// RevokeRefreshToken revokes a refresh token by setting revoked_at timestamp.
+// RevokeRefreshToken revokes an active refresh token by setting revoked_at timestamp.
+// Only one caller can revoke a token; the others get ErrRefreshTokenRevoked.
 func (s *DatabaseTokenStore) RevokeRefreshToken(ctx context.Context, tokenHash string) error {
-	query := `UPDATE refresh_tokens SET revoked_at = $1 WHERE token_hash = $2`
-
-	err := s.db.Exec(ctx, s.db.Rebind(query), time.Now().UTC().Truncate(time.Second), tokenHash)
-
+	now := time.Now().UTC().Truncate(time.Second)
+	var err error
+	if s.db.DriverName() == "mysql" {
+		// MySQL does not support UPDATE ... RETURNING; lock the row within a transaction instead.
+		err = s.db.WithTx(ctx, func(ctx context.Context, tx Tx) error {
+			var id int64
+			query := `SELECT id FROM refresh_tokens WHERE token_hash = $1 AND revoked_at IS NULL FOR UPDATE`
+			if err := tx.QueryRow(ctx, query, tokenHash).Scan(&id); err != nil {
+				return err
+			}
+			return tx.Exec(ctx, `UPDATE refresh_tokens SET revoked_at = $1 WHERE id = $2`, now, id)
+		})
+	} else {
+		var revoked string
+		query := `UPDATE refresh_tokens SET revoked_at = $1
+			 WHERE token_hash = $2 AND revoked_at IS NULL
+			 RETURNING token_hash`
+		err = s.db.QueryRow(ctx, s.db.Rebind(query), now, tokenHash).Scan(&revoked)
+	}
 	if err != nil {
+		if isNoRows(err) {
+			return ErrRefreshTokenRevoked
+		}
 		return fmt.Errorf("failed to revoke refresh token: %w", err)
 	}
 
@@ -123,6 +165,76 @@ func (s *DatabaseTokenStore) RevokeAllUserTokens(ctx context.Context, userID str
 	return nil
 }
 
+// RevokeBySession revokes every refresh token issued for a session (sid), i.e. the whole
+// rotation family of a login.
+func (s *DatabaseTokenStore) RevokeBySession(ctx context.Context, sessionID string) error {
+	if sessionID == "" {
+		return nil
+	}
+
+	query := `UPDATE refresh_tokens SET revoked_at = $1 WHERE session_id = $2 AND revoked_at IS NULL`
+
+	err := s.db.Exec(ctx, s.db.Rebind(query), time.Now().UTC().Truncate(time.Second), sessionID)
+
+	if err != nil {
+		return fmt.Errorf("failed to revoke session tokens: %w", err)
+	}
+
+	return nil
+}
+
+// ListActiveSessions lists the refresh tokens of a user that are neither revoked nor expired,
+// newest first. Token rotation keeps a single active token per session, so each row is one
+// logged in device.
+func (s *DatabaseTokenStore) ListActiveSessions(ctx context.Context, userID string) ([]*RefreshToken, error) {
+	query := `SELECT id, user_id, token_hash, session_id, user_agent, ip_address, expires_at, created_at, revoked_at, remember_me, session_expires_at
+		 FROM refresh_tokens WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > $2
+		 ORDER BY created_at DESC, id DESC`
+
+	rows, err := s.db.Query(ctx, s.db.Rebind(query), userID, time.Now().UTC().Truncate(time.Second))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []*RefreshToken
+	for rows.Next() {
+		token := &RefreshToken{}
+		if err := rows.Scan(
+			&token.ID, &token.UserID, &token.TokenHash, &token.SessionID, &token.UserAgent, &token.IPAddress,
+			&token.ExpiresAt, &token.CreatedAt, &token.RevokedAt, &token.RememberMe, &token.SessionExpiresAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan session: %w", err)
+		}
+		sessions = append(sessions, token)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession revokes an active refresh token of a user by its ID.
+func (s *DatabaseTokenStore) RevokeSession(ctx context.Context, userID string, id int64) error {
+	var found int64
+	query := `SELECT id FROM refresh_tokens WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL`
+	if err := s.db.QueryRow(ctx, s.db.Rebind(query), id, userID).Scan(&found); err != nil {
+		if isNoRows(err) {
+			return ErrSessionNotFound
+		}
+		return fmt.Errorf("failed to find session: %w", err)
+	}
+
+	query = `UPDATE refresh_tokens SET revoked_at = $1 WHERE id = $2`
+	if err := s.db.Exec(ctx, s.db.Rebind(query), time.Now().UTC().Truncate(time.Second), id); err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+
+	return nil
+}
+
 // SavePasswordResetToken saves a password reset token to the database.
 func (s *DatabaseTokenStore) SavePasswordResetToken(ctx context.Context, token *PasswordResetToken) error {
 	now := time.Now().UTC().Truncate(time.Second)
@@ -174,10 +286,32 @@ func (s *DatabaseTokenStore) MarkPasswordResetUsed(ctx context.Context, tokenHas
 	return nil
 }
 
+// DeleteExpired deletes refresh tokens and password reset tokens that expired before the given time.
+// Revoked refresh tokens are only needed for reuse detection until they expire, so they are
+// removed together with the rest.
+func (s *DatabaseTokenStore) DeleteExpired(ctx context.Context, before time.Time) error {
+	before = before.UTC().Truncate(time.Second)
+
+	if err := s.db.Exec(ctx, s.db.Rebind(`DELETE FROM refresh_tokens WHERE expires_at < $1`), before); err != nil {
+		return fmt.Errorf("failed to delete expired refresh tokens: %w", err)
+	}
+	if err := s.db.Exec(ctx, s.db.Rebind(`DELETE FROM password_reset_tokens WHERE expires_at < $1`), before); err != nil {
+		return fmt.Errorf("failed to delete expired password reset tokens: %w", err)
+	}
+
+	return nil
+}
+
 // MockTokenStore is a mock implementation for testing
 type MockTokenStore struct {
 	refreshTokens map[string]*RefreshToken
 	resetTokens   map[string]*PasswordResetToken
+	mfaSecrets    map[string]*MFASecret
+	recoveryCodes map[string]map[string]bool // userID -> code hash -> used
+	mfaChallenges map[string]*MFAChallenge
+	magicLinks    map[string]*MagicLinkToken
+	verifications map[string]*EmailVerificationToken
+	emailChanges  map[int64]*EmailChange
 }
 
 // NewMockTokenStore creates a new mock token store.
@@ -185,6 +319,12 @@ func NewMockTokenStore() *MockTokenStore {
 	return &MockTokenStore{
 		refreshTokens: make(map[string]*RefreshToken),
 		resetTokens:   make(map[string]*PasswordResetToken),
+		mfaSecrets:    make(map[string]*MFASecret),
+		recoveryCodes: make(map[string]map[string]bool),
+		mfaChallenges: make(map[string]*MFAChallenge),
+		magicLinks:    make(map[string]*MagicLinkToken),
+		verifications: make(map[string]*EmailVerificationToken),
+		emailChanges:  make(map[int64]*EmailChange),
 	}
 }
 
@@ -205,12 +345,14 @@ func (s *MockTokenStore) FindRefreshToken(ctx context.Context, tokenHash string)
 	return token, nil
 }
 
-// RevokeRefreshToken revokes a refresh token in mock store.
+// RevokeRefreshToken revokes an active refresh token in mock store.
 func (s *MockTokenStore) RevokeRefreshToken(ctx context.Context, tokenHash string) error {
-	if token, exists := s.refreshTokens[tokenHash]; exists {
-		now := time.Now()
-		token.RevokedAt = &now
+	token, exists := s.refreshTokens[tokenHash]
+	if !exists || token.RevokedAt != nil {
+		return ErrRefreshTokenRevoked
 	}
+	now := time.Now()
+	token.RevokedAt = &now
 	return nil
 }
 
@@ -225,6 +367,51 @@ func (s *MockTokenStore) RevokeAllUserTokens(ctx context.Context, userID string)
 	return nil
 }
 
+// RevokeBySession revokes all tokens of a session in mock store.
+func (s *MockTokenStore) RevokeBySession(ctx context.Context, sessionID string) error {
+	if sessionID == "" {
+		return nil
+	}
+	now := time.Now()
+	for _, token := range s.refreshTokens {
+		if token.SessionID == sessionID && token.RevokedAt == nil {
+			token.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+// ListActiveSessions lists active refresh tokens of a user in mock store, newest first.
+func (s *MockTokenStore) ListActiveSessions(ctx context.Context, userID string) ([]*RefreshToken, error) {
+	now := time.Now()
+	var sessions []*RefreshToken
+	for _, token := range s.refreshTokens {
+		if token.UserID == userID && token.RevokedAt == nil && token.ExpiresAt.After(now) {
+			copied := *token
+			sessions = append(sessions, &copied)
+		}
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		if !sessions[i].CreatedAt.Equal(sessions[j].CreatedAt) {
+			return sessions[i].CreatedAt.After(sessions[j].CreatedAt)
+		}
+		return sessions[i].ID > sessions[j].ID
+	})
+	return sessions, nil
+}
+
+// RevokeSession revokes an active refresh token of a user by ID in mock store.
+func (s *MockTokenStore) RevokeSession(ctx context.Context, userID string, id int64) error {
+	for _, token := range s.refreshTokens {
+		if token.ID == id && token.UserID == userID && token.RevokedAt == nil {
+			now := time.Now()
+			token.RevokedAt = &now
+			return nil
+		}
+	}
+	return ErrSessionNotFound
+}
+
 // SavePasswordResetToken saves a password reset token in mock store.
 func (s *MockTokenStore) SavePasswordResetToken(ctx context.Context, token *PasswordResetToken) error {
 	token.ID = int64(len(s.resetTokens) + 1)
@@ -250,3 +437,18 @@ func (s *MockTokenStore) MarkPasswordResetUsed(ctx context.Context, tokenHash st
 	}
 	return nil
 }
+
+// DeleteExpired deletes expired refresh and password reset tokens in mock store.
+func (s *MockTokenStore) DeleteExpired(ctx context.Context, before time.Time) error {
+	for hash, token := range s.refreshTokens {
+		if token.ExpiresAt.Before(before) {
+			delete(s.refreshTokens, hash)
+		}
+	}
+	for hash, token := range s.resetTokens {
+		if token.ExpiresAt.Before(before) {
+			delete(s.resetTokens, hash)
+		}
+	}
+	return nil
+}
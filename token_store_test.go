@@ -2,6 +2,7 @@ package dim
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 )
@@ -113,6 +114,45 @@ func TestMockTokenStoreRevokeAllUserTokens(t *testing.T) {
 	}
 }
 
+func TestMockTokenStoreRevokeRefreshTokenOnce(t *testing.T) {
+	store := NewMockTokenStore()
+	ctx := context.Background()
+	store.SaveRefreshToken(ctx, &RefreshToken{UserID: "1", TokenHash: "hash1"})
+
+	if err := store.RevokeRefreshToken(ctx, "hash1"); err != nil {
+		t.Fatalf("RevokeRefreshToken() error = %v", err)
+	}
+	if err := store.RevokeRefreshToken(ctx, "hash1"); !errors.Is(err, ErrRefreshTokenRevoked) {
+		t.Errorf("second RevokeRefreshToken() error = %v, want ErrRefreshTokenRevoked", err)
+	}
+	if err := store.RevokeRefreshToken(ctx, "missing"); !errors.Is(err, ErrRefreshTokenRevoked) {
+		t.Errorf("RevokeRefreshToken(missing) error = %v, want ErrRefreshTokenRevoked", err)
+	}
+}
+
+func TestMockTokenStoreRevokeBySession(t *testing.T) {
+	store := NewMockTokenStore()
+	ctx := context.Background()
+
+	store.SaveRefreshToken(ctx, &RefreshToken{UserID: "1", TokenHash: "hash1", SessionID: "sid-1"})
+	store.SaveRefreshToken(ctx, &RefreshToken{UserID: "1", TokenHash: "hash2", SessionID: "sid-1"})
+	store.SaveRefreshToken(ctx, &RefreshToken{UserID: "1", TokenHash: "hash3", SessionID: "sid-2"})
+
+	if err := store.RevokeBySession(ctx, "sid-1"); err != nil {
+		t.Errorf("RevokeBySession() error = %v", err)
+	}
+
+	found1, _ := store.FindRefreshToken(ctx, "hash1")
+	found2, _ := store.FindRefreshToken(ctx, "hash2")
+	found3, _ := store.FindRefreshToken(ctx, "hash3")
+	if found1.RevokedAt == nil || found2.RevokedAt == nil {
+		t.Errorf("tokens of the session should be revoked")
+	}
+	if found3.RevokedAt != nil {
+		t.Errorf("tokens of other sessions should not be revoked")
+	}
+}
+
 func TestMockTokenStoreSavePasswordResetToken(t *testing.T) {
 	store := NewMockTokenStore()
 	ctx := context.Background()
@@ -177,3 +217,55 @@ func TestMockTokenStoreMarkPasswordResetUsed(t *testing.T) {
 		t.Errorf("token should be marked as used")
 	}
 }
+
+func TestMockTokenStoreDeleteExpired(t *testing.T) {
+	store := NewMockTokenStore()
+	ctx := context.Background()
+	now := time.Now()
+
+	store.SaveRefreshToken(ctx, &RefreshToken{UserID: "1", TokenHash: "old", ExpiresAt: now.Add(-time.Hour)})
+	store.SaveRefreshToken(ctx, &RefreshToken{UserID: "1", TokenHash: "active", ExpiresAt: now.Add(time.Hour)})
+	store.SavePasswordResetToken(ctx, &PasswordResetToken{UserID: "1", TokenHash: "reset_old", ExpiresAt: now.Add(-time.Hour)})
+	store.SavePasswordResetToken(ctx, &PasswordResetToken{UserID: "1", TokenHash: "reset_active", ExpiresAt: now.Add(time.Hour)})
+
+	if err := store.DeleteExpired(ctx, now); err != nil {
+		t.Fatalf("DeleteExpired() error = %v", err)
+	}
+
+	if _, err := store.FindRefreshToken(ctx, "old"); err == nil {
+		t.Error("expired refresh token should be deleted")
+	}
+	if _, err := store.FindRefreshToken(ctx, "active"); err != nil {
+		t.Errorf("active refresh token deleted: %v", err)
+	}
+	if _, err := store.FindPasswordResetToken(ctx, "reset_old"); err == nil {
+		t.Error("expired password reset token should be deleted")
+	}
+	if _, err := store.FindPasswordResetToken(ctx, "reset_active"); err != nil {
+		t.Errorf("active password reset token deleted: %v", err)
+	}
+}
+
+func TestTokenCleanupTask(t *testing.T) {
+	store := NewMockTokenStore()
+	ctx := context.Background()
+	now := time.Now()
+
+	store.SaveRefreshToken(ctx, &RefreshToken{UserID: "1", TokenHash: "expired_2d", ExpiresAt: now.Add(-48 * time.Hour)})
+	store.SaveRefreshToken(ctx, &RefreshToken{UserID: "1", TokenHash: "expired_1h", ExpiresAt: now.Add(-time.Hour)})
+
+	scheduler := NewScheduler(SchedulerOptions{})
+	if err := scheduler.Cron(TokenCleanupTaskName, "@hourly", TokenCleanupTask(store, 24*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if err := scheduler.RunNow(ctx, TokenCleanupTaskName); err != nil {
+		t.Fatalf("RunNow() error = %v", err)
+	}
+
+	if _, err := store.FindRefreshToken(ctx, "expired_2d"); err == nil {
+		t.Error("token expired beyond retention should be deleted")
+	}
+	if _, err := store.FindRefreshToken(ctx, "expired_1h"); err != nil {
+		t.Errorf("token within retention deleted: %v", err)
+	}
+}
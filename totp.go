@@ -0,0 +1,142 @@
+package dim
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Parameter TOTP yang didukung semua aplikasi authenticator (Google Authenticator, Authy, 1Password).
+const (
+	totpDigits = 6
+	totpPeriod = 30
+	// totpSkew adalah jumlah step sebelum/sesudah waktu saat ini yang masih diterima (toleransi jam).
+	totpSkew = 1
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateTOTPSecret membuat secret TOTP acak 160-bit (sesuai rekomendasi RFC 4226) dalam
+// format base32 tanpa padding, siap dimasukkan ke aplikasi authenticator.
+//
+// Returns:
+//   - string: secret base32
+//   - error: jika pembacaan crypto/rand gagal
+func GenerateTOTPSecret() (string, error) {
+	b := make([]byte, 20)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return totpEncoding.EncodeToString(b), nil
+}
+
+// TOTPCode menghitung kode TOTP 6 digit (RFC 6238, HMAC-SHA1, periode 30 detik) untuk waktu t.
+//
+// Parameters:
+//   - secret: secret base32 (spasi dan huruf kecil diabaikan)
+//   - t: waktu kode
+//
+// Returns:
+//   - string: kode 6 digit dengan leading zero
+//   - error: jika secret bukan base32 yang valid
+func TOTPCode(secret string, t time.Time) (string, error) {
+	key, err := decodeTOTPSecret(secret)
+	if err != nil {
+		return "", err
+	}
+	return totpCodeAt(key, totpStep(t)), nil
+}
+
+// ValidateTOTP memeriksa kode TOTP pada waktu t dengan toleransi satu step (±30 detik).
+// Mengembalikan step yang cocok agar pemanggil dapat menolak kode yang sama dipakai ulang.
+//
+// Parameters:
+//   - secret: secret base32
+//   - code: kode dari user (spasi diabaikan)
+//   - t: waktu verifikasi, biasanya time.Now()
+//
+// Returns:
+//   - int64: step (Unix/30) dari kode yang cocok
+//   - bool: true jika kode valid
+func ValidateTOTP(secret, code string, t time.Time) (int64, bool) {
+	code = strings.ReplaceAll(code, " ", "")
+	if len(code) != totpDigits {
+		return 0, false
+	}
+	key, err := decodeTOTPSecret(secret)
+	if err != nil {
+		return 0, false
+	}
+
+	current := totpStep(t)
+	for step := current - totpSkew; step <= current+totpSkew; step++ {
+		if subtle.ConstantTimeCompare([]byte(totpCodeAt(key, step)), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// TOTPProvisioningURI membuat URI otpauth://totp/... untuk didaftarkan di aplikasi authenticator.
+// Tampilkan sebagai QR code (misal dengan QRCodePNG) atau sebagai link di perangkat mobile.
+//
+// Parameters:
+//   - issuer: nama aplikasi yang ditampilkan di authenticator
+//   - account: identitas akun, biasanya email user
+//   - secret: secret base32 dari GenerateTOTPSecret
+//
+// Returns:
+//   - string: URI provisioning
+//
+// Example:
+//
+//	uri := dim.TOTPProvisioningURI("Acme", user.GetEmail(), secret)
+//	png, err := dim.QRCodePNG(uri, dim.QROptions{Size: 256})
+func TOTPProvisioningURI(issuer, account, secret string) string {
+	label := account
+	if issuer != "" {
+		label = issuer + ":" + account
+	}
+	q := url.Values{}
+	q.Set("secret", secret)
+	if issuer != "" {
+		q.Set("issuer", issuer)
+	}
+	q.Set("algorithm", "SHA1")
+	q.Set("digits", fmt.Sprint(totpDigits))
+	q.Set("period", fmt.Sprint(totpPeriod))
+	return "otpauth://totp/" + url.PathEscape(label) + "?" + q.Encode()
+}
+
+func totpStep(t time.Time) int64 {
+	return t.Unix() / totpPeriod
+}
+
+func decodeTOTPSecret(secret string) ([]byte, error) {
+	secret = strings.ToUpper(strings.ReplaceAll(secret, " ", ""))
+	key, err := totpEncoding.DecodeString(strings.TrimRight(secret, "="))
+	if err != nil {
+		return nil, fmt.Errorf("invalid totp secret: %w", err)
+	}
+	return key, nil
+}
+
+// totpCodeAt menghitung HOTP (RFC 4226) untuk counter step.
+func totpCodeAt(key []byte, step int64) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(step))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", totpDigits, value%1000000)
+}
@@ -0,0 +1,72 @@
+package dim
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTOTPCode_RFC6238(t *testing.T) {
+	// Test vector RFC 6238 Appendix B (SHA1), diambil 6 digit terakhir
+	secret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+	tests := map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	}
+	for unix, want := range tests {
+		got, err := TOTPCode(secret, time.Unix(unix, 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("TOTPCode(%d) = %s, want %s", unix, got, want)
+		}
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(secret) != 32 {
+		t.Errorf("secret length = %d, want 32", len(secret))
+	}
+
+	now := time.Unix(1700000000, 0)
+	code, _ := TOTPCode(secret, now)
+
+	step, ok := ValidateTOTP(secret, code, now)
+	if !ok || step != now.Unix()/30 {
+		t.Fatalf("ValidateTOTP = %d, %v", step, ok)
+	}
+	// Toleransi satu step
+	if _, ok := ValidateTOTP(secret, code, now.Add(30*time.Second)); !ok {
+		t.Error("expected code to be valid in next step")
+	}
+	if _, ok := ValidateTOTP(secret, code, now.Add(90*time.Second)); ok {
+		t.Error("expected code to be invalid after skew window")
+	}
+	if _, ok := ValidateTOTP(strings.ToLower(secret), code[:3]+" "+code[3:], now); !ok {
+		t.Error("expected spaces and lowercase secret to be accepted")
+	}
+	if _, ok := ValidateTOTP(secret, "12345", now); ok {
+		t.Error("expected short code to be rejected")
+	}
+	if _, ok := ValidateTOTP("not base32!", code, now); ok {
+		t.Error("expected invalid secret to be rejected")
+	}
+}
+
+func TestTOTPProvisioningURI(t *testing.T) {
+	uri := TOTPProvisioningURI("Acme Corp", "ana@example.com", "JBSWY3DPEHPK3PXP")
+	want := "otpauth://totp/Acme%20Corp:ana@example.com?algorithm=SHA1&digits=6&issuer=Acme+Corp&period=30&secret=JBSWY3DPEHPK3PXP"
+	if uri != want {
+		t.Errorf("uri = %s\nwant  %s", uri, want)
+	}
+}
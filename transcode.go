@@ -0,0 +1,398 @@
+package dim
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+const transcodeMetadataKey contextKey = "transcode_metadata"
+
+// GRPCInvoker memanggil method gRPC unary. Signature-nya mengikuti grpc.ClientConn.Invoke
+// sehingga framework tidak bergantung langsung pada google.golang.org/grpc.
+//
+// Example:
+//
+//	conn, _ := grpc.NewClient("users:9090", grpc.WithTransportCredentials(insecure.NewCredentials()))
+//	invoker := func(ctx context.Context, method string, req, resp interface{}) error {
+//	    md := metadata.MD(dim.TranscodeMetadata(ctx))
+//	    return conn.Invoke(metadata.NewOutgoingContext(ctx, md), method, req, resp)
+//	}
+type GRPCInvoker func(ctx context.Context, method string, req, resp interface{}) error
+
+// Transcoder memetakan route REST ke method gRPC (gaya grpc-gateway): path parameter,
+// query string, dan body di-bind ke request message, lalu response message dikirim sebagai JSON.
+// Route tetap didaftarkan di Router biasa sehingga middleware dim (auth, rate limit, logging)
+// berlaku seperti handler lain.
+type Transcoder struct {
+	invoker        GRPCInvoker
+	forwardHeaders []string
+	unmarshal      func(data []byte, v interface{}) error
+	marshal        func(v interface{}) ([]byte, error)
+	errorHandler   func(w http.ResponseWriter, r *http.Request, err error)
+	maxBytes       int64
+}
+
+// NewTranscoder membuat transcoder untuk invoker gRPC.
+//
+// Parameters:
+//   - invoker: fungsi pemanggil method gRPC
+//
+// Returns:
+//   - *Transcoder: transcoder dengan codec encoding/json
+//
+// Example:
+//
+//	tc := dim.NewTranscoder(invoker).WithForwardHeaders("Authorization", "X-Request-ID")
+//	router.Get("/v1/users/{user_id}", dim.Transcode[userpb.GetUserRequest, userpb.User](tc,
+//	    "/users.v1.UserService/GetUser"), dim.RequireAuth(jwtManager, blocklist))
+func NewTranscoder(invoker GRPCInvoker) *Transcoder {
+	return &Transcoder{
+		invoker:  invoker,
+		maxBytes: DefaultBindMaxBytes,
+	}
+}
+
+// WithForwardHeaders meneruskan header HTTP tertentu sebagai metadata gRPC (key lowercase).
+// Nilainya dibaca invoker melalui TranscodeMetadata.
+func (t *Transcoder) WithForwardHeaders(headers ...string) *Transcoder {
+	t.forwardHeaders = append(t.forwardHeaders, headers...)
+	return t
+}
+
+// WithCodec mengganti codec body dan response, misal protojson untuk message protobuf
+// (oneof, enum sebagai string, well-known types).
+//
+// Example:
+//
+//	tc.WithCodec(
+//	    func(data []byte, v interface{}) error { return protojson.Unmarshal(data, v.(proto.Message)) },
+//	    func(v interface{}) ([]byte, error) { return protojson.Marshal(v.(proto.Message)) },
+//	)
+func (t *Transcoder) WithCodec(unmarshal func(data []byte, v interface{}) error, marshal func(v interface{}) ([]byte, error)) *Transcoder {
+	t.unmarshal = unmarshal
+	t.marshal = marshal
+	return t
+}
+
+// WithErrorHandler mengganti penanganan error dari invoker.
+// Default: *AppError dikirim apa adanya, status gRPC dipetakan dengan GRPCStatusToHTTP.
+func (t *Transcoder) WithErrorHandler(fn func(w http.ResponseWriter, r *http.Request, err error)) *Transcoder {
+	t.errorHandler = fn
+	return t
+}
+
+// WithMaxBytes membatasi ukuran body request (default DefaultBindMaxBytes).
+func (t *Transcoder) WithMaxBytes(n int64) *Transcoder {
+	t.maxBytes = n
+	return t
+}
+
+// TranscodeOption mengatur satu route transcoding.
+type TranscodeOption func(*transcodeRoute)
+
+type transcodeRoute struct {
+	body   string
+	status int
+}
+
+// TranscodeBody menentukan field tujuan body seperti opsi `body` pada google.api.http:
+// "*" untuk seluruh message, nama field untuk message bersarang, atau "" untuk mengabaikan body.
+// Tanpa option ini, body (jika ada) di-decode ke seluruh message.
+func TranscodeBody(field string) TranscodeOption {
+	return func(r *transcodeRoute) {
+		r.body = field
+	}
+}
+
+// TranscodeStatus mengatur status HTTP response sukses (default 200).
+func TranscodeStatus(status int) TranscodeOption {
+	return func(r *transcodeRoute) {
+		r.status = status
+	}
+}
+
+// Transcode membuat HandlerFunc yang memanggil method gRPC dengan request message Req
+// dan mengembalikan response message Resp sebagai JSON.
+//
+// Urutan binding: body, lalu query string, lalu path parameter (path selalu menang).
+// Nama parameter dicocokkan dengan tag `path`/`query`, nama field protobuf (`protobuf:"...,name=x"`),
+// tag `json`, atau nama field Go (case-insensitive). Field bersarang memakai notasi titik
+// (?page.size=10).
+//
+// Parameters:
+//   - t: transcoder
+//   - method: nama method gRPC lengkap, misal "/users.v1.UserService/GetUser"
+//   - opts: TranscodeBody, TranscodeStatus
+//
+// Returns:
+//   - HandlerFunc: handler untuk didaftarkan di Router
+//
+// Example:
+//
+//	router.Post("/v1/users", dim.Transcode[userpb.CreateUserRequest, userpb.User](tc,
+//	    "/users.v1.UserService/CreateUser", dim.TranscodeBody("user"), dim.TranscodeStatus(http.StatusCreated)))
+func Transcode[Req, Resp any](t *Transcoder, method string, opts ...TranscodeOption) HandlerFunc {
+	route := &transcodeRoute{body: "*", status: http.StatusOK}
+	for _, opt := range opts {
+		opt(route)
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := new(Req)
+		if err := t.bindRequest(r, req, route); err != nil {
+			appErr, _ := AsAppError(err)
+			JsonAppError(w, appErr)
+			return
+		}
+
+		ctx := r.Context()
+		if len(t.forwardHeaders) > 0 {
+			md := make(map[string][]string, len(t.forwardHeaders))
+			for _, h := range t.forwardHeaders {
+				if values := r.Header.Values(h); len(values) > 0 {
+					md[strings.ToLower(h)] = values
+				}
+			}
+			ctx = context.WithValue(ctx, transcodeMetadataKey, md)
+		}
+
+		resp := new(Resp)
+		if err := t.invoker(ctx, method, req, resp); err != nil {
+			if t.errorHandler != nil {
+				t.errorHandler(w, r, err)
+			} else {
+				writeTranscodeError(w, err)
+			}
+			return
+		}
+
+		if t.marshal == nil {
+			Json(w, route.status, resp)
+			return
+		}
+		data, err := t.marshal(resp)
+		if err != nil {
+			InternalServerError(w, "Gagal memproses response")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(route.status)
+		w.Write(data) //nolint:errcheck
+	}
+}
+
+// TranscodeMetadata mengembalikan header yang diteruskan WithForwardHeaders untuk request ini,
+// dengan key lowercase siap dipakai sebagai metadata.MD.
+func TranscodeMetadata(ctx context.Context) map[string][]string {
+	md, _ := ctx.Value(transcodeMetadataKey).(map[string][]string)
+	return md
+}
+
+func (t *Transcoder) bindRequest(r *http.Request, req interface{}, route *transcodeRoute) error {
+	if route.body != "" && r.Body != nil && r.Body != http.NoBody && r.ContentLength != 0 {
+		data, err := io.ReadAll(http.MaxBytesReader(nil, r.Body, t.maxBytes))
+		if err != nil {
+			var maxErr *http.MaxBytesError
+			if errors.As(err, &maxErr) {
+				return NewAppError("Ukuran request terlalu besar", http.StatusRequestEntityTooLarge)
+			}
+			return NewAppError("Format JSON tidak valid", http.StatusBadRequest)
+		}
+
+		target := req
+		if route.body != "*" {
+			field, ok := transcodeField(reflect.ValueOf(req).Elem(), route.body, "")
+			if !ok {
+				return NewAppError("Format JSON tidak valid", http.StatusBadRequest)
+			}
+			if field.Kind() == reflect.Ptr {
+				if field.IsNil() {
+					field.Set(reflect.New(field.Type().Elem()))
+				}
+				target = field.Interface()
+			} else {
+				target = field.Addr().Interface()
+			}
+		}
+
+		if t.unmarshal != nil {
+			if err := t.unmarshal(data, target); err != nil {
+				return NewAppError("Format JSON tidak valid", http.StatusBadRequest)
+			}
+		} else if err := json.Unmarshal(data, target); err != nil {
+			return jsonBindError(err, GetLocale(r))
+		}
+	}
+
+	appErr := NewAppError("Parameter tidak valid", http.StatusBadRequest)
+	locale := GetLocale(r)
+	rv := reflect.ValueOf(req).Elem()
+
+	bind := func(name, tag string, values []string) {
+		field, ok := transcodeField(rv, name, tag)
+		if !ok {
+			return
+		}
+		if err := setFieldFromStrings(field, values); err != nil {
+			appErr.WithFieldError(name, Translate(locale, "%s %s", name, translateError(locale, err)))
+		}
+	}
+
+	query := r.URL.Query()
+	for name, values := range query {
+		bind(name, "query", values)
+	}
+	if rp, ok := r.Context().Value(paramsKey).(*routeParams); ok {
+		for i, key := range rp.keys {
+			bind(key, "path", []string{rp.vals[i]})
+		}
+	}
+
+	if len(appErr.Errors) > 0 {
+		return appErr
+	}
+	return nil
+}
+
+// transcodeField mencari field berdasarkan path bertitik, membuat pointer message bersarang bila perlu.
+func transcodeField(rv reflect.Value, path, tag string) (reflect.Value, bool) {
+	for _, part := range strings.Split(path, ".") {
+		for rv.Kind() == reflect.Ptr {
+			if rv.IsNil() {
+				rv.Set(reflect.New(rv.Type().Elem()))
+			}
+			rv = rv.Elem()
+		}
+		if rv.Kind() != reflect.Struct {
+			return reflect.Value{}, false
+		}
+
+		index := transcodeFieldIndex(rv.Type(), part, tag)
+		if index < 0 {
+			return reflect.Value{}, false
+		}
+		rv = rv.Field(index)
+	}
+	return rv, rv.CanSet()
+}
+
+func transcodeFieldIndex(rt reflect.Type, name, tag string) int {
+	fallback := -1
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if tag != "" && strings.Split(field.Tag.Get(tag), ",")[0] == name {
+			return i
+		}
+		if transcodeProtoName(field.Tag.Get("protobuf")) == name || strings.Split(field.Tag.Get("json"), ",")[0] == name {
+			return i
+		}
+		if fallback < 0 && strings.EqualFold(field.Name, name) {
+			fallback = i
+		}
+	}
+	return fallback
+}
+
+// transcodeProtoName membaca name=... dari tag protobuf, misal `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3"`.
+func transcodeProtoName(tag string) string {
+	for _, part := range strings.Split(tag, ",") {
+		if name, ok := strings.CutPrefix(part, "name="); ok {
+			return name
+		}
+	}
+	return ""
+}
+
+// grpcStatusHTTP memetakan kode status gRPC ke status HTTP (mengikuti grpc-gateway).
+var grpcStatusHTTP = map[uint32]int{
+	0:  http.StatusOK,
+	1:  499, // Canceled
+	2:  http.StatusInternalServerError,
+	3:  http.StatusBadRequest,
+	4:  http.StatusGatewayTimeout,
+	5:  http.StatusNotFound,
+	6:  http.StatusConflict,
+	7:  http.StatusForbidden,
+	8:  http.StatusTooManyRequests,
+	9:  http.StatusBadRequest,
+	10: http.StatusConflict,
+	11: http.StatusBadRequest,
+	12: http.StatusNotImplemented,
+	13: http.StatusInternalServerError,
+	14: http.StatusServiceUnavailable,
+	15: http.StatusInternalServerError,
+	16: http.StatusUnauthorized,
+}
+
+// GRPCStatusToHTTP memetakan kode status gRPC (codes.Code) ke status HTTP.
+// Kode yang tidak dikenal dipetakan ke 500.
+//
+// Example:
+//
+//	status := dim.GRPCStatusToHTTP(uint32(codes.NotFound)) // 404
+func GRPCStatusToHTTP(code uint32) int {
+	if status, ok := grpcStatusHTTP[code]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// grpcStatusFromError membaca kode dan pesan dari error status gRPC tanpa import grpc:
+// error status mengimplementasikan GRPCStatus() yang mengembalikan nilai dengan method Code() dan Message().
+func grpcStatusFromError(err error) (uint32, string, bool) {
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		method := reflect.ValueOf(e).MethodByName("GRPCStatus")
+		if !method.IsValid() || method.Type().NumIn() != 0 || method.Type().NumOut() != 1 {
+			continue
+		}
+		status := method.Call(nil)[0]
+		if status.Kind() == reflect.Ptr && status.IsNil() {
+			continue
+		}
+		code := status.MethodByName("Code")
+		message := status.MethodByName("Message")
+		if !code.IsValid() || !message.IsValid() {
+			continue
+		}
+		codeValue := code.Call(nil)[0]
+		if !codeValue.CanUint() {
+			continue
+		}
+		return uint32(codeValue.Uint()), message.Call(nil)[0].String(), true
+	}
+	return 0, "", false
+}
+
+func writeTranscodeError(w http.ResponseWriter, err error) {
+	if appErr, ok := AsAppError(err); ok {
+		JsonAppError(w, appErr)
+		return
+	}
+
+	status := http.StatusInternalServerError
+	message := ""
+	if code, msg, ok := grpcStatusFromError(err); ok {
+		status, message = GRPCStatusToHTTP(code), msg
+	} else if errors.Is(err, context.DeadlineExceeded) {
+		status = http.StatusGatewayTimeout
+	}
+
+	// Pesan error internal dari service tidak diteruskan ke client
+	switch {
+	case status == http.StatusServiceUnavailable:
+		message = "Layanan tidak tersedia sementara"
+	case status == http.StatusGatewayTimeout:
+		message = "Waktu permintaan habis"
+	case status >= 500:
+		message = "Terjadi kesalahan pada server"
+	}
+	JsonError(w, status, message, nil)
+}
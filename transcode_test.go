@@ -0,0 +1,209 @@
+package dim
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Structs shaped like protoc-gen-go output
+type transcodeUser struct {
+	Id   string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+type transcodePage struct {
+	Size  int32  `protobuf:"varint,1,opt,name=size,proto3" json:"size,omitempty"`
+	Token string `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`
+}
+
+type transcodeRequest struct {
+	OrgId  string         `protobuf:"bytes,1,opt,name=org_id,json=orgId,proto3" json:"org_id,omitempty"`
+	UserId string         `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Fields []string       `protobuf:"bytes,3,rep,name=fields,proto3" json:"fields,omitempty"`
+	Page   *transcodePage `protobuf:"bytes,4,opt,name=page,proto3" json:"page,omitempty"`
+	User   *transcodeUser `protobuf:"bytes,5,opt,name=user,proto3" json:"user,omitempty"`
+}
+
+type fakeGRPCCode uint32
+
+type fakeGRPCStatus struct {
+	code    fakeGRPCCode
+	message string
+}
+
+func (s *fakeGRPCStatus) Code() fakeGRPCCode { return s.code }
+func (s *fakeGRPCStatus) Message() string    { return s.message }
+
+type fakeGRPCError struct{ status *fakeGRPCStatus }
+
+func (e *fakeGRPCError) Error() string               { return e.status.message }
+func (e *fakeGRPCError) GRPCStatus() *fakeGRPCStatus { return e.status }
+
+func TestTranscode_BindsPathQueryAndBody(t *testing.T) {
+	var gotMethod string
+	var gotReq *transcodeRequest
+	var gotMD map[string][]string
+
+	tc := NewTranscoder(func(ctx context.Context, method string, req, resp interface{}) error {
+		gotMethod = method
+		gotReq = req.(*transcodeRequest)
+		gotMD = TranscodeMetadata(ctx)
+		*resp.(*transcodeUser) = transcodeUser{Id: gotReq.UserId, Name: gotReq.User.Name}
+		return nil
+	}).WithForwardHeaders("Authorization", "X-Request-ID")
+
+	router := NewRouter()
+	router.Patch("/v1/orgs/{org_id}/users/{user_id}", Transcode[transcodeRequest, transcodeUser](tc,
+		"/users.v1.UserService/UpdateUser", TranscodeBody("user"), TranscodeStatus(http.StatusAccepted)))
+
+	body := strings.NewReader(`{"id":"ignored","name":"Budi"}`)
+	req := httptest.NewRequest(http.MethodPatch, "/v1/orgs/acme/users/u-1?fields=name,email&page.size=20&user_id=spoofed", body)
+	req.Header.Set("Authorization", "Bearer abc")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if gotMethod != "/users.v1.UserService/UpdateUser" {
+		t.Errorf("method = %q", gotMethod)
+	}
+
+	want := &transcodeRequest{
+		OrgId:  "acme",
+		UserId: "u-1",
+		Fields: []string{"name", "email"},
+		Page:   &transcodePage{Size: 20},
+		User:   &transcodeUser{Id: "ignored", Name: "Budi"},
+	}
+	if !reflect.DeepEqual(gotReq, want) {
+		t.Errorf("request = %+v, want %+v", gotReq, want)
+	}
+	if !reflect.DeepEqual(gotMD, map[string][]string{"authorization": {"Bearer abc"}}) {
+		t.Errorf("metadata = %v", gotMD)
+	}
+
+	var resp transcodeUser
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	if resp.Id != "u-1" || resp.Name != "Budi" {
+		t.Errorf("response = %+v", resp)
+	}
+}
+
+func TestTranscode_WholeBodyAndBindErrors(t *testing.T) {
+	var gotReq *transcodeRequest
+	tc := NewTranscoder(func(ctx context.Context, method string, req, resp interface{}) error {
+		gotReq = req.(*transcodeRequest)
+		return nil
+	})
+	handler := Transcode[transcodeRequest, transcodeUser](tc, "/svc/Method")
+
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"user_id":"u-9","page":{"size":5}}`)))
+	if w.Code != http.StatusOK || gotReq.UserId != "u-9" || gotReq.Page.Size != 5 {
+		t.Errorf("status = %d, request = %+v", w.Code, gotReq)
+	}
+
+	w = httptest.NewRecorder()
+	handler(w, httptest.NewRequest(http.MethodGet, "/?page.size=big", nil))
+	if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "page.size") {
+		t.Errorf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	handler(w, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"user_id":`)))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("invalid JSON: status = %d", w.Code)
+	}
+
+	small := Transcode[transcodeRequest, transcodeUser](NewTranscoder(tc.invoker).WithMaxBytes(8), "/svc/Method")
+	w = httptest.NewRecorder()
+	small(w, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"user_id":"0123456789"}`)))
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("oversized body: status = %d", w.Code)
+	}
+}
+
+func TestTranscode_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantStatus  int
+		wantMessage string
+	}{
+		{"not found", &fakeGRPCError{&fakeGRPCStatus{5, "user u-1 not found"}}, http.StatusNotFound, "user u-1 not found"},
+		{"wrapped permission denied", fmt.Errorf("call: %w", &fakeGRPCError{&fakeGRPCStatus{7, "denied"}}), http.StatusForbidden, "denied"},
+		{"internal hides message", &fakeGRPCError{&fakeGRPCStatus{13, "pq: connection refused"}}, http.StatusInternalServerError, "Terjadi kesalahan pada server"},
+		{"unavailable", &fakeGRPCError{&fakeGRPCStatus{14, "no healthy upstream"}}, http.StatusServiceUnavailable, "Layanan tidak tersedia sementara"},
+		{"app error", NewAppError("Kuota habis", http.StatusPaymentRequired), http.StatusPaymentRequired, "Kuota habis"},
+		{"deadline", context.DeadlineExceeded, http.StatusGatewayTimeout, "Waktu permintaan habis"},
+		{"plain error", errors.New("boom"), http.StatusInternalServerError, "Terjadi kesalahan pada server"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tc := NewTranscoder(func(ctx context.Context, method string, req, resp interface{}) error {
+				return tt.err
+			})
+			w := httptest.NewRecorder()
+			Transcode[transcodeRequest, transcodeUser](tc, "/svc/Method")(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if !strings.Contains(w.Body.String(), tt.wantMessage) {
+				t.Errorf("body = %s, want message %q", w.Body.String(), tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestTranscode_CustomCodecAndErrorHandler(t *testing.T) {
+	tc := NewTranscoder(func(ctx context.Context, method string, req, resp interface{}) error {
+		resp.(*transcodeUser).Name = req.(*transcodeRequest).UserId
+		return nil
+	}).WithCodec(
+		func(data []byte, v interface{}) error {
+			v.(*transcodeRequest).UserId = strings.ToUpper(string(data))
+			return nil
+		},
+		func(v interface{}) ([]byte, error) {
+			return []byte(`{"custom":"` + v.(*transcodeUser).Name + `"}`), nil
+		},
+	)
+
+	w := httptest.NewRecorder()
+	Transcode[transcodeRequest, transcodeUser](tc, "/svc/Method")(w, httptest.NewRequest(http.MethodPost, "/", strings.NewReader("abc")))
+	if w.Body.String() != `{"custom":"ABC"}` || w.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("body = %s", w.Body.String())
+	}
+
+	handled := false
+	tc = NewTranscoder(func(ctx context.Context, method string, req, resp interface{}) error {
+		return errors.New("boom")
+	}).WithErrorHandler(func(w http.ResponseWriter, r *http.Request, err error) {
+		handled = true
+		w.WriteHeader(http.StatusTeapot)
+	})
+	w = httptest.NewRecorder()
+	Transcode[transcodeRequest, transcodeUser](tc, "/svc/Method")(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if !handled || w.Code != http.StatusTeapot {
+		t.Errorf("expected custom error handler, status = %d", w.Code)
+	}
+}
+
+func TestGRPCStatusToHTTP(t *testing.T) {
+	cases := map[uint32]int{0: 200, 3: 400, 5: 404, 7: 403, 8: 429, 12: 501, 16: 401, 99: 500}
+	for code, want := range cases {
+		if got := GRPCStatusToHTTP(code); got != want {
+			t.Errorf("GRPCStatusToHTTP(%d) = %d, want %d", code, got, want)
+		}
+	}
+}
@@ -1,5 +1,7 @@
 package dim
 
+import "time"
+
 // Authenticatable merepresentasikan entitas pengguna yang dapat diotentikasi.
 // Interface ini memungkinkan framework untuk berinteraksi dengan model User apa pun.
 type Authenticatable interface {
@@ -15,6 +17,8 @@ type TokenUser struct {
 	Email    string
 	Password string // Usually empty for token-derived users
 	Claims   map[string]interface{}
+	// DeletedAt diisi jika user sudah di-soft delete (hanya terlihat lewat WithTrashed)
+	DeletedAt *time.Time
 }
 
 func (u *TokenUser) GetID() string {
@@ -36,3 +40,33 @@ func (u *TokenUser) SetPassword(password string) {
 func (u *TokenUser) GetClaims() map[string]interface{} {
 	return u.Claims
 }
+
+// User merepresentasikan satu row lengkap tabel users dari GetUserMigrations, dipakai untuk
+// operasi admin seperti daftar user. Password tidak pernah ikut di-encode ke JSON.
+type User struct {
+	ID        string     `json:"id"`
+	Email     string     `json:"email"`
+	Name      string     `json:"name"`
+	Password  string     `json:"-"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	// Version naik setiap kali row diubah; dipakai UpdateWithVersion untuk optimistic locking
+	Version int64 `json:"version"`
+}
+
+func (u *User) GetID() string {
+	return u.ID
+}
+
+func (u *User) GetEmail() string {
+	return u.Email
+}
+
+func (u *User) GetPassword() string {
+	return u.Password
+}
+
+func (u *User) SetPassword(password string) {
+	u.Password = password
+}
@@ -0,0 +1,214 @@
+package dim
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// UserListColumns adalah allowlist filter dan sort untuk daftar user ke kolom tabel users.
+// Map yang sama dipakai oleh DatabaseAuthUserStore.List dan UserListHandler.
+var UserListColumns = map[string]string{
+	"id":         "id",
+	"email":      "email",
+	"name":       "name",
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+}
+
+// ListUsersQuery berisi hasil FilterParser, SortParser, dan PaginationParser untuk
+// DatabaseAuthUserStore.List.
+type ListUsersQuery struct {
+	// Filters adalah hasil FilterParser.Conditions; field harus ada di UserListColumns.
+	Filters []FilterCondition
+	// Sort adalah hasil SortParser.Parse. Kosong berarti "-created_at". Urutan selalu
+	// ditutup dengan id agar pagination stabil.
+	Sort []SortField
+	// Pagination membatasi hasil; nil berarti semua user dikembalikan.
+	Pagination *Pagination
+}
+
+// UserListFilters adalah struct filter bawaan untuk UserListHandler.
+//
+// Example:
+//
+//	// ?filters[email]=ana@example.com&filters[name][like]=ana&filters[created_at][gte]=2024-01-01
+type UserListFilters struct {
+	ID        []string  `filter:"id"`
+	Email     []string  `filter:"email"`
+	Name      *string   `filter:"name"`
+	CreatedAt TimeRange `filter:"created_at"`
+	UpdatedAt TimeRange `filter:"updated_at"`
+}
+
+// UserLister adalah penyimpanan yang dapat menampilkan daftar user, diimplementasikan oleh
+// DatabaseAuthUserStore.
+type UserLister interface {
+	List(ctx context.Context, q ListUsersQuery) ([]*User, int, error)
+}
+
+// List mengembalikan user yang cocok dengan filter, urut sesuai sort, untuk halaman yang diminta,
+// beserta jumlah total user yang cocok (tanpa pagination). User yang di-soft delete tidak
+// disertakan kecuali ctx dibuat dengan WithTrashed.
+//
+// Parameters:
+//   - ctx: context request
+//   - q: filter, sort, dan pagination
+//
+// Returns:
+//   - []*User: user pada halaman yang diminta
+//   - int: total user yang cocok dengan filter
+//   - error: jika filter atau sort tidak ada di UserListColumns, atau query gagal
+//
+// Example:
+//
+//	users, total, err := userStore.List(ctx, dim.ListUsersQuery{
+//	    Filters:    fp.Conditions(),
+//	    Sort:       sort,
+//	    Pagination: page,
+//	})
+func (s *DatabaseAuthUserStore) List(ctx context.Context, q ListUsersQuery) ([]*User, int, error) {
+	where, args, err := NewFilterSQLBuilder(UserListColumns).
+		WithDriver(s.db.DriverName()).
+		WithConverter("created_at", filterTimeValue).
+		WithConverter("updated_at", filterTimeValue).
+		Build(q.Filters)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var conditions []string
+	if where != "" {
+		conditions = append(conditions, where)
+	}
+	if scope := SoftDeleteScope(ctx, ""); scope != "" {
+		conditions = append(conditions, scope)
+	}
+	whereSQL := ""
+	if len(conditions) > 0 {
+		whereSQL = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	sort := q.Sort
+	if len(sort) == 0 {
+		sort = []SortField{{Field: "created_at", Direction: SortDesc}}
+	}
+	orderBy, err := NewSortParser(nil).WithColumns(UserListColumns).OrderBy(sort)
+	if err != nil {
+		return nil, 0, err
+	}
+	if !sortsByField(sort, "id") {
+		orderBy += ", id ASC"
+	}
+
+	var total int
+	countQuery := s.db.Rebind("SELECT COUNT(*) FROM users" + whereSQL)
+	if err := s.db.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count users: %w", err)
+	}
+
+	query := `SELECT id, email, COALESCE(name, ''), password, created_at, updated_at, deleted_at, version FROM users` +
+		whereSQL + " ORDER BY " + orderBy
+	if q.Pagination != nil {
+		clause, pageArgs := q.Pagination.LimitOffset(len(args))
+		query += " " + clause
+		args = append(args, pageArgs...)
+	}
+
+	rows, err := s.db.Query(ctx, s.db.Rebind(query), args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list users: %w", err)
+	}
+	defer rows.Close()
+
+	users := make([]*User, 0)
+	for rows.Next() {
+		user := &User{}
+		if err := rows.Scan(&user.ID, &user.Email, &user.Name, &user.Password, &user.CreatedAt, &user.UpdatedAt, &user.DeletedAt, &user.Version); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to list users: %w", err)
+	}
+	return users, total, nil
+}
+
+// sortsByField reports whether field is one of the sort fields.
+func sortsByField(fields []SortField, field string) bool {
+	for _, f := range fields {
+		if f.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// filterTimeValue mengubah nilai filter time.Time yang sudah dinormalisasi FilterParser
+// (RFC 3339) menjadi time.Time UTC.
+func filterTimeValue(value string) (interface{}, error) {
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return nil, err
+	}
+	return t.UTC(), nil
+}
+
+// UserListHandler membuat endpoint admin daftar user dengan filter (UserListFilters), sort
+// (?sort=-created_at,email; field dari UserListColumns, maksimal 3), dan pagination
+// (?page=2&limit=50; default 20, maksimal 100). Response memakai format JsonPagination dan
+// tidak pernah menyertakan password. Pasang middleware autentikasi dan otorisasi admin pada route.
+//
+// Parameters:
+//   - users: penyimpanan user, biasanya DatabaseAuthUserStore
+//
+// Returns:
+//   - HandlerFunc: handler GET daftar user
+//
+// Example:
+//
+//	admin := router.Group("/admin", dim.RequireAuth(tokenManager, blocklist), requireAdmin)
+//	admin.Get("/users", dim.UserListHandler(userStore))
+func UserListHandler(users UserLister) HandlerFunc {
+	sortParser := NewSortParser(nil).WithColumns(UserListColumns).WithDefault("-created_at").WithMaxFields(3)
+	pageParser := NewPaginationParser(20, 100)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		fp := NewFilterParser(r)
+		if _, errs := ParseWith[UserListFilters](fp); errs != nil {
+			fieldErrors := make(FieldErrors, len(errs))
+			for key, msg := range errs {
+				fieldErrors[key] = msg
+			}
+			BadRequest(w, "Filter tidak valid", fieldErrors)
+			return
+		}
+
+		sort, err := sortParser.Parse(r)
+		if err != nil {
+			appErr, _ := AsAppError(err)
+			JsonAppError(w, appErr)
+			return
+		}
+		page, err := pageParser.Parse(r)
+		if err != nil {
+			appErr, _ := AsAppError(err)
+			JsonAppError(w, appErr)
+			return
+		}
+
+		list, total, err := users.List(r.Context(), ListUsersQuery{
+			Filters:    fp.Conditions(),
+			Sort:       sort,
+			Pagination: page,
+		})
+		if err != nil {
+			InternalServerError(w, "Gagal memuat daftar user")
+			return
+		}
+		JsonPagination(w, http.StatusOK, list, page.Meta(total))
+	}
+}
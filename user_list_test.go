@@ -0,0 +1,120 @@
+package dim
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// newTestUserListStore membuat DatabaseAuthUserStore SQLite berisi empat user, satu di antaranya di-soft delete.
+func newTestUserListStore(t *testing.T) *DatabaseAuthUserStore {
+	t.Helper()
+	db := newTestSQLiteAuthDB(t)
+	ctx := context.Background()
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	users := []struct{ id, email, name string }{
+		{"u-1", "ana@example.com", "Ana"},
+		{"u-2", "budi@example.com", "Budi"},
+		{"u-3", "citra@example.com", ""},
+		{"u-4", "dewi@example.com", "Dewi"},
+	}
+	for i, u := range users {
+		created := base.AddDate(0, i, 0)
+		err := db.Exec(ctx, db.Rebind(`INSERT INTO users (id, email, name, password, created_at, updated_at) VALUES ($1, $2, $3, 'hash', $4, $5)`),
+			u.id, u.email, u.name, created, created)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	store := NewDatabaseAuthUserStore(db)
+	if err := store.SoftDelete(ctx, "u-4"); err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+func userIDs(users []*User) []string {
+	ids := make([]string, len(users))
+	for i, u := range users {
+		ids[i] = u.ID
+	}
+	return ids
+}
+
+func TestDatabaseAuthUserStore_List_SQLite(t *testing.T) {
+	store := newTestUserListStore(t)
+	ctx := context.Background()
+
+	users, total, err := store.List(ctx, ListUsersQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 3 || len(users) != 3 || users[0].ID != "u-3" || users[2].ID != "u-1" {
+		t.Fatalf("List() = %v, total %d", userIDs(users), total)
+	}
+	if users[2].Name != "Ana" || users[2].CreatedAt.Year() != 2024 || users[0].Name != "" {
+		t.Errorf("user fields = %+v / %+v", users[2], users[0])
+	}
+
+	users, total, err = store.List(ctx, ListUsersQuery{
+		Filters: []FilterCondition{
+			{Field: "created_at", Op: FilterOpGte, Values: []string{"2024-02-01T00:00:00Z"}},
+		},
+		Sort:       []SortField{{Field: "email", Direction: SortAsc}},
+		Pagination: &Pagination{Page: 1, Limit: 1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 2 || len(users) != 1 || users[0].ID != "u-2" {
+		t.Errorf("filtered List() = %v, total %d", userIDs(users), total)
+	}
+
+	users, total, err = store.List(WithTrashed(ctx), ListUsersQuery{
+		Filters: []FilterCondition{{Field: "name", Op: FilterOpLike, Values: []string{"de"}}},
+	})
+	if err != nil || total != 1 || users[0].ID != "u-4" || users[0].DeletedAt == nil {
+		t.Errorf("List(WithTrashed) = %v, total %d, err %v", userIDs(users), total, err)
+	}
+
+	if _, _, err := store.List(ctx, ListUsersQuery{Filters: []FilterCondition{{Field: "password", Op: FilterOpEq, Values: []string{"hash"}}}}); err == nil {
+		t.Error("expected error for filter outside UserListColumns")
+	}
+	if _, _, err := store.List(ctx, ListUsersQuery{Sort: []SortField{{Field: "password", Direction: SortAsc}}}); err == nil {
+		t.Error("expected error for sort outside UserListColumns")
+	}
+}
+
+func TestUserListHandler(t *testing.T) {
+	handler := UserListHandler(newTestUserListStore(t))
+
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, "/admin/users?filters[email]=ana@example.com,budi@example.com&sort=email&limit=1&page=2", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	var resp struct {
+		Data []map[string]interface{} `json:"data"`
+		Meta PaginationMeta           `json:"meta"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Data) != 1 || resp.Data[0]["id"] != "u-2" || resp.Meta.Total != 2 || resp.Meta.TotalPages != 2 {
+		t.Errorf("response = %s", rec.Body.String())
+	}
+	if _, ok := resp.Data[0]["password"]; ok {
+		t.Error("password must not be encoded")
+	}
+
+	for _, query := range []string{"sort=password", "filters[created_at]=bukan-tanggal", "limit=0"} {
+		rec = httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(http.MethodGet, "/admin/users?"+query, nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", query, rec.Code)
+		}
+	}
+}
@@ -5,7 +5,8 @@ import (
 )
 
 // GetUserMigrations mengembalikan daftar migrasi terkait tabel users.
-// Mencakup pembuatan tabel users dasar.
+// Mencakup pembuatan tabel users dasar, kolom soft delete (deleted_at), dan kolom
+// version untuk optimistic locking.
 func GetUserMigrations() []Migration {
 	return []Migration{
 		{
@@ -25,6 +26,18 @@ func GetUserMigrations() []Migration {
 						);
 						CREATE INDEX IF NOT EXISTS idx_users_email ON users(email);
 					`
+				} else if db.DriverName() == "mysql" {
+					// Kolom email UNIQUE sudah memiliki index
+					query = `
+						CREATE TABLE IF NOT EXISTS users (
+							id CHAR(36) PRIMARY KEY,
+							email VARCHAR(255) UNIQUE NOT NULL,
+							name VARCHAR(100),
+							password VARCHAR(255) NOT NULL,
+							created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+							updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+						) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+					`
 				} else {
 					query = `
 						CREATE TABLE IF NOT EXISTS users (
@@ -48,5 +61,7 @@ func GetUserMigrations() []Migration {
 				return db.Exec(context.Background(), query)
 			},
 		},
+		AddSoftDeleteColumn(6, "users"),
+		AddVersionColumn(7, "users"),
 	}
 }
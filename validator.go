@@ -12,6 +12,7 @@ import (
 type Validator struct {
 	errors     map[string][]string
 	fullErrors bool
+	locale     string
 }
 
 // NewValidator membuat instance Validator baru dengan empty error map.
@@ -62,6 +63,29 @@ func (v *Validator) WithFullErrors() *Validator {
 	return v
 }
 
+// WithLocale mengatur locale pesan error yang dihasilkan validator.
+// Berlaku untuk validasi setelah pemanggilan ini; default menggunakan locale fallback Translator.
+//
+// Parameters:
+//   - locale: kode locale, misal "en" atau dim.GetLocale(r)
+//
+// Returns:
+//   - *Validator: pointer to validator untuk method chaining
+//
+// Example:
+//
+//	v := NewValidator().WithLocale(dim.GetLocale(r)).
+//	  Required("email", email)
+func (v *Validator) WithLocale(locale string) *Validator {
+	v.locale = locale
+	return v
+}
+
+// t menerjemahkan pesan ke locale validator.
+func (v *Validator) t(message string, args ...interface{}) string {
+	return Translate(v.locale, message, args...)
+}
+
 // addError menambahkan error ke field berdasarkan mode aktif.
 // Default: skip jika field sudah punya error (first-error-wins).
 // Full-errors: selalu append.
@@ -87,7 +111,7 @@ func (v *Validator) addError(field, message string) {
 //	v.Required("email", email)
 func (v *Validator) Required(field, value string) *Validator {
 	if strings.TrimSpace(value) == "" {
-		v.addError(field, field+" wajib diisi")
+		v.addError(field, v.t("%s wajib diisi", field))
 	}
 	return v
 }
@@ -108,7 +132,51 @@ func (v *Validator) Required(field, value string) *Validator {
 func (v *Validator) Email(field, value string) *Validator {
 	emailRegex := regexp.MustCompile(`^[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}$`)
 	if !emailRegex.MatchString(value) {
-		v.addError(field, field+" harus berupa alamat email yang valid")
+		v.addError(field, v.t("%s harus berupa alamat email yang valid", field))
+	}
+	return v
+}
+
+// Phone memvalidasi bahwa value adalah nomor telepon yang valid (lihat ParsePhone).
+//
+// Parameters:
+//   - field: nama field untuk error message
+//   - value: nomor telepon yang akan divalidasi
+//   - region: kode negara ISO opsional untuk nomor tanpa kode negara (default DefaultPhoneRegion)
+//
+// Returns:
+//   - *Validator: pointer to validator untuk method chaining
+//
+// Example:
+//
+//	v.Phone("phone", req.Phone)
+//	v.Phone("phone", req.Phone, "MY")
+func (v *Validator) Phone(field, value string, region ...string) *Validator {
+	r := ""
+	if len(region) > 0 {
+		r = region[0]
+	}
+	if !IsValidPhone(value, r) {
+		v.addError(field, v.t("%s harus berupa nomor telepon yang valid", field))
+	}
+	return v
+}
+
+// GeoPoint memvalidasi bahwa latitude di -90..90 dan longitude di -180..180.
+//
+// Parameters:
+//   - field: nama field untuk error message
+//   - value: koordinat yang akan divalidasi
+//
+// Returns:
+//   - *Validator: pointer to validator untuk method chaining
+//
+// Example:
+//
+//	v.GeoPoint("location", req.Location)
+func (v *Validator) GeoPoint(field string, value GeoPoint) *Validator {
+	if value.Validate() != nil {
+		v.addError(field, v.t("%s harus berupa koordinat yang valid", field))
 	}
 	return v
 }
@@ -129,7 +197,7 @@ func (v *Validator) Email(field, value string) *Validator {
 //	v.MinLength("password", password, 8)
 func (v *Validator) MinLength(field, value string, min int) *Validator {
 	if len(strings.TrimSpace(value)) < min {
-		v.addError(field, field+" harus minimal "+string(rune(min))+" karakter")
+		v.addError(field, v.t("%s harus minimal %d karakter", field, min))
 	}
 	return v
 }
@@ -149,7 +217,7 @@ func (v *Validator) MinLength(field, value string, min int) *Validator {
 //	v.MaxLength("name", name, 255)
 func (v *Validator) MaxLength(field, value string, max int) *Validator {
 	if len(value) > max {
-		v.addError(field, field+" tidak boleh melebihi "+string(rune(max))+" karakter")
+		v.addError(field, v.t("%s tidak boleh melebihi %d karakter", field, max))
 	}
 	return v
 }
@@ -169,7 +237,7 @@ func (v *Validator) MaxLength(field, value string, max int) *Validator {
 //	v.Length("code", code, 6)
 func (v *Validator) Length(field, value string, length int) *Validator {
 	if len(value) != length {
-		v.addError(field, field+" harus tepat "+string(rune(length))+" karakter")
+		v.addError(field, v.t("%s harus tepat %d karakter", field, length))
 	}
 	return v
 }
@@ -193,11 +261,11 @@ func (v *Validator) Pattern(field, value string, pattern string) *Validator {
 	}
 	regex, err := regexp.Compile(pattern)
 	if err != nil {
-		v.addError(field, "pola validasi tidak valid")
+		v.addError(field, v.t("pola validasi tidak valid"))
 		return v
 	}
 	if !regex.MatchString(value) {
-		v.addError(field, "format "+field+" tidak valid")
+		v.addError(field, v.t("format %s tidak valid", field))
 	}
 	return v
 }
@@ -218,7 +286,7 @@ func (v *Validator) Pattern(field, value string, pattern string) *Validator {
 //	v.Custom("username", func(u string) bool { return len(u) > 3 }, username, "Username minimal 4 karakter")
 func (v *Validator) Custom(field string, fn func(string) bool, value string, message string) *Validator {
 	if !fn(value) {
-		v.addError(field, message)
+		v.addError(field, v.t(message))
 	}
 	return v
 }
@@ -238,7 +306,7 @@ func (v *Validator) Custom(field string, fn func(string) bool, value string, mes
 //	v.In("role", role, "admin", "user", "guest")
 func (v *Validator) In(field, value string, allowed ...string) *Validator {
 	if !slices.Contains(allowed, value) {
-		v.addError(field, field+" memiliki nilai yang tidak valid")
+		v.addError(field, v.t("%s memiliki nilai yang tidak valid", field))
 	}
 	return v
 }
@@ -259,7 +327,7 @@ func (v *Validator) In(field, value string, allowed ...string) *Validator {
 //	v.NumRange("age", age, 18, 120)
 func (v *Validator) NumRange(field string, value, min, max int) *Validator {
 	if value < min || value > max {
-		v.addError(field, field+" harus antara "+string(rune(min))+" dan "+string(rune(max)))
+		v.addError(field, v.t("%s harus antara %d dan %d", field, min, max))
 	}
 	return v
 }
@@ -281,7 +349,7 @@ func (v *Validator) NumRange(field string, value, min, max int) *Validator {
 //	v.Matches("password", password, "password_confirmation", passwordConfirm)
 func (v *Validator) Matches(field, value, otherField, otherValue string) *Validator {
 	if value != otherValue {
-		v.addError(field, field+" tidak cocok dengan "+otherField)
+		v.addError(field, v.t("%s tidak cocok dengan %s", field, otherField))
 	}
 	return v
 }
@@ -358,7 +426,7 @@ func (v *Validator) ErrorMap() FieldErrors {
 //
 //	v.AddError("email", "Email sudah terdaftar")
 func (v *Validator) AddError(field, message string) *Validator {
-	v.addError(field, message)
+	v.addError(field, v.t(message))
 	return v
 }
 
@@ -536,3 +604,23 @@ func (v *Validator) OptionalMatches(field string, value JsonNull[string], patter
 	}
 	return v
 }
+
+// OptionalPhone memvalidasi nomor telepon hanya jika field present dan valid.
+//
+// Parameters:
+//   - field: nama field untuk error message
+//   - value: JsonNull[string] field value
+//   - region: kode negara ISO opsional (default DefaultPhoneRegion)
+//
+// Returns:
+//   - *Validator: pointer to validator untuk method chaining
+//
+// Example:
+//
+//	v.OptionalPhone("phone", phoneJsonNull)
+func (v *Validator) OptionalPhone(field string, value JsonNull[string], region ...string) *Validator {
+	if value.Present && value.Valid {
+		v.Phone(field, value.Value, region...)
+	}
+	return v
+}
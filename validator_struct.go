@@ -0,0 +1,437 @@
+package dim
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// TagValidator memvalidasi satu aturan tag `validate` terhadap nilai field.
+// value sudah di-dereference (pointer dan JsonNull), param berisi teks setelah "=".
+// Mengembalikan pesan error, atau string kosong jika nilai valid.
+//
+// Example:
+//
+//	func(field string, value reflect.Value, param string) string {
+//	  if value.Kind() == reflect.String && !strings.HasPrefix(value.String(), param) {
+//	    return localizedErrorf("%s harus diawali ", field) + param
+//	  }
+//	  return ""
+//	}
+type TagValidator func(field string, value reflect.Value, param string) string
+
+var (
+	tagValidatorsMu sync.RWMutex
+	tagValidators   = map[string]TagValidator{}
+)
+
+// builtinTagRule adalah aturan bawaan; pesan dikembalikan sebagai localizedError agar
+// diterjemahkan ke locale Validator.
+type builtinTagRule func(field string, value reflect.Value, param string) error
+
+var builtinTagRules = map[string]builtinTagRule{
+	"email":    validateTagEmail,
+	"min":      validateTagMin,
+	"max":      validateTagMax,
+	"len":      validateTagLen,
+	"oneof":    validateTagOneOf,
+	"url":      validateTagURL,
+	"uuid":     validateTagUUID,
+	"numeric":  validateTagNumeric,
+	"alphanum": validateTagAlphanum,
+	"honeypot": validateTagHoneypot,
+	"phone":    validateTagPhone,
+	"latlng":   validateTagLatLng,
+}
+
+// RegisterTagValidator mendaftarkan aturan custom untuk tag `validate`.
+// Aturan custom diperiksa lebih dulu sehingga dapat menggantikan aturan bawaan dengan nama sama.
+// Pesan yang dikembalikan ikut diterjemahkan jika Translator memiliki padanannya.
+// Aman dipanggil secara concurrent, namun sebaiknya dipanggil saat inisialisasi aplikasi.
+//
+// Parameters:
+//   - name: nama aturan di tag, misal "slug"
+//   - fn: TagValidator yang memvalidasi nilai
+//
+// Example:
+//
+//	dim.RegisterTagValidator("slug", func(field string, value reflect.Value, param string) string {
+//	  if !slugRegex.MatchString(value.String()) {
+//	    return localizedErrorf("%s harus berupa slug", field)
+//	  }
+//	  return ""
+//	})
+//
+//	type CreatePostRequest struct {
+//	  Slug string `json:"slug" validate:"required,slug"`
+//	}
+func RegisterTagValidator(name string, fn TagValidator) {
+	tagValidatorsMu.Lock()
+	defer tagValidatorsMu.Unlock()
+	tagValidators[name] = fn
+}
+
+func lookupTagValidator(name string) (TagValidator, bool) {
+	tagValidatorsMu.RLock()
+	defer tagValidatorsMu.RUnlock()
+	fn, ok := tagValidators[name]
+	return fn, ok
+}
+
+// ValidateStruct memvalidasi struct berdasarkan tag `validate` dan mengembalikan
+// FieldErrors dengan bentuk yang sama seperti Validator.ErrorMap(), atau nil jika valid.
+// Nama field diambil dari tag json (lalu form, query), fallback ke nama field Go.
+// Struct bersarang menghasilkan path "address.city", slice of struct menghasilkan "items.0.name".
+//
+// Aturan bawaan: required, omitempty, email, min, max, len, oneof (dipisah "|"), url, uuid,
+// numeric, alphanum, phone (opsional dengan region, misal phone=MY), latlng (GeoPoint atau
+// "lat,lng"). min/max/len berlaku untuk jumlah karakter string, nilai angka, atau jumlah item
+// slice/map. Aturan yang tidak dikenal menyebabkan panic karena merupakan bug program.
+//
+// Parameters:
+//   - s: struct atau pointer ke struct
+//
+// Returns:
+//   - FieldErrors: map field ke pesan error, nil jika tidak ada error
+//
+// Example:
+//
+//	type RegisterRequest struct {
+//	  Email    string `json:"email" validate:"required,email"`
+//	  Password string `json:"password" validate:"required,min=8"`
+//	  Role     string `json:"role" validate:"omitempty,oneof=admin|user"`
+//	}
+//
+//	if errs := dim.ValidateStruct(req); errs != nil {
+//	  dim.BadRequest(w, "Validasi gagal", errs)
+//	}
+func ValidateStruct(s interface{}) FieldErrors {
+	v := NewValidator().Struct(s)
+	if v.IsValid() {
+		return nil
+	}
+	return v.ErrorMap()
+}
+
+// Struct menjalankan validasi tag `validate` pada struct dan menambahkan error ke validator.
+// Dapat dikombinasikan dengan aturan fluent lain dan mengikuti mode WithFullErrors.
+//
+// Parameters:
+//   - s: struct atau pointer ke struct
+//
+// Returns:
+//   - *Validator: pointer to validator untuk method chaining
+//
+// Example:
+//
+//	v := NewValidator().
+//	  Struct(req).
+//	  Matches("password", req.Password, "password_confirmation", req.PasswordConfirmation)
+func (v *Validator) Struct(s interface{}) *Validator {
+	rv := reflect.ValueOf(s)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return v
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() == reflect.Struct {
+		v.validateStructValue(rv, "")
+	}
+	return v
+}
+
+func (v *Validator) validateStructValue(rv reflect.Value, prefix string) {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		fv := rv.Field(i)
+
+		if sf.Anonymous && sf.Tag.Get("validate") == "" {
+			if inner, ok := derefValue(fv); ok && inner.Kind() == reflect.Struct {
+				v.validateStructValue(inner, prefix)
+			}
+			continue
+		}
+
+		name := validationFieldName(sf)
+		if name == "-" {
+			continue
+		}
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+
+		tag := sf.Tag.Get("validate")
+		if tag == "-" {
+			continue
+		}
+		if tag != "" && !v.validateFieldRules(name, fv, tag) {
+			continue
+		}
+
+		v.validateNested(name, fv)
+	}
+}
+
+// validateNested turun ke struct bersarang dan elemen slice/array bertipe struct.
+func (v *Validator) validateNested(name string, fv reflect.Value) {
+	inner, ok := derefValue(fv)
+	if !ok {
+		return
+	}
+
+	switch inner.Kind() {
+	case reflect.Struct:
+		if !isLeafStruct(inner.Type()) {
+			v.validateStructValue(inner, name)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < inner.Len(); i++ {
+			elem, ok := derefValue(inner.Index(i))
+			if ok && elem.Kind() == reflect.Struct && !isLeafStruct(elem.Type()) {
+				v.validateStructValue(elem, name+"."+strconv.Itoa(i))
+			}
+		}
+	}
+}
+
+// validateFieldRules menjalankan aturan tag pada satu field.
+// Mengembalikan false jika validasi nested sebaiknya dilewati (nilai kosong).
+func (v *Validator) validateFieldRules(name string, fv reflect.Value, tag string) bool {
+	value, present := derefValue(fv)
+	empty := !present || value.IsZero()
+
+	rules := strings.Split(tag, ",")
+	if slices.Contains(rules, "omitempty") && empty {
+		return false
+	}
+
+	for _, rule := range rules {
+		rule = strings.TrimSpace(rule)
+		key, param, _ := strings.Cut(rule, "=")
+		switch key {
+		case "", "omitempty":
+			continue
+		case "required":
+			if empty {
+				v.addError(name, v.t("%s wajib diisi", name))
+				return false
+			}
+			continue
+		}
+
+		if !present {
+			continue
+		}
+
+		if fn, ok := lookupTagValidator(key); ok {
+			if msg := fn(name, value, param); msg != "" {
+				v.addError(name, v.t(msg))
+			}
+			continue
+		}
+
+		builtin, ok := builtinTagRules[key]
+		if !ok {
+			panic(fmt.Sprintf("dim: unknown validation rule %q on field %s", key, name))
+		}
+		if err := builtin(name, value, param); err != nil {
+			v.addError(name, translateError(v.locale, err))
+		}
+	}
+
+	return !empty
+}
+
+// derefValue melepas pointer, interface, dan JsonNull.
+// Mengembalikan false jika nilai nil, null, atau JsonNull yang tidak di-set.
+func derefValue(fv reflect.Value) (reflect.Value, bool) {
+	for {
+		switch fv.Kind() {
+		case reflect.Ptr, reflect.Interface:
+			if fv.IsNil() {
+				return fv, false
+			}
+			fv = fv.Elem()
+			continue
+		case reflect.Struct:
+			if isJsonNullType(fv.Type()) {
+				if !fv.FieldByName("Present").Bool() || !fv.FieldByName("Valid").Bool() {
+					return fv, false
+				}
+				fv = fv.FieldByName("Value")
+				continue
+			}
+		}
+		return fv, true
+	}
+}
+
+func isJsonNullType(t reflect.Type) bool {
+	if t.NumField() != 3 {
+		return false
+	}
+	_, hasValue := t.FieldByName("Value")
+	valid, hasValid := t.FieldByName("Valid")
+	present, hasPresent := t.FieldByName("Present")
+	return hasValue && hasValid && hasPresent &&
+		valid.Type.Kind() == reflect.Bool && present.Type.Kind() == reflect.Bool
+}
+
+// isLeafStruct menandai struct yang divalidasi sebagai nilai, bukan ditelusuri field-nya (misal time.Time).
+func isLeafStruct(t reflect.Type) bool {
+	return t == timeType || reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+func validationFieldName(sf reflect.StructField) string {
+	for _, key := range []string{"json", "form", "query"} {
+		if tag := sf.Tag.Get(key); tag != "" {
+			name, _, _ := strings.Cut(tag, ",")
+			if name != "" {
+				return name
+			}
+		}
+	}
+	return sf.Name
+}
+
+var (
+	tagEmailRegex    = regexp.MustCompile(`^[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}$`)
+	tagUUIDRegex     = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	tagAlphanumRegex = regexp.MustCompile(`^[a-zA-Z0-9]+$`)
+)
+
+func validateTagEmail(field string, value reflect.Value, _ string) error {
+	if value.Kind() != reflect.String || !tagEmailRegex.MatchString(value.String()) {
+		return localizedErrorf("%s harus berupa alamat email yang valid", field)
+	}
+	return nil
+}
+
+func validateTagURL(field string, value reflect.Value, _ string) error {
+	if value.Kind() == reflect.String {
+		if u, err := url.ParseRequestURI(value.String()); err == nil && u.Scheme != "" && u.Host != "" {
+			return nil
+		}
+	}
+	return localizedErrorf("%s harus berupa URL yang valid", field)
+}
+
+// validateTagHoneypot menolak field honeypot yang terisi; field ini disembunyikan dari
+// manusia sehingga hanya bot yang mengisinya.
+func validateTagHoneypot(field string, value reflect.Value, _ string) error {
+	if !value.IsZero() {
+		return localizedErrorf("%s harus dikosongkan", field)
+	}
+	return nil
+}
+
+// validateTagPhone memvalidasi nomor telepon untuk region param (default DefaultPhoneRegion).
+func validateTagPhone(field string, value reflect.Value, param string) error {
+	if value.Kind() != reflect.String || !IsValidPhone(value.String(), param) {
+		return localizedErrorf("%s harus berupa nomor telepon yang valid", field)
+	}
+	return nil
+}
+
+// validateTagLatLng memvalidasi GeoPoint atau string "lat,lng".
+func validateTagLatLng(field string, value reflect.Value, _ string) error {
+	var err error
+	switch v := value.Interface().(type) {
+	case GeoPoint:
+		err = v.Validate()
+	case string:
+		_, err = ParseGeoPoint(v)
+	default:
+		err = ErrInvalidGeoPoint
+	}
+	if err != nil {
+		return localizedErrorf("%s harus berupa koordinat yang valid", field)
+	}
+	return nil
+}
+
+func validateTagUUID(field string, value reflect.Value, _ string) error {
+	if value.Kind() != reflect.String || !tagUUIDRegex.MatchString(value.String()) {
+		return localizedErrorf("%s harus berupa UUID yang valid", field)
+	}
+	return nil
+}
+
+func validateTagNumeric(field string, value reflect.Value, _ string) error {
+	if value.Kind() == reflect.String {
+		if _, err := strconv.ParseFloat(value.String(), 64); err != nil {
+			return localizedErrorf("%s harus berupa angka", field)
+		}
+	}
+	return nil
+}
+
+func validateTagAlphanum(field string, value reflect.Value, _ string) error {
+	if value.Kind() != reflect.String || !tagAlphanumRegex.MatchString(value.String()) {
+		return localizedErrorf("%s hanya boleh berisi huruf dan angka", field)
+	}
+	return nil
+}
+
+func validateTagOneOf(field string, value reflect.Value, param string) error {
+	if !slices.Contains(strings.Split(param, "|"), fmt.Sprint(value.Interface())) {
+		return localizedErrorf("%s memiliki nilai yang tidak valid", field)
+	}
+	return nil
+}
+
+func validateTagMin(field string, value reflect.Value, param string) error {
+	return compareTagSize(field, value, param, func(n, limit float64) bool { return n >= limit },
+		"%s harus minimal %s karakter", "%s harus minimal %s", "%s harus berisi minimal %s item")
+}
+
+func validateTagMax(field string, value reflect.Value, param string) error {
+	return compareTagSize(field, value, param, func(n, limit float64) bool { return n <= limit },
+		"%s tidak boleh melebihi %s karakter", "%s tidak boleh lebih dari %s", "%s tidak boleh berisi lebih dari %s item")
+}
+
+func validateTagLen(field string, value reflect.Value, param string) error {
+	return compareTagSize(field, value, param, func(n, limit float64) bool { return n == limit },
+		"%s harus tepat %s karakter", "%s harus bernilai %s", "%s harus berisi tepat %s item")
+}
+
+// compareTagSize membandingkan panjang string, nilai angka, atau jumlah item dengan param.
+func compareTagSize(field string, value reflect.Value, param string, ok func(n, limit float64) bool, strMsg, numMsg, lenMsg string) error {
+	limit, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		panic(fmt.Sprintf("dim: invalid validation parameter %q on field %s", param, field))
+	}
+
+	var n float64
+	msg := numMsg
+	switch value.Kind() {
+	case reflect.String:
+		n, msg = float64(utf8.RuneCountInString(value.String())), strMsg
+	case reflect.Slice, reflect.Array, reflect.Map:
+		n, msg = float64(value.Len()), lenMsg
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n = float64(value.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n = float64(value.Uint())
+	case reflect.Float32, reflect.Float64:
+		n = value.Float()
+	default:
+		return nil
+	}
+
+	if ok(n, limit) {
+		return nil
+	}
+	return localizedErrorf(msg, field, param)
+}
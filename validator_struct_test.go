@@ -0,0 +1,196 @@
+package dim
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type structTestAddress struct {
+	City    string `json:"city" validate:"required"`
+	ZipCode string `json:"zip_code" validate:"omitempty,len=5,numeric"`
+}
+
+type structTestItem struct {
+	SKU      string `json:"sku" validate:"required,alphanum"`
+	Quantity int    `json:"quantity" validate:"min=1,max=100"`
+}
+
+type structTestRequest struct {
+	Email    string            `json:"email" validate:"required,email"`
+	Password string            `json:"password" validate:"required,min=8"`
+	Role     string            `json:"role" validate:"omitempty,oneof=admin|user"`
+	Website  *string           `json:"website" validate:"omitempty,url"`
+	Address  structTestAddress `json:"address"`
+	Items    []structTestItem  `json:"items" validate:"required,max=3"`
+	Internal string            `json:"-" validate:"required"`
+}
+
+func TestValidateStruct_Valid(t *testing.T) {
+	website := "https://example.com"
+	req := structTestRequest{
+		Email:    "user@example.com",
+		Password: "rahasia123",
+		Role:     "admin",
+		Website:  &website,
+		Address:  structTestAddress{City: "Bandung", ZipCode: "40111"},
+		Items:    []structTestItem{{SKU: "ABC123", Quantity: 2}},
+	}
+
+	if errs := ValidateStruct(&req); errs != nil {
+		t.Errorf("expected no errors, got %v", errs)
+	}
+}
+
+func TestValidateStruct_Errors(t *testing.T) {
+	website := "not a url"
+	req := structTestRequest{
+		Email:    "invalid",
+		Password: "short",
+		Role:     "root",
+		Website:  &website,
+		Address:  structTestAddress{ZipCode: "12"},
+		Items: []structTestItem{
+			{SKU: "ok1", Quantity: 1},
+			{SKU: "bad sku", Quantity: 0},
+		},
+	}
+
+	errs := ValidateStruct(req)
+	want := map[string]string{
+		"email":            "email harus berupa alamat email yang valid",
+		"password":         "password harus minimal 8 karakter",
+		"role":             "role memiliki nilai yang tidak valid",
+		"website":          "website harus berupa URL yang valid",
+		"address.city":     "address.city wajib diisi",
+		"address.zip_code": "address.zip_code harus tepat 5 karakter",
+		"items.1.sku":      "items.1.sku hanya boleh berisi huruf dan angka",
+		"items.1.quantity": "items.1.quantity harus minimal 1",
+	}
+
+	for field, msg := range want {
+		if errs[field] != msg {
+			t.Errorf("errs[%q] = %v, want %q", field, errs[field], msg)
+		}
+	}
+	if len(errs) != len(want) {
+		t.Errorf("expected %d errors, got %d: %v", len(want), len(errs), errs)
+	}
+}
+
+func TestValidateStruct_RequiredAndSliceLength(t *testing.T) {
+	req := structTestRequest{Items: make([]structTestItem, 4)}
+	for i := range req.Items {
+		req.Items[i] = structTestItem{SKU: "A1", Quantity: 1}
+	}
+
+	errs := ValidateStruct(&req)
+	if errs["email"] != "email wajib diisi" {
+		t.Errorf("unexpected email error %v", errs["email"])
+	}
+	if errs["items"] != "items tidak boleh berisi lebih dari 3 item" {
+		t.Errorf("unexpected items error %v", errs["items"])
+	}
+	// omitempty melewati aturan lain untuk field kosong
+	if _, ok := errs["role"]; ok {
+		t.Errorf("expected empty role to be skipped, got %v", errs["role"])
+	}
+}
+
+func TestValidateStruct_JsonNullAndLeafStructs(t *testing.T) {
+	type patchRequest struct {
+		Name      JsonNull[string] `json:"name" validate:"omitempty,min=3"`
+		Nickname  JsonNull[string] `json:"nickname" validate:"required"`
+		StartedAt time.Time        `json:"started_at" validate:"required"`
+	}
+
+	errs := ValidateStruct(patchRequest{Name: NewJsonNull("ab")})
+	if errs["name"] != "name harus minimal 3 karakter" {
+		t.Errorf("unexpected name error %v", errs["name"])
+	}
+	if errs["nickname"] != "nickname wajib diisi" {
+		t.Errorf("unexpected nickname error %v", errs["nickname"])
+	}
+	if errs["started_at"] != "started_at wajib diisi" {
+		t.Errorf("unexpected started_at error %v", errs["started_at"])
+	}
+
+	errs = ValidateStruct(patchRequest{Nickname: NewJsonNull("x"), StartedAt: time.Now()})
+	if errs != nil {
+		t.Errorf("expected unset JsonNull to be skipped, got %v", errs)
+	}
+}
+
+func TestValidateStruct_FullErrors(t *testing.T) {
+	req := struct {
+		Code string `json:"code" validate:"len=4,numeric"`
+	}{Code: "abc"}
+
+	errs := NewValidator().WithFullErrors().Struct(req).ErrorMap()
+	msgs, ok := errs["code"].([]string)
+	if !ok || len(msgs) != 2 {
+		t.Errorf("expected two errors for code, got %v", errs["code"])
+	}
+}
+
+func TestRegisterTagValidator(t *testing.T) {
+	RegisterTagValidator("prefix", func(field string, value reflect.Value, param string) string {
+		if !strings.HasPrefix(value.String(), param) {
+			return field + " harus diawali " + param
+		}
+		return ""
+	})
+	defer func() {
+		tagValidatorsMu.Lock()
+		delete(tagValidators, "prefix")
+		tagValidatorsMu.Unlock()
+	}()
+
+	req := struct {
+		Code string `json:"code" validate:"required,prefix=INV-"`
+	}{Code: "PO-1"}
+
+	errs := ValidateStruct(req)
+	if errs["code"] != "code harus diawali INV-" {
+		t.Errorf("unexpected error %v", errs["code"])
+	}
+}
+
+func TestValidateStruct_UnknownRulePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for unknown rule")
+		}
+	}()
+
+	ValidateStruct(struct {
+		Name string `validate:"nonexistent"`
+	}{Name: "x"})
+}
+
+func TestBind_ValidateTags(t *testing.T) {
+	type createRequest struct {
+		Email string `json:"email" validate:"required,email"`
+		Page  int    `query:"page" validate:"omitempty,min=1"`
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/?page=0", strings.NewReader(`{"email":"nope"}`))
+	r.Header.Set("Content-Type", "application/json")
+
+	var req createRequest
+	appErr := bindAppError(t, Bind(r, &req))
+	if appErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", appErr.StatusCode)
+	}
+	if _, ok := appErr.Errors["email"]; !ok {
+		t.Errorf("expected email field error, got %v", appErr.Errors)
+	}
+
+	r = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"email":"nope"}`))
+	if err := Bind(r, &req, WithoutValidation()); err != nil {
+		t.Errorf("expected validation to be skipped, got %v", err)
+	}
+}
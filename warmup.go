@@ -0,0 +1,277 @@
+package dim
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/atfromhome/goreus/pkg/cache"
+)
+
+// WarmupPolicy menentukan apa yang terjadi ketika sebuah loader warmup gagal.
+type WarmupPolicy int
+
+const (
+	// WarmupRequired: kegagalan loader membatalkan startup (server tidak dijalankan).
+	WarmupRequired WarmupPolicy = iota
+	// WarmupOptional: kegagalan loader hanya di-log, data akan dimuat saat cache miss.
+	WarmupOptional
+)
+
+// String mengembalikan nama policy untuk logging.
+func (p WarmupPolicy) String() string {
+	switch p {
+	case WarmupRequired:
+		return "required"
+	case WarmupOptional:
+		return "optional"
+	default:
+		return "unknown"
+	}
+}
+
+// WarmupFunc memuat data referensi dan mengisi cache.
+// Context akan dibatalkan ketika timeout task habis.
+type WarmupFunc func(ctx context.Context) error
+
+// WarmupTask mendeskripsikan satu loader data referensi.
+type WarmupTask struct {
+	// Name adalah nama unik task, dipakai untuk logging dan report (contoh: "countries").
+	Name string
+	// Load memuat data dan mengisi cache.
+	Load WarmupFunc
+	// Timeout adalah batas waktu task. Jika 0, timeout default Warmup digunakan.
+	Timeout time.Duration
+	// Policy menentukan apakah kegagalan task membatalkan startup (default: WarmupRequired).
+	Policy WarmupPolicy
+}
+
+// WarmupResult berisi hasil eksekusi satu task warmup.
+type WarmupResult struct {
+	Name     string
+	Policy   WarmupPolicy
+	Duration time.Duration
+	Err      error
+}
+
+// Warmup adalah registry loader data referensi (negara, settings, feature flags) yang
+// dijalankan secara concurrent saat startup, sebelum server menerima traffic.
+// Thread-safe.
+type Warmup struct {
+	mu          sync.Mutex
+	tasks       []WarmupTask
+	names       map[string]bool
+	timeout     time.Duration
+	concurrency int
+}
+
+// NewWarmup membuat registry Warmup kosong dengan timeout default 30 detik per task
+// dan tanpa batas concurrency.
+//
+// Returns:
+//   - *Warmup: registry yang siap diisi dengan Register
+//
+// Example:
+//
+//	warmup := dim.NewWarmup().WithTimeout(10 * time.Second)
+//	warmup.Register(dim.WarmupTask{Name: "countries", Load: countryStore.Warm})
+//	warmup.Register(dim.WarmupTask{Name: "feature_flags", Load: flags.Warm, Policy: dim.WarmupOptional})
+func NewWarmup() *Warmup {
+	return &Warmup{
+		names:   make(map[string]bool),
+		timeout: 30 * time.Second,
+	}
+}
+
+// WithTimeout mengatur timeout default untuk task yang tidak menentukan Timeout sendiri.
+func (w *Warmup) WithTimeout(timeout time.Duration) *Warmup {
+	w.timeout = timeout
+	return w
+}
+
+// WithConcurrency membatasi jumlah task yang berjalan bersamaan. 0 berarti tanpa batas.
+// Berguna agar warmup tidak menghabiskan connection pool database.
+func (w *Warmup) WithConcurrency(n int) *Warmup {
+	w.concurrency = n
+	return w
+}
+
+// Register mendaftarkan task warmup. Name harus unik dan Load tidak boleh nil.
+//
+// Parameters:
+//   - task: WarmupTask yang akan didaftarkan
+//
+// Returns:
+//   - error: error jika nama kosong, sudah terdaftar, atau Load nil
+func (w *Warmup) Register(task WarmupTask) error {
+	if task.Name == "" {
+		return fmt.Errorf("warmup task name is required")
+	}
+	if task.Load == nil {
+		return fmt.Errorf("warmup task %s has no loader", task.Name)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.names[task.Name] {
+		return fmt.Errorf("warmup task already registered: %s", task.Name)
+	}
+	w.names[task.Name] = true
+	w.tasks = append(w.tasks, task)
+	return nil
+}
+
+// Tasks mengembalikan nama task yang terdaftar, terurut sesuai urutan registrasi.
+func (w *Warmup) Tasks() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	names := make([]string, len(w.tasks))
+	for i, task := range w.tasks {
+		names[i] = task.Name
+	}
+	return names
+}
+
+// Run menjalankan semua task secara concurrent dan menunggu semuanya selesai.
+// Panic di dalam loader ditangkap dan diperlakukan sebagai kegagalan task.
+// Kegagalan task WarmupOptional hanya di-log; kegagalan task WarmupRequired
+// digabung dan dikembalikan sebagai error.
+//
+// Parameters:
+//   - ctx: context induk; pembatalan ctx menghentikan semua task
+//
+// Returns:
+//   - []WarmupResult: hasil setiap task, terurut sesuai urutan registrasi
+//   - error: gabungan error dari task WarmupRequired yang gagal, nil jika semua berhasil
+//
+// Example:
+//
+//	if _, err := warmup.Run(ctx); err != nil {
+//	    log.Fatal(err)
+//	}
+//	dim.StartServer(ctx, cfg.Server, router)
+func (w *Warmup) Run(ctx context.Context) ([]WarmupResult, error) {
+	w.mu.Lock()
+	tasks := make([]WarmupTask, len(w.tasks))
+	copy(tasks, w.tasks)
+	w.mu.Unlock()
+
+	results := make([]WarmupResult, len(tasks))
+
+	var sem chan struct{}
+	if w.concurrency > 0 {
+		sem = make(chan struct{}, w.concurrency)
+	}
+
+	var wg sync.WaitGroup
+	for i, task := range tasks {
+		wg.Add(1)
+		go func(i int, task WarmupTask) {
+			defer wg.Done()
+			if sem != nil {
+				select {
+				case sem <- struct{}{}:
+					defer func() { <-sem }()
+				case <-ctx.Done():
+					results[i] = WarmupResult{Name: task.Name, Policy: task.Policy, Err: ctx.Err()}
+					return
+				}
+			}
+			results[i] = w.runTask(ctx, task)
+		}(i, task)
+	}
+	wg.Wait()
+
+	var errs []error
+	for _, result := range results {
+		if result.Err == nil {
+			slog.Info("warmup completed", "task", result.Name, "duration", result.Duration)
+			continue
+		}
+		if result.Policy == WarmupOptional {
+			slog.Warn("warmup failed", "task", result.Name, "policy", result.Policy.String(), "error", result.Err)
+			continue
+		}
+		slog.Error("warmup failed", "task", result.Name, "policy", result.Policy.String(), "error", result.Err)
+		errs = append(errs, fmt.Errorf("warmup %s: %w", result.Name, result.Err))
+	}
+
+	return results, errors.Join(errs...)
+}
+
+// runTask menjalankan satu task dengan timeout dan recovery dari panic.
+func (w *Warmup) runTask(ctx context.Context, task WarmupTask) (result WarmupResult) {
+	timeout := task.Timeout
+	if timeout == 0 {
+		timeout = w.timeout
+	}
+
+	taskCtx := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		taskCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	result = WarmupResult{Name: task.Name, Policy: task.Policy}
+
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if rec := recover(); rec != nil {
+				done <- fmt.Errorf("panic: %v", rec)
+			}
+		}()
+		done <- task.Load(taskCtx)
+	}()
+
+	// Loader yang mengabaikan context tidak boleh menahan startup melewati timeout.
+	select {
+	case err := <-done:
+		result.Err = err
+	case <-taskCtx.Done():
+		result.Err = taskCtx.Err()
+	}
+	result.Duration = time.Since(start)
+	return result
+}
+
+// WarmupCache membuat WarmupFunc yang memanggil load lalu menyimpan setiap entry ke cache.
+// Entry baru ditulis setelah load berhasil, sehingga kegagalan tidak meninggalkan cache setengah terisi.
+//
+// Parameters:
+//   - c: cache tujuan (misal *cache.InMemoryCache yang dipakai store)
+//   - load: fungsi yang memuat data referensi sebagai map key → value
+//   - opts: opsi Set, misalnya cache.WithTTL
+//
+// Returns:
+//   - WarmupFunc: loader yang siap didaftarkan ke Warmup
+//
+// Example:
+//
+//	countries := cache.NewInMemoryCache[string, Country](500, 24*time.Hour)
+//	warmup.Register(dim.WarmupTask{
+//	    Name: "countries",
+//	    Load: dim.WarmupCache(countries, countryStore.LoadAll),
+//	})
+func WarmupCache[K comparable, V any](c cache.Cache[K, V], load func(ctx context.Context) (map[K]V, error), opts ...cache.SetOption) WarmupFunc {
+	return func(ctx context.Context) error {
+		entries, err := load(ctx)
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		for key, value := range entries {
+			c.Set(ctx, key, value, opts...)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,192 @@
+package dim
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/atfromhome/goreus/pkg/cache"
+)
+
+func TestWarmup_Register(t *testing.T) {
+	w := NewWarmup()
+	load := func(ctx context.Context) error { return nil }
+
+	if err := w.Register(WarmupTask{Name: "countries", Load: load}); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if err := w.Register(WarmupTask{Name: "countries", Load: load}); err == nil {
+		t.Error("expected error for duplicate task name")
+	}
+	if err := w.Register(WarmupTask{Load: load}); err == nil {
+		t.Error("expected error for empty task name")
+	}
+	if err := w.Register(WarmupTask{Name: "settings"}); err == nil {
+		t.Error("expected error for nil loader")
+	}
+	if got := w.Tasks(); !reflect.DeepEqual(got, []string{"countries"}) {
+		t.Errorf("Tasks() = %v", got)
+	}
+}
+
+func TestWarmup_RunsConcurrently(t *testing.T) {
+	w := NewWarmup().WithTimeout(time.Second)
+
+	// Each loader waits until all three have started, which only succeeds when run concurrently
+	var started sync.WaitGroup
+	started.Add(3)
+	for _, name := range []string{"countries", "settings", "flags"} {
+		w.Register(WarmupTask{Name: name, Load: func(ctx context.Context) error {
+			started.Done()
+			started.Wait()
+			return nil
+		}})
+	}
+
+	results, err := w.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(results) != 3 || results[0].Name != "countries" || results[2].Name != "flags" {
+		t.Errorf("results = %+v, want registration order", results)
+	}
+}
+
+func TestWarmup_FailurePolicies(t *testing.T) {
+	w := NewWarmup()
+	w.Register(WarmupTask{Name: "countries", Load: func(ctx context.Context) error { return errors.New("db down") }})
+	w.Register(WarmupTask{Name: "flags", Policy: WarmupOptional, Load: func(ctx context.Context) error { return errors.New("flag service down") }})
+	w.Register(WarmupTask{Name: "settings", Load: func(ctx context.Context) error { return nil }})
+
+	results, err := w.Run(context.Background())
+	if err == nil {
+		t.Fatal("expected error from required task")
+	}
+	if !strings.Contains(err.Error(), "warmup countries: db down") {
+		t.Errorf("error = %v", err)
+	}
+	if strings.Contains(err.Error(), "flags") {
+		t.Errorf("optional task failure should not be returned: %v", err)
+	}
+	if results[1].Err == nil || results[2].Err != nil {
+		t.Errorf("results = %+v", results)
+	}
+
+	optionalOnly := NewWarmup()
+	optionalOnly.Register(WarmupTask{Name: "flags", Policy: WarmupOptional, Load: func(ctx context.Context) error { return errors.New("down") }})
+	if _, err := optionalOnly.Run(context.Background()); err != nil {
+		t.Errorf("optional failure should not fail Run: %v", err)
+	}
+}
+
+func TestWarmup_Timeout(t *testing.T) {
+	w := NewWarmup().WithTimeout(20 * time.Millisecond)
+	release := make(chan struct{})
+	defer close(release)
+
+	// Loader ignores its context; Run must still return after the timeout
+	w.Register(WarmupTask{Name: "slow", Load: func(ctx context.Context) error {
+		<-release
+		return nil
+	}})
+	w.Register(WarmupTask{Name: "custom", Timeout: time.Second, Load: func(ctx context.Context) error {
+		select {
+		case <-time.After(50 * time.Millisecond):
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}})
+
+	start := time.Now()
+	results, err := w.Run(context.Background())
+	if time.Since(start) > 500*time.Millisecond {
+		t.Fatalf("Run() took %v, timeout not enforced", time.Since(start))
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("error = %v, want deadline exceeded", err)
+	}
+	if results[1].Err != nil {
+		t.Errorf("task timeout should override default: %v", results[1].Err)
+	}
+}
+
+func TestWarmup_RecoversPanic(t *testing.T) {
+	w := NewWarmup()
+	w.Register(WarmupTask{Name: "broken", Load: func(ctx context.Context) error {
+		panic("nil map")
+	}})
+
+	_, err := w.Run(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "panic: nil map") {
+		t.Errorf("error = %v", err)
+	}
+}
+
+func TestWarmup_Concurrency(t *testing.T) {
+	w := NewWarmup().WithConcurrency(2)
+
+	var running, peak int32
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		w.Register(WarmupTask{Name: name, Load: func(ctx context.Context) error {
+			n := atomic.AddInt32(&running, 1)
+			for {
+				p := atomic.LoadInt32(&peak)
+				if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+			return nil
+		}})
+	}
+
+	if _, err := w.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if peak > 2 {
+		t.Errorf("peak concurrency = %d, want <= 2", peak)
+	}
+}
+
+func TestWarmupCache(t *testing.T) {
+	c := cache.NewInMemoryCache[string, string](10, 0)
+	defer c.Close()
+
+	load := WarmupCache[string, string](c, func(ctx context.Context) (map[string]string, error) {
+		return map[string]string{"ID": "Indonesia", "SG": "Singapore"}, nil
+	})
+	if err := load(context.Background()); err != nil {
+		t.Fatalf("load error = %v", err)
+	}
+	if v, ok := c.Get(context.Background(), "ID"); !ok || v != "Indonesia" {
+		t.Errorf("cache[ID] = %q, %v", v, ok)
+	}
+
+	failing := WarmupCache[string, string](c, func(ctx context.Context) (map[string]string, error) {
+		return map[string]string{"MY": "Malaysia"}, errors.New("query failed")
+	})
+	if err := failing(context.Background()); err == nil {
+		t.Error("expected load error")
+	}
+	if _, ok := c.Get(context.Background(), "MY"); ok {
+		t.Error("failed load should not populate cache")
+	}
+}
+
+func TestServeCommand_Execute_WarmupFailure(t *testing.T) {
+	w := NewWarmup()
+	w.Register(WarmupTask{Name: "settings", Load: func(ctx context.Context) error { return errors.New("db down") }})
+
+	cmd := &ServeCommand{}
+	err := cmd.Execute(&CommandContext{Router: NewRouter(), Config: &Config{}, Warmup: w})
+	if err == nil || !strings.Contains(err.Error(), "warmup failed") {
+		t.Errorf("error = %v, want warmup failure before server start", err)
+	}
+}
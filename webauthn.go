@@ -0,0 +1,723 @@
+package dim
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrWebAuthnCredentialNotFound dikembalikan WebAuthnStore jika credential tidak ditemukan.
+	ErrWebAuthnCredentialNotFound = errors.New("webauthn credential not found")
+	// ErrWebAuthnChallengeNotFound dikembalikan WebAuthnStore jika challenge tidak ada atau sudah dipakai.
+	ErrWebAuthnChallengeNotFound = errors.New("webauthn challenge not found")
+)
+
+// Nilai ceremony pada clientDataJSON.type.
+const (
+	webAuthnCeremonyCreate = "webauthn.create"
+	webAuthnCeremonyGet    = "webauthn.get"
+)
+
+// Nilai user verification yang didukung WithUserVerification.
+const (
+	WebAuthnUserVerificationRequired    = "required"
+	WebAuthnUserVerificationPreferred   = "preferred"
+	WebAuthnUserVerificationDiscouraged = "discouraged"
+)
+
+// Base64URL adalah []byte yang di-encode sebagai string base64url tanpa padding di JSON,
+// format yang dipakai WebAuthn JSON (PublicKeyCredential.toJSON dan parseCreationOptionsFromJSON).
+type Base64URL []byte
+
+// MarshalJSON mengimplementasikan json.Marshaler.
+func (b Base64URL) MarshalJSON() ([]byte, error) {
+	return json.Marshal(base64.RawURLEncoding.EncodeToString(b))
+}
+
+// UnmarshalJSON mengimplementasikan json.Unmarshaler. Padding "=" diterima dan diabaikan.
+func (b *Base64URL) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+	if err != nil {
+		return err
+	}
+	*b = decoded
+	return nil
+}
+
+// WebAuthnCredential adalah passkey yang terdaftar untuk user.
+type WebAuthnCredential struct {
+	// ID adalah credential ID dalam base64url.
+	ID     string `json:"id"`
+	UserID string `json:"user_id"`
+	Name   string `json:"name"`
+	// PublicKey adalah public key dalam format COSE_Key.
+	PublicKey         []byte     `json:"-"`
+	SignCount         uint32     `json:"sign_count"`
+	AAGUID            string     `json:"aaguid"`
+	Transports        []string   `json:"transports,omitempty"`
+	AttestationFormat string     `json:"attestation_format"`
+	CreatedAt         time.Time  `json:"created_at"`
+	LastUsedAt        *time.Time `json:"last_used_at,omitempty"`
+}
+
+// WebAuthnChallenge adalah challenge ceremony yang sedang berjalan. Hanya hash challenge yang
+// disimpan. UserID kosong untuk login dengan discoverable credential (tanpa email).
+type WebAuthnChallenge struct {
+	ChallengeHash string    `json:"-"`
+	UserID        string    `json:"user_id"`
+	Ceremony      string    `json:"ceremony"`
+	ExpiresAt     time.Time `json:"expires_at"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// WebAuthnStore mendefinisikan penyimpanan passkey dan challenge ceremony.
+type WebAuthnStore interface {
+	SaveWebAuthnCredential(ctx context.Context, credential *WebAuthnCredential) error
+	FindWebAuthnCredential(ctx context.Context, id string) (*WebAuthnCredential, error) // ErrWebAuthnCredentialNotFound jika tidak ada
+	ListUserWebAuthnCredentials(ctx context.Context, userID string) ([]*WebAuthnCredential, error)
+	UpdateWebAuthnCredentialUsage(ctx context.Context, id string, signCount uint32, usedAt time.Time) error
+	DeleteWebAuthnCredential(ctx context.Context, userID, id string) error
+
+	SaveWebAuthnChallenge(ctx context.Context, challenge *WebAuthnChallenge) error
+	// ConsumeWebAuthnChallenge mengambil lalu menghapus challenge (sekali pakai).
+	ConsumeWebAuthnChallenge(ctx context.Context, challengeHash string) (*WebAuthnChallenge, error)
+}
+
+// WebAuthnRelyingParty adalah entitas relying party (aplikasi) pada creation options.
+type WebAuthnRelyingParty struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// WebAuthnUserEntity adalah entitas user pada creation options. ID adalah user handle.
+type WebAuthnUserEntity struct {
+	ID          Base64URL `json:"id"`
+	Name        string    `json:"name"`
+	DisplayName string    `json:"displayName"`
+}
+
+// WebAuthnCredentialParameter adalah algoritma public key yang diterima.
+type WebAuthnCredentialParameter struct {
+	Type string `json:"type"`
+	Alg  int64  `json:"alg"`
+}
+
+// WebAuthnCredentialDescriptor mengidentifikasi credential untuk excludeCredentials dan allowCredentials.
+type WebAuthnCredentialDescriptor struct {
+	Type       string    `json:"type"`
+	ID         Base64URL `json:"id"`
+	Transports []string  `json:"transports,omitempty"`
+}
+
+// WebAuthnAuthenticatorSelection adalah kriteria authenticator saat registrasi.
+type WebAuthnAuthenticatorSelection struct {
+	ResidentKey        string `json:"residentKey"`
+	RequireResidentKey bool   `json:"requireResidentKey"`
+	UserVerification   string `json:"userVerification"`
+}
+
+// WebAuthnCreationOptions adalah PublicKeyCredentialCreationOptionsJSON untuk
+// navigator.credentials.create().
+type WebAuthnCreationOptions struct {
+	Challenge              Base64URL                      `json:"challenge"`
+	RP                     WebAuthnRelyingParty           `json:"rp"`
+	User                   WebAuthnUserEntity             `json:"user"`
+	PubKeyCredParams       []WebAuthnCredentialParameter  `json:"pubKeyCredParams"`
+	Timeout                int64                          `json:"timeout"`
+	ExcludeCredentials     []WebAuthnCredentialDescriptor `json:"excludeCredentials"`
+	AuthenticatorSelection WebAuthnAuthenticatorSelection `json:"authenticatorSelection"`
+	Attestation            string                         `json:"attestation"`
+}
+
+// WebAuthnRequestOptions adalah PublicKeyCredentialRequestOptionsJSON untuk
+// navigator.credentials.get().
+type WebAuthnRequestOptions struct {
+	Challenge        Base64URL                      `json:"challenge"`
+	RPID             string                         `json:"rpId"`
+	Timeout          int64                          `json:"timeout"`
+	AllowCredentials []WebAuthnCredentialDescriptor `json:"allowCredentials"`
+	UserVerification string                         `json:"userVerification"`
+}
+
+// WebAuthnAttestationResponse adalah field response dari credential hasil registrasi.
+type WebAuthnAttestationResponse struct {
+	ClientDataJSON    Base64URL `json:"clientDataJSON"`
+	AttestationObject Base64URL `json:"attestationObject"`
+	Transports        []string  `json:"transports,omitempty"`
+}
+
+// WebAuthnRegistration adalah PublicKeyCredential hasil navigator.credentials.create() dalam
+// format JSON (credential.toJSON()).
+type WebAuthnRegistration struct {
+	ID       string                      `json:"id"`
+	RawID    Base64URL                   `json:"rawId"`
+	Type     string                      `json:"type"`
+	Response WebAuthnAttestationResponse `json:"response"`
+}
+
+// WebAuthnAssertionResponse adalah field response dari credential hasil login.
+type WebAuthnAssertionResponse struct {
+	ClientDataJSON    Base64URL `json:"clientDataJSON"`
+	AuthenticatorData Base64URL `json:"authenticatorData"`
+	Signature         Base64URL `json:"signature"`
+	UserHandle        Base64URL `json:"userHandle,omitempty"`
+}
+
+// WebAuthnAssertion adalah PublicKeyCredential hasil navigator.credentials.get() dalam format
+// JSON (credential.toJSON()).
+type WebAuthnAssertion struct {
+	ID       string                    `json:"id"`
+	RawID    Base64URL                 `json:"rawId"`
+	Type     string                    `json:"type"`
+	Response WebAuthnAssertionResponse `json:"response"`
+}
+
+// webAuthnClientData adalah isi clientDataJSON yang diperiksa relying party.
+type webAuthnClientData struct {
+	Type        string `json:"type"`
+	Challenge   string `json:"challenge"`
+	Origin      string `json:"origin"`
+	CrossOrigin bool   `json:"crossOrigin"`
+}
+
+// WebAuthnService menambahkan login passkey (WebAuthn) ke AuthService: registrasi credential,
+// challenge sekali pakai, verifikasi attestation dan assertion, lalu penerbitan access & refresh
+// token melalui AuthService (sama seperti Login biasa, termasuk ClaimsProvider).
+type WebAuthnService struct {
+	auth             *AuthService
+	store            WebAuthnStore
+	rpID             string
+	rpName           string
+	origins          []string
+	timeout          time.Duration
+	userVerification string
+}
+
+// NewWebAuthnService membuat WebAuthnService. Secara default challenge berlaku 5 menit dan
+// user verification "preferred".
+//
+// Parameters:
+//   - auth: AuthService yang menerbitkan token
+//   - store: penyimpanan passkey dan challenge, misal DatabaseWebAuthnStore
+//   - rpID: domain relying party, misal "example.com" (harus sama atau parent dari domain frontend)
+//   - rpName: nama aplikasi yang ditampilkan browser
+//   - origins: origin frontend yang diizinkan, misal "https://app.example.com"
+//
+// Returns:
+//   - *WebAuthnService: service yang siap digunakan
+//
+// Example:
+//
+//	passkeys := dim.NewWebAuthnService(authService, dim.NewDatabaseWebAuthnStore(db),
+//	    "example.com", "Acme", "https://app.example.com")
+//
+//	router.Post("/auth/passkeys/register/options", passkeys.RegistrationOptionsHandler(), dim.RequireAuth(jwtManager, blocklist))
+//	router.Post("/auth/passkeys/register", passkeys.RegistrationHandler(), dim.RequireAuth(jwtManager, blocklist))
+//	router.Post("/auth/passkeys/login/options", passkeys.LoginOptionsHandler())
+//	router.Post("/auth/passkeys/login", passkeys.LoginHandler())
+func NewWebAuthnService(auth *AuthService, store WebAuthnStore, rpID, rpName string, origins ...string) *WebAuthnService {
+	return &WebAuthnService{
+		auth:             auth,
+		store:            store,
+		rpID:             rpID,
+		rpName:           rpName,
+		origins:          origins,
+		timeout:          5 * time.Minute,
+		userVerification: WebAuthnUserVerificationPreferred,
+	}
+}
+
+// WithTimeout mengatur masa berlaku challenge dan timeout ceremony di browser (default: 5 menit).
+func (s *WebAuthnService) WithTimeout(timeout time.Duration) *WebAuthnService {
+	s.timeout = timeout
+	return s
+}
+
+// WithUserVerification mengatur user verification (PIN/biometrik): "required", "preferred"
+// (default), atau "discouraged". Dengan "required", assertion tanpa flag UV ditolak.
+func (s *WebAuthnService) WithUserVerification(requirement string) *WebAuthnService {
+	s.userVerification = requirement
+	return s
+}
+
+// BeginRegistration membuat creation options untuk mendaftarkan passkey baru bagi user yang
+// sedang login. Passkey yang sudah terdaftar dimasukkan ke excludeCredentials.
+//
+// Parameters:
+//   - ctx: context request
+//   - user: user yang sedang login
+//
+// Returns:
+//   - *WebAuthnCreationOptions: kirim ke frontend untuk navigator.credentials.create()
+//   - error: error jika store gagal
+func (s *WebAuthnService) BeginRegistration(ctx context.Context, user Authenticatable) (*WebAuthnCreationOptions, error) {
+	existing, err := s.store.ListUserWebAuthnCredentials(ctx, user.GetID())
+	if err != nil {
+		return nil, err
+	}
+	exclude := make([]WebAuthnCredentialDescriptor, 0, len(existing))
+	for _, c := range existing {
+		if d, ok := credentialDescriptor(c); ok {
+			exclude = append(exclude, d)
+		}
+	}
+
+	challenge, err := s.newChallenge(ctx, user.GetID(), webAuthnCeremonyCreate)
+	if err != nil {
+		return nil, err
+	}
+
+	return &WebAuthnCreationOptions{
+		Challenge: challenge,
+		RP:        WebAuthnRelyingParty{ID: s.rpID, Name: s.rpName},
+		User: WebAuthnUserEntity{
+			ID:          Base64URL(user.GetID()),
+			Name:        user.GetEmail(),
+			DisplayName: user.GetEmail(),
+		},
+		PubKeyCredParams: []WebAuthnCredentialParameter{
+			{Type: "public-key", Alg: coseAlgES256},
+			{Type: "public-key", Alg: coseAlgEdDSA},
+			{Type: "public-key", Alg: coseAlgRS256},
+		},
+		Timeout:            s.timeout.Milliseconds(),
+		ExcludeCredentials: exclude,
+		AuthenticatorSelection: WebAuthnAuthenticatorSelection{
+			ResidentKey:      "preferred",
+			UserVerification: s.userVerification,
+		},
+		Attestation: "none",
+	}, nil
+}
+
+// FinishRegistration memverifikasi hasil navigator.credentials.create() dan menyimpan passkey.
+// Challenge, origin, RP ID, flag user present/verified, dan attestation statement (format "none"
+// atau "packed") diperiksa.
+//
+// Parameters:
+//   - ctx: context request
+//   - user: user yang sama dengan saat BeginRegistration
+//   - name: label passkey untuk ditampilkan ke user (misal "MacBook")
+//   - registration: credential dari browser
+//
+// Returns:
+//   - *WebAuthnCredential: passkey yang tersimpan
+//   - error: *AppError (400 jika verifikasi gagal, 409 jika credential sudah terdaftar)
+func (s *WebAuthnService) FinishRegistration(ctx context.Context, user Authenticatable, name string, registration *WebAuthnRegistration) (*WebAuthnCredential, error) {
+	credential, err := s.verifyRegistration(ctx, user, registration)
+	if err != nil {
+		if appErr, ok := AsAppError(err); ok {
+			return nil, appErr
+		}
+		s.logWarn("Passkey registration rejected", user.GetID(), err)
+		return nil, NewAppError("Registrasi passkey gagal", http.StatusBadRequest)
+	}
+
+	if _, err := s.store.FindWebAuthnCredential(ctx, credential.ID); err == nil {
+		return nil, NewAppError("Passkey sudah terdaftar", http.StatusConflict)
+	} else if !errors.Is(err, ErrWebAuthnCredentialNotFound) {
+		return nil, err
+	}
+
+	credential.Name = strings.TrimSpace(name)
+	if credential.Name == "" {
+		credential.Name = "Passkey"
+	}
+	if err := s.store.SaveWebAuthnCredential(ctx, credential); err != nil {
+		return nil, err
+	}
+	return credential, nil
+}
+
+func (s *WebAuthnService) verifyRegistration(ctx context.Context, user Authenticatable, registration *WebAuthnRegistration) (*WebAuthnCredential, error) {
+	if registration.Type != "public-key" {
+		return nil, fmt.Errorf("unexpected credential type %q", registration.Type)
+	}
+	raw := registration.Response.ClientDataJSON
+	if _, err := s.verifyClientData(ctx, raw, webAuthnCeremonyCreate, user.GetID()); err != nil {
+		return nil, err
+	}
+
+	attestation, err := parseAttestationObject(registration.Response.AttestationObject)
+	if err != nil {
+		return nil, err
+	}
+	authData, err := parseAuthenticatorData(attestation.authData)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.verifyAuthenticatorData(authData); err != nil {
+		return nil, err
+	}
+	if authData.key == nil {
+		return nil, errors.New("attested credential data missing")
+	}
+	if len(registration.RawID) > 0 && !bytes.Equal(registration.RawID, authData.credentialID) {
+		return nil, errors.New("credential id mismatch")
+	}
+
+	clientDataHash := sha256.Sum256(raw)
+	if err := attestation.verify(clientDataHash[:], authData.key); err != nil {
+		return nil, fmt.Errorf("attestation: %w", err)
+	}
+
+	return &WebAuthnCredential{
+		ID:                base64.RawURLEncoding.EncodeToString(authData.credentialID),
+		UserID:            user.GetID(),
+		PublicKey:         bytes.Clone(authData.publicKey),
+		SignCount:         authData.signCount,
+		AAGUID:            formatAAGUID(authData.aaguid),
+		Transports:        registration.Response.Transports,
+		AttestationFormat: attestation.format,
+	}, nil
+}
+
+// BeginLogin membuat request options untuk login dengan passkey. Dengan email kosong, browser
+// menawarkan discoverable credential (passkey) yang tersimpan untuk RP ini. Email yang tidak
+// terdaftar menghasilkan allowCredentials kosong sehingga keberadaan akun tidak terungkap.
+//
+// Parameters:
+//   - ctx: context request
+//   - email: email user (opsional)
+//
+// Returns:
+//   - *WebAuthnRequestOptions: kirim ke frontend untuk navigator.credentials.get()
+//   - error: error jika store gagal
+func (s *WebAuthnService) BeginLogin(ctx context.Context, email string) (*WebAuthnRequestOptions, error) {
+	userID := ""
+	allow := make([]WebAuthnCredentialDescriptor, 0)
+	if email != "" {
+		if user, err := s.auth.userStore.FindByEmail(ctx, email); err == nil {
+			userID = user.GetID()
+			credentials, err := s.store.ListUserWebAuthnCredentials(ctx, userID)
+			if err != nil {
+				return nil, err
+			}
+			for _, c := range credentials {
+				if d, ok := credentialDescriptor(c); ok {
+					allow = append(allow, d)
+				}
+			}
+		}
+	}
+
+	challenge, err := s.newChallenge(ctx, userID, webAuthnCeremonyGet)
+	if err != nil {
+		return nil, err
+	}
+	return &WebAuthnRequestOptions{
+		Challenge:        challenge,
+		RPID:             s.rpID,
+		Timeout:          s.timeout.Milliseconds(),
+		AllowCredentials: allow,
+		UserVerification: s.userVerification,
+	}, nil
+}
+
+// FinishLogin memverifikasi hasil navigator.credentials.get() lalu menerbitkan access & refresh
+// token seperti Login. Assertion dengan user verification (PIN/biometrik) dianggap multi-factor
+// sehingga token membawa claim MFAClaim; tanpa UV, user dengan MFA aktif mendapat
+// *MFARequiredError seperti Login.
+//
+// Parameters:
+//   - ctx: context request
+//   - assertion: credential dari browser
+//
+// Returns:
+//   - string: access token
+//   - string: refresh token
+//   - error: *AppError (401 jika verifikasi gagal) atau *MFARequiredError
+func (s *WebAuthnService) FinishLogin(ctx context.Context, assertion *WebAuthnAssertion) (string, string, error) {
+	credential, authData, err := s.verifyAssertion(ctx, assertion)
+	if err != nil {
+		s.logWarn("Passkey login rejected", assertion.ID, err)
+		return "", "", NewAppError("Passkey tidak valid", http.StatusUnauthorized)
+	}
+
+	if err := s.store.UpdateWebAuthnCredentialUsage(ctx, credential.ID, authData.signCount, time.Now()); err != nil {
+		return "", "", err
+	}
+
+	user, err := s.auth.userStore.FindByID(ctx, credential.UserID)
+	if err != nil {
+		return "", "", NewAppError("Passkey tidak valid", http.StatusUnauthorized)
+	}
+
+	if authData.userVerified() {
+		return s.auth.login(ctx, user, LoginMethodPasskey, true)
+	}
+	challenge, err := s.auth.mfaChallenge(ctx, user)
+	if err != nil {
+		return "", "", err
+	}
+	if challenge != nil {
+		return "", "", challenge
+	}
+	return s.auth.login(ctx, user, LoginMethodPasskey, false)
+}
+
+func (s *WebAuthnService) verifyAssertion(ctx context.Context, assertion *WebAuthnAssertion) (*WebAuthnCredential, *authenticatorData, error) {
+	if assertion.Type != "public-key" {
+		return nil, nil, fmt.Errorf("unexpected credential type %q", assertion.Type)
+	}
+	raw := assertion.Response.ClientDataJSON
+	challenge, err := s.verifyClientData(ctx, raw, webAuthnCeremonyGet, "")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	id := assertion.ID
+	if len(assertion.RawID) > 0 {
+		id = base64.RawURLEncoding.EncodeToString(assertion.RawID)
+	}
+	credential, err := s.store.FindWebAuthnCredential(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if challenge.UserID != "" && challenge.UserID != credential.UserID {
+		return nil, nil, errors.New("credential does not belong to the requested user")
+	}
+	if len(assertion.Response.UserHandle) > 0 && string(assertion.Response.UserHandle) != credential.UserID {
+		return nil, nil, errors.New("user handle mismatch")
+	}
+
+	authData, err := parseAuthenticatorData(assertion.Response.AuthenticatorData)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := s.verifyAuthenticatorData(authData); err != nil {
+		return nil, nil, err
+	}
+
+	key, _, err := parseCOSEKey(credential.PublicKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	clientDataHash := sha256.Sum256(raw)
+	signed := append(bytes.Clone(assertion.Response.AuthenticatorData), clientDataHash[:]...)
+	if err := key.verify(signed, assertion.Response.Signature); err != nil {
+		return nil, nil, err
+	}
+
+	// Counter yang tidak naik menandakan authenticator mungkin diklon. Passkey tersinkron
+	// selalu melaporkan 0 sehingga tidak diperiksa.
+	if (authData.signCount != 0 || credential.SignCount != 0) && authData.signCount <= credential.SignCount {
+		return nil, nil, fmt.Errorf("sign count %d not greater than stored %d", authData.signCount, credential.SignCount)
+	}
+	return credential, authData, nil
+}
+
+// Credentials mengembalikan passkey milik user, misal untuk halaman pengaturan keamanan.
+func (s *WebAuthnService) Credentials(ctx context.Context, userID string) ([]*WebAuthnCredential, error) {
+	return s.store.ListUserWebAuthnCredentials(ctx, userID)
+}
+
+// DeleteCredential menghapus passkey milik user.
+//
+// Returns:
+//   - error: *AppError 404 jika passkey tidak ditemukan atau milik user lain
+func (s *WebAuthnService) DeleteCredential(ctx context.Context, userID, id string) error {
+	credential, err := s.store.FindWebAuthnCredential(ctx, id)
+	if err != nil || credential.UserID != userID {
+		if err == nil || errors.Is(err, ErrWebAuthnCredentialNotFound) {
+			return NewAppError("Passkey tidak ditemukan", http.StatusNotFound)
+		}
+		return err
+	}
+	return s.store.DeleteWebAuthnCredential(ctx, userID, id)
+}
+
+// newChallenge membuat challenge acak 32 byte dan menyimpan hash-nya.
+func (s *WebAuthnService) newChallenge(ctx context.Context, userID, ceremony string) (Base64URL, error) {
+	challenge := make([]byte, 32)
+	if _, err := rand.Read(challenge); err != nil {
+		return nil, err
+	}
+	err := s.store.SaveWebAuthnChallenge(ctx, &WebAuthnChallenge{
+		ChallengeHash: GenerateTokenHash(base64.RawURLEncoding.EncodeToString(challenge)),
+		UserID:        userID,
+		Ceremony:      ceremony,
+		ExpiresAt:     time.Now().Add(s.timeout),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return challenge, nil
+}
+
+// verifyClientData memeriksa type, origin, dan challenge pada clientDataJSON. Challenge
+// dikonsumsi sehingga tidak dapat dipakai ulang. userID kosong berarti challenge boleh milik
+// siapa saja (login).
+func (s *WebAuthnService) verifyClientData(ctx context.Context, raw []byte, ceremony, userID string) (*WebAuthnChallenge, error) {
+	var clientData webAuthnClientData
+	if err := json.Unmarshal(raw, &clientData); err != nil {
+		return nil, fmt.Errorf("%w: client data: %v", errWebAuthnMalformed, err)
+	}
+	if clientData.Type != ceremony {
+		return nil, fmt.Errorf("unexpected client data type %q", clientData.Type)
+	}
+	if !slices.Contains(s.origins, clientData.Origin) {
+		return nil, fmt.Errorf("origin %q not allowed", clientData.Origin)
+	}
+
+	challenge, err := s.store.ConsumeWebAuthnChallenge(ctx, GenerateTokenHash(strings.TrimRight(clientData.Challenge, "=")))
+	if err != nil {
+		return nil, err
+	}
+	if challenge.Ceremony != ceremony || time.Now().After(challenge.ExpiresAt) {
+		return nil, ErrWebAuthnChallengeNotFound
+	}
+	if userID != "" && challenge.UserID != userID {
+		return nil, errors.New("challenge was issued for another user")
+	}
+	return challenge, nil
+}
+
+// verifyAuthenticatorData memeriksa hash RP ID dan flag user present/verified.
+func (s *WebAuthnService) verifyAuthenticatorData(authData *authenticatorData) error {
+	rpIDHash := sha256.Sum256([]byte(s.rpID))
+	if subtle.ConstantTimeCompare(authData.rpIDHash, rpIDHash[:]) != 1 {
+		return errors.New("rp id hash mismatch")
+	}
+	if !authData.userPresent() {
+		return errors.New("user not present")
+	}
+	if s.userVerification == WebAuthnUserVerificationRequired && !authData.userVerified() {
+		return errors.New("user verification required")
+	}
+	return nil
+}
+
+func (s *WebAuthnService) logWarn(msg, subject string, err error) {
+	if s.auth.logger != nil {
+		s.auth.logger.Warn(msg, "subject", subject, "error", err.Error())
+	}
+}
+
+// credentialDescriptor membuat descriptor dari credential yang tersimpan.
+func credentialDescriptor(c *WebAuthnCredential) (WebAuthnCredentialDescriptor, bool) {
+	id, err := base64.RawURLEncoding.DecodeString(c.ID)
+	if err != nil {
+		return WebAuthnCredentialDescriptor{}, false
+	}
+	return WebAuthnCredentialDescriptor{Type: "public-key", ID: id, Transports: c.Transports}, true
+}
+
+// webAuthnRegistrationRequest adalah body RegistrationHandler.
+type webAuthnRegistrationRequest struct {
+	Name       string               `json:"name"`
+	Credential WebAuthnRegistration `json:"credential"`
+}
+
+// RegistrationOptionsHandler membuat handler yang mengembalikan WebAuthnCreationOptions untuk
+// user yang sedang login. Pasang di belakang RequireAuth.
+func (s *WebAuthnService) RegistrationOptionsHandler() HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, ok := GetUser(r)
+		if !ok {
+			Unauthorized(w, "Tidak terotentikasi")
+			return
+		}
+		options, err := s.BeginRegistration(r.Context(), user)
+		if err != nil {
+			InternalServerError(w, "Gagal memulai registrasi passkey")
+			return
+		}
+		Json(w, http.StatusOK, options)
+	}
+}
+
+// RegistrationHandler membuat handler yang menyimpan passkey baru dari body
+// {"name": "...", "credential": <credential.toJSON()>}. Pasang di belakang RequireAuth.
+func (s *WebAuthnService) RegistrationHandler() HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, ok := GetUser(r)
+		if !ok {
+			Unauthorized(w, "Tidak terotentikasi")
+			return
+		}
+		var req webAuthnRegistrationRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			BadRequest(w, "Request tidak valid", nil)
+			return
+		}
+		credential, err := s.FinishRegistration(r.Context(), user, req.Name, &req.Credential)
+		if err != nil {
+			if appErr, ok := AsAppError(err); ok {
+				JsonAppError(w, appErr)
+				return
+			}
+			InternalServerError(w, "Gagal menyimpan passkey")
+			return
+		}
+		Json(w, http.StatusCreated, credential)
+	}
+}
+
+// LoginOptionsHandler membuat handler yang mengembalikan WebAuthnRequestOptions. Body
+// {"email": "..."} bersifat opsional; tanpa email browser menawarkan passkey yang tersedia.
+func (s *WebAuthnService) LoginOptionsHandler() HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Email string `json:"email"`
+		}
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				BadRequest(w, "Request tidak valid", nil)
+				return
+			}
+		}
+		options, err := s.BeginLogin(r.Context(), strings.TrimSpace(req.Email))
+		if err != nil {
+			InternalServerError(w, "Gagal memulai login passkey")
+			return
+		}
+		Json(w, http.StatusOK, options)
+	}
+}
+
+// LoginHandler membuat handler yang memverifikasi assertion dan mengembalikan TokenResponse,
+// atau MFARequiredError jika user harus memasukkan kode MFA.
+func (s *WebAuthnService) LoginHandler() HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var assertion WebAuthnAssertion
+		if err := json.NewDecoder(r.Body).Decode(&assertion); err != nil {
+			BadRequest(w, "Request tidak valid", nil)
+			return
+		}
+		access, refresh, err := s.FinishLogin(requestClientContext(r), &assertion)
+		if err != nil {
+			var mfa *MFARequiredError
+			if errors.As(err, &mfa) {
+				Json(w, http.StatusOK, mfa)
+				return
+			}
+			if appErr, ok := AsAppError(err); ok {
+				JsonAppError(w, appErr)
+				return
+			}
+			InternalServerError(w, "Gagal login")
+			return
+		}
+		Json(w, http.StatusOK, TokenResponse{
+			AccessToken:  access,
+			RefreshToken: refresh,
+			TokenType:    "Bearer",
+		})
+	}
+}
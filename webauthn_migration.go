@@ -0,0 +1,138 @@
+package dim
+
+import (
+	"context"
+)
+
+// GetWebAuthnMigrations mengembalikan migrasi tabel passkey (webauthn_credentials) dan challenge
+// ceremony (webauthn_challenges) yang dipakai DatabaseWebAuthnStore. Modul ini opsional sehingga
+// tidak termasuk dalam GetFrameworkMigrations; gabungkan secara manual setelah migrasi users.
+// Menggunakan versi 151-152 agar tidak bentrok dengan migrasi framework maupun aplikasi.
+//
+// Example:
+//
+//	migrations := append(dim.GetFrameworkMigrations(), dim.GetWebAuthnMigrations()...)
+//	dim.RunMigrations(db, migrations)
+func GetWebAuthnMigrations() []Migration {
+	return []Migration{
+		{
+			Version: 151,
+			Name:    "create_webauthn_credentials_table",
+			Up:      CreateWebAuthnCredentialsTable,
+			Down:    DropWebAuthnCredentialsTable,
+		},
+		{
+			Version: 152,
+			Name:    "create_webauthn_challenges_table",
+			Up:      CreateWebAuthnChallengesTable,
+			Down:    DropWebAuthnChallengesTable,
+		},
+	}
+}
+
+// CreateWebAuthnCredentialsTable membuat tabel webauthn_credentials. Kolom id berisi credential ID
+// dalam base64url; public_key berisi COSE_Key.
+func CreateWebAuthnCredentialsTable(db Database) error {
+	var query string
+	switch db.DriverName() {
+	case "sqlite":
+		query = `
+			CREATE TABLE IF NOT EXISTS webauthn_credentials (
+				id TEXT PRIMARY KEY,
+				user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+				name TEXT NOT NULL,
+				public_key BLOB NOT NULL,
+				sign_count INTEGER NOT NULL DEFAULT 0,
+				aaguid TEXT NOT NULL DEFAULT '',
+				transports TEXT NOT NULL DEFAULT '',
+				attestation_format TEXT NOT NULL DEFAULT 'none',
+				created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				last_used_at TIMESTAMP NULL
+			);
+			CREATE INDEX IF NOT EXISTS idx_webauthn_credentials_user_id ON webauthn_credentials(user_id);
+		`
+	case "mysql":
+		query = `
+			CREATE TABLE IF NOT EXISTS webauthn_credentials (
+				id VARCHAR(768) PRIMARY KEY,
+				user_id CHAR(36) NOT NULL,
+				name VARCHAR(255) NOT NULL,
+				public_key BLOB NOT NULL,
+				sign_count BIGINT NOT NULL DEFAULT 0,
+				aaguid VARCHAR(36) NOT NULL DEFAULT '',
+				transports VARCHAR(255) NOT NULL DEFAULT '',
+				attestation_format VARCHAR(32) NOT NULL DEFAULT 'none',
+				created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				last_used_at DATETIME NULL,
+				KEY idx_webauthn_credentials_user_id (user_id),
+				CONSTRAINT fk_webauthn_credentials_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+		`
+	default:
+		query = `
+			CREATE TABLE IF NOT EXISTS webauthn_credentials (
+				id TEXT PRIMARY KEY,
+				user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+				name VARCHAR(255) NOT NULL,
+				public_key BYTEA NOT NULL,
+				sign_count BIGINT NOT NULL DEFAULT 0,
+				aaguid VARCHAR(36) NOT NULL DEFAULT '',
+				transports VARCHAR(255) NOT NULL DEFAULT '',
+				attestation_format VARCHAR(32) NOT NULL DEFAULT 'none',
+				created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+				last_used_at TIMESTAMP NULL
+			);
+			CREATE INDEX IF NOT EXISTS idx_webauthn_credentials_user_id ON webauthn_credentials(user_id);
+		`
+	}
+	return db.Exec(context.Background(), query)
+}
+
+// DropWebAuthnCredentialsTable menghapus tabel webauthn_credentials.
+func DropWebAuthnCredentialsTable(db Database) error {
+	return db.Exec(context.Background(), "DROP TABLE IF EXISTS webauthn_credentials")
+}
+
+// CreateWebAuthnChallengesTable membuat tabel webauthn_challenges. user_id kosong untuk challenge
+// login tanpa email sehingga tidak memiliki foreign key.
+func CreateWebAuthnChallengesTable(db Database) error {
+	var query string
+	switch db.DriverName() {
+	case "sqlite":
+		query = `
+			CREATE TABLE IF NOT EXISTS webauthn_challenges (
+				challenge_hash TEXT PRIMARY KEY,
+				user_id TEXT NOT NULL DEFAULT '',
+				ceremony TEXT NOT NULL,
+				expires_at TIMESTAMP NOT NULL,
+				created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+			)
+		`
+	case "mysql":
+		query = `
+			CREATE TABLE IF NOT EXISTS webauthn_challenges (
+				challenge_hash VARCHAR(64) PRIMARY KEY,
+				user_id VARCHAR(36) NOT NULL DEFAULT '',
+				ceremony VARCHAR(16) NOT NULL,
+				expires_at DATETIME NOT NULL,
+				created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4
+		`
+	default:
+		query = `
+			CREATE TABLE IF NOT EXISTS webauthn_challenges (
+				challenge_hash VARCHAR(64) PRIMARY KEY,
+				user_id VARCHAR(36) NOT NULL DEFAULT '',
+				ceremony VARCHAR(16) NOT NULL,
+				expires_at TIMESTAMP NOT NULL,
+				created_at TIMESTAMP NOT NULL DEFAULT NOW()
+			)
+		`
+	}
+	return db.Exec(context.Background(), query)
+}
+
+// DropWebAuthnChallengesTable menghapus tabel webauthn_challenges.
+func DropWebAuthnChallengesTable(db Database) error {
+	return db.Exec(context.Background(), "DROP TABLE IF EXISTS webauthn_challenges")
+}
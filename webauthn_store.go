@@ -0,0 +1,266 @@
+package dim
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DatabaseWebAuthnStore is the SQL implementation of WebAuthnStore (PostgreSQL, MySQL & SQLite)
+type DatabaseWebAuthnStore struct {
+	db Database
+}
+
+// NewDatabaseWebAuthnStore creates a new SQL WebAuthn store.
+// Requires the tables created by GetWebAuthnMigrations.
+func NewDatabaseWebAuthnStore(db Database) *DatabaseWebAuthnStore {
+	return &DatabaseWebAuthnStore{db: db}
+}
+
+const webAuthnCredentialColumns = `id, user_id, name, public_key, sign_count, aaguid, transports,
+	 attestation_format, created_at, last_used_at`
+
+func scanWebAuthnCredential(row Row) (*WebAuthnCredential, error) {
+	c := &WebAuthnCredential{}
+	var signCount int64
+	var transports string
+	err := row.Scan(&c.ID, &c.UserID, &c.Name, &c.PublicKey, &signCount, &c.AAGUID, &transports,
+		&c.AttestationFormat, &c.CreatedAt, &c.LastUsedAt)
+	if err != nil {
+		return nil, err
+	}
+	c.SignCount = uint32(signCount)
+	if transports != "" {
+		c.Transports = strings.Split(transports, ",")
+	}
+	return c, nil
+}
+
+// SaveWebAuthnCredential inserts a new credential.
+func (s *DatabaseWebAuthnStore) SaveWebAuthnCredential(ctx context.Context, credential *WebAuthnCredential) error {
+	credential.CreatedAt = time.Now().UTC().Truncate(time.Second)
+	query := `INSERT INTO webauthn_credentials (` + webAuthnCredentialColumns + `)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+
+	err := s.db.Exec(ctx, s.db.Rebind(query),
+		credential.ID,
+		credential.UserID,
+		credential.Name,
+		credential.PublicKey,
+		int64(credential.SignCount),
+		credential.AAGUID,
+		strings.Join(credential.Transports, ","),
+		credential.AttestationFormat,
+		credential.CreatedAt,
+		nil,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save webauthn credential: %w", err)
+	}
+
+	return nil
+}
+
+// FindWebAuthnCredential finds a credential by its base64url ID.
+func (s *DatabaseWebAuthnStore) FindWebAuthnCredential(ctx context.Context, id string) (*WebAuthnCredential, error) {
+	query := `SELECT ` + webAuthnCredentialColumns + ` FROM webauthn_credentials WHERE id = $1`
+
+	c, err := scanWebAuthnCredential(s.db.QueryRow(ctx, s.db.Rebind(query), id))
+	if err != nil {
+		if isNoRows(err) {
+			return nil, ErrWebAuthnCredentialNotFound
+		}
+		return nil, fmt.Errorf("failed to find webauthn credential: %w", err)
+	}
+
+	return c, nil
+}
+
+// ListUserWebAuthnCredentials lists the credentials of a user, oldest first.
+func (s *DatabaseWebAuthnStore) ListUserWebAuthnCredentials(ctx context.Context, userID string) ([]*WebAuthnCredential, error) {
+	query := `SELECT ` + webAuthnCredentialColumns + ` FROM webauthn_credentials
+		 WHERE user_id = $1 ORDER BY created_at, id`
+
+	rows, err := s.db.Query(ctx, s.db.Rebind(query), userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webauthn credentials: %w", err)
+	}
+	defer rows.Close()
+
+	credentials := make([]*WebAuthnCredential, 0)
+	for rows.Next() {
+		c, err := scanWebAuthnCredential(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webauthn credential: %w", err)
+		}
+		credentials = append(credentials, c)
+	}
+
+	return credentials, rows.Err()
+}
+
+// UpdateWebAuthnCredentialUsage records the sign counter and last use of a credential.
+func (s *DatabaseWebAuthnStore) UpdateWebAuthnCredentialUsage(ctx context.Context, id string, signCount uint32, usedAt time.Time) error {
+	query := `UPDATE webauthn_credentials SET sign_count = $1, last_used_at = $2 WHERE id = $3`
+
+	if err := s.db.Exec(ctx, s.db.Rebind(query), int64(signCount), usedAt.UTC().Truncate(time.Second), id); err != nil {
+		return fmt.Errorf("failed to update webauthn credential: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteWebAuthnCredential deletes a credential owned by a user.
+func (s *DatabaseWebAuthnStore) DeleteWebAuthnCredential(ctx context.Context, userID, id string) error {
+	query := `DELETE FROM webauthn_credentials WHERE id = $1 AND user_id = $2`
+
+	if err := s.db.Exec(ctx, s.db.Rebind(query), id, userID); err != nil {
+		return fmt.Errorf("failed to delete webauthn credential: %w", err)
+	}
+
+	return nil
+}
+
+// SaveWebAuthnChallenge saves a pending ceremony challenge.
+func (s *DatabaseWebAuthnStore) SaveWebAuthnChallenge(ctx context.Context, challenge *WebAuthnChallenge) error {
+	challenge.CreatedAt = time.Now().UTC().Truncate(time.Second)
+	query := `INSERT INTO webauthn_challenges (challenge_hash, user_id, ceremony, expires_at, created_at)
+		 VALUES ($1, $2, $3, $4, $5)`
+
+	err := s.db.Exec(ctx, s.db.Rebind(query),
+		challenge.ChallengeHash,
+		challenge.UserID,
+		challenge.Ceremony,
+		challenge.ExpiresAt.UTC().Truncate(time.Second),
+		challenge.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save webauthn challenge: %w", err)
+	}
+
+	return nil
+}
+
+// ConsumeWebAuthnChallenge finds and deletes a challenge so it can only be used once.
+func (s *DatabaseWebAuthnStore) ConsumeWebAuthnChallenge(ctx context.Context, challengeHash string) (*WebAuthnChallenge, error) {
+	challenge := &WebAuthnChallenge{}
+	err := InTx(ctx, s.db, func(ctx context.Context) error {
+		query := `SELECT challenge_hash, user_id, ceremony, expires_at, created_at
+			 FROM webauthn_challenges WHERE challenge_hash = $1`
+		err := s.db.QueryRow(ctx, s.db.Rebind(query), challengeHash).Scan(
+			&challenge.ChallengeHash, &challenge.UserID, &challenge.Ceremony, &challenge.ExpiresAt, &challenge.CreatedAt,
+		)
+		if err != nil {
+			return err
+		}
+		return s.db.Exec(ctx, s.db.Rebind(`DELETE FROM webauthn_challenges WHERE challenge_hash = $1`), challengeHash)
+	})
+	if err != nil {
+		if isNoRows(err) {
+			return nil, ErrWebAuthnChallengeNotFound
+		}
+		return nil, fmt.Errorf("failed to consume webauthn challenge: %w", err)
+	}
+
+	return challenge, nil
+}
+
+// MockWebAuthnStore is a mock implementation for testing
+type MockWebAuthnStore struct {
+	mu          sync.RWMutex
+	credentials map[string]*WebAuthnCredential
+	challenges  map[string]*WebAuthnChallenge
+}
+
+// NewMockWebAuthnStore creates a new mock WebAuthn store.
+func NewMockWebAuthnStore() *MockWebAuthnStore {
+	return &MockWebAuthnStore{
+		credentials: make(map[string]*WebAuthnCredential),
+		challenges:  make(map[string]*WebAuthnChallenge),
+	}
+}
+
+// SaveWebAuthnCredential saves a credential in mock store.
+func (s *MockWebAuthnStore) SaveWebAuthnCredential(ctx context.Context, credential *WebAuthnCredential) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	credential.CreatedAt = time.Now()
+	copied := *credential
+	s.credentials[credential.ID] = &copied
+	return nil
+}
+
+// FindWebAuthnCredential finds a credential in mock store.
+func (s *MockWebAuthnStore) FindWebAuthnCredential(ctx context.Context, id string) (*WebAuthnCredential, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	c, exists := s.credentials[id]
+	if !exists {
+		return nil, ErrWebAuthnCredentialNotFound
+	}
+	copied := *c
+	return &copied, nil
+}
+
+// ListUserWebAuthnCredentials lists a user's credentials in mock store.
+func (s *MockWebAuthnStore) ListUserWebAuthnCredentials(ctx context.Context, userID string) ([]*WebAuthnCredential, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	credentials := make([]*WebAuthnCredential, 0)
+	for _, c := range s.credentials {
+		if c.UserID == userID {
+			copied := *c
+			credentials = append(credentials, &copied)
+		}
+	}
+	sort.Slice(credentials, func(i, j int) bool {
+		return credentials[i].CreatedAt.Before(credentials[j].CreatedAt)
+	})
+	return credentials, nil
+}
+
+// UpdateWebAuthnCredentialUsage updates a credential's usage in mock store.
+func (s *MockWebAuthnStore) UpdateWebAuthnCredentialUsage(ctx context.Context, id string, signCount uint32, usedAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if c, exists := s.credentials[id]; exists {
+		c.SignCount = signCount
+		c.LastUsedAt = &usedAt
+	}
+	return nil
+}
+
+// DeleteWebAuthnCredential deletes a credential in mock store.
+func (s *MockWebAuthnStore) DeleteWebAuthnCredential(ctx context.Context, userID, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if c, exists := s.credentials[id]; exists && c.UserID == userID {
+		delete(s.credentials, id)
+	}
+	return nil
+}
+
+// SaveWebAuthnChallenge saves a challenge in mock store.
+func (s *MockWebAuthnStore) SaveWebAuthnChallenge(ctx context.Context, challenge *WebAuthnChallenge) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	challenge.CreatedAt = time.Now()
+	copied := *challenge
+	s.challenges[challenge.ChallengeHash] = &copied
+	return nil
+}
+
+// ConsumeWebAuthnChallenge finds and deletes a challenge in mock store.
+func (s *MockWebAuthnStore) ConsumeWebAuthnChallenge(ctx context.Context, challengeHash string) (*WebAuthnChallenge, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	challenge, exists := s.challenges[challengeHash]
+	if !exists {
+		return nil, ErrWebAuthnChallengeNotFound
+	}
+	delete(s.challenges, challengeHash)
+	return challenge, nil
+}
@@ -0,0 +1,452 @@
+package dim
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const (
+	testWebAuthnRPID   = "example.com"
+	testWebAuthnOrigin = "https://app.example.com"
+)
+
+// testCBORMap adalah map CBOR berurutan: key, value, key, value, ...
+type testCBORMap []interface{}
+
+// testCBOR adalah encoder CBOR minimal untuk membangun data authenticator di test.
+func testCBOR(v interface{}) []byte {
+	switch x := v.(type) {
+	case int:
+		if x < 0 {
+			return testCBORHead(1, uint64(-1-x))
+		}
+		return testCBORHead(0, uint64(x))
+	case string:
+		return append(testCBORHead(3, uint64(len(x))), x...)
+	case []byte:
+		return append(testCBORHead(2, uint64(len(x))), x...)
+	case []interface{}:
+		out := testCBORHead(4, uint64(len(x)))
+		for _, item := range x {
+			out = append(out, testCBOR(item)...)
+		}
+		return out
+	case testCBORMap:
+		out := testCBORHead(5, uint64(len(x)/2))
+		for _, item := range x {
+			out = append(out, testCBOR(item)...)
+		}
+		return out
+	}
+	panic("unsupported test cbor value")
+}
+
+func testCBORHead(major byte, n uint64) []byte {
+	switch {
+	case n < 24:
+		return []byte{major<<5 | byte(n)}
+	case n < 1<<8:
+		return []byte{major<<5 | 24, byte(n)}
+	case n < 1<<16:
+		return []byte{major<<5 | 25, byte(n >> 8), byte(n)}
+	}
+	return binary.BigEndian.AppendUint32([]byte{major<<5 | 26}, uint32(n))
+}
+
+// testPasskey adalah software authenticator ES256 untuk menjalankan ceremony di test.
+type testPasskey struct {
+	key       *ecdsa.PrivateKey
+	id        []byte
+	signCount uint32
+	rpID      string
+	origin    string
+	flags     byte
+	format    string
+	// attestationKey menandatangani attestation "packed" (default: key credential, self attestation).
+	attestationKey *ecdsa.PrivateKey
+}
+
+func newTestPasskey(t *testing.T) *testPasskey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := make([]byte, 16)
+	rand.Read(id)
+	return &testPasskey{
+		key:    key,
+		id:     id,
+		rpID:   testWebAuthnRPID,
+		origin: testWebAuthnOrigin,
+		flags:  authDataFlagUserPresent | authDataFlagUserVerified,
+		format: "none",
+	}
+}
+
+func (p *testPasskey) coseKey() []byte {
+	x := p.key.X.FillBytes(make([]byte, 32))
+	y := p.key.Y.FillBytes(make([]byte, 32))
+	return testCBOR(testCBORMap{1, 2, 3, -7, -1, 1, -2, x, -3, y})
+}
+
+func (p *testPasskey) authData(attested bool) []byte {
+	rpIDHash := sha256.Sum256([]byte(p.rpID))
+	flags := p.flags
+	if attested {
+		flags |= authDataFlagAttestedCredential
+	}
+	data := append(rpIDHash[:], flags)
+	data = binary.BigEndian.AppendUint32(data, p.signCount)
+	if attested {
+		data = append(data, make([]byte, 16)...) // AAGUID kosong
+		data = binary.BigEndian.AppendUint16(data, uint16(len(p.id)))
+		data = append(data, p.id...)
+		data = append(data, p.coseKey()...)
+	}
+	return data
+}
+
+func (p *testPasskey) clientData(ceremony string, challenge []byte) []byte {
+	data, _ := json.Marshal(map[string]interface{}{
+		"type":      ceremony,
+		"challenge": base64.RawURLEncoding.EncodeToString(challenge),
+		"origin":    p.origin,
+	})
+	return data
+}
+
+func (p *testPasskey) sign(t *testing.T, key *ecdsa.PrivateKey, authData, clientData []byte) []byte {
+	t.Helper()
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(bytes.Clone(authData), clientDataHash[:]...))
+	sig, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sig
+}
+
+func (p *testPasskey) register(t *testing.T, options *WebAuthnCreationOptions) *WebAuthnRegistration {
+	t.Helper()
+	clientData := p.clientData(webAuthnCeremonyCreate, options.Challenge)
+	authData := p.authData(true)
+
+	stmt := testCBORMap{}
+	if p.format == "packed" {
+		key := p.key
+		if p.attestationKey != nil {
+			key = p.attestationKey
+		}
+		stmt = testCBORMap{"alg", -7, "sig", p.sign(t, key, authData, clientData)}
+	}
+	return &WebAuthnRegistration{
+		ID:    base64.RawURLEncoding.EncodeToString(p.id),
+		RawID: p.id,
+		Type:  "public-key",
+		Response: WebAuthnAttestationResponse{
+			ClientDataJSON:    clientData,
+			AttestationObject: testCBOR(testCBORMap{"fmt", p.format, "attStmt", stmt, "authData", authData}),
+			Transports:        []string{"internal", "hybrid"},
+		},
+	}
+}
+
+func (p *testPasskey) login(t *testing.T, options *WebAuthnRequestOptions) *WebAuthnAssertion {
+	t.Helper()
+	clientData := p.clientData(webAuthnCeremonyGet, options.Challenge)
+	authData := p.authData(false)
+	return &WebAuthnAssertion{
+		ID:    base64.RawURLEncoding.EncodeToString(p.id),
+		RawID: p.id,
+		Type:  "public-key",
+		Response: WebAuthnAssertionResponse{
+			ClientDataJSON:    clientData,
+			AuthenticatorData: authData,
+			Signature:         p.sign(t, p.key, authData, clientData),
+			UserHandle:        []byte("1"),
+		},
+	}
+}
+
+func newTestWebAuthnService(t *testing.T, store WebAuthnStore) (*WebAuthnService, *AuthService, *MockUser) {
+	t.Helper()
+	userStore := NewMockUserStore()
+	hashed, _ := HashPassword("ValidPass123!")
+	user := &MockUser{ID: "1", Email: "test@example.com", Password: hashed}
+	userStore.AddUser(user)
+	auth := newTestMFAAuthService(t, userStore, NewMockTokenStore())
+	return NewWebAuthnService(auth, store, testWebAuthnRPID, "Acme", testWebAuthnOrigin), auth, user
+}
+
+// registerTestPasskey menjalankan ceremony registrasi hingga passkey tersimpan.
+func registerTestPasskey(t *testing.T, service *WebAuthnService, user Authenticatable, passkey *testPasskey) *WebAuthnCredential {
+	t.Helper()
+	options, err := service.BeginRegistration(context.Background(), user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	credential, err := service.FinishRegistration(context.Background(), user, "Laptop", passkey.register(t, options))
+	if err != nil {
+		t.Fatalf("FinishRegistration error: %v", err)
+	}
+	return credential
+}
+
+func TestWebAuthnService_RegisterAndLogin(t *testing.T) {
+	ctx := context.Background()
+	service, auth, user := newTestWebAuthnService(t, NewMockWebAuthnStore())
+	passkey := newTestPasskey(t)
+
+	options, err := service.BeginRegistration(ctx, user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if options.RP.ID != testWebAuthnRPID || string(options.User.ID) != "1" || options.Attestation != "none" {
+		t.Errorf("creation options = %+v", options)
+	}
+	registration := passkey.register(t, options)
+	credential, err := service.FinishRegistration(ctx, user, " Laptop ", registration)
+	if err != nil {
+		t.Fatalf("FinishRegistration error: %v", err)
+	}
+	if credential.Name != "Laptop" || credential.UserID != "1" || credential.AttestationFormat != "none" ||
+		credential.AAGUID != "00000000-0000-0000-0000-000000000000" {
+		t.Errorf("credential = %+v", credential)
+	}
+	if _, err := service.FinishRegistration(ctx, user, "Laptop", registration); err == nil {
+		t.Error("expected registration challenge to be single use")
+	}
+	if options, _ := service.BeginRegistration(ctx, user); len(options.ExcludeCredentials) != 1 {
+		t.Errorf("ExcludeCredentials = %v, want registered passkey", options.ExcludeCredentials)
+	}
+
+	// Login tanpa email (discoverable credential), dengan user verification
+	requestOptions, err := service.BeginLogin(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	passkey.signCount = 1
+	assertion := passkey.login(t, requestOptions)
+	access, refresh, err := service.FinishLogin(ctx, assertion)
+	if err != nil || refresh == "" {
+		t.Fatalf("FinishLogin error: %v", err)
+	}
+	if claims, _ := auth.tokenManager.VerifyToken(access); claims["sub"] != "1" || claims[MFAClaim] != true {
+		t.Errorf("access token claims = %v", claims)
+	}
+	if _, _, err := service.FinishLogin(ctx, assertion); err == nil {
+		t.Error("expected replayed assertion to be rejected")
+	}
+
+	// Login dengan email membatasi allowCredentials ke passkey user
+	requestOptions, _ = service.BeginLogin(ctx, "test@example.com")
+	if len(requestOptions.AllowCredentials) != 1 || !bytes.Equal(requestOptions.AllowCredentials[0].ID, passkey.id) {
+		t.Errorf("AllowCredentials = %v", requestOptions.AllowCredentials)
+	}
+	if unknown, _ := service.BeginLogin(ctx, "nobody@example.com"); len(unknown.AllowCredentials) != 0 {
+		t.Errorf("AllowCredentials for unknown email = %v", unknown.AllowCredentials)
+	}
+
+	// Counter yang tidak naik ditolak (kemungkinan authenticator diklon)
+	if _, _, err := service.FinishLogin(ctx, passkey.login(t, requestOptions)); err == nil {
+		t.Error("expected non-increasing sign count to be rejected")
+	}
+
+	// Tanpa user verification, user dengan MFA aktif harus menyelesaikan MFA
+	enableTestMFA(t, auth, user)
+	passkey.signCount, passkey.flags = 5, authDataFlagUserPresent
+	requestOptions, _ = service.BeginLogin(ctx, "")
+	_, _, err = service.FinishLogin(ctx, passkey.login(t, requestOptions))
+	var mfa *MFARequiredError
+	if !errors.As(err, &mfa) || mfa.MFAToken == "" {
+		t.Errorf("FinishLogin without UV error = %v, want MFARequiredError", err)
+	}
+
+	if err := service.DeleteCredential(ctx, "2", credential.ID); err == nil {
+		t.Error("expected deleting another user's passkey to fail")
+	}
+	if err := service.DeleteCredential(ctx, "1", credential.ID); err != nil {
+		t.Fatal(err)
+	}
+	if list, _ := service.Credentials(ctx, "1"); len(list) != 0 {
+		t.Errorf("Credentials after delete = %v", list)
+	}
+}
+
+func TestWebAuthnService_RejectsInvalidCeremonies(t *testing.T) {
+	for name, tc := range map[string]func(p *testPasskey){
+		"wrong origin":     func(p *testPasskey) { p.origin = "https://evil.example.net" },
+		"wrong rp id":      func(p *testPasskey) { p.rpID = "evil.example.net" },
+		"user not present": func(p *testPasskey) { p.flags = authDataFlagUserVerified },
+		"unknown format":   func(p *testPasskey) { p.format = "fido-u2f" },
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			service, _, user := newTestWebAuthnService(t, NewMockWebAuthnStore())
+			passkey := newTestPasskey(t)
+			tc(passkey)
+
+			options, _ := service.BeginRegistration(ctx, user)
+			_, err := service.FinishRegistration(ctx, user, "Laptop", passkey.register(t, options))
+			appErr, ok := AsAppError(err)
+			if !ok || appErr.StatusCode != http.StatusBadRequest {
+				t.Errorf("FinishRegistration error = %v, want 400", err)
+			}
+		})
+	}
+}
+
+func TestWebAuthnService_PackedAttestation(t *testing.T) {
+	ctx := context.Background()
+	service, _, user := newTestWebAuthnService(t, NewMockWebAuthnStore())
+	passkey := newTestPasskey(t)
+	passkey.format = "packed"
+
+	credential := registerTestPasskey(t, service, user, passkey)
+	if credential.AttestationFormat != "packed" {
+		t.Errorf("AttestationFormat = %q", credential.AttestationFormat)
+	}
+
+	// Self attestation yang ditandatangani key lain ditolak
+	other := newTestPasskey(t)
+	other.format = "packed"
+	other.attestationKey = newTestPasskey(t).key
+	options, _ := service.BeginRegistration(ctx, user)
+	if _, err := service.FinishRegistration(ctx, user, "Other", other.register(t, options)); err == nil {
+		t.Error("expected attestation signed by another key to be rejected")
+	}
+}
+
+func TestWebAuthnService_DatabaseStore(t *testing.T) {
+	db := newTestSQLiteAuthDB(t)
+	if err := RunMigrations(db, GetWebAuthnMigrations()); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if err := db.Exec(ctx, db.Rebind("INSERT INTO users (id, email, password) VALUES ($1, $2, $3)"), "1", "test@example.com", "hash"); err != nil {
+		t.Fatal(err)
+	}
+
+	service, _, user := newTestWebAuthnService(t, NewDatabaseWebAuthnStore(db))
+	passkey := newTestPasskey(t)
+	credential := registerTestPasskey(t, service, user, passkey)
+
+	list, err := service.Credentials(ctx, "1")
+	if err != nil || len(list) != 1 {
+		t.Fatalf("Credentials = %v, %v", list, err)
+	}
+	if list[0].ID != credential.ID || !bytes.Equal(list[0].PublicKey, credential.PublicKey) ||
+		strings.Join(list[0].Transports, ",") != "internal,hybrid" || list[0].LastUsedAt != nil {
+		t.Errorf("stored credential = %+v", list[0])
+	}
+
+	options, _ := service.BeginLogin(ctx, "test@example.com")
+	passkey.signCount = 3
+	if _, _, err := service.FinishLogin(ctx, passkey.login(t, options)); err != nil {
+		t.Fatalf("FinishLogin error: %v", err)
+	}
+	stored, err := NewDatabaseWebAuthnStore(db).FindWebAuthnCredential(ctx, credential.ID)
+	if err != nil || stored.SignCount != 3 || stored.LastUsedAt == nil {
+		t.Errorf("credential after login = %+v, %v", stored, err)
+	}
+
+	if _, err := NewDatabaseWebAuthnStore(db).ConsumeWebAuthnChallenge(ctx, "missing"); !errors.Is(err, ErrWebAuthnChallengeNotFound) {
+		t.Errorf("ConsumeWebAuthnChallenge error = %v", err)
+	}
+	if err := service.DeleteCredential(ctx, "1", credential.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewDatabaseWebAuthnStore(db).FindWebAuthnCredential(ctx, credential.ID); !errors.Is(err, ErrWebAuthnCredentialNotFound) {
+		t.Errorf("FindWebAuthnCredential after delete error = %v", err)
+	}
+}
+
+func TestWebAuthnService_Handlers(t *testing.T) {
+	service, _, user := newTestWebAuthnService(t, NewMockWebAuthnStore())
+	passkey := newTestPasskey(t)
+
+	w := httptest.NewRecorder()
+	service.RegistrationOptionsHandler()(w, httptest.NewRequest(http.MethodPost, "/auth/passkeys/register/options", nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("unauthenticated registration options status = %d", w.Code)
+	}
+
+	r := SetUser(httptest.NewRequest(http.MethodPost, "/auth/passkeys/register/options", nil), user)
+	w = httptest.NewRecorder()
+	service.RegistrationOptionsHandler()(w, r)
+	var creation WebAuthnCreationOptions
+	if err := json.NewDecoder(w.Body).Decode(&creation); err != nil || len(creation.Challenge) != 32 {
+		t.Fatalf("registration options = %d %+v, %v", w.Code, creation, err)
+	}
+
+	body, _ := json.Marshal(map[string]interface{}{"name": "Phone", "credential": passkey.register(t, &creation)})
+	r = SetUser(httptest.NewRequest(http.MethodPost, "/auth/passkeys/register", bytes.NewReader(body)), user)
+	w = httptest.NewRecorder()
+	service.RegistrationHandler()(w, r)
+	if w.Code != http.StatusCreated || !strings.Contains(w.Body.String(), `"name":"Phone"`) {
+		t.Fatalf("registration status = %d, body = %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	service.LoginOptionsHandler()(w, httptest.NewRequest(http.MethodPost, "/auth/passkeys/login/options", nil))
+	var request WebAuthnRequestOptions
+	if err := json.NewDecoder(w.Body).Decode(&request); err != nil || request.RPID != testWebAuthnRPID {
+		t.Fatalf("login options = %d %+v, %v", w.Code, request, err)
+	}
+
+	body, _ = json.Marshal(passkey.login(t, &request))
+	w = httptest.NewRecorder()
+	service.LoginHandler()(w, httptest.NewRequest(http.MethodPost, "/auth/passkeys/login", bytes.NewReader(body)))
+	var tokens TokenResponse
+	if err := json.NewDecoder(w.Body).Decode(&tokens); err != nil || w.Code != http.StatusOK || tokens.AccessToken == "" {
+		t.Errorf("login status = %d, tokens = %+v, %v", w.Code, tokens, err)
+	}
+
+	w = httptest.NewRecorder()
+	service.LoginHandler()(w, httptest.NewRequest(http.MethodPost, "/auth/passkeys/login", bytes.NewReader(body)))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("replayed login status = %d, want 401", w.Code)
+	}
+}
+
+func TestCBORDecode_Malformed(t *testing.T) {
+	deep := bytes.Repeat([]byte{0x81}, cborMaxDepth+2)
+	for name, data := range map[string][]byte{
+		"empty":             {},
+		"truncated bytes":   {0x42, 0x01},
+		"indefinite array":  {0x9f, 0x01, 0xff},
+		"byte string key":   {0xa1, 0x41, 0x01, 0x01},
+		"truncated length":  {0x19, 0x01},
+		"too deeply nested": append(deep, 0x01),
+		"array longer than": {0x85, 0x01},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, _, err := cborDecode(data); !errors.Is(err, errWebAuthnMalformed) {
+				t.Errorf("cborDecode error = %v, want errWebAuthnMalformed", err)
+			}
+		})
+	}
+
+	value, rest, err := cborDecode(testCBOR(testCBORMap{"a", []interface{}{1, -300, []byte{7}}, 3, "x"}))
+	if err != nil || len(rest) != 0 {
+		t.Fatalf("cborDecode error = %v, rest = %v", err, rest)
+	}
+	m := value.(map[interface{}]interface{})
+	items := m["a"].([]interface{})
+	if items[0] != int64(1) || items[1] != int64(-300) || !bytes.Equal(items[2].([]byte), []byte{7}) || m[int64(3)] != "x" {
+		t.Errorf("decoded = %#v", value)
+	}
+}
@@ -0,0 +1,391 @@
+package dim
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+)
+
+// Algoritma COSE yang didukung (IANA COSE Algorithms).
+const (
+	coseAlgES256 int64 = -7
+	coseAlgEdDSA int64 = -8
+	coseAlgRS256 int64 = -257
+)
+
+// Flag authenticator data (WebAuthn §6.1).
+const (
+	authDataFlagUserPresent        byte = 0x01
+	authDataFlagUserVerified       byte = 0x04
+	authDataFlagAttestedCredential byte = 0x40
+	authDataFlagExtensions         byte = 0x80
+)
+
+// webAuthnMaxCredentialIDLength adalah panjang maksimum credential ID menurut spesifikasi.
+const webAuthnMaxCredentialIDLength = 1023
+
+// errWebAuthnMalformed menandai data dari authenticator yang tidak dapat di-decode.
+var errWebAuthnMalformed = errors.New("malformed webauthn data")
+
+// cborMaxDepth membatasi nesting CBOR agar input berbahaya tidak menghabiskan stack.
+const cborMaxDepth = 16
+
+// cborDecode men-decode satu item CBOR (RFC 8949) dan mengembalikan sisa data. Hanya subset
+// yang dipakai WebAuthn yang didukung: integer, byte/text string, array, map, tag, bool, dan null
+// dengan panjang definit. Integer dikembalikan sebagai int64 dan map sebagai
+// map[interface{}]interface{} dengan key int64 atau string.
+func cborDecode(data []byte) (interface{}, []byte, error) {
+	return cborDecodeItem(data, 0)
+}
+
+func cborDecodeItem(data []byte, depth int) (interface{}, []byte, error) {
+	if depth > cborMaxDepth || len(data) == 0 {
+		return nil, nil, errWebAuthnMalformed
+	}
+	major, info := data[0]>>5, data[0]&0x1f
+	data = data[1:]
+
+	if major == 7 {
+		switch info {
+		case 20:
+			return false, data, nil
+		case 21:
+			return true, data, nil
+		case 22, 23:
+			return nil, data, nil
+		}
+		return nil, nil, fmt.Errorf("%w: unsupported cbor simple value %d", errWebAuthnMalformed, info)
+	}
+
+	n, data, err := cborArgument(info, data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch major {
+	case 0:
+		if n > math.MaxInt64 {
+			return nil, nil, errWebAuthnMalformed
+		}
+		return int64(n), data, nil
+	case 1:
+		if n > math.MaxInt64 {
+			return nil, nil, errWebAuthnMalformed
+		}
+		return -1 - int64(n), data, nil
+	case 2, 3:
+		if uint64(len(data)) < n {
+			return nil, nil, errWebAuthnMalformed
+		}
+		if major == 3 {
+			return string(data[:n]), data[n:], nil
+		}
+		return bytes.Clone(data[:n]), data[n:], nil
+	case 4:
+		if n > uint64(len(data)) {
+			return nil, nil, errWebAuthnMalformed
+		}
+		items := make([]interface{}, 0, n)
+		for i := uint64(0); i < n; i++ {
+			var item interface{}
+			if item, data, err = cborDecodeItem(data, depth+1); err != nil {
+				return nil, nil, err
+			}
+			items = append(items, item)
+		}
+		return items, data, nil
+	case 5:
+		if n > uint64(len(data)) {
+			return nil, nil, errWebAuthnMalformed
+		}
+		m := make(map[interface{}]interface{}, n)
+		for i := uint64(0); i < n; i++ {
+			var key, value interface{}
+			if key, data, err = cborDecodeItem(data, depth+1); err != nil {
+				return nil, nil, err
+			}
+			switch key.(type) {
+			case int64, string:
+			default:
+				return nil, nil, fmt.Errorf("%w: unsupported cbor map key", errWebAuthnMalformed)
+			}
+			if value, data, err = cborDecodeItem(data, depth+1); err != nil {
+				return nil, nil, err
+			}
+			m[key] = value
+		}
+		return m, data, nil
+	default: // 6: tag, nilai di dalamnya dipakai apa adanya
+		return cborDecodeItem(data, depth+1)
+	}
+}
+
+// cborArgument membaca argumen (panjang atau nilai) dari header item CBOR.
+func cborArgument(info byte, data []byte) (uint64, []byte, error) {
+	size := 0
+	switch {
+	case info < 24:
+		return uint64(info), data, nil
+	case info == 24:
+		size = 1
+	case info == 25:
+		size = 2
+	case info == 26:
+		size = 4
+	case info == 27:
+		size = 8
+	default:
+		return 0, nil, fmt.Errorf("%w: indefinite-length cbor is not supported", errWebAuthnMalformed)
+	}
+	if len(data) < size {
+		return 0, nil, errWebAuthnMalformed
+	}
+	var n uint64
+	for _, b := range data[:size] {
+		n = n<<8 | uint64(b)
+	}
+	return n, data[size:], nil
+}
+
+// coseKey adalah public key credential yang sudah di-parse dari format COSE_Key.
+type coseKey struct {
+	alg int64
+	pub crypto.PublicKey
+}
+
+// parseCOSEKey mem-parse COSE_Key (RFC 9052) untuk algoritma ES256, EdDSA (Ed25519), dan RS256,
+// lalu mengembalikan sisa data setelah key.
+func parseCOSEKey(data []byte) (*coseKey, []byte, error) {
+	decoded, rest, err := cborDecode(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	m, ok := decoded.(map[interface{}]interface{})
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: cose key is not a map", errWebAuthnMalformed)
+	}
+	kty, _ := m[int64(1)].(int64)
+	alg, _ := m[int64(3)].(int64)
+
+	key := &coseKey{alg: alg}
+	switch {
+	case kty == 2 && alg == coseAlgES256:
+		crv, _ := m[int64(-1)].(int64)
+		x, _ := m[int64(-2)].([]byte)
+		y, _ := m[int64(-3)].([]byte)
+		if crv != 1 || len(x) != 32 || len(y) != 32 {
+			return nil, nil, fmt.Errorf("%w: invalid EC2 key", errWebAuthnMalformed)
+		}
+		point := append(append([]byte{0x04}, x...), y...)
+		pub, err := ecdsa.ParseUncompressedPublicKey(elliptic.P256(), point)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", errWebAuthnMalformed, err)
+		}
+		key.pub = pub
+	case kty == 1 && alg == coseAlgEdDSA:
+		crv, _ := m[int64(-1)].(int64)
+		x, _ := m[int64(-2)].([]byte)
+		if crv != 6 || len(x) != ed25519.PublicKeySize {
+			return nil, nil, fmt.Errorf("%w: invalid OKP key", errWebAuthnMalformed)
+		}
+		key.pub = ed25519.PublicKey(x)
+	case kty == 3 && alg == coseAlgRS256:
+		n, _ := m[int64(-1)].([]byte)
+		e, _ := m[int64(-2)].([]byte)
+		if len(n) < 256 || len(e) == 0 || len(e) > 4 {
+			return nil, nil, fmt.Errorf("%w: invalid RSA key", errWebAuthnMalformed)
+		}
+		exponent := 0
+		for _, b := range e {
+			exponent = exponent<<8 | int(b)
+		}
+		key.pub = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: exponent}
+	default:
+		return nil, nil, fmt.Errorf("%w: unsupported cose key (kty %d, alg %d)", errWebAuthnMalformed, kty, alg)
+	}
+	return key, rest, nil
+}
+
+// verify memverifikasi signature atas data dengan key ini.
+func (k *coseKey) verify(data, sig []byte) error {
+	ok := false
+	switch pub := k.pub.(type) {
+	case *ecdsa.PublicKey:
+		digest := sha256.Sum256(data)
+		ok = ecdsa.VerifyASN1(pub, digest[:], sig)
+	case ed25519.PublicKey:
+		ok = ed25519.Verify(pub, data, sig)
+	case *rsa.PublicKey:
+		digest := sha256.Sum256(data)
+		ok = rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], sig) == nil
+	}
+	if !ok {
+		return errors.New("invalid signature")
+	}
+	return nil
+}
+
+// x509SignatureAlgorithm memetakan algoritma COSE ke algoritma signature x509.
+func x509SignatureAlgorithm(alg int64) (x509.SignatureAlgorithm, bool) {
+	switch alg {
+	case coseAlgES256:
+		return x509.ECDSAWithSHA256, true
+	case coseAlgEdDSA:
+		return x509.PureEd25519, true
+	case coseAlgRS256:
+		return x509.SHA256WithRSA, true
+	}
+	return x509.UnknownSignatureAlgorithm, false
+}
+
+// authenticatorData adalah authenticator data (WebAuthn §6.1) yang sudah di-parse.
+type authenticatorData struct {
+	rpIDHash  []byte
+	flags     byte
+	signCount uint32
+
+	// Hanya terisi saat registrasi (flag AT).
+	aaguid       []byte
+	credentialID []byte
+	publicKey    []byte // COSE_Key mentah, disimpan apa adanya
+	key          *coseKey
+}
+
+func (d *authenticatorData) userPresent() bool  { return d.flags&authDataFlagUserPresent != 0 }
+func (d *authenticatorData) userVerified() bool { return d.flags&authDataFlagUserVerified != 0 }
+
+// parseAuthenticatorData mem-parse authenticator data beserta attested credential data jika ada.
+func parseAuthenticatorData(data []byte) (*authenticatorData, error) {
+	if len(data) < 37 {
+		return nil, fmt.Errorf("%w: authenticator data too short", errWebAuthnMalformed)
+	}
+	d := &authenticatorData{
+		rpIDHash:  data[:32],
+		flags:     data[32],
+		signCount: binary.BigEndian.Uint32(data[33:37]),
+	}
+	rest := data[37:]
+
+	if d.flags&authDataFlagAttestedCredential != 0 {
+		if len(rest) < 18 {
+			return nil, fmt.Errorf("%w: attested credential data too short", errWebAuthnMalformed)
+		}
+		d.aaguid = rest[:16]
+		idLen := int(binary.BigEndian.Uint16(rest[16:18]))
+		rest = rest[18:]
+		if idLen == 0 || idLen > webAuthnMaxCredentialIDLength || len(rest) < idLen {
+			return nil, fmt.Errorf("%w: invalid credential id", errWebAuthnMalformed)
+		}
+		d.credentialID, rest = rest[:idLen], rest[idLen:]
+
+		key, after, err := parseCOSEKey(rest)
+		if err != nil {
+			return nil, err
+		}
+		d.key, d.publicKey, rest = key, rest[:len(rest)-len(after)], after
+	}
+
+	if d.flags&authDataFlagExtensions != 0 {
+		_, after, err := cborDecode(rest)
+		if err != nil {
+			return nil, err
+		}
+		rest = after
+	}
+	if len(rest) != 0 {
+		return nil, fmt.Errorf("%w: trailing authenticator data", errWebAuthnMalformed)
+	}
+	return d, nil
+}
+
+// formatAAGUID memformat AAGUID authenticator sebagai UUID string.
+func formatAAGUID(aaguid []byte) string {
+	if len(aaguid) != 16 {
+		return ""
+	}
+	h := hex.EncodeToString(aaguid)
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:32]
+}
+
+// webAuthnAttestation adalah attestation object yang sudah di-decode.
+type webAuthnAttestation struct {
+	format   string
+	stmt     map[interface{}]interface{}
+	authData []byte
+}
+
+// parseAttestationObject men-decode attestationObject (map CBOR fmt, attStmt, authData).
+func parseAttestationObject(data []byte) (*webAuthnAttestation, error) {
+	decoded, rest, err := cborDecode(data)
+	if err != nil {
+		return nil, err
+	}
+	m, ok := decoded.(map[interface{}]interface{})
+	if !ok || len(rest) != 0 {
+		return nil, fmt.Errorf("%w: invalid attestation object", errWebAuthnMalformed)
+	}
+	att := &webAuthnAttestation{}
+	att.format, _ = m["fmt"].(string)
+	att.stmt, _ = m["attStmt"].(map[interface{}]interface{})
+	att.authData, _ = m["authData"].([]byte)
+	if att.format == "" || att.stmt == nil || att.authData == nil {
+		return nil, fmt.Errorf("%w: incomplete attestation object", errWebAuthnMalformed)
+	}
+	return att, nil
+}
+
+// verify memverifikasi attestation statement untuk format "none" dan "packed" (self attestation
+// atau x5c). Rantai sertifikat x5c tidak dievaluasi terhadap root CA (metadata service);
+// signature-nya tetap harus valid.
+func (a *webAuthnAttestation) verify(clientDataHash []byte, credentialKey *coseKey) error {
+	switch a.format {
+	case "none":
+		if len(a.stmt) != 0 {
+			return errors.New("attestation statement must be empty for format none")
+		}
+		return nil
+	case "packed":
+		alg, _ := a.stmt["alg"].(int64)
+		sig, _ := a.stmt["sig"].([]byte)
+		if len(sig) == 0 {
+			return errors.New("packed attestation without signature")
+		}
+		signed := append(bytes.Clone(a.authData), clientDataHash...)
+
+		if x5c, present := a.stmt["x5c"].([]interface{}); present {
+			if len(x5c) == 0 {
+				return errors.New("packed attestation with empty x5c")
+			}
+			der, _ := x5c[0].([]byte)
+			cert, err := x509.ParseCertificate(der)
+			if err != nil {
+				return fmt.Errorf("invalid attestation certificate: %w", err)
+			}
+			if cert.IsCA {
+				return errors.New("attestation certificate must not be a CA")
+			}
+			sigAlg, ok := x509SignatureAlgorithm(alg)
+			if !ok {
+				return fmt.Errorf("unsupported attestation algorithm %d", alg)
+			}
+			return cert.CheckSignature(sigAlg, signed, sig)
+		}
+
+		if alg != credentialKey.alg {
+			return errors.New("self attestation algorithm does not match credential key")
+		}
+		return credentialKey.verify(signed, sig)
+	}
+	return fmt.Errorf("unsupported attestation format %q", a.format)
+}